@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/cvhariharan/flowctl/internal/core"
+	"github.com/spf13/cobra"
+)
+
+// adminPageSize is the number of items fetched per query when listing from the database
+const adminPageSize = 100
+
+// useAPI checks if an admin command should use the API of a remote server. Without a server,
+// admin commands use the database in the config file.
+func useAPI(cmd *cobra.Command) bool {
+	server, _ := cmd.Flags().GetString("server")
+	return server != ""
+}
+
+// openAdminCore connects to the database in the config file and returns a core for
+// administrative commands. The returned function closes the connection.
+func openAdminCore(cmd *cobra.Command) (*core.Core, func(), error) {
+	configPath, _ := cmd.Flags().GetString("config")
+	if err := LoadConfig(configPath); err != nil {
+		return nil, nil, err
+	}
+
+	db, err := connectDB(cmd.Context(), appConfig.DB)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not connect to database: %w", err)
+	}
+
+	enforcer, err := newEnforcer(db)
+	if err != nil {
+		db.Close()
+		return nil, nil, err
+	}
+
+	return core.NewAdminCore(newStore(db), enforcer), func() { db.Close() }, nil
+}
+
+// readPassword reads a password from the first line of r
+func readPassword(r io.Reader) (string, error) {
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("could not read password: %w", err)
+	}
+
+	password := strings.TrimRight(line, "\r\n")
+	if password == "" {
+		return "", errors.New("password cannot be empty")
+	}
+	return password, nil
+}
+
+// addAdminFlags adds the flags of commands that use either the API of a remote server or the database
+func addAdminFlags(cmd *cobra.Command) {
+	cmd.Flags().String("server", os.Getenv("FLOWCTL_SERVER"), "URL of the flowctl server, the database in the config file is used if not set (env FLOWCTL_SERVER)")
+	cmd.Flags().String("token", os.Getenv("FLOWCTL_TOKEN"), "API token used to authenticate with --server (env FLOWCTL_TOKEN)")
+}
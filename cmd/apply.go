@@ -0,0 +1,383 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/cvhariharan/flowctl/internal/handlers"
+	"github.com/spf13/cobra"
+)
+
+const (
+	applyCreate = "create"
+	applyUpdate = "update"
+	applyDelete = "delete"
+)
+
+var applyDone = map[string]string{
+	applyCreate: "created",
+	applyUpdate: "updated",
+	applyDelete: "deleted",
+}
+
+// localFlow is a flow read from a file in the directory being applied
+type localFlow struct {
+	path   string
+	config handlers.FlowCreateReq
+}
+
+// applyChange is a change needed to make the flows on the server match the directory
+type applyChange struct {
+	action string
+	id     string
+	// fields lists the parts of the flow that differ for updates
+	fields []string
+	config handlers.FlowCreateReq
+}
+
+// applyCmd makes the flows of a namespace on a remote server match a directory of flow files
+var applyCmd = &cobra.Command{
+	Use:   "apply <directory>",
+	Short: "Create, update and delete flows on a remote flowctl server to match a directory of flow files",
+	Long: `Compare a directory of flow files with the flows of a namespace on a remote server,
+and create, update and delete flows on the server until they match.
+
+The directory has the same layout as a namespace in the flows directory: each flow is a
+.yaml, .yml or .huml file, either directly in the directory or in a subdirectory of its own.
+
+Use --dry-run to only print the changes that would be made.`,
+	Example: `  flowctl apply flows/production -n production --dry-run
+  flowctl apply flows/production -n production --server https://flowctl.example.com`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newAPIClient(cmd)
+		if err != nil {
+			return err
+		}
+
+		namespace, _ := cmd.Flags().GetString("namespace")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		prune, _ := cmd.Flags().GetBool("prune")
+
+		local, err := readFlowDirectory(args[0], namespace)
+		if err != nil {
+			return err
+		}
+		// An empty directory would delete every flow in the namespace, which is
+		// more likely a wrong path than what was intended.
+		if len(local) == 0 {
+			return fmt.Errorf("no flows found in %s", args[0])
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		ns := url.PathEscape(namespace)
+		remote, err := fetchFlowConfigs(ctx, client, ns)
+		if err != nil {
+			return err
+		}
+
+		changes, err := planApply(local, remote, prune)
+		if err != nil {
+			return err
+		}
+		printApplyPlan(changes)
+
+		if dryRun || len(changes) == 0 {
+			return nil
+		}
+
+		for _, c := range changes {
+			revision, err := applyFlowChange(ctx, client, ns, c)
+			if err != nil {
+				return fmt.Errorf("could not %s flow %s: %w", c.action, c.id, err)
+			}
+			if revision != "" {
+				fmt.Fprintf(os.Stderr, "update of flow %s is waiting for review as revision %s\n", c.id, revision)
+				continue
+			}
+			fmt.Fprintf(os.Stderr, "%s flow %s\n", applyDone[c.action], c.id)
+		}
+
+		return nil
+	},
+}
+
+// readFlowDirectory reads the flow files in dir and returns their configuration by flow ID
+func readFlowDirectory(dir string, namespace string) (map[string]localFlow, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("could not read flow directory: %w", err)
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		if !entry.IsDir() {
+			if isLocalFlowFile(entry.Name()) {
+				paths = append(paths, path)
+			}
+			continue
+		}
+
+		// Like the flows directory, only the first flow file of a subdirectory is used
+		files, err := os.ReadDir(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not read flow directory: %w", err)
+		}
+		for _, file := range files {
+			if !file.IsDir() && isLocalFlowFile(file.Name()) {
+				paths = append(paths, filepath.Join(path, file.Name()))
+				break
+			}
+		}
+	}
+
+	flows := make(map[string]localFlow, len(paths))
+	for _, path := range paths {
+		f, err := readFlowFile(path, namespace)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if existing, ok := flows[f.Meta.ID]; ok {
+			return nil, fmt.Errorf("flow %s is defined in both %s and %s", f.Meta.ID, existing.path, path)
+		}
+		flows[f.Meta.ID] = localFlow{path: path, config: handlers.NewFlowConfig(f)}
+	}
+
+	return flows, nil
+}
+
+// readFlowFile reads and validates a flow file. The flow has to be creatable through the API,
+// which derives the flow ID from its name.
+func readFlowFile(path string, namespace string) (models.Flow, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return models.Flow{}, err
+	}
+
+	format := models.FlowFormatYAML
+	if strings.EqualFold(filepath.Ext(path), ".huml") {
+		format = models.FlowFormatHUML
+	}
+
+	f, err := models.UnmarshalFlow(data, format)
+	if err != nil {
+		return models.Flow{}, err
+	}
+	if err := f.Validate(); err != nil {
+		return models.Flow{}, fmt.Errorf("invalid flow: %w", err)
+	}
+
+	if f.Meta.Namespace != "" && f.Meta.Namespace != namespace {
+		return models.Flow{}, fmt.Errorf("flow belongs to namespace %s, not %s", f.Meta.Namespace, namespace)
+	}
+	if id := handlers.GenerateSlug(f.Meta.Name); f.Meta.ID != id {
+		return models.Flow{}, fmt.Errorf("flow id %s does not match its name, flows created on the server get the id %s", f.Meta.ID, id)
+	}
+	for _, a := range f.Actions {
+		if id := handlers.GenerateSlug(a.Name); a.ID != id {
+			fmt.Fprintf(os.Stderr, "warning: %s: action id %s will be %s on the server since action ids are derived from names\n", path, a.ID, id)
+		}
+	}
+
+	return f, nil
+}
+
+func isLocalFlowFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".yaml", ".yml", ".huml":
+		return true
+	}
+	return false
+}
+
+// fetchFlowConfigs returns the configuration of all flows in the namespace by flow ID
+func fetchFlowConfigs(ctx context.Context, client *apiClient, ns string) (map[string]handlers.FlowCreateReq, error) {
+	var flows []struct {
+		ID string `json:"id"`
+	}
+	if err := listAll(ctx, client, fmt.Sprintf("/api/v1/%s/flows", ns), "flows", &flows); err != nil {
+		return nil, fmt.Errorf("could not list flows: %w", err)
+	}
+
+	configs := make(map[string]handlers.FlowCreateReq, len(flows))
+	for _, f := range flows {
+		var config handlers.FlowCreateReq
+		if err := client.getJSON(ctx, fmt.Sprintf("/api/v1/%s/flows/%s/config", ns, url.PathEscape(f.ID)), &config); err != nil {
+			return nil, fmt.Errorf("could not get config of flow %s: %w", f.ID, err)
+		}
+		configs[f.ID] = config
+	}
+
+	return configs, nil
+}
+
+// planApply returns the changes that make remote match local, ordered by action and flow ID
+func planApply(local map[string]localFlow, remote map[string]handlers.FlowCreateReq, prune bool) ([]applyChange, error) {
+	var changes []applyChange
+
+	for id, l := range local {
+		r, ok := remote[id]
+		if !ok {
+			changes = append(changes, applyChange{action: applyCreate, id: id, config: l.config})
+			continue
+		}
+
+		fields, err := diffFlowConfigs(l.config, r)
+		if err != nil {
+			return nil, fmt.Errorf("could not compare flow %s: %w", id, err)
+		}
+		if len(fields) > 0 {
+			changes = append(changes, applyChange{action: applyUpdate, id: id, fields: fields, config: l.config})
+		}
+	}
+
+	if prune {
+		for id := range remote {
+			if _, ok := local[id]; !ok {
+				changes = append(changes, applyChange{action: applyDelete, id: id})
+			}
+		}
+	}
+
+	order := map[string]int{applyCreate: 0, applyUpdate: 1, applyDelete: 2}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].action != changes[j].action {
+			return order[changes[i].action] < order[changes[j].action]
+		}
+		return changes[i].id < changes[j].id
+	})
+
+	return changes, nil
+}
+
+// diffFlowConfigs returns the parts of two flow configurations that differ. They are compared
+// as JSON so that values decoded from YAML and from the API compare equal.
+func diffFlowConfigs(a, b handlers.FlowCreateReq) ([]string, error) {
+	a.Meta.Namespace, b.Meta.Namespace = "", ""
+
+	parts := []struct {
+		name string
+		a, b any
+	}{
+		{"metadata", a.Meta, b.Meta},
+		{"inputs", a.Inputs, b.Inputs},
+		{"actions", a.Actions, b.Actions},
+		{"notify", a.Notifications, b.Notifications},
+	}
+
+	var fields []string
+	for _, p := range parts {
+		equal, err := jsonEqual(p.a, p.b)
+		if err != nil {
+			return nil, err
+		}
+		if !equal {
+			fields = append(fields, p.name)
+		}
+	}
+
+	return fields, nil
+}
+
+func jsonEqual(a, b any) (bool, error) {
+	ja, err := json.Marshal(a)
+	if err != nil {
+		return false, err
+	}
+	jb, err := json.Marshal(b)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(ja, jb), nil
+}
+
+func printApplyPlan(changes []applyChange) {
+	if len(changes) == 0 {
+		fmt.Println("No changes, the flows on the server match the directory.")
+		return
+	}
+
+	counts := make(map[string]int)
+	for _, c := range changes {
+		counts[c.action]++
+		switch c.action {
+		case applyCreate:
+			fmt.Printf("+ create %s\n", c.id)
+		case applyUpdate:
+			fmt.Printf("~ update %s (%s)\n", c.id, strings.Join(c.fields, ", "))
+		case applyDelete:
+			fmt.Printf("- delete %s\n", c.id)
+		}
+	}
+
+	fmt.Printf("\nPlan: %d to create, %d to update, %d to delete.\n", counts[applyCreate], counts[applyUpdate], counts[applyDelete])
+}
+
+// applyFlowChange makes a change on the server. If the namespace requires flow changes to be
+// reviewed, updates are held as revisions and the ID of the revision is returned.
+func applyFlowChange(ctx context.Context, client *apiClient, ns string, c applyChange) (string, error) {
+	flowPath := fmt.Sprintf("/api/v1/%s/flows/%s", ns, url.PathEscape(c.id))
+
+	switch c.action {
+	case applyCreate:
+		var resp handlers.FlowCreateResp
+		if err := client.sendJSON(ctx, http.MethodPost, fmt.Sprintf("/api/v1/%s/flows", ns), c.config, &resp); err != nil {
+			return "", err
+		}
+		if resp.ID != c.id {
+			return "", fmt.Errorf("flow was created as %s", resp.ID)
+		}
+		return "", nil
+	case applyUpdate:
+		var resp handlers.FlowRevisionResp
+		if err := client.sendJSON(ctx, http.MethodPut, flowPath, handlers.FlowUpdateReq{
+			Prefix:          c.config.Meta.Prefix,
+			Schedules:       c.config.Meta.Schedules,
+			Notify:          c.config.Notifications,
+			AllowOverlap:    c.config.Meta.AllowOverlap,
+			UserSchedulable: c.config.Meta.UserSchedulable,
+			Description:     c.config.Meta.Description,
+			Inputs:          c.config.Inputs,
+			Actions:         c.config.Actions,
+		}, &resp); err != nil {
+			return "", err
+		}
+		if resp.Status == string(models.FlowRevisionStatusPending) {
+			return resp.ID, nil
+		}
+		return "", nil
+	case applyDelete:
+		_, err := client.do(ctx, http.MethodDelete, flowPath, nil, "")
+		return "", err
+	}
+
+	return "", errors.New("unknown change")
+}
+
+func init() {
+	addRemoteFlags(applyCmd)
+	applyCmd.Flags().StringP("namespace", "n", "default", "Namespace to apply the flows to")
+	applyCmd.Flags().Bool("dry-run", false, "Only print the changes that would be made")
+	applyCmd.Flags().Bool("prune", true, "Delete flows on the server that are not in the directory")
+	rootCmd.AddCommand(applyCmd)
+}
@@ -0,0 +1,316 @@
+package cmd
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cvhariharan/flowctl/internal/flowstore"
+	"github.com/spf13/cobra"
+	"gocloud.dev/secrets"
+)
+
+const (
+	backupFormatVersion = 1
+
+	backupManifestFile = "manifest.json"
+	backupKeeperFile   = "keeper.json"
+	backupDatabaseFile = "database.dump"
+	backupFlowsDir     = "flows/"
+
+	// keeperCheckValue is encrypted with the keeper at backup time so that restore can check
+	// that the configured keeper can decrypt the secrets in the backup
+	keeperCheckValue = "flowctl-backup-check"
+)
+
+// backupManifest describes a backup. It is always the first file in the archive.
+type backupManifest struct {
+	FormatVersion  int       `json:"format_version"`
+	FlowctlVersion string    `json:"flowctl_version"`
+	SchemaVersion  uint      `json:"schema_version"`
+	CreatedAt      time.Time `json:"created_at"`
+	// Driver is the database driver of the dump, backups without it are of postgres databases
+	Driver string `json:"driver,omitempty"`
+}
+
+// backupKeeper holds metadata about the keeper that encrypted the secrets in the backup.
+// The key itself is never included.
+type backupKeeper struct {
+	Scheme string `json:"scheme"`
+	Check  []byte `json:"check"`
+}
+
+// backupCmd backs up the database, the flows directory and keeper metadata into one archive
+var backupCmd = &cobra.Command{
+	Use:          "backup",
+	Short:        "Back up the database and flows into an archive",
+	Example:      `  flowctl backup --config config.toml -o flowctl-backup.tar.gz`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		m, closeDB, err := openMigrate(cmd)
+		if err != nil {
+			return err
+		}
+		defer closeDB()
+
+		schemaVersion, dirty, err := m.Version()
+		if err != nil {
+			return fmt.Errorf("failed to get migration version: %w", err)
+		}
+		if dirty {
+			return fmt.Errorf("database is dirty at version %d, fix it before taking a backup", schemaVersion)
+		}
+
+		output, _ := cmd.Flags().GetString("output")
+		if output == "" {
+			output = fmt.Sprintf("flowctl-backup-%s.tar.gz", time.Now().UTC().Format("20060102-150405"))
+		}
+
+		ctx := context.Background()
+		keeperMeta, err := newBackupKeeper(ctx)
+		if err != nil {
+			return err
+		}
+
+		tmpDir, err := os.MkdirTemp("", "flowctl-backup")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(tmpDir)
+
+		dumpPath := filepath.Join(tmpDir, backupDatabaseFile)
+		if err := dumpDatabase(ctx, dumpPath); err != nil {
+			return fmt.Errorf("could not dump database: %w", err)
+		}
+
+		flowsDir := appConfig.App.FlowsDirectory
+		if flowstore.IsBucketURL(flowsDir) {
+			flowsDir, err = pullFlowsBucket(ctx, tmpDir)
+			if err != nil {
+				return err
+			}
+		}
+
+		f, err := os.OpenFile(output, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		gw := gzip.NewWriter(f)
+		tw := tar.NewWriter(gw)
+
+		manifest := backupManifest{
+			FormatVersion:  backupFormatVersion,
+			FlowctlVersion: getBuildInfo().Version,
+			SchemaVersion:  schemaVersion,
+			Driver:         backupDriver(),
+			CreatedAt:      time.Now().UTC(),
+		}
+		if err := tarAddJSON(tw, backupManifestFile, manifest); err != nil {
+			return err
+		}
+		if err := tarAddJSON(tw, backupKeeperFile, keeperMeta); err != nil {
+			return err
+		}
+		if err := tarAddFile(tw, backupDatabaseFile, dumpPath); err != nil {
+			return err
+		}
+		if err := tarAddDir(tw, backupFlowsDir, flowsDir); err != nil {
+			return fmt.Errorf("could not add flows directory: %w", err)
+		}
+
+		if err := tw.Close(); err != nil {
+			return err
+		}
+		if err := gw.Close(); err != nil {
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+
+		fmt.Printf("backed up schema version %d to %s\n", schemaVersion, output)
+		return nil
+	},
+}
+
+// newBackupKeeper encrypts a check value with the configured keeper
+func newBackupKeeper(ctx context.Context) (backupKeeper, error) {
+	keeper, err := secrets.OpenKeeper(ctx, appConfig.Keystore.KeeperURL)
+	if err != nil {
+		return backupKeeper{}, fmt.Errorf("could not open secrets keeper: %w", err)
+	}
+	defer keeper.Close()
+
+	check, err := keeper.Encrypt(ctx, []byte(keeperCheckValue))
+	if err != nil {
+		return backupKeeper{}, fmt.Errorf("could not encrypt keeper check value: %w", err)
+	}
+
+	scheme, _, _ := strings.Cut(appConfig.Keystore.KeeperURL, "://")
+	return backupKeeper{Scheme: scheme, Check: check}, nil
+}
+
+// backupDriver returns the driver recorded in the manifest of backups of the configured database
+func backupDriver() string {
+	if appConfig.DB.SQLite() {
+		return "sqlite"
+	}
+	return "postgres"
+}
+
+// dumpDatabase writes a dump of the database to path. SQLite databases are copied with
+// VACUUM INTO, which gives a consistent copy while flowctl is running.
+func dumpDatabase(ctx context.Context, path string) error {
+	if !appConfig.DB.SQLite() {
+		return runPGCommand(ctx, "pg_dump", "--format=custom", "--no-owner", "--file="+path)
+	}
+
+	db, err := connectDB(ctx, appConfig.DB)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	_, err = db.ExecContext(ctx, "VACUUM INTO $1", path)
+	return err
+}
+
+// runPGCommand runs a PostgreSQL client tool against the database in the config file.
+// The password is passed through the environment so that it doesn't show up in the process list.
+func runPGCommand(ctx context.Context, name string, args ...string) error {
+	conn := appConfig.DB.ConnectionString()
+	env := os.Environ()
+	if u, err := url.Parse(conn); err == nil && u.User != nil {
+		if password, ok := u.User.Password(); ok {
+			env = append(env, "PGPASSWORD="+password)
+			u.User = url.User(u.User.Username())
+			conn = u.String()
+		}
+	}
+
+	c := exec.CommandContext(ctx, name, append(args, "--dbname="+conn)...)
+	c.Env = env
+	c.Stdout = os.Stderr
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return fmt.Errorf("%s not found, install the PostgreSQL client tools", name)
+		}
+		return err
+	}
+	return nil
+}
+
+func tarAddJSON(tw *tar.Writer, name string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0600,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	}); err != nil {
+		return err
+	}
+
+	_, err = tw.Write(data)
+	return err
+}
+
+func tarAddFile(tw *tar.Writer, name string, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	hdr, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	hdr.Name = name
+
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// pullFlowsBucket downloads the flows stored in the flows bucket to a directory in tmpDir
+func pullFlowsBucket(ctx context.Context, tmpDir string) (string, error) {
+	store, err := flowstore.NewBucketStore(ctx, appConfig.App.FlowsDirectory, filepath.Join(tmpDir, "flows"))
+	if err != nil {
+		return "", err
+	}
+	defer store.Close()
+
+	if err := store.Pull(ctx); err != nil {
+		return "", fmt.Errorf("could not download flows: %w", err)
+	}
+	return store.Dir(), nil
+}
+
+// tarAddDir adds the directories and regular files under dir with the given prefix
+func tarAddDir(tw *tar.Writer, prefix string, dir string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		name := prefix + filepath.ToSlash(rel)
+
+		if d.IsDir() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			hdr, err := tar.FileInfoHeader(info, "")
+			if err != nil {
+				return err
+			}
+			hdr.Name = name + "/"
+			return tw.WriteHeader(hdr)
+		}
+
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		return tarAddFile(tw, name, path)
+	})
+}
+
+func init() {
+	backupCmd.Flags().StringP("output", "o", "", "Path of the archive (default flowctl-backup-<timestamp>.tar.gz)")
+	rootCmd.AddCommand(backupCmd)
+}
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// apiClient talks to a remote flowctl server, authenticating with a personal API token
+type apiClient struct {
+	server     string
+	token      string
+	httpClient *http.Client
+}
+
+// addRemoteFlags adds the flags used by commands that talk to a remote server.
+// The server and token default to the FLOWCTL_SERVER and FLOWCTL_TOKEN environment variables.
+func addRemoteFlags(cmd *cobra.Command) {
+	cmd.Flags().String("server", os.Getenv("FLOWCTL_SERVER"), "URL of the flowctl server (env FLOWCTL_SERVER)")
+	cmd.Flags().String("token", os.Getenv("FLOWCTL_TOKEN"), "API token used to authenticate (env FLOWCTL_TOKEN)")
+}
+
+func newAPIClient(cmd *cobra.Command) (*apiClient, error) {
+	server, _ := cmd.Flags().GetString("server")
+	token, _ := cmd.Flags().GetString("token")
+	if server == "" {
+		return nil, errors.New("server URL is required, set --server or FLOWCTL_SERVER")
+	}
+	if token == "" {
+		return nil, errors.New("API token is required, set --token or FLOWCTL_TOKEN")
+	}
+
+	return &apiClient{
+		server: strings.TrimRight(server, "/"),
+		token:  token,
+		httpClient: &http.Client{
+			Timeout: 60 * time.Second,
+		},
+	}, nil
+}
+
+func (c *apiClient) newRequest(ctx context.Context, method, path string, body io.Reader, contentType string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.server+path, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", "application/json")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	return req, nil
+}
+
+// do sends an authenticated request and returns the response body. Error responses
+// are turned into errors using the message returned by the server.
+func (c *apiClient) do(ctx context.Context, method, path string, body io.Reader, contentType string) ([]byte, error) {
+	req, err := c.newRequest(ctx, method, path, body, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, responseError(resp.StatusCode, respBody)
+	}
+
+	return respBody, nil
+}
+
+// stream sends an authenticated GET request and returns the response body for reading as it arrives.
+// Unlike do, the request has no timeout so it can be used for long lived streams.
+func (c *apiClient) stream(ctx context.Context, path string, accept string) (io.ReadCloser, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil, "")
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", accept)
+
+	resp, err := (&http.Client{Transport: c.httpClient.Transport}).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, responseError(resp.StatusCode, respBody)
+	}
+
+	return resp.Body, nil
+}
+
+// apiError is an error response returned by the server
+type apiError struct {
+	Status  int
+	Message string
+	Details any
+}
+
+func (e *apiError) Error() string {
+	if e.Details != nil {
+		return fmt.Sprintf("%s (status %d): %v", e.Message, e.Status, e.Details)
+	}
+	return fmt.Sprintf("%s (status %d)", e.Message, e.Status)
+}
+
+// isNotFound checks if err is a not found response from the server
+func isNotFound(err error) bool {
+	var apiErr *apiError
+	return errors.As(err, &apiErr) && apiErr.Status == http.StatusNotFound
+}
+
+// responseError turns an error response into an error using the message returned by the server
+func responseError(status int, body []byte) error {
+	var resp struct {
+		Error   string `json:"error"`
+		Details any    `json:"details"`
+	}
+	if err := json.Unmarshal(body, &resp); err == nil && resp.Error != "" {
+		return &apiError{Status: status, Message: resp.Error, Details: resp.Details}
+	}
+	return &apiError{Status: status, Message: fmt.Sprintf("request failed: %s", strings.TrimSpace(string(body)))}
+}
+
+// sendJSON sends v as a JSON request body and decodes the JSON response into out if it is not nil
+func (c *apiClient) sendJSON(ctx context.Context, method, path string, v any, out any) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	respBody, err := c.do(ctx, method, path, bytes.NewReader(body), "application/json")
+	if err != nil {
+		return err
+	}
+
+	if out == nil {
+		return nil
+	}
+	return decodeJSON(respBody, out)
+}
+
+// getJSON sends a GET request and decodes the JSON response into v
+func (c *apiClient) getJSON(ctx context.Context, path string, v any) error {
+	body, err := c.do(ctx, http.MethodGet, path, nil, "")
+	if err != nil {
+		return err
+	}
+
+	return decodeJSON(body, v)
+}
+
+func decodeJSON(body []byte, v any) error {
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,352 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/cvhariharan/flowctl/internal/flowstore"
+	"github.com/docker/docker/client"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/jmoiron/sqlx"
+	"github.com/spf13/cobra"
+	"gocloud.dev/blob"
+	"gocloud.dev/secrets"
+)
+
+const doctorCheckTimeout = 5 * time.Second
+
+type doctorStatus string
+
+const (
+	doctorOK   doctorStatus = "ok"
+	doctorWarn doctorStatus = "warn"
+	doctorFail doctorStatus = "fail"
+)
+
+// doctorFinding is the result of a single check. Hint tells the user how to fix a problem.
+type doctorFinding struct {
+	Check   string
+	Status  doctorStatus
+	Message string
+	Hint    string
+}
+
+// doctorCmd checks that the environment flowctl runs in is set up correctly
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose problems with the config and environment",
+	Long: `Check the config file, database connectivity and migration state, secrets keeper, log directory,
+Docker daemon and SSH reachability of the configured nodes, and print what needs to be fixed.
+
+The command exits with a non-zero status if any check fails. Warnings only affect
+optional features.`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		configPath, _ := cmd.Flags().GetString("config")
+		skipSSH, _ := cmd.Flags().GetBool("skip-ssh")
+		ctx := context.Background()
+
+		var findings []doctorFinding
+		report := func(f doctorFinding) {
+			findings = append(findings, f)
+			printDoctorFinding(f)
+		}
+
+		if err := LoadConfig(configPath); err != nil {
+			report(doctorFinding{
+				Check:   "config",
+				Status:  doctorFail,
+				Message: err.Error(),
+				Hint:    "fix the config file, or generate a new one with flowctl --new-config",
+			})
+			return errors.New("config is invalid, skipping the remaining checks")
+		}
+		report(doctorFinding{Check: "config", Status: doctorOK, Message: "config is valid"})
+
+		db, finding := checkDatabase(ctx)
+		report(finding)
+		if db != nil {
+			defer db.Close()
+			report(checkMigrations(db))
+		}
+
+		report(checkKeeper(ctx))
+		if flowstore.IsBucketURL(appConfig.App.FlowsDirectory) {
+			report(checkBucket(ctx, "flows bucket", appConfig.App.FlowsDirectory, "check flows_directory in the [app] section and the credentials of the object store"))
+		} else {
+			report(checkFlowsDirectory())
+		}
+		report(checkLogDirectory())
+		if appConfig.Logger.Backend == "object" {
+			report(checkBucket(ctx, "log bucket", appConfig.Logger.BucketURL, "check bucket_url in the [logger] section and the credentials of the object store"))
+		}
+		report(checkDocker(ctx))
+
+		if db != nil && !skipSSH {
+			for _, f := range checkNodes(ctx, db) {
+				report(f)
+			}
+		}
+
+		failed := 0
+		for _, f := range findings {
+			if f.Status == doctorFail {
+				failed++
+			}
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d check(s) failed", failed)
+		}
+		return nil
+	},
+}
+
+func printDoctorFinding(f doctorFinding) {
+	fmt.Printf("[%-4s] %s: %s\n", f.Status, f.Check, f.Message)
+	if f.Hint != "" && f.Status != doctorOK {
+		fmt.Printf("       -> %s\n", f.Hint)
+	}
+}
+
+func checkDatabase(ctx context.Context) (*sqlx.DB, doctorFinding) {
+	ctx, cancel := context.WithTimeout(ctx, doctorCheckTimeout)
+	defer cancel()
+
+	db, err := connectDB(ctx, appConfig.DB)
+	if err != nil {
+		hint := "check that PostgreSQL is running and the [db] settings are correct"
+		if appConfig.DB.SQLite() {
+			hint = "check that the directory of the database file in db.dsn exists and is writable"
+		}
+		return nil, doctorFinding{
+			Check:   "database",
+			Status:  doctorFail,
+			Message: fmt.Sprintf("could not connect: %v", err),
+			Hint:    hint,
+		}
+	}
+	return db, doctorFinding{Check: "database", Status: doctorOK, Message: "connected"}
+}
+
+func checkMigrations(db *sqlx.DB) doctorFinding {
+	f := doctorFinding{Check: "migrations"}
+
+	latest, err := latestMigration()
+	if err != nil {
+		f.Status, f.Message = doctorFail, err.Error()
+		return f
+	}
+
+	m, err := newMigrate(db)
+	if err != nil {
+		f.Status, f.Message = doctorFail, err.Error()
+		return f
+	}
+
+	version, dirty, err := m.Version()
+	switch {
+	case errors.Is(err, migrate.ErrNilVersion):
+		f.Status, f.Message = doctorFail, "database has no schema"
+		f.Hint = "run flowctl install or flowctl migrate up"
+	case err != nil:
+		f.Status, f.Message = doctorFail, fmt.Sprintf("could not get the schema version: %v", err)
+	case dirty:
+		f.Status, f.Message = doctorFail, fmt.Sprintf("database is dirty at version %d", version)
+		f.Hint = "fix the schema by hand and run flowctl migrate force <version>"
+	case version < latest:
+		f.Status, f.Message = doctorFail, fmt.Sprintf("schema version %d is behind the latest migration %d", version, latest)
+		f.Hint = "run flowctl migrate up"
+	case version > latest:
+		f.Status, f.Message = doctorFail, fmt.Sprintf("schema version %d is newer than the latest migration %d in this binary", version, latest)
+		f.Hint = "upgrade flowctl, or roll back the schema with a newer binary"
+	default:
+		f.Status, f.Message = doctorOK, fmt.Sprintf("schema is at the latest version %d", version)
+	}
+	return f
+}
+
+func checkKeeper(ctx context.Context) doctorFinding {
+	f := doctorFinding{
+		Check: "keeper",
+		Hint:  "check keeper_url in the [keystore] section and the credentials of the KMS",
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, doctorCheckTimeout)
+	defer cancel()
+
+	keeper, err := secrets.OpenKeeper(ctx, appConfig.Keystore.KeeperURL)
+	if err != nil {
+		f.Status, f.Message = doctorFail, fmt.Sprintf("could not open keeper: %v", err)
+		return f
+	}
+	defer keeper.Close()
+
+	ciphertext, err := keeper.Encrypt(ctx, []byte(keeperCheckValue))
+	if err != nil {
+		f.Status, f.Message = doctorFail, fmt.Sprintf("could not encrypt: %v", err)
+		return f
+	}
+	if _, err := keeper.Decrypt(ctx, ciphertext); err != nil {
+		f.Status, f.Message = doctorFail, fmt.Sprintf("could not decrypt: %v", err)
+		return f
+	}
+
+	f.Status, f.Message = doctorOK, "encrypt and decrypt work"
+	return f
+}
+
+func checkFlowsDirectory() doctorFinding {
+	f := doctorFinding{Check: "flows directory"}
+	dir := appConfig.App.FlowsDirectory
+
+	info, err := os.Stat(dir)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		f.Status, f.Message = doctorWarn, fmt.Sprintf("%s does not exist", dir)
+		f.Hint = fmt.Sprintf("it is created when a flow is saved, or create it with mkdir -p %s", dir)
+	case err != nil:
+		f.Status, f.Message = doctorFail, err.Error()
+	case !info.IsDir():
+		f.Status, f.Message = doctorFail, fmt.Sprintf("%s is not a directory", dir)
+		f.Hint = "point flows_directory in the [app] section to a directory"
+	default:
+		if err := checkWritable(dir); err != nil {
+			f.Status, f.Message = doctorFail, fmt.Sprintf("%s is not writable: %v", dir, err)
+			f.Hint = "give the user running flowctl write access to the directory"
+		} else {
+			f.Status, f.Message = doctorOK, fmt.Sprintf("%s is writable", dir)
+		}
+	}
+	return f
+}
+
+func checkLogDirectory() doctorFinding {
+	f := doctorFinding{Check: "log directory"}
+	dir := appConfig.Logger.Directory
+
+	info, err := os.Stat(dir)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		f.Status, f.Message = doctorWarn, fmt.Sprintf("%s does not exist", dir)
+		f.Hint = "flowctl start creates it, make sure the user running flowctl can write to the parent directory"
+	case err != nil:
+		f.Status, f.Message = doctorFail, err.Error()
+	case !info.IsDir():
+		f.Status, f.Message = doctorFail, fmt.Sprintf("%s is not a directory", dir)
+		f.Hint = "point log_directory in the [logger] section to a directory"
+	default:
+		if err := checkWritable(dir); err != nil {
+			f.Status, f.Message = doctorFail, fmt.Sprintf("%s is not writable: %v", dir, err)
+			f.Hint = "give the user running flowctl write access to the directory"
+		} else {
+			f.Status, f.Message = doctorOK, fmt.Sprintf("%s is writable", dir)
+		}
+	}
+	return f
+}
+
+// checkWritable creates and removes a file in dir
+func checkWritable(dir string) error {
+	tmp, err := os.CreateTemp(dir, ".flowctl-doctor-*")
+	if err != nil {
+		return err
+	}
+	tmp.Close()
+	return os.Remove(tmp.Name())
+}
+
+// checkBucket checks that the bucket at bucketURL can be accessed
+func checkBucket(ctx context.Context, check string, bucketURL string, hint string) doctorFinding {
+	f := doctorFinding{
+		Check: check,
+		Hint:  hint,
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, doctorCheckTimeout)
+	defer cancel()
+
+	bucket, err := blob.OpenBucket(ctx, bucketURL)
+	if err != nil {
+		f.Status, f.Message = doctorFail, fmt.Sprintf("could not open bucket: %v", err)
+		return f
+	}
+	defer bucket.Close()
+
+	ok, err := bucket.IsAccessible(ctx)
+	if err != nil || !ok {
+		f.Status, f.Message = doctorFail, fmt.Sprintf("bucket is not accessible: %v", err)
+		return f
+	}
+
+	f.Status, f.Message = doctorOK, "bucket is accessible"
+	return f
+}
+
+// checkDocker pings the local Docker daemon used by the docker executor
+func checkDocker(ctx context.Context) doctorFinding {
+	f := doctorFinding{
+		Check: "docker",
+		Hint:  "start Docker or set DOCKER_HOST, this is only needed for the docker executor on the local node",
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, doctorCheckTimeout)
+	defer cancel()
+
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		f.Status, f.Message = doctorWarn, fmt.Sprintf("could not create client: %v", err)
+		return f
+	}
+	defer cli.Close()
+
+	ping, err := cli.Ping(ctx)
+	if err != nil {
+		f.Status, f.Message = doctorWarn, fmt.Sprintf("daemon at %s is not reachable: %v", cli.DaemonHost(), err)
+		return f
+	}
+
+	f.Status, f.Message = doctorOK, fmt.Sprintf("daemon at %s is reachable (API %s)", cli.DaemonHost(), ping.APIVersion)
+	return f
+}
+
+// checkNodes checks that the SSH port of every node is reachable from this host
+func checkNodes(ctx context.Context, db *sqlx.DB) []doctorFinding {
+	nodes, err := newStore(db).ListNodeAddresses(ctx)
+	if err != nil {
+		return []doctorFinding{{Check: "nodes", Status: doctorFail, Message: fmt.Sprintf("could not list nodes: %v", err)}}
+	}
+	if len(nodes) == 0 {
+		return []doctorFinding{{Check: "nodes", Status: doctorOK, Message: "no nodes configured"}}
+	}
+
+	var (
+		findings []doctorFinding
+		dialer   = net.Dialer{Timeout: doctorCheckTimeout}
+	)
+	for _, n := range nodes {
+		f := doctorFinding{Check: fmt.Sprintf("node %s/%s", n.NamespaceName, n.Name)}
+		addr := net.JoinHostPort(n.Hostname, strconv.Itoa(int(n.Port)))
+
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			f.Status, f.Message = doctorWarn, fmt.Sprintf("%s is not reachable: %v", addr, err)
+			f.Hint = "check the hostname and port of the node and any firewalls between this host and the node"
+		} else {
+			conn.Close()
+			f.Status, f.Message = doctorOK, fmt.Sprintf("%s is reachable", addr)
+		}
+		findings = append(findings, f)
+	}
+	return findings
+}
+
+func init() {
+	doctorCmd.Flags().Bool("skip-ssh", false, "Don't check the reachability of nodes")
+	rootCmd.AddCommand(doctorCmd)
+}
@@ -0,0 +1,275 @@
+package cmd
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	archiveVersion = 1
+
+	archiveManifestFile    = "manifest.json"
+	archiveNodesFile       = "nodes.json"
+	archiveCredentialsFile = "credentials.json"
+	archiveFlowsDir        = "flows/"
+
+	// exportPageSize is the number of items fetched per request when listing resources
+	exportPageSize = 100
+)
+
+// archiveManifest describes the contents of an export archive
+type archiveManifest struct {
+	Version    int       `json:"version"`
+	Namespace  string    `json:"namespace"`
+	ExportedAt time.Time `json:"exported_at"`
+	Flows      []string  `json:"flows"`
+}
+
+// archiveNode is an exported node. The credential is referenced by name since
+// credential IDs differ between servers.
+type archiveNode struct {
+	Name           string   `json:"name"`
+	Hostname       string   `json:"hostname"`
+	Port           int      `json:"port"`
+	Username       string   `json:"username"`
+	ConnectionType string   `json:"connection_type"`
+	OSFamily       string   `json:"os_family,omitempty"`
+	Tags           []string `json:"tags"`
+	AuthMethod     string   `json:"auth_method"`
+	CredentialName string   `json:"credential_name"`
+}
+
+// archiveCredential is the metadata of an exported credential. Key data is never exported.
+type archiveCredential struct {
+	Name    string `json:"name"`
+	KeyType string `json:"key_type"`
+}
+
+type nodeResp struct {
+	ID             string   `json:"id"`
+	Name           string   `json:"name"`
+	Hostname       string   `json:"hostname"`
+	Port           int      `json:"port"`
+	Username       string   `json:"username"`
+	ConnectionType string   `json:"connection_type"`
+	OSFamily       string   `json:"os_family"`
+	Tags           []string `json:"tags"`
+	Auth           nodeAuth `json:"auth"`
+}
+
+type nodeAuth struct {
+	Method       string `json:"method"`
+	CredentialID string `json:"credential_id"`
+}
+
+type credentialResp struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	KeyType string `json:"key_type"`
+}
+
+// exportCmd exports the flows of a namespace to an archive that can be imported into another server
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the flows of a namespace on a remote flowctl server to an archive",
+	Example: `  flowctl export -n staging -o staging.tar.gz --nodes --credentials
+  flowctl import staging.tar.gz -n production --server https://prod.flowctl.example.com`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newAPIClient(cmd)
+		if err != nil {
+			return err
+		}
+
+		namespace, _ := cmd.Flags().GetString("namespace")
+		output, _ := cmd.Flags().GetString("output")
+		withNodes, _ := cmd.Flags().GetBool("nodes")
+		withCredentials, _ := cmd.Flags().GetBool("credentials")
+		if output == "" {
+			output = namespace + ".tar.gz"
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		ns := url.PathEscape(namespace)
+		files := make(map[string][]byte)
+		manifest := archiveManifest{
+			Version:    archiveVersion,
+			Namespace:  namespace,
+			ExportedAt: time.Now().UTC(),
+		}
+
+		var flows []struct {
+			ID string `json:"id"`
+		}
+		if err := listAll(ctx, client, fmt.Sprintf("/api/v1/%s/flows", ns), "flows", &flows); err != nil {
+			return fmt.Errorf("could not list flows: %w", err)
+		}
+		for _, f := range flows {
+			config, err := client.do(ctx, http.MethodGet, fmt.Sprintf("/api/v1/%s/flows/%s/config", ns, url.PathEscape(f.ID)), nil, "")
+			if err != nil {
+				return fmt.Errorf("could not get config of flow %s: %w", f.ID, err)
+			}
+			files[archiveFlowsDir+f.ID+".json"] = config
+			manifest.Flows = append(manifest.Flows, f.ID)
+		}
+
+		// Credentials are needed to resolve the credential names of nodes
+		var credentials []credentialResp
+		if withNodes || withCredentials {
+			if err := listAll(ctx, client, fmt.Sprintf("/api/v1/%s/credentials", ns), "credentials", &credentials); err != nil {
+				return fmt.Errorf("could not list credentials: %w", err)
+			}
+		}
+
+		if withCredentials {
+			exported := make([]archiveCredential, 0, len(credentials))
+			for _, c := range credentials {
+				exported = append(exported, archiveCredential{Name: c.Name, KeyType: c.KeyType})
+			}
+			if files[archiveCredentialsFile], err = json.MarshalIndent(exported, "", "  "); err != nil {
+				return err
+			}
+		}
+
+		if withNodes {
+			credentialNames := make(map[string]string, len(credentials))
+			for _, c := range credentials {
+				credentialNames[c.ID] = c.Name
+			}
+
+			var nodes []nodeResp
+			if err := listAll(ctx, client, fmt.Sprintf("/api/v1/%s/nodes", ns), "nodes", &nodes); err != nil {
+				return fmt.Errorf("could not list nodes: %w", err)
+			}
+
+			exported := make([]archiveNode, 0, len(nodes))
+			for _, n := range nodes {
+				exported = append(exported, archiveNode{
+					Name:           n.Name,
+					Hostname:       n.Hostname,
+					Port:           n.Port,
+					Username:       n.Username,
+					ConnectionType: n.ConnectionType,
+					OSFamily:       n.OSFamily,
+					Tags:           n.Tags,
+					AuthMethod:     n.Auth.Method,
+					CredentialName: credentialNames[n.Auth.CredentialID],
+				})
+			}
+			if files[archiveNodesFile], err = json.MarshalIndent(exported, "", "  "); err != nil {
+				return err
+			}
+		}
+
+		if files[archiveManifestFile], err = json.MarshalIndent(manifest, "", "  "); err != nil {
+			return err
+		}
+
+		if err := writeArchive(output, files); err != nil {
+			return fmt.Errorf("could not write archive: %w", err)
+		}
+
+		fmt.Fprintf(os.Stderr, "exported %d flows from %s to %s\n", len(manifest.Flows), namespace, output)
+		return nil
+	},
+}
+
+// listAll fetches all pages of a paginated list endpoint and decodes the items under key into out
+func listAll[T any](ctx context.Context, client *apiClient, path string, key string, out *[]T) error {
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+
+	for page := 1; ; page++ {
+		var resp map[string]json.RawMessage
+		if err := client.getJSON(ctx, fmt.Sprintf("%s%spage=%d&count_per_page=%d", path, sep, page, exportPageSize), &resp); err != nil {
+			return err
+		}
+
+		var items []T
+		if raw, ok := resp[key]; ok {
+			if err := json.Unmarshal(raw, &items); err != nil {
+				return fmt.Errorf("failed to decode response: %w", err)
+			}
+		}
+		*out = append(*out, items...)
+
+		var pageCount int
+		if err := json.Unmarshal(resp["page_count"], &pageCount); err != nil || page >= pageCount || len(items) == 0 {
+			return nil
+		}
+	}
+}
+
+// writeArchive writes files to a gzip compressed tar archive at path. The manifest is written first.
+func writeArchive(path string, files map[string][]byte) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if names[i] == archiveManifestFile || names[j] == archiveManifestFile {
+			return names[i] == archiveManifestFile
+		}
+		return names[i] < names[j]
+	})
+
+	now := time.Now()
+	for _, name := range names {
+		data := files[name]
+		if err := tw.WriteHeader(&tar.Header{
+			Name:    name,
+			Mode:    0600,
+			Size:    int64(len(data)),
+			ModTime: now,
+		}); err != nil {
+			return err
+		}
+		if _, err := tw.Write(data); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gw.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+func init() {
+	addRemoteFlags(exportCmd)
+	exportCmd.Flags().StringP("namespace", "n", "default", "Namespace to export")
+	exportCmd.Flags().StringP("output", "o", "", "Path of the archive (default <namespace>.tar.gz)")
+	exportCmd.Flags().Bool("nodes", false, "Include nodes in the archive")
+	exportCmd.Flags().Bool("credentials", false, "Include credential names and types in the archive, key data is never exported")
+	rootCmd.AddCommand(exportCmd)
+}
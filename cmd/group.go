@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// cliGroup is a group as printed by the group commands
+type cliGroup struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Users       []cliUser `json:"users"`
+}
+
+// groupCmd groups the group management commands. Like the user commands, they use the API
+// of a remote server if --server is set and the database in the config file otherwise.
+var groupCmd = &cobra.Command{
+	Use:   "group",
+	Short: "Manage groups",
+}
+
+var groupCreateCmd = &cobra.Command{
+	Use:          "create <name>",
+	Short:        "Create a group",
+	Example:      `  flowctl group create developers --description "Application developers"`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		description, _ := cmd.Flags().GetString("description")
+		ctx := context.Background()
+
+		if useAPI(cmd) {
+			client, err := newAPIClient(cmd)
+			if err != nil {
+				return err
+			}
+
+			var group cliGroup
+			if err := client.sendJSON(ctx, http.MethodPost, "/api/v1/groups", map[string]any{
+				"name":        args[0],
+				"description": description,
+			}, &group); err != nil {
+				return fmt.Errorf("could not create group: %w", err)
+			}
+
+			fmt.Println(group.ID)
+			return nil
+		}
+
+		co, closeDB, err := openAdminCore(cmd)
+		if err != nil {
+			return err
+		}
+		defer closeDB()
+
+		group, err := co.CreateGroup(ctx, args[0], description)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(group.ID)
+		return nil
+	},
+}
+
+var groupListCmd = &cobra.Command{
+	Use:          "list",
+	Short:        "List groups",
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		var groups []cliGroup
+		if useAPI(cmd) {
+			client, err := newAPIClient(cmd)
+			if err != nil {
+				return err
+			}
+			if err := listAll(ctx, client, "/api/v1/groups", "groups", &groups); err != nil {
+				return fmt.Errorf("could not list groups: %w", err)
+			}
+		} else {
+			co, closeDB, err := openAdminCore(cmd)
+			if err != nil {
+				return err
+			}
+			defer closeDB()
+
+			for offset := 0; ; offset += adminPageSize {
+				page, _, _, err := co.SearchGroup(ctx, "", adminPageSize, offset)
+				if err != nil {
+					return fmt.Errorf("could not list groups: %w", err)
+				}
+				for _, g := range page {
+					groups = append(groups, cliGroup{
+						ID:          g.ID,
+						Name:        g.Name,
+						Description: g.Description,
+						Users:       make([]cliUser, len(g.Users)),
+					})
+				}
+				if len(page) < adminPageSize {
+					break
+				}
+			}
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME\tUSERS\tDESCRIPTION")
+		for _, g := range groups {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", g.ID, g.Name, len(g.Users), g.Description)
+		}
+		return w.Flush()
+	},
+}
+
+func init() {
+	for _, c := range []*cobra.Command{groupCreateCmd, groupListCmd} {
+		addAdminFlags(c)
+		groupCmd.AddCommand(c)
+	}
+
+	groupCreateCmd.Flags().String("description", "", "Description of the group")
+
+	rootCmd.AddCommand(groupCmd)
+}
@@ -0,0 +1,284 @@
+package cmd
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+)
+
+// maxArchiveFileSize limits the size of a single file read from an archive
+const maxArchiveFileSize = 16 * 1024 * 1024
+
+// flowConfig is the flow configuration returned by the flow config endpoint. Only the
+// metadata is decoded, the rest is passed through to the target server as is.
+type flowConfig struct {
+	Metadata struct {
+		ID              string          `json:"id"`
+		Name            string          `json:"name"`
+		Description     string          `json:"description"`
+		Prefix          string          `json:"prefix"`
+		Schedules       json.RawMessage `json:"schedules"`
+		AllowOverlap    bool            `json:"allow_overlap"`
+		UserSchedulable bool            `json:"user_schedulable"`
+	} `json:"metadata"`
+	Inputs  json.RawMessage `json:"inputs"`
+	Actions json.RawMessage `json:"actions"`
+	Notify  json.RawMessage `json:"notify"`
+}
+
+// importCmd imports an archive created by export into a namespace
+var importCmd = &cobra.Command{
+	Use:   "import <archive>",
+	Short: "Import an archive created by flowctl export into a namespace on a remote flowctl server",
+	Example: `  flowctl import staging.tar.gz -n production
+  flowctl import staging.tar.gz -n production --overwrite`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newAPIClient(cmd)
+		if err != nil {
+			return err
+		}
+
+		namespace, _ := cmd.Flags().GetString("namespace")
+		overwrite, _ := cmd.Flags().GetBool("overwrite")
+
+		files, err := readArchive(args[0])
+		if err != nil {
+			return fmt.Errorf("could not read archive: %w", err)
+		}
+
+		var manifest archiveManifest
+		if err := json.Unmarshal(files[archiveManifestFile], &manifest); err != nil {
+			return fmt.Errorf("invalid archive, could not read manifest: %w", err)
+		}
+		if manifest.Version != archiveVersion {
+			return fmt.Errorf("unsupported archive version %d", manifest.Version)
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		ns := url.PathEscape(namespace)
+
+		// Credentials can't be imported since the archive has no key data. Report the ones
+		// that have to be created before nodes using them can be imported.
+		var credentials []credentialResp
+		if err := listAll(ctx, client, fmt.Sprintf("/api/v1/%s/credentials", ns), "credentials", &credentials); err != nil {
+			return fmt.Errorf("could not list credentials: %w", err)
+		}
+		credentialIDs := make(map[string]string, len(credentials))
+		for _, c := range credentials {
+			credentialIDs[c.Name] = c.ID
+		}
+
+		if data, ok := files[archiveCredentialsFile]; ok {
+			var exported []archiveCredential
+			if err := json.Unmarshal(data, &exported); err != nil {
+				return fmt.Errorf("invalid archive, could not read credentials: %w", err)
+			}
+			for _, c := range exported {
+				if _, ok := credentialIDs[c.Name]; !ok {
+					fmt.Fprintf(os.Stderr, "credential %s (%s) does not exist in %s, create it before importing nodes that use it\n", c.Name, c.KeyType, namespace)
+				}
+			}
+		}
+
+		if data, ok := files[archiveNodesFile]; ok {
+			if err := importNodes(ctx, client, ns, data, credentialIDs, overwrite); err != nil {
+				return err
+			}
+		}
+
+		var imported, skipped int
+		for _, id := range manifest.Flows {
+			data, ok := files[archiveFlowsDir+id+".json"]
+			if !ok {
+				return fmt.Errorf("invalid archive, flow %s is missing", id)
+			}
+
+			created, err := importFlow(ctx, client, ns, id, data, overwrite)
+			if err != nil {
+				return fmt.Errorf("could not import flow %s: %w", id, err)
+			}
+			if created {
+				imported++
+			} else {
+				skipped++
+			}
+		}
+
+		fmt.Fprintf(os.Stderr, "imported %d flows into %s, skipped %d existing flows\n", imported, namespace, skipped)
+		return nil
+	},
+}
+
+// importFlow creates the flow, or updates it if it exists and overwrite is set.
+// Returns false if an existing flow was skipped.
+func importFlow(ctx context.Context, client *apiClient, ns string, id string, data []byte, overwrite bool) (bool, error) {
+	var config flowConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return false, fmt.Errorf("invalid flow config: %w", err)
+	}
+
+	flowPath := fmt.Sprintf("/api/v1/%s/flows/%s", ns, url.PathEscape(id))
+	_, err := client.do(ctx, http.MethodGet, flowPath+"/config", nil, "")
+	if err != nil && !isNotFound(err) {
+		return false, err
+	}
+
+	if isNotFound(err) {
+		var resp struct {
+			ID string `json:"id"`
+		}
+		if err := client.sendJSON(ctx, http.MethodPost, fmt.Sprintf("/api/v1/%s/flows", ns), json.RawMessage(data), &resp); err != nil {
+			return false, err
+		}
+		if resp.ID != id {
+			fmt.Fprintf(os.Stderr, "flow %s was imported as %s\n", id, resp.ID)
+		}
+		fmt.Fprintf(os.Stderr, "created flow %s\n", resp.ID)
+		return true, nil
+	}
+
+	if !overwrite {
+		fmt.Fprintf(os.Stderr, "flow %s already exists, skipping\n", id)
+		return false, nil
+	}
+
+	update := map[string]any{
+		"prefix":           config.Metadata.Prefix,
+		"schedules":        config.Metadata.Schedules,
+		"notify":           config.Notify,
+		"allow_overlap":    config.Metadata.AllowOverlap,
+		"user_schedulable": config.Metadata.UserSchedulable,
+		"description":      config.Metadata.Description,
+		"inputs":           config.Inputs,
+		"actions":          config.Actions,
+	}
+	if err := client.sendJSON(ctx, http.MethodPut, flowPath, update, nil); err != nil {
+		return false, err
+	}
+
+	fmt.Fprintf(os.Stderr, "updated flow %s\n", id)
+	return true, nil
+}
+
+// importNodes creates the nodes in the archive whose credentials exist in the namespace.
+// Existing nodes are matched by name and only updated if overwrite is set.
+func importNodes(ctx context.Context, client *apiClient, ns string, data []byte, credentialIDs map[string]string, overwrite bool) error {
+	var exported []archiveNode
+	if err := json.Unmarshal(data, &exported); err != nil {
+		return fmt.Errorf("invalid archive, could not read nodes: %w", err)
+	}
+
+	var nodes []nodeResp
+	if err := listAll(ctx, client, fmt.Sprintf("/api/v1/%s/nodes", ns), "nodes", &nodes); err != nil {
+		return fmt.Errorf("could not list nodes: %w", err)
+	}
+	nodeIDs := make(map[string]string, len(nodes))
+	for _, n := range nodes {
+		nodeIDs[n.Name] = n.ID
+	}
+
+	for _, n := range exported {
+		credentialID, ok := credentialIDs[n.CredentialName]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "skipping node %s, credential %q does not exist\n", n.Name, n.CredentialName)
+			continue
+		}
+
+		req := map[string]any{
+			"name":            n.Name,
+			"hostname":        n.Hostname,
+			"port":            n.Port,
+			"username":        n.Username,
+			"connection_type": n.ConnectionType,
+			"os_family":       n.OSFamily,
+			"tags":            n.Tags,
+			"auth": nodeAuth{
+				Method:       n.AuthMethod,
+				CredentialID: credentialID,
+			},
+		}
+
+		if id, ok := nodeIDs[n.Name]; ok {
+			if !overwrite {
+				fmt.Fprintf(os.Stderr, "node %s already exists, skipping\n", n.Name)
+				continue
+			}
+			if err := client.sendJSON(ctx, http.MethodPut, fmt.Sprintf("/api/v1/%s/nodes/%s", ns, url.PathEscape(id)), req, nil); err != nil {
+				return fmt.Errorf("could not update node %s: %w", n.Name, err)
+			}
+			fmt.Fprintf(os.Stderr, "updated node %s\n", n.Name)
+			continue
+		}
+
+		if err := client.sendJSON(ctx, http.MethodPost, fmt.Sprintf("/api/v1/%s/nodes", ns), req, nil); err != nil {
+			return fmt.Errorf("could not create node %s: %w", n.Name, err)
+		}
+		fmt.Fprintf(os.Stderr, "created node %s\n", n.Name)
+	}
+
+	return nil
+}
+
+// readArchive reads all files of a gzip compressed tar archive
+func readArchive(path string) (map[string][]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	defer gr.Close()
+
+	files := make(map[string][]byte)
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if hdr.Size > maxArchiveFileSize {
+			return nil, fmt.Errorf("file %s in archive is too large", hdr.Name)
+		}
+
+		data, err := io.ReadAll(io.LimitReader(tr, maxArchiveFileSize))
+		if err != nil {
+			return nil, err
+		}
+		files[strings.TrimPrefix(hdr.Name, "./")] = data
+	}
+
+	return files, nil
+}
+
+func init() {
+	addRemoteFlags(importCmd)
+	importCmd.Flags().StringP("namespace", "n", "default", "Namespace to import into")
+	importCmd.Flags().Bool("overwrite", false, "Update flows and nodes that already exist")
+	rootCmd.AddCommand(importCmd)
+}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	"github.com/cvhariharan/flowctl/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// exampleFlowID must match the id in configs/example-flow.yaml
+const exampleFlowID = "hello_world"
+
+// initCmd scaffolds a config file and a flows directory with an example flow
+var initCmd = &cobra.Command{
+	Use:   "init [directory]",
+	Short: "Create a config file and flows directory to get started",
+	Long: `Create a commented config.toml, the flows directory and an example flow in the given
+directory, or the current directory if none is given.
+
+Flows are laid out as <flows_directory>/<namespace>/<flow id>/flow.yaml. The example flow is
+created in the default namespace and uses the script executor, so it runs without Docker or
+remote nodes. Existing files are never overwritten unless --force is set.`,
+	Example: `  flowctl init my-flowctl
+  cd my-flowctl && flowctl install && flowctl start`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir := "."
+		if len(args) > 0 {
+			dir = args[0]
+		}
+
+		force, _ := cmd.Flags().GetBool("force")
+		noExample, _ := cmd.Flags().GetBool("no-example")
+		logDir, _ := cmd.Flags().GetString("log-directory")
+
+		cfg := config.GetDefaultConfig()
+		cfg.Logger.Directory = logDir
+
+		flowsDir := filepath.Join(dir, cfg.App.FlowsDirectory)
+		if err := os.MkdirAll(filepath.Join(flowsDir, "default"), 0755); err != nil {
+			return fmt.Errorf("could not create flows directory: %w", err)
+		}
+
+		configPath := filepath.Join(dir, "config.toml")
+		if err := writeNewFile(configPath, 0600, force, func(w io.Writer) error {
+			return renderConfig(w, cfg)
+		}); err != nil {
+			return err
+		}
+		fmt.Printf("created %s\n", configPath)
+
+		if !noExample {
+			flowPath := filepath.Join(flowsDir, "default", exampleFlowID, "flow.yaml")
+			if err := os.MkdirAll(filepath.Dir(flowPath), 0755); err != nil {
+				return fmt.Errorf("could not create flow directory: %w", err)
+			}
+
+			example, err := StaticFiles.ReadFile("configs/example-flow.yaml")
+			if err != nil {
+				return err
+			}
+			if err := writeNewFile(flowPath, 0644, force, func(w io.Writer) error {
+				_, err := w.Write(example)
+				return err
+			}); err != nil {
+				return err
+			}
+			fmt.Printf("created %s\n", flowPath)
+		}
+
+		fmt.Println()
+		fmt.Println("Next steps:")
+		if dir != "." {
+			fmt.Printf("  cd %s\n", dir)
+		}
+		fmt.Println("  # set the [db] section in config.toml to point to a PostgreSQL database or a SQLite file")
+		fmt.Println("  flowctl install")
+		fmt.Println("  flowctl start")
+		fmt.Printf("  # log in at %s as %s, the password is in config.toml\n", cfg.App.RootURL, cfg.App.AdminUsername)
+		return nil
+	},
+}
+
+// renderConfig writes a commented config file with the values from cfg
+func renderConfig(w io.Writer, cfg config.Config) error {
+	tmpl, err := template.ParseFS(StaticFiles, "configs/*.toml")
+	if err != nil {
+		return err
+	}
+	return tmpl.ExecuteTemplate(w, "config.sample.toml", cfg)
+}
+
+// writeNewFile creates path with perm and writes to it with write. It fails if path already exists, unless force is set.
+func writeNewFile(path string, perm os.FileMode, force bool, write func(io.Writer) error) error {
+	flags := os.O_CREATE | os.O_WRONLY | os.O_EXCL
+	if force {
+		flags = os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	}
+
+	f, err := os.OpenFile(path, flags, perm)
+	if errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("%s already exists, use --force to overwrite it", path)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := write(f); err != nil {
+		f.Close()
+		return fmt.Errorf("could not write %s: %w", path, err)
+	}
+	return f.Close()
+}
+
+func init() {
+	initCmd.Flags().Bool("force", false, "Overwrite existing files")
+	initCmd.Flags().Bool("no-example", false, "Don't create the example flow")
+	initCmd.Flags().String("log-directory", "logs", "Directory for execution logs, relative to where flowctl runs")
+	rootCmd.AddCommand(initCmd)
+}
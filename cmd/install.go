@@ -5,13 +5,10 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	"io/fs"
 	"log"
 
 	"github.com/cvhariharan/flowctl/internal/repo"
 	"github.com/golang-migrate/migrate/v4"
-	"github.com/golang-migrate/migrate/v4/database/postgres"
-	"github.com/golang-migrate/migrate/v4/source/iofs"
 	"github.com/jmoiron/sqlx"
 	"github.com/spf13/cobra"
 	"golang.org/x/crypto/bcrypt"
@@ -27,7 +24,15 @@ var installCmd = &cobra.Command{
 			log.Fatal(err)
 		}
 
-		db, err := sqlx.Connect("postgres", fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=disable", appConfig.DB.User, appConfig.DB.Password, appConfig.DB.Host, appConfig.DB.Port, appConfig.DB.DBName))
+		var (
+			db  *sqlx.DB
+			err error
+		)
+		if appConfig.DB.SQLite() {
+			db, err = connectDB(context.Background(), appConfig.DB)
+		} else {
+			db, err = sqlx.Connect("postgres", fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=disable", appConfig.DB.User, appConfig.DB.Password, appConfig.DB.Host, appConfig.DB.Port, appConfig.DB.DBName))
+		}
 		if err != nil {
 			log.Fatalf("could not connect to database: %v", err)
 		}
@@ -37,7 +42,7 @@ var installCmd = &cobra.Command{
 			log.Fatal(err)
 		}
 
-		s := repo.NewPostgresStore(db)
+		s := newStore(db)
 		if err := initAdmin(s); err != nil {
 			log.Fatal(err)
 		}
@@ -50,24 +55,9 @@ func init() {
 
 // initDB performs DB migrations and can be safely run multiple times
 func initDB(db *sqlx.DB) error {
-	driver, err := postgres.WithInstance(db.DB, &postgres.Config{})
-	if err != nil {
-		return fmt.Errorf("failed to create postgres driver instance: %w", err)
-	}
-
-	migrationsFS, err := fs.Sub(StaticFiles, "migrations")
+	m, err := newMigrate(db)
 	if err != nil {
-		return fmt.Errorf("failed to get migrations sub-filesystem: %w", err)
-	}
-
-	sourceDriver, err := iofs.New(migrationsFS, ".")
-	if err != nil {
-		return fmt.Errorf("failed to create iofs source driver: %w", err)
-	}
-
-	m, err := migrate.NewWithInstance("iofs", sourceDriver, "postgres", driver)
-	if err != nil {
-		return fmt.Errorf("failed to create migration instance: %w", err)
+		return err
 	}
 
 	// Get current version before attempting migration
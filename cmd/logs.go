@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+)
+
+// logMessage is a log message sent by the log streaming endpoint
+type logMessage struct {
+	ActionID  string            `json:"action_id"`
+	MType     string            `json:"message_type"`
+	NodeID    string            `json:"node_id"`
+	Value     string            `json:"value"`
+	Timestamp string            `json:"timestamp"`
+	Results   map[string]string `json:"results,omitempty"`
+}
+
+// logsCmd streams the logs of an execution from a remote server
+var logsCmd = &cobra.Command{
+	Use:   "logs <exec-id>",
+	Short: "Print the logs of an execution on a remote flowctl server",
+	Example: `  flowctl logs 6f1c2a0e-3b4d-4c8e-9a57-2d1f0b7e8c91 -n production -f
+  flowctl logs 6f1c2a0e-3b4d-4c8e-9a57-2d1f0b7e8c91 --action build -o json`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newAPIClient(cmd)
+		if err != nil {
+			return err
+		}
+
+		namespace, _ := cmd.Flags().GetString("namespace")
+		follow, _ := cmd.Flags().GetBool("follow")
+		action, _ := cmd.Flags().GetString("action")
+		output, _ := cmd.Flags().GetString("output")
+		stripANSI, _ := cmd.Flags().GetBool("strip-ansi")
+
+		if output != "plain" && output != "json" {
+			return fmt.Errorf("invalid output %q, expected plain or json", output)
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		execID := args[0]
+		ns := url.PathEscape(namespace)
+
+		// The log stream only ends when the execution finishes, so without --follow
+		// logs can only be printed for finished executions
+		if !follow {
+			var exec executionStatusResp
+			if err := client.getJSON(ctx, fmt.Sprintf("/api/v1/%s/flows/executions/%s", ns, url.PathEscape(execID)), &exec); err != nil {
+				return fmt.Errorf("could not get execution: %w", err)
+			}
+			switch exec.Status {
+			case "completed", "errored", "cancelled":
+			default:
+				return fmt.Errorf("execution %s is %s, use --follow to stream its logs", execID, exec.Status)
+			}
+		}
+
+		query := url.Values{}
+		if stripANSI {
+			query.Set("ansi", "strip")
+		}
+		path := fmt.Sprintf("/api/v1/%s/logs/%s", ns, url.PathEscape(execID))
+		if len(query) > 0 {
+			path += "?" + query.Encode()
+		}
+
+		body, err := client.stream(ctx, path, "text/event-stream")
+		if err != nil {
+			return fmt.Errorf("could not stream logs: %w", err)
+		}
+		defer body.Close()
+
+		enc := json.NewEncoder(os.Stdout)
+		err = readLogEvents(body, func(msg logMessage) error {
+			if action != "" && msg.ActionID != action {
+				return nil
+			}
+			if output == "json" {
+				return enc.Encode(msg)
+			}
+			printLogMessage(os.Stdout, msg)
+			return nil
+		})
+		if err != nil && ctx.Err() == nil {
+			return fmt.Errorf("could not read logs: %w", err)
+		}
+
+		return nil
+	},
+}
+
+// readLogEvents reads server sent events from r and calls fn for each log message
+// until the server sends the end event.
+func readLogEvents(r io.Reader, fn func(logMessage) error) error {
+	reader := bufio.NewReader(r)
+
+	var (
+		event string
+		data  strings.Builder
+	)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return errors.New("log stream closed before the execution finished")
+			}
+			return err
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		switch {
+		case line == "":
+			// A blank line dispatches the event
+			if event == "end" {
+				return nil
+			}
+			if data.Len() > 0 {
+				var msg logMessage
+				if err := json.Unmarshal([]byte(data.String()), &msg); err != nil {
+					return fmt.Errorf("could not decode log message: %w", err)
+				}
+				if err := fn(msg); err != nil {
+					return err
+				}
+			}
+			event = ""
+			data.Reset()
+		case strings.HasPrefix(line, ":"):
+			// Comments are heartbeats
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+}
+
+// printLogMessage prints a log message as a line of text. Progress messages are skipped
+// since they repeat what is already in the logs.
+func printLogMessage(w io.Writer, msg logMessage) {
+	prefix := msg.Timestamp
+	if msg.ActionID != "" {
+		prefix += " [" + msg.ActionID + "]"
+	}
+	if msg.NodeID != "" {
+		prefix += " [" + msg.NodeID + "]"
+	}
+
+	switch msg.MType {
+	case "progress":
+		return
+	case "result":
+		keys := make([]string, 0, len(msg.Results))
+		for k := range msg.Results {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(w, "%s result: %s=%s\n", prefix, k, msg.Results[k])
+		}
+	case "log":
+		for _, line := range strings.Split(strings.TrimRight(msg.Value, "\n"), "\n") {
+			fmt.Fprintf(w, "%s %s\n", prefix, line)
+		}
+	default:
+		fmt.Fprintf(w, "%s %s: %s\n", prefix, msg.MType, strings.TrimRight(msg.Value, "\n"))
+	}
+}
+
+func init() {
+	addRemoteFlags(logsCmd)
+	logsCmd.Flags().StringP("namespace", "n", "default", "Namespace of the execution")
+	logsCmd.Flags().BoolP("follow", "f", false, "Stream the logs until the execution finishes")
+	logsCmd.Flags().String("action", "", "Only print logs of the action with this ID")
+	logsCmd.Flags().StringP("output", "o", "plain", "Output format: plain or json")
+	logsCmd.Flags().Bool("strip-ansi", false, "Remove ANSI escape codes from the logs")
+	rootCmd.AddCommand(logsCmd)
+}
@@ -0,0 +1,301 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jmoiron/sqlx"
+	"github.com/spf13/cobra"
+)
+
+// embeddedMigration is a migration embedded in the binary
+type embeddedMigration struct {
+	Version uint
+	Name    string
+}
+
+// migrateCmd groups the commands that manage the database schema
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Manage database migrations",
+}
+
+var migrateUpCmd = &cobra.Command{
+	Use:          "up",
+	Short:        "Apply all pending migrations",
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		m, closeDB, err := openMigrate(cmd)
+		if err != nil {
+			return err
+		}
+		defer closeDB()
+
+		if err := checkDirty(m); err != nil {
+			return err
+		}
+
+		if err := m.Up(); err != nil {
+			if errors.Is(err, migrate.ErrNoChange) {
+				fmt.Println("database is up to date")
+				return nil
+			}
+			return fmt.Errorf("failed to run migrations: %w", err)
+		}
+
+		version, _, _ := m.Version()
+		fmt.Printf("migrated to version %d\n", version)
+		return nil
+	},
+}
+
+var migrateStatusCmd = &cobra.Command{
+	Use:          "status",
+	Short:        "Show the current schema version and pending migrations",
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		m, closeDB, err := openMigrate(cmd)
+		if err != nil {
+			return err
+		}
+		defer closeDB()
+
+		version, dirty, err := m.Version()
+		if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+			return fmt.Errorf("failed to get migration version: %w", err)
+		}
+
+		migrations, err := embeddedMigrations()
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("current version: %d\n", version)
+		if dirty {
+			fmt.Printf("the database is dirty, migration %d failed partway through\n", version)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tSTATUS")
+		for _, mg := range migrations {
+			status := "applied"
+			switch {
+			case mg.Version > version:
+				status = "pending"
+			case mg.Version == version && dirty:
+				status = "dirty"
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\n", mg.Version, mg.Name, status)
+		}
+		return w.Flush()
+	},
+}
+
+var migrateDownCmd = &cobra.Command{
+	Use:          "down",
+	Short:        "Roll back migrations to a version",
+	Example:      `  flowctl migrate down --to 15`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		to, _ := cmd.Flags().GetUint("to")
+		yes, _ := cmd.Flags().GetBool("yes")
+		if to == 0 {
+			return errors.New("--to is required and must be at least 1")
+		}
+
+		m, closeDB, err := openMigrate(cmd)
+		if err != nil {
+			return err
+		}
+		defer closeDB()
+
+		if err := checkDirty(m); err != nil {
+			return err
+		}
+
+		version, _, err := m.Version()
+		if err != nil {
+			return fmt.Errorf("failed to get migration version: %w", err)
+		}
+		if to >= version {
+			return fmt.Errorf("database is at version %d, nothing to roll back", version)
+		}
+
+		if !yes && !confirm(fmt.Sprintf("Roll back the database from version %d to %d? Data in the rolled back tables will be lost", version, to)) {
+			return errors.New("aborted")
+		}
+
+		if err := m.Migrate(to); err != nil {
+			return fmt.Errorf("failed to roll back migrations: %w", err)
+		}
+
+		fmt.Printf("rolled back to version %d\n", to)
+		return nil
+	},
+}
+
+var migrateForceCmd = &cobra.Command{
+	Use:          "force <version>",
+	Short:        "Mark the database as being at a version without running migrations, to recover from a failed migration",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		version, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid version: %w", err)
+		}
+
+		m, closeDB, err := openMigrate(cmd)
+		if err != nil {
+			return err
+		}
+		defer closeDB()
+
+		if err := m.Force(version); err != nil {
+			return fmt.Errorf("failed to force migration version: %w", err)
+		}
+
+		fmt.Printf("forced version %d\n", version)
+		return nil
+	},
+}
+
+// migrationsDir returns the directory of the embedded migrations of the configured database.
+// SQLite databases start from a single migration with the schema of the postgres migrations
+// of the same version.
+func migrationsDir() string {
+	if appConfig.DB.SQLite() {
+		return "migrations_sqlite"
+	}
+	return "migrations"
+}
+
+// newMigrate creates a migrate instance that uses the migrations embedded in the binary
+func newMigrate(db *sqlx.DB) (*migrate.Migrate, error) {
+	var (
+		driverName = "postgres"
+		driver     database.Driver
+		err        error
+	)
+	if appConfig.DB.SQLite() {
+		driverName = "sqlite"
+		driver, err = sqlite.WithInstance(db.DB, &sqlite.Config{})
+	} else {
+		driver, err = postgres.WithInstance(db.DB, &postgres.Config{})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s driver instance: %w", driverName, err)
+	}
+
+	migrationsFS, err := fs.Sub(StaticFiles, migrationsDir())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get migrations sub-filesystem: %w", err)
+	}
+
+	sourceDriver, err := iofs.New(migrationsFS, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create iofs source driver: %w", err)
+	}
+
+	m, err := migrate.NewWithInstance("iofs", sourceDriver, driverName, driver)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create migration instance: %w", err)
+	}
+
+	return m, nil
+}
+
+// openMigrate connects to the database in the config file and creates a migrate instance.
+// The returned function closes the connection.
+func openMigrate(cmd *cobra.Command) (*migrate.Migrate, func(), error) {
+	configPath, _ := cmd.Flags().GetString("config")
+	if err := LoadConfig(configPath); err != nil {
+		return nil, nil, err
+	}
+
+	db, err := connectDB(cmd.Context(), appConfig.DB)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not connect to database: %w", err)
+	}
+
+	m, err := newMigrate(db)
+	if err != nil {
+		db.Close()
+		return nil, nil, err
+	}
+
+	return m, func() { db.Close() }, nil
+}
+
+// checkDirty returns an error if the last migration failed partway through
+func checkDirty(m *migrate.Migrate) error {
+	version, dirty, err := m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return fmt.Errorf("failed to get migration version: %w", err)
+	}
+	if dirty {
+		return fmt.Errorf("database is dirty at version %d, fix the schema and run flowctl migrate force <version>", version)
+	}
+	return nil
+}
+
+// embeddedMigrations lists the migrations embedded in the binary, sorted by version
+func embeddedMigrations() ([]embeddedMigration, error) {
+	entries, err := fs.ReadDir(StaticFiles, migrationsDir())
+	if err != nil {
+		return nil, fmt.Errorf("could not read migrations: %w", err)
+	}
+
+	var migrations []embeddedMigration
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".up.sql")
+		if !ok {
+			continue
+		}
+		v, title, ok := strings.Cut(name, "_")
+		if !ok {
+			continue
+		}
+		version, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			continue
+		}
+		migrations = append(migrations, embeddedMigration{Version: uint(version), Name: title})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// confirm asks a yes/no question on the terminal
+func confirm(question string) bool {
+	fmt.Printf("%s [y/N]: ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+func init() {
+	migrateDownCmd.Flags().Uint("to", 0, "Version to roll back to")
+	migrateDownCmd.Flags().Bool("yes", false, "Don't ask for confirmation")
+
+	migrateCmd.AddCommand(migrateUpCmd, migrateStatusCmd, migrateDownCmd, migrateForceCmd)
+	rootCmd.AddCommand(migrateCmd)
+}
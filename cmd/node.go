@@ -0,0 +1,398 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// nodeSpec is a node to add, from the flags or a row of a hosts file
+type nodeSpec struct {
+	Name           string
+	Hostname       string
+	Port           int
+	Username       string
+	Credential     string
+	AuthMethod     string
+	ConnectionType string
+	OSFamily       string
+	Tags           []string
+}
+
+type nodeTestResp struct {
+	Success    bool   `json:"success"`
+	Output     string `json:"output"`
+	Error      string `json:"error"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// nodeCmd groups the node management commands. They use the API of a remote server.
+var nodeCmd = &cobra.Command{
+	Use:   "node",
+	Short: "Manage nodes on a remote flowctl server",
+}
+
+var nodeAddCmd = &cobra.Command{
+	Use:   "add [name]",
+	Short: "Add a node, or many nodes from a hosts file",
+	Long: `Add a node, or many nodes from a CSV hosts file with --file.
+
+The first row of the hosts file is a header naming the columns. name and hostname are required,
+the optional columns port, username, credential, auth_method, connection_type, os_family and tags
+override the flags for that row. Tags are separated by spaces. Lines starting with # are ignored.
+
+  name,hostname,port,tags
+  web1,10.0.0.11,22,web prod
+  web2,10.0.0.12,2222,web
+
+Credentials are referenced by name or ID and must already exist in the namespace.`,
+	Example: `  flowctl node add web1 --host 10.0.0.11 --user deploy --credential deploy-key --tags web,prod
+  flowctl node add --file hosts.csv --user deploy --credential deploy-key -n production`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newAPIClient(cmd)
+		if err != nil {
+			return err
+		}
+
+		namespace, _ := cmd.Flags().GetString("namespace")
+		file, _ := cmd.Flags().GetString("file")
+		update, _ := cmd.Flags().GetBool("update")
+
+		defaults := nodeSpec{}
+		defaults.Hostname, _ = cmd.Flags().GetString("host")
+		defaults.Port, _ = cmd.Flags().GetInt("port")
+		defaults.Username, _ = cmd.Flags().GetString("user")
+		defaults.Credential, _ = cmd.Flags().GetString("credential")
+		defaults.AuthMethod, _ = cmd.Flags().GetString("auth-method")
+		defaults.ConnectionType, _ = cmd.Flags().GetString("connection")
+		defaults.OSFamily, _ = cmd.Flags().GetString("os")
+		defaults.Tags, _ = cmd.Flags().GetStringSlice("tags")
+
+		var specs []nodeSpec
+		switch {
+		case file != "" && len(args) > 0:
+			return errors.New("a node name can't be used with --file")
+		case file != "":
+			f, err := os.Open(file)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			if specs, err = readHostsFile(f, defaults); err != nil {
+				return fmt.Errorf("could not read %s: %w", file, err)
+			}
+		case len(args) == 1:
+			spec := defaults
+			spec.Name = args[0]
+			specs = []nodeSpec{spec}
+		default:
+			return errors.New("a node name or --file is required")
+		}
+
+		for _, s := range specs {
+			if s.Hostname == "" || s.Username == "" || s.Credential == "" {
+				return fmt.Errorf("node %s: hostname, username and credential are required", s.Name)
+			}
+		}
+
+		ctx := context.Background()
+		ns := url.PathEscape(namespace)
+
+		var credentials []credentialResp
+		if err := listAll(ctx, client, fmt.Sprintf("/api/v1/%s/credentials", ns), "credentials", &credentials); err != nil {
+			return fmt.Errorf("could not list credentials: %w", err)
+		}
+		credentialIDs := make(map[string]string, len(credentials)*2)
+		for _, c := range credentials {
+			credentialIDs[c.Name] = c.ID
+			credentialIDs[c.ID] = c.ID
+		}
+
+		var nodes []nodeResp
+		if err := listAll(ctx, client, fmt.Sprintf("/api/v1/%s/nodes", ns), "nodes", &nodes); err != nil {
+			return fmt.Errorf("could not list nodes: %w", err)
+		}
+		nodeIDs := make(map[string]string, len(nodes))
+		for _, n := range nodes {
+			nodeIDs[n.Name] = n.ID
+		}
+
+		var failed int
+		for _, s := range specs {
+			credentialID, ok := credentialIDs[s.Credential]
+			if !ok {
+				fmt.Fprintf(os.Stderr, "node %s: credential %q does not exist\n", s.Name, s.Credential)
+				failed++
+				continue
+			}
+
+			req := map[string]any{
+				"name":            s.Name,
+				"hostname":        s.Hostname,
+				"port":            s.Port,
+				"username":        s.Username,
+				"connection_type": s.ConnectionType,
+				"os_family":       s.OSFamily,
+				"tags":            s.Tags,
+				"auth": nodeAuth{
+					Method:       s.AuthMethod,
+					CredentialID: credentialID,
+				},
+			}
+
+			if id, ok := nodeIDs[s.Name]; ok {
+				if !update {
+					fmt.Fprintf(os.Stderr, "node %s already exists, skipping\n", s.Name)
+					continue
+				}
+				if err := client.sendJSON(ctx, http.MethodPut, fmt.Sprintf("/api/v1/%s/nodes/%s", ns, url.PathEscape(id)), req, nil); err != nil {
+					fmt.Fprintf(os.Stderr, "node %s: could not update: %v\n", s.Name, err)
+					failed++
+					continue
+				}
+				fmt.Printf("updated node %s\n", s.Name)
+				continue
+			}
+
+			if err := client.sendJSON(ctx, http.MethodPost, fmt.Sprintf("/api/v1/%s/nodes", ns), req, nil); err != nil {
+				fmt.Fprintf(os.Stderr, "node %s: could not create: %v\n", s.Name, err)
+				failed++
+				continue
+			}
+			fmt.Printf("created node %s\n", s.Name)
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("%d of %d node(s) could not be added", failed, len(specs))
+		}
+		return nil
+	},
+}
+
+var nodeListCmd = &cobra.Command{
+	Use:          "list",
+	Short:        "List nodes",
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newAPIClient(cmd)
+		if err != nil {
+			return err
+		}
+
+		namespace, _ := cmd.Flags().GetString("namespace")
+		tags, _ := cmd.Flags().GetStringSlice("tags")
+		filter, _ := cmd.Flags().GetString("filter")
+
+		query := url.Values{}
+		if filter != "" {
+			query.Set("filter", filter)
+		}
+		for _, t := range tags {
+			query.Add("tags", t)
+		}
+		path := fmt.Sprintf("/api/v1/%s/nodes", url.PathEscape(namespace))
+		if len(query) > 0 {
+			path += "?" + query.Encode()
+		}
+
+		var nodes []nodeResp
+		if err := listAll(context.Background(), client, path, "nodes", &nodes); err != nil {
+			return fmt.Errorf("could not list nodes: %w", err)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tHOSTNAME\tPORT\tUSERNAME\tCONNECTION\tTAGS")
+		for _, n := range nodes {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\t%s\n", n.Name, n.Hostname, n.Port, n.Username, n.ConnectionType, strings.Join(n.Tags, ","))
+		}
+		return w.Flush()
+	},
+}
+
+var nodeTestCmd = &cobra.Command{
+	Use:   "test [name...]",
+	Short: "Check that nodes can be connected to and run commands",
+	Long: `Check that the server can connect to nodes with their credentials and run a command on them.
+The command exits with a non-zero status if any node fails.`,
+	Example: `  flowctl node test web1 web2
+  flowctl node test --all -n production`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newAPIClient(cmd)
+		if err != nil {
+			return err
+		}
+
+		namespace, _ := cmd.Flags().GetString("namespace")
+		all, _ := cmd.Flags().GetBool("all")
+		if all == (len(args) > 0) {
+			return errors.New("either node names or --all is required")
+		}
+
+		ctx := context.Background()
+		ns := url.PathEscape(namespace)
+
+		var nodes []nodeResp
+		if err := listAll(ctx, client, fmt.Sprintf("/api/v1/%s/nodes", ns), "nodes", &nodes); err != nil {
+			return fmt.Errorf("could not list nodes: %w", err)
+		}
+
+		if !all {
+			byName := make(map[string]nodeResp, len(nodes))
+			for _, n := range nodes {
+				byName[n.Name] = n
+			}
+			selected := make([]nodeResp, 0, len(args))
+			for _, name := range args {
+				n, ok := byName[name]
+				if !ok {
+					return fmt.Errorf("node %s not found in namespace %s", name, namespace)
+				}
+				selected = append(selected, n)
+			}
+			nodes = selected
+		}
+
+		var failed int
+		for _, n := range nodes {
+			var resp nodeTestResp
+			if err := client.sendJSON(ctx, http.MethodPost, fmt.Sprintf("/api/v1/%s/nodes/%s/test", ns, url.PathEscape(n.ID)), nil, &resp); err != nil {
+				fmt.Printf("FAIL  %s: %v\n", n.Name, err)
+				failed++
+				continue
+			}
+			if !resp.Success {
+				fmt.Printf("FAIL  %s (%dms): %s\n", n.Name, resp.DurationMs, resp.Error)
+				failed++
+				continue
+			}
+			fmt.Printf("OK    %s (%dms): %s\n", n.Name, resp.DurationMs, resp.Output)
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("%d of %d node(s) failed", failed, len(nodes))
+		}
+		return nil
+	},
+}
+
+// readHostsFile reads nodes from a CSV file with a header row. Columns that are missing
+// or empty in a row use the value from defaults.
+func readHostsFile(r io.Reader, defaults nodeSpec) ([]nodeSpec, error) {
+	cr := csv.NewReader(r)
+	cr.Comment = '#'
+	cr.TrimLeadingSpace = true
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("could not read header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, h := range header {
+		columns[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	for _, required := range []string{"name", "hostname"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("missing %s column", required)
+		}
+	}
+
+	var specs []nodeSpec
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := cr.FieldPos(0)
+		get := func(column string) string {
+			i, ok := columns[column]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		spec := defaults
+		spec.Name = get("name")
+		spec.Hostname = get("hostname")
+		if spec.Name == "" || spec.Hostname == "" {
+			return nil, fmt.Errorf("line %d: name and hostname are required", line)
+		}
+		if v := get("port"); v != "" {
+			if spec.Port, err = strconv.Atoi(v); err != nil {
+				return nil, fmt.Errorf("line %d: invalid port %q", line, v)
+			}
+		}
+		if v := get("username"); v != "" {
+			spec.Username = v
+		}
+		if v := get("credential"); v != "" {
+			spec.Credential = v
+		}
+		if v := get("auth_method"); v != "" {
+			spec.AuthMethod = v
+		}
+		if v := get("connection_type"); v != "" {
+			spec.ConnectionType = v
+		}
+		if v := get("os_family"); v != "" {
+			spec.OSFamily = v
+		}
+		if v := get("tags"); v != "" {
+			spec.Tags = strings.Fields(v)
+		}
+
+		specs = append(specs, spec)
+	}
+
+	if len(specs) == 0 {
+		return nil, errors.New("no nodes found")
+	}
+	return specs, nil
+}
+
+func init() {
+	for _, c := range []*cobra.Command{nodeAddCmd, nodeListCmd, nodeTestCmd} {
+		addRemoteFlags(c)
+		c.Flags().StringP("namespace", "n", "default", "Namespace of the nodes")
+	}
+
+	nodeAddCmd.Flags().StringP("file", "f", "", "CSV file with the nodes to add")
+	nodeAddCmd.Flags().String("host", "", "Hostname or IP address of the node")
+	nodeAddCmd.Flags().Int("port", 22, "SSH port")
+	nodeAddCmd.Flags().String("user", "", "User to log in as")
+	nodeAddCmd.Flags().String("credential", "", "Name or ID of the credential used to log in")
+	nodeAddCmd.Flags().String("auth-method", "private_key", "Authentication method, private_key or password")
+	nodeAddCmd.Flags().String("connection", "ssh", "Connection type, ssh or qssh")
+	nodeAddCmd.Flags().String("os", "linux", "Operating system of the node, linux or windows")
+	nodeAddCmd.Flags().StringSlice("tags", nil, "Tags of the node, comma separated")
+	nodeAddCmd.Flags().Bool("update", false, "Update nodes that already exist")
+
+	nodeListCmd.Flags().String("filter", "", "Only list nodes whose name or hostname contains this")
+	nodeListCmd.Flags().StringSlice("tags", nil, "Only list nodes with any of these tags")
+
+	nodeTestCmd.Flags().Bool("all", false, "Test all nodes in the namespace")
+
+	nodeCmd.AddCommand(nodeAddCmd, nodeListCmd, nodeTestCmd)
+	rootCmd.AddCommand(nodeCmd)
+}
@@ -1,6 +1,9 @@
 package cmd
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
@@ -19,13 +22,14 @@ var pluginClients []*goplugin.Client
 
 // registerExecutorPlugin registers a single ExecutorPlugin into the executor registries
 // and generates an API token for it, returning the token.
-func registerExecutorPlugin(name string, plugin executor.ExecutorPlugin, signingKey []byte) string {
+func registerExecutorPlugin(name string, plugin executor.ExecutorPlugin, version string, signingKey []byte) string {
 	executor.RegisterExecutor(name, plugin.New)
 	schema := plugin.GetSchema()
 	if schema != nil {
 		executor.RegisterSchema(name, schema)
 	}
 	executor.RegisterCapabilities(name, plugin.GetCapabilities())
+	executor.RegisterVersion(name, version)
 
 	token, err := core.GenerateExecutorToken(name, signingKey)
 	if err != nil {
@@ -43,9 +47,12 @@ func registerPlugins(pluginDir string, signingKey []byte) map[string]string {
 		"flow":   &flow.FlowExecutorPlugin{},
 	}
 
+	// Built-in executors are versioned with flowctl
+	builtinVersion := getBuildInfo().Version
+
 	executorKeys := make(map[string]string)
 	for name, plugin := range builtins {
-		executorKeys[name] = registerExecutorPlugin(name, plugin, signingKey)
+		executorKeys[name] = registerExecutorPlugin(name, plugin, builtinVersion, signingKey)
 	}
 
 	// Load external plugins
@@ -90,14 +97,35 @@ func loadExternalPlugins(dir string, signingKey []byte) map[string]string {
 			continue
 		}
 
+		version, err := pluginChecksum(path)
+		if err != nil {
+			log.Printf("could not compute checksum of plugin %s: %v", path, err)
+		}
+
 		pluginClients = append(pluginClients, client)
-		executorKeys[name] = registerExecutorPlugin(name, plugin, signingKey)
+		executorKeys[name] = registerExecutorPlugin(name, plugin, version, signingKey)
 		log.Printf("loaded external executor plugin: %s", name)
 	}
 
 	return executorKeys
 }
 
+// pluginChecksum returns the SHA-256 checksum of a plugin binary, used as the version of the
+// executor since plugins don't report one
+func pluginChecksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
 // CleanupPlugins kills all external plugin processes.
 func CleanupPlugins() {
 	for _, c := range pluginClients {
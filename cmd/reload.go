@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/cvhariharan/flowctl/internal/config"
+	"github.com/cvhariharan/flowctl/internal/core"
+	"github.com/cvhariharan/flowctl/internal/messengers"
+	"github.com/cvhariharan/flowctl/internal/scheduler"
+)
+
+// configReloader reads the config file again and applies the settings that can change while
+// the server is running: the log level, messengers, scheduler workers and retention options.
+// Running executions are not interrupted. Other changes only take effect after a restart.
+type configReloader struct {
+	mu   sync.Mutex
+	path string
+	// started is the config the server was started with, changes to it that can't be applied
+	// are reported on every reload until the server is restarted
+	started config.Config
+
+	logLevel   *slog.LevelVar
+	scheduler  *scheduler.Scheduler
+	messengers *messengers.Registry
+	core       *core.Core
+	logger     *slog.Logger
+}
+
+// Reload applies the config file and returns the sections that changed but need a restart.
+// Nothing is applied if the config file is invalid or can't be applied to the running server.
+func (r *configReloader) Reload() ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	next, err := config.Load(r.path)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.started.CheckReload(next); err != nil {
+		return nil, err
+	}
+
+	r.logLevel.Set(logLevel(next.Logger.Level))
+	r.scheduler.SetWorkerCount(next.Scheduler.WorkerCount)
+	r.core.SetRetentionOptions(retentionOptions(next.Retention))
+
+	restart := r.started.RestartRequired(next)
+	if len(restart) > 0 {
+		r.logger.Warn("config changes require a restart", "sections", restart)
+	}
+
+	// The other settings are kept even if a messenger can't be recreated
+	if err := r.messengers.Reload(next.Messengers); err != nil {
+		return restart, fmt.Errorf("could not reload messengers: %w", err)
+	}
+	if err := r.core.LoadMessengerConfigs(context.Background()); err != nil {
+		return restart, err
+	}
+	r.logger.Info("config reloaded", "path", r.path)
+
+	return restart, nil
+}
+
+// watchSignals reloads the config every time the process receives SIGHUP, until ctx is cancelled
+func (r *configReloader) watchSignals(ctx context.Context) {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGHUP)
+	defer signal.Stop(sig)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sig:
+			if _, err := r.Reload(); err != nil {
+				r.logger.Error("could not reload config", "error", err)
+			}
+		}
+	}
+}
+
+// logLevel returns the level of the server logs. DEBUG_LOG=true always logs at debug.
+func logLevel(level string) slog.Level {
+	if os.Getenv("DEBUG_LOG") == "true" {
+		return slog.LevelDebug
+	}
+
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		return slog.LevelInfo
+	}
+	return l
+}
+
+// retentionOptions returns the options of the retention job from the config
+func retentionOptions(cfg config.RetentionConfig) core.RetentionOptions {
+	return core.RetentionOptions{
+		Interval:                       cfg.Interval,
+		BatchSize:                      cfg.BatchSize,
+		ArtifactGrace:                  cfg.ArtifactGrace,
+		PendingApprovalArtifactsMaxAge: cfg.PendingApprovalArtifactsMaxAge,
+		RetryableArtifactsMaxAge:       cfg.RetryableArtifactsMaxAge,
+	}
+}
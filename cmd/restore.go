@@ -0,0 +1,321 @@
+package cmd
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cvhariharan/flowctl/internal/flowstore"
+	"github.com/spf13/cobra"
+	"gocloud.dev/secrets"
+)
+
+// restoreCmd restores a backup created with flowctl backup
+var restoreCmd = &cobra.Command{
+	Use:   "restore <archive>",
+	Short: "Restore the database and flows from a backup",
+	Long: `Restore the database and flows from a backup created with flowctl backup.
+
+The database is restored with pg_restore and replaces the existing tables. SQLite databases are
+replaced by the database file in the backup, stop flowctl before restoring them. The current flows
+directory is kept next to the restored one with a .pre-restore-<timestamp> suffix.
+
+A backup can only be restored by a binary whose migrations include the schema version of the backup.
+Backups of an older schema are restored as is, run flowctl migrate up afterwards to upgrade them.
+The secrets keeper in the config file must be able to decrypt the secrets in the backup.`,
+	Example:      `  flowctl restore --config config.toml flowctl-backup.tar.gz`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		configPath, _ := cmd.Flags().GetString("config")
+		if err := LoadConfig(configPath); err != nil {
+			return err
+		}
+
+		force, _ := cmd.Flags().GetBool("force")
+		yes, _ := cmd.Flags().GetBool("yes")
+		ctx := context.Background()
+
+		tmpDir, err := os.MkdirTemp("", "flowctl-restore")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(tmpDir)
+
+		manifest, keeperMeta, err := extractBackup(args[0], tmpDir)
+		if err != nil {
+			return err
+		}
+
+		// A dump can only be restored into a database of the same driver
+		if driver := manifestDriver(manifest); driver != backupDriver() {
+			return fmt.Errorf("backup is of a %s database, the config uses %s", driver, backupDriver())
+		}
+
+		latest, err := latestMigration()
+		if err != nil {
+			return err
+		}
+		if err := checkBackupCompatibility(manifest, latest); err != nil {
+			if !force {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+		}
+
+		if err := checkBackupKeeper(ctx, keeperMeta); err != nil {
+			if !force {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+		}
+
+		fmt.Printf("backup created at %s by flowctl %s with schema version %d\n",
+			manifest.CreatedAt.Format(time.RFC3339), manifest.FlowctlVersion, manifest.SchemaVersion)
+		if !yes && !confirm("This replaces the data in the database and the flows directory. Continue?") {
+			return errors.New("aborted")
+		}
+
+		dumpPath := filepath.Join(tmpDir, backupDatabaseFile)
+		if err := restoreDatabase(ctx, dumpPath); err != nil {
+			return fmt.Errorf("could not restore database: %w", err)
+		}
+
+		if err := replaceFlowsDirectory(ctx, filepath.Join(tmpDir, backupFlowsDir)); err != nil {
+			return fmt.Errorf("could not restore flows directory: %w", err)
+		}
+
+		fmt.Println("restore complete")
+		if manifest.SchemaVersion < latest {
+			fmt.Printf("the backup has schema version %d, run flowctl migrate up to upgrade it to %d\n", manifest.SchemaVersion, latest)
+		}
+		return nil
+	},
+}
+
+// extractBackup reads the manifest and keeper metadata of a backup and extracts
+// the database dump and flows into dir
+func extractBackup(archive string, dir string) (backupManifest, backupKeeper, error) {
+	var (
+		manifest     backupManifest
+		keeperMeta   backupKeeper
+		seenManifest bool
+		seenKeeper   bool
+		seenDump     bool
+	)
+
+	f, err := os.Open(archive)
+	if err != nil {
+		return manifest, keeperMeta, err
+	}
+	defer f.Close()
+
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		return manifest, keeperMeta, fmt.Errorf("invalid backup: %w", err)
+	}
+	defer gr.Close()
+
+	if err := os.MkdirAll(filepath.Join(dir, backupFlowsDir), 0755); err != nil {
+		return manifest, keeperMeta, err
+	}
+
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return manifest, keeperMeta, fmt.Errorf("invalid backup: %w", err)
+		}
+
+		name := path.Clean(hdr.Name)
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return manifest, keeperMeta, fmt.Errorf("invalid path %q in backup", hdr.Name)
+		}
+
+		switch {
+		case name == backupManifestFile:
+			if err := json.NewDecoder(tr).Decode(&manifest); err != nil {
+				return manifest, keeperMeta, fmt.Errorf("invalid manifest: %w", err)
+			}
+			if manifest.FormatVersion != backupFormatVersion {
+				return manifest, keeperMeta, fmt.Errorf("unsupported backup format version %d", manifest.FormatVersion)
+			}
+			seenManifest = true
+		case name == backupKeeperFile:
+			if err := json.NewDecoder(tr).Decode(&keeperMeta); err != nil {
+				return manifest, keeperMeta, fmt.Errorf("invalid keeper metadata: %w", err)
+			}
+			seenKeeper = true
+		case name == backupDatabaseFile:
+			if err := extractFile(tr, filepath.Join(dir, backupDatabaseFile)); err != nil {
+				return manifest, keeperMeta, err
+			}
+			seenDump = true
+		case strings.HasPrefix(name, strings.TrimSuffix(backupFlowsDir, "/")+"/"):
+			target := filepath.Join(dir, filepath.FromSlash(name))
+			switch hdr.Typeflag {
+			case tar.TypeDir:
+				if err := os.MkdirAll(target, 0755); err != nil {
+					return manifest, keeperMeta, err
+				}
+			case tar.TypeReg:
+				if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+					return manifest, keeperMeta, err
+				}
+				if err := extractFile(tr, target); err != nil {
+					return manifest, keeperMeta, err
+				}
+			}
+		}
+	}
+
+	if !seenManifest || !seenKeeper || !seenDump {
+		return manifest, keeperMeta, errors.New("invalid backup: missing manifest, keeper metadata or database dump")
+	}
+	return manifest, keeperMeta, nil
+}
+
+func extractFile(r io.Reader, target string) error {
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, r); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// manifestDriver returns the database driver of a backup
+func manifestDriver(manifest backupManifest) string {
+	if manifest.Driver == "" {
+		return "postgres"
+	}
+	return manifest.Driver
+}
+
+// restoreDatabase restores the dump at path into the database. A SQLite database file is replaced
+// by the dump, along with its write-ahead log.
+func restoreDatabase(ctx context.Context, path string) error {
+	if !appConfig.DB.SQLite() {
+		return runPGCommand(ctx, "pg_restore", "--clean", "--if-exists", "--no-owner", "--single-transaction", path)
+	}
+
+	dbPath := filepath.Clean(appConfig.DB.DSN)
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
+		return err
+	}
+
+	// The dump is copied next to the database first, the temp directory can be on a different
+	// filesystem and the database must not be left half written
+	tmpPath := dbPath + ".restore"
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	if err := extractFile(src, tmpPath); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	for _, suffix := range []string{"-wal", "-shm"} {
+		if err := os.Remove(dbPath + suffix); err != nil && !errors.Is(err, os.ErrNotExist) {
+			os.Remove(tmpPath)
+			return err
+		}
+	}
+	return os.Rename(tmpPath, dbPath)
+}
+
+// checkBackupCompatibility checks that a backup can be restored by this binary.
+// Backups of a newer schema than the latest embedded migration come from a newer flowctl
+// and can't be migrated or rolled back by this binary.
+func checkBackupCompatibility(manifest backupManifest, latest uint) error {
+	if manifest.SchemaVersion > latest {
+		return fmt.Errorf("backup has schema version %d from flowctl %s, this binary only supports up to %d",
+			manifest.SchemaVersion, manifest.FlowctlVersion, latest)
+	}
+	return nil
+}
+
+// checkBackupKeeper checks that the configured keeper can decrypt secrets encrypted
+// by the keeper used when taking the backup
+func checkBackupKeeper(ctx context.Context, keeperMeta backupKeeper) error {
+	keeper, err := secrets.OpenKeeper(ctx, appConfig.Keystore.KeeperURL)
+	if err != nil {
+		return fmt.Errorf("could not open secrets keeper: %w", err)
+	}
+	defer keeper.Close()
+
+	plain, err := keeper.Decrypt(ctx, keeperMeta.Check)
+	if err != nil || string(plain) != keeperCheckValue {
+		return fmt.Errorf("the configured %s keeper can't decrypt the secrets in the backup", keeperMeta.Scheme)
+	}
+	return nil
+}
+
+// replaceFlowsDirectory moves the restored flows into the flows directory, keeping the existing one.
+// Flows stored in a bucket are replaced with the restored flows.
+func replaceFlowsDirectory(ctx context.Context, restored string) error {
+	if flowstore.IsBucketURL(appConfig.App.FlowsDirectory) {
+		store, err := flowstore.NewBucketStore(ctx, appConfig.App.FlowsDirectory, restored)
+		if err != nil {
+			return err
+		}
+		defer store.Close()
+		return store.Push(ctx, restored)
+	}
+
+	flowsDir := filepath.Clean(appConfig.App.FlowsDirectory)
+
+	if _, err := os.Stat(flowsDir); err == nil {
+		previous := fmt.Sprintf("%s.pre-restore-%s", flowsDir, time.Now().UTC().Format("20060102-150405"))
+		if err := os.Rename(flowsDir, previous); err != nil {
+			return err
+		}
+		fmt.Printf("existing flows directory moved to %s\n", previous)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(flowsDir), 0755); err != nil {
+		return err
+	}
+	if err := os.Rename(restored, flowsDir); err != nil {
+		// The temp directory can be on a different filesystem
+		return os.CopyFS(flowsDir, os.DirFS(restored))
+	}
+	return nil
+}
+
+// latestMigration returns the version of the newest migration embedded in the binary
+func latestMigration() (uint, error) {
+	migrations, err := embeddedMigrations()
+	if err != nil {
+		return 0, err
+	}
+	if len(migrations) == 0 {
+		return 0, errors.New("no migrations found")
+	}
+	return migrations[len(migrations)-1].Version, nil
+}
+
+func init() {
+	restoreCmd.Flags().Bool("force", false, "Restore even if the compatibility or keeper checks fail")
+	restoreCmd.Flags().Bool("yes", false, "Don't ask for confirmation")
+	rootCmd.AddCommand(restoreCmd)
+}
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"text/template"
 
 	"github.com/cvhariharan/flowctl/internal/config"
 	"github.com/spf13/cobra"
@@ -19,15 +18,12 @@ var rootCmd = &cobra.Command{
 	Short: "Self-service workflow execution engine",
 	Run: func(cmd *cobra.Command, args []string) {
 		if ok, _ := cmd.Flags().GetBool("new-config"); ok {
-			cfg, err := template.ParseFS(StaticFiles, "configs/*.toml")
-			if err != nil {
-				log.Fatal(err)
-			}
 			cfgFile, err := os.Create("config.toml")
 			if err != nil {
 				log.Fatal(err)
 			}
-			if err := cfg.ExecuteTemplate(cfgFile, "config.sample.toml", config.GetDefaultConfig()); err != nil {
+			defer cfgFile.Close()
+			if err := renderConfig(cfgFile, config.GetDefaultConfig()); err != nil {
 				log.Fatal(err)
 			}
 		}
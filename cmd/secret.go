@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+type secretResp struct {
+	ID          string `json:"id"`
+	Key         string `json:"key"`
+	Description string `json:"description"`
+	CreatedAt   string `json:"created_at"`
+	UpdatedAt   string `json:"updated_at"`
+}
+
+// secretCmd groups the commands that manage namespace and flow secrets on a remote server
+var secretCmd = &cobra.Command{
+	Use:   "secret",
+	Short: "Manage namespace and flow secrets on a remote flowctl server",
+	Long: `Manage secrets on a remote flowctl server. Secrets belong to a namespace, or to a single
+flow with --flow. Flow secrets take precedence over namespace secrets with the same key.`,
+}
+
+var secretSetCmd = &cobra.Command{
+	Use:   "set <key>",
+	Short: "Create or update a secret",
+	Long: `Create a secret, or update it if a secret with the key already exists.
+
+The value is read from stdin so that it doesn't end up in the shell history or the process list,
+or from an environment variable with --from-env. A single trailing newline is removed from stdin.`,
+	Example: `  vault kv get -field=password secret/db | flowctl secret set DB_PASSWORD -n production
+  flowctl secret set API_TOKEN --flow deploy --from-env CI_API_TOKEN --description "Token for the deploy API"`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newAPIClient(cmd)
+		if err != nil {
+			return err
+		}
+
+		key := args[0]
+		fromEnv, _ := cmd.Flags().GetString("from-env")
+		description, _ := cmd.Flags().GetString("description")
+
+		var value string
+		if fromEnv != "" {
+			v, ok := os.LookupEnv(fromEnv)
+			if !ok {
+				return fmt.Errorf("environment variable %s is not set", fromEnv)
+			}
+			value = v
+		} else {
+			data, err := io.ReadAll(os.Stdin)
+			if err != nil {
+				return fmt.Errorf("could not read value: %w", err)
+			}
+			value = strings.TrimSuffix(strings.TrimSuffix(string(data), "\n"), "\r")
+		}
+		if value == "" {
+			return errors.New("secret value cannot be empty")
+		}
+
+		ctx := context.Background()
+		path := secretsPath(cmd)
+
+		existing, err := findSecret(ctx, client, path, key)
+		if err != nil {
+			return err
+		}
+
+		req := map[string]string{
+			"key":         key,
+			"value":       value,
+			"description": description,
+		}
+
+		if existing != nil {
+			// Keep the description unless a new one is given
+			if !cmd.Flags().Changed("description") {
+				req["description"] = existing.Description
+			}
+			if err := client.sendJSON(ctx, http.MethodPut, path+"/"+url.PathEscape(existing.ID), req, nil); err != nil {
+				return fmt.Errorf("could not update secret %s: %w", key, err)
+			}
+			fmt.Printf("updated secret %s\n", key)
+			return nil
+		}
+
+		if err := client.sendJSON(ctx, http.MethodPost, path, req, nil); err != nil {
+			return fmt.Errorf("could not create secret %s: %w", key, err)
+		}
+		fmt.Printf("created secret %s\n", key)
+		return nil
+	},
+}
+
+var secretListCmd = &cobra.Command{
+	Use:          "list",
+	Short:        "List secrets, values are never shown",
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newAPIClient(cmd)
+		if err != nil {
+			return err
+		}
+
+		var secrets []secretResp
+		if err := client.getJSON(context.Background(), secretsPath(cmd), &secrets); err != nil {
+			return fmt.Errorf("could not list secrets: %w", err)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "KEY\tDESCRIPTION\tUPDATED")
+		for _, s := range secrets {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", s.Key, s.Description, s.UpdatedAt)
+		}
+		return w.Flush()
+	},
+}
+
+var secretDeleteCmd = &cobra.Command{
+	Use:          "delete <key>",
+	Short:        "Delete a secret",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newAPIClient(cmd)
+		if err != nil {
+			return err
+		}
+
+		ctx := context.Background()
+		path := secretsPath(cmd)
+
+		existing, err := findSecret(ctx, client, path, args[0])
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			return fmt.Errorf("secret %s not found", args[0])
+		}
+
+		if _, err := client.do(ctx, http.MethodDelete, path+"/"+url.PathEscape(existing.ID), nil, ""); err != nil {
+			return fmt.Errorf("could not delete secret %s: %w", args[0], err)
+		}
+		fmt.Printf("deleted secret %s\n", args[0])
+		return nil
+	},
+}
+
+// secretsPath returns the API path of the namespace secrets, or the secrets of a flow if --flow is set
+func secretsPath(cmd *cobra.Command) string {
+	namespace, _ := cmd.Flags().GetString("namespace")
+	flow, _ := cmd.Flags().GetString("flow")
+
+	if flow != "" {
+		return fmt.Sprintf("/api/v1/%s/flows/%s/secrets", url.PathEscape(namespace), url.PathEscape(flow))
+	}
+	return fmt.Sprintf("/api/v1/%s/secrets", url.PathEscape(namespace))
+}
+
+// findSecret returns the secret with the given key, or nil if it doesn't exist
+func findSecret(ctx context.Context, client *apiClient, path string, key string) (*secretResp, error) {
+	var secrets []secretResp
+	if err := client.getJSON(ctx, path, &secrets); err != nil {
+		return nil, fmt.Errorf("could not list secrets: %w", err)
+	}
+
+	for _, s := range secrets {
+		if s.Key == key {
+			return &s, nil
+		}
+	}
+	return nil, nil
+}
+
+func init() {
+	for _, c := range []*cobra.Command{secretSetCmd, secretListCmd, secretDeleteCmd} {
+		addRemoteFlags(c)
+		c.Flags().StringP("namespace", "n", "default", "Namespace of the secrets")
+		c.Flags().String("flow", "", "ID of the flow, namespace secrets are used if not set")
+	}
+
+	secretSetCmd.Flags().String("from-env", "", "Read the value from this environment variable instead of stdin")
+	secretSetCmd.Flags().String("description", "", "Description of the secret")
+
+	secretCmd.AddCommand(secretSetCmd, secretListCmd, secretDeleteCmd)
+	rootCmd.AddCommand(secretCmd)
+}
@@ -3,31 +3,49 @@ package cmd
 import (
 	"context"
 	"embed"
+	"errors"
+	"fmt"
 	"io/fs"
 	"log"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"path/filepath"
 	"sync"
+	"syscall"
 
 	"github.com/casbin/casbin/v2"
 	casbin_model "github.com/casbin/casbin/v2/model"
 	"github.com/casbin/casbin/v2/util"
+	"github.com/cvhariharan/flowctl/internal/artifactstore"
 	"github.com/cvhariharan/flowctl/internal/config"
 	"github.com/cvhariharan/flowctl/internal/core"
 	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/cvhariharan/flowctl/internal/eventbus"
+	"github.com/cvhariharan/flowctl/internal/flowstore"
+	"github.com/cvhariharan/flowctl/internal/gitsync"
 	"github.com/cvhariharan/flowctl/internal/handlers"
 	"github.com/cvhariharan/flowctl/internal/messengers"
 	"github.com/cvhariharan/flowctl/internal/metrics"
 	"github.com/cvhariharan/flowctl/internal/repo"
 	"github.com/cvhariharan/flowctl/internal/scheduler"
 	"github.com/cvhariharan/flowctl/internal/scheduler/storage"
+	"github.com/cvhariharan/flowctl/internal/securitylog"
 	"github.com/cvhariharan/flowctl/internal/streamlogger"
+	"github.com/cvhariharan/flowctl/internal/tracing"
+	"github.com/cvhariharan/flowctl/internal/uploadstore"
 	"github.com/jmoiron/sqlx"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	sqlxadapter "github.com/memwey/casbin-sqlx-adapter"
 	"github.com/spf13/cobra"
+	_ "gocloud.dev/blob/gcsblob"
+	_ "gocloud.dev/blob/s3blob"
+	_ "gocloud.dev/pubsub/awssnssqs"
+	_ "gocloud.dev/pubsub/kafkapubsub"
+	_ "gocloud.dev/pubsub/natspubsub"
 	"gocloud.dev/secrets"
 	_ "gocloud.dev/secrets/localsecrets"
 )
@@ -49,15 +67,33 @@ var startCmd = &cobra.Command{
 		shared := initializeSharedComponents()
 		defer shared.Cleanup()
 
+		// The server stops on SIGINT or SIGTERM, running executions are handed off to the server
+		// that picks up their jobs next
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
 		var wg sync.WaitGroup
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
 			// start worker
-			startWorker(shared.Scheduler, shared.Logger)
+			startWorker(ctx, shared.Scheduler, shared.Logger)
 		}()
+
+		// Safe parts of the config are reloaded on SIGHUP or through the API
+		reloader := &configReloader{
+			path:       configPath,
+			started:    appConfig,
+			logLevel:   shared.LogLevel,
+			scheduler:  shared.Scheduler,
+			messengers: shared.Messengers,
+			core:       shared.Core,
+			logger:     shared.Logger.WithGroup("config"),
+		}
+		go reloader.watchSignals(context.Background())
+
 		// start server
-		startServer(shared.DB, shared.Core, shared.Metrics, shared.Logger, shared.ExecutorSigningKey)
+		startServer(ctx, shared.DB, shared.Core, shared.Metrics, shared.Logger, shared.ExecutorSigningKey, shared.GitSync, shared.SecurityLog, reloader)
 		wg.Wait()
 	},
 }
@@ -69,13 +105,22 @@ func init() {
 // SharedComponents holds components that are shared between server and worker
 type SharedComponents struct {
 	DB                 *sqlx.DB
+	ReplicaDB          *sqlx.DB
 	Core               *core.Core
 	Scheduler          *scheduler.Scheduler
 	Metrics            *metrics.Manager
 	Logger             *slog.Logger
+	LogLevel           *slog.LevelVar
 	Keeper             *secrets.Keeper
-	Messengers         map[string]messengers.Messenger
+	Messengers         *messengers.Registry
+	GitSync            *gitsync.Syncer
 	ExecutorSigningKey []byte
+	ShutdownTracing    func(context.Context) error
+	SecurityLog        *securitylog.Logger
+	EventBus           *eventbus.Publisher
+	Uploads            *uploadstore.Store
+	Artifacts          artifactstore.Store
+	Archive            *artifactstore.Archive
 }
 
 // Cleanup cleans up all shared resources
@@ -84,51 +129,64 @@ func (s *SharedComponents) Cleanup() {
 	if s.DB != nil {
 		s.DB.Close()
 	}
+	if s.ReplicaDB != nil {
+		s.ReplicaDB.Close()
+	}
 	if s.Keeper != nil {
 		s.Keeper.Close()
 	}
-	for _, m := range s.Messengers {
-		if m != nil {
-			m.Close()
+	if s.Messengers != nil {
+		s.Messengers.Close()
+	}
+	if s.SecurityLog != nil {
+		s.SecurityLog.Close()
+	}
+	if s.EventBus != nil {
+		s.EventBus.Close()
+	}
+	if s.Uploads != nil {
+		s.Uploads.Close()
+	}
+	if s.Artifacts != nil {
+		s.Artifacts.Close()
+	}
+	if s.Archive != nil {
+		s.Archive.Close()
+	}
+	if s.ShutdownTracing != nil {
+		if err := s.ShutdownTracing(context.Background()); err != nil {
+			log.Printf("could not flush traces: %v", err)
 		}
 	}
 }
 
-// initMessengers creates and returns all enabled messengers as a map keyed by channel name.
-func initMessengers(cfg config.MessengersConfig, groupResolver messengers.GroupResolver, logger *slog.Logger) map[string]messengers.Messenger {
-	m := make(map[string]messengers.Messenger)
-
-	if cfg.Email.Enabled {
-		emailMessenger, err := messengers.NewEmailMessenger(cfg.Email, groupResolver, logger.WithGroup("email_messenger"), appConfig.App.RootURL)
-		if err != nil {
-			logger.Error("failed to create email messenger", "error", err)
-		} else {
-			m["email"] = emailMessenger
-			messengers.RegisterSchema("email", messengers.GetEmailNotifySchema())
-			logger.Info("email messenger initialized")
-		}
+// newEnforcer initializes casbin with the RBAC model and policies stored in the database
+func newEnforcer(db *sqlx.DB) (*casbin.Enforcer, error) {
+	modelContent, err := StaticFiles.ReadFile("configs/rbac_model.conf")
+	if err != nil {
+		return nil, fmt.Errorf("could not read rbac_model.conf from embedded FS: %w", err)
+	}
+	m, err := casbin_model.NewModelFromString(string(modelContent))
+	if err != nil {
+		return nil, fmt.Errorf("could not create casbin model: %w", err)
 	}
 
-	if cfg.Webhook.Enabled {
-		webhookMessenger, err := messengers.NewWebhookMessenger(cfg.Webhook, logger.WithGroup("webhook_messenger"))
-		if err != nil {
-			logger.Error("failed to create webhook messenger", "error", err)
-		} else {
-			m["webhook"] = webhookMessenger
-			messengers.RegisterSchema("webhook", messengers.GetWebhookNotifySchema())
-			logger.Info("webhook messenger initialized")
-		}
+	a := sqlxadapter.NewAdapterFromOptions(&sqlxadapter.AdapterOptions{DB: db})
+
+	enforcer, err := casbin.NewEnforcer(m, a)
+	if err != nil {
+		return nil, fmt.Errorf("could not initialize casbin enforcer: %w", err)
 	}
+	enforcer.AddNamedDomainMatchingFunc("g", "keyMatch2", util.KeyMatch2)
 
-	return m
+	return enforcer, nil
 }
 
 // initializeSharedComponents sets up all shared components (DB, scheduler, core, etc.)
 func initializeSharedComponents() *SharedComponents {
-	loglevel := slog.LevelInfo
-	if os.Getenv("DEBUG_LOG") == "true" {
-		loglevel = slog.LevelDebug
-	}
+	// The level can be changed by reloading the config
+	loglevel := new(slog.LevelVar)
+	loglevel.Set(logLevel(appConfig.Logger.Level))
 
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
 		Level: loglevel,
@@ -139,37 +197,18 @@ func initializeSharedComponents() *SharedComponents {
 	if err := os.MkdirAll(appConfig.Logger.Directory, 0755); err != nil {
 		log.Fatalf("could not create log directory: %v", err)
 	}
-	fileLogManager := streamlogger.NewFileLogManager(streamlogger.FileLogManagerCfg{
-		RetentionTime: appConfig.Logger.RetentionTime,
-		MaxSizeBytes:  appConfig.Logger.MaxSizeBytes * 1024 * 1024,
-		LogDir:        appConfig.Logger.Directory,
-		ScanInterval:  appConfig.Logger.ScanInterval,
-	})
-	go fileLogManager.Run(context.Background(), logger.WithGroup("file_log_manager"))
+	logManager := initLogManager(logger)
 
-	dbConnectionString := appConfig.DB.ConnectionString()
-	db, err := sqlx.Connect("postgres", dbConnectionString)
+	db, err := connectDB(context.Background(), appConfig.DB)
 	if err != nil {
 		log.Fatalf("could not connect to database: %v", err)
 	}
+	configureDBPool(db, appConfig.DB)
 
-	// Initialize casbin with sqlx adapter
-	modelContent, err := StaticFiles.ReadFile("configs/rbac_model.conf")
+	enforcer, err := newEnforcer(db)
 	if err != nil {
-		log.Fatalf("could not read rbac_model.conf from embedded FS: %v", err)
-	}
-	m, err := casbin_model.NewModelFromString(string(modelContent))
-	if err != nil {
-		log.Fatalf("could not create casbin model: %v", err)
-	}
-
-	a := sqlxadapter.NewAdapter("postgres", dbConnectionString)
-
-	enforcer, err := casbin.NewEnforcer(m, a)
-	if err != nil {
-		log.Fatalf("could not initialize casbin enforcer: %v", err)
+		log.Fatal(err)
 	}
-	enforcer.AddNamedDomainMatchingFunc("g", "keyMatch2", util.KeyMatch2)
 
 	keeperURL := appConfig.Keystore.KeeperURL
 	if keeperURL == "" {
@@ -181,15 +220,96 @@ func initializeSharedComponents() *SharedComponents {
 		log.Fatalf("could not open secrets keeper: %v", err)
 	}
 
-	s := repo.NewPostgresStore(db)
-
-	jobStore := storage.NewPostgresStorage(db)
+	var replicaDB *sqlx.DB
+	if appConfig.DB.ReadReplicaDSN != "" {
+		replicaDB, err = sqlx.Connect("postgres", appConfig.DB.ReadReplicaDSN)
+		if err != nil {
+			log.Fatalf("could not connect to read replica: %v", err)
+		}
+		configureDBPool(replicaDB, appConfig.DB)
+	}
 
 	// Initialize metrics
 	var metricsManager *metrics.Manager
 	if appConfig.Metrics.Enabled {
 		metricsManager = metrics.NewManager()
 		metricsManager.Register()
+		metricsManager.RegisterDBStats(db.DB, "primary")
+		if replicaDB != nil {
+			metricsManager.RegisterDBStats(replicaDB.DB, "replica")
+		}
+
+		if appConfig.Metrics.Push.URL != "" {
+			go metricsManager.RunPusher(context.Background(), metrics.PushConfig{
+				URL:      appConfig.Metrics.Push.URL,
+				Job:      appConfig.Metrics.Push.Job,
+				Interval: appConfig.Metrics.Push.Interval,
+				Username: appConfig.Metrics.Push.Username,
+				Password: appConfig.Metrics.Push.Password,
+			}, logger.WithGroup("metrics"))
+		}
+	}
+
+	var storeOpts []repo.StoreOption
+	if metricsManager != nil {
+		storeOpts = append(storeOpts, repo.WithQueryObserver(metricsManager.ObserveDBQuery))
+	}
+
+	s := newStore(db, storeOpts...)
+	if replicaDB != nil {
+		s = repo.NewPostgresStoreWithReplica(db, replicaDB, storeOpts...)
+	}
+
+	var jobStore storage.Storage = storage.NewPostgresStorage(db)
+	if appConfig.DB.SQLite() {
+		jobStore = storage.NewSQLiteStorage(db)
+	}
+
+	// Initialize tracing
+	var shutdownTracing func(context.Context) error
+	if appConfig.Tracing.Enabled {
+		shutdownTracing, err = tracing.Setup(context.Background(), tracing.Config{
+			Endpoint:    appConfig.Tracing.Endpoint,
+			Insecure:    appConfig.Tracing.Insecure,
+			ServiceName: appConfig.Tracing.ServiceName,
+			SampleRatio: appConfig.Tracing.SampleRatio,
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Initialize the security events sink
+	var securityLog *securitylog.Logger
+	if appConfig.SecurityEvents.Enabled {
+		securityLog, err = securitylog.New(securitylog.Config{
+			Sink:          appConfig.SecurityEvents.Sink,
+			FilePath:      appConfig.SecurityEvents.FilePath,
+			SyslogNetwork: appConfig.SecurityEvents.SyslogNetwork,
+			SyslogAddress: appConfig.SecurityEvents.SyslogAddress,
+			SyslogTag:     appConfig.SecurityEvents.SyslogTag,
+			HTTPURL:       appConfig.SecurityEvents.HTTPURL,
+			HTTPHeaders:   appConfig.SecurityEvents.HTTPHeaders,
+			HTTPTimeout:   appConfig.SecurityEvents.HTTPTimeout,
+		}, logger.WithGroup("security_events"))
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Initialize the event bus publisher
+	var eventBus *eventbus.Publisher
+	if appConfig.EventBus.Enabled {
+		eventBus, err = eventbus.New(eventbus.Config{
+			Provider:      appConfig.EventBus.Provider,
+			URL:           appConfig.EventBus.URL,
+			Brokers:       appConfig.EventBus.Brokers,
+			SubjectPrefix: appConfig.EventBus.SubjectPrefix,
+			Timeout:       appConfig.EventBus.Timeout,
+		}, logger.WithGroup("event_bus"))
+		if err != nil {
+			log.Fatal(err)
+		}
 	}
 
 	// Build scheduler
@@ -198,19 +318,72 @@ func initializeSharedComponents() *SharedComponents {
 		WithWorkerCount(appConfig.Scheduler.WorkerCount).
 		WithCronSyncInterval(appConfig.Scheduler.CronSyncInterval).
 		WithRetryOptions(scheduler.DefaultRetryOptions()).
+		WithMetrics(metricsManager).
 		Build()
 	if err != nil {
 		log.Fatal(err)
 	}
 
+	flowStore, err := flowstore.Open(context.Background(), appConfig.App.FlowsDirectory, appConfig.App.FlowsCacheDir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	// Create core with scheduler
-	co, err := core.NewCore(appConfig.App.FlowsDirectory, s, sch, keeper, enforcer)
+	co, err := core.NewCore(flowStore, s, sch, keeper, enforcer)
+	if err != nil {
+		log.Fatal(err)
+	}
+	co.LogManager = logManager
+
+	// Files uploaded to file inputs are kept here until the executions using them have run
+	uploadsLocation := appConfig.App.UploadsDirectory
+	if uploadsLocation == "" {
+		uploadsLocation = filepath.Join(os.TempDir(), "flowctl-uploads")
+	}
+	uploads, err := uploadstore.Open(context.Background(), uploadsLocation)
+	if err != nil {
+		log.Fatal(err)
+	}
+	co.Uploads = uploads
+	if scan := appConfig.UploadScan; len(scan.Command) > 0 {
+		co.UploadValidators = append(co.UploadValidators, core.CommandScanner{Command: scan.Command, Timeout: scan.Timeout})
+	}
+	if scan := appConfig.UploadScan; scan.WebhookURL != "" {
+		co.UploadValidators = append(co.UploadValidators, core.WebhookScanner{URL: scan.WebhookURL, Client: &http.Client{Timeout: scan.Timeout}})
+	}
+
+	artifacts, err := artifactstore.Open(context.Background(), appConfig.App.ArtifactsDir, "")
 	if err != nil {
 		log.Fatal(err)
 	}
-	co.LogManager = fileLogManager
+	co.Artifacts = artifacts
 
-	messengersMap := initMessengers(appConfig.Messengers, co, logger)
+	// Files that actions publish are kept here after their executions finished
+	archiveLocation := appConfig.App.ArtifactsArchive
+	if archiveLocation == "" {
+		archiveLocation = filepath.Join(os.TempDir(), "flowctl-artifacts")
+	}
+	archive, err := artifactstore.OpenArchive(context.Background(), archiveLocation)
+	if err != nil {
+		log.Fatal(err)
+	}
+	co.Archive = archive
+
+	messengerRegistry := messengers.NewRegistry(appConfig.Messengers, messengers.RegistryOptions{
+		GroupResolver: co,
+		Logger:        logger,
+		RootURL:       appConfig.App.RootURL,
+	})
+	co.Messengers = messengerRegistry
+	co.Metrics = metricsManager
+
+	// Apply messenger settings configured at runtime on top of the config file, and keep
+	// applying the changes made through other instances
+	if err := co.LoadMessengerConfigs(context.Background()); err != nil {
+		logger.Error("failed to load messenger configs", "error", err)
+	}
+	go co.RunMessengerConfigSync(context.Background())
 
 	executorSigningKey, err := core.GenerateSigningKey()
 	if err != nil {
@@ -220,14 +393,24 @@ func initializeSharedComponents() *SharedComponents {
 
 	// Create flow execution handler with core's secrets provider
 	flowHandler := scheduler.NewFlowExecutionHandler(scheduler.FlowHandlerConfig{
-		Store:                s,
-		SecretsProvider:      co.GetMergedSecretsForFlow,
-		LogManager:           fileLogManager,
-		Logger:               logger.WithGroup("flow_handler"),
-		Metrics:              metricsManager,
-		FlowExecutionTimeout: appConfig.Scheduler.FlowExecutionTimeout,
-		ExecutorKeys:         executorKeys,
-		APIBaseURL:           appConfig.App.RootURL,
+		Store:                 s,
+		SecretsProvider:       co.GetMergedSecretsForFlow,
+		VariablesProvider:     co.GetVariablesForNamespace,
+		InputSealer:           co.SealInputs,
+		CompletionHook:        co.OnExecutionFinished,
+		SubflowQueuer:         co.QueueSubflowExecution,
+		LogManager:            logManager,
+		Logger:                logger.WithGroup("flow_handler"),
+		Metrics:               metricsManager,
+		FlowExecutionTimeout:  appConfig.Scheduler.FlowExecutionTimeout,
+		TelemetryInterval:     appConfig.Scheduler.TelemetryInterval,
+		ProgressFlushInterval: appConfig.Scheduler.ProgressFlushInterval,
+		ExecutorKeys:          executorKeys,
+		APIBaseURL:            appConfig.App.RootURL,
+		FlowFiles:             flowStore,
+		Uploads:               uploads,
+		Artifacts:             artifacts,
+		Archive:               archive,
 	})
 
 	// Set handler and queue config on scheduler
@@ -235,26 +418,19 @@ func initializeSharedComponents() *SharedComponents {
 		log.Fatal(err)
 	}
 
-	queueWeights := []scheduler.QueueWeight{
-		{PayloadType: scheduler.PayloadTypeFlowExecution, Weight: 100},
+	// The notification handler is always registered since messengers can be enabled at runtime
+	notificationHandler := scheduler.NewNotificationHandler(messengerRegistry, s, logger.WithGroup("notification_handler"))
+	if err := sch.SetHandler(notificationHandler); err != nil {
+		log.Fatal(err)
 	}
 
-	if len(messengersMap) > 0 {
-		// Create and register notification handler
-		notificationHandler := scheduler.NewNotificationHandler(messengersMap, s, logger.WithGroup("notification_handler"))
-		if err := sch.SetHandler(notificationHandler); err != nil {
-			log.Fatal(err)
-		}
-
-		// Update queue weights to include notifications
-		queueWeights = []scheduler.QueueWeight{
-			{PayloadType: scheduler.PayloadTypeFlowExecution, Weight: 90},
-			{PayloadType: scheduler.PayloadTypeNotification, Weight: 10},
-		}
-
-		logger.Info("notifications enabled", "channels", len(messengersMap))
+	queueWeights := []scheduler.QueueWeight{
+		{PayloadType: scheduler.PayloadTypeFlowExecution, Weight: 90},
+		{PayloadType: scheduler.PayloadTypeNotification, Weight: 10},
 	}
 
+	logger.Info("notifications enabled", "channels", messengerRegistry.Len())
+
 	if err := sch.SetQueueConfig(scheduler.QueueConfig{Queues: queueWeights}); err != nil {
 		log.Fatal(err)
 	}
@@ -264,24 +440,175 @@ func initializeSharedComponents() *SharedComponents {
 
 	// Set job syncer for cron scheduling
 	sch.SetJobSyncer(co.SyncScheduledFlowJobs)
+	sch.SetSkipChecker(co.ScheduledRunSkipReason)
+	sch.SetSLAMonitor(co.CheckSLABreaches)
+	sch.SetConcurrencyLimiter(co.AcquireExecutionSlot)
+	co.SetFlowReviewNamespaces(appConfig.FlowReview.Namespaces)
+	co.SetFlowLoadConcurrency(appConfig.App.FlowLoadConcurrency)
+
+	// Flows are loaded in the background so that the API is served while large flow directories
+	// are imported. Scheduled jobs are synced once they are loaded.
+	go func() {
+		if err := co.LoadFlows(context.Background()); err != nil {
+			logger.Error("could not load flows", "error", err)
+			return
+		}
+		status := co.FlowLoadStatus()
+		logger.Info("flows loaded", "loaded", status.Loaded, "failed", status.Failed, "duration", status.FinishedAt.Sub(status.StartedAt))
+		if err := sch.SyncScheduledJobs(context.Background()); err != nil {
+			logger.Error("could not sync scheduled jobs", "error", err)
+		}
+
+		// Message triggers start once their flows are loaded
+		for _, t := range appConfig.MessageTriggers {
+			go func() {
+				err := co.RunMessageTrigger(context.Background(), core.MessageTrigger{
+					Name:        t.Name,
+					URL:         t.URL,
+					Namespace:   t.Namespace,
+					Flow:        t.Flow,
+					Inputs:      t.Inputs,
+					Concurrency: t.Concurrency,
+				})
+				if err != nil {
+					logger.Error("message trigger stopped", "trigger", t.Name, "error", err)
+				}
+			}()
+		}
+	}()
+
+	gitSyncer, err := gitsync.NewSyncer(appConfig.GitSync, gitsync.Options{
+		Sync:   co.SyncNamespaceFlows,
+		Logger: logger.WithGroup("git_sync"),
+	})
+	if err != nil {
+		log.Fatalf("could not set up git sync: %v", err)
+	}
+	go gitSyncer.Run(context.Background())
+
+	if appConfig.Archive.After > 0 {
+		go co.RunExecutionArchiver(context.Background(), appConfig.Archive.After, appConfig.Archive.Interval, appConfig.Archive.BatchSize)
+	}
+
+	go func() {
+		var err error
+		if appConfig.DB.SQLite() {
+			err = co.PollExecutionEvents(context.Background(), db.DB)
+		} else {
+			err = co.ListenExecutionEvents(context.Background(), appConfig.DB.ConnectionString())
+		}
+		if err != nil {
+			logger.Error("could not listen for execution events", "error", err)
+		}
+	}()
+
+	if eventBus != nil {
+		co.SetEventBus(eventBus)
+		go co.RunEventBus(context.Background(), db.DB)
+	}
+
+	go co.RunRetentionPurge(context.Background(), retentionOptions(appConfig.Retention))
+
+	if appConfig.App.WatchFlows && flowstore.IsBucketURL(appConfig.App.FlowsDirectory) {
+		logger.Warn("watch_flows is not supported when flows are stored in a bucket")
+	} else if appConfig.App.WatchFlows {
+		go func() {
+			if err := co.WatchFlows(context.Background(), appConfig.App.WatchDebounce); err != nil {
+				logger.Error("could not watch flows directory", "error", err)
+			}
+		}()
+	}
 
 	return &SharedComponents{
 		DB:                 db,
+		ReplicaDB:          replicaDB,
 		Core:               co,
 		Scheduler:          sch,
 		Metrics:            metricsManager,
 		Logger:             logger,
+		LogLevel:           loglevel,
 		Keeper:             keeper,
-		Messengers:         messengersMap,
+		Messengers:         messengerRegistry,
+		GitSync:            gitSyncer,
 		ExecutorSigningKey: executorSigningKey,
+		ShutdownTracing:    shutdownTracing,
+		SecurityLog:        securityLog,
+		EventBus:           eventBus,
+		Uploads:            uploads,
+		Artifacts:          artifacts,
+		Archive:            archive,
 	}
 }
 
-func startServer(db *sqlx.DB, co *core.Core, metricsManager *metrics.Manager, logger *slog.Logger, executorSigningKey []byte) {
-	h, err := handlers.NewHandler(logger, db.DB, co, appConfig, executorSigningKey)
+// configureDBPool applies the connection pool settings to db. Without limits, busy workers can open
+// more connections than the database allows
+func configureDBPool(db *sqlx.DB, cfg config.DBConfig) {
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+}
+
+// connectDB connects to the database in cfg
+func connectDB(ctx context.Context, cfg config.DBConfig) (*sqlx.DB, error) {
+	if cfg.SQLite() {
+		return repo.OpenSQLite(cfg.DSN)
+	}
+	return sqlx.ConnectContext(ctx, "postgres", cfg.ConnectionString())
+}
+
+// newStore creates the store of the database in the config
+func newStore(db *sqlx.DB, opts ...repo.StoreOption) repo.Store {
+	if appConfig.DB.SQLite() {
+		return repo.NewSQLiteStore(db, opts...)
+	}
+	return repo.NewPostgresStore(db, opts...)
+}
+
+// initLogManager creates the log manager for the configured backend and starts its scan loop
+func initLogManager(logger *slog.Logger) streamlogger.LogManager {
+	switch appConfig.Logger.Backend {
+	case "object":
+		objectLogManager, err := streamlogger.NewObjectLogManager(context.Background(), streamlogger.ObjectLogManagerCfg{
+			BucketURL:            appConfig.Logger.BucketURL,
+			RetentionTime:        appConfig.Logger.RetentionTime,
+			MaxSizeBytes:         appConfig.Logger.MaxSizeBytes * 1024 * 1024,
+			MaxExecutionLogBytes: appConfig.Logger.MaxExecutionLogSize * 1024 * 1024,
+			LogDir:               appConfig.Logger.Directory,
+			ScanInterval:         appConfig.Logger.ScanInterval,
+			Shared:               appConfig.Logger.Shared,
+		})
+		if err != nil {
+			log.Fatalf("could not create object log manager: %v", err)
+		}
+		go objectLogManager.Run(context.Background(), logger.WithGroup("object_log_manager"))
+		return objectLogManager
+	default:
+		fileLogManager := streamlogger.NewFileLogManager(streamlogger.FileLogManagerCfg{
+			RetentionTime:        appConfig.Logger.RetentionTime,
+			MaxSizeBytes:         appConfig.Logger.MaxSizeBytes * 1024 * 1024,
+			MaxExecutionLogBytes: appConfig.Logger.MaxExecutionLogSize * 1024 * 1024,
+			LogDir:               appConfig.Logger.Directory,
+			ScanInterval:         appConfig.Logger.ScanInterval,
+			Compress:             appConfig.Logger.Compress,
+			Shared:               appConfig.Logger.Shared,
+		})
+		go fileLogManager.Run(context.Background(), logger.WithGroup("file_log_manager"))
+		return fileLogManager
+	}
+}
+
+func startServer(ctx context.Context, db *sqlx.DB, co *core.Core, metricsManager *metrics.Manager, logger *slog.Logger, executorSigningKey []byte, gitSyncer *gitsync.Syncer, securityLog *securitylog.Logger, reloader *configReloader) {
+	info := getBuildInfo()
+	h, err := handlers.NewHandler(logger, db.DB, co, appConfig, executorSigningKey, handlers.BuildInfo{
+		Version: info.Version,
+		Commit:  info.Commit,
+		Date:    info.Date,
+	}, gitSyncer, securityLog)
 	if err != nil {
 		log.Fatal(err)
 	}
+	h.SetConfigReloader(reloader.Reload)
 
 	e := echo.New()
 	e.Use(middleware.Recover())
@@ -290,6 +617,10 @@ func startServer(db *sqlx.DB, co *core.Core, metricsManager *metrics.Manager, lo
 		e.Use(metricsManager.HTTPMetricsMiddleware())
 	}
 
+	if appConfig.Tracing.Enabled {
+		e.Use(tracing.Middleware())
+	}
+
 	e.GET("/ping", h.HandlePing)
 	e.POST("/login", h.HandleLoginPage)
 	e.POST("/logout", h.HandleLogout)
@@ -298,12 +629,44 @@ func startServer(db *sqlx.DB, co *core.Core, metricsManager *metrics.Manager, lo
 	e.GET("/login/oidc/:provider", h.HandleOIDCLogin)
 	e.GET("/auth/callback", h.HandleAuthCallback)
 
+	// Push webhooks are authenticated with the webhook secret of the repository
+	e.POST("/webhooks/git/:namespace", h.HandleGitSyncWebhook)
+	// Flow webhooks are authenticated with the token in their URL and the signature of the request
+	e.POST("/api/v1/:namespace/hooks/:flowID/:token", h.HandleFlowWebhook)
+
 	if metricsManager != nil {
 		metricsPath := appConfig.Metrics.Path
 		if metricsPath == "" {
 			metricsPath = "/metrics"
 		}
-		e.GET(metricsPath, echo.WrapHandler(metricsManager.GetHandler()))
+
+		if appConfig.Metrics.ListenAddress != "" {
+			// A separate listener is meant for a private network and is not authenticated
+			go func() {
+				if err := metricsManager.Serve(context.Background(), appConfig.Metrics.ListenAddress, metricsPath); err != nil {
+					log.Fatalf("could not serve metrics: %v", err)
+				}
+			}()
+		} else if appConfig.Metrics.RequireAuth {
+			e.GET(metricsPath, echo.WrapHandler(metricsManager.GetHandler()), h.Authenticate, h.AuthorizeForRole("superuser"))
+		} else {
+			e.GET(metricsPath, echo.WrapHandler(metricsManager.GetHandler()))
+		}
+	}
+
+	if appConfig.Debug.Enabled {
+		if addr := appConfig.Debug.ListenAddress; addr != "" {
+			if !isLoopbackAddress(addr) {
+				log.Fatalf("debug.listen_address %s must be a loopback address", addr)
+			}
+			go func() {
+				if err := http.ListenAndServe(addr, handlers.DebugHandler()); err != nil {
+					log.Fatalf("could not serve debug endpoints: %v", err)
+				}
+			}()
+		} else {
+			e.Any("/debug/*", echo.WrapHandler(handlers.DebugHandler()), h.Authenticate, h.AuthorizeForRole("superuser"))
+		}
 	}
 
 	e.Logger.SetLevel(0)
@@ -312,10 +675,28 @@ func startServer(db *sqlx.DB, co *core.Core, metricsManager *metrics.Manager, lo
 
 	api := e.Group("/api/v1", h.Authenticate)
 
+	api.GET("/version", h.HandleGetVersion)
+	api.POST("/config/reload", h.HandleReloadConfig, h.AuthorizeForRole("superuser"))
+	api.GET("/flows/load-status", h.HandleGetFlowLoadStatus)
+
+	if appConfig.Debug.Enabled {
+		api.GET("/debug/runtime", h.HandleGetRuntimeStats, h.AuthorizeForRole("superuser"))
+		api.GET("/debug/goroutines", h.HandleGetGoroutines, h.AuthorizeForRole("superuser"))
+	}
+
+	api.GET("/audit", h.HandleListAuditLog, h.AuthorizeForRole("superuser"))
+
 	api.GET("/messengers", h.HandleGetMessengers)
+	api.GET("/messengers/config", h.HandleListMessengerConfigs, h.AuthorizeForRole("superuser"))
+	api.PUT("/messengers/:channel", h.HandleUpdateMessengerConfig, h.AuthorizeForRole("superuser"))
+	api.DELETE("/messengers/:channel", h.HandleDeleteMessengerConfig, h.AuthorizeForRole("superuser"))
+	api.POST("/messengers/:channel/test", h.HandleTestMessenger, h.AuthorizeForRole("superuser"))
 
 	api.GET("/users", h.HandleUserPagination, h.AuthorizeNamespaceAdmins())
 	api.GET("/users/profile", h.HandleGetUserProfile)
+	api.GET("/users/profile/tokens", h.HandleListAPITokens)
+	api.POST("/users/profile/tokens", h.HandleCreateAPIToken, h.LogCredentialAccess)
+	api.DELETE("/users/profile/tokens/:tokenID", h.HandleDeleteAPIToken, h.LogCredentialAccess)
 	api.GET("/users/:userID", h.HandleGetUser, h.AuthorizeForRole("superuser"))
 	api.POST("/users", h.HandleCreateUser, h.AuthorizeForRole("superuser"))
 	api.DELETE("/users/:userID", h.HandleDeleteUser, h.AuthorizeForRole("superuser"))
@@ -333,17 +714,44 @@ func startServer(db *sqlx.DB, co *core.Core, metricsManager *metrics.Manager, lo
 	api.GET("/permissions", h.HandleGetCasbinPermissions)
 	api.POST("/permissions/check", h.HandleCheckPermissions)
 
+	api.GET("/templates", h.HandleListFlowTemplates)
+	api.GET("/templates/:templateID", h.HandleGetFlowTemplate)
+
 	api.GET("/namespaces", h.HandleListNamespaces)
 	api.GET("/namespaces/:namespaceID", h.HandleGetNamespace, h.AuthorizeForRole("superuser"))
 	api.POST("/namespaces", h.HandleCreateNamespace, h.AuthorizeForRole("superuser"))
 	api.PUT("/namespaces/:namespaceID", h.HandleUpdateNamespace, h.AuthorizeForRole("superuser"))
 	api.DELETE("/namespaces/:namespaceID", h.HandleDeleteNamespace, h.AuthorizeForRole("superuser"))
+	api.PUT("/namespaces/:namespaceID/quota", h.HandleUpdateNamespaceQuota, h.AuthorizeForRole("superuser"))
+	api.DELETE("/namespaces/:namespaceID/quota", h.HandleDeleteNamespaceQuota, h.AuthorizeForRole("superuser"))
+	api.GET("/variables", h.HandleListGlobalVariables, h.AuthorizeForRole("superuser"))
+	api.PUT("/variables/:key", h.HandleSetGlobalVariable, h.AuthorizeForRole("superuser"))
+	api.DELETE("/variables/:key", h.HandleDeleteGlobalVariable, h.AuthorizeForRole("superuser"))
+	api.GET("/namespaces/:namespaceID/executor-policy", h.HandleGetExecutorPolicy, h.AuthorizeForRole("superuser"))
+	api.PUT("/namespaces/:namespaceID/executor-policy", h.HandleUpdateExecutorPolicy, h.AuthorizeForRole("superuser"))
+	api.DELETE("/namespaces/:namespaceID/executor-policy", h.HandleDeleteExecutorPolicy, h.AuthorizeForRole("superuser"))
 
 	namespaceGroup := api.Group("/:namespace", h.NamespaceMiddleware)
 	namespaceGroup.GET("/flows", h.HandleFlowsPagination, h.AuthorizeNamespaceAction(models.ResourceNamespace, models.RBACActionView))
 	namespaceGroup.POST("/flows", h.HandleCreateFlow, h.AuthorizeNamespaceAction(models.ResourceFlow, models.RBACActionCreate))
+	namespaceGroup.POST("/flows/templates/:templateID", h.HandleCreateFlowFromTemplate, h.AuthorizeNamespaceAction(models.ResourceFlow, models.RBACActionCreate))
+	namespaceGroup.POST("/flows/import", h.HandleImportFlow, h.AuthorizeNamespaceAction(models.ResourceFlow, models.RBACActionCreate))
 
 	namespaceGroup.GET("/flows/groups/me", h.HandleListMyFlowGroups, h.AuthorizeNamespaceAction(models.ResourceNamespace, models.RBACActionView))
+	namespaceGroup.GET("/flows/favorites", h.HandleListFlowFavorites, h.AuthorizeNamespaceAction(models.ResourceNamespace, models.RBACActionView))
+	namespaceGroup.GET("/flows/recent", h.HandleListRecentFlows, h.AuthorizeNamespaceAction(models.ResourceNamespace, models.RBACActionView))
+	namespaceGroup.PUT("/flows/:flowID/favorite", h.HandleAddFlowFavorite, h.AuthorizeNamespaceAction(models.ResourceFlow, models.RBACActionView))
+	namespaceGroup.DELETE("/flows/:flowID/favorite", h.HandleRemoveFlowFavorite, h.AuthorizeNamespaceAction(models.ResourceFlow, models.RBACActionView))
+	namespaceGroup.GET("/watches", h.HandleListExecutionWatches, h.AuthorizeNamespaceAction(models.ResourceNamespace, models.RBACActionView))
+	namespaceGroup.PUT("/flows/:flowID/watch", h.HandleWatchFlow, h.AuthorizeNamespaceAction(models.ResourceFlow, models.RBACActionView))
+	namespaceGroup.DELETE("/flows/:flowID/watch", h.HandleUnwatchFlow, h.AuthorizeNamespaceAction(models.ResourceFlow, models.RBACActionView))
+	namespaceGroup.PUT("/flows/executions/:execID/watch", h.HandleWatchExecution, h.AuthorizeNamespaceAction(models.ResourceExecution, models.RBACActionView))
+	namespaceGroup.DELETE("/flows/executions/:execID/watch", h.HandleUnwatchExecution, h.AuthorizeNamespaceAction(models.ResourceExecution, models.RBACActionView))
+	namespaceGroup.POST("/flows/:flowID/promote", h.HandlePromoteFlow, h.AuthorizeNamespaceAction(models.ResourceFlow, models.RBACActionView))
+	namespaceGroup.GET("/flows/:flowID/promotions", h.HandleListFlowPromotions, h.AuthorizeNamespaceAction(models.ResourceFlow, models.RBACActionView))
+	namespaceGroup.GET("/flows/:flowID/presets", h.HandleListInputPresets, h.AuthorizeNamespaceAction(models.ResourceFlow, models.RBACActionView))
+	namespaceGroup.POST("/flows/:flowID/presets", h.HandleSaveInputPreset, h.AuthorizeNamespaceAction(models.ResourceFlow, models.RBACActionExecute))
+	namespaceGroup.DELETE("/flows/:flowID/presets/:presetID", h.HandleDeleteInputPreset, h.AuthorizeNamespaceAction(models.ResourceFlow, models.RBACActionExecute))
 	namespaceGroup.GET("/flows/groups/:group", h.HandleGetFlowGroup, h.AuthorizeNamespaceAction(models.ResourceFlow, models.RBACActionView))
 	namespaceGroup.GET("/flows/groups", h.HandleListFlowGroups, h.AuthorizeNamespaceAction(models.ResourceFlow, models.RBACActionView))
 	namespaceGroup.POST("/flows/groups", h.HandleCreateFlowGroup, h.AuthorizeNamespaceAction(models.ResourceFlow, models.RBACActionCreate))
@@ -355,20 +763,46 @@ func startServer(db *sqlx.DB, co *core.Core, metricsManager *metrics.Manager, lo
 	namespaceGroup.DELETE("/flows/:flowID", h.HandleDeleteFlow, h.AuthorizeNamespaceAction(models.ResourceFlow, models.RBACActionDelete))
 
 	namespaceGroup.GET("/flows/executions/:execID", h.HandleGetExecutionSummary, h.AuthorizeNamespaceAction(models.ResourceExecution, models.RBACActionView))
+	namespaceGroup.GET("/flows/executions/:execID/telemetry", h.HandleGetExecutionTelemetry, h.AuthorizeNamespaceAction(models.ResourceExecution, models.RBACActionView))
+	namespaceGroup.GET("/flows/executions/:execID/timeline", h.HandleGetExecutionTimeline, h.AuthorizeNamespaceAction(models.ResourceExecution, models.RBACActionView))
+	namespaceGroup.GET("/flows/executions/:execID/outputs", h.HandleGetExecutionOutputs, h.AuthorizeNamespaceAction(models.ResourceExecution, models.RBACActionView))
+	namespaceGroup.GET("/flows/executions/:execID/artifacts", h.HandleListExecutionArtifacts, h.AuthorizeNamespaceAction(models.ResourceExecution, models.RBACActionView))
+	namespaceGroup.GET("/flows/executions/:execID/artifacts/:artifactID", h.HandleDownloadArtifact, h.AuthorizeNamespaceAction(models.ResourceExecution, models.RBACActionView))
 	namespaceGroup.POST("/flows/executions/:execID/cancel", h.HandleCancelExecution, h.AuthorizeNamespaceAction(models.ResourceExecution, models.RBACActionUpdate))
 	namespaceGroup.POST("/flows/executions/:execID/retry", h.HandleRetryExecution, h.AuthorizeNamespaceAction(models.ResourceExecution, models.RBACActionUpdate))
+	// Resuming from another action can skip approvals, so it also requires the permission to approve
+	namespaceGroup.POST("/flows/executions/:execID/resume", h.HandleResumeExecution, h.AuthorizeNamespaceAction(models.ResourceExecution, models.RBACActionUpdate), h.AuthorizeNamespaceAction(models.ResourceApproval, models.RBACActionApprove))
 	namespaceGroup.GET("/flows/:flowID/executions", h.HandleExecutionsPagination, h.AuthorizeNamespaceAction(models.ResourceExecution, models.RBACActionView))
 	namespaceGroup.GET("/flows/executions", h.HandleAllExecutionsPagination, h.AuthorizeNamespaceAction(models.ResourceNamespace, models.RBACActionView))
+	namespaceGroup.GET("/flows/executions/archived", h.HandleArchivedExecutionsPagination, h.AuthorizeNamespaceAction(models.ResourceNamespace, models.RBACActionView))
+	namespaceGroup.GET("/flows/executions/archived/:execID", h.HandleGetArchivedExecution, h.AuthorizeNamespaceAction(models.ResourceExecution, models.RBACActionView))
+
+	namespaceGroup.GET("/analytics/failures", h.HandleGetFailureAnalytics, h.AuthorizeNamespaceAction(models.ResourceExecution, models.RBACActionView))
+	namespaceGroup.GET("/schedules/lag", h.HandleGetScheduleLag, h.AuthorizeNamespaceAction(models.ResourceFlow, models.RBACActionView))
+	namespaceGroup.GET("/schedules/calendar", h.HandleGetScheduleCalendar, h.AuthorizeNamespaceAction(models.ResourceFlow, models.RBACActionView))
+
+	namespaceGroup.GET("/flows/sync", h.HandleGetGitSyncStatus, h.AuthorizeNamespaceAction(models.ResourceFlow, models.RBACActionView))
+	namespaceGroup.POST("/flows/sync", h.HandleTriggerGitSync, h.AuthorizeNamespaceAction(models.ResourceFlow, models.RBACActionUpdate))
+	namespaceGroup.GET("/flows/errors", h.HandleListFlowImportErrors, h.AuthorizeNamespaceAction(models.ResourceFlow, models.RBACActionView))
+	namespaceGroup.GET("/flows/revisions", h.HandleListFlowRevisions, h.AuthorizeNamespaceAction(models.ResourceFlow, models.RBACActionView))
+	namespaceGroup.GET("/flows/revisions/:revisionID", h.HandleGetFlowRevision, h.AuthorizeNamespaceAction(models.ResourceFlow, models.RBACActionViewConfig))
+	namespaceGroup.POST("/flows/revisions/:revisionID", h.HandleReviewFlowRevision, h.AuthorizeNamespaceAction(models.ResourceFlow, models.RBACActionApprove))
 
 	namespaceGroup.GET("/flows/:flowID/inputs", h.HandleGetFlowInputs, h.AuthorizeNamespaceAction(models.ResourceFlow, models.RBACActionView))
 	namespaceGroup.GET("/flows/:flowID/meta", h.HandleGetFlowMeta, h.AuthorizeNamespaceAction(models.ResourceFlow, models.RBACActionView))
 	namespaceGroup.GET("/flows/:flowID/config", h.HandleGetFlowConfig, h.AuthorizeNamespaceAction(models.ResourceFlow, models.RBACActionViewConfig))
+	namespaceGroup.GET("/flows/:flowID/versions", h.HandleListFlowVersions, h.AuthorizeNamespaceAction(models.ResourceFlow, models.RBACActionViewConfig))
+	namespaceGroup.GET("/flows/:flowID/versions/:from/diff/:to", h.HandleDiffFlowVersions, h.AuthorizeNamespaceAction(models.ResourceFlow, models.RBACActionViewConfig))
+
+	namespaceGroup.GET("/flows/:flowID/webhook", h.HandleGetFlowWebhook, h.AuthorizeNamespaceAction(models.ResourceFlow, models.RBACActionUpdate))
+	namespaceGroup.POST("/flows/:flowID/webhook", h.HandleCreateFlowWebhook, h.AuthorizeNamespaceAction(models.ResourceFlow, models.RBACActionUpdate), h.LogCredentialAccess)
+	namespaceGroup.DELETE("/flows/:flowID/webhook", h.HandleDeleteFlowWebhook, h.AuthorizeNamespaceAction(models.ResourceFlow, models.RBACActionUpdate))
 
 	namespaceGroup.GET("/flows/:flowID/secrets", h.HandleListFlowSecrets, h.AuthorizeNamespaceAction(models.ResourceFlowSecret, models.RBACActionView))
-	namespaceGroup.GET("/flows/:flowID/secrets/:secretID", h.HandleGetFlowSecret, h.AuthorizeNamespaceAction(models.ResourceFlowSecret, models.RBACActionView))
-	namespaceGroup.POST("/flows/:flowID/secrets", h.HandleCreateFlowSecret, h.AuthorizeNamespaceAction(models.ResourceFlowSecret, models.RBACActionCreate))
-	namespaceGroup.PUT("/flows/:flowID/secrets/:secretID", h.HandleUpdateFlowSecret, h.AuthorizeNamespaceAction(models.ResourceFlowSecret, models.RBACActionUpdate))
-	namespaceGroup.DELETE("/flows/:flowID/secrets/:secretID", h.HandleDeleteFlowSecret, h.AuthorizeNamespaceAction(models.ResourceFlowSecret, models.RBACActionDelete))
+	namespaceGroup.GET("/flows/:flowID/secrets/:secretID", h.HandleGetFlowSecret, h.AuthorizeNamespaceAction(models.ResourceFlowSecret, models.RBACActionView), h.LogCredentialAccess)
+	namespaceGroup.POST("/flows/:flowID/secrets", h.HandleCreateFlowSecret, h.AuthorizeNamespaceAction(models.ResourceFlowSecret, models.RBACActionCreate), h.LogCredentialAccess)
+	namespaceGroup.PUT("/flows/:flowID/secrets/:secretID", h.HandleUpdateFlowSecret, h.AuthorizeNamespaceAction(models.ResourceFlowSecret, models.RBACActionUpdate), h.LogCredentialAccess)
+	namespaceGroup.DELETE("/flows/:flowID/secrets/:secretID", h.HandleDeleteFlowSecret, h.AuthorizeNamespaceAction(models.ResourceFlowSecret, models.RBACActionDelete), h.LogCredentialAccess)
 
 	namespaceGroup.GET("/flows/:flowID/schedules", h.HandleListSchedules, h.AuthorizeNamespaceAction(models.ResourceFlow, models.RBACActionExecute))
 	namespaceGroup.GET("/flows/:flowID/schedules/:schedule_id", h.HandleGetSchedule, h.AuthorizeNamespaceAction(models.ResourceFlow, models.RBACActionExecute))
@@ -377,8 +811,10 @@ func startServer(db *sqlx.DB, co *core.Core, metricsManager *metrics.Manager, lo
 	namespaceGroup.DELETE("/flows/:flowID/schedules/:schedule_id", h.HandleDeleteSchedule, h.AuthorizeNamespaceAction(models.ResourceFlow, models.RBACActionExecute))
 
 	namespaceGroup.POST("/trigger/:flow", h.HandleFlowTrigger, h.AuthorizeNamespaceAction(models.ResourceFlow, models.RBACActionExecute))
+	namespaceGroup.GET("/logs/search", h.HandleLogSearch, h.AuthorizeNamespaceAction(models.ResourceExecution, models.RBACActionView))
 	namespaceGroup.GET("/logs/:logID", h.HandleLogStreaming, h.AuthorizeNamespaceAction(models.ResourceExecution, models.RBACActionView))
 	namespaceGroup.GET("/logs/:logID/download", h.HandleLogDownload, h.AuthorizeNamespaceAction(models.ResourceExecution, models.RBACActionView))
+	namespaceGroup.GET("/events", h.HandleExecutionEvents, h.AuthorizeNamespaceAction(models.ResourceExecution, models.RBACActionView))
 
 	namespaceGroup.GET("/nodes", h.HandleListNodes, h.AuthorizeNamespaceAction(models.ResourceNode, models.RBACActionView))
 	namespaceGroup.GET("/nodes/stats", h.HandleGetNodeStats, h.AuthorizeNamespaceAction(models.ResourceNode, models.RBACActionView))
@@ -386,17 +822,27 @@ func startServer(db *sqlx.DB, co *core.Core, metricsManager *metrics.Manager, lo
 	namespaceGroup.POST("/nodes", h.HandleCreateNode, h.AuthorizeNamespaceAction(models.ResourceNode, models.RBACActionCreate))
 	namespaceGroup.PUT("/nodes/:nodeID", h.HandleUpdateNode, h.AuthorizeNamespaceAction(models.ResourceNode, models.RBACActionUpdate))
 	namespaceGroup.DELETE("/nodes/:nodeID", h.HandleDeleteNode, h.AuthorizeNamespaceAction(models.ResourceNode, models.RBACActionDelete))
+	namespaceGroup.POST("/nodes/:nodeID/test", h.HandleTestNode, h.AuthorizeNamespaceAction(models.ResourceNode, models.RBACActionUpdate))
 
 	namespaceGroup.GET("/credentials", h.HandleListCredentials, h.AuthorizeNamespaceAction(models.ResourceCredential, models.RBACActionView))
-	namespaceGroup.GET("/credentials/:credID", h.HandleGetCredential, h.AuthorizeNamespaceAction(models.ResourceCredential, models.RBACActionView))
-	namespaceGroup.POST("/credentials", h.HandleCreateCredential, h.AuthorizeNamespaceAction(models.ResourceCredential, models.RBACActionCreate))
-	namespaceGroup.PUT("/credentials/:credID", h.HandleUpdateCredential, h.AuthorizeNamespaceAction(models.ResourceCredential, models.RBACActionUpdate))
-	namespaceGroup.DELETE("/credentials/:credID", h.HandleDeleteCredential, h.AuthorizeNamespaceAction(models.ResourceCredential, models.RBACActionDelete))
+	namespaceGroup.GET("/credentials/:credID", h.HandleGetCredential, h.AuthorizeNamespaceAction(models.ResourceCredential, models.RBACActionView), h.LogCredentialAccess)
+	namespaceGroup.POST("/credentials", h.HandleCreateCredential, h.AuthorizeNamespaceAction(models.ResourceCredential, models.RBACActionCreate), h.LogCredentialAccess)
+	namespaceGroup.PUT("/credentials/:credID", h.HandleUpdateCredential, h.AuthorizeNamespaceAction(models.ResourceCredential, models.RBACActionUpdate), h.LogCredentialAccess)
+	namespaceGroup.DELETE("/credentials/:credID", h.HandleDeleteCredential, h.AuthorizeNamespaceAction(models.ResourceCredential, models.RBACActionDelete), h.LogCredentialAccess)
+
+	namespaceGroup.GET("/notifications/deliveries", h.HandleListNotificationDeliveries, h.AuthorizeNamespaceAction(models.ResourceExecution, models.RBACActionView))
 
 	namespaceGroup.GET("/approvals", h.HandleListApprovals, h.AuthorizeNamespaceAction(models.ResourceApproval, models.RBACActionView))
+	namespaceGroup.GET("/approvals/delegations", h.HandleListApprovalDelegations, h.AuthorizeNamespaceAction(models.ResourceApproval, models.RBACActionView))
+	namespaceGroup.POST("/approvals/delegations", h.HandleCreateApprovalDelegation, h.AuthorizeNamespaceAction(models.ResourceApproval, models.RBACActionApprove))
+	namespaceGroup.DELETE("/approvals/delegations/:delegationID", h.HandleDeleteApprovalDelegation, h.AuthorizeNamespaceAction(models.ResourceApproval, models.RBACActionApprove))
 	namespaceGroup.GET("/approvals/:approvalID", h.HandleGetApproval, h.AuthorizeNamespaceAction(models.ResourceApproval, models.RBACActionView))
 	namespaceGroup.POST("/approvals/:approvalID", h.HandleApprovalAction, h.AuthorizeNamespaceAction(models.ResourceApproval, models.RBACActionApprove))
 
+	namespaceGroup.GET("/tasks", h.HandleListManualTasks, h.AuthorizeNamespaceAction(models.ResourceApproval, models.RBACActionView))
+	namespaceGroup.GET("/tasks/:taskID", h.HandleGetManualTask, h.AuthorizeNamespaceAction(models.ResourceApproval, models.RBACActionView))
+	namespaceGroup.POST("/tasks/:taskID", h.HandleCompleteManualTask, h.AuthorizeNamespaceAction(models.ResourceApproval, models.RBACActionApprove))
+
 	namespaceGroup.GET("/members", h.HandleGetNamespaceMembers, h.AuthorizeNamespaceAction(models.ResourceMember, models.RBACActionView))
 	namespaceGroup.POST("/members", h.HandleAddNamespaceMember, h.AuthorizeNamespaceAction(models.ResourceMember, models.RBACActionCreate))
 	namespaceGroup.PUT("/members/:membershipID", h.HandleUpdateNamespaceMember, h.AuthorizeNamespaceAction(models.ResourceMember, models.RBACActionUpdate))
@@ -406,11 +852,24 @@ func startServer(db *sqlx.DB, co *core.Core, metricsManager *metrics.Manager, lo
 	namespaceGroup.POST("/members/:membershipID/groups", h.HandleGrantGroupAccess, h.AuthorizeNamespaceAction(models.ResourceMember, models.RBACActionUpdate))
 	namespaceGroup.DELETE("/members/:membershipID/groups/:group", h.HandleRevokeGroupAccess, h.AuthorizeNamespaceAction(models.ResourceMember, models.RBACActionUpdate))
 
+	namespaceGroup.GET("/retention", h.HandleGetRetentionPolicy, h.AuthorizeNamespaceAction(models.ResourceNamespace, models.RBACActionView))
+	namespaceGroup.GET("/retention/preview", h.HandlePreviewRetention, h.AuthorizeNamespaceAction(models.ResourceNamespace, models.RBACActionUpdate))
+	namespaceGroup.PUT("/retention", h.HandleUpdateRetentionPolicy, h.AuthorizeNamespaceAction(models.ResourceNamespace, models.RBACActionUpdate))
+	namespaceGroup.DELETE("/retention", h.HandleDeleteRetentionPolicy, h.AuthorizeNamespaceAction(models.ResourceNamespace, models.RBACActionUpdate))
+	namespaceGroup.GET("/audit", h.HandleListNamespaceAuditLog, h.AuthorizeNamespaceAction(models.ResourceNamespace, models.RBACActionUpdate))
+	namespaceGroup.GET("/defaults", h.HandleGetNamespaceDefaults, h.AuthorizeNamespaceAction(models.ResourceNamespace, models.RBACActionView))
+	namespaceGroup.PUT("/defaults", h.HandleUpdateNamespaceDefaults, h.AuthorizeNamespaceAction(models.ResourceNamespace, models.RBACActionUpdate))
+	namespaceGroup.DELETE("/defaults", h.HandleDeleteNamespaceDefaults, h.AuthorizeNamespaceAction(models.ResourceNamespace, models.RBACActionUpdate))
+	namespaceGroup.GET("/usage", h.HandleGetNamespaceUsage, h.AuthorizeNamespaceAction(models.ResourceNamespace, models.RBACActionView))
+
+	namespaceGroup.GET("/variables", h.HandleListNamespaceVariables, h.AuthorizeNamespaceAction(models.ResourceNamespace, models.RBACActionView))
+	namespaceGroup.PUT("/variables/:key", h.HandleSetNamespaceVariable, h.AuthorizeNamespaceAction(models.ResourceNamespace, models.RBACActionUpdate))
+	namespaceGroup.DELETE("/variables/:key", h.HandleDeleteNamespaceVariable, h.AuthorizeNamespaceAction(models.ResourceNamespace, models.RBACActionUpdate))
 	namespaceGroup.GET("/secrets", h.HandleListNamespaceSecrets, h.AuthorizeNamespaceAction(models.ResourceNamespaceSecret, models.RBACActionView))
-	namespaceGroup.GET("/secrets/:secretID", h.HandleGetNamespaceSecret, h.AuthorizeNamespaceAction(models.ResourceNamespaceSecret, models.RBACActionView))
-	namespaceGroup.POST("/secrets", h.HandleCreateNamespaceSecret, h.AuthorizeNamespaceAction(models.ResourceNamespaceSecret, models.RBACActionCreate))
-	namespaceGroup.PUT("/secrets/:secretID", h.HandleUpdateNamespaceSecret, h.AuthorizeNamespaceAction(models.ResourceNamespaceSecret, models.RBACActionUpdate))
-	namespaceGroup.DELETE("/secrets/:secretID", h.HandleDeleteNamespaceSecret, h.AuthorizeNamespaceAction(models.ResourceNamespaceSecret, models.RBACActionDelete))
+	namespaceGroup.GET("/secrets/:secretID", h.HandleGetNamespaceSecret, h.AuthorizeNamespaceAction(models.ResourceNamespaceSecret, models.RBACActionView), h.LogCredentialAccess)
+	namespaceGroup.POST("/secrets", h.HandleCreateNamespaceSecret, h.AuthorizeNamespaceAction(models.ResourceNamespaceSecret, models.RBACActionCreate), h.LogCredentialAccess)
+	namespaceGroup.PUT("/secrets/:secretID", h.HandleUpdateNamespaceSecret, h.AuthorizeNamespaceAction(models.ResourceNamespaceSecret, models.RBACActionUpdate), h.LogCredentialAccess)
+	namespaceGroup.DELETE("/secrets/:secretID", h.HandleDeleteNamespaceSecret, h.AuthorizeNamespaceAction(models.ResourceNamespaceSecret, models.RBACActionDelete), h.LogCredentialAccess)
 
 	buildFS, err := fs.Sub(StaticFiles, "site/build")
 	if err != nil {
@@ -433,21 +892,55 @@ func startServer(db *sqlx.DB, co *core.Core, metricsManager *metrics.Manager, lo
 		return c.Stream(http.StatusOK, "text/html; charset=utf-8", indexFile)
 	})
 
+	// Requests in flight are given the shutdown timeout to finish once ctx is done
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), appConfig.App.ShutdownTimeout)
+		defer cancel()
+		if err := e.Shutdown(shutdownCtx); err != nil {
+			logger.Error("could not shut down the server", "error", err)
+		}
+	}()
+
 	address := appConfig.App.Address
 	if appConfig.App.UseTLS {
-		log.Fatal(e.StartTLS(address, appConfig.App.HTTPTLSCert, appConfig.App.HTTPTLSKey))
+		err = e.StartTLS(address, appConfig.App.HTTPTLSCert, appConfig.App.HTTPTLSKey)
 	} else {
-		log.Fatal(e.Start(address))
+		err = e.Start(address)
+	}
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
 	}
 }
 
-// startWorker creates a worker that processes jobs using the shared scheduler.
-func startWorker(sch scheduler.TaskScheduler, logger *slog.Logger) {
+// startWorker creates a worker that processes jobs using the shared scheduler until ctx is done.
+// Running jobs are then handed off, their executions are adopted by the server that picks up
+// the jobs next.
+func startWorker(ctx context.Context, sch scheduler.TaskScheduler, logger *slog.Logger) {
 	logger.Info("Starting scheduler worker")
 	if err := sch.Start(context.Background()); err != nil {
 		logger.Error("Failed to start scheduler", "error", err)
 		log.Fatal(err)
 	}
 
-	select {}
+	<-ctx.Done()
+	logger.Info("Stopping scheduler worker")
+	stopCtx, cancel := context.WithTimeout(context.Background(), appConfig.App.ShutdownTimeout)
+	defer cancel()
+	if err := sch.Stop(stopCtx); err != nil {
+		logger.Error("could not stop the scheduler", "error", err)
+	}
+}
+
+// isLoopbackAddress reports whether the host of a listen address only accepts local connections
+func isLoopbackAddress(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
 }
@@ -0,0 +1,716 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+const (
+	// topMaxLogLines is the number of log lines kept for the execution being viewed
+	topMaxLogLines = 5000
+	// topExecutionCount is the number of recent executions listed
+	topExecutionCount = 50
+)
+
+type topView int
+
+const (
+	topViewList topView = iota
+	topViewLogs
+)
+
+type topKey int
+
+const (
+	topKeyRune topKey = iota
+	topKeyUp
+	topKeyDown
+	topKeyPageUp
+	topKeyPageDown
+	topKeyEnter
+	topKeyEsc
+	topKeyTab
+	topKeyCtrlC
+)
+
+type topKeyEvent struct {
+	key topKey
+	r   rune
+}
+
+// topExecution is an execution as listed by the executions API
+type topExecution struct {
+	ID              string `json:"id"`
+	FlowID          string `json:"flow_id"`
+	FlowName        string `json:"flow_name"`
+	Status          string `json:"status"`
+	TriggeredBy     string `json:"triggered_by"`
+	CurrentActionID string `json:"current_action_id"`
+	CreatedAt       string `json:"created_at"`
+	StartedAt       string `json:"started_at"`
+	CompletedAt     string `json:"completed_at"`
+	Progress        *struct {
+		ActionIndex  int `json:"action_index"`
+		TotalActions int `json:"total_actions"`
+	} `json:"progress"`
+}
+
+type topLogLine struct {
+	actionID string
+	text     string
+}
+
+type topExecutionsResult struct {
+	executions []topExecution
+	err        error
+}
+
+type topLogEvent struct {
+	execID string
+	lines  []topLogLine
+	done   bool
+	err    error
+}
+
+type topActionResult struct {
+	message string
+	err     error
+}
+
+// topModel is the state of the terminal UI. It is only accessed from the event loop.
+type topModel struct {
+	client    *apiClient
+	namespace string
+
+	view       topView
+	executions []topExecution
+	selected   int
+	listErr    error
+
+	logExec    topExecution
+	logLines   []topLogLine
+	logActions []string
+	logAction  string
+	logScroll  int
+	logDone    bool
+	logErr     error
+	logCancel  context.CancelFunc
+
+	confirm   string
+	onConfirm func()
+	message   string
+	messageAt time.Time
+}
+
+// topCmd shows a terminal UI with the executions of a namespace on a remote server
+var topCmd = &cobra.Command{
+	Use:   "top",
+	Short: "Watch executions on a remote flowctl server in a terminal UI",
+	Long: `Show the recent executions of a namespace with their live status, and drill down
+into the logs of an execution.
+
+Keys in the execution list:
+  up/down, j/k   select an execution
+  enter          view the logs of the selected execution
+  c              cancel the selected execution
+  r              retry the selected execution
+  q, ctrl+c      quit
+
+Keys in the log view:
+  up/down, j/k   scroll, pgup/pgdn scroll a page
+  G              follow the end of the logs
+  tab, a         show the logs of the next action only
+  c, r           cancel or retry the execution
+  esc, q         back to the execution list`,
+	Example:      `  flowctl top -n production`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newAPIClient(cmd)
+		if err != nil {
+			return err
+		}
+
+		namespace, _ := cmd.Flags().GetString("namespace")
+		interval, _ := cmd.Flags().GetDuration("interval")
+		if interval < time.Second {
+			return errors.New("--interval must be at least 1s")
+		}
+
+		fd := int(os.Stdin.Fd())
+		if !term.IsTerminal(fd) || !term.IsTerminal(int(os.Stdout.Fd())) {
+			return errors.New("flowctl top needs a terminal")
+		}
+
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return fmt.Errorf("could not set up the terminal: %w", err)
+		}
+		// Switch to the alternate screen and hide the cursor
+		fmt.Print("\x1b[?1049h\x1b[?25l")
+		defer func() {
+			fmt.Print("\x1b[?25h\x1b[?1049l")
+			term.Restore(fd, state)
+		}()
+
+		m := &topModel{client: client, namespace: namespace}
+		return m.run(interval)
+	},
+}
+
+func (m *topModel) run(interval time.Duration) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	defer m.stopLogs()
+
+	keys := make(chan topKeyEvent)
+	go readTopKeys(os.Stdin, keys)
+
+	execCh := make(chan topExecutionsResult, 1)
+	logCh := make(chan topLogEvent, 64)
+	actionCh := make(chan topActionResult, 1)
+
+	refresh := func() {
+		go func() {
+			executions, err := m.fetchExecutions(ctx)
+			select {
+			case execCh <- topExecutionsResult{executions: executions, err: err}:
+			case <-ctx.Done():
+			}
+		}()
+	}
+	refresh()
+
+	fetchTicker := time.NewTicker(interval)
+	defer fetchTicker.Stop()
+	// Redraw regularly so that resizing the terminal and durations are picked up
+	renderTicker := time.NewTicker(time.Second)
+	defer renderTicker.Stop()
+
+	m.render()
+	for {
+		select {
+		case k, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			if quit := m.handleKey(ctx, k, logCh, actionCh); quit {
+				return nil
+			}
+		case r := <-execCh:
+			m.executions, m.listErr = r.executions, r.err
+			m.selected = min(m.selected, max(len(m.executions)-1, 0))
+			for _, e := range m.executions {
+				if e.ID == m.logExec.ID {
+					m.logExec = e
+				}
+			}
+		case ev := <-logCh:
+			m.handleLogEvent(ev)
+		case r := <-actionCh:
+			m.setMessage(r.message, r.err)
+			refresh()
+		case <-fetchTicker.C:
+			refresh()
+		case <-renderTicker.C:
+		}
+		m.render()
+	}
+}
+
+// handleKey updates the state for a key press and reports whether the UI should quit
+func (m *topModel) handleKey(ctx context.Context, k topKeyEvent, logCh chan<- topLogEvent, actionCh chan<- topActionResult) bool {
+	if k.key == topKeyCtrlC {
+		return true
+	}
+
+	if m.confirm != "" {
+		if k.key == topKeyRune && (k.r == 'y' || k.r == 'Y') {
+			m.onConfirm()
+		}
+		m.confirm, m.onConfirm = "", nil
+		return false
+	}
+
+	selected, ok := m.selectedExecution()
+
+	switch {
+	case k.key == topKeyRune && k.r == 'c' && ok:
+		m.confirm = fmt.Sprintf("Cancel execution %s of %s? [y/N]", shortID(selected.ID), selected.FlowName)
+		m.onConfirm = func() { m.executionAction(ctx, selected, "cancel", actionCh) }
+		return false
+	case k.key == topKeyRune && k.r == 'r' && ok:
+		m.confirm = fmt.Sprintf("Retry execution %s of %s? [y/N]", shortID(selected.ID), selected.FlowName)
+		m.onConfirm = func() { m.executionAction(ctx, selected, "retry", actionCh) }
+		return false
+	}
+
+	if m.view == topViewList {
+		switch {
+		case k.key == topKeyRune && k.r == 'q':
+			return true
+		case k.key == topKeyUp || (k.key == topKeyRune && k.r == 'k'):
+			m.selected = max(m.selected-1, 0)
+		case k.key == topKeyDown || (k.key == topKeyRune && k.r == 'j'):
+			m.selected = min(m.selected+1, max(len(m.executions)-1, 0))
+		case k.key == topKeyEnter && ok:
+			m.startLogs(ctx, selected, logCh)
+		}
+		return false
+	}
+
+	_, height := m.size()
+	page := max(height-4, 1)
+	switch {
+	case k.key == topKeyEsc || (k.key == topKeyRune && k.r == 'q'):
+		m.stopLogs()
+		m.view = topViewList
+	case k.key == topKeyUp || (k.key == topKeyRune && k.r == 'k'):
+		m.logScroll++
+	case k.key == topKeyDown || (k.key == topKeyRune && k.r == 'j'):
+		m.logScroll = max(m.logScroll-1, 0)
+	case k.key == topKeyPageUp:
+		m.logScroll += page
+	case k.key == topKeyPageDown:
+		m.logScroll = max(m.logScroll-page, 0)
+	case k.key == topKeyRune && k.r == 'G':
+		m.logScroll = 0
+	case k.key == topKeyTab || (k.key == topKeyRune && k.r == 'a'):
+		m.logAction = nextAction(m.logActions, m.logAction)
+		m.logScroll = 0
+	}
+	return false
+}
+
+// selectedExecution returns the execution that cancel and retry apply to
+func (m *topModel) selectedExecution() (topExecution, bool) {
+	if m.view == topViewLogs {
+		return m.logExec, true
+	}
+	if m.selected < len(m.executions) {
+		return m.executions[m.selected], true
+	}
+	return topExecution{}, false
+}
+
+func (m *topModel) executionAction(ctx context.Context, e topExecution, action string, actionCh chan<- topActionResult) {
+	path := fmt.Sprintf("/api/v1/%s/flows/executions/%s/%s", url.PathEscape(m.namespace), url.PathEscape(e.ID), action)
+	go func() {
+		var result topActionResult
+		if _, err := m.client.do(ctx, http.MethodPost, path, nil, ""); err != nil {
+			result.err = fmt.Errorf("could not %s execution %s: %w", action, shortID(e.ID), err)
+		} else {
+			result.message = fmt.Sprintf("requested %s of execution %s", action, shortID(e.ID))
+		}
+		select {
+		case actionCh <- result:
+		case <-ctx.Done():
+		}
+	}()
+}
+
+func (m *topModel) fetchExecutions(ctx context.Context) ([]topExecution, error) {
+	var resp struct {
+		Executions []topExecution `json:"executions"`
+	}
+	path := fmt.Sprintf("/api/v1/%s/flows/executions?page=1&count_per_page=%d", url.PathEscape(m.namespace), topExecutionCount)
+	if err := m.client.getJSON(ctx, path, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Executions, nil
+}
+
+// startLogs switches to the log view of e and streams its logs in the background
+func (m *topModel) startLogs(ctx context.Context, e topExecution, logCh chan<- topLogEvent) {
+	m.stopLogs()
+
+	ctx, cancel := context.WithCancel(ctx)
+	m.view = topViewLogs
+	m.logExec = e
+	m.logLines = nil
+	m.logActions = nil
+	m.logAction = ""
+	m.logScroll = 0
+	m.logDone = false
+	m.logErr = nil
+	m.logCancel = cancel
+
+	path := fmt.Sprintf("/api/v1/%s/logs/%s?ansi=strip", url.PathEscape(m.namespace), url.PathEscape(e.ID))
+	go func() {
+		send := func(ev topLogEvent) bool {
+			ev.execID = e.ID
+			select {
+			case logCh <- ev:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		body, err := m.client.stream(ctx, path, "text/event-stream")
+		if err != nil {
+			send(topLogEvent{err: err, done: true})
+			return
+		}
+		defer body.Close()
+
+		err = readLogEvents(body, func(msg logMessage) error {
+			var buf bytes.Buffer
+			printLogMessage(&buf, msg)
+			var lines []topLogLine
+			for _, line := range strings.Split(strings.TrimRight(buf.String(), "\n"), "\n") {
+				if line != "" {
+					lines = append(lines, topLogLine{actionID: msg.ActionID, text: line})
+				}
+			}
+			if len(lines) > 0 && !send(topLogEvent{lines: lines}) {
+				return ctx.Err()
+			}
+			return nil
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		send(topLogEvent{err: err, done: true})
+	}()
+}
+
+func (m *topModel) stopLogs() {
+	if m.logCancel != nil {
+		m.logCancel()
+		m.logCancel = nil
+	}
+}
+
+func (m *topModel) handleLogEvent(ev topLogEvent) {
+	// Events of a log stream that was stopped can still be queued
+	if m.view != topViewLogs || ev.execID != m.logExec.ID {
+		return
+	}
+
+	for _, l := range ev.lines {
+		if l.actionID != "" && !contains(m.logActions, l.actionID) {
+			m.logActions = append(m.logActions, l.actionID)
+		}
+		m.logLines = append(m.logLines, l)
+		// Keep the view in place while scrolled up
+		if m.logScroll > 0 && (m.logAction == "" || l.actionID == m.logAction) {
+			m.logScroll++
+		}
+	}
+	if len(m.logLines) > topMaxLogLines {
+		m.logLines = m.logLines[len(m.logLines)-topMaxLogLines:]
+	}
+
+	if ev.done {
+		m.logDone = true
+		m.logErr = ev.err
+	}
+}
+
+func (m *topModel) setMessage(message string, err error) {
+	if err != nil {
+		message = err.Error()
+	}
+	m.message = message
+	m.messageAt = time.Now()
+}
+
+func (m *topModel) size() (int, int) {
+	width, height, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil || width <= 0 || height <= 0 {
+		return 80, 24
+	}
+	return width, height
+}
+
+// render redraws the whole screen. Lines are written in place and cleared to the end
+// instead of clearing the screen first, which avoids flicker.
+func (m *topModel) render() {
+	width, height := m.size()
+
+	var lines []string
+	if m.view == topViewList {
+		lines = m.renderList(width, height)
+	} else {
+		lines = m.renderLogs(width, height)
+	}
+
+	var b strings.Builder
+	b.WriteString("\x1b[H")
+	for i, line := range lines {
+		if i > 0 {
+			b.WriteString("\r\n")
+		}
+		b.WriteString(line)
+		b.WriteString("\x1b[K")
+	}
+	b.WriteString("\x1b[J")
+	os.Stdout.WriteString(b.String())
+}
+
+func (m *topModel) renderList(width, height int) []string {
+	lines := []string{
+		topStyle("1", truncate(fmt.Sprintf("flowctl top - %s - %s", m.client.server, m.namespace), width)),
+		"",
+	}
+
+	header := fmt.Sprintf("%-16s %-8s %-24s %-20s %-9s %-8s %s", "STATUS", "ID", "FLOW", "TRIGGERED BY", "DURATION", "ACTION", "STARTED")
+	lines = append(lines, topStyle("7", padRight(truncate(header, width), width)))
+
+	rows := max(height-len(lines)-2, 0)
+	// Scroll so that the selected execution is visible
+	offset := max(m.selected-rows+1, 0)
+
+	switch {
+	case m.listErr != nil:
+		lines = append(lines, topStyle("31", truncate("could not list executions: "+m.listErr.Error(), width)))
+	case len(m.executions) == 0:
+		lines = append(lines, "no executions")
+	}
+
+	for i := offset; i < len(m.executions) && i < offset+rows; i++ {
+		e := m.executions[i]
+		action := ""
+		if e.Progress != nil && e.Progress.TotalActions > 0 {
+			action = fmt.Sprintf("%d/%d", min(e.Progress.ActionIndex+1, e.Progress.TotalActions), e.Progress.TotalActions)
+		}
+		row := fmt.Sprintf("%-16s %-8s %-24s %-20s %-9s %-8s %s",
+			e.Status, shortID(e.ID), truncate(e.FlowName, 24), truncate(e.TriggeredBy, 20),
+			executionDuration(e), action, formatTimestamp(e.StartedAt))
+		row = truncate(row, width)
+
+		if i == m.selected {
+			lines = append(lines, topStyle("7", padRight(row, width)))
+			continue
+		}
+		// Only color the status column
+		status := fmt.Sprintf("%-16s", e.Status)
+		lines = append(lines, topStyle(statusColor(e.Status), status)+strings.TrimPrefix(row, status))
+	}
+
+	for len(lines) < height-1 {
+		lines = append(lines, "")
+	}
+	lines = append(lines, m.footer(width, "enter logs  c cancel  r retry  j/k move  q quit"))
+	return lines
+}
+
+func (m *topModel) renderLogs(width, height int) []string {
+	e := m.logExec
+	title := fmt.Sprintf("%s %s - %s", e.FlowName, shortID(e.ID), e.Status)
+	if d := executionDuration(e); d != "" {
+		title += " - " + d
+	}
+	action := "all actions"
+	if m.logAction != "" {
+		action = "action " + m.logAction
+	}
+
+	lines := []string{
+		topStyle("1", truncate(title, width)),
+		truncate(action, width),
+	}
+
+	var visible []string
+	for _, l := range m.logLines {
+		if m.logAction == "" || l.actionID == m.logAction {
+			visible = append(visible, truncate(l.text, width))
+		}
+	}
+	switch {
+	case m.logErr != nil:
+		visible = append(visible, topStyle("31", truncate("log stream ended: "+m.logErr.Error(), width)))
+	case m.logDone:
+		visible = append(visible, topStyle("2", "-- end of logs --"))
+	}
+
+	rows := max(height-len(lines)-1, 0)
+	m.logScroll = min(m.logScroll, max(len(visible)-rows, 0))
+	end := len(visible) - m.logScroll
+	start := max(end-rows, 0)
+	lines = append(lines, visible[start:end]...)
+
+	for len(lines) < height-1 {
+		lines = append(lines, "")
+	}
+
+	keys := "esc back  tab next action  c cancel  r retry  j/k scroll  G follow"
+	if m.logScroll > 0 {
+		keys += fmt.Sprintf("  (%d lines below)", m.logScroll)
+	}
+	lines = append(lines, m.footer(width, keys))
+	return lines
+}
+
+// footer shows the pending confirmation, the result of the last action or the key bindings
+func (m *topModel) footer(width int, keys string) string {
+	switch {
+	case m.confirm != "":
+		return topStyle("1;33", truncate(m.confirm, width))
+	case m.message != "" && time.Since(m.messageAt) < 5*time.Second:
+		return topStyle("33", truncate(m.message, width))
+	}
+	return topStyle("2", truncate(keys, width))
+}
+
+// readTopKeys reads key presses from the terminal in raw mode until it is closed
+func readTopKeys(f *os.File, keys chan<- topKeyEvent) {
+	defer close(keys)
+
+	buf := make([]byte, 64)
+	for {
+		n, err := f.Read(buf)
+		if err != nil {
+			return
+		}
+		for _, k := range parseTopKeys(buf[:n]) {
+			keys <- k
+		}
+	}
+}
+
+// parseTopKeys parses the bytes of one read from the terminal. Escape sequences
+// are assumed to arrive in a single read.
+func parseTopKeys(b []byte) []topKeyEvent {
+	var keys []topKeyEvent
+	for len(b) > 0 {
+		switch {
+		case bytes.HasPrefix(b, []byte("\x1b[A")), bytes.HasPrefix(b, []byte("\x1bOA")):
+			keys, b = append(keys, topKeyEvent{key: topKeyUp}), b[3:]
+		case bytes.HasPrefix(b, []byte("\x1b[B")), bytes.HasPrefix(b, []byte("\x1bOB")):
+			keys, b = append(keys, topKeyEvent{key: topKeyDown}), b[3:]
+		case bytes.HasPrefix(b, []byte("\x1b[5~")):
+			keys, b = append(keys, topKeyEvent{key: topKeyPageUp}), b[4:]
+		case bytes.HasPrefix(b, []byte("\x1b[6~")):
+			keys, b = append(keys, topKeyEvent{key: topKeyPageDown}), b[4:]
+		case b[0] == 0x1b && len(b) > 1 && (b[1] == '[' || b[1] == 'O'):
+			// Ignore other escape sequences
+			i := 2
+			for i < len(b) && (b[i] < 0x40 || b[i] > 0x7e) {
+				i++
+			}
+			b = b[min(i+1, len(b)):]
+		case b[0] == 0x1b:
+			keys, b = append(keys, topKeyEvent{key: topKeyEsc}), b[1:]
+		case b[0] == '\r' || b[0] == '\n':
+			keys, b = append(keys, topKeyEvent{key: topKeyEnter}), b[1:]
+		case b[0] == '\t':
+			keys, b = append(keys, topKeyEvent{key: topKeyTab}), b[1:]
+		case b[0] == 0x03:
+			keys, b = append(keys, topKeyEvent{key: topKeyCtrlC}), b[1:]
+		default:
+			r, size := utf8.DecodeRune(b)
+			keys, b = append(keys, topKeyEvent{key: topKeyRune, r: r}), b[size:]
+		}
+	}
+	return keys
+}
+
+func topStyle(code string, s string) string {
+	return "\x1b[" + code + "m" + s + "\x1b[0m"
+}
+
+func statusColor(status string) string {
+	switch status {
+	case "completed":
+		return "32"
+	case "errored":
+		return "31"
+	case "running":
+		return "36"
+	case "pending_approval":
+		return "33"
+	case "cancelled":
+		return "2"
+	}
+	return "0"
+}
+
+func executionDuration(e topExecution) string {
+	started, err := time.Parse(time.RFC3339, e.StartedAt)
+	if err != nil {
+		return ""
+	}
+	end := time.Now()
+	if completed, err := time.Parse(time.RFC3339, e.CompletedAt); err == nil {
+		end = completed
+	}
+	return end.Sub(started).Truncate(time.Second).String()
+}
+
+func formatTimestamp(ts string) string {
+	t, err := time.Parse(time.RFC3339, ts)
+	if err != nil {
+		return ts
+	}
+	return t.Local().Format("2006-01-02 15:04:05")
+}
+
+// nextAction returns the action after current, cycling through all actions and then none
+func nextAction(actions []string, current string) string {
+	if current == "" {
+		if len(actions) > 0 {
+			return actions[0]
+		}
+		return ""
+	}
+	for i, a := range actions {
+		if a == current && i+1 < len(actions) {
+			return actions[i+1]
+		}
+	}
+	return ""
+}
+
+func shortID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
+
+func truncate(s string, width int) string {
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	if width <= 0 {
+		return ""
+	}
+	runes := []rune(s)
+	return string(runes[:width-1]) + "…"
+}
+
+func padRight(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	addRemoteFlags(topCmd)
+	topCmd.Flags().StringP("namespace", "n", "default", "Namespace to watch")
+	topCmd.Flags().Duration("interval", 2*time.Second, "How often the execution list is refreshed")
+	rootCmd.AddCommand(topCmd)
+}
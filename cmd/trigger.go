@@ -0,0 +1,231 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+type triggerResp struct {
+	ExecID      string  `json:"exec_id"`
+	ScheduledAt *string `json:"scheduled_at,omitempty"`
+}
+
+type executionStatusResp struct {
+	ID          string `json:"id"`
+	Status      string `json:"status"`
+	CompletedAt string `json:"completed_at"`
+}
+
+// triggerCmd triggers a flow on a remote server
+// With --wait, the exit code is 0 only if the execution completed successfully
+var triggerCmd = &cobra.Command{
+	Use:   "trigger <flow>",
+	Short: "Trigger a flow on a remote flowctl server",
+	Example: `  flowctl trigger deploy --server https://flowctl.example.com --token $TOKEN \
+    -n production -i version=1.4.2 --file manifest=@./manifest.yaml --wait
+  flowctl trigger deploy -n production --preset "prod-eu rollout" -i version=1.4.2`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newAPIClient(cmd)
+		if err != nil {
+			return err
+		}
+
+		namespace, _ := cmd.Flags().GetString("namespace")
+		inputs, _ := cmd.Flags().GetStringArray("input")
+		files, _ := cmd.Flags().GetStringArray("file")
+		preset, _ := cmd.Flags().GetString("preset")
+		wait, _ := cmd.Flags().GetBool("wait")
+		pollInterval, _ := cmd.Flags().GetDuration("poll-interval")
+		if pollInterval <= 0 {
+			return fmt.Errorf("poll interval must be positive")
+		}
+
+		fields, err := parseKeyValues(inputs)
+		if err != nil {
+			return err
+		}
+
+		uploads, err := parseKeyValues(files)
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		execID, err := triggerFlow(ctx, client, namespace, args[0], preset, fields, uploads)
+		if err != nil {
+			return err
+		}
+		fmt.Println(execID)
+
+		if !wait {
+			return nil
+		}
+
+		status, err := waitForExecution(ctx, client, namespace, execID, pollInterval)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(os.Stderr, "execution %s %s\n", execID, status)
+		if status != "completed" {
+			os.Exit(1)
+		}
+		return nil
+	},
+}
+
+// parseKeyValues parses a list of key=value pairs
+func parseKeyValues(pairs []string) (map[string]string, error) {
+	values := make(map[string]string, len(pairs))
+	for _, p := range pairs {
+		k, v, ok := strings.Cut(p, "=")
+		if !ok || k == "" {
+			return nil, fmt.Errorf("invalid value %q, expected key=value", p)
+		}
+		values[k] = v
+	}
+	return values, nil
+}
+
+// triggerFlow triggers the flow with the given inputs and returns the exec ID.
+// Files are uploaded in a multipart form, which is streamed so that large files are not loaded into memory.
+// Inputs that are not given are taken from the preset, if one is set.
+func triggerFlow(ctx context.Context, client *apiClient, namespace, flowID, preset string, fields map[string]string, uploads map[string]string) (string, error) {
+	path := fmt.Sprintf("/api/v1/%s/trigger/%s", url.PathEscape(namespace), url.PathEscape(flowID))
+	if preset != "" {
+		path += "?" + url.Values{"preset": {preset}}.Encode()
+	}
+
+	var (
+		body        io.Reader
+		contentType string
+	)
+	if len(uploads) == 0 {
+		form := url.Values{}
+		for k, v := range fields {
+			form.Set(k, v)
+		}
+		body = strings.NewReader(form.Encode())
+		contentType = "application/x-www-form-urlencoded"
+	} else {
+		// Check the files up front so that a missing file doesn't surface as a failed request
+		for name, p := range uploads {
+			p = strings.TrimPrefix(p, "@")
+			if _, err := os.Stat(p); err != nil {
+				return "", fmt.Errorf("could not read file for input %s: %w", name, err)
+			}
+			uploads[name] = p
+		}
+
+		pr, pw := io.Pipe()
+		mw := multipart.NewWriter(pw)
+		go func() {
+			pw.CloseWithError(writeMultipartForm(mw, fields, uploads))
+		}()
+		body = pr
+		contentType = mw.FormDataContentType()
+	}
+
+	var resp triggerResp
+	respBody, err := client.do(ctx, http.MethodPost, path, body, contentType)
+	if err != nil {
+		return "", fmt.Errorf("could not trigger flow: %w", err)
+	}
+	if err := decodeJSON(respBody, &resp); err != nil {
+		return "", err
+	}
+
+	if resp.ScheduledAt != nil {
+		fmt.Fprintf(os.Stderr, "execution scheduled at %s\n", *resp.ScheduledAt)
+	}
+
+	return resp.ExecID, nil
+}
+
+func writeMultipartForm(mw *multipart.Writer, fields map[string]string, uploads map[string]string) error {
+	for k, v := range fields {
+		if err := mw.WriteField(k, v); err != nil {
+			return err
+		}
+	}
+
+	for name, p := range uploads {
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+
+		part, err := mw.CreateFormFile(name, filepath.Base(p))
+		if err != nil {
+			f.Close()
+			return err
+		}
+
+		_, err = io.Copy(part, f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return mw.Close()
+}
+
+// waitForExecution polls the execution until it finishes and returns its final status
+func waitForExecution(ctx context.Context, client *apiClient, namespace, execID string, interval time.Duration) (string, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	path := fmt.Sprintf("/api/v1/%s/flows/executions/%s", url.PathEscape(namespace), url.PathEscape(execID))
+
+	lastStatus := ""
+	for {
+		var exec executionStatusResp
+		if err := client.getJSON(ctx, path, &exec); err != nil {
+			return "", fmt.Errorf("could not get execution status: %w", err)
+		}
+
+		if exec.Status != lastStatus {
+			fmt.Fprintf(os.Stderr, "status: %s\n", exec.Status)
+			lastStatus = exec.Status
+		}
+
+		switch exec.Status {
+		case "completed", "errored", "cancelled":
+			return exec.Status, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func init() {
+	addRemoteFlags(triggerCmd)
+	triggerCmd.Flags().StringP("namespace", "n", "default", "Namespace of the flow")
+	triggerCmd.Flags().StringArrayP("input", "i", nil, "Flow input as key=value, can be repeated")
+	triggerCmd.Flags().StringArray("file", nil, "File input as input=@path, can be repeated")
+	triggerCmd.Flags().String("preset", "", "Name of an input preset of the flow to take inputs that are not given from")
+	triggerCmd.Flags().Bool("wait", false, "Wait for the execution to finish and exit with a non-zero code if it did not complete successfully")
+	triggerCmd.Flags().Duration("poll-interval", 2*time.Second, "How often to check the execution status with --wait")
+	rootCmd.AddCommand(triggerCmd)
+}
@@ -0,0 +1,274 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/cvhariharan/flowctl/internal/core"
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/spf13/cobra"
+)
+
+// cliUser is a user as printed by the user commands
+type cliUser struct {
+	ID        string `json:"id"`
+	Username  string `json:"username"`
+	Name      string `json:"name"`
+	LoginType string `json:"login_type"`
+	Role      string `json:"role"`
+	Groups    []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"groups"`
+}
+
+func coreUserToCLIUser(u models.UserWithGroups) cliUser {
+	user := cliUser{
+		ID:        u.ID,
+		Username:  u.Username,
+		Name:      u.Name,
+		LoginType: string(u.LoginType),
+		Role:      string(u.Role),
+	}
+	for _, g := range u.Groups {
+		user.Groups = append(user.Groups, struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		}{ID: g.ID, Name: g.Name})
+	}
+	return user
+}
+
+// userCmd groups the user management commands. They use the API of a remote server if --server
+// is set and the database in the config file otherwise, e.g. to create the first superuser.
+var userCmd = &cobra.Command{
+	Use:   "user",
+	Short: "Manage users",
+}
+
+var userCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a user",
+	Example: `  flowctl user create --name "Jane Doe" --username jane@example.com --group developers
+  echo "$ADMIN_PASSWORD" | flowctl user create --name Ops --username ops@example.com --role superuser --password-stdin`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, _ := cmd.Flags().GetString("name")
+		username, _ := cmd.Flags().GetString("username")
+		role, _ := cmd.Flags().GetString("role")
+		groups, _ := cmd.Flags().GetStringArray("group")
+		passwordStdin, _ := cmd.Flags().GetBool("password-stdin")
+
+		if name == "" || username == "" {
+			return errors.New("--name and --username are required")
+		}
+		if role != string(models.StandardUserRole) && role != string(models.SuperuserUserRole) {
+			return fmt.Errorf("invalid role %q, expected user or superuser", role)
+		}
+
+		ctx := context.Background()
+
+		if useAPI(cmd) {
+			// Users created through the API always log in with OIDC and have the user role
+			if role != string(models.StandardUserRole) || passwordStdin {
+				return errors.New("--role and --password-stdin can only be used without --server")
+			}
+
+			client, err := newAPIClient(cmd)
+			if err != nil {
+				return err
+			}
+
+			groupIDs, err := resolveGroupsAPI(ctx, client, groups)
+			if err != nil {
+				return err
+			}
+
+			var user cliUser
+			if err := client.sendJSON(ctx, http.MethodPost, "/api/v1/users", map[string]any{
+				"name":     name,
+				"username": username,
+				"groups":   groupIDs,
+			}, &user); err != nil {
+				return fmt.Errorf("could not create user: %w", err)
+			}
+
+			fmt.Println(user.ID)
+			return nil
+		}
+
+		loginType := models.OIDCLoginType
+		var password string
+		if passwordStdin {
+			var err error
+			if password, err = readPassword(os.Stdin); err != nil {
+				return err
+			}
+			loginType = models.StandardLoginType
+		}
+
+		co, closeDB, err := openAdminCore(cmd)
+		if err != nil {
+			return err
+		}
+		defer closeDB()
+
+		groupIDs, err := resolveGroupsDB(ctx, co, groups)
+		if err != nil {
+			return err
+		}
+
+		user, err := co.CreateUser(ctx, name, username, loginType, models.UserRoleType(role), groupIDs)
+		if err != nil {
+			return fmt.Errorf("could not create user: %w", err)
+		}
+
+		if passwordStdin {
+			if err := co.SetUserPassword(ctx, username, password); err != nil {
+				return err
+			}
+		}
+
+		fmt.Println(user.ID)
+		return nil
+	},
+}
+
+var userListCmd = &cobra.Command{
+	Use:          "list",
+	Short:        "List users",
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		var users []cliUser
+		if useAPI(cmd) {
+			client, err := newAPIClient(cmd)
+			if err != nil {
+				return err
+			}
+			if err := listAll(ctx, client, "/api/v1/users", "users", &users); err != nil {
+				return fmt.Errorf("could not list users: %w", err)
+			}
+		} else {
+			co, closeDB, err := openAdminCore(cmd)
+			if err != nil {
+				return err
+			}
+			defer closeDB()
+
+			for offset := 0; ; offset += adminPageSize {
+				page, _, _, err := co.SearchUser(ctx, "", adminPageSize, offset)
+				if err != nil {
+					return fmt.Errorf("could not list users: %w", err)
+				}
+				for _, u := range page {
+					users = append(users, coreUserToCLIUser(u))
+				}
+				if len(page) < adminPageSize {
+					break
+				}
+			}
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tUSERNAME\tNAME\tROLE\tLOGIN\tGROUPS")
+		for _, u := range users {
+			groups := make([]string, 0, len(u.Groups))
+			for _, g := range u.Groups {
+				groups = append(groups, g.Name)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", u.ID, u.Username, u.Name, u.Role, u.LoginType, strings.Join(groups, ","))
+		}
+		return w.Flush()
+	},
+}
+
+var userResetPasswordCmd = &cobra.Command{
+	Use:          "reset-password <username>",
+	Short:        "Set the password of a user that logs in with a password, read from stdin",
+	Example:      `  echo "$NEW_PASSWORD" | flowctl user reset-password flowctl_admin`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Passwords can't be changed through the API
+		if useAPI(cmd) {
+			return errors.New("reset-password uses the database directly and cannot be used with --server")
+		}
+
+		password, err := readPassword(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		co, closeDB, err := openAdminCore(cmd)
+		if err != nil {
+			return err
+		}
+		defer closeDB()
+
+		return co.SetUserPassword(context.Background(), args[0], password)
+	},
+}
+
+// resolveGroupsAPI returns the IDs of the groups with the given names using the API
+func resolveGroupsAPI(ctx context.Context, client *apiClient, names []string) ([]string, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	var groups []cliGroup
+	if err := listAll(ctx, client, "/api/v1/groups", "groups", &groups); err != nil {
+		return nil, fmt.Errorf("could not list groups: %w", err)
+	}
+
+	ids := make(map[string]string, len(groups))
+	for _, g := range groups {
+		ids[g.Name] = g.ID
+	}
+
+	var res []string
+	for _, name := range names {
+		id, ok := ids[name]
+		if !ok {
+			return nil, fmt.Errorf("group %s does not exist", name)
+		}
+		res = append(res, id)
+	}
+	return res, nil
+}
+
+// resolveGroupsDB returns the IDs of the groups with the given names using the database
+func resolveGroupsDB(ctx context.Context, co *core.Core, names []string) ([]string, error) {
+	var res []string
+	for _, name := range names {
+		g, err := co.GetGroupByName(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("group %s does not exist: %w", name, err)
+		}
+		res = append(res, g.ID)
+	}
+	return res, nil
+}
+
+func init() {
+	for _, c := range []*cobra.Command{userCreateCmd, userListCmd, userResetPasswordCmd} {
+		addAdminFlags(c)
+		userCmd.AddCommand(c)
+	}
+
+	userCreateCmd.Flags().String("name", "", "Full name of the user")
+	userCreateCmd.Flags().String("username", "", "Email address of the user")
+	userCreateCmd.Flags().String("role", "user", "Role of the user: user or superuser")
+	userCreateCmd.Flags().StringArray("group", nil, "Name of a group to add the user to, can be repeated")
+	userCreateCmd.Flags().Bool("password-stdin", false, "Read a password from stdin so that the user can log in with a password instead of OIDC")
+
+	rootCmd.AddCommand(userCmd)
+}
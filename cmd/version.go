@@ -1,7 +1,15 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -12,16 +20,187 @@ var (
 	date    = ""
 )
 
+// defaultReleaseFeed is the GitHub API endpoint of the latest flowctl release
+const defaultReleaseFeed = "https://api.github.com/repos/cvhariharan/flowctl/releases/latest"
+
+// buildInfo describes the running binary
+type buildInfo struct {
+	Version   string
+	Commit    string
+	Date      string
+	GoVersion string
+	Platform  string
+}
+
+// release is the latest release as returned by the release feed
+type release struct {
+	TagName string `json:"tag_name"`
+	HTMLURL string `json:"html_url"`
+}
+
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Print version information",
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Printf("Version: %s\n", version)
-		fmt.Printf("Commit: %s\n", commit)
-		fmt.Printf("Date: %s\n", date)
+	Long: `Print version information. With --check, the release feed is queried for a newer version
+and the command exits with a non-zero status if the check fails.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		info := getBuildInfo()
+		fmt.Printf("Version: %s\n", info.Version)
+		fmt.Printf("Commit: %s\n", info.Commit)
+		fmt.Printf("Date: %s\n", info.Date)
+		fmt.Printf("Go: %s\n", info.GoVersion)
+		fmt.Printf("Platform: %s\n", info.Platform)
+
+		check, _ := cmd.Flags().GetBool("check")
+		if !check {
+			return nil
+		}
+
+		feed, _ := cmd.Flags().GetString("feed")
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		latest, err := fetchLatestRelease(ctx, feed)
+		if err != nil {
+			return fmt.Errorf("could not check for updates: %w", err)
+		}
+
+		fmt.Println()
+		if compareVersions(latest.TagName, info.Version) > 0 {
+			fmt.Printf("A newer version is available: %s\n", latest.TagName)
+			if latest.HTMLURL != "" {
+				fmt.Printf("Download it from %s\n", latest.HTMLURL)
+			}
+			fmt.Println("Back up the database before upgrading and run flowctl migrate up after.")
+		} else {
+			fmt.Printf("flowctl is up to date, the latest release is %s\n", latest.TagName)
+		}
+		return nil
 	},
 }
 
+// getBuildInfo returns the version information set at build time. Builds without ldflags,
+// e.g. with go install, fall back to the module version and VCS information embedded by Go.
+func getBuildInfo() buildInfo {
+	info := buildInfo{
+		Version:   version,
+		Commit:    commit,
+		Date:      date,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+
+	if info.Version == "dev" && bi.Main.Version != "" && bi.Main.Version != "(devel)" {
+		info.Version = bi.Main.Version
+	}
+	for _, s := range bi.Settings {
+		switch {
+		case s.Key == "vcs.revision" && info.Commit == "":
+			info.Commit = s.Value
+			if len(info.Commit) > 7 {
+				info.Commit = info.Commit[:7]
+			}
+		case s.Key == "vcs.time" && info.Date == "":
+			info.Date = s.Value
+		case s.Key == "vcs.modified" && s.Value == "true" && info.Commit != "" && !strings.HasSuffix(info.Commit, "-dirty"):
+			info.Commit += "-dirty"
+		}
+	}
+	return info
+}
+
+func fetchLatestRelease(ctx context.Context, feed string) (release, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feed, nil)
+	if err != nil {
+		return release{}, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "flowctl/"+version)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return release{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return release{}, fmt.Errorf("release feed returned %s", resp.Status)
+	}
+
+	var r release
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return release{}, fmt.Errorf("could not decode release feed: %w", err)
+	}
+	if r.TagName == "" {
+		return release{}, fmt.Errorf("release feed has no tag name")
+	}
+	return r, nil
+}
+
+// compareVersions compares two versions of the form v1.2.3[-pre]. Versions that can't be
+// parsed, like dev builds, are older than any release. A pre-release is older than
+// the release with the same version.
+func compareVersions(a, b string) int {
+	pa, prea, oka := parseVersion(a)
+	pb, preb, okb := parseVersion(b)
+	switch {
+	case !oka && !okb:
+		return 0
+	case !oka:
+		return -1
+	case !okb:
+		return 1
+	}
+
+	for i := range pa {
+		if pa[i] != pb[i] {
+			if pa[i] > pb[i] {
+				return 1
+			}
+			return -1
+		}
+	}
+
+	switch {
+	case prea == preb:
+		return 0
+	case prea == "":
+		return 1
+	case preb == "":
+		return -1
+	}
+	return strings.Compare(prea, preb)
+}
+
+func parseVersion(v string) ([3]int, string, bool) {
+	var parts [3]int
+
+	v = strings.TrimPrefix(v, "v")
+	v, _, _ = strings.Cut(v, "+")
+	v, pre, _ := strings.Cut(v, "-")
+
+	fields := strings.Split(v, ".")
+	if len(fields) != 3 {
+		return parts, "", false
+	}
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil {
+			return parts, "", false
+		}
+		parts[i] = n
+	}
+	return parts, pre, true
+}
+
 func init() {
+	versionCmd.Flags().Bool("check", false, "Check the release feed for a newer version")
+	versionCmd.Flags().String("feed", defaultReleaseFeed, "URL of the release feed, in the format of the GitHub latest release API")
 	rootCmd.AddCommand(versionCmd)
 }
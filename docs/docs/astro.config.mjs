@@ -34,6 +34,7 @@ export default defineConfig({
               slug: "general/nodes-and-executors",
             },
             { label: "Access Control", slug: "general/access-control" },
+            { label: "CLI", slug: "general/cli" },
           ],
         },
         {
@@ -41,6 +41,7 @@ type DockerWithConfig struct {
 type DockerExecutor struct {
 	name             string
 	image            string
+	imageDigest      string
 	env              []string
 	script           string
 	entrypoint       []string
@@ -66,6 +67,11 @@ type DockerRunnerOptions struct {
 func NewDockerExecutor(name string, node executor.Node, execID string) (executor.Executor, error) {
 	jobName := slug.Make(fmt.Sprintf("%s-%s", name, xid.New().String()))
 
+	// The executor talks to the docker daemon over its unix socket
+	if node.OSFamily == executor.OSFamilyWindows {
+		return nil, fmt.Errorf("docker executor does not support windows nodes")
+	}
+
 	driver, err := executor.NewNodeDriver(context.Background(), node)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create node driver: %w", err)
@@ -279,6 +285,13 @@ func (d *DockerExecutor) run(ctx context.Context) error {
 		return fmt.Errorf("could not pull image: %w", err)
 	}
 
+	// The digest pins the image that was pulled for tags that move
+	if inspect, err := d.client.ImageInspect(ctx, d.image); err != nil {
+		log.Printf("could not inspect image %s: %v", d.image, err)
+	} else {
+		d.imageDigest = imageDigest(inspect)
+	}
+
 	resp, err := d.createContainer(ctx, d.client)
 	if err != nil {
 		return fmt.Errorf("unable to create container: %w", err)
@@ -364,6 +377,25 @@ func (d *DockerExecutor) pullImage(ctx context.Context, cli *client.Client) erro
 	return nil
 }
 
+// Environment reports the image the container ran and its digest
+func (d *DockerExecutor) Environment() map[string]string {
+	return map[string]string{
+		executor.EnvironmentImage:       d.image,
+		executor.EnvironmentImageDigest: d.imageDigest,
+	}
+}
+
+// imageDigest returns the repository digest of an image, or its ID for images that were built
+// locally and were never pushed to or pulled from a registry
+func imageDigest(inspect image.InspectResponse) string {
+	for _, repoDigest := range inspect.RepoDigests {
+		if _, digest, ok := strings.Cut(repoDigest, "@"); ok {
+			return digest
+		}
+	}
+	return inspect.ID
+}
+
 func (d *DockerExecutor) createContainer(ctx context.Context, cli *client.Client) (container.CreateResponse, error) {
 	interpreter := d.interpreter
 	if interpreter == "" {
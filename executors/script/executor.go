@@ -1,11 +1,13 @@
 package script
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
 	"os"
 	"strings"
+	"unicode/utf16"
 
 	"github.com/cvhariharan/flowctl/sdk/executor"
 	"github.com/hashicorp/go-envparse"
@@ -16,8 +18,8 @@ import (
 
 type ScriptWithConfig struct {
 	Script      string `yaml:"script" json:"script" jsonschema:"title=script" jsonschema_extras:"widget=codeeditor"`
-	Interpreter string `yaml:"interpreter,omitempty" json:"interpreter,omitempty" jsonschema:"title=interpreter,description=Shell interpreter to use (default: /bin/bash)" jsonschema_extras:"placeholder=/bin/bash"`
-	Extension   string `yaml:"extension,omitempty" json:"extension,omitempty" jsonschema:"title=extension,description=File extension for the script (default: .sh)" jsonschema_extras:"placeholder=.sh`
+	Interpreter string `yaml:"interpreter,omitempty" json:"interpreter,omitempty" jsonschema:"title=interpreter,description=Shell interpreter to use (default: /bin/bash or powershell on Windows nodes)" jsonschema_extras:"placeholder=/bin/bash"`
+	Extension   string `yaml:"extension,omitempty" json:"extension,omitempty" jsonschema:"title=extension,description=File extension for the script (default: .sh or .ps1 on Windows nodes)" jsonschema_extras:"placeholder=.sh"`
 }
 
 type ScriptExecutor struct {
@@ -28,6 +30,7 @@ type ScriptExecutor struct {
 	driver           executor.NodeDriver
 	artifactsDir     string
 	execID           string
+	windows          bool
 }
 
 func GetSchema() interface{} {
@@ -55,6 +58,7 @@ func NewScriptExecutor(name string, node executor.Node, execID string) (executor
 		driver:           driver,
 		artifactsDir:     artifactsDir,
 		execID:           execID,
+		windows:          node.OSFamily == executor.OSFamilyWindows,
 	}
 
 	return exec, nil
@@ -81,6 +85,9 @@ func (s *ScriptExecutor) Execute(ctx context.Context, execCtx executor.Execution
 	// Set default interpreter
 	if config.Interpreter == "" {
 		config.Interpreter = "/bin/bash"
+		if s.windows {
+			config.Interpreter = "powershell"
+		}
 	}
 
 	s.stdout = execCtx.Stdout
@@ -134,14 +141,23 @@ func (s *ScriptExecutor) runScript(ctx context.Context, config ScriptWithConfig,
 	// Normalize extension (add dot if not present)
 	if config.Extension == "" {
 		config.Extension = ".sh"
+		if s.windows {
+			config.Extension = windowsExtension(config.Interpreter)
+		}
 	}
 	ext := config.Extension
 	if !strings.HasPrefix(ext, ".") {
 		ext = "." + ext
 	}
 
+	script := config.Script
+	if s.windows {
+		// Batch files need CRLF line endings for labels and multi-line blocks to work
+		script = strings.ReplaceAll(strings.ReplaceAll(script, "\r\n", "\n"), "\n", "\r\n")
+	}
+
 	localScriptFile := fmt.Sprintf("/tmp/local-script-%s%s", xid.New().String(), ext)
-	if err := os.WriteFile(localScriptFile, []byte(config.Script), 0755); err != nil {
+	if err := os.WriteFile(localScriptFile, []byte(script), 0755); err != nil {
 		return fmt.Errorf("failed to write local script file: %w", err)
 	}
 	defer os.Remove(localScriptFile)
@@ -157,9 +173,40 @@ func (s *ScriptExecutor) runScript(ctx context.Context, config ScriptWithConfig,
 	}
 
 	command := fmt.Sprintf("%s %s", config.Interpreter, remoteScriptFile)
+	if s.windows {
+		command = windowsCommand(config.Interpreter, remoteScriptFile)
+	}
 	return s.driver.Exec(ctx, command, s.workingDirectory, env, s.stdout, s.stderr)
 }
 
+// windowsInterpreter returns the name of the interpreter without its directory and .exe suffix
+func windowsInterpreter(interpreter string) string {
+	name := interpreter[strings.LastIndexAny(interpreter, `\/`)+1:]
+	return strings.TrimSuffix(strings.ToLower(name), ".exe")
+}
+
+// windowsExtension returns the default script extension for the interpreter on Windows nodes
+func windowsExtension(interpreter string) string {
+	if windowsInterpreter(interpreter) == "cmd" {
+		return ".cmd"
+	}
+	return ".ps1"
+}
+
+// windowsCommand returns the PowerShell command that runs the script with the interpreter.
+// PowerShell scripts are run with the execution policy bypassed, since they are not signed.
+func windowsCommand(interpreter, scriptFile string) string {
+	quote := func(s string) string { return "'" + strings.ReplaceAll(s, "'", "''") + "'" }
+
+	switch windowsInterpreter(interpreter) {
+	case "powershell", "pwsh":
+		return fmt.Sprintf("& %s -NoProfile -NonInteractive -ExecutionPolicy Bypass -File %s", quote(interpreter), quote(scriptFile))
+	case "cmd":
+		return fmt.Sprintf("& %s /c %s", quote(interpreter), quote(scriptFile))
+	}
+	return fmt.Sprintf("& %s %s", quote(interpreter), quote(scriptFile))
+}
+
 func (s *ScriptExecutor) readTempFileContents(ctx context.Context, tempFile string) (io.Reader, error) {
 	localTempFile, err := os.CreateTemp("/tmp", "script-executor-output-*")
 	if err != nil {
@@ -176,9 +223,29 @@ func (s *ScriptExecutor) readTempFileContents(ctx context.Context, tempFile stri
 	if err != nil {
 		return nil, fmt.Errorf("failed to read temp file %s: %w", localTempFile.Name(), err)
 	}
+	if s.windows {
+		content = normalizeWindowsOutput(content)
+	}
 	return strings.NewReader(string(content)), nil
 }
 
+// normalizeWindowsOutput converts the output file written on a Windows node to UTF-8 with LF line
+// endings. Windows PowerShell writes redirected output as UTF-16 and other tools may add a BOM.
+func normalizeWindowsOutput(content []byte) []byte {
+	switch {
+	case bytes.HasPrefix(content, []byte{0xFF, 0xFE}):
+		content = content[2:]
+		units := make([]uint16, len(content)/2)
+		for i := range units {
+			units[i] = uint16(content[2*i]) | uint16(content[2*i+1])<<8
+		}
+		content = []byte(string(utf16.Decode(units)))
+	case bytes.HasPrefix(content, []byte{0xEF, 0xBB, 0xBF}):
+		content = content[3:]
+	}
+	return bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n"))
+}
+
 // ScriptExecutorPlugin implements executor.ExecutorPlugin for the script executor.
 type ScriptExecutorPlugin struct{}
 
@@ -0,0 +1,161 @@
+package artifactstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"gocloud.dev/blob"
+	"gocloud.dev/gcerrors"
+)
+
+// ErrNoSignedURL is returned by SignedURL when the archive can't sign URLs, like archives in a
+// local directory. The file is read with NewReader instead.
+var ErrNoSignedURL = errors.New("archive can't sign URLs")
+
+// Archive keeps the files that actions publish from the artifact directory after their executions
+// finished, so that they can be downloaded. Files are keyed by the execution, the action and their
+// path in the artifact directory.
+type Archive struct {
+	bucket *blob.Bucket
+}
+
+// OpenArchive opens the archive at location, a gocloud blob URL such as s3://bucket or
+// gs://bucket, or a local directory which is created if it doesn't exist
+func OpenArchive(ctx context.Context, location string) (*Archive, error) {
+	bucket, err := OpenBucket(ctx, location)
+	if err != nil {
+		return nil, fmt.Errorf("could not open artifacts archive: %w", err)
+	}
+	return &Archive{bucket: bucket}, nil
+}
+
+// ArchiveKey returns the key of a file published by an action of an execution. name is the
+// slash separated path of the file in the artifact directory.
+func ArchiveKey(execID, actionID, name string) string {
+	return path.Join(execID, actionID, name)
+}
+
+// Put uploads the file at p under key and returns its size. The file is downloaded as an
+// attachment named after it from signed URLs.
+func (a *Archive) Put(ctx context.Context, key string, p string) (int64, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	// Cancelling ctx before closing the writer discards a partial upload
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	w, err := a.bucket.NewWriter(ctx, key, &blob.WriterOptions{
+		ContentDisposition: mime.FormatMediaType("attachment", map[string]string{"filename": path.Base(key)}),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("could not archive %s: %w", key, err)
+	}
+	n, err := io.Copy(w, f)
+	if err != nil {
+		cancel()
+		w.Close()
+		return n, fmt.Errorf("could not archive %s: %w", key, err)
+	}
+	if err := w.Close(); err != nil {
+		return n, fmt.Errorf("could not archive %s: %w", key, err)
+	}
+	return n, nil
+}
+
+// NewReader returns a reader of the file archived under key
+func (a *Archive) NewReader(ctx context.Context, key string) (io.ReadCloser, error) {
+	r, err := a.bucket.NewReader(ctx, key, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not read artifact %s: %w", key, err)
+	}
+	return r, nil
+}
+
+// SignedURL returns a URL the file archived under key can be downloaded from until expiry
+// without credentials
+func (a *Archive) SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	u, err := a.bucket.SignedURL(ctx, key, &blob.SignedURLOptions{Expiry: expiry})
+	if gcerrors.Code(err) == gcerrors.Unimplemented {
+		return "", ErrNoSignedURL
+	}
+	if err != nil {
+		return "", fmt.Errorf("could not sign URL of artifact %s: %w", key, err)
+	}
+	return u, nil
+}
+
+// DeleteExecution removes the files archived for an execution
+func (a *Archive) DeleteExecution(ctx context.Context, execID string) error {
+	if err := checkExecID(execID); err != nil {
+		return err
+	}
+
+	iter := a.bucket.List(&blob.ListOptions{Prefix: execID + "/"})
+	for {
+		obj, err := iter.Next(ctx)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("could not list archived artifacts of execution %s: %w", execID, err)
+		}
+		if obj.IsDir {
+			continue
+		}
+
+		if err := a.bucket.Delete(ctx, obj.Key); err != nil && gcerrors.Code(err) != gcerrors.NotFound {
+			return fmt.Errorf("could not delete artifact %s: %w", obj.Key, err)
+		}
+	}
+}
+
+func (a *Archive) Close() error {
+	return a.bucket.Close()
+}
+
+// Match returns the regular files in dir whose slash separated path relative to dir matches one
+// of patterns, in lexical order. Patterns use the syntax of path.Match, so * doesn't match
+// across directories.
+func Match(dir string, patterns []string) ([]string, error) {
+	var names []string
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		for _, pattern := range patterns {
+			ok, err := path.Match(pattern, name)
+			if err != nil {
+				return fmt.Errorf("invalid artifact pattern %q: %w", pattern, err)
+			}
+			if ok {
+				names = append(names, name)
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return names, nil
+}
@@ -0,0 +1,87 @@
+package artifactstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"report.xml", "build/app.tar.gz", "build/app.log", "local/coverage.out"} {
+		writeFile(t, filepath.Join(dir, name), name)
+	}
+
+	tests := []struct {
+		patterns []string
+		want     []string
+	}{
+		{[]string{"*.xml"}, []string{"report.xml"}},
+		{[]string{"build/*.tar.gz", "*/coverage.out"}, []string{"build/app.tar.gz", "local/coverage.out"}},
+		// * doesn't match across directories
+		{[]string{"*.log"}, nil},
+		{[]string{"build/*", "build/app.log"}, []string{"build/app.log", "build/app.tar.gz"}},
+	}
+	for _, tt := range tests {
+		got, err := Match(dir, tt.patterns)
+		if err != nil {
+			t.Fatalf("Match(%v) error = %v", tt.patterns, err)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("Match(%v) = %v, want %v", tt.patterns, got, tt.want)
+		}
+	}
+
+	if _, err := Match(dir, []string{"[report"}); err == nil {
+		t.Error("Match() with an invalid pattern succeeded")
+	}
+}
+
+func TestArchive(t *testing.T) {
+	ctx := context.Background()
+	a, err := OpenArchive(ctx, filepath.Join(t.TempDir(), "archive"))
+	if err != nil {
+		t.Fatalf("OpenArchive() error = %v", err)
+	}
+	defer a.Close()
+
+	src := filepath.Join(t.TempDir(), "report.xml")
+	writeFile(t, src, "<report/>")
+
+	key := ArchiveKey("exec-1", "test", "reports/report.xml")
+	if key != "exec-1/test/reports/report.xml" {
+		t.Errorf("ArchiveKey() = %q", key)
+	}
+	size, err := a.Put(ctx, key, src)
+	if err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if size != int64(len("<report/>")) {
+		t.Errorf("Put() size = %d", size)
+	}
+
+	// Files in a local directory are read by the server instead of a signed URL
+	if _, err := a.SignedURL(ctx, key, 0); !errors.Is(err, ErrNoSignedURL) {
+		t.Errorf("SignedURL() error = %v, want ErrNoSignedURL", err)
+	}
+
+	r, err := a.NewReader(ctx, key)
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+	got, err := io.ReadAll(r)
+	r.Close()
+	if err != nil || string(got) != "<report/>" {
+		t.Errorf("NewReader() read %q, %v", got, err)
+	}
+
+	if err := a.DeleteExecution(ctx, "exec-1"); err != nil {
+		t.Fatalf("DeleteExecution() error = %v", err)
+	}
+	if _, err := a.NewReader(ctx, key); err == nil {
+		t.Error("artifact can still be read after DeleteExecution()")
+	}
+}
@@ -0,0 +1,178 @@
+// Package artifactstore keeps the artifacts of flow executions. Actions always read and write
+// artifacts in a local directory. When artifacts are stored in a bucket, the local directory is
+// a working copy that is restored from the bucket before an execution runs and saved back when
+// it stops, so an execution that is paused for an approval or retried can continue on any worker.
+package artifactstore
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gocloud.dev/blob"
+	"gocloud.dev/blob/fileblob"
+)
+
+// Store is where the artifacts of executions are kept
+type Store interface {
+	// Dir is the local directory the artifacts of an execution are read from and written to
+	Dir(execID string) string
+
+	// Restore creates Dir for an execution with the artifacts stored for it
+	Restore(ctx context.Context, execID string) error
+
+	// Save stores the contents of Dir for an execution. Stored artifacts that no longer exist
+	// locally are removed.
+	Save(ctx context.Context, execID string) error
+
+	// Delete removes the stored and the local artifacts of an execution
+	Delete(ctx context.Context, execID string) error
+
+	// List returns the executions that have stored or local artifacts
+	List(ctx context.Context) ([]Artifacts, error)
+
+	Close() error
+}
+
+// Artifacts describes the artifacts kept for an execution
+type Artifacts struct {
+	ExecID string
+	// Size is the total size of the files in bytes
+	Size int64
+	// ModTime is when a file was last written
+	ModTime time.Time
+}
+
+// IsBucketURL reports whether location is a gocloud blob URL (s3://, gs://, ...) instead of
+// a local directory
+func IsBucketURL(location string) bool {
+	return strings.Contains(location, "://")
+}
+
+// OpenBucket opens the bucket at location, a gocloud blob URL such as s3://bucket or
+// gs://bucket, or a local directory which is created if it doesn't exist
+func OpenBucket(ctx context.Context, location string) (*blob.Bucket, error) {
+	if IsBucketURL(location) {
+		return blob.OpenBucket(ctx, location)
+	}
+
+	if err := os.MkdirAll(location, 0700); err != nil {
+		return nil, err
+	}
+	return fileblob.OpenBucket(location, &fileblob.Options{CreateDir: true, NoTempDir: true})
+}
+
+// Open returns the store for location. Artifacts stored in a bucket are worked on in cacheDir,
+// any other location is used as the local artifacts directory. An empty location keeps the
+// artifacts in the system temp directory.
+func Open(ctx context.Context, location string, cacheDir string) (Store, error) {
+	if location == "" {
+		return NewLocalStore(os.TempDir()), nil
+	}
+	if !IsBucketURL(location) {
+		if err := os.MkdirAll(location, 0700); err != nil {
+			return nil, fmt.Errorf("could not create artifacts directory: %w", err)
+		}
+		return NewLocalStore(location), nil
+	}
+
+	if cacheDir == "" {
+		cacheDir = os.TempDir()
+	}
+	return NewBucketStore(ctx, location, cacheDir)
+}
+
+const execDirPrefix = "artifacts-store-"
+
+// execDir returns the directory for the artifacts of an execution in dir
+func execDir(dir string, execID string) string {
+	return filepath.Join(dir, execDirPrefix+execID)
+}
+
+// listLocal returns the artifact directories of executions in dir
+func listLocal(dir string) ([]Artifacts, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("could not read artifacts directory: %w", err)
+	}
+
+	var artifacts []Artifacts
+	for _, entry := range entries {
+		execID, ok := strings.CutPrefix(entry.Name(), execDirPrefix)
+		if !ok || !entry.IsDir() {
+			continue
+		}
+
+		a := Artifacts{ExecID: execID}
+		if info, err := entry.Info(); err == nil {
+			a.ModTime = info.ModTime()
+		}
+		err := filepath.WalkDir(filepath.Join(dir, entry.Name()), func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			if info.ModTime().After(a.ModTime) {
+				a.ModTime = info.ModTime()
+			}
+			if d.Type().IsRegular() {
+				a.Size += info.Size()
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("could not read artifacts of execution %s: %w", execID, err)
+		}
+		artifacts = append(artifacts, a)
+	}
+
+	return artifacts, nil
+}
+
+// LocalStore keeps artifacts in a directory on the local filesystem. A directory on a shared
+// filesystem such as NFS can be used to share the artifacts between instances.
+type LocalStore struct {
+	dir string
+}
+
+func NewLocalStore(dir string) *LocalStore {
+	return &LocalStore{dir: dir}
+}
+
+func (l *LocalStore) Dir(execID string) string {
+	return execDir(l.dir, execID)
+}
+
+func (l *LocalStore) Restore(ctx context.Context, execID string) error {
+	if err := os.MkdirAll(l.Dir(execID), 0700); err != nil {
+		return fmt.Errorf("failed to create artifact directory: %w", err)
+	}
+	return nil
+}
+
+// Save is a no-op since the local directory is the store
+func (l *LocalStore) Save(ctx context.Context, execID string) error {
+	return nil
+}
+
+func (l *LocalStore) Delete(ctx context.Context, execID string) error {
+	if err := os.RemoveAll(l.Dir(execID)); err != nil {
+		return fmt.Errorf("could not delete artifacts of execution %s: %w", execID, err)
+	}
+	return nil
+}
+
+func (l *LocalStore) List(ctx context.Context) ([]Artifacts, error) {
+	return listLocal(l.dir)
+}
+
+func (l *LocalStore) Close() error {
+	return nil
+}
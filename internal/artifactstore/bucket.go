@@ -0,0 +1,268 @@
+package artifactstore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gocloud.dev/blob"
+	"gocloud.dev/gcerrors"
+)
+
+// BucketStore keeps artifacts in object storage with a local working copy in a cache directory.
+// Object keys are the execution ID followed by the path of the file in the artifact directory.
+type BucketStore struct {
+	bucket *blob.Bucket
+	dir    string
+}
+
+// NewBucketStore opens the bucket at bucketURL, a gocloud blob URL such as s3://bucket or
+// gs://bucket. A key prefix can be set with the prefix query parameter.
+func NewBucketStore(ctx context.Context, bucketURL string, cacheDir string) (*BucketStore, error) {
+	bucket, err := blob.OpenBucket(ctx, bucketURL)
+	if err != nil {
+		return nil, fmt.Errorf("could not open artifacts bucket: %w", err)
+	}
+
+	return &BucketStore{bucket: bucket, dir: cacheDir}, nil
+}
+
+func (b *BucketStore) Dir(execID string) string {
+	return execDir(b.dir, execID)
+}
+
+// Restore replaces the local directory of an execution with the stored artifacts. It's empty
+// for executions that haven't stored any artifacts yet.
+func (b *BucketStore) Restore(ctx context.Context, execID string) error {
+	if err := checkExecID(execID); err != nil {
+		return err
+	}
+
+	dir := b.Dir(execID)
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("could not clear artifact directory: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create artifact directory: %w", err)
+	}
+
+	iter := b.bucket.List(&blob.ListOptions{Prefix: execID + "/"})
+	for {
+		obj, err := iter.Next(ctx)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("could not list artifacts of execution %s: %w", execID, err)
+		}
+
+		rel := filepath.FromSlash(obj.Key[len(execID)+1:])
+		if obj.IsDir || !filepath.IsLocal(rel) {
+			continue
+		}
+
+		target := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(target), 0700); err != nil {
+			return fmt.Errorf("could not create directory for %s: %w", obj.Key, err)
+		}
+		if err := b.download(ctx, obj.Key, target); err != nil {
+			return err
+		}
+	}
+}
+
+// Save uploads the files in the local directory of an execution and deletes the stored
+// artifacts whose files were removed
+func (b *BucketStore) Save(ctx context.Context, execID string) error {
+	if err := checkExecID(execID); err != nil {
+		return err
+	}
+
+	stored, err := b.list(ctx, execID)
+	if err != nil {
+		return err
+	}
+
+	dir := b.Dir(execID)
+	err = filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		key := execID + "/" + filepath.ToSlash(rel)
+		delete(stored, key)
+
+		return b.upload(ctx, p, key)
+	})
+	if err != nil {
+		return fmt.Errorf("could not save artifacts of execution %s: %w", execID, err)
+	}
+
+	for key := range stored {
+		if err := b.bucket.Delete(ctx, key); err != nil && gcerrors.Code(err) != gcerrors.NotFound {
+			return fmt.Errorf("could not delete %s: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+func (b *BucketStore) Delete(ctx context.Context, execID string) error {
+	if err := checkExecID(execID); err != nil {
+		return err
+	}
+
+	stored, err := b.list(ctx, execID)
+	if err != nil {
+		return err
+	}
+
+	for key := range stored {
+		if err := b.bucket.Delete(ctx, key); err != nil && gcerrors.Code(err) != gcerrors.NotFound {
+			return fmt.Errorf("could not delete %s: %w", key, err)
+		}
+	}
+
+	if err := os.RemoveAll(b.Dir(execID)); err != nil {
+		return fmt.Errorf("could not delete artifacts of execution %s: %w", execID, err)
+	}
+	return nil
+}
+
+// List returns the executions with artifacts in the bucket or in the cache directory. Working
+// copies of executions that failed before saving their artifacts are only in the cache directory.
+func (b *BucketStore) List(ctx context.Context) ([]Artifacts, error) {
+	byExec := make(map[string]*Artifacts)
+	iter := b.bucket.List(nil)
+	for {
+		obj, err := iter.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not list artifacts bucket: %w", err)
+		}
+
+		execID, _, ok := strings.Cut(obj.Key, "/")
+		if obj.IsDir || !ok {
+			continue
+		}
+		a, ok := byExec[execID]
+		if !ok {
+			a = &Artifacts{ExecID: execID}
+			byExec[execID] = a
+		}
+		a.Size += obj.Size
+		if obj.ModTime.After(a.ModTime) {
+			a.ModTime = obj.ModTime
+		}
+	}
+
+	local, err := listLocal(b.dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range local {
+		a, ok := byExec[l.ExecID]
+		if !ok {
+			byExec[l.ExecID] = &l
+			continue
+		}
+		if l.ModTime.After(a.ModTime) {
+			a.ModTime = l.ModTime
+		}
+	}
+
+	artifacts := make([]Artifacts, 0, len(byExec))
+	for _, a := range byExec {
+		artifacts = append(artifacts, *a)
+	}
+	return artifacts, nil
+}
+
+func (b *BucketStore) Close() error {
+	return b.bucket.Close()
+}
+
+// checkExecID rejects execution IDs that would match the keys of other executions or whose
+// directory is outside the cache directory
+func checkExecID(execID string) error {
+	if execID == "" || strings.ContainsAny(execID, `/\`) {
+		return fmt.Errorf("invalid execution ID %q", execID)
+	}
+	return nil
+}
+
+// list returns the keys of the stored artifacts of an execution
+func (b *BucketStore) list(ctx context.Context, execID string) (map[string]struct{}, error) {
+	stored := make(map[string]struct{})
+	iter := b.bucket.List(&blob.ListOptions{Prefix: execID + "/"})
+	for {
+		obj, err := iter.Next(ctx)
+		if err == io.EOF {
+			return stored, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not list artifacts of execution %s: %w", execID, err)
+		}
+		if !obj.IsDir {
+			stored[obj.Key] = struct{}{}
+		}
+	}
+}
+
+func (b *BucketStore) upload(ctx context.Context, path string, key string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// The writer detects the content type of the artifact, Upload requires it to be set.
+	// Cancelling ctx before closing the writer discards a partial upload.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	w, err := b.bucket.NewWriter(ctx, key, nil)
+	if err != nil {
+		return fmt.Errorf("could not upload %s: %w", key, err)
+	}
+	if _, err := io.Copy(w, f); err != nil {
+		cancel()
+		w.Close()
+		return fmt.Errorf("could not upload %s: %w", key, err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("could not upload %s: %w", key, err)
+	}
+	return nil
+}
+
+func (b *BucketStore) download(ctx context.Context, key string, target string) error {
+	r, err := b.bucket.NewReader(ctx, key, nil)
+	if err != nil {
+		return fmt.Errorf("could not read %s: %w", key, err)
+	}
+	defer r.Close()
+
+	f, err := os.Create(target)
+	if err != nil {
+		return fmt.Errorf("failed to create file %s: %w", target, err)
+	}
+
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write file %s: %w", target, err)
+	}
+	return f.Close()
+}
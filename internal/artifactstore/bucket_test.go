@@ -0,0 +1,171 @@
+package artifactstore
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gocloud.dev/blob/memblob"
+)
+
+func newTestBucketStore(t *testing.T) *BucketStore {
+	t.Helper()
+	b := &BucketStore{bucket: memblob.OpenBucket(nil), dir: t.TempDir()}
+	t.Cleanup(func() { b.Close() })
+	return b
+}
+
+func writeFile(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBucketStore_SaveRestore(t *testing.T) {
+	ctx := context.Background()
+	b := newTestBucketStore(t)
+
+	if err := b.Restore(ctx, "exec-1"); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	files := map[string]string{
+		"report.txt":       "report",
+		"build/output.bin": "output",
+	}
+	for name, content := range files {
+		writeFile(t, filepath.Join(b.Dir("exec-1"), name), content)
+	}
+	if err := b.Save(ctx, "exec-1"); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	for _, key := range []string{"exec-1/report.txt", "exec-1/build/output.bin"} {
+		if ok, err := b.bucket.Exists(ctx, key); err != nil || !ok {
+			t.Errorf("Exists(%s) = %v, %v, want true", key, ok, err)
+		}
+	}
+
+	// Restoring replaces the working copy, including files that were never saved
+	writeFile(t, filepath.Join(b.Dir("exec-1"), "unsaved.txt"), "unsaved")
+	if err := b.Restore(ctx, "exec-1"); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	for name, want := range files {
+		got, err := os.ReadFile(filepath.Join(b.Dir("exec-1"), name))
+		if err != nil {
+			t.Fatalf("ReadFile(%s) error = %v", name, err)
+		}
+		if string(got) != want {
+			t.Errorf("ReadFile(%s) = %q, want %q", name, got, want)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(b.Dir("exec-1"), "unsaved.txt")); !os.IsNotExist(err) {
+		t.Errorf("Stat(unsaved.txt) error = %v, want not exist", err)
+	}
+
+	// Files removed from the working copy are removed from the bucket on the next save
+	if err := os.Remove(filepath.Join(b.Dir("exec-1"), "report.txt")); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Save(ctx, "exec-1"); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if ok, _ := b.bucket.Exists(ctx, "exec-1/report.txt"); ok {
+		t.Errorf("Exists(exec-1/report.txt) = true after it was removed")
+	}
+}
+
+func TestBucketStore_Delete(t *testing.T) {
+	ctx := context.Background()
+	b := newTestBucketStore(t)
+
+	for _, execID := range []string{"exec-1", "exec-10"} {
+		writeFile(t, filepath.Join(b.Dir(execID), "report.txt"), execID)
+		if err := b.Save(ctx, execID); err != nil {
+			t.Fatalf("Save(%s) error = %v", execID, err)
+		}
+	}
+
+	if err := b.Delete(ctx, "exec-1"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if ok, _ := b.bucket.Exists(ctx, "exec-1/report.txt"); ok {
+		t.Errorf("Exists(exec-1/report.txt) = true after delete")
+	}
+	if _, err := os.Stat(b.Dir("exec-1")); !os.IsNotExist(err) {
+		t.Errorf("Stat(Dir(exec-1)) error = %v, want not exist", err)
+	}
+
+	// Executions whose ID starts with the deleted one are kept
+	if ok, _ := b.bucket.Exists(ctx, "exec-10/report.txt"); !ok {
+		t.Errorf("Exists(exec-10/report.txt) = false, want true")
+	}
+
+	artifacts, err := b.List(ctx)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(artifacts) != 1 || artifacts[0].ExecID != "exec-10" {
+		t.Errorf("List() = %v, want only exec-10", artifacts)
+	}
+}
+
+func TestBucketStore_RestoreSkipsEscapingKeys(t *testing.T) {
+	ctx := context.Background()
+	b := newTestBucketStore(t)
+
+	keys := []string{
+		"exec-1/report.txt",
+		"exec-1/../escaped.txt",
+		"exec-1/nested/../../escaped-nested.txt",
+		"exec-1//absolute.txt",
+		"exec-10/other.txt",
+	}
+	for _, key := range keys {
+		if err := b.bucket.WriteAll(ctx, key, []byte(key), nil); err != nil {
+			t.Fatalf("WriteAll(%s) error = %v", key, err)
+		}
+	}
+
+	if err := b.Restore(ctx, "exec-1"); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+
+	var restored []string
+	err := filepath.WalkDir(b.dir, func(p string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(b.dir, p)
+		restored = append(restored, filepath.ToSlash(rel))
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(restored) != 1 || restored[0] != execDirPrefix+"exec-1/report.txt" {
+		t.Errorf("restored files = %v, want only %s", restored, execDirPrefix+"exec-1/report.txt")
+	}
+}
+
+func TestBucketStore_InvalidExecID(t *testing.T) {
+	ctx := context.Background()
+	b := newTestBucketStore(t)
+
+	for _, execID := range []string{"", "exec-1/nested", "../exec-1", `..\exec-1`} {
+		if err := b.Restore(ctx, execID); err == nil {
+			t.Errorf("Restore(%q) error = nil, want error", execID)
+		}
+		if err := b.Save(ctx, execID); err == nil {
+			t.Errorf("Save(%q) error = nil, want error", execID)
+		}
+		if err := b.Delete(ctx, execID); err == nil {
+			t.Errorf("Delete(%q) error = nil, want error", execID)
+		}
+	}
+}
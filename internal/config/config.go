@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"net/url"
+	"reflect"
 	"runtime"
 	"strings"
 	"time"
@@ -25,7 +26,19 @@ type Config struct {
 	Scheduler  SchedulerConfig  `koanf:"scheduler"`
 	Logger     Logger           `koanf:"logger"`
 	Metrics    Metrics          `koanf:"metrics"`
+	Tracing    TracingConfig    `koanf:"tracing"`
 	Messengers MessengersConfig `koanf:"messengers"`
+	GitSync    GitSyncConfig    `koanf:"git_sync"`
+	FlowReview FlowReviewConfig `koanf:"flow_review"`
+
+	SecurityEvents SecurityEventsConfig `koanf:"security_events"`
+	Debug          DebugConfig          `koanf:"debug"`
+	Archive        ArchiveConfig        `koanf:"archive"`
+	Retention      RetentionConfig      `koanf:"retention"`
+	UploadScan     UploadScanConfig     `koanf:"upload_scan"`
+	EventBus       EventBusConfig       `koanf:"event_bus"`
+
+	MessageTriggers []MessageTriggerConfig `koanf:"message_triggers" validate:"dive"`
 }
 
 func (c *Config) Validate() error {
@@ -39,15 +52,177 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid oidc configuration: %w", err)
 	}
 
+	if err := validateGitSyncRepos(c.GitSync.Repos); err != nil {
+		return fmt.Errorf("invalid git_sync configuration: %w", err)
+	}
+
+	if err := validateMessageTriggers(c.MessageTriggers); err != nil {
+		return fmt.Errorf("invalid message_triggers configuration: %w", err)
+	}
+
+	if err := validateDBDriver(c.DB); err != nil {
+		return fmt.Errorf("invalid db configuration: %w", err)
+	}
+
+	if err := validateDBPool(c.DB, c.Scheduler.WorkerCount); err != nil {
+		return fmt.Errorf("invalid db configuration: %w", err)
+	}
+
+	return nil
+}
+
+// RestartRequired returns the sections that differ between c and next and are only applied
+// when the server starts. The log level, messengers, scheduler workers and retention settings
+// are applied when the config is reloaded.
+func (c Config) RestartRequired(next Config) []string {
+	c.clearReloadable()
+	next.clearReloadable()
+
+	var sections []string
+	cv, nv := reflect.ValueOf(c), reflect.ValueOf(next)
+	for i := 0; i < cv.NumField(); i++ {
+		if !reflect.DeepEqual(cv.Field(i).Interface(), nv.Field(i).Interface()) {
+			sections = append(sections, cv.Type().Field(i).Tag.Get("koanf"))
+		}
+	}
+	return sections
+}
+
+// CheckReload returns an error if next can't be applied to a server started with c. The database
+// pool is only rebuilt on a restart, so the scheduler workers of next must fit the pool of c.
+func (c Config) CheckReload(next Config) error {
+	if err := validateDBPool(c.DB, next.Scheduler.WorkerCount); err != nil {
+		return fmt.Errorf("scheduler workers don't fit the running db pool, a restart is needed to resize it: %w", err)
+	}
 	return nil
 }
 
+// clearReloadable zeroes the settings that can be changed without a restart
+func (c *Config) clearReloadable() {
+	c.Logger.Level = ""
+	c.Messengers = MessengersConfig{}
+	c.Scheduler.WorkerCount = 0
+	c.Retention = RetentionConfig{}
+}
+
 type Metrics struct {
 	Enabled bool   `koanf:"enabled"`
 	Path    string `koanf:"path"`
+	// ListenAddress serves metrics on a separate listener instead of the main server
+	ListenAddress string `koanf:"listen_address"`
+	// RequireAuth only lets superusers read metrics from the main server
+	RequireAuth bool              `koanf:"require_auth"`
+	Push        MetricsPushConfig `koanf:"push"`
+}
+
+// MetricsPushConfig periodically pushes metrics to a Prometheus Pushgateway, for setups
+// where flowctl can't be scraped
+type MetricsPushConfig struct {
+	URL      string        `koanf:"url" validate:"omitempty,url"`
+	Job      string        `koanf:"job"`
+	Interval time.Duration `koanf:"interval" validate:"omitempty,min=1s"`
+	Username string        `koanf:"username"`
+	Password string        `koanf:"password"`
+}
+
+// TracingConfig configures exporting OpenTelemetry traces of requests and executions to an
+// OTLP/HTTP collector
+type TracingConfig struct {
+	Enabled     bool    `koanf:"enabled"`
+	Endpoint    string  `koanf:"endpoint" validate:"required_if=Enabled true"`
+	Insecure    bool    `koanf:"insecure"`
+	ServiceName string  `koanf:"service_name"`
+	SampleRatio float64 `koanf:"sample_ratio" validate:"min=0,max=1"`
+}
+
+// SecurityEventsConfig configures writing security events such as logins, permission denials,
+// credential access and approval decisions as JSON lines to a sink for a SIEM to ingest
+type SecurityEventsConfig struct {
+	Enabled       bool              `koanf:"enabled"`
+	Sink          string            `koanf:"sink" validate:"required_if=Enabled true,omitempty,oneof=file syslog http"`
+	FilePath      string            `koanf:"file_path" validate:"required_if=Sink file"`
+	SyslogNetwork string            `koanf:"syslog_network" validate:"omitempty,oneof=udp tcp"`
+	SyslogAddress string            `koanf:"syslog_address" validate:"required_with=SyslogNetwork"`
+	SyslogTag     string            `koanf:"syslog_tag"`
+	HTTPURL       string            `koanf:"http_url" validate:"required_if=Sink http,omitempty,url"`
+	HTTPHeaders   map[string]string `koanf:"http_headers"`
+	HTTPTimeout   time.Duration     `koanf:"http_timeout" validate:"min=0"`
+}
+
+// EventBusConfig configures publishing execution and approval lifecycle events to NATS subjects
+// or Kafka topics for data platforms and audit pipelines.
+type EventBusConfig struct {
+	Enabled  bool   `koanf:"enabled"`
+	Provider string `koanf:"provider" validate:"required_if=Enabled true,omitempty,oneof=nats kafka"`
+	// URL is the NATS server
+	URL string `koanf:"url" validate:"required_if=Enabled true Provider nats,omitempty,url"`
+	// Brokers are the addresses of the Kafka brokers
+	Brokers       []string      `koanf:"brokers" validate:"required_if=Enabled true Provider kafka,dive,hostname_port"`
+	SubjectPrefix string        `koanf:"subject_prefix"`
+	Timeout       time.Duration `koanf:"timeout" validate:"min=0"`
+}
+
+// MessageTriggerConfig runs a flow for every message received from a Kafka topic, NATS subject
+// or SQS queue. URL is a subscription URL such as kafka://group?topic=alerts,
+// nats://alerts?queue=flowctl or awssqs://sqs.us-east-1.amazonaws.com/123456789012/alerts.
+type MessageTriggerConfig struct {
+	Name      string `koanf:"name" validate:"required"`
+	URL       string `koanf:"url" validate:"required,url"`
+	Namespace string `koanf:"namespace" validate:"required"`
+	Flow      string `koanf:"flow" validate:"required"`
+	// Inputs maps input names to values with {{ message.* }} and {{ metadata.* }} placeholders
+	Inputs map[string]string `koanf:"inputs"`
+	// Concurrency is the number of executions started by the trigger that can run at once on
+	// each instance, 0 means 1
+	Concurrency int `koanf:"concurrency" validate:"min=0"`
+}
+
+// DebugConfig enables the pprof, expvar and runtime snapshot endpoints. Without a listen
+// address they are served on the main server to superusers only.
+type DebugConfig struct {
+	Enabled bool `koanf:"enabled"`
+	// ListenAddress serves pprof and expvar without authentication. It must be a loopback address.
+	ListenAddress string `koanf:"listen_address"`
+}
+
+// ArchiveConfig configures moving finished executions out of execution_log into summaries
+// in execution_archive. Archival is disabled if After is 0.
+type ArchiveConfig struct {
+	After     time.Duration `koanf:"after" validate:"omitempty,min=24h"`
+	Interval  time.Duration `koanf:"interval" validate:"min=1m"`
+	BatchSize int           `koanf:"batch_size" validate:"min=1,max=10000"`
+}
+
+// UploadScanConfig configures external scanners that check the files uploaded to file inputs
+// before the execution is queued. The command and the webhook are both run if set.
+type UploadScanConfig struct {
+	// Command is run with the file on its standard input, a non-zero exit status rejects the file
+	Command []string `koanf:"command"`
+	// WebhookURL is sent the file in a POST request, a response other than 2xx rejects the file
+	WebhookURL string        `koanf:"webhook_url" validate:"omitempty,url"`
+	Timeout    time.Duration `koanf:"timeout" validate:"min=1s"`
+}
+
+// RetentionConfig configures the job that purges executions according to the retention
+// policies of the namespaces
+type RetentionConfig struct {
+	Interval  time.Duration `koanf:"interval" validate:"min=1m"`
+	BatchSize int           `koanf:"batch_size" validate:"min=1,max=10000"`
+	// ArtifactGrace is how long artifacts must be untouched before they are collected, so that
+	// executions that were just queued or resumed keep theirs
+	ArtifactGrace time.Duration `koanf:"artifact_grace" validate:"min=0"`
+	// PendingApprovalArtifactsMaxAge collects the artifacts of executions that have waited for an
+	// approval longer than this. They are kept until the execution is purged if 0.
+	PendingApprovalArtifactsMaxAge time.Duration `koanf:"pending_approval_artifacts_max_age" validate:"min=0"`
+	// RetryableArtifactsMaxAge collects the artifacts of errored and cancelled executions that
+	// finished longer ago than this. They are kept until the execution is purged if 0.
+	RetryableArtifactsMaxAge time.Duration `koanf:"retryable_artifacts_max_age" validate:"min=0"`
 }
 
 type DBConfig struct {
+	// Driver is the database flowctl uses, postgres or sqlite. The DSN of sqlite is the path
+	// of the database file
+	Driver      string `koanf:"driver" validate:"omitempty,oneof=postgres sqlite"`
 	DSN         string `koanf:"dsn"`
 	DBName      string `koanf:"dbname" validate:"required_without=DSN"`
 	User        string `koanf:"user" validate:"required_without=DSN"`
@@ -58,6 +233,20 @@ type DBConfig struct {
 	SSLCert     string `koanf:"sslcert"`
 	SSLKey      string `koanf:"sslkey"`
 	SSLRootCert string `koanf:"sslrootcert"`
+	// ReadReplicaDSN is the connection string of a read-only replica that serves the
+	// list, search and stats queries. They use the primary if it is not set
+	ReadReplicaDSN string `koanf:"read_replica_dsn"`
+
+	// Connection pool settings of the primary and the read replica. 0 means no limit
+	MaxOpenConns    int           `koanf:"max_open_conns" validate:"min=0"`
+	MaxIdleConns    int           `koanf:"max_idle_conns" validate:"min=0"`
+	ConnMaxLifetime time.Duration `koanf:"conn_max_lifetime" validate:"min=0"`
+	ConnMaxIdleTime time.Duration `koanf:"conn_max_idle_time" validate:"min=0"`
+}
+
+// SQLite returns whether the database is a SQLite database
+func (db DBConfig) SQLite() bool {
+	return db.Driver == "sqlite"
 }
 
 // ConnectionString returns the database connection string.
@@ -102,27 +291,76 @@ type SchedulerConfig struct {
 	Backend              string        `koanf:"backend"`
 	CronSyncInterval     time.Duration `koanf:"cron_sync_interval" validate:"min=1s"`
 	FlowExecutionTimeout time.Duration `koanf:"flow_execution_timeout" validate:"min=1s"`
+	// TelemetryInterval is how often node resource usage is sampled for actions with telemetry enabled
+	TelemetryInterval time.Duration `koanf:"telemetry_interval" validate:"min=1s"`
+	// ProgressFlushInterval is how long execution progress and node timeline entries are buffered before they are written to the database
+	ProgressFlushInterval time.Duration `koanf:"progress_flush_interval" validate:"min=100ms"`
 }
 
 type Logger struct {
-	Backend       string        `koanf:"backend"`
-	Directory     string        `koanf:"log_directory" validate:"required"`
-	MaxSizeBytes  int64         `koanf:"max_size_bytes" validate:"min=0"`
-	RetentionTime time.Duration `koanf:"retention_time" validate:"min=0"`
-	ScanInterval  time.Duration `koanf:"scan_interval" validate:"min=1s"`
+	// Level is the level of the server logs, DEBUG_LOG=true always logs at debug
+	Level               string        `koanf:"level" validate:"omitempty,oneof=debug info warn error"`
+	Backend             string        `koanf:"backend" validate:"omitempty,oneof=file object"`
+	Directory           string        `koanf:"log_directory" validate:"required"`
+	BucketURL           string        `koanf:"bucket_url" validate:"required_if=Backend object"`
+	MaxSizeBytes        int64         `koanf:"max_size_bytes" validate:"min=0"`
+	RetentionTime       time.Duration `koanf:"retention_time" validate:"min=0"`
+	ScanInterval        time.Duration `koanf:"scan_interval" validate:"min=1s"`
+	Compress            bool          `koanf:"compress"`
+	MaxExecutionLogSize int64         `koanf:"max_execution_log_size" validate:"min=0"`
+	ANSI                string        `koanf:"ansi" validate:"omitempty,oneof=preserve strip"`
+	Shared              bool          `koanf:"shared"`
 }
 
 type AppConfig struct {
-	AdminUsername     string `koanf:"admin_username" validate:"required,min=1"`
-	AdminPassword     string `koanf:"admin_password" validate:"required,min=8"`
-	RootURL           string `koanf:"root_url" validate:"required,url"`
-	Address           string `koanf:"address" validate:"required"`
-	UseTLS            bool   `koanf:"use_tls"`
-	HTTPTLSCert       string `koanf:"http_tls_cert" validate:"required_if=UseTLS true"`
-	HTTPTLSKey        string `koanf:"http_tls_key" validate:"required_if=UseTLS true"`
-	FlowsDirectory    string `koanf:"flows_directory" validate:"required"`
-	MaxFileUploadSize int64  `koanf:"max_file_upload_size" validate:"required,min=1"`
-	PluginDir         string `koanf:"plugin_dir"`
+	AdminUsername     string        `koanf:"admin_username" validate:"required,min=1"`
+	AdminPassword     string        `koanf:"admin_password" validate:"required,min=8"`
+	RootURL           string        `koanf:"root_url" validate:"required,url"`
+	Address           string        `koanf:"address" validate:"required"`
+	UseTLS            bool          `koanf:"use_tls"`
+	HTTPTLSCert       string        `koanf:"http_tls_cert" validate:"required_if=UseTLS true"`
+	HTTPTLSKey        string        `koanf:"http_tls_key" validate:"required_if=UseTLS true"`
+	FlowsDirectory    string        `koanf:"flows_directory" validate:"required"`
+	FlowsCacheDir     string        `koanf:"flows_cache_directory"`
+	WatchFlows        bool          `koanf:"watch_flows"`
+	WatchDebounce     time.Duration `koanf:"watch_debounce" validate:"min=0"`
+	MaxFileUploadSize int64         `koanf:"max_file_upload_size" validate:"required,min=1"`
+	UploadsDirectory  string        `koanf:"uploads_directory"`
+	ArtifactsDir      string        `koanf:"artifacts_directory"`
+	PluginDir         string        `koanf:"plugin_dir"`
+	// ArtifactsArchive is where the files that actions publish are kept after their executions
+	// finished, a local directory or a bucket URL
+	ArtifactsArchive string `koanf:"artifacts_archive"`
+	// FlowLoadConcurrency is how many flow files of a namespace are imported at the same time
+	FlowLoadConcurrency int `koanf:"flow_load_concurrency" validate:"min=0"`
+	// ShutdownTimeout is how long requests in flight and running executions are given to finish
+	// or be handed off when the server stops
+	ShutdownTimeout time.Duration `koanf:"shutdown_timeout" validate:"min=1s"`
+}
+
+// GitSyncConfig configures syncing the flows of namespaces from git repositories.
+// Repositories are cloned into WorkDirectory.
+type GitSyncConfig struct {
+	WorkDirectory string          `koanf:"work_directory" validate:"required_with=Repos"`
+	Repos         []GitRepoConfig `koanf:"repos" validate:"dive"`
+}
+
+// FlowReviewConfig lists the namespaces in which changes to flows through the API or git sync
+// are held as pending revisions until a reviewer approves them.
+type FlowReviewConfig struct {
+	Namespaces []string `koanf:"namespaces" validate:"dive,required"`
+}
+
+// GitRepoConfig is a git repository whose flows replace the flows of a namespace.
+// Path is the directory in the repository with the flows, the repository root if empty.
+type GitRepoConfig struct {
+	Namespace     string        `koanf:"namespace" validate:"required"`
+	URL           string        `koanf:"url" validate:"required"`
+	Branch        string        `koanf:"branch"`
+	Path          string        `koanf:"path"`
+	PollInterval  time.Duration `koanf:"poll_interval" validate:"omitempty,min=10s"`
+	WebhookSecret string        `koanf:"webhook_secret"`
+	SSHKeyPath    string        `koanf:"ssh_key_path"`
 }
 
 type KeystoreConfig struct {
@@ -150,8 +388,82 @@ type OIDCConfig struct {
 }
 
 type MessengersConfig struct {
-	Email   SMTPConfig    `koanf:"email"`
-	Webhook WebhookConfig `koanf:"webhook"`
+	Email     SMTPConfig      `koanf:"email"`
+	Webhook   WebhookConfig   `koanf:"webhook"`
+	PagerDuty PagerDutyConfig `koanf:"pagerduty"`
+	Opsgenie  OpsgenieConfig  `koanf:"opsgenie"`
+	Slack     SlackConfig     `koanf:"slack"`
+}
+
+// mapProvider is a koanf provider for an in-memory config map.
+type mapProvider map[string]any
+
+func (m mapProvider) ReadBytes() ([]byte, error) {
+	return nil, fmt.Errorf("map provider does not support ReadBytes")
+}
+
+func (m mapProvider) Read() (map[string]any, error) {
+	return m, nil
+}
+
+// WithOverride returns a copy of the messengers config with the section for channel
+// overlaid by raw, which uses the same keys as the config file. Keys missing from raw
+// keep their value from the config file.
+func (m MessengersConfig) WithOverride(channel string, enabled bool, raw map[string]any) (MessengersConfig, error) {
+	k := koanf.New(".")
+	if err := k.Load(mapProvider(raw), nil); err != nil {
+		return m, fmt.Errorf("error loading %s config: %w", channel, err)
+	}
+
+	section := m.section(channel)
+	if section == nil {
+		return m, fmt.Errorf("unknown messenger %q", channel)
+	}
+
+	if err := k.Unmarshal("", section); err != nil {
+		return m, fmt.Errorf("error unmarshaling %s config: %w", channel, err)
+	}
+
+	switch channel {
+	case "email":
+		m.Email.Enabled = enabled
+	case "webhook":
+		m.Webhook.Enabled = enabled
+	case "pagerduty":
+		m.PagerDuty.Enabled = enabled
+	case "opsgenie":
+		m.Opsgenie.Enabled = enabled
+	case "slack":
+		m.Slack.Enabled = enabled
+	}
+
+	if err := validator.New().Struct(section); err != nil {
+		return m, fmt.Errorf("invalid %s config: %w", channel, err)
+	}
+
+	return m, nil
+}
+
+// ChannelEqual reports whether channel has the same settings in both configs
+func (m MessengersConfig) ChannelEqual(other MessengersConfig, channel string) bool {
+	return reflect.DeepEqual(m.section(channel), other.section(channel))
+}
+
+// section returns a pointer to the settings of channel, nil if the channel is unknown
+func (m *MessengersConfig) section(channel string) any {
+	switch channel {
+	case "email":
+		return &m.Email
+	case "webhook":
+		return &m.Webhook
+	case "pagerduty":
+		return &m.PagerDuty
+	case "opsgenie":
+		return &m.Opsgenie
+	case "slack":
+		return &m.Slack
+	}
+	return nil
 }
 
 type WebhookConfig struct {
@@ -160,6 +472,40 @@ type WebhookConfig struct {
 	Timeout    time.Duration `koanf:"timeout"`
 }
 
+// PagerDutyConfig configures the PagerDuty Events API v2 messenger. Routing keys can be set
+// per flow in the notify config, per namespace name in NamespaceRoutingKeys, or globally.
+type PagerDutyConfig struct {
+	Enabled              bool              `koanf:"enabled"`
+	RoutingKey           string            `koanf:"routing_key"`
+	NamespaceRoutingKeys map[string]string `koanf:"namespace_routing_keys"`
+	EventsURL            string            `koanf:"events_url" validate:"omitempty,url"`
+	Timeout              time.Duration     `koanf:"timeout"`
+}
+
+// OpsgenieConfig configures the Opsgenie Alert API messenger. API keys can be set
+// per flow in the notify config, per namespace name in NamespaceAPIKeys, or globally.
+type OpsgenieConfig struct {
+	Enabled          bool              `koanf:"enabled"`
+	APIKey           string            `koanf:"api_key"`
+	NamespaceAPIKeys map[string]string `koanf:"namespace_api_keys"`
+	APIURL           string            `koanf:"api_url" validate:"omitempty,url"`
+	Timeout          time.Duration     `koanf:"timeout"`
+}
+
+// SlackConfig configures the Slack messenger. Messages are posted to an incoming webhook, or with
+// a bot token to the channel set per flow in the notify config, per namespace name in
+// NamespaceChannels, or DefaultChannel. Template is a Go template of the message text.
+type SlackConfig struct {
+	Enabled           bool              `koanf:"enabled"`
+	WebhookURL        string            `koanf:"webhook_url" validate:"omitempty,url"`
+	BotToken          string            `koanf:"bot_token"`
+	DefaultChannel    string            `koanf:"default_channel"`
+	NamespaceChannels map[string]string `koanf:"namespace_channels"`
+	APIURL            string            `koanf:"api_url" validate:"omitempty,url"`
+	Template          string            `koanf:"template"`
+	Timeout           time.Duration     `koanf:"timeout"`
+}
+
 type SMTPConfig struct {
 	Enabled     bool   `koanf:"enabled"`
 	Host        string `koanf:"host" validate:"required_if=Enabled true"`
@@ -207,28 +553,38 @@ func Load(configPath string) (Config, error) {
 func GetDefaultConfig() Config {
 	return Config{
 		DB: DBConfig{
-			DSN:         "",
-			DBName:      "flowctl",
-			User:        "flowctl",
-			Password:    "flowctl",
-			Host:        "127.0.0.1",
-			Port:        5432,
-			SSLMode:     "disable",
-			SSLCert:     "",
-			SSLKey:      "",
-			SSLRootCert: "",
+			Driver:          "postgres",
+			DSN:             "",
+			DBName:          "flowctl",
+			User:            "flowctl",
+			Password:        "flowctl",
+			Host:            "127.0.0.1",
+			Port:            5432,
+			SSLMode:         "disable",
+			SSLCert:         "",
+			SSLKey:          "",
+			SSLRootCert:     "",
+			MaxOpenConns:    0,
+			MaxIdleConns:    25,
+			ConnMaxLifetime: 30 * time.Minute,
+			ConnMaxIdleTime: 5 * time.Minute,
 		},
 		App: AppConfig{
-			AdminUsername:     "flowctl_admin",
-			AdminPassword:     "flowctl_password",
-			RootURL:           "http://localhost:7000",
-			Address:           ":7000",
-			UseTLS:            false,
-			HTTPTLSCert:       "server_cert.pem",
-			HTTPTLSKey:        "server_key.pem",
-			FlowsDirectory:    "flows",
-			MaxFileUploadSize: 100 * 1024 * 1024, // 100MB
-			PluginDir:         "",
+			AdminUsername:       "flowctl_admin",
+			AdminPassword:       "flowctl_password",
+			RootURL:             "http://localhost:7000",
+			Address:             ":7000",
+			UseTLS:              false,
+			HTTPTLSCert:         "server_cert.pem",
+			HTTPTLSKey:          "server_key.pem",
+			FlowsDirectory:      "flows",
+			FlowsCacheDir:       "flows-cache",
+			WatchFlows:          false,
+			WatchDebounce:       500 * time.Millisecond,
+			MaxFileUploadSize:   100 * 1024 * 1024, // 100MB
+			PluginDir:           "",
+			FlowLoadConcurrency: 8,
+			ShutdownTimeout:     30 * time.Second,
 		},
 		Keystore: KeystoreConfig{
 			KeeperURL: fmt.Sprintf("base64key://%s", genKey(32)),
@@ -241,13 +597,17 @@ func GetDefaultConfig() Config {
 			},
 		},
 		Scheduler: SchedulerConfig{
-			WorkerCount:          runtime.NumCPU(),
-			CronSyncInterval:     5 * time.Minute,
-			FlowExecutionTimeout: time.Hour,
+			WorkerCount:           runtime.NumCPU(),
+			CronSyncInterval:      5 * time.Minute,
+			FlowExecutionTimeout:  time.Hour,
+			TelemetryInterval:     15 * time.Second,
+			ProgressFlushInterval: 2 * time.Second,
 		},
 		Logger: Logger{
+			Level:         "info",
 			Backend:       "file",
 			Directory:     "/var/log/flowctl",
+			ANSI:          "preserve",
 			RetentionTime: 0,
 			ScanInterval:  1 * time.Hour,
 		},
@@ -263,6 +623,64 @@ func GetDefaultConfig() Config {
 				Enabled: false,
 				Timeout: 30 * time.Second,
 			},
+			PagerDuty: PagerDutyConfig{
+				Enabled:   false,
+				EventsURL: "https://events.pagerduty.com/v2/enqueue",
+				Timeout:   30 * time.Second,
+			},
+			Opsgenie: OpsgenieConfig{
+				Enabled: false,
+				APIURL:  "https://api.opsgenie.com",
+				Timeout: 30 * time.Second,
+			},
+			Slack: SlackConfig{
+				Enabled: false,
+				APIURL:  "https://slack.com/api",
+				Timeout: 30 * time.Second,
+			},
+		},
+		Metrics: Metrics{
+			Path: "/metrics",
+			Push: MetricsPushConfig{
+				Job:      "flowctl",
+				Interval: 30 * time.Second,
+			},
+		},
+		Tracing: TracingConfig{
+			Enabled:     false,
+			Endpoint:    "localhost:4318",
+			Insecure:    true,
+			ServiceName: "flowctl",
+			SampleRatio: 1,
+		},
+		SecurityEvents: SecurityEventsConfig{
+			Enabled:     false,
+			Sink:        "file",
+			FilePath:    "security.log",
+			SyslogTag:   "flowctl",
+			HTTPTimeout: 10 * time.Second,
+		},
+		GitSync: GitSyncConfig{
+			WorkDirectory: "git",
+		},
+		Archive: ArchiveConfig{
+			After:     0,
+			Interval:  time.Hour,
+			BatchSize: 500,
+		},
+		Retention: RetentionConfig{
+			Interval:      time.Hour,
+			BatchSize:     500,
+			ArtifactGrace: time.Hour,
+		},
+		UploadScan: UploadScanConfig{
+			Timeout: time.Minute,
+		},
+		EventBus: EventBusConfig{
+			Enabled:       false,
+			Provider:      "nats",
+			SubjectPrefix: "flowctl",
+			Timeout:       10 * time.Second,
 		},
 	}
 }
@@ -289,3 +707,63 @@ func validateOIDCProviders(providers []OIDCConfig) error {
 
 	return nil
 }
+
+// minDBConnsHeadroom is the number of connections kept free for requests and job bookkeeping
+// on top of the ones held by workers
+const minDBConnsHeadroom = 10
+
+// validateMessageTriggers ensures message trigger names are unique
+func validateMessageTriggers(triggers []MessageTriggerConfig) error {
+	names := make(map[string]bool)
+
+	for _, t := range triggers {
+		if names[t.Name] {
+			return fmt.Errorf("duplicate trigger name: %s", t.Name)
+		}
+		names[t.Name] = true
+	}
+
+	return nil
+}
+
+// validateDBDriver ensures a SQLite database is given by the path of its file. SQLite databases
+// have no replicas.
+func validateDBDriver(db DBConfig) error {
+	if !db.SQLite() {
+		return nil
+	}
+	if db.DSN == "" {
+		return fmt.Errorf("dsn must be the path of the database file with the sqlite driver")
+	}
+	if db.ReadReplicaDSN != "" {
+		return fmt.Errorf("read_replica_dsn is not supported with the sqlite driver")
+	}
+	return nil
+}
+
+// validateDBPool ensures a limited pool has room for the connection every worker holds for the
+// whole job, besides the connections used by requests and by the jobs themselves. A smaller pool
+// deadlocks once all its connections are held by running jobs.
+func validateDBPool(db DBConfig, workers int) error {
+	if db.MaxOpenConns == 0 {
+		return nil
+	}
+	if required := workers + minDBConnsHeadroom; db.MaxOpenConns < required {
+		return fmt.Errorf("max_open_conns must be 0 or at least %d, the number of scheduler workers plus %d", required, minDBConnsHeadroom)
+	}
+	return nil
+}
+
+// validateGitSyncRepos ensures each namespace is synced from at most one repository
+func validateGitSyncRepos(repos []GitRepoConfig) error {
+	namespaces := make(map[string]bool)
+
+	for _, repo := range repos {
+		if namespaces[repo.Namespace] {
+			return fmt.Errorf("namespace %s has more than one repository", repo.Namespace)
+		}
+		namespaces[repo.Namespace] = true
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRestartRequired(t *testing.T) {
+	base := GetDefaultConfig()
+
+	tests := []struct {
+		name   string
+		change func(c *Config)
+		want   []string
+	}{
+		{
+			name:   "unchanged",
+			change: func(c *Config) {},
+		},
+		{
+			name: "reloadable settings",
+			change: func(c *Config) {
+				c.Logger.Level = "debug"
+				c.Scheduler.WorkerCount = 32
+				c.Retention.Interval = time.Minute
+				c.Messengers.Email.Enabled = true
+			},
+		},
+		{
+			name: "settings applied on start",
+			change: func(c *Config) {
+				c.App.Address = ":8000"
+				c.Scheduler.FlowExecutionTimeout = 2 * time.Hour
+				c.Logger.Level = "warn"
+			},
+			want: []string{"app", "scheduler"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := base
+			tt.change(&next)
+
+			if got := base.RestartRequired(next); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RestartRequired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckReload(t *testing.T) {
+	base := GetDefaultConfig()
+	base.DB.MaxOpenConns = 20
+	base.Scheduler.WorkerCount = 8
+
+	tests := []struct {
+		name    string
+		change  func(c *Config)
+		wantErr bool
+	}{
+		{
+			name:   "workers fit the running pool",
+			change: func(c *Config) { c.Scheduler.WorkerCount = 20 - minDBConnsHeadroom },
+		},
+		{
+			name:    "workers exceed the running pool",
+			change:  func(c *Config) { c.Scheduler.WorkerCount = 20 },
+			wantErr: true,
+		},
+		{
+			name: "pool grown in the same reload",
+			change: func(c *Config) {
+				c.DB.MaxOpenConns = 100
+				c.Scheduler.WorkerCount = 32
+			},
+			wantErr: true,
+		},
+		{
+			name:   "pool shrunk in the same reload",
+			change: func(c *Config) { c.DB.MaxOpenConns = 10 },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := base
+			tt.change(&next)
+
+			if err := base.CheckReload(next); (err != nil) != tt.wantErr {
+				t.Errorf("CheckReload() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateDBDriver(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  func(c *Config)
+		wantErr bool
+	}{
+		{
+			name: "postgres",
+		},
+		{
+			name: "sqlite",
+			change: func(c *Config) {
+				c.DB.Driver = "sqlite"
+				c.DB.DSN = "/var/lib/flowctl/flowctl.db"
+			},
+		},
+		{
+			name:    "sqlite without a path",
+			change:  func(c *Config) { c.DB.Driver = "sqlite" },
+			wantErr: true,
+		},
+		{
+			name: "sqlite with a read replica",
+			change: func(c *Config) {
+				c.DB.Driver = "sqlite"
+				c.DB.DSN = "flowctl.db"
+				c.DB.ReadReplicaDSN = "flowctl-replica.db"
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := GetDefaultConfig()
+			if tt.change != nil {
+				tt.change(&c)
+			}
+
+			if err := validateDBDriver(c.DB); (err != nil) != tt.wantErr {
+				t.Errorf("validateDBDriver() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package core
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/cvhariharan/flowctl/internal/repo"
+	"github.com/google/uuid"
+)
+
+// APITokenPrefix marks personal API tokens so that they can be told apart from executor tokens.
+const APITokenPrefix = "fctu_"
+
+// hashAPIToken returns the hex encoded SHA-256 of a token. Only the hash is stored,
+// the token itself is shown once when it is created.
+func hashAPIToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateAPIToken creates a personal API token for the user and returns it along with the secret token.
+// A nil expiresAt creates a token that never expires.
+func (c *Core) CreateAPIToken(ctx context.Context, userID string, name string, expiresAt *time.Time) (models.APIToken, string, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return models.APIToken{}, "", fmt.Errorf("user ID should be a UUID: %w", err)
+	}
+
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return models.APIToken{}, "", errors.New("token expiry is in the past")
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return models.APIToken{}, "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	token := APITokenPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	var expiry sql.NullTime
+	if expiresAt != nil {
+		expiry = sql.NullTime{Time: *expiresAt, Valid: true}
+	}
+
+	t, err := c.store.CreateAPIToken(ctx, repo.CreateAPITokenParams{
+		UserUuid:  userUUID,
+		Name:      name,
+		TokenHash: hashAPIToken(token),
+		ExpiresAt: expiry,
+	})
+	if err != nil {
+		return models.APIToken{}, "", fmt.Errorf("could not create API token: %w", err)
+	}
+
+	return repoAPITokenToAPIToken(t), token, nil
+}
+
+// ListAPITokens returns the API tokens of the user. The secret tokens are never returned.
+func (c *Core) ListAPITokens(ctx context.Context, userID string) ([]models.APIToken, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("user ID should be a UUID: %w", err)
+	}
+
+	tokens, err := c.store.ListAPITokensByUser(ctx, userUUID)
+	if err != nil {
+		return nil, fmt.Errorf("could not list API tokens: %w", err)
+	}
+
+	res := make([]models.APIToken, 0, len(tokens))
+	for _, t := range tokens {
+		res = append(res, repoAPITokenToAPIToken(t))
+	}
+
+	return res, nil
+}
+
+// DeleteAPIToken revokes an API token owned by the user.
+func (c *Core) DeleteAPIToken(ctx context.Context, tokenID string, userID string) error {
+	tokenUUID, err := uuid.Parse(tokenID)
+	if err != nil {
+		return fmt.Errorf("token ID should be a UUID: %w", err)
+	}
+
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return fmt.Errorf("user ID should be a UUID: %w", err)
+	}
+
+	rowsAffected, err := c.store.DeleteAPITokenByUser(ctx, repo.DeleteAPITokenByUserParams{
+		Uuid:     tokenUUID,
+		UserUuid: userUUID,
+	})
+	if err != nil {
+		return fmt.Errorf("could not delete API token: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("API token not found")
+	}
+
+	return nil
+}
+
+// ValidateAPIToken checks that the token exists and has not expired, and returns the
+// user it belongs to.
+func (c *Core) ValidateAPIToken(ctx context.Context, token string) (models.UserWithGroups, error) {
+	if !strings.HasPrefix(token, APITokenPrefix) {
+		return models.UserWithGroups{}, fmt.Errorf("invalid token prefix")
+	}
+
+	owner, err := c.store.GetAPITokenOwnerByHash(ctx, hashAPIToken(token))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.UserWithGroups{}, fmt.Errorf("invalid or expired API token")
+		}
+		return models.UserWithGroups{}, fmt.Errorf("could not validate API token: %w", err)
+	}
+
+	if owner.ExpiresAt.Valid && !owner.ExpiresAt.Time.After(time.Now()) {
+		return models.UserWithGroups{}, fmt.Errorf("invalid or expired API token")
+	}
+
+	return c.GetUserWithUUIDWithGroups(ctx, owner.UserUuid.String())
+}
+
+func repoAPITokenToAPIToken(t repo.ApiToken) models.APIToken {
+	token := models.APIToken{
+		ID:        t.Uuid.String(),
+		Name:      t.Name,
+		CreatedAt: t.CreatedAt,
+	}
+	if t.ExpiresAt.Valid {
+		token.ExpiresAt = &t.ExpiresAt.Time
+	}
+	if t.LastUsedAt.Valid {
+		token.LastUsedAt = &t.LastUsedAt.Time
+	}
+	return token
+}
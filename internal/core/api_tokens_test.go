@@ -0,0 +1,146 @@
+package core
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cvhariharan/flowctl/internal/repo"
+	"github.com/google/uuid"
+)
+
+// apiTokenStore keeps API tokens by their hash. It returns the tokens as they were stored, checking
+// the hash and the expiry is left to the core.
+type apiTokenStore struct {
+	repo.Store
+	tokens map[string]repo.CreateAPITokenParams
+	users  map[uuid.UUID]repo.UserView
+}
+
+func (s *apiTokenStore) CreateAPIToken(ctx context.Context, arg repo.CreateAPITokenParams) (repo.ApiToken, error) {
+	s.tokens[arg.TokenHash] = arg
+	return repo.ApiToken{Uuid: uuid.New(), Name: arg.Name, ExpiresAt: arg.ExpiresAt, CreatedAt: time.Now()}, nil
+}
+
+func (s *apiTokenStore) GetAPITokenOwnerByHash(ctx context.Context, hash string) (repo.GetAPITokenOwnerByHashRow, error) {
+	t, ok := s.tokens[hash]
+	if !ok {
+		return repo.GetAPITokenOwnerByHashRow{}, sql.ErrNoRows
+	}
+	return repo.GetAPITokenOwnerByHashRow{UserUuid: t.UserUuid, ExpiresAt: t.ExpiresAt}, nil
+}
+
+func (s *apiTokenStore) GetUserByUUIDWithGroups(ctx context.Context, id uuid.UUID) (repo.UserView, error) {
+	u, ok := s.users[id]
+	if !ok {
+		return repo.UserView{}, sql.ErrNoRows
+	}
+	return u, nil
+}
+
+func TestCreateAPIToken(t *testing.T) {
+	userID := uuid.New()
+	store := &apiTokenStore{tokens: make(map[string]repo.CreateAPITokenParams)}
+	c := &Core{store: store}
+
+	_, token, err := c.CreateAPIToken(context.Background(), userID.String(), "ci", nil)
+	if err != nil {
+		t.Fatalf("CreateAPIToken() error = %v", err)
+	}
+	if !strings.HasPrefix(token, APITokenPrefix) {
+		t.Errorf("CreateAPIToken() token = %q, want prefix %q", token, APITokenPrefix)
+	}
+	if len(store.tokens) != 1 {
+		t.Fatalf("CreateAPIToken() stored %d tokens, want 1", len(store.tokens))
+	}
+	sum := sha256.Sum256([]byte(token))
+	want := hex.EncodeToString(sum[:])
+	for hash, stored := range store.tokens {
+		if hash == token || strings.Contains(hash, strings.TrimPrefix(token, APITokenPrefix)) {
+			t.Errorf("CreateAPIToken() stored the token instead of its hash")
+		}
+		if hash != want || stored.TokenHash != want || stored.UserUuid != userID {
+			t.Errorf("CreateAPIToken() stored %s for %s, want the SHA-256 %s for %s", hash, stored.UserUuid, want, userID)
+		}
+		if stored.ExpiresAt.Valid {
+			t.Errorf("CreateAPIToken() without an expiry stored expiry %v", stored.ExpiresAt.Time)
+		}
+	}
+
+	_, other, err := c.CreateAPIToken(context.Background(), userID.String(), "ci", nil)
+	if err != nil {
+		t.Fatalf("CreateAPIToken() error = %v", err)
+	}
+	if other == token {
+		t.Errorf("CreateAPIToken() returned the same token twice")
+	}
+
+	past := time.Now().Add(-time.Minute)
+	if _, _, err := c.CreateAPIToken(context.Background(), userID.String(), "expired", &past); err == nil {
+		t.Errorf("CreateAPIToken() with an expiry in the past error = nil, want error")
+	}
+	if len(store.tokens) != 2 {
+		t.Errorf("CreateAPIToken() stored %d tokens, want 2", len(store.tokens))
+	}
+}
+
+func TestValidateAPIToken(t *testing.T) {
+	now := time.Now()
+	userID := uuid.New()
+	store := &apiTokenStore{
+		tokens: make(map[string]repo.CreateAPITokenParams),
+		users: map[uuid.UUID]repo.UserView{
+			userID: {Uuid: userID, Name: "CI", Username: "ci@example.com", Role: "user"},
+		},
+	}
+	c := &Core{store: store}
+
+	create := func(expiresAt *time.Time) string {
+		_, token, err := c.CreateAPIToken(context.Background(), userID.String(), "ci", expiresAt)
+		if err != nil {
+			t.Fatalf("CreateAPIToken() error = %v", err)
+		}
+		return token
+	}
+	expiry := now.Add(time.Hour)
+	forever := create(nil)
+	expiring := create(&expiry)
+
+	// Tokens can't be created with an expiry in the past, so the expired one is stored directly
+	expired := APITokenPrefix + "expired"
+	store.tokens[hashAPIToken(expired)] = repo.CreateAPITokenParams{
+		UserUuid:  userID,
+		TokenHash: hashAPIToken(expired),
+		ExpiresAt: sql.NullTime{Time: now.Add(-time.Second), Valid: true},
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{"token without expiry", forever, false},
+		{"token before expiry", expiring, false},
+		{"expired token", expired, true},
+		{"unknown token", APITokenPrefix + "unknown", true},
+		{"hash of a token", APITokenPrefix + hashAPIToken(forever), true},
+		{"token without prefix", strings.TrimPrefix(forever, APITokenPrefix), true},
+		{"executor token", ExecutorTokenPrefix + strings.TrimPrefix(forever, APITokenPrefix), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := c.ValidateAPIToken(context.Background(), tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateAPIToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && u.ID != userID.String() {
+				t.Errorf("ValidateAPIToken() user = %s, want %s", u.ID, userID)
+			}
+		})
+	}
+}
@@ -0,0 +1,245 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/cvhariharan/flowctl/internal/repo"
+	"github.com/google/uuid"
+)
+
+// CreateApprovalDelegation lets the delegator hand over their approval rights in the namespace
+// to a user or group between StartsAt and EndsAt. The delegator must be able to approve
+// requests in the namespace without relying on another delegation.
+func (c *Core) CreateApprovalDelegation(ctx context.Context, d models.ApprovalDelegation, namespaceID string) (models.ApprovalDelegation, error) {
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return models.ApprovalDelegation{}, fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	delegatorUUID, err := uuid.Parse(d.DelegatorID)
+	if err != nil {
+		return models.ApprovalDelegation{}, fmt.Errorf("invalid delegator UUID: %w", err)
+	}
+
+	delegateUUID, err := uuid.Parse(d.DelegateID)
+	if err != nil {
+		return models.ApprovalDelegation{}, fmt.Errorf("invalid delegate UUID: %w", err)
+	}
+
+	if !d.EndsAt.After(d.StartsAt) {
+		return models.ApprovalDelegation{}, errors.New("delegation must end after it starts")
+	}
+
+	if !d.EndsAt.After(time.Now()) {
+		return models.ApprovalDelegation{}, errors.New("delegation end time is in the past")
+	}
+
+	if d.DelegateType == "user" && d.DelegateID == d.DelegatorID {
+		return models.ApprovalDelegation{}, errors.New("cannot delegate approvals to yourself")
+	}
+
+	allowed, err := c.checkDirectPermission(ctx, d.DelegatorID, NamespaceDomain(namespaceID), models.ResourceApproval, models.RBACActionApprove)
+	if err != nil {
+		return models.ApprovalDelegation{}, err
+	}
+	if !allowed {
+		return models.ApprovalDelegation{}, errors.New("only users who can approve requests can delegate approvals")
+	}
+
+	params := repo.CreateApprovalDelegationParams{
+		NamespaceUuid: namespaceUUID,
+		DelegatorUuid: delegatorUUID,
+		StartsAt:      d.StartsAt,
+		EndsAt:        d.EndsAt,
+		Reason:        d.Reason,
+	}
+
+	switch d.DelegateType {
+	case "user":
+		delegate, err := c.store.GetUserByUUID(ctx, delegateUUID)
+		if err != nil {
+			return models.ApprovalDelegation{}, fmt.Errorf("could not find delegate user: %w", err)
+		}
+		d.DelegateName = delegate.Name
+		params.DelegateUserUuid = uuid.NullUUID{UUID: delegateUUID, Valid: true}
+	case "group":
+		delegate, err := c.store.GetGroupByUUID(ctx, delegateUUID)
+		if err != nil {
+			return models.ApprovalDelegation{}, fmt.Errorf("could not find delegate group: %w", err)
+		}
+		d.DelegateName = delegate.Name
+		params.DelegateGroupUuid = uuid.NullUUID{UUID: delegateUUID, Valid: true}
+	default:
+		return models.ApprovalDelegation{}, fmt.Errorf("invalid delegate type: %s", d.DelegateType)
+	}
+
+	member, err := c.isNamespaceMember(ctx, d.DelegateType, delegateUUID, namespaceID)
+	if err != nil {
+		return models.ApprovalDelegation{}, fmt.Errorf("could not check namespace membership of the delegate: %w", err)
+	}
+	if !member {
+		return models.ApprovalDelegation{}, errors.New("approvals can only be delegated to members of the namespace")
+	}
+
+	created, err := c.store.CreateApprovalDelegation(ctx, params)
+	if err != nil {
+		return models.ApprovalDelegation{}, fmt.Errorf("could not create approval delegation: %w", err)
+	}
+
+	d.ID = created.Uuid.String()
+	d.CreatedAt = created.CreatedAt
+	return d, nil
+}
+
+// isNamespaceMember reports whether the user or group has a role in the namespace. Users can also
+// be members through one of their groups, superusers are members of every namespace.
+func (c *Core) isNamespaceMember(ctx context.Context, subjectType string, subjectUUID uuid.UUID, namespaceID string) (bool, error) {
+	domain := NamespaceDomain(namespaceID)
+	if subjectType == "group" {
+		return len(c.enforcer.GetRolesForUserInDomain("group:"+subjectUUID.String(), domain)) > 0, nil
+	}
+
+	user, err := c.store.GetUserByUUID(ctx, subjectUUID)
+	if err != nil {
+		return false, err
+	}
+	if user.Role == "superuser" {
+		return true, nil
+	}
+	if len(c.enforcer.GetRolesForUserInDomain("user:"+subjectUUID.String(), domain)) > 0 {
+		return true, nil
+	}
+
+	groups, err := c.store.GetUserGroups(ctx, subjectUUID)
+	if err != nil {
+		return false, err
+	}
+	for _, g := range groups {
+		if len(c.enforcer.GetRolesForUserInDomain("group:"+g.Uuid.String(), domain)) > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ListApprovalDelegations returns the current and upcoming delegations in a namespace
+func (c *Core) ListApprovalDelegations(ctx context.Context, namespaceID string) ([]models.ApprovalDelegation, error) {
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	rows, err := c.store.ListApprovalDelegations(ctx, namespaceUUID)
+	if err != nil {
+		return nil, fmt.Errorf("could not list approval delegations: %w", err)
+	}
+
+	delegations := make([]models.ApprovalDelegation, 0, len(rows))
+	for _, row := range rows {
+		d := models.ApprovalDelegation{
+			ID:            row.Uuid.String(),
+			DelegatorID:   row.DelegatorUuid.String(),
+			DelegatorName: row.DelegatorName,
+			StartsAt:      row.StartsAt,
+			EndsAt:        row.EndsAt,
+			Reason:        row.Reason,
+			CreatedAt:     row.CreatedAt,
+		}
+		if row.DelegateUserUuid.Valid {
+			d.DelegateType = "user"
+			d.DelegateID = row.DelegateUserUuid.UUID.String()
+			d.DelegateName = row.DelegateUserName.String
+		} else {
+			d.DelegateType = "group"
+			d.DelegateID = row.DelegateGroupUuid.UUID.String()
+			d.DelegateName = row.DelegateGroupName.String
+		}
+		delegations = append(delegations, d)
+	}
+
+	return delegations, nil
+}
+
+// DeleteApprovalDelegation removes a delegation. Only the delegator can remove it.
+func (c *Core) DeleteApprovalDelegation(ctx context.Context, delegationID, userID, namespaceID string) error {
+	delegationUUID, err := uuid.Parse(delegationID)
+	if err != nil {
+		return fmt.Errorf("invalid delegation UUID: %w", err)
+	}
+
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user UUID: %w", err)
+	}
+
+	_, err = c.store.DeleteApprovalDelegation(ctx, repo.DeleteApprovalDelegationParams{
+		Uuid:   delegationUUID,
+		Uuid_2: namespaceUUID,
+		Uuid_3: userUUID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.New("approval delegation not found or not owned by user")
+		}
+		return fmt.Errorf("could not delete approval delegation: %w", err)
+	}
+
+	return nil
+}
+
+// checkDelegatedPermission checks whether any user who has currently delegated their approval
+// rights in the namespace to userID holds the permission themselves.
+func (c *Core) checkDelegatedPermission(ctx context.Context, userUUID uuid.UUID, domain string, action models.RBACAction) (bool, error) {
+	namespaceID, ok := namespaceFromDomain(domain)
+	if !ok {
+		return false, nil
+	}
+
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return false, nil
+	}
+
+	delegators, err := c.store.GetActiveDelegatorsForUser(ctx, repo.GetActiveDelegatorsForUserParams{
+		Uuid:   namespaceUUID,
+		Uuid_2: userUUID,
+	})
+	if err != nil {
+		return false, fmt.Errorf("could not get approval delegations: %w", err)
+	}
+
+	for _, delegator := range delegators {
+		allowed, err := c.checkDirectPermission(ctx, delegator.String(), domain, models.ResourceApproval, action)
+		if err != nil {
+			return false, err
+		}
+		if allowed {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// ResolveDelegateEmails returns the email addresses of users currently acting on behalf of
+// the given users in the namespace. This implements the messengers.DelegateResolver interface.
+func (c *Core) ResolveDelegateEmails(ctx context.Context, namespace string, emails []string) ([]string, error) {
+	if len(emails) == 0 {
+		return nil, nil
+	}
+
+	return c.store.GetActiveDelegateEmails(ctx, repo.GetActiveDelegateEmailsParams{
+		Name:    namespace,
+		Column2: emails,
+	})
+}
@@ -0,0 +1,249 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/casbin/casbin/v2"
+	casbin_model "github.com/casbin/casbin/v2/model"
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/cvhariharan/flowctl/internal/repo"
+	"github.com/google/uuid"
+)
+
+// delegationStore keeps the users, groups and delegations used by the permission checks
+type delegationStore struct {
+	repo.Store
+	now         time.Time
+	users       map[uuid.UUID]repo.User
+	groups      map[uuid.UUID][]repo.Group
+	delegations map[uuid.UUID][]models.ApprovalDelegation
+	created     []repo.CreateApprovalDelegationParams
+}
+
+func (s *delegationStore) GetUserByUUID(ctx context.Context, id uuid.UUID) (repo.User, error) {
+	u, ok := s.users[id]
+	if !ok {
+		return repo.User{}, sql.ErrNoRows
+	}
+	return u, nil
+}
+
+func (s *delegationStore) GetGroupByUUID(ctx context.Context, id uuid.UUID) (repo.Group, error) {
+	for _, groups := range s.groups {
+		for _, g := range groups {
+			if g.Uuid == id {
+				return g, nil
+			}
+		}
+	}
+	return repo.Group{}, sql.ErrNoRows
+}
+
+func (s *delegationStore) GetUserGroups(ctx context.Context, id uuid.UUID) ([]repo.Group, error) {
+	return s.groups[id], nil
+}
+
+// GetActiveDelegatorsForUser returns the delegators of the delegations in the namespace that are
+// active now and made to the user or one of their groups
+func (s *delegationStore) GetActiveDelegatorsForUser(ctx context.Context, arg repo.GetActiveDelegatorsForUserParams) ([]uuid.UUID, error) {
+	delegates := map[string]bool{arg.Uuid_2.String(): true}
+	for _, g := range s.groups[arg.Uuid_2] {
+		delegates[g.Uuid.String()] = true
+	}
+
+	var delegators []uuid.UUID
+	for _, d := range s.delegations[arg.Uuid] {
+		if d.IsActive(s.now) && delegates[d.DelegateID] {
+			delegators = append(delegators, uuid.MustParse(d.DelegatorID))
+		}
+	}
+	return delegators, nil
+}
+
+func (s *delegationStore) CreateApprovalDelegation(ctx context.Context, arg repo.CreateApprovalDelegationParams) (repo.ApprovalDelegation, error) {
+	s.created = append(s.created, arg)
+	return repo.ApprovalDelegation{Uuid: uuid.New(), StartsAt: arg.StartsAt, EndsAt: arg.EndsAt, CreatedAt: s.now}, nil
+}
+
+func newTestEnforcer(t *testing.T) *casbin.Enforcer {
+	t.Helper()
+	modelContent, err := os.ReadFile("../../configs/rbac_model.conf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := casbin_model.NewModelFromString(string(modelContent))
+	if err != nil {
+		t.Fatal(err)
+	}
+	enforcer, err := casbin.NewEnforcer(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return enforcer
+}
+
+type delegationFixture struct {
+	core      *Core
+	store     *delegationStore
+	namespace uuid.UUID
+	users     map[string]uuid.UUID
+}
+
+// newDelegationFixture sets up a namespace where only the approver can approve requests and the
+// other users depend on delegations from the approver or from users who can't approve themselves
+func newDelegationFixture(t *testing.T) delegationFixture {
+	t.Helper()
+	now := time.Now()
+	namespace, otherNamespace := uuid.New(), uuid.New()
+	users := make(map[string]uuid.UUID)
+	store := &delegationStore{
+		now:         now,
+		users:       make(map[uuid.UUID]repo.User),
+		groups:      make(map[uuid.UUID][]repo.Group),
+		delegations: make(map[uuid.UUID][]models.ApprovalDelegation),
+	}
+	for _, name := range []string{"approver", "user", "delegate", "group_member", "expired", "upcoming", "other_namespace", "chained", "from_user", "outsider"} {
+		id := uuid.New()
+		users[name] = id
+		store.users[id] = repo.User{Uuid: id, Name: name, Username: name + "@example.com", Role: "user"}
+	}
+	group := repo.Group{Uuid: uuid.New(), Name: "oncall"}
+	store.groups[users["group_member"]] = []repo.Group{group}
+
+	delegate := func(ns uuid.UUID, delegator, delegateID string, startsAt, endsAt time.Time) {
+		store.delegations[ns] = append(store.delegations[ns], models.ApprovalDelegation{
+			DelegatorID: delegator,
+			DelegateID:  delegateID,
+			StartsAt:    startsAt,
+			EndsAt:      endsAt,
+		})
+	}
+	approver := users["approver"].String()
+	delegate(namespace, approver, users["delegate"].String(), now.Add(-time.Hour), now.Add(time.Hour))
+	delegate(namespace, approver, group.Uuid.String(), now.Add(-time.Hour), now.Add(time.Hour))
+	delegate(namespace, approver, users["expired"].String(), now.Add(-2*time.Hour), now.Add(-time.Hour))
+	delegate(namespace, approver, users["upcoming"].String(), now.Add(time.Hour), now.Add(2*time.Hour))
+	delegate(otherNamespace, approver, users["other_namespace"].String(), now.Add(-time.Hour), now.Add(time.Hour))
+	delegate(namespace, users["delegate"].String(), users["chained"].String(), now.Add(-time.Hour), now.Add(time.Hour))
+	delegate(namespace, users["user"].String(), users["from_user"].String(), now.Add(-time.Hour), now.Add(time.Hour))
+
+	enforcer := newTestEnforcer(t)
+	for _, ns := range []uuid.UUID{namespace, otherNamespace} {
+		if _, err := enforcer.AddPolicy("user:"+approver, NamespaceDomain(ns.String()), string(models.ResourceApproval), string(models.RBACActionApprove)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Everyone except the outsider has a role in the namespace of the delegation they are tested with
+	member := func(subject string, ns uuid.UUID) {
+		if _, err := enforcer.AddGroupingPolicy(subject, "role:user", NamespaceDomain(ns.String())); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, name := range []string{"user", "delegate", "expired", "upcoming", "chained", "from_user"} {
+		member("user:"+users[name].String(), namespace)
+	}
+	member("group:"+group.Uuid.String(), namespace)
+	member("user:"+users["other_namespace"].String(), otherNamespace)
+
+	return delegationFixture{
+		core:      &Core{store: store, enforcer: enforcer},
+		store:     store,
+		namespace: namespace,
+		users:     users,
+	}
+}
+
+func TestCheckPermission_ApprovalDelegation(t *testing.T) {
+	f := newDelegationFixture(t)
+	domain := NamespaceDomain(f.namespace.String())
+
+	tests := []struct {
+		name     string
+		user     string
+		resource models.Resource
+		action   models.RBACAction
+		want     bool
+	}{
+		{"approver", "approver", models.ResourceApproval, models.RBACActionApprove, true},
+		{"user without delegation", "user", models.ResourceApproval, models.RBACActionApprove, false},
+		{"delegate", "delegate", models.ResourceApproval, models.RBACActionApprove, true},
+		{"delegate through a group", "group_member", models.ResourceApproval, models.RBACActionApprove, true},
+		{"expired delegation", "expired", models.ResourceApproval, models.RBACActionApprove, false},
+		{"delegation that hasn't started", "upcoming", models.ResourceApproval, models.RBACActionApprove, false},
+		{"delegation in another namespace", "other_namespace", models.ResourceApproval, models.RBACActionApprove, false},
+		{"delegations are not chained", "chained", models.ResourceApproval, models.RBACActionApprove, false},
+		{"delegator who can't approve", "from_user", models.ResourceApproval, models.RBACActionApprove, false},
+		{"only approvals are delegated", "delegate", models.ResourceFlow, models.RBACActionExecute, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := f.core.CheckPermission(context.Background(), f.users[tt.user].String(), domain, tt.resource, tt.action)
+			if err != nil {
+				t.Fatalf("CheckPermission() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("CheckPermission() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateApprovalDelegation(t *testing.T) {
+	f := newDelegationFixture(t)
+	now := time.Now()
+
+	tests := []struct {
+		name      string
+		delegator string
+		delegate  string
+		startsAt  time.Time
+		endsAt    time.Time
+		wantErr   string
+	}{
+		{"valid", "approver", "user", now, now.Add(time.Hour), ""},
+		{"ends before it starts", "approver", "user", now.Add(time.Hour), now, "must end after it starts"},
+		{"ended", "approver", "user", now.Add(-2 * time.Hour), now.Add(-time.Hour), "in the past"},
+		{"to themselves", "approver", "approver", now, now.Add(time.Hour), "yourself"},
+		{"delegator who can't approve", "user", "delegate", now, now.Add(time.Hour), "only users who can approve"},
+		{"delegator who can only approve through a delegation", "delegate", "user", now, now.Add(time.Hour), "only users who can approve"},
+		{"delegate who is a member through a group", "approver", "group_member", now, now.Add(time.Hour), ""},
+		{"delegate who is not a member", "approver", "outsider", now, now.Add(time.Hour), "members of the namespace"},
+		{"delegate who is a member of another namespace", "approver", "other_namespace", now, now.Add(time.Hour), "members of the namespace"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f.store.created = nil
+			_, err := f.core.CreateApprovalDelegation(context.Background(), models.ApprovalDelegation{
+				DelegatorID:  f.users[tt.delegator].String(),
+				DelegateType: "user",
+				DelegateID:   f.users[tt.delegate].String(),
+				StartsAt:     tt.startsAt,
+				EndsAt:       tt.endsAt,
+			}, f.namespace.String())
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("CreateApprovalDelegation() error = %v", err)
+				}
+				if len(f.store.created) != 1 {
+					t.Errorf("CreateApprovalDelegation() stored %d delegations, want 1", len(f.store.created))
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("CreateApprovalDelegation() error = %v, want %q", err, tt.wantErr)
+			}
+			if len(f.store.created) != 0 {
+				t.Errorf("CreateApprovalDelegation() stored a delegation after an error")
+			}
+		})
+	}
+}
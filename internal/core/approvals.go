@@ -5,23 +5,29 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"log"
 	"time"
 
 	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/cvhariharan/flowctl/internal/eventbus"
+	"github.com/cvhariharan/flowctl/internal/messengers"
 	"github.com/cvhariharan/flowctl/internal/repo"
+	"github.com/cvhariharan/flowctl/internal/scheduler"
 	"github.com/google/uuid"
 )
 
 var (
 	ErrNoPendingApproval = errors.New("no pending approval")
 	ErrNil               = errors.New("not found")
+	ErrNotApprover       = errors.New("user is not an approver of the action")
 )
 
 // ApproveOrRejectAction handles approval or rejection of an action request by a user.
-// It takes the approval UUID, the ID of the user making the decision, and the approval status.
+// It takes the approval UUID, the ID of the user making the decision, the approval status and
+// a comment justifying the decision. A comment is mandatory when rejecting.
 // The function updates the database with the decision.
 // Once approved, the task is moved to a resume queue for further processing.
-func (c *Core) ApproveOrRejectAction(ctx context.Context, approvalUUID, decidedBy string, status models.ApprovalType, namespaceID string) error {
+func (c *Core) ApproveOrRejectAction(ctx context.Context, approvalUUID, decidedBy string, status models.ApprovalType, comment string, namespaceID string) error {
 	var err error
 	uid, err := uuid.Parse(approvalUUID)
 	if err != nil {
@@ -37,6 +43,14 @@ func (c *Core) ApproveOrRejectAction(ctx context.Context, approvalUUID, decidedB
 		return fmt.Errorf("request has already been processed")
 	}
 
+	if status == models.ApprovalStatusRejected && comment == "" {
+		return fmt.Errorf("a comment is required when rejecting a request")
+	}
+
+	if err := c.checkApprover(ctx, areq, decidedBy, namespaceID); err != nil {
+		return err
+	}
+
 	userid, err := uuid.Parse(decidedBy)
 	if err != nil {
 		return fmt.Errorf("decidedby UUID is not a UUID: %w", err)
@@ -54,7 +68,7 @@ func (c *Core) ApproveOrRejectAction(ctx context.Context, approvalUUID, decidedB
 
 	var cancellationNote string
 	if status == models.ApprovalStatusRejected {
-		cancellationNote = fmt.Sprintf("Flow execution cancelled due to approval rejection by %s", user.Name)
+		cancellationNote = fmt.Sprintf("Flow execution cancelled due to approval rejection by %s: %s", user.Name, comment)
 	}
 
 	// Process approval decision
@@ -64,11 +78,18 @@ func (c *Core) ApproveOrRejectAction(ctx context.Context, approvalUUID, decidedB
 		DecidedByUserID:  user.ID,
 		Status:           repo.ApprovalStatus(status),
 		CancellationNote: cancellationNote,
+		Comment:          comment,
 	})
 	if err != nil {
 		return fmt.Errorf("could not process approval decision for %s: %w", approvalUUID, err)
 	}
 
+	if c.Metrics != nil {
+		if f, err := c.GetFlowFromLogID(result.ExecID, namespaceID); err == nil {
+			c.Metrics.ObserveApprovalWait(namespaceID, f.Meta.ID, string(result.Status), result.DecidedAt.Sub(result.CreatedAt))
+		}
+	}
+
 	approval := models.ApprovalRequest{
 		UUID:        result.Uuid.String(),
 		Status:      models.ApprovalType(result.Status),
@@ -77,16 +98,128 @@ func (c *Core) ApproveOrRejectAction(ctx context.Context, approvalUUID, decidedB
 		RequestedBy: result.RequestedBy,
 	}
 
+	eventType := eventbus.TypeApprovalApproved
+	if status == models.ApprovalStatusRejected {
+		eventType = eventbus.TypeApprovalRejected
+	}
+	c.publishApprovalEvent(eventType, approval, decidedBy, namespaceID, map[string]any{"comment": comment})
+
 	// If approved, move to resume queue
 	if status == models.ApprovalStatusApproved {
 		if err := c.ResumeFlowExecution(ctx, result.ExecID, approval.ActionID, decidedBy, namespaceID, true); err != nil {
 			return fmt.Errorf("could not resume task %s: %w", result.ExecID, err)
 		}
+		return nil
+	}
+
+	// A rejection cancels the execution without going through the scheduler, so
+	// the cancellation notifications are queued here
+	if err := c.queueRejectionNotifications(ctx, result.ExecID, cancellationNote, &messengers.ApprovalDecision{
+		ActionID:  result.ActionID,
+		Status:    string(result.Status),
+		DecidedBy: user.Name,
+		Comment:   comment,
+	}, namespaceID); err != nil {
+		log.Printf("could not queue rejection notifications for exec %s: %v", result.ExecID, err)
 	}
 
 	return nil
 }
 
+// checkApprover returns ErrNotApprover if the action of the approval request restricts who can
+// approve it and the user is not one of its approvers, directly or through an active delegation
+func (c *Core) checkApprover(ctx context.Context, areq models.ApprovalRequest, userID string, namespaceID string) error {
+	f, err := c.GetFlowFromLogID(areq.ExecID, namespaceID)
+	if err != nil {
+		return err
+	}
+
+	idx, err := f.GetActionIndexByID(areq.ActionID)
+	if err != nil {
+		return err
+	}
+	action := f.Actions[idx]
+	if len(action.Approvers) == 0 {
+		return nil
+	}
+
+	ok, err := c.isApprover(ctx, action, userID)
+	if err != nil || ok {
+		return err
+	}
+
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user UUID: %w", err)
+	}
+
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	delegators, err := c.store.GetActiveDelegatorsForUser(ctx, repo.GetActiveDelegatorsForUserParams{
+		Uuid:   namespaceUUID,
+		Uuid_2: userUUID,
+	})
+	if err != nil {
+		return fmt.Errorf("could not get approval delegations: %w", err)
+	}
+
+	for _, delegator := range delegators {
+		ok, err := c.isApprover(ctx, action, delegator.String())
+		if err != nil || ok {
+			return err
+		}
+	}
+
+	return ErrNotApprover
+}
+
+// isApprover reports whether the user is one of the approvers of the action
+func (c *Core) isApprover(ctx context.Context, action models.Action, userID string) (bool, error) {
+	user, err := c.GetUserWithUUIDWithGroups(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	if user.Role == models.SuperuserUserRole {
+		return true, nil
+	}
+
+	groups := make([]string, 0, len(user.Groups))
+	for _, g := range user.Groups {
+		groups = append(groups, g.Name)
+	}
+	return action.IsApprover(user.Username, groups), nil
+}
+
+func (c *Core) queueRejectionNotifications(ctx context.Context, execID string, note string, decision *messengers.ApprovalDecision, namespaceID string) error {
+	f, err := c.GetFlowFromLogID(execID, namespaceID)
+	if err != nil {
+		return err
+	}
+
+	sf, err := c.GetSchedulerFlow(ctx, f.Meta.ID, namespaceID)
+	if err != nil {
+		return err
+	}
+
+	// Notifications may come from the namespace defaults
+	if len(sf.Notify) == 0 {
+		return nil
+	}
+
+	return scheduler.QueueNotifications(ctx, c.scheduler, sf, scheduler.NotificationPayload{
+		FlowID:      sf.Meta.ID,
+		FlowName:    sf.Meta.Name,
+		ExecID:      execID,
+		Status:      string(repo.ExecutionStatusCancelled),
+		Error:       note,
+		NamespaceID: namespaceID,
+		Approval:    decision,
+	})
+}
+
 func (c *Core) RequestApproval(ctx context.Context, execID string, action models.Action, namespaceID string) (string, error) {
 	namespaceUUID, err := uuid.Parse(namespaceID)
 	if err != nil {
@@ -111,6 +244,14 @@ func (c *Core) RequestApproval(ctx context.Context, execID string, action models
 		return "", err
 	}
 
+	c.publishApprovalEvent(eventbus.TypeApprovalRequested, models.ApprovalRequest{
+		UUID:        areq.Uuid.String(),
+		ActionID:    areq.ActionID,
+		Status:      models.ApprovalType(areq.Status),
+		ExecID:      execID,
+		RequestedBy: areq.RequestedBy,
+	}, "", namespaceID, nil)
+
 	return areq.Uuid.String(), nil
 }
 
@@ -216,12 +357,20 @@ func (c *Core) GetApprovalWithInputs(ctx context.Context, approvalUUID string, n
 			RequestedBy: approval.RequestedBy,
 		},
 		DecidedBy: approval.DecidedByName.String,
-		Inputs:    approval.ExecInputs,
+		Comment:   approval.Comment,
+		Inputs:    c.maskInputJSON(approval.ExecInputs, approval.FlowSlug, namespaceID),
 		FlowName:  approval.FlowName,
 		FlowID:    approval.FlowSlug,
 		CreatedAt: approval.CreatedAt.Format(time.RFC3339),
 		UpdatedAt: approval.UpdatedAt.Format(time.RFC3339),
 	}
+	if f, err := c.GetFlowByID(approval.FlowSlug, namespaceID); err == nil {
+		details.Assignees = f.Meta.Owners
+		// Only the approvers of the action can decide on it
+		if idx, err := f.GetActionIndexByID(approval.ActionID); err == nil && len(f.Actions[idx].Approvers) > 0 {
+			details.Assignees = f.Actions[idx].Approvers
+		}
+	}
 
 	return details, nil
 }
@@ -0,0 +1,157 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/cvhariharan/flowctl/internal/repo"
+	"github.com/google/uuid"
+)
+
+// RunExecutionArchiver archives the executions that finished longer than after ago, every
+// interval, until ctx is cancelled
+func (c *Core) RunExecutionArchiver(ctx context.Context, after time.Duration, interval time.Duration, batchSize int) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := c.ArchiveExecutions(ctx, time.Now().Add(-after), batchSize)
+		if err != nil {
+			log.Printf("failed to archive executions: %v", err)
+		} else if n > 0 {
+			log.Printf("archived %d executions", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ArchiveExecutions moves the executions that finished before the given time from execution_log
+// into execution_archive, batchSize at a time. Only a summary of each execution is kept, its
+// progress, timeline and telemetry are removed. It returns the number of archived executions.
+func (c *Core) ArchiveExecutions(ctx context.Context, before time.Time, batchSize int) (int64, error) {
+	var total int64
+	for {
+		n, err := c.store.ArchiveExecutions(ctx, repo.ArchiveExecutionsParams{
+			Before:    before,
+			BatchSize: int32(batchSize),
+		})
+		if err != nil {
+			return total, fmt.Errorf("could not archive executions: %w", err)
+		}
+		total += n
+
+		if n < int64(batchSize) || ctx.Err() != nil {
+			return total, nil
+		}
+	}
+}
+
+// GetArchivedExecutionsPaginated lists the archived executions of a namespace, newest first.
+// If triggeredBy is set, only the executions triggered by that user are returned.
+func (c *Core) GetArchivedExecutionsPaginated(ctx context.Context, namespaceID string, triggeredBy string, filter string, limit, offset int) ([]models.ExecutionSummary, int64, int64, error) {
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	var triggeredByUUID uuid.NullUUID
+	if triggeredBy != "" {
+		id, err := uuid.Parse(triggeredBy)
+		if err != nil {
+			return nil, 0, 0, fmt.Errorf("invalid user UUID: %w", err)
+		}
+		triggeredByUUID = uuid.NullUUID{UUID: id, Valid: true}
+	}
+
+	execs, err := c.store.ListArchivedExecutionsPaginated(ctx, repo.ListArchivedExecutionsPaginatedParams{
+		NamespaceUuid: namespaceUUID,
+		TriggeredBy:   triggeredByUUID,
+		Filter:        filter,
+		Limit:         int32(limit),
+		Offset:        int32(offset),
+	})
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("could not get archived executions: %w", err)
+	}
+
+	m := make([]models.ExecutionSummary, 0, len(execs))
+	var pageCount, totalCount int64
+	for _, v := range execs {
+		m = append(m, c.archivedExecutionSummary(repo.ExecutionArchive{
+			ExecID:          v.ExecID,
+			NamespaceID:     v.NamespaceID,
+			FlowSlug:        v.FlowSlug,
+			FlowName:        v.FlowName,
+			Status:          v.Status,
+			TriggerType:     v.TriggerType,
+			TriggeredByUuid: v.TriggeredByUuid,
+			TriggeredByName: v.TriggeredByName,
+			Input:           v.Input,
+			Error:           v.Error,
+			CurrentActionID: v.CurrentActionID,
+			ActionRetries:   v.ActionRetries,
+			CreatedAt:       v.CreatedAt,
+			StartedAt:       v.StartedAt,
+			CompletedAt:     v.CompletedAt,
+			ScheduledAt:     v.ScheduledAt,
+			ArchivedAt:      v.ArchivedAt,
+		}, namespaceID))
+		pageCount = v.PageCount
+		totalCount = v.TotalCount
+	}
+
+	return m, pageCount, totalCount, nil
+}
+
+// GetArchivedExecution returns the summary of an archived execution
+func (c *Core) GetArchivedExecution(ctx context.Context, execID string, namespaceID string) (models.ExecutionSummary, error) {
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return models.ExecutionSummary{}, fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	e, err := c.store.GetArchivedExecution(ctx, repo.GetArchivedExecutionParams{
+		ExecID: execID,
+		Uuid:   namespaceUUID,
+	})
+	if err != nil {
+		return models.ExecutionSummary{}, fmt.Errorf("could not get archived execution %s: %w", execID, err)
+	}
+
+	return c.archivedExecutionSummary(e, namespaceID), nil
+}
+
+func (c *Core) archivedExecutionSummary(e repo.ExecutionArchive, namespaceID string) models.ExecutionSummary {
+	actionRetries := make(map[string]int)
+	if e.ActionRetries.Valid {
+		if err := json.Unmarshal(e.ActionRetries.RawMessage, &actionRetries); err != nil {
+			log.Printf("failed to unmarshal action_retries: %v", err)
+		}
+	}
+
+	return models.ExecutionSummary{
+		ExecID:          e.ExecID,
+		FlowName:        e.FlowName,
+		FlowID:          e.FlowSlug,
+		Status:          models.ExecutionStatus(e.Status),
+		Input:           c.maskInputJSON(e.Input, e.FlowSlug, namespaceID),
+		TriggerType:     string(e.TriggerType),
+		TriggeredByName: e.TriggeredByName,
+		TriggeredByID:   e.TriggeredByUuid.String(),
+		CurrentActionID: e.CurrentActionID.String,
+		CreatedAt:       e.CreatedAt,
+		StartedAt:       e.StartedAt.Time,
+		CompletedAt:     e.CompletedAt.Time,
+		ScheduledAt:     e.ScheduledAt.Time,
+		ActionRetries:   actionRetries,
+	}
+}
@@ -0,0 +1,107 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/cvhariharan/flowctl/internal/artifactstore"
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/cvhariharan/flowctl/internal/repo"
+	"github.com/google/uuid"
+)
+
+// ArtifactURLExpiry is how long the signed URLs that artifacts are downloaded from are valid
+const ArtifactURLExpiry = 5 * time.Minute
+
+// ErrArtifactNotFound is returned when an execution has no artifact with the given ID
+var ErrArtifactNotFound = errors.New("artifact not found")
+
+// ArtifactDownload is where an artifact is downloaded from. URL is a signed URL of the archive,
+// when the archive can't sign URLs the file is read from Reader instead.
+type ArtifactDownload struct {
+	Artifact models.Artifact
+	URL      string
+	Reader   io.ReadCloser
+}
+
+// ListArtifacts returns the files published by the actions of an execution, in the order they
+// were archived
+func (c *Core) ListArtifacts(ctx context.Context, execID string, namespaceID string) ([]models.Artifact, error) {
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	rows, err := c.store.ListExecutionArtifacts(ctx, repo.ListExecutionArtifactsParams{
+		ExecID: execID,
+		Uuid:   namespaceUUID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not get artifacts of exec %s: %w", execID, err)
+	}
+
+	artifacts := make([]models.Artifact, 0, len(rows))
+	for _, r := range rows {
+		artifacts = append(artifacts, repoArtifactToArtifact(r))
+	}
+	return artifacts, nil
+}
+
+// DownloadArtifact returns where an artifact of an execution is downloaded from. The caller
+// closes the reader of the download if it is set.
+func (c *Core) DownloadArtifact(ctx context.Context, execID string, artifactID string, namespaceID string) (ArtifactDownload, error) {
+	if c.Archive == nil {
+		return ArtifactDownload{}, errors.New("no artifact archive is configured")
+	}
+
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return ArtifactDownload{}, fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+	artifactUUID, err := uuid.Parse(artifactID)
+	if err != nil {
+		return ArtifactDownload{}, ErrArtifactNotFound
+	}
+
+	r, err := c.store.GetExecutionArtifact(ctx, repo.GetExecutionArtifactParams{
+		Uuid:   artifactUUID,
+		ExecID: execID,
+		Uuid_2: namespaceUUID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return ArtifactDownload{}, ErrArtifactNotFound
+	}
+	if err != nil {
+		return ArtifactDownload{}, fmt.Errorf("could not get artifact %s of exec %s: %w", artifactID, execID, err)
+	}
+
+	download := ArtifactDownload{Artifact: repoArtifactToArtifact(r)}
+	download.URL, err = c.Archive.SignedURL(ctx, r.ObjectKey, ArtifactURLExpiry)
+	if err == nil {
+		return download, nil
+	}
+	if !errors.Is(err, artifactstore.ErrNoSignedURL) {
+		return ArtifactDownload{}, err
+	}
+
+	download.Reader, err = c.Archive.NewReader(ctx, r.ObjectKey)
+	if err != nil {
+		return ArtifactDownload{}, err
+	}
+	return download, nil
+}
+
+func repoArtifactToArtifact(a repo.Artifact) models.Artifact {
+	return models.Artifact{
+		ID:        a.Uuid.String(),
+		ExecID:    a.ExecID,
+		ActionID:  a.ActionID,
+		Name:      a.Name,
+		Size:      a.Size,
+		CreatedAt: a.CreatedAt,
+	}
+}
@@ -0,0 +1,121 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/cvhariharan/flowctl/internal/repo"
+	"github.com/google/uuid"
+	"github.com/sqlc-dev/pqtype"
+)
+
+// RecordAudit records a change in the audit log. The snapshots of the resource are stored as JSON.
+func (c *Core) RecordAudit(ctx context.Context, e models.AuditEntry) error {
+	actorUUID, err := uuid.Parse(e.ActorID)
+	if err != nil {
+		return fmt.Errorf("invalid actor UUID: %w", err)
+	}
+
+	var namespaceUUID uuid.NullUUID
+	if e.NamespaceID != "" {
+		id, err := uuid.Parse(e.NamespaceID)
+		if err != nil {
+			return fmt.Errorf("invalid namespace UUID: %w", err)
+		}
+		namespaceUUID = uuid.NullUUID{UUID: id, Valid: true}
+	}
+
+	before, err := auditSnapshot(e.Before)
+	if err != nil {
+		return err
+	}
+	after, err := auditSnapshot(e.After)
+	if err != nil {
+		return err
+	}
+
+	if err := c.store.RecordAuditEntry(ctx, repo.RecordAuditEntryParams{
+		NamespaceUuid: namespaceUUID,
+		ActorUuid:     actorUUID,
+		ActorName:     e.ActorName,
+		Action:        e.Action,
+		ResourceType:  e.ResourceType,
+		ResourceID:    e.ResourceID,
+		Before:        before,
+		After:         after,
+		SourceIp:      e.SourceIP,
+	}); err != nil {
+		return fmt.Errorf("could not record %s of %s %s: %w", e.Action, e.ResourceType, e.ResourceID, err)
+	}
+	return nil
+}
+
+// ListAuditLog returns the entries of the audit log that match filter, newest first, with the
+// number of pages and entries. The entries of every namespace and the changes outside of
+// namespaces are returned if namespaceID is empty.
+func (c *Core) ListAuditLog(ctx context.Context, namespaceID string, filter string, limit, offset int) ([]models.AuditEntry, int64, int64, error) {
+	var namespaceUUID uuid.NullUUID
+	if namespaceID != "" {
+		id, err := uuid.Parse(namespaceID)
+		if err != nil {
+			return nil, -1, -1, fmt.Errorf("invalid namespace UUID: %w", err)
+		}
+		namespaceUUID = uuid.NullUUID{UUID: id, Valid: true}
+	}
+
+	rows, err := c.store.ListAuditEntriesPaginated(ctx, repo.ListAuditEntriesPaginatedParams{
+		NamespaceUuid: namespaceUUID,
+		Filter:        filter,
+		Limit:         int32(limit),
+		Offset:        int32(offset),
+	})
+	if err != nil {
+		return nil, -1, -1, fmt.Errorf("could not list audit log: %w", err)
+	}
+
+	var pageCount, totalCount int64
+	entries := make([]models.AuditEntry, 0, len(rows))
+	for i, r := range rows {
+		if i == 0 {
+			pageCount = r.PageCount
+			totalCount = r.TotalCount
+		}
+
+		e := models.AuditEntry{
+			ID:           r.ID,
+			ActorID:      r.ActorUuid.String(),
+			ActorName:    r.ActorName,
+			Action:       r.Action,
+			ResourceType: r.ResourceType,
+			ResourceID:   r.ResourceID,
+			SourceIP:     r.SourceIp,
+			CreatedAt:    r.CreatedAt,
+		}
+		if r.NamespaceUuid.Valid {
+			e.NamespaceID = r.NamespaceUuid.UUID.String()
+		}
+		if r.Before.Valid {
+			e.Before = r.Before.RawMessage
+		}
+		if r.After.Valid {
+			e.After = r.After.RawMessage
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, pageCount, totalCount, nil
+}
+
+// auditSnapshot encodes a snapshot of a resource, a nil snapshot is stored as NULL
+func auditSnapshot(v any) (pqtype.NullRawMessage, error) {
+	if v == nil {
+		return pqtype.NullRawMessage{}, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return pqtype.NullRawMessage{}, fmt.Errorf("could not encode audit snapshot: %w", err)
+	}
+	return pqtype.NullRawMessage{RawMessage: b, Valid: true}, nil
+}
@@ -0,0 +1,70 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/cvhariharan/flowctl/internal/repo"
+	"github.com/google/uuid"
+)
+
+// auditStore keeps the entries recorded in the audit log
+type auditStore struct {
+	repo.Store
+	recorded []repo.RecordAuditEntryParams
+}
+
+func (s *auditStore) RecordAuditEntry(ctx context.Context, arg repo.RecordAuditEntryParams) error {
+	s.recorded = append(s.recorded, arg)
+	return nil
+}
+
+func TestRecordAudit(t *testing.T) {
+	store := &auditStore{}
+	c := &Core{store: store}
+	actor := uuid.New()
+	namespace := uuid.New()
+
+	err := c.RecordAudit(context.Background(), models.AuditEntry{
+		NamespaceID:  namespace.String(),
+		ActorID:      actor.String(),
+		ActorName:    "admin@example.com",
+		Action:       models.AuditNodeUpdate,
+		ResourceType: string(models.ResourceNode),
+		ResourceID:   "node-1",
+		Before:       map[string]int{"port": 22},
+		After:        map[string]int{"port": 2222},
+		SourceIP:     "10.0.0.1",
+	})
+	if err != nil {
+		t.Fatalf("RecordAudit() error = %v", err)
+	}
+
+	if len(store.recorded) != 1 {
+		t.Fatalf("recorded %d entries, want 1", len(store.recorded))
+	}
+	got := store.recorded[0]
+	if got.ActorUuid != actor || got.NamespaceUuid != (uuid.NullUUID{UUID: namespace, Valid: true}) {
+		t.Errorf("recorded actor %v in namespace %v", got.ActorUuid, got.NamespaceUuid)
+	}
+	if string(got.Before.RawMessage) != `{"port":22}` || string(got.After.RawMessage) != `{"port":2222}` {
+		t.Errorf("recorded snapshots %s and %s", got.Before.RawMessage, got.After.RawMessage)
+	}
+
+	// Changes outside of namespaces and of resources that didn't exist are stored as NULL
+	if err := c.RecordAudit(context.Background(), models.AuditEntry{
+		ActorID: actor.String(),
+		Action:  models.AuditExecutionTrigger,
+	}); err != nil {
+		t.Fatalf("RecordAudit() error = %v", err)
+	}
+	got = store.recorded[1]
+	if got.NamespaceUuid.Valid || got.Before.Valid || got.After.Valid {
+		t.Errorf("recorded %+v, want NULL namespace and snapshots", got)
+	}
+
+	if err := c.RecordAudit(context.Background(), models.AuditEntry{ActorID: "invalid"}); err == nil {
+		t.Error("RecordAudit() with an invalid actor succeeded")
+	}
+}
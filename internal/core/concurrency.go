@@ -0,0 +1,66 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/cvhariharan/flowctl/internal/repo"
+	"github.com/cvhariharan/flowctl/internal/scheduler"
+	"github.com/google/uuid"
+)
+
+// AcquireExecutionSlot decides whether a flow execution picked from the job queue can run within
+// the max_concurrency of its flow and namespace. Executions that can run take a slot, which is
+// released once the job was handled, including when the execution pauses for an approval or a
+// wait. Executions of flows and namespaces without limits always run.
+func (c *Core) AcquireExecutionSlot(ctx context.Context, job scheduler.Job) (func(), bool, error) {
+	if job.PayloadType != scheduler.PayloadTypeFlowExecution {
+		return nil, true, nil
+	}
+
+	var payload scheduler.FlowExecutionPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return nil, false, fmt.Errorf("failed to unmarshal flow payload: %w", err)
+	}
+
+	namespaceUUID, err := uuid.Parse(payload.NamespaceID)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	// The limits are read when the job is picked, so that changes apply to queued executions
+	var maxFlow int
+	if f, err := c.GetFlowByID(payload.Workflow.Meta.ID, payload.NamespaceID); err == nil {
+		maxFlow = f.Meta.MaxConcurrency
+	} else if !errors.Is(err, ErrFlowNotFound) {
+		return nil, false, err
+	}
+	defaults, err := c.getNamespaceDefaults(ctx, namespaceUUID)
+	if err != nil {
+		return nil, false, err
+	}
+	if maxFlow == 0 && defaults.MaxConcurrency == 0 {
+		return nil, true, nil
+	}
+
+	ok, err := c.store.TakeExecutionSlotTx(ctx, repo.TakeExecutionSlotTxParams{
+		ExecID:            job.ExecID,
+		FlowSlug:          payload.Workflow.Meta.ID,
+		NamespaceUUID:     namespaceUUID,
+		MaxFlowSlots:      int32(maxFlow),
+		MaxNamespaceSlots: int32(defaults.MaxConcurrency),
+	})
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	release := func() {
+		if err := c.store.ReleaseExecutionSlot(context.Background(), job.ExecID); err != nil {
+			log.Printf("failed to release execution slot of exec %s: %v", job.ExecID, err)
+		}
+	}
+	return release, true, nil
+}
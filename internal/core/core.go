@@ -7,10 +7,15 @@ import (
 	"time"
 
 	"github.com/casbin/casbin/v2"
-	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/cvhariharan/flowctl/internal/artifactstore"
+	"github.com/cvhariharan/flowctl/internal/eventbus"
+	"github.com/cvhariharan/flowctl/internal/flowstore"
+	"github.com/cvhariharan/flowctl/internal/messengers"
+	"github.com/cvhariharan/flowctl/internal/metrics"
 	"github.com/cvhariharan/flowctl/internal/repo"
 	"github.com/cvhariharan/flowctl/internal/scheduler"
 	"github.com/cvhariharan/flowctl/internal/streamlogger"
+	"github.com/cvhariharan/flowctl/internal/uploadstore"
 	"gocloud.dev/secrets"
 )
 
@@ -21,38 +26,67 @@ const (
 type Core struct {
 	store      repo.Store
 	scheduler  scheduler.TaskScheduler
-	rwf        sync.RWMutex
-	flows      map[string]models.Flow
+	flows      *flowCache
+	loadMu     sync.Mutex
 	keeper     *secrets.Keeper
 	LogManager streamlogger.LogManager
+	Messengers *messengers.Registry
+	Metrics    *metrics.Manager
+	Uploads    *uploadstore.Store
+	Artifacts  artifactstore.Store
+	// Archive keeps the files that actions publish after their executions finished
+	Archive *artifactstore.Archive
+
+	// UploadValidators check the files uploaded to file inputs before the execution is queued
+	UploadValidators []UploadValidator
 
 	// store the mapping between logID and flowID
 	logMap   map[string]string
 	enforcer *casbin.Enforcer
 
 	flowDirectory string
+	flowStore     flowstore.Store
 	httpClient    *http.Client
 
 	remoteOptionsCache   map[string]remoteOptionsCacheEntry
 	remoteOptionsCacheMu sync.RWMutex
+
+	reviewNamespaces map[string]bool
+
+	flowLoad            *flowLoadTracker
+	flowLoadConcurrency int
+
+	executionEvents *executionEventHub
+	eventBus        *eventbus.Publisher
+
+	// messengerConfigs is the update time of the runtime settings of each channel that were
+	// applied on this instance
+	messengerConfigs   map[string]time.Time
+	messengerConfigsMu sync.Mutex
+
+	retentionOpts    RetentionOptions
+	retentionMu      sync.Mutex
+	retentionChanged chan struct{}
 }
 
-func NewCore(flowsDirectory string, s repo.Store, sch scheduler.TaskScheduler, keeper *secrets.Keeper, enforcer *casbin.Enforcer) (*Core, error) {
+// NewCore creates a Core. Flows are not loaded, LoadFlows must be called to load them.
+func NewCore(flows flowstore.Store, s repo.Store, sch scheduler.TaskScheduler, keeper *secrets.Keeper, enforcer *casbin.Enforcer) (*Core, error) {
 	c := &Core{
 		store:              s,
 		scheduler:          sch,
-		flowDirectory:      flowsDirectory,
-		flows:              make(map[string]models.Flow),
+		flowDirectory:      flows.Dir(),
+		flowStore:          flows,
+		flows:              newFlowCache(),
 		logMap:             make(map[string]string),
 		keeper:             keeper,
 		enforcer:           enforcer,
 		httpClient:         &http.Client{Timeout: 10 * time.Second},
 		remoteOptionsCache: make(map[string]remoteOptionsCacheEntry),
+		flowLoad:           newFlowLoadTracker(),
+		executionEvents:    newExecutionEventHub(),
+		retentionChanged:   make(chan struct{}, 1),
 	}
 
-	if err := c.LoadFlows(context.Background()); err != nil {
-		return nil, err
-	}
 	if err := c.InitializeRBACPolicies(); err != nil {
 		return nil, err
 	}
@@ -65,6 +99,21 @@ func NewCore(flowsDirectory string, s repo.Store, sch scheduler.TaskScheduler, k
 	return c, nil
 }
 
+// NewAdminCore creates a Core that only uses the store and RBAC, for administrative commands
+// such as managing users from the CLI. Flows are not loaded and nothing can be executed.
+func NewAdminCore(s repo.Store, enforcer *casbin.Enforcer) *Core {
+	return &Core{
+		store:              s,
+		flows:              newFlowCache(),
+		logMap:             make(map[string]string),
+		enforcer:           enforcer,
+		httpClient:         &http.Client{Timeout: 10 * time.Second},
+		remoteOptionsCache: make(map[string]remoteOptionsCacheEntry),
+		flowLoad:           newFlowLoadTracker(),
+		executionEvents:    newExecutionEventHub(),
+	}
+}
+
 // ResolveGroupEmails resolves a group name to member email addresses.
 // This implements the messengers.GroupResolver interface.
 func (c *Core) ResolveGroupEmails(ctx context.Context, groupName string) ([]string, error) {
@@ -78,4 +127,3 @@ func (c *Core) ResolveGroupEmails(ctx context.Context, groupName string) ([]stri
 	}
 	return emails, nil
 }
-
@@ -2,6 +2,7 @@ package core
 
 import (
 	"context"
+	"database/sql"
 	"encoding/hex"
 	"errors"
 	"fmt"
@@ -92,6 +93,39 @@ func (c *Core) GetCredentialByID(ctx context.Context, id string, namespaceID str
 	}, nil
 }
 
+// GetCredentialByName returns a credential of the namespace with its key decrypted, for actions
+// that log in to their nodes with a credential other than the one of the node
+func (c *Core) GetCredentialByName(ctx context.Context, name string, namespaceUUID uuid.UUID) (models.Credential, error) {
+	cred, err := c.store.GetCredentialByName(ctx, repo.GetCredentialByNameParams{
+		Name: name,
+		Uuid: namespaceUUID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Credential{}, fmt.Errorf("credential %s not found", name)
+		}
+		return models.Credential{}, fmt.Errorf("could not get credential %s: %w", name, err)
+	}
+
+	dKey, err := hex.DecodeString(cred.KeyData)
+	if err != nil {
+		return models.Credential{}, fmt.Errorf("could not decode key for credential %s: %w", name, err)
+	}
+
+	decryptedKey, err := c.keeper.Decrypt(ctx, dKey)
+	if err != nil {
+		return models.Credential{}, fmt.Errorf("could not decrypt key for credential %s: %w", name, err)
+	}
+
+	return models.Credential{
+		ID:            cred.Uuid.String(),
+		Name:          cred.Name,
+		KeyType:       cred.KeyType,
+		KeyData:       string(decryptedKey),
+		NamespaceUUID: cred.NamespaceUuid.String(),
+	}, nil
+}
+
 func (c *Core) SearchCredentials(ctx context.Context, filter string, limit, offset int, namespaceID string) ([]models.Credential, int64, int64, error) {
 	namespaceUUID, err := uuid.Parse(namespaceID)
 	if err != nil {
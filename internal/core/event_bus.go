@@ -0,0 +1,128 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/cvhariharan/flowctl/internal/eventbus"
+	"github.com/cvhariharan/flowctl/internal/repo"
+)
+
+// eventBusLockKey is the advisory lock held by the instance that publishes execution events.
+// Every instance receives every execution event, only the lock holder publishes them.
+const eventBusLockKey int64 = 0x666c6f77637400
+
+// eventBusLockInterval is how often instances try to take the event bus lock, and how often the
+// holder checks that the connection holding it is still up
+const eventBusLockInterval = 30 * time.Second
+
+// SetEventBus sets the publisher execution and approval lifecycle events are sent to
+func (c *Core) SetEventBus(p *eventbus.Publisher) {
+	c.eventBus = p
+}
+
+// RunEventBus publishes the execution events of every namespace to the event bus while this
+// instance holds the event bus lock, until ctx is done. The other instances keep trying to take
+// the lock, so another one takes over if the publishing instance stops. Approval events are
+// published by the instance that handles them.
+func (c *Core) RunEventBus(ctx context.Context, db *sql.DB) {
+	if c.eventBus == nil {
+		return
+	}
+
+	t := time.NewTicker(eventBusLockInterval)
+	defer t.Stop()
+	for {
+		if err := c.publishExecutionEventsWhileLocked(ctx, db); err != nil {
+			log.Printf("event bus: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// publishExecutionEventsWhileLocked takes the event bus lock and publishes execution events
+// until the connection holding the lock is lost or ctx is done. It returns immediately if
+// another instance holds the lock.
+func (c *Core) publishExecutionEventsWhileLocked(ctx context.Context, db *sql.DB) error {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	q := repo.New(conn)
+	locked, err := q.TryAdvisoryLock(ctx, eventBusLockKey)
+	if err != nil || !locked {
+		return err
+	}
+	// The lock is released with the connection if the unlock fails
+	defer q.AdvisoryUnlock(context.Background(), eventBusLockKey)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	events := c.SubscribeExecutionEvents(ctx, "")
+	ping := time.NewTicker(eventBusLockInterval)
+	defer ping.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ping.C:
+			if err := conn.PingContext(ctx); err != nil {
+				return err
+			}
+		case e, ok := <-events:
+			if !ok {
+				return nil
+			}
+			c.eventBus.Publish(eventbus.Event{
+				Time:        e.Timestamp,
+				Type:        "execution." + e.Name(),
+				NamespaceID: e.NamespaceID,
+				FlowID:      e.FlowID,
+				ExecID:      e.ExecID,
+				Status:      string(e.Status),
+				UserID:      e.TriggeredByID,
+			})
+		}
+	}
+}
+
+// publishApprovalEvent publishes the lifecycle event of an approval request. userID is the user
+// that decided on the request, if any.
+func (c *Core) publishApprovalEvent(eventType string, a models.ApprovalRequest, userID, namespaceID string, details map[string]any) {
+	if c.eventBus == nil {
+		return
+	}
+
+	var flowID string
+	if f, err := c.GetFlowFromLogID(a.ExecID, namespaceID); err == nil {
+		flowID = f.Meta.ID
+	}
+	if details == nil {
+		details = make(map[string]any)
+	}
+	details["approval_id"] = a.UUID
+	details["action_id"] = a.ActionID
+	if a.RequestedBy != "" {
+		details["requested_by"] = a.RequestedBy
+	}
+
+	c.eventBus.Publish(eventbus.Event{
+		Type:        eventType,
+		NamespaceID: namespaceID,
+		FlowID:      flowID,
+		ExecID:      a.ExecID,
+		Status:      string(a.Status),
+		UserID:      userID,
+		Details:     details,
+	})
+}
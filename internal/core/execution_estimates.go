@@ -0,0 +1,109 @@
+package core
+
+import (
+	"context"
+	"log"
+	"math"
+	"time"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/cvhariharan/flowctl/internal/repo"
+	"github.com/cvhariharan/flowctl/internal/scheduler"
+	"github.com/google/uuid"
+)
+
+const (
+	// estimateSampleSize is the number of last completed executions of a flow estimates are based on
+	estimateSampleSize = 20
+	// overdueFactor is how many times its usual duration an action runs for before it is overdue
+	overdueFactor = 2
+)
+
+// getExecutionEstimate estimates how long a running execution takes from the durations of the
+// actions of the flow in its last completed executions. Estimates are informational so failures
+// are only logged.
+func (c *Core) getExecutionEstimate(ctx context.Context, e repo.GetExecutionByExecIDRow, namespaceUUID uuid.UUID) *models.ExecutionEstimate {
+	if e.Status != repo.ExecutionStatusRunning {
+		return nil
+	}
+
+	f, ok := c.flows.get(namespaceUUID.String(), e.FlowSlug)
+	if !ok {
+		return nil
+	}
+
+	rows, err := c.store.GetActionDurationEstimates(ctx, repo.GetActionDurationEstimatesParams{
+		FlowID:     e.FlowID,
+		SampleSize: estimateSampleSize,
+	})
+	if err != nil {
+		log.Printf("failed to get action durations of flow %s: %v", e.FlowSlug, err)
+		return nil
+	}
+
+	usual := make(map[string]time.Duration, len(rows))
+	samples := 0
+	for _, r := range rows {
+		usual[r.ActionID] = time.Duration(math.Round(r.MedianSeconds * float64(time.Second)))
+		samples = max(samples, int(r.Samples))
+	}
+
+	entries, err := c.store.ListExecutionTimeline(ctx, repo.ListExecutionTimelineParams{
+		ExecID: e.ExecID,
+		Uuid:   namespaceUUID,
+	})
+	if err != nil {
+		log.Printf("failed to get timeline for exec %s: %v", e.ExecID, err)
+		return nil
+	}
+
+	// The last attempt of each action, entries are ordered by when they started
+	latest := make(map[string]repo.ExecutionTimeline)
+	for _, entry := range entries {
+		if entry.Node == "" {
+			latest[entry.ActionID] = entry
+		}
+	}
+
+	actionIDs := make([]string, len(f.Actions))
+	for i, a := range f.Actions {
+		actionIDs[i] = a.ID
+	}
+
+	return estimateExecution(actionIDs, usual, latest, samples, time.Now())
+}
+
+// estimateExecution returns the expected duration and remaining time of an execution. actionIDs
+// are the actions of the flow in order, usual their usual durations and latest the last attempt
+// of each action that started. Actions that never completed before are not counted, nil is
+// returned if none did.
+func estimateExecution(actionIDs []string, usual map[string]time.Duration, latest map[string]repo.ExecutionTimeline, samples int, now time.Time) *models.ExecutionEstimate {
+	if len(usual) == 0 {
+		return nil
+	}
+
+	est := &models.ExecutionEstimate{Samples: samples}
+	for _, id := range actionIDs {
+		d := usual[id]
+		est.Duration += d
+
+		run, ok := latest[id]
+		switch {
+		case ok && run.Status == scheduler.ProgressStatusSuccess:
+		case ok && run.Status == "running":
+			elapsed := now.Sub(run.StartedAt)
+			if elapsed < d {
+				est.Remaining += d - elapsed
+			}
+			if d > 0 && elapsed > overdueFactor*d {
+				est.Overdue = true
+			}
+		default:
+			// Actions that haven't run yet, or failed and are retried
+			est.Remaining += d
+		}
+	}
+	est.CompletesAt = now.Add(est.Remaining)
+
+	return est
+}
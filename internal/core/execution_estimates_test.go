@@ -0,0 +1,106 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cvhariharan/flowctl/internal/repo"
+)
+
+func TestEstimateExecution(t *testing.T) {
+	now := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	actions := []string{"build", "test", "deploy"}
+	usual := map[string]time.Duration{
+		"build":  2 * time.Minute,
+		"test":   5 * time.Minute,
+		"deploy": 1 * time.Minute,
+	}
+
+	tests := []struct {
+		name          string
+		usual         map[string]time.Duration
+		latest        map[string]repo.ExecutionTimeline
+		wantNil       bool
+		wantRemaining time.Duration
+		wantOverdue   bool
+	}{
+		{
+			name:    "no history",
+			usual:   map[string]time.Duration{},
+			wantNil: true,
+		},
+		{
+			name:          "not started",
+			usual:         usual,
+			wantRemaining: 8 * time.Minute,
+		},
+		{
+			name:  "running the second action",
+			usual: usual,
+			latest: map[string]repo.ExecutionTimeline{
+				"build": {Status: "success", StartedAt: now.Add(-4 * time.Minute)},
+				"test":  {Status: "running", StartedAt: now.Add(-2 * time.Minute)},
+			},
+			wantRemaining: 4 * time.Minute,
+		},
+		{
+			name:  "running longer than usual",
+			usual: usual,
+			latest: map[string]repo.ExecutionTimeline{
+				"build": {Status: "success", StartedAt: now.Add(-20 * time.Minute)},
+				"test":  {Status: "running", StartedAt: now.Add(-11 * time.Minute)},
+			},
+			wantRemaining: 1 * time.Minute,
+			wantOverdue:   true,
+		},
+		{
+			name:  "failed action is retried",
+			usual: usual,
+			latest: map[string]repo.ExecutionTimeline{
+				"build": {Status: "failed", StartedAt: now.Add(-1 * time.Minute)},
+			},
+			wantRemaining: 8 * time.Minute,
+		},
+		{
+			name:  "action without history",
+			usual: map[string]time.Duration{"build": 2 * time.Minute},
+			latest: map[string]repo.ExecutionTimeline{
+				"build": {Status: "success", StartedAt: now.Add(-2 * time.Minute)},
+				"test":  {Status: "running", StartedAt: now.Add(-time.Hour)},
+			},
+			wantRemaining: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := estimateExecution(actions, tt.usual, tt.latest, 5, now)
+			if tt.wantNil {
+				if got != nil {
+					t.Fatalf("estimateExecution() = %+v, want nil", got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatal("estimateExecution() = nil, want an estimate")
+			}
+
+			var wantDuration time.Duration
+			for _, d := range tt.usual {
+				wantDuration += d
+			}
+			if got.Duration != wantDuration {
+				t.Errorf("Duration = %v, want %v", got.Duration, wantDuration)
+			}
+			if got.Remaining != tt.wantRemaining {
+				t.Errorf("Remaining = %v, want %v", got.Remaining, tt.wantRemaining)
+			}
+			if !got.CompletesAt.Equal(now.Add(tt.wantRemaining)) {
+				t.Errorf("CompletesAt = %v, want %v", got.CompletesAt, now.Add(tt.wantRemaining))
+			}
+			if got.Overdue != tt.wantOverdue {
+				t.Errorf("Overdue = %v, want %v", got.Overdue, tt.wantOverdue)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/cvhariharan/flowctl/internal/repo"
+	"github.com/lib/pq"
+)
+
+// ExecutionEventsChannel is the postgres channel execution status changes are published on by
+// the execution_log trigger
+const ExecutionEventsChannel = "flowctl_execution_events"
+
+// executionEventsPollInterval is how often the execution events recorded by SQLite are read
+const executionEventsPollInterval = 500 * time.Millisecond
+
+// executionEventBuffer is the number of events kept for a subscriber. Events for a subscriber
+// that falls further behind are dropped so that it can't hold up the others.
+const executionEventBuffer = 64
+
+// executionEventHub fans out execution events to the subscribers of each namespace. Subscribers
+// of the empty namespace ID receive the events of every namespace.
+type executionEventHub struct {
+	mu   sync.RWMutex
+	subs map[chan models.ExecutionEvent]string
+}
+
+func newExecutionEventHub() *executionEventHub {
+	return &executionEventHub{subs: make(map[chan models.ExecutionEvent]string)}
+}
+
+func (h *executionEventHub) subscribe(namespaceID string) chan models.ExecutionEvent {
+	ch := make(chan models.ExecutionEvent, executionEventBuffer)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.subs[ch] = namespaceID
+	return ch
+}
+
+func (h *executionEventHub) unsubscribe(ch chan models.ExecutionEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.subs[ch]; ok {
+		delete(h.subs, ch)
+		close(ch)
+	}
+}
+
+func (h *executionEventHub) publish(e models.ExecutionEvent) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for ch, namespaceID := range h.subs {
+		if namespaceID != "" && namespaceID != e.NamespaceID {
+			continue
+		}
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+// SubscribeExecutionEvents returns a channel of the execution events of a namespace. The channel
+// is closed when ctx is done.
+func (c *Core) SubscribeExecutionEvents(ctx context.Context, namespaceID string) <-chan models.ExecutionEvent {
+	ch := c.executionEvents.subscribe(namespaceID)
+	go func() {
+		<-ctx.Done()
+		c.executionEvents.unsubscribe(ch)
+	}()
+	return ch
+}
+
+// ListenExecutionEvents listens for the execution status changes published by the database and
+// sends them to the subscribers until ctx is done. Changes made by any instance are received, so
+// every server streams every execution.
+func (c *Core) ListenExecutionEvents(ctx context.Context, dsn string) error {
+	listener := pq.NewListener(dsn, 10*time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Printf("execution events listener: %v", err)
+		}
+	})
+	defer listener.Close()
+
+	if err := listener.Listen(ExecutionEventsChannel); err != nil {
+		return fmt.Errorf("could not listen for execution events: %w", err)
+	}
+
+	ping := time.NewTicker(time.Minute)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ping.C:
+			go listener.Ping()
+		case n := <-listener.Notify:
+			// A nil notification means the connection was re-established, changes made while
+			// it was down are not received
+			if n == nil {
+				continue
+			}
+
+			var e models.ExecutionEvent
+			if err := json.Unmarshal([]byte(n.Extra), &e); err != nil {
+				log.Printf("invalid execution event: %v", err)
+				continue
+			}
+			c.executionEvents.publish(e)
+		}
+	}
+}
+
+// PollExecutionEvents sends the execution status changes recorded by a SQLite database to the
+// subscribers until ctx is done. SQLite has no LISTEN/NOTIFY, triggers record the changes in the
+// execution_events table instead and they are deleted once they are sent.
+func (c *Core) PollExecutionEvents(ctx context.Context, db *sql.DB) error {
+	// Like with ListenExecutionEvents, changes made while the server was down are not sent
+	if err := repo.DeleteSQLiteExecutionEvents(ctx, db, math.MaxInt64); err != nil {
+		return fmt.Errorf("could not delete old execution events: %w", err)
+	}
+
+	ticker := time.NewTicker(executionEventsPollInterval)
+	defer ticker.Stop()
+
+	var last int64
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		events, err := repo.ListSQLiteExecutionEvents(ctx, db, last)
+		if err != nil {
+			log.Printf("could not read execution events: %v", err)
+			continue
+		}
+		if len(events) == 0 {
+			continue
+		}
+
+		for _, ev := range events {
+			var e models.ExecutionEvent
+			if err := json.Unmarshal(ev.Payload, &e); err != nil {
+				log.Printf("invalid execution event: %v", err)
+				continue
+			}
+			c.executionEvents.publish(e)
+		}
+		last = events[len(events)-1].ID
+		if err := repo.DeleteSQLiteExecutionEvents(ctx, db, last); err != nil {
+			log.Printf("could not delete execution events: %v", err)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+)
+
+func TestExecutionEventHub(t *testing.T) {
+	h := newExecutionEventHub()
+	ns1 := h.subscribe("ns1")
+	ns2 := h.subscribe("ns2")
+	all := h.subscribe("")
+
+	h.publish(models.ExecutionEvent{ExecID: "a", NamespaceID: "ns1", Status: models.ExecutionStatusPending})
+
+	select {
+	case e := <-ns1:
+		if e.ExecID != "a" || e.Name() != "queued" {
+			t.Errorf("got event %+v (%s), want a (queued)", e, e.Name())
+		}
+	default:
+		t.Fatal("subscriber of the namespace did not get the event")
+	}
+	select {
+	case e := <-ns2:
+		t.Errorf("subscriber of another namespace got %+v", e)
+	default:
+	}
+	select {
+	case <-all:
+	default:
+		t.Fatal("subscriber of every namespace did not get the event")
+	}
+
+	// A subscriber that doesn't read drops events instead of blocking
+	for range executionEventBuffer + 1 {
+		h.publish(models.ExecutionEvent{ExecID: "b", NamespaceID: "ns1", Status: models.ExecutionStatusCompleted})
+	}
+	if len(ns1) != executionEventBuffer {
+		t.Errorf("buffered %d events, want %d", len(ns1), executionEventBuffer)
+	}
+
+	// The channel is closed once, events published afterwards are not sent to it
+	h.unsubscribe(ns1)
+	h.unsubscribe(ns1)
+	h.publish(models.ExecutionEvent{ExecID: "c", NamespaceID: "ns1"})
+	for e := range ns1 {
+		if e.ExecID == "c" {
+			t.Errorf("unsubscribed channel got %+v", e)
+		}
+	}
+}
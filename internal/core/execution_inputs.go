@@ -0,0 +1,120 @@
+package core
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/cvhariharan/flowctl/internal/scheduler"
+)
+
+const (
+	// sealedInputPrefix marks password input values that were encrypted before the execution was stored
+	sealedInputPrefix = "sealed:"
+	// MaskedInputValue replaces the values of password inputs returned by read APIs
+	MaskedInputValue = "********"
+)
+
+// SealInputs returns a copy of the input with the values of password inputs encrypted, so that
+// they are never stored in plain text. The executor is given the original input.
+func (c *Core) SealInputs(ctx context.Context, inputs []scheduler.Input, input map[string]any) (map[string]any, error) {
+	sealed := maps.Clone(input)
+	for _, in := range inputs {
+		if in.Type != scheduler.INPUT_TYPE_PASSWORD {
+			continue
+		}
+		v, ok := input[in.Name]
+		if !ok || v == nil {
+			continue
+		}
+
+		enc, err := c.keeper.Encrypt(ctx, []byte(fmt.Sprint(v)))
+		if err != nil {
+			return nil, fmt.Errorf("could not encrypt input %s: %w", in.Name, err)
+		}
+		sealed[in.Name] = sealedInputPrefix + hex.EncodeToString(enc)
+	}
+	return sealed, nil
+}
+
+// unsealInputs decrypts the password inputs of a stored execution in place
+func (c *Core) unsealInputs(ctx context.Context, input map[string]any) error {
+	for name, v := range input {
+		s, ok := v.(string)
+		if !ok || !strings.HasPrefix(s, sealedInputPrefix) {
+			continue
+		}
+
+		enc, err := hex.DecodeString(strings.TrimPrefix(s, sealedInputPrefix))
+		if err != nil {
+			return fmt.Errorf("could not decode input %s: %w", name, err)
+		}
+		dec, err := c.keeper.Decrypt(ctx, enc)
+		if err != nil {
+			return fmt.Errorf("could not decrypt input %s: %w", name, err)
+		}
+		input[name] = string(dec)
+	}
+	return nil
+}
+
+// maskInputs replaces sealed values and the values of the given password inputs with MaskedInputValue.
+// Password inputs are masked by name as well, since executions stored before inputs were sealed
+// have them in plain text.
+func maskInputs(input map[string]any, passwords []string) map[string]any {
+	if input == nil {
+		return nil
+	}
+
+	masked := maps.Clone(input)
+	for name, v := range masked {
+		if s, ok := v.(string); ok && strings.HasPrefix(s, sealedInputPrefix) {
+			masked[name] = MaskedInputValue
+		}
+	}
+	for _, name := range passwords {
+		if v, ok := masked[name]; ok && v != nil {
+			masked[name] = MaskedInputValue
+		}
+	}
+	return masked
+}
+
+// maskInputJSON masks the password inputs of the stored input of an execution of the given flow
+func (c *Core) maskInputJSON(raw json.RawMessage, flowSlug, namespaceID string) json.RawMessage {
+	if len(raw) == 0 {
+		return raw
+	}
+
+	var input map[string]any
+	if err := json.Unmarshal(raw, &input); err != nil {
+		// Never return an input that could not be checked
+		return nil
+	}
+
+	masked, err := json.Marshal(maskInputs(input, c.passwordInputs(flowSlug, namespaceID)))
+	if err != nil {
+		return nil
+	}
+	return masked
+}
+
+// passwordInputs returns the names of the password inputs of a flow
+func (c *Core) passwordInputs(flowSlug, namespaceID string) []string {
+	f, err := c.GetFlowByID(flowSlug, namespaceID)
+	if err != nil {
+		return nil
+	}
+
+	var names []string
+	for _, in := range f.Inputs {
+		if in.Type == models.INPUT_TYPE_PASSWORD {
+			names = append(names, in.Name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,50 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMaskInputs(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     map[string]any
+		passwords []string
+		want      map[string]any
+	}{
+		{"no input", nil, []string{"token"}, nil},
+		{
+			"sealed values are masked",
+			map[string]any{"env": "prod", "token": sealedInputPrefix + "a1b2"},
+			nil,
+			map[string]any{"env": "prod", "token": MaskedInputValue},
+		},
+		{
+			"plain password inputs are masked",
+			map[string]any{"env": "prod", "token": "hunter2"},
+			[]string{"token"},
+			map[string]any{"env": "prod", "token": MaskedInputValue},
+		},
+		{
+			"missing and empty password inputs are kept",
+			map[string]any{"env": "prod", "token": nil},
+			[]string{"token", "key"},
+			map[string]any{"env": "prod", "token": nil},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := map[string]any{}
+			for k, v := range tt.input {
+				original[k] = v
+			}
+			if got := maskInputs(tt.input, tt.passwords); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("maskInputs() = %v, want %v", got, tt.want)
+			}
+			if len(tt.input) > 0 && !reflect.DeepEqual(tt.input, original) {
+				t.Errorf("maskInputs() changed the original input")
+			}
+		})
+	}
+}
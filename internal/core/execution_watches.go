@@ -0,0 +1,99 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/cvhariharan/flowctl/internal/repo"
+	"github.com/google/uuid"
+)
+
+// WatchFlow notifies a user when any execution of a flow completes or fails. Watching a flow
+// twice has no effect.
+func (c *Core) WatchFlow(ctx context.Context, flowID, userID, namespaceID string) error {
+	return c.setWatch(ctx, flowID, "", userID, namespaceID, true)
+}
+
+// UnwatchFlow stops notifying a user about the executions of a flow
+func (c *Core) UnwatchFlow(ctx context.Context, flowID, userID, namespaceID string) error {
+	return c.setWatch(ctx, flowID, "", userID, namespaceID, false)
+}
+
+// WatchExecution notifies a user when an execution completes or fails. Watching an execution
+// twice has no effect.
+func (c *Core) WatchExecution(ctx context.Context, execID, userID, namespaceID string) error {
+	exec, err := c.GetExecutionSummaryByExecID(ctx, execID, namespaceID)
+	if err != nil {
+		return err
+	}
+	return c.setWatch(ctx, exec.FlowID, execID, userID, namespaceID, true)
+}
+
+// UnwatchExecution stops notifying a user about an execution. Watches of its flow are kept.
+func (c *Core) UnwatchExecution(ctx context.Context, execID, userID, namespaceID string) error {
+	exec, err := c.GetExecutionSummaryByExecID(ctx, execID, namespaceID)
+	if err != nil {
+		return err
+	}
+	return c.setWatch(ctx, exec.FlowID, execID, userID, namespaceID, false)
+}
+
+func (c *Core) setWatch(ctx context.Context, flowID, execID, userID, namespaceID string, watched bool) error {
+	f, err := c.GetFlowByID(flowID, namespaceID)
+	if err != nil {
+		return err
+	}
+
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user UUID: %w", err)
+	}
+
+	exec := sql.NullString{String: execID, Valid: execID != ""}
+	if watched {
+		err = c.store.AddExecutionWatch(ctx, repo.AddExecutionWatchParams{
+			Uuid:   userUUID,
+			FlowID: f.Meta.DBID,
+			ExecID: exec,
+		})
+	} else {
+		err = c.store.RemoveExecutionWatch(ctx, repo.RemoveExecutionWatchParams{
+			Uuid:   userUUID,
+			FlowID: f.Meta.DBID,
+			ExecID: exec,
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("could not update watches of flow %s: %w", flowID, err)
+	}
+	return nil
+}
+
+// ListExecutionWatches returns the flows and executions of a namespace watched by a user, last watched first
+func (c *Core) ListExecutionWatches(ctx context.Context, userID, namespaceID string) ([]models.ExecutionWatch, error) {
+	userUUID, namespaceUUID, err := parseUserAndNamespace(userID, namespaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := c.store.ListExecutionWatches(ctx, repo.ListExecutionWatchesParams{
+		UserUuid:      userUUID,
+		NamespaceUuid: namespaceUUID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not get watches: %w", err)
+	}
+
+	watches := make([]models.ExecutionWatch, 0, len(rows))
+	for _, v := range rows {
+		watches = append(watches, models.ExecutionWatch{
+			FlowID:    v.FlowSlug,
+			FlowName:  v.FlowName,
+			ExecID:    v.ExecID.String,
+			CreatedAt: v.CreatedAt,
+		})
+	}
+	return watches, nil
+}
@@ -0,0 +1,98 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/cvhariharan/flowctl/internal/repo"
+	"github.com/google/uuid"
+)
+
+// ErrExecutorNotAllowed is returned when a flow uses an executor its namespace may not use
+var ErrExecutorNotAllowed = errors.New("executor not allowed by namespace policy")
+
+// GetExecutorPolicy returns the executor policy of a namespace.
+// Namespaces without a policy get one that allows every executor.
+func (c *Core) GetExecutorPolicy(ctx context.Context, namespaceID string) (models.ExecutorPolicy, error) {
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return models.ExecutorPolicy{}, fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	return c.getExecutorPolicy(ctx, namespaceUUID)
+}
+
+func (c *Core) getExecutorPolicy(ctx context.Context, namespaceUUID uuid.UUID) (models.ExecutorPolicy, error) {
+	p, err := c.store.GetNamespaceExecutorPolicy(ctx, namespaceUUID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.ExecutorPolicy{}, nil
+		}
+		return models.ExecutorPolicy{}, fmt.Errorf("could not get executor policy: %w", err)
+	}
+
+	return models.ExecutorPolicy{
+		AllowedExecutors: p.AllowedExecutors,
+		UpdatedAt:        p.UpdatedAt,
+	}, nil
+}
+
+// SetExecutorPolicy creates or replaces the executor policy of a namespace.
+// A policy without executors removes the existing one. Flows that are already loaded are
+// checked when they run.
+func (c *Core) SetExecutorPolicy(ctx context.Context, namespaceID string, policy models.ExecutorPolicy) (models.ExecutorPolicy, error) {
+	if !policy.Enabled() {
+		return models.ExecutorPolicy{}, c.DeleteExecutorPolicy(ctx, namespaceID)
+	}
+
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return models.ExecutorPolicy{}, fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	allowed := slices.Clone(policy.AllowedExecutors)
+	slices.Sort(allowed)
+	allowed = slices.Compact(allowed)
+
+	p, err := c.store.UpsertNamespaceExecutorPolicy(ctx, repo.UpsertNamespaceExecutorPolicyParams{
+		Uuid:             namespaceUUID,
+		AllowedExecutors: allowed,
+	})
+	if err != nil {
+		return models.ExecutorPolicy{}, fmt.Errorf("could not save executor policy: %w", err)
+	}
+
+	return models.ExecutorPolicy{
+		AllowedExecutors: p.AllowedExecutors,
+		UpdatedAt:        p.UpdatedAt,
+	}, nil
+}
+
+// DeleteExecutorPolicy removes the executor policy of a namespace, its flows may use every executor
+func (c *Core) DeleteExecutorPolicy(ctx context.Context, namespaceID string) error {
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	if err := c.store.DeleteNamespaceExecutorPolicy(ctx, namespaceUUID); err != nil {
+		return fmt.Errorf("could not delete executor policy: %w", err)
+	}
+	return nil
+}
+
+// checkExecutorPolicy checks that the flow only uses executors its namespace may use
+func (c *Core) checkExecutorPolicy(ctx context.Context, f models.Flow, namespaceUUID uuid.UUID) error {
+	policy, err := c.getExecutorPolicy(ctx, namespaceUUID)
+	if err != nil {
+		return err
+	}
+	if err := f.ValidateExecutors(policy); err != nil {
+		return fmt.Errorf("%w: %w", ErrExecutorNotAllowed, err)
+	}
+	return nil
+}
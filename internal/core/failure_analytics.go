@@ -0,0 +1,191 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/cvhariharan/flowctl/internal/repo"
+	"github.com/google/uuid"
+)
+
+const failureMessageMaxLen = 300
+
+// errorNormalizers replace the parts of an error message that usually differ between occurrences
+// of the same failure. They are applied in order, so the more specific patterns come first.
+var errorNormalizers = []struct {
+	re          *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`), "<uuid>"},
+	{regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:?\d{2})?`), "<time>"},
+	{regexp.MustCompile(`\b\d{1,3}(\.\d{1,3}){3}(:\d+)?\b`), "<ip>"},
+	{regexp.MustCompile(`\b(0x[0-9a-fA-F]+|[0-9a-fA-F]{8,})\b`), "<hex>"},
+	{regexp.MustCompile(`\b\d+(\.\d+)?\b`), "<n>"},
+	{regexp.MustCompile(`\s+`), " "},
+}
+
+// normalizeFailureMessage reduces an error message to a form that is shared by every occurrence of the failure
+func normalizeFailureMessage(msg string) string {
+	msg = strings.TrimSpace(msg)
+	if msg == "" {
+		return "unknown error"
+	}
+
+	for _, n := range errorNormalizers {
+		msg = n.re.ReplaceAllString(msg, n.replacement)
+	}
+
+	if r := []rune(msg); len(r) > failureMessageMaxLen {
+		msg = string(r[:failureMessageMaxLen]) + "..."
+	}
+	return msg
+}
+
+// GetFailureAnalytics aggregates the errors of the executions that failed within the window
+// into their most common causes, and the actions and nodes that failed the most
+func (c *Core) GetFailureAnalytics(ctx context.Context, namespaceID string, q models.FailureAnalyticsQuery) (models.FailureAnalytics, error) {
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return models.FailureAnalytics{}, fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	if q.To.IsZero() {
+		q.To = time.Now()
+	}
+	if q.From.IsZero() {
+		q.From = q.To.Add(-models.FailureAnalyticsDefaultWindow)
+	}
+	if !q.From.Before(q.To) {
+		return models.FailureAnalytics{}, fmt.Errorf("from should be before to")
+	}
+	if q.To.Sub(q.From) > models.FailureAnalyticsMaxWindow {
+		return models.FailureAnalytics{}, fmt.Errorf("window cannot be longer than %d days", int(models.FailureAnalyticsMaxWindow.Hours()/24))
+	}
+	if q.Limit <= 0 {
+		q.Limit = models.FailureAnalyticsDefaultLimit
+	}
+	q.Limit = min(q.Limit, models.FailureAnalyticsMaxLimit)
+
+	failed, err := c.store.ListFailedExecutions(ctx, repo.ListFailedExecutionsParams{
+		NamespaceUuid: namespaceUUID,
+		From:          q.From,
+		To:            q.To,
+	})
+	if err != nil {
+		return models.FailureAnalytics{}, fmt.Errorf("could not list failed executions: %w", err)
+	}
+
+	nodes, err := c.store.ListFailedNodes(ctx, repo.ListFailedNodesParams{
+		NamespaceUuid: namespaceUUID,
+		From:          q.From,
+		To:            q.To,
+	})
+	if err != nil {
+		return models.FailureAnalytics{}, fmt.Errorf("could not list failed nodes: %w", err)
+	}
+
+	result := models.FailureAnalytics{
+		From:          q.From,
+		To:            q.To,
+		TotalFailures: len(failed),
+	}
+
+	// Failed executions are ordered by most recent first, so the first occurrence of a cause
+	// provides its sample error and last seen time
+	causes := make(map[string]*models.FailureCause)
+	actions := make(map[string]*models.FailureCount)
+	failedExecs := make(map[string]struct{}, len(failed))
+	for _, e := range failed {
+		failedExecs[e.ExecID] = struct{}{}
+
+		msg := normalizeFailureMessage(e.Error.String)
+		cause, ok := causes[msg]
+		if !ok {
+			cause = &models.FailureCause{
+				Message:     msg,
+				SampleError: e.Error.String,
+				LastSeen:    e.UpdatedAt,
+			}
+			causes[msg] = cause
+		}
+		cause.Count++
+		if !slices.Contains(cause.FlowIDs, e.FlowSlug) {
+			cause.FlowIDs = append(cause.FlowIDs, e.FlowSlug)
+		}
+		if e.CurrentActionID.String != "" && !slices.Contains(cause.ActionIDs, e.CurrentActionID.String) {
+			cause.ActionIDs = append(cause.ActionIDs, e.CurrentActionID.String)
+		}
+		if len(cause.SampleExecIDs) < models.FailureCauseMaxSamples {
+			cause.SampleExecIDs = append(cause.SampleExecIDs, e.ExecID)
+		}
+
+		if e.CurrentActionID.String != "" {
+			countFailure(actions, e.FlowSlug+"/"+e.CurrentActionID.String, e.UpdatedAt)
+		}
+	}
+
+	// Only node failures that made the execution fail are counted, failures that
+	// were recovered by a retry are not
+	nodeCounts := make(map[string]*models.FailureCount)
+	for _, n := range nodes {
+		if _, ok := failedExecs[n.ExecID]; !ok {
+			continue
+		}
+		countFailure(nodeCounts, n.Node, n.StartedAt)
+	}
+
+	result.Causes = make([]models.FailureCause, 0, len(causes))
+	for _, cause := range causes {
+		result.Causes = append(result.Causes, *cause)
+	}
+	sort.Slice(result.Causes, func(i, j int) bool {
+		if result.Causes[i].Count != result.Causes[j].Count {
+			return result.Causes[i].Count > result.Causes[j].Count
+		}
+		return result.Causes[i].LastSeen.After(result.Causes[j].LastSeen)
+	})
+	if len(result.Causes) > q.Limit {
+		result.Causes = result.Causes[:q.Limit]
+	}
+
+	result.Actions = topFailureCounts(actions, q.Limit)
+	result.Nodes = topFailureCounts(nodeCounts, q.Limit)
+
+	return result, nil
+}
+
+func countFailure(counts map[string]*models.FailureCount, name string, at time.Time) {
+	fc, ok := counts[name]
+	if !ok {
+		fc = &models.FailureCount{Name: name}
+		counts[name] = fc
+	}
+	fc.Count++
+	if at.After(fc.LastSeen) {
+		fc.LastSeen = at
+	}
+}
+
+// topFailureCounts returns up to limit entries of counts, with the most failures first
+func topFailureCounts(counts map[string]*models.FailureCount, limit int) []models.FailureCount {
+	top := make([]models.FailureCount, 0, len(counts))
+	for _, fc := range counts {
+		top = append(top, *fc)
+	}
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].Count != top[j].Count {
+			return top[i].Count > top[j].Count
+		}
+		return top[i].Name < top[j].Name
+	})
+	if len(top) > limit {
+		top = top[:limit]
+	}
+	return top
+}
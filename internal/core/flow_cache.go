@@ -0,0 +1,190 @@
+package core
+
+import (
+	"sync"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+)
+
+// flowCache holds the flows loaded from the flows directory, grouped by namespace UUID and keyed
+// by flow slug. Every namespace has its own lock, so reloading or updating the flows of one
+// namespace doesn't block readers of the others. The locks are only held while the maps are
+// accessed, callers must not hold them across database or file system calls.
+type flowCache struct {
+	// mu guards the namespaces map, not the flows of a namespace
+	mu         sync.RWMutex
+	namespaces map[string]*namespaceFlows
+}
+
+type namespaceFlows struct {
+	mu    sync.RWMutex
+	flows map[string]models.Flow
+	// reserved holds the slugs of flows that are being created
+	reserved map[string]struct{}
+}
+
+func newNamespaceFlows(flows map[string]models.Flow) *namespaceFlows {
+	return &namespaceFlows{flows: flows, reserved: make(map[string]struct{})}
+}
+
+func newFlowCache() *flowCache {
+	return &flowCache{namespaces: make(map[string]*namespaceFlows)}
+}
+
+// namespace returns the flows of a namespace. If create is set, an empty entry is added for
+// namespaces that have no flows yet, otherwise nil is returned for them.
+func (fc *flowCache) namespace(namespaceID string, create bool) *namespaceFlows {
+	fc.mu.RLock()
+	nf, ok := fc.namespaces[namespaceID]
+	fc.mu.RUnlock()
+	if ok || !create {
+		return nf
+	}
+
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	if nf, ok := fc.namespaces[namespaceID]; ok {
+		return nf
+	}
+	nf = newNamespaceFlows(make(map[string]models.Flow))
+	fc.namespaces[namespaceID] = nf
+	return nf
+}
+
+// get returns the flow with the given slug in a namespace
+func (fc *flowCache) get(namespaceID, slug string) (models.Flow, bool) {
+	nf := fc.namespace(namespaceID, false)
+	if nf == nil {
+		return models.Flow{}, false
+	}
+
+	nf.mu.RLock()
+	defer nf.mu.RUnlock()
+	f, ok := nf.flows[slug]
+	return f, ok
+}
+
+// exists reports whether a flow with the given slug is loaded in a namespace
+func (fc *flowCache) exists(namespaceID, slug string) bool {
+	_, ok := fc.get(namespaceID, slug)
+	return ok
+}
+
+// getMany returns the loaded versions of flows, in the same order. flows are usually built from
+// database rows, those that are not loaded are returned as given.
+func (fc *flowCache) getMany(namespaceID string, flows []models.Flow) []models.Flow {
+	fs := make([]models.Flow, 0, len(flows))
+	nf := fc.namespace(namespaceID, false)
+	if nf == nil {
+		return append(fs, flows...)
+	}
+
+	nf.mu.RLock()
+	defer nf.mu.RUnlock()
+	for _, f := range flows {
+		if loaded, ok := nf.flows[f.Meta.ID]; ok {
+			f = loaded
+		}
+		fs = append(fs, f)
+	}
+	return fs
+}
+
+// filter returns the flows of a namespace for which keep returns true, in no particular order
+func (fc *flowCache) filter(namespaceID string, keep func(models.Flow) bool) []models.Flow {
+	nf := fc.namespace(namespaceID, false)
+	if nf == nil {
+		return nil
+	}
+
+	nf.mu.RLock()
+	defer nf.mu.RUnlock()
+	var fs []models.Flow
+	for _, f := range nf.flows {
+		if keep(f) {
+			fs = append(fs, f)
+		}
+	}
+	return fs
+}
+
+// reserve marks a slug in a namespace as being created. It fails if the flow is loaded or
+// already reserved. The reservation must be released once the flow is created or has failed.
+func (fc *flowCache) reserve(namespaceID, slug string) bool {
+	nf := fc.namespace(namespaceID, true)
+
+	nf.mu.Lock()
+	defer nf.mu.Unlock()
+	if _, ok := nf.flows[slug]; ok {
+		return false
+	}
+	if _, ok := nf.reserved[slug]; ok {
+		return false
+	}
+	nf.reserved[slug] = struct{}{}
+	return true
+}
+
+// release removes the reservation of a slug
+func (fc *flowCache) release(namespaceID, slug string) {
+	nf := fc.namespace(namespaceID, false)
+	if nf == nil {
+		return
+	}
+
+	nf.mu.Lock()
+	defer nf.mu.Unlock()
+	delete(nf.reserved, slug)
+}
+
+// set adds or replaces a flow in a namespace
+func (fc *flowCache) set(namespaceID string, f models.Flow) {
+	nf := fc.namespace(namespaceID, true)
+
+	nf.mu.Lock()
+	defer nf.mu.Unlock()
+	nf.flows[f.Meta.ID] = f
+}
+
+// delete removes a flow from a namespace
+func (fc *flowCache) delete(namespaceID, slug string) {
+	nf := fc.namespace(namespaceID, false)
+	if nf == nil {
+		return
+	}
+
+	nf.mu.Lock()
+	defer nf.mu.Unlock()
+	delete(nf.flows, slug)
+}
+
+// replaceNamespace replaces all the flows of a namespace with flows, keyed by slug
+func (fc *flowCache) replaceNamespace(namespaceID string, flows map[string]models.Flow) {
+	nf := fc.namespace(namespaceID, true)
+
+	nf.mu.Lock()
+	defer nf.mu.Unlock()
+	nf.flows = flows
+}
+
+// replaceAll replaces every loaded flow with flows, keyed by namespace UUID and slug.
+// Namespaces that are not in flows are removed. Namespaces that are kept keep their reservations.
+func (fc *flowCache) replaceAll(flows map[string]map[string]models.Flow) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	namespaces := make(map[string]*namespaceFlows, len(flows))
+	for namespaceID, nsFlows := range flows {
+		nf, ok := fc.namespaces[namespaceID]
+		if !ok {
+			namespaces[namespaceID] = newNamespaceFlows(nsFlows)
+			continue
+		}
+
+		nf.mu.Lock()
+		nf.flows = nsFlows
+		nf.mu.Unlock()
+		namespaces[namespaceID] = nf
+	}
+	fc.namespaces = namespaces
+}
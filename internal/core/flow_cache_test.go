@@ -0,0 +1,222 @@
+package core
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+)
+
+// newBenchFlowCache returns a cache with namespaces namespaces holding perNamespace flows each
+func newBenchFlowCache(namespaces, perNamespace int) (*flowCache, []string, []string) {
+	fc := newFlowCache()
+	nsIDs := make([]string, 0, namespaces)
+	slugs := make([]string, 0, perNamespace)
+	for i := 0; i < perNamespace; i++ {
+		slugs = append(slugs, fmt.Sprintf("flow_%d", i))
+	}
+
+	all := make(map[string]map[string]models.Flow, namespaces)
+	for n := 0; n < namespaces; n++ {
+		nsID := fmt.Sprintf("namespace-%d", n)
+		nsIDs = append(nsIDs, nsID)
+		all[nsID] = benchNamespaceFlows(slugs)
+	}
+	fc.replaceAll(all)
+
+	return fc, nsIDs, slugs
+}
+
+func benchNamespaceFlows(slugs []string) map[string]models.Flow {
+	flows := make(map[string]models.Flow, len(slugs))
+	for _, slug := range slugs {
+		flows[slug] = models.Flow{Meta: models.Metadata{ID: slug, Name: slug}}
+	}
+	return flows
+}
+
+func BenchmarkFlowCache_Get(b *testing.B) {
+	for _, size := range []int{1000, 10000, 100000} {
+		b.Run(fmt.Sprintf("flows=%d", size), func(b *testing.B) {
+			fc, nsIDs, slugs := newBenchFlowCache(10, size/10)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				fc.get(nsIDs[i%len(nsIDs)], slugs[i%len(slugs)])
+			}
+		})
+	}
+}
+
+// BenchmarkFlowCache_GetPage simulates listing a page of flows after the paginated DB query
+func BenchmarkFlowCache_GetPage(b *testing.B) {
+	for _, size := range []int{1000, 10000, 100000} {
+		b.Run(fmt.Sprintf("flows=%d", size), func(b *testing.B) {
+			fc, nsIDs, slugs := newBenchFlowCache(10, size/10)
+			page := make([]models.Flow, 0, len(slugs))
+			for _, slug := range slugs {
+				page = append(page, models.Flow{Meta: models.Metadata{ID: slug}})
+			}
+			b.ReportAllocs()
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					start := (i * 50) % (len(page) - 50)
+					fc.getMany(nsIDs[i%len(nsIDs)], page[start:start+50])
+					i++
+				}
+			})
+		})
+	}
+}
+
+// BenchmarkFlowCache_GetDuringReload measures reads while another namespace is reloaded continuously
+func BenchmarkFlowCache_GetDuringReload(b *testing.B) {
+	fc, nsIDs, slugs := newBenchFlowCache(10, 10000)
+	reload := benchNamespaceFlows(slugs)
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				fc.replaceNamespace(nsIDs[0], reload)
+			}
+		}
+	}()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			fc.get(nsIDs[1+i%(len(nsIDs)-1)], slugs[i%len(slugs)])
+			i++
+		}
+	})
+}
+
+func testFlow(slug, name string) models.Flow {
+	return models.Flow{Meta: models.Metadata{ID: slug, Name: name}}
+}
+
+func TestFlowCache_GetSetDelete(t *testing.T) {
+	fc := newFlowCache()
+
+	if _, ok := fc.get("ns1", "deploy"); ok {
+		t.Fatal("get() found a flow in an empty cache")
+	}
+
+	fc.set("ns1", testFlow("deploy", "Deploy"))
+	f, ok := fc.get("ns1", "deploy")
+	if !ok || f.Meta.Name != "Deploy" {
+		t.Fatalf("get() = %v, %v, want the flow that was set", f.Meta, ok)
+	}
+	if fc.exists("ns2", "deploy") {
+		t.Error("exists() found the flow in another namespace")
+	}
+
+	fc.set("ns1", testFlow("deploy", "Deploy v2"))
+	if f, _ := fc.get("ns1", "deploy"); f.Meta.Name != "Deploy v2" {
+		t.Errorf("get() after update = %s, want Deploy v2", f.Meta.Name)
+	}
+
+	fc.delete("ns1", "deploy")
+	if fc.exists("ns1", "deploy") {
+		t.Error("exists() = true after delete")
+	}
+	// Deleting from a namespace that was never loaded is a no-op
+	fc.delete("ns2", "deploy")
+}
+
+func TestFlowCache_Replace(t *testing.T) {
+	fc := newFlowCache()
+	fc.set("ns1", testFlow("old", "Old"))
+	fc.set("ns2", testFlow("other", "Other"))
+
+	fc.replaceNamespace("ns1", map[string]models.Flow{"new": testFlow("new", "New")})
+	if fc.exists("ns1", "old") || !fc.exists("ns1", "new") {
+		t.Error("replaceNamespace() didn't replace the flows of the namespace")
+	}
+	if !fc.exists("ns2", "other") {
+		t.Error("replaceNamespace() changed another namespace")
+	}
+
+	fc.replaceAll(map[string]map[string]models.Flow{
+		"ns1": {"reloaded": testFlow("reloaded", "Reloaded")},
+	})
+	if !fc.exists("ns1", "reloaded") || fc.exists("ns1", "new") {
+		t.Error("replaceAll() didn't replace the flows of a kept namespace")
+	}
+	if fc.exists("ns2", "other") {
+		t.Error("replaceAll() kept a namespace that was removed")
+	}
+}
+
+func TestFlowCache_GetMany(t *testing.T) {
+	fc := newFlowCache()
+	fc.set("ns1", testFlow("a", "Loaded A"))
+	fc.set("ns1", testFlow("c", "Loaded C"))
+
+	page := []models.Flow{testFlow("c", "DB C"), testFlow("b", "DB B"), testFlow("a", "DB A")}
+	got := fc.getMany("ns1", page)
+	want := []string{"Loaded C", "DB B", "Loaded A"}
+	if len(got) != len(want) {
+		t.Fatalf("getMany() returned %d flows, want %d", len(got), len(want))
+	}
+	for i, name := range want {
+		if got[i].Meta.Name != name {
+			t.Errorf("getMany()[%d] = %s, want %s", i, got[i].Meta.Name, name)
+		}
+	}
+
+	if got := fc.getMany("unloaded", page); len(got) != len(page) || got[0].Meta.Name != "DB C" {
+		t.Errorf("getMany() on an unloaded namespace = %v, want the given flows", got)
+	}
+}
+
+func TestFlowCache_Reserve(t *testing.T) {
+	fc := newFlowCache()
+	fc.set("ns1", testFlow("existing", "Existing"))
+
+	if fc.reserve("ns1", "existing") {
+		t.Error("reserve() succeeded for a loaded flow")
+	}
+	if !fc.reserve("ns1", "new") {
+		t.Fatal("reserve() failed for a new flow")
+	}
+	if fc.reserve("ns1", "new") {
+		t.Error("reserve() succeeded twice for the same flow")
+	}
+	if !fc.reserve("ns2", "new") {
+		t.Error("reserve() failed for the same slug in another namespace")
+	}
+
+	fc.release("ns1", "new")
+	if !fc.reserve("ns1", "new") {
+		t.Error("reserve() failed after the reservation was released")
+	}
+
+	// Only one of many concurrent reservations of a slug succeeds
+	var wg sync.WaitGroup
+	var succeeded atomic.Int32
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if fc.reserve("ns3", "racy") {
+				succeeded.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := succeeded.Load(); n != 1 {
+		t.Errorf("%d concurrent reservations succeeded, want 1", n)
+	}
+}
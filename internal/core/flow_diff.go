@@ -0,0 +1,282 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+)
+
+const (
+	// diffContext is the number of unchanged lines shown around changes in a unified diff
+	diffContext = 3
+	// maxDiffCells bounds the size of the table used to find the common lines of two files.
+	// Larger changes are shown as the whole changed part being replaced.
+	maxDiffCells = 1 << 22
+)
+
+// diffFlows returns the changes to the metadata, inputs and actions between two flows
+func diffFlows(from, to models.Flow) models.FlowDiff {
+	d := models.FlowDiff{
+		Metadata: fieldChanges(from.Meta, to.Meta),
+	}
+
+	// Schedules and notifications are not part of the metadata section of a flow file, but
+	// are shown with it since they configure the flow as a whole
+	if !reflect.DeepEqual(from.Schedules, to.Schedules) {
+		d.Metadata = append(d.Metadata, models.FieldChange{Field: "schedules", Old: from.Schedules, New: to.Schedules})
+	}
+	if !reflect.DeepEqual(from.Notify, to.Notify) {
+		d.Metadata = append(d.Metadata, models.FieldChange{Field: "notify", Old: from.Notify, New: to.Notify})
+	}
+
+	d.Inputs = itemChanges(from.Inputs, to.Inputs, func(i models.Input) string { return i.Name })
+	d.Actions = itemChanges(from.Actions, to.Actions, func(a models.Action) string { return a.ID })
+
+	return d
+}
+
+// itemChanges compares two lists of items by their ID. Items that are in both lists but in a
+// different order relative to each other get a position change, since actions run in order.
+func itemChanges[T any](from, to []T, id func(T) string) []models.ItemChange {
+	fromByID := make(map[string]int, len(from))
+	for i, item := range from {
+		fromByID[id(item)] = i
+	}
+	toByID := make(map[string]int, len(to))
+	for i, item := range to {
+		toByID[id(item)] = i
+	}
+
+	// Positions among the items in both lists, so that adding or removing an item doesn't
+	// show every item after it as moved
+	fromPos := make(map[string]int)
+	for _, item := range from {
+		if _, ok := toByID[id(item)]; ok {
+			fromPos[id(item)] = len(fromPos)
+		}
+	}
+	toPos := make(map[string]int)
+	for _, item := range to {
+		if _, ok := fromByID[id(item)]; ok {
+			toPos[id(item)] = len(toPos)
+		}
+	}
+
+	var changes []models.ItemChange
+	for _, item := range to {
+		key := id(item)
+		i, ok := fromByID[key]
+		if !ok {
+			changes = append(changes, models.ItemChange{ID: key, Change: models.FlowChangeAdded})
+			continue
+		}
+
+		fields := fieldChanges(from[i], item)
+		if fromPos[key] != toPos[key] {
+			fields = append(fields, models.FieldChange{Field: "position", Old: i, New: toByID[key]})
+		}
+		if len(fields) > 0 {
+			changes = append(changes, models.ItemChange{ID: key, Change: models.FlowChangeChanged, Fields: fields})
+		}
+	}
+	for _, item := range from {
+		if _, ok := toByID[id(item)]; !ok {
+			changes = append(changes, models.ItemChange{ID: id(item), Change: models.FlowChangeRemoved})
+		}
+	}
+
+	return changes
+}
+
+// fieldChanges compares the fields of two structs of the same type that are part of a flow file.
+// Fields are named by their yaml key.
+func fieldChanges(from, to any) []models.FieldChange {
+	fv, tv := reflect.ValueOf(from), reflect.ValueOf(to)
+	t := fv.Type()
+
+	var changes []models.FieldChange
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+		if name == "-" || !t.Field(i).IsExported() {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(t.Field(i).Name)
+		}
+
+		a, b := fv.Field(i).Interface(), tv.Field(i).Interface()
+		if !reflect.DeepEqual(a, b) {
+			changes = append(changes, models.FieldChange{Field: name, Old: a, New: b})
+		}
+	}
+
+	return changes
+}
+
+type diffOp struct {
+	kind byte
+	line string
+}
+
+// unifiedDiff returns the changes between two files in the unified diff format
+func unifiedDiff(fromName, toName, from, to string) string {
+	if from == to {
+		return ""
+	}
+
+	ops := diffLines(splitLines(from), splitLines(to))
+
+	// Line numbers in both files at the start of each op
+	fromLine := make([]int, len(ops)+1)
+	toLine := make([]int, len(ops)+1)
+	fromLine[0], toLine[0] = 1, 1
+	for i, op := range ops {
+		fromLine[i+1], toLine[i+1] = fromLine[i], toLine[i]
+		if op.kind != '+' {
+			fromLine[i+1]++
+		}
+		if op.kind != '-' {
+			toLine[i+1]++
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromName, toName)
+
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+
+		// A hunk starts with context before the first change and continues until there are
+		// more unchanged lines than fit in the context after a change and before the next one
+		start := max(0, i-diffContext)
+		end := i
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			run := 0
+			for end+run < len(ops) && ops[end+run].kind == ' ' {
+				run++
+			}
+			if end+run == len(ops) || run > 2*diffContext {
+				end += min(run, diffContext)
+				break
+			}
+			end += run
+		}
+
+		var fromCount, toCount int
+		for _, op := range ops[start:end] {
+			if op.kind != '+' {
+				fromCount++
+			}
+			if op.kind != '-' {
+				toCount++
+			}
+		}
+
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(fromLine[start], fromCount), hunkRange(toLine[start], toCount))
+		for _, op := range ops[start:end] {
+			sb.WriteByte(op.kind)
+			sb.WriteString(op.line)
+			sb.WriteByte('\n')
+		}
+
+		i = end
+	}
+
+	return sb.String()
+}
+
+// diffLines returns the ops that turn a into b, using the longest common subsequence of lines
+func diffLines(a, b []string) []diffOp {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]diffOp, 0, len(a)+len(b))
+	for _, l := range a[:prefix] {
+		ops = append(ops, diffOp{' ', l})
+	}
+
+	am, bm := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if len(am)*len(bm) > maxDiffCells {
+		for _, l := range am {
+			ops = append(ops, diffOp{'-', l})
+		}
+		for _, l := range bm {
+			ops = append(ops, diffOp{'+', l})
+		}
+	} else {
+		// lcs[i][j] is the length of the longest common subsequence of am[i:] and bm[j:]
+		lcs := make([][]int, len(am)+1)
+		for i := range lcs {
+			lcs[i] = make([]int, len(bm)+1)
+		}
+		for i := len(am) - 1; i >= 0; i-- {
+			for j := len(bm) - 1; j >= 0; j-- {
+				if am[i] == bm[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else {
+					lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+				}
+			}
+		}
+
+		i, j := 0, 0
+		for i < len(am) && j < len(bm) {
+			switch {
+			case am[i] == bm[j]:
+				ops = append(ops, diffOp{' ', am[i]})
+				i++
+				j++
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				ops = append(ops, diffOp{'-', am[i]})
+				i++
+			default:
+				ops = append(ops, diffOp{'+', bm[j]})
+				j++
+			}
+		}
+		for ; i < len(am); i++ {
+			ops = append(ops, diffOp{'-', am[i]})
+		}
+		for ; j < len(bm); j++ {
+			ops = append(ops, diffOp{'+', bm[j]})
+		}
+	}
+
+	for _, l := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{' ', l})
+	}
+	return ops
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// hunkRange formats the start and length of a hunk. Empty ranges start at the line before.
+func hunkRange(start, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start-1)
+	case 1:
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
@@ -0,0 +1,162 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		from string
+		to   string
+		want string
+	}{
+		{
+			name: "unchanged",
+			from: "a\nb\n",
+			to:   "a\nb\n",
+			want: "",
+		},
+		{
+			name: "changed line",
+			from: "a\nb\nc\n",
+			to:   "a\nB\nc\n",
+			want: "--- 1\n+++ 2\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+		},
+		{
+			name: "added to empty file",
+			from: "",
+			to:   "a\n",
+			want: "--- 1\n+++ 2\n@@ -0,0 +1 @@\n+a\n",
+		},
+		{
+			name: "removed all lines",
+			from: "a\nb\n",
+			to:   "",
+			want: "--- 1\n+++ 2\n@@ -1,2 +0,0 @@\n-a\n-b\n",
+		},
+		{
+			name: "context is limited",
+			from: "1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			to:   "1\n2\n3\n4\nfive\n6\n7\n8\n9\n",
+			want: "--- 1\n+++ 2\n@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n",
+		},
+		{
+			name: "distant changes are separate hunks",
+			from: "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			to:   "one\n2\n3\n4\n5\n6\n7\n8\n9\nten\n",
+			want: "--- 1\n+++ 2\n@@ -1,4 +1,4 @@\n-1\n+one\n 2\n 3\n 4\n@@ -7,4 +7,4 @@\n 7\n 8\n 9\n-10\n+ten\n",
+		},
+		{
+			name: "close changes share a hunk",
+			from: "1\n2\n3\n4\n5\n",
+			to:   "one\n2\n3\n4\nfive\n",
+			want: "--- 1\n+++ 2\n@@ -1,5 +1,5 @@\n-1\n+one\n 2\n 3\n 4\n-5\n+five\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unifiedDiff("1", "2", tt.from, tt.to); got != tt.want {
+				t.Errorf("unifiedDiff() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestItemChanges(t *testing.T) {
+	action := func(id, name string) models.Action {
+		return models.Action{ID: id, Name: name}
+	}
+
+	tests := []struct {
+		name string
+		from []models.Action
+		to   []models.Action
+		want []models.ItemChange
+	}{
+		{
+			name: "unchanged",
+			from: []models.Action{action("a", "A"), action("b", "B")},
+			to:   []models.Action{action("a", "A"), action("b", "B")},
+			want: nil,
+		},
+		{
+			name: "added and removed",
+			from: []models.Action{action("a", "A"), action("b", "B")},
+			to:   []models.Action{action("a", "A"), action("c", "C")},
+			want: []models.ItemChange{
+				{ID: "c", Change: models.FlowChangeAdded},
+				{ID: "b", Change: models.FlowChangeRemoved},
+			},
+		},
+		{
+			name: "inserting an item doesn't move the others",
+			from: []models.Action{action("a", "A"), action("b", "B")},
+			to:   []models.Action{action("new", "New"), action("a", "A"), action("b", "B")},
+			want: []models.ItemChange{
+				{ID: "new", Change: models.FlowChangeAdded},
+			},
+		},
+		{
+			name: "changed field",
+			from: []models.Action{action("a", "A")},
+			to:   []models.Action{action("a", "Renamed")},
+			want: []models.ItemChange{
+				{ID: "a", Change: models.FlowChangeChanged, Fields: []models.FieldChange{{Field: "name", Old: "A", New: "Renamed"}}},
+			},
+		},
+		{
+			name: "reordered",
+			from: []models.Action{action("a", "A"), action("b", "B")},
+			to:   []models.Action{action("b", "B"), action("a", "A")},
+			want: []models.ItemChange{
+				{ID: "b", Change: models.FlowChangeChanged, Fields: []models.FieldChange{{Field: "position", Old: 1, New: 0}}},
+				{ID: "a", Change: models.FlowChangeChanged, Fields: []models.FieldChange{{Field: "position", Old: 0, New: 1}}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := itemChanges(tt.from, tt.to, func(a models.Action) string { return a.ID })
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("itemChanges() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffFlows(t *testing.T) {
+	from := models.Flow{
+		Meta:   models.Metadata{ID: "deploy", Name: "Deploy", CommitSHA: "abc"},
+		Inputs: []models.Input{{Name: "env", Type: "string"}},
+		Actions: []models.Action{
+			{ID: "build", Name: "Build"},
+		},
+	}
+	to := models.Flow{
+		Meta:      models.Metadata{ID: "deploy", Name: "Deploy app", CommitSHA: "def"},
+		Inputs:    []models.Input{{Name: "env", Type: "string", Required: true}},
+		Actions:   []models.Action{{ID: "build", Name: "Build"}},
+		Schedules: []models.Schedule{{Cron: "0 * * * *"}},
+	}
+
+	got := diffFlows(from, to)
+	want := models.FlowDiff{
+		// Fields that are not part of the flow file, such as the commit, are not compared
+		Metadata: []models.FieldChange{
+			{Field: "name", Old: "Deploy", New: "Deploy app"},
+			{Field: "schedules", Old: []models.Schedule(nil), New: to.Schedules},
+		},
+		Inputs: []models.ItemChange{
+			{ID: "env", Change: models.FlowChangeChanged, Fields: []models.FieldChange{{Field: "required", Old: false, New: true}}},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("diffFlows() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,138 @@
+package core
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/cvhariharan/flowctl/internal/repo"
+	"github.com/google/uuid"
+)
+
+// MaxRecentFlows limits how many recently run flows are listed
+const MaxRecentFlows = 50
+
+// AddFlowFavorite stars a flow for a user. Starring a flow twice has no effect.
+func (c *Core) AddFlowFavorite(ctx context.Context, flowID, userID, namespaceID string) error {
+	f, err := c.GetFlowByID(flowID, namespaceID)
+	if err != nil {
+		return err
+	}
+
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user UUID: %w", err)
+	}
+
+	if err := c.store.AddFlowFavorite(ctx, repo.AddFlowFavoriteParams{
+		Uuid:   userUUID,
+		FlowID: f.Meta.DBID,
+	}); err != nil {
+		return fmt.Errorf("could not star flow %s: %w", flowID, err)
+	}
+	return nil
+}
+
+// RemoveFlowFavorite unstars a flow for a user
+func (c *Core) RemoveFlowFavorite(ctx context.Context, flowID, userID, namespaceID string) error {
+	f, err := c.GetFlowByID(flowID, namespaceID)
+	if err != nil {
+		return err
+	}
+
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user UUID: %w", err)
+	}
+
+	if err := c.store.RemoveFlowFavorite(ctx, repo.RemoveFlowFavoriteParams{
+		Uuid:   userUUID,
+		FlowID: f.Meta.DBID,
+	}); err != nil {
+		return fmt.Errorf("could not unstar flow %s: %w", flowID, err)
+	}
+	return nil
+}
+
+// ListFlowFavorites returns the flows of a namespace starred by a user, by name.
+// Flows in flow groups the user can no longer view are left out.
+func (c *Core) ListFlowFavorites(ctx context.Context, userID, namespaceID string) ([]models.Flow, error) {
+	userUUID, namespaceUUID, err := parseUserAndNamespace(userID, namespaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	prefixes, hasFullAccess, err := c.getUserPrefixAccess(ctx, userID, namespaceID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get user prefix access: %w", err)
+	}
+
+	rows, err := c.store.ListFlowFavorites(ctx, repo.ListFlowFavoritesParams{
+		UserUuid:      userUUID,
+		NamespaceUuid: namespaceUUID,
+		FullAccess:    hasFullAccess,
+		Prefixes:      prefixes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not get starred flows: %w", err)
+	}
+
+	flows := make([]models.Flow, 0, len(rows))
+	for _, v := range rows {
+		flows = append(flows, flowFromDB(v.ID, v.Slug, v.Name, v.Description, v.PrefixName))
+	}
+	return c.flows.getMany(namespaceID, flows), nil
+}
+
+// ListRecentFlows returns up to limit flows of a namespace that a user triggered, last run first.
+// Scheduled runs are not counted and flows in flow groups the user can no longer view are left out.
+func (c *Core) ListRecentFlows(ctx context.Context, userID, namespaceID string, limit int) ([]models.RecentFlow, error) {
+	if limit <= 0 || limit > MaxRecentFlows {
+		return nil, fmt.Errorf("limit must be between 1 and %d", MaxRecentFlows)
+	}
+
+	userUUID, namespaceUUID, err := parseUserAndNamespace(userID, namespaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	prefixes, hasFullAccess, err := c.getUserPrefixAccess(ctx, userID, namespaceID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get user prefix access: %w", err)
+	}
+
+	rows, err := c.store.ListRecentFlowsByUser(ctx, repo.ListRecentFlowsByUserParams{
+		UserUuid:      userUUID,
+		NamespaceUuid: namespaceUUID,
+		FullAccess:    hasFullAccess,
+		Prefixes:      prefixes,
+		Limit:         int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not get recently run flows: %w", err)
+	}
+
+	flows := make([]models.Flow, 0, len(rows))
+	for _, v := range rows {
+		flows = append(flows, flowFromDB(v.ID, v.Slug, v.Name, v.Description, v.PrefixName))
+	}
+
+	recent := make([]models.RecentFlow, 0, len(rows))
+	for i, f := range c.flows.getMany(namespaceID, flows) {
+		recent = append(recent, models.RecentFlow{Flow: f, LastRunAt: rows[i].LastRunAt})
+	}
+	return recent, nil
+}
+
+func parseUserAndNamespace(userID, namespaceID string) (uuid.UUID, uuid.UUID, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fmt.Errorf("invalid user UUID: %w", err)
+	}
+
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+	return userUUID, namespaceUUID, nil
+}
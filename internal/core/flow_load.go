@@ -0,0 +1,97 @@
+package core
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+)
+
+// DefaultFlowLoadConcurrency is the number of flow files imported at the same time
+const DefaultFlowLoadConcurrency = 8
+
+// ErrFlowsLoading is returned by operations that need every flow while flows are loaded for the first time
+var ErrFlowsLoading = errors.New("flows are still being loaded")
+
+// flowLoadTracker records the progress of loading the flows directory
+type flowLoadTracker struct {
+	mu     sync.Mutex
+	status models.FlowLoadStatus
+	// loaded is set once a load has finished without errors
+	loaded bool
+}
+
+func newFlowLoadTracker() *flowLoadTracker {
+	return &flowLoadTracker{status: models.FlowLoadStatus{State: models.FlowLoadPending}}
+}
+
+func (t *flowLoadTracker) start(namespaces int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.status = models.FlowLoadStatus{
+		State:      models.FlowLoadLoading,
+		Namespaces: namespaces,
+		StartedAt:  time.Now(),
+	}
+}
+
+// addFlows records that a namespace with total flow files is being loaded
+func (t *flowLoadTracker) addFlows(total int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.status.Total += total
+}
+
+func (t *flowLoadTracker) flowDone(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err != nil {
+		t.status.Failed++
+		return
+	}
+	t.status.Loaded++
+}
+
+func (t *flowLoadTracker) namespaceDone() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.status.NamespacesLoaded++
+}
+
+func (t *flowLoadTracker) finish(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.status.FinishedAt = time.Now()
+	if err != nil {
+		t.status.State = models.FlowLoadFailed
+		t.status.Error = err.Error()
+		return
+	}
+	t.status.State = models.FlowLoadFinished
+	t.loaded = true
+}
+
+func (t *flowLoadTracker) get() (models.FlowLoadStatus, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.status, t.loaded
+}
+
+// SetFlowLoadConcurrency sets how many flow files of a namespace are imported at the same time.
+// It must be called before flows are loaded. DefaultFlowLoadConcurrency is used if n is not positive.
+func (c *Core) SetFlowLoadConcurrency(n int) {
+	c.flowLoadConcurrency = n
+}
+
+// FlowLoadStatus returns the progress of the latest load of the flows directory
+func (c *Core) FlowLoadStatus() models.FlowLoadStatus {
+	status, _ := c.flowLoad.get()
+	return status
+}
+
+// FlowsLoaded reports whether flows have been loaded without errors since the server started
+func (c *Core) FlowsLoaded() bool {
+	_, loaded := c.flowLoad.get()
+	return loaded
+}
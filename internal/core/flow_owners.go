@@ -0,0 +1,44 @@
+package core
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/google/uuid"
+)
+
+// checkFlowOwners checks that the owners of the flow and the approvers of its actions are members
+// of its namespace
+func (c *Core) checkFlowOwners(ctx context.Context, f models.Flow, namespaceUUID uuid.UUID) error {
+	if len(f.Meta.Owners) == 0 && !f.HasApprovers() {
+		return nil
+	}
+
+	candidates, err := c.store.ListNamespaceOwnerCandidates(ctx, namespaceUUID)
+	if err != nil {
+		return fmt.Errorf("could not get namespace members: %w", err)
+	}
+
+	var users, groups []string
+	for _, m := range candidates {
+		if m.SubjectType == "group" {
+			groups = append(groups, m.Name)
+		} else {
+			users = append(users, m.Name)
+		}
+	}
+	return f.ValidateOwners(users, groups)
+}
+
+// withOwnerNotify returns the flow with its owners notified by email if it has no notifications
+// of its own. Owners are only notified when the email channel is enabled.
+func (c *Core) withOwnerNotify(f models.Flow) models.Flow {
+	if c.Messengers == nil {
+		return f
+	}
+	if _, ok := c.Messengers.Get("email"); !ok {
+		return f
+	}
+	return f.WithOwnerNotify()
+}
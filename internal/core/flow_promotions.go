@@ -0,0 +1,205 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/cvhariharan/flowctl/internal/repo"
+	"github.com/google/uuid"
+)
+
+// ErrPromotionConflict is returned when a flow is promoted to a namespace that already has a
+// flow with the same ID and conflicts are not renamed or replaced
+var ErrPromotionConflict = errors.New("a flow with the same id exists in the target namespace")
+
+// PromoteFlow copies a flow to another namespace and records the promotion. Only the flow file
+// is copied, secrets are created without values if CopySecretKeys is set and have to be set in
+// the target namespace before the flow runs.
+func (c *Core) PromoteFlow(ctx context.Context, flowID, namespaceID, userID string, req models.FlowPromotionRequest) (models.FlowPromotion, error) {
+	if req.TargetNamespaceID == namespaceID {
+		return models.FlowPromotion{}, fmt.Errorf("flow can't be promoted to its own namespace")
+	}
+
+	src, err := c.GetFlowByID(flowID, namespaceID)
+	if err != nil {
+		return models.FlowPromotion{}, err
+	}
+	srcRecord, err := c.getFlowRecord(ctx, flowID, namespaceID)
+	if err != nil {
+		return models.FlowPromotion{}, err
+	}
+
+	source, err := c.GetNamespaceByID(ctx, namespaceID)
+	if err != nil {
+		return models.FlowPromotion{}, fmt.Errorf("could not get namespace details for %s: %w", namespaceID, err)
+	}
+	target, err := c.GetNamespaceByID(ctx, req.TargetNamespaceID)
+	if err != nil {
+		return models.FlowPromotion{}, fmt.Errorf("could not get target namespace: %w", err)
+	}
+
+	f := src
+	f.Meta.ID = req.TargetFlowID
+	if f.Meta.ID == "" {
+		f.Meta.ID = src.Meta.ID
+	}
+	f.Meta.Namespace = target.Name
+	f.Meta.DBID = 0
+	f.Meta.SrcDir = ""
+	f.Meta.CommitSHA = ""
+
+	var revision *models.FlowRevision
+	exists := func(id string) bool { return c.flows.exists(req.TargetNamespaceID, id) }
+	switch {
+	case !exists(f.Meta.ID):
+		err = c.CreateFlow(ctx, f, req.TargetNamespaceID)
+	case req.OnConflict == models.PromotionConflictRename:
+		f.Meta.ID = models.PromotedFlowID(f.Meta.ID, exists)
+		err = c.CreateFlow(ctx, f, req.TargetNamespaceID)
+	case req.OnConflict == models.PromotionConflictReplace:
+		revision, err = c.RequestFlowUpdate(ctx, f, req.TargetNamespaceID, userID)
+	default:
+		return models.FlowPromotion{}, fmt.Errorf("%w: %s", ErrPromotionConflict, f.Meta.ID)
+	}
+	if err != nil {
+		return models.FlowPromotion{}, err
+	}
+
+	var secretKeys []string
+	if req.CopySecretKeys {
+		secretKeys, err = c.copyFlowSecretKeys(ctx, src.Meta.ID, namespaceID, f.Meta.ID, req.TargetNamespaceID)
+		if err != nil {
+			return models.FlowPromotion{}, err
+		}
+	}
+
+	targetRecord, err := c.getFlowRecord(ctx, f.Meta.ID, req.TargetNamespaceID)
+	if err != nil {
+		return models.FlowPromotion{}, err
+	}
+
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return models.FlowPromotion{}, fmt.Errorf("invalid user UUID: %w", err)
+	}
+
+	var revisionUUID uuid.NullUUID
+	if revision != nil {
+		revisionUUID.UUID, err = uuid.Parse(revision.ID)
+		revisionUUID.Valid = err == nil
+	}
+
+	p, err := c.store.CreateFlowPromotion(ctx, repo.CreateFlowPromotionParams{
+		SourceFlowID:   srcRecord.ID,
+		SourceChecksum: srcRecord.Checksum,
+		TargetFlowID:   targetRecord.ID,
+		RevisionUuid:   revisionUUID,
+		PromotedByUuid: userUUID,
+	})
+	if err != nil {
+		return models.FlowPromotion{}, fmt.Errorf("could not record promotion of flow %s: %w", flowID, err)
+	}
+
+	promotion := models.FlowPromotion{
+		ID:              p.ID,
+		SourceNamespace: source.Name,
+		SourceFlowID:    src.Meta.ID,
+		SourceChecksum:  p.SourceChecksum,
+		TargetNamespace: target.Name,
+		TargetFlowID:    f.Meta.ID,
+		SecretKeys:      secretKeys,
+		CreatedAt:       p.CreatedAt,
+	}
+	if revision != nil {
+		promotion.RevisionID = revision.ID
+	}
+	return promotion, nil
+}
+
+// copyFlowSecretKeys creates the secrets of a flow that are missing from another flow, with
+// empty values. It returns the keys that were created.
+func (c *Core) copyFlowSecretKeys(ctx context.Context, srcFlowID, srcNamespaceID, dstFlowID, dstNamespaceID string) ([]string, error) {
+	srcSecrets, err := c.ListFlowSecrets(ctx, srcFlowID, srcNamespaceID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get secrets of flow %s: %w", srcFlowID, err)
+	}
+	if len(srcSecrets) == 0 {
+		return nil, nil
+	}
+
+	dstSecrets, err := c.ListFlowSecrets(ctx, dstFlowID, dstNamespaceID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get secrets of flow %s: %w", dstFlowID, err)
+	}
+
+	dst, err := c.GetFlowByID(dstFlowID, dstNamespaceID)
+	if err != nil {
+		return nil, err
+	}
+	namespaceUUID, err := uuid.Parse(dstNamespaceID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	enc, err := c.keeper.Encrypt(ctx, []byte{})
+	if err != nil {
+		return nil, err
+	}
+
+	var created []string
+	for _, s := range srcSecrets {
+		if slices.ContainsFunc(dstSecrets, func(d models.FlowSecret) bool { return d.Key == s.Key }) {
+			continue
+		}
+
+		_, err := c.store.CreateFlowSecret(ctx, repo.CreateFlowSecretParams{
+			FlowID:         dst.Meta.DBID,
+			Key:            s.Key,
+			EncryptedValue: hex.EncodeToString(enc),
+			Description:    sql.NullString{String: s.Description, Valid: s.Description != ""},
+			Uuid:           namespaceUUID,
+		})
+		if err != nil {
+			return created, fmt.Errorf("could not create secret %s: %w", s.Key, err)
+		}
+		created = append(created, s.Key)
+	}
+	return created, nil
+}
+
+// ListFlowPromotions returns the promotions a flow was the source or the target of, newest first
+func (c *Core) ListFlowPromotions(ctx context.Context, flowID, namespaceID string) ([]models.FlowPromotion, error) {
+	fd, err := c.getFlowRecord(ctx, flowID, namespaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := c.store.ListFlowPromotions(ctx, fd.ID)
+	if err != nil {
+		return nil, fmt.Errorf("could not list promotions of flow %s: %w", flowID, err)
+	}
+
+	promotions := make([]models.FlowPromotion, 0, len(rows))
+	for _, v := range rows {
+		p := models.FlowPromotion{
+			ID:              v.ID,
+			SourceNamespace: v.SourceNamespace,
+			SourceFlowID:    v.SourceFlowSlug,
+			SourceChecksum:  v.SourceChecksum,
+			TargetNamespace: v.TargetNamespace,
+			TargetFlowID:    v.TargetFlowSlug,
+			PromotedBy:      v.PromotedByName,
+			CreatedAt:       v.CreatedAt,
+		}
+		if v.RevisionUuid.Valid {
+			p.RevisionID = v.RevisionUuid.UUID.String()
+		}
+		promotions = append(promotions, p)
+	}
+	return promotions, nil
+}
@@ -0,0 +1,422 @@
+package core
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/cvhariharan/flowctl/internal/repo"
+	"github.com/google/uuid"
+)
+
+var ErrSelfReview = errors.New("a revision cannot be reviewed by the user who requested it")
+
+// SetFlowReviewNamespaces sets the namespaces in which changes to flows have to be approved
+// by a reviewer before they are used
+func (c *Core) SetFlowReviewNamespaces(namespaces []string) {
+	c.reviewNamespaces = make(map[string]bool, len(namespaces))
+	for _, ns := range namespaces {
+		c.reviewNamespaces[ns] = true
+	}
+}
+
+// FlowReviewRequired reports whether changes to the flows of a namespace need a review
+func (c *Core) FlowReviewRequired(namespaceName string) bool {
+	return c.reviewNamespaces[namespaceName]
+}
+
+// RequestFlowUpdate updates a flow, or records the update as a pending revision if the
+// namespace requires flow changes to be reviewed. The revision is returned if one was created.
+func (c *Core) RequestFlowUpdate(ctx context.Context, f models.Flow, namespaceID string, userUUID string) (*models.FlowRevision, error) {
+	n, err := c.GetNamespaceByID(ctx, namespaceID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get namespace details for %s: %w", namespaceID, err)
+	}
+	if !c.FlowReviewRequired(n.Name) {
+		return nil, c.UpdateFlow(ctx, f, namespaceID)
+	}
+
+	f.Schedules = removeDuplicateSchedules(f.Schedules)
+
+	ns, err := c.store.GetNamespaceByName(ctx, n.Name)
+	if err != nil {
+		return nil, fmt.Errorf("could not get namespace %s: %w", n.Name, err)
+	}
+
+	existingFlow, err := c.store.GetFlowBySlug(ctx, repo.GetFlowBySlugParams{
+		Slug:     f.Meta.ID,
+		Uuid:     ns.Uuid,
+		IsActive: sql.NullBool{Bool: true, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not get existing flow: %w", err)
+	}
+
+	// A revision that could never be applied is rejected right away
+	if err := c.checkExecutorPolicy(ctx, f, ns.Uuid); err != nil {
+		return nil, err
+	}
+	if err := c.checkFlowOwners(ctx, f, ns.Uuid); err != nil {
+		return nil, err
+	}
+
+	userID, err := uuid.Parse(userUUID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user UUID: %w", err)
+	}
+	user, err := c.store.GetUserByUUID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get user %s: %w", userUUID, err)
+	}
+
+	format := detectFlowFormat(existingFlow.FilePath)
+	data, err := models.MarshalFlow(f, format)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal flow to %s: %w", format, err)
+	}
+	if current, err := os.ReadFile(existingFlow.FilePath); err == nil && string(current) == string(data) {
+		return nil, nil
+	}
+
+	rev, err := c.proposeFlowRevision(ctx, existingFlow.ID, ns.ID, data, format, "", sql.NullInt32{Int32: user.ID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	revision := flowRevisionToModel(rev)
+	revision.FlowID = f.Meta.ID
+	revision.FlowName = f.Meta.Name
+	revision.RequestedBy = user.Name
+	return &revision, nil
+}
+
+// proposeFlowRevision records data as the pending revision of a flow, replacing any revision
+// that was still waiting for review. A pending revision with the same content is reused.
+func (c *Core) proposeFlowRevision(ctx context.Context, flowID, namespaceID int32, data []byte, format models.FlowFormat, commit string, requestedBy sql.NullInt32) (repo.FlowRevision, error) {
+	h := sha256.New()
+	h.Write(data)
+	checksum := hex.EncodeToString(h.Sum(nil))
+
+	pending, err := c.store.GetPendingFlowRevision(ctx, flowID)
+	if err == nil && pending.Checksum == checksum {
+		return pending, nil
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return repo.FlowRevision{}, fmt.Errorf("could not get pending revision: %w", err)
+	}
+
+	if err := c.store.SupersedePendingFlowRevisions(ctx, flowID); err != nil {
+		return repo.FlowRevision{}, fmt.Errorf("could not supersede pending revisions: %w", err)
+	}
+
+	rev, err := c.store.CreateFlowRevision(ctx, repo.CreateFlowRevisionParams{
+		FlowID:      flowID,
+		NamespaceID: namespaceID,
+		Content:     string(data),
+		Format:      string(format),
+		Checksum:    checksum,
+		CommitSha:   sql.NullString{String: commit, Valid: commit != ""},
+		RequestedBy: requestedBy,
+	})
+	if err != nil {
+		return repo.FlowRevision{}, fmt.Errorf("could not create flow revision: %w", err)
+	}
+
+	return rev, nil
+}
+
+// holdFlowRevisions keeps the current version of flows that were changed in stagingDir and
+// records the changes as pending revisions. Flows that don't exist yet are not held.
+// c.loadMu must be held.
+func (c *Core) holdFlowRevisions(ctx context.Context, ns repo.Namespace, namespaceDir, stagingDir, commit string) error {
+	entries, err := os.ReadDir(stagingDir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		currentPath := findFlowFile(filepath.Join(namespaceDir, entry.Name()))
+		newPath := findFlowFile(filepath.Join(stagingDir, entry.Name()))
+		if currentPath == "" || newPath == "" {
+			continue
+		}
+
+		current, err := os.ReadFile(currentPath)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(newPath)
+		if err != nil {
+			return err
+		}
+		if string(current) == string(data) {
+			continue
+		}
+
+		currentFlow, err := models.UnmarshalFlow(current, detectFlowFormat(currentPath))
+		if err != nil {
+			return fmt.Errorf("could not parse current flow in %s: %w", currentPath, err)
+		}
+		fd, err := c.store.GetFlowBySlug(ctx, repo.GetFlowBySlugParams{
+			Slug:     currentFlow.Meta.ID,
+			Uuid:     ns.Uuid,
+			IsActive: sql.NullBool{Valid: false},
+		})
+		if err != nil {
+			// The current file was never imported, there is nothing to hold
+			continue
+		}
+
+		if _, err := c.proposeFlowRevision(ctx, fd.ID, ns.ID, data, detectFlowFormat(newPath), commit, sql.NullInt32{}); err != nil {
+			return fmt.Errorf("could not record revision of flow %s: %w", currentFlow.Meta.ID, err)
+		}
+
+		if err := os.Remove(newPath); err != nil {
+			return err
+		}
+		if err := copyFlowFile(currentPath, filepath.Join(stagingDir, entry.Name(), filepath.Base(currentPath))); err != nil {
+			return err
+		}
+		log.Printf("change to flow %s in namespace %s is waiting for review", currentFlow.Meta.ID, ns.Name)
+	}
+
+	return nil
+}
+
+// GetFlowRevision returns a revision of a flow along with its content
+func (c *Core) GetFlowRevision(ctx context.Context, revisionID string, namespaceID string) (models.FlowRevision, error) {
+	rev, err := c.getFlowRevision(ctx, revisionID, namespaceID)
+	if err != nil {
+		return models.FlowRevision{}, err
+	}
+
+	revision := flowRevisionToModel(repo.FlowRevision{
+		Uuid:       rev.Uuid,
+		Status:     rev.Status,
+		Format:     rev.Format,
+		Checksum:   rev.Checksum,
+		CommitSha:  rev.CommitSha,
+		Comment:    rev.Comment,
+		CreatedAt:  rev.CreatedAt,
+		ReviewedAt: rev.ReviewedAt,
+	})
+	revision.FlowID = rev.FlowSlug
+	revision.FlowName = rev.FlowName
+	revision.RequestedBy = rev.RequestedByName
+	revision.ReviewedBy = rev.ReviewedByName
+	revision.Content = rev.Content
+	return revision, nil
+}
+
+func (c *Core) getFlowRevision(ctx context.Context, revisionID string, namespaceID string) (repo.GetFlowRevisionByUUIDRow, error) {
+	revisionUUID, err := uuid.Parse(revisionID)
+	if err != nil {
+		return repo.GetFlowRevisionByUUIDRow{}, fmt.Errorf("invalid revision UUID: %w", err)
+	}
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return repo.GetFlowRevisionByUUIDRow{}, fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	rev, err := c.store.GetFlowRevisionByUUID(ctx, repo.GetFlowRevisionByUUIDParams{
+		Uuid:   revisionUUID,
+		Uuid_2: namespaceUUID,
+	})
+	if err != nil {
+		return repo.GetFlowRevisionByUUIDRow{}, fmt.Errorf("could not get revision %s: %w", revisionID, err)
+	}
+	return rev, nil
+}
+
+// ListFlowRevisions returns the revisions of flows in a namespace, newest first, optionally
+// filtered by status
+func (c *Core) ListFlowRevisions(ctx context.Context, namespaceID string, status string, limit, offset int) ([]models.FlowRevision, int64, int64, error) {
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return nil, -1, -1, fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	rows, err := c.store.ListFlowRevisions(ctx, repo.ListFlowRevisionsParams{
+		Uuid:    namespaceUUID,
+		Column2: status,
+		Limit:   int32(limit),
+		Offset:  int32(offset),
+	})
+	if err != nil {
+		return nil, -1, -1, fmt.Errorf("could not list flow revisions: %w", err)
+	}
+
+	revisions := make([]models.FlowRevision, 0, len(rows))
+	var pageCount, totalCount int64
+	for _, row := range rows {
+		revision := flowRevisionToModel(repo.FlowRevision{
+			Uuid:       row.Uuid,
+			Status:     row.Status,
+			Checksum:   row.Checksum,
+			CommitSha:  row.CommitSha,
+			Comment:    row.Comment,
+			CreatedAt:  row.CreatedAt,
+			ReviewedAt: row.ReviewedAt,
+		})
+		revision.FlowID = row.FlowSlug
+		revision.FlowName = row.FlowName
+		revision.RequestedBy = row.RequestedByName
+		revision.ReviewedBy = row.ReviewedByName
+		revisions = append(revisions, revision)
+
+		pageCount = row.PageCount
+		totalCount = row.TotalCount
+	}
+
+	return revisions, pageCount, totalCount, nil
+}
+
+// ReviewFlowRevision approves or rejects a pending revision. An approved revision is written to
+// the flow file and becomes the version used by triggers and schedules. A comment is mandatory
+// when rejecting.
+func (c *Core) ReviewFlowRevision(ctx context.Context, revisionID, reviewerUUID string, status models.FlowRevisionStatus, comment string, namespaceID string) error {
+	if status != models.FlowRevisionStatusApproved && status != models.FlowRevisionStatusRejected {
+		return fmt.Errorf("invalid review status %s", status)
+	}
+	if status == models.FlowRevisionStatusRejected && comment == "" {
+		return fmt.Errorf("a comment is required when rejecting a revision")
+	}
+
+	reviewerID, err := uuid.Parse(reviewerUUID)
+	if err != nil {
+		return fmt.Errorf("invalid user UUID: %w", err)
+	}
+	reviewer, err := c.store.GetUserByUUID(ctx, reviewerID)
+	if err != nil {
+		return fmt.Errorf("could not get user %s: %w", reviewerUUID, err)
+	}
+
+	// Approving writes to the flows directory, which must not race with syncs and reloads
+	c.loadMu.Lock()
+	defer c.loadMu.Unlock()
+
+	rev, err := c.getFlowRevision(ctx, revisionID, namespaceID)
+	if err != nil {
+		return err
+	}
+	if rev.Status != string(models.FlowRevisionStatusPending) {
+		return fmt.Errorf("revision has already been %s", rev.Status)
+	}
+	if rev.RequestedBy.Valid && rev.RequestedBy.Int32 == reviewer.ID {
+		return ErrSelfReview
+	}
+
+	if status == models.FlowRevisionStatusApproved {
+		if err := c.applyFlowRevision(ctx, rev, namespaceID); err != nil {
+			return fmt.Errorf("could not apply revision: %w", err)
+		}
+	}
+
+	if _, err := c.store.ReviewFlowRevision(ctx, repo.ReviewFlowRevisionParams{
+		ID:         rev.ID,
+		Status:     string(status),
+		ReviewedBy: sql.NullInt32{Int32: reviewer.ID, Valid: true},
+		Comment:    comment,
+	}); err != nil {
+		return fmt.Errorf("could not update revision %s: %w", revisionID, err)
+	}
+
+	return nil
+}
+
+// applyFlowRevision writes the content of a revision to the flow file and reloads the flow.
+// c.loadMu must be held.
+func (c *Core) applyFlowRevision(ctx context.Context, rev repo.GetFlowRevisionByUUIDRow, namespaceID string) error {
+	n, err := c.GetNamespaceByID(ctx, namespaceID)
+	if err != nil {
+		return fmt.Errorf("could not get namespace details for %s: %w", namespaceID, err)
+	}
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	existingFlow, err := c.store.GetFlowBySlug(ctx, repo.GetFlowBySlugParams{
+		Slug:     rev.FlowSlug,
+		Uuid:     namespaceUUID,
+		IsActive: sql.NullBool{Bool: true, Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("could not get existing flow: %w", err)
+	}
+
+	flowFilePath := existingFlow.FilePath
+	isSub, err := isSubpath(c.flowDirectory, flowFilePath)
+	if err != nil || !isSub {
+		return fmt.Errorf("cannot write to file outside flows root: %s", flowFilePath)
+	}
+
+	f, err := models.UnmarshalFlow([]byte(rev.Content), models.FlowFormat(rev.Format))
+	if err != nil {
+		return fmt.Errorf("could not parse revision: %w", err)
+	}
+	if err := f.Validate(); err != nil {
+		return fmt.Errorf("revision is not a valid flow: %w", err)
+	}
+	if f.Meta.ID != rev.FlowSlug {
+		return fmt.Errorf("revision changes the flow id from %s to %s", rev.FlowSlug, f.Meta.ID)
+	}
+	if err := c.checkExecutorPolicy(ctx, f, namespaceUUID); err != nil {
+		return err
+	}
+	if err := c.checkFlowOwners(ctx, f, namespaceUUID); err != nil {
+		return err
+	}
+
+	// Revisions from git can use a different format than the flow file
+	data := []byte(rev.Content)
+	if format := detectFlowFormat(flowFilePath); string(format) != rev.Format {
+		data, err = models.MarshalFlow(f, format)
+		if err != nil {
+			return fmt.Errorf("could not marshal flow to %s: %w", format, err)
+		}
+	}
+
+	if err := os.WriteFile(flowFilePath, data, 0644); err != nil {
+		return fmt.Errorf("could not write flow file: %w", err)
+	}
+
+	if err := c.flowStore.Push(ctx, filepath.Dir(flowFilePath)); err != nil {
+		return fmt.Errorf("could not store flow: %w", err)
+	}
+
+	importedFlow, namespaceUUIDStr, err := c.importFlowFromFile(ctx, flowFilePath, n.Name)
+	if err != nil {
+		return fmt.Errorf("could not import flow after review: %w", err)
+	}
+
+	c.flows.set(namespaceUUIDStr, importedFlow)
+	return nil
+}
+
+func flowRevisionToModel(rev repo.FlowRevision) models.FlowRevision {
+	revision := models.FlowRevision{
+		ID:        rev.Uuid.String(),
+		Status:    models.FlowRevisionStatus(rev.Status),
+		Format:    models.FlowFormat(rev.Format),
+		Checksum:  rev.Checksum,
+		CommitSHA: rev.CommitSha.String,
+		Comment:   rev.Comment,
+		CreatedAt: rev.CreatedAt,
+	}
+	if rev.ReviewedAt.Valid {
+		revision.ReviewedAt = &rev.ReviewedAt.Time
+	}
+	return revision
+}
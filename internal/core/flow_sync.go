@@ -0,0 +1,135 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// flowCommitFile is written to the directory of a namespace synced from git and holds the
+// commit its flows were synced from
+const flowCommitFile = ".flowctl-commit"
+
+// readFlowCommit returns the commit the flows of a namespace directory were synced from,
+// or an empty string if the namespace is not synced from git
+func readFlowCommit(namespaceDir string) string {
+	data, err := os.ReadFile(filepath.Join(namespaceDir, flowCommitFile))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// SyncNamespaceFlows replaces the flows of a namespace with the flows in srcDir, which were
+// checked out at commit, and reloads all flows. The namespace directory is swapped in one step
+// so that flows are never loaded from a partially copied directory.
+func (c *Core) SyncNamespaceFlows(ctx context.Context, namespace string, srcDir string, commit string) error {
+	ns, err := c.store.GetNamespaceByName(ctx, namespace)
+	if err != nil {
+		return fmt.Errorf("could not get namespace %s: %w", namespace, err)
+	}
+
+	c.loadMu.Lock()
+	defer c.loadMu.Unlock()
+
+	namespaceDir := filepath.Join(c.flowDirectory, namespace)
+	if isSub, err := isSubpath(c.flowDirectory, namespaceDir); err != nil || !isSub {
+		return fmt.Errorf("namespace directory is outside the flows root: %s", namespaceDir)
+	}
+
+	// The staging directory is hidden so that it is never loaded as a namespace
+	suffix := time.Now().Format("20060102150405")
+	stagingDir := filepath.Join(c.flowDirectory, fmt.Sprintf(".%s.sync-%s", namespace, suffix))
+	if err := copyFlowTree(srcDir, stagingDir); err != nil {
+		os.RemoveAll(stagingDir)
+		return fmt.Errorf("could not copy flows: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(stagingDir, flowCommitFile), []byte(commit+"\n"), 0644); err != nil {
+		os.RemoveAll(stagingDir)
+		return fmt.Errorf("could not write commit file: %w", err)
+	}
+	if c.FlowReviewRequired(namespace) {
+		if err := c.holdFlowRevisions(ctx, ns, namespaceDir, stagingDir, commit); err != nil {
+			os.RemoveAll(stagingDir)
+			return fmt.Errorf("could not hold flow changes for review: %w", err)
+		}
+	}
+
+	oldDir := filepath.Join(c.flowDirectory, fmt.Sprintf(".%s.old-%s", namespace, suffix))
+	if err := os.Rename(namespaceDir, oldDir); err != nil && !os.IsNotExist(err) {
+		os.RemoveAll(stagingDir)
+		return fmt.Errorf("could not move namespace directory: %w", err)
+	}
+	if err := os.Rename(stagingDir, namespaceDir); err != nil {
+		os.Rename(oldDir, namespaceDir)
+		os.RemoveAll(stagingDir)
+		return fmt.Errorf("could not replace namespace directory: %w", err)
+	}
+	if err := os.RemoveAll(oldDir); err != nil {
+		log.Printf("could not remove old flows of namespace %s: %v", namespace, err)
+	}
+
+	if err := c.flowStore.Push(ctx, namespaceDir); err != nil {
+		return fmt.Errorf("could not store flows: %w", err)
+	}
+
+	return c.loadFlows(ctx)
+}
+
+// copyFlowTree copies the regular files and directories in src to dst. Git metadata is
+// skipped, as are symlinks since they could point outside the flows directory.
+func copyFlowTree(src string, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return os.MkdirAll(target, 0755)
+		}
+		if !d.Type().IsRegular() {
+			log.Printf("skipping %s while syncing flows, only regular files are copied", path)
+			return nil
+		}
+
+		return copyFlowFile(path, target)
+	})
+}
+
+func copyFlowFile(src string, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,73 @@
+package core
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+)
+
+//go:embed flow_templates/*.yaml
+var flowTemplateFS embed.FS
+
+var ErrFlowTemplateNotFound = errors.New("flow template not found")
+
+// ListFlowTemplates returns the starter flows embedded in the binary, sorted by name
+func (c *Core) ListFlowTemplates() ([]models.FlowTemplate, error) {
+	files, err := flowTemplateFS.ReadDir("flow_templates")
+	if err != nil {
+		return nil, fmt.Errorf("could not read flow templates: %w", err)
+	}
+
+	templates := make([]models.FlowTemplate, 0, len(files))
+	for _, file := range files {
+		t, err := readFlowTemplate(path.Join("flow_templates", file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, t)
+	}
+
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name < templates[j].Name
+	})
+	return templates, nil
+}
+
+// GetFlowTemplate returns the starter flow with the given ID
+func (c *Core) GetFlowTemplate(id string) (models.FlowTemplate, error) {
+	templates, err := c.ListFlowTemplates()
+	if err != nil {
+		return models.FlowTemplate{}, err
+	}
+
+	for _, t := range templates {
+		if t.ID == id {
+			return t, nil
+		}
+	}
+	return models.FlowTemplate{}, fmt.Errorf("%w: %s", ErrFlowTemplateNotFound, id)
+}
+
+func readFlowTemplate(name string) (models.FlowTemplate, error) {
+	data, err := flowTemplateFS.ReadFile(name)
+	if err != nil {
+		return models.FlowTemplate{}, fmt.Errorf("could not read flow template %s: %w", name, err)
+	}
+
+	f, err := models.UnmarshalFlow(data, models.FlowFormatYAML)
+	if err != nil {
+		return models.FlowTemplate{}, fmt.Errorf("could not parse flow template %s: %w", name, err)
+	}
+
+	return models.FlowTemplate{
+		ID:          f.Meta.ID,
+		Name:        f.Meta.Name,
+		Description: f.Meta.Description,
+		Flow:        f,
+		Content:     string(data),
+	}, nil
+}
@@ -0,0 +1,114 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/cvhariharan/flowctl/internal/repo"
+	"github.com/google/uuid"
+)
+
+// MaxTriggerDepth limits the number of executions in a chain of triggers, so that flows that
+// trigger each other don't run forever
+const MaxTriggerDepth = 10
+
+// ErrTriggerDepthExceeded is returned when a triggered execution would make a chain of triggers
+// longer than MaxTriggerDepth
+var ErrTriggerDepthExceeded = errors.New("trigger chain is too long")
+
+// RunFlowTriggers queues the flows of a namespace with a trigger on the flow of a finished
+// execution. Triggered executions run as the system user, like scheduled ones, with the inputs
+// mapped from the inputs and outputs of the finished execution. Password inputs of the finished
+// execution are masked. An execution triggers each flow at most once, also when it is retried.
+func (c *Core) RunFlowTriggers(ctx context.Context, execID, status, flowSlug, namespaceID string) error {
+	triggered := c.flows.filter(namespaceID, func(f models.Flow) bool {
+		for _, t := range f.Triggers {
+			if t.Fires(flowSlug, models.ExecutionStatus(status)) {
+				return true
+			}
+		}
+		return false
+	})
+	if len(triggered) == 0 {
+		return nil
+	}
+
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	var depth int32
+	run, err := c.store.GetFlowTriggerRun(ctx, execID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("could not get trigger of exec %s: %w", execID, err)
+	}
+	if err == nil {
+		depth = run.Depth
+	}
+	if depth >= MaxTriggerDepth {
+		return fmt.Errorf("%w: exec %s is execution %d of the chain", ErrTriggerDepthExceeded, execID, depth)
+	}
+
+	input, err := c.getExecutionInput(ctx, execID, namespaceUUID)
+	if err != nil {
+		return err
+	}
+	input = maskInputs(input, c.passwordInputs(flowSlug, namespaceID))
+
+	outputs, err := c.GetExecutionOutputs(ctx, execID, namespaceID)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, f := range triggered {
+		for _, t := range f.Triggers {
+			if !t.Fires(flowSlug, models.ExecutionStatus(status)) {
+				continue
+			}
+			if err := c.runFlowTrigger(ctx, f, t, execID, depth+1, input, outputs.Outputs, namespaceID); err != nil {
+				errs = append(errs, fmt.Errorf("could not trigger flow %s: %w", f.Meta.ID, err))
+			}
+			break
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// runFlowTrigger queues an execution of f for a trigger fired by the execution upstreamExecID
+func (c *Core) runFlowTrigger(ctx context.Context, f models.Flow, t models.FlowTrigger, upstreamExecID string, depth int32, input, outputs map[string]any, namespaceID string) error {
+	mapped, err := t.MapInputs(f, input, outputs)
+	if err != nil {
+		return err
+	}
+	if verr := f.ValidateInput(mapped); verr != nil {
+		return verr
+	}
+
+	execID := uuid.NewString()
+	_, err = c.store.CreateFlowTriggerRun(ctx, repo.CreateFlowTriggerRunParams{
+		UpstreamExecID: upstreamExecID,
+		ExecID:         execID,
+		FlowID:         f.Meta.DBID,
+		Depth:          depth,
+	})
+	// The flow was already triggered by an earlier attempt of the upstream execution
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not record trigger: %w", err)
+	}
+
+	if _, err := c.QueueFlowExecutionWithExecID(ctx, f, mapped, SystemUserUUID, namespaceID, execID, nil); err != nil {
+		if derr := c.store.DeleteFlowTriggerRun(ctx, execID); derr != nil {
+			err = errors.Join(err, derr)
+		}
+		return err
+	}
+	return nil
+}
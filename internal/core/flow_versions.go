@@ -0,0 +1,136 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/cvhariharan/flowctl/internal/repo"
+	"github.com/google/uuid"
+)
+
+// FlowVersionLatest refers to the latest version of a flow when diffing versions
+const FlowVersionLatest = "latest"
+
+var ErrFlowVersionNoContent = errors.New("version was recorded before flow contents were stored and can't be diffed")
+
+// ListFlowVersions returns the versions of a flow, newest first
+func (c *Core) ListFlowVersions(ctx context.Context, flowID string, namespaceID string, limit, offset int) ([]models.FlowVersion, int64, int64, error) {
+	fd, err := c.getFlowRecord(ctx, flowID, namespaceID)
+	if err != nil {
+		return nil, -1, -1, err
+	}
+
+	rows, err := c.store.ListFlowVersions(ctx, repo.ListFlowVersionsParams{
+		FlowID: fd.ID,
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		return nil, -1, -1, fmt.Errorf("could not list versions of flow %s: %w", flowID, err)
+	}
+
+	versions := make([]models.FlowVersion, 0, len(rows))
+	var pageCount, totalCount int64
+	for _, row := range rows {
+		versions = append(versions, models.FlowVersion{
+			ID:         row.ID,
+			Checksum:   row.Checksum,
+			CommitSHA:  row.CommitSha.String,
+			CreatedAt:  row.CreatedAt,
+			HasContent: row.HasContent,
+		})
+		pageCount = row.PageCount
+		totalCount = row.TotalCount
+	}
+
+	return versions, pageCount, totalCount, nil
+}
+
+// DiffFlowVersions returns the changes between two versions of a flow. A version is referred to
+// by its ID, by "latest", or by the ID of a revision of the flow waiting for review.
+func (c *Core) DiffFlowVersions(ctx context.Context, flowID string, namespaceID string, from, to string) (models.FlowDiff, error) {
+	fd, err := c.getFlowRecord(ctx, flowID, namespaceID)
+	if err != nil {
+		return models.FlowDiff{}, err
+	}
+
+	fromContent, fromFormat, err := c.getFlowVersionContent(ctx, fd.ID, namespaceID, from)
+	if err != nil {
+		return models.FlowDiff{}, fmt.Errorf("could not get version %s: %w", from, err)
+	}
+	toContent, toFormat, err := c.getFlowVersionContent(ctx, fd.ID, namespaceID, to)
+	if err != nil {
+		return models.FlowDiff{}, fmt.Errorf("could not get version %s: %w", to, err)
+	}
+
+	fromFlow, err := models.UnmarshalFlow([]byte(fromContent), fromFormat)
+	if err != nil {
+		return models.FlowDiff{}, fmt.Errorf("could not parse version %s: %w", from, err)
+	}
+	toFlow, err := models.UnmarshalFlow([]byte(toContent), toFormat)
+	if err != nil {
+		return models.FlowDiff{}, fmt.Errorf("could not parse version %s: %w", to, err)
+	}
+
+	d := diffFlows(fromFlow, toFlow)
+	d.From = from
+	d.To = to
+	d.Text = unifiedDiff(from, to, fromContent, toContent)
+	return d, nil
+}
+
+// getFlowRecord returns the database record of a flow, including deactivated flows
+func (c *Core) getFlowRecord(ctx context.Context, flowID string, namespaceID string) (repo.Flow, error) {
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return repo.Flow{}, fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	fd, err := c.store.GetFlowBySlug(ctx, repo.GetFlowBySlugParams{
+		Slug:     flowID,
+		Uuid:     namespaceUUID,
+		IsActive: sql.NullBool{Valid: false},
+	})
+	if err != nil {
+		return repo.Flow{}, fmt.Errorf("could not get flow %s: %w", flowID, err)
+	}
+	return fd, nil
+}
+
+// getFlowVersionContent returns the flow file of a version of a flow
+func (c *Core) getFlowVersionContent(ctx context.Context, flowDBID int32, namespaceID string, ref string) (string, models.FlowFormat, error) {
+	if _, err := uuid.Parse(ref); err == nil {
+		rev, err := c.getFlowRevision(ctx, ref, namespaceID)
+		if err != nil {
+			return "", "", err
+		}
+		if rev.FlowID != flowDBID {
+			return "", "", fmt.Errorf("revision %s is not a revision of this flow", ref)
+		}
+		return rev.Content, models.FlowFormat(rev.Format), nil
+	}
+
+	var v repo.FlowVersion
+	var err error
+	if ref == FlowVersionLatest {
+		v, err = c.store.GetLatestFlowVersion(ctx, flowDBID)
+	} else {
+		id, perr := strconv.ParseInt(ref, 10, 32)
+		if perr != nil {
+			return "", "", fmt.Errorf("invalid version %s", ref)
+		}
+		v, err = c.store.GetFlowVersion(ctx, repo.GetFlowVersionParams{ID: int32(id), FlowID: flowDBID})
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	if !v.Content.Valid {
+		return "", "", ErrFlowVersionNoContent
+	}
+	return v.Content.String, models.FlowFormat(v.Format.String), nil
+}
@@ -0,0 +1,173 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/fsnotify/fsnotify"
+)
+
+// flowWatchDepth is the deepest directory that is watched: flows/<namespace>/<flow>
+const flowWatchDepth = 2
+
+// WatchFlows reloads the flows of a namespace whenever files in its directory change, until
+// ctx is done. Changes are collected for debounce before reloading so that saving several
+// files at once reloads a namespace only once.
+func (c *Core) WatchFlows(ctx context.Context, debounce time.Duration) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("could not create flows watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	if err := c.addFlowWatches(watcher, c.flowDirectory); err != nil {
+		return fmt.Errorf("could not watch flows directory: %w", err)
+	}
+
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	pending := make(map[string]struct{})
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+
+			namespace, depth, ok := c.flowEventNamespace(event.Name)
+			if !ok {
+				continue
+			}
+
+			// fsnotify doesn't watch recursively, new namespace and flow directories are added as
+			// they appear. Removed directories are dropped from the watcher by fsnotify.
+			if event.Has(fsnotify.Create) && depth <= flowWatchDepth {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					if err := c.addFlowWatches(watcher, event.Name); err != nil {
+						log.Printf("could not watch %s: %v", event.Name, err)
+					}
+				}
+			}
+
+			// Only flow files and directories affect which flows are loaded
+			if depth > flowWatchDepth && !isFlowFile(event.Name) {
+				continue
+			}
+
+			pending[namespace] = struct{}{}
+			timer.Reset(debounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			log.Printf("error watching flows directory: %v", err)
+		case <-timer.C:
+			for namespace := range pending {
+				if err := c.reloadNamespaceFlows(ctx, namespace); err != nil {
+					log.Printf("could not reload flows of namespace %s: %v", namespace, err)
+				}
+			}
+			clear(pending)
+
+			// Scheduled jobs hold a copy of their flow, resync them so that schedules of
+			// changed and removed flows take effect right away
+			if err := c.scheduler.SyncScheduledJobs(ctx); err != nil {
+				log.Printf("could not sync scheduled flows: %v", err)
+			}
+		}
+	}
+}
+
+// addFlowWatches watches dir and its subdirectories down to the flow directories.
+// Hidden directories are skipped.
+func (c *Core) addFlowWatches(watcher *fsnotify.Watcher, dir string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// The directory may have been removed while walking
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(c.flowDirectory, path)
+		if err != nil {
+			return err
+		}
+		depth := 0
+		if rel != "." {
+			if strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			depth = len(strings.Split(rel, string(filepath.Separator)))
+		}
+		if depth > flowWatchDepth {
+			return filepath.SkipDir
+		}
+
+		return watcher.Add(path)
+	})
+}
+
+// flowEventNamespace returns the namespace a path in the flows directory belongs to and how
+// deep the path is below the flows directory. Paths in hidden directories are ignored.
+func (c *Core) flowEventNamespace(path string) (string, int, bool) {
+	rel, err := filepath.Rel(c.flowDirectory, path)
+	if err != nil || rel == "." {
+		return "", 0, false
+	}
+
+	parts := strings.Split(rel, string(filepath.Separator))
+	if parts[0] == ".." {
+		return "", 0, false
+	}
+	for _, p := range parts {
+		if strings.HasPrefix(p, ".") {
+			return "", 0, false
+		}
+	}
+
+	return parts[0], len(parts), true
+}
+
+// reloadNamespaceFlows re-imports the flows of a namespace from its directory and replaces its
+// flows in memory. If the directory was removed, all flows of the namespace are unloaded.
+func (c *Core) reloadNamespaceFlows(ctx context.Context, namespace string) error {
+	c.loadMu.Lock()
+	defer c.loadMu.Unlock()
+
+	ns, err := c.store.GetNamespaceByName(ctx, namespace)
+	if err != nil {
+		return fmt.Errorf("error getting namespace %s: %w", namespace, err)
+	}
+
+	flows := make(map[string]models.Flow)
+	namespaceDir := filepath.Join(c.flowDirectory, namespace)
+	if info, err := os.Stat(namespaceDir); err == nil && info.IsDir() {
+		_, flows, err = c.processNamespaceFlows(ctx, namespaceDir, nil)
+		if err != nil {
+			return err
+		}
+	} else if err := c.store.MarkAllFlowsInactiveForNamespace(ctx, ns.Uuid); err != nil {
+		return fmt.Errorf("error marking flows inactive for namespace %s: %w", namespace, err)
+	}
+
+	c.flows.replaceNamespace(ns.Uuid.String(), flows)
+
+	log.Printf("reloaded %d flows of namespace %s", len(flows), namespace)
+	return nil
+}
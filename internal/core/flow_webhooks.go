@@ -0,0 +1,189 @@
+package core
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/cvhariharan/flowctl/internal/repo"
+)
+
+var (
+	// ErrWebhookNotFound is returned for requests to webhooks that don't exist. Unknown namespaces,
+	// flows and tokens return the same error so that webhooks can't be discovered.
+	ErrWebhookNotFound = errors.New("webhook not found")
+	// ErrNoWebhookTrigger is returned when creating a webhook for a flow without a webhook trigger
+	ErrNoWebhookTrigger = errors.New("flow has no webhook trigger")
+	// ErrInvalidWebhookSignature is returned for requests whose signature doesn't match their body
+	ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+	// ErrInvalidWebhookPayload is returned for requests that can't be mapped to the inputs of the flow
+	ErrInvalidWebhookPayload = errors.New("invalid webhook payload")
+)
+
+// CreateFlowWebhook creates the webhook of a flow, or replaces the token and the secret of its
+// webhook. The token and the secret are returned, only the hash of the token and the encrypted
+// secret are stored.
+func (c *Core) CreateFlowWebhook(ctx context.Context, flowID string, namespaceID string) (models.FlowWebhook, string, string, error) {
+	f, err := c.GetFlowByID(flowID, namespaceID)
+	if err != nil {
+		return models.FlowWebhook{}, "", "", fmt.Errorf("flow not found: %w", err)
+	}
+	if f.Webhook == nil {
+		return models.FlowWebhook{}, "", "", ErrNoWebhookTrigger
+	}
+
+	token, err := generateWebhookKey()
+	if err != nil {
+		return models.FlowWebhook{}, "", "", err
+	}
+	secret, err := generateWebhookKey()
+	if err != nil {
+		return models.FlowWebhook{}, "", "", err
+	}
+
+	enc, err := c.keeper.Encrypt(ctx, []byte(secret))
+	if err != nil {
+		return models.FlowWebhook{}, "", "", err
+	}
+
+	w, err := c.store.UpsertFlowWebhook(ctx, repo.UpsertFlowWebhookParams{
+		FlowID:          f.Meta.DBID,
+		TokenHash:       hashAPIToken(token),
+		EncryptedSecret: hex.EncodeToString(enc),
+	})
+	if err != nil {
+		return models.FlowWebhook{}, "", "", fmt.Errorf("could not create webhook: %w", err)
+	}
+
+	return models.RepoFlowWebhookToFlowWebhook(f.Meta.ID, w), token, secret, nil
+}
+
+// GetFlowWebhook returns the webhook of a flow
+func (c *Core) GetFlowWebhook(ctx context.Context, flowID string, namespaceID string) (models.FlowWebhook, error) {
+	f, err := c.GetFlowByID(flowID, namespaceID)
+	if err != nil {
+		return models.FlowWebhook{}, fmt.Errorf("flow not found: %w", err)
+	}
+
+	w, err := c.store.GetFlowWebhook(ctx, f.Meta.DBID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.FlowWebhook{}, ErrWebhookNotFound
+	}
+	if err != nil {
+		return models.FlowWebhook{}, fmt.Errorf("could not get webhook: %w", err)
+	}
+
+	return models.RepoFlowWebhookToFlowWebhook(f.Meta.ID, w), nil
+}
+
+// DeleteFlowWebhook deletes the webhook of a flow, requests to it are rejected from then on
+func (c *Core) DeleteFlowWebhook(ctx context.Context, flowID string, namespaceID string) error {
+	f, err := c.GetFlowByID(flowID, namespaceID)
+	if err != nil {
+		return fmt.Errorf("flow not found: %w", err)
+	}
+
+	n, err := c.store.DeleteFlowWebhook(ctx, f.Meta.DBID)
+	if err != nil {
+		return fmt.Errorf("could not delete webhook: %w", err)
+	}
+	if n == 0 {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+// TriggerFlowWebhook queues an execution of a flow as the system user for a request to its
+// webhook. The request is authenticated by the token in the URL of the webhook and, if the
+// webhook trigger of the flow sets a signature header, by the signature of its body.
+func (c *Core) TriggerFlowWebhook(ctx context.Context, namespace, flowID, token string, header http.Header, query map[string][]string, body []byte) (string, error) {
+	ns, err := c.GetNamespaceByName(ctx, namespace)
+	if err != nil {
+		return "", ErrWebhookNotFound
+	}
+	f, err := c.GetFlowByID(flowID, ns.ID)
+	if err != nil || f.Webhook == nil {
+		return "", ErrWebhookNotFound
+	}
+
+	w, err := c.store.GetFlowWebhook(ctx, f.Meta.DBID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrWebhookNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("could not get webhook: %w", err)
+	}
+	if subtle.ConstantTimeCompare([]byte(hashAPIToken(token)), []byte(w.TokenHash)) != 1 {
+		return "", ErrWebhookNotFound
+	}
+
+	if f.Webhook.SignatureHeader != "" {
+		enc, err := hex.DecodeString(w.EncryptedSecret)
+		if err != nil {
+			return "", fmt.Errorf("could not decode webhook secret: %w", err)
+		}
+		secret, err := c.keeper.Decrypt(ctx, enc)
+		if err != nil {
+			return "", fmt.Errorf("could not decrypt webhook secret: %w", err)
+		}
+		if !verifyWebhookSignature(header.Get(f.Webhook.SignatureHeader), body, secret) {
+			return "", ErrInvalidWebhookSignature
+		}
+	}
+
+	var payload any
+	if err := json.Unmarshal(body, &payload); err != nil {
+		payload = string(body)
+	}
+	inputs, err := f.Webhook.MapInputs(f, payload, header, query)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidWebhookPayload, err)
+	}
+	if verr := f.ValidateInput(inputs); verr != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidWebhookPayload, verr)
+	}
+
+	execID, err := c.QueueFlowExecution(ctx, f, inputs, SystemUserUUID, ns.ID, nil)
+	if err != nil {
+		return "", fmt.Errorf("could not queue flow %s: %w", f.Meta.ID, err)
+	}
+
+	if err := c.store.TouchFlowWebhook(ctx, f.Meta.DBID); err != nil {
+		log.Printf("could not update last use of the webhook of flow %s: %v", f.Meta.ID, err)
+	}
+	return execID, nil
+}
+
+// generateWebhookKey returns a random token or secret for a webhook
+func generateWebhookKey() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate webhook key: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// verifyWebhookSignature checks a hex encoded HMAC-SHA256 of body signed with secret. The sha256=
+// prefix used by GitHub and others is accepted.
+func verifyWebhookSignature(signature string, body []byte, secret []byte) bool {
+	got, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(signature), "sha256="))
+	if err != nil || len(got) == 0 {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
@@ -0,0 +1,37 @@
+package core
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+func TestVerifyWebhookSignature(t *testing.T) {
+	secret := []byte("webhook-secret")
+	body := []byte(`{"ref":"refs/heads/main"}`)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	signature := hex.EncodeToString(mac.Sum(nil))
+
+	tests := []struct {
+		name      string
+		signature string
+		body      []byte
+		want      bool
+	}{
+		{"valid", signature, body, true},
+		{"sha256 prefix", "sha256=" + signature, body, true},
+		{"other body", signature, []byte(`{"ref":"refs/heads/dev"}`), false},
+		{"missing", "", body, false},
+		{"not hex", "sha256=not-a-signature", body, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := verifyWebhookSignature(tt.signature, tt.body, secret); got != tt.want {
+				t.Errorf("verifyWebhookSignature() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
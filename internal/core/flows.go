@@ -16,15 +16,19 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/cvhariharan/flowctl/internal/core/models"
 	"github.com/cvhariharan/flowctl/internal/repo"
 	"github.com/cvhariharan/flowctl/internal/scheduler"
+	"github.com/cvhariharan/flowctl/internal/tracing"
 	"github.com/expr-lang/expr"
 	"github.com/google/uuid"
 	"github.com/sqlc-dev/pqtype"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 var (
@@ -73,9 +77,7 @@ func isFlowFile(filename string) bool {
 
 // GetFlowByID returns a flow from memory using the flow slug (id) and namespace
 func (c *Core) GetFlowByID(id string, namespaceID string) (models.Flow, error) {
-	c.rwf.RLock()
-	defer c.rwf.RUnlock()
-	f, ok := c.flows[fmt.Sprintf("%s:%s", id, namespaceID)]
+	f, ok := c.flows.get(namespaceID, id)
 	if !ok {
 		return models.Flow{}, ErrFlowNotFound
 	}
@@ -109,8 +111,6 @@ func (c *Core) GetScheduledExecutionsByFlow(ctx context.Context, flowID int32, n
 }
 
 func (c *Core) GetFlowsPaginated(ctx context.Context, namespaceID string, userID string, limit, offset int) ([]models.Flow, int64, int64, error) {
-	c.rwf.RLock()
-	defer c.rwf.RUnlock()
 	namespaceUUID, err := uuid.Parse(namespaceID)
 	if err != nil {
 		return nil, 0, 0, fmt.Errorf("invalid namespace UUID: %w", err)
@@ -121,7 +121,7 @@ func (c *Core) GetFlowsPaginated(ctx context.Context, namespaceID string, userID
 		return nil, 0, 0, fmt.Errorf("could not get user prefix access: %w", err)
 	}
 
-	var fs []models.Flow
+	var dbFlows []models.Flow
 	var pageCount, totalCount int64
 
 	if hasFullAccess {
@@ -134,7 +134,7 @@ func (c *Core) GetFlowsPaginated(ctx context.Context, namespaceID string, userID
 			return nil, 0, 0, fmt.Errorf("could not get paginated flows for namespace %s: %w", namespaceID, err)
 		}
 		for _, v := range flows {
-			fs = append(fs, c.flows[fmt.Sprintf("%s:%s", v.Slug, namespaceID)])
+			dbFlows = append(dbFlows, flowFromDB(v.ID, v.Slug, v.Name, v.Description, v.PrefixName))
 			pageCount = v.PageCount
 			totalCount = v.TotalCount
 		}
@@ -149,18 +149,16 @@ func (c *Core) GetFlowsPaginated(ctx context.Context, namespaceID string, userID
 			return nil, 0, 0, fmt.Errorf("could not get filtered paginated flows for namespace %s: %w", namespaceID, err)
 		}
 		for _, v := range flows {
-			fs = append(fs, c.flows[fmt.Sprintf("%s:%s", v.Slug, namespaceID)])
+			dbFlows = append(dbFlows, flowFromDB(v.ID, v.Slug, v.Name, v.Description, v.PrefixName))
 			pageCount = v.PageCount
 			totalCount = v.TotalCount
 		}
 	}
 
-	return fs, pageCount, totalCount, nil
+	return c.flows.getMany(namespaceID, dbFlows), pageCount, totalCount, nil
 }
 
 func (c *Core) SearchFlows(ctx context.Context, namespaceID string, userID string, query string, limit, offset int) ([]models.Flow, int64, int64, error) {
-	c.rwf.RLock()
-	defer c.rwf.RUnlock()
 	namespaceUUID, err := uuid.Parse(namespaceID)
 	if err != nil {
 		return nil, 0, 0, fmt.Errorf("invalid namespace UUID: %w", err)
@@ -171,7 +169,7 @@ func (c *Core) SearchFlows(ctx context.Context, namespaceID string, userID strin
 		return nil, 0, 0, fmt.Errorf("could not get user prefix access: %w", err)
 	}
 
-	var fs []models.Flow
+	var dbFlows []models.Flow
 	var pageCount, totalCount int64
 
 	if hasFullAccess {
@@ -185,7 +183,7 @@ func (c *Core) SearchFlows(ctx context.Context, namespaceID string, userID strin
 			return nil, 0, 0, fmt.Errorf("could not search flows for namespace %s: %w", namespaceID, err)
 		}
 		for _, v := range flows {
-			fs = append(fs, c.flows[fmt.Sprintf("%s:%s", v.Slug, namespaceID)])
+			dbFlows = append(dbFlows, flowFromDB(v.ID, v.Slug, v.Name, v.Description, v.PrefixName))
 			pageCount = v.PageCount
 			totalCount = v.TotalCount
 		}
@@ -201,20 +199,17 @@ func (c *Core) SearchFlows(ctx context.Context, namespaceID string, userID strin
 			return nil, 0, 0, fmt.Errorf("could not search filtered flows for namespace %s: %w", namespaceID, err)
 		}
 		for _, v := range flows {
-			fs = append(fs, c.flows[fmt.Sprintf("%s:%s", v.Slug, namespaceID)])
+			dbFlows = append(dbFlows, flowFromDB(v.ID, v.Slug, v.Name, v.Description, v.PrefixName))
 			pageCount = v.PageCount
 			totalCount = v.TotalCount
 		}
 	}
 
-	return fs, pageCount, totalCount, nil
+	return c.flows.getMany(namespaceID, dbFlows), pageCount, totalCount, nil
 }
 
 // GetFlowsByPrefix returns all flows in a namespace with the given prefix name
 func (c *Core) GetFlowsByPrefix(ctx context.Context, namespaceID, prefix string) ([]models.Flow, error) {
-	c.rwf.RLock()
-	defer c.rwf.RUnlock()
-
 	prefixID, err := c.ResolvePrefixID(ctx, prefix, namespaceID)
 	if err != nil {
 		return nil, err
@@ -233,13 +228,25 @@ func (c *Core) GetFlowsByPrefix(ctx context.Context, namespaceID, prefix string)
 		return nil, fmt.Errorf("could not get flows by prefix: %w", err)
 	}
 
-	var fs []models.Flow
+	dbFlows := make([]models.Flow, 0, len(rows))
 	for _, v := range rows {
-		if f, ok := c.flows[fmt.Sprintf("%s:%s", v.Slug, namespaceID)]; ok {
-			fs = append(fs, f)
-		}
+		dbFlows = append(dbFlows, flowFromDB(v.ID, v.Slug, v.Name, v.Description, sql.NullString{String: prefix, Valid: true}))
+	}
+	return c.flows.getMany(namespaceID, dbFlows), nil
+}
+
+// flowFromDB returns a flow with the metadata stored in the database. It stands in for flows
+// that are listed by a query but are not loaded, so that pages match their counts.
+func flowFromDB(id int32, slug, name string, description, prefix sql.NullString) models.Flow {
+	return models.Flow{
+		Meta: models.Metadata{
+			ID:          slug,
+			DBID:        id,
+			Name:        name,
+			Description: description.String,
+			Prefix:      prefix.String,
+		},
 	}
-	return fs, nil
 }
 
 func (c *Core) GetFlowFromLogID(logID string, namespaceID string) (models.Flow, error) {
@@ -271,12 +278,14 @@ func (c *Core) QueueFlowExecution(ctx context.Context, f models.Flow, input map[
 
 // QueueFlowExecutionWithExecID adds a flow in the execution queue with a pre-generated execution ID.
 // If execID is empty, a new UUID is generated. Use this when files need to be uploaded before queuing.
+// ErrQuotaExceeded is returned if the namespace can't start more executions.
 func (c *Core) QueueFlowExecutionWithExecID(ctx context.Context, f models.Flow, input map[string]interface{}, userUUID string, namespaceID string, execID string, scheduledAt *time.Time) (string, error) {
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return "", fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
 	if !f.Meta.AllowOverlap {
-		namespaceUUID, err := uuid.Parse(namespaceID)
-		if err != nil {
-			return "", fmt.Errorf("invalid namespace UUID: %w", err)
-		}
 		execExists, err := c.store.ExecutionExistsForFlow(ctx, repo.ExecutionExistsForFlowParams{
 			Slug: f.Meta.ID,
 			Uuid: namespaceUUID,
@@ -289,7 +298,11 @@ func (c *Core) QueueFlowExecutionWithExecID(ctx context.Context, f models.Flow,
 		}
 	}
 
-	info, err := c.queueFlow(ctx, f, input, execID, 0, userUUID, namespaceID, false, scheduledAt)
+	if err := c.reserveExecution(ctx, namespaceUUID); err != nil {
+		return "", fmt.Errorf("could not queue flow %s for execution: %w", f.Meta.Name, err)
+	}
+
+	info, err := c.queueFlow(ctx, f, input, execID, 0, userUUID, namespaceID, false, scheduledAt, nil)
 	if err != nil {
 		return "", err
 	}
@@ -299,10 +312,19 @@ func (c *Core) QueueFlowExecutionWithExecID(ctx context.Context, f models.Flow,
 
 // ResumeFlowExecution moves the task to a resume queue for further processing.
 func (c *Core) ResumeFlowExecution(ctx context.Context, execID string, actionID string, userUUID string, namespaceID string, retry bool) error {
-	exec, err := c.GetExecutionByExecID(ctx, execID, namespaceID)
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	// The executor needs the password inputs that were sealed when the execution was stored
+	input, err := c.getExecutionInput(ctx, execID, namespaceUUID)
 	if err != nil {
 		return fmt.Errorf("could not get exec %s: %w", execID, err)
 	}
+	if err := c.unsealInputs(ctx, input); err != nil {
+		return fmt.Errorf("could not get input for %s: %w", execID, err)
+	}
 
 	f, err := c.GetFlowFromLogID(execID, namespaceID)
 	if err != nil {
@@ -314,7 +336,7 @@ func (c *Core) ResumeFlowExecution(ctx context.Context, execID string, actionID
 		return err
 	}
 
-	if _, err := c.queueFlow(ctx, f, exec.Input, execID, actionIndex, userUUID, namespaceID, retry, nil); err != nil {
+	if _, err := c.queueFlow(ctx, f, input, execID, actionIndex, userUUID, namespaceID, retry, nil, nil); err != nil {
 		return err
 	}
 
@@ -340,14 +362,171 @@ func (c *Core) RetryFlowExecution(ctx context.Context, execID string, userUUID s
 	return c.ResumeFlowExecution(ctx, execID, exec.CurrentActionID, userUUID, namespaceID, true)
 }
 
+// ResumeFlowExecutionFrom resumes a failed or cancelled execution from the given action instead of
+// the point of failure. Actions before it are skipped, even if they never ran, so operators can move
+// past steps they completed by hand or run earlier steps again.
+func (c *Core) ResumeFlowExecutionFrom(ctx context.Context, execID string, actionID string, userUUID string, namespaceID string) error {
+	exec, err := c.GetExecutionSummaryByExecID(ctx, execID, namespaceID)
+	if err != nil {
+		return fmt.Errorf("could not get exec %s: %w", execID, err)
+	}
+
+	if exec.Status != models.ExecutionStatus(repo.ExecutionStatusErrored) && exec.Status != models.ExecutionStatus(repo.ExecutionStatusCancelled) {
+		return fmt.Errorf("execution must be in errored or cancelled state to resume, current status: %s", exec.Status)
+	}
+
+	f, err := c.GetFlowFromLogID(execID, namespaceID)
+	if err != nil {
+		return err
+	}
+
+	actionIndex, err := f.GetActionIndexByID(actionID)
+	if err != nil {
+		return err
+	}
+
+	// Cancelled executions may not have started any action
+	failedIndex := 0
+	if exec.CurrentActionID != "" {
+		failedIndex, err = f.GetActionIndexByID(exec.CurrentActionID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := c.checkSkippedApprovals(ctx, f, execID, failedIndex, actionIndex, userUUID, namespaceID); err != nil {
+		return err
+	}
+
+	return c.ResumeFlowExecution(ctx, execID, actionID, userUUID, namespaceID, true)
+}
+
+// checkSkippedApprovals returns an error if an approval action of the flow, from index from up to
+// but not including index to, was not approved or the user is not one of its approvers. These
+// actions are skipped when the execution continues from index to, so they must not be a way around
+// an approval. ErrNotApprover is returned if the user can't decide on one of the approvals.
+func (c *Core) checkSkippedApprovals(ctx context.Context, f models.Flow, execID string, from int, to int, userUUID string, namespaceID string) error {
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	for i := from; i < to; i++ {
+		action := f.Actions[i]
+		if !action.Approval {
+			continue
+		}
+
+		a, err := c.store.GetApprovalRequestForActionAndExec(ctx, repo.GetApprovalRequestForActionAndExecParams{
+			ExecID:   execID,
+			ActionID: action.ID,
+			Uuid:     namespaceUUID,
+		})
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("could not get approval for action %s: %w", action.ID, err)
+		}
+		if a.Status != repo.ApprovalStatusApproved {
+			return fmt.Errorf("action %s was not approved and cannot be skipped", action.ID)
+		}
+
+		if err := c.checkApprover(ctx, models.ApprovalRequest{ExecID: execID, ActionID: action.ID}, userUUID, namespaceID); err != nil {
+			return fmt.Errorf("action %s cannot be skipped: %w", action.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// RetryFlowExecutionSkippingFailed retries a failed execution from the action after the one that
+// failed. The failed action is recorded as skipped by the user, along with the time, and is noted in
+// the logs of the execution. An approval action can only be skipped if it was approved, by a user
+// who can decide on its approval.
+func (c *Core) RetryFlowExecutionSkippingFailed(ctx context.Context, execID string, userUUID string, namespaceID string) error {
+	exec, err := c.GetExecutionSummaryByExecID(ctx, execID, namespaceID)
+	if err != nil {
+		return fmt.Errorf("could not get exec %s: %w", execID, err)
+	}
+
+	if exec.Status != models.ExecutionStatus(repo.ExecutionStatusErrored) {
+		return fmt.Errorf("execution must be in errored state to skip the failed action, current status: %s", exec.Status)
+	}
+
+	if exec.CurrentActionID == "" {
+		return fmt.Errorf("cannot determine the failed action - no current action ID")
+	}
+
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	userID, err := uuid.Parse(userUUID)
+	if err != nil {
+		return fmt.Errorf("user id is not a UUID: %w", err)
+	}
+
+	f, err := c.GetFlowFromLogID(execID, namespaceID)
+	if err != nil {
+		return err
+	}
+
+	actionIndex, err := f.GetActionIndexByID(exec.CurrentActionID)
+	if err != nil {
+		return err
+	}
+
+	if err := c.checkSkippedApprovals(ctx, f, execID, actionIndex, actionIndex+1, userUUID, namespaceID); err != nil {
+		return err
+	}
+
+	user, err := c.store.GetUserByUUID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("could not get user %s: %w", userUUID, err)
+	}
+
+	// The executor needs the password inputs that were sealed when the execution was stored
+	input, err := c.getExecutionInput(ctx, execID, namespaceUUID)
+	if err != nil {
+		return fmt.Errorf("could not get exec %s: %w", execID, err)
+	}
+	if err := c.unsealInputs(ctx, input); err != nil {
+		return fmt.Errorf("could not get input for %s: %w", execID, err)
+	}
+
+	if _, err := c.store.AddExecutionActionSkip(ctx, repo.AddExecutionActionSkipParams{
+		ExecID:   execID,
+		ActionID: exec.CurrentActionID,
+		Uuid:     namespaceUUID,
+		Uuid_2:   userID,
+	}); err != nil {
+		return fmt.Errorf("could not record skip of action %s: %w", exec.CurrentActionID, err)
+	}
+
+	skipped := &scheduler.SkippedAction{ActionID: exec.CurrentActionID, SkippedBy: user.Name}
+	if _, err := c.queueFlow(ctx, f, input, execID, actionIndex+1, userUUID, namespaceID, true, nil, skipped); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // queueFlow adds a flow to the execution queue. If the actionIndex is not zero, it is moved to a resume queue.
 // If scheduledAt is provided, the flow will be scheduled to run at that time instead of immediately.
-func (c *Core) queueFlow(ctx context.Context, f models.Flow, input map[string]interface{}, execID string, actionIndex int, userUUID string, namespaceID string, retry bool, scheduledAt *time.Time) (string, error) {
+// skipped is the failed action skipped by a retry, if any.
+func (c *Core) queueFlow(ctx context.Context, f models.Flow, input map[string]interface{}, execID string, actionIndex int, userUUID string, namespaceID string, retry bool, scheduledAt *time.Time, skipped *scheduler.SkippedAction) (string, error) {
 	// If execID is empty, it is a new flow execution
 	if execID == "" {
 		execID = uuid.NewString()
 	}
 
+	ctx, span := tracing.Start(ctx, "queue flow",
+		attribute.String("flowctl.exec_id", execID),
+		attribute.String("flowctl.flow", f.Meta.ID),
+		attribute.String("flowctl.namespace_id", namespaceID),
+		attribute.Bool("flowctl.resumed", retry),
+	)
+	defer span.End()
+
 	userID, err := uuid.Parse(userUUID)
 	if err != nil {
 		return "", fmt.Errorf("user id is not a UUID: %w", err)
@@ -367,8 +546,18 @@ func (c *Core) queueFlow(ctx context.Context, f models.Flow, input map[string]in
 		return "", fmt.Errorf("error getting flow details for %s from DB: %w", f.Meta.ID, err)
 	}
 
+	// The policy may have changed since the flow was loaded
+	if err := c.checkExecutorPolicy(ctx, f, namespaceUUID); err != nil {
+		return "", err
+	}
+
+	f, err = c.withNamespaceDefaults(ctx, f, namespaceUUID)
+	if err != nil {
+		return "", err
+	}
+
 	// Convert to scheduler flow format
-	schedulerFlow, err := models.ConvertToSchedulerFlow(ctx, f, namespaceUUID, c.GetNodesByNames, c.GetNodesByTags)
+	schedulerFlow, err := models.ConvertToSchedulerFlow(ctx, f, namespaceUUID, c.GetNodesByNames, c.GetNodesByTags, c.GetCredentialByName)
 	if err != nil {
 		return "", fmt.Errorf("error converting flow to scheduler model: %w", err)
 	}
@@ -391,10 +580,16 @@ func (c *Core) queueFlow(ctx context.Context, f models.Flow, input map[string]in
 		UserUUID:          userUUID,
 		FlowDirectory:     filepath.Dir(fl.FilePath),
 		Resumed:           retry,
+		TraceContext:      tracing.Inject(ctx),
+		Skipped:           skipped,
 	}
 
 	// Create execution log for manual flows before queuing (needed for immediate API calls)
-	inputB, err := json.Marshal(input)
+	sealedInput, err := c.SealInputs(ctx, schedulerFlow.Inputs, input)
+	if err != nil {
+		return "", err
+	}
+	inputB, err := json.Marshal(sealedInput)
 	if err != nil {
 		return "", fmt.Errorf("could not marshal input to json: %w", err)
 	}
@@ -425,6 +620,7 @@ func (c *Core) queueFlow(ctx context.Context, f models.Flow, input map[string]in
 		_, err = c.scheduler.QueueTask(ctx, scheduler.PayloadTypeFlowExecution, execID, payload)
 	}
 	if err != nil {
+		tracing.End(span, err)
 		return "", err
 	}
 
@@ -448,6 +644,14 @@ func (c *Core) CancelFlowExecution(ctx context.Context, execID string, namespace
 		return fmt.Errorf("failed to update execution status: %w", err)
 	}
 
+	// Tasks of manual actions can no longer be completed, they are requested again on a retry
+	if err := c.store.CancelManualTasksForExec(ctx, repo.CancelManualTasksForExecParams{
+		ExecID: execID,
+		Uuid:   namespaceUUID,
+	}); err != nil {
+		return fmt.Errorf("failed to cancel manual tasks: %w", err)
+	}
+
 	return c.scheduler.CancelTask(ctx, execID)
 }
 
@@ -585,7 +789,7 @@ func (c *Core) GetExecutionSummaryByExecID(ctx context.Context, execID string, n
 
 	return models.ExecutionSummary{
 		ExecID:          execID,
-		Input:           e.Input,
+		Input:           c.maskInputJSON(e.Input, e.FlowSlug, namespaceID),
 		FlowName:        e.FlowName,
 		FlowID:          e.FlowSlug,
 		Status:          models.ExecutionStatus(e.Status),
@@ -598,15 +802,258 @@ func (c *Core) GetExecutionSummaryByExecID(ctx context.Context, execID string, n
 		CurrentActionID: e.CurrentActionID.String,
 		ActionRetries:   actionRetries,
 		ScheduledAt:     e.ScheduledAt.Time,
+		Progress:        c.getExecutionProgress(ctx, execID, namespaceUUID),
+		SkippedActions:  c.getExecutionSkips(ctx, execID, namespaceUUID),
+		Environment:     c.getExecutionEnvironment(ctx, execID, namespaceUUID),
+		Estimate:        c.getExecutionEstimate(ctx, e, namespaceUUID),
+		Parent:          c.getSubflowParent(ctx, execID),
+		Subflows:        c.getSubflows(ctx, execID, namespaceUUID),
 	}, nil
 }
 
-func (c *Core) GetInputForExec(ctx context.Context, execID string, namespaceID string) (map[string]interface{}, error) {
-	var input map[string]interface{}
+// getExecutionEnvironment returns the runtime details of the nodes the actions of an execution ran on
+func (c *Core) getExecutionEnvironment(ctx context.Context, execID string, namespaceUUID uuid.UUID) []models.NodeEnvironment {
+	rows, err := c.store.ListExecutionEnvironments(ctx, repo.ListExecutionEnvironmentsParams{
+		ExecID: execID,
+		Uuid:   namespaceUUID,
+	})
+	if err != nil {
+		log.Printf("failed to get environment for exec %s: %v", execID, err)
+		return nil
+	}
+
+	var env []models.NodeEnvironment
+	for _, r := range rows {
+		env = append(env, models.NodeEnvironment{
+			ActionID:        r.ActionID,
+			Node:            r.Node,
+			Hostname:        r.Hostname,
+			OS:              r.Os,
+			Executor:        r.Executor,
+			ExecutorVersion: r.ExecutorVersion,
+			Image:           r.Image,
+			ImageDigest:     r.ImageDigest,
+			FlowChecksum:    r.FlowChecksum,
+			RecordedAt:      r.RecordedAt,
+		})
+	}
+	return env
+}
+
+// getExecutionSkips returns the failed actions that were skipped when retrying an execution
+func (c *Core) getExecutionSkips(ctx context.Context, execID string, namespaceUUID uuid.UUID) []models.ActionSkip {
+	rows, err := c.store.ListExecutionActionSkips(ctx, repo.ListExecutionActionSkipsParams{
+		ExecID: execID,
+		Uuid:   namespaceUUID,
+	})
+	if err != nil {
+		log.Printf("failed to get skipped actions for exec %s: %v", execID, err)
+		return nil
+	}
+
+	var skips []models.ActionSkip
+	for _, r := range rows {
+		skips = append(skips, models.ActionSkip{
+			ActionID:  r.ActionID,
+			SkippedBy: r.SkippedByName,
+			SkippedAt: r.CreatedAt,
+		})
+	}
+	return skips
+}
+
+// getExecutionProgress returns the last recorded progress of an execution or nil if there is none
+func (c *Core) getExecutionProgress(ctx context.Context, execID string, namespaceUUID uuid.UUID) *models.ExecutionProgress {
+	raw, err := c.store.GetExecutionProgress(ctx, repo.GetExecutionProgressParams{
+		ExecID: execID,
+		Uuid:   namespaceUUID,
+	})
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Printf("failed to get progress for exec %s: %v", execID, err)
+		}
+		return nil
+	}
+
+	var progress models.ExecutionProgress
+	if err := json.Unmarshal(raw, &progress); err != nil {
+		log.Printf("failed to unmarshal progress for exec %s: %v", execID, err)
+		return nil
+	}
+
+	return &progress
+}
+
+// GetExecutionOutputs returns the results of the actions of an execution that have finished
+func (c *Core) GetExecutionOutputs(ctx context.Context, execID string, namespaceID string) (models.ExecutionOutputs, error) {
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return models.ExecutionOutputs{}, fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	rows, err := c.store.ListExecutionOutputs(ctx, repo.ListExecutionOutputsParams{
+		ExecID: execID,
+		Uuid:   namespaceUUID,
+	})
+	if err != nil {
+		return models.ExecutionOutputs{}, fmt.Errorf("could not get outputs for exec %s: %w", execID, err)
+	}
+
+	outputs := models.ExecutionOutputs{
+		ExecID:  execID,
+		Actions: make([]models.ActionOutputs, 0, len(rows)),
+		Outputs: make(map[string]any),
+	}
+	for _, r := range rows {
+		var results map[string]string
+		if err := json.Unmarshal(r.Outputs, &results); err != nil {
+			return models.ExecutionOutputs{}, fmt.Errorf("could not unmarshal outputs of action %s: %w", r.ActionID, err)
+		}
+
+		outputs.Actions = append(outputs.Actions, models.ActionOutputs{
+			ActionID:   r.ActionID,
+			Outputs:    results,
+			FinishedAt: r.CreatedAt,
+		})
+		scheduler.MergeActionResults(results, outputs.Outputs)
+	}
+
+	return outputs, nil
+}
+
+// GetExecutionTimeline returns the recorded start and end times of the actions of an execution
+// and of the nodes they ran on
+func (c *Core) GetExecutionTimeline(ctx context.Context, execID string, namespaceID string) (models.ExecutionTimeline, error) {
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return models.ExecutionTimeline{}, fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	entries, err := c.store.ListExecutionTimeline(ctx, repo.ListExecutionTimelineParams{
+		ExecID: execID,
+		Uuid:   namespaceUUID,
+	})
+	if err != nil {
+		return models.ExecutionTimeline{}, fmt.Errorf("could not get timeline for exec %s: %w", execID, err)
+	}
+
+	timeline := models.ExecutionTimeline{
+		ExecID:  execID,
+		Actions: make([]models.ActionTimeline, 0),
+	}
+
+	// Index of the action attempt in timeline.Actions, keyed by action ID and retry
+	type attempt struct {
+		actionID string
+		retry    int32
+	}
+	actionIndex := make(map[attempt]int)
+	now := time.Now()
+
+	for _, e := range entries {
+		key := attempt{actionID: e.ActionID, retry: e.Retry}
+		idx, ok := actionIndex[key]
+		if !ok {
+			timeline.Actions = append(timeline.Actions, models.ActionTimeline{
+				ActionID: e.ActionID,
+				Retry:    int(e.Retry),
+				Nodes:    make([]models.NodeTimeline, 0),
+			})
+			idx = len(timeline.Actions) - 1
+			actionIndex[key] = idx
+		}
+
+		finishedAt := e.FinishedAt.Time
+		end := finishedAt
+		if !e.FinishedAt.Valid {
+			end = now
+		}
+
+		if e.Node == "" {
+			a := &timeline.Actions[idx]
+			a.Status = e.Status
+			a.Error = e.Error.String
+			a.StartedAt = e.StartedAt
+			a.FinishedAt = finishedAt
+			a.Duration = end.Sub(e.StartedAt)
+			continue
+		}
+
+		timeline.Actions[idx].Nodes = append(timeline.Actions[idx].Nodes, models.NodeTimeline{
+			Node:       e.Node,
+			Status:     e.Status,
+			Error:      e.Error.String,
+			StartedAt:  e.StartedAt,
+			FinishedAt: finishedAt,
+			Duration:   end.Sub(e.StartedAt),
+		})
+	}
+
+	var slowest time.Duration
+	for _, a := range timeline.Actions {
+		if a.Duration > slowest {
+			slowest = a.Duration
+			timeline.SlowestActionID = a.ActionID
+		}
+	}
+
+	return timeline, nil
+}
+
+// GetExecutionTelemetry returns the resource usage sampled on the nodes of an execution, one
+// series per action and node in the order they started
+func (c *Core) GetExecutionTelemetry(ctx context.Context, execID string, namespaceID string) ([]models.NodeTelemetry, error) {
 	namespaceUUID, err := uuid.Parse(namespaceID)
 	if err != nil {
 		return nil, fmt.Errorf("invalid namespace UUID: %w", err)
 	}
+
+	samples, err := c.store.ListExecutionTelemetry(ctx, repo.ListExecutionTelemetryParams{
+		ExecID: execID,
+		Uuid:   namespaceUUID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not get telemetry for exec %s: %w", execID, err)
+	}
+
+	series := make([]models.NodeTelemetry, 0)
+	index := make(map[[2]string]int)
+	for _, s := range samples {
+		key := [2]string{s.ActionID, s.Node}
+		idx, ok := index[key]
+		if !ok {
+			series = append(series, models.NodeTelemetry{
+				ActionID: s.ActionID,
+				Node:     s.Node,
+			})
+			idx = len(series) - 1
+			index[key] = idx
+		}
+
+		series[idx].Samples = append(series[idx].Samples, models.TelemetrySample{
+			Time:          s.SampledAt,
+			CPUPercent:    s.CpuPercent,
+			MemoryPercent: s.MemoryPercent,
+			DiskPercent:   s.DiskPercent,
+			Load1:         s.Load1,
+		})
+	}
+
+	return series, nil
+}
+
+// GetInputForExec returns the input of an execution with the values of password inputs masked
+func (c *Core) GetInputForExec(ctx context.Context, execID string, namespaceID string) (map[string]interface{}, error) {
+	e, err := c.GetExecutionByExecID(ctx, execID, namespaceID)
+	if err != nil {
+		return nil, err
+	}
+	return e.Input, nil
+}
+
+// getExecutionInput returns the stored input of an execution, password inputs are still sealed
+func (c *Core) getExecutionInput(ctx context.Context, execID string, namespaceUUID uuid.UUID) (map[string]interface{}, error) {
+	var input map[string]interface{}
 	in, err := c.store.GetInputForExecByUUID(ctx, repo.GetInputForExecByUUIDParams{
 		ExecID: execID,
 		Uuid:   namespaceUUID,
@@ -622,6 +1069,7 @@ func (c *Core) GetInputForExec(ctx context.Context, execID string, namespaceID s
 	return input, nil
 }
 
+// GetExecutionByExecID returns an execution with the values of password inputs masked
 func (c *Core) GetExecutionByExecID(ctx context.Context, execID string, namespaceID string) (models.Execution, error) {
 	namespaceUUID, err := uuid.Parse(namespaceID)
 	if err != nil {
@@ -648,18 +1096,18 @@ func (c *Core) GetExecutionByExecID(ctx context.Context, execID string, namespac
 	return models.Execution{
 		ExecID:      e.ExecID,
 		Version:     int64(e.Version),
-		Input:       input,
+		Input:       maskInputs(input, c.passwordInputs(e.FlowSlug, namespaceID)),
 		ErrorMsg:    e.Error.String,
 		TriggeredBy: u.Uuid.String(),
 	}, nil
 }
 
 func (c *Core) CreateFlow(ctx context.Context, f models.Flow, namespaceID string) error {
-	c.rwf.RLock()
-	if _, exists := c.flows[f.Meta.ID]; exists {
+	// The slug is reserved until the flow is loaded, so concurrent creates of the same flow fail
+	if !c.flows.reserve(namespaceID, f.Meta.ID) {
 		return fmt.Errorf("flow with id %s already exists", f.Meta.ID)
 	}
-	c.rwf.RUnlock()
+	defer c.flows.release(namespaceID, f.Meta.ID)
 
 	// Remove duplicate schedules
 	f.Schedules = removeDuplicateSchedules(f.Schedules)
@@ -669,6 +1117,18 @@ func (c *Core) CreateFlow(ctx context.Context, f models.Flow, namespaceID string
 		return fmt.Errorf("could not get namespace details for %s: %w", namespaceID, err)
 	}
 
+	// Checked before the flow file is written
+	namespaceUUID, err := uuid.Parse(n.ID)
+	if err != nil {
+		return fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+	if err := c.checkExecutorPolicy(ctx, f, namespaceUUID); err != nil {
+		return err
+	}
+	if err := c.checkFlowOwners(ctx, f, namespaceUUID); err != nil {
+		return err
+	}
+
 	namespaceDirPath := filepath.Join(c.flowDirectory, n.Name)
 	if err := os.MkdirAll(namespaceDirPath, 0755); err != nil {
 		return fmt.Errorf("could not create namespace directory: %w", err)
@@ -693,23 +1153,23 @@ func (c *Core) CreateFlow(ctx context.Context, f models.Flow, namespaceID string
 		return fmt.Errorf("could not write flow file: %w", err)
 	}
 
-	importedFlow, namespaceUUID, err := c.importFlowFromFile(ctx, yamlFilePath, n.Name)
+	if err := c.flowStore.Push(ctx, flowDir); err != nil {
+		return fmt.Errorf("could not store flow: %w", err)
+	}
+
+	importedFlow, importedNamespaceID, err := c.importFlowFromFile(ctx, yamlFilePath, n.Name)
 	if err != nil {
 		return fmt.Errorf("could not import flow after creation: %w", err)
 	}
 
-	c.rwf.Lock()
-	defer c.rwf.Unlock()
-	c.flows[fmt.Sprintf("%s:%s", importedFlow.Meta.ID, namespaceUUID)] = importedFlow
+	c.flows.set(importedNamespaceID, importedFlow)
 	return nil
 }
 
 func (c *Core) UpdateFlow(ctx context.Context, f models.Flow, namespaceID string) error {
-	c.rwf.RLock()
-	if _, exists := c.flows[fmt.Sprintf("%s:%s", f.Meta.ID, namespaceID)]; !exists {
+	if !c.flows.exists(namespaceID, f.Meta.ID) {
 		return fmt.Errorf("flow with id %s does not exist", f.Meta.ID)
 	}
-	c.rwf.RUnlock()
 
 	// Remove duplicate schedules
 	f.Schedules = removeDuplicateSchedules(f.Schedules)
@@ -734,6 +1194,13 @@ func (c *Core) UpdateFlow(ctx context.Context, f models.Flow, namespaceID string
 		return fmt.Errorf("could not get existing flow: %w", err)
 	}
 
+	if err := c.checkExecutorPolicy(ctx, f, namespaceUUID); err != nil {
+		return err
+	}
+	if err := c.checkFlowOwners(ctx, f, namespaceUUID); err != nil {
+		return err
+	}
+
 	flowFilePath := existingFlow.FilePath
 	if _, err := os.Stat(flowFilePath); err != nil {
 		return fmt.Errorf("flow file does not exist at %s: %w", flowFilePath, err)
@@ -755,23 +1222,23 @@ func (c *Core) UpdateFlow(ctx context.Context, f models.Flow, namespaceID string
 		return fmt.Errorf("could not write flow file: %w", err)
 	}
 
+	if err := c.flowStore.Push(ctx, filepath.Dir(flowFilePath)); err != nil {
+		return fmt.Errorf("could not store flow: %w", err)
+	}
+
 	importedFlow, namespaceUUIDStr, err := c.importFlowFromFile(ctx, flowFilePath, n.Name)
 	if err != nil {
 		return fmt.Errorf("could not import flow after update: %w", err)
 	}
 
-	c.rwf.Lock()
-	defer c.rwf.Unlock()
-	c.flows[fmt.Sprintf("%s:%s", importedFlow.Meta.ID, namespaceUUIDStr)] = importedFlow
+	c.flows.set(namespaceUUIDStr, importedFlow)
 	return nil
 }
 
 func (c *Core) DeleteFlow(ctx context.Context, flowID, namespaceID string) error {
-	c.rwf.RLock()
-	if _, exists := c.flows[fmt.Sprintf("%s:%s", flowID, namespaceID)]; !exists {
+	if !c.flows.exists(namespaceID, flowID) {
 		return fmt.Errorf("flow with id %s does not exist", flowID)
 	}
-	c.rwf.RUnlock()
 
 	namespaceUUID, err := uuid.Parse(namespaceID)
 	if err != nil {
@@ -799,6 +1266,10 @@ func (c *Core) DeleteFlow(ctx context.Context, flowID, namespaceID string) error
 		return fmt.Errorf("could not delete flow: %w", err)
 	}
 
+	if err := c.flowStore.Push(ctx, flowDir); err != nil {
+		return fmt.Errorf("could not delete flow from storage: %w", err)
+	}
+
 	if err := c.store.DeleteFlow(ctx, repo.DeleteFlowParams{
 		Slug: flowID,
 		Uuid: namespaceUUID,
@@ -806,49 +1277,81 @@ func (c *Core) DeleteFlow(ctx context.Context, flowID, namespaceID string) error
 		return fmt.Errorf("could not delete flow %s from DB: %w", flowID, err)
 	}
 
-	c.rwf.Lock()
-	defer c.rwf.Unlock()
-	delete(c.flows, fmt.Sprintf("%s:%s", flowID, namespaceID))
+	c.flows.delete(namespaceID, flowID)
 	return nil
 }
 
+// LoadFlows pulls the flows directory and loads every flow in it. Progress is reported by
+// FlowLoadStatus.
 func (c *Core) LoadFlows(ctx context.Context) error {
-	m := make(map[string]models.Flow)
+	c.loadMu.Lock()
+	defer c.loadMu.Unlock()
+
+	if err := c.flowStore.Pull(ctx); err != nil {
+		err = fmt.Errorf("could not pull flows: %w", err)
+		c.flowLoad.start(0)
+		c.flowLoad.finish(err)
+		return err
+	}
+
+	return c.loadFlows(ctx)
+}
+
+// loadFlows replaces the flows in memory with the flows in the flows directory. The flows of a
+// namespace are available as soon as the namespace is loaded.
+// c.loadMu must be held.
+func (c *Core) loadFlows(ctx context.Context) error {
+	m := make(map[string]map[string]models.Flow)
 
 	// Read immediate subdirectories
 	entries, err := os.ReadDir(c.flowDirectory)
 	if err != nil {
-		return fmt.Errorf("error reading flow directory: %w", err)
+		err = fmt.Errorf("error reading flow directory: %w", err)
+		c.flowLoad.start(0)
+		c.flowLoad.finish(err)
+		return err
 	}
 
-	// Each subdirectory in the root flows directory should be a namespace
+	// Each subdirectory in the root flows directory should be a namespace. Hidden directories
+	// are used while syncing namespaces and are skipped.
+	var namespaceDirs []string
 	for _, entry := range entries {
-		if !entry.IsDir() {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
 			continue
 		}
+		namespaceDirs = append(namespaceDirs, filepath.Join(c.flowDirectory, entry.Name()))
+	}
 
-		namespaceDir := filepath.Join(c.flowDirectory, entry.Name())
-		namespaceFlows, err := c.processNamespaceFlows(ctx, namespaceDir)
+	c.flowLoad.start(len(namespaceDirs))
+	for _, namespaceDir := range namespaceDirs {
+		namespaceID, namespaceFlows, err := c.processNamespaceFlows(ctx, namespaceDir, c.flowLoad)
+		c.flowLoad.namespaceDone()
 		if err != nil {
-			log.Printf("could not process flows from namespace %s: %v", entry.Name(), err)
+			log.Printf("could not process flows from namespace %s: %v", filepath.Base(namespaceDir), err)
 			continue
 		}
 
-		maps.Copy(m, namespaceFlows)
+		m[namespaceID] = namespaceFlows
+		c.flows.replaceNamespace(namespaceID, namespaceFlows)
 	}
-	c.flows = m
+
+	c.flows.replaceAll(m)
+	c.flowLoad.finish(nil)
 	return nil
 }
 
 // processNamespaceFlows iterates through directories in the namespace directory and imports flows.
-// Each subdirectory under flows/<namespace>/ is treated as a flow directory.
-func (c *Core) processNamespaceFlows(ctx context.Context, namespaceDir string) (map[string]models.Flow, error) {
+// Each subdirectory under flows/<namespace>/ is treated as a flow directory. Up to
+// c.flowLoadConcurrency flows are imported at the same time. If progress is set, the imported
+// flows are counted in it.
+// It returns the namespace UUID and the imported flows keyed by slug.
+func (c *Core) processNamespaceFlows(ctx context.Context, namespaceDir string, progress *flowLoadTracker) (string, map[string]models.Flow, error) {
 	m := make(map[string]models.Flow)
 	namespaceName := filepath.Base(namespaceDir)
 
 	ns, err := c.store.GetNamespaceByName(context.Background(), namespaceName)
 	if err != nil {
-		return nil, fmt.Errorf("error getting namespace %s: %w", namespaceName, err)
+		return "", nil, fmt.Errorf("error getting namespace %s: %w", namespaceName, err)
 	}
 
 	err = c.store.MarkAllFlowsInactiveForNamespace(context.Background(), ns.Uuid)
@@ -856,31 +1359,112 @@ func (c *Core) processNamespaceFlows(ctx context.Context, namespaceDir string) (
 		log.Printf("error marking flows inactive for namespace %s: %v", namespaceName, err)
 	}
 
+	// Errors are recorded again for the files that still fail to import
+	if err := c.store.ClearFlowImportErrors(ctx, ns.ID); err != nil {
+		log.Printf("error clearing flow import errors for namespace %s: %v", namespaceName, err)
+	}
+
 	entries, err := os.ReadDir(namespaceDir)
 	if err != nil {
-		return nil, fmt.Errorf("error reading namespace %s directory: %w", namespaceDir, err)
+		return "", nil, fmt.Errorf("error reading namespace %s directory: %w", namespaceDir, err)
 	}
 
+	var flowPaths []string
 	for _, entry := range entries {
 		if !entry.IsDir() {
 			continue
 		}
 
-		flowDir := filepath.Join(namespaceDir, entry.Name())
-		flowPath := findFlowFile(flowDir)
+		flowPath := findFlowFile(filepath.Join(namespaceDir, entry.Name()))
 		if flowPath == "" {
 			continue
 		}
+		flowPaths = append(flowPaths, flowPath)
+	}
+	if progress != nil {
+		progress.addFlows(len(flowPaths))
+	}
 
-		f, nsUUID, err := c.importFlowFromFile(ctx, flowPath, namespaceName)
-		if err != nil {
-			log.Printf("error importing flow from %s: %v", flowPath, err)
-			continue
+	concurrency := c.flowLoadConcurrency
+	if concurrency <= 0 {
+		concurrency = DefaultFlowLoadConcurrency
+	}
+
+	// Results are kept in directory order, so that the last directory wins if two flows share a slug
+	imported := make([]*models.Flow, len(flowPaths))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for i, flowPath := range flowPaths {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, flowPath string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			f, _, err := c.importFlowFromFile(ctx, flowPath, namespaceName)
+			if progress != nil {
+				progress.flowDone(err)
+			}
+			if err != nil {
+				log.Printf("error importing flow from %s: %v", flowPath, err)
+				c.recordFlowImportError(ctx, ns.ID, namespaceDir, flowPath, err)
+				return
+			}
+			imported[i] = &f
+		}(i, flowPath)
+	}
+	wg.Wait()
+
+	for _, f := range imported {
+		if f != nil {
+			m[f.Meta.ID] = *f
 		}
-		m[fmt.Sprintf("%s:%s", f.Meta.ID, nsUUID)] = f
 	}
 
-	return m, nil
+	return ns.Uuid.String(), m, nil
+}
+
+// recordFlowImportError stores why a flow file could not be imported, so that it can be shown
+// to flow authors
+func (c *Core) recordFlowImportError(ctx context.Context, namespaceID int32, namespaceDir, flowPath string, importErr error) {
+	rel, err := filepath.Rel(namespaceDir, flowPath)
+	if err != nil {
+		rel = filepath.Base(flowPath)
+	}
+
+	// Paths in the error are shown relative to the namespace directory as well
+	msg := strings.ReplaceAll(importErr.Error(), namespaceDir+string(filepath.Separator), "")
+
+	if err := c.store.CreateFlowImportError(ctx, repo.CreateFlowImportErrorParams{
+		NamespaceID: namespaceID,
+		FilePath:    filepath.ToSlash(rel),
+		Error:       msg,
+	}); err != nil {
+		log.Printf("error recording import error for %s: %v", flowPath, err)
+	}
+}
+
+// ListFlowImportErrors returns the flow files in a namespace that failed to load
+func (c *Core) ListFlowImportErrors(ctx context.Context, namespaceID string) ([]models.FlowImportError, error) {
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	rows, err := c.store.ListFlowImportErrors(ctx, namespaceUUID)
+	if err != nil {
+		return nil, fmt.Errorf("could not list flow import errors: %w", err)
+	}
+
+	importErrors := make([]models.FlowImportError, 0, len(rows))
+	for _, row := range rows {
+		importErrors = append(importErrors, models.FlowImportError{
+			FilePath:  row.FilePath,
+			Error:     row.Error,
+			CreatedAt: row.CreatedAt,
+		})
+	}
+	return importErrors, nil
 }
 
 // findFlowFile returns the path to the first flow file in the given directory
@@ -932,6 +1516,13 @@ func (c *Core) importFlowFromFile(ctx context.Context, flowFilePath, namespaceNa
 		return models.Flow{}, "", fmt.Errorf("error getting namespace %s: %w", f.Meta.Namespace, err)
 	}
 
+	if err := c.checkExecutorPolicy(ctx, f, ns.Uuid); err != nil {
+		return models.Flow{}, "", fmt.Errorf("validation error in %s: %w", flowFilePath, err)
+	}
+	if err := c.checkFlowOwners(ctx, f, ns.Uuid); err != nil {
+		return models.Flow{}, "", fmt.Errorf("validation error in %s: %w", flowFilePath, err)
+	}
+
 	var schedules []struct {
 		Cron     string
 		Timezone string
@@ -956,16 +1547,18 @@ func (c *Core) importFlowFromFile(ctx context.Context, flowFilePath, namespaceNa
 		Uuid:     ns.Uuid,
 		IsActive: sql.NullBool{Valid: false},
 	})
+	changed := err != nil || fd.Checksum != checksum
 	if err != nil {
 		fd, err = c.store.CreateFlowTx(context.Background(), repo.CreateFlowTxParams{
-			Slug:        f.Meta.ID,
-			Name:        f.Meta.Name,
-			Description: f.Meta.Description,
-			Checksum:    checksum,
-			FilePath:    flowFilePath,
-			Namespace:   f.Meta.Namespace,
-			PrefixID:    prefixID,
-			Schedules:   schedules,
+			Slug:          f.Meta.ID,
+			Name:          f.Meta.Name,
+			Description:   f.Meta.Description,
+			Checksum:      checksum,
+			FilePath:      flowFilePath,
+			Namespace:     f.Meta.Namespace,
+			PrefixID:      prefixID,
+			SearchContent: f.SearchContent(),
+			Schedules:     schedules,
 		})
 	} else if fd.Checksum != checksum {
 		fd, err = c.store.UpdateFlowTx(context.Background(), repo.UpdateFlowTxParams{
@@ -977,12 +1570,35 @@ func (c *Core) importFlowFromFile(ctx context.Context, flowFilePath, namespaceNa
 			Namespace:       f.Meta.Namespace,
 			PrefixID:        prefixID,
 			UserSchedulable: f.Meta.UserSchedulable,
+			SearchContent:   f.SearchContent(),
 			Schedules:       schedules,
 		})
 	}
 	if err != nil {
 		return models.Flow{}, "", fmt.Errorf("database operation failed for flow %s: %w", f.Meta.ID, err)
 	}
+	if !changed {
+		if err := c.store.CreateFlowSearchIfMissing(context.Background(), repo.CreateFlowSearchIfMissingParams{
+			FlowID:  fd.ID,
+			Content: f.SearchContent(),
+		}); err != nil {
+			return models.Flow{}, "", fmt.Errorf("could not index flow %s: %w", f.Meta.ID, err)
+		}
+	}
+
+	// Namespaces synced from git record the commit each version of a flow came from
+	f.Meta.CommitSHA = readFlowCommit(filepath.Dir(filepath.Dir(flowFilePath)))
+	if changed {
+		if _, err := c.store.CreateFlowVersion(context.Background(), repo.CreateFlowVersionParams{
+			FlowID:    fd.ID,
+			Checksum:  checksum,
+			CommitSha: sql.NullString{String: f.Meta.CommitSHA, Valid: f.Meta.CommitSHA != ""},
+			Content:   sql.NullString{String: string(data), Valid: true},
+			Format:    sql.NullString{String: string(format), Valid: true},
+		}); err != nil {
+			return models.Flow{}, "", fmt.Errorf("failed to record version of flow %s: %w", f.Meta.ID, err)
+		}
+	}
 
 	err = c.store.MarkFlowActive(context.Background(), repo.MarkFlowActiveParams{
 		Slug: f.Meta.ID,
@@ -998,9 +1614,6 @@ func (c *Core) importFlowFromFile(ctx context.Context, flowFilePath, namespaceNa
 
 // GetScheduledFlows returns all flows that have a cron schedule configured
 func (c *Core) GetScheduledFlows() []models.Flow {
-	c.rwf.RLock()
-	defer c.rwf.RUnlock()
-
 	ctx := context.Background()
 	scheduledFlowRows, err := c.store.GetScheduledFlows(ctx)
 	if err != nil {
@@ -1010,8 +1623,7 @@ func (c *Core) GetScheduledFlows() []models.Flow {
 
 	var scheduledFlows []models.Flow
 	for _, row := range scheduledFlowRows {
-		flowKey := fmt.Sprintf("%s:%s", row.Slug, row.NamespaceUuid.String())
-		if flow, exists := c.flows[flowKey]; exists {
+		if flow, exists := c.flows.get(row.NamespaceUuid.String(), row.Slug); exists {
 			scheduledFlows = append(scheduledFlows, flow)
 		}
 	}
@@ -1034,8 +1646,13 @@ func (c *Core) GetSchedulerFlow(ctx context.Context, flowSlug string, namespaceU
 		return scheduler.Flow{}, fmt.Errorf("invalid namespace UUID: %w", err)
 	}
 
+	flow, err = c.withNamespaceDefaults(ctx, flow, nsUUID)
+	if err != nil {
+		return scheduler.Flow{}, err
+	}
+
 	// Convert to scheduler format with nodes resolved
-	return models.ConvertToSchedulerFlow(ctx, flow, nsUUID, c.GetNodesByNames, c.GetNodesByTags)
+	return models.ConvertToSchedulerFlow(ctx, flow, nsUUID, c.GetNodesByNames, c.GetNodesByTags, c.GetCredentialByName)
 }
 
 // removeDuplicateSchedules removes duplicate schedules from a slice
@@ -1058,25 +1675,139 @@ func removeDuplicateSchedules(schedules []models.Schedule) []models.Schedule {
 	return result
 }
 
-// SyncScheduledFlowJobs loads scheduled flows from the database and converts them to scheduled jobs
+// ScheduledRunSkipReason reports why a due scheduled job should not be started.
+// A scheduled run is skipped when the flow disallows overlapping executions and one is still in progress,
+// or when the namespace would go over its quota.
+// This function can be used as a SkipCheckerFn for the scheduler
+func (c *Core) ScheduledRunSkipReason(ctx context.Context, job scheduler.ScheduledJob) (string, error) {
+	payload, ok := job.Payload.(scheduler.FlowExecutionPayload)
+	if !ok {
+		return "", nil
+	}
+
+	f, err := c.GetFlowByID(payload.Workflow.Meta.ID, payload.NamespaceID)
+	if err != nil {
+		return "", err
+	}
+
+	namespaceUUID, err := uuid.Parse(payload.NamespaceID)
+	if err != nil {
+		return "", fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	if !f.Meta.AllowOverlap {
+		execExists, err := c.store.ExecutionExistsForFlow(ctx, repo.ExecutionExistsForFlowParams{
+			Slug: f.Meta.ID,
+			Uuid: namespaceUUID,
+		})
+		if err != nil {
+			return "", fmt.Errorf("error checking existing executions for flow %s: %w", f.Meta.ID, err)
+		}
+		if execExists {
+			return "a previous execution is still in progress and execution overlap is disabled", nil
+		}
+	}
+
+	if err := c.checkExecutorPolicy(ctx, f, namespaceUUID); err != nil {
+		if errors.Is(err, ErrExecutorNotAllowed) {
+			return err.Error(), nil
+		}
+		return "", err
+	}
+
+	// Runs that are not skipped count against the daily executions of the namespace
+	if err := c.reserveExecution(ctx, namespaceUUID); err != nil {
+		if errors.Is(err, ErrQuotaExceeded) {
+			return err.Error(), nil
+		}
+		return "", err
+	}
+
+	return "", nil
+}
+
+// SyncScheduledFlowJobs loads scheduled flows from the database and converts them to scheduled jobs.
+// The schedules are loaded with a single query, every flow is converted once however many schedules
+// it has and the nodes of all flows are resolved together, so the number of queries doesn't grow
+// with the number of schedules.
 // This function can be used as a JobSyncerFn for the scheduler
 func (c *Core) SyncScheduledFlowJobs(ctx context.Context) ([]scheduler.ScheduledJob, error) {
-	flows, err := c.store.GetScheduledFlows(ctx)
+	// Flows that are not loaded yet would be dropped from the schedule
+	if !c.FlowsLoaded() {
+		return nil, ErrFlowsLoading
+	}
+
+	rows, err := c.store.ListScheduledFlowJobs(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	jobs := make([]scheduler.ScheduledJob, 0, len(flows))
-	for _, flow := range flows {
-		namespace, err := c.store.GetNamespaceByUUID(ctx, flow.NamespaceUuid)
+	type flowKey struct {
+		namespace uuid.UUID
+		slug      string
+	}
+
+	// Load the scheduled flows from memory and collect the nodes they run on
+	flows := make(map[flowKey]models.Flow)
+	missing := make(map[flowKey]bool)
+	namespaceUUIDs := make(map[uuid.UUID]struct{})
+	nodeNames := make(map[string]struct{})
+	tags := make(map[string]struct{})
+	for _, row := range rows {
+		key := flowKey{namespace: row.NamespaceUuid, slug: row.Slug}
+		if _, ok := flows[key]; ok || missing[key] {
+			continue
+		}
+
+		f, err := c.GetFlowByID(row.Slug, row.NamespaceUuid.String())
 		if err != nil {
-			log.Printf("failed to get namespace for flow %s: %v", flow.Name, err)
+			log.Printf("failed to load flow %s: %v", row.Slug, err)
+			missing[key] = true
 			continue
 		}
+		flows[key] = f
+
+		namespaceUUIDs[row.NamespaceUuid] = struct{}{}
+		for _, action := range f.Actions {
+			n, t := models.ParseActionTargets(action.On)
+			for _, name := range n {
+				nodeNames[name] = struct{}{}
+			}
+			for _, tag := range t {
+				tags[tag] = struct{}{}
+			}
+		}
+	}
+
+	nodes, err := c.loadNodeIndex(ctx, slices.Collect(maps.Keys(namespaceUUIDs)), slices.Collect(maps.Keys(nodeNames)), slices.Collect(maps.Keys(tags)))
+	if err != nil {
+		return nil, fmt.Errorf("could not load nodes of scheduled flows: %w", err)
+	}
+
+	// Defaults are loaded once per namespace
+	defaults := make(map[uuid.UUID]models.NamespaceDefaults, len(namespaceUUIDs))
+	for namespaceUUID := range namespaceUUIDs {
+		d, err := c.getNamespaceDefaults(ctx, namespaceUUID)
+		if err != nil {
+			return nil, err
+		}
+		defaults[namespaceUUID] = d
+	}
 
-		schedulerFlow, err := c.GetSchedulerFlow(ctx, flow.Slug, flow.NamespaceUuid.String())
+	schedulerFlows := make(map[flowKey]scheduler.Flow, len(flows))
+	for key, f := range flows {
+		schedulerFlow, err := models.ConvertToSchedulerFlow(ctx, defaults[key.namespace].Apply(c.withOwnerNotify(f)), key.namespace, nodes.byNames, nodes.byTags, c.GetCredentialByName)
 		if err != nil {
-			log.Printf("failed to load flow %s: %v", flow.Slug, err)
+			log.Printf("failed to load flow %s: %v", key.slug, err)
+			continue
+		}
+		schedulerFlows[key] = schedulerFlow
+	}
+
+	jobs := make([]scheduler.ScheduledJob, 0, len(rows))
+	for _, flow := range rows {
+		schedulerFlow, ok := schedulerFlows[flowKey{namespace: flow.NamespaceUuid, slug: flow.Slug}]
+		if !ok {
 			continue
 		}
 
@@ -1095,11 +1826,10 @@ func (c *Core) SyncScheduledFlowJobs(ctx context.Context) ([]scheduler.Scheduled
 
 		userUUID := SystemUserUUID
 		if flow.IsUserCreated {
-			user, err := c.store.GetUserByID(ctx, flow.CreatedBy)
-			if err != nil {
-				log.Printf("failed to get user for schedule %d: %v", flow.ScheduleID, err)
+			if flow.CreatedByUuid.Valid {
+				userUUID = flow.CreatedByUuid.UUID.String()
 			} else {
-				userUUID = user.Uuid.String()
+				log.Printf("failed to get user for schedule %d: user does not exist", flow.ScheduleID)
 			}
 		}
 
@@ -1107,17 +1837,24 @@ func (c *Core) SyncScheduledFlowJobs(ctx context.Context) ([]scheduler.Scheduled
 			Workflow:          schedulerFlow,
 			Input:             input,
 			StartingActionIdx: 0,
-			NamespaceID:       namespace.Uuid.String(),
+			NamespaceID:       flow.NamespaceUuid.String(),
 			TriggerType:       scheduler.TriggerTypeScheduled,
 			UserUUID:          userUUID,
 			FlowDirectory:     filepath.Dir(flow.FilePath),
+			ScheduleID:        flow.ScheduleID,
+		}
+
+		// Schedules without a timezone use the namespace default
+		timezone := flow.Timezone
+		if timezone == "" {
+			timezone = defaults[flow.NamespaceUuid].Timezone
 		}
 
 		jobs = append(jobs, scheduler.ScheduledJob{
 			ID:          fmt.Sprintf("schedule_%d", flow.ScheduleID),
 			Name:        fmt.Sprintf("%s (%s)", flow.Name, flow.Cron),
 			Cron:        flow.Cron,
-			Timezone:    flow.Timezone,
+			Timezone:    timezone,
 			PayloadType: scheduler.PayloadTypeFlowExecution,
 			Payload:     payload,
 		})
@@ -1389,6 +2126,101 @@ func (c *Core) DeleteSchedule(ctx context.Context, scheduleUUID, userUUID, names
 	return nil
 }
 
+// GetScheduleLag returns when each cron schedule in the namespace last fired and how late
+// its executions started
+func (c *Core) GetScheduleLag(ctx context.Context, namespaceID string) ([]models.ScheduleLag, error) {
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	rows, err := c.store.ListCronScheduleRuns(ctx, namespaceUUID)
+	if err != nil {
+		return nil, fmt.Errorf("could not list schedule runs: %w", err)
+	}
+
+	lag := make([]models.ScheduleLag, len(rows))
+	for i, r := range rows {
+		lag[i] = models.ScheduleLag{
+			ScheduleUUID:  r.Uuid.String(),
+			FlowSlug:      r.FlowSlug,
+			FlowName:      r.FlowName,
+			Cron:          r.Cron,
+			Timezone:      r.Timezone,
+			IsUserCreated: r.IsUserCreated,
+			IsActive:      r.IsActive,
+			LastExecID:    r.ExecID.String,
+			LastFiredAt:   r.FiredAt.Time,
+			LastStartedAt: r.StartedAt.Time,
+			MaxDrift:      time.Duration(r.MaxDriftMs.Int64) * time.Millisecond,
+			Runs:          r.RunCount.Int64,
+		}
+		if r.FiredAt.Valid && r.StartedAt.Valid {
+			lag[i].LastDrift = r.StartedAt.Time.Sub(r.FiredAt.Time)
+		}
+	}
+
+	return lag, nil
+}
+
+// maxScheduleOccurrences limits the occurrences returned by GetScheduleCalendar
+const maxScheduleOccurrences = 5000
+
+// GetScheduleCalendar returns when the active schedules of the namespace fire between from and
+// to, ordered by time. Schedules without a timezone use the namespace default, like scheduled
+// jobs. The second return value is true if there were more than maxScheduleOccurrences.
+func (c *Core) GetScheduleCalendar(ctx context.Context, namespaceID string, from, to time.Time) ([]models.ScheduleOccurrence, bool, error) {
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	rows, err := c.store.ListNamespaceSchedules(ctx, namespaceUUID)
+	if err != nil {
+		return nil, false, fmt.Errorf("could not list schedules: %w", err)
+	}
+
+	defaults, err := c.getNamespaceDefaults(ctx, namespaceUUID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var occurrences []models.ScheduleOccurrence
+	for _, r := range rows {
+		timezone := r.Timezone
+		if timezone == "" {
+			timezone = defaults.Timezone
+		}
+
+		// One more than the limit is kept to tell whether any were left out
+		times, err := scheduler.CronOccurrences(r.Cron, timezone, from, to, maxScheduleOccurrences+1)
+		if err != nil {
+			log.Printf("invalid schedule %s of flow %s: %v", r.Uuid, r.FlowSlug, err)
+			continue
+		}
+		for _, t := range times {
+			occurrences = append(occurrences, models.ScheduleOccurrence{
+				ScheduleUUID:  r.Uuid.String(),
+				FlowSlug:      r.FlowSlug,
+				FlowName:      r.FlowName,
+				Cron:          r.Cron,
+				Timezone:      timezone,
+				IsUserCreated: r.IsUserCreated,
+				At:            t,
+			})
+		}
+	}
+
+	slices.SortStableFunc(occurrences, func(a, b models.ScheduleOccurrence) int {
+		return a.At.Compare(b.At)
+	})
+
+	if len(occurrences) > maxScheduleOccurrences {
+		return occurrences[:maxScheduleOccurrences], true, nil
+	}
+	return occurrences, false, nil
+}
+
 // PopulateRemoteOptions fetches remote options for all select inputs in the flow
 // that have RemoteOptions configured and populates flow.Inputs[i].Options.
 // namespaceID is used to look up flow secrets for header interpolation.
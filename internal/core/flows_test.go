@@ -0,0 +1,177 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/cvhariharan/flowctl/internal/repo"
+	"github.com/google/uuid"
+)
+
+// approvalStore returns the approval requests of an execution by action ID
+type approvalStore struct {
+	repo.Store
+	approvals map[string]repo.ApprovalStatus
+}
+
+func (s *approvalStore) GetApprovalRequestForActionAndExec(ctx context.Context, arg repo.GetApprovalRequestForActionAndExecParams) (repo.Approval, error) {
+	status, ok := s.approvals[arg.ActionID]
+	if !ok {
+		return repo.Approval{}, sql.ErrNoRows
+	}
+	return repo.Approval{ActionID: arg.ActionID, Status: status}, nil
+}
+
+func TestCheckSkippedApprovals(t *testing.T) {
+	f := models.Flow{
+		Meta: models.Metadata{ID: "deploy"},
+		Actions: []models.Action{
+			{ID: "build"},
+			{ID: "approved", Approval: true},
+			{ID: "test"},
+			{ID: "pending", Approval: true},
+			{ID: "rejected", Approval: true},
+			{ID: "never_requested", Approval: true},
+			{ID: "release"},
+		},
+	}
+	namespace := uuid.NewString()
+	flows := newFlowCache()
+	flows.set(namespace, f)
+	c := &Core{
+		store: &approvalStore{approvals: map[string]repo.ApprovalStatus{
+			"approved": repo.ApprovalStatusApproved,
+			"pending":  repo.ApprovalStatusPending,
+			"rejected": repo.ApprovalStatusRejected,
+		}},
+		flows:  flows,
+		logMap: map[string]string{"exec-1": "deploy"},
+	}
+
+	tests := []struct {
+		name    string
+		from    string
+		to      string
+		wantErr string
+	}{
+		{"no approval in between", "build", "approved", ""},
+		{"approved action", "build", "pending", ""},
+		{"same action", "pending", "pending", ""},
+		{"earlier action", "release", "build", ""},
+		{"pending approval", "test", "rejected", "action pending was not approved"},
+		{"rejected approval", "rejected", "release", "action rejected was not approved"},
+		{"approval that was never requested", "never_requested", "release", "action never_requested was not approved"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, _ := f.GetActionIndexByID(tt.from)
+			to, _ := f.GetActionIndexByID(tt.to)
+
+			err := c.checkSkippedApprovals(context.Background(), f, "exec-1", from, to, uuid.NewString(), namespace)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("checkSkippedApprovals() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("checkSkippedApprovals() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// approverStore returns the users of the delegation fixture with their groups
+type approverStore struct {
+	*delegationStore
+}
+
+func (s approverStore) GetUserByUUIDWithGroups(ctx context.Context, id uuid.UUID) (repo.UserView, error) {
+	u, ok := s.users[id]
+	if !ok {
+		return repo.UserView{}, sql.ErrNoRows
+	}
+
+	groups := make([]models.Group, 0, len(s.groups[id]))
+	for _, g := range s.groups[id] {
+		groups = append(groups, models.Group{ID: g.Uuid.String(), Name: g.Name})
+	}
+	data, err := json.Marshal(groups)
+	if err != nil {
+		return repo.UserView{}, err
+	}
+	return repo.UserView{Uuid: u.Uuid, Name: u.Name, Username: u.Username, Role: repo.UserRoleType(u.Role), Groups: data}, nil
+}
+
+func TestCheckSkippedApprovals_Approvers(t *testing.T) {
+	fixture := newDelegationFixture(t)
+	f := models.Flow{
+		Meta: models.Metadata{ID: "deploy"},
+		Actions: []models.Action{
+			{ID: "restricted", Approval: true, Approvers: []string{"approver@example.com"}},
+			{ID: "oncall", Approval: true, Approvers: []string{"group:oncall"}},
+			{ID: "open", Approval: true},
+			{ID: "release"},
+		},
+	}
+
+	flows := newFlowCache()
+	flows.set(fixture.namespace.String(), f)
+	c := &Core{
+		store: &approvalStore{
+			Store: approverStore{fixture.store},
+			approvals: map[string]repo.ApprovalStatus{
+				"restricted": repo.ApprovalStatusApproved,
+				"oncall":     repo.ApprovalStatusApproved,
+				"open":       repo.ApprovalStatusApproved,
+			},
+		},
+		flows:  flows,
+		logMap: map[string]string{"exec-1": "deploy"},
+	}
+
+	superuser := uuid.New()
+	fixture.store.users[superuser] = repo.User{Uuid: superuser, Name: "admin", Username: "admin@example.com", Role: repo.UserRoleType(models.SuperuserUserRole)}
+	fixture.users["superuser"] = superuser
+
+	tests := []struct {
+		name          string
+		user          string
+		from          string
+		to            string
+		wantForbidden bool
+	}{
+		{"approver", "approver", "restricted", "oncall", false},
+		{"superuser", "superuser", "restricted", "release", false},
+		{"delegate of the approver", "delegate", "restricted", "oncall", false},
+		{"expired delegation", "expired", "restricted", "oncall", true},
+		{"user who is not an approver", "user", "restricted", "oncall", true},
+		{"member of an approver group", "group_member", "oncall", "open", false},
+		{"user outside an approver group", "user", "oncall", "open", true},
+		{"action without approvers", "user", "open", "release", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, _ := f.GetActionIndexByID(tt.from)
+			to, _ := f.GetActionIndexByID(tt.to)
+
+			err := c.checkSkippedApprovals(context.Background(), f, "exec-1", from, to, fixture.users[tt.user].String(), fixture.namespace.String())
+			if !tt.wantForbidden {
+				if err != nil {
+					t.Fatalf("checkSkippedApprovals() error = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrNotApprover) {
+				t.Errorf("checkSkippedApprovals() error = %v, want ErrNotApprover", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/cvhariharan/flowctl/internal/repo"
+	"github.com/google/uuid"
+)
+
+// ErrInputPresetNotFound is returned when a preset doesn't exist or is private to another user
+var ErrInputPresetNotFound = errors.New("input preset not found")
+
+// SaveInputPreset saves the input values of a flow under a name. Saving a preset with the name of
+// one of the user's presets replaces it.
+func (c *Core) SaveInputPreset(ctx context.Context, flowID, name string, inputs map[string]string, shared bool, userID, namespaceID string) (models.InputPreset, error) {
+	f, err := c.GetFlowByID(flowID, namespaceID)
+	if err != nil {
+		return models.InputPreset{}, err
+	}
+	if err := f.ValidatePreset(inputs); err != nil {
+		return models.InputPreset{}, err
+	}
+
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return models.InputPreset{}, fmt.Errorf("invalid user UUID: %w", err)
+	}
+
+	inputsJSON, err := json.Marshal(inputs)
+	if err != nil {
+		return models.InputPreset{}, fmt.Errorf("could not marshal inputs: %w", err)
+	}
+
+	p, err := c.store.UpsertInputPreset(ctx, repo.UpsertInputPresetParams{
+		FlowID:   f.Meta.DBID,
+		Name:     name,
+		Inputs:   inputsJSON,
+		IsShared: shared,
+		UserUuid: userUUID,
+	})
+	if err != nil {
+		return models.InputPreset{}, fmt.Errorf("could not save preset %s: %w", name, err)
+	}
+
+	return models.InputPreset{
+		ID:          p.Uuid.String(),
+		Name:        p.Name,
+		Inputs:      inputs,
+		Shared:      p.IsShared,
+		CreatedByID: userID,
+		CreatedAt:   p.CreatedAt,
+		UpdatedAt:   p.UpdatedAt,
+	}, nil
+}
+
+// ListInputPresets returns the presets of a flow a user can use, their own and the shared ones
+func (c *Core) ListInputPresets(ctx context.Context, flowID, userID, namespaceID string) ([]models.InputPreset, error) {
+	f, err := c.GetFlowByID(flowID, namespaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user UUID: %w", err)
+	}
+
+	rows, err := c.store.ListInputPresets(ctx, repo.ListInputPresetsParams{
+		FlowID:   f.Meta.DBID,
+		UserUuid: userUUID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not list presets of flow %s: %w", flowID, err)
+	}
+
+	presets := make([]models.InputPreset, 0, len(rows))
+	for _, r := range rows {
+		p, err := inputPresetFromRow(r)
+		if err != nil {
+			return nil, err
+		}
+		presets = append(presets, p)
+	}
+	return presets, nil
+}
+
+// GetInputPresetByName returns the preset of a flow a user can use with the given name. The
+// user's own preset takes precedence over a shared preset with the same name.
+func (c *Core) GetInputPresetByName(ctx context.Context, flowID, name, userID, namespaceID string) (models.InputPreset, error) {
+	f, err := c.GetFlowByID(flowID, namespaceID)
+	if err != nil {
+		return models.InputPreset{}, err
+	}
+
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return models.InputPreset{}, fmt.Errorf("invalid user UUID: %w", err)
+	}
+
+	r, err := c.store.GetInputPresetByName(ctx, repo.GetInputPresetByNameParams{
+		FlowID:   f.Meta.DBID,
+		Name:     name,
+		UserUuid: userUUID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.InputPreset{}, fmt.Errorf("%w: %s", ErrInputPresetNotFound, name)
+	}
+	if err != nil {
+		return models.InputPreset{}, fmt.Errorf("could not get preset %s: %w", name, err)
+	}
+	return inputPresetFromRow(repo.ListInputPresetsRow(r))
+}
+
+// GetInputPreset returns a preset of a flow a user can use by its ID
+func (c *Core) GetInputPreset(ctx context.Context, flowID, presetID, userID, namespaceID string) (models.InputPreset, error) {
+	f, err := c.GetFlowByID(flowID, namespaceID)
+	if err != nil {
+		return models.InputPreset{}, err
+	}
+
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return models.InputPreset{}, fmt.Errorf("invalid user UUID: %w", err)
+	}
+	presetUUID, err := uuid.Parse(presetID)
+	if err != nil {
+		return models.InputPreset{}, fmt.Errorf("%w: %s", ErrInputPresetNotFound, presetID)
+	}
+
+	r, err := c.store.GetInputPresetByUUID(ctx, repo.GetInputPresetByUUIDParams{
+		Uuid:     presetUUID,
+		FlowID:   f.Meta.DBID,
+		UserUuid: userUUID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.InputPreset{}, fmt.Errorf("%w: %s", ErrInputPresetNotFound, presetID)
+	}
+	if err != nil {
+		return models.InputPreset{}, fmt.Errorf("could not get preset %s: %w", presetID, err)
+	}
+	return inputPresetFromRow(repo.ListInputPresetsRow(r))
+}
+
+// DeleteInputPreset deletes a preset of a flow
+func (c *Core) DeleteInputPreset(ctx context.Context, flowID, presetID, namespaceID string) error {
+	f, err := c.GetFlowByID(flowID, namespaceID)
+	if err != nil {
+		return err
+	}
+
+	presetUUID, err := uuid.Parse(presetID)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInputPresetNotFound, presetID)
+	}
+
+	if err := c.store.DeleteInputPreset(ctx, repo.DeleteInputPresetParams{
+		Uuid:   presetUUID,
+		FlowID: f.Meta.DBID,
+	}); err != nil {
+		return fmt.Errorf("could not delete preset %s: %w", presetID, err)
+	}
+	return nil
+}
+
+func inputPresetFromRow(r repo.ListInputPresetsRow) (models.InputPreset, error) {
+	var inputs map[string]string
+	if err := json.Unmarshal(r.Inputs, &inputs); err != nil {
+		return models.InputPreset{}, fmt.Errorf("could not unmarshal inputs of preset %s: %w", r.Name, err)
+	}
+
+	return models.InputPreset{
+		ID:            r.Uuid.String(),
+		Name:          r.Name,
+		Inputs:        inputs,
+		Shared:        r.IsShared,
+		CreatedByID:   r.CreatedByUuid.String(),
+		CreatedByName: r.CreatedByName,
+		CreatedAt:     r.CreatedAt,
+		UpdatedAt:     r.UpdatedAt,
+	}, nil
+}
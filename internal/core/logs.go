@@ -2,10 +2,13 @@ package core
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"io"
 	"log"
+	"regexp"
+	"strings"
 	"time"
 
 	"encoding/json"
@@ -211,3 +214,229 @@ func (c *Core) checkApprovalRequests(ctx context.Context, execID string, namespa
 
 	return ch, nil
 }
+
+// logSearchTarget is an execution whose logs are searched
+type logSearchTarget struct {
+	execID        string
+	flowID        string
+	flowName      string
+	actionRetries map[string]int32
+}
+
+// SearchLogs searches the logs of finished executions in the namespace for q.Pattern.
+// Executions are searched newest first, up to models.LogSearchMaxExecutions of them.
+// Only executions visible to the caller are searched unless q.ExecID is set.
+func (c *Core) SearchLogs(ctx context.Context, namespaceID string, callerID string, q models.LogSearchQuery) (models.LogSearchResult, error) {
+	match, err := logMatcher(q)
+	if err != nil {
+		return models.LogSearchResult{}, err
+	}
+
+	if q.Limit <= 0 {
+		q.Limit = models.LogSearchDefaultLimit
+	}
+	q.Limit = min(q.Limit, models.LogSearchMaxLimit)
+	q.Context = max(0, min(q.Context, models.LogSearchMaxContext))
+
+	targets, err := c.logSearchTargets(ctx, namespaceID, callerID, q)
+	if err != nil {
+		return models.LogSearchResult{}, err
+	}
+
+	var result models.LogSearchResult
+	for _, t := range targets {
+		if len(result.Matches) >= q.Limit {
+			result.Truncated = true
+			break
+		}
+
+		if c.LogManager.LoggerExists(t.execID) {
+			continue
+		}
+
+		matches, truncated, err := c.searchExecutionLogs(ctx, t, match, q.Context, q.Limit-len(result.Matches))
+		if err != nil {
+			return models.LogSearchResult{}, fmt.Errorf("could not search logs for execution %s: %w", t.execID, err)
+		}
+
+		result.Matches = append(result.Matches, matches...)
+		result.SearchedExecutions++
+		if truncated {
+			result.Truncated = true
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// logMatcher returns a function that reports whether a log value matches the query pattern
+func logMatcher(q models.LogSearchQuery) (func(string) bool, error) {
+	if q.Pattern == "" {
+		return nil, fmt.Errorf("search pattern cannot be empty")
+	}
+
+	if q.Regex {
+		pattern := q.Pattern
+		if q.IgnoreCase {
+			pattern = "(?i)" + pattern
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid search pattern: %w", err)
+		}
+		return re.MatchString, nil
+	}
+
+	if q.IgnoreCase {
+		pattern := strings.ToLower(q.Pattern)
+		return func(s string) bool {
+			return strings.Contains(strings.ToLower(s), pattern)
+		}, nil
+	}
+
+	return func(s string) bool {
+		return strings.Contains(s, q.Pattern)
+	}, nil
+}
+
+// logSearchTargets returns the executions that should be searched for q
+func (c *Core) logSearchTargets(ctx context.Context, namespaceID string, callerID string, q models.LogSearchQuery) ([]logSearchTarget, error) {
+	if q.ExecID != "" {
+		exec, err := c.GetExecutionSummaryByExecID(ctx, q.ExecID, namespaceID)
+		if err != nil {
+			return nil, err
+		}
+
+		switch exec.Status {
+		case models.ExecutionStatusCompleted, models.ExecutionStatusErrored, models.ExecutionStatusCancelled, models.ExecutionStatusPendingApproval:
+			// ok to search
+		default:
+			return nil, fmt.Errorf("execution %s is still running, search is only available for finished executions", q.ExecID)
+		}
+
+		return []logSearchTarget{{
+			execID:        q.ExecID,
+			flowID:        exec.FlowID,
+			flowName:      exec.FlowName,
+			actionRetries: c.getActionRetries(ctx, q.ExecID, namespaceID),
+		}}, nil
+	}
+
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	callerUUID, err := uuid.Parse(callerID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid caller UUID: %w", err)
+	}
+
+	execs, err := c.store.GetExecutionsForLogSearch(ctx, repo.GetExecutionsForLogSearchParams{
+		NamespaceUuid: namespaceUUID,
+		CallerUuid:    callerUUID,
+		FlowSlug:      q.FlowID,
+		CreatedAfter:  sql.NullTime{Time: q.From, Valid: !q.From.IsZero()},
+		CreatedBefore: sql.NullTime{Time: q.To, Valid: !q.To.IsZero()},
+		MaxExecutions: models.LogSearchMaxExecutions,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not get executions to search: %w", err)
+	}
+
+	targets := make([]logSearchTarget, 0, len(execs))
+	for _, e := range execs {
+		actionRetries := make(map[string]int32)
+		if e.ActionRetries.Valid && len(e.ActionRetries.RawMessage) > 0 {
+			if err := json.Unmarshal(e.ActionRetries.RawMessage, &actionRetries); err != nil {
+				log.Printf("failed to parse action retries for exec %s, using empty map: %v", e.ExecID, err)
+			}
+		}
+
+		targets = append(targets, logSearchTarget{
+			execID:        e.ExecID,
+			flowID:        e.FlowSlug,
+			flowName:      e.FlowName,
+			actionRetries: actionRetries,
+		})
+	}
+
+	return targets, nil
+}
+
+// searchExecutionLogs returns up to limit messages of a single execution that match, with
+// contextLines messages around each of them. truncated is set if the limit was reached.
+func (c *Core) searchExecutionLogs(ctx context.Context, t logSearchTarget, match func(string) bool, contextLines int, limit int) ([]models.LogSearchMatch, bool, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	logCh, err := c.LogManager.StreamLogs(ctx, t.execID, t.actionRetries)
+	if err != nil {
+		return nil, false, err
+	}
+	// Drain the channel so the streaming goroutine can exit after cancellation
+	defer func() {
+		cancel()
+		for range logCh {
+		}
+	}()
+
+	var (
+		matches   []models.LogSearchMatch
+		before    []models.StreamMessage
+		pending   []int
+		truncated bool
+	)
+
+	for line := range logCh {
+		var sm models.StreamMessage
+		if err := json.Unmarshal([]byte(line), &sm); err != nil {
+			continue
+		}
+		// Progress events are structured data, not log output
+		if sm.MType == models.ProgressMessageType {
+			continue
+		}
+
+		// Fill the trailing context of earlier matches
+		open := pending[:0]
+		for _, i := range pending {
+			matches[i].After = append(matches[i].After, sm)
+			if len(matches[i].After) < contextLines {
+				open = append(open, i)
+			}
+		}
+		pending = open
+
+		if !truncated && match(sm.Val) {
+			if len(matches) >= limit {
+				truncated = true
+			} else {
+				matches = append(matches, models.LogSearchMatch{
+					ExecID:   t.execID,
+					FlowID:   t.flowID,
+					FlowName: t.flowName,
+					Message:  sm,
+					Before:   append([]models.StreamMessage(nil), before...),
+				})
+				if contextLines > 0 {
+					pending = append(pending, len(matches)-1)
+				}
+			}
+		}
+
+		if truncated && len(pending) == 0 {
+			break
+		}
+
+		if contextLines > 0 {
+			before = append(before, sm)
+			if len(before) > contextLines {
+				before = before[1:]
+			}
+		}
+	}
+
+	return matches, truncated, ctx.Err()
+}
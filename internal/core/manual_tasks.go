@@ -0,0 +1,236 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/cvhariharan/flowctl/internal/repo"
+	"github.com/cvhariharan/flowctl/internal/scheduler"
+	"github.com/google/uuid"
+	"github.com/sqlc-dev/pqtype"
+)
+
+var ErrNotTaskAssignee = errors.New("user is not a member of the group the task is assigned to")
+
+// GetManualTask returns a manual task with its instructions rendered with the inputs, the
+// outputs so far and the variables of its execution. Password inputs are masked.
+func (c *Core) GetManualTask(ctx context.Context, taskUUID string, namespaceID string) (models.ManualTaskDetails, error) {
+	uid, err := uuid.Parse(taskUUID)
+	if err != nil {
+		return models.ManualTaskDetails{}, fmt.Errorf("invalid task UUID: %w", err)
+	}
+
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return models.ManualTaskDetails{}, fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	t, err := c.store.GetManualTaskByUUID(ctx, repo.GetManualTaskByUUIDParams{
+		Uuid:   uid,
+		Uuid_2: namespaceUUID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.ManualTaskDetails{}, ErrNil
+		}
+		return models.ManualTaskDetails{}, fmt.Errorf("could not get manual task %s: %w", taskUUID, err)
+	}
+
+	details, task, err := c.repoManualTaskToDetails(repo.ManualTask{
+		Uuid:       t.Uuid,
+		ExecID:     t.ExecID,
+		ActionID:   t.ActionID,
+		Definition: t.Definition,
+		Status:     t.Status,
+		Response:   t.Response,
+		CreatedAt:  t.CreatedAt,
+		UpdatedAt:  t.UpdatedAt,
+	}, t.CompletedByName.String, namespaceID)
+	if err != nil {
+		return models.ManualTaskDetails{}, err
+	}
+
+	env := map[string]any{"vars": map[string]string{}}
+	if input, err := c.getExecutionInput(ctx, t.ExecID, namespaceUUID); err == nil {
+		env["inputs"] = maskInputs(input, c.passwordInputs(details.FlowID, namespaceID))
+	}
+	if outputs, err := c.GetExecutionOutputs(ctx, t.ExecID, namespaceID); err == nil {
+		env["outputs"] = outputs.Outputs
+	}
+	if vars, err := c.GetVariablesForNamespace(ctx, namespaceID); err == nil {
+		env["vars"] = vars
+	}
+	details.Instructions = task.RenderInstructions(env)
+
+	return details, nil
+}
+
+// ListManualTasks returns a page of the manual tasks of a namespace, newest first. All tasks are
+// returned if status is empty. The instructions are not rendered.
+func (c *Core) ListManualTasks(ctx context.Context, namespaceID, status string, page, countPerPage int) ([]models.ManualTaskDetails, int64, int64, error) {
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return nil, -1, -1, fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	offset := (page - 1) * countPerPage
+
+	tasks, err := c.store.ListManualTasks(ctx, repo.ListManualTasksParams{
+		Uuid:    namespaceUUID,
+		Column2: status,
+		Limit:   int32(countPerPage),
+		Offset:  int32(offset),
+	})
+	if err != nil {
+		return nil, -1, -1, fmt.Errorf("could not list manual tasks: %w", err)
+	}
+
+	details := make([]models.ManualTaskDetails, 0, len(tasks))
+	var pageCount, totalCount int64
+	for _, t := range tasks {
+		d, _, err := c.repoManualTaskToDetails(repo.ManualTask{
+			Uuid:       t.Uuid,
+			ExecID:     t.ExecID,
+			ActionID:   t.ActionID,
+			Definition: t.Definition,
+			Status:     t.Status,
+			Response:   t.Response,
+			CreatedAt:  t.CreatedAt,
+			UpdatedAt:  t.UpdatedAt,
+		}, t.CompletedByName.String, namespaceID)
+		if err != nil {
+			return nil, -1, -1, err
+		}
+		d.Instructions = ""
+		details = append(details, d)
+		pageCount = t.PageCount
+		totalCount = t.TotalCount
+	}
+
+	return details, pageCount, totalCount, nil
+}
+
+// CompleteManualTask completes a pending manual task with the response of a user and resumes
+// its execution. Only members of the group the task is assigned to can complete it, every
+// checklist item has to be ticked and the inputs have to be valid.
+func (c *Core) CompleteManualTask(ctx context.Context, taskUUID, userUUID string, checklist []string, inputs map[string]any, comment string, namespaceID string) error {
+	uid, err := uuid.Parse(taskUUID)
+	if err != nil {
+		return fmt.Errorf("invalid task UUID: %w", err)
+	}
+
+	userID, err := uuid.Parse(userUUID)
+	if err != nil {
+		return fmt.Errorf("user UUID is not a UUID: %w", err)
+	}
+
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	t, err := c.store.GetManualTaskByUUID(ctx, repo.GetManualTaskByUUIDParams{
+		Uuid:   uid,
+		Uuid_2: namespaceUUID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNil
+		}
+		return fmt.Errorf("could not get manual task %s: %w", taskUUID, err)
+	}
+	if t.Status != string(models.ManualTaskStatusPending) {
+		return fmt.Errorf("task has already been %s", t.Status)
+	}
+
+	var task models.ManualTask
+	if err := json.Unmarshal(t.Definition, &task); err != nil {
+		return fmt.Errorf("could not decode manual task %s: %w", taskUUID, err)
+	}
+
+	user, err := c.GetUserWithUUIDWithGroups(ctx, userUUID)
+	if err != nil {
+		return err
+	}
+	if task.Group != "" && user.Role != models.SuperuserUserRole && !slices.ContainsFunc(user.Groups, func(g models.Group) bool {
+		return g.Name == task.Group
+	}) {
+		return ErrNotTaskAssignee
+	}
+
+	if inputs == nil {
+		inputs = make(map[string]any)
+	}
+	if verr := task.ValidateResponse(checklist, inputs); verr != nil {
+		return verr
+	}
+
+	response, err := json.Marshal(scheduler.ManualTaskResponse{
+		Inputs:      inputs,
+		Checklist:   checklist,
+		Comment:     comment,
+		CompletedBy: user.Name,
+	})
+	if err != nil {
+		return err
+	}
+
+	completed, err := c.store.CompleteManualTask(ctx, repo.CompleteManualTaskParams{
+		Uuid:     uid,
+		Uuid_2:   namespaceUUID,
+		Response: pqtype.NullRawMessage{RawMessage: response, Valid: true},
+		Uuid_3:   userID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("task has already been processed")
+	}
+	if err != nil {
+		return fmt.Errorf("could not complete manual task %s: %w", taskUUID, err)
+	}
+
+	if err := c.ResumeFlowExecution(ctx, completed.ExecID, completed.ActionID, userUUID, namespaceID, true); err != nil {
+		return fmt.Errorf("could not resume exec %s: %w", completed.ExecID, err)
+	}
+	return nil
+}
+
+// repoManualTaskToDetails converts a stored task, returning the decoded definition with it
+func (c *Core) repoManualTaskToDetails(t repo.ManualTask, completedBy string, namespaceID string) (models.ManualTaskDetails, models.ManualTask, error) {
+	var task models.ManualTask
+	if err := json.Unmarshal(t.Definition, &task); err != nil {
+		return models.ManualTaskDetails{}, models.ManualTask{}, fmt.Errorf("could not decode manual task %s: %w", t.Uuid, err)
+	}
+
+	details := models.ManualTaskDetails{
+		UUID:         t.Uuid.String(),
+		ExecID:       t.ExecID,
+		ActionID:     t.ActionID,
+		Status:       models.ManualTaskStatus(t.Status),
+		Instructions: task.Instructions,
+		Group:        task.Group,
+		Checklist:    task.Checklist,
+		Inputs:       task.Inputs,
+		CompletedBy:  completedBy,
+		CreatedAt:    t.CreatedAt.Format(TimeFormat),
+		UpdatedAt:    t.UpdatedAt.Format(TimeFormat),
+	}
+	if f, err := c.GetFlowFromLogID(t.ExecID, namespaceID); err == nil {
+		details.FlowID = f.Meta.ID
+		details.FlowName = f.Meta.Name
+	}
+
+	if t.Response.Valid {
+		var resp scheduler.ManualTaskResponse
+		if err := json.Unmarshal(t.Response.RawMessage, &resp); err == nil {
+			details.Response = resp.Inputs
+			details.Comment = resp.Comment
+		}
+	}
+
+	return details, task, nil
+}
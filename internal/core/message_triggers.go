@@ -0,0 +1,191 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"gocloud.dev/pubsub"
+)
+
+// messageTriggerRetryDelay is how long a message whose execution could not be queued is held
+// before it is redelivered, so that an unavailable flow doesn't make the queue spin
+const messageTriggerRetryDelay = 30 * time.Second
+
+// messageTriggerPollInterval is how often the status of an execution started by a message is
+// checked, in case its completion event was missed
+const messageTriggerPollInterval = time.Minute
+
+// errInvalidMessage is returned for messages that can't be mapped to the inputs of the flow.
+// They are acknowledged and dropped since redelivering them can't succeed.
+var errInvalidMessage = errors.New("invalid message")
+
+// MessageTrigger runs a flow for every message received from a subscription
+type MessageTrigger struct {
+	Name string
+	// URL is a gocloud.dev subscription URL, the scheme picks the Kafka, NATS or SQS driver
+	URL       string
+	Namespace string
+	Flow      string
+	// Inputs maps input names to values with {{ message.* }} and {{ metadata.* }} placeholders.
+	// message is the JSON decoded body, or the body as a string if it isn't JSON.
+	Inputs map[string]string
+	// Concurrency is the number of executions started by the trigger that run at once
+	Concurrency int
+}
+
+// RunMessageTrigger queues an execution of the flow of t for every message received from its
+// subscription until ctx is done. At most Concurrency executions started by the trigger run at
+// once on this instance, the next message is received when one of them finishes. Messages are
+// acknowledged once their execution is queued.
+func (c *Core) RunMessageTrigger(ctx context.Context, t MessageTrigger) error {
+	ns, err := c.GetNamespaceByName(ctx, t.Namespace)
+	if err != nil {
+		return err
+	}
+	f, err := c.GetFlowByID(t.Flow, ns.ID)
+	if err != nil {
+		return fmt.Errorf("could not get flow %s: %w", t.Flow, err)
+	}
+	if err := models.ValidateTriggerInputs(f, t.Inputs, "message", "metadata"); err != nil {
+		return err
+	}
+
+	sub, err := pubsub.OpenSubscription(ctx, t.URL)
+	if err != nil {
+		return fmt.Errorf("could not open subscription: %w", err)
+	}
+	defer sub.Shutdown(context.Background())
+
+	slots := make(chan struct{}, max(t.Concurrency, 1))
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case slots <- struct{}{}:
+		}
+
+		msg, err := sub.Receive(ctx)
+		if err != nil {
+			<-slots
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("could not receive message: %w", err)
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			c.handleTriggerMessage(ctx, t, ns.ID, msg)
+		}()
+	}
+}
+
+// handleTriggerMessage queues an execution for a message and waits for it to finish
+func (c *Core) handleTriggerMessage(ctx context.Context, t MessageTrigger, namespaceID string, msg *pubsub.Message) {
+	// Subscribe before queueing so that the completion of a short execution isn't missed
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	events := c.SubscribeExecutionEvents(ctx, namespaceID)
+
+	execID, err := c.queueTriggerMessage(ctx, t, namespaceID, msg)
+	if errors.Is(err, errInvalidMessage) {
+		log.Printf("message trigger %s: dropping message: %v", t.Name, err)
+		msg.Ack()
+		return
+	}
+	if err != nil {
+		log.Printf("message trigger %s: %v", t.Name, err)
+		select {
+		case <-ctx.Done():
+		case <-time.After(messageTriggerRetryDelay):
+		}
+		if msg.Nackable() {
+			msg.Nack()
+		} else {
+			msg.Ack()
+		}
+		return
+	}
+	msg.Ack()
+
+	c.waitForExecution(ctx, events, execID, namespaceID)
+}
+
+// queueTriggerMessage maps a message to the inputs of the flow of t and queues an execution
+// as the system user
+func (c *Core) queueTriggerMessage(ctx context.Context, t MessageTrigger, namespaceID string, msg *pubsub.Message) (string, error) {
+	f, err := c.GetFlowByID(t.Flow, namespaceID)
+	if err != nil {
+		return "", fmt.Errorf("could not get flow %s: %w", t.Flow, err)
+	}
+
+	var body any
+	if err := json.Unmarshal(msg.Body, &body); err != nil {
+		body = string(msg.Body)
+	}
+	metadata := make(map[string]any, len(msg.Metadata))
+	for k, v := range msg.Metadata {
+		metadata[k] = v
+	}
+
+	inputs, err := models.MapTriggerInputs(f, t.Inputs, map[string]any{
+		"message":  body,
+		"metadata": metadata,
+	})
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", errInvalidMessage, err)
+	}
+	if verr := f.ValidateInput(inputs); verr != nil {
+		return "", fmt.Errorf("%w: %v", errInvalidMessage, verr)
+	}
+
+	execID, err := c.QueueFlowExecution(ctx, f, inputs, SystemUserUUID, namespaceID, nil)
+	if err != nil {
+		return "", fmt.Errorf("could not queue flow %s: %w", t.Flow, err)
+	}
+	return execID, nil
+}
+
+// waitForExecution returns once an execution has completed, errored or been cancelled, or ctx
+// is done. Executions waiting for an approval are still running.
+func (c *Core) waitForExecution(ctx context.Context, events <-chan models.ExecutionEvent, execID, namespaceID string) {
+	poll := time.NewTicker(messageTriggerPollInterval)
+	defer poll.Stop()
+	for {
+		var status models.ExecutionStatus
+		select {
+		case <-ctx.Done():
+			return
+		case e, ok := <-events:
+			if !ok {
+				return
+			}
+			if e.ExecID != execID {
+				continue
+			}
+			status = e.Status
+		case <-poll.C:
+			s, err := c.GetExecutionSummaryByExecID(ctx, execID, namespaceID)
+			if err != nil {
+				log.Printf("could not get status of exec %s: %v", execID, err)
+				continue
+			}
+			status = s.Status
+		}
+
+		switch status {
+		case models.ExecutionStatusCompleted, models.ExecutionStatusErrored, models.ExecutionStatusCancelled:
+			return
+		}
+	}
+}
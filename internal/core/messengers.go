@@ -0,0 +1,306 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/cvhariharan/flowctl/internal/messengers"
+	"github.com/cvhariharan/flowctl/internal/repo"
+)
+
+// messengerSecretKeys are config keys whose values are never returned by the API
+var messengerSecretKeys = []string{"password", "signing_key", "routing_key", "namespace_routing_keys", "api_key", "namespace_api_keys", "webhook_url", "bot_token"}
+
+const redactedValue = "********"
+
+// messengerConfigSyncInterval is how often RunMessengerConfigSync loads the messenger settings
+const messengerConfigSyncInterval = 30 * time.Second
+
+// LoadMessengerConfigs applies the messenger settings stored in the database on top of the config file.
+// Channels whose stored settings didn't change since they were applied are left as they are, and
+// channels whose settings were deleted go back to the config file, so changes made through any
+// instance are picked up. Channels with invalid settings are logged and keep their current settings.
+func (c *Core) LoadMessengerConfigs(ctx context.Context) error {
+	rows, err := c.store.ListMessengerConfigs(ctx)
+	if err != nil {
+		return fmt.Errorf("could not list messenger configs: %w", err)
+	}
+
+	c.messengerConfigsMu.Lock()
+	defer c.messengerConfigsMu.Unlock()
+	if c.messengerConfigs == nil {
+		c.messengerConfigs = make(map[string]time.Time)
+	}
+
+	stored := make(map[string]bool, len(rows))
+	for _, row := range rows {
+		stored[row.Channel] = true
+		if applied, ok := c.messengerConfigs[row.Channel]; ok && applied.Equal(row.UpdatedAt) {
+			continue
+		}
+
+		cfg, err := c.decryptMessengerConfig(ctx, row.EncryptedConfig)
+		if err != nil {
+			log.Printf("could not decrypt config for messenger %s: %v", row.Channel, err)
+			continue
+		}
+
+		if err := c.Messengers.Configure(row.Channel, row.Enabled, cfg); err != nil {
+			log.Printf("could not configure messenger %s: %v", row.Channel, err)
+			continue
+		}
+		c.messengerConfigs[row.Channel] = row.UpdatedAt
+	}
+
+	for _, status := range c.Messengers.Status() {
+		if !status.Overridden || stored[status.Channel] {
+			continue
+		}
+		if err := c.Messengers.Reset(status.Channel); err != nil {
+			log.Printf("could not restore messenger %s: %v", status.Channel, err)
+			continue
+		}
+		delete(c.messengerConfigs, status.Channel)
+	}
+
+	return nil
+}
+
+// RunMessengerConfigSync loads the messenger settings stored in the database periodically until
+// ctx is done, to apply the changes made through the API of other instances
+func (c *Core) RunMessengerConfigSync(ctx context.Context) {
+	t := time.NewTicker(messengerConfigSyncInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if err := c.LoadMessengerConfigs(ctx); err != nil {
+				log.Printf("failed to load messenger configs: %v", err)
+			}
+		}
+	}
+}
+
+// ListMessengerConfigs returns every supported channel with its current state
+func (c *Core) ListMessengerConfigs(ctx context.Context) ([]models.MessengerConfig, error) {
+	rows, err := c.store.ListMessengerConfigs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not list messenger configs: %w", err)
+	}
+
+	stored := make(map[string]repo.MessengerConfig, len(rows))
+	for _, row := range rows {
+		stored[row.Channel] = row
+	}
+
+	var configs []models.MessengerConfig
+	for _, status := range c.Messengers.Status() {
+		mc := models.MessengerConfig{
+			Channel: status.Channel,
+			Enabled: status.Enabled,
+			Source:  models.MessengerSourceFile,
+		}
+
+		if row, ok := stored[status.Channel]; ok && status.Overridden {
+			cfg, err := c.decryptMessengerConfig(ctx, row.EncryptedConfig)
+			if err != nil {
+				return nil, fmt.Errorf("could not decrypt config for messenger %s: %w", row.Channel, err)
+			}
+			mc.Source = models.MessengerSourceRuntime
+			mc.Config = redactMessengerConfig(cfg)
+			mc.UpdatedAt = row.UpdatedAt
+		}
+
+		configs = append(configs, mc)
+	}
+
+	return configs, nil
+}
+
+// UpdateMessengerConfig stores the runtime settings of a channel and applies them immediately.
+// cfg uses the same keys as the channel's section in the config file; keys that are not set
+// fall back to the config file.
+func (c *Core) UpdateMessengerConfig(ctx context.Context, channel string, enabled bool, cfg map[string]any) (models.MessengerConfig, error) {
+	if !slices.Contains(messengers.Channels, channel) {
+		return models.MessengerConfig{}, fmt.Errorf("unknown messenger %q", channel)
+	}
+
+	if cfg == nil {
+		cfg = make(map[string]any)
+	}
+
+	prev, prevCfg, err := c.storedMessengerConfig(ctx, channel)
+	if err != nil {
+		return models.MessengerConfig{}, err
+	}
+	restoreRedactedValues(cfg, prevCfg)
+
+	if err := c.Messengers.Validate(channel, enabled, cfg); err != nil {
+		return models.MessengerConfig{}, err
+	}
+
+	raw, err := json.Marshal(cfg)
+	if err != nil {
+		return models.MessengerConfig{}, fmt.Errorf("could not encode messenger config: %w", err)
+	}
+
+	enc, err := c.keeper.Encrypt(ctx, raw)
+	if err != nil {
+		return models.MessengerConfig{}, err
+	}
+
+	c.messengerConfigsMu.Lock()
+	defer c.messengerConfigsMu.Unlock()
+	if c.messengerConfigs == nil {
+		c.messengerConfigs = make(map[string]time.Time)
+	}
+
+	// Apply before storing so that a config that fails to initialize is never persisted
+	if err := c.Messengers.Configure(channel, enabled, cfg); err != nil {
+		return models.MessengerConfig{}, fmt.Errorf("could not configure messenger %s: %w", channel, err)
+	}
+
+	row, err := c.store.UpsertMessengerConfig(ctx, repo.UpsertMessengerConfigParams{
+		Channel:         channel,
+		Enabled:         enabled,
+		EncryptedConfig: hex.EncodeToString(enc),
+	})
+	if err != nil {
+		// Go back to the stored settings, which the other instances keep using
+		if rerr := c.restoreMessenger(channel, prev, prevCfg); rerr != nil {
+			log.Printf("could not restore messenger %s: %v", channel, rerr)
+		}
+		return models.MessengerConfig{}, fmt.Errorf("could not save messenger config: %w", err)
+	}
+	c.messengerConfigs[channel] = row.UpdatedAt
+
+	return models.MessengerConfig{
+		Channel:   channel,
+		Enabled:   enabled,
+		Source:    models.MessengerSourceRuntime,
+		Config:    redactMessengerConfig(cfg),
+		UpdatedAt: row.UpdatedAt,
+	}, nil
+}
+
+// DeleteMessengerConfig removes the runtime settings of a channel and restores the config file settings
+func (c *Core) DeleteMessengerConfig(ctx context.Context, channel string) error {
+	c.messengerConfigsMu.Lock()
+	defer c.messengerConfigsMu.Unlock()
+
+	if _, err := c.store.DeleteMessengerConfig(ctx, channel); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("messenger %s has no runtime config", channel)
+		}
+		return fmt.Errorf("could not delete messenger config: %w", err)
+	}
+
+	if err := c.Messengers.Reset(channel); err != nil {
+		return fmt.Errorf("could not restore messenger %s: %w", channel, err)
+	}
+	delete(c.messengerConfigs, channel)
+
+	return nil
+}
+
+// TestMessenger sends a test notification through an enabled channel. cfg is the per-flow
+// notify config for the channel, such as the email receivers or the webhook URL.
+func (c *Core) TestMessenger(ctx context.Context, channel string, cfg map[string]any, requestedBy string) error {
+	m, ok := c.Messengers.Get(channel)
+	if !ok {
+		return fmt.Errorf("messenger %s is not enabled", channel)
+	}
+
+	return m.Send(ctx, messengers.Message{
+		Event: messengers.EventTest,
+		Data: messengers.TestEvent{
+			Message:     "This is a test notification from flowctl",
+			RequestedBy: requestedBy,
+		},
+		Config: cfg,
+	})
+}
+
+// storedMessengerConfig returns the runtime settings stored for a channel and its decrypted
+// config, or nil if the channel uses the config file
+func (c *Core) storedMessengerConfig(ctx context.Context, channel string) (*repo.MessengerConfig, map[string]any, error) {
+	rows, err := c.store.ListMessengerConfigs(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not list messenger configs: %w", err)
+	}
+
+	for _, row := range rows {
+		if row.Channel != channel {
+			continue
+		}
+		cfg, err := c.decryptMessengerConfig(ctx, row.EncryptedConfig)
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not decrypt config for messenger %s: %w", channel, err)
+		}
+		return &row, cfg, nil
+	}
+	return nil, nil, nil
+}
+
+// restoreMessenger applies the stored settings of a channel again, or the config file settings
+// if there are none
+func (c *Core) restoreMessenger(channel string, stored *repo.MessengerConfig, cfg map[string]any) error {
+	if stored == nil {
+		return c.Messengers.Reset(channel)
+	}
+	return c.Messengers.Configure(channel, stored.Enabled, cfg)
+}
+
+// restoreRedactedValues replaces secret values that were sent back redacted with the stored values,
+// so a config returned by ListMessengerConfigs can be edited and saved again.
+func restoreRedactedValues(cfg map[string]any, existing map[string]any) {
+	for k, v := range cfg {
+		if v != redactedValue {
+			continue
+		}
+		if old, ok := existing[k]; ok {
+			cfg[k] = old
+		} else {
+			delete(cfg, k)
+		}
+	}
+}
+
+func (c *Core) decryptMessengerConfig(ctx context.Context, encrypted string) (map[string]any, error) {
+	enc, err := hex.DecodeString(encrypted)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := c.keeper.Decrypt(ctx, enc)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg map[string]any
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func redactMessengerConfig(cfg map[string]any) map[string]any {
+	redacted := make(map[string]any, len(cfg))
+	for k, v := range cfg {
+		if slices.Contains(messengerSecretKeys, k) {
+			v = redactedValue
+		}
+		redacted[k] = v
+	}
+	return redacted
+}
@@ -0,0 +1,125 @@
+package core
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/cvhariharan/flowctl/internal/config"
+	"github.com/cvhariharan/flowctl/internal/messengers"
+	"github.com/cvhariharan/flowctl/internal/repo"
+	"gocloud.dev/secrets"
+	"gocloud.dev/secrets/localsecrets"
+)
+
+// webhookSigningKey is a valid signing key for the webhook messenger
+const webhookSigningKey = "whsk_AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA="
+
+// messengerConfigStore keeps the runtime messenger settings in memory
+type messengerConfigStore struct {
+	repo.Store
+	rows      []repo.MessengerConfig
+	upsertErr error
+}
+
+func (s *messengerConfigStore) ListMessengerConfigs(ctx context.Context) ([]repo.MessengerConfig, error) {
+	return s.rows, nil
+}
+
+func (s *messengerConfigStore) UpsertMessengerConfig(ctx context.Context, arg repo.UpsertMessengerConfigParams) (repo.MessengerConfig, error) {
+	if s.upsertErr != nil {
+		return repo.MessengerConfig{}, s.upsertErr
+	}
+	row := repo.MessengerConfig{Channel: arg.Channel, Enabled: arg.Enabled, EncryptedConfig: arg.EncryptedConfig, UpdatedAt: time.Now()}
+	s.rows = []repo.MessengerConfig{row}
+	return row, nil
+}
+
+func newMessengerTestCore(t *testing.T, store repo.Store) *Core {
+	t.Helper()
+	key, err := localsecrets.NewRandomKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	keeper := localsecrets.NewKeeper(key)
+	t.Cleanup(func() { keeper.Close() })
+
+	return &Core{
+		store:      store,
+		keeper:     keeper,
+		Messengers: messengers.NewRegistry(config.MessengersConfig{}, messengers.RegistryOptions{Logger: slog.New(slog.DiscardHandler)}),
+	}
+}
+
+func encryptMessengerConfig(t *testing.T, keeper *secrets.Keeper, cfg map[string]any) string {
+	t.Helper()
+	raw, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc, err := keeper.Encrypt(context.Background(), raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hex.EncodeToString(enc)
+}
+
+func TestLoadMessengerConfigs(t *testing.T) {
+	store := &messengerConfigStore{}
+	c := newMessengerTestCore(t, store)
+	ctx := context.Background()
+
+	// Settings stored through another instance are applied
+	store.rows = []repo.MessengerConfig{{
+		Channel:         "webhook",
+		Enabled:         true,
+		EncryptedConfig: encryptMessengerConfig(t, c.keeper, map[string]any{"signing_key": webhookSigningKey}),
+		UpdatedAt:       time.Now(),
+	}}
+	if err := c.LoadMessengerConfigs(ctx); err != nil {
+		t.Fatalf("LoadMessengerConfigs() error = %v", err)
+	}
+	first, ok := c.Messengers.Get("webhook")
+	if !ok {
+		t.Fatal("webhook messenger is not enabled after loading its stored settings")
+	}
+
+	// Unchanged settings don't recreate the messenger
+	if err := c.LoadMessengerConfigs(ctx); err != nil {
+		t.Fatalf("LoadMessengerConfigs() error = %v", err)
+	}
+	if m, _ := c.Messengers.Get("webhook"); m != first {
+		t.Error("webhook messenger was recreated with unchanged settings")
+	}
+
+	// Deleted settings go back to the config file, where the webhook is disabled
+	store.rows = nil
+	if err := c.LoadMessengerConfigs(ctx); err != nil {
+		t.Fatalf("LoadMessengerConfigs() error = %v", err)
+	}
+	if _, ok := c.Messengers.Get("webhook"); ok {
+		t.Error("webhook messenger is still enabled after its stored settings were deleted")
+	}
+}
+
+func TestUpdateMessengerConfigNotSaved(t *testing.T) {
+	store := &messengerConfigStore{upsertErr: errors.New("connection refused")}
+	c := newMessengerTestCore(t, store)
+
+	_, err := c.UpdateMessengerConfig(context.Background(), "webhook", true, map[string]any{"signing_key": webhookSigningKey})
+	if err == nil {
+		t.Fatal("UpdateMessengerConfig() succeeded when the config couldn't be saved")
+	}
+	if _, ok := c.Messengers.Get("webhook"); ok {
+		t.Error("webhook messenger is enabled although its settings were not saved")
+	}
+	for _, status := range c.Messengers.Status() {
+		if status.Channel == "webhook" && status.Overridden {
+			t.Error("webhook messenger is configured at runtime although its settings were not saved")
+		}
+	}
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// APIToken is a personal access token that authenticates API requests as the user who created it
+type APIToken struct {
+	ID         string
+	Name       string
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	CreatedAt  time.Time
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// ApprovalDelegation grants another user or group the delegator's approval rights
+// in a namespace for a bounded time window.
+type ApprovalDelegation struct {
+	ID            string
+	DelegatorID   string
+	DelegatorName string
+	DelegateType  string // "user" or "group"
+	DelegateID    string
+	DelegateName  string
+	StartsAt      time.Time
+	EndsAt        time.Time
+	Reason        string
+	CreatedAt     time.Time
+}
+
+// IsActive reports whether the delegation is in effect at t.
+func (d ApprovalDelegation) IsActive(t time.Time) bool {
+	return !t.Before(d.StartsAt) && t.Before(d.EndsAt)
+}
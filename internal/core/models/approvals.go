@@ -70,11 +70,16 @@ func (a *ApprovalRequest) UnmarshalBinary(data []byte) error {
 type ApprovalDetails struct {
 	ApprovalRequest
 	DecidedBy string
+	Comment   string
 	Inputs    json.RawMessage
 	FlowName  string
 	FlowID    string
 	CreatedAt string
 	UpdatedAt string
+
+	// Assignees are the approvers of the action, or the owners of the flow if the action doesn't
+	// restrict who can approve it, who are expected to decide on the approval
+	Assignees []string
 }
 
 type ApprovalPaginationDetails struct {
@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+// Actions recorded in the audit log
+const (
+	AuditFlowCreate         = "flow.create"
+	AuditFlowUpdate         = "flow.update"
+	AuditFlowUpdateRequest  = "flow.update_request"
+	AuditFlowDelete         = "flow.delete"
+	AuditSecretCreate       = "secret.create"
+	AuditSecretUpdate       = "secret.update"
+	AuditSecretDelete       = "secret.delete"
+	AuditApprovalDecide     = "approval.decide"
+	AuditNodeCreate         = "node.create"
+	AuditNodeUpdate         = "node.update"
+	AuditNodeDelete         = "node.delete"
+	AuditCredentialCreate   = "credential.create"
+	AuditCredentialUpdate   = "credential.update"
+	AuditCredentialDelete   = "credential.delete"
+	AuditMemberAdd          = "member.add"
+	AuditMemberUpdate       = "member.update"
+	AuditMemberRemove       = "member.remove"
+	AuditMemberGrantAccess  = "member.grant_access"
+	AuditMemberRevokeAccess = "member.revoke_access"
+	AuditExecutionTrigger   = "execution.trigger"
+	AuditExecutionCancel    = "execution.cancel"
+	AuditUserCreate         = "user.create"
+	AuditUserUpdate         = "user.update"
+	AuditUserDelete         = "user.delete"
+	AuditGroupCreate        = "group.create"
+	AuditGroupUpdate        = "group.update"
+	AuditGroupDelete        = "group.delete"
+	AuditNamespaceCreate    = "namespace.create"
+	AuditNamespaceUpdate    = "namespace.update"
+	AuditNamespaceDelete    = "namespace.delete"
+)
+
+// Resources outside of namespaces whose changes are recorded in the audit log. They are not
+// RBAC resources, only superusers change them.
+const (
+	AuditResourceUser  Resource = "user"
+	AuditResourceGroup Resource = "group"
+)
+
+// AuditEntry is a change made through the API. Before and After are snapshots of the changed
+// resource, nil if it didn't exist before or after the change. NamespaceID is empty for changes
+// outside of namespaces.
+type AuditEntry struct {
+	ID           int64
+	NamespaceID  string
+	ActorID      string
+	ActorName    string
+	Action       string
+	ResourceType string
+	ResourceID   string
+	Before       any
+	After        any
+	SourceIP     string
+	CreatedAt    time.Time
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// Variable scopes
+const (
+	VariableScopeGlobal    = "global"
+	VariableScopeNamespace = "namespace"
+)
+
+// ConfigVariable is a plain, non-secret value that flows can use in expressions as vars.<Key>.
+// Global variables are available in every namespace, and a namespace variable with the same
+// key takes precedence.
+type ConfigVariable struct {
+	Key         string
+	Value       string
+	Description string
+	Scope       string
+	UpdatedAt   time.Time
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// ExecutionEvent is published when an execution is created or its status changes
+type ExecutionEvent struct {
+	ExecID        string          `json:"exec_id"`
+	NamespaceID   string          `json:"namespace_id"`
+	FlowID        string          `json:"flow_id"`
+	Status        ExecutionStatus `json:"status"`
+	TriggeredByID string          `json:"triggered_by"`
+	Timestamp     time.Time       `json:"timestamp"`
+}
+
+// Name returns the lifecycle event of the status change. Pending executions are queued.
+func (e ExecutionEvent) Name() string {
+	if e.Status == ExecutionStatusPending {
+		return "queued"
+	}
+	return string(e.Status)
+}
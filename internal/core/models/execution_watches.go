@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// ExecutionWatch is a flow or an execution a user is notified about. Watches without an ExecID
+// cover every execution of the flow.
+type ExecutionWatch struct {
+	FlowID    string
+	FlowName  string
+	ExecID    string
+	CreatedAt time.Time
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/cvhariharan/flowctl/internal/scheduler"
+)
+
+// ExecutorPolicy restricts the executors the flows of a namespace may use.
+// A policy without executors allows every executor.
+type ExecutorPolicy struct {
+	AllowedExecutors []string
+	UpdatedAt        time.Time
+}
+
+// Enabled reports whether the policy restricts executors
+func (p ExecutorPolicy) Enabled() bool {
+	return len(p.AllowedExecutors) > 0
+}
+
+// Allows reports whether actions may use the executor
+func (p ExecutorPolicy) Allows(executor string) bool {
+	return !p.Enabled() || slices.Contains(p.AllowedExecutors, executor)
+}
+
+// ValidateExecutors checks the executors of the flow's actions against the policy of its namespace.
+// Built-in executors run nothing on nodes and are always allowed.
+func (f Flow) ValidateExecutors(p ExecutorPolicy) error {
+	for _, action := range f.Actions {
+		if scheduler.IsBuiltinExecutor(action.Executor) {
+			continue
+		}
+		if !p.Allows(action.Executor) {
+			return fmt.Errorf("action %s uses executor %q which is not allowed in this namespace", action.ID, action.Executor)
+		}
+	}
+	return nil
+}
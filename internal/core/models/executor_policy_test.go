@@ -0,0 +1,30 @@
+package models
+
+import "testing"
+
+func TestFlow_ValidateExecutors(t *testing.T) {
+	f := Flow{Actions: []Action{
+		{ID: "build", Executor: "docker"},
+		{ID: "deploy", Executor: "script"},
+	}}
+
+	tests := []struct {
+		name    string
+		allowed []string
+		wantErr bool
+	}{
+		{"no policy", nil, false},
+		{"every executor allowed", []string{"docker", "script"}, false},
+		{"one executor not allowed", []string{"docker"}, true},
+		{"no executor allowed", []string{"kubernetes"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := f.ValidateExecutors(ExecutorPolicy{AllowedExecutors: tt.allowed})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateExecutors() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+const (
+	FailureAnalyticsDefaultWindow = 7 * 24 * time.Hour
+	FailureAnalyticsMaxWindow     = 90 * 24 * time.Hour
+	FailureAnalyticsDefaultLimit  = 10
+	FailureAnalyticsMaxLimit      = 100
+	// FailureCauseMaxSamples is the number of execution IDs kept as examples of each cause
+	FailureCauseMaxSamples = 5
+)
+
+// FailureAnalyticsQuery selects the window of failed executions to aggregate.
+// Limit caps the number of entries in each of the returned lists.
+type FailureAnalyticsQuery struct {
+	From  time.Time
+	To    time.Time
+	Limit int
+}
+
+// FailureAnalytics aggregates the errored executions of a namespace within a window
+type FailureAnalytics struct {
+	From          time.Time
+	To            time.Time
+	TotalFailures int
+	// Causes are the normalized error messages, most frequent first
+	Causes []FailureCause
+	// Actions are named flow_id/action_id
+	Actions []FailureCount
+	Nodes   []FailureCount
+}
+
+// FailureCause groups the failures whose errors only differ in details such as IDs, addresses and numbers
+type FailureCause struct {
+	// Message is the error with the varying parts replaced by placeholders
+	Message string
+	// SampleError is the original error of the most recent failure
+	SampleError   string
+	Count         int
+	FlowIDs       []string
+	ActionIDs     []string
+	SampleExecIDs []string
+	LastSeen      time.Time
+}
+
+// FailureCount is the number of failures attributed to an action or a node
+type FailureCount struct {
+	Name     string
+	Count    int
+	LastSeen time.Time
+}
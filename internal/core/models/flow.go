@@ -3,11 +3,16 @@ package models
 import (
 	"context"
 	"fmt"
+	"maps"
+	"mime"
 	"net/url"
+	"path"
+	"path/filepath"
 	"regexp"
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/cvhariharan/flowctl/internal/scheduler"
 	"github.com/expr-lang/expr"
@@ -45,9 +50,37 @@ type Input struct {
 	Default       string         `yaml:"default" huml:"default" json:"default"`
 	Options       []string       `yaml:"options" huml:"options" json:"options"`
 	MaxFileSize   int64          `yaml:"max_file_size" huml:"max_file_size" json:"max_file_size"`
+	Accept        []string       `yaml:"accept,omitempty" huml:"accept" json:"accept,omitempty"`
 	RemoteOptions *RemoteOptions `yaml:"remote_options,omitempty" huml:"remote_options" json:"remote_options,omitempty"`
 }
 
+// AcceptsFile reports whether a file with the given name and content type can be uploaded to
+// the input. Both are provided by the client, so this doesn't inspect the contents of the file.
+func (i Input) AcceptsFile(filename, contentType string) bool {
+	if len(i.Accept) == 0 {
+		return true
+	}
+
+	ext := strings.ToLower(filepath.Ext(filename))
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	for _, a := range i.Accept {
+		a = strings.ToLower(strings.TrimSpace(a))
+		switch {
+		case strings.HasPrefix(a, "."):
+			if ext == a {
+				return true
+			}
+		case strings.HasSuffix(a, "/*"):
+			if mediaType != "" && strings.HasPrefix(mediaType, strings.TrimSuffix(a, "*")) {
+				return true
+			}
+		case mediaType == a:
+			return true
+		}
+	}
+	return false
+}
+
 // type Schedule struct {
 // 	Cron     string `yaml:"cron" huml:"cron" json:"cron" validate:"required,cron"`
 // 	Timezone string `yaml:"timezone" huml:"timezone" json:"timezone" validate:"required,timezone"`
@@ -60,12 +93,14 @@ const (
 	NotifyEventOnFailure   NotifyEvent = "on_failure"
 	NotifyEventOnWaiting   NotifyEvent = "on_waiting"
 	NotifyEventOnCancelled NotifyEvent = "on_cancelled"
+	NotifyEventOnSkipped   NotifyEvent = "on_skipped"
+	NotifyEventOnSLABreach NotifyEvent = "on_sla_breach"
 )
 
 type Notify struct {
-	Channel string         `yaml:"channel" huml:"channel" json:"channel" validate:"required,oneof=email webhook"`
+	Channel string         `yaml:"channel" huml:"channel" json:"channel" validate:"required,oneof=email webhook pagerduty opsgenie slack"`
 	Config  map[string]any `yaml:"config" huml:"config" json:"config" validate:"required"`
-	Events  []NotifyEvent  `yaml:"events" huml:"events" json:"events" validate:"required,dive,min=1,oneof=on_success on_failure on_waiting on_cancelled"`
+	Events  []NotifyEvent  `yaml:"events" huml:"events" json:"events" validate:"required,dive,min=1,oneof=on_success on_failure on_waiting on_cancelled on_skipped on_sla_breach"`
 }
 
 type Action struct {
@@ -76,6 +111,28 @@ type Action struct {
 	Approval  bool           `yaml:"approval" huml:"approval"`
 	Variables []Variable     `yaml:"variables" huml:"variables"`
 	On        []string       `yaml:"on" huml:"on"`
+	// Telemetry samples the resource usage of the nodes while the action runs
+	Telemetry bool `yaml:"telemetry,omitempty" huml:"telemetry"`
+	// Lock is a mutex key held while the action runs. Actions and flows in the namespace that
+	// declare the same key run one at a time.
+	Lock string `yaml:"lock,omitempty" huml:"lock" validate:"omitempty,printascii,max=100"`
+	// Approvers are the usernames of users and group:name references of groups that can decide
+	// on the approval of the action. Anyone who can approve requests in the namespace can if empty.
+	Approvers []string `yaml:"approvers,omitempty" huml:"approvers" validate:"omitempty,dive,required,max=150"`
+	// Username and Credential override the user and the credential, by name, that the action
+	// logs in to its nodes with
+	Username   string `yaml:"username,omitempty" huml:"username" validate:"omitempty,min=1,max=50"`
+	Credential string `yaml:"credential,omitempty" huml:"credential" validate:"omitempty,max=150"`
+	// Needs are the IDs of the actions that must complete before the action runs. Once an action
+	// of the flow declares needs, its actions run as a graph and actions that don't need each
+	// other run in parallel.
+	Needs []string `yaml:"needs,omitempty" huml:"needs" validate:"omitempty,dive,required"`
+	// Condition is an expression on the inputs, outputs, secrets and variables of the execution.
+	// The action is skipped when it evaluates to false.
+	Condition string `yaml:"condition,omitempty" huml:"condition" validate:"omitempty,max=1000"`
+	// Artifacts are patterns of files in the artifact directory that are archived once the action
+	// completed, so that they can be downloaded from the execution
+	Artifacts []string `yaml:"artifacts,omitempty" huml:"artifacts" validate:"omitempty,dive,required,max=255"`
 }
 
 func SchedulerActionToAction(a scheduler.Action) Action {
@@ -90,13 +147,20 @@ func SchedulerActionToAction(a scheduler.Action) Action {
 	}
 
 	return Action{
-		ID:        a.ID,
-		Name:      a.Name,
-		With:      a.With,
-		On:        nodeNames,
-		Executor:  a.Executor,
-		Approval:  a.Approval,
-		Variables: variables,
+		ID:         a.ID,
+		Name:       a.Name,
+		With:       a.With,
+		On:         nodeNames,
+		Executor:   a.Executor,
+		Approval:   a.Approval,
+		Variables:  variables,
+		Lock:       a.Lock,
+		Approvers:  a.Approvers,
+		Username:   a.Username,
+		Credential: a.Credential,
+		Needs:      a.Needs,
+		Condition:  a.Condition,
+		Artifacts:  a.Artifacts,
 	}
 }
 
@@ -110,6 +174,104 @@ type Metadata struct {
 	Prefix          string `yaml:"prefix" huml:"prefix" validate:"omitempty,alphanum_underscore,max=100"`
 	AllowOverlap    bool   `yaml:"allow_overlap" huml:"allow_overlap"`
 	UserSchedulable bool   `yaml:"user_schedulable" huml:"user_schedulable"`
+	SLA             *SLA   `yaml:"sla,omitempty" huml:"sla" validate:"omitempty"`
+	CommitSHA       string `yaml:"-" huml:"-"`
+	// Lock is a mutex key held while an execution of the flow runs. Executions of flows in the
+	// namespace that declare the same key run one at a time.
+	Lock string `yaml:"lock,omitempty" huml:"lock" validate:"omitempty,printascii,max=100"`
+	// MaxConcurrency is the number of executions of the flow that run at the same time, the
+	// others stay queued. 0 doesn't limit them.
+	MaxConcurrency int `yaml:"max_concurrency,omitempty" huml:"max_concurrency" validate:"gte=0,lte=1000"`
+
+	// Owners are the usernames of users and group:name references of groups that are members of
+	// the namespace. They are notified when the flow doesn't set its own notifications.
+	Owners []string `yaml:"owners,omitempty" huml:"owners" validate:"omitempty,dive,required,max=150"`
+}
+
+// Kinds of SLA breaches
+const (
+	SLABreachMaxDuration = "max_duration"
+	SLABreachDeadline    = "deadline"
+)
+
+// SLA is the expected run time of a flow's executions. An execution breaches it if it hasn't
+// finished within MaxDuration of when it was triggered or scheduled to run, or by the next
+// Deadline, a time of day in Timezone, after that.
+type SLA struct {
+	MaxDuration string `yaml:"max_duration,omitempty" huml:"max_duration" json:"max_duration,omitempty"`
+	Deadline    string `yaml:"deadline,omitempty" huml:"deadline" json:"deadline,omitempty"`
+	Timezone    string `yaml:"timezone,omitempty" huml:"timezone" json:"timezone,omitempty" validate:"omitempty,timezone"`
+}
+
+// SLABreach describes how an execution breached the SLA
+type SLABreach struct {
+	Kind   string
+	Reason string
+}
+
+func (s SLA) Validate() error {
+	if s.MaxDuration == "" && s.Deadline == "" {
+		return fmt.Errorf("sla requires max_duration or deadline")
+	}
+	if s.MaxDuration != "" {
+		d, err := time.ParseDuration(s.MaxDuration)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("sla max_duration must be a positive duration such as 30m or 2h")
+		}
+	}
+	if s.Deadline != "" {
+		if _, err := time.Parse("15:04", s.Deadline); err != nil {
+			return fmt.Errorf("sla deadline must be a time of day in HH:MM format")
+		}
+	}
+	return nil
+}
+
+// Breaches returns the ways an execution that started at start and hasn't finished by now
+// breached the SLA
+func (s SLA) Breaches(start time.Time, now time.Time) []SLABreach {
+	var breaches []SLABreach
+
+	if d, err := time.ParseDuration(s.MaxDuration); err == nil && d > 0 && now.Sub(start) > d {
+		breaches = append(breaches, SLABreach{
+			Kind:   SLABreachMaxDuration,
+			Reason: fmt.Sprintf("execution has been running for %s, longer than the expected %s", now.Sub(start).Truncate(time.Second), d),
+		})
+	}
+
+	if deadline, ok := s.deadlineAfter(start); ok && now.After(deadline) {
+		breaches = append(breaches, SLABreach{
+			Kind:   SLABreachDeadline,
+			Reason: fmt.Sprintf("execution did not complete by %s", deadline.Format("2006-01-02 15:04 MST")),
+		})
+	}
+
+	return breaches
+}
+
+// deadlineAfter returns the first occurrence of the deadline time of day at or after start
+func (s SLA) deadlineAfter(start time.Time) (time.Time, bool) {
+	if s.Deadline == "" {
+		return time.Time{}, false
+	}
+	tod, err := time.Parse("15:04", s.Deadline)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	loc := time.UTC
+	if s.Timezone != "" {
+		if l, err := time.LoadLocation(s.Timezone); err == nil {
+			loc = l
+		}
+	}
+
+	t := start.In(loc)
+	deadline := time.Date(t.Year(), t.Month(), t.Day(), tod.Hour(), tod.Minute(), 0, 0, loc)
+	if deadline.Before(t) {
+		deadline = deadline.AddDate(0, 0, 1)
+	}
+	return deadline, true
 }
 
 type Variable map[string]any
@@ -161,6 +323,10 @@ type Flow struct {
 	Outputs   []Output   `yaml:"outputs" huml:"outputs"`
 	Schedules []Schedule `yaml:"schedules" huml:"schedules" validate:"omitempty,dive"`
 	Notify    []Notify   `yaml:"notify" huml:"notify" json:"notify" validate:"omitempty,dive"`
+	// Triggers run the flow when executions of other flows in the namespace finish
+	Triggers []FlowTrigger `yaml:"triggers" huml:"triggers" json:"triggers" validate:"omitempty,dive"`
+	// Webhook runs the flow for the requests to its webhook, once a webhook is created for the flow
+	Webhook *WebhookTrigger `yaml:"webhook,omitempty" huml:"webhook" json:"webhook,omitempty" validate:"omitempty"`
 }
 
 func AlphanumericUnderscore(fl validator.FieldLevel) bool {
@@ -221,11 +387,67 @@ func (f Flow) Validate() error {
 		actionsIDs[action.ID] = 1
 	}
 
+	for _, action := range f.Actions {
+		var err error
+		switch action.Executor {
+		case scheduler.ExecutorManual:
+			err = validateManualAction(validate, action)
+		case scheduler.ExecutorWait:
+			err = validateWaitAction(action)
+		case scheduler.ExecutorFlow:
+			err = validateSubflowAction(f, action)
+		}
+		if err != nil {
+			return fmt.Errorf("action %s: %w", action.ID, err)
+		}
+		if len(action.Approvers) > 0 && !action.Approval {
+			return fmt.Errorf("action %s: approvers can only be set on actions that require approval", action.ID)
+		}
+		if (action.Username != "" || action.Credential != "") && len(action.On) == 0 {
+			return fmt.Errorf("action %s: username and credential can only be set on actions that run on nodes", action.ID)
+		}
+		// The types of the inputs and outputs are only known when the action is reached
+		if action.Condition != "" {
+			if _, err := expr.Compile(action.Condition, expr.AsBool()); err != nil {
+				return fmt.Errorf("action %s: invalid condition: %w", action.ID, err)
+			}
+		}
+		for _, pattern := range action.Artifacts {
+			if _, err := path.Match(pattern, ""); err != nil || !filepath.IsLocal(pattern) {
+				return fmt.Errorf("action %s: invalid artifact pattern %q", action.ID, pattern)
+			}
+		}
+	}
+
+	if err := validateNeeds(f.Actions); err != nil {
+		return err
+	}
+
 	// Validate default values for inputs
 	for _, input := range f.Inputs {
 		if err := validateDefaultValue(input); err != nil {
 			return fmt.Errorf("validation error for input %s: %w", input.Name, err)
 		}
+		if err := validateAccept(input); err != nil {
+			return fmt.Errorf("validation error for input %s: %w", input.Name, err)
+		}
+	}
+
+	if f.Meta.SLA != nil {
+		if err := f.Meta.SLA.Validate(); err != nil {
+			return err
+		}
+	}
+
+	for _, t := range f.Triggers {
+		if err := t.validate(f); err != nil {
+			return err
+		}
+	}
+	if f.Webhook != nil {
+		if err := f.Webhook.validate(f); err != nil {
+			return err
+		}
 	}
 
 	// Reject reserved prefix values that collide with Casbin domain sentinels
@@ -268,6 +490,165 @@ func (f Flow) IsApprovalRequired() bool {
 	return false
 }
 
+// SearchContent returns the text of the flow that is searched besides its name and description:
+// the labels and descriptions of its inputs, the names of its actions and the string values
+// of their configuration, such as scripts, commands and images
+func (f Flow) SearchContent() string {
+	var parts []string
+	for _, input := range f.Inputs {
+		parts = append(parts, input.Label, input.Description)
+	}
+	for _, action := range f.Actions {
+		parts = append(parts, action.Name)
+		parts = appendSearchValues(parts, action.With)
+	}
+
+	return strings.Join(slices.DeleteFunc(parts, func(s string) bool { return s == "" }), "\n")
+}
+
+// appendSearchValues appends the string values nested in v to parts
+func appendSearchValues(parts []string, v any) []string {
+	switch v := v.(type) {
+	case string:
+		return append(parts, v)
+	case map[string]any:
+		// Sorted so that the content only changes when the flow does
+		for _, k := range slices.Sorted(maps.Keys(v)) {
+			parts = appendSearchValues(parts, v[k])
+		}
+	case []any:
+		for _, e := range v {
+			parts = appendSearchValues(parts, e)
+		}
+	}
+	return parts
+}
+
+// validateAccept validates that only file inputs set accept and that its entries are file
+// extensions or media types
+func validateAccept(input Input) error {
+	if len(input.Accept) == 0 {
+		return nil
+	}
+
+	if input.Type != INPUT_TYPE_FILE {
+		return fmt.Errorf("accept can only be set for file inputs")
+	}
+
+	for _, a := range input.Accept {
+		a = strings.TrimSpace(a)
+		if !strings.HasPrefix(a, ".") && !strings.Contains(a, "/") {
+			return fmt.Errorf("accept entry %q must be a file extension such as .csv or a media type such as text/plain", a)
+		}
+	}
+	return nil
+}
+
+// validateWaitAction validates the with block of a wait action. An until expression can only be
+// evaluated when the action is reached, so it is only compiled.
+func validateWaitAction(action Action) error {
+	if len(action.On) > 0 {
+		return fmt.Errorf("wait actions don't run on nodes")
+	}
+
+	if until, ok := action.With["until"].(string); ok {
+		if m := triggerExprPattern.FindStringSubmatch(until); m != nil && m[0] == strings.TrimSpace(until) {
+			if _, ok := action.With["duration"]; ok {
+				return fmt.Errorf("only one of duration and until can be set")
+			}
+			if _, err := expr.Compile(strings.TrimSpace(m[1])); err != nil {
+				return fmt.Errorf("invalid until: %w", err)
+			}
+			return nil
+		}
+	}
+
+	_, err := scheduler.WaitUntil(action.With, nil, time.Now())
+	return err
+}
+
+// validateSubflowAction validates the with block of a flow action. The flow it runs is looked up
+// when the action is reached, since flows of a namespace can be loaded in any order.
+func validateSubflowAction(f Flow, action Action) error {
+	if len(action.On) > 0 {
+		return fmt.Errorf("flow actions don't run on nodes")
+	}
+
+	flowID, _ := action.With["flow"].(string)
+	if flowID == "" {
+		return fmt.Errorf("flow is required")
+	}
+	if flowID == f.Meta.ID {
+		return fmt.Errorf("a flow can't run itself")
+	}
+
+	inputs, ok := action.With["inputs"]
+	if !ok || inputs == nil {
+		return nil
+	}
+	m, ok := inputs.(map[string]any)
+	if !ok {
+		return fmt.Errorf("inputs must be a map")
+	}
+	for k, v := range m {
+		s, ok := v.(string)
+		if !ok {
+			continue
+		}
+		for _, match := range triggerExprPattern.FindAllStringSubmatch(s, -1) {
+			if _, err := expr.Compile(strings.TrimSpace(match[1])); err != nil {
+				return fmt.Errorf("invalid input %s: %w", k, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateNeeds checks that the actions needed by each action exist and that actions don't need
+// each other in a cycle, which would never run
+func validateNeeds(actions []Action) error {
+	ids := make(map[string]bool, len(actions))
+	for _, action := range actions {
+		ids[action.ID] = true
+	}
+
+	for _, action := range actions {
+		for _, need := range action.Needs {
+			if need == action.ID {
+				return fmt.Errorf("action %s: an action can't need itself", action.ID)
+			}
+			if !ids[need] {
+				return fmt.Errorf("action %s: needs unknown action %s", action.ID, need)
+			}
+		}
+	}
+
+	// Actions are removed once all the actions they need are removed, the ones left are in a cycle
+	done := make(map[string]bool, len(actions))
+	for len(done) < len(actions) {
+		progressed := false
+		for _, action := range actions {
+			if done[action.ID] {
+				continue
+			}
+			if !slices.ContainsFunc(action.Needs, func(need string) bool { return !done[need] }) {
+				done[action.ID] = true
+				progressed = true
+			}
+		}
+		if !progressed {
+			break
+		}
+	}
+	for _, action := range actions {
+		if !done[action.ID] {
+			return fmt.Errorf("action %s: needs form a cycle", action.ID)
+		}
+	}
+
+	return nil
+}
+
 // validateDefaultValue validates that a default value matches the expected input type
 func validateDefaultValue(input Input) error {
 	if input.Type == INPUT_TYPE_FILE && input.Default != "" {
@@ -381,6 +762,14 @@ type Execution struct {
 	TriggeredBy string                 `json:"triggered_by"`
 }
 
+// FlowImportError is a flow file that could not be loaded. FilePath is relative to the
+// namespace directory.
+type FlowImportError struct {
+	FilePath  string
+	Error     string
+	CreatedAt time.Time
+}
+
 // FlowFormat represents the file format for flows
 type FlowFormat string
 
@@ -445,8 +834,9 @@ func ParseActionTargets(on []string) (nodeNames []string, tags []string) {
 	return nodeNames, tags
 }
 
-// ConvertToSchedulerFlow converts a Flow to scheduler.Flow
-func ConvertToSchedulerFlow(ctx context.Context, f Flow, namespaceUUID uuid.UUID, getNodesByNames func(context.Context, []string, uuid.UUID) ([]Node, error), getNodesByTags func(context.Context, []string, uuid.UUID) ([]Node, error)) (scheduler.Flow, error) {
+// ConvertToSchedulerFlow converts a Flow to scheduler.Flow. getCredential returns a credential of the
+// namespace by name, with its key decrypted, for actions that log in to their nodes with another credential.
+func ConvertToSchedulerFlow(ctx context.Context, f Flow, namespaceUUID uuid.UUID, getNodesByNames func(context.Context, []string, uuid.UUID) ([]Node, error), getNodesByTags func(context.Context, []string, uuid.UUID) ([]Node, error), getCredential func(context.Context, string, uuid.UUID) (Credential, error)) (scheduler.Flow, error) {
 	// Convert inputs
 	var inputs []scheduler.Input
 	for _, inp := range f.Inputs {
@@ -513,6 +903,26 @@ func ConvertToSchedulerFlow(ctx context.Context, f Flow, namespaceUUID uuid.UUID
 			})
 		}
 
+		// The action may log in to its nodes as another user or with another credential
+		if act.Credential != "" {
+			cred, err := getCredential(ctx, act.Credential, namespaceUUID)
+			if err != nil {
+				return scheduler.Flow{}, fmt.Errorf("failed to get credential %s for action %s: %w", act.Credential, act.ID, err)
+			}
+			for i := range schedulerNodes {
+				schedulerNodes[i].Auth = scheduler.NodeAuth{
+					CredentialID: cred.ID,
+					Method:       scheduler.AuthMethod(cred.KeyType),
+					Key:          cred.KeyData,
+				}
+			}
+		}
+		if act.Username != "" {
+			for i := range schedulerNodes {
+				schedulerNodes[i].Username = act.Username
+			}
+		}
+
 		// Convert variables
 		var variables []scheduler.Variable
 		for _, v := range act.Variables {
@@ -520,13 +930,21 @@ func ConvertToSchedulerFlow(ctx context.Context, f Flow, namespaceUUID uuid.UUID
 		}
 
 		actions = append(actions, scheduler.Action{
-			ID:        act.ID,
-			Name:      act.Name,
-			Executor:  act.Executor,
-			With:      act.With,
-			Approval:  act.Approval,
-			Variables: variables,
-			On:        schedulerNodes,
+			ID:         act.ID,
+			Name:       act.Name,
+			Executor:   act.Executor,
+			With:       act.With,
+			Approval:   act.Approval,
+			Variables:  variables,
+			On:         schedulerNodes,
+			Telemetry:  act.Telemetry,
+			Lock:       act.Lock,
+			Approvers:  act.Approvers,
+			Username:   act.Username,
+			Credential: act.Credential,
+			Needs:      act.Needs,
+			Condition:  act.Condition,
+			Artifacts:  act.Artifacts,
 		})
 	}
 
@@ -567,6 +985,7 @@ func ConvertToSchedulerFlow(ctx context.Context, f Flow, namespaceUUID uuid.UUID
 			Description: f.Meta.Description,
 			SrcDir:      f.Meta.SrcDir,
 			Namespace:   f.Meta.Namespace,
+			Lock:        f.Meta.Lock,
 		},
 		Inputs:    inputs,
 		Actions:   actions,
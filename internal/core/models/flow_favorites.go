@@ -0,0 +1,9 @@
+package models
+
+import "time"
+
+// RecentFlow is a flow a user triggered and when they last did
+type RecentFlow struct {
+	Flow      Flow
+	LastRunAt time.Time
+}
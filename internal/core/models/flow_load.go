@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+type FlowLoadState string
+
+const (
+	// FlowLoadPending is the state before flows are loaded for the first time
+	FlowLoadPending  FlowLoadState = "pending"
+	FlowLoadLoading  FlowLoadState = "loading"
+	FlowLoadFinished FlowLoadState = "finished"
+	FlowLoadFailed   FlowLoadState = "failed"
+)
+
+// FlowLoadStatus reports the progress of the latest load of the flows directory. Flows are
+// counted once their namespace has been read, so Total grows while namespaces are loaded.
+type FlowLoadStatus struct {
+	State            FlowLoadState
+	Namespaces       int
+	NamespacesLoaded int
+	Total            int
+	Loaded           int
+	Failed           int
+	StartedAt        time.Time
+	FinishedAt       time.Time
+	Error            string
+}
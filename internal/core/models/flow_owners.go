@@ -0,0 +1,88 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// OwnerGroupPrefix marks owners that are groups, the same way as email receivers
+const OwnerGroupPrefix = "group:"
+
+// OwnerNotifyEvents are the events the owners of a flow are notified of by email when the flow
+// doesn't set its own notifications
+var OwnerNotifyEvents = []NotifyEvent{NotifyEventOnFailure, NotifyEventOnWaiting, NotifyEventOnSLABreach}
+
+// ValidateOwners checks that the owners of the flow and the approvers of its actions are among
+// the given usernames and group names
+func (f Flow) ValidateOwners(users, groups []string) error {
+	for _, owner := range f.Meta.Owners {
+		if err := validateMember(owner, users, groups); err != nil {
+			return fmt.Errorf("owner %w", err)
+		}
+	}
+
+	for _, action := range f.Actions {
+		for _, approver := range action.Approvers {
+			if err := validateMember(approver, users, groups); err != nil {
+				return fmt.Errorf("approver of action %s: %w", action.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+func validateMember(member string, users, groups []string) error {
+	if group, ok := strings.CutPrefix(member, OwnerGroupPrefix); ok {
+		if !slices.Contains(groups, group) {
+			return fmt.Errorf("%s is not a group in the namespace", member)
+		}
+		return nil
+	}
+
+	if !slices.ContainsFunc(users, func(u string) bool { return strings.EqualFold(u, member) }) {
+		return fmt.Errorf("%s is not a member of the namespace", member)
+	}
+	return nil
+}
+
+// HasApprovers reports whether any action of the flow restricts who can approve it
+func (f Flow) HasApprovers() bool {
+	return slices.ContainsFunc(f.Actions, func(a Action) bool { return len(a.Approvers) > 0 })
+}
+
+// IsApprover reports whether a user with the given username and group names can decide on the
+// approval of the action
+func (a Action) IsApprover(username string, groups []string) bool {
+	if len(a.Approvers) == 0 {
+		return true
+	}
+
+	for _, approver := range a.Approvers {
+		if group, ok := strings.CutPrefix(approver, OwnerGroupPrefix); ok {
+			if slices.Contains(groups, group) {
+				return true
+			}
+			continue
+		}
+		if strings.EqualFold(approver, username) {
+			return true
+		}
+	}
+	return false
+}
+
+// WithOwnerNotify returns a copy of the flow that notifies its owners by email if the flow has
+// owners and no notifications of its own
+func (f Flow) WithOwnerNotify() Flow {
+	if len(f.Notify) > 0 || len(f.Meta.Owners) == 0 {
+		return f
+	}
+
+	f.Notify = []Notify{{
+		Channel: "email",
+		Config:  map[string]any{"receivers": slices.Clone(f.Meta.Owners)},
+		Events:  slices.Clone(OwnerNotifyEvents),
+	}}
+	return f
+}
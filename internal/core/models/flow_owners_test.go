@@ -0,0 +1,110 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFlow_ValidateOwners(t *testing.T) {
+	users := []string{"alice@example.com", "bob@example.com"}
+	groups := []string{"ops"}
+
+	tests := []struct {
+		name    string
+		owners  []string
+		wantErr bool
+	}{
+		{"no owners", nil, false},
+		{"members", []string{"alice@example.com", "group:ops"}, false},
+		{"usernames are case insensitive", []string{"Bob@Example.com"}, false},
+		{"user outside the namespace", []string{"mallory@example.com"}, true},
+		{"group outside the namespace", []string{"group:finance"}, true},
+		{"username used as a group", []string{"group:alice@example.com"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := Flow{Meta: Metadata{Owners: tt.owners}}
+			if err := f.ValidateOwners(users, groups); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateOwners() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFlow_WithOwnerNotify(t *testing.T) {
+	owners := []string{"alice@example.com", "group:ops"}
+	flowNotify := []Notify{
+		{Channel: "webhook", Config: map[string]any{"url": "https://example.com"}, Events: []NotifyEvent{NotifyEventOnSuccess}},
+	}
+
+	tests := []struct {
+		name string
+		flow Flow
+		want []Notify
+	}{
+		{
+			name: "owners are notified",
+			flow: Flow{Meta: Metadata{Owners: owners}},
+			want: []Notify{{Channel: "email", Config: map[string]any{"receivers": owners}, Events: OwnerNotifyEvents}},
+		},
+		{
+			name: "flow notifications take precedence",
+			flow: Flow{Meta: Metadata{Owners: owners}, Notify: flowNotify},
+			want: flowNotify,
+		},
+		{
+			name: "no owners",
+			flow: Flow{},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.flow.WithOwnerNotify().Notify; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("WithOwnerNotify().Notify = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlow_ValidateOwners_Approvers(t *testing.T) {
+	users := []string{"alice@example.com"}
+	groups := []string{"ops"}
+
+	f := Flow{Actions: []Action{{ID: "deploy", Approval: true, Approvers: []string{"alice@example.com", "group:ops"}}}}
+	if err := f.ValidateOwners(users, groups); err != nil {
+		t.Errorf("ValidateOwners() error = %v", err)
+	}
+
+	f.Actions[0].Approvers = []string{"group:finance"}
+	if err := f.ValidateOwners(users, groups); err == nil {
+		t.Error("ValidateOwners() expected an error for a group outside the namespace")
+	}
+}
+
+func TestAction_IsApprover(t *testing.T) {
+	action := Action{ID: "deploy", Approval: true, Approvers: []string{"alice@example.com", "group:ops"}}
+
+	tests := []struct {
+		name     string
+		action   Action
+		username string
+		groups   []string
+		want     bool
+	}{
+		{"listed user", action, "Alice@example.com", nil, true},
+		{"group member", action, "bob@example.com", []string{"dev", "ops"}, true},
+		{"not listed", action, "bob@example.com", []string{"dev"}, false},
+		{"no approvers", Action{ID: "deploy", Approval: true}, "bob@example.com", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.action.IsApprover(tt.username, tt.groups); got != tt.want {
+				t.Errorf("IsApprover() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
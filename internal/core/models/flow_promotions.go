@@ -0,0 +1,58 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// PromotionConflict is what happens when a flow is promoted to a namespace that already has
+// a flow with the same ID
+type PromotionConflict string
+
+const (
+	// PromotionConflictFail rejects the promotion
+	PromotionConflictFail PromotionConflict = "fail"
+	// PromotionConflictRename creates the flow under the first free ID with a numeric suffix
+	PromotionConflictRename PromotionConflict = "rename"
+	// PromotionConflictReplace updates the existing flow, through a review if the namespace requires one
+	PromotionConflictReplace PromotionConflict = "replace"
+)
+
+// FlowPromotionRequest describes where a flow is promoted to
+type FlowPromotionRequest struct {
+	TargetNamespaceID string
+	// TargetFlowID is the ID of the flow in the target namespace, the source ID if empty
+	TargetFlowID string
+	OnConflict   PromotionConflict
+	// CopySecretKeys creates the secrets of the source flow in the target flow, without values
+	CopySecretKeys bool
+}
+
+// FlowPromotion is a record of a flow copied from one namespace to another
+type FlowPromotion struct {
+	ID              int32
+	SourceNamespace string
+	SourceFlowID    string
+	SourceChecksum  string
+	TargetNamespace string
+	TargetFlowID    string
+	// RevisionID is set if the promotion replaced a flow in a namespace that requires flow reviews
+	RevisionID string
+	// SecretKeys are the secrets created without values in the target flow
+	SecretKeys []string
+	PromotedBy string
+	CreatedAt  time.Time
+}
+
+// PromotedFlowID returns id if it isn't taken, otherwise the first of id_2, id_3, ... that isn't
+func PromotedFlowID(id string, taken func(string) bool) string {
+	if !taken(id) {
+		return id
+	}
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s_%d", id, i)
+		if !taken(candidate) {
+			return candidate
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPromotedFlowID(t *testing.T) {
+	tests := []struct {
+		name  string
+		id    string
+		taken []string
+		want  string
+	}{
+		{name: "free id", id: "deploy", want: "deploy"},
+		{name: "taken id", id: "deploy", taken: []string{"deploy"}, want: "deploy_2"},
+		{name: "taken suffixes", id: "deploy", taken: []string{"deploy", "deploy_2", "deploy_3"}, want: "deploy_4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := PromotedFlowID(tt.id, func(id string) bool { return slices.Contains(tt.taken, id) })
+			if got != tt.want {
+				t.Errorf("PromotedFlowID() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+type FlowRevisionStatus string
+
+const (
+	FlowRevisionStatusPending    FlowRevisionStatus = "pending"
+	FlowRevisionStatusApproved   FlowRevisionStatus = "approved"
+	FlowRevisionStatusRejected   FlowRevisionStatus = "rejected"
+	FlowRevisionStatusSuperseded FlowRevisionStatus = "superseded"
+)
+
+// FlowRevision is a change to a flow that waits for a reviewer before it becomes the
+// version used by triggers and schedules
+type FlowRevision struct {
+	ID          string
+	FlowID      string
+	FlowName    string
+	Status      FlowRevisionStatus
+	Format      FlowFormat
+	Checksum    string
+	CommitSHA   string
+	RequestedBy string
+	ReviewedBy  string
+	Comment     string
+	CreatedAt   time.Time
+	ReviewedAt  *time.Time
+	// Content is the flow file of the revision, it is only set when a single revision is fetched
+	Content string
+}
@@ -0,0 +1,11 @@
+package models
+
+// FlowTemplate is a starter flow that ships with flowctl and can be created in any namespace
+type FlowTemplate struct {
+	ID          string
+	Name        string
+	Description string
+	Flow        Flow
+	// Content is the flow file of the template
+	Content string
+}
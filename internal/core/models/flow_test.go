@@ -0,0 +1,115 @@
+package models
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/cvhariharan/flowctl/internal/scheduler"
+	"github.com/google/uuid"
+)
+
+func TestInput_AcceptsFile(t *testing.T) {
+	tests := []struct {
+		name        string
+		accept      []string
+		filename    string
+		contentType string
+		want        bool
+	}{
+		{"no restriction", nil, "data.bin", "application/octet-stream", true},
+		{"extension", []string{".csv"}, "data.csv", "", true},
+		{"extension is case insensitive", []string{".CSV"}, "DATA.Csv", "", true},
+		{"other extension", []string{".csv"}, "data.json", "application/json", false},
+		{"media type", []string{"application/json"}, "data", "application/json", true},
+		{"media type with parameters", []string{"text/plain"}, "notes", "text/plain; charset=utf-8", true},
+		{"wildcard media type", []string{"image/*"}, "logo", "image/png", true},
+		{"wildcard without content type", []string{"image/*"}, "logo.png", "", false},
+		{"any of several", []string{".csv", "application/json"}, "data", "application/json", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := Input{Name: "file", Type: INPUT_TYPE_FILE, Accept: tt.accept}
+			if got := in.AcceptsFile(tt.filename, tt.contentType); got != tt.want {
+				t.Errorf("AcceptsFile(%q, %q) = %v, want %v", tt.filename, tt.contentType, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertToSchedulerFlow_LoginOverride(t *testing.T) {
+	getNodesByNames := func(ctx context.Context, names []string, ns uuid.UUID) ([]Node, error) {
+		var nodes []Node
+		for _, name := range names {
+			nodes = append(nodes, Node{
+				ID:       name,
+				Name:     name,
+				Username: "ubuntu",
+				Auth:     NodeAuth{CredentialID: "node-key", Method: AuthMethodPrivateKey, Key: "node"},
+			})
+		}
+		return nodes, nil
+	}
+	getNodesByTags := func(ctx context.Context, tags []string, ns uuid.UUID) ([]Node, error) {
+		return nil, nil
+	}
+	getCredential := func(ctx context.Context, name string, ns uuid.UUID) (Credential, error) {
+		return Credential{ID: "root-key", Name: name, KeyType: "password", KeyData: "secret"}, nil
+	}
+
+	f := Flow{
+		Meta: Metadata{ID: "deploy", Name: "deploy"},
+		Actions: []Action{
+			{ID: "build", Name: "build", Executor: "script", On: []string{"web1", "web2"}},
+			{ID: "restart", Name: "restart", Executor: "script", On: []string{"web1", "web2"}, Username: "root", Credential: "root-key"},
+		},
+	}
+
+	sf, err := ConvertToSchedulerFlow(context.Background(), f, uuid.New(), getNodesByNames, getNodesByTags, getCredential)
+	if err != nil {
+		t.Fatalf("ConvertToSchedulerFlow() error = %v", err)
+	}
+
+	for _, n := range sf.Actions[0].On {
+		if n.Username != "ubuntu" || n.Auth.CredentialID != "node-key" {
+			t.Errorf("build on %s: got %s with %s, want the login of the node", n.Name, n.Username, n.Auth.CredentialID)
+		}
+	}
+	for _, n := range sf.Actions[1].On {
+		want := scheduler.NodeAuth{CredentialID: "root-key", Method: scheduler.AuthMethodPassword, Key: "secret"}
+		if n.Username != "root" || n.Auth != want {
+			t.Errorf("restart on %s: got %s with %+v, want root with %+v", n.Name, n.Username, n.Auth, want)
+		}
+	}
+}
+
+func TestValidateNeeds(t *testing.T) {
+	tests := []struct {
+		name    string
+		actions []Action
+		wantErr string
+	}{
+		{"no needs", []Action{{ID: "build"}, {ID: "test"}}, ""},
+		{"graph", []Action{{ID: "build"}, {ID: "lint", Needs: []string{"build"}}, {ID: "test", Needs: []string{"build"}}, {ID: "deploy", Needs: []string{"lint", "test"}}}, ""},
+		{"needs a later action", []Action{{ID: "deploy", Needs: []string{"build"}}, {ID: "build"}}, ""},
+		{"needs itself", []Action{{ID: "build", Needs: []string{"build"}}}, "can't need itself"},
+		{"unknown action", []Action{{ID: "build"}, {ID: "test", Needs: []string{"compile"}}}, "needs unknown action compile"},
+		{"cycle", []Action{{ID: "build", Needs: []string{"deploy"}}, {ID: "test", Needs: []string{"build"}}, {ID: "deploy", Needs: []string{"test"}}}, "needs form a cycle"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateNeeds(tt.actions)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("validateNeeds() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateNeeds() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/expr-lang/expr"
+)
+
+// triggerExprPattern matches the {{ expression }} placeholders in the input mapping of a trigger
+var triggerExprPattern = regexp.MustCompile(`{{\s*([^}]+)\s*}}`)
+
+// FlowTrigger runs a flow when an execution of another flow in the same namespace finishes.
+// Events defaults to on_success. The values of Inputs are evaluated with {{ expression }}
+// placeholders that can use the inputs and outputs of the finished execution.
+type FlowTrigger struct {
+	Flow   string            `yaml:"flow" huml:"flow" json:"flow" validate:"required"`
+	Events []NotifyEvent     `yaml:"events" huml:"events" json:"events" validate:"omitempty,dive,oneof=on_success on_failure"`
+	Inputs map[string]string `yaml:"inputs" huml:"inputs" json:"inputs"`
+}
+
+// Fires reports whether the trigger runs its flow when an execution of flowID finishes with status
+func (t FlowTrigger) Fires(flowID string, status ExecutionStatus) bool {
+	if t.Flow != flowID {
+		return false
+	}
+
+	events := t.Events
+	if len(events) == 0 {
+		events = []NotifyEvent{NotifyEventOnSuccess}
+	}
+	switch status {
+	case ExecutionStatusCompleted:
+		return slices.Contains(events, NotifyEventOnSuccess)
+	case ExecutionStatusErrored:
+		return slices.Contains(events, NotifyEventOnFailure)
+	}
+	return false
+}
+
+// MapInputs returns the inputs of the triggered flow f from the inputs and outputs of the
+// finished execution
+func (t FlowTrigger) MapInputs(f Flow, inputs, outputs map[string]any) (map[string]any, error) {
+	if inputs == nil {
+		inputs = map[string]any{}
+	}
+	if outputs == nil {
+		outputs = map[string]any{}
+	}
+	return MapTriggerInputs(f, t.Inputs, map[string]any{
+		"inputs":  inputs,
+		"outputs": outputs,
+	})
+}
+
+// MapTriggerInputs returns the inputs of f from a mapping of input names to values with
+// {{ expression }} placeholders evaluated against env. A value that is a single placeholder
+// keeps the type of the expression, strings are converted for number and checkbox inputs.
+func MapTriggerInputs(f Flow, mapping map[string]string, env map[string]any) (map[string]any, error) {
+	mapped := make(map[string]any, len(mapping))
+	for _, in := range f.Inputs {
+		tmpl, ok := mapping[in.Name]
+		if !ok {
+			continue
+		}
+
+		v, err := evalTriggerValue(tmpl, env)
+		if err != nil {
+			return nil, fmt.Errorf("input %s: %w", in.Name, err)
+		}
+		mapped[in.Name] = convertTriggerValue(in, v)
+	}
+	return mapped, nil
+}
+
+// validate checks that the trigger maps to inputs of f and that its expressions compile
+func (t FlowTrigger) validate(f Flow) error {
+	if t.Flow == f.Meta.ID {
+		return fmt.Errorf("trigger: a flow can't be triggered by itself")
+	}
+	if err := ValidateTriggerInputs(f, t.Inputs, "inputs", "outputs"); err != nil {
+		return fmt.Errorf("trigger on %s: %w", t.Flow, err)
+	}
+	return nil
+}
+
+// ValidateTriggerInputs checks that a mapping only sets inputs of f and that its expressions
+// compile with the given variables
+func ValidateTriggerInputs(f Flow, mapping map[string]string, vars ...string) error {
+	env := make(map[string]any, len(vars))
+	for _, v := range vars {
+		env[v] = map[string]any{}
+	}
+
+	for name, tmpl := range mapping {
+		if !slices.ContainsFunc(f.Inputs, func(in Input) bool { return in.Name == name }) {
+			return fmt.Errorf("%s is not an input of flow %s", name, f.Meta.ID)
+		}
+		for _, m := range triggerExprPattern.FindAllStringSubmatch(tmpl, -1) {
+			if _, err := expr.Compile(strings.TrimSpace(m[1]), expr.Env(env)); err != nil {
+				return fmt.Errorf("input %s: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// evalTriggerValue evaluates the placeholders of a mapped input value
+func evalTriggerValue(tmpl string, env map[string]any) (any, error) {
+	if m := triggerExprPattern.FindStringSubmatch(tmpl); m != nil && m[0] == strings.TrimSpace(tmpl) {
+		return expr.Eval(strings.TrimSpace(m[1]), env)
+	}
+
+	var evalErr error
+	s := triggerExprPattern.ReplaceAllStringFunc(tmpl, func(match string) string {
+		if evalErr != nil {
+			return ""
+		}
+		out, err := expr.Eval(strings.TrimSpace(triggerExprPattern.FindStringSubmatch(match)[1]), env)
+		if err != nil {
+			evalErr = err
+			return ""
+		}
+		if out == nil {
+			return ""
+		}
+		return fmt.Sprint(out)
+	})
+	if evalErr != nil {
+		return nil, evalErr
+	}
+	return s, nil
+}
+
+// convertTriggerValue converts a mapped value to the type of the input. Outputs of actions are
+// always strings. Values that can't be converted are returned as is and rejected when the
+// inputs are validated.
+func convertTriggerValue(in Input, v any) any {
+	s, ok := v.(string)
+	switch in.Type {
+	case INPUT_TYPE_NUMBER:
+		if n, err := strconv.ParseFloat(strings.TrimSpace(s), 64); ok && err == nil {
+			return n
+		}
+	case INPUT_TYPE_CHECKBOX:
+		if b, err := strconv.ParseBool(strings.TrimSpace(s)); ok && err == nil {
+			return b
+		}
+	default:
+		if !ok && v != nil {
+			return fmt.Sprint(v)
+		}
+	}
+	return v
+}
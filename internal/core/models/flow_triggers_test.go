@@ -0,0 +1,167 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFlowTrigger_Fires(t *testing.T) {
+	tests := []struct {
+		name    string
+		trigger FlowTrigger
+		flowID  string
+		status  ExecutionStatus
+		want    bool
+	}{
+		{"success by default", FlowTrigger{Flow: "build"}, "build", ExecutionStatusCompleted, true},
+		{"not on failure by default", FlowTrigger{Flow: "build"}, "build", ExecutionStatusErrored, false},
+		{"on failure", FlowTrigger{Flow: "build", Events: []NotifyEvent{NotifyEventOnFailure}}, "build", ExecutionStatusErrored, true},
+		{"never on cancel", FlowTrigger{Flow: "build", Events: []NotifyEvent{NotifyEventOnSuccess, NotifyEventOnFailure}}, "build", ExecutionStatusCancelled, false},
+		{"other flow", FlowTrigger{Flow: "build"}, "deploy", ExecutionStatusCompleted, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.trigger.Fires(tt.flowID, tt.status); got != tt.want {
+				t.Errorf("Fires() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlowTrigger_MapInputs(t *testing.T) {
+	f := Flow{
+		Meta: Metadata{ID: "deploy"},
+		Inputs: []Input{
+			{Name: "version", Type: INPUT_TYPE_STRING},
+			{Name: "replicas", Type: INPUT_TYPE_NUMBER},
+			{Name: "canary", Type: INPUT_TYPE_CHECKBOX},
+			{Name: "message", Type: INPUT_TYPE_STRING},
+		},
+	}
+	trigger := FlowTrigger{
+		Flow: "build",
+		Inputs: map[string]string{
+			"version":  "{{ outputs.tag }}",
+			"replicas": "{{ outputs.replicas }}",
+			"canary":   "{{ inputs.env == 'prod' }}",
+			"message":  "built {{ outputs.tag }} on {{ outputs.node1.arch }}",
+		},
+	}
+	inputs := map[string]any{"env": "prod"}
+	outputs := map[string]any{
+		"tag":      "v1.2.0",
+		"replicas": "3",
+		"node1":    map[string]any{"arch": "arm64"},
+	}
+
+	got, err := trigger.MapInputs(f, inputs, outputs)
+	if err != nil {
+		t.Fatalf("MapInputs() error = %v", err)
+	}
+	want := map[string]any{
+		"version":  "v1.2.0",
+		"replicas": float64(3),
+		"canary":   true,
+		"message":  "built v1.2.0 on arm64",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MapInputs() = %v, want %v", got, want)
+	}
+	if verr := f.ValidateInput(got); verr != nil {
+		t.Errorf("mapped inputs are invalid: %v", verr)
+	}
+}
+
+func TestMapTriggerInputs_Message(t *testing.T) {
+	f := Flow{
+		Meta: Metadata{ID: "clean-disk"},
+		Inputs: []Input{
+			{Name: "host", Type: INPUT_TYPE_STRING},
+			{Name: "threshold", Type: INPUT_TYPE_NUMBER},
+			{Name: "source", Type: INPUT_TYPE_STRING},
+		},
+	}
+	mapping := map[string]string{
+		"host":      "{{ message.labels.instance }}",
+		"threshold": "{{ message.value }}",
+		"source":    "{{ metadata.origin }}",
+	}
+	if err := ValidateTriggerInputs(f, mapping, "message", "metadata"); err != nil {
+		t.Fatalf("ValidateTriggerInputs() error = %v", err)
+	}
+
+	got, err := MapTriggerInputs(f, mapping, map[string]any{
+		"message":  map[string]any{"labels": map[string]any{"instance": "web-1"}, "value": float64(92)},
+		"metadata": map[string]any{"origin": "alertmanager"},
+	})
+	if err != nil {
+		t.Fatalf("MapTriggerInputs() error = %v", err)
+	}
+	want := map[string]any{"host": "web-1", "threshold": float64(92), "source": "alertmanager"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MapTriggerInputs() = %v, want %v", got, want)
+	}
+}
+
+func TestFlowTrigger_Validate(t *testing.T) {
+	f := Flow{
+		Meta:   Metadata{ID: "deploy"},
+		Inputs: []Input{{Name: "version", Type: INPUT_TYPE_STRING}},
+	}
+
+	tests := []struct {
+		name    string
+		trigger FlowTrigger
+		wantErr bool
+	}{
+		{"valid", FlowTrigger{Flow: "build", Inputs: map[string]string{"version": "{{ outputs.tag }}"}}, false},
+		{"triggered by itself", FlowTrigger{Flow: "deploy"}, true},
+		{"unknown input", FlowTrigger{Flow: "build", Inputs: map[string]string{"tag": "{{ outputs.tag }}"}}, true},
+		{"invalid expression", FlowTrigger{Flow: "build", Inputs: map[string]string{"version": "{{ outputs. }}"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.trigger.validate(f); (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWebhookTrigger_MapInputs(t *testing.T) {
+	f := Flow{
+		Meta: Metadata{ID: "deploy"},
+		Inputs: []Input{
+			{Name: "branch", Type: INPUT_TYPE_STRING},
+			{Name: "event", Type: INPUT_TYPE_STRING},
+			{Name: "dry_run", Type: INPUT_TYPE_CHECKBOX},
+		},
+	}
+	w := WebhookTrigger{Inputs: map[string]string{
+		"branch":  "{{ payload.ref }}",
+		"event":   "{{ headers['x-github-event'] }}",
+		"dry_run": "{{ query.dry_run }}",
+	}}
+
+	got, err := w.MapInputs(f,
+		map[string]any{"ref": "refs/heads/main"},
+		map[string][]string{"X-Github-Event": {"push"}},
+		map[string][]string{"dry_run": {"true", "false"}},
+	)
+	if err != nil {
+		t.Fatalf("MapInputs() error = %v", err)
+	}
+	want := map[string]any{"branch": "refs/heads/main", "event": "push", "dry_run": true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MapInputs() = %v, want %v", got, want)
+	}
+
+	if err := (WebhookTrigger{Inputs: map[string]string{"version": "{{ payload.tag }}"}}).validate(f); err == nil {
+		t.Error("validate() accepted a mapping to an input that is not in the flow")
+	}
+	if err := (WebhookTrigger{SignatureHeader: "X-Signature: sha256"}).validate(f); err == nil {
+		t.Error("validate() accepted an invalid signature header")
+	}
+}
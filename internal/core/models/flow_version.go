@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// FlowVersion is a version of a flow recorded each time its flow file changed
+type FlowVersion struct {
+	ID        int32
+	Checksum  string
+	CommitSHA string
+	CreatedAt time.Time
+	// HasContent is false for versions recorded before flow contents were stored, which can't be diffed
+	HasContent bool
+}
+
+type FlowChangeType string
+
+const (
+	FlowChangeAdded   FlowChangeType = "added"
+	FlowChangeRemoved FlowChangeType = "removed"
+	FlowChangeChanged FlowChangeType = "changed"
+)
+
+// FieldChange is a field whose value differs between two versions of a flow
+type FieldChange struct {
+	Field string
+	Old   any
+	New   any
+}
+
+// ItemChange is an input or action that was added, removed or changed between two versions
+// of a flow. Fields is only set for changed items.
+type ItemChange struct {
+	ID     string
+	Change FlowChangeType
+	Fields []FieldChange
+}
+
+// FlowDiff is the difference between two versions of a flow, both as changes to the parts of
+// the flow and as a unified diff of the flow files
+type FlowDiff struct {
+	From     string
+	To       string
+	Metadata []FieldChange
+	Inputs   []ItemChange
+	Actions  []ItemChange
+	Text     string
+}
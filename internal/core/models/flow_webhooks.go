@@ -0,0 +1,78 @@
+package models
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/cvhariharan/flowctl/internal/repo"
+)
+
+// headerNamePattern matches the names of HTTP headers
+var headerNamePattern = regexp.MustCompile(`^[A-Za-z0-9-]+$`)
+
+// WebhookTrigger runs the flow for the requests to its webhook. The values of Inputs are evaluated
+// with {{ expression }} placeholders that can use the JSON decoded body of the request as payload,
+// and its headers and query parameters.
+type WebhookTrigger struct {
+	Inputs map[string]string `yaml:"inputs" huml:"inputs" json:"inputs"`
+	// SignatureHeader is the header with the hex encoded HMAC-SHA256 of the body of the request,
+	// signed with the secret of the webhook. Requests are only authenticated by the token in the
+	// URL of the webhook if empty.
+	SignatureHeader string `yaml:"signature_header,omitempty" huml:"signature_header" json:"signature_header,omitempty" validate:"omitempty,max=100"`
+}
+
+// validate checks that the webhook maps to inputs of f and that its expressions compile
+func (w WebhookTrigger) validate(f Flow) error {
+	if w.SignatureHeader != "" && !headerNamePattern.MatchString(w.SignatureHeader) {
+		return fmt.Errorf("webhook: invalid signature header %q", w.SignatureHeader)
+	}
+	if err := ValidateTriggerInputs(f, w.Inputs, "payload", "headers", "query"); err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	return nil
+}
+
+// MapInputs returns the inputs of f from the body, headers and query parameters of a request to
+// its webhook. Headers and query parameters with several values map to their first value, header
+// names are lower cased.
+func (w WebhookTrigger) MapInputs(f Flow, payload any, headers http.Header, query map[string][]string) (map[string]any, error) {
+	h := make(map[string]any, len(headers))
+	for k, v := range headers {
+		if len(v) > 0 {
+			h[strings.ToLower(k)] = v[0]
+		}
+	}
+	q := make(map[string]any, len(query))
+	for k, v := range query {
+		if len(v) > 0 {
+			q[k] = v[0]
+		}
+	}
+
+	return MapTriggerInputs(f, w.Inputs, map[string]any{
+		"payload": payload,
+		"headers": h,
+		"query":   q,
+	})
+}
+
+// FlowWebhook is the webhook of a flow. Its token and secret are only shown when it is created.
+type FlowWebhook struct {
+	FlowID     string
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+}
+
+func RepoFlowWebhookToFlowWebhook(flowID string, w repo.FlowWebhook) FlowWebhook {
+	hook := FlowWebhook{
+		FlowID:    flowID,
+		CreatedAt: w.CreatedAt,
+	}
+	if w.LastUsedAt.Valid {
+		hook.LastUsedAt = &w.LastUsedAt.Time
+	}
+	return hook
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// InputPreset is a named set of input values a flow can be triggered with. Presets are private to
+// the user that saved them unless they are shared with the namespace.
+type InputPreset struct {
+	ID            string
+	Name          string
+	Inputs        map[string]string
+	Shared        bool
+	CreatedByID   string
+	CreatedByName string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// ValidatePreset validates that the values of a preset are for inputs of the flow. Values of
+// password and file inputs can't be saved in a preset.
+func (f Flow) ValidatePreset(inputs map[string]string) error {
+	for name, value := range inputs {
+		input, ok := f.input(name)
+		if !ok {
+			return fmt.Errorf("unknown input %s", name)
+		}
+
+		switch input.Type {
+		case INPUT_TYPE_PASSWORD, INPUT_TYPE_FILE:
+			return fmt.Errorf("%s inputs can't be saved in a preset: %s", input.Type, name)
+		case INPUT_TYPE_CHECKBOX:
+			if value != "true" && value != "false" {
+				return fmt.Errorf("value of checkbox %s must be 'true' or 'false'", name)
+			}
+		case INPUT_TYPE_NUMBER:
+			if _, err := strconv.ParseFloat(value, 64); err != nil {
+				return fmt.Errorf("value of %s must be a valid number", name)
+			}
+		}
+	}
+	return nil
+}
+
+// PresetFormValues returns the values of a preset as trigger form values. Values of inputs that
+// were removed from the flow since the preset was saved are dropped and unchecked checkboxes
+// are left empty, like in a submitted form.
+func (f Flow) PresetFormValues(p InputPreset) map[string]string {
+	values := make(map[string]string, len(p.Inputs))
+	for name, value := range p.Inputs {
+		input, ok := f.input(name)
+		if !ok || input.Type == INPUT_TYPE_PASSWORD || input.Type == INPUT_TYPE_FILE {
+			continue
+		}
+		if input.Type == INPUT_TYPE_CHECKBOX && value != "true" {
+			value = ""
+		}
+		values[name] = value
+	}
+	return values
+}
+
+func (f Flow) input(name string) (Input, bool) {
+	for _, in := range f.Inputs {
+		if in.Name == name {
+			return in, true
+		}
+	}
+	return Input{}, false
+}
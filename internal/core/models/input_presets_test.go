@@ -0,0 +1,56 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func presetFlow() Flow {
+	return Flow{
+		Inputs: []Input{
+			{Name: "region", Type: INPUT_TYPE_STRING},
+			{Name: "replicas", Type: INPUT_TYPE_NUMBER},
+			{Name: "dry_run", Type: INPUT_TYPE_CHECKBOX},
+			{Name: "token", Type: INPUT_TYPE_PASSWORD},
+			{Name: "manifest", Type: INPUT_TYPE_FILE},
+		},
+	}
+}
+
+func TestFlow_ValidatePreset(t *testing.T) {
+	tests := []struct {
+		name    string
+		inputs  map[string]string
+		wantErr bool
+	}{
+		{name: "valid", inputs: map[string]string{"region": "eu-west-1", "replicas": "3", "dry_run": "true"}},
+		{name: "unknown input", inputs: map[string]string{"zone": "a"}, wantErr: true},
+		{name: "password input", inputs: map[string]string{"token": "secret"}, wantErr: true},
+		{name: "file input", inputs: map[string]string{"manifest": "a.yaml"}, wantErr: true},
+		{name: "invalid checkbox", inputs: map[string]string{"dry_run": "yes"}, wantErr: true},
+		{name: "invalid number", inputs: map[string]string{"replicas": "three"}, wantErr: true},
+	}
+
+	f := presetFlow()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := f.ValidatePreset(tt.inputs); (err != nil) != tt.wantErr {
+				t.Errorf("ValidatePreset() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFlow_PresetFormValues(t *testing.T) {
+	p := InputPreset{Inputs: map[string]string{
+		"region":  "eu-west-1",
+		"dry_run": "false",
+		"removed": "x",
+		"token":   "secret",
+	}}
+
+	want := map[string]string{"region": "eu-west-1", "dry_run": ""}
+	if got := presetFlow().PresetFormValues(p); !reflect.DeepEqual(got, want) {
+		t.Errorf("PresetFormValues() = %v, want %v", got, want)
+	}
+}
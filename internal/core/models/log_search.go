@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+const (
+	LogSearchDefaultLimit  = 100
+	LogSearchMaxLimit      = 1000
+	LogSearchMaxContext    = 10
+	LogSearchMaxExecutions = 50
+)
+
+// LogSearchQuery selects the executions to search and the pattern to look for.
+// If ExecID is set, only that execution is searched and the other filters are ignored.
+type LogSearchQuery struct {
+	ExecID     string
+	FlowID     string
+	From       time.Time
+	To         time.Time
+	Pattern    string
+	Regex      bool
+	IgnoreCase bool
+	// Context is the number of messages to include before and after each match
+	Context int
+	// Limit is the maximum number of matches to return
+	Limit int
+}
+
+// LogSearchMatch is a log message that matched the search pattern along with the
+// messages around it from the same execution
+type LogSearchMatch struct {
+	ExecID   string
+	FlowID   string
+	FlowName string
+	Message  StreamMessage
+	Before   []StreamMessage
+	After    []StreamMessage
+}
+
+type LogSearchResult struct {
+	Matches            []LogSearchMatch
+	SearchedExecutions int
+	// Truncated is set when the search stopped early because Limit was reached
+	Truncated bool
+}
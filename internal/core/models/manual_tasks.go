@@ -0,0 +1,114 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type ManualTaskStatus string
+
+const (
+	ManualTaskStatusPending   ManualTaskStatus = "pending"
+	ManualTaskStatusCompleted ManualTaskStatus = "completed"
+	ManualTaskStatusCancelled ManualTaskStatus = "cancelled"
+)
+
+// ManualTask is the with block of an action run by the manual executor. The execution waits
+// until a person follows the instructions, ticks every checklist item and fills in the inputs.
+type ManualTask struct {
+	// Instructions may reference {{ inputs.* }}, {{ outputs.* }} and {{ vars.* }}
+	Instructions string `json:"instructions" validate:"required"`
+	// Group is the name of the group whose members can complete the task. Anyone that can
+	// approve in the namespace can complete it when it is empty.
+	Group     string   `json:"group"`
+	Checklist []string `json:"checklist" validate:"dive,required"`
+	Inputs    []Input  `json:"inputs" validate:"dive"`
+}
+
+// ParseManualTask decodes the with block of a manual action
+func ParseManualTask(with map[string]any) (ManualTask, error) {
+	b, err := json.Marshal(with)
+	if err != nil {
+		return ManualTask{}, err
+	}
+	var t ManualTask
+	if err := json.Unmarshal(b, &t); err != nil {
+		return ManualTask{}, fmt.Errorf("invalid manual task: %w", err)
+	}
+	return t, nil
+}
+
+// RenderInstructions replaces the placeholders of the instructions with their values in env.
+// The instructions are returned as is if a placeholder can't be evaluated.
+func (t ManualTask) RenderInstructions(env map[string]any) string {
+	out, err := evalTriggerValue(t.Instructions, env)
+	if err != nil || out == nil {
+		return t.Instructions
+	}
+	return fmt.Sprint(out)
+}
+
+// ValidateResponse checks that every checklist item is ticked and that the inputs are valid
+func (t ManualTask) ValidateResponse(checklist []string, inputs map[string]any) *FlowValidationError {
+	for _, item := range t.Checklist {
+		if !slices.Contains(checklist, item) {
+			return &FlowValidationError{FieldName: "checklist", Msg: fmt.Sprintf("%q is not checked", item)}
+		}
+	}
+	return Flow{Inputs: t.Inputs}.ValidateInput(inputs)
+}
+
+// validateManualAction validates the with block of a manual action
+func validateManualAction(validate *validator.Validate, action Action) error {
+	if len(action.On) > 0 {
+		return fmt.Errorf("manual actions don't run on nodes")
+	}
+
+	t, err := ParseManualTask(action.With)
+	if err != nil {
+		return err
+	}
+	if err := validate.Struct(t); err != nil {
+		return err
+	}
+
+	names := make(map[string]bool)
+	for _, input := range t.Inputs {
+		if names[input.Name] {
+			return fmt.Errorf("input %s is defined more than once", input.Name)
+		}
+		names[input.Name] = true
+
+		// Responses are stored and returned as they were submitted
+		if input.Type == INPUT_TYPE_FILE || input.Type == INPUT_TYPE_PASSWORD || input.RemoteOptions != nil {
+			return fmt.Errorf("input %s: manual tasks only support string, number, datetime, checkbox and select inputs", input.Name)
+		}
+		if err := validateDefaultValue(input); err != nil {
+			return fmt.Errorf("validation error for input %s: %w", input.Name, err)
+		}
+	}
+	return nil
+}
+
+// ManualTaskDetails is a manual task with its rendered instructions and the response once it
+// is completed
+type ManualTaskDetails struct {
+	UUID         string
+	ExecID       string
+	ActionID     string
+	FlowID       string
+	FlowName     string
+	Status       ManualTaskStatus
+	Instructions string
+	Group        string
+	Checklist    []string
+	Inputs       []Input
+	Response     map[string]any
+	Comment      string
+	CompletedBy  string
+	CreatedAt    string
+	UpdatedAt    string
+}
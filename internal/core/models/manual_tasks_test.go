@@ -0,0 +1,133 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+)
+
+func TestValidateManualAction(t *testing.T) {
+	tests := []struct {
+		name    string
+		action  Action
+		wantErr bool
+	}{
+		{
+			name: "instructions only",
+			action: Action{ID: "verify", Executor: "manual", With: map[string]any{
+				"instructions": "Check the dashboard",
+			}},
+		},
+		{
+			name: "checklist and inputs",
+			action: Action{ID: "verify", Executor: "manual", With: map[string]any{
+				"instructions": "Check the dashboard",
+				"group":        "oncall",
+				"checklist":    []any{"Errors are down", "Latency is normal"},
+				"inputs": []any{
+					map[string]any{"name": "ticket", "type": "string", "required": true},
+					map[string]any{"name": "region", "type": "select", "options": []any{"eu", "us"}},
+				},
+			}},
+		},
+		{
+			name:    "missing instructions",
+			action:  Action{ID: "verify", Executor: "manual", With: map[string]any{}},
+			wantErr: true,
+		},
+		{
+			name: "runs on nodes",
+			action: Action{ID: "verify", Executor: "manual", On: []string{"web"}, With: map[string]any{
+				"instructions": "Check the dashboard",
+			}},
+			wantErr: true,
+		},
+		{
+			name: "file input",
+			action: Action{ID: "verify", Executor: "manual", With: map[string]any{
+				"instructions": "Upload the report",
+				"inputs":       []any{map[string]any{"name": "report", "type": "file"}},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "duplicate input",
+			action: Action{ID: "verify", Executor: "manual", With: map[string]any{
+				"instructions": "Check the dashboard",
+				"inputs": []any{
+					map[string]any{"name": "ticket", "type": "string"},
+					map[string]any{"name": "ticket", "type": "number"},
+				},
+			}},
+			wantErr: true,
+		},
+	}
+
+	validate := validator.New()
+	validate.RegisterValidation("alphanum_underscore", AlphanumericUnderscore)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateManualAction(validate, tt.action)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateManualAction() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestManualTask_ValidateResponse(t *testing.T) {
+	task := ManualTask{
+		Instructions: "Check the dashboard",
+		Checklist:    []string{"Errors are down", "Latency is normal"},
+		Inputs: []Input{
+			{Name: "ticket", Type: INPUT_TYPE_STRING, Required: true},
+			{Name: "replicas", Type: INPUT_TYPE_NUMBER},
+		},
+	}
+	checked := []string{"Latency is normal", "Errors are down"}
+
+	tests := []struct {
+		name      string
+		checklist []string
+		inputs    map[string]any
+		wantField string
+	}{
+		{"complete", checked, map[string]any{"ticket": "OPS-1", "replicas": float64(3)}, ""},
+		{"unchecked item", checked[:1], map[string]any{"ticket": "OPS-1"}, "checklist"},
+		{"missing required input", checked, map[string]any{}, "ticket"},
+		{"wrong input type", checked, map[string]any{"ticket": "OPS-1", "replicas": "three"}, "replicas"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := task.ValidateResponse(tt.checklist, tt.inputs)
+			if tt.wantField == "" {
+				if err != nil {
+					t.Errorf("ValidateResponse() error = %v", err)
+				}
+				return
+			}
+			if err == nil || err.FieldName != tt.wantField {
+				t.Errorf("ValidateResponse() error = %v, want error for %s", err, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestManualTask_RenderInstructions(t *testing.T) {
+	task := ManualTask{Instructions: "Deploy {{ inputs.version }} to {{ vars.region }}"}
+	env := map[string]any{
+		"inputs": map[string]any{"version": "1.2.0"},
+		"vars":   map[string]string{"region": "eu"},
+	}
+
+	if got := task.RenderInstructions(env); got != "Deploy 1.2.0 to eu" {
+		t.Errorf("RenderInstructions() = %q", got)
+	}
+
+	task.Instructions = "Deploy {{ inputs.version + }}"
+	if got := task.RenderInstructions(env); got != task.Instructions {
+		t.Errorf("RenderInstructions() = %q, want the instructions as is", got)
+	}
+}
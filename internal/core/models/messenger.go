@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+const (
+	MessengerSourceFile    = "file"
+	MessengerSourceRuntime = "runtime"
+)
+
+// MessengerConfig describes a notification channel and where its settings come from.
+// Config holds the runtime settings with secret values redacted and is empty for
+// channels configured in the config file.
+type MessengerConfig struct {
+	Channel   string
+	Enabled   bool
+	Source    string
+	Config    map[string]any
+	UpdatedAt time.Time
+}
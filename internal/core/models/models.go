@@ -24,6 +24,7 @@ const (
 	ResultMessageType    MessageType = "result"
 	ApprovalMessageType  MessageType = "approval"
 	CancelledMessageType MessageType = "cancelled"
+	ProgressMessageType  MessageType = "progress"
 )
 
 type StreamMessage struct {
@@ -153,6 +154,154 @@ type ExecutionSummary struct {
 	CompletedAt     time.Time
 	ScheduledAt     time.Time
 	ActionRetries   map[string]int
+	// Progress is nil if the execution hasn't started running an action yet
+	Progress *ExecutionProgress
+	// SkippedActions are the failed actions that were skipped when retrying the execution
+	SkippedActions []ActionSkip
+	// Environment is the runtime details of the nodes the actions ran on
+	Environment []NodeEnvironment
+	// Estimate is nil unless the execution is running and the flow completed before
+	Estimate *ExecutionEstimate
+	// Parent is nil unless the execution was started by a flow action of another execution
+	Parent *SubflowParent
+	// Subflows are the executions started by the flow actions of the execution
+	Subflows []SubflowExecution
+}
+
+// SubflowParent is the execution and flow action that started an execution
+type SubflowParent struct {
+	ExecID   string
+	ActionID string
+}
+
+// SubflowExecution is an execution started by a flow action
+type SubflowExecution struct {
+	ActionID  string
+	ExecID    string
+	FlowID    string
+	FlowName  string
+	Status    ExecutionStatus
+	CreatedAt time.Time
+}
+
+// ExecutionEstimate is how long a running execution is expected to take, from the usual
+// durations of its actions in the last completed executions of the flow
+type ExecutionEstimate struct {
+	// Duration is the expected duration of the whole execution
+	Duration time.Duration
+	// Remaining is the expected time until the execution finishes
+	Remaining   time.Duration
+	CompletesAt time.Time
+	// Samples is the number of completed executions the estimate is based on
+	Samples int
+	// Overdue is set when the current action has run for more than twice its usual duration
+	Overdue bool
+}
+
+// ActionSkip is a failed action that a user skipped when retrying an execution
+type ActionSkip struct {
+	ActionID  string
+	SkippedBy string
+	SkippedAt time.Time
+}
+
+// NodeEnvironment is the runtime details of a node an action of an execution ran on, recorded
+// for reproducing the execution. Node is empty for actions that ran on the flowctl server.
+type NodeEnvironment struct {
+	ActionID        string
+	Node            string
+	Hostname        string
+	OS              string
+	Executor        string
+	ExecutorVersion string
+	Image           string
+	ImageDigest     string
+	// FlowChecksum is the checksum of the flow when the action ran
+	FlowChecksum string
+	RecordedAt   time.Time
+}
+
+// ExecutionProgress is the progress of an execution as of its last progress event
+type ExecutionProgress struct {
+	Event          string `json:"event"`
+	ActionIndex    int    `json:"action_index"`
+	TotalActions   int    `json:"total_actions"`
+	CompletedNodes int    `json:"completed_nodes"`
+	TotalNodes     int    `json:"total_nodes"`
+	Node           string `json:"node,omitempty"`
+	Status         string `json:"status,omitempty"`
+}
+
+// ExecutionTimeline has the start and end of every action attempt of an execution, in the order
+// they started, along with the nodes each attempt ran on
+type ExecutionTimeline struct {
+	ExecID  string
+	Actions []ActionTimeline
+	// SlowestActionID is the action whose attempt took the longest
+	SlowestActionID string
+}
+
+type ActionTimeline struct {
+	ActionID   string
+	Retry      int
+	Status     string
+	Error      string
+	StartedAt  time.Time
+	FinishedAt time.Time
+	// Duration is the time taken so far if the action is still running
+	Duration time.Duration
+	Nodes    []NodeTimeline
+}
+
+type NodeTimeline struct {
+	Node       string
+	Status     string
+	Error      string
+	StartedAt  time.Time
+	FinishedAt time.Time
+	Duration   time.Duration
+}
+
+// ExecutionOutputs are the results of the actions of an execution that finished, in the order
+// they finished. Values are masked like they are in the logs.
+type ExecutionOutputs struct {
+	ExecID  string
+	Actions []ActionOutputs
+	// Outputs has the results of every action the way later actions see them, with the results of
+	// a node grouped under the node name
+	Outputs map[string]any
+}
+
+type ActionOutputs struct {
+	ActionID   string
+	Outputs    map[string]string
+	FinishedAt time.Time
+}
+
+// Artifact is a file an action of an execution published from the artifact directory
+type Artifact struct {
+	ID       string
+	ExecID   string
+	ActionID string
+	// Name is the path of the file in the artifact directory
+	Name      string
+	Size      int64
+	CreatedAt time.Time
+}
+
+// NodeTelemetry is the resource usage of a node sampled while it ran an action
+type NodeTelemetry struct {
+	ActionID string
+	Node     string
+	Samples  []TelemetrySample
+}
+
+type TelemetrySample struct {
+	Time          time.Time
+	CPUPercent    float64
+	MemoryPercent float64
+	DiskPercent   float64
+	Load1         float64
 }
 
 type ScheduledExecution struct {
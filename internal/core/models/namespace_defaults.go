@@ -0,0 +1,97 @@
+package models
+
+import (
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// NamespaceDefaults are settings inherited by the flows of a namespace. A flow that sets one
+// of them itself keeps its own value.
+type NamespaceDefaults struct {
+	// Timezone is used by schedules and SLA deadlines that don't set a timezone
+	Timezone string
+	// Notify is used by flows without notifications
+	Notify []Notify
+	// ExecutorOptions are the default options of actions, keyed by executor name.
+	// Options set in the action's with block take precedence.
+	ExecutorOptions map[string]map[string]any
+	// MaxConcurrency is the number of executions of the namespace that run at the same time,
+	// the others stay queued. 0 doesn't limit them.
+	MaxConcurrency int
+	UpdatedAt      time.Time
+}
+
+// Enabled reports whether any default is set
+func (d NamespaceDefaults) Enabled() bool {
+	return d.Timezone != "" || len(d.Notify) > 0 || len(d.ExecutorOptions) > 0 || d.MaxConcurrency > 0
+}
+
+func (d NamespaceDefaults) Validate() error {
+	if d.Timezone != "" {
+		if _, err := time.LoadLocation(d.Timezone); err != nil {
+			return fmt.Errorf("invalid timezone %q", d.Timezone)
+		}
+	}
+
+	if d.MaxConcurrency < 0 {
+		return fmt.Errorf("max concurrency can't be negative")
+	}
+
+	validate := validator.New()
+	for _, n := range d.Notify {
+		if err := validate.Struct(n); err != nil {
+			return fmt.Errorf("invalid notification for %s: %w", n.Channel, err)
+		}
+	}
+
+	for name := range d.ExecutorOptions {
+		if name == "" {
+			return fmt.Errorf("executor options require an executor name")
+		}
+	}
+	return nil
+}
+
+// Apply returns a copy of the flow with the defaults filled in where the flow doesn't set them
+func (d NamespaceDefaults) Apply(f Flow) Flow {
+	if d.Timezone != "" {
+		if len(f.Schedules) > 0 {
+			schedules := make([]Schedule, len(f.Schedules))
+			for i, s := range f.Schedules {
+				if s.Timezone == "" {
+					s.Timezone = d.Timezone
+				}
+				schedules[i] = s
+			}
+			f.Schedules = schedules
+		}
+		if f.Meta.SLA != nil && f.Meta.SLA.Timezone == "" {
+			sla := *f.Meta.SLA
+			sla.Timezone = d.Timezone
+			f.Meta.SLA = &sla
+		}
+	}
+
+	if len(f.Notify) == 0 && len(d.Notify) > 0 {
+		f.Notify = d.Notify
+	}
+
+	if len(d.ExecutorOptions) > 0 {
+		actions := make([]Action, len(f.Actions))
+		for i, a := range f.Actions {
+			if opts, ok := d.ExecutorOptions[a.Executor]; ok {
+				with := make(map[string]any, len(opts)+len(a.With))
+				maps.Copy(with, opts)
+				maps.Copy(with, a.With)
+				a.With = with
+			}
+			actions[i] = a
+		}
+		f.Actions = actions
+	}
+
+	return f
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNamespaceDefaults_Apply(t *testing.T) {
+	defaults := NamespaceDefaults{
+		Timezone: "Asia/Kolkata",
+		Notify: []Notify{
+			{Channel: "email", Config: map[string]any{"receivers": []any{"ops@example.com"}}, Events: []NotifyEvent{NotifyEventOnFailure}},
+		},
+		ExecutorOptions: map[string]map[string]any{
+			"docker": {"image": "alpine:3", "network": "host"},
+		},
+	}
+	flowNotify := []Notify{
+		{Channel: "webhook", Config: map[string]any{"url": "https://example.com"}, Events: []NotifyEvent{NotifyEventOnSuccess}},
+	}
+
+	tests := []struct {
+		name string
+		flow Flow
+		want Flow
+	}{
+		{
+			name: "inherits every default",
+			flow: Flow{
+				Meta:      Metadata{SLA: &SLA{Deadline: "09:00"}},
+				Schedules: []Schedule{{Cron: "0 * * * *"}},
+				Actions:   []Action{{ID: "run", Executor: "docker", With: map[string]any{"script": "ls"}}},
+			},
+			want: Flow{
+				Meta:      Metadata{SLA: &SLA{Deadline: "09:00", Timezone: "Asia/Kolkata"}},
+				Schedules: []Schedule{{Cron: "0 * * * *", Timezone: "Asia/Kolkata"}},
+				Actions:   []Action{{ID: "run", Executor: "docker", With: map[string]any{"script": "ls", "image": "alpine:3", "network": "host"}}},
+				Notify:    defaults.Notify,
+			},
+		},
+		{
+			name: "flow settings take precedence",
+			flow: Flow{
+				Meta:      Metadata{SLA: &SLA{Deadline: "09:00", Timezone: "UTC"}},
+				Schedules: []Schedule{{Cron: "0 * * * *", Timezone: "Europe/Berlin"}},
+				Actions:   []Action{{ID: "run", Executor: "docker", With: map[string]any{"image": "ubuntu:24.04"}}},
+				Notify:    flowNotify,
+			},
+			want: Flow{
+				Meta:      Metadata{SLA: &SLA{Deadline: "09:00", Timezone: "UTC"}},
+				Schedules: []Schedule{{Cron: "0 * * * *", Timezone: "Europe/Berlin"}},
+				Actions:   []Action{{ID: "run", Executor: "docker", With: map[string]any{"image": "ubuntu:24.04", "network": "host"}}},
+				Notify:    flowNotify,
+			},
+		},
+		{
+			name: "other executors are unchanged",
+			flow: Flow{
+				Actions: []Action{{ID: "run", Executor: "script", With: map[string]any{"script": "ls"}}},
+			},
+			want: Flow{
+				Actions: []Action{{ID: "run", Executor: "script", With: map[string]any{"script": "ls"}}},
+				Notify:  defaults.Notify,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := tt.flow.Actions[0].With["image"]
+			if got := defaults.Apply(tt.flow); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Apply() = %+v, want %+v", got, tt.want)
+			}
+			if tt.flow.Actions[0].With["image"] != original {
+				t.Errorf("Apply() changed the options of the original flow")
+			}
+		})
+	}
+}
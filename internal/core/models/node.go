@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 type AuthMethod string
 
 const (
@@ -26,6 +28,14 @@ type NodeAuth struct {
 	Key          string
 }
 
+// NodeTestResult is the result of connecting to a node and running a command on it
+type NodeTestResult struct {
+	Success  bool
+	Output   string
+	Error    string
+	Duration time.Duration
+}
+
 type NodeStats struct {
 	TotalHosts int64 `json:"total_hosts"`
 	SSHHosts   int64 `json:"ssh_hosts"`
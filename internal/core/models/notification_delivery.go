@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// NotificationDelivery is the delivery status of a notification for a single receiver
+type NotificationDelivery struct {
+	ID        string
+	ExecID    string
+	FlowID    string
+	Channel   string
+	Receiver  string
+	Event     string
+	Status    string
+	Attempts  int
+	LastError string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// NamespaceQuota limits what a namespace can use on a shared instance. A zero value disables the limit.
+type NamespaceQuota struct {
+	// MaxExecutionsPerDay limits the executions started per UTC day
+	MaxExecutionsPerDay int
+	// MaxConcurrentExecutions limits the executions that are running or waiting to run
+	MaxConcurrentExecutions int
+	MaxNodes                int
+	// MaxLogBytes limits the size of the logs kept for the executions of the namespace
+	MaxLogBytes int64
+	UpdatedAt   time.Time
+}
+
+// Enabled reports whether the quota limits the namespace
+func (q NamespaceQuota) Enabled() bool {
+	return q.MaxExecutionsPerDay > 0 || q.MaxConcurrentExecutions > 0 || q.MaxNodes > 0 || q.MaxLogBytes > 0
+}
+
+// NamespaceUsage is what a namespace currently uses of its quota
+type NamespaceUsage struct {
+	Quota                NamespaceQuota
+	ExecutionsToday      int
+	ConcurrentExecutions int
+	Nodes                int
+	LogBytes             int64
+}
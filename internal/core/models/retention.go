@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// RetentionPreviewMaxSamples is the number of execution IDs listed as examples in a retention preview
+const RetentionPreviewMaxSamples = 10
+
+// RetentionPolicy limits how long the finished executions of a namespace are kept.
+// Executions older than MaxAgeDays, or beyond the KeepLast most recent ones, are purged
+// along with their logs. A zero value disables the limit.
+type RetentionPolicy struct {
+	MaxAgeDays int
+	KeepLast   int
+	UpdatedAt  time.Time
+}
+
+// Enabled reports whether the policy limits the executions of the namespace
+func (p RetentionPolicy) Enabled() bool {
+	return p.MaxAgeDays > 0 || p.KeepLast > 0
+}
+
+// RetentionPreview describes the executions that the retention policy would purge on its next run
+type RetentionPreview struct {
+	Policy RetentionPolicy
+	Total  int64
+	// Archived is the number of purged executions that were already archived
+	Archived int64
+	ByStatus map[ExecutionStatus]int64
+	// Oldest and Newest are the creation times of the oldest and newest executions that would be purged
+	Oldest        time.Time
+	Newest        time.Time
+	SampleExecIDs []string
+}
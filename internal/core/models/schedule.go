@@ -16,3 +16,38 @@ type Schedule struct {
 	CreatedAt     time.Time              `json:"created_at" yaml:"-" huml:"-"`
 	UpdatedAt     time.Time              `json:"updated_at" yaml:"-" huml:"-"`
 }
+
+// ScheduleLag is when a cron schedule last fired and how late its executions started.
+// The last run fields are empty if the schedule hasn't run yet.
+type ScheduleLag struct {
+	ScheduleUUID  string
+	FlowSlug      string
+	FlowName      string
+	Cron          string
+	Timezone      string
+	IsUserCreated bool
+	IsActive      bool
+	LastExecID    string
+	LastFiredAt   time.Time
+	LastStartedAt time.Time
+	// LastDrift is the delay of the last run and MaxDrift the largest delay seen
+	LastDrift time.Duration
+	MaxDrift  time.Duration
+	Runs      int64
+}
+
+const (
+	ScheduleCalendarDefaultWindow = 7 * 24 * time.Hour
+	ScheduleCalendarMaxWindow     = 90 * 24 * time.Hour
+)
+
+// ScheduleOccurrence is a time a cron schedule will fire at, in the timezone of the schedule
+type ScheduleOccurrence struct {
+	ScheduleUUID  string
+	FlowSlug      string
+	FlowName      string
+	Cron          string
+	Timezone      string
+	IsUserCreated bool
+	At            time.Time
+}
@@ -0,0 +1,62 @@
+package models
+
+import "testing"
+
+func TestValidateSubflowAction(t *testing.T) {
+	f := Flow{Meta: Metadata{ID: "release"}}
+
+	tests := []struct {
+		name    string
+		action  Action
+		wantErr bool
+	}{
+		{
+			name: "flow and inputs",
+			action: Action{ID: "deploy", Executor: "flow", With: map[string]any{
+				"flow":   "deploy_service",
+				"inputs": map[string]any{"version": "{{ outputs.BUILD_ID }}", "replicas": 3},
+			}},
+		},
+		{
+			name:   "flow without inputs",
+			action: Action{ID: "deploy", Executor: "flow", With: map[string]any{"flow": "deploy_service"}},
+		},
+		{
+			name:    "missing flow",
+			action:  Action{ID: "deploy", Executor: "flow", With: map[string]any{}},
+			wantErr: true,
+		},
+		{
+			name:    "runs itself",
+			action:  Action{ID: "deploy", Executor: "flow", With: map[string]any{"flow": "release"}},
+			wantErr: true,
+		},
+		{
+			name:    "runs on nodes",
+			action:  Action{ID: "deploy", Executor: "flow", On: []string{"web"}, With: map[string]any{"flow": "deploy_service"}},
+			wantErr: true,
+		},
+		{
+			name:    "inputs not a map",
+			action:  Action{ID: "deploy", Executor: "flow", With: map[string]any{"flow": "deploy_service", "inputs": []any{"v1"}}},
+			wantErr: true,
+		},
+		{
+			name: "invalid expression",
+			action: Action{ID: "deploy", Executor: "flow", With: map[string]any{
+				"flow":   "deploy_service",
+				"inputs": map[string]any{"version": "{{ outputs. }}"},
+			}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSubflowAction(f, tt.action)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateSubflowAction() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
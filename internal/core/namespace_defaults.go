@@ -0,0 +1,128 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/cvhariharan/flowctl/internal/repo"
+	"github.com/google/uuid"
+)
+
+// GetNamespaceDefaults returns the defaults inherited by the flows of a namespace.
+// Namespaces without defaults get empty ones.
+func (c *Core) GetNamespaceDefaults(ctx context.Context, namespaceID string) (models.NamespaceDefaults, error) {
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return models.NamespaceDefaults{}, fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	return c.getNamespaceDefaults(ctx, namespaceUUID)
+}
+
+func (c *Core) getNamespaceDefaults(ctx context.Context, namespaceUUID uuid.UUID) (models.NamespaceDefaults, error) {
+	d, err := c.store.GetNamespaceDefaults(ctx, namespaceUUID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.NamespaceDefaults{}, nil
+		}
+		return models.NamespaceDefaults{}, fmt.Errorf("could not get namespace defaults: %w", err)
+	}
+
+	return repoNamespaceDefaultsToModel(d)
+}
+
+// SetNamespaceDefaults creates or replaces the defaults of a namespace.
+// Defaults without any setting remove the existing ones.
+func (c *Core) SetNamespaceDefaults(ctx context.Context, namespaceID string, defaults models.NamespaceDefaults) (models.NamespaceDefaults, error) {
+	if err := defaults.Validate(); err != nil {
+		return models.NamespaceDefaults{}, err
+	}
+
+	if !defaults.Enabled() {
+		return models.NamespaceDefaults{}, c.DeleteNamespaceDefaults(ctx, namespaceID)
+	}
+
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return models.NamespaceDefaults{}, fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	notify := defaults.Notify
+	if notify == nil {
+		notify = []models.Notify{}
+	}
+	notifyJSON, err := json.Marshal(notify)
+	if err != nil {
+		return models.NamespaceDefaults{}, fmt.Errorf("could not encode notifications: %w", err)
+	}
+
+	executorOptions := defaults.ExecutorOptions
+	if executorOptions == nil {
+		executorOptions = map[string]map[string]any{}
+	}
+	optionsJSON, err := json.Marshal(executorOptions)
+	if err != nil {
+		return models.NamespaceDefaults{}, fmt.Errorf("could not encode executor options: %w", err)
+	}
+
+	d, err := c.store.UpsertNamespaceDefaults(ctx, repo.UpsertNamespaceDefaultsParams{
+		Uuid:            namespaceUUID,
+		Timezone:        sql.NullString{String: defaults.Timezone, Valid: defaults.Timezone != ""},
+		Notify:          notifyJSON,
+		ExecutorOptions: optionsJSON,
+		MaxConcurrency:  int32(defaults.MaxConcurrency),
+	})
+	if err != nil {
+		return models.NamespaceDefaults{}, fmt.Errorf("could not save namespace defaults: %w", err)
+	}
+
+	return repoNamespaceDefaultsToModel(d)
+}
+
+// DeleteNamespaceDefaults removes the defaults of a namespace, its flows only use their own settings
+func (c *Core) DeleteNamespaceDefaults(ctx context.Context, namespaceID string) error {
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	if err := c.store.DeleteNamespaceDefaults(ctx, namespaceUUID); err != nil {
+		return fmt.Errorf("could not delete namespace defaults: %w", err)
+	}
+	return nil
+}
+
+// withNamespaceDefaults returns the flow with the defaults of its namespace applied.
+// Defaults are applied when a flow runs rather than when it is loaded, so that they are
+// never written back to the flow file and changes take effect without reloading flows.
+// The owners of a flow are notified in preference to the namespace notifications.
+func (c *Core) withNamespaceDefaults(ctx context.Context, f models.Flow, namespaceUUID uuid.UUID) (models.Flow, error) {
+	defaults, err := c.getNamespaceDefaults(ctx, namespaceUUID)
+	if err != nil {
+		return models.Flow{}, err
+	}
+	return defaults.Apply(c.withOwnerNotify(f)), nil
+}
+
+func repoNamespaceDefaultsToModel(d repo.NamespaceDefault) (models.NamespaceDefaults, error) {
+	defaults := models.NamespaceDefaults{
+		Timezone:       d.Timezone.String,
+		MaxConcurrency: int(d.MaxConcurrency),
+		UpdatedAt:      d.UpdatedAt,
+	}
+	if len(d.Notify) > 0 {
+		if err := json.Unmarshal(d.Notify, &defaults.Notify); err != nil {
+			return models.NamespaceDefaults{}, fmt.Errorf("could not decode namespace notifications: %w", err)
+		}
+	}
+	if len(d.ExecutorOptions) > 0 {
+		if err := json.Unmarshal(d.ExecutorOptions, &defaults.ExecutorOptions); err != nil {
+			return models.NamespaceDefaults{}, fmt.Errorf("could not decode namespace executor options: %w", err)
+		}
+	}
+	return defaults, nil
+}
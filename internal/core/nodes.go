@@ -1,14 +1,20 @@
 package core
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
+	"encoding/hex"
 	"errors"
 	"fmt"
-	"encoding/hex"
+	"log"
+	"strings"
+	"time"
 
 	"github.com/cvhariharan/flowctl/internal/core/models"
 	"github.com/cvhariharan/flowctl/internal/repo"
+	"github.com/cvhariharan/flowctl/sdk/executor"
+	"github.com/cvhariharan/flowctl/sdk/remoteclient"
 	"github.com/google/uuid"
 )
 
@@ -24,12 +30,19 @@ func (c *Core) CreateNode(ctx context.Context, node *models.Node, namespaceID st
 	if node.Hostname == "" {
 		return models.Node{}, errors.New("hostname is required")
 	}
+	if node.OSFamily == "" {
+		node.OSFamily = "linux"
+	}
 
 	credID, err := uuid.Parse(node.Auth.CredentialID)
 	if err != nil {
 		return models.Node{}, errors.New("invalid credential ID format")
 	}
 
+	if err := c.checkNodeQuota(ctx, namespaceUUID); err != nil {
+		return models.Node{}, err
+	}
+
 	credential, err := c.store.GetCredentialByUUID(ctx, repo.GetCredentialByUUIDParams{
 		Uuid:   credID,
 		Uuid_2: namespaceUUID,
@@ -164,6 +177,9 @@ func (c *Core) UpdateNode(ctx context.Context, id string, node *models.Node, nam
 	if node.Hostname == "" {
 		return models.Node{}, errors.New("hostname is required")
 	}
+	if node.OSFamily == "" {
+		node.OSFamily = "linux"
+	}
 
 	uuidID, err := uuid.Parse(id)
 	if err != nil {
@@ -356,3 +372,235 @@ func (c *Core) GetNodesByTags(ctx context.Context, tags []string, namespaceUUID
 
 	return nodes, nil
 }
+
+// nodeTestCommand is run on a node to check that commands can be executed after connecting.
+// Windows nodes run the command with the shell of their SSH server, cmd by default.
+const (
+	nodeTestCommand        = "uname -a"
+	nodeTestCommandWindows = "ver"
+)
+
+// TestNode connects to a node with its credential and runs a command on it.
+// Connection and command failures are reported in the result, an error is only returned
+// if the node can't be loaded.
+func (c *Core) TestNode(ctx context.Context, id string, namespaceID string) (models.NodeTestResult, error) {
+	node, err := c.GetNodeByID(ctx, id, namespaceID)
+	if err != nil {
+		return models.NodeTestResult{}, err
+	}
+
+	dKey, err := hex.DecodeString(node.Auth.Key)
+	if err != nil {
+		return models.NodeTestResult{}, fmt.Errorf("could not decode key for node %s: %w", node.Name, err)
+	}
+
+	decryptedKey, err := c.keeper.Decrypt(ctx, dKey)
+	if err != nil {
+		return models.NodeTestResult{}, fmt.Errorf("could not decrypt key for node %s: %w", node.Name, err)
+	}
+
+	start := time.Now()
+	result := models.NodeTestResult{}
+
+	type dialResult struct {
+		client remoteclient.RemoteClient
+		err    error
+	}
+	dialCh := make(chan dialResult, 1)
+	go func() {
+		client, err := remoteclient.GetClient(node.ConnectionType, remoteclient.NodeConfig{
+			Hostname: node.Hostname,
+			Port:     node.Port,
+			Username: node.Username,
+			Auth: remoteclient.NodeAuth{
+				Method: string(node.Auth.Method),
+				Key:    string(decryptedKey),
+			},
+		})
+		dialCh <- dialResult{client: client, err: err}
+	}()
+
+	var client remoteclient.RemoteClient
+	select {
+	case <-ctx.Done():
+		// Close the client if the connection succeeds after the test was abandoned
+		go func() {
+			if r := <-dialCh; r.err == nil {
+				r.client.Close()
+			}
+		}()
+		result.Error = fmt.Sprintf("could not connect: %v", ctx.Err())
+		result.Duration = time.Since(start)
+		return result, nil
+	case r := <-dialCh:
+		if r.err != nil {
+			result.Error = fmt.Sprintf("could not connect: %v", r.err)
+			result.Duration = time.Since(start)
+			return result, nil
+		}
+		client = r.client
+	}
+	defer client.Close()
+
+	cmd := nodeTestCommand
+	if node.OSFamily == executor.OSFamilyWindows {
+		cmd = nodeTestCommandWindows
+	}
+
+	var stdout, stderr bytes.Buffer
+	if err := client.RunCommand(ctx, cmd, &stdout, &stderr); err != nil {
+		result.Error = fmt.Sprintf("connected but could not run a command: %v %s", err, strings.TrimSpace(stderr.String()))
+		result.Duration = time.Since(start)
+		return result, nil
+	}
+
+	result.Success = true
+	result.Output = strings.TrimSpace(stdout.String())
+	result.Duration = time.Since(start)
+	return result, nil
+}
+
+// nodeIndex holds the nodes of many namespaces loaded with a single query. Its lookups return
+// the same nodes as GetNodesByNames and GetNodesByTags, so that it can be used to resolve the
+// nodes of many flows without a query per action.
+type nodeIndex struct {
+	nodes map[uuid.UUID][]models.Node
+	// broken holds the nodes whose key could not be read. Like GetNodesByNames and GetNodesByTags,
+	// lookups that match one of them fail, so only the flows that run on them can't be resolved.
+	broken map[uuid.UUID][]brokenNode
+}
+
+type brokenNode struct {
+	name string
+	tags []string
+	err  error
+}
+
+// loadNodeIndex loads the nodes of the given namespaces that have one of the names or tags
+func (c *Core) loadNodeIndex(ctx context.Context, namespaceUUIDs []uuid.UUID, names []string, tags []string) (nodeIndex, error) {
+	idx := nodeIndex{
+		nodes:  make(map[uuid.UUID][]models.Node),
+		broken: make(map[uuid.UUID][]brokenNode),
+	}
+	if len(namespaceUUIDs) == 0 || (len(names) == 0 && len(tags) == 0) {
+		return idx, nil
+	}
+
+	rows, err := c.store.GetNodesByNamesOrTagsInNamespaces(ctx, repo.GetNodesByNamesOrTagsInNamespacesParams{
+		NamespaceUuids: namespaceUUIDs,
+		Names:          names,
+		Tags:           tags,
+	})
+	if err != nil {
+		return idx, fmt.Errorf("could not get nodes: %w", err)
+	}
+
+	for _, v := range rows {
+		key, err := c.decryptNodeKey(ctx, v.Name, v.CredentialKeyData.String)
+		if err != nil {
+			log.Printf("skipping node %s in namespace %s: %v", v.Name, v.NamespaceUuid, err)
+			idx.broken[v.NamespaceUuid] = append(idx.broken[v.NamespaceUuid], brokenNode{name: v.Name, tags: v.Tags, err: err})
+			continue
+		}
+
+		idx.nodes[v.NamespaceUuid] = append(idx.nodes[v.NamespaceUuid], models.Node{
+			ID:             v.Uuid.String(),
+			Name:           v.Name,
+			Hostname:       v.Hostname,
+			Port:           int(v.Port),
+			Username:       v.Username,
+			OSFamily:       v.OsFamily,
+			Tags:           v.Tags,
+			ConnectionType: string(v.ConnectionType),
+			Auth: models.NodeAuth{
+				CredentialID: v.CredentialUuid.UUID.String(),
+				Method:       models.AuthMethod(v.AuthMethod),
+				Key:          key,
+			},
+		})
+	}
+
+	return idx, nil
+}
+
+// decryptNodeKey decodes and decrypts the stored key of a node's credential
+func (c *Core) decryptNodeKey(ctx context.Context, nodeName string, keyData string) (string, error) {
+	dKey, err := hex.DecodeString(keyData)
+	if err != nil {
+		return "", fmt.Errorf("could not decode key for node %s: %w", nodeName, err)
+	}
+
+	decryptedKey, err := c.keeper.Decrypt(ctx, dKey)
+	if err != nil {
+		return "", fmt.Errorf("could not decrypt key for node %s: %w", nodeName, err)
+	}
+	return string(decryptedKey), nil
+}
+
+// byNames returns the nodes of a namespace with one of the names
+func (idx nodeIndex) byNames(_ context.Context, nodeNames []string, namespaceUUID uuid.UUID) ([]models.Node, error) {
+	if len(nodeNames) == 0 {
+		return nil, nil
+	}
+
+	names := make(map[string]struct{}, len(nodeNames))
+	for _, name := range nodeNames {
+		names[name] = struct{}{}
+	}
+
+	for _, b := range idx.broken[namespaceUUID] {
+		if _, ok := names[b.name]; ok {
+			return nil, b.err
+		}
+	}
+
+	var nodes []models.Node
+	for _, n := range idx.nodes[namespaceUUID] {
+		if _, ok := names[n.Name]; ok {
+			nodes = append(nodes, n)
+		}
+	}
+
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("no nodes found for names %v", nodeNames)
+	}
+	return nodes, nil
+}
+
+// byTags returns the nodes of a namespace with any of the tags
+func (idx nodeIndex) byTags(_ context.Context, tags []string, namespaceUUID uuid.UUID) ([]models.Node, error) {
+	if len(tags) == 0 {
+		return nil, nil
+	}
+
+	tagSet := make(map[string]struct{}, len(tags))
+	for _, t := range tags {
+		tagSet[t] = struct{}{}
+	}
+	hasTag := func(nodeTags []string) bool {
+		for _, t := range nodeTags {
+			if _, ok := tagSet[t]; ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, b := range idx.broken[namespaceUUID] {
+		if hasTag(b.tags) {
+			return nil, b.err
+		}
+	}
+
+	var nodes []models.Node
+	for _, n := range idx.nodes[namespaceUUID] {
+		if hasTag(n.Tags) {
+			nodes = append(nodes, n)
+		}
+	}
+
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("no nodes found for tags %v", tags)
+	}
+	return nodes, nil
+}
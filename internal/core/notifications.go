@@ -0,0 +1,54 @@
+package core
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/cvhariharan/flowctl/internal/repo"
+	"github.com/google/uuid"
+)
+
+// GetNotificationDeliveriesPaginated returns notification deliveries in a namespace, optionally
+// filtered by delivery status and execution ID.
+func (c *Core) GetNotificationDeliveriesPaginated(ctx context.Context, namespaceID, status, execID string, page, countPerPage int) ([]models.NotificationDelivery, int64, int64, error) {
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return nil, -1, -1, fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	offset := (page - 1) * countPerPage
+
+	rows, err := c.store.ListNotificationDeliveriesPaginated(ctx, repo.ListNotificationDeliveriesPaginatedParams{
+		NamespaceUuid: namespaceUUID,
+		Status:        status,
+		ExecID:        execID,
+		Limit:         int32(countPerPage),
+		Offset:        int32(offset),
+	})
+	if err != nil {
+		return nil, -1, -1, fmt.Errorf("failed to get notification deliveries: %w", err)
+	}
+
+	var deliveries []models.NotificationDelivery
+	var pageCount, totalCount int64
+	for _, row := range rows {
+		deliveries = append(deliveries, models.NotificationDelivery{
+			ID:        row.Uuid.String(),
+			ExecID:    row.ExecID,
+			FlowID:    row.FlowID,
+			Channel:   row.Channel,
+			Receiver:  row.Receiver,
+			Event:     row.Event,
+			Status:    row.Status,
+			Attempts:  int(row.Attempts),
+			LastError: row.LastError,
+			CreatedAt: row.CreatedAt,
+			UpdatedAt: row.UpdatedAt,
+		})
+		pageCount = row.PageCount
+		totalCount = row.TotalCount
+	}
+
+	return deliveries, pageCount, totalCount, nil
+}
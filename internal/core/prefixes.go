@@ -177,7 +177,15 @@ func (c *Core) getOrCreateFlowPrefix(ctx context.Context, prefix string, namespa
 			Description: "",
 		})
 		if err != nil {
-			return sql.NullInt32{}, fmt.Errorf("failed to create flow prefix %s: %w", prefix, err)
+			// Flows are imported concurrently, another flow may have created the prefix
+			var getErr error
+			fp, getErr = c.store.GetFlowPrefixByName(ctx, repo.GetFlowPrefixByNameParams{
+				Name: prefix,
+				Uuid: namespaceUUID,
+			})
+			if getErr != nil {
+				return sql.NullInt32{}, fmt.Errorf("failed to create flow prefix %s: %w", prefix, err)
+			}
 		}
 	}
 
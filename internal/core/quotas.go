@@ -0,0 +1,186 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/cvhariharan/flowctl/internal/repo"
+	"github.com/google/uuid"
+)
+
+// ErrQuotaExceeded is returned when a namespace would go over one of its quotas
+var ErrQuotaExceeded = errors.New("namespace quota exceeded")
+
+// GetNamespaceQuota returns the quota of a namespace.
+// Namespaces without a quota get a disabled one.
+func (c *Core) GetNamespaceQuota(ctx context.Context, namespaceID string) (models.NamespaceQuota, error) {
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return models.NamespaceQuota{}, fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	return c.getNamespaceQuota(ctx, namespaceUUID)
+}
+
+func (c *Core) getNamespaceQuota(ctx context.Context, namespaceUUID uuid.UUID) (models.NamespaceQuota, error) {
+	q, err := c.store.GetNamespaceQuota(ctx, namespaceUUID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.NamespaceQuota{}, nil
+		}
+		return models.NamespaceQuota{}, fmt.Errorf("could not get namespace quota: %w", err)
+	}
+
+	return repoNamespaceQuotaToModel(q), nil
+}
+
+// SetNamespaceQuota creates or replaces the quota of a namespace.
+// A quota without limits removes the existing one.
+func (c *Core) SetNamespaceQuota(ctx context.Context, namespaceID string, quota models.NamespaceQuota) (models.NamespaceQuota, error) {
+	if quota.MaxExecutionsPerDay < 0 || quota.MaxConcurrentExecutions < 0 || quota.MaxNodes < 0 || quota.MaxLogBytes < 0 {
+		return models.NamespaceQuota{}, fmt.Errorf("quota limits cannot be negative")
+	}
+
+	if !quota.Enabled() {
+		return models.NamespaceQuota{}, c.DeleteNamespaceQuota(ctx, namespaceID)
+	}
+
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return models.NamespaceQuota{}, fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	q, err := c.store.UpsertNamespaceQuota(ctx, repo.UpsertNamespaceQuotaParams{
+		Uuid:                    namespaceUUID,
+		MaxExecutionsPerDay:     sql.NullInt32{Int32: int32(quota.MaxExecutionsPerDay), Valid: quota.MaxExecutionsPerDay > 0},
+		MaxConcurrentExecutions: sql.NullInt32{Int32: int32(quota.MaxConcurrentExecutions), Valid: quota.MaxConcurrentExecutions > 0},
+		MaxNodes:                sql.NullInt32{Int32: int32(quota.MaxNodes), Valid: quota.MaxNodes > 0},
+		MaxLogBytes:             sql.NullInt64{Int64: quota.MaxLogBytes, Valid: quota.MaxLogBytes > 0},
+	})
+	if err != nil {
+		return models.NamespaceQuota{}, fmt.Errorf("could not save namespace quota: %w", err)
+	}
+
+	return repoNamespaceQuotaToModel(q), nil
+}
+
+// DeleteNamespaceQuota removes the quota of a namespace, it is no longer limited
+func (c *Core) DeleteNamespaceQuota(ctx context.Context, namespaceID string) error {
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	if err := c.store.DeleteNamespaceQuota(ctx, namespaceUUID); err != nil {
+		return fmt.Errorf("could not delete namespace quota: %w", err)
+	}
+	return nil
+}
+
+// GetNamespaceUsage returns the quota of a namespace together with how much of it is used
+func (c *Core) GetNamespaceUsage(ctx context.Context, namespaceID string) (models.NamespaceUsage, error) {
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return models.NamespaceUsage{}, fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	quota, err := c.getNamespaceQuota(ctx, namespaceUUID)
+	if err != nil {
+		return models.NamespaceUsage{}, err
+	}
+
+	return c.getNamespaceUsage(ctx, namespaceUUID, quota)
+}
+
+func (c *Core) getNamespaceUsage(ctx context.Context, namespaceUUID uuid.UUID, quota models.NamespaceQuota) (models.NamespaceUsage, error) {
+	u, err := c.store.GetNamespaceUsage(ctx, namespaceUUID)
+	if err != nil {
+		return models.NamespaceUsage{}, fmt.Errorf("could not get namespace usage: %w", err)
+	}
+
+	return models.NamespaceUsage{
+		Quota:                quota,
+		ExecutionsToday:      int(u.ExecutionsToday),
+		ConcurrentExecutions: int(u.ConcurrentExecutions),
+		Nodes:                int(u.Nodes),
+		LogBytes:             u.LogBytes,
+	}, nil
+}
+
+// reserveExecution checks that a new execution fits in the quota of the namespace and counts it
+// against the executions of the day. Resumed and retried executions are not counted.
+func (c *Core) reserveExecution(ctx context.Context, namespaceUUID uuid.UUID) error {
+	quota, err := c.getNamespaceQuota(ctx, namespaceUUID)
+	if err != nil {
+		return err
+	}
+
+	if quota.MaxConcurrentExecutions > 0 || quota.MaxLogBytes > 0 {
+		usage, err := c.getNamespaceUsage(ctx, namespaceUUID, quota)
+		if err != nil {
+			return err
+		}
+		if err := executionQuotaViolation(usage); err != nil {
+			return err
+		}
+	}
+
+	// The daily limit is checked while counting, so that executions started at the same time
+	// can't both take the last one
+	_, err = c.store.CountNamespaceExecution(ctx, repo.CountNamespaceExecutionParams{
+		Uuid:          namespaceUUID,
+		MaxExecutions: sql.NullInt32{Int32: int32(quota.MaxExecutionsPerDay), Valid: quota.MaxExecutionsPerDay > 0},
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: %d executions per day", ErrQuotaExceeded, quota.MaxExecutionsPerDay)
+	}
+	if err != nil {
+		return fmt.Errorf("could not count execution: %w", err)
+	}
+	return nil
+}
+
+// executionQuotaViolation returns the reason a new execution can't be started with the given usage
+func executionQuotaViolation(usage models.NamespaceUsage) error {
+	quota := usage.Quota
+	if quota.MaxConcurrentExecutions > 0 && usage.ConcurrentExecutions >= quota.MaxConcurrentExecutions {
+		return fmt.Errorf("%w: %d concurrent executions", ErrQuotaExceeded, quota.MaxConcurrentExecutions)
+	}
+	if quota.MaxLogBytes > 0 && usage.LogBytes >= quota.MaxLogBytes {
+		return fmt.Errorf("%w: %d bytes of logs", ErrQuotaExceeded, quota.MaxLogBytes)
+	}
+	return nil
+}
+
+// checkNodeQuota checks that another node can be added to the namespace
+func (c *Core) checkNodeQuota(ctx context.Context, namespaceUUID uuid.UUID) error {
+	quota, err := c.getNamespaceQuota(ctx, namespaceUUID)
+	if err != nil {
+		return err
+	}
+	if quota.MaxNodes == 0 {
+		return nil
+	}
+
+	usage, err := c.getNamespaceUsage(ctx, namespaceUUID, quota)
+	if err != nil {
+		return err
+	}
+	if usage.Nodes >= quota.MaxNodes {
+		return fmt.Errorf("%w: %d nodes", ErrQuotaExceeded, quota.MaxNodes)
+	}
+	return nil
+}
+
+func repoNamespaceQuotaToModel(q repo.NamespaceQuota) models.NamespaceQuota {
+	return models.NamespaceQuota{
+		MaxExecutionsPerDay:     int(q.MaxExecutionsPerDay.Int32),
+		MaxConcurrentExecutions: int(q.MaxConcurrentExecutions.Int32),
+		MaxNodes:                int(q.MaxNodes.Int32),
+		MaxLogBytes:             q.MaxLogBytes.Int64,
+		UpdatedAt:               q.UpdatedAt,
+	}
+}
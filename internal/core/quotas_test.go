@@ -0,0 +1,55 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+)
+
+func TestExecutionQuotaViolation(t *testing.T) {
+	tests := []struct {
+		name    string
+		usage   models.NamespaceUsage
+		wantErr bool
+	}{
+		{
+			name:  "no quota",
+			usage: models.NamespaceUsage{ConcurrentExecutions: 100, LogBytes: 1 << 40},
+		},
+		{
+			name:  "under the concurrent limit",
+			usage: models.NamespaceUsage{Quota: models.NamespaceQuota{MaxConcurrentExecutions: 2}, ConcurrentExecutions: 1},
+		},
+		{
+			name:    "at the concurrent limit",
+			usage:   models.NamespaceUsage{Quota: models.NamespaceQuota{MaxConcurrentExecutions: 2}, ConcurrentExecutions: 2},
+			wantErr: true,
+		},
+		{
+			name:  "under the log limit",
+			usage: models.NamespaceUsage{Quota: models.NamespaceQuota{MaxLogBytes: 1024}, LogBytes: 1023},
+		},
+		{
+			name:    "at the log limit",
+			usage:   models.NamespaceUsage{Quota: models.NamespaceQuota{MaxLogBytes: 1024}, LogBytes: 1024},
+			wantErr: true,
+		},
+		{
+			name:  "other limits are not checked",
+			usage: models.NamespaceUsage{Quota: models.NamespaceQuota{MaxExecutionsPerDay: 1, MaxNodes: 1}, ExecutionsToday: 5, Nodes: 5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := executionQuotaViolation(tt.usage)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("executionQuotaViolation() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrQuotaExceeded) {
+				t.Errorf("executionQuotaViolation() error = %v, want ErrQuotaExceeded", err)
+			}
+		})
+	}
+}
@@ -59,6 +59,9 @@ func (c *Core) InitializeRBACPolicies() error {
 	c.enforcer.AddPolicy("role:reviewer", "/*", string(models.ResourceMember), string(models.RBACActionView))
 	c.enforcer.AddPolicy("role:reviewer", "/*", string(models.ResourceApproval), string(models.RBACActionApprove))
 	c.enforcer.AddPolicy("role:reviewer", "/*", string(models.ResourceExecution), string(models.RBACActionView))
+	// Reviewers approve changes to flows in namespaces that require a review, which needs the full config
+	c.enforcer.AddPolicy("role:reviewer", "/*", string(models.ResourceFlow), string(models.RBACActionViewConfig))
+	c.enforcer.AddPolicy("role:reviewer", "/*", string(models.ResourceFlow), string(models.RBACActionApprove))
 
 	// Admin role policies
 	c.enforcer.AddPolicy("role:admin", "/*", string(models.ResourceFlow), string(models.RBACActionCreate))
@@ -66,6 +69,7 @@ func (c *Core) InitializeRBACPolicies() error {
 	c.enforcer.AddPolicy("role:admin", "/*", string(models.ResourceFlow), string(models.RBACActionDelete))
 	c.enforcer.AddPolicy("role:admin", "/*", string(models.ResourceFlow), string(models.RBACActionView))
 	c.enforcer.AddPolicy("role:admin", "/*", string(models.ResourceFlow), string(models.RBACActionExecute))
+	c.enforcer.AddPolicy("role:admin", "/*", string(models.ResourceFlow), string(models.RBACActionApprove))
 	c.enforcer.AddPolicy("role:admin", "/*", string(models.ResourceExecution), string(models.RBACActionView))
 	c.enforcer.AddPolicy("role:admin", "/*", string(models.ResourceExecution), string(models.RBACActionUpdate))
 	c.enforcer.AddPolicy("role:admin", "/*", string(models.ResourceNode), string(models.RBACActionView))
@@ -90,6 +94,8 @@ func (c *Core) InitializeRBACPolicies() error {
 	c.enforcer.AddPolicy("role:admin", "/*", string(models.ResourceNamespaceSecret), string(models.RBACActionCreate))
 	c.enforcer.AddPolicy("role:admin", "/*", string(models.ResourceNamespaceSecret), string(models.RBACActionUpdate))
 	c.enforcer.AddPolicy("role:admin", "/*", string(models.ResourceNamespaceSecret), string(models.RBACActionDelete))
+	// Admin can change namespace settings such as the execution retention policy
+	c.enforcer.AddPolicy("role:admin", "/*", string(models.ResourceNamespace), string(models.RBACActionUpdate))
 	// Admin can view flow config (does not inherit from operator, so must be explicit)
 	c.enforcer.AddPolicy("role:admin", "/*", string(models.ResourceFlow), string(models.RBACActionViewConfig))
 
@@ -159,7 +165,27 @@ func (c *Core) AssignNamespaceRole(ctx context.Context, subjectID string, subjec
 
 // CheckPermission checks if a user has permission to perform an action on a resource.
 // The domain parameter encodes namespace and optional prefix scope.
+// Approval permissions also follow any active approval delegations made to the user.
 func (c *Core) CheckPermission(ctx context.Context, userID string, domain string, resource models.Resource, action models.RBACAction) (bool, error) {
+	allowed, err := c.checkDirectPermission(ctx, userID, domain, resource, action)
+	if err != nil {
+		return false, err
+	}
+	if allowed || resource != models.ResourceApproval {
+		return allowed, nil
+	}
+
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return false, fmt.Errorf("invalid user UUID: %w", err)
+	}
+
+	return c.checkDelegatedPermission(ctx, userUUID, domain, action)
+}
+
+// checkDirectPermission checks the permissions granted to the user and their groups,
+// without considering approval delegations.
+func (c *Core) checkDirectPermission(ctx context.Context, userID string, domain string, resource models.Resource, action models.RBACAction) (bool, error) {
 	userUUID, err := uuid.Parse(userID)
 	if err != nil {
 		return false, fmt.Errorf("invalid user UUID: %w", err)
@@ -204,6 +230,15 @@ func (c *Core) CheckPermission(ctx context.Context, userID string, domain string
 	return false, nil
 }
 
+// namespaceFromDomain extracts the namespace ID from a casbin domain of the form "/<namespace>/<scope>".
+func namespaceFromDomain(domain string) (string, bool) {
+	parts := strings.SplitN(strings.TrimPrefix(domain, "/"), "/", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return "", false
+	}
+	return parts[0], true
+}
+
 // GetUserNamespaces returns all namespaces a user has access to with their roles
 func (c *Core) GetUserNamespaces(ctx context.Context, userID string) ([]models.NamespaceWithRole, error) {
 	userUUID, err := uuid.Parse(userID)
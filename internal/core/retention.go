@@ -0,0 +1,415 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/cvhariharan/flowctl/internal/artifactstore"
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/cvhariharan/flowctl/internal/repo"
+	"github.com/google/uuid"
+)
+
+// GetRetentionPolicy returns the retention policy of a namespace.
+// Namespaces without a policy get a disabled one.
+func (c *Core) GetRetentionPolicy(ctx context.Context, namespaceID string) (models.RetentionPolicy, error) {
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return models.RetentionPolicy{}, fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	p, err := c.store.GetRetentionPolicy(ctx, namespaceUUID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.RetentionPolicy{}, nil
+		}
+		return models.RetentionPolicy{}, fmt.Errorf("could not get retention policy: %w", err)
+	}
+
+	return repoRetentionPolicyToModel(p), nil
+}
+
+// SetRetentionPolicy creates or replaces the retention policy of a namespace.
+// A policy without limits removes the existing one.
+func (c *Core) SetRetentionPolicy(ctx context.Context, namespaceID string, policy models.RetentionPolicy) (models.RetentionPolicy, error) {
+	if policy.MaxAgeDays < 0 || policy.KeepLast < 0 {
+		return models.RetentionPolicy{}, fmt.Errorf("retention limits cannot be negative")
+	}
+
+	if !policy.Enabled() {
+		return models.RetentionPolicy{}, c.DeleteRetentionPolicy(ctx, namespaceID)
+	}
+
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return models.RetentionPolicy{}, fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	maxAgeDays, keepLast := retentionLimits(policy)
+	p, err := c.store.UpsertRetentionPolicy(ctx, repo.UpsertRetentionPolicyParams{
+		Uuid:       namespaceUUID,
+		MaxAgeDays: maxAgeDays,
+		KeepLast:   keepLast,
+	})
+	if err != nil {
+		return models.RetentionPolicy{}, fmt.Errorf("could not save retention policy: %w", err)
+	}
+
+	return repoRetentionPolicyToModel(p), nil
+}
+
+// DeleteRetentionPolicy removes the retention policy of a namespace, its executions are kept forever
+func (c *Core) DeleteRetentionPolicy(ctx context.Context, namespaceID string) error {
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	if err := c.store.DeleteRetentionPolicy(ctx, namespaceUUID); err != nil {
+		return fmt.Errorf("could not delete retention policy: %w", err)
+	}
+	return nil
+}
+
+// PreviewRetention reports the executions of a namespace that the next purge would remove
+// without removing them
+func (c *Core) PreviewRetention(ctx context.Context, namespaceID string) (models.RetentionPreview, error) {
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return models.RetentionPreview{}, fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	p, err := c.store.GetRetentionPolicy(ctx, namespaceUUID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.RetentionPreview{ByStatus: map[models.ExecutionStatus]int64{}}, nil
+		}
+		return models.RetentionPreview{}, fmt.Errorf("could not get retention policy: %w", err)
+	}
+
+	before := retentionCutoff(p.MaxAgeDays, time.Now())
+	summary, err := c.store.SummarizeExpiredExecutions(ctx, repo.SummarizeExpiredExecutionsParams{
+		NamespaceID: p.NamespaceID,
+		Before:      before,
+		KeepLast:    p.KeepLast,
+	})
+	if err != nil {
+		return models.RetentionPreview{}, fmt.Errorf("could not summarize expired executions: %w", err)
+	}
+
+	samples, err := c.store.ListExpiredExecutions(ctx, repo.ListExpiredExecutionsParams{
+		NamespaceID: p.NamespaceID,
+		Before:      before,
+		KeepLast:    p.KeepLast,
+		BatchSize:   models.RetentionPreviewMaxSamples,
+	})
+	if err != nil {
+		return models.RetentionPreview{}, fmt.Errorf("could not list expired executions: %w", err)
+	}
+
+	preview := models.RetentionPreview{
+		Policy:        repoRetentionPolicyToModel(p),
+		ByStatus:      make(map[models.ExecutionStatus]int64, len(summary)),
+		SampleExecIDs: make([]string, 0, len(samples)),
+	}
+	for _, s := range summary {
+		preview.Total += s.Count
+		preview.Archived += s.ArchivedCount
+		preview.ByStatus[models.ExecutionStatus(s.Status)] = s.Count
+		if preview.Oldest.IsZero() || s.Oldest.Before(preview.Oldest) {
+			preview.Oldest = s.Oldest
+		}
+		if s.Newest.After(preview.Newest) {
+			preview.Newest = s.Newest
+		}
+	}
+	for _, s := range samples {
+		preview.SampleExecIDs = append(preview.SampleExecIDs, s.ExecID)
+	}
+
+	return preview, nil
+}
+
+// RetentionOptions configures the retention job
+type RetentionOptions struct {
+	Interval  time.Duration
+	BatchSize int
+	// ArtifactGrace is how long artifacts must be untouched before they can be collected
+	ArtifactGrace time.Duration
+	// PendingApprovalArtifactsMaxAge is how long an execution can wait for an approval before
+	// its artifacts are collected, 0 keeps them until the execution is purged
+	PendingApprovalArtifactsMaxAge time.Duration
+	// RetryableArtifactsMaxAge is how long the artifacts of errored and cancelled executions,
+	// which can be retried, are kept. 0 keeps them until the execution is purged.
+	RetryableArtifactsMaxAge time.Duration
+}
+
+// RunRetentionPurge applies the retention policies of all namespaces and collects leftover
+// artifacts every interval, until ctx is cancelled. The options can be changed while it runs
+// with SetRetentionOptions.
+func (c *Core) RunRetentionPurge(ctx context.Context, opts RetentionOptions) {
+	c.retentionMu.Lock()
+	c.retentionOpts = opts
+	c.retentionMu.Unlock()
+
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		opts := c.retentionOptions()
+		if err := c.PurgeExpiredExecutions(ctx, opts.BatchSize); err != nil {
+			log.Printf("failed to purge expired executions: %v", err)
+		}
+		if err := c.CollectArtifacts(ctx, opts); err != nil {
+			log.Printf("failed to collect artifacts: %v", err)
+		}
+
+		if !c.waitForRetentionRun(ctx, ticker) {
+			return
+		}
+	}
+}
+
+// SetRetentionOptions changes the options of the running retention job. They are used from its
+// next run, and a new interval starts counting from the change.
+func (c *Core) SetRetentionOptions(opts RetentionOptions) {
+	c.retentionMu.Lock()
+	c.retentionOpts = opts
+	c.retentionMu.Unlock()
+
+	select {
+	case c.retentionChanged <- struct{}{}:
+	default:
+	}
+}
+
+func (c *Core) retentionOptions() RetentionOptions {
+	c.retentionMu.Lock()
+	defer c.retentionMu.Unlock()
+	return c.retentionOpts
+}
+
+// waitForRetentionRun blocks until the next run of the retention job is due. It returns false
+// if ctx is cancelled first.
+func (c *Core) waitForRetentionRun(ctx context.Context, ticker *time.Ticker) bool {
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+			return true
+		case <-c.retentionChanged:
+			ticker.Reset(c.retentionOptions().Interval)
+		}
+	}
+}
+
+// PurgeExpiredExecutions removes the executions that fall outside the retention policy of their
+// namespace, together with their logs and any artifacts left on this host
+func (c *Core) PurgeExpiredExecutions(ctx context.Context, batchSize int) error {
+	policies, err := c.store.ListRetentionPolicies(ctx)
+	if err != nil {
+		return fmt.Errorf("could not list retention policies: %w", err)
+	}
+
+	for _, p := range policies {
+		n, err := c.purgeNamespaceExecutions(ctx, p.NamespaceID, retentionCutoff(p.MaxAgeDays, time.Now()), p.KeepLast, batchSize)
+		if err != nil {
+			log.Printf("failed to purge executions of namespace %s: %v", p.NamespaceUuid, err)
+			continue
+		}
+		if n > 0 {
+			log.Printf("purged %d executions of namespace %s", n, p.NamespaceUuid)
+		}
+	}
+
+	return nil
+}
+
+func (c *Core) purgeNamespaceExecutions(ctx context.Context, namespaceID int32, before sql.NullTime, keepLast sql.NullInt32, batchSize int) (int64, error) {
+	var total int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+
+		execs, err := c.store.ListExpiredExecutions(ctx, repo.ListExpiredExecutionsParams{
+			NamespaceID: namespaceID,
+			Before:      before,
+			KeepLast:    keepLast,
+			BatchSize:   int32(batchSize),
+		})
+		if err != nil {
+			return total, fmt.Errorf("could not list expired executions: %w", err)
+		}
+		if len(execs) == 0 {
+			return total, nil
+		}
+
+		execIDs := make([]string, 0, len(execs))
+		for _, e := range execs {
+			execIDs = append(execIDs, e.ExecID)
+		}
+
+		if err := c.store.PurgeExecutions(ctx, execIDs); err != nil {
+			return total, fmt.Errorf("could not purge executions: %w", err)
+		}
+		total += int64(len(execIDs))
+
+		// Files are removed after the records so that a failed purge doesn't leave executions without logs
+		for _, execID := range execIDs {
+			c.removeExecutionFiles(ctx, execID)
+		}
+
+		if len(execs) < batchSize {
+			return total, nil
+		}
+	}
+}
+
+// removeExecutionFiles removes the logs of an execution, its uploaded files, the files its actions
+// published and the artifact store that is left behind when an execution doesn't run all of its
+// actions
+func (c *Core) removeExecutionFiles(ctx context.Context, execID string) {
+	if c.LogManager != nil {
+		if err := c.LogManager.DeleteLogs(ctx, execID); err != nil {
+			log.Printf("could not delete logs of execution %s: %v", execID, err)
+		}
+	}
+
+	if c.Artifacts != nil {
+		if err := c.Artifacts.Delete(ctx, execID); err != nil {
+			log.Printf("could not delete artifacts of execution %s: %v", execID, err)
+		}
+	}
+
+	if c.Archive != nil {
+		if err := c.Archive.DeleteExecution(ctx, execID); err != nil {
+			log.Printf("could not delete archived artifacts of execution %s: %v", execID, err)
+		}
+	}
+
+	if err := c.DeleteUploads(ctx, execID); err != nil {
+		log.Printf("could not delete uploads of execution %s: %v", execID, err)
+	}
+}
+
+// CollectArtifacts removes the artifacts that no execution will use again: those of completed
+// executions and of executions that were purged. Errored and cancelled executions can be retried,
+// and executions waiting for an approval resume later, so their artifacts are only collected once
+// they are older than the configured maximum age. Artifacts written within the grace period are
+// skipped so that executions that were just queued or resumed keep theirs.
+func (c *Core) CollectArtifacts(ctx context.Context, opts RetentionOptions) error {
+	if c.Artifacts == nil {
+		return nil
+	}
+
+	stored, err := c.Artifacts.List(ctx)
+	if err != nil {
+		return fmt.Errorf("could not list artifacts: %w", err)
+	}
+
+	now := time.Now()
+	candidates := make([]artifactstore.Artifacts, 0, len(stored))
+	for _, a := range stored {
+		// Only the artifacts of executions are collected, anything else in the store is left alone
+		if _, err := uuid.Parse(a.ExecID); err != nil {
+			continue
+		}
+		if now.Sub(a.ModTime) >= opts.ArtifactGrace {
+			candidates = append(candidates, a)
+		}
+	}
+
+	var collected int
+	var reclaimed int64
+	for start := 0; start < len(candidates); start += opts.BatchSize {
+		batch := candidates[start:min(start+opts.BatchSize, len(candidates))]
+
+		execIDs := make([]string, 0, len(batch))
+		for _, a := range batch {
+			execIDs = append(execIDs, a.ExecID)
+		}
+		statuses, err := c.store.GetExecutionStatuses(ctx, execIDs)
+		if err != nil {
+			return fmt.Errorf("could not get execution statuses: %w", err)
+		}
+		byExecID := make(map[string]repo.GetExecutionStatusesRow, len(statuses))
+		for _, s := range statuses {
+			byExecID[s.ExecID] = s
+		}
+
+		for _, a := range batch {
+			reason, ok := artifactCollectReason(byExecID, a.ExecID, now, opts)
+			if !ok {
+				continue
+			}
+			if err := c.Artifacts.Delete(ctx, a.ExecID); err != nil {
+				log.Printf("could not collect artifacts of execution %s: %v", a.ExecID, err)
+				continue
+			}
+
+			collected++
+			reclaimed += a.Size
+			if c.Metrics != nil {
+				c.Metrics.AddArtifactsCollected(reason, a.Size)
+			}
+		}
+	}
+
+	if collected > 0 {
+		log.Printf("collected artifacts of %d executions, reclaimed %d bytes", collected, reclaimed)
+	}
+	return nil
+}
+
+// artifactCollectReason returns why the artifacts of an execution can be collected, if they can.
+// statuses holds the executions that still exist, live or archived, so executions missing from
+// it were purged.
+func artifactCollectReason(statuses map[string]repo.GetExecutionStatusesRow, execID string, now time.Time, opts RetentionOptions) (string, bool) {
+	s, ok := statuses[execID]
+	if !ok {
+		return "purged", true
+	}
+
+	switch s.Status {
+	case repo.ExecutionStatusCompleted:
+		return string(s.Status), true
+	case repo.ExecutionStatusErrored, repo.ExecutionStatusCancelled:
+		if opts.RetryableArtifactsMaxAge > 0 && now.Sub(s.UpdatedAt) >= opts.RetryableArtifactsMaxAge {
+			return string(s.Status), true
+		}
+	case repo.ExecutionStatusPendingApproval:
+		if opts.PendingApprovalArtifactsMaxAge > 0 && now.Sub(s.UpdatedAt) >= opts.PendingApprovalArtifactsMaxAge {
+			return string(s.Status), true
+		}
+	}
+	return "", false
+}
+
+// retentionLimits returns the limits of a policy as stored, a limit of zero is not set.
+// An execution is expired when it is past either limit.
+func retentionLimits(policy models.RetentionPolicy) (maxAgeDays, keepLast sql.NullInt32) {
+	return sql.NullInt32{Int32: int32(policy.MaxAgeDays), Valid: policy.MaxAgeDays > 0},
+		sql.NullInt32{Int32: int32(policy.KeepLast), Valid: policy.KeepLast > 0}
+}
+
+// retentionCutoff returns the time before which executions are expired, if the policy has a maximum age
+func retentionCutoff(maxAgeDays sql.NullInt32, now time.Time) sql.NullTime {
+	if !maxAgeDays.Valid {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: now.AddDate(0, 0, -int(maxAgeDays.Int32)), Valid: true}
+}
+
+func repoRetentionPolicyToModel(p repo.ExecutionRetentionPolicy) models.RetentionPolicy {
+	return models.RetentionPolicy{
+		MaxAgeDays: int(p.MaxAgeDays.Int32),
+		KeepLast:   int(p.KeepLast.Int32),
+		UpdatedAt:  p.UpdatedAt,
+	}
+}
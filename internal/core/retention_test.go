@@ -0,0 +1,104 @@
+package core
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/cvhariharan/flowctl/internal/repo"
+)
+
+func TestArtifactCollectReason(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	statuses := map[string]repo.GetExecutionStatusesRow{
+		"completed":      {ExecID: "completed", Status: repo.ExecutionStatusCompleted, UpdatedAt: now.Add(-time.Hour)},
+		"errored":        {ExecID: "errored", Status: repo.ExecutionStatusErrored, UpdatedAt: now.Add(-10 * day)},
+		"errored_recent": {ExecID: "errored_recent", Status: repo.ExecutionStatusErrored, UpdatedAt: now.Add(-time.Hour)},
+		"cancelled":      {ExecID: "cancelled", Status: repo.ExecutionStatusCancelled, UpdatedAt: now.Add(-10 * day)},
+		"pending":        {ExecID: "pending", Status: repo.ExecutionStatusPendingApproval, UpdatedAt: now.Add(-10 * day)},
+		"pending_recent": {ExecID: "pending_recent", Status: repo.ExecutionStatusPendingApproval, UpdatedAt: now.Add(-time.Hour)},
+		"running":        {ExecID: "running", Status: repo.ExecutionStatusRunning, UpdatedAt: now.Add(-10 * day)},
+	}
+	keepAll := RetentionOptions{}
+	maxAges := RetentionOptions{PendingApprovalArtifactsMaxAge: 7 * day, RetryableArtifactsMaxAge: 7 * day}
+
+	tests := []struct {
+		name       string
+		execID     string
+		opts       RetentionOptions
+		wantReason string
+		wantOK     bool
+	}{
+		{"purged", "missing", keepAll, "purged", true},
+		{"completed", "completed", keepAll, "completed", true},
+		{"errored is kept for a retry", "errored", keepAll, "", false},
+		{"cancelled is kept for a retry", "cancelled", keepAll, "", false},
+		{"errored past max age", "errored", maxAges, "errored", true},
+		{"errored within max age", "errored_recent", maxAges, "", false},
+		{"cancelled past max age", "cancelled", maxAges, "cancelled", true},
+		{"pending approval is kept", "pending", keepAll, "", false},
+		{"pending approval past max age", "pending", maxAges, "pending_approval", true},
+		{"pending approval within max age", "pending_recent", maxAges, "", false},
+		{"running", "running", maxAges, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, ok := artifactCollectReason(statuses, tt.execID, now, tt.opts)
+			if reason != tt.wantReason || ok != tt.wantOK {
+				t.Errorf("artifactCollectReason(%s) = %q, %v, want %q, %v", tt.execID, reason, ok, tt.wantReason, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRetentionCutoff(t *testing.T) {
+	now := time.Date(2026, 3, 29, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		maxAgeDays sql.NullInt32
+		want       sql.NullTime
+	}{
+		{"no max age", sql.NullInt32{}, sql.NullTime{}},
+		{"one day", sql.NullInt32{Int32: 1, Valid: true}, sql.NullTime{Time: now.AddDate(0, 0, -1), Valid: true}},
+		{"across a month", sql.NullInt32{Int32: 30, Valid: true}, sql.NullTime{Time: time.Date(2026, 2, 27, 12, 0, 0, 0, time.UTC), Valid: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := retentionCutoff(tt.maxAgeDays, now)
+			if got.Valid != tt.want.Valid || !got.Time.Equal(tt.want.Time) {
+				t.Errorf("retentionCutoff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetentionLimits(t *testing.T) {
+	tests := []struct {
+		name         string
+		policy       models.RetentionPolicy
+		wantMaxAge   sql.NullInt32
+		wantKeepLast sql.NullInt32
+	}{
+		{"disabled", models.RetentionPolicy{}, sql.NullInt32{}, sql.NullInt32{}},
+		{"max age only", models.RetentionPolicy{MaxAgeDays: 30}, sql.NullInt32{Int32: 30, Valid: true}, sql.NullInt32{}},
+		{"keep last only", models.RetentionPolicy{KeepLast: 100}, sql.NullInt32{}, sql.NullInt32{Int32: 100, Valid: true}},
+		{"both", models.RetentionPolicy{MaxAgeDays: 30, KeepLast: 100}, sql.NullInt32{Int32: 30, Valid: true}, sql.NullInt32{Int32: 100, Valid: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			maxAge, keepLast := retentionLimits(tt.policy)
+			if maxAge != tt.wantMaxAge || keepLast != tt.wantKeepLast {
+				t.Errorf("retentionLimits() = %v, %v, want %v, %v", maxAge, keepLast, tt.wantMaxAge, tt.wantKeepLast)
+			}
+			if got := retentionCutoff(maxAge, time.Now()); got.Valid != tt.wantMaxAge.Valid {
+				t.Errorf("retentionCutoff() valid = %v, want %v", got.Valid, tt.wantMaxAge.Valid)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/cvhariharan/flowctl/internal/repo"
+	"github.com/cvhariharan/flowctl/internal/scheduler"
+)
+
+// CheckSLABreaches compares the executions that haven't finished yet against the SLA of their
+// flows. A breach is recorded once per execution and kind, and triggers the on_sla_breach
+// notifications of the flow.
+// This function can be used as a SLAMonitorFn for the scheduler
+func (c *Core) CheckSLABreaches(ctx context.Context) error {
+	executions, err := c.store.ListActiveExecutionsForSLA(ctx)
+	if err != nil {
+		return fmt.Errorf("could not list active executions: %w", err)
+	}
+
+	now := time.Now()
+	for _, e := range executions {
+		namespaceID := e.NamespaceUuid.String()
+
+		f, err := c.GetFlowByID(e.FlowSlug, namespaceID)
+		if err != nil || f.Meta.SLA == nil {
+			continue
+		}
+		f, err = c.withNamespaceDefaults(ctx, f, e.NamespaceUuid)
+		if err != nil {
+			log.Printf("failed to apply namespace defaults to flow %s: %v", e.FlowSlug, err)
+			continue
+		}
+
+		// The SLA of a scheduled execution starts when it was due to run
+		start := e.FirstCreatedAt
+		if e.ScheduledAt.Valid {
+			start = e.ScheduledAt.Time
+		}
+		if start.After(now) {
+			continue
+		}
+
+		var reasons []string
+		for _, breach := range f.Meta.SLA.Breaches(start, now) {
+			n, err := c.store.CreateSLABreach(ctx, repo.CreateSLABreachParams{
+				ExecID:        e.ExecID,
+				NamespaceUuid: e.NamespaceUuid,
+				FlowSlug:      e.FlowSlug,
+				Kind:          breach.Kind,
+				Reason:        breach.Reason,
+			})
+			if err != nil {
+				log.Printf("failed to record SLA breach for exec %s: %v", e.ExecID, err)
+				continue
+			}
+			// Already reported in an earlier check
+			if n == 0 {
+				continue
+			}
+
+			log.Printf("execution %s of flow %s breached its SLA: %s", e.ExecID, e.FlowSlug, breach.Reason)
+			if c.Metrics != nil {
+				c.Metrics.IncSLABreaches(namespaceID, f.Meta.ID, breach.Kind)
+			}
+			reasons = append(reasons, breach.Reason)
+		}
+
+		if len(reasons) == 0 {
+			continue
+		}
+
+		if err := c.notifySLABreach(ctx, e.ExecID, e.FlowSlug, namespaceID, strings.Join(reasons, "; ")); err != nil {
+			log.Printf("failed to queue SLA breach notifications for exec %s: %v", e.ExecID, err)
+		}
+	}
+
+	return nil
+}
+
+// notifySLABreach queues the on_sla_breach notifications configured on the flow
+func (c *Core) notifySLABreach(ctx context.Context, execID string, flowSlug string, namespaceID string, reason string) error {
+	sf, err := c.GetSchedulerFlow(ctx, flowSlug, namespaceID)
+	if err != nil {
+		return err
+	}
+
+	return scheduler.QueueNotifications(ctx, c.scheduler, sf, scheduler.NotificationPayload{
+		FlowID:      sf.Meta.ID,
+		FlowName:    sf.Meta.Name,
+		ExecID:      execID,
+		Status:      scheduler.StatusSLABreached,
+		Reason:      reason,
+		NamespaceID: namespaceID,
+	})
+}
@@ -0,0 +1,170 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/cvhariharan/flowctl/internal/repo"
+	"github.com/cvhariharan/flowctl/internal/scheduler"
+	"github.com/google/uuid"
+)
+
+// MaxSubflowDepth limits the number of executions in a chain of flow actions, so that flows that
+// run each other don't run forever
+const MaxSubflowDepth = 10
+
+// ErrSubflowDepthExceeded is returned when a flow action would make a chain of sub-flows longer
+// than MaxSubflowDepth
+var ErrSubflowDepthExceeded = errors.New("chain of sub-flows is too long")
+
+// QueueSubflowExecution queues the execution of a flow for the flow action of a parent execution.
+// The inputs are mapped like the inputs of a triggered flow. The execution runs as the user that
+// triggered the parent execution and resumes it once it finished.
+func (c *Core) QueueSubflowExecution(ctx context.Context, req scheduler.SubflowRequest) (string, error) {
+	f, err := c.GetFlowByID(req.FlowID, req.NamespaceID)
+	if err != nil {
+		return "", fmt.Errorf("could not get flow %s: %w", req.FlowID, err)
+	}
+
+	if err := models.ValidateTriggerInputs(f, req.Inputs, "inputs", "vars", "outputs"); err != nil {
+		return "", err
+	}
+	inputs, err := models.MapTriggerInputs(f, req.Inputs, req.Env)
+	if err != nil {
+		return "", err
+	}
+	if verr := f.ValidateInput(inputs); verr != nil {
+		return "", verr
+	}
+
+	namespaceUUID, err := uuid.Parse(req.NamespaceID)
+	if err != nil {
+		return "", fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	var depth int32
+	parent, err := c.store.GetSubflowExecution(ctx, req.ParentExecID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("could not get sub-flow of exec %s: %w", req.ParentExecID, err)
+	}
+	if err == nil {
+		depth = parent.Depth
+	}
+	if depth >= MaxSubflowDepth {
+		return "", fmt.Errorf("%w: exec %s is execution %d of the chain", ErrSubflowDepthExceeded, req.ParentExecID, depth)
+	}
+
+	execID := uuid.NewString()
+	_, err = c.store.CreateSubflowExecution(ctx, repo.CreateSubflowExecutionParams{
+		ParentExecID:   req.ParentExecID,
+		ParentActionID: req.ParentActionID,
+		ExecID:         execID,
+		NamespaceUuid:  namespaceUUID,
+		Depth:          depth + 1,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("action %s of exec %s is already running a flow", req.ParentActionID, req.ParentExecID)
+	}
+	if err != nil {
+		return "", fmt.Errorf("could not record sub-flow: %w", err)
+	}
+
+	if _, err := c.QueueFlowExecutionWithExecID(ctx, f, inputs, req.UserUUID, req.NamespaceID, execID, nil); err != nil {
+		if derr := c.store.DeleteSubflowExecution(ctx, execID); derr != nil {
+			err = errors.Join(err, derr)
+		}
+		return "", err
+	}
+	return execID, nil
+}
+
+// OnExecutionFinished runs once an execution completed, errored or was cancelled. It resumes the
+// execution waiting on it in a flow action and queues the flows triggered by it.
+func (c *Core) OnExecutionFinished(ctx context.Context, execID, status, flowSlug, namespaceID string) error {
+	return errors.Join(
+		c.resumeSubflowParent(ctx, execID, namespaceID),
+		c.RunFlowTriggers(ctx, execID, status, flowSlug, namespaceID),
+	)
+}
+
+// resumeSubflowParent resumes the execution that started execID from a flow action, as the user
+// that triggered it. Parents that are no longer waiting on the action, like cancelled ones, are
+// left as they are.
+func (c *Core) resumeSubflowParent(ctx context.Context, execID, namespaceID string) error {
+	sub, err := c.store.GetSubflowExecution(ctx, execID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not get sub-flow of exec %s: %w", execID, err)
+	}
+	if sub.ResolvedAt.Valid {
+		return nil
+	}
+
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+	parent, err := c.store.GetExecutionByExecID(ctx, repo.GetExecutionByExecIDParams{
+		ExecID: sub.ParentExecID,
+		Uuid:   namespaceUUID,
+	})
+	if err != nil {
+		return fmt.Errorf("could not get exec %s: %w", sub.ParentExecID, err)
+	}
+	if parent.Status != repo.ExecutionStatusPending || parent.CurrentActionID.String != sub.ParentActionID {
+		return nil
+	}
+
+	if err := c.ResumeFlowExecution(ctx, sub.ParentExecID, sub.ParentActionID, parent.TriggeredByUuid.String(), namespaceID, true); err != nil {
+		return fmt.Errorf("could not resume exec %s: %w", sub.ParentExecID, err)
+	}
+	return nil
+}
+
+// getSubflowParent returns the execution that started an execution from a flow action, nil if
+// it wasn't started by one
+func (c *Core) getSubflowParent(ctx context.Context, execID string) *models.SubflowParent {
+	sub, err := c.store.GetSubflowExecution(ctx, execID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		log.Printf("failed to get parent of exec %s: %v", execID, err)
+		return nil
+	}
+	return &models.SubflowParent{
+		ExecID:   sub.ParentExecID,
+		ActionID: sub.ParentActionID,
+	}
+}
+
+// getSubflows returns the executions started by the flow actions of an execution
+func (c *Core) getSubflows(ctx context.Context, execID string, namespaceUUID uuid.UUID) []models.SubflowExecution {
+	rows, err := c.store.ListSubflowExecutions(ctx, repo.ListSubflowExecutionsParams{
+		ParentExecID: execID,
+		Uuid:         namespaceUUID,
+	})
+	if err != nil {
+		log.Printf("failed to get sub-flows of exec %s: %v", execID, err)
+		return nil
+	}
+
+	var subflows []models.SubflowExecution
+	for _, r := range rows {
+		subflows = append(subflows, models.SubflowExecution{
+			ActionID:  r.ParentActionID,
+			ExecID:    r.ExecID,
+			FlowID:    r.FlowSlug,
+			FlowName:  r.FlowName,
+			Status:    models.ExecutionStatus(r.Status),
+			CreatedAt: r.CreatedAt,
+		})
+	}
+	return subflows
+}
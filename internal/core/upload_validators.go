@@ -0,0 +1,155 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/cvhariharan/flowctl/internal/uploadstore"
+)
+
+// ErrUploadRejected is returned when a validator rejects a file uploaded to a file input
+var ErrUploadRejected = errors.New("upload rejected")
+
+// maxScanMessage limits how much of the output of a scanner is returned as the reason a file was rejected
+const maxScanMessage = 1024
+
+// Upload is a file uploaded to a file input of an execution
+type Upload struct {
+	ExecID      string
+	Input       models.Input
+	Filename    string
+	ContentType string
+}
+
+// UploadValidator checks a file uploaded to a file input after it is stored and before the
+// execution is queued. An error rejects the file, errors wrapping ErrUploadRejected are
+// returned to the user as the reason.
+type UploadValidator interface {
+	ValidateUpload(ctx context.Context, u Upload, r io.Reader) error
+}
+
+// ValidateUpload runs the upload validators on the file referenced by ref. The accept and
+// max_file_size settings of the input are checked while the file is stored by SaveUpload.
+func (c *Core) ValidateUpload(ctx context.Context, u Upload, ref string) error {
+	if len(c.UploadValidators) == 0 {
+		return nil
+	}
+
+	key, ok := uploadstore.ParseRef(ref)
+	if !ok {
+		return fmt.Errorf("invalid upload reference for %s", u.Input.Name)
+	}
+
+	for _, v := range c.UploadValidators {
+		r, err := c.Uploads.NewReader(ctx, key)
+		if err != nil {
+			return err
+		}
+		err = v.ValidateUpload(ctx, u, r)
+		r.Close()
+		if err != nil {
+			return fmt.Errorf("file %s: %w", u.Input.Name, err)
+		}
+	}
+	return nil
+}
+
+// CommandScanner rejects files for which a command exits with a non-zero status. The file is
+// written to the standard input of the command and its name, content type and input are set
+// in FLOWCTL_UPLOAD_FILENAME, FLOWCTL_UPLOAD_CONTENT_TYPE and FLOWCTL_UPLOAD_INPUT.
+type CommandScanner struct {
+	Command []string
+	Timeout time.Duration
+}
+
+func (s CommandScanner) ValidateUpload(ctx context.Context, u Upload, r io.Reader) error {
+	if len(s.Command) == 0 {
+		return nil
+	}
+	if s.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.Timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(ctx, s.Command[0], s.Command[1:]...)
+	cmd.Stdin = r
+	cmd.Env = append(os.Environ(),
+		"FLOWCTL_UPLOAD_FILENAME="+u.Filename,
+		"FLOWCTL_UPLOAD_CONTENT_TYPE="+u.ContentType,
+		"FLOWCTL_UPLOAD_INPUT="+u.Input.Name,
+	)
+
+	out, err := cmd.CombinedOutput()
+	if ctx.Err() != nil {
+		return fmt.Errorf("upload scanner did not finish: %w", ctx.Err())
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return fmt.Errorf("%w: %s", ErrUploadRejected, scanMessage(out, exitErr.Error()))
+	}
+	if err != nil {
+		return fmt.Errorf("could not run upload scanner: %w", err)
+	}
+	return nil
+}
+
+// WebhookScanner rejects files for which a webhook doesn't respond with 2xx. The file is the
+// body of a POST request, its name, content type and input are set in the X-Flowctl-Filename,
+// X-Flowctl-Content-Type and X-Flowctl-Input headers.
+type WebhookScanner struct {
+	URL    string
+	Client *http.Client
+}
+
+func (s WebhookScanner) ValidateUpload(ctx context.Context, u Upload, r io.Reader) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, r)
+	if err != nil {
+		return fmt.Errorf("could not create upload scan request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("X-Flowctl-Filename", u.Filename)
+	req.Header.Set("X-Flowctl-Content-Type", u.ContentType)
+	req.Header.Set("X-Flowctl-Input", u.Input.Name)
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("could not reach upload scanner: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxScanMessage))
+	if resp.StatusCode >= 500 {
+		return fmt.Errorf("upload scanner returned %s", resp.Status)
+	}
+	return fmt.Errorf("%w: %s", ErrUploadRejected, scanMessage(body, resp.Status))
+}
+
+// scanMessage returns the output of a scanner as the reason a file was rejected, or fallback
+// if there is no output
+func scanMessage(out []byte, fallback string) string {
+	msg := strings.TrimSpace(string(out))
+	if len(msg) > maxScanMessage {
+		msg = msg[:maxScanMessage]
+	}
+	if msg == "" {
+		return fallback
+	}
+	return msg
+}
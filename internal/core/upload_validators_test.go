@@ -0,0 +1,81 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+)
+
+func TestCommandScanner(t *testing.T) {
+	scanner := CommandScanner{Command: []string{"sh", "-c", `if grep -q EICAR; then echo "$FLOWCTL_UPLOAD_FILENAME is infected"; exit 1; fi`}}
+	upload := Upload{Input: models.Input{Name: "report"}, Filename: "report.csv"}
+
+	tests := []struct {
+		name     string
+		content  string
+		rejected string
+	}{
+		{"clean file", "id,name\n1,flowctl\n", ""},
+		{"rejected file", "X5O!P%@AP EICAR-STANDARD-ANTIVIRUS-TEST-FILE", "report.csv is infected"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := scanner.ValidateUpload(context.Background(), upload, strings.NewReader(tt.content))
+			if tt.rejected == "" {
+				if err != nil {
+					t.Fatalf("ValidateUpload() error = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrUploadRejected) || !strings.Contains(err.Error(), tt.rejected) {
+				t.Fatalf("ValidateUpload() error = %v, want rejection %q", err, tt.rejected)
+			}
+		})
+	}
+}
+
+func TestWebhookScanner(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch string(body) {
+		case "infected":
+			http.Error(w, r.Header.Get("X-Flowctl-Filename")+" is infected", http.StatusUnprocessableEntity)
+		case "unavailable":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	scanner := WebhookScanner{URL: srv.URL}
+	upload := Upload{Input: models.Input{Name: "report"}, Filename: "report.csv"}
+
+	tests := []struct {
+		name     string
+		content  string
+		wantErr  bool
+		rejected bool
+	}{
+		{"clean file", "clean", false, false},
+		{"rejected file", "infected", true, true},
+		{"scanner unavailable", "unavailable", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := scanner.ValidateUpload(context.Background(), upload, strings.NewReader(tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateUpload() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if errors.Is(err, ErrUploadRejected) != tt.rejected {
+				t.Errorf("ValidateUpload() error = %v, rejected %v", err, tt.rejected)
+			}
+		})
+	}
+}
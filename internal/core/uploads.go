@@ -0,0 +1,48 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/cvhariharan/flowctl/internal/uploadstore"
+)
+
+// SaveUpload streams a file uploaded to a file input of an execution into the upload store and
+// returns the reference that is passed to the execution as the value of the input
+func (c *Core) SaveUpload(ctx context.Context, execID string, input models.Input, filename string, contentType string, r io.Reader, maxSize int64) (string, error) {
+	if c.Uploads == nil {
+		return "", fmt.Errorf("file uploads are not configured")
+	}
+
+	if !input.AcceptsFile(filename, contentType) {
+		return "", fmt.Errorf("file %s must be one of %s", input.Name, strings.Join(input.Accept, ", "))
+	}
+
+	filename = filepath.Base(filepath.Clean(filename))
+	if filename == "" || filename == "." || filename == ".." || filename == string(filepath.Separator) {
+		filename = fmt.Sprintf("uploaded_%s", input.Name)
+	}
+
+	key := uploadstore.Key(execID, input.Name, filename)
+	if _, err := c.Uploads.Put(ctx, key, r, maxSize); err != nil {
+		if errors.Is(err, uploadstore.ErrTooLarge) {
+			return "", fmt.Errorf("file %s exceeds maximum size of %dMB", input.Name, maxSize/(1024*1024))
+		}
+		return "", fmt.Errorf("failed to save uploaded file: %w", err)
+	}
+
+	return uploadstore.Ref(key), nil
+}
+
+// DeleteUploads removes the files uploaded for an execution
+func (c *Core) DeleteUploads(ctx context.Context, execID string) error {
+	if c.Uploads == nil {
+		return nil
+	}
+	return c.Uploads.DeleteExecution(ctx, execID)
+}
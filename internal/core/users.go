@@ -2,6 +2,7 @@ package core
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -9,6 +10,7 @@ import (
 	"github.com/cvhariharan/flowctl/internal/core/models"
 	"github.com/cvhariharan/flowctl/internal/repo"
 	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
 )
 
 const (
@@ -231,6 +233,33 @@ func (c *Core) UpdateUser(ctx context.Context, userUUID string, name string, use
 	return c.repoUserViewToUserWithGroups(userWithGroups)
 }
 
+// SetUserPassword sets the password of a user that logs in with a username and password
+func (c *Core) SetUserPassword(ctx context.Context, username string, password string) error {
+	user, err := c.store.GetUserByUsername(ctx, username)
+	if err != nil {
+		return fmt.Errorf("could not get user %s: %w", username, err)
+	}
+
+	if user.LoginType != repo.UserLoginTypeStandard {
+		return fmt.Errorf("user %s does not use password login", username)
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("could not hash password: %w", err)
+	}
+
+	_, err = c.store.UpdateUserPasswordByUsername(ctx, repo.UpdateUserPasswordByUsernameParams{
+		Username: username,
+		Password: sql.NullString{String: string(hashed), Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("could not update password of user %s: %w", username, err)
+	}
+
+	return nil
+}
+
 func (c *Core) repoUserViewToUserWithGroups(user repo.UserView) (models.UserWithGroups, error) {
 	var groups []models.Group
 	if user.Groups != nil {
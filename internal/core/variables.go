@@ -0,0 +1,185 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/cvhariharan/flowctl/internal/repo"
+	"github.com/google/uuid"
+)
+
+// ErrVariableNotFound is returned when deleting a variable that doesn't exist
+var ErrVariableNotFound = errors.New("variable not found")
+
+// ListGlobalVariables returns the variables available in every namespace
+func (c *Core) ListGlobalVariables(ctx context.Context) ([]models.ConfigVariable, error) {
+	rows, err := c.store.ListGlobalVariables(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not list global variables: %w", err)
+	}
+
+	variables := make([]models.ConfigVariable, 0, len(rows))
+	for _, v := range rows {
+		variables = append(variables, repoGlobalVariableToModel(v))
+	}
+	return variables, nil
+}
+
+// SetGlobalVariable creates or updates a global variable
+func (c *Core) SetGlobalVariable(ctx context.Context, v models.ConfigVariable) (models.ConfigVariable, error) {
+	if v.Key == "" {
+		return models.ConfigVariable{}, errors.New("variable key is required")
+	}
+
+	row, err := c.store.UpsertGlobalVariable(ctx, repo.UpsertGlobalVariableParams{
+		Key:         v.Key,
+		Value:       v.Value,
+		Description: sql.NullString{String: v.Description, Valid: v.Description != ""},
+	})
+	if err != nil {
+		return models.ConfigVariable{}, fmt.Errorf("could not save global variable: %w", err)
+	}
+
+	return repoGlobalVariableToModel(row), nil
+}
+
+func (c *Core) DeleteGlobalVariable(ctx context.Context, key string) error {
+	n, err := c.store.DeleteGlobalVariable(ctx, key)
+	if err != nil {
+		return fmt.Errorf("could not delete global variable: %w", err)
+	}
+	if n == 0 {
+		return ErrVariableNotFound
+	}
+	return nil
+}
+
+// ListNamespaceVariables returns the variables available in a namespace, its own and the global
+// ones it doesn't override, ordered by key
+func (c *Core) ListNamespaceVariables(ctx context.Context, namespaceID string) ([]models.ConfigVariable, error) {
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	globals, err := c.ListGlobalVariables(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := c.store.ListNamespaceVariables(ctx, namespaceUUID)
+	if err != nil {
+		return nil, fmt.Errorf("could not list namespace variables: %w", err)
+	}
+
+	namespaced := make([]models.ConfigVariable, 0, len(rows))
+	for _, v := range rows {
+		namespaced = append(namespaced, repoNamespaceVariableToModel(v))
+	}
+
+	return mergeVariables(globals, namespaced), nil
+}
+
+// SetNamespaceVariable creates or updates a variable of a namespace
+func (c *Core) SetNamespaceVariable(ctx context.Context, namespaceID string, v models.ConfigVariable) (models.ConfigVariable, error) {
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return models.ConfigVariable{}, fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	if v.Key == "" {
+		return models.ConfigVariable{}, errors.New("variable key is required")
+	}
+
+	row, err := c.store.UpsertNamespaceVariable(ctx, repo.UpsertNamespaceVariableParams{
+		Uuid:        namespaceUUID,
+		Key:         v.Key,
+		Value:       v.Value,
+		Description: sql.NullString{String: v.Description, Valid: v.Description != ""},
+	})
+	if err != nil {
+		return models.ConfigVariable{}, fmt.Errorf("could not save namespace variable: %w", err)
+	}
+
+	return repoNamespaceVariableToModel(row), nil
+}
+
+// DeleteNamespaceVariable removes a variable of a namespace. A global variable with the same key
+// becomes visible again.
+func (c *Core) DeleteNamespaceVariable(ctx context.Context, namespaceID string, key string) error {
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	n, err := c.store.DeleteNamespaceVariable(ctx, repo.DeleteNamespaceVariableParams{
+		Key:  key,
+		Uuid: namespaceUUID,
+	})
+	if err != nil {
+		return fmt.Errorf("could not delete namespace variable: %w", err)
+	}
+	if n == 0 {
+		return ErrVariableNotFound
+	}
+	return nil
+}
+
+// GetVariablesForNamespace returns the values of the variables available in a namespace by key.
+// This is the VariablesProviderFn implementation that should be used by the scheduler
+func (c *Core) GetVariablesForNamespace(ctx context.Context, namespaceID string) (map[string]string, error) {
+	variables, err := c.ListNamespaceVariables(ctx, namespaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(variables))
+	for _, v := range variables {
+		values[v.Key] = v.Value
+	}
+	return values, nil
+}
+
+// mergeVariables returns the namespace variables and the global variables they don't override,
+// ordered by key
+func mergeVariables(globals, namespaced []models.ConfigVariable) []models.ConfigVariable {
+	byKey := make(map[string]models.ConfigVariable, len(globals)+len(namespaced))
+	for _, v := range globals {
+		byKey[v.Key] = v
+	}
+	for _, v := range namespaced {
+		byKey[v.Key] = v
+	}
+
+	merged := slices.Collect(maps.Values(byKey))
+	slices.SortFunc(merged, func(a, b models.ConfigVariable) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+	return merged
+}
+
+func repoGlobalVariableToModel(v repo.GlobalVariable) models.ConfigVariable {
+	return models.ConfigVariable{
+		Key:         v.Key,
+		Value:       v.Value,
+		Description: v.Description.String,
+		Scope:       models.VariableScopeGlobal,
+		UpdatedAt:   v.UpdatedAt,
+	}
+}
+
+func repoNamespaceVariableToModel(v repo.NamespaceVariable) models.ConfigVariable {
+	return models.ConfigVariable{
+		Key:         v.Key,
+		Value:       v.Value,
+		Description: v.Description.String,
+		Scope:       models.VariableScopeNamespace,
+		UpdatedAt:   v.UpdatedAt,
+	}
+}
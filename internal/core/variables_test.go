@@ -0,0 +1,47 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+)
+
+func TestMergeVariables(t *testing.T) {
+	global := func(key, value string) models.ConfigVariable {
+		return models.ConfigVariable{Key: key, Value: value, Scope: models.VariableScopeGlobal}
+	}
+	namespaced := func(key, value string) models.ConfigVariable {
+		return models.ConfigVariable{Key: key, Value: value, Scope: models.VariableScopeNamespace}
+	}
+
+	tests := []struct {
+		name       string
+		globals    []models.ConfigVariable
+		namespaced []models.ConfigVariable
+		want       []models.ConfigVariable
+	}{
+		{"none", nil, nil, nil},
+		{"only global", []models.ConfigVariable{global("REGISTRY", "registry.example.com")}, nil, []models.ConfigVariable{global("REGISTRY", "registry.example.com")}},
+		{
+			"namespace overrides global",
+			[]models.ConfigVariable{global("ENV", "staging"), global("REGISTRY", "registry.example.com")},
+			[]models.ConfigVariable{namespaced("ENV", "production")},
+			[]models.ConfigVariable{namespaced("ENV", "production"), global("REGISTRY", "registry.example.com")},
+		},
+		{
+			"ordered by key",
+			[]models.ConfigVariable{global("b", "1"), global("D", "2")},
+			[]models.ConfigVariable{namespaced("a", "3"), namespaced("C", "4")},
+			[]models.ConfigVariable{namespaced("C", "4"), global("D", "2"), namespaced("a", "3"), global("b", "1")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mergeVariables(tt.globals, tt.namespaced); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergeVariables() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package eventbus
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Types of approval events. Execution events are typed execution.<status>, e.g. execution.queued
+// or execution.completed.
+const (
+	TypeApprovalRequested = "approval.requested"
+	TypeApprovalApproved  = "approval.approved"
+	TypeApprovalRejected  = "approval.rejected"
+)
+
+// queueSize is the number of events that can wait to be published before new events are dropped
+const queueSize = 1024
+
+// Event is an execution or approval lifecycle event. It is published as a JSON object.
+type Event struct {
+	ID          string         `json:"id"`
+	Time        time.Time      `json:"time"`
+	Type        string         `json:"type"`
+	NamespaceID string         `json:"namespace_id"`
+	FlowID      string         `json:"flow_id,omitempty"`
+	ExecID      string         `json:"exec_id,omitempty"`
+	Status      string         `json:"status,omitempty"`
+	UserID      string         `json:"user_id,omitempty"`
+	Details     map[string]any `json:"details,omitempty"`
+}
+
+type Config struct {
+	// Provider is nats or kafka
+	Provider string
+	// URL is the NATS server
+	URL string
+	// Brokers are the addresses of the Kafka brokers
+	Brokers []string
+	// SubjectPrefix is prepended to the type of an event to get the NATS subject or Kafka topic
+	SubjectPrefix string
+	Timeout       time.Duration
+}
+
+// transport sends an encoded event to a subject or topic. key identifies the execution the event
+// belongs to, so that the events of an execution are kept in order where the broker supports it.
+type transport interface {
+	Send(subject, key string, data []byte) error
+	Close() error
+}
+
+// Publisher publishes events to NATS or Kafka in the background so that executions and requests
+// are not slowed down by the broker. A nil Publisher discards events.
+type Publisher struct {
+	transport transport
+	prefix    string
+	events    chan Event
+	wg        sync.WaitGroup
+	logger    *slog.Logger
+
+	// mu guards closed so that events published while shutting down are dropped
+	mu     sync.RWMutex
+	closed bool
+}
+
+func New(cfg Config, logger *slog.Logger) (*Publisher, error) {
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+
+	var (
+		t   transport
+		err error
+	)
+	switch cfg.Provider {
+	case "nats":
+		t, err = newNATSTransport(cfg.URL, cfg.Timeout)
+	case "kafka":
+		t, err = newKafkaTransport(cfg.Brokers, cfg.Timeout)
+	default:
+		return nil, fmt.Errorf("unknown event bus provider %q", cfg.Provider)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Publisher{
+		transport: t,
+		prefix:    cfg.SubjectPrefix,
+		events:    make(chan Event, queueSize),
+		logger:    logger,
+	}
+
+	p.wg.Add(1)
+	go p.run()
+
+	return p, nil
+}
+
+// Subject returns the NATS subject or Kafka topic an event of the given type is published to
+func (p *Publisher) Subject(eventType string) string {
+	if p.prefix == "" {
+		return eventType
+	}
+	return p.prefix + "." + eventType
+}
+
+// Publish queues the event to be published. If the broker can't keep up, the event is dropped.
+func (p *Publisher) Publish(e Event) {
+	if p == nil {
+		return
+	}
+	if e.ID == "" {
+		e.ID = uuid.NewString()
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return
+	}
+
+	select {
+	case p.events <- e:
+	default:
+		p.logger.Warn("event bus queue is full, dropping event", "type", e.Type)
+	}
+}
+
+// Close publishes the queued events and closes the connection to the broker
+func (p *Publisher) Close() error {
+	if p == nil {
+		return nil
+	}
+	p.mu.Lock()
+	p.closed = true
+	close(p.events)
+	p.mu.Unlock()
+
+	p.wg.Wait()
+	return p.transport.Close()
+}
+
+func (p *Publisher) run() {
+	defer p.wg.Done()
+
+	for e := range p.events {
+		data, err := json.Marshal(e)
+		if err != nil {
+			p.logger.Error("could not encode event", "type", e.Type, "error", err)
+			continue
+		}
+
+		key := e.ExecID
+		if key == "" {
+			key = e.NamespaceID
+		}
+		if err := p.transport.Send(p.Subject(e.Type), key, data); err != nil {
+			p.logger.Error("could not publish event", "type", e.Type, "error", err)
+		}
+	}
+}
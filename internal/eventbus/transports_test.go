@@ -0,0 +1,65 @@
+package eventbus
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gocloud.dev/pubsub"
+	"gocloud.dev/pubsub/mempubsub"
+)
+
+func TestTopicTransport_Send(t *testing.T) {
+	ctx := context.Background()
+
+	topics := make(map[string]*pubsub.Topic)
+	subs := make(map[string]*pubsub.Subscription)
+	for _, subject := range []string{"flowctl.execution.completed", "flowctl.approval.requested"} {
+		topics[subject] = mempubsub.NewTopic()
+		subs[subject] = mempubsub.NewSubscription(topics[subject], time.Minute)
+	}
+
+	opened := make(map[string]int)
+	closed := false
+	tr := newTopicTransport(func(subject string) (*pubsub.Topic, error) {
+		opened[subject]++
+		return topics[subject], nil
+	}, func() { closed = true }, time.Second)
+
+	for range 2 {
+		if err := tr.Send("flowctl.execution.completed", "exec-1", []byte(`{"id":"1"}`)); err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+	}
+	if err := tr.Send("flowctl.approval.requested", "exec-2", []byte(`{"id":"2"}`)); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	if opened["flowctl.execution.completed"] != 1 || opened["flowctl.approval.requested"] != 1 {
+		t.Errorf("topics opened = %v, want each once", opened)
+	}
+
+	recvCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	msg, err := subs["flowctl.approval.requested"].Receive(recvCtx)
+	if err != nil {
+		t.Fatalf("Receive() error = %v", err)
+	}
+	msg.Ack()
+	if string(msg.Body) != `{"id":"2"}` {
+		t.Errorf("message body = %s", msg.Body)
+	}
+	if msg.Metadata[keyMetadata] != "exec-2" {
+		t.Errorf("message key = %q, want exec-2", msg.Metadata[keyMetadata])
+	}
+
+	if err := tr.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if !closed {
+		t.Error("Close() did not close the connection")
+	}
+	if err := topics["flowctl.execution.completed"].Send(ctx, &pubsub.Message{}); err == nil {
+		t.Error("topic is still open after Close()")
+	}
+}
@@ -0,0 +1,232 @@
+package flowstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gocloud.dev/blob"
+	"gocloud.dev/gcerrors"
+)
+
+// BucketStore keeps flows in object storage with a local copy in a cache directory.
+// Object keys are the paths of the files relative to the flows directory.
+type BucketStore struct {
+	bucket *blob.Bucket
+	dir    string
+}
+
+// NewBucketStore opens the bucket at bucketURL, a gocloud blob URL such as s3://bucket or
+// gs://bucket. A key prefix can be set with the prefix query parameter.
+func NewBucketStore(ctx context.Context, bucketURL string, cacheDir string) (*BucketStore, error) {
+	if cacheDir == "" {
+		return nil, errors.New("a cache directory is required to store flows in a bucket")
+	}
+
+	bucket, err := blob.OpenBucket(ctx, bucketURL)
+	if err != nil {
+		return nil, fmt.Errorf("could not open flows bucket: %w", err)
+	}
+
+	return &BucketStore{bucket: bucket, dir: cacheDir}, nil
+}
+
+func (b *BucketStore) Dir() string {
+	return b.dir
+}
+
+// Pull downloads the bucket into a staging directory and then replaces the cache directory
+// with it, so a failed download leaves the cache as it was.
+func (b *BucketStore) Pull(ctx context.Context) error {
+	stagingDir := b.dir + ".pull"
+	if err := os.RemoveAll(stagingDir); err != nil {
+		return fmt.Errorf("could not clear staging directory: %w", err)
+	}
+	if err := os.MkdirAll(stagingDir, 0755); err != nil {
+		return fmt.Errorf("could not create staging directory: %w", err)
+	}
+
+	iter := b.bucket.List(nil)
+	for {
+		obj, err := iter.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			os.RemoveAll(stagingDir)
+			return fmt.Errorf("could not list flows bucket: %w", err)
+		}
+
+		rel := filepath.FromSlash(obj.Key)
+		if obj.IsDir || !filepath.IsLocal(rel) {
+			continue
+		}
+
+		target := filepath.Join(stagingDir, rel)
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			os.RemoveAll(stagingDir)
+			return fmt.Errorf("could not create directory for %s: %w", obj.Key, err)
+		}
+		if err := b.download(ctx, obj.Key, target); err != nil {
+			os.RemoveAll(stagingDir)
+			return err
+		}
+	}
+
+	if err := os.RemoveAll(b.dir); err != nil {
+		os.RemoveAll(stagingDir)
+		return fmt.Errorf("could not clear cache directory: %w", err)
+	}
+	if err := os.Rename(stagingDir, b.dir); err != nil {
+		return fmt.Errorf("could not replace cache directory: %w", err)
+	}
+
+	return nil
+}
+
+// Push uploads the files under dir that changed and deletes the objects under dir whose
+// files were removed. Hidden directories at the top of the flows directory are used while
+// syncing namespaces and are not stored.
+func (b *BucketStore) Push(ctx context.Context, dir string) error {
+	prefix, err := b.keyPrefix(dir)
+	if err != nil {
+		return err
+	}
+
+	stored := make(map[string][]byte)
+	iter := b.bucket.List(&blob.ListOptions{Prefix: prefix})
+	for {
+		obj, err := iter.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("could not list flows bucket: %w", err)
+		}
+		if !obj.IsDir && !hiddenKey(obj.Key) {
+			stored[obj.Key] = obj.MD5
+		}
+	}
+
+	// A directory that doesn't exist was deleted, which deletes all its objects
+	if _, err := os.Stat(dir); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("could not push %s: %w", dir, err)
+	} else if err == nil {
+		if err := b.upload(ctx, dir, stored); err != nil {
+			return fmt.Errorf("could not push %s: %w", dir, err)
+		}
+	}
+
+	for key := range stored {
+		if err := b.bucket.Delete(ctx, key); err != nil && gcerrors.Code(err) != gcerrors.NotFound {
+			return fmt.Errorf("could not delete %s: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+// upload uploads the files under dir whose contents differ from the stored objects. Uploaded
+// and unchanged files are removed from stored, leaving the objects that have no local file.
+func (b *BucketStore) upload(ctx context.Context, dir string, stored map[string][]byte) error {
+	return filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(b.dir, p)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if hiddenKey(key) {
+			return nil
+		}
+
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+
+		storedMD5, ok := stored[key]
+		delete(stored, key)
+		if sum := md5.Sum(data); ok && bytes.Equal(storedMD5, sum[:]) {
+			return nil
+		}
+
+		if err := b.bucket.WriteAll(ctx, key, data, nil); err != nil {
+			return fmt.Errorf("could not upload %s: %w", key, err)
+		}
+		return nil
+	})
+}
+
+// CopyFiles downloads the top-level files of the flow directory from the bucket, so it
+// works on hosts whose cache is out of date
+func (b *BucketStore) CopyFiles(ctx context.Context, dir string, dst string) error {
+	prefix, err := b.keyPrefix(dir)
+	if err != nil {
+		return err
+	}
+
+	iter := b.bucket.List(&blob.ListOptions{Prefix: prefix, Delimiter: "/"})
+	for {
+		obj, err := iter.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("could not list flow directory: %w", err)
+		}
+		if obj.IsDir {
+			continue
+		}
+
+		if err := b.download(ctx, obj.Key, filepath.Join(dst, path.Base(obj.Key))); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (b *BucketStore) Close() error {
+	return b.bucket.Close()
+}
+
+// keyPrefix returns the prefix of the keys of the files under dir
+func (b *BucketStore) keyPrefix(dir string) (string, error) {
+	rel, err := filepath.Rel(b.dir, dir)
+	if err != nil || !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("%s is outside the flows directory", dir)
+	}
+	if rel == "." {
+		return "", nil
+	}
+	return filepath.ToSlash(rel) + "/", nil
+}
+
+func (b *BucketStore) download(ctx context.Context, key string, target string) error {
+	r, err := b.bucket.NewReader(ctx, key, nil)
+	if err != nil {
+		return fmt.Errorf("could not read %s: %w", key, err)
+	}
+	defer r.Close()
+
+	return writeFile(target, r)
+}
+
+func hiddenKey(key string) bool {
+	return strings.HasPrefix(key, ".")
+}
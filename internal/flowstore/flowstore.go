@@ -0,0 +1,117 @@
+// Package flowstore stores flow directories either on the local filesystem or in object
+// storage. Flows are always read from a local directory. When they are stored in a bucket,
+// the local directory is a cache of the bucket that is refreshed with Pull, and changes made
+// to it are written back with Push.
+package flowstore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Store is where flow directories are kept
+type Store interface {
+	// Dir is the local directory flows are read from and written to
+	Dir() string
+
+	// Pull replaces the contents of Dir with the stored flows
+	Pull(ctx context.Context) error
+
+	// Push stores the files in dir, which is Dir or a directory in it. Stored files under dir
+	// that no longer exist locally are removed, so pushing a deleted directory deletes it.
+	Push(ctx context.Context, dir string) error
+
+	// CopyFiles copies the top-level files of the flow directory dir to dst
+	CopyFiles(ctx context.Context, dir string, dst string) error
+
+	Close() error
+}
+
+// IsBucketURL reports whether location is a gocloud blob URL (s3://, gs://, ...) instead of
+// a local directory
+func IsBucketURL(location string) bool {
+	return strings.Contains(location, "://")
+}
+
+// Open returns the store for location. A bucket URL is mirrored into cacheDir, any other
+// location is used as the local flows directory.
+func Open(ctx context.Context, location string, cacheDir string) (Store, error) {
+	if !IsBucketURL(location) {
+		return NewLocalStore(location), nil
+	}
+
+	return NewBucketStore(ctx, location, cacheDir)
+}
+
+// LocalStore keeps flows in a directory on the local filesystem
+type LocalStore struct {
+	dir string
+}
+
+func NewLocalStore(dir string) *LocalStore {
+	return &LocalStore{dir: dir}
+}
+
+func (l *LocalStore) Dir() string {
+	return l.dir
+}
+
+// Pull is a no-op since the local directory is the store
+func (l *LocalStore) Pull(ctx context.Context) error {
+	return nil
+}
+
+// Push is a no-op since the local directory is the store
+func (l *LocalStore) Push(ctx context.Context, dir string) error {
+	return nil
+}
+
+func (l *LocalStore) CopyFiles(ctx context.Context, dir string, dst string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read flow directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		// Skip directories, only copy top-level files
+		if entry.IsDir() {
+			continue
+		}
+
+		src, err := os.Open(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return fmt.Errorf("failed to open source file %s: %w", entry.Name(), err)
+		}
+
+		err = writeFile(filepath.Join(dst, entry.Name()), src)
+		src.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (l *LocalStore) Close() error {
+	return nil
+}
+
+// writeFile creates path with the contents of r
+func writeFile(path string, r io.Reader) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create file %s: %w", path, err)
+	}
+
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write file %s: %w", path, err)
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,309 @@
+// Package gitsync keeps the flows of namespaces in sync with git repositories. Each repository
+// is cloned into a working directory, polled for new commits, and can be pulled on demand when
+// a push webhook is received.
+package gitsync
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cvhariharan/flowctl/internal/config"
+)
+
+const (
+	// DefaultPollInterval is used for repositories without a poll interval
+	DefaultPollInterval = time.Minute
+	// gitTimeout limits how long a single git command can run
+	gitTimeout = 5 * time.Minute
+)
+
+var ErrUnknownNamespace = errors.New("namespace is not synced from git")
+
+// SyncFn replaces the flows of namespace with the flows in dir, checked out at commit
+type SyncFn func(ctx context.Context, namespace string, dir string, commit string) error
+
+// Options holds the dependencies of a Syncer
+type Options struct {
+	Sync   SyncFn
+	Logger *slog.Logger
+}
+
+type repoState struct {
+	cfg     config.GitRepoConfig
+	dir     string
+	trigger chan struct{}
+
+	mu         sync.Mutex
+	lastCommit string
+	lastSync   time.Time
+	lastErr    error
+}
+
+// Status is the sync state of a repository
+type Status struct {
+	Namespace  string
+	Branch     string
+	LastCommit string
+	LastSync   time.Time
+	LastError  string
+}
+
+// Syncer syncs the flows of each configured namespace from its repository
+type Syncer struct {
+	repos map[string]*repoState
+	opts  Options
+}
+
+// NewSyncer creates a Syncer for the repositories in cfg. Nothing is synced until Run is called.
+func NewSyncer(cfg config.GitSyncConfig, opts Options) (*Syncer, error) {
+	if opts.Sync == nil {
+		return nil, errors.New("sync function is required")
+	}
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+	if len(cfg.Repos) > 0 {
+		if _, err := exec.LookPath("git"); err != nil {
+			return nil, fmt.Errorf("git is required to sync flows: %w", err)
+		}
+	}
+
+	s := &Syncer{
+		repos: make(map[string]*repoState, len(cfg.Repos)),
+		opts:  opts,
+	}
+	for _, r := range cfg.Repos {
+		if r.PollInterval == 0 {
+			r.PollInterval = DefaultPollInterval
+		}
+		s.repos[r.Namespace] = &repoState{
+			cfg:     r,
+			dir:     filepath.Join(cfg.WorkDirectory, r.Namespace),
+			trigger: make(chan struct{}, 1),
+		}
+	}
+
+	return s, nil
+}
+
+// Run syncs every repository once and then whenever it is polled or triggered, until ctx is done
+func (s *Syncer) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, r := range s.repos {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.runRepo(ctx, r)
+		}()
+	}
+	wg.Wait()
+}
+
+// Trigger schedules a sync of the repository of namespace. Triggers received while a sync
+// is pending are merged.
+func (s *Syncer) Trigger(namespace string) error {
+	r, ok := s.repos[namespace]
+	if !ok {
+		return ErrUnknownNamespace
+	}
+
+	select {
+	case r.trigger <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Repo returns the configuration of the repository of namespace
+func (s *Syncer) Repo(namespace string) (config.GitRepoConfig, bool) {
+	r, ok := s.repos[namespace]
+	if !ok {
+		return config.GitRepoConfig{}, false
+	}
+	return r.cfg, true
+}
+
+// Status returns the sync state of the repository of namespace
+func (s *Syncer) Status(namespace string) (Status, error) {
+	r, ok := s.repos[namespace]
+	if !ok {
+		return Status{}, ErrUnknownNamespace
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	status := Status{
+		Namespace:  namespace,
+		Branch:     r.cfg.Branch,
+		LastCommit: r.lastCommit,
+		LastSync:   r.lastSync,
+	}
+	if r.lastErr != nil {
+		status.LastError = r.lastErr.Error()
+	}
+	return status, nil
+}
+
+func (s *Syncer) runRepo(ctx context.Context, r *repoState) {
+	logger := s.opts.Logger.With("namespace", r.cfg.Namespace, "url", redactURL(r.cfg.URL))
+
+	ticker := time.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		err := s.syncRepo(ctx, r, logger)
+		if err != nil && ctx.Err() == nil {
+			logger.Error("failed to sync flows from git", "error", err)
+		}
+
+		r.mu.Lock()
+		r.lastErr = err
+		r.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-r.trigger:
+		}
+	}
+}
+
+// syncRepo pulls the repository and syncs the flows if the commit changed since the last sync
+func (s *Syncer) syncRepo(ctx context.Context, r *repoState, logger *slog.Logger) error {
+	commit, err := s.pull(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	unchanged := commit == r.lastCommit
+	r.mu.Unlock()
+	if unchanged {
+		return nil
+	}
+
+	flowsDir := r.dir
+	if r.cfg.Path != "" {
+		flowsDir = filepath.Join(r.dir, filepath.Clean("/"+r.cfg.Path))
+	}
+	if info, err := os.Stat(flowsDir); err != nil || !info.IsDir() {
+		return fmt.Errorf("path %s does not exist in the repository", r.cfg.Path)
+	}
+
+	if err := s.opts.Sync(ctx, r.cfg.Namespace, flowsDir, commit); err != nil {
+		return fmt.Errorf("could not sync flows: %w", err)
+	}
+
+	r.mu.Lock()
+	r.lastCommit = commit
+	r.lastSync = time.Now()
+	r.mu.Unlock()
+
+	logger.Info("synced flows from git", "commit", commit)
+	return nil
+}
+
+// pull clones the repository or fetches the latest commit of the branch, and returns the
+// commit that is checked out
+func (s *Syncer) pull(ctx context.Context, r *repoState) (string, error) {
+	if _, err := os.Stat(filepath.Join(r.dir, ".git")); err != nil {
+		if err := os.RemoveAll(r.dir); err != nil {
+			return "", err
+		}
+		if err := os.MkdirAll(filepath.Dir(r.dir), 0755); err != nil {
+			return "", err
+		}
+
+		args := []string{"clone", "--depth", "1", "--single-branch"}
+		if r.cfg.Branch != "" {
+			args = append(args, "--branch", r.cfg.Branch)
+		}
+		args = append(args, "--", r.cfg.URL, r.dir)
+		if _, err := s.git(ctx, r, "", args...); err != nil {
+			return "", fmt.Errorf("could not clone repository: %w", err)
+		}
+	} else {
+		ref := "HEAD"
+		if r.cfg.Branch != "" {
+			ref = r.cfg.Branch
+		}
+		// The url might have changed in the config since the repository was cloned
+		if _, err := s.git(ctx, r, r.dir, "remote", "set-url", "origin", r.cfg.URL); err != nil {
+			return "", fmt.Errorf("could not set repository url: %w", err)
+		}
+		if _, err := s.git(ctx, r, r.dir, "fetch", "--depth", "1", "origin", ref); err != nil {
+			return "", fmt.Errorf("could not fetch repository: %w", err)
+		}
+		if _, err := s.git(ctx, r, r.dir, "reset", "--hard", "FETCH_HEAD"); err != nil {
+			return "", fmt.Errorf("could not check out %s: %w", ref, err)
+		}
+		if _, err := s.git(ctx, r, r.dir, "clean", "-ffdx"); err != nil {
+			return "", fmt.Errorf("could not clean working tree: %w", err)
+		}
+	}
+
+	commit, err := s.git(ctx, r, r.dir, "rev-parse", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("could not get commit: %w", err)
+	}
+	return commit, nil
+}
+
+// git runs a git command in dir and returns its trimmed output
+func (s *Syncer) git(ctx context.Context, r *repoState, dir string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, gitTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	// Never prompt for credentials, they have to be part of the url or the ssh key
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if r.cfg.SSHKeyPath != "" {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("GIT_SSH_COMMAND=ssh -i %s -o IdentitiesOnly=yes -o StrictHostKeyChecking=accept-new", shellQuote(r.cfg.SSHKeyPath)))
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			return "", err
+		}
+		// The url can contain credentials and is part of most git errors
+		msg = strings.ReplaceAll(msg, r.cfg.URL, redactURL(r.cfg.URL))
+		return "", fmt.Errorf("%w: %s", err, msg)
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// redactURL removes credentials from a repository url. A user without a password is
+// redacted too since tokens are often passed as the user.
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.User == nil {
+		return rawURL
+	}
+	if _, ok := u.User.Password(); !ok {
+		u.User = url.User("xxxxx")
+		return u.String()
+	}
+	return u.Redacted()
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
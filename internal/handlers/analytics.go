@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/labstack/echo/v4"
+)
+
+var analyticsWindows = map[string]time.Duration{
+	"24h": 24 * time.Hour,
+	"7d":  7 * 24 * time.Hour,
+	"30d": 30 * 24 * time.Hour,
+	"90d": 90 * 24 * time.Hour,
+}
+
+func (h *Handler) HandleGetFailureAnalytics(c echo.Context) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	var req FailureAnalyticsReq
+	if err := c.Bind(&req); err != nil {
+		return wrapError(ErrInvalidInput, "invalid request", err, nil)
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return wrapError(ErrValidationFailed, fmt.Sprintf("request validation failed: %s", formatValidationErrors(err)), err, nil)
+	}
+
+	user, err := h.getUserInfo(c)
+	if err != nil {
+		return wrapError(ErrForbidden, "could not get user info", err, nil)
+	}
+
+	// The analytics include the errors of every execution in the namespace
+	restricted, err := h.isUserOnly(c.Request().Context(), user.ID, namespace)
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not determine user role", err, nil)
+	}
+	if restricted {
+		return wrapError(ErrForbidden, "insufficient permissions", nil, nil)
+	}
+
+	query := models.FailureAnalyticsQuery{
+		To:    time.Now(),
+		Limit: req.Limit,
+	}
+
+	if req.To != "" {
+		query.To, err = time.Parse(time.RFC3339, req.To)
+		if err != nil {
+			return wrapError(ErrValidationFailed, "invalid to format, expected RFC3339", err, nil)
+		}
+	}
+
+	switch {
+	case req.From != "":
+		query.From, err = time.Parse(time.RFC3339, req.From)
+		if err != nil {
+			return wrapError(ErrValidationFailed, "invalid from format, expected RFC3339", err, nil)
+		}
+	case req.Window != "":
+		query.From = query.To.Add(-analyticsWindows[req.Window])
+	default:
+		query.From = query.To.Add(-models.FailureAnalyticsDefaultWindow)
+	}
+
+	if !query.From.Before(query.To) {
+		return wrapError(ErrValidationFailed, "from should be before to", nil, nil)
+	}
+	if query.To.Sub(query.From) > models.FailureAnalyticsMaxWindow {
+		return wrapError(ErrValidationFailed, "window cannot be longer than 90 days", nil, nil)
+	}
+
+	analytics, err := h.co.GetFailureAnalytics(c.Request().Context(), namespace, query)
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not get failure analytics", err, nil)
+	}
+
+	return c.JSON(http.StatusOK, coreFailureAnalyticsToResp(analytics))
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (h *Handler) HandleListAPITokens(c echo.Context) error {
+	user, err := h.getUserInfo(c)
+	if err != nil {
+		return wrapError(ErrAuthenticationFailed, "could not get user details", err, nil)
+	}
+
+	tokens, err := h.co.ListAPITokens(c.Request().Context(), user.ID)
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not list API tokens", err, nil)
+	}
+
+	resp := make([]APITokenResp, 0, len(tokens))
+	for _, t := range tokens {
+		resp = append(resp, coreAPITokenToResp(t))
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *Handler) HandleCreateAPIToken(c echo.Context) error {
+	// Tokens can only be created from a login session so that a leaked token can't be used to mint more
+	if isAPIToken, _ := c.Get("is_api_token").(bool); isAPIToken {
+		return wrapError(ErrForbidden, "API tokens cannot be used to create API tokens", nil, nil)
+	}
+
+	var req APITokenReq
+	if err := c.Bind(&req); err != nil {
+		return wrapError(ErrInvalidInput, "could not decode request", err, nil)
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return wrapError(ErrValidationFailed, fmt.Sprintf("request validation failed: %s", formatValidationErrors(err)), err, nil)
+	}
+
+	user, err := h.getUserInfo(c)
+	if err != nil {
+		return wrapError(ErrAuthenticationFailed, "could not get user details", err, nil)
+	}
+
+	created, token, err := h.co.CreateAPIToken(c.Request().Context(), user.ID, strings.TrimSpace(req.Name), req.ExpiresAt)
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not create API token", err, nil)
+	}
+
+	return c.JSON(http.StatusCreated, APITokenCreateResp{
+		APITokenResp: coreAPITokenToResp(created),
+		Token:        token,
+	})
+}
+
+func (h *Handler) HandleDeleteAPIToken(c echo.Context) error {
+	var req APITokenGetReq
+	if err := c.Bind(&req); err != nil {
+		return wrapError(ErrInvalidInput, "could not decode request", err, nil)
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return wrapError(ErrValidationFailed, fmt.Sprintf("request validation failed: %s", formatValidationErrors(err)), err, nil)
+	}
+
+	user, err := h.getUserInfo(c)
+	if err != nil {
+		return wrapError(ErrAuthenticationFailed, "could not get user details", err, nil)
+	}
+
+	if err := h.co.DeleteAPIToken(c.Request().Context(), req.TokenID, user.ID); err != nil {
+		return wrapError(ErrOperationFailed, "could not delete API token", err, nil)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
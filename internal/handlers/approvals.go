@@ -1,10 +1,14 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 
+	"github.com/cvhariharan/flowctl/internal/core"
 	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/cvhariharan/flowctl/internal/securitylog"
 	"github.com/labstack/echo/v4"
 )
 
@@ -18,6 +22,7 @@ func (h *Handler) HandleApprovalAction(c echo.Context) error {
 	if err := c.Bind(&req); err != nil {
 		return wrapError(ErrInvalidInput, "invalid request", err, nil)
 	}
+	req.Comment = strings.TrimSpace(req.Comment)
 
 	if err := h.validate.Struct(req); err != nil {
 		return wrapError(ErrValidationFailed, fmt.Sprintf("request validation failed: %s", formatValidationErrors(err)), err, nil)
@@ -38,10 +43,22 @@ func (h *Handler) HandleApprovalAction(c echo.Context) error {
 		message = "The request has been rejected."
 	}
 
-	err = h.co.ApproveOrRejectAction(c.Request().Context(), req.ApprovalID, user.ID, status, namespace)
+	err = h.co.ApproveOrRejectAction(c.Request().Context(), req.ApprovalID, user.ID, status, req.Comment, namespace)
+	h.logSecurityEvent(c, securitylog.TypeApprovalDecision, err, map[string]any{
+		"approval_id": req.ApprovalID,
+		"decision":    string(status),
+		"comment":     req.Comment,
+	})
+	if errors.Is(err, core.ErrNotApprover) {
+		return wrapError(ErrForbidden, "you are not an approver of this action", err, nil)
+	}
 	if err != nil {
 		return wrapError(ErrOperationFailed, "could not process approval action", err, nil)
 	}
+	// Only pending approvals can be decided
+	h.audit(c, models.AuditApprovalDecide, models.ResourceApproval, req.ApprovalID,
+		map[string]string{"status": string(models.ApprovalStatusPending)},
+		map[string]string{"status": string(status), "comment": req.Comment})
 
 	return c.JSON(http.StatusOK, ApprovalActionResp{
 		ID:      req.ApprovalID,
@@ -77,9 +94,11 @@ func (h *Handler) HandleGetApproval(c echo.Context) error {
 		ExecID:      approval.ExecID,
 		Inputs:      approval.Inputs,
 		DecidedBy:   approval.DecidedBy,
+		Comment:     approval.Comment,
 		FlowName:    approval.FlowName,
 		FlowID:      approval.FlowID,
 		RequestedBy: approval.RequestedBy,
+		Assignees:   approval.Assignees,
 		CreatedAt:   approval.CreatedAt,
 		UpdatedAt:   approval.UpdatedAt,
 	}
@@ -139,3 +158,86 @@ func (h *Handler) HandleListApprovals(c echo.Context) error {
 		TotalCount: totalCount,
 	})
 }
+
+func (h *Handler) HandleListApprovalDelegations(c echo.Context) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	delegations, err := h.co.ListApprovalDelegations(c.Request().Context(), namespace)
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not list approval delegations", err, nil)
+	}
+
+	resp := make([]ApprovalDelegationResp, 0, len(delegations))
+	for _, d := range delegations {
+		resp = append(resp, coreApprovalDelegationToResp(d))
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *Handler) HandleCreateApprovalDelegation(c echo.Context) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	var req ApprovalDelegationReq
+	if err := c.Bind(&req); err != nil {
+		return wrapError(ErrInvalidInput, "could not decode request", err, nil)
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return wrapError(ErrValidationFailed, fmt.Sprintf("request validation failed: %s", formatValidationErrors(err)), err, nil)
+	}
+
+	user, err := h.getUserInfo(c)
+	if err != nil {
+		return wrapError(ErrAuthenticationFailed, "could not get user details", err, nil)
+	}
+
+	created, err := h.co.CreateApprovalDelegation(c.Request().Context(), models.ApprovalDelegation{
+		DelegatorID:   user.ID,
+		DelegatorName: user.Name,
+		DelegateType:  req.DelegateType,
+		DelegateID:    req.DelegateID,
+		StartsAt:      req.StartsAt,
+		EndsAt:        req.EndsAt,
+		Reason:        strings.TrimSpace(req.Reason),
+	}, namespace)
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not create approval delegation", err, nil)
+	}
+
+	return c.JSON(http.StatusCreated, coreApprovalDelegationToResp(created))
+}
+
+func (h *Handler) HandleDeleteApprovalDelegation(c echo.Context) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	var req ApprovalDelegationGetReq
+	if err := c.Bind(&req); err != nil {
+		return wrapError(ErrInvalidInput, "could not decode request", err, nil)
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return wrapError(ErrValidationFailed, fmt.Sprintf("request validation failed: %s", formatValidationErrors(err)), err, nil)
+	}
+
+	user, err := h.getUserInfo(c)
+	if err != nil {
+		return wrapError(ErrAuthenticationFailed, "could not get user details", err, nil)
+	}
+
+	err = h.co.DeleteApprovalDelegation(c.Request().Context(), req.DelegationID, user.ID, namespace)
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not delete approval delegation", err, nil)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (h *Handler) HandleArchivedExecutionsPagination(c echo.Context) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	var req PaginateRequest
+	if err := c.Bind(&req); err != nil {
+		return wrapError(ErrInvalidInput, "invalid request", err, nil)
+	}
+
+	if req.Page < 0 || req.Count < 0 {
+		return wrapError(ErrInvalidPagination, "invalid request, page or count per page cannot be less than 0", fmt.Errorf("page and count per page less than zero"), nil)
+	}
+
+	if req.Page > 0 {
+		req.Page -= 1
+	}
+
+	if req.Count == 0 {
+		req.Count = CountPerPage
+	}
+
+	userInfo, err := h.getUserInfo(c)
+	if err != nil {
+		return wrapError(ErrForbidden, "could not get user info", err, nil)
+	}
+
+	// Users only see the executions they triggered
+	restricted, err := h.isUserOnly(c.Request().Context(), userInfo.ID, namespace)
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not determine user role", err, nil)
+	}
+	var triggeredBy string
+	if restricted {
+		triggeredBy = userInfo.ID
+	}
+
+	executions, pageCount, totalCount, err := h.co.GetArchivedExecutionsPaginated(c.Request().Context(), namespace, triggeredBy, req.Filter, req.Count, req.Count*req.Page)
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not get archived executions", err, nil)
+	}
+
+	executionItems := make([]ExecutionSummary, len(executions))
+	for i, exec := range executions {
+		executionItems[i] = coreExecutionSummaryToExecutionSummary(exec)
+	}
+
+	return c.JSON(http.StatusOK, ExecutionsPaginateResponse{
+		Executions: executionItems,
+		PageCount:  pageCount,
+		TotalCount: totalCount,
+	})
+}
+
+func (h *Handler) HandleGetArchivedExecution(c echo.Context) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	var req ExecutionGetReq
+	if err := c.Bind(&req); err != nil {
+		return wrapError(ErrInvalidInput, "could not decode request", err, nil)
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return wrapError(ErrValidationFailed, fmt.Sprintf("request validation failed: %s", formatValidationErrors(err)), err, nil)
+	}
+
+	execSummary, err := h.co.GetArchivedExecution(c.Request().Context(), req.ExecID, namespace)
+	if err != nil {
+		return wrapError(ErrResourceNotFound, "archived execution not found", err, nil)
+	}
+
+	userInfo, err := h.getUserInfo(c)
+	if err != nil {
+		return wrapError(ErrForbidden, "could not get user info", err, nil)
+	}
+
+	restricted, err := h.isUserOnly(c.Request().Context(), userInfo.ID, namespace)
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not determine user role", err, nil)
+	}
+	if restricted && execSummary.TriggeredByID != userInfo.ID {
+		return wrapError(ErrForbidden, "insufficient permissions", nil, nil)
+	}
+
+	return c.JSON(http.StatusOK, coreExecutionSummaryToExecutionSummary(execSummary))
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+
+	"github.com/cvhariharan/flowctl/internal/core"
+	"github.com/labstack/echo/v4"
+)
+
+// HandleListExecutionArtifacts returns the files published by the actions of an execution along
+// with the endpoints that download them
+func (h *Handler) HandleListExecutionArtifacts(c echo.Context) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	var req ExecutionGetReq
+	if err := c.Bind(&req); err != nil {
+		return wrapError(ErrInvalidInput, "could not decode request", err, nil)
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return wrapError(ErrValidationFailed, fmt.Sprintf("request validation failed: %s", formatValidationErrors(err)), err, nil)
+	}
+
+	if err := h.checkExecutionAccess(c, req.ExecID, namespace); err != nil {
+		return err
+	}
+
+	artifacts, err := h.co.ListArtifacts(c.Request().Context(), req.ExecID, namespace)
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not get execution artifacts", err, nil)
+	}
+
+	resp := ExecutionArtifactsResp{ExecID: req.ExecID, Artifacts: make([]ArtifactResp, 0, len(artifacts))}
+	for _, a := range artifacts {
+		resp.Artifacts = append(resp.Artifacts, ArtifactResp{
+			ID:        a.ID,
+			ActionID:  a.ActionID,
+			Name:      a.Name,
+			Size:      a.Size,
+			CreatedAt: a.CreatedAt.Format(TimeFormat),
+			URL:       path.Join(c.Request().URL.Path, a.ID),
+		})
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// HandleDownloadArtifact redirects to a short-lived signed URL of an artifact in the archive, or
+// streams the artifact when the archive can't sign URLs
+func (h *Handler) HandleDownloadArtifact(c echo.Context) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	var req ArtifactGetReq
+	if err := c.Bind(&req); err != nil {
+		return wrapError(ErrInvalidInput, "could not decode request", err, nil)
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return wrapError(ErrValidationFailed, fmt.Sprintf("request validation failed: %s", formatValidationErrors(err)), err, nil)
+	}
+
+	if err := h.checkExecutionAccess(c, req.ExecID, namespace); err != nil {
+		return err
+	}
+
+	download, err := h.co.DownloadArtifact(c.Request().Context(), req.ExecID, req.ArtifactID, namespace)
+	if errors.Is(err, core.ErrArtifactNotFound) {
+		return wrapError(ErrResourceNotFound, "artifact not found", err, nil)
+	}
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not download artifact", err, nil)
+	}
+
+	if download.Reader == nil {
+		return c.Redirect(http.StatusFound, download.URL)
+	}
+	defer download.Reader.Close()
+
+	c.Response().Header().Set("Content-Type", "application/octet-stream")
+	c.Response().Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": path.Base(download.Artifact.Name)}))
+	c.Response().Header().Set("Content-Length", strconv.FormatInt(download.Artifact.Size, 10))
+	c.Response().WriteHeader(http.StatusOK)
+
+	if _, err := io.Copy(c.Response(), download.Reader); err != nil {
+		h.logger.Error("artifact download error", "execID", req.ExecID, "artifactID", req.ArtifactID, "error", err)
+		return err
+	}
+	return nil
+}
+
+// checkExecutionAccess returns an error if the execution doesn't exist in the namespace or the
+// current user can only see the executions they triggered and didn't trigger it
+func (h *Handler) checkExecutionAccess(c echo.Context, execID string, namespace string) error {
+	execSummary, err := h.co.GetExecutionSummaryByExecID(c.Request().Context(), execID, namespace)
+	if err != nil {
+		return wrapError(ErrResourceNotFound, "execution not found", err, nil)
+	}
+
+	userInfo, err := h.getUserInfo(c)
+	if err != nil {
+		return wrapError(ErrForbidden, "could not get user info", err, nil)
+	}
+
+	restricted, err := h.isUserOnly(c.Request().Context(), userInfo.ID, namespace)
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not determine user role", err, nil)
+	}
+	if restricted && execSummary.TriggeredByID != userInfo.ID {
+		return wrapError(ErrForbidden, "insufficient permissions", nil, nil)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/labstack/echo/v4"
+)
+
+// audit records a change made by the request in the audit log. before and after are snapshots
+// of the resource and must not contain secret values, nil if the resource didn't exist. A
+// change that can't be recorded is logged and doesn't fail the request.
+func (h *Handler) audit(c echo.Context, action string, resourceType models.Resource, resourceID string, before, after any) {
+	user, ok := c.Get("user").(models.UserInfo)
+	if !ok {
+		h.logger.Error("could not record audit entry without a user", "action", action, "resourceID", resourceID)
+		return
+	}
+
+	namespace, _ := c.Get("namespace").(string)
+	e := models.AuditEntry{
+		NamespaceID:  namespace,
+		ActorID:      user.ID,
+		ActorName:    user.Username,
+		Action:       action,
+		ResourceType: string(resourceType),
+		ResourceID:   resourceID,
+		Before:       before,
+		After:        after,
+		SourceIP:     c.RealIP(),
+	}
+
+	// The change is recorded even if the client has gone away
+	if err := h.co.RecordAudit(context.WithoutCancel(c.Request().Context()), e); err != nil {
+		h.logger.Error("could not record audit entry", "action", action, "resourceID", resourceID, "error", err)
+	}
+}
+
+func (h *Handler) HandleListAuditLog(c echo.Context) error {
+	return h.listAuditLog(c, "")
+}
+
+func (h *Handler) HandleListNamespaceAuditLog(c echo.Context) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	return h.listAuditLog(c, namespace)
+}
+
+func (h *Handler) listAuditLog(c echo.Context, namespace string) error {
+	var req PaginateRequest
+	if err := c.Bind(&req); err != nil {
+		return wrapError(ErrInvalidInput, "invalid request", err, nil)
+	}
+
+	if req.Page < 0 || req.Count < 0 {
+		return wrapError(ErrInvalidPagination, "invalid request, page or count per page cannot be less than 0", fmt.Errorf("page and count per page less than zero"), nil)
+	}
+
+	if req.Page > 0 {
+		req.Page -= 1
+	}
+
+	if req.Count == 0 {
+		req.Count = CountPerPage
+	}
+
+	entries, pageCount, totalCount, err := h.co.ListAuditLog(c.Request().Context(), namespace, req.Filter, req.Count, req.Count*req.Page)
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not get audit log", err, nil)
+	}
+
+	items := make([]AuditEntryResp, len(entries))
+	for i, e := range entries {
+		items[i] = coreAuditEntryToAuditEntryResp(e)
+	}
+
+	return c.JSON(http.StatusOK, AuditLogPaginateResponse{
+		Entries:    items,
+		PageCount:  pageCount,
+		TotalCount: totalCount,
+	})
+}
+
+// namespaceMember returns the member of a namespace that match selects as an audit snapshot, nil
+// if there is no such member
+func (h *Handler) namespaceMember(ctx context.Context, namespace string, match func(NamespaceMemberResp) bool) any {
+	members, err := h.co.GetNamespaceMembers(ctx, namespace)
+	if err != nil {
+		h.logger.Error("could not get namespace members", "namespace", namespace, "error", err)
+		return nil
+	}
+
+	for _, m := range coreNamespaceMembersToResp(members).Members {
+		if match(m) {
+			return m
+		}
+	}
+	return nil
+}
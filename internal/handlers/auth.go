@@ -5,6 +5,7 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -14,6 +15,7 @@ import (
 	"github.com/coreos/go-oidc/v3/oidc"
 	"github.com/cvhariharan/flowctl/internal/config"
 	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/cvhariharan/flowctl/internal/securitylog"
 	"github.com/labstack/echo/v4"
 	"github.com/zerodha/simplesessions/v3"
 	"golang.org/x/oauth2"
@@ -160,15 +162,18 @@ func (h *Handler) HandleLoginPage(c echo.Context) error {
 
 	user, err := h.co.GetUserByUsernameWithGroups(c.Request().Context(), req.Username)
 	if err != nil {
+		h.logLogin(c, req.Username, "password", errors.New("unknown user"))
 		return wrapError(ErrAuthenticationFailed, "could not authenticate user", err, nil)
 	}
 
 	// not using password based login
 	if user.LoginType != models.StandardLoginType {
+		h.logLogin(c, req.Username, "password", errors.New("invalid authentication method"))
 		return wrapError(ErrAuthenticationFailed, "invalid authentication method", fmt.Errorf("invalid authentication method for user: %s", user.ID), nil)
 	}
 
 	if err := user.CheckPassword(req.Password); err != nil {
+		h.logLogin(c, req.Username, "password", errors.New("invalid password"))
 		return wrapError(ErrInvalidCredentials, "invalid credentials", err, nil)
 	}
 
@@ -180,6 +185,7 @@ func (h *Handler) HandleLoginPage(c echo.Context) error {
 	}
 
 	sess.Set("user", user.ToUserInfo())
+	h.logLogin(c, req.Username, "password", nil)
 
 	redirectAfterLogin := RedirectAfterLogin
 	if redirectURL := c.QueryParam("redirect_url"); redirectURL != "" && isSafeRedirect(redirectURL) {
@@ -280,6 +286,7 @@ func (h *Handler) HandleAuthCallback(c echo.Context) error {
 
 	idToken, err := h.authconfig[sessionState.Provider].verifier.Verify(context.Background(), rawIDToken)
 	if err != nil {
+		h.logLogin(c, "", "oidc:"+sessionState.Provider, errors.New("invalid ID token"))
 		return wrapError(ErrOperationFailed, "failed to verify ID token", err, nil)
 	}
 
@@ -296,6 +303,7 @@ func (h *Handler) HandleAuthCallback(c echo.Context) error {
 	if err != nil {
 		user, err = h.autoCreateOIDCUser(c.Request().Context(), sessionState.Provider, claims.Email, claims.Name)
 		if err != nil {
+			h.logLogin(c, claims.Email, "oidc:"+sessionState.Provider, err)
 			return wrapError(ErrForbidden, err.Error(), err, nil)
 		}
 	}
@@ -314,6 +322,7 @@ func (h *Handler) HandleAuthCallback(c echo.Context) error {
 	sess.Set("id_token", tokenData)
 
 	sess.Set("user", user.ToUserInfo())
+	h.logLogin(c, claims.Email, "oidc:"+sessionState.Provider, nil)
 
 	redirectAfterLogin := RedirectAfterLogin
 	if redirectURL, err := sess.Get("redirect_url"); err == nil && redirectURL != nil {
@@ -397,10 +406,15 @@ func (h *Handler) HandleLogout(c echo.Context) error {
 		return c.NoContent(http.StatusOK)
 	}
 
+	if user, err := h.getUserInfo(c); err == nil {
+		c.Set("user", user)
+	}
+
 	err = sess.Destroy()
 	if err != nil {
 		return wrapError(ErrInternalError, "could not destroy session", err, nil)
 	}
+	h.logSecurityEvent(c, securitylog.TypeLogout, nil, nil)
 
 	return c.NoContent(http.StatusOK)
 }
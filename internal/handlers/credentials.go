@@ -34,7 +34,10 @@ func (h *Handler) HandleCreateCredential(c echo.Context) error {
 		return wrapError(ErrOperationFailed, "could not create credential", err, nil)
 	}
 
-	return c.JSON(http.StatusCreated, coreCredentialToCredentialResp(created))
+	resp := coreCredentialToCredentialResp(created)
+	h.audit(c, models.AuditCredentialCreate, models.ResourceCredential, resp.ID, nil, resp)
+
+	return c.JSON(http.StatusCreated, resp)
 }
 
 func (h *Handler) HandleGetCredential(c echo.Context) error {
@@ -110,6 +113,11 @@ func (h *Handler) HandleUpdateCredential(c echo.Context) error {
 		return wrapError(ErrValidationFailed, fmt.Sprintf("request validation failed: %s", formatValidationErrors(err)), err, nil)
 	}
 
+	existing, err := h.co.GetCredentialByID(c.Request().Context(), req.CredID, namespace)
+	if err != nil {
+		return wrapError(ErrResourceNotFound, "credential not found", err, nil)
+	}
+
 	cred := &models.Credential{
 		Name:    req.Name,
 		KeyType: req.KeyType,
@@ -121,7 +129,10 @@ func (h *Handler) HandleUpdateCredential(c echo.Context) error {
 		return wrapError(ErrOperationFailed, "could not update credential", err, nil)
 	}
 
-	return c.JSON(http.StatusOK, coreCredentialToCredentialResp(updated))
+	resp := coreCredentialToCredentialResp(updated)
+	h.audit(c, models.AuditCredentialUpdate, models.ResourceCredential, req.CredID, coreCredentialToCredentialResp(existing), resp)
+
+	return c.JSON(http.StatusOK, resp)
 }
 
 func (h *Handler) HandleDeleteCredential(c echo.Context) error {
@@ -139,10 +150,16 @@ func (h *Handler) HandleDeleteCredential(c echo.Context) error {
 		return wrapError(ErrValidationFailed, fmt.Sprintf("request validation failed: %s", formatValidationErrors(err)), err, nil)
 	}
 
-	err := h.co.DeleteCredential(c.Request().Context(), req.CredID, namespace)
+	existing, err := h.co.GetCredentialByID(c.Request().Context(), req.CredID, namespace)
+	if err != nil {
+		return wrapError(ErrResourceNotFound, "credential not found", err, nil)
+	}
+
+	err = h.co.DeleteCredential(c.Request().Context(), req.CredID, namespace)
 	if err != nil {
 		return wrapError(ErrOperationFailed, "could not delete credential", err, nil)
 	}
+	h.audit(c, models.AuditCredentialDelete, models.ResourceCredential, req.CredID, coreCredentialToCredentialResp(existing), nil)
 
 	return c.NoContent(http.StatusOK)
 }
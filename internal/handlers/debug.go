@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	runtimepprof "runtime/pprof"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+var startedAt = time.Now()
+
+func init() {
+	expvar.Publish("goroutines", expvar.Func(func() any {
+		return runtime.NumGoroutine()
+	}))
+}
+
+// DebugHandler serves the pprof profiles under /debug/pprof/ and the expvar variables at
+// /debug/vars. It must only be reachable by administrators.
+func DebugHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+	return mux
+}
+
+// HandleGetRuntimeStats returns a snapshot of the goroutine count and heap of the server
+func (h *Handler) HandleGetRuntimeStats(c echo.Context) error {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+
+	var lastGC string
+	if m.LastGC > 0 {
+		lastGC = time.Unix(0, int64(m.LastGC)).UTC().Format(time.RFC3339)
+	}
+
+	return c.JSON(http.StatusOK, RuntimeStatsResp{
+		Goroutines:     runtime.NumGoroutine(),
+		NumCPU:         runtime.NumCPU(),
+		GOMAXPROCS:     runtime.GOMAXPROCS(0),
+		UptimeSeconds:  time.Since(startedAt).Seconds(),
+		HeapAlloc:      m.HeapAlloc,
+		HeapInuse:      m.HeapInuse,
+		HeapObjects:    m.HeapObjects,
+		HeapReleased:   m.HeapReleased,
+		Sys:            m.Sys,
+		TotalAlloc:     m.TotalAlloc,
+		NumGC:          m.NumGC,
+		LastGC:         lastGC,
+		PauseTotalSecs: time.Duration(m.PauseTotalNs).Seconds(),
+	})
+}
+
+// HandleGetGoroutines returns the stack traces of all goroutines as text
+func (h *Handler) HandleGetGoroutines(c echo.Context) error {
+	c.Response().Header().Set(echo.HeaderContentType, echo.MIMETextPlainCharsetUTF8)
+	c.Response().WriteHeader(http.StatusOK)
+	return runtimepprof.Lookup("goroutine").WriteTo(c.Response(), 2)
+}
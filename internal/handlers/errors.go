@@ -5,6 +5,8 @@ import (
 	"runtime"
 	"strings"
 
+	"github.com/cvhariharan/flowctl/internal/i18n"
+	"github.com/cvhariharan/flowctl/internal/securitylog"
 	"github.com/labstack/echo/v4"
 )
 
@@ -27,6 +29,9 @@ const (
 	// Not found errors (404)
 	ErrResourceNotFound = "RESOURCE_NOT_FOUND"
 
+	// Quota errors (429 Too Many Requests)
+	ErrQuotaExceeded = "QUOTA_EXCEEDED"
+
 	// Server errors (500)
 	ErrOperationFailed = "OPERATION_FAILED"
 	ErrInternalError   = "INTERNAL_ERROR"
@@ -51,11 +56,17 @@ var errorCodeToHTTPStatus = map[string]int{
 	// Not found errors (404)
 	ErrResourceNotFound: http.StatusNotFound,
 
+	// Quota errors (429 Too Many Requests)
+	ErrQuotaExceeded: http.StatusTooManyRequests,
+
 	// Server errors (500)
 	ErrOperationFailed: http.StatusInternalServerError,
 	ErrInternalError:   http.StatusInternalServerError,
 }
 
+// messages localizes error messages to the language requested by the client
+var messages = i18n.MustNew()
+
 type HTTPError struct {
 	code      int
 	msg       string
@@ -129,19 +140,32 @@ func (h *Handler) ErrorHandler(err error, c echo.Context) {
 		"line", line,
 		"remote_ip", c.RealIP())
 
+	if errorCode == ErrForbidden {
+		e := h.securityEvent(c, securitylog.TypeAccessDenied, securitylog.OutcomeFailure)
+		e.Reason = msg
+		h.securityLog.Log(e)
+	}
+
+	acceptLanguage := c.Request().Header.Get("Accept-Language")
+	messageCode, localized := messages.Localize(msg, acceptLanguage)
+	c.Response().Header().Set("Content-Language", messages.Match(acceptLanguage).String())
+
 	if strings.Contains(c.Request().URL.Path, "/view") {
 		c.Render(code, "error_page", struct {
 			ErrorCode int
 			Message   string
 		}{
 			ErrorCode: code,
-			Message:   msg,
+			Message:   localized,
 		})
 	} else {
 		response := map[string]interface{}{
-			"error": msg,
+			"error": localized,
 			"code":  errorCode,
 		}
+		if messageCode != "" {
+			response["message_code"] = messageCode
+		}
 		if details != nil {
 			response["details"] = details
 		}
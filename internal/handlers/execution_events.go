@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// HandleExecutionEvents streams the status changes of the executions of the namespace as
+// server-sent events named after the lifecycle event: queued, running, pending_approval,
+// completed, errored or cancelled. Users only receive the executions they triggered.
+func (h *Handler) HandleExecutionEvents(c echo.Context) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	user, err := h.getUserInfo(c)
+	if err != nil {
+		return wrapError(ErrForbidden, "could not get user info", err, nil)
+	}
+
+	restricted, err := h.isUserOnly(c.Request().Context(), user.ID, namespace)
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not determine user role", err, nil)
+	}
+
+	events := h.co.SubscribeExecutionEvents(c.Request().Context(), namespace)
+
+	c.Response().Header().Set("Content-Type", "text/event-stream")
+	c.Response().Header().Set("Cache-Control", "no-cache")
+	c.Response().Header().Set("Connection", "keep-alive")
+	c.Response().Header().Set("X-Accel-Buffering", "no")
+	c.Response().WriteHeader(http.StatusOK)
+	flush := func() {
+		if flusher, ok := c.Response().Unwrap().(http.Flusher); ok {
+			flusher.Flush()
+		}
+	}
+	flush()
+
+	heartbeatTicker := time.NewTicker(5 * time.Second)
+	defer heartbeatTicker.Stop()
+
+	for {
+		select {
+		case <-c.Request().Context().Done():
+			return nil
+		case <-heartbeatTicker.C:
+			if _, err := fmt.Fprintf(c.Response(), ": heartbeat\n\n"); err != nil {
+				return nil
+			}
+			flush()
+		case e, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if restricted && e.TriggeredByID != user.ID {
+				continue
+			}
+
+			data, err := json.Marshal(ExecutionEventResp{
+				ExecID:      e.ExecID,
+				FlowID:      e.FlowID,
+				Status:      string(e.Status),
+				TriggeredBy: e.TriggeredByID,
+				Timestamp:   e.Timestamp.Format(TimeFormat),
+			})
+			if err != nil {
+				h.logger.Error("could not marshal execution event", "error", err)
+				continue
+			}
+			if _, err := fmt.Fprintf(c.Response(), "event: %s\ndata: %s\n\n", e.Name(), data); err != nil {
+				h.logger.Debug("execution events client disconnected", "error", err)
+				return nil
+			}
+			flush()
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/cvhariharan/flowctl/internal/core"
+	"github.com/labstack/echo/v4"
+)
+
+// HandleListExecutionWatches returns the flows and executions of the namespace watched by the current user
+func (h *Handler) HandleListExecutionWatches(c echo.Context) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	user, err := h.getUserInfo(c)
+	if err != nil {
+		return wrapError(ErrAuthenticationFailed, "could not get user details", err, nil)
+	}
+
+	watches, err := h.co.ListExecutionWatches(c.Request().Context(), user.ID, namespace)
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not get watches", err, nil)
+	}
+
+	resp := ExecutionWatchesResp{Watches: make([]ExecutionWatchResp, 0, len(watches))}
+	for _, w := range watches {
+		resp.Watches = append(resp.Watches, ExecutionWatchResp{
+			FlowID:    w.FlowID,
+			FlowName:  w.FlowName,
+			ExecID:    w.ExecID,
+			CreatedAt: w.CreatedAt.Format(TimeFormat),
+		})
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// HandleWatchFlow notifies the current user when executions of a flow complete or fail
+func (h *Handler) HandleWatchFlow(c echo.Context) error {
+	return h.setFlowWatch(c, true)
+}
+
+// HandleUnwatchFlow stops notifying the current user about the executions of a flow
+func (h *Handler) HandleUnwatchFlow(c echo.Context) error {
+	return h.setFlowWatch(c, false)
+}
+
+func (h *Handler) setFlowWatch(c echo.Context, watched bool) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	user, err := h.getUserInfo(c)
+	if err != nil {
+		return wrapError(ErrAuthenticationFailed, "could not get user details", err, nil)
+	}
+
+	// Users only see the executions they triggered, so they can't be notified about every execution of a flow
+	restricted, err := h.isUserOnly(c.Request().Context(), user.ID, namespace)
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not determine user role", err, nil)
+	}
+	if restricted && watched {
+		return wrapError(ErrForbidden, "insufficient permissions to watch every execution of the flow", nil, nil)
+	}
+
+	flowID := c.Param("flowID")
+	if watched {
+		err = h.co.WatchFlow(c.Request().Context(), flowID, user.ID, namespace)
+	} else {
+		err = h.co.UnwatchFlow(c.Request().Context(), flowID, user.ID, namespace)
+	}
+	if errors.Is(err, core.ErrFlowNotFound) {
+		return wrapError(ErrResourceNotFound, "flow not found", err, nil)
+	}
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not update watches", err, nil)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// HandleWatchExecution notifies the current user when an execution completes or fails
+func (h *Handler) HandleWatchExecution(c echo.Context) error {
+	return h.setExecutionWatch(c, true)
+}
+
+// HandleUnwatchExecution stops notifying the current user about an execution
+func (h *Handler) HandleUnwatchExecution(c echo.Context) error {
+	return h.setExecutionWatch(c, false)
+}
+
+func (h *Handler) setExecutionWatch(c echo.Context, watched bool) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	user, err := h.getUserInfo(c)
+	if err != nil {
+		return wrapError(ErrAuthenticationFailed, "could not get user details", err, nil)
+	}
+
+	execID := c.Param("execID")
+	exec, err := h.co.GetExecutionSummaryByExecID(c.Request().Context(), execID, namespace)
+	if err != nil {
+		return wrapError(ErrResourceNotFound, "execution not found", err, nil)
+	}
+
+	restricted, err := h.isUserOnly(c.Request().Context(), user.ID, namespace)
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not determine user role", err, nil)
+	}
+	if restricted && exec.TriggeredByID != user.ID {
+		return wrapError(ErrForbidden, "insufficient permissions", nil, nil)
+	}
+
+	if watched {
+		err = h.co.WatchExecution(c.Request().Context(), execID, user.ID, namespace)
+	} else {
+		err = h.co.UnwatchExecution(c.Request().Context(), execID, user.ID, namespace)
+	}
+	if errors.Is(err, core.ErrFlowNotFound) {
+		return wrapError(ErrResourceNotFound, "flow not found", err, nil)
+	}
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not update watches", err, nil)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
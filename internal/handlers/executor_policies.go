@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/cvhariharan/flowctl/sdk/executor"
+	"github.com/labstack/echo/v4"
+)
+
+func (h *Handler) HandleGetExecutorPolicy(c echo.Context) error {
+	namespaceID := c.Param("namespaceID")
+	if namespaceID == "" {
+		return wrapError(ErrRequiredFieldMissing, "namespace ID cannot be empty", nil, nil)
+	}
+
+	policy, err := h.co.GetExecutorPolicy(c.Request().Context(), namespaceID)
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not get executor policy", err, nil)
+	}
+
+	return c.JSON(http.StatusOK, coreExecutorPolicyToResp(policy))
+}
+
+// HandleUpdateExecutorPolicy sets the executors a namespace may use. Policies are managed by
+// superusers so that namespace admins can't allow executors for themselves.
+func (h *Handler) HandleUpdateExecutorPolicy(c echo.Context) error {
+	namespaceID := c.Param("namespaceID")
+	if namespaceID == "" {
+		return wrapError(ErrRequiredFieldMissing, "namespace ID cannot be empty", nil, nil)
+	}
+
+	var req ExecutorPolicyReq
+	if err := c.Bind(&req); err != nil {
+		return wrapError(ErrInvalidInput, "could not decode request", err, nil)
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return wrapError(ErrValidationFailed, fmt.Sprintf("request validation failed: %s", formatValidationErrors(err)), err, nil)
+	}
+
+	for _, name := range req.AllowedExecutors {
+		if _, err := executor.GetNewExecutorFunc(name); err != nil {
+			return wrapError(ErrValidationFailed, fmt.Sprintf("unknown executor %s", name), err, nil)
+		}
+	}
+
+	policy, err := h.co.SetExecutorPolicy(c.Request().Context(), namespaceID, models.ExecutorPolicy{
+		AllowedExecutors: req.AllowedExecutors,
+	})
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not update executor policy", err, nil)
+	}
+
+	return c.JSON(http.StatusOK, coreExecutorPolicyToResp(policy))
+}
+
+func (h *Handler) HandleDeleteExecutorPolicy(c echo.Context) error {
+	namespaceID := c.Param("namespaceID")
+	if namespaceID == "" {
+		return wrapError(ErrRequiredFieldMissing, "namespace ID cannot be empty", nil, nil)
+	}
+
+	if err := h.co.DeleteExecutorPolicy(c.Request().Context(), namespaceID); err != nil {
+		return wrapError(ErrOperationFailed, "could not delete executor policy", err, nil)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/cvhariharan/flowctl/internal/core"
+	"github.com/labstack/echo/v4"
+)
+
+// defaultRecentFlows is how many recently run flows are listed if the request doesn't set a count
+const defaultRecentFlows = 10
+
+// HandleListFlowFavorites returns the flows of the namespace starred by the current user
+func (h *Handler) HandleListFlowFavorites(c echo.Context) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	user, err := h.getUserInfo(c)
+	if err != nil {
+		return wrapError(ErrAuthenticationFailed, "could not get user details", err, nil)
+	}
+
+	flows, err := h.co.ListFlowFavorites(c.Request().Context(), user.ID, namespace)
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not get starred flows", err, nil)
+	}
+
+	return c.JSON(http.StatusOK, coreFlowsToFlows(flows))
+}
+
+// HandleAddFlowFavorite stars a flow for the current user
+func (h *Handler) HandleAddFlowFavorite(c echo.Context) error {
+	return h.setFlowFavorite(c, true)
+}
+
+// HandleRemoveFlowFavorite unstars a flow for the current user
+func (h *Handler) HandleRemoveFlowFavorite(c echo.Context) error {
+	return h.setFlowFavorite(c, false)
+}
+
+func (h *Handler) setFlowFavorite(c echo.Context, starred bool) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	user, err := h.getUserInfo(c)
+	if err != nil {
+		return wrapError(ErrAuthenticationFailed, "could not get user details", err, nil)
+	}
+
+	flowID := c.Param("flowID")
+	if starred {
+		err = h.co.AddFlowFavorite(c.Request().Context(), flowID, user.ID, namespace)
+	} else {
+		err = h.co.RemoveFlowFavorite(c.Request().Context(), flowID, user.ID, namespace)
+	}
+	if errors.Is(err, core.ErrFlowNotFound) {
+		return wrapError(ErrResourceNotFound, "flow not found", err, nil)
+	}
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not update starred flows", err, nil)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// HandleListRecentFlows returns the flows of the namespace the current user triggered last
+func (h *Handler) HandleListRecentFlows(c echo.Context) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	user, err := h.getUserInfo(c)
+	if err != nil {
+		return wrapError(ErrAuthenticationFailed, "could not get user details", err, nil)
+	}
+
+	var req RecentFlowsReq
+	if err := c.Bind(&req); err != nil {
+		return wrapError(ErrInvalidInput, "invalid request", err, nil)
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return wrapError(ErrValidationFailed, fmt.Sprintf("request validation failed: %s", formatValidationErrors(err)), err, nil)
+	}
+	if req.Count == 0 {
+		req.Count = defaultRecentFlows
+	}
+
+	recent, err := h.co.ListRecentFlows(c.Request().Context(), user.ID, namespace, req.Count)
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not get recently run flows", err, nil)
+	}
+
+	resp := RecentFlowsResp{Flows: make([]RecentFlowItem, 0, len(recent))}
+	for _, r := range recent {
+		resp.Flows = append(resp.Flows, RecentFlowItem{
+			FlowListItem: coreFlowToFlow(r.Flow),
+			LastRunAt:    r.LastRunAt.Format(TimeFormat),
+		})
+	}
+	return c.JSON(http.StatusOK, resp)
+}
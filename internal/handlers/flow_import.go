@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// maxFlowImportSize limits the size of imported flow files
+	maxFlowImportSize = 1 << 20
+	// flowImportTimeout bounds fetching a flow file from a URL
+	flowImportTimeout = 30 * time.Second
+	// maxFlowImportRedirects limits the redirects followed while fetching a flow file
+	maxFlowImportRedirects = 5
+)
+
+// sharedAddressSpace is the carrier-grade NAT range (RFC 6598). It is not public, but
+// net.IP.IsPrivate does not include it.
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// flowImportClient fetches flow files from user supplied URLs. It only connects to public
+// addresses, the check is done on the resolved address of every connection, including the
+// ones made for redirects, so a hostname can't be pointed at an internal service.
+var flowImportClient = &http.Client{
+	Timeout: flowImportTimeout,
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: 10 * time.Second,
+			Control: func(network, address string, _ syscall.RawConn) error {
+				host, _, err := net.SplitHostPort(address)
+				if err != nil {
+					return err
+				}
+				return checkFlowImportIP(net.ParseIP(host))
+			},
+		}).DialContext,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: flowImportTimeout,
+	},
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxFlowImportRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxFlowImportRedirects)
+		}
+		if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+			return errors.New("redirect to a non http url")
+		}
+		if ip := net.ParseIP(req.URL.Hostname()); ip != nil {
+			return checkFlowImportIP(ip)
+		}
+		return nil
+	},
+}
+
+// checkFlowImportIP rejects addresses flow files must not be fetched from: loopback, private,
+// shared (carrier-grade NAT), link-local and unspecified addresses, which include cloud metadata
+// endpoints
+func checkFlowImportIP(ip net.IP) error {
+	if ip == nil {
+		return errors.New("invalid address")
+	}
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() || sharedAddressSpace.Contains(ip) {
+		return fmt.Errorf("flows can't be imported from %s, only public addresses are allowed", ip)
+	}
+	return nil
+}
+
+// HandleImportFlow creates a flow from a flow file uploaded as the "file" form field or
+// fetched from a URL. The flow goes through the same validation as a flow created from the UI.
+func (h *Handler) HandleImportFlow(c echo.Context) error {
+	namespace := c.Param("namespace")
+
+	var (
+		data     []byte
+		filename string
+		fromFile bool
+	)
+	if file, err := c.FormFile("file"); err == nil {
+		if file.Size > maxFlowImportSize {
+			return wrapError(ErrValidationFailed, fmt.Sprintf("flow file exceeds maximum size of %dKB", maxFlowImportSize/1024), nil, nil)
+		}
+
+		src, err := file.Open()
+		if err != nil {
+			return wrapError(ErrInvalidInput, "could not read uploaded file", err, nil)
+		}
+		defer src.Close()
+
+		data, err = io.ReadAll(io.LimitReader(src, maxFlowImportSize))
+		if err != nil {
+			return wrapError(ErrInvalidInput, "could not read uploaded file", err, nil)
+		}
+		filename = file.Filename
+		fromFile = true
+	} else {
+		var req FlowImportReq
+		if err := c.Bind(&req); err != nil {
+			return wrapError(ErrInvalidInput, "invalid request", err, nil)
+		}
+
+		if err := h.validate.Struct(req); err != nil {
+			return wrapError(ErrValidationFailed, fmt.Sprintf("request validation failed: %s", formatValidationErrors(err)), err, nil)
+		}
+
+		u, err := rawFlowURL(req.URL)
+		if err != nil {
+			return wrapError(ErrValidationFailed, err.Error(), err, nil)
+		}
+
+		data, err = fetchFlowFile(c.Request().Context(), u)
+		if err != nil {
+			return wrapError(ErrOperationFailed, "could not fetch flow", err, nil)
+		}
+		filename = u.Path
+	}
+
+	format := models.FlowFormatYAML
+	if strings.EqualFold(path.Ext(filename), ".huml") {
+		format = models.FlowFormatHUML
+	}
+
+	f, err := models.UnmarshalFlow(data, format)
+	if err != nil {
+		// Parse errors quote the content, which is only shown for files the user uploaded
+		// and not for responses of remote servers
+		msg := "could not parse the fetched flow file"
+		if fromFile {
+			msg = fmt.Sprintf("could not parse flow: %v", err)
+		}
+		return wrapError(ErrValidationFailed, msg, err, nil)
+	}
+
+	if f.Meta.Namespace != "" && f.Meta.Namespace != namespace {
+		return wrapError(ErrValidationFailed, fmt.Sprintf("flow belongs to namespace %s", f.Meta.Namespace), nil, nil)
+	}
+
+	return h.createFlow(c, NewFlowConfig(f))
+}
+
+// HandleListFlowImportErrors returns the flow files in the namespace that failed to load,
+// with the reason, since those flows are missing from the flow list
+func (h *Handler) HandleListFlowImportErrors(c echo.Context) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	importErrors, err := h.co.ListFlowImportErrors(c.Request().Context(), namespace)
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not list flow import errors", err, nil)
+	}
+
+	resp := make([]FlowImportErrorResp, 0, len(importErrors))
+	for _, e := range importErrors {
+		resp = append(resp, FlowImportErrorResp{
+			FilePath:  e.FilePath,
+			Error:     e.Error,
+			CreatedAt: e.CreatedAt.Format(TimeFormat),
+		})
+	}
+
+	return c.JSON(http.StatusOK, FlowImportErrorsResp{Errors: resp})
+}
+
+// rawFlowURL parses a flow URL and turns links to files on GitHub and GitLab into links to
+// the raw file
+func rawFlowURL(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid url: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, errors.New("only http and https urls can be imported")
+	}
+
+	switch {
+	case u.Host == "github.com":
+		// github.com/<owner>/<repo>/blob/<ref>/<path> -> raw.githubusercontent.com/<owner>/<repo>/<ref>/<path>
+		parts := strings.SplitN(strings.TrimPrefix(u.Path, "/"), "/", 4)
+		if len(parts) == 4 && parts[2] == "blob" {
+			u.Host = "raw.githubusercontent.com"
+			u.Path = "/" + strings.Join([]string{parts[0], parts[1], parts[3]}, "/")
+			u.RawPath = ""
+		}
+	case strings.Contains(u.Path, "/-/blob/"):
+		// GitLab, including self-hosted instances
+		u.Path = strings.Replace(u.Path, "/-/blob/", "/-/raw/", 1)
+		u.RawPath = ""
+	}
+
+	return u, nil
+}
+
+// fetchFlowFile downloads a flow file from a public address, failing if it is larger than
+// maxFlowImportSize
+func fetchFlowFile(ctx context.Context, u *url.URL) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := flowImportClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFlowImportSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxFlowImportSize {
+		return nil, fmt.Errorf("flow file exceeds maximum size of %dKB", maxFlowImportSize/1024)
+	}
+
+	return data, nil
+}
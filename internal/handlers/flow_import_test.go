@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"net"
+	"testing"
+)
+
+func TestCheckFlowImportIP(t *testing.T) {
+	tests := []struct {
+		ip      string
+		allowed bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.10", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"100.64.0.1", false},
+		{"100.127.255.254", false},
+		{"100.128.0.1", true},
+		{"fe80::1", false},
+		{"fd00::1", false},
+		{"0.0.0.0", false},
+		{"::ffff:127.0.0.1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			err := checkFlowImportIP(net.ParseIP(tt.ip))
+			if allowed := err == nil; allowed != tt.allowed {
+				t.Errorf("checkFlowImportIP(%s) error = %v, want allowed %v", tt.ip, err, tt.allowed)
+			}
+		})
+	}
+}
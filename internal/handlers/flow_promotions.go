@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/cvhariharan/flowctl/internal/core"
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/labstack/echo/v4"
+)
+
+// HandlePromoteFlow copies a flow to another namespace. The user needs permission to create
+// the flow in the target namespace, and to update it when an existing flow is replaced.
+func (h *Handler) HandlePromoteFlow(c echo.Context) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	user, err := h.getUserInfo(c)
+	if err != nil {
+		return wrapError(ErrAuthenticationFailed, "could not get user details", err, nil)
+	}
+
+	var req FlowPromoteReq
+	if err := c.Bind(&req); err != nil {
+		return wrapError(ErrInvalidInput, "invalid request", err, nil)
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return wrapError(ErrValidationFailed, fmt.Sprintf("request validation failed: %s", formatValidationErrors(err)), err, nil)
+	}
+
+	flowID := c.Param("flowID")
+	flow, err := h.co.GetFlowByID(flowID, namespace)
+	if err != nil {
+		return wrapError(ErrResourceNotFound, "flow not found", err, nil)
+	}
+
+	ctx := c.Request().Context()
+	target, err := h.co.GetNamespaceByName(ctx, req.Namespace)
+	if err != nil {
+		return wrapError(ErrResourceNotFound, "target namespace not found", err, nil)
+	}
+
+	actions := []models.RBACAction{models.RBACActionCreate}
+	if models.PromotionConflict(req.OnConflict) == models.PromotionConflictReplace {
+		actions = append(actions, models.RBACActionUpdate)
+	}
+	for _, action := range actions {
+		allowed, err := h.co.CheckPermission(ctx, user.ID, core.FlowDomain(target.ID, flow.Meta.Prefix), models.ResourceFlow, action)
+		if err != nil {
+			return wrapError(ErrOperationFailed, "could not check permissions", err, nil)
+		}
+		if !allowed {
+			return wrapError(ErrForbidden, "insufficient permissions to promote the flow to the target namespace", nil, nil)
+		}
+	}
+
+	promotion, err := h.co.PromoteFlow(ctx, flowID, namespace, user.ID, models.FlowPromotionRequest{
+		TargetNamespaceID: target.ID,
+		TargetFlowID:      req.ID,
+		OnConflict:        models.PromotionConflict(req.OnConflict),
+		CopySecretKeys:    req.CopySecrets,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, core.ErrPromotionConflict):
+			return wrapError(ErrValidationFailed, err.Error(), err, nil)
+		case errors.Is(err, core.ErrFlowNotFound):
+			return wrapError(ErrResourceNotFound, "flow not found", err, nil)
+		case errors.Is(err, core.ErrExecutorNotAllowed):
+			return wrapError(ErrForbidden, err.Error(), err, nil)
+		}
+		return wrapError(ErrOperationFailed, "could not promote flow", err, nil)
+	}
+	promotion.PromotedBy = user.Name
+
+	return c.JSON(http.StatusCreated, coreFlowPromotionToResp(promotion))
+}
+
+// HandleListFlowPromotions returns the promotions a flow was the source or the target of
+func (h *Handler) HandleListFlowPromotions(c echo.Context) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	promotions, err := h.co.ListFlowPromotions(c.Request().Context(), c.Param("flowID"), namespace)
+	if err != nil {
+		if errors.Is(err, core.ErrFlowNotFound) {
+			return wrapError(ErrResourceNotFound, "flow not found", err, nil)
+		}
+		return wrapError(ErrOperationFailed, "could not get flow promotions", err, nil)
+	}
+
+	resp := FlowPromotionsResp{Promotions: make([]FlowPromotionResp, 0, len(promotions))}
+	for _, p := range promotions {
+		resp.Promotions = append(resp.Promotions, coreFlowPromotionToResp(p))
+	}
+	return c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cvhariharan/flowctl/internal/core"
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/labstack/echo/v4"
+)
+
+func (h *Handler) HandleListFlowRevisions(c echo.Context) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	var req FlowRevisionsPaginateReq
+	if err := c.Bind(&req); err != nil {
+		return wrapError(ErrInvalidInput, "could not decode request", err, nil)
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return wrapError(ErrValidationFailed, fmt.Sprintf("request validation failed: %s", formatValidationErrors(err)), err, nil)
+	}
+
+	if req.Page < 0 || req.Count < 0 {
+		return wrapError(ErrInvalidPagination, "invalid pagination parameters", nil, nil)
+	}
+
+	if req.Page > 0 {
+		req.Page -= 1
+	}
+
+	if req.Count == 0 {
+		req.Count = CountPerPage
+	}
+
+	revisions, pageCount, totalCount, err := h.co.ListFlowRevisions(c.Request().Context(), namespace, req.Status, req.Count, req.Count*req.Page)
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not list flow revisions", err, nil)
+	}
+
+	resp := make([]FlowRevisionResp, 0, len(revisions))
+	for _, r := range revisions {
+		resp = append(resp, coreFlowRevisionToResp(r))
+	}
+
+	return c.JSON(http.StatusOK, FlowRevisionsPaginateResponse{
+		Revisions:  resp,
+		PageCount:  pageCount,
+		TotalCount: totalCount,
+	})
+}
+
+func (h *Handler) HandleGetFlowRevision(c echo.Context) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	revision, err := h.co.GetFlowRevision(c.Request().Context(), c.Param("revisionID"), namespace)
+	if err != nil {
+		return wrapError(ErrResourceNotFound, "could not get flow revision", err, nil)
+	}
+
+	return c.JSON(http.StatusOK, FlowRevisionDetailsResp{
+		FlowRevisionResp: coreFlowRevisionToResp(revision),
+		Format:           string(revision.Format),
+		Content:          revision.Content,
+	})
+}
+
+func (h *Handler) HandleReviewFlowRevision(c echo.Context) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	var req FlowRevisionReviewReq
+	if err := c.Bind(&req); err != nil {
+		return wrapError(ErrInvalidInput, "invalid request", err, nil)
+	}
+	req.Comment = strings.TrimSpace(req.Comment)
+
+	if err := h.validate.Struct(req); err != nil {
+		return wrapError(ErrValidationFailed, fmt.Sprintf("request validation failed: %s", formatValidationErrors(err)), err, nil)
+	}
+
+	user, err := h.getUserInfo(c)
+	if err != nil {
+		return wrapError(ErrAuthenticationFailed, "could not get user details", err, nil)
+	}
+
+	status := models.FlowRevisionStatusApproved
+	if req.Action == "reject" {
+		status = models.FlowRevisionStatusRejected
+	}
+
+	err = h.co.ReviewFlowRevision(c.Request().Context(), req.RevisionID, user.ID, status, req.Comment, namespace)
+	if errors.Is(err, core.ErrSelfReview) {
+		return wrapError(ErrForbidden, err.Error(), err, nil)
+	}
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not review flow revision", err, nil)
+	}
+
+	revision, err := h.co.GetFlowRevision(c.Request().Context(), req.RevisionID, namespace)
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not get flow revision", err, nil)
+	}
+
+	return c.JSON(http.StatusOK, coreFlowRevisionToResp(revision))
+}
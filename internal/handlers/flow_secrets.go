@@ -34,7 +34,10 @@ func (h *Handler) HandleCreateFlowSecret(c echo.Context) error {
 		return wrapError(ErrOperationFailed, "could not create flow secret", err, nil)
 	}
 
-	return c.JSON(http.StatusCreated, coreFlowSecretToFlowSecretResp(created))
+	resp := coreFlowSecretToFlowSecretResp(created)
+	h.audit(c, models.AuditSecretCreate, models.ResourceFlowSecret, resp.ID, nil, resp)
+
+	return c.JSON(http.StatusCreated, resp)
 }
 
 func (h *Handler) HandleGetFlowSecret(c echo.Context) error {
@@ -103,6 +106,11 @@ func (h *Handler) HandleUpdateFlowSecret(c echo.Context) error {
 		return wrapError(ErrValidationFailed, fmt.Sprintf("request validation failed: %s", formatValidationErrors(err)), err, nil)
 	}
 
+	existing, err := h.co.GetFlowSecretByID(c.Request().Context(), req.SecretID, namespace)
+	if err != nil {
+		return wrapError(ErrResourceNotFound, "secret not found", err, nil)
+	}
+
 	secret := models.FlowSecret{
 		Value:       req.Value,
 		Description: req.Description,
@@ -113,7 +121,10 @@ func (h *Handler) HandleUpdateFlowSecret(c echo.Context) error {
 		return wrapError(ErrOperationFailed, "could not update flow secret", err, nil)
 	}
 
-	return c.JSON(http.StatusOK, coreFlowSecretToFlowSecretResp(updated))
+	resp := coreFlowSecretToFlowSecretResp(updated)
+	h.audit(c, models.AuditSecretUpdate, models.ResourceFlowSecret, req.SecretID, coreFlowSecretToFlowSecretResp(existing), resp)
+
+	return c.JSON(http.StatusOK, resp)
 }
 
 func (h *Handler) HandleDeleteFlowSecret(c echo.Context) error {
@@ -131,10 +142,16 @@ func (h *Handler) HandleDeleteFlowSecret(c echo.Context) error {
 		return wrapError(ErrValidationFailed, fmt.Sprintf("request validation failed: %s", formatValidationErrors(err)), err, nil)
 	}
 
-	err := h.co.DeleteFlowSecret(c.Request().Context(), req.SecretID, namespace)
+	existing, err := h.co.GetFlowSecretByID(c.Request().Context(), req.SecretID, namespace)
+	if err != nil {
+		return wrapError(ErrResourceNotFound, "secret not found", err, nil)
+	}
+
+	err = h.co.DeleteFlowSecret(c.Request().Context(), req.SecretID, namespace)
 	if err != nil {
 		return wrapError(ErrOperationFailed, "could not delete flow secret", err, nil)
 	}
+	h.audit(c, models.AuditSecretDelete, models.ResourceFlowSecret, req.SecretID, coreFlowSecretToFlowSecretResp(existing), nil)
 
 	return c.NoContent(http.StatusOK)
 }
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/cvhariharan/flowctl/internal/core"
+	"github.com/labstack/echo/v4"
+)
+
+func (h *Handler) HandleListFlowTemplates(c echo.Context) error {
+	templates, err := h.co.ListFlowTemplates()
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not list flow templates", err, nil)
+	}
+
+	resp := make([]FlowTemplateResp, 0, len(templates))
+	for _, t := range templates {
+		resp = append(resp, coreFlowTemplateToResp(t))
+	}
+
+	return c.JSON(http.StatusOK, FlowTemplatesResp{Templates: resp})
+}
+
+func (h *Handler) HandleGetFlowTemplate(c echo.Context) error {
+	t, err := h.co.GetFlowTemplate(c.Param("templateID"))
+	if err != nil {
+		if errors.Is(err, core.ErrFlowTemplateNotFound) {
+			return wrapError(ErrResourceNotFound, "flow template not found", err, nil)
+		}
+		return wrapError(ErrOperationFailed, "could not get flow template", err, nil)
+	}
+
+	return c.JSON(http.StatusOK, coreFlowTemplateToResp(t))
+}
+
+// HandleCreateFlowFromTemplate creates a flow in the namespace from a template. The name of the
+// template is used unless a new one is given, and the flow ID is derived from the name.
+func (h *Handler) HandleCreateFlowFromTemplate(c echo.Context) error {
+	var req FlowTemplateCreateReq
+	if err := c.Bind(&req); err != nil {
+		return wrapError(ErrInvalidInput, "invalid request", err, nil)
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return wrapError(ErrValidationFailed, fmt.Sprintf("request validation failed: %s", formatValidationErrors(err)), err, nil)
+	}
+
+	t, err := h.co.GetFlowTemplate(req.TemplateID)
+	if err != nil {
+		if errors.Is(err, core.ErrFlowTemplateNotFound) {
+			return wrapError(ErrResourceNotFound, "flow template not found", err, nil)
+		}
+		return wrapError(ErrOperationFailed, "could not get flow template", err, nil)
+	}
+
+	f := t.Flow
+	if req.Name != "" {
+		f.Meta.Name = req.Name
+	}
+	f.Meta.Prefix = req.Prefix
+
+	return h.createFlow(c, NewFlowConfig(f))
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/cvhariharan/flowctl/internal/core"
+	"github.com/labstack/echo/v4"
+)
+
+func (h *Handler) HandleListFlowVersions(c echo.Context) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	var req PaginateRequest
+	if err := c.Bind(&req); err != nil {
+		return wrapError(ErrInvalidInput, "could not decode request", err, nil)
+	}
+
+	if req.Page < 0 || req.Count < 0 {
+		return wrapError(ErrInvalidPagination, "invalid pagination parameters", nil, nil)
+	}
+
+	if req.Page > 0 {
+		req.Page -= 1
+	}
+
+	if req.Count == 0 {
+		req.Count = CountPerPage
+	}
+
+	versions, pageCount, totalCount, err := h.co.ListFlowVersions(c.Request().Context(), c.Param("flowID"), namespace, req.Count, req.Count*req.Page)
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not list flow versions", err, nil)
+	}
+
+	resp := make([]FlowVersionResp, 0, len(versions))
+	for _, v := range versions {
+		resp = append(resp, FlowVersionResp{
+			ID:         v.ID,
+			Checksum:   v.Checksum,
+			CommitSHA:  v.CommitSHA,
+			CreatedAt:  v.CreatedAt.Format(TimeFormat),
+			HasContent: v.HasContent,
+		})
+	}
+
+	return c.JSON(http.StatusOK, FlowVersionsPaginateResponse{
+		Versions:   resp,
+		PageCount:  pageCount,
+		TotalCount: totalCount,
+	})
+}
+
+// HandleDiffFlowVersions returns the changes between two versions of a flow. Versions are
+// referred to by ID, "latest", or the ID of a flow revision waiting for review.
+func (h *Handler) HandleDiffFlowVersions(c echo.Context) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	var req FlowDiffReq
+	if err := c.Bind(&req); err != nil {
+		return wrapError(ErrInvalidInput, "could not decode request", err, nil)
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return wrapError(ErrValidationFailed, fmt.Sprintf("request validation failed: %s", formatValidationErrors(err)), err, nil)
+	}
+
+	diff, err := h.co.DiffFlowVersions(c.Request().Context(), req.FlowID, namespace, req.From, req.To)
+	if err != nil {
+		if errors.Is(err, core.ErrFlowVersionNoContent) {
+			return wrapError(ErrValidationFailed, err.Error(), err, nil)
+		}
+		return wrapError(ErrResourceNotFound, "could not diff flow versions", err, nil)
+	}
+
+	return c.JSON(http.StatusOK, coreFlowDiffToResp(diff))
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/cvhariharan/flowctl/internal/core"
+	"github.com/labstack/echo/v4"
+)
+
+// maxFlowWebhookSize limits the size of the requests to the webhooks of flows
+const maxFlowWebhookSize = 1 << 20
+
+// HandleFlowWebhook queues an execution of a flow for a request to its webhook. The request is
+// authenticated by the token in its URL and the signature of its body, not by a session.
+func (h *Handler) HandleFlowWebhook(c echo.Context) error {
+	body, err := io.ReadAll(io.LimitReader(c.Request().Body, maxFlowWebhookSize))
+	if err != nil {
+		return wrapError(ErrInvalidInput, "could not read webhook payload", err, nil)
+	}
+
+	execID, err := h.co.TriggerFlowWebhook(c.Request().Context(), c.Param("namespace"), c.Param("flowID"), c.Param("token"), c.Request().Header, c.QueryParams(), body)
+	switch {
+	case errors.Is(err, core.ErrWebhookNotFound):
+		return wrapError(ErrResourceNotFound, "webhook not found", err, nil)
+	case errors.Is(err, core.ErrInvalidWebhookSignature):
+		return wrapError(ErrAuthenticationFailed, "invalid webhook signature", err, nil)
+	case errors.Is(err, core.ErrInvalidWebhookPayload):
+		return wrapError(ErrValidationFailed, err.Error(), err, nil)
+	case errors.Is(err, core.ErrQuotaExceeded):
+		return wrapError(ErrQuotaExceeded, err.Error(), err, nil)
+	case errors.Is(err, core.ErrExecutorNotAllowed):
+		return wrapError(ErrForbidden, err.Error(), err, nil)
+	case err != nil:
+		return wrapError(ErrOperationFailed, "could not trigger flow", err, nil)
+	}
+
+	return c.JSON(http.StatusAccepted, FlowTriggerResp{ExecID: execID})
+}
+
+func (h *Handler) HandleGetFlowWebhook(c echo.Context) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	w, err := h.co.GetFlowWebhook(c.Request().Context(), c.Param("flowID"), namespace)
+	if errors.Is(err, core.ErrFlowNotFound) || errors.Is(err, core.ErrWebhookNotFound) {
+		return wrapError(ErrResourceNotFound, "webhook not found", err, nil)
+	}
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not get webhook", err, nil)
+	}
+
+	return c.JSON(http.StatusOK, coreFlowWebhookToResp(w))
+}
+
+// HandleCreateFlowWebhook creates the webhook of a flow, or replaces its token and secret. The
+// URL and the secret of the webhook are only returned here.
+func (h *Handler) HandleCreateFlowWebhook(c echo.Context) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	flowID := c.Param("flowID")
+	w, token, secret, err := h.co.CreateFlowWebhook(c.Request().Context(), flowID, namespace)
+	if errors.Is(err, core.ErrFlowNotFound) {
+		return wrapError(ErrResourceNotFound, "flow not found", err, nil)
+	}
+	if errors.Is(err, core.ErrNoWebhookTrigger) {
+		return wrapError(ErrValidationFailed, err.Error(), err, nil)
+	}
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not create webhook", err, nil)
+	}
+
+	hookURL, err := url.JoinPath(h.config.App.RootURL, "api/v1", c.Param("namespace"), "hooks", flowID, token)
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not build webhook URL", err, nil)
+	}
+
+	return c.JSON(http.StatusCreated, FlowWebhookCreateResp{
+		FlowWebhookResp: coreFlowWebhookToResp(w),
+		URL:             hookURL,
+		Secret:          secret,
+	})
+}
+
+func (h *Handler) HandleDeleteFlowWebhook(c echo.Context) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	err := h.co.DeleteFlowWebhook(c.Request().Context(), c.Param("flowID"), namespace)
+	if errors.Is(err, core.ErrFlowNotFound) || errors.Is(err, core.ErrWebhookNotFound) {
+		return wrapError(ErrResourceNotFound, "webhook not found", err, nil)
+	}
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not delete webhook", err, nil)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
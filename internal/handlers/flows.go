@@ -3,15 +3,18 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"mime/multipart"
 	"net/http"
-	"os"
-	"path/filepath"
 	"strconv"
 	"time"
 
+	"github.com/cvhariharan/flowctl/internal/core"
 	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/cvhariharan/flowctl/internal/securitylog"
+	"github.com/cvhariharan/flowctl/internal/streamlogger"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 )
@@ -68,79 +71,124 @@ func convertRequestInputs(req map[string]interface{}, flow models.Flow) error {
 	return nil
 }
 
-// processFileUpload handles a single file upload and saves it to the artifacts directory
-func (h *Handler) processFileUpload(c echo.Context, input models.Input, execID string, globalMaxSize int64) (string, error) {
-	file, err := c.FormFile(input.Name)
-	if err != nil {
-		if input.Required {
-			return "", fmt.Errorf("file %s is required", input.Name)
-		}
-		return "", nil
-	}
+// maxFormValueSize is the maximum size of a form value that isn't a file
+const maxFormValueSize = 1 << 20
 
+// processFileUpload streams a file uploaded to a file input into the upload store and returns
+// the reference to the stored file
+func (h *Handler) processFileUpload(ctx context.Context, part *multipart.Part, input models.Input, execID string, globalMaxSize int64) (string, error) {
 	maxSize := globalMaxSize
 	if input.MaxFileSize > 0 {
 		maxSize = input.MaxFileSize
 	}
 
-	if file.Size > maxSize {
-		return "", fmt.Errorf("file %s exceeds maximum size of %dMB", input.Name, maxSize/(1024*1024))
+	upload := core.Upload{
+		ExecID:      execID,
+		Input:       input,
+		Filename:    part.FileName(),
+		ContentType: part.Header.Get("Content-Type"),
+	}
+	ref, err := h.co.SaveUpload(ctx, execID, input, upload.Filename, upload.ContentType, part, maxSize)
+	if err != nil {
+		return "", err
 	}
 
-	artifactDir := filepath.Join(os.TempDir(), fmt.Sprintf("artifacts-store-%s", execID), "uploads")
-	if err := os.MkdirAll(artifactDir, 0700); err != nil {
-		return "", fmt.Errorf("could not create artifacts directory: %w", err)
+	if err := h.co.ValidateUpload(ctx, upload, ref); err != nil {
+		return "", err
 	}
+	return ref, nil
+}
 
-	filename := filepath.Base(filepath.Clean(file.Filename))
-	if filename == "" || filename == "." || filename == ".." {
-		filename = fmt.Sprintf("uploaded_%s", input.Name)
+// processFlowInputs processes all flow inputs from the request and returns a map of input values.
+// Multipart forms are read part by part so that uploaded files are streamed into the upload store
+// instead of being buffered on this host.
+// Values of the preset are used for inputs that are not in the form.
+func (h *Handler) processFlowInputs(c echo.Context, flow models.Flow, execID string, globalMaxSize int64, preset map[string]string) (map[string]interface{}, error) {
+	inputs := make(map[string]models.Input, len(flow.Inputs))
+	for _, input := range flow.Inputs {
+		inputs[input.Name] = input
 	}
 
-	filename = fmt.Sprintf("%s_%s", input.Name, filename)
-	filePath := filepath.Join(artifactDir, filename)
+	values := make(map[string]string)
+	uploads := make(map[string]string)
 
-	src, err := file.Open()
-	if err != nil {
-		return "", fmt.Errorf("failed to open uploaded file: %w", err)
-	}
-	defer src.Close()
+	mr, err := c.Request().MultipartReader()
+	switch {
+	case errors.Is(err, http.ErrNotMultipart):
+		form, err := c.FormParams()
+		if err != nil {
+			return nil, fmt.Errorf("could not parse form: %w", err)
+		}
+		for name := range form {
+			values[name] = form.Get(name)
+		}
+	case err != nil:
+		return nil, fmt.Errorf("could not read form: %w", err)
+	default:
+		for {
+			part, err := mr.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("could not read form: %w", err)
+			}
 
-	dst, err := os.Create(filePath)
-	if err != nil {
-		return "", fmt.Errorf("failed to create file: %w", err)
+			name := part.FormName()
+			input, isInput := inputs[name]
+			switch {
+			case part.FileName() != "":
+				// Only the first file of a file input is kept, other files are skipped
+				if _, exists := uploads[name]; exists || !isInput || input.Type != models.INPUT_TYPE_FILE {
+					break
+				}
+				ref, err := h.processFileUpload(c.Request().Context(), part, input, execID, globalMaxSize)
+				if err != nil {
+					part.Close()
+					return nil, err
+				}
+				uploads[name] = ref
+			default:
+				value, err := io.ReadAll(io.LimitReader(part, maxFormValueSize+1))
+				if err != nil {
+					part.Close()
+					return nil, fmt.Errorf("could not read form value %s: %w", name, err)
+				}
+				if len(value) > maxFormValueSize {
+					part.Close()
+					return nil, fmt.Errorf("form value %s is too large", name)
+				}
+				if _, exists := values[name]; !exists {
+					values[name] = string(value)
+				}
+			}
+			part.Close()
+		}
 	}
-	defer dst.Close()
 
-	if _, err := io.Copy(dst, src); err != nil {
-		return "", fmt.Errorf("failed to save uploaded file: %w", err)
+	for name, value := range preset {
+		if _, exists := values[name]; !exists {
+			values[name] = value
+		}
 	}
 
-	return filePath, nil
-}
-
-// processFlowInputs processes all flow inputs from the request and returns a map of input values
-func (h *Handler) processFlowInputs(c echo.Context, flow models.Flow, execID string, globalMaxSize int64) (map[string]interface{}, error) {
 	req := make(map[string]interface{})
-
 	for _, input := range flow.Inputs {
 		switch input.Type {
 		case models.INPUT_TYPE_FILE:
-			filePath, err := h.processFileUpload(c, input, execID, globalMaxSize)
-			if err != nil {
-				return nil, err
-			}
-			if filePath != "" {
-				req[input.Name] = filePath
+			if ref, ok := uploads[input.Name]; ok {
+				req[input.Name] = ref
+			} else if input.Required {
+				return nil, fmt.Errorf("file %s is required", input.Name)
 			}
 		case models.INPUT_TYPE_CHECKBOX:
-			if c.FormValue(input.Name) != "" {
+			if values[input.Name] != "" {
 				req[input.Name] = "true"
 			} else {
 				req[input.Name] = "false"
 			}
 		default:
-			if value := c.FormValue(input.Name); value != "" {
+			if value := values[input.Name]; value != "" {
 				req[input.Name] = value
 			}
 		}
@@ -185,7 +233,29 @@ func (h *Handler) HandleFlowTrigger(c echo.Context) error {
 	execID := uuid.NewString()
 	globalMaxSize := h.config.App.MaxFileUploadSize
 
-	req, err := h.processFlowInputs(c, f, execID, globalMaxSize)
+	// Uploads are only kept if the execution is queued
+	queued := false
+	defer func() {
+		if !queued {
+			if err := h.co.DeleteUploads(context.Background(), execID); err != nil {
+				h.logger.Error("could not delete uploads", "exec_id", execID, "error", err)
+			}
+		}
+	}()
+
+	var preset map[string]string
+	if name := c.QueryParam("preset"); name != "" {
+		p, err := h.co.GetInputPresetByName(c.Request().Context(), f.Meta.ID, name, user.ID, namespace)
+		if errors.Is(err, core.ErrInputPresetNotFound) {
+			return wrapError(ErrResourceNotFound, err.Error(), err, nil)
+		}
+		if err != nil {
+			return wrapError(ErrOperationFailed, "could not get preset", err, nil)
+		}
+		preset = f.PresetFormValues(p)
+	}
+
+	req, err := h.processFlowInputs(c, f, execID, globalMaxSize, preset)
 	if err != nil {
 		return wrapError(ErrValidationFailed, err.Error(), err, nil)
 	}
@@ -204,9 +274,22 @@ func (h *Handler) HandleFlowTrigger(c echo.Context) error {
 
 	// Add to queue
 	execID, err = h.co.QueueFlowExecutionWithExecID(c.Request().Context(), f, req, user.ID, namespace, execID, scheduledAt)
+	if errors.Is(err, core.ErrQuotaExceeded) {
+		return wrapError(ErrQuotaExceeded, err.Error(), err, nil)
+	}
+	if errors.Is(err, core.ErrExecutorNotAllowed) {
+		return wrapError(ErrForbidden, err.Error(), err, nil)
+	}
 	if err != nil {
 		return wrapError(ErrOperationFailed, fmt.Sprintf("could not trigger flow: %v", err), err, nil)
 	}
+	queued = true
+
+	// The inputs can contain passwords and are left out
+	h.audit(c, models.AuditExecutionTrigger, models.ResourceExecution, execID, nil, map[string]any{
+		"flow_id":      f.Meta.ID,
+		"scheduled_at": scheduledAt,
+	})
 
 	resp := FlowTriggerResp{
 		ExecID: execID,
@@ -261,6 +344,14 @@ func (h *Handler) HandleLogStreaming(c echo.Context) error {
 
 	h.logger.Debug("SSE connection created", "logID", logID)
 
+	if h.co.Metrics != nil {
+		h.co.Metrics.IncSSEClients(namespace, execSummary.FlowID)
+		defer h.co.Metrics.DecSSEClients(namespace, execSummary.FlowID)
+	}
+
+	stripANSI := h.stripANSI(req.ANSI)
+	loc := logTimezone(req.TZ)
+
 	msgCh, err := h.co.StreamLogs(c.Request().Context(), logID, namespace)
 	if err != nil {
 		h.logger.Error("log msg ch", "error", err)
@@ -296,6 +387,10 @@ func (h *Handler) HandleLogStreaming(c echo.Context) error {
 				h.logger.Debug("SSE streaming completed", "logID", logID)
 				return nil
 			}
+			if stripANSI {
+				msg.Val = streamlogger.StripANSI(msg.Val)
+			}
+			msg.Timestamp = formatLogTimestamp(msg.Timestamp, loc)
 			if err := h.handleLogStreaming(msg, c.Response()); err != nil {
 				h.logger.Error("SSE streaming error", "error", err, "logID", logID)
 				return nil
@@ -351,6 +446,153 @@ func (h *Handler) HandleLogDownload(c echo.Context) error {
 	return nil
 }
 
+func (h *Handler) HandleLogSearch(c echo.Context) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	var req LogSearchReq
+	if err := c.Bind(&req); err != nil {
+		return wrapError(ErrInvalidInput, "invalid request", err, nil)
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return wrapError(ErrValidationFailed, fmt.Sprintf("request validation failed: %s", formatValidationErrors(err)), err, nil)
+	}
+
+	user, err := h.getUserInfo(c)
+	if err != nil {
+		return wrapError(ErrForbidden, "could not get user info", err, nil)
+	}
+
+	query := models.LogSearchQuery{
+		ExecID:     req.ExecID,
+		FlowID:     req.FlowID,
+		Pattern:    req.Pattern,
+		Regex:      req.Regex,
+		IgnoreCase: req.IgnoreCase,
+		Context:    req.Context,
+		Limit:      req.Limit,
+	}
+
+	if req.From != "" {
+		query.From, err = time.Parse(time.RFC3339, req.From)
+		if err != nil {
+			return wrapError(ErrValidationFailed, "invalid from format, expected RFC3339", err, nil)
+		}
+	}
+
+	if req.To != "" {
+		query.To, err = time.Parse(time.RFC3339, req.To)
+		if err != nil {
+			return wrapError(ErrValidationFailed, "invalid to format, expected RFC3339", err, nil)
+		}
+	}
+
+	if req.ExecID != "" {
+		execSummary, err := h.co.GetExecutionSummaryByExecID(c.Request().Context(), req.ExecID, namespace)
+		if err != nil {
+			return wrapError(ErrResourceNotFound, "execution not found", err, nil)
+		}
+
+		restricted, err := h.isUserOnly(c.Request().Context(), user.ID, namespace)
+		if err != nil {
+			return wrapError(ErrOperationFailed, "could not determine user role", err, nil)
+		}
+		if restricted && execSummary.TriggeredByID != user.ID {
+			return wrapError(ErrForbidden, "insufficient permissions", nil, nil)
+		}
+	}
+
+	result, err := h.co.SearchLogs(c.Request().Context(), namespace, user.ID, query)
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not search logs", err, nil)
+	}
+
+	resp := LogSearchResp{
+		Matches:            make([]LogSearchMatchResp, len(result.Matches)),
+		SearchedExecutions: result.SearchedExecutions,
+		Truncated:          result.Truncated,
+	}
+	stripANSI := h.stripANSI(req.ANSI)
+	loc := logTimezone(req.TZ)
+	for i, m := range result.Matches {
+		if stripANSI {
+			m.Message.Val = streamlogger.StripANSI(m.Message.Val)
+			for j := range m.Before {
+				m.Before[j].Val = streamlogger.StripANSI(m.Before[j].Val)
+			}
+			for j := range m.After {
+				m.After[j].Val = streamlogger.StripANSI(m.After[j].Val)
+			}
+		}
+		resp.Matches[i] = LogSearchMatchResp{
+			ExecID:   m.ExecID,
+			FlowID:   m.FlowID,
+			FlowName: m.FlowName,
+			Message:  streamMessageToLogResp(m.Message, loc),
+			Before:   streamMessagesToLogResp(m.Before, loc),
+			After:    streamMessagesToLogResp(m.After, loc),
+		}
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// stripANSI reports whether ANSI escape sequences should be removed from log values.
+// The mode requested by the client takes precedence over the server config.
+func (h *Handler) stripANSI(mode string) bool {
+	if mode == "" {
+		mode = h.config.Logger.ANSI
+	}
+	return mode == "strip"
+}
+
+// logTimezone returns the location log timestamps are displayed in. The timezone is
+// validated by the request, so an unknown name falls back to UTC.
+func logTimezone(tz string) *time.Location {
+	if tz == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// formatLogTimestamp converts a stored log timestamp to loc. Timestamps that can't be
+// parsed are returned as is.
+func formatLogTimestamp(ts string, loc *time.Location) string {
+	if ts == "" {
+		return ts
+	}
+	t, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return ts
+	}
+	return t.In(loc).Format(time.RFC3339Nano)
+}
+
+func streamMessageToLogResp(msg models.StreamMessage, loc *time.Location) FlowLogResp {
+	return FlowLogResp{
+		ActionID:  msg.ActionID,
+		MType:     string(msg.MType),
+		NodeID:    msg.NodeID,
+		Value:     msg.Val,
+		Timestamp: formatLogTimestamp(msg.Timestamp, loc),
+	}
+}
+
+func streamMessagesToLogResp(msgs []models.StreamMessage, loc *time.Location) []FlowLogResp {
+	resp := make([]FlowLogResp, len(msgs))
+	for i, msg := range msgs {
+		resp[i] = streamMessageToLogResp(msg, loc)
+	}
+	return resp
+}
+
 func (h *Handler) handleLogStreaming(msg models.StreamMessage, w http.ResponseWriter) error {
 	var response FlowLogResp
 
@@ -500,6 +742,131 @@ func (h *Handler) HandleGetExecutionSummary(c echo.Context) error {
 	return c.JSON(http.StatusOK, response)
 }
 
+func (h *Handler) HandleGetExecutionTimeline(c echo.Context) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	var req ExecutionGetReq
+	if err := c.Bind(&req); err != nil {
+		return wrapError(ErrInvalidInput, "could not decode request", err, nil)
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return wrapError(ErrValidationFailed, fmt.Sprintf("request validation failed: %s", formatValidationErrors(err)), err, nil)
+	}
+
+	execSummary, err := h.co.GetExecutionSummaryByExecID(c.Request().Context(), req.ExecID, namespace)
+	if err != nil {
+		return wrapError(ErrResourceNotFound, "execution not found", err, nil)
+	}
+
+	userInfo, err := h.getUserInfo(c)
+	if err != nil {
+		return wrapError(ErrForbidden, "could not get user info", err, nil)
+	}
+
+	restricted, err := h.isUserOnly(c.Request().Context(), userInfo.ID, namespace)
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not determine user role", err, nil)
+	}
+	if restricted && execSummary.TriggeredByID != userInfo.ID {
+		return wrapError(ErrForbidden, "insufficient permissions", nil, nil)
+	}
+
+	timeline, err := h.co.GetExecutionTimeline(c.Request().Context(), req.ExecID, namespace)
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not get execution timeline", err, nil)
+	}
+
+	return c.JSON(http.StatusOK, coreExecutionTimelineToExecutionTimelineResp(timeline))
+}
+
+// HandleGetExecutionOutputs returns the results of the actions of an execution so that they can be
+// used outside of the log stream
+func (h *Handler) HandleGetExecutionOutputs(c echo.Context) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	var req ExecutionGetReq
+	if err := c.Bind(&req); err != nil {
+		return wrapError(ErrInvalidInput, "could not decode request", err, nil)
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return wrapError(ErrValidationFailed, fmt.Sprintf("request validation failed: %s", formatValidationErrors(err)), err, nil)
+	}
+
+	execSummary, err := h.co.GetExecutionSummaryByExecID(c.Request().Context(), req.ExecID, namespace)
+	if err != nil {
+		return wrapError(ErrResourceNotFound, "execution not found", err, nil)
+	}
+
+	userInfo, err := h.getUserInfo(c)
+	if err != nil {
+		return wrapError(ErrForbidden, "could not get user info", err, nil)
+	}
+
+	restricted, err := h.isUserOnly(c.Request().Context(), userInfo.ID, namespace)
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not determine user role", err, nil)
+	}
+	if restricted && execSummary.TriggeredByID != userInfo.ID {
+		return wrapError(ErrForbidden, "insufficient permissions", nil, nil)
+	}
+
+	outputs, err := h.co.GetExecutionOutputs(c.Request().Context(), req.ExecID, namespace)
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not get execution outputs", err, nil)
+	}
+
+	return c.JSON(http.StatusOK, coreExecutionOutputsToResp(execSummary.Status, outputs))
+}
+
+func (h *Handler) HandleGetExecutionTelemetry(c echo.Context) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	var req ExecutionGetReq
+	if err := c.Bind(&req); err != nil {
+		return wrapError(ErrInvalidInput, "could not decode request", err, nil)
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return wrapError(ErrValidationFailed, fmt.Sprintf("request validation failed: %s", formatValidationErrors(err)), err, nil)
+	}
+
+	execSummary, err := h.co.GetExecutionSummaryByExecID(c.Request().Context(), req.ExecID, namespace)
+	if err != nil {
+		return wrapError(ErrResourceNotFound, "execution not found", err, nil)
+	}
+
+	userInfo, err := h.getUserInfo(c)
+	if err != nil {
+		return wrapError(ErrForbidden, "could not get user info", err, nil)
+	}
+
+	restricted, err := h.isUserOnly(c.Request().Context(), userInfo.ID, namespace)
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not determine user role", err, nil)
+	}
+	if restricted && execSummary.TriggeredByID != userInfo.ID {
+		return wrapError(ErrForbidden, "insufficient permissions", nil, nil)
+	}
+
+	series, err := h.co.GetExecutionTelemetry(c.Request().Context(), req.ExecID, namespace)
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not get execution telemetry", err, nil)
+	}
+
+	return c.JSON(http.StatusOK, coreNodeTelemetryToNodeTelemetryResp(series))
+}
+
 func (h *Handler) HandleExecutionsPagination(c echo.Context) error {
 	namespace, ok := c.Get("namespace").(string)
 	if !ok {
@@ -688,21 +1055,27 @@ func (h *Handler) HandleGetFlowMeta(c echo.Context) error {
 		Metadata:            meta,
 		Actions:             actions,
 		ScheduledExecutions: scheduledExecutionItems,
+		CommitSHA:           flow.Meta.CommitSHA,
 	})
 }
 
 func (h *Handler) HandleCreateFlow(c echo.Context) error {
+	var req FlowCreateReq
+	if err := c.Bind(&req); err != nil {
+		return wrapError(ErrInvalidInput, "invalid request", err, nil)
+	}
+
+	return h.createFlow(c, req)
+}
+
+// createFlow validates a flow create request and creates the flow in the namespace of the request
+func (h *Handler) createFlow(c echo.Context, req FlowCreateReq) error {
 	namespace := c.Param("namespace")
 	namespaceID, ok := c.Get("namespace").(string)
 	if !ok {
 		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
 	}
 
-	var req FlowCreateReq
-	if err := c.Bind(&req); err != nil {
-		return wrapError(ErrInvalidInput, "invalid request", err, nil)
-	}
-
 	if err := h.validate.Struct(req); err != nil {
 		return wrapError(ErrValidationFailed, fmt.Sprintf("request validation failed: %s", formatValidationErrors(err)), err, nil)
 	}
@@ -718,12 +1091,17 @@ func (h *Handler) HandleCreateFlow(c echo.Context) error {
 
 	flow := models.Flow{
 		Meta: models.Metadata{
-			ID:           GenerateSlug(req.Meta.Name),
-			Name:         req.Meta.Name,
-			Description:  req.Meta.Description,
-			Prefix:       req.Meta.Prefix,
-			Namespace:    namespace,
-			AllowOverlap: req.Meta.AllowOverlap,
+			ID:              GenerateSlug(req.Meta.Name),
+			Name:            req.Meta.Name,
+			Description:     req.Meta.Description,
+			Prefix:          req.Meta.Prefix,
+			Namespace:       namespace,
+			AllowOverlap:    req.Meta.AllowOverlap,
+			UserSchedulable: req.Meta.UserSchedulable,
+			SLA:             flowSLAToCoreSLA(req.Meta.SLA),
+			Owners:          req.Meta.Owners,
+			Lock:            req.Meta.Lock,
+			MaxConcurrency:  req.Meta.MaxConcurrency,
 		},
 		Inputs:    convertFlowInputsReqToInputs(req.Inputs),
 		Actions:   convertFlowActionsReqToActions(req.Actions),
@@ -736,8 +1114,12 @@ func (h *Handler) HandleCreateFlow(c echo.Context) error {
 	}
 
 	if err := h.co.CreateFlow(c.Request().Context(), flow, namespaceID); err != nil {
+		if errors.Is(err, core.ErrExecutorNotAllowed) {
+			return wrapError(ErrForbidden, err.Error(), err, nil)
+		}
 		return wrapError(ErrOperationFailed, err.Error(), err, nil)
 	}
+	h.audit(c, models.AuditFlowCreate, models.ResourceFlow, flow.Meta.ID, nil, NewFlowConfig(flow))
 
 	return c.JSON(http.StatusCreated, FlowCreateResp{
 		ID: flow.Meta.ID,
@@ -773,6 +1155,8 @@ func (h *Handler) HandleUpdateFlow(c echo.Context) error {
 	updatedMeta.Prefix = req.Prefix
 	updatedMeta.AllowOverlap = req.AllowOverlap
 	updatedMeta.UserSchedulable = req.UserSchedulable
+	updatedMeta.Lock = req.Lock
+	updatedMeta.MaxConcurrency = req.MaxConcurrency
 	updatedMeta.Description = req.Description
 
 	flow := models.Flow{
@@ -781,15 +1165,33 @@ func (h *Handler) HandleUpdateFlow(c echo.Context) error {
 		Actions:   convertFlowActionsReqToActions(req.Actions),
 		Notify:    convertNotifyReqToNotify(req.Notify),
 		Schedules: schedules,
+		// Triggers are only set in flow files
+		Triggers: f.Triggers,
+		Webhook:  f.Webhook,
 	}
 
 	if err := flow.Validate(); err != nil {
 		return wrapError(ErrValidationFailed, err.Error(), err, nil)
 	}
 
-	if err := h.co.UpdateFlow(c.Request().Context(), flow, namespaceID); err != nil {
+	user, err := h.getUserInfo(c)
+	if err != nil {
+		return wrapError(ErrAuthenticationFailed, "could not get user details", err, nil)
+	}
+
+	revision, err := h.co.RequestFlowUpdate(c.Request().Context(), flow, namespaceID, user.ID)
+	if errors.Is(err, core.ErrExecutorNotAllowed) {
+		return wrapError(ErrForbidden, err.Error(), err, nil)
+	}
+	if err != nil {
 		return wrapError(ErrOperationFailed, err.Error(), err, nil)
 	}
+	// The namespace requires a review, the flow is updated once the revision is approved
+	if revision != nil {
+		h.audit(c, models.AuditFlowUpdateRequest, models.ResourceFlow, flow.Meta.ID, NewFlowConfig(f), NewFlowConfig(flow))
+		return c.JSON(http.StatusAccepted, coreFlowRevisionToResp(*revision))
+	}
+	h.audit(c, models.AuditFlowUpdate, models.ResourceFlow, flow.Meta.ID, NewFlowConfig(f), NewFlowConfig(flow))
 
 	return c.JSON(http.StatusOK, FlowCreateResp{
 		ID: flow.Meta.ID,
@@ -803,9 +1205,15 @@ func (h *Handler) HandleDeleteFlow(c echo.Context) error {
 	}
 	flowID := c.Param("flowID")
 
+	f, err := h.co.GetFlowByID(flowID, namespaceID)
+	if err != nil {
+		return wrapError(ErrResourceNotFound, "could not get flow", err, nil)
+	}
+
 	if err := h.co.DeleteFlow(c.Request().Context(), flowID, namespaceID); err != nil {
 		return wrapError(ErrOperationFailed, err.Error(), err, nil)
 	}
+	h.audit(c, models.AuditFlowDelete, models.ResourceFlow, flowID, NewFlowConfig(f), nil)
 
 	return c.NoContent(http.StatusOK)
 }
@@ -821,28 +1229,7 @@ func (h *Handler) HandleGetFlowConfig(c echo.Context) error {
 		return wrapError(ErrResourceNotFound, "could not get flow", err, nil)
 	}
 
-	schedules := make([]Schedule, 0)
-	for _, s := range f.Schedules {
-		schedules = append(schedules, Schedule{
-			Cron:     s.Cron,
-			Timezone: s.Timezone,
-		})
-	}
-
-	return c.JSON(http.StatusOK, FlowCreateReq{
-		Meta: FlowMeta{
-			ID:              f.Meta.ID,
-			Name:            f.Meta.Name,
-			Description:     f.Meta.Description,
-			Prefix:          f.Meta.Prefix,
-			Schedules:       schedules,
-			AllowOverlap:    f.Meta.AllowOverlap,
-			UserSchedulable: f.Meta.UserSchedulable,
-		},
-		Inputs:        convertFlowInputsToInputsReq(f.Inputs),
-		Actions:       convertFlowActionsToActionsReq(f.Actions),
-		Notifications: convertNotifyToNotifyReq(f.Notify),
-	})
+	return c.JSON(http.StatusOK, NewFlowConfig(f))
 }
 
 func (h *Handler) HandleCancelExecution(c echo.Context) error {
@@ -878,6 +1265,10 @@ func (h *Handler) HandleCancelExecution(c echo.Context) error {
 	if err != nil {
 		return wrapError(ErrOperationFailed, "failed to cancel execution", err, nil)
 	}
+	h.audit(c, models.AuditExecutionCancel, models.ResourceExecution, execID, map[string]any{
+		"flow_id": execSummary.FlowID,
+		"status":  execSummary.Status,
+	}, nil)
 
 	return c.JSON(http.StatusOK, FlowCancellationResp{
 		Message: "Cancellation signal sent",
@@ -891,11 +1282,16 @@ func (h *Handler) HandleRetryExecution(c echo.Context) error {
 		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
 	}
 
-	execID := c.Param("execID")
-	if execID == "" {
-		return wrapError(ErrRequiredFieldMissing, "execution ID is required", nil, nil)
+	var req ExecutionRetryReq
+	if err := c.Bind(&req); err != nil {
+		return wrapError(ErrInvalidInput, "invalid request", err, nil)
 	}
 
+	if err := h.validate.Struct(req); err != nil {
+		return wrapError(ErrRequiredFieldMissing, "execution ID is required", err, nil)
+	}
+	execID := req.ExecID
+
 	user, err := h.getUserInfo(c)
 	if err != nil {
 		return wrapError(ErrAuthenticationFailed, "could not get user details", err, nil)
@@ -914,7 +1310,56 @@ func (h *Handler) HandleRetryExecution(c echo.Context) error {
 		return wrapError(ErrForbidden, "insufficient permissions", nil, nil)
 	}
 
-	err = h.co.RetryFlowExecution(c.Request().Context(), execID, user.ID, namespace)
+	if req.SkipFailed {
+		err = h.co.RetryFlowExecutionSkippingFailed(c.Request().Context(), execID, user.ID, namespace)
+	} else {
+		err = h.co.RetryFlowExecution(c.Request().Context(), execID, user.ID, namespace)
+	}
+	if errors.Is(err, core.ErrNotApprover) {
+		return wrapError(ErrForbidden, "you are not an approver of this action", err, nil)
+	}
+	if err != nil {
+		return wrapError(ErrOperationFailed, err.Error(), err, nil)
+	}
+
+	return c.NoContent(http.StatusCreated)
+}
+
+func (h *Handler) HandleResumeExecution(c echo.Context) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	var req ExecutionResumeReq
+	if err := c.Bind(&req); err != nil {
+		return wrapError(ErrInvalidInput, "invalid request", err, nil)
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return wrapError(ErrValidationFailed, fmt.Sprintf("request validation failed: %s", formatValidationErrors(err)), err, nil)
+	}
+
+	user, err := h.getUserInfo(c)
+	if err != nil {
+		return wrapError(ErrAuthenticationFailed, "could not get user details", err, nil)
+	}
+
+	execSummary, err := h.co.GetExecutionSummaryByExecID(c.Request().Context(), req.ExecID, namespace)
+	if err != nil {
+		return wrapError(ErrResourceNotFound, "execution not found", err, nil)
+	}
+
+	err = h.co.ResumeFlowExecutionFrom(c.Request().Context(), req.ExecID, req.ActionID, user.ID, namespace)
+	h.logSecurityEvent(c, securitylog.TypeExecutionResume, err, map[string]any{
+		"exec_id":          req.ExecID,
+		"flow_id":          execSummary.FlowID,
+		"action_id":        req.ActionID,
+		"failed_action_id": execSummary.CurrentActionID,
+	})
+	if errors.Is(err, core.ErrNotApprover) {
+		return wrapError(ErrForbidden, "you are not an approver of this action", err, nil)
+	}
 	if err != nil {
 		return wrapError(ErrOperationFailed, err.Error(), err, nil)
 	}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// maxGitWebhookSize limits the size of push webhook payloads
+const maxGitWebhookSize = 1 << 20
+
+// HandleGitSyncWebhook schedules a sync of a namespace when its repository receives a push.
+// GitHub and Gitea sign the payload with the webhook secret, GitLab sends the secret as a token.
+func (h *Handler) HandleGitSyncWebhook(c echo.Context) error {
+	namespace := c.Param("namespace")
+
+	if h.gitSync == nil {
+		return wrapError(ErrResourceNotFound, "git sync is not enabled", nil, nil)
+	}
+	repo, ok := h.gitSync.Repo(namespace)
+	if !ok || repo.WebhookSecret == "" {
+		return wrapError(ErrResourceNotFound, "webhooks are not enabled for this namespace", nil, nil)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request().Body, maxGitWebhookSize))
+	if err != nil {
+		return wrapError(ErrInvalidInput, "could not read webhook payload", err, nil)
+	}
+
+	if !verifyGitWebhook(c.Request().Header, body, repo.WebhookSecret) {
+		return wrapError(ErrAuthenticationFailed, "invalid webhook signature", nil, nil)
+	}
+
+	// Pushes to other branches don't change the synced flows
+	var payload struct {
+		Ref string `json:"ref"`
+	}
+	if err := json.Unmarshal(body, &payload); err == nil && payload.Ref != "" && repo.Branch != "" && payload.Ref != "refs/heads/"+repo.Branch {
+		return c.JSON(http.StatusOK, GitSyncResp{Message: "push to another branch, ignored"})
+	}
+
+	if err := h.gitSync.Trigger(namespace); err != nil {
+		return wrapError(ErrOperationFailed, "could not schedule sync", err, nil)
+	}
+
+	return c.JSON(http.StatusAccepted, GitSyncResp{Message: "sync scheduled"})
+}
+
+func (h *Handler) HandleGetGitSyncStatus(c echo.Context) error {
+	if h.gitSync == nil {
+		return wrapError(ErrResourceNotFound, "git sync is not enabled", nil, nil)
+	}
+
+	status, err := h.gitSync.Status(c.Param("namespace"))
+	if err != nil {
+		return wrapError(ErrResourceNotFound, "namespace is not synced from git", err, nil)
+	}
+	repo, _ := h.gitSync.Repo(status.Namespace)
+
+	resp := GitSyncStatusResp{
+		Namespace:  status.Namespace,
+		Branch:     status.Branch,
+		Path:       repo.Path,
+		LastCommit: status.LastCommit,
+		LastError:  status.LastError,
+	}
+	if !status.LastSync.IsZero() {
+		resp.LastSync = status.LastSync.Format(TimeFormat)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *Handler) HandleTriggerGitSync(c echo.Context) error {
+	if h.gitSync == nil {
+		return wrapError(ErrResourceNotFound, "git sync is not enabled", nil, nil)
+	}
+
+	if err := h.gitSync.Trigger(c.Param("namespace")); err != nil {
+		return wrapError(ErrResourceNotFound, "namespace is not synced from git", err, nil)
+	}
+
+	return c.JSON(http.StatusAccepted, GitSyncResp{Message: "sync scheduled"})
+}
+
+// verifyGitWebhook checks the webhook signature headers of GitHub, Gitea and GitLab
+func verifyGitWebhook(header http.Header, body []byte, secret string) bool {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	expected := mac.Sum(nil)
+
+	if sig, ok := strings.CutPrefix(header.Get("X-Hub-Signature-256"), "sha256="); ok {
+		got, err := hex.DecodeString(sig)
+		return err == nil && hmac.Equal(got, expected)
+	}
+	if sig := header.Get("X-Gitea-Signature"); sig != "" {
+		got, err := hex.DecodeString(sig)
+		return err == nil && hmac.Equal(got, expected)
+	}
+	if token := header.Get("X-Gitlab-Token"); token != "" {
+		return subtle.ConstantTimeCompare([]byte(token), []byte(secret)) == 1
+	}
+
+	return false
+}
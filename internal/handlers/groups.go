@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/cvhariharan/flowctl/internal/core/models"
 	"github.com/labstack/echo/v4"
 )
 
@@ -39,10 +40,13 @@ func (h *Handler) HandleCreateGroup(c echo.Context) error {
 		return wrapError(ErrOperationFailed, "could not create group", err, nil)
 	}
 
-	return c.JSON(http.StatusCreated, GroupWithUsers{
+	resp := GroupWithUsers{
 		Group: coreGroupToGroup(group.Group),
 		Users: coreUserArrayCast(group.Users),
-	})
+	}
+	h.audit(c, models.AuditGroupCreate, models.AuditResourceGroup, resp.ID, nil, resp.Group)
+
+	return c.JSON(http.StatusCreated, resp)
 }
 
 func (h *Handler) HandleUpdateGroup(c echo.Context) error {
@@ -60,15 +64,23 @@ func (h *Handler) HandleUpdateGroup(c echo.Context) error {
 		return wrapError(ErrValidationFailed, fmt.Sprintf("request validation failed: %s", formatValidationErrors(err)), err, nil)
 	}
 
+	existing, err := h.co.GetGroupByUUID(c.Request().Context(), groupID)
+	if err != nil {
+		return wrapError(ErrResourceNotFound, "could not get group", err, nil)
+	}
+
 	group, err := h.co.UpdateGroup(c.Request().Context(), groupID, req.Name, req.Description)
 	if err != nil {
 		return wrapError(ErrOperationFailed, "could not update group", err, nil)
 	}
 
-	return c.JSON(http.StatusOK, GroupWithUsers{
+	resp := GroupWithUsers{
 		Group: coreGroupToGroup(group.Group),
 		Users: coreUserArrayCast(group.Users),
-	})
+	}
+	h.audit(c, models.AuditGroupUpdate, models.AuditResourceGroup, groupID, coreGroupToGroup(existing), resp.Group)
+
+	return c.JSON(http.StatusOK, resp)
 }
 
 func (h *Handler) HandleDeleteGroup(c echo.Context) error {
@@ -78,7 +90,7 @@ func (h *Handler) HandleDeleteGroup(c echo.Context) error {
 		return wrapError(ErrRequiredFieldMissing, "group id cannot be empty", nil, nil)
 	}
 
-	_, err := h.co.GetGroupByUUID(c.Request().Context(), groupID)
+	existing, err := h.co.GetGroupByUUID(c.Request().Context(), groupID)
 	if err != nil {
 		return wrapError(ErrResourceNotFound, "could not get group", err, nil)
 	}
@@ -86,6 +98,7 @@ func (h *Handler) HandleDeleteGroup(c echo.Context) error {
 	if err := h.co.DeleteGroupByUUID(c.Request().Context(), groupID); err != nil {
 		return wrapError(ErrOperationFailed, "could not delete group", err, nil)
 	}
+	h.audit(c, models.AuditGroupDelete, models.AuditResourceGroup, groupID, coreGroupToGroup(existing), nil)
 
 	return c.NoContent(http.StatusOK)
 }
@@ -6,6 +6,7 @@ import (
 	"log"
 	"log/slog"
 	"net/http"
+	"runtime"
 	"strings"
 	"time"
 
@@ -13,6 +14,8 @@ import (
 	"github.com/cvhariharan/flowctl/internal/config"
 	"github.com/cvhariharan/flowctl/internal/core"
 	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/cvhariharan/flowctl/internal/gitsync"
+	"github.com/cvhariharan/flowctl/internal/securitylog"
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
 	"github.com/zerodha/simplesessions/stores/postgres/v3"
@@ -23,6 +26,9 @@ import (
 const (
 	// Pagination count per page
 	CountPerPage = 10
+
+	// NodeTestTimeout bounds connecting to a node and running the test command on it
+	NodeTestTimeout = 30 * time.Second
 )
 
 type OIDCAuthConfig struct {
@@ -39,6 +45,19 @@ type Handler struct {
 	logger             *slog.Logger
 	config             config.Config
 	executorSigningKey []byte
+	buildInfo          BuildInfo
+	gitSync            *gitsync.Syncer
+	securityLog        *securitylog.Logger
+
+	// reloadConfig applies the config file and returns the sections that need a restart
+	reloadConfig func() ([]string, error)
+}
+
+// BuildInfo describes the running flowctl binary
+type BuildInfo struct {
+	Version string
+	Commit  string
+	Date    string
 }
 
 func getCookie(name string, r interface{}) (*http.Cookie, error) {
@@ -52,7 +71,7 @@ func setCookie(cookie *http.Cookie, w interface{}) error {
 	return nil
 }
 
-func NewHandler(logger *slog.Logger, db *sql.DB, co *core.Core, cfg config.Config, executorSigningKey []byte) (*Handler, error) {
+func NewHandler(logger *slog.Logger, db *sql.DB, co *core.Core, cfg config.Config, executorSigningKey []byte, buildInfo BuildInfo, gitSync *gitsync.Syncer, securityLog *securitylog.Logger) (*Handler, error) {
 	validate := validator.New()
 	validate.RegisterValidation("alphanum_underscore", models.AlphanumericUnderscore)
 	validate.RegisterValidation("alphanum_whitespace", models.AlphanumericSpace)
@@ -68,11 +87,20 @@ func NewHandler(logger *slog.Logger, db *sql.DB, co *core.Core, cfg config.Confi
 
 	sessMgr.SetCookieHooks(getCookie, setCookie)
 
-	sessionStore, err := postgres.New(postgres.Opt{
-		TTL: SessionTimeout,
-	}, db)
-	if err != nil {
-		return nil, fmt.Errorf("could not initialize postgres session store: %w", err)
+	var sessionStore interface {
+		simplesessions.Store
+		Prune() error
+	}
+	if cfg.DB.SQLite() {
+		sessionStore = newSQLiteSessionStore(db, SessionTimeout)
+	} else {
+		pgStore, err := postgres.New(postgres.Opt{
+			TTL: SessionTimeout,
+		}, db)
+		if err != nil {
+			return nil, fmt.Errorf("could not initialize postgres session store: %w", err)
+		}
+		sessionStore = pgStore
 	}
 
 	sessMgr.UseStore(sessionStore)
@@ -84,7 +112,7 @@ func NewHandler(logger *slog.Logger, db *sql.DB, co *core.Core, cfg config.Confi
 		time.Sleep(SessionTimeout / 2)
 	}()
 
-	h := &Handler{co: co, validate: validate, logger: logger, sessMgr: sessMgr, config: cfg, authconfig: make(map[string]OIDCAuthConfig), executorSigningKey: executorSigningKey}
+	h := &Handler{co: co, validate: validate, logger: logger, sessMgr: sessMgr, config: cfg, authconfig: make(map[string]OIDCAuthConfig), executorSigningKey: executorSigningKey, buildInfo: buildInfo, gitSync: gitSync, securityLog: securityLog}
 	if err := h.initOIDC(); err != nil {
 		return nil, fmt.Errorf("error initializing oidc config: %w", err)
 	}
@@ -95,6 +123,63 @@ func (h *Handler) HandlePing(c echo.Context) error {
 	return c.NoContent(http.StatusOK)
 }
 
+func (h *Handler) HandleGetVersion(c echo.Context) error {
+	return c.JSON(http.StatusOK, VersionResp{
+		Version:   h.buildInfo.Version,
+		Commit:    h.buildInfo.Commit,
+		Date:      h.buildInfo.Date,
+		GoVersion: runtime.Version(),
+	})
+}
+
+// SetConfigReloader sets the function that reloads the config file for HandleReloadConfig
+func (h *Handler) SetConfigReloader(reload func() ([]string, error)) {
+	h.reloadConfig = reload
+}
+
+// HandleReloadConfig reloads the config file without restarting the server. Sections that
+// changed but can only be applied on start are listed in the response.
+func (h *Handler) HandleReloadConfig(c echo.Context) error {
+	if h.reloadConfig == nil {
+		return wrapError(ErrOperationFailed, "config reload is not enabled", nil, nil)
+	}
+
+	restart, err := h.reloadConfig()
+	if err != nil {
+		return wrapError(ErrOperationFailed, fmt.Sprintf("could not reload config: %v", err), err, nil)
+	}
+
+	if restart == nil {
+		restart = []string{}
+	}
+	return c.JSON(http.StatusOK, ConfigReloadResp{
+		RestartRequired: restart,
+	})
+}
+
+// HandleGetFlowLoadStatus returns the progress of loading the flows directory. Flows of
+// namespaces that are not loaded yet can't be found or executed.
+func (h *Handler) HandleGetFlowLoadStatus(c echo.Context) error {
+	status := h.co.FlowLoadStatus()
+
+	resp := FlowLoadStatusResp{
+		State:            string(status.State),
+		Namespaces:       status.Namespaces,
+		NamespacesLoaded: status.NamespacesLoaded,
+		Total:            status.Total,
+		Loaded:           status.Loaded,
+		Failed:           status.Failed,
+		Error:            status.Error,
+	}
+	if !status.StartedAt.IsZero() {
+		resp.StartedAt = status.StartedAt.Format(time.RFC3339)
+	}
+	if !status.FinishedAt.IsZero() {
+		resp.FinishedAt = status.FinishedAt.Format(time.RFC3339)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
 func formatValidationErrors(err error) string {
 	if err == nil {
 		return ""
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/cvhariharan/flowctl/internal/core"
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/labstack/echo/v4"
+)
+
+// HandleListInputPresets returns the presets of a flow the current user can trigger it with
+func (h *Handler) HandleListInputPresets(c echo.Context) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	user, err := h.getUserInfo(c)
+	if err != nil {
+		return wrapError(ErrAuthenticationFailed, "could not get user details", err, nil)
+	}
+
+	presets, err := h.co.ListInputPresets(c.Request().Context(), c.Param("flowID"), user.ID, namespace)
+	if err != nil {
+		if errors.Is(err, core.ErrFlowNotFound) {
+			return wrapError(ErrResourceNotFound, "flow not found", err, nil)
+		}
+		return wrapError(ErrOperationFailed, "could not get presets", err, nil)
+	}
+
+	resp := InputPresetsResp{Presets: make([]InputPresetResp, 0, len(presets))}
+	for _, p := range presets {
+		resp.Presets = append(resp.Presets, coreInputPresetToResp(p))
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// HandleSaveInputPreset saves a preset of the current user. Sharing a preset with the namespace
+// needs permission to update the flow.
+func (h *Handler) HandleSaveInputPreset(c echo.Context) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	user, err := h.getUserInfo(c)
+	if err != nil {
+		return wrapError(ErrAuthenticationFailed, "could not get user details", err, nil)
+	}
+
+	var req InputPresetReq
+	if err := c.Bind(&req); err != nil {
+		return wrapError(ErrInvalidInput, "invalid request", err, nil)
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return wrapError(ErrValidationFailed, fmt.Sprintf("request validation failed: %s", formatValidationErrors(err)), err, nil)
+	}
+
+	flowID := c.Param("flowID")
+	ctx := c.Request().Context()
+	if req.Shared {
+		if err := h.checkPresetManagement(ctx, user.ID, flowID, namespace); err != nil {
+			return err
+		}
+	}
+
+	preset, err := h.co.SaveInputPreset(ctx, flowID, req.Name, req.Inputs, req.Shared, user.ID, namespace)
+	if err != nil {
+		if errors.Is(err, core.ErrFlowNotFound) {
+			return wrapError(ErrResourceNotFound, "flow not found", err, nil)
+		}
+		return wrapError(ErrValidationFailed, err.Error(), err, nil)
+	}
+	preset.CreatedByName = user.Username
+
+	return c.JSON(http.StatusOK, coreInputPresetToResp(preset))
+}
+
+// HandleDeleteInputPreset deletes a preset. Shared presets of other users can only be deleted
+// with permission to update the flow.
+func (h *Handler) HandleDeleteInputPreset(c echo.Context) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	user, err := h.getUserInfo(c)
+	if err != nil {
+		return wrapError(ErrAuthenticationFailed, "could not get user details", err, nil)
+	}
+
+	flowID := c.Param("flowID")
+	presetID := c.Param("presetID")
+	ctx := c.Request().Context()
+
+	preset, err := h.co.GetInputPreset(ctx, flowID, presetID, user.ID, namespace)
+	if err != nil {
+		if errors.Is(err, core.ErrFlowNotFound) || errors.Is(err, core.ErrInputPresetNotFound) {
+			return wrapError(ErrResourceNotFound, "preset not found", err, nil)
+		}
+		return wrapError(ErrOperationFailed, "could not get preset", err, nil)
+	}
+
+	if preset.CreatedByID != user.ID {
+		if err := h.checkPresetManagement(ctx, user.ID, flowID, namespace); err != nil {
+			return err
+		}
+	}
+
+	if err := h.co.DeleteInputPreset(ctx, flowID, presetID, namespace); err != nil {
+		return wrapError(ErrOperationFailed, "could not delete preset", err, nil)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// checkPresetManagement checks that a user can manage the shared presets of a flow
+func (h *Handler) checkPresetManagement(ctx context.Context, userID, flowID, namespaceID string) error {
+	f, err := h.co.GetFlowByID(flowID, namespaceID)
+	if err != nil {
+		return wrapError(ErrResourceNotFound, "flow not found", err, nil)
+	}
+
+	allowed, err := h.co.CheckPermission(ctx, userID, core.FlowDomain(namespaceID, f.Meta.Prefix), models.ResourceFlow, models.RBACActionUpdate)
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not check permissions", err, nil)
+	}
+	if !allowed {
+		return wrapError(ErrForbidden, "insufficient permissions to manage the shared presets of the flow", nil, nil)
+	}
+	return nil
+}
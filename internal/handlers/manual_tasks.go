@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cvhariharan/flowctl/internal/core"
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/labstack/echo/v4"
+)
+
+func (h *Handler) HandleListManualTasks(c echo.Context) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	var req ManualTaskPaginateRequest
+	if err := c.Bind(&req); err != nil {
+		return wrapError(ErrInvalidInput, "could not decode request", err, nil)
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return wrapError(ErrValidationFailed, "request validation failed", err, nil)
+	}
+
+	if req.Page < 0 || req.Count < 0 {
+		return wrapError(ErrInvalidPagination, "invalid pagination parameters", nil, nil)
+	}
+
+	if req.Page > 0 {
+		req.Page -= 1
+	}
+
+	if req.Count == 0 {
+		req.Count = CountPerPage
+	}
+
+	tasks, pageCount, totalCount, err := h.co.ListManualTasks(c.Request().Context(), namespace, req.Status, req.Page+1, req.Count)
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not get manual tasks", err, nil)
+	}
+
+	resp := make([]ManualTaskResp, 0, len(tasks))
+	for _, t := range tasks {
+		resp = append(resp, coreManualTaskToResp(t))
+	}
+
+	return c.JSON(http.StatusOK, ManualTasksPaginateResponse{
+		Tasks:      resp,
+		PageCount:  pageCount,
+		TotalCount: totalCount,
+	})
+}
+
+func (h *Handler) HandleGetManualTask(c echo.Context) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	var req ManualTaskGetReq
+	if err := c.Bind(&req); err != nil {
+		return wrapError(ErrInvalidInput, "invalid request", err, nil)
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return wrapError(ErrValidationFailed, fmt.Sprintf("request validation failed: %s", formatValidationErrors(err)), err, nil)
+	}
+
+	task, err := h.co.GetManualTask(c.Request().Context(), req.TaskID, namespace)
+	if errors.Is(err, core.ErrNil) {
+		return wrapError(ErrResourceNotFound, "manual task not found", err, nil)
+	}
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not get manual task", err, nil)
+	}
+
+	return c.JSON(http.StatusOK, coreManualTaskToResp(task))
+}
+
+func (h *Handler) HandleCompleteManualTask(c echo.Context) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	var req ManualTaskCompleteReq
+	if err := c.Bind(&req); err != nil {
+		return wrapError(ErrInvalidInput, "invalid request", err, nil)
+	}
+	req.Comment = strings.TrimSpace(req.Comment)
+
+	if err := h.validate.Struct(req); err != nil {
+		return wrapError(ErrValidationFailed, fmt.Sprintf("request validation failed: %s", formatValidationErrors(err)), err, nil)
+	}
+
+	user, err := h.getUserInfo(c)
+	if err != nil {
+		return wrapError(ErrAuthenticationFailed, "could not get user details", err, nil)
+	}
+
+	err = h.co.CompleteManualTask(c.Request().Context(), req.TaskID, user.ID, req.Checklist, req.Inputs, req.Comment, namespace)
+	var verr *models.FlowValidationError
+	switch {
+	case err == nil:
+	case errors.Is(err, core.ErrNil):
+		return wrapError(ErrResourceNotFound, "manual task not found", err, nil)
+	case errors.Is(err, core.ErrNotTaskAssignee):
+		return wrapError(ErrForbidden, err.Error(), err, nil)
+	case errors.As(err, &verr):
+		return wrapError(ErrValidationFailed, "", err, FlowInputValidationError{
+			FieldName:  verr.FieldName,
+			ErrMessage: verr.Msg,
+		})
+	default:
+		return wrapError(ErrOperationFailed, "could not complete manual task", err, nil)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
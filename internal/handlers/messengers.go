@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/cvhariharan/flowctl/internal/messengers"
@@ -10,3 +11,78 @@ import (
 func (h *Handler) HandleGetMessengers(c echo.Context) error {
 	return c.JSON(http.StatusOK, messengers.GetAllSchemas())
 }
+
+func (h *Handler) HandleListMessengerConfigs(c echo.Context) error {
+	configs, err := h.co.ListMessengerConfigs(c.Request().Context())
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not list messengers", err, nil)
+	}
+
+	schemas := messengers.GetAllSchemas()
+	resp := MessengersResp{Messengers: make([]MessengerResp, 0, len(configs))}
+	for _, mc := range configs {
+		resp.Messengers = append(resp.Messengers, coreMessengerConfigToResp(mc, schemas[mc.Channel]))
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *Handler) HandleUpdateMessengerConfig(c echo.Context) error {
+	var req MessengerConfigReq
+	if err := c.Bind(&req); err != nil {
+		return wrapError(ErrInvalidInput, "could not decode request", err, nil)
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return wrapError(ErrValidationFailed, fmt.Sprintf("request validation failed: %s", formatValidationErrors(err)), err, nil)
+	}
+
+	mc, err := h.co.UpdateMessengerConfig(c.Request().Context(), req.Channel, req.Enabled, req.Config)
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not update messenger", err, nil)
+	}
+
+	return c.JSON(http.StatusOK, coreMessengerConfigToResp(mc, messengers.GetAllSchemas()[mc.Channel]))
+}
+
+func (h *Handler) HandleDeleteMessengerConfig(c echo.Context) error {
+	var req MessengerGetReq
+	if err := c.Bind(&req); err != nil {
+		return wrapError(ErrInvalidInput, "could not decode request", err, nil)
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return wrapError(ErrValidationFailed, fmt.Sprintf("request validation failed: %s", formatValidationErrors(err)), err, nil)
+	}
+
+	if err := h.co.DeleteMessengerConfig(c.Request().Context(), req.Channel); err != nil {
+		return wrapError(ErrOperationFailed, "could not reset messenger", err, nil)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+func (h *Handler) HandleTestMessenger(c echo.Context) error {
+	var req MessengerTestReq
+	if err := c.Bind(&req); err != nil {
+		return wrapError(ErrInvalidInput, "could not decode request", err, nil)
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return wrapError(ErrValidationFailed, fmt.Sprintf("request validation failed: %s", formatValidationErrors(err)), err, nil)
+	}
+
+	user, err := h.getUserInfo(c)
+	if err != nil {
+		return wrapError(ErrAuthenticationFailed, "could not get user details", err, nil)
+	}
+
+	if err := h.co.TestMessenger(c.Request().Context(), req.Channel, req.Config, user.Username); err != nil {
+		return wrapError(ErrOperationFailed, "could not send test notification", err, nil)
+	}
+
+	return c.JSON(http.StatusOK, MessengerTestResp{
+		Channel: req.Channel,
+		Message: "Test notification sent",
+	})
+}
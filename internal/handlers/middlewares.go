@@ -9,6 +9,7 @@ import (
 
 	"github.com/cvhariharan/flowctl/internal/core"
 	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/cvhariharan/flowctl/internal/securitylog"
 	"github.com/labstack/echo/v4"
 )
 
@@ -25,6 +26,20 @@ func (h *Handler) Authenticate(next echo.HandlerFunc) echo.HandlerFunc {
 			return next(c)
 		}
 
+		// Personal API tokens authenticate as the user who created them
+		apiTokenUser, err := h.authenticateAPIToken(c)
+		if err != nil {
+			e := h.securityEvent(c, securitylog.TypeAPITokenRejected, securitylog.OutcomeFailure)
+			e.Reason = err.Error()
+			h.securityLog.Log(e)
+			return wrapError(ErrAuthenticationFailed, "invalid API token", err, nil)
+		}
+		if apiTokenUser != nil {
+			c.Set("user", *apiTokenUser)
+			c.Set("is_api_token", true)
+			return next(c)
+		}
+
 		sess, err := h.sessMgr.Acquire(nil, c, c)
 		if err != nil {
 			return wrapError(ErrAuthenticationFailed, "could not get user session", err, nil)
@@ -102,6 +117,23 @@ func (h *Handler) authenticateExecutor(c echo.Context) (string, error) {
 	return executorName, nil
 }
 
+// authenticateAPIToken validates a personal API token from the Authorization header.
+// Returns nil if the request does not use an API token.
+func (h *Handler) authenticateAPIToken(c echo.Context) (*models.UserInfo, error) {
+	authHeader := c.Request().Header.Get("Authorization")
+	if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer "+core.APITokenPrefix) {
+		return nil, nil
+	}
+
+	userWithGroups, err := h.co.ValidateAPIToken(c.Request().Context(), strings.TrimPrefix(authHeader, "Bearer "))
+	if err != nil {
+		return nil, err
+	}
+
+	userInfo := userWithGroups.ToUserInfo()
+	return &userInfo, nil
+}
+
 func (h *Handler) AuthorizeForRole(expectedRole string) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
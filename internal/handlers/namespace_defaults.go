@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/cvhariharan/flowctl/sdk/executor"
+	"github.com/labstack/echo/v4"
+)
+
+// HandleGetNamespaceDefaults returns the settings inherited by the flows of the namespace
+func (h *Handler) HandleGetNamespaceDefaults(c echo.Context) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	defaults, err := h.co.GetNamespaceDefaults(c.Request().Context(), namespace)
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not get namespace defaults", err, nil)
+	}
+
+	return c.JSON(http.StatusOK, coreNamespaceDefaultsToResp(defaults))
+}
+
+func (h *Handler) HandleUpdateNamespaceDefaults(c echo.Context) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	var req NamespaceDefaultsReq
+	if err := c.Bind(&req); err != nil {
+		return wrapError(ErrInvalidInput, "could not decode request", err, nil)
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return wrapError(ErrValidationFailed, fmt.Sprintf("request validation failed: %s", formatValidationErrors(err)), err, nil)
+	}
+
+	for name := range req.ExecutorOptions {
+		if _, err := executor.GetNewExecutorFunc(name); err != nil {
+			return wrapError(ErrValidationFailed, fmt.Sprintf("unknown executor %s", name), err, nil)
+		}
+	}
+
+	defaults, err := h.co.SetNamespaceDefaults(c.Request().Context(), namespace, models.NamespaceDefaults{
+		Timezone:        req.Timezone,
+		Notify:          convertNotifyReqToNotify(req.Notify),
+		ExecutorOptions: req.ExecutorOptions,
+		MaxConcurrency:  req.MaxConcurrency,
+	})
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not update namespace defaults", err, nil)
+	}
+
+	return c.JSON(http.StatusOK, coreNamespaceDefaultsToResp(defaults))
+}
+
+func (h *Handler) HandleDeleteNamespaceDefaults(c echo.Context) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	if err := h.co.DeleteNamespaceDefaults(c.Request().Context(), namespace); err != nil {
+		return wrapError(ErrOperationFailed, "could not delete namespace defaults", err, nil)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
@@ -34,7 +34,10 @@ func (h *Handler) HandleCreateNamespaceSecret(c echo.Context) error {
 		return wrapError(ErrOperationFailed, "could not create namespace secret", err, nil)
 	}
 
-	return c.JSON(http.StatusCreated, coreNamespaceSecretToNamespaceSecretResp(created))
+	resp := coreNamespaceSecretToNamespaceSecretResp(created)
+	h.audit(c, models.AuditSecretCreate, models.ResourceNamespaceSecret, resp.ID, nil, resp)
+
+	return c.JSON(http.StatusCreated, resp)
 }
 
 func (h *Handler) HandleGetNamespaceSecret(c echo.Context) error {
@@ -94,6 +97,11 @@ func (h *Handler) HandleUpdateNamespaceSecret(c echo.Context) error {
 		return wrapError(ErrValidationFailed, fmt.Sprintf("request validation failed: %s", formatValidationErrors(err)), err, nil)
 	}
 
+	existing, err := h.co.GetNamespaceSecretByID(c.Request().Context(), req.SecretID, namespace)
+	if err != nil {
+		return wrapError(ErrResourceNotFound, "secret not found", err, nil)
+	}
+
 	secret := models.NamespaceSecret{
 		Value:       req.Value,
 		Description: req.Description,
@@ -104,7 +112,10 @@ func (h *Handler) HandleUpdateNamespaceSecret(c echo.Context) error {
 		return wrapError(ErrOperationFailed, "could not update namespace secret", err, nil)
 	}
 
-	return c.JSON(http.StatusOK, coreNamespaceSecretToNamespaceSecretResp(updated))
+	resp := coreNamespaceSecretToNamespaceSecretResp(updated)
+	h.audit(c, models.AuditSecretUpdate, models.ResourceNamespaceSecret, req.SecretID, coreNamespaceSecretToNamespaceSecretResp(existing), resp)
+
+	return c.JSON(http.StatusOK, resp)
 }
 
 func (h *Handler) HandleDeleteNamespaceSecret(c echo.Context) error {
@@ -122,10 +133,16 @@ func (h *Handler) HandleDeleteNamespaceSecret(c echo.Context) error {
 		return wrapError(ErrValidationFailed, fmt.Sprintf("request validation failed: %s", formatValidationErrors(err)), err, nil)
 	}
 
-	err := h.co.DeleteNamespaceSecret(c.Request().Context(), req.SecretID, namespace)
+	existing, err := h.co.GetNamespaceSecretByID(c.Request().Context(), req.SecretID, namespace)
+	if err != nil {
+		return wrapError(ErrResourceNotFound, "secret not found", err, nil)
+	}
+
+	err = h.co.DeleteNamespaceSecret(c.Request().Context(), req.SecretID, namespace)
 	if err != nil {
 		return wrapError(ErrOperationFailed, "could not delete namespace secret", err, nil)
 	}
+	h.audit(c, models.AuditSecretDelete, models.ResourceNamespaceSecret, req.SecretID, coreNamespaceSecretToNamespaceSecretResp(existing), nil)
 
 	return c.NoContent(http.StatusOK)
 }
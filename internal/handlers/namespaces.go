@@ -27,7 +27,10 @@ func (h *Handler) HandleCreateNamespace(c echo.Context) error {
 		return wrapError(ErrOperationFailed, "could not create namespace", err, nil)
 	}
 
-	return c.JSON(http.StatusCreated, coreNamespaceToNamespaceResp(created))
+	resp := coreNamespaceToNamespaceResp(created)
+	h.audit(c, models.AuditNamespaceCreate, models.ResourceNamespace, resp.ID, nil, resp)
+
+	return c.JSON(http.StatusCreated, resp)
 }
 
 func (h *Handler) HandleGetNamespace(c echo.Context) error {
@@ -94,6 +97,11 @@ func (h *Handler) HandleUpdateNamespace(c echo.Context) error {
 		return wrapError(ErrValidationFailed, fmt.Sprintf("request validation failed: %s", formatValidationErrors(err)), err, nil)
 	}
 
+	existing, err := h.co.GetNamespaceByID(c.Request().Context(), namespaceID)
+	if err != nil {
+		return wrapError(ErrResourceNotFound, "namespace not found", err, nil)
+	}
+
 	namespace := &models.Namespace{
 		Name: req.Name,
 	}
@@ -103,7 +111,10 @@ func (h *Handler) HandleUpdateNamespace(c echo.Context) error {
 		return wrapError(ErrOperationFailed, "could not update namespace", err, nil)
 	}
 
-	return c.JSON(http.StatusOK, coreNamespaceToNamespaceResp(updated))
+	resp := coreNamespaceToNamespaceResp(updated)
+	h.audit(c, models.AuditNamespaceUpdate, models.ResourceNamespace, namespaceID, coreNamespaceToNamespaceResp(existing), resp)
+
+	return c.JSON(http.StatusOK, resp)
 }
 
 func (h *Handler) HandleDeleteNamespace(c echo.Context) error {
@@ -112,10 +123,15 @@ func (h *Handler) HandleDeleteNamespace(c echo.Context) error {
 		return wrapError(ErrRequiredFieldMissing, "namespace ID cannot be empty", nil, nil)
 	}
 
-	err := h.co.DeleteNamespace(c.Request().Context(), namespaceID)
+	existing, err := h.co.GetNamespaceByID(c.Request().Context(), namespaceID)
 	if err != nil {
+		return wrapError(ErrResourceNotFound, "namespace not found", err, nil)
+	}
+
+	if err := h.co.DeleteNamespace(c.Request().Context(), namespaceID); err != nil {
 		return wrapError(ErrOperationFailed, "could not delete namespace", err, nil)
 	}
+	h.audit(c, models.AuditNamespaceDelete, models.ResourceNamespace, namespaceID, coreNamespaceToNamespaceResp(existing), nil)
 
 	return c.NoContent(http.StatusOK)
 }
@@ -135,11 +151,17 @@ func (h *Handler) HandleAddNamespaceMember(c echo.Context) error {
 		return wrapError(ErrValidationFailed, fmt.Sprintf("request validation failed: %s", formatValidationErrors(err)), err, nil)
 	}
 
+	isSubject := func(m NamespaceMemberResp) bool {
+		return m.SubjectID == req.SubjectID && m.SubjectType == req.SubjectType
+	}
+	before := h.namespaceMember(c.Request().Context(), namespace, isSubject)
+
 	role := models.NamespaceRole(req.Role)
 	err := h.co.AssignNamespaceRole(c.Request().Context(), req.SubjectID, req.SubjectType, namespace, role)
 	if err != nil {
 		return wrapError(ErrOperationFailed, "could not assign role", err, nil)
 	}
+	h.audit(c, models.AuditMemberAdd, models.ResourceMember, req.SubjectID, before, h.namespaceMember(c.Request().Context(), namespace, isSubject))
 
 	return c.NoContent(http.StatusOK)
 }
@@ -178,11 +200,15 @@ func (h *Handler) HandleUpdateNamespaceMember(c echo.Context) error {
 		return wrapError(ErrValidationFailed, fmt.Sprintf("request validation failed: %s", formatValidationErrors(err)), err, nil)
 	}
 
+	isMember := func(m NamespaceMemberResp) bool { return m.ID == membershipID }
+	before := h.namespaceMember(c.Request().Context(), namespace, isMember)
+
 	role := models.NamespaceRole(req.Role)
 	err := h.co.UpdateNamespaceMember(c.Request().Context(), membershipID, namespace, role)
 	if err != nil {
 		return wrapError(ErrOperationFailed, "could not update namespace member", err, nil)
 	}
+	h.audit(c, models.AuditMemberUpdate, models.ResourceMember, membershipID, before, h.namespaceMember(c.Request().Context(), namespace, isMember))
 
 	return c.NoContent(http.StatusOK)
 }
@@ -198,10 +224,13 @@ func (h *Handler) HandleRemoveNamespaceMember(c echo.Context) error {
 		return wrapError(ErrRequiredFieldMissing, "subject ID cannot be empty", nil, nil)
 	}
 
+	before := h.namespaceMember(c.Request().Context(), namespace, func(m NamespaceMemberResp) bool { return m.ID == membershipID })
+
 	err := h.co.RemoveNamespaceMember(c.Request().Context(), membershipID, namespace)
 	if err != nil {
 		return wrapError(ErrOperationFailed, "could not remove namespace member", err, nil)
 	}
+	h.audit(c, models.AuditMemberRemove, models.ResourceMember, membershipID, before, nil)
 
 	return c.NoContent(http.StatusOK)
 }
@@ -257,6 +286,7 @@ func (h *Handler) HandleGrantGroupAccess(c echo.Context) error {
 	if err := h.co.GrantPrefixAccessForMember(c.Request().Context(), namespace, membershipID, req.Prefix); err != nil {
 		return wrapError(ErrOperationFailed, "could not grant group access", err, nil)
 	}
+	h.audit(c, models.AuditMemberGrantAccess, models.ResourceMember, membershipID, nil, map[string]string{"group": req.Prefix})
 
 	return c.NoContent(http.StatusOK)
 }
@@ -280,6 +310,7 @@ func (h *Handler) HandleRevokeGroupAccess(c echo.Context) error {
 	if err := h.co.RevokePrefixAccessForMember(c.Request().Context(), namespace, membershipID, group); err != nil {
 		return wrapError(ErrOperationFailed, "could not revoke group access", err, nil)
 	}
+	h.audit(c, models.AuditMemberRevokeAccess, models.ResourceMember, membershipID, map[string]string{"group": group}, nil)
 
 	return c.NoContent(http.StatusOK)
 }
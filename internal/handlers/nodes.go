@@ -1,9 +1,12 @@
 package handlers
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 
+	"github.com/cvhariharan/flowctl/internal/core"
 	"github.com/cvhariharan/flowctl/internal/core/models"
 	"github.com/labstack/echo/v4"
 )
@@ -28,12 +31,11 @@ func (h *Handler) HandleCreateNode(c echo.Context) error {
 	}
 
 	node := &models.Node{
-		Name:     req.Name,
-		Hostname: req.Hostname,
-		Port:     req.Port,
-		Username: req.Username,
-		// Only linux is supported right now
-		OSFamily:       "linux",
+		Name:           req.Name,
+		Hostname:       req.Hostname,
+		Port:           req.Port,
+		Username:       req.Username,
+		OSFamily:       req.OSFamily,
 		ConnectionType: req.ConnectionType,
 		Tags:           req.Tags,
 		Auth: models.NodeAuth{
@@ -43,11 +45,17 @@ func (h *Handler) HandleCreateNode(c echo.Context) error {
 	}
 
 	created, err := h.co.CreateNode(c.Request().Context(), node, namespace)
+	if errors.Is(err, core.ErrQuotaExceeded) {
+		return wrapError(ErrQuotaExceeded, err.Error(), err, nil)
+	}
 	if err != nil {
 		return wrapError(ErrOperationFailed, "could not create node", err, nil)
 	}
 
-	return c.JSON(http.StatusCreated, coreNodeToNodeResp(created))
+	resp := coreNodeToNodeResp(created)
+	h.audit(c, models.AuditNodeCreate, models.ResourceNode, resp.ID, nil, resp)
+
+	return c.JSON(http.StatusCreated, resp)
 }
 
 func (h *Handler) HandleGetNode(c echo.Context) error {
@@ -124,13 +132,17 @@ func (h *Handler) HandleUpdateNode(c echo.Context) error {
 		return wrapError(ErrValidationFailed, fmt.Sprintf("request validation failed: %s", formatValidationErrors(err)), err, nil)
 	}
 
+	existing, err := h.co.GetNodeByID(c.Request().Context(), nodeID, namespace)
+	if err != nil {
+		return wrapError(ErrResourceNotFound, "node not found", err, nil)
+	}
+
 	node := &models.Node{
-		Name:     req.Name,
-		Hostname: req.Hostname,
-		Port:     req.Port,
-		Username: req.Username,
-		// Only linux is supported right now
-		OSFamily:       "linux",
+		Name:           req.Name,
+		Hostname:       req.Hostname,
+		Port:           req.Port,
+		Username:       req.Username,
+		OSFamily:       req.OSFamily,
 		ConnectionType: req.ConnectionType,
 		Tags:           req.Tags,
 		Auth: models.NodeAuth{
@@ -144,7 +156,10 @@ func (h *Handler) HandleUpdateNode(c echo.Context) error {
 		return wrapError(ErrOperationFailed, "could not update node", err, nil)
 	}
 
-	return c.JSON(http.StatusOK, coreNodeToNodeResp(updated))
+	resp := coreNodeToNodeResp(updated)
+	h.audit(c, models.AuditNodeUpdate, models.ResourceNode, nodeID, coreNodeToNodeResp(existing), resp)
+
+	return c.JSON(http.StatusOK, resp)
 }
 
 func (h *Handler) HandleDeleteNode(c echo.Context) error {
@@ -158,10 +173,16 @@ func (h *Handler) HandleDeleteNode(c echo.Context) error {
 		return wrapError(ErrRequiredFieldMissing, "node ID cannot be empty", nil, nil)
 	}
 
-	err := h.co.DeleteNode(c.Request().Context(), nodeID, namespace)
+	existing, err := h.co.GetNodeByID(c.Request().Context(), nodeID, namespace)
+	if err != nil {
+		return wrapError(ErrResourceNotFound, "node not found", err, nil)
+	}
+
+	err = h.co.DeleteNode(c.Request().Context(), nodeID, namespace)
 	if err != nil {
 		return wrapError(ErrOperationFailed, "could not delete node", err, nil)
 	}
+	h.audit(c, models.AuditNodeDelete, models.ResourceNode, nodeID, coreNodeToNodeResp(existing), nil)
 
 	return c.NoContent(http.StatusOK)
 }
@@ -183,3 +204,30 @@ func (h *Handler) HandleGetNodeStats(c echo.Context) error {
 		QSSHHosts:  stats.QSSHHosts,
 	})
 }
+
+func (h *Handler) HandleTestNode(c echo.Context) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	nodeID := c.Param("nodeID")
+	if nodeID == "" {
+		return wrapError(ErrRequiredFieldMissing, "node ID cannot be empty", nil, nil)
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request().Context(), NodeTestTimeout)
+	defer cancel()
+
+	result, err := h.co.TestNode(ctx, nodeID, namespace)
+	if err != nil {
+		return wrapError(ErrResourceNotFound, "node not found", err, nil)
+	}
+
+	return c.JSON(http.StatusOK, NodeTestResp{
+		Success:    result.Success,
+		Output:     result.Output,
+		Error:      result.Error,
+		DurationMs: result.Duration.Milliseconds(),
+	})
+}
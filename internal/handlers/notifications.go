@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (h *Handler) HandleListNotificationDeliveries(c echo.Context) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	var req NotificationDeliveryPaginateRequest
+	if err := c.Bind(&req); err != nil {
+		return wrapError(ErrInvalidInput, "could not decode request", err, nil)
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return wrapError(ErrValidationFailed, fmt.Sprintf("request validation failed: %s", formatValidationErrors(err)), err, nil)
+	}
+
+	if req.Page < 0 || req.Count < 0 {
+		return wrapError(ErrInvalidPagination, "invalid pagination parameters", nil, nil)
+	}
+
+	if req.Page > 0 {
+		req.Page -= 1
+	}
+
+	if req.Count == 0 {
+		req.Count = CountPerPage
+	}
+
+	deliveries, pageCount, totalCount, err := h.co.GetNotificationDeliveriesPaginated(c.Request().Context(), namespace, req.Status, req.ExecID, req.Page+1, req.Count)
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not get notification deliveries", err, nil)
+	}
+
+	resp := make([]NotificationDeliveryResp, len(deliveries))
+	for i, d := range deliveries {
+		resp[i] = NotificationDeliveryResp{
+			ID:        d.ID,
+			ExecID:    d.ExecID,
+			FlowID:    d.FlowID,
+			Channel:   d.Channel,
+			Receiver:  d.Receiver,
+			Event:     d.Event,
+			Status:    d.Status,
+			Attempts:  d.Attempts,
+			LastError: d.LastError,
+			CreatedAt: d.CreatedAt.Format(TimeFormat),
+			UpdatedAt: d.UpdatedAt.Format(TimeFormat),
+		}
+	}
+
+	return c.JSON(http.StatusOK, NotificationDeliveriesPaginateResponse{
+		Deliveries: resp,
+		PageCount:  pageCount,
+		TotalCount: totalCount,
+	})
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/labstack/echo/v4"
+)
+
+// HandleGetNamespaceUsage returns the quota of the namespace and how much of it is used
+func (h *Handler) HandleGetNamespaceUsage(c echo.Context) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	usage, err := h.co.GetNamespaceUsage(c.Request().Context(), namespace)
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not get namespace usage", err, nil)
+	}
+
+	return c.JSON(http.StatusOK, coreNamespaceUsageToResp(usage))
+}
+
+// HandleUpdateNamespaceQuota sets the quota of a namespace. Quotas are managed by superusers so
+// that namespace admins can't raise their own limits.
+func (h *Handler) HandleUpdateNamespaceQuota(c echo.Context) error {
+	namespaceID := c.Param("namespaceID")
+	if namespaceID == "" {
+		return wrapError(ErrRequiredFieldMissing, "namespace ID cannot be empty", nil, nil)
+	}
+
+	var req NamespaceQuotaReq
+	if err := c.Bind(&req); err != nil {
+		return wrapError(ErrInvalidInput, "could not decode request", err, nil)
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return wrapError(ErrValidationFailed, fmt.Sprintf("request validation failed: %s", formatValidationErrors(err)), err, nil)
+	}
+
+	quota, err := h.co.SetNamespaceQuota(c.Request().Context(), namespaceID, models.NamespaceQuota{
+		MaxExecutionsPerDay:     req.MaxExecutionsPerDay,
+		MaxConcurrentExecutions: req.MaxConcurrentExecutions,
+		MaxNodes:                req.MaxNodes,
+		MaxLogBytes:             req.MaxLogBytes,
+	})
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not update namespace quota", err, nil)
+	}
+
+	return c.JSON(http.StatusOK, coreNamespaceQuotaToResp(quota))
+}
+
+func (h *Handler) HandleDeleteNamespaceQuota(c echo.Context) error {
+	namespaceID := c.Param("namespaceID")
+	if namespaceID == "" {
+		return wrapError(ErrRequiredFieldMissing, "namespace ID cannot be empty", nil, nil)
+	}
+
+	if err := h.co.DeleteNamespaceQuota(c.Request().Context(), namespaceID); err != nil {
+		return wrapError(ErrOperationFailed, "could not delete namespace quota", err, nil)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
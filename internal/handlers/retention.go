@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/labstack/echo/v4"
+)
+
+func (h *Handler) HandleGetRetentionPolicy(c echo.Context) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	policy, err := h.co.GetRetentionPolicy(c.Request().Context(), namespace)
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not get retention policy", err, nil)
+	}
+
+	return c.JSON(http.StatusOK, coreRetentionPolicyToResp(policy))
+}
+
+func (h *Handler) HandleUpdateRetentionPolicy(c echo.Context) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	var req RetentionPolicyReq
+	if err := c.Bind(&req); err != nil {
+		return wrapError(ErrInvalidInput, "could not decode request", err, nil)
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return wrapError(ErrValidationFailed, fmt.Sprintf("request validation failed: %s", formatValidationErrors(err)), err, nil)
+	}
+
+	policy, err := h.co.SetRetentionPolicy(c.Request().Context(), namespace, models.RetentionPolicy{
+		MaxAgeDays: req.MaxAgeDays,
+		KeepLast:   req.KeepLast,
+	})
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not update retention policy", err, nil)
+	}
+
+	return c.JSON(http.StatusOK, coreRetentionPolicyToResp(policy))
+}
+
+func (h *Handler) HandleDeleteRetentionPolicy(c echo.Context) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	if err := h.co.DeleteRetentionPolicy(c.Request().Context(), namespace); err != nil {
+		return wrapError(ErrOperationFailed, "could not delete retention policy", err, nil)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// HandlePreviewRetention is a dry run of the retention policy, it reports what the next purge would remove
+func (h *Handler) HandlePreviewRetention(c echo.Context) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	preview, err := h.co.PreviewRetention(c.Request().Context(), namespace)
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not preview retention policy", err, nil)
+	}
+
+	return c.JSON(http.StatusOK, coreRetentionPreviewToResp(preview))
+}
@@ -3,7 +3,9 @@ package handlers
 import (
 	"fmt"
 	"net/http"
+	"time"
 
+	"github.com/cvhariharan/flowctl/internal/core/models"
 	"github.com/labstack/echo/v4"
 )
 
@@ -107,6 +109,67 @@ func (h *Handler) HandleListSchedules(c echo.Context) error {
 	})
 }
 
+// HandleGetScheduleLag lists the cron schedules of the namespace with when they last fired
+// and how late their executions started
+func (h *Handler) HandleGetScheduleLag(c echo.Context) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	lag, err := h.co.GetScheduleLag(c.Request().Context(), namespace)
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not get schedule lag", err, nil)
+	}
+
+	return c.JSON(http.StatusOK, coreScheduleLagToResps(lag))
+}
+
+// HandleGetScheduleCalendar lists when the active schedules of the namespace fire in a time
+// range, a week from now unless set
+func (h *Handler) HandleGetScheduleCalendar(c echo.Context) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	var req ScheduleCalendarReq
+	if err := c.Bind(&req); err != nil {
+		return wrapError(ErrInvalidInput, "invalid request", err, nil)
+	}
+
+	var err error
+	from := time.Now()
+	if req.From != "" {
+		from, err = time.Parse(time.RFC3339, req.From)
+		if err != nil {
+			return wrapError(ErrValidationFailed, "invalid from format, expected RFC3339", err, nil)
+		}
+	}
+
+	to := from.Add(models.ScheduleCalendarDefaultWindow)
+	if req.To != "" {
+		to, err = time.Parse(time.RFC3339, req.To)
+		if err != nil {
+			return wrapError(ErrValidationFailed, "invalid to format, expected RFC3339", err, nil)
+		}
+	}
+
+	if !from.Before(to) {
+		return wrapError(ErrValidationFailed, "from should be before to", nil, nil)
+	}
+	if to.Sub(from) > models.ScheduleCalendarMaxWindow {
+		return wrapError(ErrValidationFailed, "range cannot be longer than 90 days", nil, nil)
+	}
+
+	occurrences, truncated, err := h.co.GetScheduleCalendar(c.Request().Context(), namespace, from, to)
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not get schedule calendar", err, nil)
+	}
+
+	return c.JSON(http.StatusOK, coreScheduleOccurrencesToCalendarResp(from, to, occurrences, truncated))
+}
+
 func (h *Handler) HandleUpdateSchedule(c echo.Context) error {
 	namespace, ok := c.Get("namespace").(string)
 	if !ok {
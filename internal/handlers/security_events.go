@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/cvhariharan/flowctl/internal/securitylog"
+	"github.com/labstack/echo/v4"
+)
+
+// securityEvent returns an event with the details of the request and the authenticated user
+func (h *Handler) securityEvent(c echo.Context, eventType string, outcome string) securitylog.Event {
+	e := securitylog.Event{
+		Type:      eventType,
+		Outcome:   outcome,
+		SourceIP:  c.RealIP(),
+		Namespace: c.Param("namespace"),
+		Method:    c.Request().Method,
+		Path:      c.Request().URL.Path,
+	}
+	if user, ok := c.Get("user").(models.UserInfo); ok {
+		e.UserID = user.ID
+		e.Username = user.Username
+	}
+	return e
+}
+
+// logSecurityEvent logs an event of the request. A failure is recorded if err is not nil, with
+// the message returned to the client as the reason.
+func (h *Handler) logSecurityEvent(c echo.Context, eventType string, err error, details map[string]any) {
+	outcome := securitylog.OutcomeSuccess
+	if err != nil {
+		outcome = securitylog.OutcomeFailure
+	}
+
+	e := h.securityEvent(c, eventType, outcome)
+	if err != nil {
+		var he *HTTPError
+		if errors.As(err, &he) {
+			e.Reason = he.msg
+		} else {
+			e.Reason = err.Error()
+		}
+	}
+	e.Details = details
+
+	h.securityLog.Log(e)
+}
+
+// LogCredentialAccess records reads and changes of credentials, secrets and API tokens as
+// security events. The path parameters identify what was accessed.
+func (h *Handler) LogCredentialAccess(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		err := next(c)
+
+		details := make(map[string]any)
+		for _, name := range c.ParamNames() {
+			if name != "namespace" {
+				details[name] = c.Param(name)
+			}
+		}
+		h.logSecurityEvent(c, securitylog.TypeCredentialAccess, err, details)
+
+		return err
+	}
+}
+
+// logLogin records a login attempt of username with the given method
+func (h *Handler) logLogin(c echo.Context, username string, method string, err error) {
+	outcome := securitylog.OutcomeSuccess
+	if err != nil {
+		outcome = securitylog.OutcomeFailure
+	}
+
+	e := h.securityEvent(c, securitylog.TypeLogin, outcome)
+	e.Username = username
+	e.Details = map[string]any{"method": method}
+	if err != nil {
+		e.Reason = err.Error()
+	}
+
+	h.securityLog.Log(e)
+}
@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// sqliteSessionStore is the simplesessions store of SQLite databases. It keeps sessions in the
+// sessions table like the postgres store does, as JSON objects.
+type sqliteSessionStore struct {
+	db  *sql.DB
+	ttl time.Duration
+}
+
+// sessionStoreErr carries the error codes the simplesessions manager maps to its own errors
+type sessionStoreErr struct {
+	code int
+	msg  string
+}
+
+func (e *sessionStoreErr) Error() string {
+	return e.msg
+}
+
+func (e *sessionStoreErr) Code() int {
+	return e.code
+}
+
+var (
+	errSessionInvalid    = &sessionStoreErr{code: 1, msg: "invalid session"}
+	errSessionAssertType = &sessionStoreErr{code: 3, msg: "assertion failed"}
+)
+
+func newSQLiteSessionStore(db *sql.DB, ttl time.Duration) *sqliteSessionStore {
+	return &sqliteSessionStore{db: db, ttl: ttl}
+}
+
+func (s *sqliteSessionStore) Create(id string) error {
+	_, err := s.db.Exec("INSERT INTO sessions (id, data) VALUES ($1, '{}')", id)
+	return err
+}
+
+func (s *sqliteSessionStore) Get(id, key string) (interface{}, error) {
+	vals, err := s.GetAll(id)
+	if err != nil {
+		return nil, err
+	}
+	return vals[key], nil
+}
+
+func (s *sqliteSessionStore) GetMulti(id string, keys ...string) (map[string]interface{}, error) {
+	vals, err := s.GetAll(id)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]interface{}, len(keys))
+	for _, k := range keys {
+		v, ok := vals[k]
+		if !ok {
+			return nil, nil
+		}
+		out[k] = v
+	}
+	return out, nil
+}
+
+func (s *sqliteSessionStore) GetAll(id string) (map[string]interface{}, error) {
+	var b []byte
+	err := s.db.QueryRow("SELECT data FROM sessions WHERE id = $1 AND created_at >= $2", id, time.Now().Add(-s.ttl)).Scan(&b)
+	if err == sql.ErrNoRows {
+		return nil, errSessionInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]interface{})
+	if err := json.Unmarshal(b, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *sqliteSessionStore) Set(id, key string, val interface{}) error {
+	return s.SetMulti(id, map[string]interface{}{key: val})
+}
+
+func (s *sqliteSessionStore) SetMulti(id string, data map[string]interface{}) error {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return s.update(id, "json_patch(data, $2)", string(b))
+}
+
+func (s *sqliteSessionStore) Delete(id string, keys ...string) error {
+	expr := "data"
+	args := make([]interface{}, len(keys))
+	for i, k := range keys {
+		expr += ", $" + strconv.Itoa(i+2)
+		args[i] = `$."` + k + `"`
+	}
+	return s.update(id, "json_remove("+expr+")", args...)
+}
+
+func (s *sqliteSessionStore) Clear(id string) error {
+	return s.update(id, "'{}'")
+}
+
+func (s *sqliteSessionStore) Destroy(id string) error {
+	res, err := s.db.Exec("DELETE FROM sessions WHERE id = $1", id)
+	return sessionChanged(res, err)
+}
+
+// update sets the data of the session to the expression data
+func (s *sqliteSessionStore) update(id, data string, args ...interface{}) error {
+	res, err := s.db.Exec("UPDATE sessions SET data = "+data+" WHERE id = $1", append([]interface{}{id}, args...)...)
+	return sessionChanged(res, err)
+}
+
+// sessionChanged returns errSessionInvalid if no session was changed, as the session didn't exist
+func sessionChanged(res sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return errSessionInvalid
+	}
+	return nil
+}
+
+// Prune deletes the sessions that have exceeded the TTL
+func (s *sqliteSessionStore) Prune() error {
+	_, err := s.db.Exec("DELETE FROM sessions WHERE created_at < $1", time.Now().Add(-s.ttl))
+	return err
+}
+
+// Values are decoded from JSON, numbers are float64 like they are with the postgres store
+
+func (s *sqliteSessionStore) Int(r interface{}, err error) (int, error) {
+	v, err := s.Float64(r, err)
+	return int(v), err
+}
+
+func (s *sqliteSessionStore) Int64(r interface{}, err error) (int64, error) {
+	v, err := s.Float64(r, err)
+	return int64(v), err
+}
+
+func (s *sqliteSessionStore) UInt64(r interface{}, err error) (uint64, error) {
+	v, err := s.Float64(r, err)
+	return uint64(v), err
+}
+
+func (s *sqliteSessionStore) Float64(r interface{}, err error) (float64, error) {
+	if err != nil {
+		return 0, err
+	}
+	v, ok := r.(float64)
+	if !ok {
+		return 0, errSessionAssertType
+	}
+	return v, nil
+}
+
+func (s *sqliteSessionStore) String(r interface{}, err error) (string, error) {
+	if err != nil {
+		return "", err
+	}
+	v, ok := r.(string)
+	if !ok {
+		return "", errSessionAssertType
+	}
+	return v, nil
+}
+
+func (s *sqliteSessionStore) Bytes(r interface{}, err error) ([]byte, error) {
+	v, err := s.String(r, err)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(v), nil
+}
+
+func (s *sqliteSessionStore) Bool(r interface{}, err error) (bool, error) {
+	if err != nil {
+		return false, err
+	}
+	v, ok := r.(bool)
+	if !ok {
+		return false, errSessionAssertType
+	}
+	return v, nil
+}
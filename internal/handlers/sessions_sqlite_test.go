@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cvhariharan/flowctl/internal/repo"
+)
+
+func TestSQLiteSessionStore(t *testing.T) {
+	db, err := repo.OpenSQLite(filepath.Join(t.TempDir(), "flowctl.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	schema, err := os.ReadFile("../../migrations_sqlite/00048_create_initial_schema.up.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(string(schema)); err != nil {
+		t.Fatal(err)
+	}
+
+	s := newSQLiteSessionStore(db.DB, time.Hour)
+	if err := s.Create("sess"); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := s.SetMulti("sess", map[string]interface{}{"user": "alice", "expiry": 60, "admin": true}); err != nil {
+		t.Fatalf("SetMulti() error = %v", err)
+	}
+	if err := s.Set("sess", "method", "oidc"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	if v, err := s.String(s.Get("sess", "user")); err != nil || v != "alice" {
+		t.Errorf("Get(user) = %q, %v, want alice", v, err)
+	}
+	if v, err := s.Int(s.Get("sess", "expiry")); err != nil || v != 60 {
+		t.Errorf("Get(expiry) = %d, %v, want 60", v, err)
+	}
+	if v, err := s.Bool(s.Get("sess", "admin")); err != nil || !v {
+		t.Errorf("Get(admin) = %v, %v, want true", v, err)
+	}
+	if _, err := s.Int(s.Get("sess", "user")); !errors.Is(err, errSessionAssertType) {
+		t.Errorf("Int(user) error = %v, want %v", err, errSessionAssertType)
+	}
+
+	if err := s.Delete("sess", "user", "admin"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	all, err := s.GetAll("sess")
+	if err != nil {
+		t.Fatalf("GetAll() error = %v", err)
+	}
+	if len(all) != 2 || all["method"] != "oidc" {
+		t.Errorf("GetAll() = %v, want expiry and method", all)
+	}
+
+	if err := s.Destroy("sess"); err != nil {
+		t.Fatalf("Destroy() error = %v", err)
+	}
+	if _, err := s.GetAll("sess"); !errors.Is(err, errSessionInvalid) {
+		t.Errorf("GetAll() error = %v after destroy, want %v", err, errSessionInvalid)
+	}
+	if err := s.Set("sess", "user", "alice"); !errors.Is(err, errSessionInvalid) {
+		t.Errorf("Set() error = %v after destroy, want %v", err, errSessionInvalid)
+	}
+
+	expired := newSQLiteSessionStore(db.DB, -time.Minute)
+	if err := expired.Create("old"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := expired.GetAll("old"); !errors.Is(err, errSessionInvalid) {
+		t.Errorf("GetAll() error = %v for an expired session, want %v", err, errSessionInvalid)
+	}
+	if err := expired.Prune(); err != nil {
+		t.Fatalf("Prune() error = %v", err)
+	}
+	var n int
+	if err := db.Get(&n, "SELECT COUNT(*) FROM sessions"); err != nil || n != 0 {
+		t.Errorf("%d sessions after prune, %v, want none", n, err)
+	}
+}
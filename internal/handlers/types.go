@@ -107,6 +107,14 @@ type FlowLogResp struct {
 	Results   map[string]string `json:"results,omitempty"`
 }
 
+type ExecutionEventResp struct {
+	ExecID      string `json:"exec_id"`
+	FlowID      string `json:"flow_id"`
+	Status      string `json:"status"`
+	TriggeredBy string `json:"triggered_by"`
+	Timestamp   string `json:"timestamp"`
+}
+
 type PaginateRequest struct {
 	Filter string `query:"filter"`
 	Page   int    `query:"page"`
@@ -135,6 +143,7 @@ type GroupsPaginateResponse struct {
 type ApprovalActionReq struct {
 	ApprovalID string `param:"approvalID" validate:"required,uuid4"`
 	Action     string `json:"action" validate:"required,oneof=approve reject"`
+	Comment    string `json:"comment" validate:"required_if=Action reject,max=1000"`
 }
 
 type ApprovalGetReq struct {
@@ -174,7 +183,9 @@ type ApprovalDetailsResp struct {
 	FlowName    string          `json:"flow_name"`
 	FlowID      string          `json:"flow_id"`
 	DecidedBy   string          `json:"approved_by"`
+	Comment     string          `json:"comment"`
 	RequestedBy string          `json:"requested_by"`
+	Assignees   []string        `json:"assignees"`
 	CreatedAt   string          `json:"created_at"`
 	UpdatedAt   string          `json:"updated_at"`
 }
@@ -185,6 +196,124 @@ type ApprovalsPaginateResponse struct {
 	TotalCount int64          `json:"total_count"`
 }
 
+type ApprovalDelegationReq struct {
+	DelegateType string    `json:"delegate_type" validate:"required,oneof=user group"`
+	DelegateID   string    `json:"delegate_id" validate:"required,uuid4"`
+	StartsAt     time.Time `json:"starts_at" validate:"required"`
+	EndsAt       time.Time `json:"ends_at" validate:"required,gtfield=StartsAt"`
+	Reason       string    `json:"reason" validate:"max=255"`
+}
+
+type ApprovalDelegationGetReq struct {
+	DelegationID string `param:"delegationID" validate:"required,uuid4"`
+}
+
+type ApprovalDelegationResp struct {
+	ID            string `json:"id"`
+	DelegatorID   string `json:"delegator_id"`
+	DelegatorName string `json:"delegator_name"`
+	DelegateType  string `json:"delegate_type"`
+	DelegateID    string `json:"delegate_id"`
+	DelegateName  string `json:"delegate_name"`
+	StartsAt      string `json:"starts_at"`
+	EndsAt        string `json:"ends_at"`
+	Reason        string `json:"reason"`
+	Active        bool   `json:"active"`
+	CreatedAt     string `json:"created_at"`
+}
+
+func coreApprovalDelegationToResp(d models.ApprovalDelegation) ApprovalDelegationResp {
+	return ApprovalDelegationResp{
+		ID:            d.ID,
+		DelegatorID:   d.DelegatorID,
+		DelegatorName: d.DelegatorName,
+		DelegateType:  d.DelegateType,
+		DelegateID:    d.DelegateID,
+		DelegateName:  d.DelegateName,
+		StartsAt:      d.StartsAt.Format(TimeFormat),
+		EndsAt:        d.EndsAt.Format(TimeFormat),
+		Reason:        d.Reason,
+		Active:        d.IsActive(time.Now()),
+		CreatedAt:     d.CreatedAt.Format(TimeFormat),
+	}
+}
+
+type MessengerGetReq struct {
+	Channel string `param:"channel" validate:"required,oneof=email webhook pagerduty opsgenie slack"`
+}
+
+type MessengerConfigReq struct {
+	MessengerGetReq
+	Enabled bool           `json:"enabled"`
+	Config  map[string]any `json:"config"`
+}
+
+type MessengerTestReq struct {
+	MessengerGetReq
+	Config map[string]any `json:"config"`
+}
+
+type MessengerTestResp struct {
+	Channel string `json:"channel"`
+	Message string `json:"message"`
+}
+
+type MessengerResp struct {
+	Channel   string         `json:"channel"`
+	Enabled   bool           `json:"enabled"`
+	Source    string         `json:"source"`
+	Config    map[string]any `json:"config,omitempty"`
+	Schema    any            `json:"schema,omitempty"`
+	UpdatedAt string         `json:"updated_at,omitempty"`
+}
+
+type MessengersResp struct {
+	Messengers []MessengerResp `json:"messengers"`
+}
+
+func coreMessengerConfigToResp(mc models.MessengerConfig, schema any) MessengerResp {
+	var updatedAt string
+	if !mc.UpdatedAt.IsZero() {
+		updatedAt = mc.UpdatedAt.Format(TimeFormat)
+	}
+
+	return MessengerResp{
+		Channel:   mc.Channel,
+		Enabled:   mc.Enabled,
+		Source:    mc.Source,
+		Config:    mc.Config,
+		Schema:    schema,
+		UpdatedAt: updatedAt,
+	}
+}
+
+type NotificationDeliveryPaginateRequest struct {
+	Status string `query:"status" validate:"omitempty,oneof=delivered retrying failed"`
+	ExecID string `query:"exec_id" validate:"omitempty,max=36"`
+	Page   int    `query:"page"`
+	Count  int    `query:"count_per_page"`
+}
+
+type NotificationDeliveryResp struct {
+	ID        string `json:"id"`
+	ExecID    string `json:"exec_id"`
+	FlowID    string `json:"flow_id"`
+	Channel   string `json:"channel"`
+	Receiver  string `json:"receiver"`
+	Event     string `json:"event"`
+	Status    string `json:"status"`
+	Attempts  int    `json:"attempts"`
+	LastError string `json:"last_error"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+type NotificationDeliveriesPaginateResponse struct {
+	Deliveries []NotificationDeliveryResp `json:"deliveries"`
+	PageCount  int64                      `json:"page_count"`
+	TotalCount int64                      `json:"total_count"`
+}
+
 // Node related types
 type NodeAuth struct {
 	Method       string `json:"method" validate:"required,oneof=private_key password"`
@@ -199,7 +328,7 @@ type NodeReq struct {
 	ConnectionType string   `json:"connection_type" validate:"required,oneof=ssh qssh"`
 	Tags           []string `json:"tags" validate:"omitempty,dive,alphanum_underscore"`
 	Auth           NodeAuth `json:"auth" validate:"required"`
-	// OSFamily       string   `json:"os_family" validate:"required,oneof=linux windows"`
+	OSFamily       string   `json:"os_family" validate:"omitempty,oneof=linux windows"`
 }
 
 type NodeResp struct {
@@ -220,6 +349,53 @@ type NodesPaginateResponse struct {
 	TotalCount int64      `json:"total_count"`
 }
 
+type VersionResp struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	Date      string `json:"date"`
+	GoVersion string `json:"go_version"`
+}
+
+type ConfigReloadResp struct {
+	// RestartRequired lists the config sections that changed but are only applied on start
+	RestartRequired []string `json:"restart_required"`
+}
+
+type FlowLoadStatusResp struct {
+	State            string `json:"state"`
+	Namespaces       int    `json:"namespaces"`
+	NamespacesLoaded int    `json:"namespaces_loaded"`
+	Total            int    `json:"total"`
+	Loaded           int    `json:"loaded"`
+	Failed           int    `json:"failed"`
+	StartedAt        string `json:"started_at,omitempty"`
+	FinishedAt       string `json:"finished_at,omitempty"`
+	Error            string `json:"error,omitempty"`
+}
+
+type RuntimeStatsResp struct {
+	Goroutines     int     `json:"goroutines"`
+	NumCPU         int     `json:"num_cpu"`
+	GOMAXPROCS     int     `json:"gomaxprocs"`
+	UptimeSeconds  float64 `json:"uptime_seconds"`
+	HeapAlloc      uint64  `json:"heap_alloc_bytes"`
+	HeapInuse      uint64  `json:"heap_inuse_bytes"`
+	HeapObjects    uint64  `json:"heap_objects"`
+	HeapReleased   uint64  `json:"heap_released_bytes"`
+	Sys            uint64  `json:"sys_bytes"`
+	TotalAlloc     uint64  `json:"total_alloc_bytes"`
+	NumGC          uint32  `json:"num_gc"`
+	LastGC         string  `json:"last_gc"`
+	PauseTotalSecs float64 `json:"gc_pause_total_seconds"`
+}
+
+type NodeTestResp struct {
+	Success    bool   `json:"success"`
+	Output     string `json:"output,omitempty"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
 type NodeStatsResp struct {
 	TotalHosts int64 `json:"total_hosts"`
 	SSHHosts   int64 `json:"ssh_hosts"`
@@ -336,9 +512,9 @@ type Schedule struct {
 
 // Notify represents notification configuration for flow events
 type Notify struct {
-	Channel string         `json:"channel" validate:"required,oneof=email webhook"`
+	Channel string         `json:"channel" validate:"required,oneof=email webhook pagerduty opsgenie slack"`
 	Config  map[string]any `json:"config" validate:"required"`
-	Events  []string       `json:"events" validate:"required,dive,min=1,oneof=on_success on_failure on_waiting on_cancelled"`
+	Events  []string       `json:"events" validate:"required,dive,min=1,oneof=on_success on_failure on_waiting on_cancelled on_skipped on_sla_breach"`
 }
 
 func convertNotifyToNotifyReq(notify []models.Notify) []Notify {
@@ -382,6 +558,7 @@ type FlowListItem struct {
 	Prefix      string     `json:"prefix"`
 	Schedules   []Schedule `json:"schedules"`
 	StepCount   int        `json:"step_count"`
+	Owners      []string   `json:"owners"`
 }
 
 type FlowInput struct {
@@ -393,6 +570,7 @@ type FlowInput struct {
 	Options     []string `json:"options"`
 	Default     string   `json:"default,omitempty"`
 	MaxFileSize int64    `json:"max_file_size,omitempty"`
+	Accept      []string `json:"accept,omitempty"`
 }
 
 type FlowInputsResp struct {
@@ -410,6 +588,7 @@ func coreFlowInputToInput(input models.Input) FlowInput {
 		Options:     input.Options,
 		Default:     input.Default,
 		MaxFileSize: input.MaxFileSize,
+		Accept:      input.Accept,
 	}
 }
 
@@ -430,6 +609,38 @@ type FlowMeta struct {
 	Namespace       string     `json:"namespace"`
 	AllowOverlap    bool       `json:"allow_overlap"`
 	UserSchedulable bool       `json:"user_schedulable"`
+	SLA             *FlowSLA   `json:"sla,omitempty" validate:"omitempty"`
+	Owners          []string   `json:"owners" validate:"omitempty,dive,required,max=150"`
+	Lock            string     `json:"lock" validate:"omitempty,printascii,max=100"`
+	MaxConcurrency  int        `json:"max_concurrency" validate:"gte=0,lte=1000"`
+}
+
+type FlowSLA struct {
+	MaxDuration string `json:"max_duration,omitempty"`
+	Deadline    string `json:"deadline,omitempty"`
+	Timezone    string `json:"timezone,omitempty" validate:"omitempty,timezone"`
+}
+
+func coreSLAToFlowSLA(s *models.SLA) *FlowSLA {
+	if s == nil {
+		return nil
+	}
+	return &FlowSLA{
+		MaxDuration: s.MaxDuration,
+		Deadline:    s.Deadline,
+		Timezone:    s.Timezone,
+	}
+}
+
+func flowSLAToCoreSLA(s *FlowSLA) *models.SLA {
+	if s == nil {
+		return nil
+	}
+	return &models.SLA{
+		MaxDuration: s.MaxDuration,
+		Deadline:    s.Deadline,
+		Timezone:    s.Timezone,
+	}
 }
 
 func coreSchedulesToSchedules(schedules []models.Schedule) []Schedule {
@@ -453,24 +664,36 @@ func coreFlowMetatoFlowMeta(m models.Metadata, schedules []models.Schedule) Flow
 		Namespace:       m.Namespace,
 		AllowOverlap:    m.AllowOverlap,
 		UserSchedulable: m.UserSchedulable,
+		SLA:             coreSLAToFlowSLA(m.SLA),
+		Owners:          m.Owners,
+		Lock:            m.Lock,
+		MaxConcurrency:  m.MaxConcurrency,
 	}
 }
 
 type FlowAction struct {
-	ID       string   `json:"id"`
-	Name     string   `json:"name"`
-	Executor string   `json:"executor"`
-	Approval bool     `json:"approval"`
-	On       []string `json:"on"`
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	Executor  string   `json:"executor"`
+	Approval  bool     `json:"approval"`
+	Approvers []string `json:"approvers,omitempty"`
+	On        []string `json:"on"`
+	Needs     []string `json:"needs,omitempty"`
+	Condition string   `json:"condition,omitempty"`
+	Artifacts []string `json:"artifacts,omitempty"`
 }
 
 func coreFlowActiontoFlowAction(a models.Action) FlowAction {
 	return FlowAction{
-		ID:       a.ID,
-		Name:     a.Name,
-		Executor: a.Executor,
-		Approval: a.Approval,
-		On:       a.On,
+		ID:        a.ID,
+		Name:      a.Name,
+		Executor:  a.Executor,
+		Approval:  a.Approval,
+		Approvers: a.Approvers,
+		On:        a.On,
+		Needs:     a.Needs,
+		Condition: a.Condition,
+		Artifacts: a.Artifacts,
 	}
 }
 
@@ -486,6 +709,7 @@ type FlowMetaResp struct {
 	Metadata            FlowMeta             `json:"meta"`
 	Actions             []FlowAction         `json:"actions"`
 	ScheduledExecutions []ScheduledExecution `json:"scheduled_executions"`
+	CommitSHA           string               `json:"commit_sha,omitempty"`
 }
 
 type ScheduledExecution struct {
@@ -529,6 +753,7 @@ func coreFlowToFlow(flow models.Flow) FlowListItem {
 		Prefix:      flow.Meta.Prefix,
 		Schedules:   coreSchedulesToSchedules(flow.Schedules),
 		StepCount:   len(flow.Actions),
+		Owners:      flow.Meta.Owners,
 	}
 }
 
@@ -617,19 +842,72 @@ const (
 )
 
 type ExecutionSummary struct {
-	ID              string          `json:"id"`
-	FlowName        string          `json:"flow_name"`
-	FlowID          string          `json:"flow_id"`
-	Status          ExecutionStatus `json:"status"`
-	TriggerType     string          `json:"trigger_type"`
-	Input           json.RawMessage `json:"input,omitempty"`
-	TriggeredBy     string          `json:"triggered_by"`
-	CurrentActionID string          `json:"current_action_id"`
-	CreatedAt       string          `json:"created_at"`
-	StartedAt       string          `json:"started_at"`
-	CompletedAt     string          `json:"completed_at"`
-	ScheduledAt     string          `json:"scheduled_at,omitempty"`
-	ActionRetries   map[string]int  `json:"action_retries,omitempty"`
+	ID              string                 `json:"id"`
+	FlowName        string                 `json:"flow_name"`
+	FlowID          string                 `json:"flow_id"`
+	Status          ExecutionStatus        `json:"status"`
+	TriggerType     string                 `json:"trigger_type"`
+	Input           json.RawMessage        `json:"input,omitempty"`
+	TriggeredBy     string                 `json:"triggered_by"`
+	CurrentActionID string                 `json:"current_action_id"`
+	CreatedAt       string                 `json:"created_at"`
+	StartedAt       string                 `json:"started_at"`
+	CompletedAt     string                 `json:"completed_at"`
+	ScheduledAt     string                 `json:"scheduled_at,omitempty"`
+	ActionRetries   map[string]int         `json:"action_retries,omitempty"`
+	Progress        *ExecutionProgressResp `json:"progress,omitempty"`
+	SkippedActions  []ActionSkipResp       `json:"skipped_actions,omitempty"`
+	Environment     []NodeEnvironmentResp  `json:"environment,omitempty"`
+	Estimate        *ExecutionEstimateResp `json:"estimate,omitempty"`
+	ParentExecID    string                 `json:"parent_exec_id,omitempty"`
+	ParentActionID  string                 `json:"parent_action_id,omitempty"`
+	Subflows        []SubflowExecutionResp `json:"subflows,omitempty"`
+}
+
+type SubflowExecutionResp struct {
+	ActionID  string          `json:"action_id"`
+	ExecID    string          `json:"exec_id"`
+	FlowID    string          `json:"flow_id"`
+	FlowName  string          `json:"flow_name"`
+	Status    ExecutionStatus `json:"status"`
+	CreatedAt string          `json:"created_at"`
+}
+
+type ExecutionEstimateResp struct {
+	DurationMs  int64  `json:"duration_ms"`
+	RemainingMs int64  `json:"remaining_ms"`
+	CompletesAt string `json:"completes_at"`
+	Samples     int    `json:"samples"`
+	Overdue     bool   `json:"overdue"`
+}
+
+type ActionSkipResp struct {
+	ActionID  string `json:"action_id"`
+	SkippedBy string `json:"skipped_by"`
+	SkippedAt string `json:"skipped_at"`
+}
+
+type NodeEnvironmentResp struct {
+	ActionID        string `json:"action_id"`
+	Node            string `json:"node"`
+	Hostname        string `json:"hostname"`
+	OS              string `json:"os"`
+	Executor        string `json:"executor"`
+	ExecutorVersion string `json:"executor_version"`
+	Image           string `json:"image,omitempty"`
+	ImageDigest     string `json:"image_digest,omitempty"`
+	FlowChecksum    string `json:"flow_checksum"`
+	RecordedAt      string `json:"recorded_at"`
+}
+
+type ExecutionProgressResp struct {
+	Event          string `json:"event"`
+	ActionIndex    int    `json:"action_index"`
+	TotalActions   int    `json:"total_actions"`
+	CompletedNodes int    `json:"completed_nodes"`
+	TotalNodes     int    `json:"total_nodes"`
+	Node           string `json:"node,omitempty"`
+	Status         string `json:"status,omitempty"`
 }
 
 func coreExecutionSummaryToExecutionSummary(e models.ExecutionSummary) ExecutionSummary {
@@ -648,7 +926,68 @@ func coreExecutionSummaryToExecutionSummary(e models.ExecutionSummary) Execution
 		startedAt = e.StartedAt.Format(TimeFormat)
 	}
 
-	return ExecutionSummary{
+	var progress *ExecutionProgressResp
+	if e.Progress != nil {
+		progress = &ExecutionProgressResp{
+			Event:          e.Progress.Event,
+			ActionIndex:    e.Progress.ActionIndex,
+			TotalActions:   e.Progress.TotalActions,
+			CompletedNodes: e.Progress.CompletedNodes,
+			TotalNodes:     e.Progress.TotalNodes,
+			Node:           e.Progress.Node,
+			Status:         e.Progress.Status,
+		}
+	}
+
+	var skipped []ActionSkipResp
+	for _, s := range e.SkippedActions {
+		skipped = append(skipped, ActionSkipResp{
+			ActionID:  s.ActionID,
+			SkippedBy: s.SkippedBy,
+			SkippedAt: s.SkippedAt.Format(TimeFormat),
+		})
+	}
+
+	var environment []NodeEnvironmentResp
+	for _, n := range e.Environment {
+		environment = append(environment, NodeEnvironmentResp{
+			ActionID:        n.ActionID,
+			Node:            n.Node,
+			Hostname:        n.Hostname,
+			OS:              n.OS,
+			Executor:        n.Executor,
+			ExecutorVersion: n.ExecutorVersion,
+			Image:           n.Image,
+			ImageDigest:     n.ImageDigest,
+			FlowChecksum:    n.FlowChecksum,
+			RecordedAt:      n.RecordedAt.Format(TimeFormat),
+		})
+	}
+
+	var estimate *ExecutionEstimateResp
+	if e.Estimate != nil {
+		estimate = &ExecutionEstimateResp{
+			DurationMs:  e.Estimate.Duration.Milliseconds(),
+			RemainingMs: e.Estimate.Remaining.Milliseconds(),
+			CompletesAt: e.Estimate.CompletesAt.Format(TimeFormat),
+			Samples:     e.Estimate.Samples,
+			Overdue:     e.Estimate.Overdue,
+		}
+	}
+
+	var subflows []SubflowExecutionResp
+	for _, sf := range e.Subflows {
+		subflows = append(subflows, SubflowExecutionResp{
+			ActionID:  sf.ActionID,
+			ExecID:    sf.ExecID,
+			FlowID:    sf.FlowID,
+			FlowName:  sf.FlowName,
+			Status:    ExecutionStatus(sf.Status),
+			CreatedAt: sf.CreatedAt.Format(TimeFormat),
+		})
+	}
+
+	summary := ExecutionSummary{
 		ID:              e.ExecID,
 		FlowName:        e.FlowName,
 		FlowID:          e.FlowID,
@@ -662,7 +1001,151 @@ func coreExecutionSummaryToExecutionSummary(e models.ExecutionSummary) Execution
 		CompletedAt:     completedAt,
 		ScheduledAt:     scheduledAt,
 		ActionRetries:   e.ActionRetries,
+		Progress:        progress,
+		SkippedActions:  skipped,
+		Environment:     environment,
+		Estimate:        estimate,
+		Subflows:        subflows,
+	}
+	if e.Parent != nil {
+		summary.ParentExecID = e.Parent.ExecID
+		summary.ParentActionID = e.Parent.ActionID
 	}
+	return summary
+}
+
+type ExecutionTimelineResp struct {
+	ExecID          string               `json:"exec_id"`
+	Actions         []ActionTimelineResp `json:"actions"`
+	SlowestActionID string               `json:"slowest_action_id,omitempty"`
+}
+
+type ActionTimelineResp struct {
+	ActionID   string             `json:"action_id"`
+	Retry      int                `json:"retry"`
+	Status     string             `json:"status"`
+	Error      string             `json:"error,omitempty"`
+	StartedAt  string             `json:"started_at"`
+	FinishedAt string             `json:"finished_at,omitempty"`
+	DurationMs int64              `json:"duration_ms"`
+	Nodes      []NodeTimelineResp `json:"nodes"`
+}
+
+type NodeTimelineResp struct {
+	Node       string `json:"node"`
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+	StartedAt  string `json:"started_at"`
+	FinishedAt string `json:"finished_at,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+func coreExecutionTimelineToExecutionTimelineResp(t models.ExecutionTimeline) ExecutionTimelineResp {
+	formatFinished := func(ts time.Time) string {
+		if ts.IsZero() {
+			return ""
+		}
+		return ts.Format(TimeFormat)
+	}
+
+	actions := make([]ActionTimelineResp, len(t.Actions))
+	for i, a := range t.Actions {
+		nodes := make([]NodeTimelineResp, len(a.Nodes))
+		for j, n := range a.Nodes {
+			nodes[j] = NodeTimelineResp{
+				Node:       n.Node,
+				Status:     n.Status,
+				Error:      n.Error,
+				StartedAt:  n.StartedAt.Format(TimeFormat),
+				FinishedAt: formatFinished(n.FinishedAt),
+				DurationMs: n.Duration.Milliseconds(),
+			}
+		}
+
+		actions[i] = ActionTimelineResp{
+			ActionID:   a.ActionID,
+			Retry:      a.Retry,
+			Status:     a.Status,
+			Error:      a.Error,
+			StartedAt:  a.StartedAt.Format(TimeFormat),
+			FinishedAt: formatFinished(a.FinishedAt),
+			DurationMs: a.Duration.Milliseconds(),
+			Nodes:      nodes,
+		}
+	}
+
+	return ExecutionTimelineResp{
+		ExecID:          t.ExecID,
+		Actions:         actions,
+		SlowestActionID: t.SlowestActionID,
+	}
+}
+
+type ExecutionOutputsResp struct {
+	ExecID  string              `json:"exec_id"`
+	Status  string              `json:"status"`
+	Actions []ActionOutputsResp `json:"actions"`
+	Outputs map[string]any      `json:"outputs"`
+}
+
+type ActionOutputsResp struct {
+	ActionID   string            `json:"action_id"`
+	Outputs    map[string]string `json:"outputs"`
+	FinishedAt string            `json:"finished_at"`
+}
+
+func coreExecutionOutputsToResp(status models.ExecutionStatus, o models.ExecutionOutputs) ExecutionOutputsResp {
+	actions := make([]ActionOutputsResp, len(o.Actions))
+	for i, a := range o.Actions {
+		actions[i] = ActionOutputsResp{
+			ActionID:   a.ActionID,
+			Outputs:    a.Outputs,
+			FinishedAt: a.FinishedAt.Format(TimeFormat),
+		}
+	}
+
+	return ExecutionOutputsResp{
+		ExecID:  o.ExecID,
+		Status:  string(status),
+		Actions: actions,
+		Outputs: o.Outputs,
+	}
+}
+
+type NodeTelemetryResp struct {
+	ActionID string                `json:"action_id"`
+	Node     string                `json:"node"`
+	Samples  []TelemetrySampleResp `json:"samples"`
+}
+
+type TelemetrySampleResp struct {
+	Time          string  `json:"time"`
+	CPUPercent    float64 `json:"cpu_percent"`
+	MemoryPercent float64 `json:"memory_percent"`
+	DiskPercent   float64 `json:"disk_percent"`
+	Load1         float64 `json:"load1"`
+}
+
+func coreNodeTelemetryToNodeTelemetryResp(series []models.NodeTelemetry) []NodeTelemetryResp {
+	resp := make([]NodeTelemetryResp, len(series))
+	for i, s := range series {
+		samples := make([]TelemetrySampleResp, len(s.Samples))
+		for j, sample := range s.Samples {
+			samples[j] = TelemetrySampleResp{
+				Time:          sample.Time.Format(TimeFormat),
+				CPUPercent:    sample.CPUPercent,
+				MemoryPercent: sample.MemoryPercent,
+				DiskPercent:   sample.DiskPercent,
+				Load1:         sample.Load1,
+			}
+		}
+		resp[i] = NodeTelemetryResp{
+			ActionID: s.ActionID,
+			Node:     s.Node,
+			Samples:  samples,
+		}
+	}
+	return resp
 }
 
 type FlowCreateReq struct {
@@ -688,29 +1171,230 @@ type FlowInputReq struct {
 	Default       string             `json:"default"`
 	Options       []string           `json:"options"`
 	MaxFileSize   int64              `json:"max_file_size"`
+	Accept        []string           `json:"accept,omitempty"`
 	RemoteOptions *RemoteOptionsReq  `json:"remote_options,omitempty" validate:"omitempty"`
 }
 
 type FlowActionReq struct {
-	Name      string           `json:"name" validate:"required,alphanum_whitespace,min=1,max=150"`
-	Executor  string           `json:"executor"`
-	With      map[string]any   `json:"with" validate:"required"`
-	Approval  bool             `json:"approval"`
-	Variables []map[string]any `json:"variables"`
-	Condition string           `json:"condition"`
-	On        []string         `json:"on"`
+	Name       string           `json:"name" validate:"required,alphanum_whitespace,min=1,max=150"`
+	Executor   string           `json:"executor"`
+	With       map[string]any   `json:"with" validate:"required"`
+	Approval   bool             `json:"approval"`
+	Variables  []map[string]any `json:"variables"`
+	Condition  string           `json:"condition" validate:"omitempty,max=1000"`
+	On         []string         `json:"on"`
+	Telemetry  bool             `json:"telemetry"`
+	Lock       string           `json:"lock" validate:"omitempty,printascii,max=100"`
+	Approvers  []string         `json:"approvers,omitempty" validate:"omitempty,dive,required,max=150"`
+	Username   string           `json:"username,omitempty" validate:"omitempty,min=1,max=50"`
+	Credential string           `json:"credential,omitempty" validate:"omitempty,max=150"`
+	Needs      []string         `json:"needs,omitempty" validate:"omitempty,dive,required"`
+	Artifacts  []string         `json:"artifacts,omitempty" validate:"omitempty,dive,required,max=255"`
 }
 
 type FlowCreateResp struct {
 	ID string `json:"id"`
 }
 
+type FlowImportReq struct {
+	URL string `json:"url" form:"url" validate:"required,url"`
+}
+
+type FlowTemplateResp struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Content     string `json:"content"`
+}
+
+type FlowTemplatesResp struct {
+	Templates []FlowTemplateResp `json:"templates"`
+}
+
+type FlowTemplateCreateReq struct {
+	TemplateID string `param:"templateID" validate:"required"`
+	Name       string `json:"name" validate:"omitempty,min=1,max=150,alphanum_whitespace"`
+	Prefix     string `json:"prefix" validate:"omitempty,alphanum_underscore,max=100"`
+}
+
+func coreFlowTemplateToResp(t models.FlowTemplate) FlowTemplateResp {
+	return FlowTemplateResp{
+		ID:          t.ID,
+		Name:        t.Name,
+		Description: t.Description,
+		Content:     t.Content,
+	}
+}
+
+type FlowImportErrorResp struct {
+	FilePath  string `json:"file_path"`
+	Error     string `json:"error"`
+	CreatedAt string `json:"created_at"`
+}
+
+type FlowImportErrorsResp struct {
+	Errors []FlowImportErrorResp `json:"errors"`
+}
+
+type FlowRevisionResp struct {
+	ID          string `json:"id"`
+	FlowID      string `json:"flow_id"`
+	FlowName    string `json:"flow_name"`
+	Status      string `json:"status"`
+	CommitSHA   string `json:"commit_sha,omitempty"`
+	RequestedBy string `json:"requested_by"`
+	ReviewedBy  string `json:"reviewed_by,omitempty"`
+	Comment     string `json:"comment,omitempty"`
+	CreatedAt   string `json:"created_at"`
+	ReviewedAt  string `json:"reviewed_at,omitempty"`
+}
+
+type FlowRevisionDetailsResp struct {
+	FlowRevisionResp
+	Format  string `json:"format"`
+	Content string `json:"content"`
+}
+
+type FlowRevisionsPaginateReq struct {
+	Status string `query:"status" validate:"oneof='' pending approved rejected superseded"`
+	Page   int    `query:"page"`
+	Count  int    `query:"count_per_page"`
+}
+
+type FlowRevisionsPaginateResponse struct {
+	Revisions  []FlowRevisionResp `json:"revisions"`
+	PageCount  int64              `json:"page_count"`
+	TotalCount int64              `json:"total_count"`
+}
+
+type FlowRevisionReviewReq struct {
+	RevisionID string `param:"revisionID" validate:"required,uuid4"`
+	Action     string `json:"action" validate:"required,oneof=approve reject"`
+	Comment    string `json:"comment" validate:"required_if=Action reject,max=1000"`
+}
+
+func coreFlowRevisionToResp(r models.FlowRevision) FlowRevisionResp {
+	resp := FlowRevisionResp{
+		ID:          r.ID,
+		FlowID:      r.FlowID,
+		FlowName:    r.FlowName,
+		Status:      string(r.Status),
+		CommitSHA:   r.CommitSHA,
+		RequestedBy: r.RequestedBy,
+		ReviewedBy:  r.ReviewedBy,
+		Comment:     r.Comment,
+		CreatedAt:   r.CreatedAt.Format(TimeFormat),
+	}
+	if r.ReviewedAt != nil {
+		resp.ReviewedAt = r.ReviewedAt.Format(TimeFormat)
+	}
+	return resp
+}
+
+type FlowVersionResp struct {
+	ID         int32  `json:"id"`
+	Checksum   string `json:"checksum"`
+	CommitSHA  string `json:"commit_sha,omitempty"`
+	CreatedAt  string `json:"created_at"`
+	HasContent bool   `json:"has_content"`
+}
+
+type FlowVersionsPaginateResponse struct {
+	Versions   []FlowVersionResp `json:"versions"`
+	PageCount  int64             `json:"page_count"`
+	TotalCount int64             `json:"total_count"`
+}
+
+type FlowDiffReq struct {
+	FlowID string `param:"flowID" validate:"required"`
+	From   string `param:"from" validate:"required"`
+	To     string `param:"to" validate:"required"`
+}
+
+type FieldChangeResp struct {
+	Field string `json:"field"`
+	Old   any    `json:"old"`
+	New   any    `json:"new"`
+}
+
+type ItemChangeResp struct {
+	ID     string            `json:"id"`
+	Change string            `json:"change"`
+	Fields []FieldChangeResp `json:"fields,omitempty"`
+}
+
+type FlowDiffResp struct {
+	From     string            `json:"from"`
+	To       string            `json:"to"`
+	Metadata []FieldChangeResp `json:"metadata"`
+	Inputs   []ItemChangeResp  `json:"inputs"`
+	Actions  []ItemChangeResp  `json:"actions"`
+	Text     string            `json:"text"`
+}
+
+func coreFlowDiffToResp(d models.FlowDiff) FlowDiffResp {
+	fields := func(changes []models.FieldChange) []FieldChangeResp {
+		resp := make([]FieldChangeResp, 0, len(changes))
+		for _, f := range changes {
+			resp = append(resp, FieldChangeResp{Field: f.Field, Old: f.Old, New: f.New})
+		}
+		return resp
+	}
+	items := func(changes []models.ItemChange) []ItemChangeResp {
+		resp := make([]ItemChangeResp, 0, len(changes))
+		for _, i := range changes {
+			resp = append(resp, ItemChangeResp{ID: i.ID, Change: string(i.Change), Fields: fields(i.Fields)})
+		}
+		return resp
+	}
+
+	return FlowDiffResp{
+		From:     d.From,
+		To:       d.To,
+		Metadata: fields(d.Metadata),
+		Inputs:   items(d.Inputs),
+		Actions:  items(d.Actions),
+		Text:     d.Text,
+	}
+}
+
 type FlowGetReq struct {
 	FlowID string `param:"flowID" validate:"required"`
 }
 
 type LogStreamingReq struct {
 	LogID string `param:"logID" validate:"required,uuid4"`
+	ANSI  string `query:"ansi" validate:"omitempty,oneof=preserve strip"`
+	TZ    string `query:"tz" validate:"omitempty,timezone"`
+}
+
+type LogSearchReq struct {
+	ExecID     string `query:"exec_id" validate:"omitempty,uuid4"`
+	FlowID     string `query:"flow_id" validate:"omitempty,max=150"`
+	From       string `query:"from"`
+	To         string `query:"to"`
+	Pattern    string `query:"pattern" validate:"required,max=1024"`
+	Regex      bool   `query:"regex"`
+	IgnoreCase bool   `query:"ignore_case"`
+	Context    int    `query:"context" validate:"min=0,max=10"`
+	Limit      int    `query:"limit" validate:"min=0,max=1000"`
+	ANSI       string `query:"ansi" validate:"omitempty,oneof=preserve strip"`
+	TZ         string `query:"tz" validate:"omitempty,timezone"`
+}
+
+type LogSearchMatchResp struct {
+	ExecID   string        `json:"exec_id"`
+	FlowID   string        `json:"flow_id"`
+	FlowName string        `json:"flow_name"`
+	Message  FlowLogResp   `json:"message"`
+	Before   []FlowLogResp `json:"before"`
+	After    []FlowLogResp `json:"after"`
+}
+
+type LogSearchResp struct {
+	Matches            []LogSearchMatchResp `json:"matches"`
+	SearchedExecutions int                  `json:"searched_executions"`
+	Truncated          bool                 `json:"truncated"`
 }
 
 type ExecutionGetReq struct {
@@ -723,6 +1407,8 @@ type FlowUpdateReq struct {
 	Notify          []Notify        `json:"notify" validate:"omitempty,dive"`
 	AllowOverlap    bool            `json:"allow_overlap"`
 	UserSchedulable bool            `json:"user_schedulable"`
+	Lock            string          `json:"lock" validate:"omitempty,printascii,max=100"`
+	MaxConcurrency  int             `json:"max_concurrency" validate:"gte=0,lte=1000"`
 	Description     string          `json:"description" validate:"max=255,no_html"`
 	Inputs          []FlowInputReq  `json:"inputs" validate:"required,dive"`
 	Actions         []FlowActionReq `json:"actions" validate:"required,dive"`
@@ -750,6 +1436,7 @@ func convertFlowInputsReqToInputs(inputsReq []FlowInputReq) []models.Input {
 			Default:       input.Default,
 			Options:       input.Options,
 			MaxFileSize:   input.MaxFileSize,
+			Accept:        input.Accept,
 			RemoteOptions: remoteOpts,
 		}
 	}
@@ -766,13 +1453,21 @@ func convertFlowActionsReqToActions(actionsReq []FlowActionReq) []models.Action
 		}
 
 		actions[i] = models.Action{
-			ID:        GenerateSlug(action.Name),
-			Name:      action.Name,
-			Executor:  action.Executor,
-			With:      action.With,
-			Approval:  action.Approval,
-			Variables: variables,
-			On:        action.On,
+			ID:         GenerateSlug(action.Name),
+			Name:       action.Name,
+			Executor:   action.Executor,
+			With:       action.With,
+			Approval:   action.Approval,
+			Variables:  variables,
+			On:         action.On,
+			Telemetry:  action.Telemetry,
+			Lock:       action.Lock,
+			Approvers:  action.Approvers,
+			Username:   action.Username,
+			Credential: action.Credential,
+			Needs:      action.Needs,
+			Condition:  action.Condition,
+			Artifacts:  action.Artifacts,
 		}
 	}
 	return actions
@@ -800,6 +1495,7 @@ func convertFlowInputsToInputsReq(inputs []models.Input) []FlowInputReq {
 			Default:       input.Default,
 			Options:       input.Options,
 			MaxFileSize:   input.MaxFileSize,
+			Accept:        input.Accept,
 			RemoteOptions: remoteOpts,
 		}
 	}
@@ -816,17 +1512,55 @@ func convertFlowActionsToActionsReq(actions []models.Action) []FlowActionReq {
 		}
 
 		actionsReq[i] = FlowActionReq{
-			Name:      action.Name,
-			Executor:  action.Executor,
-			With:      action.With,
-			Approval:  action.Approval,
-			Variables: variables,
-			On:        action.On,
+			Name:       action.Name,
+			Executor:   action.Executor,
+			With:       action.With,
+			Approval:   action.Approval,
+			Variables:  variables,
+			On:         action.On,
+			Telemetry:  action.Telemetry,
+			Lock:       action.Lock,
+			Approvers:  action.Approvers,
+			Username:   action.Username,
+			Credential: action.Credential,
+			Needs:      action.Needs,
+			Condition:  action.Condition,
+			Artifacts:  action.Artifacts,
 		}
 	}
 	return actionsReq
 }
 
+// NewFlowConfig returns the configuration of a flow in the format accepted by the create flow endpoint
+func NewFlowConfig(f models.Flow) FlowCreateReq {
+	schedules := make([]Schedule, 0)
+	for _, s := range f.Schedules {
+		schedules = append(schedules, Schedule{
+			Cron:     s.Cron,
+			Timezone: s.Timezone,
+		})
+	}
+
+	return FlowCreateReq{
+		Meta: FlowMeta{
+			ID:              f.Meta.ID,
+			Name:            f.Meta.Name,
+			Description:     f.Meta.Description,
+			Prefix:          f.Meta.Prefix,
+			Schedules:       schedules,
+			AllowOverlap:    f.Meta.AllowOverlap,
+			UserSchedulable: f.Meta.UserSchedulable,
+			SLA:             coreSLAToFlowSLA(f.Meta.SLA),
+			Owners:          f.Meta.Owners,
+			Lock:            f.Meta.Lock,
+			MaxConcurrency:  f.Meta.MaxConcurrency,
+		},
+		Inputs:        convertFlowInputsToInputsReq(f.Inputs),
+		Actions:       convertFlowActionsToActionsReq(f.Actions),
+		Notifications: convertNotifyToNotifyReq(f.Notify),
+	}
+}
+
 type FlowSecretReq struct {
 	FlowID      string `param:"flowID" validate:"required"`
 	Key         string `json:"key" validate:"required,min=1,max=150,alphanum_underscore"`
@@ -905,6 +1639,17 @@ type FlowCancellationResp struct {
 	ExecID  string `json:"execID"`
 }
 
+type ExecutionRetryReq struct {
+	ExecID string `param:"execID" validate:"required"`
+	// SkipFailed skips the failed action and continues with the next one
+	SkipFailed bool `json:"skip_failed"`
+}
+
+type ExecutionResumeReq struct {
+	ExecID   string `param:"execID" validate:"required"`
+	ActionID string `json:"action_id" validate:"required,max=150"`
+}
+
 type ScheduleCreateReq struct {
 	FlowID   string                 `param:"flowID" validate:"required"`
 	Cron     string                 `json:"cron" validate:"required,cron"`
@@ -980,6 +1725,89 @@ func coreSchedulesToScheduleResps(schedules []models.Schedule) []ScheduleResp {
 	return resp
 }
 
+type ScheduleLagResp struct {
+	UUID          string `json:"uuid"`
+	FlowSlug      string `json:"flow_slug"`
+	FlowName      string `json:"flow_name"`
+	Cron          string `json:"cron"`
+	Timezone      string `json:"timezone"`
+	IsActive      bool   `json:"is_active"`
+	IsUserCreated bool   `json:"is_user_created"`
+	LastExecID    string `json:"last_exec_id,omitempty"`
+	LastFiredAt   string `json:"last_fired_at,omitempty"`
+	LastStartedAt string `json:"last_started_at,omitempty"`
+	LastDriftMs   int64  `json:"last_drift_ms"`
+	MaxDriftMs    int64  `json:"max_drift_ms"`
+	Runs          int64  `json:"runs"`
+}
+
+func coreScheduleLagToResps(lag []models.ScheduleLag) []ScheduleLagResp {
+	resp := make([]ScheduleLagResp, len(lag))
+	for i, l := range lag {
+		resp[i] = ScheduleLagResp{
+			UUID:          l.ScheduleUUID,
+			FlowSlug:      l.FlowSlug,
+			FlowName:      l.FlowName,
+			Cron:          l.Cron,
+			Timezone:      l.Timezone,
+			IsActive:      l.IsActive,
+			IsUserCreated: l.IsUserCreated,
+			LastExecID:    l.LastExecID,
+			LastDriftMs:   l.LastDrift.Milliseconds(),
+			MaxDriftMs:    l.MaxDrift.Milliseconds(),
+			Runs:          l.Runs,
+		}
+		if !l.LastFiredAt.IsZero() {
+			resp[i].LastFiredAt = l.LastFiredAt.Format(TimeFormat)
+			resp[i].LastStartedAt = l.LastStartedAt.Format(TimeFormat)
+		}
+	}
+	return resp
+}
+
+type ScheduleCalendarReq struct {
+	From string `query:"from"`
+	To   string `query:"to"`
+}
+
+type ScheduleOccurrenceResp struct {
+	ScheduleUUID  string `json:"schedule_uuid"`
+	FlowSlug      string `json:"flow_slug"`
+	FlowName      string `json:"flow_name"`
+	Cron          string `json:"cron"`
+	Timezone      string `json:"timezone"`
+	IsUserCreated bool   `json:"is_user_created"`
+	At            string `json:"at"`
+}
+
+type ScheduleCalendarResp struct {
+	From        string                   `json:"from"`
+	To          string                   `json:"to"`
+	Occurrences []ScheduleOccurrenceResp `json:"occurrences"`
+	Truncated   bool                     `json:"truncated"`
+}
+
+func coreScheduleOccurrencesToCalendarResp(from, to time.Time, occurrences []models.ScheduleOccurrence, truncated bool) ScheduleCalendarResp {
+	resp := ScheduleCalendarResp{
+		From:        from.Format(TimeFormat),
+		To:          to.Format(TimeFormat),
+		Occurrences: make([]ScheduleOccurrenceResp, len(occurrences)),
+		Truncated:   truncated,
+	}
+	for i, o := range occurrences {
+		resp.Occurrences[i] = ScheduleOccurrenceResp{
+			ScheduleUUID:  o.ScheduleUUID,
+			FlowSlug:      o.FlowSlug,
+			FlowName:      o.FlowName,
+			Cron:          o.Cron,
+			Timezone:      o.Timezone,
+			IsUserCreated: o.IsUserCreated,
+			At:            o.At.Format(TimeFormat),
+		}
+	}
+	return resp
+}
+
 // Flow group types
 type FlowGroupResp struct {
 	ID          string `json:"id"`
@@ -1024,3 +1852,557 @@ type ExecutorInfo struct {
 type ExecutorsListResponse struct {
 	Executors []ExecutorInfo `json:"executors"`
 }
+
+type APITokenReq struct {
+	Name      string     `json:"name" validate:"required,min=1,max=150,no_html"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type APITokenGetReq struct {
+	TokenID string `param:"tokenID" validate:"required,uuid4"`
+}
+
+type APITokenResp struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	ExpiresAt  string `json:"expires_at,omitempty"`
+	LastUsedAt string `json:"last_used_at,omitempty"`
+	CreatedAt  string `json:"created_at"`
+}
+
+// APITokenCreateResp includes the secret token, which is only returned when it is created
+type APITokenCreateResp struct {
+	APITokenResp
+	Token string `json:"token"`
+}
+
+func coreAPITokenToResp(t models.APIToken) APITokenResp {
+	resp := APITokenResp{
+		ID:        t.ID,
+		Name:      t.Name,
+		CreatedAt: t.CreatedAt.Format(TimeFormat),
+	}
+	if t.ExpiresAt != nil {
+		resp.ExpiresAt = t.ExpiresAt.Format(TimeFormat)
+	}
+	if t.LastUsedAt != nil {
+		resp.LastUsedAt = t.LastUsedAt.Format(TimeFormat)
+	}
+	return resp
+}
+
+type FlowWebhookResp struct {
+	FlowID     string `json:"flow_id"`
+	CreatedAt  string `json:"created_at"`
+	LastUsedAt string `json:"last_used_at,omitempty"`
+}
+
+// FlowWebhookCreateResp includes the URL with the token and the secret of the webhook, which are
+// only returned when it is created
+type FlowWebhookCreateResp struct {
+	FlowWebhookResp
+	URL    string `json:"url"`
+	Secret string `json:"secret"`
+}
+
+func coreFlowWebhookToResp(w models.FlowWebhook) FlowWebhookResp {
+	resp := FlowWebhookResp{
+		FlowID:    w.FlowID,
+		CreatedAt: w.CreatedAt.Format(TimeFormat),
+	}
+	if w.LastUsedAt != nil {
+		resp.LastUsedAt = w.LastUsedAt.Format(TimeFormat)
+	}
+	return resp
+}
+
+type GitSyncResp struct {
+	Message string `json:"message"`
+}
+
+type GitSyncStatusResp struct {
+	Namespace  string `json:"namespace"`
+	Branch     string `json:"branch"`
+	Path       string `json:"path"`
+	LastCommit string `json:"last_commit"`
+	LastSync   string `json:"last_sync"`
+	LastError  string `json:"last_error"`
+}
+
+type FailureAnalyticsReq struct {
+	From   string `query:"from"`
+	To     string `query:"to"`
+	Window string `query:"window" validate:"omitempty,oneof=24h 7d 30d 90d"`
+	Limit  int    `query:"limit" validate:"min=0,max=100"`
+}
+
+type FailureAnalyticsResp struct {
+	From          string             `json:"from"`
+	To            string             `json:"to"`
+	TotalFailures int                `json:"total_failures"`
+	Causes        []FailureCauseResp `json:"causes"`
+	Actions       []FailureCountResp `json:"actions"`
+	Nodes         []FailureCountResp `json:"nodes"`
+}
+
+type FailureCauseResp struct {
+	Message       string   `json:"message"`
+	SampleError   string   `json:"sample_error"`
+	Count         int      `json:"count"`
+	FlowIDs       []string `json:"flow_ids"`
+	ActionIDs     []string `json:"action_ids"`
+	SampleExecIDs []string `json:"sample_exec_ids"`
+	LastSeen      string   `json:"last_seen"`
+}
+
+type FailureCountResp struct {
+	Name     string `json:"name"`
+	Count    int    `json:"count"`
+	LastSeen string `json:"last_seen"`
+}
+
+func coreFailureAnalyticsToResp(a models.FailureAnalytics) FailureAnalyticsResp {
+	resp := FailureAnalyticsResp{
+		From:          a.From.Format(TimeFormat),
+		To:            a.To.Format(TimeFormat),
+		TotalFailures: a.TotalFailures,
+		Causes:        make([]FailureCauseResp, len(a.Causes)),
+		Actions:       coreFailureCountsToResp(a.Actions),
+		Nodes:         coreFailureCountsToResp(a.Nodes),
+	}
+	for i, c := range a.Causes {
+		resp.Causes[i] = FailureCauseResp{
+			Message:       c.Message,
+			SampleError:   c.SampleError,
+			Count:         c.Count,
+			FlowIDs:       c.FlowIDs,
+			ActionIDs:     c.ActionIDs,
+			SampleExecIDs: c.SampleExecIDs,
+			LastSeen:      c.LastSeen.Format(TimeFormat),
+		}
+		if resp.Causes[i].ActionIDs == nil {
+			resp.Causes[i].ActionIDs = []string{}
+		}
+	}
+	return resp
+}
+
+func coreFailureCountsToResp(counts []models.FailureCount) []FailureCountResp {
+	resp := make([]FailureCountResp, len(counts))
+	for i, c := range counts {
+		resp[i] = FailureCountResp{
+			Name:     c.Name,
+			Count:    c.Count,
+			LastSeen: c.LastSeen.Format(TimeFormat),
+		}
+	}
+	return resp
+}
+
+type RetentionPolicyReq struct {
+	MaxAgeDays int `json:"max_age_days" validate:"min=0,max=36500"`
+	KeepLast   int `json:"keep_last" validate:"min=0,max=10000000"`
+}
+
+type RetentionPolicyResp struct {
+	Enabled    bool   `json:"enabled"`
+	MaxAgeDays int    `json:"max_age_days"`
+	KeepLast   int    `json:"keep_last"`
+	UpdatedAt  string `json:"updated_at,omitempty"`
+}
+
+type RetentionPreviewResp struct {
+	Policy        RetentionPolicyResp `json:"policy"`
+	Total         int64               `json:"total"`
+	Archived      int64               `json:"archived"`
+	ByStatus      map[string]int64    `json:"by_status"`
+	Oldest        string              `json:"oldest,omitempty"`
+	Newest        string              `json:"newest,omitempty"`
+	SampleExecIDs []string            `json:"sample_exec_ids"`
+}
+
+func coreRetentionPolicyToResp(p models.RetentionPolicy) RetentionPolicyResp {
+	resp := RetentionPolicyResp{
+		Enabled:    p.Enabled(),
+		MaxAgeDays: p.MaxAgeDays,
+		KeepLast:   p.KeepLast,
+	}
+	if !p.UpdatedAt.IsZero() {
+		resp.UpdatedAt = p.UpdatedAt.Format(TimeFormat)
+	}
+	return resp
+}
+
+func coreRetentionPreviewToResp(p models.RetentionPreview) RetentionPreviewResp {
+	resp := RetentionPreviewResp{
+		Policy:        coreRetentionPolicyToResp(p.Policy),
+		Total:         p.Total,
+		Archived:      p.Archived,
+		ByStatus:      make(map[string]int64, len(p.ByStatus)),
+		SampleExecIDs: p.SampleExecIDs,
+	}
+	for status, count := range p.ByStatus {
+		resp.ByStatus[string(status)] = count
+	}
+	if !p.Oldest.IsZero() {
+		resp.Oldest = p.Oldest.Format(TimeFormat)
+		resp.Newest = p.Newest.Format(TimeFormat)
+	}
+	if resp.SampleExecIDs == nil {
+		resp.SampleExecIDs = []string{}
+	}
+	return resp
+}
+
+type NamespaceQuotaReq struct {
+	MaxExecutionsPerDay     int   `json:"max_executions_per_day" validate:"min=0,max=10000000"`
+	MaxConcurrentExecutions int   `json:"max_concurrent_executions" validate:"min=0,max=100000"`
+	MaxNodes                int   `json:"max_nodes" validate:"min=0,max=1000000"`
+	MaxLogBytes             int64 `json:"max_log_bytes" validate:"min=0"`
+}
+
+type NamespaceQuotaResp struct {
+	Enabled                 bool   `json:"enabled"`
+	MaxExecutionsPerDay     int    `json:"max_executions_per_day"`
+	MaxConcurrentExecutions int    `json:"max_concurrent_executions"`
+	MaxNodes                int    `json:"max_nodes"`
+	MaxLogBytes             int64  `json:"max_log_bytes"`
+	UpdatedAt               string `json:"updated_at,omitempty"`
+}
+
+type NamespaceUsageResp struct {
+	Quota                NamespaceQuotaResp `json:"quota"`
+	ExecutionsToday      int                `json:"executions_today"`
+	ConcurrentExecutions int                `json:"concurrent_executions"`
+	Nodes                int                `json:"nodes"`
+	LogBytes             int64              `json:"log_bytes"`
+}
+
+func coreNamespaceQuotaToResp(q models.NamespaceQuota) NamespaceQuotaResp {
+	resp := NamespaceQuotaResp{
+		Enabled:                 q.Enabled(),
+		MaxExecutionsPerDay:     q.MaxExecutionsPerDay,
+		MaxConcurrentExecutions: q.MaxConcurrentExecutions,
+		MaxNodes:                q.MaxNodes,
+		MaxLogBytes:             q.MaxLogBytes,
+	}
+	if !q.UpdatedAt.IsZero() {
+		resp.UpdatedAt = q.UpdatedAt.Format(TimeFormat)
+	}
+	return resp
+}
+
+func coreNamespaceUsageToResp(u models.NamespaceUsage) NamespaceUsageResp {
+	return NamespaceUsageResp{
+		Quota:                coreNamespaceQuotaToResp(u.Quota),
+		ExecutionsToday:      u.ExecutionsToday,
+		ConcurrentExecutions: u.ConcurrentExecutions,
+		Nodes:                u.Nodes,
+		LogBytes:             u.LogBytes,
+	}
+}
+
+type NamespaceDefaultsReq struct {
+	Timezone        string                    `json:"timezone" validate:"omitempty,timezone"`
+	Notify          []Notify                  `json:"notify" validate:"omitempty,max=20,dive"`
+	ExecutorOptions map[string]map[string]any `json:"executor_options" validate:"omitempty,max=50"`
+	MaxConcurrency  int                       `json:"max_concurrency" validate:"gte=0,lte=1000"`
+}
+
+type NamespaceDefaultsResp struct {
+	Enabled         bool                      `json:"enabled"`
+	Timezone        string                    `json:"timezone"`
+	Notify          []Notify                  `json:"notify"`
+	ExecutorOptions map[string]map[string]any `json:"executor_options"`
+	MaxConcurrency  int                       `json:"max_concurrency"`
+	UpdatedAt       string                    `json:"updated_at,omitempty"`
+}
+
+func coreNamespaceDefaultsToResp(d models.NamespaceDefaults) NamespaceDefaultsResp {
+	resp := NamespaceDefaultsResp{
+		Enabled:         d.Enabled(),
+		Timezone:        d.Timezone,
+		Notify:          convertNotifyToNotifyReq(d.Notify),
+		ExecutorOptions: d.ExecutorOptions,
+		MaxConcurrency:  d.MaxConcurrency,
+	}
+	if resp.ExecutorOptions == nil {
+		resp.ExecutorOptions = map[string]map[string]any{}
+	}
+	if !d.UpdatedAt.IsZero() {
+		resp.UpdatedAt = d.UpdatedAt.Format(TimeFormat)
+	}
+	return resp
+}
+
+type ExecutorPolicyReq struct {
+	AllowedExecutors []string `json:"allowed_executors" validate:"max=50,dive,required"`
+}
+
+type ExecutorPolicyResp struct {
+	Enabled          bool     `json:"enabled"`
+	AllowedExecutors []string `json:"allowed_executors"`
+	UpdatedAt        string   `json:"updated_at,omitempty"`
+}
+
+func coreExecutorPolicyToResp(p models.ExecutorPolicy) ExecutorPolicyResp {
+	resp := ExecutorPolicyResp{
+		Enabled:          p.Enabled(),
+		AllowedExecutors: p.AllowedExecutors,
+	}
+	if resp.AllowedExecutors == nil {
+		resp.AllowedExecutors = []string{}
+	}
+	if !p.UpdatedAt.IsZero() {
+		resp.UpdatedAt = p.UpdatedAt.Format(TimeFormat)
+	}
+	return resp
+}
+
+type VariableReq struct {
+	Key         string `param:"key" validate:"required,min=1,max=150,alphanum_underscore"`
+	Value       string `json:"value" validate:"max=4096"`
+	Description string `json:"description" validate:"max=255"`
+}
+
+type VariableResp struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Description string `json:"description"`
+	Scope       string `json:"scope"`
+	UpdatedAt   string `json:"updated_at"`
+}
+
+func coreVariableToResp(v models.ConfigVariable) VariableResp {
+	return VariableResp{
+		Key:         v.Key,
+		Value:       v.Value,
+		Description: v.Description,
+		Scope:       v.Scope,
+		UpdatedAt:   v.UpdatedAt.Format(TimeFormat),
+	}
+}
+
+func coreVariablesToResp(variables []models.ConfigVariable) []VariableResp {
+	resp := make([]VariableResp, len(variables))
+	for i, v := range variables {
+		resp[i] = coreVariableToResp(v)
+	}
+	return resp
+}
+
+type RecentFlowsReq struct {
+	Count int `query:"count" validate:"min=0,max=50"`
+}
+
+type RecentFlowItem struct {
+	FlowListItem
+	LastRunAt string `json:"last_run_at"`
+}
+
+type RecentFlowsResp struct {
+	Flows []RecentFlowItem `json:"flows"`
+}
+
+type ExecutionWatchResp struct {
+	FlowID    string `json:"flow_id"`
+	FlowName  string `json:"flow_name"`
+	ExecID    string `json:"exec_id,omitempty"`
+	CreatedAt string `json:"created_at"`
+}
+
+type ExecutionWatchesResp struct {
+	Watches []ExecutionWatchResp `json:"watches"`
+}
+
+type FlowPromoteReq struct {
+	Namespace   string `json:"namespace" validate:"required"`
+	ID          string `json:"id" validate:"omitempty,alphanum_underscore,max=150"`
+	OnConflict  string `json:"on_conflict" validate:"omitempty,oneof=fail rename replace"`
+	CopySecrets bool   `json:"copy_secrets"`
+}
+
+type FlowPromotionResp struct {
+	ID              int32    `json:"id"`
+	SourceNamespace string   `json:"source_namespace"`
+	SourceFlowID    string   `json:"source_flow_id"`
+	SourceChecksum  string   `json:"source_checksum"`
+	TargetNamespace string   `json:"target_namespace"`
+	TargetFlowID    string   `json:"target_flow_id"`
+	RevisionID      string   `json:"revision_id,omitempty"`
+	SecretKeys      []string `json:"secret_keys,omitempty"`
+	PromotedBy      string   `json:"promoted_by,omitempty"`
+	CreatedAt       string   `json:"created_at"`
+}
+
+func coreFlowPromotionToResp(p models.FlowPromotion) FlowPromotionResp {
+	return FlowPromotionResp{
+		ID:              p.ID,
+		SourceNamespace: p.SourceNamespace,
+		SourceFlowID:    p.SourceFlowID,
+		SourceChecksum:  p.SourceChecksum,
+		TargetNamespace: p.TargetNamespace,
+		TargetFlowID:    p.TargetFlowID,
+		RevisionID:      p.RevisionID,
+		SecretKeys:      p.SecretKeys,
+		PromotedBy:      p.PromotedBy,
+		CreatedAt:       p.CreatedAt.Format(TimeFormat),
+	}
+}
+
+type FlowPromotionsResp struct {
+	Promotions []FlowPromotionResp `json:"promotions"`
+}
+
+type InputPresetReq struct {
+	Name   string            `json:"name" validate:"required,min=1,max=150"`
+	Inputs map[string]string `json:"inputs" validate:"required"`
+	Shared bool              `json:"shared"`
+}
+
+type InputPresetResp struct {
+	ID        string            `json:"id"`
+	Name      string            `json:"name"`
+	Inputs    map[string]string `json:"inputs"`
+	Shared    bool              `json:"shared"`
+	CreatedBy string            `json:"created_by"`
+	CreatedAt string            `json:"created_at"`
+	UpdatedAt string            `json:"updated_at"`
+}
+
+func coreInputPresetToResp(p models.InputPreset) InputPresetResp {
+	return InputPresetResp{
+		ID:        p.ID,
+		Name:      p.Name,
+		Inputs:    p.Inputs,
+		Shared:    p.Shared,
+		CreatedBy: p.CreatedByName,
+		CreatedAt: p.CreatedAt.Format(TimeFormat),
+		UpdatedAt: p.UpdatedAt.Format(TimeFormat),
+	}
+}
+
+type InputPresetsResp struct {
+	Presets []InputPresetResp `json:"presets"`
+}
+
+type ManualTaskGetReq struct {
+	TaskID string `param:"taskID" validate:"required,uuid4"`
+}
+
+type ManualTaskCompleteReq struct {
+	TaskID    string         `param:"taskID" validate:"required,uuid4"`
+	Checklist []string       `json:"checklist"`
+	Inputs    map[string]any `json:"inputs"`
+	Comment   string         `json:"comment" validate:"max=1000"`
+}
+
+type ManualTaskPaginateRequest struct {
+	Status string `query:"status" validate:"oneof='' pending completed cancelled"`
+	Page   int    `query:"page"`
+	Count  int    `query:"count_per_page"`
+}
+
+type ManualTaskResp struct {
+	ID           string         `json:"id"`
+	ExecID       string         `json:"exec_id"`
+	ActionID     string         `json:"action_id"`
+	FlowID       string         `json:"flow_id"`
+	FlowName     string         `json:"flow_name"`
+	Status       string         `json:"status"`
+	Instructions string         `json:"instructions,omitempty"`
+	Group        string         `json:"group"`
+	Checklist    []string       `json:"checklist"`
+	Inputs       []FlowInput    `json:"inputs"`
+	Response     map[string]any `json:"response,omitempty"`
+	Comment      string         `json:"comment,omitempty"`
+	CompletedBy  string         `json:"completed_by,omitempty"`
+	CreatedAt    string         `json:"created_at"`
+	UpdatedAt    string         `json:"updated_at"`
+}
+
+func coreManualTaskToResp(t models.ManualTaskDetails) ManualTaskResp {
+	checklist := t.Checklist
+	if checklist == nil {
+		checklist = []string{}
+	}
+	return ManualTaskResp{
+		ID:           t.UUID,
+		ExecID:       t.ExecID,
+		ActionID:     t.ActionID,
+		FlowID:       t.FlowID,
+		FlowName:     t.FlowName,
+		Status:       string(t.Status),
+		Instructions: t.Instructions,
+		Group:        t.Group,
+		Checklist:    checklist,
+		Inputs:       coreFlowInputsToInputs(t.Inputs),
+		Response:     t.Response,
+		Comment:      t.Comment,
+		CompletedBy:  t.CompletedBy,
+		CreatedAt:    t.CreatedAt,
+		UpdatedAt:    t.UpdatedAt,
+	}
+}
+
+type ManualTasksPaginateResponse struct {
+	Tasks      []ManualTaskResp `json:"tasks"`
+	PageCount  int64            `json:"page_count"`
+	TotalCount int64            `json:"total_count"`
+}
+
+type ArtifactGetReq struct {
+	ExecID     string `param:"execID" validate:"required,uuid4"`
+	ArtifactID string `param:"artifactID" validate:"required,uuid4"`
+}
+
+type ArtifactResp struct {
+	ID        string `json:"id"`
+	ActionID  string `json:"action_id"`
+	Name      string `json:"name"`
+	Size      int64  `json:"size"`
+	CreatedAt string `json:"created_at"`
+	// URL is the endpoint that downloads the artifact
+	URL string `json:"url"`
+}
+
+type ExecutionArtifactsResp struct {
+	ExecID    string         `json:"exec_id"`
+	Artifacts []ArtifactResp `json:"artifacts"`
+}
+
+type AuditEntryResp struct {
+	ID           int64  `json:"id"`
+	NamespaceID  string `json:"namespace_id,omitempty"`
+	ActorID      string `json:"actor_id"`
+	ActorName    string `json:"actor_name"`
+	Action       string `json:"action"`
+	ResourceType string `json:"resource_type"`
+	ResourceID   string `json:"resource_id"`
+	Before       any    `json:"before"`
+	After        any    `json:"after"`
+	SourceIP     string `json:"source_ip"`
+	CreatedAt    string `json:"created_at"`
+}
+
+func coreAuditEntryToAuditEntryResp(e models.AuditEntry) AuditEntryResp {
+	return AuditEntryResp{
+		ID:           e.ID,
+		NamespaceID:  e.NamespaceID,
+		ActorID:      e.ActorID,
+		ActorName:    e.ActorName,
+		Action:       e.Action,
+		ResourceType: e.ResourceType,
+		ResourceID:   e.ResourceID,
+		Before:       e.Before,
+		After:        e.After,
+		SourceIP:     e.SourceIP,
+		CreatedAt:    e.CreatedAt.Format(TimeFormat),
+	}
+}
+
+type AuditLogPaginateResponse struct {
+	Entries    []AuditEntryResp `json:"entries"`
+	PageCount  int64            `json:"page_count"`
+	TotalCount int64            `json:"total_count"`
+}
@@ -40,7 +40,7 @@ func (h *Handler) HandleUpdateUser(c echo.Context) error {
 		return wrapError(ErrRequiredFieldMissing, "user ID cannot be empty", nil, nil)
 	}
 
-	_, err := h.co.GetUserWithUUIDWithGroups(c.Request().Context(), userID)
+	existing, err := h.co.GetUserWithUUIDWithGroups(c.Request().Context(), userID)
 	if err != nil {
 		return wrapError(ErrResourceNotFound, "user not found", err, nil)
 	}
@@ -65,10 +65,16 @@ func (h *Handler) HandleUpdateUser(c echo.Context) error {
 		return wrapError(ErrOperationFailed, err.Error(), err, nil)
 	}
 
-	return c.JSON(http.StatusOK, UserWithGroups{
+	resp := UserWithGroups{
 		User:   coreUsertoUser(user.User),
 		Groups: coreGroupArrayCast(user.Groups),
-	})
+	}
+	h.audit(c, models.AuditUserUpdate, models.AuditResourceUser, userID, UserWithGroups{
+		User:   coreUsertoUser(existing.User),
+		Groups: coreGroupArrayCast(existing.Groups),
+	}, resp)
+
+	return c.JSON(http.StatusOK, resp)
 }
 
 func (h *Handler) HandleUserPagination(c echo.Context) error {
@@ -116,11 +122,21 @@ func (h *Handler) HandleDeleteUser(c echo.Context) error {
 		return wrapError(ErrRequiredFieldMissing, "user id cannot be empty", nil, nil)
 	}
 
-	err := h.co.DeleteUserByUUID(c.Request().Context(), userID)
+	existing, err := h.co.GetUserWithUUIDWithGroups(c.Request().Context(), userID)
+	if err != nil {
+		return wrapError(ErrResourceNotFound, "user not found", err, nil)
+	}
+
+	err = h.co.DeleteUserByUUID(c.Request().Context(), userID)
 	if err != nil {
 		return wrapError(ErrOperationFailed, err.Error(), err, nil)
 	}
 
+	h.audit(c, models.AuditUserDelete, models.AuditResourceUser, userID, UserWithGroups{
+		User:   coreUsertoUser(existing.User),
+		Groups: coreGroupArrayCast(existing.Groups),
+	}, nil)
+
 	return c.NoContent(http.StatusOK)
 }
 
@@ -144,8 +160,11 @@ func (h *Handler) HandleCreateUser(c echo.Context) error {
 		return wrapError(ErrOperationFailed, "could not retrieve created user", err, nil)
 	}
 
-	return c.JSON(http.StatusCreated, UserWithGroups{
+	resp := UserWithGroups{
 		User:   coreUsertoUser(user.User),
 		Groups: coreGroupArrayCast(user.Groups),
-	})
+	}
+	h.audit(c, models.AuditUserCreate, models.AuditResourceUser, resp.ID, nil, resp)
+
+	return c.JSON(http.StatusCreated, resp)
 }
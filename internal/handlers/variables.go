@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/cvhariharan/flowctl/internal/core"
+	"github.com/cvhariharan/flowctl/internal/core/models"
+	"github.com/labstack/echo/v4"
+)
+
+func (h *Handler) HandleListGlobalVariables(c echo.Context) error {
+	variables, err := h.co.ListGlobalVariables(c.Request().Context())
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not list global variables", err, nil)
+	}
+
+	return c.JSON(http.StatusOK, coreVariablesToResp(variables))
+}
+
+func (h *Handler) HandleSetGlobalVariable(c echo.Context) error {
+	var req VariableReq
+	if err := c.Bind(&req); err != nil {
+		return wrapError(ErrInvalidInput, "could not decode request", err, nil)
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return wrapError(ErrValidationFailed, fmt.Sprintf("request validation failed: %s", formatValidationErrors(err)), err, nil)
+	}
+
+	variable, err := h.co.SetGlobalVariable(c.Request().Context(), models.ConfigVariable{
+		Key:         req.Key,
+		Value:       req.Value,
+		Description: req.Description,
+	})
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not save global variable", err, nil)
+	}
+
+	return c.JSON(http.StatusOK, coreVariableToResp(variable))
+}
+
+func (h *Handler) HandleDeleteGlobalVariable(c echo.Context) error {
+	key := c.Param("key")
+	if key == "" {
+		return wrapError(ErrRequiredFieldMissing, "variable key cannot be empty", nil, nil)
+	}
+
+	err := h.co.DeleteGlobalVariable(c.Request().Context(), key)
+	if errors.Is(err, core.ErrVariableNotFound) {
+		return wrapError(ErrResourceNotFound, "variable not found", err, nil)
+	}
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not delete global variable", err, nil)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// HandleListNamespaceVariables returns the variables available to the flows of the namespace,
+// including the global ones
+func (h *Handler) HandleListNamespaceVariables(c echo.Context) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	variables, err := h.co.ListNamespaceVariables(c.Request().Context(), namespace)
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not list namespace variables", err, nil)
+	}
+
+	return c.JSON(http.StatusOK, coreVariablesToResp(variables))
+}
+
+func (h *Handler) HandleSetNamespaceVariable(c echo.Context) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	var req VariableReq
+	if err := c.Bind(&req); err != nil {
+		return wrapError(ErrInvalidInput, "could not decode request", err, nil)
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return wrapError(ErrValidationFailed, fmt.Sprintf("request validation failed: %s", formatValidationErrors(err)), err, nil)
+	}
+
+	variable, err := h.co.SetNamespaceVariable(c.Request().Context(), namespace, models.ConfigVariable{
+		Key:         req.Key,
+		Value:       req.Value,
+		Description: req.Description,
+	})
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not save namespace variable", err, nil)
+	}
+
+	return c.JSON(http.StatusOK, coreVariableToResp(variable))
+}
+
+func (h *Handler) HandleDeleteNamespaceVariable(c echo.Context) error {
+	namespace, ok := c.Get("namespace").(string)
+	if !ok {
+		return wrapError(ErrRequiredFieldMissing, "could not get namespace", nil, nil)
+	}
+
+	key := c.Param("key")
+	if key == "" {
+		return wrapError(ErrRequiredFieldMissing, "variable key cannot be empty", nil, nil)
+	}
+
+	err := h.co.DeleteNamespaceVariable(c.Request().Context(), namespace, key)
+	if errors.Is(err, core.ErrVariableNotFound) {
+		return wrapError(ErrResourceNotFound, "variable not found", err, nil)
+	}
+	if err != nil {
+		return wrapError(ErrOperationFailed, "could not delete namespace variable", err, nil)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
@@ -0,0 +1,188 @@
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+//go:embed locales/*.json
+var locales embed.FS
+
+// placeholder is the verb translations use for the variable parts of a message
+const placeholder = "%s"
+
+// template matches messages that contain variable parts, such as the name of an executor
+type template struct {
+	code string
+	re   *regexp.Regexp
+}
+
+// Catalog maps the English messages returned by the API to stable message codes and their
+// translations. English is the source language, a message that is not in the catalog is
+// returned as is.
+type Catalog struct {
+	tags      []language.Tag
+	matcher   language.Matcher
+	messages  []map[string]string
+	codes     map[string]string
+	templates []template
+}
+
+// New loads the catalog from the embedded locales. Every locale is a JSON object of message
+// codes to messages named after its language tag, en.json being the source.
+func New() (*Catalog, error) {
+	entries, err := locales.ReadDir("locales")
+	if err != nil {
+		return nil, fmt.Errorf("could not read locales: %w", err)
+	}
+
+	c := &Catalog{
+		tags:     []language.Tag{language.English},
+		messages: []map[string]string{nil},
+		codes:    make(map[string]string),
+	}
+
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".json")
+		tag, err := language.Parse(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid locale %s: %w", entry.Name(), err)
+		}
+
+		data, err := locales.ReadFile(path.Join("locales", entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("could not read locale %s: %w", entry.Name(), err)
+		}
+
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return nil, fmt.Errorf("could not parse locale %s: %w", entry.Name(), err)
+		}
+
+		// English is always the first tag so that it is the fallback of the matcher
+		if tag == language.English {
+			c.messages[0] = messages
+			continue
+		}
+		c.tags = append(c.tags, tag)
+		c.messages = append(c.messages, messages)
+	}
+
+	if c.messages[0] == nil {
+		return nil, fmt.Errorf("missing source locale en.json")
+	}
+
+	for code, msg := range c.messages[0] {
+		if !strings.Contains(msg, placeholder) {
+			c.codes[strings.ToLower(msg)] = code
+			continue
+		}
+
+		parts := strings.Split(msg, placeholder)
+		for i, p := range parts {
+			parts[i] = regexp.QuoteMeta(p)
+		}
+		re, err := regexp.Compile("(?i)^" + strings.Join(parts, "(.+?)") + "$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid message %s: %w", code, err)
+		}
+		c.templates = append(c.templates, template{code: code, re: re})
+	}
+
+	c.matcher = language.NewMatcher(c.tags)
+
+	return c, nil
+}
+
+// MustNew is like New but panics if the catalog cannot be loaded
+func MustNew() *Catalog {
+	c, err := New()
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// Languages returns the tags of the languages the catalog has translations for
+func (c *Catalog) Languages() []language.Tag {
+	return c.tags
+}
+
+// Match returns the supported language that best matches an Accept-Language header. English
+// is returned if none does.
+func (c *Catalog) Match(acceptLanguage string) language.Tag {
+	return c.tags[c.match(acceptLanguage)]
+}
+
+func (c *Catalog) match(acceptLanguage string) int {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return 0
+	}
+	_, index, _ := c.matcher.Match(tags...)
+	return index
+}
+
+// Localize returns the code of the message and its translation in the language that best
+// matches the Accept-Language header. Messages that wrap the cause of an error, such as
+// "request validation failed: name is required", are matched on the part before the first
+// colon and the cause is kept as is. An empty code is returned with the original message if
+// it is not in the catalog.
+func (c *Catalog) Localize(msg string, acceptLanguage string) (string, string) {
+	index := c.match(acceptLanguage)
+	messages := c.messages[index]
+
+	code, localized, ok := c.lookup(msg, messages)
+	if !ok {
+		prefix, rest, found := strings.Cut(msg, ": ")
+		if !found {
+			return "", msg
+		}
+		if code, localized, ok = c.lookup(prefix, messages); !ok {
+			return "", msg
+		}
+		localized += ": " + rest
+	}
+
+	// English messages are returned exactly as the handlers wrote them
+	if index == 0 {
+		return code, msg
+	}
+	return code, localized
+}
+
+func (c *Catalog) lookup(msg string, messages map[string]string) (string, string, bool) {
+	if code, ok := c.codes[strings.ToLower(msg)]; ok {
+		return code, c.translate(code, messages, msg), true
+	}
+
+	for _, t := range c.templates {
+		m := t.re.FindStringSubmatch(msg)
+		if m == nil {
+			continue
+		}
+
+		args := make([]any, len(m)-1)
+		for i, v := range m[1:] {
+			args[i] = v
+		}
+		return t.code, fmt.Sprintf(c.translate(t.code, messages, c.messages[0][t.code]), args...), true
+	}
+
+	return "", "", false
+}
+
+// translate returns the translation of a code, falling back to the given message if the
+// language has none
+func (c *Catalog) translate(code string, messages map[string]string, fallback string) string {
+	if msg, ok := messages[code]; ok && msg != "" {
+		return msg
+	}
+	return fallback
+}
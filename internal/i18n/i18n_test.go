@@ -0,0 +1,128 @@
+package i18n
+
+import (
+	"encoding/json"
+	"path"
+	"testing"
+)
+
+func TestLocalize(t *testing.T) {
+	c, err := New()
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		msg            string
+		acceptLanguage string
+		wantCode       string
+		wantMsg        string
+	}{
+		{
+			name:     "english is returned as is",
+			msg:      "could not get flow",
+			wantCode: "could_not_get_flow",
+			wantMsg:  "could not get flow",
+		},
+		{
+			name:           "exact match",
+			msg:            "flow not found",
+			acceptLanguage: "es-ES,es;q=0.9,en;q=0.8",
+			wantCode:       "flow_not_found",
+			wantMsg:        "no se encontró el flujo",
+		},
+		{
+			name:           "case insensitive match",
+			msg:            "user ID cannot be empty",
+			acceptLanguage: "de",
+			wantCode:       "user_id_cannot_be_empty",
+			wantMsg:        "Benutzer-ID darf nicht leer sein",
+		},
+		{
+			name:           "template",
+			msg:            "unknown executor docker",
+			acceptLanguage: "de-DE",
+			wantCode:       "unknown_executor",
+			wantMsg:        "unbekannter Executor docker",
+		},
+		{
+			name:           "wrapped cause",
+			msg:            "request validation failed: name is required",
+			acceptLanguage: "de",
+			wantCode:       "request_validation_failed",
+			wantMsg:        "Validierung der Anfrage fehlgeschlagen: name is required",
+		},
+		{
+			name:           "unknown message",
+			msg:            "something went wrong",
+			acceptLanguage: "de",
+			wantCode:       "",
+			wantMsg:        "something went wrong",
+		},
+		{
+			name:           "unsupported language",
+			msg:            "flow not found",
+			acceptLanguage: "ja",
+			wantCode:       "flow_not_found",
+			wantMsg:        "flow not found",
+		},
+		{
+			name:           "invalid header",
+			msg:            "flow not found",
+			acceptLanguage: ";;q=",
+			wantCode:       "flow_not_found",
+			wantMsg:        "flow not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, msg := c.Localize(tt.msg, tt.acceptLanguage)
+			if code != tt.wantCode {
+				t.Errorf("Localize() code = %q, want %q", code, tt.wantCode)
+			}
+			if msg != tt.wantMsg {
+				t.Errorf("Localize() msg = %q, want %q", msg, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestLocalesAreComplete(t *testing.T) {
+	entries, err := locales.ReadDir("locales")
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+
+	load := func(name string) map[string]string {
+		data, err := locales.ReadFile(path.Join("locales", name))
+		if err != nil {
+			t.Fatalf("ReadFile(%s) error = %v", name, err)
+		}
+		var m map[string]string
+		if err := json.Unmarshal(data, &m); err != nil {
+			t.Fatalf("Unmarshal(%s) error = %v", name, err)
+		}
+		return m
+	}
+
+	source := load("en.json")
+	for _, entry := range entries {
+		if entry.Name() == "en.json" {
+			continue
+		}
+
+		messages := load(entry.Name())
+		for code := range source {
+			if _, ok := messages[code]; !ok {
+				t.Errorf("%s is missing %s", entry.Name(), code)
+			}
+		}
+		for code := range messages {
+			if _, ok := source[code]; !ok {
+				t.Errorf("%s has unknown code %s", entry.Name(), code)
+			}
+		}
+	}
+}
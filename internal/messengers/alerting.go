@@ -0,0 +1,64 @@
+package messengers
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// alertKey returns the deduplication key used for incidents raised by a flow. It is stable
+// across executions so that a later successful run resolves the incident opened by a failure.
+func alertKey(evt FlowExecutionEvent) string {
+	return fmt.Sprintf("flowctl/%s/%s", evt.Namespace, evt.FlowID)
+}
+
+// slaAlertKey returns the deduplication key used for SLA breach alerts. It is unique per
+// execution so that a breach does not resolve, or get resolved by, the flow's failure incident.
+func slaAlertKey(evt FlowExecutionEvent) string {
+	return fmt.Sprintf("flowctl/%s/%s/sla/%s", evt.Namespace, evt.FlowID, evt.ExecID)
+}
+
+// resolveAlertKey picks the routing key for an alert. A key in the flow's notify config takes
+// precedence over the namespace mapping, which in turn overrides the server default.
+func resolveAlertKey(cfg map[string]any, field, namespace string, namespaceKeys map[string]string, defaultKey string) string {
+	if key, _ := cfg[field].(string); key != "" {
+		return key
+	}
+	if key := namespaceKeys[namespace]; key != "" {
+		return key
+	}
+	return defaultKey
+}
+
+// alertDetails returns the execution metadata attached to an alert.
+func alertDetails(evt FlowExecutionEvent) map[string]string {
+	details := map[string]string{
+		"flow_id":   evt.FlowID,
+		"flow_name": evt.FlowName,
+		"exec_id":   evt.ExecID,
+		"namespace": evt.Namespace,
+		"status":    evt.Status,
+	}
+	if evt.Error != "" {
+		details["error"] = evt.Error
+	}
+	if evt.Reason != "" {
+		details["reason"] = evt.Reason
+	}
+	return details
+}
+
+// executionURL returns the link to the execution results page.
+func executionURL(rootURL string, evt FlowExecutionEvent) string {
+	return fmt.Sprintf("%s/view/%s/results/%s/%s", rootURL, evt.Namespace, evt.FlowID, evt.ExecID)
+}
+
+// truncate shortens s to at most n bytes without splitting a UTF-8 encoded character.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
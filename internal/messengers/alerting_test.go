@@ -0,0 +1,70 @@
+package messengers
+
+import "testing"
+
+func TestResolveAlertKey(t *testing.T) {
+	namespaceKeys := map[string]string{"prod": "prod-key", "empty": ""}
+
+	tests := []struct {
+		name      string
+		cfg       map[string]any
+		namespace string
+		want      string
+	}{
+		{"flow key", map[string]any{"routing_key": "flow-key"}, "prod", "flow-key"},
+		{"namespace key", nil, "prod", "prod-key"},
+		{"empty flow key uses namespace key", map[string]any{"routing_key": ""}, "prod", "prod-key"},
+		{"non string flow key is ignored", map[string]any{"routing_key": 42}, "prod", "prod-key"},
+		{"key of another field is ignored", map[string]any{"api_key": "flow-key"}, "prod", "prod-key"},
+		{"default key", nil, "staging", "default-key"},
+		{"empty namespace key uses default", nil, "empty", "default-key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resolveAlertKey(tt.cfg, "routing_key", tt.namespace, namespaceKeys, "default-key"); got != tt.want {
+				t.Errorf("resolveAlertKey() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAlertKeys(t *testing.T) {
+	failed := FlowExecutionEvent{FlowID: "deploy", ExecID: "exec-1", Namespace: "prod", Status: "errored"}
+	succeeded := FlowExecutionEvent{FlowID: "deploy", ExecID: "exec-2", Namespace: "prod", Status: "completed"}
+	other := FlowExecutionEvent{FlowID: "deploy", ExecID: "exec-1", Namespace: "staging", Status: "errored"}
+
+	if alertKey(failed) != alertKey(succeeded) {
+		t.Errorf("alertKey() differs between executions of a flow: %q, %q", alertKey(failed), alertKey(succeeded))
+	}
+	if alertKey(failed) == alertKey(other) {
+		t.Errorf("alertKey() = %q for flows in different namespaces", alertKey(failed))
+	}
+	if slaAlertKey(failed) == slaAlertKey(succeeded) {
+		t.Errorf("slaAlertKey() = %q for different executions", slaAlertKey(failed))
+	}
+	if slaAlertKey(failed) == alertKey(failed) {
+		t.Errorf("slaAlertKey() = alertKey() = %q", alertKey(failed))
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		s    string
+		n    int
+		want string
+	}{
+		{"deploy", 10, "deploy"},
+		{"deploy", 3, "dep"},
+		{"déploiement", 2, "d"},
+		{"déploiement", 3, "dé"},
+		{"日本語", 4, "日"},
+		{"日本語", 0, ""},
+	}
+
+	for _, tt := range tests {
+		if got := truncate(tt.s, tt.n); got != tt.want {
+			t.Errorf("truncate(%q, %d) = %q, want %q", tt.s, tt.n, got, tt.want)
+		}
+	}
+}
@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"context"
 	"embed"
+	"errors"
 	"fmt"
 	"html/template"
 	"log/slog"
+	"slices"
 	"strings"
 
 	"github.com/cvhariharan/flowctl/internal/config"
@@ -86,50 +88,88 @@ func NewEmailMessenger(cfg config.SMTPConfig, groupResolver GroupResolver, logge
 // Send sends an email message to receivers specified in msg.Config["receivers"].
 // Receivers can be email addresses or "group:name" references that are resolved via GroupResolver.
 func (e *EmailMessenger) Send(ctx context.Context, msg Message) error {
+	deliveries, err := e.SendWithReport(ctx, msg)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, d := range deliveries {
+		if d.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", d.Receiver, d.Err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// SendWithReport sends the message as a separate email to each resolved receiver, so that a
+// rejected address does not stop delivery to the others. Receivers in msg.SkipReceivers are skipped.
+func (e *EmailMessenger) SendWithReport(ctx context.Context, msg Message) ([]Delivery, error) {
 	receivers := configStringSlice(msg.Config, "receivers")
 	if len(receivers) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	to := e.resolveReceivers(ctx, receivers)
 	if len(to) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	var subject, body string
 	switch msg.Event {
-	case EventFlowExecution:
+	case EventFlowExecution, EventFlowSkipped, EventFlowSLABreach:
 		evt, ok := msg.Data.(FlowExecutionEvent)
 		if !ok {
-			return fmt.Errorf("email messenger: expected FlowExecutionEvent, got %T", msg.Data)
+			return nil, fmt.Errorf("email messenger: expected FlowExecutionEvent, got %T", msg.Data)
+		}
+		if evt.Status == "pending_approval" {
+			to = e.addDelegates(ctx, evt.Namespace, to)
 		}
 		subject = e.buildSubject(evt)
 		body = e.buildBody(evt)
+	case EventTest:
+		evt, ok := msg.Data.(TestEvent)
+		if !ok {
+			return nil, fmt.Errorf("email messenger: expected TestEvent, got %T", msg.Data)
+		}
+		subject = "[Test] flowctl notification"
+		body = fmt.Sprintf("<html><body><p>%s</p><p>Requested by %s.</p></body></html>",
+			template.HTMLEscapeString(evt.Message), template.HTMLEscapeString(evt.RequestedBy))
 	default:
-		return fmt.Errorf("email messenger: unsupported event type %q", msg.Event)
+		return nil, fmt.Errorf("email messenger: unsupported event type %q", msg.Event)
 	}
 
-	email := smtppool.Email{
-		From:    e.from,
-		To:      to,
-		Subject: subject,
-		HTML:    []byte(body),
-	}
+	var deliveries []Delivery
+	for _, receiver := range to {
+		if slices.Contains(msg.SkipReceivers, receiver) || slices.ContainsFunc(deliveries, func(d Delivery) bool { return d.Receiver == receiver }) {
+			continue
+		}
+
+		email := smtppool.Email{
+			From:    e.from,
+			To:      []string{receiver},
+			Subject: subject,
+			HTML:    []byte(body),
+		}
+
+		if err := e.pool.Send(email); err != nil {
+			e.logger.Error("failed to send email",
+				"to", receiver,
+				"subject", subject,
+				"error", err,
+			)
+			deliveries = append(deliveries, Delivery{Receiver: receiver, Err: fmt.Errorf("failed to send email: %w", err)})
+			continue
+		}
 
-	if err := e.pool.Send(email); err != nil {
-		e.logger.Error("failed to send email",
-			"to", to,
+		e.logger.Debug("email sent",
+			"to", receiver,
 			"subject", subject,
-			"error", err,
 		)
-		return fmt.Errorf("failed to send email: %w", err)
+		deliveries = append(deliveries, Delivery{Receiver: receiver})
 	}
 
-	e.logger.Debug("email sent",
-		"to", to,
-		"subject", subject,
-	)
-	return nil
+	return deliveries, nil
 }
 
 // buildSubject creates the email subject line from event data.
@@ -144,6 +184,10 @@ func (e *EmailMessenger) buildSubject(evt FlowExecutionEvent) string {
 		status = "[Cancelled]"
 	case "pending_approval":
 		status = "[Waiting]"
+	case "skipped":
+		status = "[Skipped]"
+	case "sla_breached":
+		status = "[SLA Breach]"
 	default:
 		status = "[Update]"
 	}
@@ -163,6 +207,10 @@ func (e *EmailMessenger) buildBody(evt FlowExecutionEvent) string {
 		statusMsg = "was cancelled"
 	case "pending_approval":
 		statusMsg = "is waiting for approval"
+	case "skipped":
+		statusMsg = "skipped a scheduled run"
+	case "sla_breached":
+		statusMsg = "breached its SLA"
 	default:
 		statusMsg = "status changed to " + evt.Status
 	}
@@ -175,6 +223,8 @@ func (e *EmailMessenger) buildBody(evt FlowExecutionEvent) string {
 		Namespace string
 		StatusMsg string
 		Error     string
+		Approval  *ApprovalDecision
+		Reason    string
 		RootURL   string
 	}{
 		FlowName:  evt.FlowName,
@@ -184,6 +234,8 @@ func (e *EmailMessenger) buildBody(evt FlowExecutionEvent) string {
 		StatusMsg: statusMsg,
 		Namespace: evt.Namespace,
 		Error:     evt.Error,
+		Approval:  evt.Approval,
+		Reason:    evt.Reason,
 		RootURL:   e.rootURL,
 	}
 
@@ -222,6 +274,28 @@ func (e *EmailMessenger) resolveReceivers(ctx context.Context, receivers []strin
 	return to
 }
 
+// addDelegates appends the users acting on behalf of any receiver under an active
+// approval delegation, so pending approvals reach whoever is covering.
+func (e *EmailMessenger) addDelegates(ctx context.Context, namespace string, to []string) []string {
+	resolver, ok := e.groupResolver.(DelegateResolver)
+	if !ok {
+		return to
+	}
+
+	delegates, err := resolver.ResolveDelegateEmails(ctx, namespace, to)
+	if err != nil {
+		e.logger.Error("failed to resolve approval delegates", "namespace", namespace, "error", err)
+		return to
+	}
+
+	for _, d := range delegates {
+		if !slices.Contains(to, d) {
+			to = append(to, d)
+		}
+	}
+	return to
+}
+
 // Close closes the SMTP connection pool
 func (e *EmailMessenger) Close() {
 	if e.pool != nil {
@@ -0,0 +1,223 @@
+package messengers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cvhariharan/flowctl/internal/config"
+	"github.com/invopop/jsonschema"
+)
+
+const defaultOpsgenieAPIURL = "https://api.opsgenie.com"
+
+// OpsgenieNotifyConfig defines the per-flow Opsgenie configuration rendered in the UI.
+type OpsgenieNotifyConfig struct {
+	APIKey   string `json:"api_key,omitempty" jsonschema:"title=API Key,description=Opsgenie integration API key. Overrides the namespace and server defaults"`
+	Priority string `json:"priority,omitempty" jsonschema:"title=Priority,enum=P1,enum=P2,enum=P3,enum=P4,enum=P5,default=P3"`
+}
+
+func GetOpsgenieNotifySchema() interface{} {
+	return jsonschema.Reflect(&OpsgenieNotifyConfig{})
+}
+
+type opsgenieAlert struct {
+	Message     string            `json:"message"`
+	Alias       string            `json:"alias"`
+	Description string            `json:"description,omitempty"`
+	Source      string            `json:"source"`
+	Entity      string            `json:"entity,omitempty"`
+	Priority    string            `json:"priority,omitempty"`
+	Tags        []string          `json:"tags,omitempty"`
+	Details     map[string]string `json:"details,omitempty"`
+}
+
+type opsgenieClose struct {
+	Source string `json:"source"`
+	Note   string `json:"note,omitempty"`
+}
+
+// OpsgenieMessenger opens Opsgenie alerts for failed flow executions and closes
+// them once the flow succeeds again, using the Alert API.
+type OpsgenieMessenger struct {
+	apiURL        string
+	apiKey        string
+	namespaceKeys map[string]string
+	client        *http.Client
+	logger        *slog.Logger
+	rootURL       string
+}
+
+// NewOpsgenieMessenger creates a new OpsgenieMessenger with the given configuration.
+func NewOpsgenieMessenger(cfg config.OpsgenieConfig, logger *slog.Logger, rootURL string) (*OpsgenieMessenger, error) {
+	if !cfg.Enabled {
+		return nil, fmt.Errorf("opsgenie messenger is disabled")
+	}
+
+	apiURL := cfg.APIURL
+	if apiURL == "" {
+		apiURL = defaultOpsgenieAPIURL
+	}
+
+	timeout := 30 * time.Second
+	if cfg.Timeout > 0 {
+		timeout = cfg.Timeout
+	}
+
+	return &OpsgenieMessenger{
+		apiURL:        strings.TrimSuffix(apiURL, "/"),
+		apiKey:        cfg.APIKey,
+		namespaceKeys: cfg.NamespaceAPIKeys,
+		client:        &http.Client{Timeout: timeout},
+		logger:        logger,
+		rootURL:       rootURL,
+	}, nil
+}
+
+// Send creates an alert when a flow execution errors and closes it when the flow completes.
+// SLA breaches create a P3 alert of their own. Other statuses are ignored. The API key is taken from msg.Config["api_key"], then the
+// namespace API keys and finally the server default.
+func (o *OpsgenieMessenger) Send(ctx context.Context, msg Message) error {
+	switch msg.Event {
+	case EventFlowExecution, EventFlowSLABreach:
+	case EventFlowSkipped:
+		return nil
+	case EventTest:
+		return o.sendTest(ctx, msg)
+	default:
+		return fmt.Errorf("opsgenie messenger: unsupported event type %q", msg.Event)
+	}
+
+	evt, ok := msg.Data.(FlowExecutionEvent)
+	if !ok {
+		return fmt.Errorf("opsgenie messenger: expected FlowExecutionEvent, got %T", msg.Data)
+	}
+
+	apiKey := resolveAlertKey(msg.Config, "api_key", evt.Namespace, o.namespaceKeys, o.apiKey)
+	if apiKey == "" {
+		return fmt.Errorf("opsgenie messenger: no api key configured for flow %s in namespace %s", evt.FlowID, evt.Namespace)
+	}
+
+	alias := alertKey(evt)
+
+	var (
+		endpoint string
+		body     any
+	)
+	switch evt.Status {
+	case "sla_breached":
+		description := evt.Reason
+		if o.rootURL != "" {
+			description = fmt.Sprintf("%s\n\n%s", evt.Reason, executionURL(o.rootURL, evt))
+		}
+
+		endpoint = o.apiURL + "/v2/alerts"
+		body = opsgenieAlert{
+			Message:     truncate(fmt.Sprintf("Flow %s breached its SLA in namespace %s", evt.FlowName, evt.Namespace), 130),
+			Alias:       slaAlertKey(evt),
+			Description: truncate(description, 15000),
+			Source:      "flowctl",
+			Entity:      evt.FlowID,
+			Priority:    "P3",
+			Tags:        []string{"flowctl", "sla", evt.Namespace},
+			Details:     alertDetails(evt),
+		}
+	case "errored":
+		priority, _ := msg.Config["priority"].(string)
+
+		description := evt.Error
+		if o.rootURL != "" {
+			description = fmt.Sprintf("%s\n\n%s", evt.Error, executionURL(o.rootURL, evt))
+		}
+
+		endpoint = o.apiURL + "/v2/alerts"
+		body = opsgenieAlert{
+			Message:     truncate(fmt.Sprintf("Flow %s failed in namespace %s", evt.FlowName, evt.Namespace), 130),
+			Alias:       alias,
+			Description: truncate(description, 15000),
+			Source:      "flowctl",
+			Entity:      evt.FlowID,
+			Priority:    priority,
+			Tags:        []string{"flowctl", evt.Namespace},
+			Details:     alertDetails(evt),
+		}
+	case "completed":
+		endpoint = fmt.Sprintf("%s/v2/alerts/%s/close?identifierType=alias", o.apiURL, url.PathEscape(alias))
+		body = opsgenieClose{
+			Source: "flowctl",
+			Note:   fmt.Sprintf("Resolved by successful execution %s", evt.ExecID),
+		}
+	default:
+		return nil
+	}
+
+	return o.post(ctx, endpoint, apiKey, body)
+}
+
+// sendTest creates a P5 alert and closes it right away.
+func (o *OpsgenieMessenger) sendTest(ctx context.Context, msg Message) error {
+	evt, ok := msg.Data.(TestEvent)
+	if !ok {
+		return fmt.Errorf("opsgenie messenger: expected TestEvent, got %T", msg.Data)
+	}
+
+	apiKey := resolveAlertKey(msg.Config, "api_key", "", o.namespaceKeys, o.apiKey)
+	if apiKey == "" {
+		return fmt.Errorf("opsgenie messenger: no api key configured")
+	}
+
+	alias := "flowctl/test"
+	err := o.post(ctx, o.apiURL+"/v2/alerts", apiKey, opsgenieAlert{
+		Message:  truncate(evt.Message, 130),
+		Alias:    alias,
+		Source:   "flowctl",
+		Priority: "P5",
+		Tags:     []string{"flowctl"},
+		Details:  map[string]string{"requested_by": evt.RequestedBy},
+	})
+	if err != nil {
+		return err
+	}
+
+	endpoint := fmt.Sprintf("%s/v2/alerts/%s/close?identifierType=alias", o.apiURL, url.PathEscape(alias))
+	return o.post(ctx, endpoint, apiKey, opsgenieClose{Source: "flowctl", Note: "Test notification"})
+}
+
+// post sends a request to the Alert API.
+func (o *OpsgenieMessenger) post(ctx context.Context, endpoint, apiKey string, body any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal opsgenie request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create opsgenie request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "GenieKey "+apiKey)
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		o.logger.Error("failed to send opsgenie request", "error", err)
+		return fmt.Errorf("failed to send opsgenie request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		o.logger.Error("opsgenie returned non-2xx status", "status", resp.StatusCode, "endpoint", endpoint)
+		return fmt.Errorf("opsgenie returned status %d", resp.StatusCode)
+	}
+
+	o.logger.Debug("opsgenie request sent", "endpoint", endpoint)
+	return nil
+}
+
+// Close is a no-op for the opsgenie messenger.
+func (o *OpsgenieMessenger) Close() {}
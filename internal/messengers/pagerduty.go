@@ -0,0 +1,224 @@
+package messengers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/cvhariharan/flowctl/internal/config"
+	"github.com/invopop/jsonschema"
+)
+
+const defaultPagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// PagerDutyNotifyConfig defines the per-flow PagerDuty configuration rendered in the UI.
+type PagerDutyNotifyConfig struct {
+	RoutingKey string `json:"routing_key,omitempty" jsonschema:"title=Routing Key,description=Events API v2 integration key. Overrides the namespace and server defaults"`
+	Severity   string `json:"severity,omitempty" jsonschema:"title=Severity,enum=critical,enum=error,enum=warning,enum=info,default=error"`
+}
+
+func GetPagerDutyNotifySchema() interface{} {
+	return jsonschema.Reflect(&PagerDutyNotifyConfig{})
+}
+
+type pagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"`
+	DedupKey    string            `json:"dedup_key"`
+	Payload     *pagerDutyPayload `json:"payload,omitempty"`
+	Links       []pagerDutyLink   `json:"links,omitempty"`
+}
+
+type pagerDutyPayload struct {
+	Summary       string            `json:"summary"`
+	Source        string            `json:"source"`
+	Severity      string            `json:"severity"`
+	Component     string            `json:"component,omitempty"`
+	Group         string            `json:"group,omitempty"`
+	CustomDetails map[string]string `json:"custom_details,omitempty"`
+}
+
+type pagerDutyLink struct {
+	Href string `json:"href"`
+	Text string `json:"text"`
+}
+
+// PagerDutyMessenger opens PagerDuty incidents for failed flow executions and resolves
+// them once the flow succeeds again, using the Events API v2.
+type PagerDutyMessenger struct {
+	eventsURL     string
+	routingKey    string
+	namespaceKeys map[string]string
+	client        *http.Client
+	logger        *slog.Logger
+	rootURL       string
+}
+
+// NewPagerDutyMessenger creates a new PagerDutyMessenger with the given configuration.
+func NewPagerDutyMessenger(cfg config.PagerDutyConfig, logger *slog.Logger, rootURL string) (*PagerDutyMessenger, error) {
+	if !cfg.Enabled {
+		return nil, fmt.Errorf("pagerduty messenger is disabled")
+	}
+
+	eventsURL := cfg.EventsURL
+	if eventsURL == "" {
+		eventsURL = defaultPagerDutyEventsURL
+	}
+
+	timeout := 30 * time.Second
+	if cfg.Timeout > 0 {
+		timeout = cfg.Timeout
+	}
+
+	return &PagerDutyMessenger{
+		eventsURL:     eventsURL,
+		routingKey:    cfg.RoutingKey,
+		namespaceKeys: cfg.NamespaceRoutingKeys,
+		client:        &http.Client{Timeout: timeout},
+		logger:        logger,
+		rootURL:       rootURL,
+	}, nil
+}
+
+// Send triggers an incident when a flow execution errors and resolves it when the flow completes.
+// SLA breaches trigger a warning incident of their own. Other statuses are ignored. The routing key is taken from msg.Config["routing_key"], then the
+// namespace routing keys and finally the server default.
+func (p *PagerDutyMessenger) Send(ctx context.Context, msg Message) error {
+	switch msg.Event {
+	case EventFlowExecution, EventFlowSLABreach:
+	case EventFlowSkipped:
+		return nil
+	case EventTest:
+		return p.sendTest(ctx, msg)
+	default:
+		return fmt.Errorf("pagerduty messenger: unsupported event type %q", msg.Event)
+	}
+
+	evt, ok := msg.Data.(FlowExecutionEvent)
+	if !ok {
+		return fmt.Errorf("pagerduty messenger: expected FlowExecutionEvent, got %T", msg.Data)
+	}
+
+	routingKey := resolveAlertKey(msg.Config, "routing_key", evt.Namespace, p.namespaceKeys, p.routingKey)
+	if routingKey == "" {
+		return fmt.Errorf("pagerduty messenger: no routing key configured for flow %s in namespace %s", evt.FlowID, evt.Namespace)
+	}
+
+	event := pagerDutyEvent{
+		RoutingKey: routingKey,
+		DedupKey:   alertKey(evt),
+	}
+
+	switch evt.Status {
+	case "sla_breached":
+		event.EventAction = "trigger"
+		event.DedupKey = slaAlertKey(evt)
+		event.Payload = &pagerDutyPayload{
+			Summary:       truncate(fmt.Sprintf("Flow %s breached its SLA in namespace %s: %s", evt.FlowName, evt.Namespace, evt.Reason), 1024),
+			Source:        "flowctl",
+			Severity:      "warning",
+			Component:     evt.FlowID,
+			Group:         evt.Namespace,
+			CustomDetails: alertDetails(evt),
+		}
+		if p.rootURL != "" {
+			event.Links = []pagerDutyLink{{Href: executionURL(p.rootURL, evt), Text: "View execution"}}
+		}
+	case "errored":
+		severity, _ := msg.Config["severity"].(string)
+		if severity == "" {
+			severity = "error"
+		}
+
+		event.EventAction = "trigger"
+		event.Payload = &pagerDutyPayload{
+			Summary:       truncate(fmt.Sprintf("Flow %s failed in namespace %s: %s", evt.FlowName, evt.Namespace, evt.Error), 1024),
+			Source:        "flowctl",
+			Severity:      severity,
+			Component:     evt.FlowID,
+			Group:         evt.Namespace,
+			CustomDetails: alertDetails(evt),
+		}
+		if p.rootURL != "" {
+			event.Links = []pagerDutyLink{{Href: executionURL(p.rootURL, evt), Text: "View execution"}}
+		}
+	case "completed":
+		event.EventAction = "resolve"
+	default:
+		return nil
+	}
+
+	return p.post(ctx, event)
+}
+
+// sendTest triggers an info incident and resolves it right away.
+func (p *PagerDutyMessenger) sendTest(ctx context.Context, msg Message) error {
+	evt, ok := msg.Data.(TestEvent)
+	if !ok {
+		return fmt.Errorf("pagerduty messenger: expected TestEvent, got %T", msg.Data)
+	}
+
+	routingKey := resolveAlertKey(msg.Config, "routing_key", "", p.namespaceKeys, p.routingKey)
+	if routingKey == "" {
+		return fmt.Errorf("pagerduty messenger: no routing key configured")
+	}
+
+	event := pagerDutyEvent{
+		RoutingKey:  routingKey,
+		EventAction: "trigger",
+		DedupKey:    "flowctl/test",
+		Payload: &pagerDutyPayload{
+			Summary:  truncate(evt.Message, 1024),
+			Source:   "flowctl",
+			Severity: "info",
+			CustomDetails: map[string]string{
+				"requested_by": evt.RequestedBy,
+			},
+		},
+	}
+	if err := p.post(ctx, event); err != nil {
+		return err
+	}
+
+	return p.post(ctx, pagerDutyEvent{
+		RoutingKey:  routingKey,
+		EventAction: "resolve",
+		DedupKey:    event.DedupKey,
+	})
+}
+
+// post sends an event to the Events API.
+func (p *PagerDutyMessenger) post(ctx context.Context, event pagerDutyEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal pagerduty event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.eventsURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create pagerduty request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		p.logger.Error("failed to send pagerduty event", "error", err)
+		return fmt.Errorf("failed to send pagerduty event: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		p.logger.Error("pagerduty returned non-2xx status", "status", resp.StatusCode, "dedup_key", event.DedupKey)
+		return fmt.Errorf("pagerduty returned status %d", resp.StatusCode)
+	}
+
+	p.logger.Debug("pagerduty event sent", "action", event.EventAction, "dedup_key", event.DedupKey)
+	return nil
+}
+
+// Close is a no-op for the pagerduty messenger.
+func (p *PagerDutyMessenger) Close() {}
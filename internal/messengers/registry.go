@@ -1,8 +1,14 @@
 package messengers
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
+	"maps"
+	"sort"
 	"sync"
+
+	"github.com/cvhariharan/flowctl/internal/config"
 )
 
 var (
@@ -21,6 +27,20 @@ func RegisterSchema(name string, schema any) {
 	schemaRegistry[name] = schema
 }
 
+// setSchema registers or replaces a messenger's config schema.
+func setSchema(name string, schema any) {
+	smu.Lock()
+	defer smu.Unlock()
+	schemaRegistry[name] = schema
+}
+
+// removeSchema removes a messenger's config schema.
+func removeSchema(name string) {
+	smu.Lock()
+	defer smu.Unlock()
+	delete(schemaRegistry, name)
+}
+
 // GetAllSchemas returns a map of all registered messenger names to their config schemas.
 func GetAllSchemas() map[string]any {
 	smu.RLock()
@@ -32,3 +52,230 @@ func GetAllSchemas() map[string]any {
 	}
 	return result
 }
+
+// Channels lists the notification channels supported by flowctl.
+var Channels = []string{"email", "webhook", "pagerduty", "opsgenie", "slack"}
+
+// RegistryOptions holds the dependencies shared by all messengers.
+type RegistryOptions struct {
+	GroupResolver GroupResolver
+	Logger        *slog.Logger
+	RootURL       string
+}
+
+// ChannelStatus describes the state of a channel in the registry.
+type ChannelStatus struct {
+	Channel string
+	Enabled bool
+	// Overridden is true when the channel is configured at runtime instead of the config file.
+	Overridden bool
+}
+
+// Registry holds the active messengers keyed by channel name. Channels start out configured
+// from the config file and can be reconfigured at runtime without a restart.
+type Registry struct {
+	mu         sync.RWMutex
+	base       config.MessengersConfig
+	opts       RegistryOptions
+	messengers map[string]Messenger
+	overrides  map[string]bool
+}
+
+// NewRegistry creates a registry with every channel enabled in the config file.
+// Channels that fail to initialize are logged and left disabled.
+func NewRegistry(cfg config.MessengersConfig, opts RegistryOptions) *Registry {
+	r := &Registry{
+		base:       cfg,
+		opts:       opts,
+		messengers: make(map[string]Messenger),
+		overrides:  make(map[string]bool),
+	}
+
+	for _, channel := range Channels {
+		if err := r.apply(channel, cfg); err != nil {
+			r.opts.Logger.Error("failed to create messenger", "channel", channel, "error", err)
+		}
+	}
+
+	return r
+}
+
+// Get returns the messenger for a channel.
+func (r *Registry) Get(channel string) (Messenger, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	m, ok := r.messengers[channel]
+	return m, ok
+}
+
+// Len returns the number of enabled messengers.
+func (r *Registry) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.messengers)
+}
+
+// Status returns the state of every supported channel, sorted by name.
+func (r *Registry) Status() []ChannelStatus {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	status := make([]ChannelStatus, 0, len(Channels))
+	for _, channel := range Channels {
+		_, enabled := r.messengers[channel]
+		status = append(status, ChannelStatus{
+			Channel:    channel,
+			Enabled:    enabled,
+			Overridden: r.overrides[channel],
+		})
+	}
+	sort.Slice(status, func(i, j int) bool { return status[i].Channel < status[j].Channel })
+	return status
+}
+
+// Configure replaces the config file settings of a channel with raw, which uses the same keys
+// as the channel's config file section. The previous messenger is closed once the new one is ready.
+func (r *Registry) Configure(channel string, enabled bool, raw map[string]any) error {
+	cfg, err := r.baseConfig().WithOverride(channel, enabled, raw)
+	if err != nil {
+		return err
+	}
+
+	if err := r.apply(channel, cfg); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	r.overrides[channel] = true
+	r.mu.Unlock()
+	return nil
+}
+
+// Validate checks that raw is a valid configuration for the channel without applying it.
+func (r *Registry) Validate(channel string, enabled bool, raw map[string]any) error {
+	_, err := r.baseConfig().WithOverride(channel, enabled, raw)
+	return err
+}
+
+// Reset drops the runtime configuration of a channel and restores the config file settings.
+func (r *Registry) Reset(channel string) error {
+	if err := r.apply(channel, r.baseConfig()); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	delete(r.overrides, channel)
+	r.mu.Unlock()
+	return nil
+}
+
+// Reload replaces the config file settings with cfg, e.g. after the config file changed.
+// Channels whose settings changed are recreated unless they are configured at runtime, which
+// keeps precedence over the file. Every channel is attempted, the errors are joined.
+func (r *Registry) Reload(cfg config.MessengersConfig) error {
+	r.mu.Lock()
+	old := r.base
+	r.base = cfg
+	overrides := maps.Clone(r.overrides)
+	r.mu.Unlock()
+
+	var errs []error
+	for _, channel := range Channels {
+		if overrides[channel] || old.ChannelEqual(cfg, channel) {
+			continue
+		}
+		if err := r.apply(channel, cfg); err != nil {
+			errs = append(errs, fmt.Errorf("could not reload %s messenger: %w", channel, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// baseConfig returns the config file settings of the channels
+func (r *Registry) baseConfig() config.MessengersConfig {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.base
+}
+
+// Close closes all messengers.
+func (r *Registry) Close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, m := range r.messengers {
+		m.Close()
+	}
+	r.messengers = make(map[string]Messenger)
+}
+
+// apply creates the messenger for channel from cfg and swaps it in, or removes the channel
+// if it is disabled in cfg.
+func (r *Registry) apply(channel string, cfg config.MessengersConfig) error {
+	m, schema, err := r.build(channel, cfg)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	old := r.messengers[channel]
+	if m != nil {
+		r.messengers[channel] = m
+		setSchema(channel, schema)
+	} else {
+		delete(r.messengers, channel)
+		removeSchema(channel)
+	}
+	r.mu.Unlock()
+
+	if old != nil {
+		old.Close()
+	}
+
+	if m != nil {
+		r.opts.Logger.Info("messenger initialized", "channel", channel)
+	}
+	return nil
+}
+
+// build creates the messenger for a channel. A nil messenger is returned if the channel is disabled.
+func (r *Registry) build(channel string, cfg config.MessengersConfig) (Messenger, any, error) {
+	logger := r.opts.Logger.WithGroup(channel + "_messenger")
+
+	switch channel {
+	case "email":
+		if !cfg.Email.Enabled {
+			return nil, nil, nil
+		}
+		m, err := NewEmailMessenger(cfg.Email, r.opts.GroupResolver, logger, r.opts.RootURL)
+		return m, GetEmailNotifySchema(), err
+	case "webhook":
+		if !cfg.Webhook.Enabled {
+			return nil, nil, nil
+		}
+		m, err := NewWebhookMessenger(cfg.Webhook, logger)
+		return m, GetWebhookNotifySchema(), err
+	case "pagerduty":
+		if !cfg.PagerDuty.Enabled {
+			return nil, nil, nil
+		}
+		m, err := NewPagerDutyMessenger(cfg.PagerDuty, logger, r.opts.RootURL)
+		return m, GetPagerDutyNotifySchema(), err
+	case "opsgenie":
+		if !cfg.Opsgenie.Enabled {
+			return nil, nil, nil
+		}
+		m, err := NewOpsgenieMessenger(cfg.Opsgenie, logger, r.opts.RootURL)
+		return m, GetOpsgenieNotifySchema(), err
+	case "slack":
+		if !cfg.Slack.Enabled {
+			return nil, nil, nil
+		}
+		m, err := NewSlackMessenger(cfg.Slack, logger, r.opts.RootURL)
+		return m, GetSlackNotifySchema(), err
+	}
+
+	return nil, nil, fmt.Errorf("unknown messenger %q", channel)
+}
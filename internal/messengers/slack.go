@@ -0,0 +1,268 @@
+package messengers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/cvhariharan/flowctl/internal/config"
+	"github.com/invopop/jsonschema"
+)
+
+const defaultSlackAPIURL = "https://slack.com/api"
+
+// defaultSlackTemplate renders the message text when no template is configured. Fields are
+// escaped for Slack's mrkdwn format before the template is executed.
+const defaultSlackTemplate = `{{ .Emoji }} *[{{ .Label }}]* Flow *{{ .FlowName }}* {{ .StatusMsg }} in namespace ` + "`{{ .Namespace }}`" + `
+{{- if .Reason }}
+*Reason:* {{ .Reason }}{{ end }}
+{{- with .Approval }}
+*Approval:* {{ .ActionID }} {{ .Status }}{{ if .DecidedBy }} by {{ .DecidedBy }}{{ end }}{{ if .Comment }}: {{ .Comment }}{{ end }}{{ end }}
+{{- if .Error }}
+` + "```{{ .Error }}```" + `{{ end }}
+{{- if .URL }}
+<{{ .URL }}|View execution {{ .ShortExecID }}>{{ end }}`
+
+// SlackNotifyConfig defines the per-flow Slack configuration rendered in the UI.
+type SlackNotifyConfig struct {
+	Channel string `json:"channel,omitempty" jsonschema:"title=Channel,description=Channel to post to with the bot token. Overrides the namespace and server defaults"`
+}
+
+func GetSlackNotifySchema() interface{} {
+	return jsonschema.Reflect(&SlackNotifyConfig{})
+}
+
+type slackMessage struct {
+	Channel     string `json:"channel,omitempty"`
+	Text        string `json:"text"`
+	UnfurlLinks bool   `json:"unfurl_links"`
+}
+
+type slackResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error"`
+}
+
+// slackMessageData is the data the message template is executed with.
+type slackMessageData struct {
+	FlowName    string
+	FlowID      string
+	ExecID      string
+	ShortExecID string
+	Status      string
+	Namespace   string
+	Emoji       string
+	Label       string
+	StatusMsg   string
+	Error       string
+	Reason      string
+	Approval    *ApprovalDecision
+	URL         string
+}
+
+// SlackMessenger posts flow execution updates to Slack, either to an incoming webhook or
+// with a bot token through chat.postMessage.
+type SlackMessenger struct {
+	webhookURL        string
+	botToken          string
+	defaultChannel    string
+	namespaceChannels map[string]string
+	apiURL            string
+	template          *template.Template
+	client            *http.Client
+	logger            *slog.Logger
+	rootURL           string
+}
+
+// NewSlackMessenger creates a new SlackMessenger with the given configuration. Either a webhook
+// URL or a bot token must be set.
+func NewSlackMessenger(cfg config.SlackConfig, logger *slog.Logger, rootURL string) (*SlackMessenger, error) {
+	if !cfg.Enabled {
+		return nil, fmt.Errorf("slack messenger is disabled")
+	}
+	if cfg.WebhookURL == "" && cfg.BotToken == "" {
+		return nil, fmt.Errorf("slack messenger needs a webhook_url or a bot_token")
+	}
+
+	text := cfg.Template
+	if text == "" {
+		text = defaultSlackTemplate
+	}
+	tmpl, err := template.New("slack").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse slack template: %w", err)
+	}
+
+	apiURL := cfg.APIURL
+	if apiURL == "" {
+		apiURL = defaultSlackAPIURL
+	}
+
+	timeout := 30 * time.Second
+	if cfg.Timeout > 0 {
+		timeout = cfg.Timeout
+	}
+
+	return &SlackMessenger{
+		webhookURL:        cfg.WebhookURL,
+		botToken:          cfg.BotToken,
+		defaultChannel:    cfg.DefaultChannel,
+		namespaceChannels: cfg.NamespaceChannels,
+		apiURL:            strings.TrimSuffix(apiURL, "/"),
+		template:          tmpl,
+		client:            &http.Client{Timeout: timeout},
+		logger:            logger,
+		rootURL:           rootURL,
+	}, nil
+}
+
+// Send posts a message for a flow execution update. With a bot token the channel is taken from
+// msg.Config["channel"], then the namespace channels and finally the server default. Without
+// one the message is posted to the incoming webhook.
+func (s *SlackMessenger) Send(ctx context.Context, msg Message) error {
+	var text, namespace string
+	switch msg.Event {
+	case EventFlowExecution, EventFlowSkipped, EventFlowSLABreach:
+		evt, ok := msg.Data.(FlowExecutionEvent)
+		if !ok {
+			return fmt.Errorf("slack messenger: expected FlowExecutionEvent, got %T", msg.Data)
+		}
+
+		var err error
+		text, err = s.buildText(evt)
+		if err != nil {
+			return err
+		}
+		namespace = evt.Namespace
+	case EventTest:
+		evt, ok := msg.Data.(TestEvent)
+		if !ok {
+			return fmt.Errorf("slack messenger: expected TestEvent, got %T", msg.Data)
+		}
+		text = fmt.Sprintf(":test_tube: %s\nRequested by %s.", slackEscape(evt.Message), slackEscape(evt.RequestedBy))
+	default:
+		return fmt.Errorf("slack messenger: unsupported event type %q", msg.Event)
+	}
+
+	if s.botToken == "" {
+		return s.post(ctx, s.webhookURL, slackMessage{Text: text})
+	}
+
+	channel := resolveAlertKey(msg.Config, "channel", namespace, s.namespaceChannels, s.defaultChannel)
+	if channel == "" {
+		return fmt.Errorf("slack messenger: no channel configured for namespace %s", namespace)
+	}
+	return s.post(ctx, s.apiURL+"/chat.postMessage", slackMessage{Channel: channel, Text: text})
+}
+
+// buildText renders the message template for an execution update.
+func (s *SlackMessenger) buildText(evt FlowExecutionEvent) (string, error) {
+	data := slackMessageData{
+		FlowName:  slackEscape(evt.FlowName),
+		FlowID:    slackEscape(evt.FlowID),
+		ExecID:    evt.ExecID,
+		Status:    evt.Status,
+		Namespace: slackEscape(evt.Namespace),
+		Error:     slackEscape(truncate(evt.Error, 2000)),
+		Reason:    slackEscape(evt.Reason),
+	}
+	data.ShortExecID = truncate(evt.ExecID, 8)
+	data.Emoji, data.Label, data.StatusMsg = slackStatus(evt.Status)
+	if evt.Approval != nil {
+		data.Approval = &ApprovalDecision{
+			ActionID:  slackEscape(evt.Approval.ActionID),
+			Status:    slackEscape(evt.Approval.Status),
+			DecidedBy: slackEscape(evt.Approval.DecidedBy),
+			Comment:   slackEscape(evt.Approval.Comment),
+		}
+	}
+	if s.rootURL != "" && evt.ExecID != "" && evt.Status != "skipped" {
+		data.URL = executionURL(s.rootURL, evt)
+	}
+
+	var buf bytes.Buffer
+	if err := s.template.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to execute slack template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// post sends a message to url. Slack's Web API answers with 200 and reports failures in the
+// body, so the response of chat.postMessage is checked as well.
+func (s *SlackMessenger) post(ctx context.Context, url string, message slackMessage) error {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal slack message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create slack request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	if s.botToken != "" {
+		req.Header.Set("Authorization", "Bearer "+s.botToken)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		s.logger.Error("failed to send slack message", "error", err)
+		return fmt.Errorf("failed to send slack message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		s.logger.Error("slack returned non-2xx status", "status", resp.StatusCode, "channel", message.Channel)
+		return fmt.Errorf("slack returned status %d", resp.StatusCode)
+	}
+
+	if s.botToken != "" {
+		var r slackResponse
+		if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+			return fmt.Errorf("failed to decode slack response: %w", err)
+		}
+		if !r.OK {
+			s.logger.Error("slack rejected message", "error", r.Error, "channel", message.Channel)
+			return fmt.Errorf("slack rejected message: %s", r.Error)
+		}
+	}
+
+	s.logger.Debug("slack message sent", "channel", message.Channel)
+	return nil
+}
+
+// Close is a no-op for the slack messenger.
+func (s *SlackMessenger) Close() {}
+
+// slackStatus returns the emoji, label and description of an execution status.
+func slackStatus(status string) (string, string, string) {
+	switch status {
+	case "completed":
+		return ":white_check_mark:", "Success", "has completed successfully"
+	case "errored":
+		return ":x:", "Failed", "has failed with an error"
+	case "cancelled":
+		return ":no_entry_sign:", "Cancelled", "was cancelled"
+	case "pending_approval":
+		return ":hourglass_flowing_sand:", "Waiting", "is waiting for approval"
+	case "skipped":
+		return ":fast_forward:", "Skipped", "skipped a scheduled run"
+	case "sla_breached":
+		return ":warning:", "SLA Breach", "breached its SLA"
+	default:
+		return ":information_source:", "Update", "status changed to " + status
+	}
+}
+
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// slackEscape escapes the characters Slack treats as control characters in message text.
+func slackEscape(s string) string {
+	return slackEscaper.Replace(s)
+}
@@ -0,0 +1,112 @@
+package messengers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cvhariharan/flowctl/internal/config"
+)
+
+func TestSlackMessenger_Send(t *testing.T) {
+	var got slackMessage
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("could not decode message: %v", err)
+		}
+		if r.URL.Path == "/api/chat.postMessage" {
+			io.WriteString(w, `{"ok": true}`)
+		}
+	}))
+	defer srv.Close()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	evt := FlowExecutionEvent{
+		FlowID:    "deploy",
+		FlowName:  "Deploy <prod>",
+		ExecID:    "0123456789abcdef",
+		Status:    "errored",
+		Error:     "exit status 1",
+		Namespace: "prod",
+	}
+
+	t.Run("webhook", func(t *testing.T) {
+		m, err := NewSlackMessenger(config.SlackConfig{Enabled: true, WebhookURL: srv.URL + "/hook"}, logger, "https://flowctl.example.com")
+		if err != nil {
+			t.Fatalf("NewSlackMessenger() error = %v", err)
+		}
+		if err := m.Send(context.Background(), Message{Event: EventFlowExecution, Data: evt}); err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+
+		if auth != "" || got.Channel != "" {
+			t.Errorf("webhook message sent with channel %q and authorization %q", got.Channel, auth)
+		}
+		for _, want := range []string{"[Failed]", "Deploy &lt;prod&gt;", "exit status 1", "<https://flowctl.example.com/view/prod/results/deploy/0123456789abcdef|View execution 01234567>"} {
+			if !strings.Contains(got.Text, want) {
+				t.Errorf("message %q does not contain %q", got.Text, want)
+			}
+		}
+	})
+
+	t.Run("bot token", func(t *testing.T) {
+		m, err := NewSlackMessenger(config.SlackConfig{
+			Enabled:           true,
+			BotToken:          "xoxb-token",
+			DefaultChannel:    "#default",
+			NamespaceChannels: map[string]string{"prod": "#prod"},
+			APIURL:            srv.URL + "/api",
+		}, logger, "")
+		if err != nil {
+			t.Fatalf("NewSlackMessenger() error = %v", err)
+		}
+
+		if err := m.Send(context.Background(), Message{Event: EventFlowExecution, Data: evt}); err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+		if auth != "Bearer xoxb-token" || got.Channel != "#prod" {
+			t.Errorf("message sent with channel %q and authorization %q", got.Channel, auth)
+		}
+		if strings.Contains(got.Text, "View execution") {
+			t.Errorf("message %q links to the execution without a root URL", got.Text)
+		}
+
+		msg := Message{Event: EventFlowExecution, Data: evt, Config: map[string]any{"channel": "#flow"}}
+		if err := m.Send(context.Background(), msg); err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+		if got.Channel != "#flow" {
+			t.Errorf("message sent to %q, want the channel of the flow", got.Channel)
+		}
+	})
+
+	t.Run("template", func(t *testing.T) {
+		m, err := NewSlackMessenger(config.SlackConfig{Enabled: true, WebhookURL: srv.URL, Template: "{{ .Label }}: {{ .FlowID }}"}, logger, "")
+		if err != nil {
+			t.Fatalf("NewSlackMessenger() error = %v", err)
+		}
+		if err := m.Send(context.Background(), Message{Event: EventFlowExecution, Data: evt}); err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+		if got.Text != "Failed: deploy" {
+			t.Errorf("message = %q, want %q", got.Text, "Failed: deploy")
+		}
+	})
+}
+
+func TestNewSlackMessenger_Invalid(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	if _, err := NewSlackMessenger(config.SlackConfig{Enabled: true}, logger, ""); err == nil {
+		t.Error("NewSlackMessenger() without a webhook URL or bot token succeeded")
+	}
+	if _, err := NewSlackMessenger(config.SlackConfig{Enabled: true, WebhookURL: "https://hooks.slack.com/x", Template: "{{ .Label"}, logger, ""); err == nil {
+		t.Error("NewSlackMessenger() with an invalid template succeeded")
+	}
+}
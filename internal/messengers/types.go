@@ -10,17 +10,40 @@ type EventType string
 
 const (
 	EventFlowExecution EventType = "flow.execution"
+	// EventFlowSkipped is sent when a scheduled run was not started, for example
+	// because a previous execution was still running and overlap is disabled.
+	EventFlowSkipped EventType = "flow.skipped"
+	// EventFlowSLABreach is sent when an execution runs longer than the SLA of its flow allows.
+	EventFlowSLABreach EventType = "flow.sla_breach"
+	// EventTest is sent from the messenger test API to check a channel's configuration.
+	EventTest EventType = "messenger.test"
 )
 
+// TestEvent carries the data of a test notification.
+type TestEvent struct {
+	Message     string `json:"message"`
+	RequestedBy string `json:"requested_by"`
+}
+
 // FlowExecutionEvent carries structured data about a flow execution state change.
 type FlowExecutionEvent struct {
-	FlowID    string `json:"flow_id"`
-	FlowName  string `json:"flow_name"`
-	ExecID    string `json:"exec_id"`
+	FlowID    string            `json:"flow_id"`
+	FlowName  string            `json:"flow_name"`
+	ExecID    string            `json:"exec_id"`
+	Status    string            `json:"status"`
+	Error     string            `json:"error"`
+	Namespace string            `json:"namespace"`
+	Approval  *ApprovalDecision `json:"approval,omitempty"`
+	Reason    string            `json:"reason,omitempty"`
+	RootURL   string            `json:"-"`
+}
+
+// ApprovalDecision carries the reviewer's decision on an approval gated action.
+type ApprovalDecision struct {
+	ActionID  string `json:"action_id"`
 	Status    string `json:"status"`
-	Error     string `json:"error"`
-	Namespace string `json:"namespace"`
-	RootURL   string `json:"-"`
+	DecidedBy string `json:"decided_by"`
+	Comment   string `json:"comment"`
 }
 
 // Message is the generic struct passed to messengers.
@@ -28,6 +51,22 @@ type Message struct {
 	Event  EventType
 	Data   any
 	Config map[string]any
+
+	// SkipReceivers lists receivers that already got this message in an earlier attempt.
+	SkipReceivers []string
+}
+
+// Delivery is the outcome of sending a message to a single receiver.
+type Delivery struct {
+	Receiver string
+	Err      error
+}
+
+// DeliveryReporter is implemented by messengers that send a message to several receivers
+// and can report the outcome for each of them. The error is only set if the message could
+// not be sent to any receiver, for example because it is malformed.
+type DeliveryReporter interface {
+	SendWithReport(ctx context.Context, message Message) ([]Delivery, error)
 }
 
 type Messenger interface {
@@ -40,6 +79,13 @@ type GroupResolver interface {
 	ResolveGroupEmails(ctx context.Context, groupName string) ([]string, error)
 }
 
+// DelegateResolver resolves the email addresses of users who currently hold delegated
+// approval rights for any of the given users in a namespace.
+// A GroupResolver may optionally implement it.
+type DelegateResolver interface {
+	ResolveDelegateEmails(ctx context.Context, namespace string, emails []string) ([]string, error)
+}
+
 // configStringSlice extracts a []string value from a config map.
 func configStringSlice(cfg map[string]any, key string) []string {
 	v, ok := cfg[key]
@@ -1,15 +1,30 @@
 package metrics
 
 import (
+	"context"
+	"database/sql"
+	"log/slog"
 	"net/http"
+	"os"
 	"strconv"
 	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/client_golang/prometheus/push"
 )
 
+// PushConfig configures pushing metrics to a Prometheus Pushgateway
+type PushConfig struct {
+	URL      string
+	Job      string
+	Interval time.Duration
+	Username string
+	Password string
+}
+
 type Manager struct {
 	executionsCount      *prometheus.CounterVec
 	executionsRunning    *prometheus.GaugeVec
@@ -18,6 +33,21 @@ type Manager struct {
 	httpRequestsTotal    *prometheus.CounterVec
 	httpRequestDuration  *prometheus.HistogramVec
 	httpRequestsInFlight *prometheus.GaugeVec
+	queueDepth           *prometheus.GaugeVec
+	jobWaitTime          *prometheus.HistogramVec
+	actionDuration       *prometheus.HistogramVec
+	nodeConnectFailures  *prometheus.CounterVec
+	approvalWaitTime     *prometheus.HistogramVec
+	logBytesWritten      *prometheus.CounterVec
+	sseClients           *prometheus.GaugeVec
+	slaBreaches          *prometheus.CounterVec
+	scheduleLag          *prometheus.HistogramVec
+	artifactsCollected   *prometheus.CounterVec
+	artifactsReclaimed   *prometheus.CounterVec
+	executionDuration    *prometheus.HistogramVec
+	workers              *prometheus.GaugeVec
+	workersBusy          *prometheus.GaugeVec
+	dbQueryDuration      *prometheus.HistogramVec
 }
 
 func NewManager() *Manager {
@@ -72,6 +102,117 @@ func NewManager() *Manager {
 		},
 			[]string{"method", "path"},
 		),
+		queueDepth: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "flowctl",
+			Name:      "queue_depth",
+			Help:      "Number of queued jobs that are ready to run",
+		},
+			[]string{"payload_type"},
+		),
+		jobWaitTime: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "flowctl",
+			Name:      "job_wait_seconds",
+			Help:      "Time executions spent in the queue before a worker picked them up",
+			Buckets:   prometheus.ExponentialBuckets(0.5, 2, 14),
+		},
+			[]string{"namespace", "flow_id"},
+		),
+		actionDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "flowctl",
+			Name:      "action_duration_seconds",
+			Help:      "Time taken to run an action on all its nodes",
+			Buckets:   prometheus.ExponentialBuckets(0.5, 2, 16),
+		},
+			[]string{"namespace", "flow_id", "action_id", "state"},
+		),
+		nodeConnectFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "flowctl",
+			Name:      "node_connectivity_failures_total",
+			Help:      "Number of times a node could not be reached to run an action",
+		},
+			[]string{"namespace", "flow_id", "node"},
+		),
+		approvalWaitTime: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "flowctl",
+			Name:      "approval_wait_seconds",
+			Help:      "Time between an approval being requested and decided",
+			Buckets:   []float64{60, 300, 900, 1800, 3600, 4 * 3600, 12 * 3600, 24 * 3600, 3 * 24 * 3600, 7 * 24 * 3600},
+		},
+			[]string{"namespace", "flow_id", "state"},
+		),
+		logBytesWritten: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "flowctl",
+			Name:      "log_bytes_written_total",
+			Help:      "Bytes of execution output written to the logs",
+		},
+			[]string{"namespace", "flow_id"},
+		),
+		sseClients: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "flowctl",
+			Name:      "sse_clients",
+			Help:      "Number of clients streaming execution logs",
+		},
+			[]string{"namespace", "flow_id"},
+		),
+		slaBreaches: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "flowctl",
+			Name:      "sla_breaches_total",
+			Help:      "Number of executions that breached the SLA of their flow",
+		},
+			[]string{"namespace", "flow_id", "kind"},
+		),
+		scheduleLag: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "flowctl",
+			Name:      "schedule_lag_seconds",
+			Help:      "Time between a scheduled execution being due and it starting",
+			Buckets:   prometheus.ExponentialBuckets(0.5, 2, 14),
+		},
+			[]string{"namespace", "flow_id", "trigger"},
+		),
+		artifactsCollected: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "flowctl",
+			Name:      "artifacts_collected_total",
+			Help:      "Number of executions whose leftover artifacts were removed",
+		},
+			[]string{"reason"},
+		),
+		artifactsReclaimed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "flowctl",
+			Name:      "artifacts_reclaimed_bytes_total",
+			Help:      "Bytes of leftover artifacts removed",
+		},
+			[]string{"reason"},
+		),
+		executionDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "flowctl",
+			Name:      "execution_duration_seconds",
+			Help:      "Time between an execution starting and finishing, including the time it waited for approvals",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 18),
+		},
+			[]string{"namespace", "flow_id", "state"},
+		),
+		workers: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "flowctl",
+			Name:      "scheduler_workers",
+			Help:      "Number of jobs the scheduler runs at a time",
+		},
+			[]string{"payload_type"},
+		),
+		workersBusy: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "flowctl",
+			Name:      "scheduler_workers_busy",
+			Help:      "Number of jobs the scheduler is running",
+		},
+			[]string{"payload_type"},
+		),
+		dbQueryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "flowctl",
+			Name:      "db_query_duration_seconds",
+			Help:      "Time the database took to respond to a query",
+			Buckets:   []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+		},
+			[]string{"db_name", "query"},
+		),
 	}
 }
 
@@ -84,9 +225,29 @@ func (m *Manager) Register() {
 		m.httpRequestsTotal,
 		m.httpRequestDuration,
 		m.httpRequestsInFlight,
+		m.queueDepth,
+		m.jobWaitTime,
+		m.actionDuration,
+		m.nodeConnectFailures,
+		m.approvalWaitTime,
+		m.logBytesWritten,
+		m.sseClients,
+		m.slaBreaches,
+		m.scheduleLag,
+		m.artifactsCollected,
+		m.artifactsReclaimed,
+		m.executionDuration,
+		m.workers,
+		m.workersBusy,
+		m.dbQueryDuration,
 	)
 }
 
+// RegisterDBStats exports the connection pool stats of db, labeled with name as db_name
+func (m *Manager) RegisterDBStats(db *sql.DB, name string) {
+	prometheus.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
 func (m *Manager) GetHandler() http.Handler {
 	return promhttp.Handler()
 }
@@ -123,6 +284,123 @@ func (m *Manager) SetExecutionsPending(namespace, flowID string, value float64)
 	m.executionsPending.WithLabelValues(namespace, flowID).Set(value)
 }
 
+// Serve serves the metrics on a listener of their own until ctx is cancelled
+func (m *Manager) Serve(ctx context.Context, address string, path string) error {
+	mux := http.NewServeMux()
+	mux.Handle(path, m.GetHandler())
+
+	srv := &http.Server{Addr: address, Handler: mux}
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// RunPusher pushes all metrics to the Pushgateway every interval until ctx is cancelled.
+// Metrics are grouped by the hostname so that each flowctl instance replaces only its own.
+func (m *Manager) RunPusher(ctx context.Context, cfg PushConfig, logger *slog.Logger) {
+	instance, err := os.Hostname()
+	if err != nil {
+		instance = "flowctl"
+	}
+
+	pusher := push.New(cfg.URL, cfg.Job).
+		Gatherer(prometheus.DefaultGatherer).
+		Grouping("instance", instance)
+	if cfg.Username != "" {
+		pusher = pusher.BasicAuth(cfg.Username, cfg.Password)
+	}
+
+	if cfg.Interval <= 0 {
+		cfg.Interval = 30 * time.Second
+	}
+
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := pusher.PushContext(ctx); err != nil {
+				logger.Error("could not push metrics", "url", cfg.URL, "error", err)
+			}
+		}
+	}
+}
+
+func (m *Manager) SetQueueDepth(payloadType string, value float64) {
+	m.queueDepth.WithLabelValues(payloadType).Set(value)
+}
+
+func (m *Manager) ObserveJobWait(namespace, flowID string, wait time.Duration) {
+	m.jobWaitTime.WithLabelValues(namespace, flowID).Observe(wait.Seconds())
+}
+
+func (m *Manager) ObserveActionDuration(namespace, flowID, actionID, state string, duration time.Duration) {
+	m.actionDuration.WithLabelValues(namespace, flowID, actionID, state).Observe(duration.Seconds())
+}
+
+func (m *Manager) IncNodeConnectivityFailures(namespace, flowID, node string) {
+	m.nodeConnectFailures.WithLabelValues(namespace, flowID, node).Inc()
+}
+
+func (m *Manager) ObserveApprovalWait(namespace, flowID, state string, wait time.Duration) {
+	m.approvalWaitTime.WithLabelValues(namespace, flowID, state).Observe(wait.Seconds())
+}
+
+func (m *Manager) AddLogBytes(namespace, flowID string, n int) {
+	m.logBytesWritten.WithLabelValues(namespace, flowID).Add(float64(n))
+}
+
+func (m *Manager) IncSSEClients(namespace, flowID string) {
+	m.sseClients.WithLabelValues(namespace, flowID).Inc()
+}
+
+func (m *Manager) DecSSEClients(namespace, flowID string) {
+	m.sseClients.WithLabelValues(namespace, flowID).Dec()
+}
+
+func (m *Manager) IncSLABreaches(namespace, flowID, kind string) {
+	m.slaBreaches.WithLabelValues(namespace, flowID, kind).Inc()
+}
+
+func (m *Manager) ObserveScheduleLag(namespace, flowID, trigger string, lag time.Duration) {
+	m.scheduleLag.WithLabelValues(namespace, flowID, trigger).Observe(lag.Seconds())
+}
+
+func (m *Manager) AddArtifactsCollected(reason string, size int64) {
+	m.artifactsCollected.WithLabelValues(reason).Inc()
+	m.artifactsReclaimed.WithLabelValues(reason).Add(float64(size))
+}
+
+func (m *Manager) ObserveExecutionDuration(namespace, flowID, state string, duration time.Duration) {
+	m.executionDuration.WithLabelValues(namespace, flowID, state).Observe(duration.Seconds())
+}
+
+func (m *Manager) SetWorkers(payloadType string, value float64) {
+	m.workers.WithLabelValues(payloadType).Set(value)
+}
+
+func (m *Manager) IncWorkersBusy(payloadType string) {
+	m.workersBusy.WithLabelValues(payloadType).Inc()
+}
+
+func (m *Manager) DecWorkersBusy(payloadType string) {
+	m.workersBusy.WithLabelValues(payloadType).Dec()
+}
+
+// ObserveDBQuery records the duration of a query, it can be used as a repo.QueryObserver
+func (m *Manager) ObserveDBQuery(dbName, query string, duration time.Duration) {
+	m.dbQueryDuration.WithLabelValues(dbName, query).Observe(duration.Seconds())
+}
+
 func (m *Manager) HTTPMetricsMiddleware() echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
@@ -22,10 +22,10 @@ WITH inserted_approval AS (
         namespace_id
     ) VALUES (
         $1, $2, (SELECT id FROM namespaces where namespaces.uuid = $3)
-    ) RETURNING id, uuid, exec_log_id, action_id, status, decided_by, namespace_id, created_at, updated_at
+    ) RETURNING id, uuid, exec_log_id, action_id, status, decided_by, namespace_id, created_at, updated_at, comment
 )
 SELECT
-    a.id, a.uuid, a.exec_log_id, a.action_id, a.status, a.decided_by, a.namespace_id, a.created_at, a.updated_at,
+    a.id, a.uuid, a.exec_log_id, a.action_id, a.status, a.decided_by, a.namespace_id, a.created_at, a.updated_at, a.comment,
     u.name as requested_by
 FROM inserted_approval a
 JOIN execution_log el ON a.exec_log_id = el.id
@@ -48,6 +48,7 @@ type AddApprovalRequestRow struct {
 	NamespaceID int32          `db:"namespace_id" json:"namespace_id"`
 	CreatedAt   time.Time      `db:"created_at" json:"created_at"`
 	UpdatedAt   time.Time      `db:"updated_at" json:"updated_at"`
+	Comment     string         `db:"comment" json:"comment"`
 	RequestedBy string         `db:"requested_by" json:"requested_by"`
 }
 
@@ -64,6 +65,7 @@ func (q *Queries) AddApprovalRequest(ctx context.Context, arg AddApprovalRequest
 		&i.NamespaceID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Comment,
 		&i.RequestedBy,
 	)
 	return i, err
@@ -73,17 +75,17 @@ const approveRequestByUUID = `-- name: ApproveRequestByUUID :one
 WITH namespace_lookup AS (
     SELECT id FROM namespaces WHERE namespaces.uuid = $3
 ), updated AS (
-    UPDATE approvals SET status = 'approved', decided_by = $2, updated_at = NOW()
+    UPDATE approvals SET status = 'approved', decided_by = $2, comment = $4, updated_at = NOW()
     WHERE approvals.uuid = $1
     AND approvals.exec_log_id IN (
         SELECT el.id FROM execution_log el
         JOIN flows f ON el.flow_id = f.id
         WHERE f.namespace_id = (SELECT id FROM namespace_lookup) AND f.is_active = TRUE
     )
-    RETURNING id, uuid, exec_log_id, action_id, status, decided_by, namespace_id, created_at, updated_at
+    RETURNING id, uuid, exec_log_id, action_id, status, decided_by, namespace_id, created_at, updated_at, comment
 )
 SELECT
-    a.id, a.uuid, a.exec_log_id, a.action_id, a.status, a.decided_by, a.namespace_id, a.created_at, a.updated_at,
+    a.id, a.uuid, a.exec_log_id, a.action_id, a.status, a.decided_by, a.namespace_id, a.created_at, a.updated_at, a.comment,
     u.name as requested_by
 FROM updated a
 JOIN execution_log el ON a.exec_log_id = el.id
@@ -94,6 +96,7 @@ type ApproveRequestByUUIDParams struct {
 	Uuid      uuid.UUID     `db:"uuid" json:"uuid"`
 	DecidedBy sql.NullInt32 `db:"decided_by" json:"decided_by"`
 	Uuid_2    uuid.UUID     `db:"uuid_2" json:"uuid_2"`
+	Comment   string        `db:"comment" json:"comment"`
 }
 
 type ApproveRequestByUUIDRow struct {
@@ -106,11 +109,17 @@ type ApproveRequestByUUIDRow struct {
 	NamespaceID int32          `db:"namespace_id" json:"namespace_id"`
 	CreatedAt   time.Time      `db:"created_at" json:"created_at"`
 	UpdatedAt   time.Time      `db:"updated_at" json:"updated_at"`
+	Comment     string         `db:"comment" json:"comment"`
 	RequestedBy string         `db:"requested_by" json:"requested_by"`
 }
 
 func (q *Queries) ApproveRequestByUUID(ctx context.Context, arg ApproveRequestByUUIDParams) (ApproveRequestByUUIDRow, error) {
-	row := q.db.QueryRowContext(ctx, approveRequestByUUID, arg.Uuid, arg.DecidedBy, arg.Uuid_2)
+	row := q.db.QueryRowContext(ctx, approveRequestByUUID,
+		arg.Uuid,
+		arg.DecidedBy,
+		arg.Uuid_2,
+		arg.Comment,
+	)
 	var i ApproveRequestByUUIDRow
 	err := row.Scan(
 		&i.ID,
@@ -122,6 +131,7 @@ func (q *Queries) ApproveRequestByUUID(ctx context.Context, arg ApproveRequestBy
 		&i.NamespaceID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Comment,
 		&i.RequestedBy,
 	)
 	return i, err
@@ -132,7 +142,7 @@ WITH namespace_lookup AS (
     SELECT id FROM namespaces WHERE namespaces.uuid = $2
 )
 SELECT
-    a.id, a.uuid, a.exec_log_id, a.action_id, a.status, a.decided_by, a.namespace_id, a.created_at, a.updated_at,
+    a.id, a.uuid, a.exec_log_id, a.action_id, a.status, a.decided_by, a.namespace_id, a.created_at, a.updated_at, a.comment,
     el.exec_id,
     u.name as requested_by
 FROM approvals a
@@ -157,6 +167,7 @@ type GetApprovalByUUIDRow struct {
 	NamespaceID int32          `db:"namespace_id" json:"namespace_id"`
 	CreatedAt   time.Time      `db:"created_at" json:"created_at"`
 	UpdatedAt   time.Time      `db:"updated_at" json:"updated_at"`
+	Comment     string         `db:"comment" json:"comment"`
 	ExecID      string         `db:"exec_id" json:"exec_id"`
 	RequestedBy string         `db:"requested_by" json:"requested_by"`
 }
@@ -174,6 +185,7 @@ func (q *Queries) GetApprovalByUUID(ctx context.Context, arg GetApprovalByUUIDPa
 		&i.NamespaceID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Comment,
 		&i.ExecID,
 		&i.RequestedBy,
 	)
@@ -184,7 +196,7 @@ const getApprovalRequestForActionAndExec = `-- name: GetApprovalRequestForAction
 WITH namespace_lookup AS (
     SELECT id FROM namespaces WHERE namespaces.uuid = $3
 )
-SELECT a.id, a.uuid, a.exec_log_id, a.action_id, a.status, a.decided_by, a.namespace_id, a.created_at, a.updated_at FROM approvals a
+SELECT a.id, a.uuid, a.exec_log_id, a.action_id, a.status, a.decided_by, a.namespace_id, a.created_at, a.updated_at, a.comment FROM approvals a
 JOIN execution_log el ON a.exec_log_id = el.id
 JOIN flows f ON el.flow_id = f.id
 WHERE el.exec_id = $1
@@ -212,6 +224,7 @@ func (q *Queries) GetApprovalRequestForActionAndExec(ctx context.Context, arg Ge
 		&i.NamespaceID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Comment,
 	)
 	return i, err
 }
@@ -226,13 +239,15 @@ WITH namespace_lookup AS (
       AND namespace_id = (SELECT id FROM namespace_lookup)
 )
 SELECT
-    a.id, a.uuid, a.exec_log_id, a.action_id, a.status, a.decided_by, a.namespace_id, a.created_at, a.updated_at,
+    a.id, a.uuid, a.exec_log_id, a.action_id, a.status, a.decided_by, a.namespace_id, a.created_at, a.updated_at, a.comment,
     el.exec_id,
-    u.name as requested_by
+    u.name as requested_by,
+    us.name as decided_by_name
 FROM approvals a
 JOIN execution_log el ON a.exec_log_id = el.id
 JOIN flows f ON el.flow_id = f.id
 JOIN users u ON el.triggered_by = u.id
+LEFT JOIN users us ON a.decided_by = us.id
 WHERE el.exec_id = $1
   AND f.namespace_id = (SELECT id FROM namespace_lookup)
   AND el.version = (SELECT max_version FROM latest_version)
@@ -245,17 +260,19 @@ type GetApprovalRequestForExecParams struct {
 }
 
 type GetApprovalRequestForExecRow struct {
-	ID          int32          `db:"id" json:"id"`
-	Uuid        uuid.UUID      `db:"uuid" json:"uuid"`
-	ExecLogID   int32          `db:"exec_log_id" json:"exec_log_id"`
-	ActionID    string         `db:"action_id" json:"action_id"`
-	Status      ApprovalStatus `db:"status" json:"status"`
-	DecidedBy   sql.NullInt32  `db:"decided_by" json:"decided_by"`
-	NamespaceID int32          `db:"namespace_id" json:"namespace_id"`
-	CreatedAt   time.Time      `db:"created_at" json:"created_at"`
-	UpdatedAt   time.Time      `db:"updated_at" json:"updated_at"`
-	ExecID      string         `db:"exec_id" json:"exec_id"`
-	RequestedBy string         `db:"requested_by" json:"requested_by"`
+	ID            int32          `db:"id" json:"id"`
+	Uuid          uuid.UUID      `db:"uuid" json:"uuid"`
+	ExecLogID     int32          `db:"exec_log_id" json:"exec_log_id"`
+	ActionID      string         `db:"action_id" json:"action_id"`
+	Status        ApprovalStatus `db:"status" json:"status"`
+	DecidedBy     sql.NullInt32  `db:"decided_by" json:"decided_by"`
+	NamespaceID   int32          `db:"namespace_id" json:"namespace_id"`
+	CreatedAt     time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time      `db:"updated_at" json:"updated_at"`
+	Comment       string         `db:"comment" json:"comment"`
+	ExecID        string         `db:"exec_id" json:"exec_id"`
+	RequestedBy   string         `db:"requested_by" json:"requested_by"`
+	DecidedByName sql.NullString `db:"decided_by_name" json:"decided_by_name"`
 }
 
 func (q *Queries) GetApprovalRequestForExec(ctx context.Context, arg GetApprovalRequestForExecParams) (GetApprovalRequestForExecRow, error) {
@@ -271,8 +288,10 @@ func (q *Queries) GetApprovalRequestForExec(ctx context.Context, arg GetApproval
 		&i.NamespaceID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Comment,
 		&i.ExecID,
 		&i.RequestedBy,
+		&i.DecidedByName,
 	)
 	return i, err
 }
@@ -282,7 +301,7 @@ WITH namespace_lookup AS (
     SELECT id FROM namespaces WHERE namespaces.uuid = $2
 )
 SELECT
-    a.id, a.uuid, a.exec_log_id, a.action_id, a.status, a.decided_by, a.namespace_id, a.created_at, a.updated_at,
+    a.id, a.uuid, a.exec_log_id, a.action_id, a.status, a.decided_by, a.namespace_id, a.created_at, a.updated_at, a.comment,
     el.exec_id,
     el.input as exec_inputs,
     f.name as flow_name,
@@ -312,6 +331,7 @@ type GetApprovalWithInputsByUUIDRow struct {
 	NamespaceID   int32           `db:"namespace_id" json:"namespace_id"`
 	CreatedAt     time.Time       `db:"created_at" json:"created_at"`
 	UpdatedAt     time.Time       `db:"updated_at" json:"updated_at"`
+	Comment       string          `db:"comment" json:"comment"`
 	ExecID        string          `db:"exec_id" json:"exec_id"`
 	ExecInputs    json.RawMessage `db:"exec_inputs" json:"exec_inputs"`
 	FlowName      string          `db:"flow_name" json:"flow_name"`
@@ -333,6 +353,7 @@ func (q *Queries) GetApprovalWithInputsByUUID(ctx context.Context, arg GetApprov
 		&i.NamespaceID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Comment,
 		&i.ExecID,
 		&i.ExecInputs,
 		&i.FlowName,
@@ -349,7 +370,7 @@ WITH namespace_lookup AS (
 ),
 filtered AS (
     SELECT
-        a.id, a.uuid, a.exec_log_id, a.action_id, a.status, a.decided_by, a.namespace_id, a.created_at, a.updated_at,
+        a.id, a.uuid, a.exec_log_id, a.action_id, a.status, a.decided_by, a.namespace_id, a.created_at, a.updated_at, a.comment,
         el.exec_id,
         u.name as requested_by,
         f.name as flow_name
@@ -372,7 +393,7 @@ total AS (
     FROM filtered
 ),
 paged AS (
-    SELECT id, uuid, exec_log_id, action_id, status, decided_by, namespace_id, created_at, updated_at, exec_id, requested_by, flow_name
+    SELECT id, uuid, exec_log_id, action_id, status, decided_by, namespace_id, created_at, updated_at, comment, exec_id, requested_by, flow_name
     FROM filtered
     ORDER BY created_at DESC
     LIMIT $4 OFFSET $5
@@ -382,7 +403,7 @@ page_count AS (
     FROM total
 )
 SELECT
-    p.id, p.uuid, p.exec_log_id, p.action_id, p.status, p.decided_by, p.namespace_id, p.created_at, p.updated_at, p.exec_id, p.requested_by, p.flow_name,
+    p.id, p.uuid, p.exec_log_id, p.action_id, p.status, p.decided_by, p.namespace_id, p.created_at, p.updated_at, p.comment, p.exec_id, p.requested_by, p.flow_name,
     pc.page_count,
     t.total_count
 FROM paged p, page_count pc, total t
@@ -406,6 +427,7 @@ type GetApprovalsPaginatedRow struct {
 	NamespaceID int32          `db:"namespace_id" json:"namespace_id"`
 	CreatedAt   time.Time      `db:"created_at" json:"created_at"`
 	UpdatedAt   time.Time      `db:"updated_at" json:"updated_at"`
+	Comment     string         `db:"comment" json:"comment"`
 	ExecID      string         `db:"exec_id" json:"exec_id"`
 	RequestedBy string         `db:"requested_by" json:"requested_by"`
 	FlowName    string         `db:"flow_name" json:"flow_name"`
@@ -438,6 +460,7 @@ func (q *Queries) GetApprovalsPaginated(ctx context.Context, arg GetApprovalsPag
 			&i.NamespaceID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Comment,
 			&i.ExecID,
 			&i.RequestedBy,
 			&i.FlowName,
@@ -461,17 +484,17 @@ const rejectRequestByUUID = `-- name: RejectRequestByUUID :one
 WITH namespace_lookup AS (
     SELECT id FROM namespaces WHERE namespaces.uuid = $3
 ), updated AS (
-    UPDATE approvals SET status = 'rejected', decided_by = $2, updated_at = NOW()
+    UPDATE approvals SET status = 'rejected', decided_by = $2, comment = $4, updated_at = NOW()
     WHERE approvals.uuid = $1
     AND approvals.exec_log_id IN (
         SELECT el.id FROM execution_log el
         JOIN flows f ON el.flow_id = f.id
         WHERE f.namespace_id = (SELECT id FROM namespace_lookup) AND f.is_active = TRUE
     )
-    RETURNING id, uuid, exec_log_id, action_id, status, decided_by, namespace_id, created_at, updated_at
+    RETURNING id, uuid, exec_log_id, action_id, status, decided_by, namespace_id, created_at, updated_at, comment
 )
 SELECT
-    a.id, a.uuid, a.exec_log_id, a.action_id, a.status, a.decided_by, a.namespace_id, a.created_at, a.updated_at,
+    a.id, a.uuid, a.exec_log_id, a.action_id, a.status, a.decided_by, a.namespace_id, a.created_at, a.updated_at, a.comment,
     el.exec_id,
     u.name as requested_by
 FROM updated a
@@ -483,6 +506,7 @@ type RejectRequestByUUIDParams struct {
 	Uuid      uuid.UUID     `db:"uuid" json:"uuid"`
 	DecidedBy sql.NullInt32 `db:"decided_by" json:"decided_by"`
 	Uuid_2    uuid.UUID     `db:"uuid_2" json:"uuid_2"`
+	Comment   string        `db:"comment" json:"comment"`
 }
 
 type RejectRequestByUUIDRow struct {
@@ -495,12 +519,18 @@ type RejectRequestByUUIDRow struct {
 	NamespaceID int32          `db:"namespace_id" json:"namespace_id"`
 	CreatedAt   time.Time      `db:"created_at" json:"created_at"`
 	UpdatedAt   time.Time      `db:"updated_at" json:"updated_at"`
+	Comment     string         `db:"comment" json:"comment"`
 	ExecID      string         `db:"exec_id" json:"exec_id"`
 	RequestedBy string         `db:"requested_by" json:"requested_by"`
 }
 
 func (q *Queries) RejectRequestByUUID(ctx context.Context, arg RejectRequestByUUIDParams) (RejectRequestByUUIDRow, error) {
-	row := q.db.QueryRowContext(ctx, rejectRequestByUUID, arg.Uuid, arg.DecidedBy, arg.Uuid_2)
+	row := q.db.QueryRowContext(ctx, rejectRequestByUUID,
+		arg.Uuid,
+		arg.DecidedBy,
+		arg.Uuid_2,
+		arg.Comment,
+	)
 	var i RejectRequestByUUIDRow
 	err := row.Scan(
 		&i.ID,
@@ -512,6 +542,7 @@ func (q *Queries) RejectRequestByUUID(ctx context.Context, arg RejectRequestByUU
 		&i.NamespaceID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Comment,
 		&i.ExecID,
 		&i.RequestedBy,
 	)
@@ -522,10 +553,10 @@ const updateApprovalStatusByUUID = `-- name: UpdateApprovalStatusByUUID :one
 WITH updated AS (
     UPDATE approvals SET status = $1, decided_by = $2, updated_at = NOW()
     WHERE uuid = $1
-    RETURNING id, uuid, exec_log_id, action_id, status, decided_by, namespace_id, created_at, updated_at
+    RETURNING id, uuid, exec_log_id, action_id, status, decided_by, namespace_id, created_at, updated_at, comment
 )
 SELECT
-    a.id, a.uuid, a.exec_log_id, a.action_id, a.status, a.decided_by, a.namespace_id, a.created_at, a.updated_at,
+    a.id, a.uuid, a.exec_log_id, a.action_id, a.status, a.decided_by, a.namespace_id, a.created_at, a.updated_at, a.comment,
     u.name as requested_by
 FROM updated a
 JOIN execution_log el ON a.exec_log_id = el.id
@@ -547,6 +578,7 @@ type UpdateApprovalStatusByUUIDRow struct {
 	NamespaceID int32          `db:"namespace_id" json:"namespace_id"`
 	CreatedAt   time.Time      `db:"created_at" json:"created_at"`
 	UpdatedAt   time.Time      `db:"updated_at" json:"updated_at"`
+	Comment     string         `db:"comment" json:"comment"`
 	RequestedBy string         `db:"requested_by" json:"requested_by"`
 }
 
@@ -563,6 +595,7 @@ func (q *Queries) UpdateApprovalStatusByUUID(ctx context.Context, arg UpdateAppr
 		&i.NamespaceID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Comment,
 		&i.RequestedBy,
 	)
 	return i, err
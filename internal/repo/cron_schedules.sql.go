@@ -402,6 +402,55 @@ func (q *Queries) GetUserScheduleByUUID(ctx context.Context, arg GetUserSchedule
 	return i, err
 }
 
+const listNamespaceSchedules = `-- name: ListNamespaceSchedules :many
+SELECT cs.uuid, cs.cron, cs.timezone, cs.is_user_created, f.slug AS flow_slug, f.name AS flow_name
+FROM cron_schedules cs
+JOIN flows f ON cs.flow_id = f.id
+JOIN namespaces n ON f.namespace_id = n.id
+WHERE n.uuid = $1 AND f.is_active = TRUE AND cs.is_active = TRUE
+ORDER BY f.slug, cs.id
+`
+
+type ListNamespaceSchedulesRow struct {
+	Uuid          uuid.UUID `db:"uuid" json:"uuid"`
+	Cron          string    `db:"cron" json:"cron"`
+	Timezone      string    `db:"timezone" json:"timezone"`
+	IsUserCreated bool      `db:"is_user_created" json:"is_user_created"`
+	FlowSlug      string    `db:"flow_slug" json:"flow_slug"`
+	FlowName      string    `db:"flow_name" json:"flow_name"`
+}
+
+// Lists the active schedules of the active flows of a namespace
+func (q *Queries) ListNamespaceSchedules(ctx context.Context, argUuid uuid.UUID) ([]ListNamespaceSchedulesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listNamespaceSchedules, argUuid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListNamespaceSchedulesRow
+	for rows.Next() {
+		var i ListNamespaceSchedulesRow
+		if err := rows.Scan(
+			&i.Uuid,
+			&i.Cron,
+			&i.Timezone,
+			&i.IsUserCreated,
+			&i.FlowSlug,
+			&i.FlowName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listSchedules = `-- name: ListSchedules :many
 WITH user_namespaces AS (
     -- Direct user membership
@@ -789,6 +789,108 @@ func (q *Queries) GetExecutionsByFlowPaginated(ctx context.Context, arg GetExecu
 	return items, nil
 }
 
+const getExecutionsForLogSearch = `-- name: GetExecutionsForLogSearch :many
+WITH namespace_lookup AS (
+    SELECT id FROM namespaces WHERE namespaces.uuid = $1
+),
+user_namespaces AS (
+    -- Direct user membership
+    SELECT n.uuid, nm.role
+    FROM namespaces n
+    JOIN namespace_members nm ON n.id = nm.namespace_id
+    JOIN users u ON nm.user_id = u.id
+    WHERE u.uuid = $2 AND n.uuid = $1
+
+    UNION
+
+    -- Group membership
+    SELECT DISTINCT n.uuid, nm.role
+    FROM namespaces n
+    JOIN namespace_members nm ON n.id = nm.namespace_id
+    JOIN groups g ON nm.group_id = g.id
+    JOIN group_memberships gm ON g.id = gm.group_id
+    WHERE gm.user_id = (SELECT id FROM users WHERE users.uuid = $2) AND n.uuid = $1
+),
+latest_versions AS (
+    SELECT exec_id, MAX(version) as max_version
+    FROM execution_log el
+    INNER JOIN flows f ON el.flow_id = f.id
+    WHERE f.namespace_id = (SELECT id FROM namespace_lookup)
+      AND f.is_active = TRUE
+    GROUP BY exec_id
+)
+SELECT el.exec_id, el.action_retries, el.created_at, f.name as flow_name, f.slug as flow_slug
+FROM execution_log el
+INNER JOIN flows f ON el.flow_id = f.id
+INNER JOIN latest_versions lv ON el.exec_id = lv.exec_id AND el.version = lv.max_version
+WHERE f.namespace_id = (SELECT id FROM namespace_lookup)
+  AND f.is_active = TRUE
+  AND el.status NOT IN ('pending', 'running')
+  AND (
+    el.triggered_by = (SELECT id FROM users WHERE users.uuid = $2)
+    OR EXISTS (SELECT id FROM users WHERE users.uuid = $2 AND users.role = 'superuser')
+    OR EXISTS (SELECT uuid FROM user_namespaces WHERE role IN ('admin', 'reviewer', 'operator'))
+  )
+  AND ($3::text = '' OR f.slug = $3::text)
+  AND ($4::timestamptz IS NULL OR el.created_at >= $4::timestamptz)
+  AND ($5::timestamptz IS NULL OR el.created_at <= $5::timestamptz)
+ORDER BY el.created_at DESC
+LIMIT $6
+`
+
+type GetExecutionsForLogSearchParams struct {
+	NamespaceUuid uuid.UUID    `db:"namespace_uuid" json:"namespace_uuid"`
+	CallerUuid    uuid.UUID    `db:"caller_uuid" json:"caller_uuid"`
+	FlowSlug      string       `db:"flow_slug" json:"flow_slug"`
+	CreatedAfter  sql.NullTime `db:"created_after" json:"created_after"`
+	CreatedBefore sql.NullTime `db:"created_before" json:"created_before"`
+	MaxExecutions int32        `db:"max_executions" json:"max_executions"`
+}
+
+type GetExecutionsForLogSearchRow struct {
+	ExecID        string                `db:"exec_id" json:"exec_id"`
+	ActionRetries pqtype.NullRawMessage `db:"action_retries" json:"action_retries"`
+	CreatedAt     time.Time             `db:"created_at" json:"created_at"`
+	FlowName      string                `db:"flow_name" json:"flow_name"`
+	FlowSlug      string                `db:"flow_slug" json:"flow_slug"`
+}
+
+func (q *Queries) GetExecutionsForLogSearch(ctx context.Context, arg GetExecutionsForLogSearchParams) ([]GetExecutionsForLogSearchRow, error) {
+	rows, err := q.db.QueryContext(ctx, getExecutionsForLogSearch,
+		arg.NamespaceUuid,
+		arg.CallerUuid,
+		arg.FlowSlug,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.MaxExecutions,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetExecutionsForLogSearchRow
+	for rows.Next() {
+		var i GetExecutionsForLogSearchRow
+		if err := rows.Scan(
+			&i.ExecID,
+			&i.ActionRetries,
+			&i.CreatedAt,
+			&i.FlowName,
+			&i.FlowSlug,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getFlowFromExecID = `-- name: GetFlowFromExecID :one
 WITH namespace_lookup AS (
     SELECT id FROM namespaces WHERE namespaces.uuid = $2
@@ -994,6 +1096,36 @@ func (q *Queries) IncrementActionRetry(ctx context.Context, arg IncrementActionR
 	return i, err
 }
 
+const markExecutionRunning = `-- name: MarkExecutionRunning :exec
+WITH namespace_lookup AS (
+    SELECT id FROM namespaces WHERE namespaces.uuid = $2
+), latest_version AS (
+    SELECT MAX(version) as version
+    FROM execution_log
+    WHERE execution_log.exec_id = $1 AND namespace_id = (SELECT id FROM namespace_lookup)
+)
+UPDATE execution_log SET
+    status = 'running',
+    error = NULL,
+    started_at = COALESCE(started_at, NOW()),
+    updated_at = NOW()
+WHERE execution_log.exec_id = $1
+  AND version = (SELECT version FROM latest_version)
+  AND namespace_id = (SELECT id FROM namespace_lookup)
+`
+
+type MarkExecutionRunningParams struct {
+	ExecID string    `db:"exec_id" json:"exec_id"`
+	Uuid   uuid.UUID `db:"uuid" json:"uuid"`
+}
+
+// MarkExecutionRunning sets the status of an execution to running and records when it first
+// started, replacing separate status and started_at updates
+func (q *Queries) MarkExecutionRunning(ctx context.Context, arg MarkExecutionRunningParams) error {
+	_, err := q.db.ExecContext(ctx, markExecutionRunning, arg.ExecID, arg.Uuid)
+	return err
+}
+
 const searchExecutionsPaginated = `-- name: SearchExecutionsPaginated :many
 WITH namespace_lookup AS (
     SELECT id FROM namespaces WHERE namespaces.uuid = $1
@@ -1159,6 +1291,44 @@ func (q *Queries) SearchExecutionsPaginated(ctx context.Context, arg SearchExecu
 	return items, nil
 }
 
+const startExecutionAction = `-- name: StartExecutionAction :one
+WITH namespace_lookup AS (
+    SELECT id FROM namespaces WHERE namespaces.uuid = $1
+), latest_version AS (
+    SELECT MAX(version) as version
+    FROM execution_log el
+    WHERE el.exec_id = $2 AND el.namespace_id = (SELECT id FROM namespace_lookup)
+)
+UPDATE execution_log el
+SET
+    current_action_id = $3::text,
+    action_retries = jsonb_set(
+        COALESCE(action_retries, '{}'::jsonb),
+        ARRAY[$3::text],
+        to_jsonb((COALESCE(action_retries->>$3::text, '0')::int + 1))
+    ),
+    updated_at = NOW()
+WHERE el.exec_id = $2
+  AND el.version = (SELECT version FROM latest_version)
+  AND el.namespace_id = (SELECT id FROM namespace_lookup)
+RETURNING (action_retries->>$3::text)::int as retry_count
+`
+
+type StartExecutionActionParams struct {
+	NamespaceUuid uuid.UUID `db:"namespace_uuid" json:"namespace_uuid"`
+	ExecID        string    `db:"exec_id" json:"exec_id"`
+	ActionID      string    `db:"action_id" json:"action_id"`
+}
+
+// StartExecutionAction sets the current action of an execution and increments its retry count
+// in a single round trip
+func (q *Queries) StartExecutionAction(ctx context.Context, arg StartExecutionActionParams) (int32, error) {
+	row := q.db.QueryRowContext(ctx, startExecutionAction, arg.NamespaceUuid, arg.ExecID, arg.ActionID)
+	var retry_count int32
+	err := row.Scan(&retry_count)
+	return retry_count, err
+}
+
 const updateExecutionActionID = `-- name: UpdateExecutionActionID :one
 WITH namespace_lookup AS (
     SELECT id FROM namespaces WHERE namespaces.uuid = $3
@@ -66,6 +66,23 @@ func (q *Queries) CreateFlow(ctx context.Context, arg CreateFlowParams) (Flow, e
 	return i, err
 }
 
+const createFlowSearchIfMissing = `-- name: CreateFlowSearchIfMissing :exec
+INSERT INTO flow_search (flow_id, content, document)
+VALUES ($1, $2::text, to_tsvector('english', $2::text))
+ON CONFLICT (flow_id) DO NOTHING
+`
+
+type CreateFlowSearchIfMissingParams struct {
+	FlowID  int32  `db:"flow_id" json:"flow_id"`
+	Content string `db:"content" json:"content"`
+}
+
+// Indexes flows that were created before search was added when they are next loaded
+func (q *Queries) CreateFlowSearchIfMissing(ctx context.Context, arg CreateFlowSearchIfMissingParams) error {
+	_, err := q.db.ExecContext(ctx, createFlowSearchIfMissing, arg.FlowID, arg.Content)
+	return err
+}
+
 const deleteAllFlows = `-- name: DeleteAllFlows :exec
 DELETE FROM flows
 `
@@ -633,6 +650,75 @@ func (q *Queries) ListFlowsPaginatedFiltered(ctx context.Context, arg ListFlowsP
 	return items, nil
 }
 
+const listScheduledFlowJobs = `-- name: ListScheduledFlowJobs :many
+SELECT
+    f.slug,
+    f.name,
+    f.file_path,
+    n.uuid AS namespace_uuid,
+    cs.id AS schedule_id,
+    cs.cron,
+    cs.timezone,
+    cs.inputs,
+    cs.is_user_created,
+    u.uuid AS created_by_uuid
+FROM flows f
+JOIN namespaces n ON f.namespace_id = n.id
+JOIN cron_schedules cs ON cs.flow_id = f.id
+LEFT JOIN users u ON cs.created_by = u.id
+WHERE f.is_active = TRUE AND cs.is_active = TRUE
+ORDER BY n.id, f.id, cs.id
+`
+
+type ListScheduledFlowJobsRow struct {
+	Slug          string                `db:"slug" json:"slug"`
+	Name          string                `db:"name" json:"name"`
+	FilePath      string                `db:"file_path" json:"file_path"`
+	NamespaceUuid uuid.UUID             `db:"namespace_uuid" json:"namespace_uuid"`
+	ScheduleID    int32                 `db:"schedule_id" json:"schedule_id"`
+	Cron          string                `db:"cron" json:"cron"`
+	Timezone      string                `db:"timezone" json:"timezone"`
+	Inputs        pqtype.NullRawMessage `db:"inputs" json:"inputs"`
+	IsUserCreated bool                  `db:"is_user_created" json:"is_user_created"`
+	CreatedByUuid uuid.NullUUID         `db:"created_by_uuid" json:"created_by_uuid"`
+}
+
+// ListScheduledFlowJobs returns the active schedules of active flows with their namespace and the
+// user that created them, so scheduled jobs can be built without a query per schedule
+func (q *Queries) ListScheduledFlowJobs(ctx context.Context) ([]ListScheduledFlowJobsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listScheduledFlowJobs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListScheduledFlowJobsRow
+	for rows.Next() {
+		var i ListScheduledFlowJobsRow
+		if err := rows.Scan(
+			&i.Slug,
+			&i.Name,
+			&i.FilePath,
+			&i.NamespaceUuid,
+			&i.ScheduleID,
+			&i.Cron,
+			&i.Timezone,
+			&i.Inputs,
+			&i.IsUserCreated,
+			&i.CreatedByUuid,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const markAllFlowsInactiveForNamespace = `-- name: MarkAllFlowsInactiveForNamespace :exec
 UPDATE flows SET is_active = FALSE, updated_at = NOW()
 WHERE namespace_id = (SELECT id FROM namespaces WHERE namespaces.uuid = $1)
@@ -665,10 +751,13 @@ WITH filtered AS (
     FROM flows f
     JOIN namespaces n ON f.namespace_id = n.id
     LEFT JOIN flow_prefixes fp ON f.prefix_id = fp.id
+    LEFT JOIN flow_search fs ON fs.flow_id = f.id
     WHERE n.uuid = $1
       AND f.is_active = TRUE
       AND (lower(f.name) LIKE '%' || lower($2::text) || '%'
-           OR lower(f.description) LIKE '%' || lower($2::text) || '%')
+           OR lower(f.description) LIKE '%' || lower($2::text) || '%'
+           OR (to_tsvector('english', f.name || ' ' || COALESCE(f.description, ''))
+               || COALESCE(fs.document, ''::tsvector)) @@ websearch_to_tsquery('english', $2::text))
 ),
 total AS (
     SELECT COUNT(*) AS total_count FROM filtered
@@ -767,10 +856,13 @@ WITH filtered AS (
     FROM flows f
     JOIN namespaces n ON f.namespace_id = n.id
     LEFT JOIN flow_prefixes fp ON f.prefix_id = fp.id
+    LEFT JOIN flow_search fs ON fs.flow_id = f.id
     WHERE n.uuid = $1
       AND f.is_active = TRUE
       AND (lower(f.name) LIKE '%' || lower($2::text) || '%'
-           OR lower(f.description) LIKE '%' || lower($2::text) || '%')
+           OR lower(f.description) LIKE '%' || lower($2::text) || '%'
+           OR (to_tsvector('english', f.name || ' ' || COALESCE(f.description, ''))
+               || COALESCE(fs.document, ''::tsvector)) @@ websearch_to_tsquery('english', $2::text))
       AND (f.prefix_id IS NULL OR fp.name = ANY($5::text[]))
 ),
 total AS (SELECT COUNT(*) AS total_count FROM filtered),
@@ -908,3 +1000,22 @@ func (q *Queries) UpdateFlow(ctx context.Context, arg UpdateFlowParams) (Flow, e
 	)
 	return i, err
 }
+
+const upsertFlowSearch = `-- name: UpsertFlowSearch :exec
+INSERT INTO flow_search (flow_id, content, document)
+VALUES ($1, $2::text, to_tsvector('english', $2::text))
+ON CONFLICT (flow_id) DO UPDATE SET
+    content = EXCLUDED.content,
+    document = EXCLUDED.document,
+    updated_at = NOW()
+`
+
+type UpsertFlowSearchParams struct {
+	FlowID  int32  `db:"flow_id" json:"flow_id"`
+	Content string `db:"content" json:"content"`
+}
+
+func (q *Queries) UpsertFlowSearch(ctx context.Context, arg UpsertFlowSearchParams) error {
+	_, err := q.db.ExecContext(ctx, upsertFlowSearch, arg.FlowID, arg.Content)
+	return err
+}
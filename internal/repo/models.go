@@ -315,6 +315,17 @@ func (ns NullUserRoleType) Value() (driver.Value, error) {
 	return string(ns.UserRoleType), nil
 }
 
+type ApiToken struct {
+	ID         int32        `db:"id" json:"id"`
+	Uuid       uuid.UUID    `db:"uuid" json:"uuid"`
+	UserID     int32        `db:"user_id" json:"user_id"`
+	Name       string       `db:"name" json:"name"`
+	TokenHash  string       `db:"token_hash" json:"token_hash"`
+	ExpiresAt  sql.NullTime `db:"expires_at" json:"expires_at"`
+	LastUsedAt sql.NullTime `db:"last_used_at" json:"last_used_at"`
+	CreatedAt  time.Time    `db:"created_at" json:"created_at"`
+}
+
 type Approval struct {
 	ID          int32          `db:"id" json:"id"`
 	Uuid        uuid.UUID      `db:"uuid" json:"uuid"`
@@ -325,6 +336,46 @@ type Approval struct {
 	NamespaceID int32          `db:"namespace_id" json:"namespace_id"`
 	CreatedAt   time.Time      `db:"created_at" json:"created_at"`
 	UpdatedAt   time.Time      `db:"updated_at" json:"updated_at"`
+	Comment     string         `db:"comment" json:"comment"`
+}
+
+type ApprovalDelegation struct {
+	ID              int32         `db:"id" json:"id"`
+	Uuid            uuid.UUID     `db:"uuid" json:"uuid"`
+	NamespaceID     int32         `db:"namespace_id" json:"namespace_id"`
+	DelegatorID     int32         `db:"delegator_id" json:"delegator_id"`
+	DelegateUserID  sql.NullInt32 `db:"delegate_user_id" json:"delegate_user_id"`
+	DelegateGroupID sql.NullInt32 `db:"delegate_group_id" json:"delegate_group_id"`
+	StartsAt        time.Time     `db:"starts_at" json:"starts_at"`
+	EndsAt          time.Time     `db:"ends_at" json:"ends_at"`
+	Reason          string        `db:"reason" json:"reason"`
+	CreatedAt       time.Time     `db:"created_at" json:"created_at"`
+}
+
+type Artifact struct {
+	ID          int32     `db:"id" json:"id"`
+	Uuid        uuid.UUID `db:"uuid" json:"uuid"`
+	ExecID      string    `db:"exec_id" json:"exec_id"`
+	NamespaceID int32     `db:"namespace_id" json:"namespace_id"`
+	ActionID    string    `db:"action_id" json:"action_id"`
+	Name        string    `db:"name" json:"name"`
+	ObjectKey   string    `db:"object_key" json:"object_key"`
+	Size        int64     `db:"size" json:"size"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+}
+
+type AuditLog struct {
+	ID            int64                 `db:"id" json:"id"`
+	NamespaceUuid uuid.NullUUID         `db:"namespace_uuid" json:"namespace_uuid"`
+	ActorUuid     uuid.UUID             `db:"actor_uuid" json:"actor_uuid"`
+	ActorName     string                `db:"actor_name" json:"actor_name"`
+	Action        string                `db:"action" json:"action"`
+	ResourceType  string                `db:"resource_type" json:"resource_type"`
+	ResourceID    string                `db:"resource_id" json:"resource_id"`
+	Before        pqtype.NullRawMessage `db:"before" json:"before"`
+	After         pqtype.NullRawMessage `db:"after" json:"after"`
+	SourceIp      string                `db:"source_ip" json:"source_ip"`
+	CreatedAt     time.Time             `db:"created_at" json:"created_at"`
 }
 
 type CasbinRule struct {
@@ -364,6 +415,68 @@ type CronSchedule struct {
 	IsActive      bool                  `db:"is_active" json:"is_active"`
 }
 
+type CronScheduleRun struct {
+	ScheduleID int32          `db:"schedule_id" json:"schedule_id"`
+	ExecID     sql.NullString `db:"exec_id" json:"exec_id"`
+	FiredAt    time.Time      `db:"fired_at" json:"fired_at"`
+	StartedAt  time.Time      `db:"started_at" json:"started_at"`
+	MaxDriftMs int64          `db:"max_drift_ms" json:"max_drift_ms"`
+	RunCount   int64          `db:"run_count" json:"run_count"`
+}
+
+type ExecutionArchive struct {
+	ExecID          string                `db:"exec_id" json:"exec_id"`
+	NamespaceID     int32                 `db:"namespace_id" json:"namespace_id"`
+	FlowSlug        string                `db:"flow_slug" json:"flow_slug"`
+	FlowName        string                `db:"flow_name" json:"flow_name"`
+	Status          ExecutionStatus       `db:"status" json:"status"`
+	TriggerType     TriggerType           `db:"trigger_type" json:"trigger_type"`
+	TriggeredByUuid uuid.UUID             `db:"triggered_by_uuid" json:"triggered_by_uuid"`
+	TriggeredByName string                `db:"triggered_by_name" json:"triggered_by_name"`
+	Input           json.RawMessage       `db:"input" json:"input"`
+	Error           sql.NullString        `db:"error" json:"error"`
+	CurrentActionID sql.NullString        `db:"current_action_id" json:"current_action_id"`
+	ActionRetries   pqtype.NullRawMessage `db:"action_retries" json:"action_retries"`
+	CreatedAt       time.Time             `db:"created_at" json:"created_at"`
+	StartedAt       sql.NullTime          `db:"started_at" json:"started_at"`
+	CompletedAt     sql.NullTime          `db:"completed_at" json:"completed_at"`
+	ScheduledAt     sql.NullTime          `db:"scheduled_at" json:"scheduled_at"`
+	ArchivedAt      time.Time             `db:"archived_at" json:"archived_at"`
+}
+
+type ExecutionActionSkip struct {
+	ID          int32         `db:"id" json:"id"`
+	ExecID      string        `db:"exec_id" json:"exec_id"`
+	ActionID    string        `db:"action_id" json:"action_id"`
+	NamespaceID int32         `db:"namespace_id" json:"namespace_id"`
+	SkippedBy   sql.NullInt32 `db:"skipped_by" json:"skipped_by"`
+	CreatedAt   time.Time     `db:"created_at" json:"created_at"`
+}
+
+type ExecutionEnvironment struct {
+	ID              int32     `db:"id" json:"id"`
+	ExecID          string    `db:"exec_id" json:"exec_id"`
+	NamespaceID     int32     `db:"namespace_id" json:"namespace_id"`
+	ActionID        string    `db:"action_id" json:"action_id"`
+	Node            string    `db:"node" json:"node"`
+	Hostname        string    `db:"hostname" json:"hostname"`
+	Os              string    `db:"os" json:"os"`
+	Executor        string    `db:"executor" json:"executor"`
+	ExecutorVersion string    `db:"executor_version" json:"executor_version"`
+	Image           string    `db:"image" json:"image"`
+	ImageDigest     string    `db:"image_digest" json:"image_digest"`
+	FlowChecksum    string    `db:"flow_checksum" json:"flow_checksum"`
+	RecordedAt      time.Time `db:"recorded_at" json:"recorded_at"`
+}
+
+type ExecutionLock struct {
+	ID          int32     `db:"id" json:"id"`
+	NamespaceID int32     `db:"namespace_id" json:"namespace_id"`
+	LockKey     string    `db:"lock_key" json:"lock_key"`
+	ExecID      string    `db:"exec_id" json:"exec_id"`
+	AcquiredAt  time.Time `db:"acquired_at" json:"acquired_at"`
+}
+
 type ExecutionLog struct {
 	ID              int32                 `db:"id" json:"id"`
 	ExecID          string                `db:"exec_id" json:"exec_id"`
@@ -384,6 +497,77 @@ type ExecutionLog struct {
 	StartedAt       sql.NullTime          `db:"started_at" json:"started_at"`
 }
 
+type ExecutionLogUsage struct {
+	ExecID      string    `db:"exec_id" json:"exec_id"`
+	NamespaceID int32     `db:"namespace_id" json:"namespace_id"`
+	LogBytes    int64     `db:"log_bytes" json:"log_bytes"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+}
+
+type ExecutionNodeTelemetry struct {
+	ID            int32     `db:"id" json:"id"`
+	ExecID        string    `db:"exec_id" json:"exec_id"`
+	NamespaceID   int32     `db:"namespace_id" json:"namespace_id"`
+	ActionID      string    `db:"action_id" json:"action_id"`
+	Node          string    `db:"node" json:"node"`
+	SampledAt     time.Time `db:"sampled_at" json:"sampled_at"`
+	CpuPercent    float64   `db:"cpu_percent" json:"cpu_percent"`
+	MemoryPercent float64   `db:"memory_percent" json:"memory_percent"`
+	DiskPercent   float64   `db:"disk_percent" json:"disk_percent"`
+	Load1         float64   `db:"load1" json:"load1"`
+}
+
+type ExecutionOutput struct {
+	ExecID      string          `db:"exec_id" json:"exec_id"`
+	NamespaceID int32           `db:"namespace_id" json:"namespace_id"`
+	ActionID    string          `db:"action_id" json:"action_id"`
+	Outputs     json.RawMessage `db:"outputs" json:"outputs"`
+	CreatedAt   time.Time       `db:"created_at" json:"created_at"`
+}
+
+type ExecutionProgress struct {
+	ID          int32           `db:"id" json:"id"`
+	ExecID      string          `db:"exec_id" json:"exec_id"`
+	NamespaceID int32           `db:"namespace_id" json:"namespace_id"`
+	Progress    json.RawMessage `db:"progress" json:"progress"`
+	UpdatedAt   time.Time       `db:"updated_at" json:"updated_at"`
+}
+
+type ExecutionRetentionPolicy struct {
+	NamespaceID int32         `db:"namespace_id" json:"namespace_id"`
+	MaxAgeDays  sql.NullInt32 `db:"max_age_days" json:"max_age_days"`
+	KeepLast    sql.NullInt32 `db:"keep_last" json:"keep_last"`
+	CreatedAt   time.Time     `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time     `db:"updated_at" json:"updated_at"`
+}
+
+type ExecutionSlot struct {
+	ExecID      string    `db:"exec_id" json:"exec_id"`
+	NamespaceID int32     `db:"namespace_id" json:"namespace_id"`
+	FlowSlug    string    `db:"flow_slug" json:"flow_slug"`
+	AcquiredAt  time.Time `db:"acquired_at" json:"acquired_at"`
+}
+
+type ExecutionTimeline struct {
+	ID          int32          `db:"id" json:"id"`
+	ExecID      string         `db:"exec_id" json:"exec_id"`
+	NamespaceID int32          `db:"namespace_id" json:"namespace_id"`
+	ActionID    string         `db:"action_id" json:"action_id"`
+	Node        string         `db:"node" json:"node"`
+	Retry       int32          `db:"retry" json:"retry"`
+	Status      string         `db:"status" json:"status"`
+	Error       sql.NullString `db:"error" json:"error"`
+	StartedAt   time.Time      `db:"started_at" json:"started_at"`
+	FinishedAt  sql.NullTime   `db:"finished_at" json:"finished_at"`
+}
+
+type ExecutionWatch struct {
+	UserID    int32          `db:"user_id" json:"user_id"`
+	FlowID    int32          `db:"flow_id" json:"flow_id"`
+	ExecID    sql.NullString `db:"exec_id" json:"exec_id"`
+	CreatedAt time.Time      `db:"created_at" json:"created_at"`
+}
+
 type Flow struct {
 	ID          int32          `db:"id" json:"id"`
 	Slug        string         `db:"slug" json:"slug"`
@@ -398,6 +582,20 @@ type Flow struct {
 	PrefixID    sql.NullInt32  `db:"prefix_id" json:"prefix_id"`
 }
 
+type FlowFavorite struct {
+	UserID    int32     `db:"user_id" json:"user_id"`
+	FlowID    int32     `db:"flow_id" json:"flow_id"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+type FlowImportError struct {
+	ID          int32     `db:"id" json:"id"`
+	NamespaceID int32     `db:"namespace_id" json:"namespace_id"`
+	FilePath    string    `db:"file_path" json:"file_path"`
+	Error       string    `db:"error" json:"error"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+}
+
 type FlowPrefix struct {
 	ID          int32     `db:"id" json:"id"`
 	Uuid        uuid.UUID `db:"uuid" json:"uuid"`
@@ -408,6 +606,40 @@ type FlowPrefix struct {
 	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
 }
 
+type FlowPromotion struct {
+	ID             int32         `db:"id" json:"id"`
+	SourceFlowID   int32         `db:"source_flow_id" json:"source_flow_id"`
+	SourceChecksum string        `db:"source_checksum" json:"source_checksum"`
+	TargetFlowID   int32         `db:"target_flow_id" json:"target_flow_id"`
+	RevisionUuid   uuid.NullUUID `db:"revision_uuid" json:"revision_uuid"`
+	PromotedBy     sql.NullInt32 `db:"promoted_by" json:"promoted_by"`
+	CreatedAt      time.Time     `db:"created_at" json:"created_at"`
+}
+
+type FlowRevision struct {
+	ID          int32          `db:"id" json:"id"`
+	Uuid        uuid.UUID      `db:"uuid" json:"uuid"`
+	FlowID      int32          `db:"flow_id" json:"flow_id"`
+	NamespaceID int32          `db:"namespace_id" json:"namespace_id"`
+	Content     string         `db:"content" json:"content"`
+	Format      string         `db:"format" json:"format"`
+	Checksum    string         `db:"checksum" json:"checksum"`
+	CommitSha   sql.NullString `db:"commit_sha" json:"commit_sha"`
+	Status      string         `db:"status" json:"status"`
+	RequestedBy sql.NullInt32  `db:"requested_by" json:"requested_by"`
+	ReviewedBy  sql.NullInt32  `db:"reviewed_by" json:"reviewed_by"`
+	Comment     string         `db:"comment" json:"comment"`
+	CreatedAt   time.Time      `db:"created_at" json:"created_at"`
+	ReviewedAt  sql.NullTime   `db:"reviewed_at" json:"reviewed_at"`
+}
+
+type FlowSearch struct {
+	FlowID    int32       `db:"flow_id" json:"flow_id"`
+	Content   string      `db:"content" json:"content"`
+	Document  interface{} `db:"document" json:"document"`
+	UpdatedAt time.Time   `db:"updated_at" json:"updated_at"`
+}
+
 type FlowSecret struct {
 	ID             int32          `db:"id" json:"id"`
 	Uuid           uuid.UUID      `db:"uuid" json:"uuid"`
@@ -420,6 +652,42 @@ type FlowSecret struct {
 	UpdatedAt      time.Time      `db:"updated_at" json:"updated_at"`
 }
 
+type FlowTriggerRun struct {
+	ID             int32     `db:"id" json:"id"`
+	UpstreamExecID string    `db:"upstream_exec_id" json:"upstream_exec_id"`
+	ExecID         string    `db:"exec_id" json:"exec_id"`
+	FlowID         int32     `db:"flow_id" json:"flow_id"`
+	Depth          int32     `db:"depth" json:"depth"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+}
+
+type FlowVersion struct {
+	ID        int32          `db:"id" json:"id"`
+	FlowID    int32          `db:"flow_id" json:"flow_id"`
+	Checksum  string         `db:"checksum" json:"checksum"`
+	CommitSha sql.NullString `db:"commit_sha" json:"commit_sha"`
+	CreatedAt time.Time      `db:"created_at" json:"created_at"`
+	Content   sql.NullString `db:"content" json:"content"`
+	Format    sql.NullString `db:"format" json:"format"`
+}
+
+type FlowWebhook struct {
+	ID              int32        `db:"id" json:"id"`
+	FlowID          int32        `db:"flow_id" json:"flow_id"`
+	TokenHash       string       `db:"token_hash" json:"token_hash"`
+	EncryptedSecret string       `db:"encrypted_secret" json:"encrypted_secret"`
+	CreatedAt       time.Time    `db:"created_at" json:"created_at"`
+	LastUsedAt      sql.NullTime `db:"last_used_at" json:"last_used_at"`
+}
+
+type GlobalVariable struct {
+	Key         string         `db:"key" json:"key"`
+	Value       string         `db:"value" json:"value"`
+	Description sql.NullString `db:"description" json:"description"`
+	CreatedAt   time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time      `db:"updated_at" json:"updated_at"`
+}
+
 type Group struct {
 	ID          int32          `db:"id" json:"id"`
 	Uuid        uuid.UUID      `db:"uuid" json:"uuid"`
@@ -446,6 +714,41 @@ type GroupView struct {
 	Users       interface{}    `db:"users" json:"users"`
 }
 
+type InputPreset struct {
+	ID        int32           `db:"id" json:"id"`
+	Uuid      uuid.UUID       `db:"uuid" json:"uuid"`
+	FlowID    int32           `db:"flow_id" json:"flow_id"`
+	Name      string          `db:"name" json:"name"`
+	Inputs    json.RawMessage `db:"inputs" json:"inputs"`
+	IsShared  bool            `db:"is_shared" json:"is_shared"`
+	CreatedBy int32           `db:"created_by" json:"created_by"`
+	CreatedAt time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time       `db:"updated_at" json:"updated_at"`
+}
+
+type ManualTask struct {
+	ID          int32                 `db:"id" json:"id"`
+	Uuid        uuid.UUID             `db:"uuid" json:"uuid"`
+	ExecID      string                `db:"exec_id" json:"exec_id"`
+	ActionID    string                `db:"action_id" json:"action_id"`
+	NamespaceID int32                 `db:"namespace_id" json:"namespace_id"`
+	Definition  json.RawMessage       `db:"definition" json:"definition"`
+	Status      string                `db:"status" json:"status"`
+	Response    pqtype.NullRawMessage `db:"response" json:"response"`
+	CompletedBy sql.NullInt32         `db:"completed_by" json:"completed_by"`
+	CreatedAt   time.Time             `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time             `db:"updated_at" json:"updated_at"`
+}
+
+type MessengerConfig struct {
+	ID              int32     `db:"id" json:"id"`
+	Channel         string    `db:"channel" json:"channel"`
+	Enabled         bool      `db:"enabled" json:"enabled"`
+	EncryptedConfig string    `db:"encrypted_config" json:"encrypted_config"`
+	CreatedAt       time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time `db:"updated_at" json:"updated_at"`
+}
+
 type Namespace struct {
 	ID        int32     `db:"id" json:"id"`
 	Uuid      uuid.UUID `db:"uuid" json:"uuid"`
@@ -454,6 +757,29 @@ type Namespace struct {
 	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
 }
 
+type NamespaceDefault struct {
+	NamespaceID     int32           `db:"namespace_id" json:"namespace_id"`
+	Timezone        sql.NullString  `db:"timezone" json:"timezone"`
+	Notify          json.RawMessage `db:"notify" json:"notify"`
+	ExecutorOptions json.RawMessage `db:"executor_options" json:"executor_options"`
+	CreatedAt       time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time       `db:"updated_at" json:"updated_at"`
+	MaxConcurrency  int32           `db:"max_concurrency" json:"max_concurrency"`
+}
+
+type NamespaceExecutionCount struct {
+	NamespaceID int32     `db:"namespace_id" json:"namespace_id"`
+	Day         time.Time `db:"day" json:"day"`
+	Executions  int32     `db:"executions" json:"executions"`
+}
+
+type NamespaceExecutorPolicy struct {
+	NamespaceID      int32     `db:"namespace_id" json:"namespace_id"`
+	AllowedExecutors []string  `db:"allowed_executors" json:"allowed_executors"`
+	CreatedAt        time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt        time.Time `db:"updated_at" json:"updated_at"`
+}
+
 type NamespaceMember struct {
 	ID          int32         `db:"id" json:"id"`
 	Uuid        uuid.UUID     `db:"uuid" json:"uuid"`
@@ -465,6 +791,16 @@ type NamespaceMember struct {
 	UpdatedAt   time.Time     `db:"updated_at" json:"updated_at"`
 }
 
+type NamespaceQuota struct {
+	NamespaceID             int32         `db:"namespace_id" json:"namespace_id"`
+	MaxExecutionsPerDay     sql.NullInt32 `db:"max_executions_per_day" json:"max_executions_per_day"`
+	MaxConcurrentExecutions sql.NullInt32 `db:"max_concurrent_executions" json:"max_concurrent_executions"`
+	MaxNodes                sql.NullInt32 `db:"max_nodes" json:"max_nodes"`
+	MaxLogBytes             sql.NullInt64 `db:"max_log_bytes" json:"max_log_bytes"`
+	CreatedAt               time.Time     `db:"created_at" json:"created_at"`
+	UpdatedAt               time.Time     `db:"updated_at" json:"updated_at"`
+}
+
 type NamespaceSecret struct {
 	ID             int32          `db:"id" json:"id"`
 	Uuid           uuid.UUID      `db:"uuid" json:"uuid"`
@@ -476,6 +812,15 @@ type NamespaceSecret struct {
 	UpdatedAt      time.Time      `db:"updated_at" json:"updated_at"`
 }
 
+type NamespaceVariable struct {
+	NamespaceID int32          `db:"namespace_id" json:"namespace_id"`
+	Key         string         `db:"key" json:"key"`
+	Value       string         `db:"value" json:"value"`
+	Description sql.NullString `db:"description" json:"description"`
+	CreatedAt   time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time      `db:"updated_at" json:"updated_at"`
+}
+
 type Node struct {
 	ID             int32                `db:"id" json:"id"`
 	Uuid           uuid.UUID            `db:"uuid" json:"uuid"`
@@ -493,6 +838,23 @@ type Node struct {
 	UpdatedAt      time.Time            `db:"updated_at" json:"updated_at"`
 }
 
+type NotificationDelivery struct {
+	ID             int32     `db:"id" json:"id"`
+	Uuid           uuid.UUID `db:"uuid" json:"uuid"`
+	NotificationID uuid.UUID `db:"notification_id" json:"notification_id"`
+	NamespaceID    int32     `db:"namespace_id" json:"namespace_id"`
+	ExecID         string    `db:"exec_id" json:"exec_id"`
+	FlowID         string    `db:"flow_id" json:"flow_id"`
+	Channel        string    `db:"channel" json:"channel"`
+	Receiver       string    `db:"receiver" json:"receiver"`
+	Event          string    `db:"event" json:"event"`
+	Status         string    `db:"status" json:"status"`
+	Attempts       int32     `db:"attempts" json:"attempts"`
+	LastError      string    `db:"last_error" json:"last_error"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time `db:"updated_at" json:"updated_at"`
+}
+
 type PrefixAccess struct {
 	ID          int32         `db:"id" json:"id"`
 	Uuid        uuid.UUID     `db:"uuid" json:"uuid"`
@@ -518,6 +880,27 @@ type Session struct {
 	CreatedAt time.Time       `db:"created_at" json:"created_at"`
 }
 
+type SlaBreach struct {
+	ID          int32     `db:"id" json:"id"`
+	ExecID      string    `db:"exec_id" json:"exec_id"`
+	NamespaceID int32     `db:"namespace_id" json:"namespace_id"`
+	FlowSlug    string    `db:"flow_slug" json:"flow_slug"`
+	Kind        string    `db:"kind" json:"kind"`
+	Reason      string    `db:"reason" json:"reason"`
+	DetectedAt  time.Time `db:"detected_at" json:"detected_at"`
+}
+
+type SubflowExecution struct {
+	ID             int32        `db:"id" json:"id"`
+	ParentExecID   string       `db:"parent_exec_id" json:"parent_exec_id"`
+	ParentActionID string       `db:"parent_action_id" json:"parent_action_id"`
+	ExecID         string       `db:"exec_id" json:"exec_id"`
+	NamespaceID    int32        `db:"namespace_id" json:"namespace_id"`
+	Depth          int32        `db:"depth" json:"depth"`
+	CreatedAt      time.Time    `db:"created_at" json:"created_at"`
+	ResolvedAt     sql.NullTime `db:"resolved_at" json:"resolved_at"`
+}
+
 type User struct {
 	ID        int32          `db:"id" json:"id"`
 	Uuid      uuid.UUID      `db:"uuid" json:"uuid"`
@@ -303,6 +303,108 @@ func (q *Queries) GetNodesByNames(ctx context.Context, arg GetNodesByNamesParams
 	return items, nil
 }
 
+const getNodesByNamesOrTagsInNamespaces = `-- name: GetNodesByNamesOrTagsInNamespaces :many
+WITH updated_credentials AS (
+    UPDATE credentials
+    SET last_accessed = NOW()
+    WHERE id IN (
+        SELECT DISTINCT n.credential_id
+        FROM nodes n
+        JOIN namespaces ns ON n.namespace_id = ns.id
+        WHERE ns.uuid = ANY($1::uuid[])
+          AND (n.name = ANY($2::text[]) OR n.tags && $3::text[])
+          AND n.credential_id IS NOT NULL
+    )
+    RETURNING id, uuid, name, key_type, key_data, namespace_id, last_accessed, created_at, updated_at
+)
+SELECT
+    n.id, n.uuid, n.name, n.hostname, n.port, n.username, n.os_family, n.tags, n.auth_method, n.connection_type, n.credential_id, n.namespace_id, n.created_at, n.updated_at,
+    ns.uuid AS namespace_uuid,
+    c.uuid AS credential_uuid,
+    c.name AS credential_name,
+    c.key_type AS credential_key_type,
+    c.key_data AS credential_key_data
+FROM nodes n
+JOIN namespaces ns ON n.namespace_id = ns.id
+LEFT JOIN credentials c ON n.credential_id = c.id
+WHERE ns.uuid = ANY($1::uuid[])
+  AND (n.name = ANY($2::text[]) OR n.tags && $3::text[])
+ORDER BY ns.id, n.name
+`
+
+type GetNodesByNamesOrTagsInNamespacesParams struct {
+	NamespaceUuids []uuid.UUID `db:"namespace_uuids" json:"namespace_uuids"`
+	Names          []string    `db:"names" json:"names"`
+	Tags           []string    `db:"tags" json:"tags"`
+}
+
+type GetNodesByNamesOrTagsInNamespacesRow struct {
+	ID                int32                `db:"id" json:"id"`
+	Uuid              uuid.UUID            `db:"uuid" json:"uuid"`
+	Name              string               `db:"name" json:"name"`
+	Hostname          string               `db:"hostname" json:"hostname"`
+	Port              int32                `db:"port" json:"port"`
+	Username          string               `db:"username" json:"username"`
+	OsFamily          string               `db:"os_family" json:"os_family"`
+	Tags              []string             `db:"tags" json:"tags"`
+	AuthMethod        AuthenticationMethod `db:"auth_method" json:"auth_method"`
+	ConnectionType    ConnectionType       `db:"connection_type" json:"connection_type"`
+	CredentialID      sql.NullInt32        `db:"credential_id" json:"credential_id"`
+	NamespaceID       int32                `db:"namespace_id" json:"namespace_id"`
+	CreatedAt         time.Time            `db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time            `db:"updated_at" json:"updated_at"`
+	NamespaceUuid     uuid.UUID            `db:"namespace_uuid" json:"namespace_uuid"`
+	CredentialUuid    uuid.NullUUID        `db:"credential_uuid" json:"credential_uuid"`
+	CredentialName    sql.NullString       `db:"credential_name" json:"credential_name"`
+	CredentialKeyType sql.NullString       `db:"credential_key_type" json:"credential_key_type"`
+	CredentialKeyData sql.NullString       `db:"credential_key_data" json:"credential_key_data"`
+}
+
+// GetNodesByNamesOrTagsInNamespaces returns the nodes of any of the namespaces that have one of the
+// names or tags, to resolve the nodes of many flows at once
+func (q *Queries) GetNodesByNamesOrTagsInNamespaces(ctx context.Context, arg GetNodesByNamesOrTagsInNamespacesParams) ([]GetNodesByNamesOrTagsInNamespacesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getNodesByNamesOrTagsInNamespaces, pq.Array(arg.NamespaceUuids), pq.Array(arg.Names), pq.Array(arg.Tags))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetNodesByNamesOrTagsInNamespacesRow
+	for rows.Next() {
+		var i GetNodesByNamesOrTagsInNamespacesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Uuid,
+			&i.Name,
+			&i.Hostname,
+			&i.Port,
+			&i.Username,
+			&i.OsFamily,
+			pq.Array(&i.Tags),
+			&i.AuthMethod,
+			&i.ConnectionType,
+			&i.CredentialID,
+			&i.NamespaceID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.NamespaceUuid,
+			&i.CredentialUuid,
+			&i.CredentialName,
+			&i.CredentialKeyType,
+			&i.CredentialKeyData,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getNodesByTags = `-- name: GetNodesByTags :many
 WITH updated_credentials AS (
     UPDATE credentials
@@ -399,6 +501,47 @@ func (q *Queries) GetNodesByTags(ctx context.Context, arg GetNodesByTagsParams)
 	return items, nil
 }
 
+const listNodeAddresses = `-- name: ListNodeAddresses :many
+SELECT ns.name AS namespace_name, n.name, n.hostname, n.port FROM nodes n
+JOIN namespaces ns ON n.namespace_id = ns.id
+ORDER BY ns.name, n.name
+`
+
+type ListNodeAddressesRow struct {
+	NamespaceName string `db:"namespace_name" json:"namespace_name"`
+	Name          string `db:"name" json:"name"`
+	Hostname      string `db:"hostname" json:"hostname"`
+	Port          int32  `db:"port" json:"port"`
+}
+
+func (q *Queries) ListNodeAddresses(ctx context.Context) ([]ListNodeAddressesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listNodeAddresses)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListNodeAddressesRow
+	for rows.Next() {
+		var i ListNodeAddressesRow
+		if err := rows.Scan(
+			&i.NamespaceName,
+			&i.Name,
+			&i.Hostname,
+			&i.Port,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const searchNodes = `-- name: SearchNodes :many
 WITH filtered AS (
     SELECT n.id, n.uuid, n.name, n.hostname, n.port, n.username, n.os_family, n.tags, n.auth_method, n.connection_type, n.credential_id, n.namespace_id, n.created_at, n.updated_at, ns.uuid AS namespace_uuid FROM nodes n
@@ -0,0 +1,76 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// QueryObserver is called with the database a query ran on, the name of the query and how long
+// the database took to respond
+type QueryObserver func(dbName string, query string, duration time.Duration)
+
+// StoreOption configures a PostgresStore
+type StoreOption func(*storeOptions)
+
+type storeOptions struct {
+	observe QueryObserver
+}
+
+// WithQueryObserver reports the duration of every query run by the store to observe, including
+// the queries of its transactions
+func WithQueryObserver(observe QueryObserver) StoreOption {
+	return func(o *storeOptions) {
+		o.observe = observe
+	}
+}
+
+// observedDB times the queries run on db. Statements that are prepared are not timed.
+type observedDB struct {
+	db      DBTX
+	name    string
+	observe QueryObserver
+}
+
+// observeDB returns db as is if there is no observer
+func observeDB(db DBTX, name string, observe QueryObserver) DBTX {
+	if observe == nil {
+		return db
+	}
+	return &observedDB{db: db, name: name, observe: observe}
+}
+
+func (o *observedDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	defer o.record(query, time.Now())
+	return o.db.ExecContext(ctx, query, args...)
+}
+
+func (o *observedDB) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return o.db.PrepareContext(ctx, query)
+}
+
+func (o *observedDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	defer o.record(query, time.Now())
+	return o.db.QueryContext(ctx, query, args...)
+}
+
+func (o *observedDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	defer o.record(query, time.Now())
+	return o.db.QueryRowContext(ctx, query, args...)
+}
+
+func (o *observedDB) record(query string, start time.Time) {
+	o.observe(o.name, QueryName(query), time.Since(start))
+}
+
+// QueryName returns the name of a query generated by sqlc, which starts with a "-- name:"
+// comment. Other queries are named "other".
+func QueryName(query string) string {
+	rest, ok := strings.CutPrefix(query, "-- name: ")
+	if !ok {
+		return "other"
+	}
+	name, _, _ := strings.Cut(rest, " ")
+	return name
+}
@@ -0,0 +1,19 @@
+package repo
+
+import "testing"
+
+func TestQueryName(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{updateExecutionStatus, "UpdateExecutionStatus"},
+		{recordAuditEntry, "RecordAuditEntry"},
+		{"SELECT 1", "other"},
+	}
+	for _, tt := range tests {
+		if got := QueryName(tt.query); got != tt.want {
+			t.Errorf("QueryName() = %q, want %q", got, tt.want)
+		}
+	}
+}
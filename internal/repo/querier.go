@@ -15,37 +15,86 @@ import (
 
 type Querier interface {
 	AccessCredential(ctx context.Context, arg AccessCredentialParams) (Credential, error)
+	// Takes a lock for an execution. A lock held by an execution that is no longer running is taken
+	// over, so that locks of executions that stopped without releasing them don't block forever.
+	// No row is returned if another running execution holds the lock.
+	AcquireExecutionLock(ctx context.Context, arg AcquireExecutionLockParams) (ExecutionLock, error)
 	AddApprovalRequest(ctx context.Context, arg AddApprovalRequestParams) (AddApprovalRequestRow, error)
+	AddExecutionActionSkip(ctx context.Context, arg AddExecutionActionSkipParams) (ExecutionActionSkip, error)
 	AddExecutionLog(ctx context.Context, arg AddExecutionLogParams) (ExecutionLog, error)
+	AddExecutionLogBytes(ctx context.Context, arg AddExecutionLogBytesParams) error
+	AddExecutionWatch(ctx context.Context, arg AddExecutionWatchParams) error
+	AddFlowFavorite(ctx context.Context, arg AddFlowFavoriteParams) error
 	AddGroupToUserByUUID(ctx context.Context, arg AddGroupToUserByUUIDParams) error
+	AdvisoryUnlock(ctx context.Context, key int64) (bool, error)
 	ApproveRequestByUUID(ctx context.Context, arg ApproveRequestByUUIDParams) (ApproveRequestByUUIDRow, error)
+	ArchiveExecutions(ctx context.Context, arg ArchiveExecutionsParams) (int64, error)
 	AssignGroupNamespaceRole(ctx context.Context, arg AssignGroupNamespaceRoleParams) (NamespaceMember, error)
 	AssignGroupPrefixAccess(ctx context.Context, arg AssignGroupPrefixAccessParams) error
 	AssignUserNamespaceRole(ctx context.Context, arg AssignUserNamespaceRoleParams) (NamespaceMember, error)
 	AssignUserPrefixAccess(ctx context.Context, arg AssignUserPrefixAccessParams) error
+	CancelManualTasksForExec(ctx context.Context, arg CancelManualTasksForExecParams) error
 	CancelTasksByExecID(ctx context.Context, execID string) error
+	ClearFlowImportErrors(ctx context.Context, namespaceID int32) error
+	// Completes a pending task. No row is returned if the task was already completed or cancelled.
+	CompleteManualTask(ctx context.Context, arg CompleteManualTaskParams) (ManualTask, error)
+	// Counts the slots held in the namespace and by executions of a flow, other than the slot of
+	// exec_id. Slots of executions that finished without releasing them are not counted.
+	CountExecutionSlots(ctx context.Context, arg CountExecutionSlotsParams) (CountExecutionSlotsRow, error)
+	// Counts an execution started in the namespace today, unless max_executions were already started.
+	// No row is returned when the limit has been reached.
+	CountNamespaceExecution(ctx context.Context, arg CountNamespaceExecutionParams) (int32, error)
+	CreateAPIToken(ctx context.Context, arg CreateAPITokenParams) (ApiToken, error)
+	CreateApprovalDelegation(ctx context.Context, arg CreateApprovalDelegationParams) (ApprovalDelegation, error)
 	CreateCredential(ctx context.Context, arg CreateCredentialParams) (Credential, error)
 	CreateCronSchedule(ctx context.Context, arg CreateCronScheduleParams) (CronSchedule, error)
 	CreateFlow(ctx context.Context, arg CreateFlowParams) (Flow, error)
+	CreateFlowImportError(ctx context.Context, arg CreateFlowImportErrorParams) error
 	CreateFlowPrefix(ctx context.Context, arg CreateFlowPrefixParams) (FlowPrefix, error)
+	CreateFlowPromotion(ctx context.Context, arg CreateFlowPromotionParams) (FlowPromotion, error)
+	CreateFlowRevision(ctx context.Context, arg CreateFlowRevisionParams) (FlowRevision, error)
+	// Indexes flows that were created before search was added when they are next loaded
+	CreateFlowSearchIfMissing(ctx context.Context, arg CreateFlowSearchIfMissingParams) error
 	CreateFlowSecret(ctx context.Context, arg CreateFlowSecretParams) (FlowSecret, error)
+	// Records an execution queued by a trigger. No row is returned if the upstream execution
+	// already triggered the flow.
+	CreateFlowTriggerRun(ctx context.Context, arg CreateFlowTriggerRunParams) (FlowTriggerRun, error)
+	CreateFlowVersion(ctx context.Context, arg CreateFlowVersionParams) (FlowVersion, error)
 	CreateGroup(ctx context.Context, arg CreateGroupParams) (Group, error)
 	CreateNamespace(ctx context.Context, name string) (Namespace, error)
 	CreateNamespaceSecret(ctx context.Context, arg CreateNamespaceSecretParams) (NamespaceSecret, error)
 	CreateNode(ctx context.Context, arg CreateNodeParams) (Node, error)
+	CreateNodeTelemetrySample(ctx context.Context, arg CreateNodeTelemetrySampleParams) error
+	CreateSLABreach(ctx context.Context, arg CreateSLABreachParams) (int64, error)
 	// Immediate task operations
 	CreateSchedulerTask(ctx context.Context, arg CreateSchedulerTaskParams) (SchedulerTask, error)
+	// Records an execution started by the flow action of a parent execution. No row is returned if
+	// the action already waits on an execution.
+	CreateSubflowExecution(ctx context.Context, arg CreateSubflowExecutionParams) (SubflowExecution, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
 	CreateUserSchedule(ctx context.Context, arg CreateUserScheduleParams) (CronSchedule, error)
+	DeleteAPITokenByUser(ctx context.Context, arg DeleteAPITokenByUserParams) (int64, error)
 	DeleteAllFlows(ctx context.Context) error
+	DeleteApprovalDelegation(ctx context.Context, arg DeleteApprovalDelegationParams) (ApprovalDelegation, error)
 	DeleteCredential(ctx context.Context, arg DeleteCredentialParams) error
 	DeleteFlow(ctx context.Context, arg DeleteFlowParams) error
 	DeleteFlowPrefix(ctx context.Context, arg DeleteFlowPrefixParams) error
 	DeleteFlowSecret(ctx context.Context, arg DeleteFlowSecretParams) error
+	DeleteFlowTriggerRun(ctx context.Context, execID string) error
+	DeleteFlowWebhook(ctx context.Context, flowID int32) (int64, error)
+	DeleteGlobalVariable(ctx context.Context, key string) (int64, error)
 	DeleteGroupByUUID(ctx context.Context, argUuid uuid.UUID) error
+	DeleteInputPreset(ctx context.Context, arg DeleteInputPresetParams) error
+	DeleteMessengerConfig(ctx context.Context, channel string) (MessengerConfig, error)
 	DeleteNamespace(ctx context.Context, argUuid uuid.UUID) error
+	DeleteNamespaceDefaults(ctx context.Context, argUuid uuid.UUID) error
+	DeleteNamespaceExecutorPolicy(ctx context.Context, argUuid uuid.UUID) error
+	DeleteNamespaceQuota(ctx context.Context, argUuid uuid.UUID) error
 	DeleteNamespaceSecret(ctx context.Context, arg DeleteNamespaceSecretParams) error
+	DeleteNamespaceVariable(ctx context.Context, arg DeleteNamespaceVariableParams) (int64, error)
 	DeleteNode(ctx context.Context, arg DeleteNodeParams) error
+	DeleteRetentionPolicy(ctx context.Context, argUuid uuid.UUID) error
+	DeleteSubflowExecution(ctx context.Context, execID string) error
 	DeleteSystemCronsByFlowID(ctx context.Context, flowID int32) error
 	DeleteUserByUUID(ctx context.Context, argUuid uuid.UUID) error
 	// DELETE FROM cron_schedules cs
@@ -58,6 +107,16 @@ type Querier interface {
 	DeleteUserScheduleByUUID(ctx context.Context, arg DeleteUserScheduleByUUIDParams) (int64, error)
 	DisableUserSchedulesForFlow(ctx context.Context, flowID int32) error
 	ExecutionExistsForFlow(ctx context.Context, arg ExecutionExistsForFlowParams) (bool, error)
+	FinishExecutionTimelineEntry(ctx context.Context, arg FinishExecutionTimelineEntryParams) error
+	GetAPITokenOwnerByHash(ctx context.Context, tokenHash string) (GetAPITokenOwnerByHashRow, error)
+	// Median duration of each action of a flow over its last completed executions. Only the attempts
+	// that succeeded are counted.
+	GetActionDurationEstimates(ctx context.Context, arg GetActionDurationEstimatesParams) ([]GetActionDurationEstimatesRow, error)
+	// Returns the emails of users that currently act on behalf of the given delegators in a namespace
+	GetActiveDelegateEmails(ctx context.Context, arg GetActiveDelegateEmailsParams) ([]string, error)
+	// Returns the users who have delegated their approval rights in the namespace
+	// to the given user, either directly or through one of the user's groups
+	GetActiveDelegatorsForUser(ctx context.Context, arg GetActiveDelegatorsForUserParams) ([]uuid.UUID, error)
 	GetAllCronSchedules(ctx context.Context) ([]GetAllCronSchedulesRow, error)
 	GetAllExecutionsPaginated(ctx context.Context, arg GetAllExecutionsPaginatedParams) ([]GetAllExecutionsPaginatedRow, error)
 	GetAllGroups(ctx context.Context) ([]Group, error)
@@ -71,25 +130,38 @@ type Querier interface {
 	GetApprovalRequestForExec(ctx context.Context, arg GetApprovalRequestForExecParams) (GetApprovalRequestForExecRow, error)
 	GetApprovalWithInputsByUUID(ctx context.Context, arg GetApprovalWithInputsByUUIDParams) (GetApprovalWithInputsByUUIDRow, error)
 	GetApprovalsPaginated(ctx context.Context, arg GetApprovalsPaginatedParams) ([]GetApprovalsPaginatedRow, error)
+	GetArchivedExecution(ctx context.Context, arg GetArchivedExecutionParams) (ExecutionArchive, error)
 	GetCredentialByID(ctx context.Context, arg GetCredentialByIDParams) (GetCredentialByIDRow, error)
+	GetCredentialByName(ctx context.Context, arg GetCredentialByNameParams) (GetCredentialByNameRow, error)
 	GetCredentialByUUID(ctx context.Context, arg GetCredentialByUUIDParams) (GetCredentialByUUIDRow, error)
 	GetCronSchedulesByFlowID(ctx context.Context, flowID int32) ([]CronSchedule, error)
 	// Used internally for execution - returns all secrets for a namespace
 	GetDecryptedNamespaceSecrets(ctx context.Context, argUuid uuid.UUID) ([]GetDecryptedNamespaceSecretsRow, error)
+	GetDeliveredReceivers(ctx context.Context, notificationID uuid.UUID) ([]string, error)
 	GetDistinctPrefixes(ctx context.Context, argUuid uuid.UUID) ([]GetDistinctPrefixesRow, error)
 	GetExecutionActionRetries(ctx context.Context, arg GetExecutionActionRetriesParams) (pqtype.NullRawMessage, error)
+	GetExecutionArtifact(ctx context.Context, arg GetExecutionArtifactParams) (Artifact, error)
 	GetExecutionByExecID(ctx context.Context, arg GetExecutionByExecIDParams) (GetExecutionByExecIDRow, error)
 	GetExecutionByExecIDWithNamespace(ctx context.Context, arg GetExecutionByExecIDWithNamespaceParams) (GetExecutionByExecIDWithNamespaceRow, error)
 	GetExecutionByID(ctx context.Context, arg GetExecutionByIDParams) (GetExecutionByIDRow, error)
+	GetExecutionLock(ctx context.Context, arg GetExecutionLockParams) (ExecutionLock, error)
+	GetExecutionProgress(ctx context.Context, arg GetExecutionProgressParams) (json.RawMessage, error)
+	// Latest status of the given executions, live or archived. Executions that were purged are not returned.
+	GetExecutionStatuses(ctx context.Context, execIds []string) ([]GetExecutionStatusesRow, error)
 	GetExecutionsByFlow(ctx context.Context, arg GetExecutionsByFlowParams) ([]GetExecutionsByFlowRow, error)
 	GetExecutionsByFlowPaginated(ctx context.Context, arg GetExecutionsByFlowPaginatedParams) ([]GetExecutionsByFlowPaginatedRow, error)
+	GetExecutionsForLogSearch(ctx context.Context, arg GetExecutionsForLogSearchParams) ([]GetExecutionsForLogSearchRow, error)
 	GetFlowBySlug(ctx context.Context, arg GetFlowBySlugParams) (Flow, error)
 	GetFlowCountByPrefix(ctx context.Context, prefixID sql.NullInt32) (int64, error)
 	GetFlowFromExecID(ctx context.Context, arg GetFlowFromExecIDParams) (Flow, error)
 	GetFlowFromExecIDWithNamespace(ctx context.Context, arg GetFlowFromExecIDWithNamespaceParams) (Flow, error)
 	GetFlowPrefixByName(ctx context.Context, arg GetFlowPrefixByNameParams) (FlowPrefix, error)
 	GetFlowPrefixByUUID(ctx context.Context, arg GetFlowPrefixByUUIDParams) (FlowPrefix, error)
+	GetFlowRevisionByUUID(ctx context.Context, arg GetFlowRevisionByUUIDParams) (GetFlowRevisionByUUIDRow, error)
 	GetFlowSecretByUUID(ctx context.Context, arg GetFlowSecretByUUIDParams) (GetFlowSecretByUUIDRow, error)
+	GetFlowTriggerRun(ctx context.Context, execID string) (FlowTriggerRun, error)
+	GetFlowVersion(ctx context.Context, arg GetFlowVersionParams) (FlowVersion, error)
+	GetFlowWebhook(ctx context.Context, flowID int32) (FlowWebhook, error)
 	GetFlowsByNamespace(ctx context.Context, argUuid uuid.UUID) ([]GetFlowsByNamespaceRow, error)
 	GetFlowsByPrefix(ctx context.Context, arg GetFlowsByPrefixParams) ([]GetFlowsByPrefixRow, error)
 	GetFlowsByPrefixUUID(ctx context.Context, arg GetFlowsByPrefixUUIDParams) ([]GetFlowsByPrefixUUIDRow, error)
@@ -99,22 +171,43 @@ type Querier interface {
 	GetGroupByUUIDWithUsers(ctx context.Context, argUuid uuid.UUID) (GroupView, error)
 	GetGroupMembersByName(ctx context.Context, name string) ([]GetGroupMembersByNameRow, error)
 	GetInputForExecByUUID(ctx context.Context, arg GetInputForExecByUUIDParams) (json.RawMessage, error)
+	// Gets a preset of a flow a user can use by name. The user's own preset takes precedence over
+	// shared presets with the same name.
+	GetInputPresetByName(ctx context.Context, arg GetInputPresetByNameParams) (GetInputPresetByNameRow, error)
+	GetInputPresetByUUID(ctx context.Context, arg GetInputPresetByUUIDParams) (GetInputPresetByUUIDRow, error)
+	GetLatestFlowVersion(ctx context.Context, flowID int32) (FlowVersion, error)
+	GetManualTaskByUUID(ctx context.Context, arg GetManualTaskByUUIDParams) (GetManualTaskByUUIDRow, error)
+	GetManualTaskForAction(ctx context.Context, arg GetManualTaskForActionParams) (ManualTask, error)
 	GetMemberPrefixes(ctx context.Context, arg GetMemberPrefixesParams) ([]GetMemberPrefixesRow, error)
 	GetNamespaceByName(ctx context.Context, name string) (Namespace, error)
 	GetNamespaceByUUID(ctx context.Context, argUuid uuid.UUID) (Namespace, error)
+	GetNamespaceDefaults(ctx context.Context, argUuid uuid.UUID) (NamespaceDefault, error)
+	GetNamespaceExecutorPolicy(ctx context.Context, argUuid uuid.UUID) (NamespaceExecutorPolicy, error)
 	GetNamespaceMemberByUUID(ctx context.Context, arg GetNamespaceMemberByUUIDParams) (GetNamespaceMemberByUUIDRow, error)
 	GetNamespaceMembers(ctx context.Context, argUuid uuid.UUID) ([]GetNamespaceMembersRow, error)
+	GetNamespaceQuota(ctx context.Context, argUuid uuid.UUID) (NamespaceQuota, error)
 	GetNamespaceSecretByUUID(ctx context.Context, arg GetNamespaceSecretByUUIDParams) (GetNamespaceSecretByUUIDRow, error)
+	// Concurrent executions are those whose latest version is pending or running, not counting
+	// executions scheduled to run later
+	GetNamespaceUsage(ctx context.Context, argUuid uuid.UUID) (GetNamespaceUsageRow, error)
 	GetNodeByName(ctx context.Context, arg GetNodeByNameParams) (GetNodeByNameRow, error)
 	GetNodeByUUID(ctx context.Context, arg GetNodeByUUIDParams) (GetNodeByUUIDRow, error)
 	GetNodeStats(ctx context.Context, argUuid uuid.UUID) (GetNodeStatsRow, error)
 	GetNodesByNames(ctx context.Context, arg GetNodesByNamesParams) ([]GetNodesByNamesRow, error)
+	// GetNodesByNamesOrTagsInNamespaces returns the nodes of any of the namespaces that have one of the
+	// names or tags, to resolve the nodes of many flows at once
+	GetNodesByNamesOrTagsInNamespaces(ctx context.Context, arg GetNodesByNamesOrTagsInNamespacesParams) ([]GetNodesByNamesOrTagsInNamespacesRow, error)
 	GetNodesByTags(ctx context.Context, arg GetNodesByTagsParams) ([]GetNodesByTagsRow, error)
+	GetPendingFlowRevision(ctx context.Context, flowID int32) (FlowRevision, error)
 	GetPendingTasks(ctx context.Context, limit int32) ([]SchedulerTask, error)
 	GetPrefixMembers(ctx context.Context, arg GetPrefixMembersParams) ([]GetPrefixMembersRow, error)
+	GetRetentionPolicy(ctx context.Context, argUuid uuid.UUID) (ExecutionRetentionPolicy, error)
 	GetScheduleByFlowAndCron(ctx context.Context, arg GetScheduleByFlowAndCronParams) (CronSchedule, error)
 	GetScheduledExecutionsByFlow(ctx context.Context, arg GetScheduledExecutionsByFlowParams) ([]GetScheduledExecutionsByFlowRow, error)
 	GetScheduledFlows(ctx context.Context) ([]GetScheduledFlowsRow, error)
+	GetSubflowExecution(ctx context.Context, execID string) (SubflowExecution, error)
+	// Returns the execution the flow action of a parent execution waits on.
+	GetUnresolvedSubflowExecution(ctx context.Context, arg GetUnresolvedSubflowExecutionParams) (SubflowExecution, error)
 	GetUserAccessiblePrefixes(ctx context.Context, arg GetUserAccessiblePrefixesParams) ([]string, error)
 	GetUserByID(ctx context.Context, id int32) (User, error)
 	GetUserByUUID(ctx context.Context, argUuid uuid.UUID) (User, error)
@@ -138,19 +231,99 @@ type Querier interface {
 	GetUserScheduleByUUID(ctx context.Context, arg GetUserScheduleByUUIDParams) (GetUserScheduleByUUIDRow, error)
 	GetUsersByRole(ctx context.Context, role UserRoleType) ([]User, error)
 	IncrementActionRetry(ctx context.Context, arg IncrementActionRetryParams) (IncrementActionRetryRow, error)
+	ListAPITokensByUser(ctx context.Context, argUuid uuid.UUID) ([]ApiToken, error)
+	ListActiveExecutionsForSLA(ctx context.Context) ([]ListActiveExecutionsForSLARow, error)
+	ListApprovalDelegations(ctx context.Context, argUuid uuid.UUID) ([]ListApprovalDelegationsRow, error)
+	ListArchivedExecutionsPaginated(ctx context.Context, arg ListArchivedExecutionsPaginatedParams) ([]ListArchivedExecutionsPaginatedRow, error)
+	// Lists the entries of a namespace, or of every namespace and the changes outside of namespaces
+	// if namespace_uuid is NULL, newest first
+	ListAuditEntriesPaginated(ctx context.Context, arg ListAuditEntriesPaginatedParams) ([]ListAuditEntriesPaginatedRow, error)
+	ListCronScheduleRuns(ctx context.Context, argUuid uuid.UUID) ([]ListCronScheduleRunsRow, error)
+	ListExecutionActionSkips(ctx context.Context, arg ListExecutionActionSkipsParams) ([]ListExecutionActionSkipsRow, error)
+	ListExecutionArtifacts(ctx context.Context, arg ListExecutionArtifactsParams) ([]Artifact, error)
+	ListExecutionEnvironments(ctx context.Context, arg ListExecutionEnvironmentsParams) ([]ExecutionEnvironment, error)
+	ListExecutionOutputs(ctx context.Context, arg ListExecutionOutputsParams) ([]ListExecutionOutputsRow, error)
+	ListExecutionTelemetry(ctx context.Context, arg ListExecutionTelemetryParams) ([]ExecutionNodeTelemetry, error)
+	ListExecutionTimeline(ctx context.Context, arg ListExecutionTimelineParams) ([]ExecutionTimeline, error)
+	// Lists the usernames of the users watching an execution or its flow that are still superusers
+	// or members of the namespace
+	ListExecutionWatchers(ctx context.Context, arg ListExecutionWatchersParams) ([]string, error)
+	// Lists the flows and executions of a namespace watched by a user, last watched first
+	ListExecutionWatches(ctx context.Context, arg ListExecutionWatchesParams) ([]ListExecutionWatchesRow, error)
+	// Finished executions of a namespace, live or archived, that are older than before or
+	// are not among the keep_last most recent executions
+	ListExpiredExecutions(ctx context.Context, arg ListExpiredExecutionsParams) ([]ListExpiredExecutionsRow, error)
+	ListFailedExecutions(ctx context.Context, arg ListFailedExecutionsParams) ([]ListFailedExecutionsRow, error)
+	ListFailedNodes(ctx context.Context, arg ListFailedNodesParams) ([]ListFailedNodesRow, error)
+	// Lists the active flows a user starred in a namespace, limited to the flow groups in prefixes unless full_access is set
+	ListFlowFavorites(ctx context.Context, arg ListFlowFavoritesParams) ([]ListFlowFavoritesRow, error)
+	ListFlowImportErrors(ctx context.Context, argUuid uuid.UUID) ([]FlowImportError, error)
 	ListFlowPrefixes(ctx context.Context, argUuid uuid.UUID) ([]FlowPrefix, error)
+	// Lists the promotions a flow was the source or the target of, newest first
+	ListFlowPromotions(ctx context.Context, flowID int32) ([]ListFlowPromotionsRow, error)
+	ListFlowRevisions(ctx context.Context, arg ListFlowRevisionsParams) ([]ListFlowRevisionsRow, error)
 	ListFlowSecrets(ctx context.Context, arg ListFlowSecretsParams) ([]ListFlowSecretsRow, error)
+	ListFlowVersions(ctx context.Context, arg ListFlowVersionsParams) ([]ListFlowVersionsRow, error)
 	ListFlows(ctx context.Context, arg ListFlowsParams) ([]ListFlowsRow, error)
 	ListFlowsPaginated(ctx context.Context, arg ListFlowsPaginatedParams) ([]ListFlowsPaginatedRow, error)
 	ListFlowsPaginatedFiltered(ctx context.Context, arg ListFlowsPaginatedFilteredParams) ([]ListFlowsPaginatedFilteredRow, error)
+	ListGlobalVariables(ctx context.Context) ([]GlobalVariable, error)
+	// Lists the presets of a flow a user can use, their own and the shared ones, by name
+	ListInputPresets(ctx context.Context, arg ListInputPresetsParams) ([]ListInputPresetsRow, error)
+	ListManualTasks(ctx context.Context, arg ListManualTasksParams) ([]ListManualTasksRow, error)
+	ListMessengerConfigs(ctx context.Context) ([]MessengerConfig, error)
+	// Lists who can own the flows of a namespace: superusers, users that are members directly or
+	// through a group, by username, and groups that are members, by name
+	ListNamespaceOwnerCandidates(ctx context.Context, argUuid uuid.UUID) ([]ListNamespaceOwnerCandidatesRow, error)
+	// Lists the active schedules of the active flows of a namespace
+	ListNamespaceSchedules(ctx context.Context, argUuid uuid.UUID) ([]ListNamespaceSchedulesRow, error)
 	ListNamespaceSecrets(ctx context.Context, argUuid uuid.UUID) ([]ListNamespaceSecretsRow, error)
+	ListNamespaceVariables(ctx context.Context, argUuid uuid.UUID) ([]NamespaceVariable, error)
 	ListNamespaces(ctx context.Context, arg ListNamespacesParams) ([]ListNamespacesRow, error)
+	ListNodeAddresses(ctx context.Context) ([]ListNodeAddressesRow, error)
+	ListNotificationDeliveriesPaginated(ctx context.Context, arg ListNotificationDeliveriesPaginatedParams) ([]ListNotificationDeliveriesPaginatedRow, error)
+	// Lists the active flows a user triggered in a namespace, last run first, limited to the flow groups in prefixes unless full_access is set
+	ListRecentFlowsByUser(ctx context.Context, arg ListRecentFlowsByUserParams) ([]ListRecentFlowsByUserRow, error)
+	ListRetentionPolicies(ctx context.Context) ([]ListRetentionPoliciesRow, error)
+	// ListScheduledFlowJobs returns the active schedules of active flows with their namespace and the
+	// user that created them, so scheduled jobs can be built without a query per schedule
+	ListScheduledFlowJobs(ctx context.Context) ([]ListScheduledFlowJobsRow, error)
 	ListSchedules(ctx context.Context, arg ListSchedulesParams) ([]ListSchedulesRow, error)
+	// Lists the executions started by the flow actions of an execution with their latest status.
+	ListSubflowExecutions(ctx context.Context, arg ListSubflowExecutionsParams) ([]ListSubflowExecutionsRow, error)
+	// Serializes taking the slots of a namespace until the end of the transaction, so that servers
+	// picking jobs at the same time can't take more slots than the limits allow.
+	LockExecutionSlots(ctx context.Context, argUuid uuid.UUID) error
 	MarkAllFlowsInactiveForNamespace(ctx context.Context, argUuid uuid.UUID) error
+	// MarkExecutionRunning sets the status of an execution to running and records when it first
+	// started, replacing separate status and started_at updates
+	MarkExecutionRunning(ctx context.Context, arg MarkExecutionRunningParams) error
 	MarkFlowActive(ctx context.Context, arg MarkFlowActiveParams) error
+	// Removes every record of the given executions. Approvals are removed with the execution_log rows.
+	PurgeExecutions(ctx context.Context, execIds []string) error
+	// Records a file published by an action. The files of a retried action replace the ones of the
+	// earlier attempt.
+	RecordArtifact(ctx context.Context, arg RecordArtifactParams) (Artifact, error)
+	RecordAuditEntry(ctx context.Context, arg RecordAuditEntryParams) error
+	RecordCronScheduleRun(ctx context.Context, arg RecordCronScheduleRunParams) error
+	// Records the results of an action. The results of a retried action replace the ones of the
+	// earlier attempt.
+	RecordExecutionOutputs(ctx context.Context, arg RecordExecutionOutputsParams) error
+	// Records the entries of the nodes running an action in a single statement. Entries carry their own
+	// start and finish times so that they can be buffered and written again as they change.
+	RecordExecutionTimelineEntries(ctx context.Context, arg RecordExecutionTimelineEntriesParams) error
 	RejectRequestByUUID(ctx context.Context, arg RejectRequestByUUIDParams) (RejectRequestByUUIDRow, error)
+	ReleaseExecutionLock(ctx context.Context, arg ReleaseExecutionLockParams) error
+	ReleaseExecutionSlot(ctx context.Context, execID string) error
 	RemoveAllGroupsForUserByUUID(ctx context.Context, userUuid uuid.UUID) error
+	RemoveExecutionWatch(ctx context.Context, arg RemoveExecutionWatchParams) error
+	RemoveFlowFavorite(ctx context.Context, arg RemoveFlowFavoriteParams) error
 	RemoveNamespaceMember(ctx context.Context, arg RemoveNamespaceMemberParams) (NamespaceMember, error)
+	// Creates the task of a manual action. A cancelled task is requested again when its execution
+	// is retried. No row is returned if the task is pending or completed.
+	RequestManualTask(ctx context.Context, arg RequestManualTaskParams) (ManualTask, error)
+	ResolveSubflowExecution(ctx context.Context, id int32) error
+	ReviewFlowRevision(ctx context.Context, arg ReviewFlowRevisionParams) (FlowRevision, error)
 	RevokeAllMemberPrefixAccess(ctx context.Context, arg RevokeAllMemberPrefixAccessParams) error
 	RevokeGroupPrefixAccess(ctx context.Context, arg RevokeGroupPrefixAccessParams) error
 	RevokeUserPrefixAccess(ctx context.Context, arg RevokeUserPrefixAccessParams) error
@@ -161,6 +334,19 @@ type Querier interface {
 	SearchGroup(ctx context.Context, arg SearchGroupParams) ([]SearchGroupRow, error)
 	SearchNodes(ctx context.Context, arg SearchNodesParams) ([]SearchNodesRow, error)
 	SearchUsersWithGroups(ctx context.Context, arg SearchUsersWithGroupsParams) ([]SearchUsersWithGroupsRow, error)
+	// StartExecutionAction sets the current action of an execution and increments its retry count
+	// in a single round trip
+	StartExecutionAction(ctx context.Context, arg StartExecutionActionParams) (int32, error)
+	StartExecutionTimelineEntry(ctx context.Context, arg StartExecutionTimelineEntryParams) error
+	// Same selection as ListExpiredExecutions, grouped by status
+	SummarizeExpiredExecutions(ctx context.Context, arg SummarizeExpiredExecutionsParams) ([]SummarizeExpiredExecutionsRow, error)
+	SupersedePendingFlowRevisions(ctx context.Context, flowID int32) error
+	// An execution that is picked again, for example after a handoff, keeps its slot
+	TakeExecutionSlot(ctx context.Context, arg TakeExecutionSlotParams) error
+	// Takes a session level advisory lock without waiting. The lock is held until it is released
+	// or the connection is closed, so it must be taken and released on the same connection.
+	TouchFlowWebhook(ctx context.Context, flowID int32) error
+	TryAdvisoryLock(ctx context.Context, key int64) (bool, error)
 	UpdateApprovalStatusByUUID(ctx context.Context, arg UpdateApprovalStatusByUUIDParams) (UpdateApprovalStatusByUUIDRow, error)
 	UpdateCredential(ctx context.Context, arg UpdateCredentialParams) (Credential, error)
 	UpdateExecutionActionID(ctx context.Context, arg UpdateExecutionActionIDParams) (ExecutionLog, error)
@@ -193,6 +379,21 @@ type Querier interface {
 	//   AND cs.created_by = (SELECT id FROM users WHERE users.uuid = $6)
 	// RETURNING cs.*;
 	UpdateUserScheduleByUUID(ctx context.Context, arg UpdateUserScheduleByUUIDParams) (CronSchedule, error)
+	// The checksum is the one of the flow when the action ran
+	UpsertExecutionEnvironment(ctx context.Context, arg UpsertExecutionEnvironmentParams) error
+	UpsertExecutionProgress(ctx context.Context, arg UpsertExecutionProgressParams) error
+	UpsertFlowSearch(ctx context.Context, arg UpsertFlowSearchParams) error
+	// Creates the webhook of a flow, or replaces the token and secret of its existing webhook
+	UpsertFlowWebhook(ctx context.Context, arg UpsertFlowWebhookParams) (FlowWebhook, error)
+	UpsertGlobalVariable(ctx context.Context, arg UpsertGlobalVariableParams) (GlobalVariable, error)
+	UpsertInputPreset(ctx context.Context, arg UpsertInputPresetParams) (InputPreset, error)
+	UpsertMessengerConfig(ctx context.Context, arg UpsertMessengerConfigParams) (MessengerConfig, error)
+	UpsertNamespaceDefaults(ctx context.Context, arg UpsertNamespaceDefaultsParams) (NamespaceDefault, error)
+	UpsertNamespaceExecutorPolicy(ctx context.Context, arg UpsertNamespaceExecutorPolicyParams) (NamespaceExecutorPolicy, error)
+	UpsertNamespaceQuota(ctx context.Context, arg UpsertNamespaceQuotaParams) (NamespaceQuota, error)
+	UpsertNamespaceVariable(ctx context.Context, arg UpsertNamespaceVariableParams) (NamespaceVariable, error)
+	UpsertNotificationDelivery(ctx context.Context, arg UpsertNotificationDeliveryParams) (NotificationDelivery, error)
+	UpsertRetentionPolicy(ctx context.Context, arg UpsertRetentionPolicyParams) (ExecutionRetentionPolicy, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,33 @@
+-- name: CreateAPIToken :one
+INSERT INTO api_tokens (
+    user_id,
+    name,
+    token_hash,
+    expires_at
+) VALUES (
+    (SELECT id FROM users WHERE users.uuid = sqlc.arg('user_uuid')),
+    sqlc.arg('name'),
+    sqlc.arg('token_hash'),
+    sqlc.arg('expires_at')
+) RETURNING *;
+
+-- name: ListAPITokensByUser :many
+SELECT t.* FROM api_tokens t
+JOIN users u ON t.user_id = u.id
+WHERE u.uuid = $1
+ORDER BY t.created_at DESC;
+
+-- name: DeleteAPITokenByUser :execrows
+DELETE FROM api_tokens
+WHERE api_tokens.uuid = sqlc.arg('uuid')
+AND user_id = (SELECT id FROM users WHERE users.uuid = sqlc.arg('user_uuid'));
+
+-- name: GetAPITokenOwnerByHash :one
+UPDATE api_tokens t SET last_used_at = CASE
+    WHEN t.expires_at IS NULL OR t.expires_at > NOW() THEN NOW()
+    ELSE t.last_used_at
+END
+FROM users u
+WHERE t.user_id = u.id
+AND t.token_hash = $1
+RETURNING u.uuid AS user_uuid, t.expires_at;
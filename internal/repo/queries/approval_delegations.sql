@@ -0,0 +1,73 @@
+-- name: CreateApprovalDelegation :one
+INSERT INTO approval_delegations (
+    namespace_id,
+    delegator_id,
+    delegate_user_id,
+    delegate_group_id,
+    starts_at,
+    ends_at,
+    reason
+) VALUES (
+    (SELECT id FROM namespaces WHERE namespaces.uuid = sqlc.arg('namespace_uuid')),
+    (SELECT id FROM users WHERE users.uuid = sqlc.arg('delegator_uuid')),
+    (SELECT id FROM users WHERE users.uuid = sqlc.narg('delegate_user_uuid')),
+    (SELECT id FROM groups WHERE groups.uuid = sqlc.narg('delegate_group_uuid')),
+    sqlc.arg('starts_at'),
+    sqlc.arg('ends_at'),
+    sqlc.arg('reason')
+) RETURNING *;
+
+-- name: ListApprovalDelegations :many
+SELECT
+    d.*,
+    du.uuid AS delegator_uuid,
+    du.name AS delegator_name,
+    u.uuid AS delegate_user_uuid,
+    u.name AS delegate_user_name,
+    g.uuid AS delegate_group_uuid,
+    g.name AS delegate_group_name
+FROM approval_delegations d
+JOIN namespaces n ON d.namespace_id = n.id
+JOIN users du ON d.delegator_id = du.id
+LEFT JOIN users u ON d.delegate_user_id = u.id
+LEFT JOIN groups g ON d.delegate_group_id = g.id
+WHERE n.uuid = $1
+  AND d.ends_at > NOW()
+ORDER BY d.starts_at ASC;
+
+-- name: DeleteApprovalDelegation :one
+DELETE FROM approval_delegations
+WHERE approval_delegations.uuid = $1
+  AND namespace_id = (SELECT id FROM namespaces WHERE namespaces.uuid = $2)
+  AND delegator_id = (SELECT id FROM users WHERE users.uuid = $3)
+RETURNING *;
+
+-- name: GetActiveDelegatorsForUser :many
+-- Returns the users who have delegated their approval rights in the namespace
+-- to the given user, either directly or through one of the user's groups
+SELECT DISTINCT du.uuid
+FROM approval_delegations d
+JOIN namespaces n ON d.namespace_id = n.id
+JOIN users du ON d.delegator_id = du.id
+WHERE n.uuid = $1
+  AND NOW() >= d.starts_at AND NOW() < d.ends_at
+  AND (
+    d.delegate_user_id = (SELECT id FROM users WHERE users.uuid = $2)
+    OR d.delegate_group_id IN (
+        SELECT gm.group_id FROM group_memberships gm
+        JOIN users u ON gm.user_id = u.id
+        WHERE u.uuid = $2
+    )
+  );
+
+-- name: GetActiveDelegateEmails :many
+-- Returns the emails of users that currently act on behalf of the given delegators in a namespace
+SELECT DISTINCT u.username
+FROM approval_delegations d
+JOIN namespaces n ON d.namespace_id = n.id
+JOIN users du ON d.delegator_id = du.id
+JOIN users u ON u.id = d.delegate_user_id
+    OR u.id IN (SELECT gm.user_id FROM group_memberships gm WHERE gm.group_id = d.delegate_group_id)
+WHERE n.name = $1
+  AND du.username = ANY($2::text[])
+  AND NOW() >= d.starts_at AND NOW() < d.ends_at;
@@ -19,7 +19,7 @@ JOIN users u ON el.triggered_by = u.id;
 WITH namespace_lookup AS (
     SELECT id FROM namespaces WHERE namespaces.uuid = $3
 ), updated AS (
-    UPDATE approvals SET status = 'approved', decided_by = $2, updated_at = NOW()
+    UPDATE approvals SET status = 'approved', decided_by = $2, comment = $4, updated_at = NOW()
     WHERE approvals.uuid = $1
     AND approvals.exec_log_id IN (
         SELECT el.id FROM execution_log el
@@ -39,7 +39,7 @@ JOIN users u ON el.triggered_by = u.id;
 WITH namespace_lookup AS (
     SELECT id FROM namespaces WHERE namespaces.uuid = $3
 ), updated AS (
-    UPDATE approvals SET status = 'rejected', decided_by = $2, updated_at = NOW()
+    UPDATE approvals SET status = 'rejected', decided_by = $2, comment = $4, updated_at = NOW()
     WHERE approvals.uuid = $1
     AND approvals.exec_log_id IN (
         SELECT el.id FROM execution_log el
@@ -126,11 +126,13 @@ WITH namespace_lookup AS (
 SELECT
     a.*,
     el.exec_id,
-    u.name as requested_by
+    u.name as requested_by,
+    us.name as decided_by_name
 FROM approvals a
 JOIN execution_log el ON a.exec_log_id = el.id
 JOIN flows f ON el.flow_id = f.id
 JOIN users u ON el.triggered_by = u.id
+LEFT JOIN users us ON a.decided_by = us.id
 WHERE el.exec_id = $1
   AND f.namespace_id = (SELECT id FROM namespace_lookup)
   AND el.version = (SELECT max_version FROM latest_version)
@@ -0,0 +1,28 @@
+-- name: RecordArtifact :one
+-- Records a file published by an action. The files of a retried action replace the ones of the
+-- earlier attempt.
+INSERT INTO artifacts (exec_id, namespace_id, action_id, name, object_key, size)
+VALUES (
+    sqlc.arg('exec_id'),
+    (SELECT id FROM namespaces WHERE namespaces.uuid = sqlc.arg('namespace_uuid')),
+    sqlc.arg('action_id'),
+    sqlc.arg('name'),
+    sqlc.arg('object_key'),
+    sqlc.arg('size')
+)
+ON CONFLICT (exec_id, action_id, name) DO UPDATE SET
+    object_key = EXCLUDED.object_key,
+    size = EXCLUDED.size,
+    created_at = NOW()
+RETURNING *;
+
+-- name: ListExecutionArtifacts :many
+SELECT a.* FROM artifacts a
+JOIN namespaces n ON a.namespace_id = n.id
+WHERE a.exec_id = $1 AND n.uuid = $2
+ORDER BY a.created_at, a.action_id, a.name;
+
+-- name: GetExecutionArtifact :one
+SELECT a.* FROM artifacts a
+JOIN namespaces n ON a.namespace_id = n.id
+WHERE a.uuid = $1 AND a.exec_id = $2 AND n.uuid = $3;
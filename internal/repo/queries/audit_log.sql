@@ -0,0 +1,46 @@
+-- name: RecordAuditEntry :exec
+INSERT INTO audit_log (namespace_uuid, actor_uuid, actor_name, action, resource_type, resource_id, before, after, source_ip)
+VALUES (
+    sqlc.narg('namespace_uuid'),
+    sqlc.arg('actor_uuid'),
+    sqlc.arg('actor_name'),
+    sqlc.arg('action'),
+    sqlc.arg('resource_type'),
+    sqlc.arg('resource_id'),
+    sqlc.narg('before'),
+    sqlc.narg('after'),
+    sqlc.arg('source_ip')
+);
+
+-- name: ListAuditEntriesPaginated :many
+-- Lists the entries of a namespace, or of every namespace and the changes outside of namespaces
+-- if namespace_uuid is NULL, newest first
+WITH filtered AS (
+    SELECT al.*
+    FROM audit_log al
+    WHERE (sqlc.narg('namespace_uuid')::UUID IS NULL OR al.namespace_uuid = sqlc.narg('namespace_uuid'))
+      AND (
+        sqlc.arg('filter')::TEXT = '' OR
+        al.action ILIKE '%' || sqlc.arg('filter') || '%' OR
+        al.resource_type ILIKE '%' || sqlc.arg('filter') || '%' OR
+        al.resource_id ILIKE '%' || sqlc.arg('filter') || '%' OR
+        al.actor_name ILIKE '%' || sqlc.arg('filter') || '%'
+      )
+),
+total AS (
+    SELECT COUNT(*) AS total_count FROM filtered
+),
+paged AS (
+    SELECT * FROM filtered
+    ORDER BY created_at DESC, id DESC
+    LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset')
+),
+page_count AS (
+    SELECT CEIL(total.total_count::numeric / sqlc.arg('limit')::numeric)::bigint AS page_count FROM total
+)
+SELECT
+    p.*,
+    pc.page_count,
+    t.total_count
+FROM paged p, page_count pc, total t
+ORDER BY p.created_at DESC, p.id DESC;
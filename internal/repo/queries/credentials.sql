@@ -13,6 +13,11 @@ SELECT c.*, ns.uuid AS namespace_uuid FROM credentials c
 JOIN namespaces ns ON c.namespace_id = ns.id
 WHERE c.id = $1 AND ns.uuid = $2;
 
+-- name: GetCredentialByName :one
+SELECT c.*, ns.uuid AS namespace_uuid FROM credentials c
+JOIN namespaces ns ON c.namespace_id = ns.id
+WHERE c.name = $1 AND ns.uuid = $2;
+
 -- name: SearchCredentials :many
 WITH filtered AS (
     SELECT c.*, ns.uuid AS namespace_uuid FROM credentials c
@@ -0,0 +1,43 @@
+-- name: RecordCronScheduleRun :exec
+INSERT INTO cron_schedule_runs (
+    schedule_id,
+    exec_id,
+    fired_at,
+    started_at,
+    max_drift_ms,
+    run_count
+) VALUES (
+    sqlc.arg('schedule_id'),
+    sqlc.arg('exec_id'),
+    sqlc.arg('fired_at'),
+    sqlc.arg('started_at'),
+    sqlc.arg('drift_ms'),
+    1
+)
+ON CONFLICT (schedule_id) DO UPDATE SET
+    exec_id = EXCLUDED.exec_id,
+    fired_at = EXCLUDED.fired_at,
+    started_at = EXCLUDED.started_at,
+    max_drift_ms = GREATEST(cron_schedule_runs.max_drift_ms, EXCLUDED.max_drift_ms),
+    run_count = cron_schedule_runs.run_count + 1;
+
+-- name: ListCronScheduleRuns :many
+SELECT
+    cs.uuid,
+    cs.cron,
+    cs.timezone,
+    cs.is_user_created,
+    cs.is_active,
+    f.slug AS flow_slug,
+    f.name AS flow_name,
+    r.exec_id,
+    r.fired_at,
+    r.started_at,
+    r.max_drift_ms,
+    r.run_count
+FROM cron_schedules cs
+JOIN flows f ON cs.flow_id = f.id
+JOIN namespaces n ON f.namespace_id = n.id
+LEFT JOIN cron_schedule_runs r ON r.schedule_id = cs.id
+WHERE n.uuid = $1
+ORDER BY f.slug, cs.id;
@@ -229,3 +229,12 @@ WHERE flow_id = $1
   AND timezone = $3
   AND is_user_created = $4
   AND is_active = TRUE;
+
+-- name: ListNamespaceSchedules :many
+-- Lists the active schedules of the active flows of a namespace
+SELECT cs.uuid, cs.cron, cs.timezone, cs.is_user_created, f.slug AS flow_slug, f.name AS flow_name
+FROM cron_schedules cs
+JOIN flows f ON cs.flow_id = f.id
+JOIN namespaces n ON f.namespace_id = n.id
+WHERE n.uuid = $1 AND f.is_active = TRUE AND cs.is_active = TRUE
+ORDER BY f.slug, cs.id;
@@ -62,6 +62,49 @@ WHERE execution_log.exec_id = $2
   AND namespace_id = (SELECT id FROM namespace_lookup)
 RETURNING *;
 
+-- name: StartExecutionAction :one
+-- StartExecutionAction sets the current action of an execution and increments its retry count
+-- in a single round trip
+WITH namespace_lookup AS (
+    SELECT id FROM namespaces WHERE namespaces.uuid = sqlc.arg('namespace_uuid')
+), latest_version AS (
+    SELECT MAX(version) as version
+    FROM execution_log el
+    WHERE el.exec_id = sqlc.arg('exec_id') AND el.namespace_id = (SELECT id FROM namespace_lookup)
+)
+UPDATE execution_log el
+SET
+    current_action_id = sqlc.arg('action_id')::text,
+    action_retries = jsonb_set(
+        COALESCE(action_retries, '{}'::jsonb),
+        ARRAY[sqlc.arg('action_id')::text],
+        to_jsonb((COALESCE(action_retries->>sqlc.arg('action_id')::text, '0')::int + 1))
+    ),
+    updated_at = NOW()
+WHERE el.exec_id = sqlc.arg('exec_id')
+  AND el.version = (SELECT version FROM latest_version)
+  AND el.namespace_id = (SELECT id FROM namespace_lookup)
+RETURNING (action_retries->>sqlc.arg('action_id')::text)::int as retry_count;
+
+-- name: MarkExecutionRunning :exec
+-- MarkExecutionRunning sets the status of an execution to running and records when it first
+-- started, replacing separate status and started_at updates
+WITH namespace_lookup AS (
+    SELECT id FROM namespaces WHERE namespaces.uuid = $2
+), latest_version AS (
+    SELECT MAX(version) as version
+    FROM execution_log
+    WHERE execution_log.exec_id = $1 AND namespace_id = (SELECT id FROM namespace_lookup)
+)
+UPDATE execution_log SET
+    status = 'running',
+    error = NULL,
+    started_at = COALESCE(started_at, NOW()),
+    updated_at = NOW()
+WHERE execution_log.exec_id = $1
+  AND version = (SELECT version FROM latest_version)
+  AND namespace_id = (SELECT id FROM namespace_lookup);
+
 -- name: GetExecutionsByFlow :many
 WITH user_lookup AS (
     SELECT id FROM users WHERE users.uuid = $2
@@ -482,3 +525,51 @@ WHERE execution_log.exec_id = $1
   AND version = (SELECT version FROM latest_version)
   AND namespace_id = (SELECT id FROM namespace_lookup)
   AND started_at IS NULL;
+
+-- name: GetExecutionsForLogSearch :many
+WITH namespace_lookup AS (
+    SELECT id FROM namespaces WHERE namespaces.uuid = sqlc.arg('namespace_uuid')
+),
+user_namespaces AS (
+    -- Direct user membership
+    SELECT n.uuid, nm.role
+    FROM namespaces n
+    JOIN namespace_members nm ON n.id = nm.namespace_id
+    JOIN users u ON nm.user_id = u.id
+    WHERE u.uuid = sqlc.arg('caller_uuid') AND n.uuid = sqlc.arg('namespace_uuid')
+
+    UNION
+
+    -- Group membership
+    SELECT DISTINCT n.uuid, nm.role
+    FROM namespaces n
+    JOIN namespace_members nm ON n.id = nm.namespace_id
+    JOIN groups g ON nm.group_id = g.id
+    JOIN group_memberships gm ON g.id = gm.group_id
+    WHERE gm.user_id = (SELECT id FROM users WHERE users.uuid = sqlc.arg('caller_uuid')) AND n.uuid = sqlc.arg('namespace_uuid')
+),
+latest_versions AS (
+    SELECT exec_id, MAX(version) as max_version
+    FROM execution_log el
+    INNER JOIN flows f ON el.flow_id = f.id
+    WHERE f.namespace_id = (SELECT id FROM namespace_lookup)
+      AND f.is_active = TRUE
+    GROUP BY exec_id
+)
+SELECT el.exec_id, el.action_retries, el.created_at, f.name as flow_name, f.slug as flow_slug
+FROM execution_log el
+INNER JOIN flows f ON el.flow_id = f.id
+INNER JOIN latest_versions lv ON el.exec_id = lv.exec_id AND el.version = lv.max_version
+WHERE f.namespace_id = (SELECT id FROM namespace_lookup)
+  AND f.is_active = TRUE
+  AND el.status NOT IN ('pending', 'running')
+  AND (
+    el.triggered_by = (SELECT id FROM users WHERE users.uuid = sqlc.arg('caller_uuid'))
+    OR EXISTS (SELECT id FROM users WHERE users.uuid = sqlc.arg('caller_uuid') AND users.role = 'superuser')
+    OR EXISTS (SELECT uuid FROM user_namespaces WHERE role IN ('admin', 'reviewer', 'operator'))
+  )
+  AND (sqlc.arg('flow_slug')::text = '' OR f.slug = sqlc.arg('flow_slug')::text)
+  AND (sqlc.narg('created_after')::timestamptz IS NULL OR el.created_at >= sqlc.narg('created_after')::timestamptz)
+  AND (sqlc.narg('created_before')::timestamptz IS NULL OR el.created_at <= sqlc.narg('created_before')::timestamptz)
+ORDER BY el.created_at DESC
+LIMIT sqlc.arg('max_executions');
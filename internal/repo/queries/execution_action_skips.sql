@@ -0,0 +1,16 @@
+-- name: AddExecutionActionSkip :one
+INSERT INTO execution_action_skips (exec_id, action_id, namespace_id, skipped_by)
+VALUES (
+    $1,
+    $2,
+    (SELECT id FROM namespaces WHERE namespaces.uuid = $3),
+    (SELECT id FROM users WHERE users.uuid = $4)
+)
+RETURNING *;
+
+-- name: ListExecutionActionSkips :many
+SELECT s.*, COALESCE(u.name, '')::TEXT AS skipped_by_name FROM execution_action_skips s
+INNER JOIN namespaces n ON s.namespace_id = n.id
+LEFT JOIN users u ON s.skipped_by = u.id
+WHERE s.exec_id = $1 AND n.uuid = $2
+ORDER BY s.created_at;
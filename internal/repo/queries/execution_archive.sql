@@ -0,0 +1,116 @@
+-- name: ArchiveExecutions :one
+WITH latest AS (
+    SELECT exec_id, MAX(version) AS max_version, MAX(updated_at) AS last_updated_at
+    FROM execution_log
+    GROUP BY exec_id
+    HAVING MAX(updated_at) < sqlc.arg('before')
+),
+candidates AS (
+    SELECT el.*
+    FROM execution_log el
+    INNER JOIN latest l ON el.exec_id = l.exec_id AND el.version = l.max_version
+    WHERE el.status IN ('completed', 'errored', 'cancelled')
+    ORDER BY l.last_updated_at
+    LIMIT sqlc.arg('batch_size')
+),
+archived AS (
+    INSERT INTO execution_archive (
+        exec_id,
+        namespace_id,
+        flow_slug,
+        flow_name,
+        status,
+        trigger_type,
+        triggered_by_uuid,
+        triggered_by_name,
+        input,
+        error,
+        current_action_id,
+        action_retries,
+        created_at,
+        started_at,
+        completed_at,
+        scheduled_at
+    )
+    SELECT
+        c.exec_id,
+        c.namespace_id,
+        f.slug,
+        f.name,
+        c.status,
+        c.trigger_type,
+        u.uuid,
+        CONCAT(u.name, ' <', u.username, '>'),
+        c.input,
+        c.error,
+        c.current_action_id,
+        c.action_retries,
+        c.created_at,
+        c.started_at,
+        c.completed_at,
+        c.scheduled_at
+    FROM candidates c
+    INNER JOIN flows f ON c.flow_id = f.id
+    INNER JOIN users u ON c.triggered_by = u.id
+    ON CONFLICT (exec_id) DO NOTHING
+    RETURNING exec_id
+),
+deleted_progress AS (
+    DELETE FROM execution_progress WHERE exec_id IN (SELECT exec_id FROM candidates)
+),
+deleted_timeline AS (
+    DELETE FROM execution_timeline WHERE exec_id IN (SELECT exec_id FROM candidates)
+),
+deleted_outputs AS (
+    DELETE FROM execution_outputs WHERE exec_id IN (SELECT exec_id FROM candidates)
+),
+deleted_skips AS (
+    DELETE FROM execution_action_skips WHERE exec_id IN (SELECT exec_id FROM candidates)
+),
+deleted_telemetry AS (
+    DELETE FROM execution_node_telemetry WHERE exec_id IN (SELECT exec_id FROM candidates)
+),
+deleted_environments AS (
+    DELETE FROM execution_environments WHERE exec_id IN (SELECT exec_id FROM candidates)
+),
+deleted_log AS (
+    DELETE FROM execution_log WHERE exec_id IN (SELECT exec_id FROM candidates)
+)
+SELECT COUNT(*) FROM archived;
+
+-- name: GetArchivedExecution :one
+SELECT ea.* FROM execution_archive ea
+INNER JOIN namespaces n ON ea.namespace_id = n.id
+WHERE ea.exec_id = $1 AND n.uuid = $2;
+
+-- name: ListArchivedExecutionsPaginated :many
+WITH filtered AS (
+    SELECT ea.*
+    FROM execution_archive ea
+    INNER JOIN namespaces n ON ea.namespace_id = n.id
+    WHERE n.uuid = sqlc.arg('namespace_uuid')
+      AND (sqlc.narg('triggered_by')::UUID IS NULL OR ea.triggered_by_uuid = sqlc.narg('triggered_by'))
+      AND (
+        sqlc.arg('filter')::TEXT = '' OR
+        ea.flow_name ILIKE '%' || sqlc.arg('filter') || '%' OR
+        ea.flow_slug ILIKE '%' || sqlc.arg('filter') || '%' OR
+        ea.exec_id ILIKE '%' || sqlc.arg('filter') || '%' OR
+        ea.triggered_by_name ILIKE '%' || sqlc.arg('filter') || '%'
+      )
+),
+total AS (
+    SELECT COUNT(*) AS total_count FROM filtered
+),
+paged AS (
+    SELECT * FROM filtered
+    ORDER BY created_at DESC
+    LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset')
+),
+page_count AS (
+    SELECT CEIL(total.total_count::numeric / sqlc.arg('limit')::numeric)::bigint AS page_count FROM total
+)
+SELECT
+    p.*,
+    pc.page_count,
+    t.total_count
+FROM paged p, page_count pc, total t;
@@ -0,0 +1,48 @@
+-- name: UpsertExecutionEnvironment :exec
+-- The checksum is the one of the flow when the action ran
+INSERT INTO execution_environments (
+    exec_id,
+    namespace_id,
+    action_id,
+    node,
+    hostname,
+    os,
+    executor,
+    executor_version,
+    image,
+    image_digest,
+    flow_checksum
+) VALUES (
+    sqlc.arg('exec_id'),
+    (SELECT id FROM namespaces WHERE namespaces.uuid = sqlc.arg('namespace_uuid')),
+    sqlc.arg('action_id'),
+    sqlc.arg('node'),
+    sqlc.arg('hostname'),
+    sqlc.arg('os'),
+    sqlc.arg('executor'),
+    sqlc.arg('executor_version'),
+    sqlc.arg('image'),
+    sqlc.arg('image_digest'),
+    COALESCE((
+        SELECT f.checksum FROM execution_log el
+        JOIN flows f ON el.flow_id = f.id
+        WHERE el.exec_id = sqlc.arg('exec_id')
+        ORDER BY el.version DESC
+        LIMIT 1
+    ), '')
+)
+ON CONFLICT (exec_id, action_id, node) DO UPDATE SET
+    hostname = EXCLUDED.hostname,
+    os = EXCLUDED.os,
+    executor = EXCLUDED.executor,
+    executor_version = EXCLUDED.executor_version,
+    image = EXCLUDED.image,
+    image_digest = EXCLUDED.image_digest,
+    flow_checksum = EXCLUDED.flow_checksum,
+    recorded_at = NOW();
+
+-- name: ListExecutionEnvironments :many
+SELECT e.* FROM execution_environments e
+JOIN namespaces n ON e.namespace_id = n.id
+WHERE e.exec_id = $1 AND n.uuid = $2
+ORDER BY e.recorded_at, e.id;
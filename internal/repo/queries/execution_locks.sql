@@ -0,0 +1,30 @@
+-- name: AcquireExecutionLock :one
+-- Takes a lock for an execution. A lock held by an execution that is no longer running is taken
+-- over, so that locks of executions that stopped without releasing them don't block forever.
+-- No row is returned if another running execution holds the lock.
+INSERT INTO execution_locks (namespace_id, lock_key, exec_id)
+VALUES ((SELECT id FROM namespaces WHERE namespaces.uuid = $1), $2, $3)
+ON CONFLICT (namespace_id, lock_key) DO UPDATE SET
+    exec_id = EXCLUDED.exec_id,
+    acquired_at = NOW()
+WHERE execution_locks.exec_id = EXCLUDED.exec_id
+   OR NOT EXISTS (
+       SELECT 1 FROM execution_log el
+       WHERE el.exec_id = execution_locks.exec_id
+         AND el.status = 'running'
+         AND NOT EXISTS (
+             SELECT 1 FROM execution_log newer
+             WHERE newer.exec_id = el.exec_id AND newer.version > el.version
+         )
+   )
+RETURNING *;
+
+-- name: GetExecutionLock :one
+SELECT l.* FROM execution_locks l
+INNER JOIN namespaces n ON l.namespace_id = n.id
+WHERE n.uuid = $1 AND l.lock_key = $2;
+
+-- name: ReleaseExecutionLock :exec
+DELETE FROM execution_locks
+WHERE namespace_id = (SELECT id FROM namespaces WHERE namespaces.uuid = $1)
+  AND lock_key = $2 AND exec_id = $3;
@@ -0,0 +1,19 @@
+-- name: RecordExecutionOutputs :exec
+-- Records the results of an action. The results of a retried action replace the ones of the
+-- earlier attempt.
+INSERT INTO execution_outputs (exec_id, namespace_id, action_id, outputs)
+VALUES (
+    sqlc.arg('exec_id'),
+    (SELECT id FROM namespaces WHERE namespaces.uuid = sqlc.arg('namespace_uuid')),
+    sqlc.arg('action_id'),
+    sqlc.arg('outputs')
+)
+ON CONFLICT (exec_id, action_id) DO UPDATE SET
+    outputs = EXCLUDED.outputs,
+    created_at = NOW();
+
+-- name: ListExecutionOutputs :many
+SELECT eo.action_id, eo.outputs, eo.created_at FROM execution_outputs eo
+JOIN namespaces n ON eo.namespace_id = n.id
+WHERE eo.exec_id = $1 AND n.uuid = $2
+ORDER BY eo.created_at, eo.action_id;
@@ -0,0 +1,18 @@
+-- name: UpsertExecutionProgress :exec
+INSERT INTO execution_progress (
+    exec_id,
+    namespace_id,
+    progress
+) VALUES (
+    sqlc.arg('exec_id'),
+    (SELECT id FROM namespaces WHERE namespaces.uuid = sqlc.arg('namespace_uuid')),
+    sqlc.arg('progress')
+)
+ON CONFLICT (exec_id) DO UPDATE SET
+    progress = EXCLUDED.progress,
+    updated_at = NOW();
+
+-- name: GetExecutionProgress :one
+SELECT ep.progress FROM execution_progress ep
+JOIN namespaces n ON ep.namespace_id = n.id
+WHERE ep.exec_id = $1 AND n.uuid = $2;
@@ -0,0 +1,142 @@
+-- name: UpsertRetentionPolicy :one
+INSERT INTO execution_retention_policies (namespace_id, max_age_days, keep_last)
+VALUES ((SELECT id FROM namespaces WHERE namespaces.uuid = $1), $2, $3)
+ON CONFLICT (namespace_id) DO UPDATE SET
+    max_age_days = EXCLUDED.max_age_days,
+    keep_last = EXCLUDED.keep_last,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: GetRetentionPolicy :one
+SELECT rp.* FROM execution_retention_policies rp
+INNER JOIN namespaces n ON rp.namespace_id = n.id
+WHERE n.uuid = $1;
+
+-- name: DeleteRetentionPolicy :exec
+DELETE FROM execution_retention_policies
+WHERE namespace_id = (SELECT id FROM namespaces WHERE namespaces.uuid = $1);
+
+-- name: ListRetentionPolicies :many
+SELECT rp.*, n.uuid AS namespace_uuid
+FROM execution_retention_policies rp
+INNER JOIN namespaces n ON rp.namespace_id = n.id
+ORDER BY n.name;
+
+-- name: ListExpiredExecutions :many
+-- Finished executions of a namespace, live or archived, that are older than before or
+-- are not among the keep_last most recent executions
+WITH executions AS (
+    SELECT * FROM (
+        SELECT DISTINCT ON (el.exec_id) el.exec_id, el.status, el.created_at, el.updated_at AS finished_at, FALSE AS archived
+        FROM execution_log el
+        WHERE el.namespace_id = sqlc.arg('namespace_id')
+        ORDER BY el.exec_id, el.version DESC
+    ) live
+    UNION ALL
+    SELECT ea.exec_id, ea.status, ea.created_at, COALESCE(ea.completed_at, ea.created_at) AS finished_at, TRUE AS archived
+    FROM execution_archive ea
+    WHERE ea.namespace_id = sqlc.arg('namespace_id')
+),
+ranked AS (
+    SELECT e.*, ROW_NUMBER() OVER (ORDER BY e.created_at DESC) AS position
+    FROM executions e
+)
+SELECT exec_id, status, created_at, finished_at, archived
+FROM ranked
+WHERE status IN ('completed', 'errored', 'cancelled')
+  AND (
+    (sqlc.narg('before')::TIMESTAMPTZ IS NOT NULL AND finished_at < sqlc.narg('before'))
+    OR (sqlc.narg('keep_last')::INTEGER IS NOT NULL AND position > sqlc.narg('keep_last'))
+  )
+ORDER BY created_at
+LIMIT sqlc.arg('batch_size');
+
+-- name: SummarizeExpiredExecutions :many
+-- Same selection as ListExpiredExecutions, grouped by status
+WITH executions AS (
+    SELECT * FROM (
+        SELECT DISTINCT ON (el.exec_id) el.exec_id, el.status, el.created_at, el.updated_at AS finished_at, FALSE AS archived
+        FROM execution_log el
+        WHERE el.namespace_id = sqlc.arg('namespace_id')
+        ORDER BY el.exec_id, el.version DESC
+    ) live
+    UNION ALL
+    SELECT ea.exec_id, ea.status, ea.created_at, COALESCE(ea.completed_at, ea.created_at) AS finished_at, TRUE AS archived
+    FROM execution_archive ea
+    WHERE ea.namespace_id = sqlc.arg('namespace_id')
+),
+ranked AS (
+    SELECT e.*, ROW_NUMBER() OVER (ORDER BY e.created_at DESC) AS position
+    FROM executions e
+)
+SELECT
+    status,
+    COUNT(*) AS count,
+    COUNT(*) FILTER (WHERE archived) AS archived_count,
+    MIN(created_at)::TIMESTAMPTZ AS oldest,
+    MAX(created_at)::TIMESTAMPTZ AS newest
+FROM ranked
+WHERE status IN ('completed', 'errored', 'cancelled')
+  AND (
+    (sqlc.narg('before')::TIMESTAMPTZ IS NOT NULL AND finished_at < sqlc.narg('before'))
+    OR (sqlc.narg('keep_last')::INTEGER IS NOT NULL AND position > sqlc.narg('keep_last'))
+  )
+GROUP BY status
+ORDER BY status;
+
+-- name: PurgeExecutions :exec
+-- Removes every record of the given executions. Approvals are removed with the execution_log rows and
+-- the last run of cron schedules keeps its timing without the execution.
+WITH deleted_progress AS (
+    DELETE FROM execution_progress WHERE exec_id = ANY(sqlc.arg('exec_ids')::TEXT[])
+),
+deleted_timeline AS (
+    DELETE FROM execution_timeline WHERE exec_id = ANY(sqlc.arg('exec_ids')::TEXT[])
+),
+deleted_outputs AS (
+    DELETE FROM execution_outputs WHERE exec_id = ANY(sqlc.arg('exec_ids')::TEXT[])
+),
+deleted_skips AS (
+    DELETE FROM execution_action_skips WHERE exec_id = ANY(sqlc.arg('exec_ids')::TEXT[])
+),
+deleted_telemetry AS (
+    DELETE FROM execution_node_telemetry WHERE exec_id = ANY(sqlc.arg('exec_ids')::TEXT[])
+),
+deleted_environments AS (
+    DELETE FROM execution_environments WHERE exec_id = ANY(sqlc.arg('exec_ids')::TEXT[])
+),
+deleted_deliveries AS (
+    DELETE FROM notification_deliveries WHERE exec_id = ANY(sqlc.arg('exec_ids')::TEXT[])
+),
+deleted_breaches AS (
+    DELETE FROM sla_breaches WHERE exec_id = ANY(sqlc.arg('exec_ids')::TEXT[])
+),
+deleted_archive AS (
+    DELETE FROM execution_archive WHERE exec_id = ANY(sqlc.arg('exec_ids')::TEXT[])
+),
+deleted_log_usage AS (
+    DELETE FROM execution_log_usage WHERE exec_id = ANY(sqlc.arg('exec_ids')::TEXT[])
+),
+deleted_subflows AS (
+    DELETE FROM subflow_executions WHERE exec_id = ANY(sqlc.arg('exec_ids')::TEXT[]) OR parent_exec_id = ANY(sqlc.arg('exec_ids')::TEXT[])
+),
+deleted_artifacts AS (
+    DELETE FROM artifacts WHERE exec_id = ANY(sqlc.arg('exec_ids')::TEXT[])
+),
+cleared_schedule_runs AS (
+    UPDATE cron_schedule_runs SET exec_id = NULL WHERE exec_id = ANY(sqlc.arg('exec_ids')::TEXT[])
+)
+DELETE FROM execution_log WHERE exec_id = ANY(sqlc.arg('exec_ids')::TEXT[]);
+
+-- name: GetExecutionStatuses :many
+-- Latest status of the given executions, live or archived. Executions that were purged are not returned.
+SELECT exec_id, status, updated_at FROM (
+    SELECT DISTINCT ON (el.exec_id) el.exec_id, el.status, el.updated_at
+    FROM execution_log el
+    WHERE el.exec_id = ANY(sqlc.arg('exec_ids')::TEXT[])
+    ORDER BY el.exec_id, el.version DESC
+) live
+UNION ALL
+SELECT ea.exec_id, ea.status, COALESCE(ea.completed_at, ea.created_at) AS updated_at
+FROM execution_archive ea
+WHERE ea.exec_id = ANY(sqlc.arg('exec_ids')::TEXT[]);
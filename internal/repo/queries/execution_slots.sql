@@ -0,0 +1,33 @@
+-- name: LockExecutionSlots :exec
+-- Serializes taking the slots of a namespace until the end of the transaction, so that servers
+-- picking jobs at the same time can't take more slots than the limits allow.
+SELECT pg_advisory_xact_lock(hashtext('execution_slots'), (SELECT id FROM namespaces WHERE namespaces.uuid = $1));
+
+-- name: CountExecutionSlots :one
+-- Counts the slots held in the namespace and by executions of a flow, other than the slot of
+-- exec_id. Slots of executions that finished without releasing them are not counted.
+SELECT
+    COUNT(*) FILTER (WHERE s.flow_slug = sqlc.arg('flow_slug'))::INTEGER AS flow_slots,
+    COUNT(*)::INTEGER AS namespace_slots
+FROM execution_slots s
+WHERE s.namespace_id = (SELECT id FROM namespaces WHERE namespaces.uuid = sqlc.arg('uuid'))
+  AND s.exec_id != sqlc.arg('exec_id')
+  AND NOT EXISTS (
+      SELECT 1 FROM execution_log el
+      WHERE el.exec_id = s.exec_id
+        AND el.status IN ('completed', 'errored', 'cancelled')
+        AND NOT EXISTS (
+            SELECT 1 FROM execution_log newer
+            WHERE newer.exec_id = el.exec_id AND newer.version > el.version
+        )
+  );
+
+-- name: TakeExecutionSlot :exec
+-- An execution that is picked again, for example after a handoff, keeps its slot
+INSERT INTO execution_slots (exec_id, namespace_id, flow_slug)
+VALUES ($1, (SELECT id FROM namespaces WHERE namespaces.uuid = $2), $3)
+ON CONFLICT (exec_id) DO UPDATE SET
+    acquired_at = NOW();
+
+-- name: ReleaseExecutionSlot :exec
+DELETE FROM execution_slots WHERE exec_id = $1;
@@ -0,0 +1,28 @@
+-- name: CreateNodeTelemetrySample :exec
+INSERT INTO execution_node_telemetry (
+    exec_id,
+    namespace_id,
+    action_id,
+    node,
+    sampled_at,
+    cpu_percent,
+    memory_percent,
+    disk_percent,
+    load1
+) VALUES (
+    sqlc.arg('exec_id'),
+    (SELECT id FROM namespaces WHERE namespaces.uuid = sqlc.arg('namespace_uuid')),
+    sqlc.arg('action_id'),
+    sqlc.arg('node'),
+    sqlc.arg('sampled_at'),
+    sqlc.arg('cpu_percent'),
+    sqlc.arg('memory_percent'),
+    sqlc.arg('disk_percent'),
+    sqlc.arg('load1')
+);
+
+-- name: ListExecutionTelemetry :many
+SELECT t.* FROM execution_node_telemetry t
+JOIN namespaces n ON t.namespace_id = n.id
+WHERE t.exec_id = $1 AND n.uuid = $2
+ORDER BY t.sampled_at, t.id;
@@ -0,0 +1,101 @@
+-- name: StartExecutionTimelineEntry :exec
+INSERT INTO execution_timeline (
+    exec_id,
+    namespace_id,
+    action_id,
+    node,
+    retry
+) VALUES (
+    sqlc.arg('exec_id'),
+    (SELECT id FROM namespaces WHERE namespaces.uuid = sqlc.arg('namespace_uuid')),
+    sqlc.arg('action_id'),
+    sqlc.arg('node'),
+    sqlc.arg('retry')
+)
+ON CONFLICT (exec_id, action_id, node, retry) DO UPDATE SET
+    status = 'running',
+    error = NULL,
+    started_at = NOW(),
+    finished_at = NULL;
+
+-- name: FinishExecutionTimelineEntry :exec
+UPDATE execution_timeline et
+SET
+    status = sqlc.arg('status'),
+    error = sqlc.narg('error'),
+    finished_at = NOW()
+FROM namespaces n
+WHERE et.namespace_id = n.id
+  AND n.uuid = sqlc.arg('namespace_uuid')
+  AND et.exec_id = sqlc.arg('exec_id')
+  AND et.action_id = sqlc.arg('action_id')
+  AND et.node = sqlc.arg('node')
+  AND et.retry = sqlc.arg('retry');
+
+-- name: RecordExecutionTimelineEntries :exec
+-- Records the entries of the nodes running an action in a single statement. Entries carry their own
+-- start and finish times so that they can be buffered and written again as they change.
+INSERT INTO execution_timeline (
+    exec_id,
+    namespace_id,
+    action_id,
+    node,
+    retry,
+    status,
+    error,
+    started_at,
+    finished_at
+)
+SELECT
+    sqlc.arg('exec_id'),
+    n.id,
+    e.action_id,
+    e.node,
+    e.retry,
+    e.status,
+    NULLIF(e.error, ''),
+    e.started_at,
+    CASE WHEN e.finished THEN e.finished_at END
+FROM namespaces n,
+    unnest(
+        sqlc.arg('action_ids')::TEXT[],
+        sqlc.arg('nodes')::TEXT[],
+        sqlc.arg('retries')::INTEGER[],
+        sqlc.arg('statuses')::TEXT[],
+        sqlc.arg('errors')::TEXT[],
+        sqlc.arg('started_at')::TIMESTAMPTZ[],
+        sqlc.arg('finished')::BOOLEAN[],
+        sqlc.arg('finished_at')::TIMESTAMPTZ[]
+    ) AS e(action_id, node, retry, status, error, started_at, finished, finished_at)
+WHERE n.uuid = sqlc.arg('namespace_uuid')
+ON CONFLICT (exec_id, action_id, node, retry) DO UPDATE SET
+    status = EXCLUDED.status,
+    error = EXCLUDED.error,
+    started_at = EXCLUDED.started_at,
+    finished_at = EXCLUDED.finished_at;
+
+-- name: ListExecutionTimeline :many
+SELECT et.* FROM execution_timeline et
+JOIN namespaces n ON et.namespace_id = n.id
+WHERE et.exec_id = $1 AND n.uuid = $2
+ORDER BY et.started_at, et.id;
+
+-- name: GetActionDurationEstimates :many
+-- Median duration of each action of a flow over its last completed executions. Only the attempts
+-- that succeeded are counted.
+WITH recent AS (
+    SELECT exec_id FROM execution_log
+    WHERE flow_id = sqlc.arg('flow_id') AND status = 'completed'
+    ORDER BY completed_at DESC NULLS LAST, id DESC
+    LIMIT sqlc.arg('sample_size')
+)
+SELECT
+    et.action_id,
+    (percentile_cont(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM et.finished_at - et.started_at)))::DOUBLE PRECISION AS median_seconds,
+    COUNT(*) AS samples
+FROM execution_timeline et
+WHERE et.exec_id IN (SELECT exec_id FROM recent)
+  AND et.node = ''
+  AND et.status = 'success'
+  AND et.finished_at IS NOT NULL
+GROUP BY et.action_id;
@@ -0,0 +1,38 @@
+-- name: AddExecutionWatch :exec
+INSERT INTO execution_watches (user_id, flow_id, exec_id)
+VALUES ((SELECT id FROM users WHERE users.uuid = $1), $2, $3)
+ON CONFLICT DO NOTHING;
+
+-- name: RemoveExecutionWatch :exec
+DELETE FROM execution_watches
+WHERE user_id = (SELECT id FROM users WHERE users.uuid = $1) AND flow_id = $2
+  AND exec_id IS NOT DISTINCT FROM $3;
+
+-- name: ListExecutionWatches :many
+-- Lists the flows and executions of a namespace watched by a user, last watched first
+SELECT f.slug AS flow_slug, f.name AS flow_name, w.exec_id, w.created_at
+FROM execution_watches w
+JOIN flows f ON w.flow_id = f.id
+JOIN namespaces n ON f.namespace_id = n.id
+WHERE w.user_id = (SELECT id FROM users WHERE users.uuid = sqlc.arg('user_uuid'))
+  AND n.uuid = sqlc.arg('namespace_uuid') AND f.is_active = TRUE
+ORDER BY w.created_at DESC;
+
+-- name: ListExecutionWatchers :many
+-- Lists the usernames of the users watching an execution or its flow that are still superusers
+-- or members of the namespace
+SELECT DISTINCT u.username
+FROM execution_watches w
+JOIN flows f ON w.flow_id = f.id
+JOIN namespaces n ON f.namespace_id = n.id
+JOIN users u ON w.user_id = u.id
+WHERE n.uuid = sqlc.arg('namespace_uuid') AND f.slug = sqlc.arg('flow_slug')
+  AND (w.exec_id IS NULL OR w.exec_id = sqlc.arg('exec_id'))
+  AND (
+    u.role = 'superuser'
+    OR EXISTS (
+        SELECT 1 FROM namespace_members nm
+        WHERE nm.namespace_id = n.id
+          AND (nm.user_id = u.id OR nm.group_id IN (SELECT gm.group_id FROM group_memberships gm WHERE gm.user_id = u.id))
+    )
+  );
@@ -0,0 +1,32 @@
+-- name: ListFailedExecutions :many
+WITH latest AS (
+    SELECT el.exec_id, MAX(el.version) AS max_version
+    FROM execution_log el
+    INNER JOIN namespaces n ON el.namespace_id = n.id
+    WHERE n.uuid = sqlc.arg('namespace_uuid')
+    GROUP BY el.exec_id
+)
+SELECT
+    el.exec_id,
+    el.error,
+    el.current_action_id,
+    el.updated_at,
+    f.slug AS flow_slug,
+    f.name AS flow_name
+FROM execution_log el
+INNER JOIN latest l ON el.exec_id = l.exec_id AND el.version = l.max_version
+INNER JOIN flows f ON el.flow_id = f.id
+WHERE el.status = 'errored'
+  AND el.updated_at >= sqlc.arg('from')
+  AND el.updated_at < sqlc.arg('to')
+ORDER BY el.updated_at DESC;
+
+-- name: ListFailedNodes :many
+SELECT et.exec_id, et.action_id, et.node, et.error, et.started_at
+FROM execution_timeline et
+INNER JOIN namespaces n ON et.namespace_id = n.id
+WHERE n.uuid = sqlc.arg('namespace_uuid')
+  AND et.status = 'failed'
+  AND et.node <> ''
+  AND et.started_at >= sqlc.arg('from')
+  AND et.started_at < sqlc.arg('to');
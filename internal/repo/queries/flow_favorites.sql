@@ -0,0 +1,35 @@
+-- name: AddFlowFavorite :exec
+INSERT INTO flow_favorites (user_id, flow_id)
+VALUES ((SELECT id FROM users WHERE users.uuid = $1), $2)
+ON CONFLICT (user_id, flow_id) DO NOTHING;
+
+-- name: RemoveFlowFavorite :exec
+DELETE FROM flow_favorites
+WHERE user_id = (SELECT id FROM users WHERE users.uuid = $1) AND flow_id = $2;
+
+-- name: ListFlowFavorites :many
+-- Lists the active flows a user starred in a namespace, limited to the flow groups in prefixes unless full_access is set
+SELECT f.id, f.slug, f.name, f.description, fp.name AS prefix_name, ff.created_at AS favorited_at
+FROM flow_favorites ff
+JOIN flows f ON ff.flow_id = f.id
+JOIN namespaces n ON f.namespace_id = n.id
+LEFT JOIN flow_prefixes fp ON f.prefix_id = fp.id
+WHERE ff.user_id = (SELECT id FROM users WHERE users.uuid = sqlc.arg('user_uuid'))
+  AND n.uuid = sqlc.arg('namespace_uuid') AND f.is_active = TRUE
+  AND (sqlc.arg('full_access')::boolean OR f.prefix_id IS NULL OR fp.name = ANY(sqlc.arg('prefixes')::text[]))
+ORDER BY f.name;
+
+-- name: ListRecentFlowsByUser :many
+-- Lists the active flows a user triggered in a namespace, last run first, limited to the flow groups in prefixes unless full_access is set
+SELECT f.id, f.slug, f.name, f.description, fp.name AS prefix_name, MAX(el.created_at)::timestamptz AS last_run_at
+FROM execution_log el
+JOIN flows f ON el.flow_id = f.id
+JOIN namespaces n ON el.namespace_id = n.id
+LEFT JOIN flow_prefixes fp ON f.prefix_id = fp.id
+WHERE el.triggered_by = (SELECT id FROM users WHERE users.uuid = sqlc.arg('user_uuid'))
+  AND el.trigger_type = 'manual'
+  AND n.uuid = sqlc.arg('namespace_uuid') AND f.is_active = TRUE
+  AND (sqlc.arg('full_access')::boolean OR f.prefix_id IS NULL OR fp.name = ANY(sqlc.arg('prefixes')::text[]))
+GROUP BY f.id, fp.name
+ORDER BY last_run_at DESC
+LIMIT sqlc.arg('limit');
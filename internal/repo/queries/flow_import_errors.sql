@@ -0,0 +1,17 @@
+-- name: ClearFlowImportErrors :exec
+DELETE FROM flow_import_errors WHERE namespace_id = $1;
+
+-- name: CreateFlowImportError :exec
+INSERT INTO flow_import_errors (
+    namespace_id,
+    file_path,
+    error
+) VALUES (
+    $1, $2, $3
+) ON CONFLICT (namespace_id, file_path) DO UPDATE
+SET error = EXCLUDED.error, created_at = NOW();
+
+-- name: ListFlowImportErrors :many
+SELECT * FROM flow_import_errors
+WHERE namespace_id = (SELECT id FROM namespaces WHERE namespaces.uuid = $1)
+ORDER BY file_path;
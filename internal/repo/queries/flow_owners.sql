@@ -0,0 +1,20 @@
+-- name: ListNamespaceOwnerCandidates :many
+-- Lists who can own the flows of a namespace: superusers, users that are members directly or
+-- through a group, by username, and groups that are members, by name
+SELECT u.username AS name, 'user'::text AS subject_type
+FROM users u
+WHERE u.role = 'superuser'
+   OR u.id IN (
+       SELECT nm.user_id FROM namespace_members nm
+       WHERE nm.namespace_id = (SELECT id FROM namespaces WHERE namespaces.uuid = $1)
+         AND nm.user_id IS NOT NULL
+       UNION
+       SELECT gm.user_id FROM namespace_members nm
+       JOIN group_memberships gm ON gm.group_id = nm.group_id
+       WHERE nm.namespace_id = (SELECT id FROM namespaces WHERE namespaces.uuid = $1)
+   )
+UNION ALL
+SELECT g.name, 'group'::text
+FROM namespace_members nm
+JOIN groups g ON nm.group_id = g.id
+WHERE nm.namespace_id = (SELECT id FROM namespaces WHERE namespaces.uuid = $1);
@@ -0,0 +1,18 @@
+-- name: CreateFlowPromotion :one
+INSERT INTO flow_promotions (source_flow_id, source_checksum, target_flow_id, revision_uuid, promoted_by)
+VALUES ($1, $2, $3, $4, (SELECT id FROM users WHERE users.uuid = sqlc.arg('promoted_by_uuid')))
+RETURNING *;
+
+-- name: ListFlowPromotions :many
+-- Lists the promotions a flow was the source or the target of, newest first
+SELECT fp.id, sn.name AS source_namespace, sf.slug AS source_flow_slug, fp.source_checksum,
+       tn.name AS target_namespace, tf.slug AS target_flow_slug, fp.revision_uuid,
+       COALESCE(u.name, '')::TEXT AS promoted_by_name, fp.created_at
+FROM flow_promotions fp
+JOIN flows sf ON fp.source_flow_id = sf.id
+JOIN namespaces sn ON sf.namespace_id = sn.id
+JOIN flows tf ON fp.target_flow_id = tf.id
+JOIN namespaces tn ON tf.namespace_id = tn.id
+LEFT JOIN users u ON fp.promoted_by = u.id
+WHERE fp.source_flow_id = sqlc.arg('flow_id') OR fp.target_flow_id = sqlc.arg('flow_id')
+ORDER BY fp.created_at DESC;
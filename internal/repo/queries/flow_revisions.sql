@@ -0,0 +1,80 @@
+-- name: CreateFlowRevision :one
+INSERT INTO flow_revisions (
+    flow_id,
+    namespace_id,
+    content,
+    format,
+    checksum,
+    commit_sha,
+    requested_by
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: GetPendingFlowRevision :one
+SELECT * FROM flow_revisions
+WHERE flow_id = $1 AND status = 'pending';
+
+-- name: SupersedePendingFlowRevisions :exec
+UPDATE flow_revisions
+SET status = 'superseded', reviewed_at = NOW()
+WHERE flow_id = $1 AND status = 'pending';
+
+-- name: GetFlowRevisionByUUID :one
+SELECT
+    r.*,
+    f.slug AS flow_slug,
+    f.name AS flow_name,
+    COALESCE(ru.name, '')::text AS requested_by_name,
+    COALESCE(vu.name, '')::text AS reviewed_by_name
+FROM flow_revisions r
+JOIN flows f ON r.flow_id = f.id
+LEFT JOIN users ru ON r.requested_by = ru.id
+LEFT JOIN users vu ON r.reviewed_by = vu.id
+WHERE r.uuid = $1
+  AND r.namespace_id = (SELECT id FROM namespaces WHERE namespaces.uuid = $2);
+
+-- name: ListFlowRevisions :many
+WITH filtered AS (
+    SELECT
+        r.id,
+        r.uuid,
+        r.checksum,
+        r.commit_sha,
+        r.status,
+        r.comment,
+        r.created_at,
+        r.reviewed_at,
+        f.slug AS flow_slug,
+        f.name AS flow_name,
+        COALESCE(ru.name, '')::text AS requested_by_name,
+        COALESCE(vu.name, '')::text AS reviewed_by_name
+    FROM flow_revisions r
+    JOIN flows f ON r.flow_id = f.id
+    LEFT JOIN users ru ON r.requested_by = ru.id
+    LEFT JOIN users vu ON r.reviewed_by = vu.id
+    WHERE r.namespace_id = (SELECT id FROM namespaces WHERE namespaces.uuid = $1)
+      AND ($2::text = '' OR r.status = $2::text)
+),
+total AS (
+    SELECT COUNT(*) AS total_count FROM filtered
+),
+paged AS (
+    SELECT * FROM filtered
+    ORDER BY created_at DESC, id DESC
+    LIMIT $3 OFFSET $4
+),
+page_count AS (
+    SELECT CEIL(total.total_count::numeric / $3::numeric)::bigint AS page_count FROM total
+)
+SELECT
+    p.*,
+    pc.page_count,
+    t.total_count
+FROM paged p, page_count pc, total t;
+
+-- name: ReviewFlowRevision :one
+UPDATE flow_revisions
+SET status = $2, reviewed_by = $3, comment = $4, reviewed_at = NOW()
+WHERE id = $1 AND status = 'pending'
+RETURNING *;
@@ -0,0 +1,13 @@
+-- name: CreateFlowTriggerRun :one
+-- Records an execution queued by a trigger. No row is returned if the upstream execution
+-- already triggered the flow.
+INSERT INTO flow_trigger_runs (upstream_exec_id, exec_id, flow_id, depth)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (upstream_exec_id, flow_id) DO NOTHING
+RETURNING *;
+
+-- name: DeleteFlowTriggerRun :exec
+DELETE FROM flow_trigger_runs WHERE exec_id = $1;
+
+-- name: GetFlowTriggerRun :one
+SELECT * FROM flow_trigger_runs WHERE exec_id = $1;
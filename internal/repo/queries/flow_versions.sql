@@ -0,0 +1,43 @@
+-- name: CreateFlowVersion :one
+INSERT INTO flow_versions (
+    flow_id,
+    checksum,
+    commit_sha,
+    content,
+    format
+) VALUES (
+    $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetLatestFlowVersion :one
+SELECT * FROM flow_versions
+WHERE flow_id = $1
+ORDER BY created_at DESC, id DESC
+LIMIT 1;
+
+-- name: GetFlowVersion :one
+SELECT * FROM flow_versions
+WHERE id = $1 AND flow_id = $2;
+
+-- name: ListFlowVersions :many
+WITH filtered AS (
+    SELECT id, checksum, commit_sha, created_at, content IS NOT NULL AS has_content
+    FROM flow_versions
+    WHERE flow_id = $1
+),
+total AS (
+    SELECT COUNT(*) AS total_count FROM filtered
+),
+paged AS (
+    SELECT * FROM filtered
+    ORDER BY created_at DESC, id DESC
+    LIMIT $2 OFFSET $3
+),
+page_count AS (
+    SELECT CEIL(total.total_count::numeric / $2::numeric)::bigint AS page_count FROM total
+)
+SELECT
+    p.*,
+    pc.page_count,
+    t.total_count
+FROM paged p, page_count pc, total t;
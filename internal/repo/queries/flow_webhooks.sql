@@ -0,0 +1,19 @@
+-- name: UpsertFlowWebhook :one
+-- Creates the webhook of a flow, or replaces the token and secret of its existing webhook
+INSERT INTO flow_webhooks (flow_id, token_hash, encrypted_secret)
+VALUES ($1, $2, $3)
+ON CONFLICT (flow_id) DO UPDATE SET
+    token_hash = EXCLUDED.token_hash,
+    encrypted_secret = EXCLUDED.encrypted_secret,
+    created_at = NOW(),
+    last_used_at = NULL
+RETURNING *;
+
+-- name: GetFlowWebhook :one
+SELECT * FROM flow_webhooks WHERE flow_id = $1;
+
+-- name: DeleteFlowWebhook :execrows
+DELETE FROM flow_webhooks WHERE flow_id = $1;
+
+-- name: TouchFlowWebhook :exec
+UPDATE flow_webhooks SET last_used_at = NOW() WHERE flow_id = $1;
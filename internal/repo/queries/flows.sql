@@ -99,10 +99,13 @@ WITH filtered AS (
     FROM flows f
     JOIN namespaces n ON f.namespace_id = n.id
     LEFT JOIN flow_prefixes fp ON f.prefix_id = fp.id
+    LEFT JOIN flow_search fs ON fs.flow_id = f.id
     WHERE n.uuid = $1
       AND f.is_active = TRUE
       AND (lower(f.name) LIKE '%' || lower($2::text) || '%'
-           OR lower(f.description) LIKE '%' || lower($2::text) || '%')
+           OR lower(f.description) LIKE '%' || lower($2::text) || '%'
+           OR (to_tsvector('english', f.name || ' ' || COALESCE(f.description, ''))
+               || COALESCE(fs.document, ''::tsvector)) @@ websearch_to_tsquery('english', $2::text))
 ),
 total AS (
     SELECT COUNT(*) AS total_count FROM filtered
@@ -155,10 +158,13 @@ WITH filtered AS (
     FROM flows f
     JOIN namespaces n ON f.namespace_id = n.id
     LEFT JOIN flow_prefixes fp ON f.prefix_id = fp.id
+    LEFT JOIN flow_search fs ON fs.flow_id = f.id
     WHERE n.uuid = $1
       AND f.is_active = TRUE
       AND (lower(f.name) LIKE '%' || lower($2::text) || '%'
-           OR lower(f.description) LIKE '%' || lower($2::text) || '%')
+           OR lower(f.description) LIKE '%' || lower($2::text) || '%'
+           OR (to_tsvector('english', f.name || ' ' || COALESCE(f.description, ''))
+               || COALESCE(fs.document, ''::tsvector)) @@ websearch_to_tsquery('english', $2::text))
       AND (f.prefix_id IS NULL OR fp.name = ANY($5::text[]))
 ),
 total AS (SELECT COUNT(*) AS total_count FROM filtered),
@@ -199,6 +205,27 @@ JOIN namespaces n ON f.namespace_id = n.id
 JOIN cron_schedules cs ON cs.flow_id = f.id
 WHERE f.is_active = TRUE AND cs.is_active = TRUE;
 
+-- name: ListScheduledFlowJobs :many
+-- ListScheduledFlowJobs returns the active schedules of active flows with their namespace and the
+-- user that created them, so scheduled jobs can be built without a query per schedule
+SELECT
+    f.slug,
+    f.name,
+    f.file_path,
+    n.uuid AS namespace_uuid,
+    cs.id AS schedule_id,
+    cs.cron,
+    cs.timezone,
+    cs.inputs,
+    cs.is_user_created,
+    u.uuid AS created_by_uuid
+FROM flows f
+JOIN namespaces n ON f.namespace_id = n.id
+JOIN cron_schedules cs ON cs.flow_id = f.id
+LEFT JOIN users u ON cs.created_by = u.id
+WHERE f.is_active = TRUE AND cs.is_active = TRUE
+ORDER BY n.id, f.id, cs.id;
+
 -- name: MarkAllFlowsInactiveForNamespace :exec
 UPDATE flows SET is_active = FALSE, updated_at = NOW()
 WHERE namespace_id = (SELECT id FROM namespaces WHERE namespaces.uuid = $1);
@@ -206,3 +233,17 @@ WHERE namespace_id = (SELECT id FROM namespaces WHERE namespaces.uuid = $1);
 -- name: MarkFlowActive :exec
 UPDATE flows SET is_active = TRUE, updated_at = NOW()
 WHERE slug = $1 AND namespace_id = (SELECT id FROM namespaces WHERE namespaces.uuid = $2);
+
+-- name: UpsertFlowSearch :exec
+INSERT INTO flow_search (flow_id, content, document)
+VALUES (sqlc.arg('flow_id'), sqlc.arg('content')::text, to_tsvector('english', sqlc.arg('content')::text))
+ON CONFLICT (flow_id) DO UPDATE SET
+    content = EXCLUDED.content,
+    document = EXCLUDED.document,
+    updated_at = NOW();
+
+-- name: CreateFlowSearchIfMissing :exec
+-- Indexes flows that were created before search was added when they are next loaded
+INSERT INTO flow_search (flow_id, content, document)
+VALUES (sqlc.arg('flow_id'), sqlc.arg('content')::text, to_tsvector('english', sqlc.arg('content')::text))
+ON CONFLICT (flow_id) DO NOTHING;
@@ -0,0 +1,38 @@
+-- name: UpsertInputPreset :one
+INSERT INTO input_presets (flow_id, name, inputs, is_shared, created_by)
+VALUES ($1, $2, $3, $4, (SELECT id FROM users WHERE users.uuid = sqlc.arg('user_uuid')))
+ON CONFLICT (flow_id, created_by, name) DO UPDATE
+SET inputs = EXCLUDED.inputs, is_shared = EXCLUDED.is_shared, updated_at = NOW()
+RETURNING *;
+
+-- name: ListInputPresets :many
+-- Lists the presets of a flow a user can use, their own and the shared ones, by name
+SELECT p.uuid, p.name, p.inputs, p.is_shared, u.uuid AS created_by_uuid, u.username AS created_by_name,
+       p.created_at, p.updated_at
+FROM input_presets p
+JOIN users u ON p.created_by = u.id
+WHERE p.flow_id = sqlc.arg('flow_id') AND (p.is_shared OR u.uuid = sqlc.arg('user_uuid'))
+ORDER BY p.name, p.is_shared;
+
+-- name: GetInputPresetByName :one
+-- Gets a preset of a flow a user can use by name. The user's own preset takes precedence over
+-- shared presets with the same name.
+SELECT p.uuid, p.name, p.inputs, p.is_shared, u.uuid AS created_by_uuid, u.username AS created_by_name,
+       p.created_at, p.updated_at
+FROM input_presets p
+JOIN users u ON p.created_by = u.id
+WHERE p.flow_id = sqlc.arg('flow_id') AND p.name = sqlc.arg('name')
+  AND (p.is_shared OR u.uuid = sqlc.arg('user_uuid'))
+ORDER BY (u.uuid = sqlc.arg('user_uuid')) DESC, p.updated_at DESC
+LIMIT 1;
+
+-- name: GetInputPresetByUUID :one
+SELECT p.uuid, p.name, p.inputs, p.is_shared, u.uuid AS created_by_uuid, u.username AS created_by_name,
+       p.created_at, p.updated_at
+FROM input_presets p
+JOIN users u ON p.created_by = u.id
+WHERE p.uuid = sqlc.arg('uuid') AND p.flow_id = sqlc.arg('flow_id')
+  AND (p.is_shared OR u.uuid = sqlc.arg('user_uuid'));
+
+-- name: DeleteInputPreset :exec
+DELETE FROM input_presets WHERE uuid = $1 AND flow_id = $2;
@@ -0,0 +1,7 @@
+-- name: TryAdvisoryLock :one
+-- Takes a session level advisory lock without waiting. The lock is held until it is released
+-- or the connection is closed, so it must be taken and released on the same connection.
+SELECT pg_try_advisory_lock(sqlc.arg('key')::BIGINT)::BOOLEAN AS locked;
+
+-- name: AdvisoryUnlock :one
+SELECT pg_advisory_unlock(sqlc.arg('key')::BIGINT)::BOOLEAN AS unlocked;
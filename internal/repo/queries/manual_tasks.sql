@@ -0,0 +1,65 @@
+-- name: CancelManualTasksForExec :exec
+UPDATE manual_tasks SET status = 'cancelled', updated_at = NOW()
+WHERE exec_id = $1
+  AND namespace_id = (SELECT id FROM namespaces WHERE namespaces.uuid = $2)
+  AND status = 'pending';
+
+-- name: CompleteManualTask :one
+-- Completes a pending task. No row is returned if the task was already completed or cancelled.
+UPDATE manual_tasks SET
+    status = 'completed',
+    response = $3,
+    completed_by = (SELECT id FROM users WHERE users.uuid = $4),
+    updated_at = NOW()
+WHERE manual_tasks.uuid = $1
+  AND namespace_id = (SELECT id FROM namespaces WHERE namespaces.uuid = $2)
+  AND status = 'pending'
+RETURNING *;
+
+-- name: GetManualTaskByUUID :one
+SELECT t.*, u.name AS completed_by_name FROM manual_tasks t
+INNER JOIN namespaces n ON t.namespace_id = n.id
+LEFT JOIN users u ON t.completed_by = u.id
+WHERE t.uuid = $1 AND n.uuid = $2;
+
+-- name: GetManualTaskForAction :one
+SELECT t.* FROM manual_tasks t
+INNER JOIN namespaces n ON t.namespace_id = n.id
+WHERE t.exec_id = $1 AND t.action_id = $2 AND n.uuid = $3;
+
+-- name: ListManualTasks :many
+WITH filtered AS (
+    SELECT t.*, u.name AS completed_by_name FROM manual_tasks t
+    LEFT JOIN users u ON t.completed_by = u.id
+    WHERE t.namespace_id = (SELECT id FROM namespaces WHERE namespaces.uuid = $1)
+      AND ($2::text = '' OR t.status = $2::text)
+),
+total AS (
+    SELECT COUNT(*) AS total_count FROM filtered
+),
+paged AS (
+    SELECT * FROM filtered
+    ORDER BY created_at DESC
+    LIMIT $3 OFFSET $4
+)
+SELECT
+    p.*,
+    CEIL(t.total_count::numeric / $3::numeric)::bigint AS page_count,
+    t.total_count
+FROM paged p, total t
+ORDER BY p.created_at DESC;
+
+-- name: RequestManualTask :one
+-- Creates the task of a manual action. A cancelled task is requested again when its execution
+-- is retried. No row is returned if the task is pending or completed.
+INSERT INTO manual_tasks (exec_id, action_id, namespace_id, definition)
+VALUES ($1, $2, (SELECT id FROM namespaces WHERE namespaces.uuid = $3), $4)
+ON CONFLICT (exec_id, action_id) DO UPDATE SET
+    definition = EXCLUDED.definition,
+    status = 'pending',
+    response = NULL,
+    completed_by = NULL,
+    created_at = NOW(),
+    updated_at = NOW()
+WHERE manual_tasks.status = 'cancelled'
+RETURNING *;
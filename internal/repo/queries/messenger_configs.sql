@@ -0,0 +1,15 @@
+-- name: UpsertMessengerConfig :one
+INSERT INTO messenger_configs (channel, enabled, encrypted_config)
+VALUES ($1, $2, $3)
+ON CONFLICT (channel) DO UPDATE SET
+    enabled = EXCLUDED.enabled,
+    encrypted_config = EXCLUDED.encrypted_config,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: ListMessengerConfigs :many
+SELECT * FROM messenger_configs ORDER BY channel;
+
+-- name: DeleteMessengerConfig :one
+DELETE FROM messenger_configs WHERE channel = $1
+RETURNING *;
@@ -0,0 +1,19 @@
+-- name: UpsertNamespaceDefaults :one
+INSERT INTO namespace_defaults (namespace_id, timezone, notify, executor_options, max_concurrency)
+VALUES ((SELECT id FROM namespaces WHERE namespaces.uuid = $1), $2, $3, $4, $5)
+ON CONFLICT (namespace_id) DO UPDATE SET
+    timezone = EXCLUDED.timezone,
+    notify = EXCLUDED.notify,
+    executor_options = EXCLUDED.executor_options,
+    max_concurrency = EXCLUDED.max_concurrency,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: GetNamespaceDefaults :one
+SELECT d.* FROM namespace_defaults d
+INNER JOIN namespaces n ON d.namespace_id = n.id
+WHERE n.uuid = $1;
+
+-- name: DeleteNamespaceDefaults :exec
+DELETE FROM namespace_defaults
+WHERE namespace_id = (SELECT id FROM namespaces WHERE namespaces.uuid = $1);
@@ -0,0 +1,16 @@
+-- name: UpsertNamespaceExecutorPolicy :one
+INSERT INTO namespace_executor_policies (namespace_id, allowed_executors)
+VALUES ((SELECT id FROM namespaces WHERE namespaces.uuid = $1), $2)
+ON CONFLICT (namespace_id) DO UPDATE SET
+    allowed_executors = EXCLUDED.allowed_executors,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: GetNamespaceExecutorPolicy :one
+SELECT p.* FROM namespace_executor_policies p
+INNER JOIN namespaces n ON p.namespace_id = n.id
+WHERE n.uuid = $1;
+
+-- name: DeleteNamespaceExecutorPolicy :exec
+DELETE FROM namespace_executor_policies
+WHERE namespace_id = (SELECT id FROM namespaces WHERE namespaces.uuid = $1);
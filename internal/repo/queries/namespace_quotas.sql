@@ -0,0 +1,60 @@
+-- name: UpsertNamespaceQuota :one
+INSERT INTO namespace_quotas (namespace_id, max_executions_per_day, max_concurrent_executions, max_nodes, max_log_bytes)
+VALUES ((SELECT id FROM namespaces WHERE namespaces.uuid = $1), $2, $3, $4, $5)
+ON CONFLICT (namespace_id) DO UPDATE SET
+    max_executions_per_day = EXCLUDED.max_executions_per_day,
+    max_concurrent_executions = EXCLUDED.max_concurrent_executions,
+    max_nodes = EXCLUDED.max_nodes,
+    max_log_bytes = EXCLUDED.max_log_bytes,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: GetNamespaceQuota :one
+SELECT q.* FROM namespace_quotas q
+INNER JOIN namespaces n ON q.namespace_id = n.id
+WHERE n.uuid = $1;
+
+-- name: DeleteNamespaceQuota :exec
+DELETE FROM namespace_quotas
+WHERE namespace_id = (SELECT id FROM namespaces WHERE namespaces.uuid = $1);
+
+-- name: CountNamespaceExecution :one
+-- Counts an execution started in the namespace today, unless max_executions were already started.
+-- No row is returned when the limit has been reached.
+INSERT INTO namespace_execution_counts (namespace_id, day, executions)
+VALUES ((SELECT id FROM namespaces WHERE namespaces.uuid = sqlc.arg('uuid')), (NOW() AT TIME ZONE 'UTC')::DATE, 1)
+ON CONFLICT (namespace_id, day) DO UPDATE SET
+    executions = namespace_execution_counts.executions + 1
+WHERE sqlc.narg('max_executions')::INTEGER IS NULL
+   OR namespace_execution_counts.executions < sqlc.narg('max_executions')::INTEGER
+RETURNING executions;
+
+-- name: GetNamespaceUsage :one
+-- Concurrent executions are those whose latest version is pending or running, not counting
+-- executions scheduled to run later
+SELECT
+    COALESCE((
+        SELECT c.executions FROM namespace_execution_counts c
+        WHERE c.namespace_id = n.id AND c.day = (NOW() AT TIME ZONE 'UTC')::DATE
+    ), 0)::INTEGER AS executions_today,
+    (
+        SELECT COUNT(*) FROM execution_log el
+        WHERE el.namespace_id = n.id
+          AND el.status IN ('pending', 'running')
+          AND (el.scheduled_at IS NULL OR el.scheduled_at <= NOW())
+          AND NOT EXISTS (
+              SELECT 1 FROM execution_log newer
+              WHERE newer.exec_id = el.exec_id AND newer.version > el.version
+          )
+    ) AS concurrent_executions,
+    (SELECT COUNT(*) FROM nodes nd WHERE nd.namespace_id = n.id) AS nodes,
+    COALESCE((SELECT SUM(u.log_bytes) FROM execution_log_usage u WHERE u.namespace_id = n.id), 0)::BIGINT AS log_bytes
+FROM namespaces n
+WHERE n.uuid = $1;
+
+-- name: AddExecutionLogBytes :exec
+INSERT INTO execution_log_usage (exec_id, namespace_id, log_bytes)
+VALUES ($1, (SELECT id FROM namespaces WHERE namespaces.uuid = $2), $3)
+ON CONFLICT (exec_id) DO UPDATE SET
+    log_bytes = execution_log_usage.log_bytes + EXCLUDED.log_bytes,
+    updated_at = NOW();
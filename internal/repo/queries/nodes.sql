@@ -75,6 +75,36 @@ LEFT JOIN credentials c ON n.credential_id = c.id
 WHERE n.name = ANY($1::text[]) AND ns.uuid = $2
 ORDER BY n.name;
 
+-- name: GetNodesByNamesOrTagsInNamespaces :many
+-- GetNodesByNamesOrTagsInNamespaces returns the nodes of any of the namespaces that have one of the
+-- names or tags, to resolve the nodes of many flows at once
+WITH updated_credentials AS (
+    UPDATE credentials
+    SET last_accessed = NOW()
+    WHERE id IN (
+        SELECT DISTINCT n.credential_id
+        FROM nodes n
+        JOIN namespaces ns ON n.namespace_id = ns.id
+        WHERE ns.uuid = ANY(sqlc.arg('namespace_uuids')::uuid[])
+          AND (n.name = ANY(sqlc.arg('names')::text[]) OR n.tags && sqlc.arg('tags')::text[])
+          AND n.credential_id IS NOT NULL
+    )
+    RETURNING *
+)
+SELECT
+    n.*,
+    ns.uuid AS namespace_uuid,
+    c.uuid AS credential_uuid,
+    c.name AS credential_name,
+    c.key_type AS credential_key_type,
+    c.key_data AS credential_key_data
+FROM nodes n
+JOIN namespaces ns ON n.namespace_id = ns.id
+LEFT JOIN credentials c ON n.credential_id = c.id
+WHERE ns.uuid = ANY(sqlc.arg('namespace_uuids')::uuid[])
+  AND (n.name = ANY(sqlc.arg('names')::text[]) OR n.tags && sqlc.arg('tags')::text[])
+ORDER BY ns.id, n.name;
+
 -- name: GetNodesByTags :many
 WITH updated_credentials AS (
     UPDATE credentials
@@ -108,3 +138,8 @@ SELECT
 FROM nodes n
 JOIN namespaces ns ON n.namespace_id = ns.id
 WHERE ns.uuid = $1;
+
+-- name: ListNodeAddresses :many
+SELECT ns.name AS namespace_name, n.name, n.hostname, n.port FROM nodes n
+JOIN namespaces ns ON n.namespace_id = ns.id
+ORDER BY ns.name, n.name;
@@ -0,0 +1,61 @@
+-- name: UpsertNotificationDelivery :one
+INSERT INTO notification_deliveries (
+    notification_id,
+    namespace_id,
+    exec_id,
+    flow_id,
+    channel,
+    receiver,
+    event,
+    status,
+    attempts,
+    last_error
+) VALUES (
+    sqlc.arg('notification_id'),
+    (SELECT id FROM namespaces WHERE namespaces.uuid = sqlc.arg('namespace_uuid')),
+    sqlc.arg('exec_id'),
+    sqlc.arg('flow_id'),
+    sqlc.arg('channel'),
+    sqlc.arg('receiver'),
+    sqlc.arg('event'),
+    sqlc.arg('status'),
+    sqlc.arg('attempts'),
+    sqlc.arg('last_error')
+)
+ON CONFLICT (notification_id, receiver) DO UPDATE SET
+    status = EXCLUDED.status,
+    attempts = EXCLUDED.attempts,
+    last_error = EXCLUDED.last_error,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: GetDeliveredReceivers :many
+SELECT receiver FROM notification_deliveries
+WHERE notification_id = $1 AND status = 'delivered';
+
+-- name: ListNotificationDeliveriesPaginated :many
+WITH filtered AS (
+    SELECT nd.*
+    FROM notification_deliveries nd
+    JOIN namespaces n ON nd.namespace_id = n.id
+    WHERE n.uuid = sqlc.arg('namespace_uuid')
+      AND (sqlc.arg('status')::text = '' OR nd.status = sqlc.arg('status')::text)
+      AND (sqlc.arg('exec_id')::text = '' OR nd.exec_id = sqlc.arg('exec_id')::text)
+),
+total AS (
+    SELECT COUNT(*) AS total_count FROM filtered
+),
+paged AS (
+    SELECT * FROM filtered
+    ORDER BY updated_at DESC
+    LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset')
+),
+page_count AS (
+    SELECT CEIL(total.total_count::numeric / sqlc.arg('limit')::numeric)::bigint AS page_count
+    FROM total
+)
+SELECT
+    p.*,
+    pc.page_count,
+    t.total_count
+FROM paged p, page_count pc, total t;
@@ -0,0 +1,37 @@
+-- name: ListActiveExecutionsForSLA :many
+WITH active AS (
+    SELECT DISTINCT exec_id FROM execution_log
+    WHERE status IN ('pending', 'running', 'pending_approval')
+), latest AS (
+    SELECT el.exec_id, MAX(el.version) AS max_version, MIN(el.created_at)::TIMESTAMPTZ AS first_created_at
+    FROM execution_log el
+    INNER JOIN active a ON el.exec_id = a.exec_id
+    GROUP BY el.exec_id
+)
+SELECT
+    el.exec_id,
+    el.status,
+    el.scheduled_at,
+    l.first_created_at,
+    f.slug AS flow_slug,
+    n.uuid AS namespace_uuid
+FROM execution_log el
+INNER JOIN latest l ON el.exec_id = l.exec_id AND el.version = l.max_version
+INNER JOIN flows f ON el.flow_id = f.id
+INNER JOIN namespaces n ON el.namespace_id = n.id
+WHERE el.status IN ('pending', 'running', 'pending_approval');
+
+-- name: CreateSLABreach :execrows
+INSERT INTO sla_breaches (
+    exec_id,
+    namespace_id,
+    flow_slug,
+    kind,
+    reason
+) VALUES (
+    sqlc.arg('exec_id'),
+    (SELECT id FROM namespaces WHERE namespaces.uuid = sqlc.arg('namespace_uuid')),
+    sqlc.arg('flow_slug'),
+    sqlc.arg('kind'),
+    sqlc.arg('reason')
+) ON CONFLICT (exec_id, kind) DO NOTHING;
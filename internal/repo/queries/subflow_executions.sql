@@ -0,0 +1,45 @@
+-- name: CreateSubflowExecution :one
+-- Records an execution started by the flow action of a parent execution. No row is returned if
+-- the action already waits on an execution.
+INSERT INTO subflow_executions (parent_exec_id, parent_action_id, exec_id, namespace_id, depth)
+VALUES (
+    sqlc.arg('parent_exec_id'),
+    sqlc.arg('parent_action_id'),
+    sqlc.arg('exec_id'),
+    (SELECT id FROM namespaces WHERE namespaces.uuid = sqlc.arg('namespace_uuid')),
+    sqlc.arg('depth')
+)
+ON CONFLICT DO NOTHING
+RETURNING *;
+
+-- name: DeleteSubflowExecution :exec
+DELETE FROM subflow_executions WHERE exec_id = $1;
+
+-- name: GetSubflowExecution :one
+SELECT * FROM subflow_executions WHERE exec_id = $1;
+
+-- name: GetUnresolvedSubflowExecution :one
+-- Returns the execution the flow action of a parent execution waits on.
+SELECT se.* FROM subflow_executions se
+JOIN namespaces n ON se.namespace_id = n.id
+WHERE se.parent_exec_id = $1 AND se.parent_action_id = $2 AND n.uuid = $3
+  AND se.resolved_at IS NULL;
+
+-- name: ResolveSubflowExecution :exec
+UPDATE subflow_executions SET resolved_at = NOW() WHERE id = $1;
+
+-- name: ListSubflowExecutions :many
+-- Lists the executions started by the flow actions of an execution with their latest status.
+SELECT DISTINCT ON (se.id)
+    se.parent_action_id,
+    se.exec_id,
+    se.created_at,
+    el.status,
+    f.slug AS flow_slug,
+    f.name AS flow_name
+FROM subflow_executions se
+INNER JOIN namespaces n ON se.namespace_id = n.id
+INNER JOIN execution_log el ON el.exec_id = se.exec_id
+INNER JOIN flows f ON el.flow_id = f.id
+WHERE se.parent_exec_id = $1 AND n.uuid = $2
+ORDER BY se.id, el.version DESC;
@@ -0,0 +1,35 @@
+-- name: UpsertGlobalVariable :one
+INSERT INTO global_variables (key, value, description)
+VALUES ($1, $2, $3)
+ON CONFLICT (key) DO UPDATE SET
+    value = EXCLUDED.value,
+    description = EXCLUDED.description,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: ListGlobalVariables :many
+SELECT * FROM global_variables
+ORDER BY key;
+
+-- name: DeleteGlobalVariable :execrows
+DELETE FROM global_variables
+WHERE key = $1;
+
+-- name: UpsertNamespaceVariable :one
+INSERT INTO namespace_variables (namespace_id, key, value, description)
+VALUES ((SELECT id FROM namespaces WHERE namespaces.uuid = $1), $2, $3, $4)
+ON CONFLICT (namespace_id, key) DO UPDATE SET
+    value = EXCLUDED.value,
+    description = EXCLUDED.description,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: ListNamespaceVariables :many
+SELECT v.* FROM namespace_variables v
+INNER JOIN namespaces n ON v.namespace_id = n.id
+WHERE n.uuid = $1
+ORDER BY v.key;
+
+-- name: DeleteNamespaceVariable :execrows
+DELETE FROM namespace_variables
+WHERE key = $1 AND namespace_id = (SELECT id FROM namespaces WHERE namespaces.uuid = $2);
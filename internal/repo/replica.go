@@ -0,0 +1,73 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// The queries below back the list, search and stats endpoints. They only read and are run
+// on the read replica when one is configured. A replica can lag behind the primary, so queries
+// whose results are used to make changes, or that are expected to see a write that was just
+// made, must not be added here. The flow lists stay on the primary since `flowctl apply` plans
+// its creates, updates and deletes from them.
+
+func (p *PostgresStore) GetApprovalsPaginated(ctx context.Context, arg GetApprovalsPaginatedParams) ([]GetApprovalsPaginatedRow, error) {
+	return p.reads.GetApprovalsPaginated(ctx, arg)
+}
+
+func (p *PostgresStore) GetExecutionsByFlowPaginated(ctx context.Context, arg GetExecutionsByFlowPaginatedParams) ([]GetExecutionsByFlowPaginatedRow, error) {
+	return p.reads.GetExecutionsByFlowPaginated(ctx, arg)
+}
+
+func (p *PostgresStore) GetExecutionsForLogSearch(ctx context.Context, arg GetExecutionsForLogSearchParams) ([]GetExecutionsForLogSearchRow, error) {
+	return p.reads.GetExecutionsForLogSearch(ctx, arg)
+}
+
+func (p *PostgresStore) GetNodeStats(ctx context.Context, argUuid uuid.UUID) (GetNodeStatsRow, error) {
+	return p.reads.GetNodeStats(ctx, argUuid)
+}
+
+func (p *PostgresStore) ListArchivedExecutionsPaginated(ctx context.Context, arg ListArchivedExecutionsPaginatedParams) ([]ListArchivedExecutionsPaginatedRow, error) {
+	return p.reads.ListArchivedExecutionsPaginated(ctx, arg)
+}
+
+func (p *PostgresStore) ListCronScheduleRuns(ctx context.Context, argUuid uuid.UUID) ([]ListCronScheduleRunsRow, error) {
+	return p.reads.ListCronScheduleRuns(ctx, argUuid)
+}
+
+func (p *PostgresStore) ListFailedExecutions(ctx context.Context, arg ListFailedExecutionsParams) ([]ListFailedExecutionsRow, error) {
+	return p.reads.ListFailedExecutions(ctx, arg)
+}
+
+func (p *PostgresStore) ListFailedNodes(ctx context.Context, arg ListFailedNodesParams) ([]ListFailedNodesRow, error) {
+	return p.reads.ListFailedNodes(ctx, arg)
+}
+
+func (p *PostgresStore) ListNotificationDeliveriesPaginated(ctx context.Context, arg ListNotificationDeliveriesPaginatedParams) ([]ListNotificationDeliveriesPaginatedRow, error) {
+	return p.reads.ListNotificationDeliveriesPaginated(ctx, arg)
+}
+
+func (p *PostgresStore) SearchCredentials(ctx context.Context, arg SearchCredentialsParams) ([]SearchCredentialsRow, error) {
+	return p.reads.SearchCredentials(ctx, arg)
+}
+
+func (p *PostgresStore) SearchExecutionsPaginated(ctx context.Context, arg SearchExecutionsPaginatedParams) ([]SearchExecutionsPaginatedRow, error) {
+	return p.reads.SearchExecutionsPaginated(ctx, arg)
+}
+
+func (p *PostgresStore) SearchGroup(ctx context.Context, arg SearchGroupParams) ([]SearchGroupRow, error) {
+	return p.reads.SearchGroup(ctx, arg)
+}
+
+func (p *PostgresStore) SearchNodes(ctx context.Context, arg SearchNodesParams) ([]SearchNodesRow, error) {
+	return p.reads.SearchNodes(ctx, arg)
+}
+
+func (p *PostgresStore) SearchUsersWithGroups(ctx context.Context, arg SearchUsersWithGroupsParams) ([]SearchUsersWithGroupsRow, error) {
+	return p.reads.SearchUsersWithGroups(ctx, arg)
+}
+
+func (p *PostgresStore) SummarizeExpiredExecutions(ctx context.Context, arg SummarizeExpiredExecutionsParams) ([]SummarizeExpiredExecutionsRow, error) {
+	return p.reads.SummarizeExpiredExecutions(ctx, arg)
+}
@@ -0,0 +1,574 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"modernc.org/sqlite"
+)
+
+// SQLiteDriverName is the database/sql driver SQLite databases are opened with. It is the
+// modernc.org/sqlite driver with the queries generated by sqlc translated from PostgreSQL, so
+// that the same Store works with both databases.
+const SQLiteDriverName = "flowctl-sqlite"
+
+// sqliteTimeFormat is the format timestamps are stored in. Timestamps are stored in UTC so
+// that they sort in time order as text.
+const sqliteTimeFormat = "2006-01-02 15:04:05.000000-07:00"
+
+func init() {
+	// Functions are registered with the driver modernc.org/sqlite registers itself as
+	db, err := sql.Open("sqlite", "")
+	if err != nil {
+		panic(err)
+	}
+	sql.Register(SQLiteDriverName, &sqliteDriver{driver: db.Driver()})
+	db.Close()
+	sqlx.BindDriver(SQLiteDriverName, sqlx.QUESTION)
+
+	// Functions of PostgreSQL used by the schema and the queries
+	sqlite.MustRegisterScalarFunction("now", 0, func(_ *sqlite.FunctionContext, _ []driver.Value) (driver.Value, error) {
+		return time.Now().UTC().Format(sqliteTimeFormat), nil
+	})
+	sqlite.MustRegisterScalarFunction("uuid_generate_v4", 0, func(_ *sqlite.FunctionContext, _ []driver.Value) (driver.Value, error) {
+		return uuid.NewString(), nil
+	})
+	sqlite.MustRegisterDeterministicScalarFunction("websearch_match", 2, func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		document, _ := args[0].(string)
+		query, _ := args[1].(string)
+		return websearchMatch(document, query), nil
+	})
+	sqlite.MustRegisterFunction("median", &sqlite.FunctionImpl{
+		NArgs:         1,
+		Deterministic: true,
+		MakeAggregate: func(sqlite.FunctionContext) (sqlite.AggregateFunction, error) {
+			return &medianAggregate{}, nil
+		},
+	})
+}
+
+// OpenSQLite opens the SQLite database at path, creating it if it doesn't exist.
+// Transactions take the write lock when they begin so that two transactions never wait on
+// each other, a connection waits up to 10 seconds for the lock.
+func OpenSQLite(path string) (*sqlx.DB, error) {
+	dsn := "file:" + path + "?_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)&_pragma=busy_timeout(10000)&_txlock=immediate"
+	db, err := sqlx.Open(SQLiteDriverName, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("could not open sqlite database %s: %w", path, err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("could not open sqlite database %s: %w", path, err)
+	}
+	return db, nil
+}
+
+// NewSQLiteStore creates a store for a database opened with OpenSQLite. The store runs the
+// same queries as a PostgreSQL store, the driver translates them.
+func NewSQLiteStore(db *sqlx.DB, opts ...StoreOption) Store {
+	return NewPostgresStore(db, opts...)
+}
+
+// SQLiteExecutionEvent is an execution status change recorded by the triggers of the SQLite
+// schema. Payload is the JSON PostgreSQL publishes on the execution events channel.
+type SQLiteExecutionEvent struct {
+	ID      int64
+	Payload []byte
+}
+
+// ListSQLiteExecutionEvents returns the execution events recorded after the event afterID,
+// oldest first
+func ListSQLiteExecutionEvents(ctx context.Context, db DBTX, afterID int64) ([]SQLiteExecutionEvent, error) {
+	rows, err := db.QueryContext(ctx, "SELECT id, payload FROM execution_events WHERE id > $1 ORDER BY id", afterID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []SQLiteExecutionEvent
+	for rows.Next() {
+		var e SQLiteExecutionEvent
+		if err := rows.Scan(&e.ID, &e.Payload); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// DeleteSQLiteExecutionEvents deletes the execution events up to and including the event upToID
+func DeleteSQLiteExecutionEvents(ctx context.Context, db DBTX, upToID int64) error {
+	_, err := db.ExecContext(ctx, "DELETE FROM execution_events WHERE id <= $1", upToID)
+	return err
+}
+
+type sqliteDriver struct {
+	driver driver.Driver
+}
+
+// sqliteDriverConn is the connection of the modernc.org/sqlite driver
+type sqliteDriverConn interface {
+	driver.Conn
+	driver.ConnBeginTx
+	driver.ConnPrepareContext
+	driver.ExecerContext
+	driver.QueryerContext
+	driver.Pinger
+	driver.SessionResetter
+	driver.Validator
+}
+
+func (d *sqliteDriver) Open(name string) (driver.Conn, error) {
+	c, err := d.driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	conn, ok := c.(sqliteDriverConn)
+	if !ok {
+		c.Close()
+		return nil, fmt.Errorf("unexpected sqlite connection %T", c)
+	}
+	return &sqliteConn{conn: conn}, nil
+}
+
+// sqliteConn translates queries and arguments to SQLite and results back to what the
+// PostgreSQL driver returns
+type sqliteConn struct {
+	conn sqliteDriverConn
+	inTx bool
+}
+
+func (c *sqliteConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *sqliteConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	q := sqliteQuery(query)
+	if q.multiple() {
+		return nil, fmt.Errorf("query %s can't be prepared on sqlite", QueryName(query))
+	}
+	s, err := c.conn.PrepareContext(ctx, q.query)
+	if err != nil {
+		return nil, err
+	}
+	stmt, ok := s.(sqliteDriverStmt)
+	if !ok {
+		s.Close()
+		return nil, fmt.Errorf("unexpected sqlite statement %T", s)
+	}
+	return &sqliteStmt{stmt: stmt}, nil
+}
+
+func (c *sqliteConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *sqliteConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	tx, err := c.conn.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	c.inTx = true
+	return &sqliteTx{tx: tx, conn: c}, nil
+}
+
+func (c *sqliteConn) Close() error {
+	return c.conn.Close()
+}
+
+func (c *sqliteConn) Ping(ctx context.Context) error {
+	return c.conn.Ping(ctx)
+}
+
+func (c *sqliteConn) ResetSession(ctx context.Context) error {
+	return c.conn.ResetSession(ctx)
+}
+
+func (c *sqliteConn) IsValid() bool {
+	return c.conn.IsValid()
+}
+
+func (c *sqliteConn) CheckNamedValue(nv *driver.NamedValue) error {
+	v, err := sqliteValue(nv.Value)
+	if err != nil {
+		return err
+	}
+	nv.Value = v
+	return nil
+}
+
+func (c *sqliteConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	q := sqliteQuery(query)
+	if !q.multiple() {
+		return c.conn.ExecContext(ctx, q.query, args)
+	}
+
+	var res driver.Result
+	err := c.transaction(ctx, func() error {
+		if err := c.execAll(ctx, q.before, args); err != nil {
+			return err
+		}
+		var err error
+		if res, err = c.conn.ExecContext(ctx, q.query, args); err != nil {
+			return err
+		}
+		return c.execAll(ctx, q.after, args)
+	})
+	return res, err
+}
+
+func (c *sqliteConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	q := sqliteQuery(query)
+	if !q.multiple() {
+		rows, err := c.conn.QueryContext(ctx, q.query, args)
+		if err != nil {
+			return nil, err
+		}
+		return newSQLiteRows(rows), nil
+	}
+
+	// The statements after the query can only run once its rows are read
+	var res *sqliteBufferedRows
+	err := c.transaction(ctx, func() error {
+		if err := c.execAll(ctx, q.before, args); err != nil {
+			return err
+		}
+		rows, err := c.conn.QueryContext(ctx, q.query, args)
+		if err != nil {
+			return err
+		}
+		if res, err = bufferRows(newSQLiteRows(rows)); err != nil {
+			return err
+		}
+		return c.execAll(ctx, q.after, args)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (c *sqliteConn) execAll(ctx context.Context, statements []string, args []driver.NamedValue) error {
+	for _, s := range statements {
+		if _, err := c.conn.ExecContext(ctx, s, args); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// transaction runs fn in a transaction, or in a savepoint of the transaction the connection is in
+func (c *sqliteConn) transaction(ctx context.Context, fn func() error) error {
+	begin, commit, rollback := "BEGIN IMMEDIATE", "COMMIT", []string{"ROLLBACK"}
+	if c.inTx {
+		begin, commit, rollback = "SAVEPOINT flowctl_query", "RELEASE flowctl_query", []string{"ROLLBACK TO flowctl_query", "RELEASE flowctl_query"}
+	}
+
+	if _, err := c.conn.ExecContext(ctx, begin, nil); err != nil {
+		return err
+	}
+	if err := fn(); err != nil {
+		for _, s := range rollback {
+			c.conn.ExecContext(context.Background(), s, nil)
+		}
+		return err
+	}
+	_, err := c.conn.ExecContext(ctx, commit, nil)
+	return err
+}
+
+type sqliteTx struct {
+	tx   driver.Tx
+	conn *sqliteConn
+}
+
+func (t *sqliteTx) Commit() error {
+	t.conn.inTx = false
+	return t.tx.Commit()
+}
+
+func (t *sqliteTx) Rollback() error {
+	t.conn.inTx = false
+	return t.tx.Rollback()
+}
+
+// sqliteDriverStmt is the prepared statement of the modernc.org/sqlite driver
+type sqliteDriverStmt interface {
+	driver.Stmt
+	driver.StmtExecContext
+	driver.StmtQueryContext
+}
+
+type sqliteStmt struct {
+	stmt sqliteDriverStmt
+}
+
+func (s *sqliteStmt) Close() error {
+	return s.stmt.Close()
+}
+
+func (s *sqliteStmt) NumInput() int {
+	return s.stmt.NumInput()
+}
+
+func (s *sqliteStmt) CheckNamedValue(nv *driver.NamedValue) error {
+	v, err := sqliteValue(nv.Value)
+	if err != nil {
+		return err
+	}
+	nv.Value = v
+	return nil
+}
+
+func (s *sqliteStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), namedValues(args))
+}
+
+func (s *sqliteStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return s.stmt.ExecContext(ctx, args)
+}
+
+func (s *sqliteStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), namedValues(args))
+}
+
+func (s *sqliteStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	rows, err := s.stmt.QueryContext(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return newSQLiteRows(rows), nil
+}
+
+func namedValues(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, v := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: v}
+	}
+	return named
+}
+
+// sqliteValue converts an argument of a query. Arrays are passed as JSON arrays, timestamps
+// in sqliteTimeFormat and JSON as text.
+func sqliteValue(v any) (driver.Value, error) {
+	if a, ok := sqliteArray(v); ok {
+		if !a.IsValid() || a.IsNil() {
+			return nil, nil
+		}
+		elems := make([]any, a.Len())
+		for i := range elems {
+			e, err := sqliteValue(a.Index(i).Interface())
+			if err != nil {
+				return nil, err
+			}
+			elems[i] = e
+		}
+		b, err := json.Marshal(elems)
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+	}
+
+	v, err := driver.DefaultParameterConverter.ConvertValue(v)
+	if err != nil {
+		return nil, err
+	}
+	switch v := v.(type) {
+	case time.Time:
+		return v.UTC().Format(sqliteTimeFormat), nil
+	case []byte:
+		return string(v), nil
+	}
+	return v, nil
+}
+
+// sqliteArray returns the slice of an argument wrapped with pq.Array
+func sqliteArray(v any) (reflect.Value, bool) {
+	switch a := v.(type) {
+	case pq.GenericArray:
+		v = a.A
+	case *pq.StringArray, *pq.Int64Array, *pq.Int32Array, *pq.Float64Array, *pq.Float32Array, *pq.BoolArray:
+	default:
+		return reflect.Value{}, false
+	}
+
+	a := reflect.ValueOf(v)
+	for a.Kind() == reflect.Pointer {
+		if a.IsNil() {
+			return reflect.Value{}, true
+		}
+		a = a.Elem()
+	}
+	return a, a.Kind() == reflect.Slice
+}
+
+// sqliteRows returns text as []byte, timestamps as UTC times and arrays as PostgreSQL array
+// literals like the PostgreSQL driver does
+type sqliteRows struct {
+	driver.Rows
+	types []string
+}
+
+func newSQLiteRows(rows driver.Rows) *sqliteRows {
+	r := &sqliteRows{Rows: rows, types: make([]string, len(rows.Columns()))}
+	if t, ok := rows.(driver.RowsColumnTypeDatabaseTypeName); ok {
+		for i := range r.types {
+			r.types[i] = t.ColumnTypeDatabaseTypeName(i)
+		}
+	}
+	return r
+}
+
+func (r *sqliteRows) Next(dest []driver.Value) error {
+	if err := r.Rows.Next(dest); err != nil {
+		return err
+	}
+	for i, v := range dest {
+		dest[i] = sqliteColumnValue(v, r.types[i])
+	}
+	return nil
+}
+
+func sqliteColumnValue(v driver.Value, typ string) driver.Value {
+	switch v := v.(type) {
+	case time.Time:
+		return v.UTC()
+	case string:
+		if strings.HasSuffix(typ, "[]") {
+			var a []string
+			if err := json.Unmarshal([]byte(v), &a); err == nil {
+				if l, err := pq.StringArray(a).Value(); err == nil {
+					return []byte(l.(string))
+				}
+			}
+		}
+		// Expressions such as MAX(created_at) have no type, their timestamps are parsed here
+		if typ == "" {
+			if t, err := time.Parse("2006-01-02 15:04:05.999999999-07:00", v); err == nil {
+				return t.UTC()
+			}
+		}
+		return []byte(v)
+	}
+	return v
+}
+
+// sqliteBufferedRows are the rows of a query read before the statements after it ran
+type sqliteBufferedRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func bufferRows(rows driver.Rows) (*sqliteBufferedRows, error) {
+	defer rows.Close()
+
+	b := &sqliteBufferedRows{columns: rows.Columns()}
+	for {
+		dest := make([]driver.Value, len(b.columns))
+		err := rows.Next(dest)
+		if errors.Is(err, io.EOF) {
+			return b, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		for i, v := range dest {
+			if v, ok := v.([]byte); ok {
+				dest[i] = append([]byte(nil), v...)
+			}
+		}
+		b.rows = append(b.rows, dest)
+	}
+}
+
+func (b *sqliteBufferedRows) Columns() []string {
+	return b.columns
+}
+
+func (b *sqliteBufferedRows) Close() error {
+	return nil
+}
+
+func (b *sqliteBufferedRows) Next(dest []driver.Value) error {
+	if len(b.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, b.rows[0])
+	b.rows = b.rows[1:]
+	return nil
+}
+
+// websearchMatch reports whether document contains every word of query, ignoring case. Like
+// websearch_to_tsquery, words starting with - must not be in document and "or" matches
+// either of the words around it.
+func websearchMatch(document, query string) bool {
+	document = strings.ToLower(document)
+
+	matched := false
+	for _, alternative := range strings.Split(" "+strings.ToLower(query)+" ", " or ") {
+		words := 0
+		ok := true
+		for _, w := range strings.Fields(alternative) {
+			exclude := strings.HasPrefix(w, "-")
+			for _, part := range strings.FieldsFunc(w, func(r rune) bool {
+				return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+			}) {
+				words++
+				if strings.Contains(document, part) == exclude {
+					ok = false
+				}
+			}
+		}
+		if words > 0 && ok {
+			matched = true
+		}
+	}
+	return matched
+}
+
+// medianAggregate is percentile_cont(0.5) of PostgreSQL
+type medianAggregate struct {
+	values []float64
+}
+
+func (m *medianAggregate) Step(_ *sqlite.FunctionContext, args []driver.Value) error {
+	switch v := args[0].(type) {
+	case int64:
+		m.values = append(m.values, float64(v))
+	case float64:
+		m.values = append(m.values, v)
+	}
+	return nil
+}
+
+func (m *medianAggregate) WindowInverse(*sqlite.FunctionContext, []driver.Value) error {
+	return errors.New("median can't be used as a window function")
+}
+
+func (m *medianAggregate) WindowValue(*sqlite.FunctionContext) (driver.Value, error) {
+	if len(m.values) == 0 {
+		return nil, nil
+	}
+	sort.Float64s(m.values)
+	mid := len(m.values) / 2
+	if len(m.values)%2 == 1 {
+		return m.values[mid], nil
+	}
+	return (m.values[mid-1] + m.values[mid]) / 2, nil
+}
+
+func (m *medianAggregate) Final(*sqlite.FunctionContext) {}
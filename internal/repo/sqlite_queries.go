@@ -0,0 +1,541 @@
+package repo
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// sqliteStatements are the statements a query runs on SQLite. Queries that change several
+// tables in PostgreSQL with data modifying CTEs run the changes as separate statements
+// before the query, in a transaction.
+type sqliteStatements struct {
+	before []string
+	query  string
+	after  []string
+}
+
+func (s sqliteStatements) multiple() bool {
+	return len(s.before) > 0 || len(s.after) > 0
+}
+
+// sqliteQueries caches the translation of every query run
+var sqliteQueries sync.Map
+
+// sqliteQuery translates a query generated by sqlc to SQLite. Other queries, such as the
+// ones of the casbin adapter, are run as is.
+func sqliteQuery(query string) sqliteStatements {
+	if !strings.HasPrefix(query, "-- name: ") {
+		return sqliteStatements{query: query}
+	}
+	if s, ok := sqliteQueries.Load(query); ok {
+		return s.(sqliteStatements)
+	}
+
+	s, ok := sqliteOverrides[QueryName(query)]
+	if !ok {
+		s = sqliteStatements{query: query}
+	}
+	t := sqliteStatements{query: sqliteTranslate(s.query)}
+	for _, b := range s.before {
+		t.before = append(t.before, sqliteTranslate(b))
+	}
+	for _, a := range s.after {
+		t.after = append(t.after, sqliteTranslate(a))
+	}
+
+	sqliteQueries.Store(query, t)
+	return t
+}
+
+// sqliteRewrites rewrite the PostgreSQL syntax used by the queries, in order. Arrays are
+// passed as JSON arrays, see sqliteValue.
+var sqliteRewrites = []struct {
+	re   *regexp.Regexp
+	with string
+}{
+	// Flow search, flows are matched by the words of their content instead of a text search vector
+	{regexp.MustCompile(`(?s)\(to_tsvector\('english', (.+?)\)\s+\|\| COALESCE\(fs\.document, ''::tsvector\)\) @@ websearch_to_tsquery\('english', (\$\d+)::text\)`), "websearch_match(${1} || ' ' || COALESCE(fs.document, ''), ${2})"},
+	{regexp.MustCompile(`to_tsvector\('english', ([^)]+)\)`), "lower(${1})"},
+	// Page counts
+	{regexp.MustCompile(`CEIL\(([\w.]+)::numeric / (\$\d+)::numeric\)::bigint`), "((${1} + ${2} - 1) / ${2})"},
+	// Arrays
+	{regexp.MustCompile(`= ANY\((\$\d+)::\w+\[\]\)`), "IN (SELECT value FROM json_each(${1}))"},
+	{regexp.MustCompile(`([\w.]+) && (\$\d+)::\w+\[\]`), "EXISTS (SELECT 1 FROM json_each(${1}) WHERE value IN (SELECT value FROM json_each(${2})))"},
+	{regexp.MustCompile(`array_length\((\$\d+)::\w+\[\], 1\)`), "NULLIF(json_array_length(${1}), 0)"},
+	// Latest version of each execution
+	{regexp.MustCompile(`(?s)SELECT DISTINCT ON \(([\w.]+)\)\s+(.*?)\s+FROM (.*?)\s+ORDER BY [\w.]+, ([^\n]+)\n(\s*)\) live`), "SELECT * FROM (SELECT ${2}, ROW_NUMBER() OVER (PARTITION BY ${1} ORDER BY ${4}) AS version_rank FROM ${3}) WHERE version_rank = 1\n${5}) live"},
+	{regexp.MustCompile(`\(NOW\(\) AT TIME ZONE 'UTC'\)::DATE`), "date('now')"},
+	{regexp.MustCompile(`\bILIKE\b`), "LIKE"},
+	{regexp.MustCompile(`\bIS NOT DISTINCT FROM\b`), "IS"},
+	{regexp.MustCompile(`\bGREATEST\(`), "max("},
+	// SQLite needs AS before the alias of the table of an UPDATE or DELETE
+	{regexp.MustCompile(`(?m)^(UPDATE|DELETE FROM) (\w+) ([a-z]{1,3})\b`), "${1} ${2} AS ${3}"},
+	{regexp.MustCompile(`ON CONFLICT ON CONSTRAINT unique_group_namespace`), "ON CONFLICT (group_id, namespace_id)"},
+	{regexp.MustCompile(`ON CONFLICT ON CONSTRAINT unique_user_namespace`), "ON CONFLICT (user_id, namespace_id)"},
+	{regexp.MustCompile(`ON CONFLICT ON CONSTRAINT unique_group_prefix`), "ON CONFLICT (group_id, namespace_id, prefix_id)"},
+	{regexp.MustCompile(`ON CONFLICT ON CONSTRAINT unique_user_prefix`), "ON CONFLICT (user_id, namespace_id, prefix_id)"},
+	// Casts, last since the rewrites above match some of them
+	{regexp.MustCompile(`::\s*[A-Za-z_]+( PRECISION)?(\[\])?`), ""},
+}
+
+func sqliteTranslate(query string) string {
+	for _, r := range sqliteRewrites {
+		query = r.re.ReplaceAllString(query, r.with)
+	}
+	return query
+}
+
+// sqliteOverrides replace the queries that can't be rewritten, they are written for
+// PostgreSQL like the queries they replace and rewritten with sqliteRewrites.
+var sqliteOverrides = map[string]sqliteStatements{
+	// SQLite serves a single flowctl instance, it holds every lock
+	"TryAdvisoryLock": {query: `SELECT TRUE AS locked`},
+	"AdvisoryUnlock":  {query: `SELECT TRUE AS unlocked`},
+	// Transactions take the write lock when they begin, see OpenSQLite
+	"LockExecutionSlots": {query: `SELECT 1`},
+
+	"ListSubflowExecutions": {query: `
+SELECT parent_action_id, exec_id, created_at, status, flow_slug, flow_name FROM (
+    SELECT
+        se.id,
+        se.parent_action_id,
+        se.exec_id,
+        se.created_at,
+        el.status,
+        f.slug AS flow_slug,
+        f.name AS flow_name,
+        ROW_NUMBER() OVER (PARTITION BY se.id ORDER BY el.version DESC) AS version_rank
+    FROM subflow_executions se
+    INNER JOIN namespaces n ON se.namespace_id = n.id
+    INNER JOIN execution_log el ON el.exec_id = se.exec_id
+    INNER JOIN flows f ON el.flow_id = f.id
+    WHERE se.parent_exec_id = $1 AND n.uuid = $2
+)
+WHERE version_rank = 1
+ORDER BY id`},
+
+	"IncrementActionRetry": {query: `
+WITH namespace_lookup AS (
+    SELECT id FROM namespaces WHERE namespaces.uuid = $3
+), latest_version AS (
+    SELECT MAX(version) as version
+    FROM execution_log el
+    WHERE el.exec_id = $1 AND el.namespace_id = (SELECT id FROM namespace_lookup)
+)
+UPDATE execution_log el
+SET
+    action_retries = json_set(
+        COALESCE(action_retries, '{}'),
+        '$."' || $2 || '"',
+        COALESCE(json_extract(action_retries, '$."' || $2 || '"'), 0) + 1
+    ),
+    updated_at = NOW()
+WHERE el.exec_id = $1
+  AND el.version = (SELECT version FROM latest_version)
+  AND el.namespace_id = (SELECT id FROM namespace_lookup)
+RETURNING
+    action_retries,
+    json_extract(action_retries, '$."' || $2 || '"') as retry_count`},
+
+	"StartExecutionAction": {query: `
+WITH namespace_lookup AS (
+    SELECT id FROM namespaces WHERE namespaces.uuid = $1
+), latest_version AS (
+    SELECT MAX(version) as version
+    FROM execution_log el
+    WHERE el.exec_id = $2 AND el.namespace_id = (SELECT id FROM namespace_lookup)
+)
+UPDATE execution_log el
+SET
+    current_action_id = $3,
+    action_retries = json_set(
+        COALESCE(action_retries, '{}'),
+        '$."' || $3 || '"',
+        COALESCE(json_extract(action_retries, '$."' || $3 || '"'), 0) + 1
+    ),
+    updated_at = NOW()
+WHERE el.exec_id = $2
+  AND el.version = (SELECT version FROM latest_version)
+  AND el.namespace_id = (SELECT id FROM namespace_lookup)
+RETURNING json_extract(action_retries, '$."' || $3 || '"') as retry_count`},
+
+	"DeleteUserScheduleByUUID": {query: `
+WITH user_namespaces AS (
+    SELECT n.uuid, n.name, nm.role
+    FROM namespaces n
+    JOIN namespace_members nm ON n.id = nm.namespace_id
+    JOIN users u ON nm.user_id = u.id
+    WHERE u.uuid = $2
+
+    UNION
+
+    SELECT DISTINCT n.uuid, n.name, nm.role
+    FROM namespaces n
+    JOIN namespace_members nm ON n.id = nm.namespace_id
+    JOIN groups g ON nm.group_id = g.id
+    JOIN group_memberships gm ON g.id = gm.group_id
+    WHERE gm.user_id = (SELECT id FROM users WHERE users.uuid = $2)
+)
+DELETE FROM cron_schedules cs
+WHERE cs.uuid = $1
+  AND cs.flow_id IN (SELECT f.id FROM flows f WHERE f.namespace_id = (SELECT id FROM namespaces WHERE namespaces.uuid = $3))
+  AND cs.is_user_created = TRUE
+  AND (cs.created_by = (SELECT id FROM users WHERE users.uuid = $2)
+        OR EXISTS (SELECT id FROM users WHERE  users.uuid = $2 AND users.role='superuser')
+        OR EXISTS (SELECT user_namespaces.uuid FROM user_namespaces WHERE user_namespaces.role='admin')
+  )`},
+
+	// SQLite can't return the columns of the other tables of an UPDATE, or qualify the columns
+	// of the updated table
+	"UpdateUserScheduleByUUID": {query: `
+WITH user_namespaces AS (
+    SELECT n.uuid, n.name, nm.role
+    FROM namespaces n
+    JOIN namespace_members nm ON n.id = nm.namespace_id
+    JOIN users u ON nm.user_id = u.id
+    WHERE u.uuid = $6
+
+    UNION
+
+    SELECT DISTINCT n.uuid, n.name, nm.role
+    FROM namespaces n
+    JOIN namespace_members nm ON n.id = nm.namespace_id
+    JOIN groups g ON nm.group_id = g.id
+    JOIN group_memberships gm ON g.id = gm.group_id
+    WHERE gm.user_id = (SELECT id FROM users WHERE users.uuid = $6)
+)
+UPDATE cron_schedules cs
+SET
+    cron = $2,
+    timezone = $3,
+    inputs = $4,
+    is_active = $5,
+    updated_at = NOW()
+FROM flows f
+WHERE cs.uuid = $1
+  AND cs.flow_id = f.id
+  AND cs.is_user_created = TRUE
+  AND f.namespace_id = (SELECT id FROM namespaces WHERE namespaces.uuid = $7)
+  AND (cs.created_by = (SELECT id FROM users WHERE users.uuid = $6)
+        OR EXISTS (SELECT id FROM users WHERE  users.uuid = $6 AND users.role='superuser')
+        OR EXISTS (SELECT user_namespaces.uuid FROM user_namespaces WHERE user_namespaces.role='admin')
+  )
+RETURNING id, flow_id, cron, timezone, created_at, updated_at, uuid, inputs, created_by, is_user_created, is_active`},
+
+	"GetAPITokenOwnerByHash": {
+		before: []string{`
+UPDATE api_tokens t SET last_used_at = CASE
+    WHEN t.expires_at IS NULL OR t.expires_at > NOW() THEN NOW()
+    ELSE t.last_used_at
+END
+WHERE t.token_hash = $1`},
+		query: `
+SELECT u.uuid AS user_uuid, t.expires_at
+FROM api_tokens t
+JOIN users u ON t.user_id = u.id
+WHERE t.token_hash = $1 AND changes() > 0`,
+	},
+
+	"GetActionDurationEstimates": {query: `
+WITH recent AS (
+    SELECT exec_id FROM execution_log
+    WHERE flow_id = $1 AND status = 'completed'
+    ORDER BY completed_at DESC NULLS LAST, id DESC
+    LIMIT $2
+)
+SELECT
+    et.action_id,
+    median(unixepoch(et.finished_at, 'subsec') - unixepoch(et.started_at, 'subsec')) AS median_seconds,
+    COUNT(*) AS samples
+FROM execution_timeline et
+WHERE et.exec_id IN (SELECT exec_id FROM recent)
+  AND et.node = ''
+  AND et.status = 'success'
+  AND et.finished_at IS NOT NULL
+GROUP BY et.action_id`},
+
+	"RecordExecutionTimelineEntries": {query: `
+INSERT INTO execution_timeline (
+    exec_id,
+    namespace_id,
+    action_id,
+    node,
+    retry,
+    status,
+    error,
+    started_at,
+    finished_at
+)
+SELECT
+    $1,
+    n.id,
+    action_id.value,
+    node.value,
+    retry.value,
+    status.value,
+    NULLIF(error.value, ''),
+    started_at.value,
+    CASE WHEN finished.value THEN finished_at.value END
+FROM namespaces n
+JOIN json_each($2) action_id
+JOIN json_each($3) node ON node.key = action_id.key
+JOIN json_each($4) retry ON retry.key = action_id.key
+JOIN json_each($5) status ON status.key = action_id.key
+JOIN json_each($6) error ON error.key = action_id.key
+JOIN json_each($7) started_at ON started_at.key = action_id.key
+JOIN json_each($8) finished ON finished.key = action_id.key
+JOIN json_each($9) finished_at ON finished_at.key = action_id.key
+WHERE n.uuid = $10
+ON CONFLICT (exec_id, action_id, node, retry) DO UPDATE SET
+    status = EXCLUDED.status,
+    error = EXCLUDED.error,
+    started_at = EXCLUDED.started_at,
+    finished_at = EXCLUDED.finished_at`},
+
+	"PurgeExecutions": {
+		before: []string{
+			`DELETE FROM execution_progress WHERE exec_id = ANY($1::TEXT[])`,
+			`DELETE FROM execution_timeline WHERE exec_id = ANY($1::TEXT[])`,
+			`DELETE FROM execution_outputs WHERE exec_id = ANY($1::TEXT[])`,
+			`DELETE FROM execution_action_skips WHERE exec_id = ANY($1::TEXT[])`,
+			`DELETE FROM execution_node_telemetry WHERE exec_id = ANY($1::TEXT[])`,
+			`DELETE FROM execution_environments WHERE exec_id = ANY($1::TEXT[])`,
+			`DELETE FROM notification_deliveries WHERE exec_id = ANY($1::TEXT[])`,
+			`DELETE FROM sla_breaches WHERE exec_id = ANY($1::TEXT[])`,
+			`DELETE FROM execution_archive WHERE exec_id = ANY($1::TEXT[])`,
+			`DELETE FROM execution_log_usage WHERE exec_id = ANY($1::TEXT[])`,
+			`DELETE FROM subflow_executions WHERE exec_id = ANY($1::TEXT[]) OR parent_exec_id = ANY($1::TEXT[])`,
+			`DELETE FROM artifacts WHERE exec_id = ANY($1::TEXT[])`,
+			`UPDATE cron_schedule_runs SET exec_id = NULL WHERE exec_id = ANY($1::TEXT[])`,
+		},
+		query: `DELETE FROM execution_log WHERE exec_id = ANY($1::TEXT[])`,
+	},
+
+	"ArchiveExecutions": {
+		before: []string{`
+CREATE TEMP TABLE archive_candidates AS
+WITH latest AS (
+    SELECT exec_id, MAX(version) AS max_version, MAX(updated_at) AS last_updated_at
+    FROM execution_log
+    GROUP BY exec_id
+    HAVING MAX(updated_at) < $1
+)
+SELECT el.*
+FROM execution_log el
+INNER JOIN latest l ON el.exec_id = l.exec_id AND el.version = l.max_version
+WHERE el.status IN ('completed', 'errored', 'cancelled')
+ORDER BY l.last_updated_at
+LIMIT $2`, `
+INSERT INTO execution_archive (
+    exec_id,
+    namespace_id,
+    flow_slug,
+    flow_name,
+    status,
+    trigger_type,
+    triggered_by_uuid,
+    triggered_by_name,
+    input,
+    error,
+    current_action_id,
+    action_retries,
+    created_at,
+    started_at,
+    completed_at,
+    scheduled_at
+)
+SELECT
+    c.exec_id,
+    c.namespace_id,
+    f.slug,
+    f.name,
+    c.status,
+    c.trigger_type,
+    u.uuid,
+    CONCAT(u.name, ' <', u.username, '>'),
+    c.input,
+    c.error,
+    c.current_action_id,
+    c.action_retries,
+    c.created_at,
+    c.started_at,
+    c.completed_at,
+    c.scheduled_at
+FROM archive_candidates c
+INNER JOIN flows f ON c.flow_id = f.id
+INNER JOIN users u ON c.triggered_by = u.id
+WHERE TRUE
+ON CONFLICT (exec_id) DO NOTHING`,
+			`CREATE TEMP TABLE archived_executions AS SELECT changes() AS count`,
+			`DELETE FROM execution_progress WHERE exec_id IN (SELECT exec_id FROM archive_candidates)`,
+			`DELETE FROM execution_timeline WHERE exec_id IN (SELECT exec_id FROM archive_candidates)`,
+			`DELETE FROM execution_outputs WHERE exec_id IN (SELECT exec_id FROM archive_candidates)`,
+			`DELETE FROM execution_action_skips WHERE exec_id IN (SELECT exec_id FROM archive_candidates)`,
+			`DELETE FROM execution_node_telemetry WHERE exec_id IN (SELECT exec_id FROM archive_candidates)`,
+			`DELETE FROM execution_environments WHERE exec_id IN (SELECT exec_id FROM archive_candidates)`,
+			`DELETE FROM execution_log WHERE exec_id IN (SELECT exec_id FROM archive_candidates)`,
+		},
+		query: `SELECT count FROM archived_executions`,
+		after: []string{
+			`DROP TABLE archive_candidates`,
+			`DROP TABLE archived_executions`,
+		},
+	},
+
+	"GetNodesByNames": {
+		before: []string{`
+UPDATE credentials
+SET last_accessed = NOW()
+WHERE id IN (
+    SELECT DISTINCT n.credential_id
+    FROM nodes n
+    JOIN namespaces ns ON n.namespace_id = ns.id
+    WHERE n.name = ANY($1::text[]) AND ns.uuid = $2 AND n.credential_id IS NOT NULL
+)`},
+		query: `
+SELECT
+    n.id, n.uuid, n.name, n.hostname, n.port, n.username, n.os_family, n.tags, n.auth_method, n.connection_type, n.credential_id, n.namespace_id, n.created_at, n.updated_at,
+    ns.uuid AS namespace_uuid,
+    c.uuid AS credential_uuid,
+    c.name AS credential_name,
+    c.key_type AS credential_key_type,
+    c.key_data AS credential_key_data
+FROM nodes n
+JOIN namespaces ns ON n.namespace_id = ns.id
+LEFT JOIN credentials c ON n.credential_id = c.id
+WHERE n.name = ANY($1::text[]) AND ns.uuid = $2
+ORDER BY n.name`,
+	},
+
+	"GetNodesByNamesOrTagsInNamespaces": {
+		before: []string{`
+UPDATE credentials
+SET last_accessed = NOW()
+WHERE id IN (
+    SELECT DISTINCT n.credential_id
+    FROM nodes n
+    JOIN namespaces ns ON n.namespace_id = ns.id
+    WHERE ns.uuid = ANY($1::uuid[])
+      AND (n.name = ANY($2::text[]) OR n.tags && $3::text[])
+      AND n.credential_id IS NOT NULL
+)`},
+		query: `
+SELECT
+    n.id, n.uuid, n.name, n.hostname, n.port, n.username, n.os_family, n.tags, n.auth_method, n.connection_type, n.credential_id, n.namespace_id, n.created_at, n.updated_at,
+    ns.uuid AS namespace_uuid,
+    c.uuid AS credential_uuid,
+    c.name AS credential_name,
+    c.key_type AS credential_key_type,
+    c.key_data AS credential_key_data
+FROM nodes n
+JOIN namespaces ns ON n.namespace_id = ns.id
+LEFT JOIN credentials c ON n.credential_id = c.id
+WHERE ns.uuid = ANY($1::uuid[])
+  AND (n.name = ANY($2::text[]) OR n.tags && $3::text[])
+ORDER BY ns.id, n.name`,
+	},
+
+	"GetNodesByTags": {
+		before: []string{`
+UPDATE credentials
+SET last_accessed = NOW()
+WHERE id IN (
+    SELECT DISTINCT n.credential_id
+    FROM nodes n
+    JOIN namespaces ns ON n.namespace_id = ns.id
+    WHERE n.tags && $1::text[] AND ns.uuid = $2 AND n.credential_id IS NOT NULL
+)`},
+		query: `
+SELECT
+    n.id, n.uuid, n.name, n.hostname, n.port, n.username, n.os_family, n.tags, n.auth_method, n.connection_type, n.credential_id, n.namespace_id, n.created_at, n.updated_at,
+    ns.uuid AS namespace_uuid,
+    c.uuid AS credential_uuid,
+    c.name AS credential_name,
+    c.key_type AS credential_key_type,
+    c.key_data AS credential_key_data
+FROM nodes n
+JOIN namespaces ns ON n.namespace_id = ns.id
+LEFT JOIN credentials c ON n.credential_id = c.id
+WHERE n.tags && $1::text[] AND ns.uuid = $2
+ORDER BY n.name`,
+	},
+
+	"AddApprovalRequest": {
+		before: []string{`
+INSERT INTO approvals (
+    exec_log_id,
+    action_id,
+    namespace_id
+) VALUES (
+    $1, $2, (SELECT id FROM namespaces where namespaces.uuid = $3)
+)`},
+		query: `
+SELECT
+    a.id, a.uuid, a.exec_log_id, a.action_id, a.status, a.decided_by, a.namespace_id, a.created_at, a.updated_at, a.comment,
+    u.name as requested_by
+FROM approvals a
+JOIN execution_log el ON a.exec_log_id = el.id
+JOIN users u ON el.triggered_by = u.id
+WHERE a.id = last_insert_rowid()`,
+	},
+
+	"ApproveRequestByUUID": {
+		before: []string{`
+WITH namespace_lookup AS (
+    SELECT id FROM namespaces WHERE namespaces.uuid = $3
+)
+UPDATE approvals SET status = 'approved', decided_by = $2, comment = $4, updated_at = NOW()
+WHERE approvals.uuid = $1
+AND approvals.exec_log_id IN (
+    SELECT el.id FROM execution_log el
+    JOIN flows f ON el.flow_id = f.id
+    WHERE f.namespace_id = (SELECT id FROM namespace_lookup) AND f.is_active = TRUE
+)`},
+		query: `
+SELECT
+    a.id, a.uuid, a.exec_log_id, a.action_id, a.status, a.decided_by, a.namespace_id, a.created_at, a.updated_at, a.comment,
+    u.name as requested_by
+FROM approvals a
+JOIN execution_log el ON a.exec_log_id = el.id
+JOIN users u ON el.triggered_by = u.id
+WHERE a.uuid = $1 AND changes() > 0`,
+	},
+
+	"RejectRequestByUUID": {
+		before: []string{`
+WITH namespace_lookup AS (
+    SELECT id FROM namespaces WHERE namespaces.uuid = $3
+)
+UPDATE approvals SET status = 'rejected', decided_by = $2, comment = $4, updated_at = NOW()
+WHERE approvals.uuid = $1
+AND approvals.exec_log_id IN (
+    SELECT el.id FROM execution_log el
+    JOIN flows f ON el.flow_id = f.id
+    WHERE f.namespace_id = (SELECT id FROM namespace_lookup) AND f.is_active = TRUE
+)`},
+		query: `
+SELECT
+    a.id, a.uuid, a.exec_log_id, a.action_id, a.status, a.decided_by, a.namespace_id, a.created_at, a.updated_at, a.comment,
+    el.exec_id,
+    u.name as requested_by
+FROM approvals a
+JOIN execution_log el ON a.exec_log_id = el.id
+JOIN users u ON el.triggered_by = u.id
+WHERE a.uuid = $1 AND changes() > 0`,
+	},
+
+	"UpdateApprovalStatusByUUID": {
+		before: []string{`
+UPDATE approvals SET status = $1, decided_by = $2, updated_at = NOW()
+WHERE uuid = $1`},
+		query: `
+SELECT
+    a.id, a.uuid, a.exec_log_id, a.action_id, a.status, a.decided_by, a.namespace_id, a.created_at, a.updated_at, a.comment,
+    u.name as requested_by
+FROM approvals a
+JOIN execution_log el ON a.exec_log_id = el.id
+JOIN users u ON el.triggered_by = u.id
+WHERE a.uuid = $1 AND changes() > 0`,
+	},
+}
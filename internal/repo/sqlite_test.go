@@ -0,0 +1,310 @@
+package repo
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func openTestSQLite(t *testing.T) *sqlx.DB {
+	t.Helper()
+
+	db, err := OpenSQLite(filepath.Join(t.TempDir(), "flowctl.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	schema, err := os.ReadFile("../../migrations_sqlite/00048_create_initial_schema.up.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(string(schema)); err != nil {
+		t.Fatalf("could not create schema: %v", err)
+	}
+	return db
+}
+
+// sqlcQueries returns the queries generated by sqlc, by name
+func sqlcQueries(t *testing.T) map[string]string {
+	t.Helper()
+
+	files, err := filepath.Glob("*.sql.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	queries := make(map[string]string)
+	fset := token.NewFileSet()
+	for _, file := range files {
+		f, err := parser.ParseFile(fset, file, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ast.Inspect(f, func(n ast.Node) bool {
+			lit, ok := n.(*ast.BasicLit)
+			if !ok || lit.Kind != token.STRING {
+				return true
+			}
+			q, err := strconv.Unquote(lit.Value)
+			if err == nil && strings.HasPrefix(q, "-- name: ") {
+				queries[QueryName(q)] = q
+			}
+			return true
+		})
+	}
+	if len(queries) == 0 {
+		t.Fatal("no queries found")
+	}
+	return queries
+}
+
+var queryParam = regexp.MustCompile(`\$(\d+)`)
+
+// Every query must be valid SQLite once translated. The tables created by the statements run
+// before a query are created since the statements after them use them.
+func TestSQLiteQueriesPrepare(t *testing.T) {
+	db := openTestSQLite(t)
+	ctx := context.Background()
+
+	for name, query := range sqlcQueries(t) {
+		q := sqliteQuery(query)
+
+		var args []driver.NamedValue
+		for _, m := range queryParam.FindAllStringSubmatch(query, -1) {
+			n, _ := strconv.Atoi(m[1])
+			for len(args) < n {
+				args = append(args, driver.NamedValue{Ordinal: len(args) + 1, Value: int64(0)})
+			}
+		}
+
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = conn.Raw(func(dc any) error {
+			c := dc.(*sqliteConn).conn
+			if _, err := c.ExecContext(ctx, "BEGIN", nil); err != nil {
+				return err
+			}
+			defer c.ExecContext(ctx, "ROLLBACK", nil)
+
+			for _, s := range append(append(q.before, q.query), q.after...) {
+				if strings.HasPrefix(strings.TrimSpace(s), "CREATE TEMP TABLE") {
+					if _, err := c.ExecContext(ctx, s, args); err != nil {
+						t.Errorf("%s: %v\n%s", name, err, s)
+					}
+					continue
+				}
+				// The driver prepares statements when they run, EXPLAIN only compiles them
+				rows, err := c.QueryContext(ctx, "EXPLAIN "+s, args)
+				if err != nil {
+					t.Errorf("%s: %v\n%s", name, err, s)
+					continue
+				}
+				rows.Close()
+			}
+			return nil
+		})
+		conn.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestSQLiteStore(t *testing.T) {
+	db := openTestSQLite(t)
+	s := NewSQLiteStore(db)
+	ctx := context.Background()
+
+	ns, err := s.GetNamespaceByName(ctx, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	system, err := s.GetUserByUsername(ctx, "system")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	flow, err := s.CreateFlowTx(ctx, CreateFlowTxParams{
+		Slug:          "deploy",
+		Name:          "Deploy",
+		Description:   "Deploys the app",
+		Namespace:     "default",
+		SearchContent: "kubectl rollout restart",
+	})
+	if err != nil {
+		t.Fatalf("CreateFlowTx() error = %v", err)
+	}
+	found, err := s.SearchFlowsPaginated(ctx, SearchFlowsPaginatedParams{Uuid: ns.Uuid, Column2: "Rollout", Limit: 10})
+	if err != nil {
+		t.Fatalf("SearchFlowsPaginated() error = %v", err)
+	}
+	if len(found) != 1 || found[0].Slug != "deploy" || found[0].PageCount != 1 || found[0].TotalCount != 1 {
+		t.Errorf("SearchFlowsPaginated() = %+v, want the deploy flow", found)
+	}
+
+	if _, err := s.AddExecutionLog(ctx, AddExecutionLogParams{
+		ExecID:      "exec-1",
+		FlowID:      flow.ID,
+		Input:       json.RawMessage(`{"env":"prod"}`),
+		Uuid:        system.Uuid,
+		Uuid_2:      ns.Uuid,
+		TriggerType: TriggerTypeManual,
+	}); err != nil {
+		t.Fatalf("AddExecutionLog() error = %v", err)
+	}
+	for want := int32(1); want <= 2; want++ {
+		r, err := s.IncrementActionRetry(ctx, IncrementActionRetryParams{ExecID: "exec-1", Column2: "build", Uuid: ns.Uuid})
+		if err != nil {
+			t.Fatalf("IncrementActionRetry() error = %v", err)
+		}
+		if r.RetryCount != want {
+			t.Errorf("IncrementActionRetry() = %d, want %d", r.RetryCount, want)
+		}
+	}
+
+	started := time.Now().Add(-time.Minute)
+	if err := s.RecordExecutionTimelineEntries(ctx, RecordExecutionTimelineEntriesParams{
+		ExecID:        "exec-1",
+		ActionIds:     []string{"build", "build"},
+		Nodes:         []string{"", "web-1"},
+		Retries:       []int32{0, 0},
+		Statuses:      []string{"success", "success"},
+		Errors:        []string{"", ""},
+		StartedAt:     []time.Time{started, started},
+		Finished:      []bool{true, false},
+		FinishedAt:    []time.Time{started.Add(30 * time.Second), {}},
+		NamespaceUuid: ns.Uuid,
+	}); err != nil {
+		t.Fatalf("RecordExecutionTimelineEntries() error = %v", err)
+	}
+
+	e, err := s.UpdateExecutionStatus(ctx, UpdateExecutionStatusParams{Status: ExecutionStatusCompleted, ExecID: "exec-1", Uuid: ns.Uuid})
+	if err != nil {
+		t.Fatalf("UpdateExecutionStatus() error = %v", err)
+	}
+	if !e.CompletedAt.Valid || e.CompletedAt.Time.Location() != time.UTC {
+		t.Errorf("UpdateExecutionStatus() completed at %v, want a UTC time", e.CompletedAt)
+	}
+
+	events, err := ListSQLiteExecutionEvents(ctx, db, 0)
+	if err != nil {
+		t.Fatalf("ListSQLiteExecutionEvents() error = %v", err)
+	}
+	var statusChanges []string
+	for _, ev := range events {
+		var p struct {
+			ExecID    string `json:"exec_id"`
+			Namespace string `json:"namespace_id"`
+			Status    string `json:"status"`
+		}
+		if err := json.Unmarshal(ev.Payload, &p); err != nil {
+			t.Fatalf("invalid event %s: %v", ev.Payload, err)
+		}
+		if p.ExecID != "exec-1" || p.Namespace != ns.Uuid.String() {
+			t.Errorf("event %s, want exec-1 in the default namespace", ev.Payload)
+		}
+		statusChanges = append(statusChanges, p.Status)
+	}
+	if want := []string{"pending", "completed"}; !reflect.DeepEqual(statusChanges, want) {
+		t.Errorf("events = %v, want %v", statusChanges, want)
+	}
+	if err := DeleteSQLiteExecutionEvents(ctx, db, events[len(events)-1].ID); err != nil {
+		t.Fatalf("DeleteSQLiteExecutionEvents() error = %v", err)
+	}
+	if events, _ := ListSQLiteExecutionEvents(ctx, db, 0); len(events) != 0 {
+		t.Errorf("ListSQLiteExecutionEvents() = %d events after delete, want none", len(events))
+	}
+
+	estimates, err := s.GetActionDurationEstimates(ctx, GetActionDurationEstimatesParams{FlowID: flow.ID, SampleSize: 10})
+	if err != nil {
+		t.Fatalf("GetActionDurationEstimates() error = %v", err)
+	}
+	if len(estimates) != 1 || estimates[0].MedianSeconds < 29.9 || estimates[0].MedianSeconds > 30.1 {
+		t.Errorf("GetActionDurationEstimates() = %+v, want 30 seconds for build", estimates)
+	}
+
+	if _, err := s.CreateNode(ctx, CreateNodeParams{
+		Name:           "web-1",
+		Hostname:       "10.0.0.1",
+		Port:           22,
+		Username:       "deploy",
+		OsFamily:       "linux",
+		Tags:           []string{"web", "prod"},
+		AuthMethod:     AuthenticationMethodPassword,
+		ConnectionType: ConnectionTypeSsh,
+		Uuid:           ns.Uuid,
+	}); err != nil {
+		t.Fatalf("CreateNode() error = %v", err)
+	}
+	nodes, err := s.GetNodesByTags(ctx, GetNodesByTagsParams{Column1: []string{"web"}, Uuid: ns.Uuid})
+	if err != nil {
+		t.Fatalf("GetNodesByTags() error = %v", err)
+	}
+	if len(nodes) != 1 || !reflect.DeepEqual(nodes[0].Tags, []string{"web", "prod"}) {
+		t.Errorf("GetNodesByTags() = %+v, want web-1", nodes)
+	}
+
+	for want := int32(1); want <= 2; want++ {
+		n, err := s.CountNamespaceExecution(ctx, CountNamespaceExecutionParams{Uuid: ns.Uuid})
+		if err != nil {
+			t.Fatalf("CountNamespaceExecution() error = %v", err)
+		}
+		if n != want {
+			t.Errorf("CountNamespaceExecution() = %d, want %d", n, want)
+		}
+	}
+
+	archived, err := s.ArchiveExecutions(ctx, ArchiveExecutionsParams{Before: time.Now().Add(time.Hour), BatchSize: 10})
+	if err != nil {
+		t.Fatalf("ArchiveExecutions() error = %v", err)
+	}
+	if archived != 1 {
+		t.Errorf("ArchiveExecutions() = %d, want 1", archived)
+	}
+	statuses, err := s.GetExecutionStatuses(ctx, []string{"exec-1"})
+	if err != nil {
+		t.Fatalf("GetExecutionStatuses() error = %v", err)
+	}
+	if len(statuses) != 1 || statuses[0].Status != ExecutionStatusCompleted {
+		t.Errorf("GetExecutionStatuses() = %+v, want exec-1 completed", statuses)
+	}
+
+	if err := s.PurgeExecutions(ctx, []string{"exec-1"}); err != nil {
+		t.Fatalf("PurgeExecutions() error = %v", err)
+	}
+	if statuses, err := s.GetExecutionStatuses(ctx, []string{"exec-1"}); err != nil || len(statuses) != 0 {
+		t.Errorf("GetExecutionStatuses() = %+v, %v after purge, want none", statuses, err)
+	}
+}
+
+// Every PostgreSQL migration needs a SQLite migration with the same number
+func TestSQLiteMigrationsUpToDate(t *testing.T) {
+	latest := func(dir string) string {
+		files, err := filepath.Glob(filepath.Join(dir, "*.up.sql"))
+		if err != nil || len(files) == 0 {
+			t.Fatalf("no migrations in %s: %v", dir, err)
+		}
+		version, _, _ := strings.Cut(filepath.Base(files[len(files)-1]), "_")
+		return version
+	}
+
+	if pg, sqlite := latest("../../migrations"), latest("../../migrations_sqlite"); pg != sqlite {
+		t.Errorf("latest SQLite migration is %s, want %s like the PostgreSQL migrations", sqlite, pg)
+	}
+}
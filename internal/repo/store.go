@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
@@ -33,6 +34,7 @@ type ApprovalDecisionTxParams struct {
 	NamespaceUUID    uuid.UUID
 	DecidedByUserID  int32
 	Status           ApprovalStatus
+	Comment          string
 	CancellationNote string
 }
 
@@ -43,6 +45,9 @@ type ApprovalDecisionResult struct {
 	RequestedBy string
 	ExecLogID   int32
 	ExecID      string
+	Comment     string
+	CreatedAt   time.Time
+	DecidedAt   time.Time
 }
 
 type CreateFlowTxParams struct {
@@ -53,7 +58,9 @@ type CreateFlowTxParams struct {
 	FilePath    string
 	Namespace   string
 	PrefixID    sql.NullInt32
-	Schedules   []struct {
+	// SearchContent is the text of the flow indexed for full text search besides its name and description
+	SearchContent string
+	Schedules     []struct {
 		Cron     string
 		Timezone string
 	}
@@ -68,6 +75,7 @@ type UpdateFlowTxParams struct {
 	Namespace       string
 	PrefixID        sql.NullInt32
 	UserSchedulable bool
+	SearchContent   string
 	Schedules       []struct {
 		Cron     string
 		Timezone string
@@ -82,17 +90,54 @@ type Store interface {
 	ProcessApprovalDecisionTx(ctx context.Context, params ApprovalDecisionTxParams) (ApprovalDecisionResult, error)
 	CreateFlowTx(ctx context.Context, params CreateFlowTxParams) (Flow, error)
 	UpdateFlowTx(ctx context.Context, params UpdateFlowTxParams) (Flow, error)
+	TakeExecutionSlotTx(ctx context.Context, params TakeExecutionSlotTxParams) (bool, error)
+}
+
+// TakeExecutionSlotTxParams are the limits an execution is started within. A limit of 0 doesn't
+// limit the executions.
+type TakeExecutionSlotTxParams struct {
+	ExecID            string
+	FlowSlug          string
+	NamespaceUUID     uuid.UUID
+	MaxFlowSlots      int32
+	MaxNamespaceSlots int32
 }
 
 type PostgresStore struct {
 	*Queries
 	db *sqlx.DB
+	// reads runs the queries that can be served by a read replica, see replica.go
+	reads   *Queries
+	observe QueryObserver
+}
+
+func NewPostgresStore(db *sqlx.DB, opts ...StoreOption) Store {
+	var o storeOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return &PostgresStore{
+		db:      db,
+		Queries: New(observeDB(db, "primary", o.observe)),
+		reads:   New(observeDB(db, "primary", o.observe)),
+		observe: o.observe,
+	}
 }
 
-func NewPostgresStore(db *sqlx.DB) Store {
+// NewPostgresStoreWithReplica creates a store that sends list, search and stats queries to
+// replica and everything else to db
+func NewPostgresStoreWithReplica(db *sqlx.DB, replica *sqlx.DB, opts ...StoreOption) Store {
+	var o storeOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	return &PostgresStore{
 		db:      db,
-		Queries: New(db),
+		Queries: New(observeDB(db, "primary", o.observe)),
+		reads:   New(observeDB(replica, "replica", o.observe)),
+		observe: o.observe,
 	}
 }
 
@@ -103,7 +148,7 @@ func (p *PostgresStore) RequestApprovalTx(ctx context.Context, execID string, na
 	}
 	defer tx.Rollback()
 
-	q := Queries{db: tx}
+	q := Queries{db: observeDB(tx, "primary", p.observe)}
 
 	e, err := q.GetExecutionByExecID(ctx, GetExecutionByExecIDParams{
 		ExecID: execID,
@@ -136,7 +181,7 @@ func (p *PostgresStore) CreateUserTx(ctx context.Context, params CreateUserTxPar
 	}
 	defer tx.Rollback()
 
-	q := Queries{db: tx}
+	q := Queries{db: observeDB(tx, "primary", p.observe)}
 
 	user, err := q.CreateUser(ctx, CreateUserParams{
 		Name:      params.Name,
@@ -183,7 +228,7 @@ func (p *PostgresStore) UpdateUserTx(ctx context.Context, params UpdateUserTxPar
 	}
 	defer tx.Rollback()
 
-	q := Queries{db: tx}
+	q := Queries{db: observeDB(tx, "primary", p.observe)}
 
 	_, err = q.UpdateUserByUUID(ctx, UpdateUserByUUIDParams{
 		Uuid:     params.UserUUID,
@@ -231,7 +276,7 @@ func (p *PostgresStore) ProcessApprovalDecisionTx(ctx context.Context, params Ap
 	}
 	defer tx.Rollback()
 
-	q := Queries{db: tx}
+	q := Queries{db: observeDB(tx, "primary", p.observe)}
 
 	var approval ApprovalDecisionResult
 
@@ -241,6 +286,7 @@ func (p *PostgresStore) ProcessApprovalDecisionTx(ctx context.Context, params Ap
 			Uuid:      params.ApprovalUUID,
 			DecidedBy: sql.NullInt32{Int32: params.DecidedByUserID, Valid: true},
 			Uuid_2:    params.NamespaceUUID,
+			Comment:   params.Comment,
 		})
 		if err != nil {
 			return ApprovalDecisionResult{}, fmt.Errorf("could not approve request: %w", err)
@@ -252,12 +298,16 @@ func (p *PostgresStore) ProcessApprovalDecisionTx(ctx context.Context, params Ap
 			ActionID:    a.ActionID,
 			RequestedBy: a.RequestedBy,
 			ExecLogID:   a.ExecLogID,
+			Comment:     a.Comment,
+			CreatedAt:   a.CreatedAt,
+			DecidedAt:   a.UpdatedAt,
 		}
 	} else if params.Status == ApprovalStatusRejected {
 		a, err := q.RejectRequestByUUID(ctx, RejectRequestByUUIDParams{
 			Uuid:      params.ApprovalUUID,
 			DecidedBy: sql.NullInt32{Int32: params.DecidedByUserID, Valid: true},
 			Uuid_2:    params.NamespaceUUID,
+			Comment:   params.Comment,
 		})
 		if err != nil {
 			return ApprovalDecisionResult{}, fmt.Errorf("could not reject request: %w", err)
@@ -269,6 +319,9 @@ func (p *PostgresStore) ProcessApprovalDecisionTx(ctx context.Context, params Ap
 			ActionID:    a.ActionID,
 			RequestedBy: a.RequestedBy,
 			ExecLogID:   a.ExecLogID,
+			Comment:     a.Comment,
+			CreatedAt:   a.CreatedAt,
+			DecidedAt:   a.UpdatedAt,
 		}
 
 		// If rejected, update execution status to cancelled
@@ -319,7 +372,7 @@ func (p *PostgresStore) CreateFlowTx(ctx context.Context, params CreateFlowTxPar
 	}
 	defer tx.Rollback()
 
-	q := Queries{db: tx}
+	q := Queries{db: observeDB(tx, "primary", p.observe)}
 
 	// Create the flow
 	flow, err := q.CreateFlow(ctx, CreateFlowParams{
@@ -335,6 +388,13 @@ func (p *PostgresStore) CreateFlowTx(ctx context.Context, params CreateFlowTxPar
 		return Flow{}, fmt.Errorf("could not create flow: %w", err)
 	}
 
+	if err := q.UpsertFlowSearch(ctx, UpsertFlowSearchParams{
+		Content: params.SearchContent,
+		FlowID:  flow.ID,
+	}); err != nil {
+		return Flow{}, fmt.Errorf("could not index flow: %w", err)
+	}
+
 	// Create cron schedules
 	for _, sched := range params.Schedules {
 		_, err = q.CreateCronSchedule(ctx, CreateCronScheduleParams{
@@ -361,7 +421,7 @@ func (p *PostgresStore) UpdateFlowTx(ctx context.Context, params UpdateFlowTxPar
 	}
 	defer tx.Rollback()
 
-	q := Queries{db: tx}
+	q := Queries{db: observeDB(tx, "primary", p.observe)}
 
 	// Update the flow
 	flow, err := q.UpdateFlow(ctx, UpdateFlowParams{
@@ -377,6 +437,13 @@ func (p *PostgresStore) UpdateFlowTx(ctx context.Context, params UpdateFlowTxPar
 		return Flow{}, fmt.Errorf("could not update flow: %w", err)
 	}
 
+	if err := q.UpsertFlowSearch(ctx, UpsertFlowSearchParams{
+		Content: params.SearchContent,
+		FlowID:  flow.ID,
+	}); err != nil {
+		return Flow{}, fmt.Errorf("could not index flow: %w", err)
+	}
+
 	// Disable user-created schedules if flow is not schedulable or not user-schedulable
 	if !params.UserSchedulable {
 		err = q.DisableUserSchedulesForFlow(ctx, flow.ID)
@@ -409,3 +476,47 @@ func (p *PostgresStore) UpdateFlowTx(ctx context.Context, params UpdateFlowTxPar
 
 	return flow, nil
 }
+
+// TakeExecutionSlotTx takes a slot for an execution unless the running executions of its flow or
+// namespace already hold as many slots as their limits allow. It reports whether a slot was taken.
+func (p *PostgresStore) TakeExecutionSlotTx(ctx context.Context, params TakeExecutionSlotTxParams) (bool, error) {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("could not start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	q := Queries{db: observeDB(tx, "primary", p.observe)}
+
+	if err := q.LockExecutionSlots(ctx, params.NamespaceUUID); err != nil {
+		return false, fmt.Errorf("could not lock execution slots: %w", err)
+	}
+
+	held, err := q.CountExecutionSlots(ctx, CountExecutionSlotsParams{
+		FlowSlug: params.FlowSlug,
+		Uuid:     params.NamespaceUUID,
+		ExecID:   params.ExecID,
+	})
+	if err != nil {
+		return false, fmt.Errorf("could not count execution slots: %w", err)
+	}
+	if params.MaxFlowSlots > 0 && held.FlowSlots >= params.MaxFlowSlots {
+		return false, nil
+	}
+	if params.MaxNamespaceSlots > 0 && held.NamespaceSlots >= params.MaxNamespaceSlots {
+		return false, nil
+	}
+
+	if err := q.TakeExecutionSlot(ctx, TakeExecutionSlotParams{
+		ExecID:   params.ExecID,
+		Uuid:     params.NamespaceUUID,
+		FlowSlug: params.FlowSlug,
+	}); err != nil {
+		return false, fmt.Errorf("could not take execution slot: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("could not commit transaction: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,68 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/cvhariharan/flowctl/internal/artifactstore"
+	"github.com/cvhariharan/flowctl/internal/repo"
+	"github.com/cvhariharan/flowctl/internal/streamlogger"
+	"github.com/google/uuid"
+)
+
+// archiveArtifacts archives the files in the artifact directory that match the artifacts of an
+// action that completed and records them, so that they can be downloaded once the artifact
+// directory is removed. The action fails if a file can't be archived.
+func (h *FlowExecutionHandler) archiveArtifacts(ctx context.Context, execID string, action Action, artifactDir string, namespaceID string, streamLogger streamlogger.Logger) error {
+	if len(action.Artifacts) == 0 {
+		return nil
+	}
+	if h.archive == nil {
+		return errors.New("no artifact archive is configured to keep the artifacts of the action")
+	}
+
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	names, err := artifactstore.Match(artifactDir, action.Artifacts)
+	if err != nil {
+		return fmt.Errorf("could not find artifacts of action %s: %w", action.ID, err)
+	}
+	if len(names) == 0 {
+		msg := fmt.Sprintf("no files match the artifacts %s", strings.Join(action.Artifacts, ", "))
+		if err := streamLogger.Checkpoint(action.ID, "", []byte(msg), streamlogger.LogMessageType); err != nil {
+			h.logger.Error("failed to log artifacts", "execID", execID, "actionID", action.ID, "error", err)
+		}
+		return nil
+	}
+
+	for _, name := range names {
+		key := artifactstore.ArchiveKey(execID, action.ID, name)
+		size, err := h.archive.Put(ctx, key, filepath.Join(artifactDir, filepath.FromSlash(name)))
+		if err != nil {
+			return fmt.Errorf("could not archive artifact %s: %w", name, err)
+		}
+
+		if _, err := h.store.RecordArtifact(ctx, repo.RecordArtifactParams{
+			ExecID:        execID,
+			NamespaceUuid: namespaceUUID,
+			ActionID:      action.ID,
+			Name:          name,
+			ObjectKey:     key,
+			Size:          size,
+		}); err != nil {
+			return fmt.Errorf("could not record artifact %s: %w", name, err)
+		}
+	}
+
+	msg := fmt.Sprintf("archived %d artifacts", len(names))
+	if err := streamLogger.Checkpoint(action.ID, "", []byte(msg), streamlogger.LogMessageType); err != nil {
+		h.logger.Error("failed to log artifacts", "execID", execID, "actionID", action.ID, "error", err)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package scheduler
+
+import (
+	"fmt"
+
+	"github.com/cvhariharan/flowctl/internal/streamlogger"
+	"github.com/expr-lang/expr"
+)
+
+// evalCondition evaluates the condition of an action. Actions without a condition always run.
+func evalCondition(condition string, env map[string]any) (bool, error) {
+	if condition == "" {
+		return true, nil
+	}
+
+	out, err := expr.Eval(condition, env)
+	if err != nil {
+		return false, fmt.Errorf("failed to evaluate condition: %w", err)
+	}
+	run, ok := out.(bool)
+	if !ok {
+		return false, fmt.Errorf("condition must evaluate to a boolean, got %T", out)
+	}
+	return run, nil
+}
+
+// checkCondition reports whether an action runs. An action whose condition is false is noted as
+// skipped in the logs of the execution.
+func (h *FlowExecutionHandler) checkCondition(execID string, action Action, input map[string]any, secrets map[string]string, vars map[string]string, outputs map[string]any, streamLogger streamlogger.Logger) (bool, error) {
+	env := map[string]any{
+		"inputs":  input,
+		"secrets": secrets,
+		"vars":    vars,
+		"outputs": outputs,
+	}
+	run, err := evalCondition(action.Condition, env)
+	if err != nil {
+		return false, fmt.Errorf("action %s: %w", action.ID, err)
+	}
+	if run {
+		return true, nil
+	}
+
+	if err := streamLogger.Checkpoint(action.ID, "", []byte(fmt.Sprintf("action %s skipped, its condition is false", action.ID)), streamlogger.LogMessageType); err != nil {
+		h.logger.Error("failed to log skipped action", "execID", execID, "actionID", action.ID, "error", err)
+	}
+	return false, nil
+}
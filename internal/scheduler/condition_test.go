@@ -0,0 +1,38 @@
+package scheduler
+
+import "testing"
+
+func TestEvalCondition(t *testing.T) {
+	env := map[string]any{
+		"inputs":  map[string]any{"environment": "production", "replicas": 3},
+		"outputs": map[string]any{"status": "healthy"},
+		"secrets": map[string]string{"token": "s3cret"},
+	}
+
+	tests := []struct {
+		name      string
+		condition string
+		want      bool
+		wantErr   bool
+	}{
+		{"no condition", "", true, false},
+		{"input", `inputs.environment == "production"`, true, false},
+		{"output", `outputs.status != "healthy"`, false, false},
+		{"secret", `secrets.token != ""`, true, false},
+		{"combined", `inputs.replicas > 1 && outputs.status == "healthy"`, true, false},
+		{"not a boolean", `inputs.environment`, false, true},
+		{"invalid expression", `inputs.environment ==`, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := evalCondition(tt.condition, env)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("evalCondition() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("evalCondition() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,12 +2,18 @@ package scheduler
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/robfig/cron/v3"
 )
 
+// SyncScheduledJobs reloads the scheduled jobs from the job syncer without waiting for the next periodic sync
+func (s *Scheduler) SyncScheduledJobs(ctx context.Context) error {
+	return s.syncScheduledJobs(ctx)
+}
+
 // syncScheduledJobs syncs scheduled jobs from the job syncer into the cache
 func (s *Scheduler) syncScheduledJobs(ctx context.Context) error {
 	if s.jobSyncer == nil {
@@ -46,11 +52,28 @@ func (s *Scheduler) checkPeriodicTasks(ctx context.Context) error {
 	s.scheduledMu.RUnlock()
 
 	for _, job := range jobs {
-		if job.Cron != "" && s.shouldRunNow(job.Cron, job.Timezone) {
+		if job.Cron == "" {
+			continue
+		}
+
+		if firedAt, due := s.dueAt(job.Cron, job.Timezone); due {
 			// Generate a new execID for each execution
 			execID := uuid.NewString()
 
-			if _, err := s.QueueTask(ctx, job.PayloadType, execID, job.Payload); err != nil {
+			if reason := s.checkSkip(ctx, job); reason != "" {
+				s.logger.Warn("skipped scheduled job", "job", job.Name, "id", job.ID, "cron", job.Cron, "reason", reason)
+				s.notifySkipped(ctx, job, execID, reason)
+				continue
+			}
+
+			// The fire time lets the handler measure how late the run started
+			payload := job.Payload
+			if p, ok := payload.(FlowExecutionPayload); ok {
+				p.FiredAt = firedAt
+				payload = p
+			}
+
+			if _, err := s.QueueTask(ctx, job.PayloadType, execID, payload); err != nil {
 				s.logger.Error("failed to queue scheduled job", "job", job.Name, "error", err)
 			} else {
 				s.logger.Info("queued scheduled job", "job", job.Name, "id", job.ID, "execID", execID, "cron", job.Cron)
@@ -61,12 +84,48 @@ func (s *Scheduler) checkPeriodicTasks(ctx context.Context) error {
 	return nil
 }
 
-// shouldRunNow evaluates if a cron expression should execute in the current minute
-func (s *Scheduler) shouldRunNow(cronExpr string, timezone string) bool {
+// checkSkip returns the reason a due scheduled job should not run, or an empty string.
+// Errors from the skip checker are logged and the job is allowed to run.
+func (s *Scheduler) checkSkip(ctx context.Context, job ScheduledJob) string {
+	if s.skipChecker == nil {
+		return ""
+	}
+
+	reason, err := s.skipChecker(ctx, job)
+	if err != nil {
+		s.logger.Error("failed to check if scheduled job should be skipped", "job", job.Name, "error", err)
+		return ""
+	}
+	return reason
+}
+
+// notifySkipped queues the on_skipped notifications configured on the flow of a skipped job
+func (s *Scheduler) notifySkipped(ctx context.Context, job ScheduledJob, execID string, reason string) {
+	payload, ok := job.Payload.(FlowExecutionPayload)
+	if !ok {
+		return
+	}
+
+	err := QueueNotifications(ctx, s, payload.Workflow, NotificationPayload{
+		FlowID:      payload.Workflow.Meta.ID,
+		FlowName:    payload.Workflow.Meta.Name,
+		ExecID:      execID,
+		Status:      StatusSkipped,
+		Reason:      reason,
+		NamespaceID: payload.NamespaceID,
+	})
+	if err != nil {
+		s.logger.Error("failed to queue skipped run notifications", "job", job.Name, "error", err)
+	}
+}
+
+// dueAt evaluates if a cron expression should execute in the current minute and returns
+// the time it was due at
+func (s *Scheduler) dueAt(cronExpr string, timezone string) (time.Time, bool) {
 	schedule, err := cron.ParseStandard(cronExpr)
 	if err != nil {
 		s.logger.Error("failed to parse cron expression", "cron", cronExpr, "error", err)
-		return false
+		return time.Time{}, false
 	}
 
 	// Load the timezone
@@ -84,5 +143,32 @@ func (s *Scheduler) shouldRunNow(cronExpr string, timezone string) bool {
 	nextRun := schedule.Next(lastMinute)
 
 	// Task should run if the next scheduled time falls within the current minute
-	return nextRun.Equal(currentMinute) || (nextRun.After(currentMinute) && nextRun.Before(currentMinute.Add(time.Minute)))
+	due := nextRun.Equal(currentMinute) || (nextRun.After(currentMinute) && nextRun.Before(currentMinute.Add(time.Minute)))
+	return nextRun, due
+}
+
+// CronOccurrences returns when a cron expression fires in the given timezone from from up to and
+// including to, at most limit times. The expression and timezone are read the way scheduled jobs
+// read them, so an invalid timezone falls back to UTC.
+func CronOccurrences(cronExpr string, timezone string, from, to time.Time, limit int) ([]time.Time, error) {
+	schedule, err := cron.ParseStandard(cronExpr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cron expression %q: %w", cronExpr, err)
+	}
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	var times []time.Time
+	// Next returns times after the one given, so start just before from to include it
+	for t := schedule.Next(from.In(loc).Add(-time.Second)); len(times) < limit; t = schedule.Next(t) {
+		// A zero time means the expression never fires again
+		if t.IsZero() || t.After(to) {
+			break
+		}
+		times = append(times, t)
+	}
+	return times, nil
 }
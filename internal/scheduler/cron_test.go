@@ -0,0 +1,82 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCronOccurrences(t *testing.T) {
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		t.Skip("timezone data not available")
+	}
+	from := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		cron     string
+		timezone string
+		to       time.Time
+		limit    int
+		want     []time.Time
+		wantErr  bool
+	}{
+		{
+			name:     "daily in a timezone",
+			cron:     "0 9 * * *",
+			timezone: "Asia/Kolkata",
+			to:       from.AddDate(0, 0, 2),
+			limit:    10,
+			want: []time.Time{
+				time.Date(2026, 10, 16, 9, 0, 0, 0, kolkata),
+				time.Date(2026, 10, 17, 9, 0, 0, 0, kolkata),
+			},
+		},
+		{
+			name:  "from and to are inclusive",
+			cron:  "0 * * * *",
+			to:    from.Add(2 * time.Hour),
+			limit: 10,
+			want:  []time.Time{from, from.Add(time.Hour), from.Add(2 * time.Hour)},
+		},
+		{
+			name:  "limited",
+			cron:  "* * * * *",
+			to:    from.AddDate(0, 0, 1),
+			limit: 2,
+			want:  []time.Time{from, from.Add(time.Minute)},
+		},
+		{
+			name:     "invalid timezone falls back to UTC",
+			cron:     "30 6 * * *",
+			timezone: "Nowhere/City",
+			to:       from.AddDate(0, 0, 1),
+			limit:    10,
+			want:     []time.Time{from.Add(6*time.Hour + 30*time.Minute)},
+		},
+		{
+			name:    "invalid cron",
+			cron:    "not a cron",
+			to:      from.AddDate(0, 0, 1),
+			limit:   10,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CronOccurrences(tt.cron, tt.timezone, from, tt.to, tt.limit)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CronOccurrences() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("CronOccurrences() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) {
+					t.Errorf("CronOccurrences()[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,255 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/cvhariharan/flowctl/internal/repo"
+	"github.com/cvhariharan/flowctl/internal/streamlogger"
+	"github.com/google/uuid"
+)
+
+// actionFunc runs an action of an execution with the outputs of the actions that ran before it
+type actionFunc func(ctx context.Context, action Action, streamLogger streamlogger.Logger, progress *progressTracker, outputs map[string]any) (map[string]string, error)
+
+// HasNeeds reports whether any action of the flow declares the actions it needs, in which case the
+// actions run as a graph instead of one after another
+func (f Flow) HasNeeds() bool {
+	return slices.ContainsFunc(f.Actions, func(a Action) bool { return len(a.Needs) > 0 })
+}
+
+// executeGraph runs the actions of a flow that declares needs. Executions resumed after an approval,
+// a wait or a retry start with the outputs of the actions that don't run again.
+func (h *FlowExecutionHandler) executeGraph(ctx context.Context, execID string, payload FlowExecutionPayload, streamLogger streamlogger.Logger, outputs map[string]any, run actionFunc) error {
+	completed, err := h.completedActions(ctx, execID, payload)
+	if err != nil {
+		return err
+	}
+	if len(completed) > 0 {
+		done := slices.DeleteFunc(slices.Clone(payload.Workflow.Actions), func(a Action) bool { return !completed[a.ID] })
+		h.restoreOutputs(ctx, execID, payload.NamespaceID, done, outputs)
+	}
+
+	// Actions running in parallel share the action ID and retry of the log
+	var mu sync.Mutex
+	stoppedID, err := runGraph(ctx, payload.Workflow.Actions, completed, outputs, func(ctx context.Context, action Action, outputs map[string]any) (map[string]string, error) {
+		logger := &actionLogger{Logger: streamLogger, mu: &mu, actionID: action.ID}
+		progress := newProgressTracker(h, logger, execID, payload.NamespaceID, payload.Workflow.Actions)
+		// Record any buffered progress before the final status of the execution is set
+		defer progress.flush()
+
+		return run(ctx, action, logger, progress, outputs)
+	})
+	if err == nil {
+		return nil
+	}
+
+	// The execution is resumed or retried from the action that stopped it, not from an action
+	// that started alongside it
+	namespaceUUID, uuidErr := uuid.Parse(payload.NamespaceID)
+	if uuidErr != nil {
+		return fmt.Errorf("invalid namespace UUID: %w", uuidErr)
+	}
+	if _, updateErr := h.store.UpdateExecutionActionID(context.WithoutCancel(ctx), repo.UpdateExecutionActionIDParams{
+		CurrentActionID: sql.NullString{String: stoppedID, Valid: stoppedID != ""},
+		ExecID:          execID,
+		Uuid:            namespaceUUID,
+	}); updateErr != nil {
+		h.logger.Error("failed to update current action", "exec_id", execID, "action_id", stoppedID, "error", updateErr)
+	}
+	return err
+}
+
+// completedActions returns the actions of a resumed execution that don't run again. These are the
+// actions whose latest attempt succeeded, except the action the execution resumes from and the
+// actions that need it, which run again like the actions after it in flows without needs. An
+// action that was skipped counts as completed.
+func (h *FlowExecutionHandler) completedActions(ctx context.Context, execID string, payload FlowExecutionPayload) (map[string]bool, error) {
+	completed := make(map[string]bool)
+	if !payload.Resumed {
+		return completed, nil
+	}
+
+	namespaceUUID, err := uuid.Parse(payload.NamespaceID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	entries, err := h.store.ListExecutionTimeline(ctx, repo.ListExecutionTimelineParams{
+		ExecID: execID,
+		Uuid:   namespaceUUID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not get timeline of exec %s: %w", execID, err)
+	}
+
+	// Entries without a node are the ones of the actions
+	latest := make(map[string]repo.ExecutionTimeline)
+	for _, e := range entries {
+		if e.Node != "" {
+			continue
+		}
+		if l, ok := latest[e.ActionID]; ok && l.Retry > e.Retry {
+			continue
+		}
+		latest[e.ActionID] = e
+	}
+	for id, e := range latest {
+		if e.Status == ProgressStatusSuccess {
+			completed[id] = true
+		}
+	}
+
+	if payload.Skipped != nil {
+		completed[payload.Skipped.ActionID] = true
+		return completed, nil
+	}
+
+	if payload.StartingActionIdx < len(payload.Workflow.Actions) {
+		for id := range dependents(payload.Workflow.Actions, payload.Workflow.Actions[payload.StartingActionIdx].ID) {
+			delete(completed, id)
+		}
+	}
+	return completed, nil
+}
+
+// dependents returns the action with the given ID and the actions that need it, directly or
+// through other actions
+func dependents(actions []Action, id string) map[string]bool {
+	deps := map[string]bool{id: true}
+	for changed := true; changed; {
+		changed = false
+		for _, a := range actions {
+			if deps[a.ID] {
+				continue
+			}
+			if slices.ContainsFunc(a.Needs, func(n string) bool { return deps[n] }) {
+				deps[a.ID] = true
+				changed = true
+			}
+		}
+	}
+	return deps
+}
+
+// runGraph runs the actions that are not completed once all the actions they need are completed.
+// Actions that are ready at the same time run in parallel, each with a copy of the outputs of the
+// actions that finished before it started. Once an action fails or pauses the execution no more
+// actions are started and the running ones are waited for. The ID of the action that stopped the
+// execution is returned with its error, a failure takes precedence over a pause. Completed actions
+// are added to completed and their results to outputs.
+func runGraph(ctx context.Context, actions []Action, completed map[string]bool, outputs map[string]any, run func(ctx context.Context, action Action, outputs map[string]any) (map[string]string, error)) (string, error) {
+	type result struct {
+		actionID string
+		res      map[string]string
+		err      error
+	}
+
+	results := make(chan result)
+	started := make(map[string]bool)
+	running := 0
+
+	var stoppedID string
+	var stopErr error
+	for {
+		if stopErr == nil {
+			for _, action := range actions {
+				if completed[action.ID] || started[action.ID] {
+					continue
+				}
+				if slices.ContainsFunc(action.Needs, func(n string) bool { return !completed[n] }) {
+					continue
+				}
+
+				started[action.ID] = true
+				running++
+				go func(action Action, outputs map[string]any) {
+					res, err := run(ctx, action, outputs)
+					results <- result{actionID: action.ID, res: res, err: err}
+				}(action, copyOutputs(outputs))
+			}
+		}
+		if running == 0 {
+			break
+		}
+
+		r := <-results
+		running--
+		if r.err != nil {
+			if stopErr == nil || (isPaused(stopErr) && !isPaused(r.err)) {
+				stoppedID, stopErr = r.actionID, r.err
+			}
+			continue
+		}
+		completed[r.actionID] = true
+		MergeActionResults(r.res, outputs)
+	}
+	if stopErr != nil {
+		return stoppedID, stopErr
+	}
+
+	for _, action := range actions {
+		if !completed[action.ID] {
+			return action.ID, fmt.Errorf("action %s needs actions that are not in the flow", action.ID)
+		}
+	}
+	return "", nil
+}
+
+// copyOutputs copies the outputs of an execution along with the outputs of its nodes, so that
+// actions running in parallel don't read them while results are merged
+func copyOutputs(outputs map[string]any) map[string]any {
+	c := make(map[string]any, len(outputs))
+	for k, v := range outputs {
+		if node, ok := v.(map[string]any); ok {
+			v = maps.Clone(node)
+		}
+		c[k] = v
+	}
+	return c
+}
+
+// actionLogger writes the logs of one of the actions running in parallel. The action ID and retry
+// of the underlying logger are shared by all actions, so every message is written with the action
+// ID and the retry is set before it while holding mu.
+type actionLogger struct {
+	streamlogger.Logger
+	mu       *sync.Mutex
+	actionID string
+	retry    atomic.Int32
+}
+
+func (l *actionLogger) Write(p []byte) (int, error) {
+	if err := l.Checkpoint(l.actionID, "", p, streamlogger.LogMessageType); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// SetActionID does nothing, the logger writes the logs of a single action
+func (l *actionLogger) SetActionID(id string) {}
+
+func (l *actionLogger) SetRetry(retry int32) {
+	l.retry.Store(retry)
+}
+
+func (l *actionLogger) Checkpoint(id string, nodeID string, val interface{}, mtype streamlogger.MessageType) error {
+	if id == "" {
+		id = l.actionID
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.Logger.SetRetry(l.retry.Load())
+	return l.Logger.Checkpoint(id, nodeID, val, mtype)
+}
+
+// Close does nothing, the log is closed once the execution finishes
+func (l *actionLogger) Close() error {
+	return nil
+}
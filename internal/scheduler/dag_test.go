@@ -0,0 +1,182 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cvhariharan/flowctl/internal/repo"
+	"github.com/google/uuid"
+)
+
+func TestRunGraph(t *testing.T) {
+	actions := []Action{
+		{ID: "build"},
+		{ID: "lint", Needs: []string{"build"}},
+		{ID: "test", Needs: []string{"build"}},
+		{ID: "deploy", Needs: []string{"lint", "test"}},
+	}
+
+	var mu sync.Mutex
+	var order []string
+	// lint and test only finish once both of them started, so they have to run in parallel
+	var parallel sync.WaitGroup
+	parallel.Add(2)
+	run := func(ctx context.Context, action Action, outputs map[string]any) (map[string]string, error) {
+		mu.Lock()
+		order = append(order, action.ID)
+		mu.Unlock()
+
+		switch action.ID {
+		case "lint", "test":
+			if outputs["version"] != "1.0" {
+				t.Errorf("action %s got outputs %v, want the outputs of build", action.ID, outputs)
+			}
+			parallel.Done()
+			parallel.Wait()
+		case "deploy":
+			if outputs["lint"] != "ok" || outputs["test"] != "ok" {
+				t.Errorf("deploy got outputs %v, want the outputs of lint and test", outputs)
+			}
+			return nil, nil
+		case "build":
+			return map[string]string{"version": "1.0"}, nil
+		}
+		return map[string]string{action.ID: "ok"}, nil
+	}
+
+	completed := make(map[string]bool)
+	outputs := make(map[string]any)
+	if _, err := runGraph(context.Background(), actions, completed, outputs, run); err != nil {
+		t.Fatalf("runGraph() error = %v", err)
+	}
+	if order[0] != "build" || order[3] != "deploy" {
+		t.Errorf("actions ran in order %v, want build first and deploy last", order)
+	}
+	if len(completed) != len(actions) {
+		t.Errorf("completed = %v, want all actions", completed)
+	}
+}
+
+func TestRunGraph_Stops(t *testing.T) {
+	actions := []Action{
+		{ID: "approve"},
+		{ID: "slow"},
+		{ID: "fail"},
+		{ID: "deploy", Needs: []string{"approve", "slow", "fail"}},
+	}
+	failure := errors.New("exit status 1")
+
+	tests := []struct {
+		name      string
+		errs      map[string]error
+		wantID    string
+		wantErr   error
+		wantSlow  bool
+		completed map[string]bool
+	}{
+		{"failure", map[string]error{"fail": failure}, "fail", failure, true, nil},
+		{"pause", map[string]error{"approve": ErrPendingApproval}, "approve", ErrPendingApproval, true, nil},
+		{"failure over pause", map[string]error{"approve": ErrPendingApproval, "fail": failure}, "fail", failure, true, nil},
+		{"completed actions don't run", nil, "", nil, false, map[string]bool{"approve": true, "slow": true, "fail": true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			ran := make(map[string]bool)
+			run := func(ctx context.Context, action Action, outputs map[string]any) (map[string]string, error) {
+				// slow finishes after the others stopped the execution
+				if action.ID == "slow" {
+					time.Sleep(10 * time.Millisecond)
+				}
+				mu.Lock()
+				ran[action.ID] = true
+				mu.Unlock()
+				return nil, tt.errs[action.ID]
+			}
+
+			completed := make(map[string]bool)
+			for id := range tt.completed {
+				completed[id] = true
+			}
+			stoppedID, err := runGraph(context.Background(), actions, completed, map[string]any{}, run)
+			if stoppedID != tt.wantID || !errors.Is(err, tt.wantErr) {
+				t.Errorf("runGraph() = %q, %v, want %q, %v", stoppedID, err, tt.wantID, tt.wantErr)
+			}
+			if ran["slow"] != tt.wantSlow {
+				t.Errorf("slow ran = %v, want %v", ran["slow"], tt.wantSlow)
+			}
+			if tt.wantErr != nil && ran["deploy"] {
+				t.Error("deploy ran after the execution stopped")
+			}
+		})
+	}
+}
+
+// timelineStore returns the timeline recorded for an execution
+type timelineStore struct {
+	repo.Store
+	entries []repo.ExecutionTimeline
+}
+
+func (s *timelineStore) ListExecutionTimeline(ctx context.Context, arg repo.ListExecutionTimelineParams) ([]repo.ExecutionTimeline, error) {
+	return s.entries, nil
+}
+
+func TestCompletedActions(t *testing.T) {
+	actions := []Action{
+		{ID: "build"},
+		{ID: "lint", Needs: []string{"build"}},
+		{ID: "test", Needs: []string{"build"}},
+		{ID: "deploy", Needs: []string{"lint", "test"}},
+	}
+	entries := []repo.ExecutionTimeline{
+		{ActionID: "build", Status: ProgressStatusSuccess},
+		{ActionID: "build", Node: "web-1", Status: ProgressStatusSuccess},
+		{ActionID: "lint", Status: ProgressStatusSuccess},
+		{ActionID: "test", Status: ProgressStatusFailed},
+		{ActionID: "test", Retry: 1, Status: ProgressStatusSuccess},
+		{ActionID: "deploy", Status: ProgressStatusFailed},
+	}
+
+	tests := []struct {
+		name    string
+		payload FlowExecutionPayload
+		want    []string
+	}{
+		{"new execution", FlowExecutionPayload{}, nil},
+		{"resumed at the last action", FlowExecutionPayload{Resumed: true, StartingActionIdx: 3}, []string{"build", "lint", "test"}},
+		{"retried from an action", FlowExecutionPayload{Resumed: true, StartingActionIdx: 1}, []string{"build", "test"}},
+		{"retried from the first action", FlowExecutionPayload{Resumed: true}, nil},
+		{"skipped action", FlowExecutionPayload{Resumed: true, StartingActionIdx: 3, Skipped: &SkippedAction{ActionID: "deploy"}}, []string{"build", "deploy", "lint", "test"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &FlowExecutionHandler{
+				store:  &timelineStore{entries: entries},
+				logger: slog.New(slog.DiscardHandler),
+			}
+			tt.payload.Workflow = Flow{Actions: actions}
+			tt.payload.NamespaceID = uuid.NewString()
+
+			completed, err := h.completedActions(context.Background(), "exec-1", tt.payload)
+			if err != nil {
+				t.Fatalf("completedActions() error = %v", err)
+			}
+			var got []string
+			for id := range completed {
+				got = append(got, id)
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("completedActions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package scheduler
+
+import (
+	"context"
+
+	"github.com/cvhariharan/flowctl/internal/repo"
+	"github.com/cvhariharan/flowctl/sdk/executor"
+	"github.com/google/uuid"
+)
+
+// recordEnvironment records the runtime details of the node an action ran on: its hostname and
+// OS, the executor and its version and the details the executor resolved, such as the digest
+// of the image that was pulled. The details are informational so failures are only logged.
+func (h *FlowExecutionHandler) recordEnvironment(ctx context.Context, driver executor.NodeDriver, exec executor.Executor, execID string, namespaceID string, action Action, nodeName string) {
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		h.logger.Error("invalid namespace UUID", "execID", execID, "error", err)
+		return
+	}
+
+	// The action may have been cancelled, the node is still described
+	ctx = context.WithoutCancel(ctx)
+
+	params := repo.UpsertExecutionEnvironmentParams{
+		ExecID:          execID,
+		NamespaceUuid:   namespaceUUID,
+		ActionID:        action.ID,
+		Node:            nodeName,
+		Executor:        action.Executor,
+		ExecutorVersion: executor.GetVersion(action.Executor),
+	}
+
+	env, err := executor.DescribeNode(ctx, driver)
+	if err != nil {
+		h.logger.Warn("failed to describe node", "execID", execID, "actionID", action.ID, "node", nodeName, "error", err)
+	} else {
+		params.Hostname = env.Hostname
+		params.Os = env.OS
+	}
+
+	if reporter, ok := exec.(executor.EnvironmentReporter); ok {
+		details := reporter.Environment()
+		params.Image = details[executor.EnvironmentImage]
+		params.ImageDigest = details[executor.EnvironmentImageDigest]
+	}
+
+	if err := h.store.UpsertExecutionEnvironment(ctx, params); err != nil {
+		h.logger.Error("failed to record execution environment", "execID", execID, "actionID", action.ID, "node", nodeName, "error", err)
+	}
+}
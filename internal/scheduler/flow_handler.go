@@ -6,24 +6,32 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"log/slog"
 	"maps"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
 	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/cvhariharan/flowctl/internal/artifactstore"
+	"github.com/cvhariharan/flowctl/internal/flowstore"
+	"github.com/cvhariharan/flowctl/internal/messengers"
 	"github.com/cvhariharan/flowctl/internal/metrics"
 	"github.com/cvhariharan/flowctl/internal/repo"
 	"github.com/cvhariharan/flowctl/internal/streamlogger"
+	"github.com/cvhariharan/flowctl/internal/tracing"
+	"github.com/cvhariharan/flowctl/internal/uploadstore"
 	"github.com/cvhariharan/flowctl/sdk/executor"
 	"github.com/expr-lang/expr"
 	"github.com/google/uuid"
 	"github.com/sqlc-dev/pqtype"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"gopkg.in/yaml.v3"
 )
 
@@ -33,25 +41,51 @@ const PayloadTypeFlowExecution PayloadType = "flow_execution"
 type FlowExecutionHandler struct {
 	store            repo.Store
 	secretsProvider  SecretsProviderFn
+	varsProvider     VariablesProviderFn
+	inputSealer      InputSealerFn
+	completionHook   CompletionHookFn
+	subflowQueuer    SubflowQueuerFn
 	logmanager       streamlogger.LogManager
 	logger           *slog.Logger
 	executionTimeout time.Duration
+	telemetryPeriod  time.Duration
+	progressFlush    time.Duration
 	metrics          *metrics.Manager
 	taskQueuer       TaskQueuer
 	executorKeys     map[string]string // executor_name → API token
 	apiBaseURL       string
+	flowFiles        flowstore.Store
+	uploads          *uploadstore.Store
+	artifacts        artifactstore.Store
+	archive          *artifactstore.Archive
 }
 
 // FlowHandlerConfig holds configuration for FlowExecutionHandler
 type FlowHandlerConfig struct {
 	Store                repo.Store
 	SecretsProvider      SecretsProviderFn
+	VariablesProvider    VariablesProviderFn // plain variables available to expressions as vars
+	InputSealer          InputSealerFn       // encrypts password inputs before they are stored
+	CompletionHook       CompletionHookFn    // runs the triggers of flows waiting on finished executions
+	SubflowQueuer        SubflowQueuerFn     // queues the executions of flows run by flow actions
 	LogManager           streamlogger.LogManager
 	Logger               *slog.Logger
 	Metrics              *metrics.Manager
 	FlowExecutionTimeout time.Duration
-	ExecutorKeys         map[string]string // executor_name → API token
-	APIBaseURL           string
+	TelemetryInterval    time.Duration
+	// ProgressFlushInterval is how long progress events are buffered before the latest one is recorded
+	ProgressFlushInterval time.Duration
+	ExecutorKeys          map[string]string // executor_name → API token
+	APIBaseURL            string
+	// FlowFiles is where files in flow directories are copied from, the local filesystem if nil
+	FlowFiles flowstore.Store
+	// Uploads is where files uploaded to file inputs are downloaded from
+	Uploads *uploadstore.Store
+	// Artifacts is where the artifacts of executions are kept, the system temp directory if nil
+	Artifacts artifactstore.Store
+	// Archive is where the files that actions publish are kept after the execution, actions
+	// can't publish files if nil
+	Archive *artifactstore.Archive
 }
 
 // NewFlowExecutionHandler creates a new flow execution handler
@@ -59,16 +93,38 @@ func NewFlowExecutionHandler(cfg FlowHandlerConfig) *FlowExecutionHandler {
 	if cfg.FlowExecutionTimeout == 0 {
 		cfg.FlowExecutionTimeout = time.Hour
 	}
+	if cfg.TelemetryInterval == 0 {
+		cfg.TelemetryInterval = 15 * time.Second
+	}
+	if cfg.ProgressFlushInterval == 0 {
+		cfg.ProgressFlushInterval = 2 * time.Second
+	}
+	if cfg.FlowFiles == nil {
+		cfg.FlowFiles = flowstore.NewLocalStore("")
+	}
+	if cfg.Artifacts == nil {
+		cfg.Artifacts = artifactstore.NewLocalStore(os.TempDir())
+	}
 
 	return &FlowExecutionHandler{
 		store:            cfg.Store,
 		secretsProvider:  cfg.SecretsProvider,
+		varsProvider:     cfg.VariablesProvider,
+		inputSealer:      cfg.InputSealer,
+		completionHook:   cfg.CompletionHook,
+		subflowQueuer:    cfg.SubflowQueuer,
 		logmanager:       cfg.LogManager,
 		logger:           cfg.Logger,
 		metrics:          cfg.Metrics,
 		executionTimeout: cfg.FlowExecutionTimeout,
+		telemetryPeriod:  cfg.TelemetryInterval,
+		progressFlush:    cfg.ProgressFlushInterval,
 		executorKeys:     cfg.ExecutorKeys,
 		apiBaseURL:       cfg.APIBaseURL,
+		flowFiles:        cfg.FlowFiles,
+		uploads:          cfg.Uploads,
+		artifacts:        cfg.Artifacts,
+		archive:          cfg.Archive,
 	}
 }
 
@@ -98,33 +154,78 @@ func (h *FlowExecutionHandler) Handle(ctx context.Context, job Job) error {
 		payload.Resumed = true
 	}
 
+	// Continue the trace of the request that queued the execution
+	ctx = tracing.Extract(ctx, payload.TraceContext)
+	attrs := []attribute.KeyValue{
+		attribute.String("flowctl.exec_id", job.ExecID),
+		attribute.String("flowctl.flow", payload.Workflow.Meta.ID),
+		attribute.String("flowctl.namespace_id", payload.NamespaceID),
+		attribute.Int("flowctl.attempt", job.Attempt),
+	}
+
+	queuedAt := job.CreatedAt
+	if !job.ScheduledAt.IsZero() {
+		queuedAt = job.ScheduledAt
+	}
+	_, waitSpan := tracing.StartAt(ctx, "queue wait", queuedAt, attrs...)
+	waitSpan.End()
+	if h.metrics != nil {
+		h.metrics.ObserveJobWait(payload.NamespaceID, payload.Workflow.Meta.ID, time.Since(queuedAt))
+	}
+
+	// Executions that are still running were interrupted by a restart of the server running them
+	adopted, err := h.adoptExecution(ctx, job.ExecID, &payload)
+	if err != nil {
+		return fmt.Errorf("failed to check for an interrupted execution: %w", err)
+	}
+
 	// Create execution log for scheduled executions or for retried jobs
-	if job.Attempt > 0 || (payload.TriggerType == TriggerTypeScheduled && job.ScheduledAt.IsZero()) {
+	if !adopted && (job.Attempt > 0 || (payload.TriggerType == TriggerTypeScheduled && job.ScheduledAt.IsZero())) {
 		if err := h.createExecutionLog(ctx, job.ExecID, payload); err != nil {
 			return fmt.Errorf("failed to create execution log: %w", err)
 		}
 	}
 
-	// Set status to Running
-	if err := h.setStatus(ctx, job.ExecID, repo.ExecutionStatusRunning, payload.NamespaceID, nil); err != nil {
+	// Set status to Running along with the started_at timestamp
+	if err := h.markRunning(ctx, job.ExecID, payload.NamespaceID); err != nil {
 		return fmt.Errorf("could not update execution_log status: %w", err)
 	}
-
-	// Set started_at timestamp
-	if err := h.setStartedAt(ctx, job.ExecID, payload.NamespaceID); err != nil {
-		h.logger.Warn("failed to set started_at", "execID", job.ExecID, "error", err)
-	}
+	h.recordScheduleLag(ctx, job, payload)
 
 	if h.metrics != nil {
 		h.metrics.IncExecutionsRunning(payload.NamespaceID, payload.Workflow.Meta.ID)
 	}
 
 	// Execute the flow
-	if err := h.executeFlow(ctx, job.ExecID, payload); err != nil {
+	execCtx, span := tracing.Start(ctx, "execute flow", attrs...)
+	err = h.executeFlow(execCtx, job.ExecID, payload)
+	endSpan(span, err)
+	if err != nil {
 		h.logger.Error("error executing flow", "flow", payload.Workflow.Meta.ID, "error", err, "attempt", job.Attempt, "maxRetries", job.MaxRetries)
 		if errors.Is(err, ErrPendingApproval) {
 			return h.setStatusWithMetrics(ctx, job.ExecID, repo.ExecutionStatusPendingApproval, payload, nil)
 		}
+		var waitErr *WaitError
+		if errors.As(err, &waitErr) {
+			if h.metrics != nil {
+				h.metrics.DecExecutionsRunning(payload.NamespaceID, payload.Workflow.Meta.ID)
+			}
+			return h.parkExecution(ctx, job.ExecID, payload, waitErr)
+		}
+		var subErr *SubflowError
+		if errors.As(err, &subErr) {
+			if h.metrics != nil {
+				h.metrics.DecExecutionsRunning(payload.NamespaceID, payload.Workflow.Meta.ID)
+			}
+			return h.startSubflow(ctx, job.ExecID, payload, subErr)
+		}
+		// Executions handed off stay running for the server that adopts them
+		if handedOff(ctx) {
+			if h.metrics != nil {
+				h.metrics.DecExecutionsRunning(payload.NamespaceID, payload.Workflow.Meta.ID)
+			}
+			return ErrHandoff
+		}
 		if errors.Is(err, ErrExecutionCancelled) {
 			// If execution is cancelled, the context will also be cancelled, so use background context
 			return h.setStatusWithMetrics(context.Background(), job.ExecID, repo.ExecutionStatusCancelled, payload, nil)
@@ -153,33 +254,81 @@ func (h *FlowExecutionHandler) executeFlow(ctx context.Context, execID string, p
 		payload.StartingActionIdx = len(payload.Workflow.Actions)
 	}
 
+	// The executor policy of the namespace may have changed since the execution was queued
+	if err := h.checkExecutorPolicy(ctx, payload); err != nil {
+		return err
+	}
+
 	// Apply default input values for any inputs not provided by the caller
 	if payload.Input == nil {
 		payload.Input = make(map[string]any)
 	}
 	applyDefaultInputs(payload.Workflow.Inputs, payload.Input)
 
-	// Create temporary directory for artifacts shared across all actions in this flow
-	artifactDir := filepath.Join(os.TempDir(), fmt.Sprintf("artifacts-store-%s", execID))
-	if err := os.MkdirAll(artifactDir, 0700); err != nil {
-		return fmt.Errorf("failed to create artifact directory: %w", err)
+	// Restore the artifacts shared across all actions in this flow, executions resumed after an
+	// approval or retried continue with the artifacts of the actions that already ran
+	artifactDir := h.artifacts.Dir(execID)
+	if err := h.artifacts.Restore(ctx, execID); err != nil {
+		return fmt.Errorf("failed to restore artifacts: %w", err)
 	}
 	h.logger.Debug("artifact directory creation", "dir", artifactDir)
 
 	// Copy files from flow directory to artifacts if flow directory is specified
 	if payload.FlowDirectory != "" {
-		if err := h.copyFlowFilesToArtifacts(payload.FlowDirectory, artifactDir); err != nil {
+		copyCtx, span := tracing.Start(ctx, "copy flow files")
+		err := h.copyFlowFilesToArtifacts(copyCtx, payload.FlowDirectory, artifactDir)
+		tracing.End(span, err)
+		if err != nil {
 			return fmt.Errorf("failed to copy flow files to artifacts: %w", err)
 		}
 	}
 
+	// Download uploaded files next to the other artifacts, the inputs reference them by their local path
+	if err := h.downloadUploads(ctx, execID, payload.Workflow.Inputs, payload.Input, artifactDir); err != nil {
+		return err
+	}
+
 	streamID := execID
 
-	streamLogger, err := h.logmanager.NewLogger(streamID)
+	// Get flow-specific secrets
+	flowSecrets := h.getFlowSecrets(ctx, payload.Workflow.Meta.ID, payload.NamespaceID, execID)
+	vars := h.getVariables(ctx, payload.NamespaceID, execID)
+
+	fileLogger, err := h.logmanager.NewLogger(streamID)
 	if err != nil {
 		return err
 	}
-	defer streamLogger.Close()
+	defer fileLogger.Close()
+
+	// Action results are recorded below the masking logger so that they are stored masked, like in the logs
+	var resultLogger streamlogger.Logger = &outputsLogger{Logger: fileLogger, record: func(actionID string, results map[string]string) {
+		h.recordOutputs(ctx, execID, payload.NamespaceID, actionID, results)
+	}}
+
+	// Redact secrets and password inputs before anything is written to the logs
+	streamLogger := streamlogger.NewMaskingLogger(resultLogger, maskedValues(payload.Workflow.Inputs, payload.Input, flowSecrets))
+
+	// The bytes written are recorded for the log storage quota of the namespace
+	var logBytes atomic.Int64
+	streamLogger = &countingLogger{Logger: streamLogger, add: func(n int) {
+		logBytes.Add(int64(n))
+		if h.metrics != nil {
+			h.metrics.AddLogBytes(payload.NamespaceID, payload.Workflow.Meta.ID, n)
+		}
+	}}
+	defer func() {
+		h.recordLogBytes(context.WithoutCancel(ctx), execID, payload.NamespaceID, logBytes.Load())
+	}()
+
+	// Executions of flows sharing the lock of the flow run one at a time, the lock is released
+	// while the execution waits for an approval
+	if key := payload.Workflow.Meta.Lock; key != "" {
+		release, err := h.acquireLock(ctx, key, execID, payload.NamespaceID, "", streamLogger)
+		if err != nil {
+			return err
+		}
+		defer release()
+	}
 
 	// Initialize action_retries for all actions in the flow for new executions only
 	if !payload.Resumed {
@@ -188,28 +337,136 @@ func (h *FlowExecutionHandler) executeFlow(ctx context.Context, execID string, p
 		}
 	}
 
-	// Get flow-specific secrets
-	flowSecrets := h.getFlowSecrets(ctx, payload.Workflow.Meta.ID, payload.NamespaceID, execID)
+	if skipped := payload.Skipped; skipped != nil {
+		if err := streamLogger.Checkpoint(skipped.ActionID, "", []byte(fmt.Sprintf("action %s skipped by %s", skipped.ActionID, skipped.SkippedBy)), streamlogger.LogMessageType); err != nil {
+			h.logger.Error("failed to log skipped action", "execID", execID, "actionID", skipped.ActionID, "error", err)
+		}
+	}
 
-	// Initialize outputs map to accumulate results from all previous actions
-	outputs := make(map[string]any)
+	if payload.Adopted && payload.StartingActionIdx < len(payload.Workflow.Actions) {
+		actionID := payload.Workflow.Actions[payload.StartingActionIdx].ID
+		if err := streamLogger.Checkpoint(actionID, "", []byte(fmt.Sprintf("execution adopted after a server restart, continuing from action %s", actionID)), streamlogger.LogMessageType); err != nil {
+			h.logger.Error("failed to log adopted execution", "execID", execID, "error", err)
+		}
+	}
 
-	for i := payload.StartingActionIdx; i < len(payload.Workflow.Actions); i++ {
-		action := payload.Workflow.Actions[i]
+	// runOne runs an action of the flow with the outputs of the actions that ran before it
+	runOne := func(ctx context.Context, action Action, streamLogger streamlogger.Logger, progress *progressTracker, outputs map[string]any) (map[string]string, error) {
+		// The lock of the flow is already held for the whole execution
+		if action.Lock == payload.Workflow.Meta.Lock {
+			action.Lock = ""
+		}
 
-		res, err := h.executeSingleAction(ctx, action, payload.Workflow.Meta.SrcDir, payload.Input, streamLogger, artifactDir, flowSecrets, outputs, execID, payload.NamespaceID, payload.UserUUID, payload.Workflow.Meta.Namespace)
-		if err != nil {
+		actionCtx, span := tracing.Start(ctx, "run action",
+			attribute.String("flowctl.action", action.ID),
+			attribute.String("flowctl.executor", action.Executor),
+			attribute.Int("flowctl.nodes", len(action.On)),
+		)
+		// The wait of the action the execution resumed from may already be over
+		var wakeAt time.Time
+		if payload.StartingActionIdx < len(payload.Workflow.Actions) && action.ID == payload.Workflow.Actions[payload.StartingActionIdx].ID {
+			wakeAt = payload.WakeAt
+		}
+
+		start := time.Now()
+		res, err := h.executeSingleAction(actionCtx, action, payload.Workflow.Meta.SrcDir, payload.Input, streamLogger, progress, artifactDir, flowSecrets, vars, outputs, execID, payload.NamespaceID, payload.Workflow.Meta.ID, payload.UserUUID, payload.Workflow.Meta.Namespace, wakeAt)
+		endSpan(span, err)
+		h.observeActionDuration(payload, action.ID, time.Since(start), err)
+		return res, err
+	}
+
+	// Initialize outputs map to accumulate results from all previous actions. Executions resumed
+	// after an approval, a wait or a retry start with the outputs of the actions that already ran.
+	outputs := make(map[string]any)
+
+	// The actions of flows that declare needs run as a graph instead of one after another
+	if payload.Workflow.HasNeeds() {
+		if err := h.executeGraph(ctx, execID, payload, streamLogger, outputs, runOne); err != nil {
+			h.saveArtifacts(ctx, execID, err)
 			return err
 		}
+	} else {
+		if payload.Resumed {
+			h.restoreOutputs(ctx, execID, payload.NamespaceID, payload.Workflow.Actions[:payload.StartingActionIdx], outputs)
+		}
+
+		progress := newProgressTracker(h, streamLogger, execID, payload.NamespaceID, payload.Workflow.Actions)
+		// Record any buffered progress before the final status of the execution is set
+		defer progress.flush()
+
+		for i := payload.StartingActionIdx; i < len(payload.Workflow.Actions); i++ {
+			res, err := runOne(ctx, payload.Workflow.Actions[i], streamLogger, progress, outputs)
+			if err != nil {
+				h.saveArtifacts(ctx, execID, err)
+				return err
+			}
 
-		h.logger.Debug("Action results", "results", res)
-		processActionResults(res, outputs)
-		h.logger.Debug("outputs", "results", outputs)
+			h.logger.Debug("Action results", "results", res)
+			MergeActionResults(res, outputs)
+			h.logger.Debug("outputs", "results", outputs)
+		}
 	}
 
 	// Only remove the artifact store when all actions have been executed
 	// This is to account for approval actions that could be run later
-	os.RemoveAll(artifactDir)
+	if err := h.artifacts.Delete(ctx, execID); err != nil {
+		h.logger.Error("failed to delete artifacts", "exec_id", execID, "error", err)
+	}
+	if h.uploads != nil {
+		if err := h.uploads.DeleteExecution(ctx, execID); err != nil {
+			h.logger.Error("failed to delete uploads", "exec_id", execID, "error", err)
+		}
+	}
+	return nil
+}
+
+// saveArtifacts saves the artifacts of an execution that stopped with err, so that it continues
+// with them once it is resumed or retried. The artifacts of cancelled executions are dropped, the
+// ones of executions handed off are restored by the server that adopts them.
+func (h *FlowExecutionHandler) saveArtifacts(ctx context.Context, execID string, err error) {
+	if errors.Is(err, ErrExecutionCancelled) && !handedOff(ctx) {
+		return
+	}
+	if saveErr := h.artifacts.Save(context.WithoutCancel(ctx), execID); saveErr != nil {
+		h.logger.Error("failed to save artifacts", "exec_id", execID, "error", saveErr)
+	}
+}
+
+// downloadUploads downloads the files referenced by file inputs to the uploads directory in the
+// artifact directory and replaces the references with the paths of the downloaded files.
+// Only file inputs are resolved, and only to files uploaded for this execution, so that a
+// reference typed into another input can't pull in the uploads of other executions.
+func (h *FlowExecutionHandler) downloadUploads(ctx context.Context, execID string, inputs []Input, input map[string]any, artifactDir string) error {
+	for _, in := range inputs {
+		if in.Type != INPUT_TYPE_FILE {
+			continue
+		}
+		value, ok := input[in.Name].(string)
+		if !ok {
+			continue
+		}
+		key, ok := uploadstore.ParseRef(value)
+		if !ok {
+			continue
+		}
+		if !uploadstore.BelongsTo(key, execID) {
+			return fmt.Errorf("input %s references an upload of another execution", in.Name)
+		}
+		if h.uploads == nil {
+			return fmt.Errorf("input %s references an upload but no upload store is configured", in.Name)
+		}
+
+		uploadsDir := filepath.Join(artifactDir, "uploads")
+		if err := os.MkdirAll(uploadsDir, 0700); err != nil {
+			return fmt.Errorf("could not create uploads directory: %w", err)
+		}
+
+		target := filepath.Join(uploadsDir, fmt.Sprintf("%s_%s", in.Name, path.Base(key)))
+		if err := h.uploads.Download(ctx, key, target); err != nil {
+			return fmt.Errorf("failed to download file for input %s: %w", in.Name, err)
+		}
+		input[in.Name] = target
+	}
 	return nil
 }
 
@@ -246,6 +503,25 @@ func (h *FlowExecutionHandler) initializeActionRetries(ctx context.Context, exec
 	return nil
 }
 
+// maskedValues returns the secret values and password inputs that should be redacted from the logs
+func maskedValues(inputs []Input, input map[string]any, secrets map[string]string) []string {
+	values := make([]string, 0, len(secrets))
+	for _, v := range secrets {
+		values = append(values, v)
+	}
+
+	for _, in := range inputs {
+		if in.Type != INPUT_TYPE_PASSWORD {
+			continue
+		}
+		if v, ok := input[in.Name]; ok && v != nil {
+			values = append(values, fmt.Sprint(v))
+		}
+	}
+
+	return values
+}
+
 // getFlowSecrets retrieves flow-specific secrets or returns an empty map if unavailable
 func (h *FlowExecutionHandler) getFlowSecrets(ctx context.Context, flowID string, namespaceID string, execID string) map[string]string {
 	if h.secretsProvider == nil {
@@ -261,87 +537,122 @@ func (h *FlowExecutionHandler) getFlowSecrets(ctx context.Context, flowID string
 	return secrets
 }
 
-// copyFlowFilesToArtifacts copies top-level files from the flow directory to the artifacts directory
-func (h *FlowExecutionHandler) copyFlowFilesToArtifacts(flowDir string, artifactDir string) error {
-	localArtifactDir := filepath.Join(artifactDir, "local")
-	if err := os.MkdirAll(localArtifactDir, 0755); err != nil {
-		return fmt.Errorf("failed to create local artifact directory: %w", err)
+// getVariables retrieves the variables of the namespace or returns an empty map if unavailable
+func (h *FlowExecutionHandler) getVariables(ctx context.Context, namespaceID string, execID string) map[string]string {
+	if h.varsProvider == nil {
+		return make(map[string]string)
 	}
 
-	entries, err := os.ReadDir(flowDir)
+	vars, err := h.varsProvider(ctx, namespaceID)
 	if err != nil {
-		return fmt.Errorf("failed to read flow directory: %w", err)
+		h.logger.Error("failed to get variables", "execID", execID, "error", err)
+		return make(map[string]string)
 	}
 
-	for _, entry := range entries {
-		// Skip directories, only copy top-level files
-		if entry.IsDir() {
-			continue
-		}
-
-		srcPath := filepath.Join(flowDir, entry.Name())
-		destPath := filepath.Join(localArtifactDir, entry.Name())
-
-		srcFile, err := os.Open(srcPath)
-		if err != nil {
-			return fmt.Errorf("failed to open source file %s: %w", srcPath, err)
-		}
-		defer srcFile.Close()
-
-		destFile, err := os.Create(destPath)
-		if err != nil {
-			return fmt.Errorf("failed to create destination file %s: %w", destPath, err)
-		}
-		defer destFile.Close()
+	return vars
+}
 
-		if _, err := io.Copy(destFile, srcFile); err != nil {
-			return fmt.Errorf("failed to copy file %s to %s: %w", srcPath, destPath, err)
-		}
+// copyFlowFilesToArtifacts copies top-level files from the flow directory to the artifacts directory
+func (h *FlowExecutionHandler) copyFlowFilesToArtifacts(ctx context.Context, flowDir string, artifactDir string) error {
+	localArtifactDir := filepath.Join(artifactDir, "local")
+	if err := os.MkdirAll(localArtifactDir, 0755); err != nil {
+		return fmt.Errorf("failed to create local artifact directory: %w", err)
+	}
 
-		h.logger.Debug("copied flow file to artifacts", "src", srcPath, "dest", destPath)
+	if err := h.flowFiles.CopyFiles(ctx, flowDir, localArtifactDir); err != nil {
+		return err
 	}
 
+	h.logger.Debug("copied flow files to artifacts", "src", flowDir, "dest", localArtifactDir)
 	return nil
 }
 
 // executeSingleAction executes a single action within a flow, handling approval and error checkpointing
-func (h *FlowExecutionHandler) executeSingleAction(ctx context.Context, action Action, srcDir string, input map[string]any, streamLogger streamlogger.Logger, artifactDir string, secrets map[string]string, outputs map[string]any, execID string, namespaceID string, userUUID string, namespaceName string) (map[string]string, error) {
+func (h *FlowExecutionHandler) executeSingleAction(ctx context.Context, action Action, srcDir string, input map[string]any, streamLogger streamlogger.Logger, progress *progressTracker, artifactDir string, secrets map[string]string, vars map[string]string, outputs map[string]any, execID string, namespaceID string, flowID string, userUUID string, namespaceName string, wakeAt time.Time) (map[string]string, error) {
 	// Check for context cancellation
 	if ctx.Err() != nil {
+		if handedOff(ctx) {
+			h.logHandoff(execID, action.ID, streamLogger)
+			return nil, ErrExecutionCancelled
+		}
 		if err := streamLogger.Checkpoint("", "", "execution cancelled", streamlogger.CancelledMessageType); err != nil {
 			h.logger.Error("failed to send cancellation message", "error", err)
 		}
 		return nil, ErrExecutionCancelled
 	}
 
+	// Actions whose condition is false are skipped before they request an approval or wait
+	run, err := h.checkCondition(execID, action, input, secrets, vars, outputs, streamLogger)
+	if err != nil {
+		streamLogger.Checkpoint(action.ID, "", err.Error(), streamlogger.ErrMessageType)
+		return nil, err
+	}
+	if !run {
+		return map[string]string{}, nil
+	}
+
 	// Check for approval requests
 	if err := h.checkApproval(ctx, execID, action, namespaceID); err != nil {
 		return nil, err
 	}
 
-	// Increment retry count for this action
+	// Manual actions wait until their task is completed
+	if err := h.checkManualTask(ctx, execID, action, namespaceID); err != nil {
+		return nil, err
+	}
+
+	// Wait actions park the execution until the wait is over
+	if err := h.checkWait(ctx, execID, action, input, vars, outputs, namespaceID, wakeAt, streamLogger); err != nil {
+		return nil, err
+	}
+
+	// Flow actions wait until the execution of the flow they run finished
+	if err := h.checkSubflow(ctx, execID, action, input, vars, outputs, namespaceID, userUUID, streamLogger); err != nil {
+		return nil, err
+	}
+
+	// Actions sharing a lock run one at a time, the lock is taken once the action is approved
+	if action.Lock != "" {
+		release, err := h.acquireLock(ctx, action.Lock, execID, namespaceID, action.ID, streamLogger)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+	}
+
+	// Set the current action and increment its retry count
 	namespaceUUID, err := uuid.Parse(namespaceID)
 	if err != nil {
 		return nil, fmt.Errorf("invalid namespace UUID: %w", err)
 	}
 
-	row, err := h.store.IncrementActionRetry(ctx, repo.IncrementActionRetryParams{
-		ExecID:  execID,
-		Column2: action.ID,
-		Uuid:    namespaceUUID,
+	h.logger.Debug("current action", "actionID", action.ID)
+	retryCount, err := h.store.StartExecutionAction(ctx, repo.StartExecutionActionParams{
+		NamespaceUuid: namespaceUUID,
+		ExecID:        execID,
+		ActionID:      action.ID,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to increment retry count for action %s: %w", action.ID, err)
+		return nil, fmt.Errorf("could not start action %s in exec %s: %w", action.ID, execID, err)
 	}
 
-	streamLogger.SetRetry(row.RetryCount)
-	h.logger.Debug("action retry count", "action", action.ID, "retry", row.RetryCount)
+	streamLogger.SetRetry(retryCount)
+	h.logger.Debug("action retry count", "action", action.ID, "retry", retryCount)
 
 	// Run the action
-	res, err := h.runAction(ctx, execID, action, input, streamLogger, artifactDir, secrets, outputs, userUUID, namespaceName)
+	progress.actionStarted(ctx, action, retryCount)
+	res, err := h.runAction(ctx, execID, action, input, streamLogger, progress, artifactDir, secrets, vars, outputs, namespaceID, flowID, userUUID, namespaceName)
+	if err == nil {
+		err = h.archiveArtifacts(ctx, execID, action, artifactDir, namespaceID, streamLogger)
+	}
+	progress.actionFinished(ctx, action.ID, err)
 	if err != nil {
 		// Check if the error is due to context cancellation
 		if errors.Is(err, context.Canceled) {
+			if handedOff(ctx) {
+				h.logHandoff(execID, action.ID, streamLogger)
+				return nil, ErrExecutionCancelled
+			}
 			if streamErr := streamLogger.Checkpoint(action.ID, "", "execution cancelled", streamlogger.CancelledMessageType); streamErr != nil {
 				h.logger.Error("failed to send cancelled message", "execID", execID, "actionID", action.ID, "error", streamErr)
 			}
@@ -359,8 +670,9 @@ func (h *FlowExecutionHandler) executeSingleAction(ctx context.Context, action A
 	return res, nil
 }
 
-// processActionResults processes action results and updates the outputs map
-func processActionResults(results map[string]string, outputs map[string]any) {
+// MergeActionResults adds the results of an action to the outputs of an execution. Results of a
+// node are grouped under the node name.
+func MergeActionResults(results map[string]string, outputs map[string]any) {
 	for k, v := range results {
 		parts := strings.SplitN(k, "@", 2)
 		// node suffixed output
@@ -379,7 +691,7 @@ func processActionResults(results map[string]string, outputs map[string]any) {
 }
 
 // executeOnNode executes an action on a single node and returns the results
-func (h *FlowExecutionHandler) executeOnNode(ctx context.Context, execID string, node Node, action Action, streamLogger streamlogger.Logger, inputVars map[string]any, withConfig []byte, artifactDir string, userUUID string, namespaceName string, allNodes []Node) ExecResults {
+func (h *FlowExecutionHandler) executeOnNode(ctx context.Context, execID string, namespaceID string, node Node, action Action, streamLogger streamlogger.Logger, inputVars map[string]any, withConfig []byte, artifactDir string, userUUID string, namespaceName string, allNodes []Node) ExecResults {
 	// Create a separate executor instance for each node
 	var exec executor.Executor
 	nodeExecutorID := fmt.Sprintf("%s-%s", action.ID, node.Name)
@@ -398,8 +710,9 @@ func (h *FlowExecutionHandler) executeOnNode(ctx context.Context, execID string,
 		if err := node.CheckConnectivity(); err != nil {
 			h.logger.Debug("node connectivity", "error", err)
 			return ExecResults{
-				result: nil,
-				err:    fmt.Errorf("failed to connect to node %s", node.Name),
+				result:      nil,
+				err:         fmt.Errorf("failed to connect to node %s", node.Name),
+				unreachable: true,
 			}
 		}
 	}
@@ -444,7 +757,10 @@ func (h *FlowExecutionHandler) executeOnNode(ctx context.Context, execID string,
 	defer artifactDriver.Close()
 
 	// Push existing artifacts to this node's executor before execution
-	if err := h.pushArtifactsWithDriver(ctx, artifactDriver, artifactDir, execID); err != nil {
+	pushCtx, pushSpan := tracing.Start(ctx, "push artifacts")
+	err = h.pushArtifactsWithDriver(pushCtx, artifactDriver, artifactDir, execID)
+	tracing.End(pushSpan, err)
+	if err != nil {
 		return ExecResults{
 			result: nil,
 			err:    fmt.Errorf("failed to push artifacts to node %s: %w", node.Name, err),
@@ -452,7 +768,7 @@ func (h *FlowExecutionHandler) executeOnNode(ctx context.Context, execID string,
 	}
 
 	// Transform file paths for remote execution
-	execInputVars := h.transformPaths(inputVars, artifactDir, exec)
+	execInputVars := h.transformPaths(inputVars, artifactDir, exec, artifactDriver)
 
 	var apiKey string
 	if key, ok := h.executorKeys[action.Executor]; ok {
@@ -477,6 +793,13 @@ func (h *FlowExecutionHandler) executeOnNode(ctx context.Context, execID string,
 		}
 	}
 
+	// Sample the node's resource usage while the action runs
+	stopTelemetry := func() {}
+	// Resource usage is read from procfs, which Windows nodes don't have
+	if action.Telemetry && node.OSFamily != executor.OSFamilyWindows {
+		stopTelemetry = h.sampleNodeResources(ctx, artifactDriver, execID, namespaceID, action.ID, node.Name)
+	}
+
 	res, err := exec.Execute(ctx, executor.ExecutionContext{
 		Inputs:        execInputVars,
 		WithConfig:    withConfig,
@@ -488,10 +811,16 @@ func (h *FlowExecutionHandler) executeOnNode(ctx context.Context, execID string,
 		APIBaseURL:    h.apiBaseURL,
 		Nodes:         execNodes,
 	})
+	stopTelemetry()
+
+	h.recordEnvironment(ctx, artifactDriver, exec, execID, namespaceID, action, node.Name)
 
 	// Pull all artifacts from this node after execution
 	if err == nil {
-		if pullErr := h.pullArtifactsWithDriver(ctx, artifactDriver, artifactDir, execID, node.Name); pullErr != nil {
+		pullCtx, pullSpan := tracing.Start(ctx, "pull artifacts")
+		pullErr := h.pullArtifactsWithDriver(pullCtx, artifactDriver, artifactDir, execID, node.Name)
+		tracing.End(pullSpan, pullErr)
+		if pullErr != nil {
 			err = fmt.Errorf("execution succeeded but failed to pull artifacts: %w", pullErr)
 		}
 	}
@@ -505,6 +834,193 @@ func (h *FlowExecutionHandler) executeOnNode(ctx context.Context, execID string,
 	}
 }
 
+// observeActionDuration records how long an action ran. Actions that stopped to wait for an
+// approval did not run and are not recorded.
+func (h *FlowExecutionHandler) observeActionDuration(payload FlowExecutionPayload, actionID string, duration time.Duration, err error) {
+	if h.metrics == nil || isPaused(err) {
+		return
+	}
+
+	state := "completed"
+	if errors.Is(err, ErrExecutionCancelled) {
+		state = "cancelled"
+	} else if err != nil {
+		state = "errored"
+	}
+	h.metrics.ObserveActionDuration(payload.NamespaceID, payload.Workflow.Meta.ID, actionID, state, duration)
+}
+
+// recordLogBytes adds the bytes of logs written by a run of an execution to its log usage
+func (h *FlowExecutionHandler) recordLogBytes(ctx context.Context, execID string, namespaceID string, n int64) {
+	if n == 0 {
+		return
+	}
+
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		h.logger.Error("invalid namespace UUID", "exec_id", execID, "error", err)
+		return
+	}
+
+	if err := h.store.AddExecutionLogBytes(ctx, repo.AddExecutionLogBytesParams{
+		ExecID:   execID,
+		Uuid:     namespaceUUID,
+		LogBytes: n,
+	}); err != nil {
+		h.logger.Error("failed to record log usage", "exec_id", execID, "error", err)
+	}
+}
+
+// checkExecutorPolicy checks that the actions left to run only use executors allowed in the namespace
+func (h *FlowExecutionHandler) checkExecutorPolicy(ctx context.Context, payload FlowExecutionPayload) error {
+	namespaceUUID, err := uuid.Parse(payload.NamespaceID)
+	if err != nil {
+		return fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	policy, err := h.store.GetNamespaceExecutorPolicy(ctx, namespaceUUID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not get executor policy: %w", err)
+	}
+
+	// Any action that didn't complete may run again in flows that declare needs
+	actions := payload.Workflow.Actions[payload.StartingActionIdx:]
+	if payload.Workflow.HasNeeds() {
+		actions = payload.Workflow.Actions
+	}
+	for _, action := range actions {
+		if IsBuiltinExecutor(action.Executor) {
+			continue
+		}
+		if !slices.Contains(policy.AllowedExecutors, action.Executor) {
+			return fmt.Errorf("action %s uses executor %q which is not allowed in this namespace", action.ID, action.Executor)
+		}
+	}
+	return nil
+}
+
+// recordOutputs stores the results of an action so that they can be fetched after the execution.
+// Errors are logged since the results are also in the execution's log.
+func (h *FlowExecutionHandler) recordOutputs(ctx context.Context, execID string, namespaceID string, actionID string, results map[string]string) {
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		h.logger.Error("invalid namespace UUID", "exec_id", execID, "error", err)
+		return
+	}
+
+	outputs, err := json.Marshal(results)
+	if err != nil {
+		h.logger.Error("failed to marshal action outputs", "exec_id", execID, "action_id", actionID, "error", err)
+		return
+	}
+
+	if err := h.store.RecordExecutionOutputs(ctx, repo.RecordExecutionOutputsParams{
+		ExecID:        execID,
+		NamespaceUuid: namespaceUUID,
+		ActionID:      actionID,
+		Outputs:       outputs,
+	}); err != nil {
+		h.logger.Error("failed to record action outputs", "exec_id", execID, "action_id", actionID, "error", err)
+	}
+}
+
+// restoreOutputs adds the recorded results of the given actions to the outputs of an execution, in
+// the order they finished. Results of actions that ran in an earlier attempt but are not among them
+// are left out since those actions run again. The results were recorded masked, so secrets in them
+// are not passed on. Errors are logged and the execution continues without the results.
+func (h *FlowExecutionHandler) restoreOutputs(ctx context.Context, execID string, namespaceID string, actions []Action, outputs map[string]any) {
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		h.logger.Error("invalid namespace UUID", "exec_id", execID, "error", err)
+		return
+	}
+
+	rows, err := h.store.ListExecutionOutputs(ctx, repo.ListExecutionOutputsParams{
+		ExecID: execID,
+		Uuid:   namespaceUUID,
+	})
+	if err != nil {
+		h.logger.Error("failed to get action outputs", "exec_id", execID, "error", err)
+		return
+	}
+
+	for _, r := range rows {
+		if !slices.ContainsFunc(actions, func(a Action) bool { return a.ID == r.ActionID }) {
+			continue
+		}
+
+		var results map[string]string
+		if err := json.Unmarshal(r.Outputs, &results); err != nil {
+			h.logger.Error("failed to unmarshal action outputs", "exec_id", execID, "action_id", r.ActionID, "error", err)
+			continue
+		}
+		MergeActionResults(results, outputs)
+	}
+}
+
+// outputsLogger records the results of the actions of an execution as they are checkpointed
+type outputsLogger struct {
+	streamlogger.Logger
+	record func(actionID string, results map[string]string)
+}
+
+func (l *outputsLogger) Checkpoint(id string, nodeID string, val interface{}, mtype streamlogger.MessageType) error {
+	if err := l.Logger.Checkpoint(id, nodeID, val, mtype); err != nil {
+		return err
+	}
+	if results, ok := val.(map[string]string); ok && mtype == streamlogger.ResultMessageType {
+		l.record(id, results)
+	}
+	return nil
+}
+
+// countingLogger counts the bytes of output written to an execution's log
+type countingLogger struct {
+	streamlogger.Logger
+	add func(n int)
+}
+
+func (l *countingLogger) Write(p []byte) (int, error) {
+	n, err := l.Logger.Write(p)
+	l.add(n)
+	return n, err
+}
+
+func (l *countingLogger) Checkpoint(id string, nodeID string, val interface{}, mtype streamlogger.MessageType) error {
+	if err := l.Logger.Checkpoint(id, nodeID, val, mtype); err != nil {
+		return err
+	}
+	if mtype == streamlogger.LogMessageType {
+		switch v := val.(type) {
+		case []byte:
+			l.add(len(v))
+		case string:
+			l.add(len(v))
+		}
+	}
+	return nil
+}
+
+// endSpan ends a span of the execution. Pausing for an approval, a wait or a flow action is not
+// recorded as an error.
+func endSpan(span trace.Span, err error) {
+	if isPaused(err) {
+		err = nil
+	}
+	tracing.End(span, err)
+}
+
+// isPaused reports whether the execution stopped to wait for an approval, a wait action or the
+// execution of a flow action
+func isPaused(err error) bool {
+	var waitErr *WaitError
+	var subErr *SubflowError
+	return errors.Is(err, ErrPendingApproval) || errors.As(err, &waitErr) || errors.As(err, &subErr)
+}
+
 // prefixResultKeys adds node name suffix to result keys for node-specific outputs
 func prefixResultKeys(results map[string]string, nodeName string) map[string]string {
 	prefixedRes := make(map[string]string)
@@ -521,7 +1037,7 @@ func prefixResultKeys(results map[string]string, nodeName string) map[string]str
 }
 
 // interpolateVariables processes action variables and replaces templated values with evaluated expressions
-func (h *FlowExecutionHandler) interpolateVariables(action Action, input map[string]any, secrets map[string]string, outputs map[string]any) (map[string]any, error) {
+func (h *FlowExecutionHandler) interpolateVariables(action Action, input map[string]any, secrets map[string]string, vars map[string]string, outputs map[string]any) (map[string]any, error) {
 	// pattern to extract interpolated variables
 	pattern := `{{\s*([^}]+)\s*}}`
 	re := regexp.MustCompile(pattern)
@@ -537,6 +1053,7 @@ func (h *FlowExecutionHandler) interpolateVariables(action Action, input map[str
 			env := map[string]any{
 				"inputs":  input,
 				"secrets": secrets,
+				"vars":    vars,
 				"outputs": outputs,
 			}
 
@@ -564,14 +1081,29 @@ func (h *FlowExecutionHandler) interpolateVariables(action Action, input map[str
 }
 
 // runAction executes a single action
-func (h *FlowExecutionHandler) runAction(ctx context.Context, execID string, action Action, input map[string]any, streamLogger streamlogger.Logger, artifactDir string, secrets map[string]string, outputs map[string]any, userUUID string, namespaceName string) (map[string]string, error) {
+func (h *FlowExecutionHandler) runAction(ctx context.Context, execID string, action Action, input map[string]any, streamLogger streamlogger.Logger, progress *progressTracker, artifactDir string, secrets map[string]string, vars map[string]string, outputs map[string]any, namespaceID string, flowID string, userUUID string, namespaceName string) (map[string]string, error) {
 	streamLogger.SetActionID(action.ID)
 
+	// Manual actions run nothing, their results are the response to their task
+	if action.Executor == ExecutorManual {
+		return h.manualTaskResults(ctx, execID, action, namespaceID)
+	}
+
+	// Wait actions run nothing once the wait is over
+	if action.Executor == ExecutorWait {
+		return map[string]string{}, nil
+	}
+
+	// Flow actions run nothing, their results are the outputs of the execution of the flow
+	if action.Executor == ExecutorFlow {
+		return h.subflowResults(ctx, execID, action, namespaceID)
+	}
+
 	jobCtx, cancel := context.WithTimeout(ctx, h.executionTimeout)
 	defer cancel()
 
 	// Interpolate variables
-	inputVars, err := h.interpolateVariables(action, input, secrets, outputs)
+	inputVars, err := h.interpolateVariables(action, input, secrets, vars, outputs)
 	if err != nil {
 		return nil, err
 	}
@@ -592,7 +1124,14 @@ func (h *FlowExecutionHandler) runAction(ctx context.Context, execID string, act
 		wg.Add(1)
 		go func(node Node) {
 			defer wg.Done()
-			result := h.executeOnNode(jobCtx, execID, node, action, streamLogger, inputVars, withConfig, artifactDir, userUUID, namespaceName, action.On)
+			progress.nodeStarted(jobCtx, action.ID, node.Name)
+			nodeCtx, span := tracing.Start(jobCtx, "run on node", attribute.String("flowctl.node", node.Name))
+			result := h.executeOnNode(nodeCtx, execID, namespaceID, node, action, streamLogger, inputVars, withConfig, artifactDir, userUUID, namespaceName, action.On)
+			tracing.End(span, result.err)
+			if result.unreachable && h.metrics != nil {
+				h.metrics.IncNodeConnectivityFailures(namespaceID, flowID, node.Name)
+			}
+			progress.nodeFinished(jobCtx, action.ID, node.Name, result.err)
 			resChan <- result
 		}(node)
 	}
@@ -617,8 +1156,9 @@ func (h *FlowExecutionHandler) runAction(ctx context.Context, execID string, act
 }
 
 // transformPaths replaces local artifact paths with executor artifact paths in input variables.
-// File input paths that reference the local artifact directory are converted to use the executor's artifact directory as the base path.
-func (h *FlowExecutionHandler) transformPaths(inputVars map[string]any, localArtifactDir string, exec executor.Executor) map[string]any {
+// File input paths that reference the local artifact directory are converted to use the executor's artifact directory as the base path,
+// joined with the path separator of the node.
+func (h *FlowExecutionHandler) transformPaths(inputVars map[string]any, localArtifactDir string, exec executor.Executor, driver executor.NodeDriver) map[string]any {
 	execArtifactDir := exec.GetArtifactsDir()
 	transformed := make(map[string]any, len(inputVars))
 
@@ -627,7 +1167,7 @@ func (h *FlowExecutionHandler) transformPaths(inputVars map[string]any, localArt
 		if strVal, ok := v.(string); ok && strings.HasPrefix(strVal, localArtifactDir) {
 			relPath, err := filepath.Rel(localArtifactDir, strVal)
 			if err == nil {
-				transformed[k] = filepath.Join(execArtifactDir, relPath)
+				transformed[k] = driver.Join(execArtifactDir, relPath)
 			}
 		}
 	}
@@ -709,26 +1249,18 @@ func (h *FlowExecutionHandler) pullArtifactsWithDriver(ctx context.Context, driv
 	return nil
 }
 
+// checkApproval returns nil if the action can run. Actions that are waiting for approval or were
+// rejected are set as the current action of the execution, the others are set when they start.
 func (h *FlowExecutionHandler) checkApproval(ctx context.Context, execID string, action Action, namespaceID string) error {
+	if !action.Approval {
+		return nil
+	}
+
 	namespaceUUID, err := uuid.Parse(namespaceID)
 	if err != nil {
 		return fmt.Errorf("invalid namespace UUID: %w", err)
 	}
 
-	// Set the current action ID
-	h.logger.Debug("current action", "actionID", action.ID)
-	if _, err := h.store.UpdateExecutionActionID(ctx, repo.UpdateExecutionActionIDParams{
-		CurrentActionID: sql.NullString{String: action.ID, Valid: action.ID != ""},
-		ExecID:          execID,
-		Uuid:            namespaceUUID,
-	}); err != nil {
-		return fmt.Errorf("could not update current action ID in exec %s: %w", execID, err)
-	}
-
-	if !action.Approval {
-		return nil
-	}
-
 	// check if pending approval, exit if not approved
 	a, err := h.store.GetApprovalRequestForActionAndExec(ctx, repo.GetApprovalRequestForActionAndExecParams{
 		ExecID:   execID,
@@ -744,6 +1276,16 @@ func (h *FlowExecutionHandler) checkApproval(ctx context.Context, execID string,
 		return nil
 	}
 
+	// Set the current action ID so that the execution is resumed or retried from this action
+	h.logger.Debug("current action", "actionID", action.ID)
+	if _, err := h.store.UpdateExecutionActionID(ctx, repo.UpdateExecutionActionIDParams{
+		CurrentActionID: sql.NullString{String: action.ID, Valid: action.ID != ""},
+		ExecID:          execID,
+		Uuid:            namespaceUUID,
+	}); err != nil {
+		return fmt.Errorf("could not update current action ID in exec %s: %w", execID, err)
+	}
+
 	if a.Status == repo.ApprovalStatusRejected {
 		return fmt.Errorf("request for running action %q is rejected", action.Name)
 	}
@@ -762,34 +1304,40 @@ func (h *FlowExecutionHandler) checkApproval(ctx context.Context, execID string,
 
 // setStatus updates the execution status in the execution_log table
 func (h *FlowExecutionHandler) setStatus(ctx context.Context, execID string, status repo.ExecutionStatus, namespaceID string, err error) error {
+	_, err = h.updateStatus(ctx, execID, status, namespaceID, err)
+	return err
+}
+
+// updateStatus sets the execution status and returns the updated execution
+func (h *FlowExecutionHandler) updateStatus(ctx context.Context, execID string, status repo.ExecutionStatus, namespaceID string, err error) (repo.ExecutionLog, error) {
 	var errMsg sql.NullString
 	if err != nil {
 		errMsg = sql.NullString{String: err.Error(), Valid: true}
 	}
 	namespaceUUID, parseErr := uuid.Parse(namespaceID)
 	if parseErr != nil {
-		return fmt.Errorf("invalid namespace ID: %w", parseErr)
+		return repo.ExecutionLog{}, fmt.Errorf("invalid namespace ID: %w", parseErr)
 	}
-	_, updateErr := h.store.UpdateExecutionStatus(ctx, repo.UpdateExecutionStatusParams{
+	exec, updateErr := h.store.UpdateExecutionStatus(ctx, repo.UpdateExecutionStatusParams{
 		Status: status,
 		Error:  errMsg,
 		ExecID: execID,
 		Uuid:   namespaceUUID,
 	})
 	if updateErr != nil {
-		return fmt.Errorf("could not update error execution status: %w", updateErr)
+		return repo.ExecutionLog{}, fmt.Errorf("could not update error execution status: %w", updateErr)
 	}
 
-	return nil
+	return exec, nil
 }
 
-// setStartedAt sets the started_at timestamp when execution begins running
-func (h *FlowExecutionHandler) setStartedAt(ctx context.Context, execID string, namespaceID string) error {
+// markRunning sets the execution status to running and the started_at timestamp, if it isn't set yet
+func (h *FlowExecutionHandler) markRunning(ctx context.Context, execID string, namespaceID string) error {
 	namespaceUUID, err := uuid.Parse(namespaceID)
 	if err != nil {
 		return fmt.Errorf("invalid namespace ID: %w", err)
 	}
-	return h.store.UpdateExecutionStartedAt(ctx, repo.UpdateExecutionStartedAtParams{
+	return h.store.MarkExecutionRunning(ctx, repo.MarkExecutionRunningParams{
 		ExecID: execID,
 		Uuid:   namespaceUUID,
 	})
@@ -808,7 +1356,15 @@ func (h *FlowExecutionHandler) createExecutionLog(ctx context.Context, execID st
 		return fmt.Errorf("invalid user UUID: %w", err)
 	}
 
-	inputJSON, err := json.Marshal(payload.Input)
+	input := payload.Input
+	if h.inputSealer != nil {
+		input, err = h.inputSealer(ctx, payload.Workflow.Inputs, payload.Input)
+		if err != nil {
+			return fmt.Errorf("failed to seal input: %w", err)
+		}
+	}
+
+	inputJSON, err := json.Marshal(input)
 	if err != nil {
 		return fmt.Errorf("failed to marshal input: %w", err)
 	}
@@ -835,7 +1391,8 @@ func (h *FlowExecutionHandler) createExecutionLog(ctx context.Context, execID st
 
 // setStatusWithMetrics updates the execution status and tracks metrics
 func (h *FlowExecutionHandler) setStatusWithMetrics(ctx context.Context, execID string, status repo.ExecutionStatus, payload FlowExecutionPayload, execErr error) error {
-	if err := h.setStatus(ctx, execID, status, payload.NamespaceID, execErr); err != nil {
+	exec, err := h.updateStatus(ctx, execID, status, payload.NamespaceID, execErr)
+	if err != nil {
 		return err
 	}
 
@@ -843,6 +1400,10 @@ func (h *FlowExecutionHandler) setStatusWithMetrics(ctx context.Context, execID
 	namespaceID := payload.NamespaceID
 
 	if h.metrics != nil {
+		// Executions that waited for an approval keep the time they first started at
+		if status != repo.ExecutionStatusPendingApproval && exec.StartedAt.Valid && exec.CompletedAt.Valid {
+			h.metrics.ObserveExecutionDuration(namespaceID, flowID, string(status), exec.CompletedAt.Time.Sub(exec.StartedAt.Time))
+		}
 		switch status {
 		case repo.ExecutionStatusCompleted:
 			h.metrics.IncrementExecutionCount(namespaceID, flowID, "completed")
@@ -859,62 +1420,136 @@ func (h *FlowExecutionHandler) setStatusWithMetrics(ctx context.Context, execID
 	h.logger.Debug("notification event", "status", status)
 	h.enqueueNotifications(ctx, execID, status, payload, execErr)
 
+	if h.completionHook != nil && status != repo.ExecutionStatusPendingApproval {
+		if err := h.completionHook(ctx, execID, string(status), flowID, namespaceID); err != nil {
+			h.logger.Error("completion hook failed", "execID", execID, "status", status, "error", err)
+		}
+	}
+
 	return nil
 }
 
 // enqueueNotifications queues notification jobs for matching notify configurations
 func (h *FlowExecutionHandler) enqueueNotifications(ctx context.Context, execID string, status repo.ExecutionStatus, payload FlowExecutionPayload, execErr error) {
-	if h.taskQueuer == nil || len(payload.Workflow.Notify) == 0 {
+	if h.taskQueuer == nil {
 		return
 	}
 
-	// Map execution status to notify event
-	var event NotifyEvent
-	switch status {
-	case repo.ExecutionStatusCompleted:
-		event = NotifyEventOnSuccess
-	case repo.ExecutionStatusErrored:
-		event = NotifyEventOnFailure
-	case repo.ExecutionStatusCancelled:
-		event = NotifyEventOnCancelled
-	case repo.ExecutionStatusPendingApproval:
-		event = NotifyEventOnWaiting
-	default:
-		return
+	var errMsg string
+	if execErr != nil {
+		errMsg = execErr.Error()
 	}
 
-	h.logger.Debug("notification event", "event", event, "status", status)
+	notifyPayload := NotificationPayload{
+		FlowID:      payload.Workflow.Meta.ID,
+		FlowName:    payload.Workflow.Meta.Name,
+		ExecID:      execID,
+		Status:      string(status),
+		Error:       errMsg,
+		NamespaceID: payload.NamespaceID,
+		Approval:    h.approvalDecision(ctx, execID, payload.NamespaceID),
+	}
 
-	// Find matching notify configurations
-	for _, notify := range payload.Workflow.Notify {
-		if !slices.Contains(notify.Events, event) {
-			continue
-		}
+	if err := QueueNotifications(ctx, h.taskQueuer, payload.Workflow, notifyPayload); err != nil {
+		h.logger.Error("failed to queue notifications", "execID", execID, "status", status, "error", err)
+	}
+	if err := h.queueWatchNotifications(ctx, notifyPayload); err != nil {
+		h.logger.Error("failed to queue watch notifications", "execID", execID, "status", status, "error", err)
+	}
+	if err := h.queueApproverNotifications(ctx, payload.Workflow, notifyPayload); err != nil {
+		h.logger.Error("failed to queue approver notifications", "execID", execID, "status", status, "error", err)
+	}
+}
+
+// queueApproverNotifications emails the approvers of the action the execution is waiting on, if
+// the action restricts who can approve it
+func (h *FlowExecutionHandler) queueApproverNotifications(ctx context.Context, flow Flow, payload NotificationPayload) error {
+	if payload.Status != string(repo.ExecutionStatusPendingApproval) {
+		return nil
+	}
+
+	namespaceUUID, err := uuid.Parse(payload.NamespaceID)
+	if err != nil {
+		return fmt.Errorf("invalid namespace ID: %w", err)
+	}
 
-		var errMsg string
-		if execErr != nil {
-			errMsg = execErr.Error()
+	a, err := h.store.GetApprovalRequestForExec(ctx, repo.GetApprovalRequestForExecParams{
+		ExecID: payload.ExecID,
+		Uuid:   namespaceUUID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
 		}
+		return fmt.Errorf("could not get approval request: %w", err)
+	}
+	// The execution may be waiting on a manual task instead
+	if a.Status != repo.ApprovalStatusPending {
+		return nil
+	}
 
-		notifyPayload := NotificationPayload{
-			FlowID:      payload.Workflow.Meta.ID,
-			FlowName:    payload.Workflow.Meta.Name,
-			ExecID:      execID,
-			Status:      string(status),
-			Error:       errMsg,
-			Config:      notify.Config,
-			NamespaceID: payload.NamespaceID,
-			Channel:     notify.Channel,
+	for _, action := range flow.Actions {
+		if action.ID == a.ActionID && len(action.Approvers) > 0 {
+			return QueueNotifications(ctx, h.taskQueuer, Flow{Notify: []Notify{ApproverNotify(action.Approvers)}}, payload)
 		}
+	}
+	return nil
+}
 
-		// Generate a unique exec ID for the notification job
-		notifyExecID := fmt.Sprintf("notify-%s-%s", execID, notify.Channel)
+// queueWatchNotifications emails the users watching the execution or its flow when it completes or fails
+func (h *FlowExecutionHandler) queueWatchNotifications(ctx context.Context, payload NotificationPayload) error {
+	event, ok := notifyEventForStatus(payload.Status)
+	if !ok || !slices.Contains(WatchNotifyEvents, event) {
+		return nil
+	}
 
-		if _, err := h.taskQueuer.QueueTaskWithRetries(ctx, PayloadTypeNotification, notifyExecID, notifyPayload, 3); err != nil {
-			h.logger.Error("failed to queue notification", "execID", execID, "channel", notify.Channel, "error", err)
-		} else {
-			h.logger.Debug("notification queued", "execID", execID, "channel", notify.Channel, "event", event)
+	namespaceUUID, err := uuid.Parse(payload.NamespaceID)
+	if err != nil {
+		return fmt.Errorf("invalid namespace ID: %w", err)
+	}
+
+	watchers, err := h.store.ListExecutionWatchers(ctx, repo.ListExecutionWatchersParams{
+		NamespaceUuid: namespaceUUID,
+		FlowSlug:      payload.FlowID,
+		ExecID:        payload.ExecID,
+	})
+	if err != nil {
+		return fmt.Errorf("could not get watchers: %w", err)
+	}
+	if len(watchers) == 0 {
+		return nil
+	}
+
+	return QueueNotifications(ctx, h.taskQueuer, Flow{Notify: []Notify{WatchNotify(watchers)}}, payload)
+}
+
+// approvalDecision returns the decision taken on the execution's approval request, if any
+func (h *FlowExecutionHandler) approvalDecision(ctx context.Context, execID string, namespaceID string) *messengers.ApprovalDecision {
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return nil
+	}
+
+	a, err := h.store.GetApprovalRequestForExec(ctx, repo.GetApprovalRequestForExecParams{
+		ExecID: execID,
+		Uuid:   namespaceUUID,
+	})
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			h.logger.Warn("could not get approval request for notification", "execID", execID, "error", err)
 		}
+		return nil
+	}
+
+	if a.Status == repo.ApprovalStatusPending {
+		return nil
+	}
+
+	return &messengers.ApprovalDecision{
+		ActionID:  a.ActionID,
+		Status:    string(a.Status),
+		DecidedBy: a.DecidedByName.String,
+		Comment:   a.Comment,
 	}
 }
 
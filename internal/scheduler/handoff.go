@@ -0,0 +1,65 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cvhariharan/flowctl/internal/repo"
+	"github.com/cvhariharan/flowctl/internal/streamlogger"
+	"github.com/google/uuid"
+)
+
+// handedOff reports whether ctx was cancelled to hand the execution off to another server
+func handedOff(ctx context.Context) bool {
+	return errors.Is(context.Cause(ctx), ErrHandoff)
+}
+
+// adoptExecution prepares payload to continue an execution that is still running when its job is
+// picked up. The server running it stopped, or lost its connection to the job queue, before the
+// execution finished and so the job was released. The execution continues from the action it was
+// running, with the outputs and artifacts of the actions that already ran.
+func (h *FlowExecutionHandler) adoptExecution(ctx context.Context, execID string, payload *FlowExecutionPayload) (bool, error) {
+	namespaceUUID, err := uuid.Parse(payload.NamespaceID)
+	if err != nil {
+		return false, fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	exec, err := h.store.GetExecutionByExecID(ctx, repo.GetExecutionByExecIDParams{
+		ExecID: execID,
+		Uuid:   namespaceUUID,
+	})
+	if err != nil {
+		// Executions of cron jobs are created once their job is picked up
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+	if exec.Status != repo.ExecutionStatusRunning {
+		return false, nil
+	}
+
+	// The action that was interrupted runs again
+	for i, action := range payload.Workflow.Actions {
+		if action.ID == exec.CurrentActionID.String && i > payload.StartingActionIdx {
+			payload.StartingActionIdx = i
+			payload.WakeAt = time.Time{}
+			break
+		}
+	}
+	payload.Resumed = true
+	payload.Adopted = true
+	payload.Skipped = nil
+
+	return true, nil
+}
+
+// logHandoff notes in the logs of the execution that it was interrupted to be handed off
+func (h *FlowExecutionHandler) logHandoff(execID string, actionID string, streamLogger streamlogger.Logger) {
+	if err := streamLogger.Checkpoint(actionID, "", []byte("server is stopping, the execution is handed off to another server"), streamlogger.LogMessageType); err != nil {
+		h.logger.Error("failed to log handoff", "execID", execID, "actionID", actionID, "error", err)
+	}
+}
@@ -0,0 +1,92 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"testing"
+
+	"github.com/cvhariharan/flowctl/internal/repo"
+	"github.com/google/uuid"
+)
+
+// executionStore returns the latest execution log of an execution, if it exists
+type executionStore struct {
+	repo.Store
+	exec *repo.GetExecutionByExecIDRow
+}
+
+func (s *executionStore) GetExecutionByExecID(ctx context.Context, arg repo.GetExecutionByExecIDParams) (repo.GetExecutionByExecIDRow, error) {
+	if s.exec == nil {
+		return repo.GetExecutionByExecIDRow{}, sql.ErrNoRows
+	}
+	return *s.exec, nil
+}
+
+func TestAdoptExecution(t *testing.T) {
+	actions := []Action{{ID: "build"}, {ID: "test"}, {ID: "deploy"}}
+
+	tests := []struct {
+		name        string
+		exec        *repo.GetExecutionByExecIDRow
+		startingIdx int
+		wantAdopted bool
+		wantIdx     int
+	}{
+		{"new cron execution", nil, 0, false, 0},
+		{"pending execution", &repo.GetExecutionByExecIDRow{Status: repo.ExecutionStatusPending}, 0, false, 0},
+		{"resumed after approval", &repo.GetExecutionByExecIDRow{Status: repo.ExecutionStatusPendingApproval, CurrentActionID: sql.NullString{String: "test", Valid: true}}, 2, false, 2},
+		{"interrupted before any action", &repo.GetExecutionByExecIDRow{Status: repo.ExecutionStatusRunning}, 0, true, 0},
+		{"interrupted during an action", &repo.GetExecutionByExecIDRow{Status: repo.ExecutionStatusRunning, CurrentActionID: sql.NullString{String: "test", Valid: true}}, 0, true, 1},
+		{"interrupted after resuming", &repo.GetExecutionByExecIDRow{Status: repo.ExecutionStatusRunning, CurrentActionID: sql.NullString{String: "deploy", Valid: true}}, 1, true, 2},
+		{"unknown action", &repo.GetExecutionByExecIDRow{Status: repo.ExecutionStatusRunning, CurrentActionID: sql.NullString{String: "removed", Valid: true}}, 1, true, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &FlowExecutionHandler{
+				store:  &executionStore{exec: tt.exec},
+				logger: slog.New(slog.DiscardHandler),
+			}
+			payload := FlowExecutionPayload{
+				Workflow:          Flow{Actions: actions},
+				StartingActionIdx: tt.startingIdx,
+				NamespaceID:       uuid.NewString(),
+			}
+
+			adopted, err := h.adoptExecution(context.Background(), "exec-1", &payload)
+			if err != nil {
+				t.Fatalf("adoptExecution() error = %v", err)
+			}
+			if adopted != tt.wantAdopted {
+				t.Errorf("adoptExecution() = %v, want %v", adopted, tt.wantAdopted)
+			}
+			if payload.StartingActionIdx != tt.wantIdx {
+				t.Errorf("StartingActionIdx = %d, want %d", payload.StartingActionIdx, tt.wantIdx)
+			}
+			if adopted && !payload.Resumed {
+				t.Error("adopted execution is not resumed, the outputs of earlier actions would not be restored")
+			}
+		})
+	}
+}
+
+func TestHandedOff(t *testing.T) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	child, stop := context.WithCancel(ctx)
+	defer stop()
+
+	if handedOff(child) {
+		t.Fatal("running context is handed off")
+	}
+	cancel(ErrHandoff)
+	if !handedOff(child) {
+		t.Error("context of an action is not handed off with its job")
+	}
+
+	cancelled, cancelJob := context.WithCancelCause(context.Background())
+	cancelJob(nil)
+	if handedOff(cancelled) {
+		t.Error("cancelled execution is handed off")
+	}
+}
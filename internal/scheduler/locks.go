@@ -0,0 +1,82 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cvhariharan/flowctl/internal/repo"
+	"github.com/cvhariharan/flowctl/internal/streamlogger"
+	"github.com/google/uuid"
+)
+
+// lockPollInterval is how often an execution waiting for a lock checks whether it was released
+const lockPollInterval = 2 * time.Second
+
+// acquireLock waits until the execution holds the lock key in its namespace and returns a
+// function that releases it. Waiting is logged to the execution logs under actionID.
+func (h *FlowExecutionHandler) acquireLock(ctx context.Context, key string, execID string, namespaceID string, actionID string, streamLogger streamlogger.Logger) (func(), error) {
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	release := func() {
+		// The lock is kept for the server that adopts an execution that is handed off
+		if handedOff(ctx) {
+			return
+		}
+		err := h.store.ReleaseExecutionLock(context.WithoutCancel(ctx), repo.ReleaseExecutionLockParams{
+			Uuid:    namespaceUUID,
+			LockKey: key,
+			ExecID:  execID,
+		})
+		if err != nil {
+			h.logger.Error("failed to release lock", "execID", execID, "lock", key, "error", err)
+		}
+	}
+
+	t := time.NewTicker(lockPollInterval)
+	defer t.Stop()
+	waiting := false
+	for {
+		_, err := h.store.AcquireExecutionLock(ctx, repo.AcquireExecutionLockParams{
+			Uuid:    namespaceUUID,
+			LockKey: key,
+			ExecID:  execID,
+		})
+		if err == nil {
+			return release, nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) && ctx.Err() == nil {
+			return nil, fmt.Errorf("could not acquire lock %s: %w", key, err)
+		}
+
+		if !waiting && ctx.Err() == nil {
+			waiting = true
+			msg := fmt.Sprintf("waiting for lock %s", key)
+			if l, err := h.store.GetExecutionLock(ctx, repo.GetExecutionLockParams{Uuid: namespaceUUID, LockKey: key}); err == nil {
+				msg = fmt.Sprintf("waiting for lock %s held by execution %s", key, l.ExecID)
+			}
+			streamLogger.Checkpoint(actionID, "", []byte(msg), streamlogger.LogMessageType)
+		}
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.Canceled) {
+				if handedOff(ctx) {
+					h.logHandoff(execID, actionID, streamLogger)
+					return nil, ErrExecutionCancelled
+				}
+				if err := streamLogger.Checkpoint(actionID, "", "execution cancelled", streamlogger.CancelledMessageType); err != nil {
+					h.logger.Error("failed to send cancellation message", "error", err)
+				}
+				return nil, ErrExecutionCancelled
+			}
+			return nil, fmt.Errorf("could not acquire lock %s: %w", key, ctx.Err())
+		case <-t.C:
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/cvhariharan/flowctl/internal/repo"
+	"github.com/cvhariharan/flowctl/internal/streamlogger"
+	"github.com/google/uuid"
+)
+
+// heldLockStore never grants the lock, another execution holds it
+type heldLockStore struct {
+	repo.Store
+}
+
+func (s *heldLockStore) AcquireExecutionLock(ctx context.Context, arg repo.AcquireExecutionLockParams) (repo.ExecutionLock, error) {
+	return repo.ExecutionLock{}, sql.ErrNoRows
+}
+
+func (s *heldLockStore) GetExecutionLock(ctx context.Context, arg repo.GetExecutionLockParams) (repo.ExecutionLock, error) {
+	return repo.ExecutionLock{ExecID: "exec-other"}, nil
+}
+
+// checkpointLogger keeps the types of the messages checkpointed
+type checkpointLogger struct {
+	streamlogger.Logger
+	types []streamlogger.MessageType
+}
+
+func (l *checkpointLogger) Checkpoint(id string, nodeID string, val interface{}, mtype streamlogger.MessageType) error {
+	l.types = append(l.types, mtype)
+	return nil
+}
+
+func TestAcquireLockCancelled(t *testing.T) {
+	tests := []struct {
+		name          string
+		cause         error
+		wantCancelled bool
+	}{
+		{"cancelled", nil, true},
+		{"handed off", ErrHandoff, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &FlowExecutionHandler{
+				store:  &heldLockStore{},
+				logger: slog.New(slog.DiscardHandler),
+			}
+			logger := &checkpointLogger{}
+
+			ctx, cancel := context.WithCancelCause(context.Background())
+			cancel(tt.cause)
+
+			_, err := h.acquireLock(ctx, "deploy", "exec-1", uuid.NewString(), "build", logger)
+			if !errors.Is(err, ErrExecutionCancelled) {
+				t.Fatalf("acquireLock() error = %v, want %v", err, ErrExecutionCancelled)
+			}
+
+			cancelled := false
+			for _, mtype := range logger.types {
+				if mtype == streamlogger.CancelledMessageType {
+					cancelled = true
+				}
+			}
+			if cancelled != tt.wantCancelled {
+				t.Errorf("checkpointed execution cancelled = %v, want %v", cancelled, tt.wantCancelled)
+			}
+		})
+	}
+}
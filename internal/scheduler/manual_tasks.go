@@ -0,0 +1,121 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/cvhariharan/flowctl/internal/repo"
+	"github.com/google/uuid"
+)
+
+// ExecutorManual is the executor of actions that run nothing on nodes. The execution waits until
+// a person completes the task described by the with block of the action.
+const ExecutorManual = "manual"
+
+// ManualTaskResponse is what the person that completed a manual task submitted
+type ManualTaskResponse struct {
+	Inputs      map[string]any `json:"inputs"`
+	Checklist   []string       `json:"checklist"`
+	Comment     string         `json:"comment"`
+	CompletedBy string         `json:"completed_by"`
+}
+
+// checkManualTask returns nil once the task of a manual action is completed. Until then the
+// action is set as the current action of the execution and the execution waits like for an
+// approval. The task is requested when the action is first reached.
+func (h *FlowExecutionHandler) checkManualTask(ctx context.Context, execID string, action Action, namespaceID string) error {
+	if action.Executor != ExecutorManual {
+		return nil
+	}
+
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	task, err := h.store.GetManualTaskForAction(ctx, repo.GetManualTaskForActionParams{
+		ExecID:   execID,
+		ActionID: action.ID,
+		Uuid:     namespaceUUID,
+	})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	if task.Status == TaskStatusCompleted {
+		return nil
+	}
+
+	// Set the current action ID so that the execution is resumed or retried from this action
+	if _, err := h.store.UpdateExecutionActionID(ctx, repo.UpdateExecutionActionIDParams{
+		CurrentActionID: sql.NullString{String: action.ID, Valid: true},
+		ExecID:          execID,
+		Uuid:            namespaceUUID,
+	}); err != nil {
+		return fmt.Errorf("could not update current action ID in exec %s: %w", execID, err)
+	}
+
+	if task.Status != TaskStatusPending {
+		definition, err := json.Marshal(action.With)
+		if err != nil {
+			return fmt.Errorf("could not marshal manual task of action %s: %w", action.ID, err)
+		}
+		if _, err := h.store.RequestManualTask(ctx, repo.RequestManualTaskParams{
+			ExecID:     execID,
+			ActionID:   action.ID,
+			Uuid:       namespaceUUID,
+			Definition: definition,
+		}); err != nil {
+			return fmt.Errorf("could not request manual task for action %s: %w", action.ID, err)
+		}
+	}
+
+	return ErrPendingApproval
+}
+
+// manualTaskResults returns the response to the completed task of a manual action as the
+// results of the action: the submitted inputs, the comment and the name of the person that
+// completed it.
+func (h *FlowExecutionHandler) manualTaskResults(ctx context.Context, execID string, action Action, namespaceID string) (map[string]string, error) {
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	task, err := h.store.GetManualTaskForAction(ctx, repo.GetManualTaskForActionParams{
+		ExecID:   execID,
+		ActionID: action.ID,
+		Uuid:     namespaceUUID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not get manual task of action %s: %w", action.ID, err)
+	}
+
+	var resp ManualTaskResponse
+	if task.Response.Valid {
+		if err := json.Unmarshal(task.Response.RawMessage, &resp); err != nil {
+			return nil, fmt.Errorf("could not decode response to manual task of action %s: %w", action.ID, err)
+		}
+	}
+
+	results := make(map[string]string, len(resp.Inputs)+2)
+	for k, v := range resp.Inputs {
+		switch v := v.(type) {
+		case string:
+			results[k] = v
+		default:
+			b, err := json.Marshal(v)
+			if err != nil {
+				return nil, fmt.Errorf("could not encode input %s of manual task: %w", k, err)
+			}
+			results[k] = string(b)
+		}
+	}
+	results["completed_by"] = resp.CompletedBy
+	if resp.Comment != "" {
+		results["comment"] = resp.Comment
+	}
+	return results, nil
+}
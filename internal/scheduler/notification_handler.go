@@ -3,8 +3,10 @@ package scheduler
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 
 	"github.com/cvhariharan/flowctl/internal/messengers"
 	"github.com/cvhariharan/flowctl/internal/repo"
@@ -13,7 +15,26 @@ import (
 
 const PayloadTypeNotification PayloadType = "notification"
 
+// StatusSkipped is the notification status used for scheduled runs that were not started
+const StatusSkipped = "skipped"
+
+// StatusSLABreached is the notification status used for executions that breached the SLA of their flow
+const StatusSLABreached = "sla_breached"
+
+// NotificationMaxRetries is the number of times a failed notification is retried with backoff
+const NotificationMaxRetries = 5
+
+// Delivery statuses recorded for each notification receiver
+const (
+	DeliveryStatusDelivered = "delivered"
+	DeliveryStatusRetrying  = "retrying"
+	DeliveryStatusFailed    = "failed"
+)
+
 type NotificationPayload struct {
+	// NotificationID identifies a notification across retries
+	NotificationID string `json:"notification_id"`
+
 	FlowID      string         `json:"flow_id"`
 	FlowName    string         `json:"flow_name"`
 	ExecID      string         `json:"exec_id"`
@@ -22,16 +43,91 @@ type NotificationPayload struct {
 	Config      map[string]any `json:"config"`
 	NamespaceID string         `json:"namespace_id"`
 	Channel     string         `json:"channel"`
+
+	Approval *messengers.ApprovalDecision `json:"approval,omitempty"`
+	Reason   string                       `json:"reason,omitempty"`
+}
+
+// WatchNotifyEvents are the events users watching an execution or its flow are notified of
+var WatchNotifyEvents = []NotifyEvent{NotifyEventOnSuccess, NotifyEventOnFailure}
+
+// WatchNotify returns the notification sent to the users watching an execution, by email
+func WatchNotify(usernames []string) Notify {
+	return Notify{
+		Channel: "email",
+		Config:  map[string]any{"receivers": usernames},
+		Events:  WatchNotifyEvents,
+	}
+}
+
+// ApproverNotify returns the notification sent to the approvers of an action when it is waiting
+// for their approval, by email. Approvers are usernames and group:name references, like receivers.
+func ApproverNotify(approvers []string) Notify {
+	return Notify{
+		Channel: "email",
+		Config:  map[string]any{"receivers": approvers},
+		Events:  []NotifyEvent{NotifyEventOnWaiting},
+	}
+}
+
+// notifyEventForStatus maps an execution status to the notify event it triggers
+func notifyEventForStatus(status string) (NotifyEvent, bool) {
+	switch status {
+	case string(repo.ExecutionStatusCompleted):
+		return NotifyEventOnSuccess, true
+	case string(repo.ExecutionStatusErrored):
+		return NotifyEventOnFailure, true
+	case string(repo.ExecutionStatusCancelled):
+		return NotifyEventOnCancelled, true
+	case string(repo.ExecutionStatusPendingApproval):
+		return NotifyEventOnWaiting, true
+	case StatusSkipped:
+		return NotifyEventOnSkipped, true
+	case StatusSLABreached:
+		return NotifyEventOnSLABreach, true
+	}
+	return "", false
+}
+
+// QueueNotifications queues a notification job for every notify configuration in the flow
+// that subscribes to the event matching the payload status. Channel and Config are filled
+// in from each notify configuration.
+func QueueNotifications(ctx context.Context, tq TaskQueuer, flow Flow, payload NotificationPayload) error {
+	event, ok := notifyEventForStatus(payload.Status)
+	if !ok {
+		return nil
+	}
+
+	var errs []error
+	for _, notify := range flow.Notify {
+		if !slices.Contains(notify.Events, event) {
+			continue
+		}
+
+		p := payload
+		p.NotificationID = uuid.NewString()
+		p.Config = notify.Config
+		p.Channel = notify.Channel
+
+		// Generate a unique exec ID for the notification job
+		notifyExecID := fmt.Sprintf("notify-%s-%s", payload.ExecID, notify.Channel)
+
+		if _, err := tq.QueueTaskWithRetries(ctx, PayloadTypeNotification, notifyExecID, p, NotificationMaxRetries); err != nil {
+			errs = append(errs, fmt.Errorf("failed to queue notification for channel %s: %w", notify.Channel, err))
+		}
+	}
+
+	return errors.Join(errs...)
 }
 
 // NotificationHandler processes notification jobs
 type NotificationHandler struct {
-	messengers map[string]messengers.Messenger
+	messengers *messengers.Registry
 	store      repo.Store
 	logger     *slog.Logger
 }
 
-func NewNotificationHandler(m map[string]messengers.Messenger, store repo.Store, logger *slog.Logger) *NotificationHandler {
+func NewNotificationHandler(m *messengers.Registry, store repo.Store, logger *slog.Logger) *NotificationHandler {
 	return &NotificationHandler{
 		messengers: m,
 		store:      store,
@@ -51,24 +147,38 @@ func (h *NotificationHandler) Handle(ctx context.Context, job Job) error {
 
 	h.logger.Debug("processing notification", "flow_id", payload.FlowID, "exec_id", payload.ExecID, "status", payload.Status, "channel", payload.Channel)
 
+	namespaceUUID, err := uuid.Parse(payload.NamespaceID)
+	if err != nil {
+		return err
+	}
+
+	notificationID, err := uuid.Parse(payload.NotificationID)
+	track := err == nil
+
 	// Route to messenger by channel name
-	messenger, ok := h.messengers[payload.Channel]
+	messenger, ok := h.messengers.Get(payload.Channel)
 	if !ok {
 		h.logger.Warn("no messenger configured for channel", "channel", payload.Channel)
+		if track {
+			h.recordDelivery(ctx, notificationID, namespaceUUID, payload, payload.Channel, DeliveryStatusFailed, job.Attempt+1, "messenger is not enabled")
+		}
 		return nil
 	}
-
-	namespaceUUID, err := uuid.Parse(payload.NamespaceID)
-	if err != nil {
-		return err
-	}
 	namespace, err := h.store.GetNamespaceByUUID(ctx, namespaceUUID)
 	if err != nil {
 		return fmt.Errorf("could not get namespace name for %s: %w", payload.NamespaceID, err)
 	}
 
+	event := messengers.EventFlowExecution
+	switch payload.Status {
+	case StatusSkipped:
+		event = messengers.EventFlowSkipped
+	case StatusSLABreached:
+		event = messengers.EventFlowSLABreach
+	}
+
 	msg := messengers.Message{
-		Event: messengers.EventFlowExecution,
+		Event: event,
 		Data: messengers.FlowExecutionEvent{
 			FlowID:    payload.FlowID,
 			FlowName:  payload.FlowName,
@@ -76,11 +186,49 @@ func (h *NotificationHandler) Handle(ctx context.Context, job Job) error {
 			Status:    payload.Status,
 			Error:     payload.Error,
 			Namespace: namespace.Name,
+			Approval:  payload.Approval,
+			Reason:    payload.Reason,
 		},
 		Config: payload.Config,
 	}
 
-	if err := messenger.Send(ctx, msg); err != nil {
+	if track && job.Attempt > 0 {
+		delivered, err := h.store.GetDeliveredReceivers(ctx, notificationID)
+		if err != nil {
+			return fmt.Errorf("could not get delivered receivers for notification %s: %w", payload.NotificationID, err)
+		}
+		msg.SkipReceivers = delivered
+	}
+
+	var deliveries []messengers.Delivery
+	if reporter, ok := messenger.(messengers.DeliveryReporter); ok {
+		deliveries, err = reporter.SendWithReport(ctx, msg)
+		if err != nil {
+			deliveries = []messengers.Delivery{{Receiver: payload.Channel, Err: err}}
+		}
+	} else {
+		deliveries = []messengers.Delivery{{Receiver: notificationReceiver(payload), Err: messenger.Send(ctx, msg)}}
+	}
+
+	var errs []error
+	for _, d := range deliveries {
+		status := DeliveryStatusDelivered
+		var lastError string
+		if d.Err != nil {
+			errs = append(errs, d.Err)
+			lastError = d.Err.Error()
+			status = DeliveryStatusFailed
+			if job.ShouldRetry() {
+				status = DeliveryStatusRetrying
+			}
+		}
+
+		if track {
+			h.recordDelivery(ctx, notificationID, namespaceUUID, payload, d.Receiver, status, job.Attempt+1, lastError)
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
 		return fmt.Errorf("failed to send notification via %s: %w", payload.Channel, err)
 	}
 
@@ -88,3 +236,32 @@ func (h *NotificationHandler) Handle(ctx context.Context, job Job) error {
 
 	return nil
 }
+
+// recordDelivery stores the delivery status of a notification for a receiver. Failures are only logged
+// so that they do not cause the notification to be sent again.
+func (h *NotificationHandler) recordDelivery(ctx context.Context, notificationID, namespaceUUID uuid.UUID, payload NotificationPayload, receiver, status string, attempts int, lastError string) {
+	_, err := h.store.UpsertNotificationDelivery(ctx, repo.UpsertNotificationDeliveryParams{
+		NotificationID: notificationID,
+		NamespaceUuid:  namespaceUUID,
+		ExecID:         payload.ExecID,
+		FlowID:         payload.FlowID,
+		Channel:        payload.Channel,
+		Receiver:       receiver,
+		Event:          payload.Status,
+		Status:         status,
+		Attempts:       int32(attempts),
+		LastError:      lastError,
+	})
+	if err != nil {
+		h.logger.Error("failed to record notification delivery", "notification_id", notificationID, "receiver", receiver, "error", err)
+	}
+}
+
+// notificationReceiver describes where a notification is sent for messengers that do not
+// report deliveries per receiver.
+func notificationReceiver(payload NotificationPayload) string {
+	if u, ok := payload.Config["url"].(string); ok && u != "" {
+		return u
+	}
+	return payload.Channel
+}
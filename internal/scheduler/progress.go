@@ -0,0 +1,241 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/cvhariharan/flowctl/internal/repo"
+	"github.com/cvhariharan/flowctl/internal/streamlogger"
+	"github.com/google/uuid"
+)
+
+const (
+	ProgressStatusSuccess   = "success"
+	ProgressStatusFailed    = "failed"
+	ProgressStatusCancelled = "cancelled"
+)
+
+// progressTracker emits progress events of an execution to its log stream and records the
+// latest one so that it can be shown in the execution summary, along with the timeline of its actions.
+// Events are streamed right away but recording them and the timeline entries of nodes is deferred
+// by flushInterval so that actions running on many nodes write the latest progress and the node
+// entries in a few statements instead of a few per node. Everything is recorded when an action
+// finishes, so at most the progress and node entries of the running action are lost if the
+// process crashes.
+type progressTracker struct {
+	store         repo.Store
+	streamLogger  streamlogger.Logger
+	logger        *slog.Logger
+	execID        string
+	namespaceID   string
+	actionIDs     []string
+	flushInterval time.Duration
+
+	// mu serializes events so that node completions are counted and recorded in order
+	mu      sync.Mutex
+	current streamlogger.ProgressEvent
+	// retry is the attempt of the current action, used to key its timeline entries
+	retry int32
+	// nodes are the timeline entries of the nodes running the current action and pendingNodes
+	// the ones that changed since they were last recorded
+	nodes        map[string]*timelineEntry
+	pendingNodes map[string]struct{}
+	// pending is the latest progress that hasn't been recorded yet and flushTimer records it
+	// along with pendingNodes
+	pending    []byte
+	flushTimer *time.Timer
+}
+
+func newProgressTracker(h *FlowExecutionHandler, streamLogger streamlogger.Logger, execID string, namespaceID string, actions []Action) *progressTracker {
+	actionIDs := make([]string, len(actions))
+	for i, action := range actions {
+		actionIDs[i] = action.ID
+	}
+
+	return &progressTracker{
+		store:         h.store,
+		streamLogger:  streamLogger,
+		logger:        h.logger,
+		execID:        execID,
+		namespaceID:   namespaceID,
+		actionIDs:     actionIDs,
+		flushInterval: h.progressFlush,
+		nodes:         make(map[string]*timelineEntry),
+		pendingNodes:  make(map[string]struct{}),
+	}
+}
+
+// actionStarted is called before an action runs on its nodes
+func (p *progressTracker) actionStarted(ctx context.Context, action Action, retry int32) {
+	totalNodes := len(action.On)
+	if totalNodes == 0 {
+		totalNodes = 1
+	}
+
+	index := 0
+	for i, id := range p.actionIDs {
+		if id == action.ID {
+			index = i
+			break
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.retry = retry
+	clear(p.nodes)
+	clear(p.pendingNodes)
+	p.current = streamlogger.ProgressEvent{
+		Event:        streamlogger.ActionStartedEvent,
+		ActionIndex:  index + 1,
+		TotalActions: len(p.actionIDs),
+		TotalNodes:   totalNodes,
+	}
+	p.emit(ctx, action.ID, "", p.current)
+	p.startTimelineEntry(ctx, action.ID, retry)
+}
+
+// nodeStarted is called when a node starts running the current action. Actions that run
+// locally are only recorded as a whole.
+func (p *progressTracker) nodeStarted(ctx context.Context, actionID string, node string) {
+	if node == "" {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.nodes[node] = &timelineEntry{
+		actionID:  actionID,
+		retry:     p.retry,
+		status:    "running",
+		startedAt: time.Now(),
+	}
+	p.pendingNodes[node] = struct{}{}
+	p.scheduleFlushLocked(ctx)
+}
+
+// nodeFinished is called when a node finished running the current action
+func (p *progressTracker) nodeFinished(ctx context.Context, actionID string, node string, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if node != "" {
+		p.finishNodeEntry(actionID, node, err)
+	}
+
+	p.current.CompletedNodes++
+	event := p.current
+	event.Event = streamlogger.NodeFinishedEvent
+	event.Node = node
+	event.Status = progressStatus(err)
+	p.emit(ctx, actionID, node, event)
+}
+
+// actionFinished is called once the current action finished on all nodes or failed. The buffered
+// progress and node entries are recorded before the action's own entry is finished.
+func (p *progressTracker) actionFinished(ctx context.Context, actionID string, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.current.Event = streamlogger.ActionFinishedEvent
+	p.current.Status = progressStatus(err)
+	p.emit(ctx, actionID, "", p.current)
+	p.finishTimelineEntry(ctx, actionID, p.retry, err)
+}
+
+// emit writes the event to the log stream and buffers it as the current progress, which is
+// recorded once the action finishes or flushInterval has passed. Callers must hold p.mu.
+// Failures are only logged since progress is informational.
+func (p *progressTracker) emit(ctx context.Context, actionID string, nodeID string, event streamlogger.ProgressEvent) {
+	if err := p.streamLogger.Checkpoint(actionID, nodeID, event, streamlogger.ProgressMessageType); err != nil {
+		p.logger.Error("failed to send progress message", "execID", p.execID, "actionID", actionID, "error", err)
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		p.logger.Error("failed to marshal progress", "execID", p.execID, "error", err)
+		return
+	}
+	p.pending = data
+
+	if event.Event == streamlogger.ActionFinishedEvent {
+		p.flushLocked(ctx)
+		return
+	}
+	p.scheduleFlushLocked(ctx)
+}
+
+// scheduleFlushLocked records the buffered progress and node entries after flushInterval, or
+// right away if buffering is disabled. Callers must hold p.mu.
+func (p *progressTracker) scheduleFlushLocked(ctx context.Context) {
+	if p.flushInterval <= 0 {
+		p.flushLocked(ctx)
+		return
+	}
+
+	if p.flushTimer == nil {
+		p.flushTimer = time.AfterFunc(p.flushInterval, func() {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			p.flushLocked(context.Background())
+		})
+	}
+}
+
+// flush records the buffered progress and node entries, if any
+func (p *progressTracker) flush() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.flushLocked(context.Background())
+}
+
+func (p *progressTracker) flushLocked(ctx context.Context) {
+	if p.flushTimer != nil {
+		p.flushTimer.Stop()
+		p.flushTimer = nil
+	}
+	if p.pending == nil && len(p.pendingNodes) == 0 {
+		return
+	}
+
+	namespaceUUID, err := uuid.Parse(p.namespaceID)
+	if err != nil {
+		p.logger.Error("invalid namespace UUID", "execID", p.execID, "error", err)
+		return
+	}
+
+	// Record the node entries first so that the progress never counts nodes missing from the timeline
+	p.recordNodeEntries(ctx, namespaceUUID)
+
+	if p.pending == nil {
+		return
+	}
+	data := p.pending
+	p.pending = nil
+
+	// Record the progress even if the execution was cancelled
+	if err := p.store.UpsertExecutionProgress(context.WithoutCancel(ctx), repo.UpsertExecutionProgressParams{
+		ExecID:        p.execID,
+		NamespaceUuid: namespaceUUID,
+		Progress:      data,
+	}); err != nil {
+		p.logger.Error("failed to record progress", "execID", p.execID, "error", err)
+	}
+}
+
+func progressStatus(err error) string {
+	switch {
+	case err == nil:
+		return ProgressStatusSuccess
+	case errors.Is(err, context.Canceled), errors.Is(err, ErrExecutionCancelled):
+		return ProgressStatusCancelled
+	default:
+		return ProgressStatusFailed
+	}
+}
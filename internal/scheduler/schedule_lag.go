@@ -0,0 +1,53 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/cvhariharan/flowctl/internal/repo"
+)
+
+// recordScheduleLag records how late a scheduled execution started
+func (h *FlowExecutionHandler) recordScheduleLag(ctx context.Context, job Job, payload FlowExecutionPayload) {
+	startedAt := time.Now()
+	lag, dueAt, trigger := scheduleLag(job, payload, startedAt)
+	if trigger == "" {
+		return
+	}
+	if h.metrics != nil {
+		h.metrics.ObserveScheduleLag(payload.NamespaceID, payload.Workflow.Meta.ID, trigger, lag)
+	}
+
+	if payload.ScheduleID == 0 {
+		return
+	}
+
+	if err := h.store.RecordCronScheduleRun(context.WithoutCancel(ctx), repo.RecordCronScheduleRunParams{
+		ScheduleID: payload.ScheduleID,
+		ExecID:     sql.NullString{String: job.ExecID, Valid: true},
+		FiredAt:    dueAt,
+		StartedAt:  startedAt,
+		DriftMs:    lag.Milliseconds(),
+	}); err != nil {
+		h.logger.Warn("failed to record schedule run", "execID", job.ExecID, "scheduleID", payload.ScheduleID, "error", err)
+	}
+}
+
+// scheduleLag measures how late a scheduled execution started compared to the time it was due,
+// either the cron fire time or the scheduled_at of a one-off run, and returns what triggered it.
+// The trigger is empty for executions that are not measured: manual runs, and resumed and
+// retried runs since their delay is expected.
+func scheduleLag(job Job, payload FlowExecutionPayload, startedAt time.Time) (time.Duration, time.Time, string) {
+	if job.Attempt > 0 || payload.Resumed {
+		return 0, time.Time{}, ""
+	}
+
+	switch {
+	case !payload.FiredAt.IsZero():
+		return max(startedAt.Sub(payload.FiredAt), 0), payload.FiredAt, "cron"
+	case !job.ScheduledAt.IsZero():
+		return max(startedAt.Sub(job.ScheduledAt), 0), job.ScheduledAt, "scheduled"
+	}
+	return 0, time.Time{}, ""
+}
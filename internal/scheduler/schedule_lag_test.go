@@ -0,0 +1,38 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleLag(t *testing.T) {
+	due := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	startedAt := due.Add(1500 * time.Millisecond)
+
+	tests := []struct {
+		name        string
+		job         Job
+		payload     FlowExecutionPayload
+		startedAt   time.Time
+		wantLag     time.Duration
+		wantDueAt   time.Time
+		wantTrigger string
+	}{
+		{"cron", Job{}, FlowExecutionPayload{FiredAt: due}, startedAt, 1500 * time.Millisecond, due, "cron"},
+		{"scheduled", Job{ScheduledAt: due}, FlowExecutionPayload{}, startedAt, 1500 * time.Millisecond, due, "scheduled"},
+		{"cron fire time wins over scheduled_at", Job{ScheduledAt: due.Add(-time.Minute)}, FlowExecutionPayload{FiredAt: due}, startedAt, 1500 * time.Millisecond, due, "cron"},
+		{"started early", Job{ScheduledAt: due}, FlowExecutionPayload{}, due.Add(-time.Second), 0, due, "scheduled"},
+		{"manual", Job{}, FlowExecutionPayload{}, startedAt, 0, time.Time{}, ""},
+		{"retried", Job{Attempt: 1, ScheduledAt: due}, FlowExecutionPayload{}, startedAt, 0, time.Time{}, ""},
+		{"resumed", Job{}, FlowExecutionPayload{FiredAt: due, Resumed: true}, startedAt, 0, time.Time{}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lag, dueAt, trigger := scheduleLag(tt.job, tt.payload, tt.startedAt)
+			if lag != tt.wantLag || !dueAt.Equal(tt.wantDueAt) || trigger != tt.wantTrigger {
+				t.Errorf("scheduleLag() = %v, %v, %q, want %v, %v, %q", lag, dueAt, trigger, tt.wantLag, tt.wantDueAt, tt.wantTrigger)
+			}
+		})
+	}
+}
@@ -7,8 +7,10 @@ import (
 	"log/slog"
 	"runtime"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/cvhariharan/flowctl/internal/metrics"
 	"github.com/cvhariharan/flowctl/internal/scheduler/storage"
 )
 
@@ -17,12 +19,17 @@ const (
 	PeriodicTicker = 1 * time.Minute
 )
 
+// maxHeldJobs is the number of jobs that can't run yet that are skipped in one pass over a queue,
+// so that a long backlog of them doesn't keep the scheduler from the other queues
+const maxHeldJobs = 100
+
 type TaskScheduler interface {
 	QueueTask(ctx context.Context, payloadType PayloadType, execID string, payload any) (string, error)
 	QueueTaskWithRetries(ctx context.Context, payloadType PayloadType, execID string, payload any, maxRetries int) (string, error)
 	QueueScheduledTask(ctx context.Context, payloadType PayloadType, execID string, payload any, scheduledAt time.Time) (string, error)
 	QueueScheduledTaskWithRetries(ctx context.Context, payloadType PayloadType, execID string, payload any, scheduledAt time.Time, maxRetries int) (string, error)
 	CancelTask(ctx context.Context, execID string) error
+	SyncScheduledJobs(ctx context.Context) error
 	Start(ctx context.Context) error
 	Stop(ctx context.Context) error
 }
@@ -32,13 +39,18 @@ type Scheduler struct {
 	jobStore         storage.Storage
 	handlers         *handlerRegistry
 	queueConfig      QueueConfig
-	workerCount      int
+	workerCount      atomic.Int64
 	cronSyncInterval time.Duration
 	jobSyncer        JobSyncerFn
+	skipChecker      SkipCheckerFn
+	slaMonitor       SLAMonitorFn
+	limiter          ConcurrencyLimiterFn
 	retryOptions     RetryOptions
+	metrics          *metrics.Manager
 
-	cancelFuncs   map[string]context.CancelFunc
+	cancelFuncs   map[string]context.CancelCauseFunc
 	cancelMu      sync.RWMutex
+	running       sync.WaitGroup
 	scheduledJobs map[string]ScheduledJob
 	scheduledMu   sync.RWMutex
 
@@ -59,6 +71,7 @@ type SchedulerBuilder struct {
 	cronSyncInterval time.Duration
 	jobSyncer        JobSyncerFn
 	retryOptions     *RetryOptions
+	metrics          *metrics.Manager
 	logger           *slog.Logger
 }
 
@@ -105,6 +118,12 @@ func (b *SchedulerBuilder) WithRetryOptions(opts RetryOptions) *SchedulerBuilder
 	return b
 }
 
+// WithMetrics reports the depth of the queue to the metrics manager
+func (b *SchedulerBuilder) WithMetrics(m *metrics.Manager) *SchedulerBuilder {
+	b.metrics = m
+	return b
+}
+
 // Build creates the scheduler instance
 func (b *SchedulerBuilder) Build() (*Scheduler, error) {
 	if b.jobStore == nil {
@@ -126,19 +145,22 @@ func (b *SchedulerBuilder) Build() (*Scheduler, error) {
 		retryOpts = *b.retryOptions
 	}
 
-	return &Scheduler{
+	s := &Scheduler{
 		jobStore:         b.jobStore,
 		handlers:         newHandlerRegistry(),
 		queueConfig:      b.queueConfig,
-		workerCount:      workerCount,
 		cronSyncInterval: cronInterval,
 		jobSyncer:        b.jobSyncer,
 		retryOptions:     retryOpts,
-		cancelFuncs:      make(map[string]context.CancelFunc),
+		metrics:          b.metrics,
+		cancelFuncs:      make(map[string]context.CancelCauseFunc),
 		scheduledJobs:    make(map[string]ScheduledJob),
 		stopCh:           make(chan struct{}),
 		logger:           b.logger,
-	}, nil
+	}
+	s.workerCount.Store(int64(workerCount))
+
+	return s, nil
 }
 
 // SetJobSyncer sets the job syncer for cron-based scheduling
@@ -146,11 +168,37 @@ func (s *Scheduler) SetJobSyncer(syncer JobSyncerFn) {
 	s.jobSyncer = syncer
 }
 
+// SetSkipChecker sets the function used to decide whether a due scheduled job should be skipped
+func (s *Scheduler) SetSkipChecker(checker SkipCheckerFn) {
+	s.skipChecker = checker
+}
+
+// SetSLAMonitor sets the function used to check executions against the SLA of their flows
+func (s *Scheduler) SetSLAMonitor(monitor SLAMonitorFn) {
+	s.slaMonitor = monitor
+}
+
+// SetConcurrencyLimiter sets the function that decides whether a picked job can run
+func (s *Scheduler) SetConcurrencyLimiter(limiter ConcurrencyLimiterFn) {
+	s.limiter = limiter
+}
+
 // SetHandler registers a handler for a payload type
 func (s *Scheduler) SetHandler(h Handler) error {
 	return s.handlers.Register(h)
 }
 
+// SetWorkerCount changes the number of jobs picked up at a time. Jobs that are already running
+// are not affected, the new count applies from the next poll of the queue.
+func (s *Scheduler) SetWorkerCount(c int) {
+	if c <= 0 {
+		c = runtime.NumCPU()
+	}
+	if old := s.workerCount.Swap(int64(c)); old != int64(c) {
+		s.logger.Info("scheduler worker count changed", "from", old, "to", c)
+	}
+}
+
 // SetQueueConfig sets the queue configuration
 func (s *Scheduler) SetQueueConfig(cfg QueueConfig) error {
 	if err := cfg.Validate(); err != nil {
@@ -166,7 +214,7 @@ func (s *Scheduler) Start(ctx context.Context) error {
 		return nil
 	}
 
-	s.logger.Debug("starting scheduler task processing", "workers", s.workerCount, "cronsyncinterval", s.cronSyncInterval)
+	s.logger.Debug("starting scheduler task processing", "workers", s.workerCount.Load(), "cronsyncinterval", s.cronSyncInterval)
 
 	if err := s.jobStore.Initialize(ctx); err != nil {
 		return err
@@ -189,7 +237,9 @@ func (s *Scheduler) Start(ctx context.Context) error {
 	return nil
 }
 
-// Stop shuts down the scheduler
+// Stop shuts down the scheduler. Running jobs are cancelled with ErrHandoff and put back in the
+// queue, so that their executions are adopted by the server that picks them up next. Stop waits
+// for the jobs to be handed off until ctx is done.
 func (s *Scheduler) Stop(ctx context.Context) error {
 	if s.stopped {
 		return nil
@@ -208,11 +258,24 @@ func (s *Scheduler) Stop(ctx context.Context) error {
 		s.cronSyncTicker.Stop()
 	}
 
+	s.cancelMu.RLock()
 	for _, cancel := range s.cancelFuncs {
-		cancel()
+		cancel(ErrHandoff)
 	}
+	s.cancelMu.RUnlock()
 
-	return nil
+	handedOff := make(chan struct{})
+	go func() {
+		s.running.Wait()
+		close(handedOff)
+	}()
+
+	select {
+	case <-handedOff:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("running jobs were not handed off: %w", ctx.Err())
+	}
 }
 
 // QueueTask queues a task for execution with specified payload type
@@ -290,7 +353,7 @@ func (s *Scheduler) QueueScheduledTaskWithRetries(ctx context.Context, payloadTy
 func (s *Scheduler) CancelTask(ctx context.Context, execID string) error {
 	s.cancelMu.Lock()
 	if cancel, exists := s.cancelFuncs[execID]; exists {
-		cancel()
+		cancel(nil)
 		delete(s.cancelFuncs, execID)
 	}
 	s.cancelMu.Unlock()
@@ -312,10 +375,16 @@ func (s *Scheduler) processLoop(ctx context.Context) {
 			if err := s.processPendingTasks(ctx); err != nil {
 				s.logger.Error("error processing pending tasks", "error", err)
 			}
+			s.updateQueueDepth(ctx)
 		case <-s.periodicTicker.C:
 			if err := s.checkPeriodicTasks(ctx); err != nil {
 				s.logger.Error("error checking periodic tasks", "error", err)
 			}
+			if s.slaMonitor != nil {
+				if err := s.slaMonitor(ctx); err != nil {
+					s.logger.Error("error checking execution SLAs", "error", err)
+				}
+			}
 		case <-s.cronSyncTicker.C:
 			if err := s.syncScheduledJobs(ctx); err != nil {
 				s.logger.Error("error syncing scheduled jobs", "error", err)
@@ -328,6 +397,23 @@ func (s *Scheduler) processLoop(ctx context.Context) {
 	}
 }
 
+// updateQueueDepth reports the number of jobs that are waiting for a worker
+func (s *Scheduler) updateQueueDepth(ctx context.Context) {
+	if s.metrics == nil {
+		return
+	}
+
+	counts, err := s.jobStore.CountReady(ctx)
+	if err != nil {
+		s.logger.Error("error counting queued jobs", "error", err)
+		return
+	}
+
+	for _, qw := range s.queueConfig.Queues {
+		s.metrics.SetQueueDepth(string(qw.PayloadType), float64(counts[string(qw.PayloadType)]))
+	}
+}
+
 // processPendingTasks gets pending tasks and executes them with weighted distribution
 func (s *Scheduler) processPendingTasks(ctx context.Context) error {
 	for _, qw := range s.queueConfig.Queues {
@@ -336,11 +422,15 @@ func (s *Scheduler) processPendingTasks(ctx context.Context) error {
 			continue
 		}
 
-		goroutineCount := s.queueConfig.GetWorkerCount(qw.PayloadType, s.workerCount)
+		goroutineCount := s.queueConfig.GetWorkerCount(qw.PayloadType, int(s.workerCount.Load()))
+		if s.metrics != nil {
+			s.metrics.SetWorkers(string(qw.PayloadType), float64(goroutineCount))
+		}
 
-		for i := 0; i < goroutineCount; i++ {
-			done := make(chan struct{})
-			job, err := s.jobStore.GetByPayloadType(ctx, string(qw.PayloadType), done)
+		var held []int64
+		for started := 0; started < goroutineCount; {
+			done := make(chan storage.Outcome, 1)
+			job, err := s.jobStore.GetByPayloadType(ctx, string(qw.PayloadType), held, done)
 			if err != nil {
 				if errors.Is(err, storage.ErrNoJobs) {
 					break
@@ -348,11 +438,34 @@ func (s *Scheduler) processPendingTasks(ctx context.Context) error {
 				return err
 			}
 
-			go func(done chan struct{}, j storage.Job, h Handler) {
+			// Jobs that can't run yet are put back in their place and skipped for the rest of
+			// this pass, so that the jobs queued after them can run
+			release, ok := s.admit(ctx, job)
+			if !ok {
+				done <- storage.Released
+				close(done)
+				held = append(held, job.ID)
+				if len(held) >= maxHeldJobs {
+					break
+				}
+				continue
+			}
+			started++
+
+			s.running.Add(1)
+			if s.metrics != nil {
+				s.metrics.IncWorkersBusy(job.PayloadType)
+			}
+			go func(done chan storage.Outcome, j storage.Job, h Handler) {
+				defer s.running.Done()
 				defer close(done)
+				defer release()
+				if s.metrics != nil {
+					defer s.metrics.DecWorkersBusy(j.PayloadType)
+				}
 
 				// Create cancellable context for this job
-				execCtx, cancel := context.WithCancel(ctx)
+				execCtx, cancel := context.WithCancelCause(ctx)
 
 				// Track cancellation function
 				s.cancelMu.Lock()
@@ -377,7 +490,14 @@ func (s *Scheduler) processPendingTasks(ctx context.Context) error {
 				}
 
 				s.logger.Debug("starting job execution", "execID", j.ExecID, "type", j.PayloadType, "jobID", j.ID, "attempt", j.Attempt, "maxRetries", j.MaxRetries)
-				if err := h.Handle(execCtx, handlerJob); err != nil {
+				err := h.Handle(execCtx, handlerJob)
+				if errors.Is(err, ErrHandoff) {
+					// The job is picked up again, by this server after a restart or by another one
+					done <- storage.Released
+					s.logger.Info("handed off job", "execID", j.ExecID, "type", j.PayloadType, "jobID", j.ID)
+					return
+				}
+				if err != nil {
 					s.logger.Error("handler error", "type", j.PayloadType, "execID", j.ExecID, "error", err)
 
 					// Check if we should retry
@@ -410,3 +530,34 @@ func (s *Scheduler) processPendingTasks(ctx context.Context) error {
 
 	return nil
 }
+
+// admit asks the concurrency limiter whether a job can run now. The returned function releases
+// what the limiter reserved for the job.
+func (s *Scheduler) admit(ctx context.Context, j storage.Job) (func(), bool) {
+	if s.limiter == nil {
+		return func() {}, true
+	}
+
+	release, ok, err := s.limiter(ctx, Job{
+		ID:          j.ID,
+		ExecID:      j.ExecID,
+		PayloadType: PayloadType(j.PayloadType),
+		Payload:     j.Payload,
+		CreatedAt:   j.CreatedAt,
+		ScheduledAt: j.ScheduledAt,
+		MaxRetries:  j.MaxRetries,
+		Attempt:     j.Attempt,
+	})
+	if err != nil {
+		s.logger.Error("error checking concurrency limits", "execID", j.ExecID, "type", j.PayloadType, "error", err)
+		return nil, false
+	}
+	if !ok {
+		s.logger.Debug("job held by concurrency limits", "execID", j.ExecID, "type", j.PayloadType, "jobID", j.ID)
+		return nil, false
+	}
+	if release == nil {
+		release = func() {}
+	}
+	return release, true
+}
@@ -0,0 +1,127 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cvhariharan/flowctl/internal/scheduler/storage"
+)
+
+// queueStorage is a job queue that records what happened to the jobs picked from it
+type queueStorage struct {
+	storage.Storage
+	mu       sync.Mutex
+	jobs     []storage.Job
+	locked   map[int64]bool
+	released []int64
+}
+
+func (q *queueStorage) GetByPayloadType(ctx context.Context, payloadType string, exclude []int64, done chan storage.Outcome) (storage.Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for _, j := range q.jobs {
+		if j.PayloadType != payloadType || q.locked[j.ID] || slices.Contains(exclude, j.ID) {
+			continue
+		}
+		q.locked[j.ID] = true
+		go func() {
+			outcome := <-done
+			q.mu.Lock()
+			defer q.mu.Unlock()
+			delete(q.locked, j.ID)
+			if outcome == storage.Released {
+				q.released = append(q.released, j.ID)
+				return
+			}
+			q.jobs = slices.DeleteFunc(q.jobs, func(other storage.Job) bool { return other.ID == j.ID })
+		}()
+		return j, nil
+	}
+	return storage.Job{}, storage.ErrNoJobs
+}
+
+// recordingHandler records the executions it handled
+type recordingHandler struct {
+	mu      sync.Mutex
+	handled []string
+}
+
+func (h *recordingHandler) Type() PayloadType { return PayloadTypeFlowExecution }
+
+func (h *recordingHandler) Handle(ctx context.Context, job Job) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.handled = append(h.handled, job.ExecID)
+	return nil
+}
+
+func TestProcessPendingTasks_ConcurrencyLimiter(t *testing.T) {
+	jobStore := &queueStorage{locked: make(map[int64]bool)}
+	for i, execID := range []string{"deploy-1", "deploy-2", "build-1"} {
+		jobStore.jobs = append(jobStore.jobs, storage.Job{ID: int64(i + 1), ExecID: execID, PayloadType: string(PayloadTypeFlowExecution)})
+	}
+
+	handler := &recordingHandler{}
+	s, err := NewSchedulerBuilder(slog.New(slog.DiscardHandler)).
+		WithJobStore(jobStore).
+		WithQueueConfig(QueueConfig{Queues: []QueueWeight{{PayloadType: PayloadTypeFlowExecution, Weight: 100}}}).
+		WithWorkerCount(2).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if err := s.SetHandler(handler); err != nil {
+		t.Fatalf("SetHandler() error = %v", err)
+	}
+
+	// deploy runs one execution at a time
+	var mu sync.Mutex
+	var released []string
+	s.SetConcurrencyLimiter(func(ctx context.Context, job Job) (func(), bool, error) {
+		if job.ExecID == "deploy-2" {
+			return nil, false, nil
+		}
+		return func() {
+			mu.Lock()
+			defer mu.Unlock()
+			released = append(released, job.ExecID)
+		}, true, nil
+	})
+
+	if err := s.processPendingTasks(context.Background()); err != nil {
+		t.Fatalf("processPendingTasks() error = %v", err)
+	}
+	s.running.Wait()
+
+	slices.Sort(handler.handled)
+	if want := []string{"build-1", "deploy-1"}; !slices.Equal(handler.handled, want) {
+		t.Errorf("handled %v, want %v: the job after the held one should run", handler.handled, want)
+	}
+	slices.Sort(released)
+	if want := []string{"build-1", "deploy-1"}; !slices.Equal(released, want) {
+		t.Errorf("released slots of %v, want %v", released, want)
+	}
+
+	// The outcomes of the jobs are applied once the workers let go of them
+	deadline := time.Now().Add(time.Second)
+	for {
+		jobStore.mu.Lock()
+		jobs, locked, held := slices.Clone(jobStore.jobs), len(jobStore.locked), slices.Clone(jobStore.released)
+		jobStore.mu.Unlock()
+		if locked == 0 || time.Now().After(deadline) {
+			if len(jobs) != 1 || jobs[0].ExecID != "deploy-2" {
+				t.Errorf("queue = %v, want the held job to stay queued", jobs)
+			}
+			if !slices.Equal(held, []int64{2}) {
+				t.Errorf("released jobs %v, want the held job", held)
+			}
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
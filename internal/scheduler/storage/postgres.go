@@ -5,6 +5,7 @@ import (
 	"database/sql"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 // PostgresStorage implements the Storage interface using PostgreSQL
@@ -111,8 +112,10 @@ func (p *PostgresStorage) Put(ctx context.Context, job Job) error {
 }
 
 // GetByPayloadType retrieves and locks a job of specific payload type from the queue
-// When the done channel is closed, the job is removed from the queue
-func (p *PostgresStorage) GetByPayloadType(ctx context.Context, payloadType string, done chan struct{}) (Job, error) {
+// When the job is completed, it is removed from the queue. A released job is unlocked for
+// another worker to pick up, as it is when the connection of the worker is lost.
+// Jobs in exclude are skipped, so that the jobs after ones that can't run yet are picked.
+func (p *PostgresStorage) GetByPayloadType(ctx context.Context, payloadType string, exclude []int64, done chan Outcome) (Job, error) {
 	tx, err := p.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return Job{}, err
@@ -125,13 +128,18 @@ func (p *PostgresStorage) GetByPayloadType(ctx context.Context, payloadType stri
 		FROM job_queue
 		WHERE payload_type = $1
 		  AND (scheduled_at IS NULL OR scheduled_at <= NOW())
+		  AND id <> ALL($2::BIGINT[])
 		ORDER BY created_at ASC
 		LIMIT 1
 		FOR UPDATE SKIP LOCKED
 	`
 
+	if exclude == nil {
+		exclude = []int64{}
+	}
+
 	var job Job
-	err = tx.GetContext(ctx, &job, selectQuery, payloadType)
+	err = tx.GetContext(ctx, &job, selectQuery, payloadType, pq.Array(exclude))
 	if err != nil {
 		tx.Rollback()
 		if err == sql.ErrNoRows {
@@ -142,7 +150,10 @@ func (p *PostgresStorage) GetByPayloadType(ctx context.Context, payloadType stri
 
 	// Wait for job completion in background, then delete and commit
 	go func() {
-		<-done
+		if <-done == Released {
+			_ = tx.Rollback()
+			return
+		}
 
 		deleteQuery := `DELETE FROM job_queue WHERE id = $1`
 		_, _ = tx.ExecContext(context.Background(), deleteQuery, job.ID)
@@ -166,6 +177,30 @@ func (p *PostgresStorage) CancelByExecID(ctx context.Context, execID string) err
 	return err
 }
 
+// CountReady returns the number of jobs of each payload type that are ready to run
+func (p *PostgresStorage) CountReady(ctx context.Context) (map[string]int, error) {
+	query := `
+		SELECT payload_type, COUNT(*) AS count
+		FROM job_queue
+		WHERE scheduled_at IS NULL OR scheduled_at <= NOW()
+		GROUP BY payload_type
+	`
+
+	var rows []struct {
+		PayloadType string `db:"payload_type"`
+		Count       int    `db:"count"`
+	}
+	if err := p.db.SelectContext(ctx, &rows, query); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int, len(rows))
+	for _, r := range rows {
+		counts[r.PayloadType] = r.Count
+	}
+	return counts, nil
+}
+
 // Close closes the storage backend
 func (p *PostgresStorage) Close() error {
 	// The database connection is managed externally, so we don't close it here
@@ -0,0 +1,158 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"sync"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// SQLiteStorage implements the Storage interface using SQLite. SQLite has no row locks, the
+// jobs being handled are tracked in memory instead, which is enough since a SQLite database
+// is used by a single flowctl instance.
+type SQLiteStorage struct {
+	db *sqlx.DB
+
+	mu      sync.Mutex
+	claimed map[int64]struct{}
+}
+
+// NewSQLiteStorage creates a new SQLite storage backend. db must be opened with
+// repo.OpenSQLite, which provides the functions the queries use.
+func NewSQLiteStorage(db *sqlx.DB) *SQLiteStorage {
+	return &SQLiteStorage{db: db, claimed: make(map[int64]struct{})}
+}
+
+// Initialize creates the job queue table
+func (s *SQLiteStorage) Initialize(ctx context.Context) error {
+	createTableQuery := `
+		CREATE TABLE IF NOT EXISTS job_queue (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			exec_id TEXT NOT NULL,
+			payload_type TEXT NOT NULL DEFAULT 'flow_execution',
+			payload TEXT NOT NULL,
+			created_at TIMESTAMP DEFAULT (now()),
+			scheduled_at TIMESTAMP DEFAULT NULL,
+			max_retries INTEGER DEFAULT 0,
+			attempt INTEGER DEFAULT 0
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_job_queue_exec_id ON job_queue(exec_id);
+		CREATE INDEX IF NOT EXISTS idx_job_queue_payload_type ON job_queue(payload_type, created_at);
+		CREATE INDEX IF NOT EXISTS idx_job_queue_scheduled_at ON job_queue(scheduled_at) WHERE scheduled_at IS NOT NULL;
+	`
+
+	_, err := s.db.ExecContext(ctx, createTableQuery)
+	return err
+}
+
+// Put adds a job to the queue
+func (s *SQLiteStorage) Put(ctx context.Context, job Job) error {
+	query := `
+		INSERT INTO job_queue (exec_id, payload_type, payload, created_at, scheduled_at, max_retries, attempt)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+
+	err := s.db.GetContext(ctx, &job.ID, query, job.ExecID, job.PayloadType, job.Payload, job.CreatedAt, job.ScheduledAt, job.MaxRetries, job.Attempt)
+	return err
+}
+
+// GetByPayloadType claims a job of specific payload type from the queue
+// When the job is completed, it is removed from the queue. A released job is unclaimed for
+// another worker to pick up, as every job is when flowctl restarts.
+// Jobs in exclude are skipped, so that the jobs after ones that can't run yet are picked.
+func (s *SQLiteStorage) GetByPayloadType(ctx context.Context, payloadType string, exclude []int64, done chan Outcome) (Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	skip := append([]int64{}, exclude...)
+	for id := range s.claimed {
+		skip = append(skip, id)
+	}
+	skipJSON, err := json.Marshal(skip)
+	if err != nil {
+		return Job{}, err
+	}
+
+	// Only return jobs that are ready to run (scheduled_at is NULL or <= NOW())
+	selectQuery := `
+		SELECT id, exec_id, payload_type, payload, created_at, scheduled_at, max_retries, attempt
+		FROM job_queue
+		WHERE payload_type = $1
+		  AND (scheduled_at IS NULL OR scheduled_at <= NOW())
+		  AND id NOT IN (SELECT value FROM json_each($2))
+		ORDER BY created_at ASC
+		LIMIT 1
+	`
+
+	var job Job
+	err = s.db.GetContext(ctx, &job, selectQuery, payloadType, string(skipJSON))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return Job{}, ErrNoJobs
+		}
+		return Job{}, err
+	}
+	s.claimed[job.ID] = struct{}{}
+
+	// Wait for job completion in background, then delete and unclaim
+	go func() {
+		if <-done != Released {
+			deleteQuery := `DELETE FROM job_queue WHERE id = $1`
+			_, _ = s.db.ExecContext(context.Background(), deleteQuery, job.ID)
+		}
+
+		s.mu.Lock()
+		delete(s.claimed, job.ID)
+		s.mu.Unlock()
+	}()
+
+	return job, nil
+}
+
+// Delete removes a job from the queue
+func (s *SQLiteStorage) Delete(ctx context.Context, jobID int64) error {
+	query := `DELETE FROM job_queue WHERE id = $1`
+	_, err := s.db.ExecContext(ctx, query, jobID)
+	return err
+}
+
+// CancelByExecID removes all jobs with the given execution ID
+func (s *SQLiteStorage) CancelByExecID(ctx context.Context, execID string) error {
+	query := `DELETE FROM job_queue WHERE exec_id = $1`
+	_, err := s.db.ExecContext(ctx, query, execID)
+	return err
+}
+
+// CountReady returns the number of jobs of each payload type that are ready to run
+func (s *SQLiteStorage) CountReady(ctx context.Context) (map[string]int, error) {
+	query := `
+		SELECT payload_type, COUNT(*) AS count
+		FROM job_queue
+		WHERE scheduled_at IS NULL OR scheduled_at <= NOW()
+		GROUP BY payload_type
+	`
+
+	var rows []struct {
+		PayloadType string `db:"payload_type"`
+		Count       int    `db:"count"`
+	}
+	if err := s.db.SelectContext(ctx, &rows, query); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int, len(rows))
+	for _, r := range rows {
+		counts[r.PayloadType] = r.Count
+	}
+	return counts, nil
+}
+
+// Close closes the storage backend
+func (s *SQLiteStorage) Close() error {
+	// The database connection is managed externally, so we don't close it here
+	return nil
+}
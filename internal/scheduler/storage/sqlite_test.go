@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cvhariharan/flowctl/internal/repo"
+)
+
+func TestSQLiteStorage(t *testing.T) {
+	db, err := repo.OpenSQLite(filepath.Join(t.TempDir(), "flowctl.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	s := NewSQLiteStorage(db)
+	if err := s.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	for _, execID := range []string{"exec-1", "exec-2"} {
+		job, err := NewJob(execID, "flow_execution", map[string]string{"exec_id": execID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Put(ctx, job); err != nil {
+			t.Fatalf("Put() error = %v", err)
+		}
+	}
+	later, _ := NewScheduledJob("exec-3", "flow_execution", nil, time.Now().Add(time.Hour))
+	if err := s.Put(ctx, later); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	counts, err := s.CountReady(ctx)
+	if err != nil {
+		t.Fatalf("CountReady() error = %v", err)
+	}
+	if counts["flow_execution"] != 2 {
+		t.Errorf("CountReady() = %v, want 2 ready jobs", counts)
+	}
+
+	done1 := make(chan Outcome)
+	job1, err := s.GetByPayloadType(ctx, "flow_execution", nil, done1)
+	if err != nil || job1.ExecID != "exec-1" || string(job1.Payload) != `{"exec_id":"exec-1"}` {
+		t.Fatalf("GetByPayloadType() = %+v, %v, want exec-1", job1, err)
+	}
+
+	// A claimed job isn't handed out again
+	done2 := make(chan Outcome)
+	job2, err := s.GetByPayloadType(ctx, "flow_execution", nil, done2)
+	if err != nil || job2.ExecID != "exec-2" {
+		t.Fatalf("GetByPayloadType() = %+v, %v, want exec-2", job2, err)
+	}
+	if _, err := s.GetByPayloadType(ctx, "flow_execution", nil, make(chan Outcome)); !errors.Is(err, ErrNoJobs) {
+		t.Fatalf("GetByPayloadType() error = %v, want ErrNoJobs", err)
+	}
+
+	// A released job is handed out again, a completed job is removed
+	done1 <- Released
+	close(done2)
+	time.Sleep(50 * time.Millisecond)
+
+	job, err := s.GetByPayloadType(ctx, "flow_execution", nil, make(chan Outcome))
+	if err != nil || job.ID != job1.ID {
+		t.Fatalf("GetByPayloadType() = %+v, %v, want exec-1 again", job, err)
+	}
+	counts, _ = s.CountReady(ctx)
+	if counts["flow_execution"] != 1 {
+		t.Errorf("CountReady() = %v, want 1 ready job", counts)
+	}
+}
@@ -23,6 +23,16 @@ var (
 	ErrNoJobs = errors.New("no jobs available")
 )
 
+// Outcome is how the handling of a job ended
+type Outcome int
+
+const (
+	// Completed jobs are removed from the queue
+	Completed Outcome = iota
+	// Released jobs are put back in the queue, to be picked up by another worker
+	Released
+)
+
 // Storage interface for job queue storage backends
 type Storage interface {
 	// Initialize sets up the storage backend (creates tables, etc.)
@@ -31,10 +41,12 @@ type Storage interface {
 	// Put adds a job to the queue
 	Put(ctx context.Context, job Job) error
 
-	// GetByPayloadType retrieves and locks a job of specific payload type from the queue
-	// The job remains locked until the done channel is closed
+	// GetByPayloadType retrieves and locks a job of specific payload type from the queue,
+	// skipping the jobs in exclude
+	// The job remains locked until an outcome is sent on the done channel or it is closed,
+	// a closed channel completes the job
 	// Returns ErrNoJobs if no jobs are available
-	GetByPayloadType(ctx context.Context, payloadType string, done chan struct{}) (Job, error)
+	GetByPayloadType(ctx context.Context, payloadType string, exclude []int64, done chan Outcome) (Job, error)
 
 	// Delete removes a job from the queue
 	Delete(ctx context.Context, jobID int64) error
@@ -42,6 +54,9 @@ type Storage interface {
 	// CancelByExecID removes all jobs with the given execution ID
 	CancelByExecID(ctx context.Context, execID string) error
 
+	// CountReady returns the number of jobs of each payload type that are ready to run
+	CountReady(ctx context.Context) (map[string]int, error)
+
 	// Close closes the storage backend
 	Close() error
 }
@@ -0,0 +1,239 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+
+	"github.com/cvhariharan/flowctl/internal/repo"
+	"github.com/cvhariharan/flowctl/internal/streamlogger"
+	"github.com/google/uuid"
+)
+
+// ExecutorFlow is the executor of actions that run another flow of the namespace. The execution
+// doesn't hold a worker while the other flow runs, it is resumed from the action once the
+// execution of the other flow finished and continues with its outputs.
+const ExecutorFlow = "flow"
+
+// SubflowRequest is an execution of a flow started by the flow action of a parent execution
+type SubflowRequest struct {
+	NamespaceID string
+	FlowID      string
+	// Inputs maps inputs of the flow to values with placeholders like triggers do, evaluated
+	// with Env, the inputs, vars and outputs of the parent execution
+	Inputs         map[string]string
+	Env            map[string]any
+	ParentExecID   string
+	ParentActionID string
+	// UserUUID is the user that triggered the parent execution, the execution runs as them
+	UserUUID string
+}
+
+// SubflowQueuerFn queues the execution of a flow for the flow action of a parent execution and
+// records the link between them
+type SubflowQueuerFn func(ctx context.Context, req SubflowRequest) (string, error)
+
+// SubflowError is returned when an execution reaches a flow action whose execution hasn't
+// finished. Request is set when the execution still has to be queued.
+type SubflowError struct {
+	ActionID string
+	Request  *SubflowRequest
+}
+
+func (e *SubflowError) Error() string {
+	return fmt.Sprintf("action %s waits for the execution of a flow", e.ActionID)
+}
+
+// subflowInputs returns the inputs a flow action passes to the flow it runs. Values that are not
+// strings are formatted, they are converted to the type of the input when the flow is queued.
+func subflowInputs(with map[string]any) (map[string]string, error) {
+	inputs := make(map[string]string)
+	raw, ok := with["inputs"]
+	if !ok || raw == nil {
+		return inputs, nil
+	}
+	m, ok := raw.(map[string]any)
+	if !ok {
+		return nil, errors.New("inputs must be a map")
+	}
+
+	for k, v := range m {
+		if s, ok := v.(string); ok {
+			inputs[k] = s
+			continue
+		}
+		inputs[k] = fmt.Sprint(v)
+	}
+	return inputs, nil
+}
+
+// checkSubflow returns nil once the execution started by a flow action finished. Until then the
+// action is set as the current action of the execution and a SubflowError is returned, with the
+// execution to queue when the action is first reached.
+func (h *FlowExecutionHandler) checkSubflow(ctx context.Context, execID string, action Action, input map[string]any, vars map[string]string, outputs map[string]any, namespaceID string, userUUID string, streamLogger streamlogger.Logger) error {
+	if action.Executor != ExecutorFlow {
+		return nil
+	}
+
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	var req *SubflowRequest
+	sub, err := h.store.GetUnresolvedSubflowExecution(ctx, repo.GetUnresolvedSubflowExecutionParams{
+		ParentExecID:   execID,
+		ParentActionID: action.ID,
+		Uuid:           namespaceUUID,
+	})
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		flowID, _ := action.With["flow"].(string)
+		inputs, err := subflowInputs(action.With)
+		if err != nil {
+			return fmt.Errorf("action %s: %w", action.ID, err)
+		}
+		req = &SubflowRequest{
+			NamespaceID: namespaceID,
+			FlowID:      flowID,
+			Inputs:      inputs,
+			Env: map[string]any{
+				"inputs":  input,
+				"vars":    vars,
+				"outputs": outputs,
+			},
+			ParentExecID:   execID,
+			ParentActionID: action.ID,
+			UserUUID:       userUUID,
+		}
+	case err != nil:
+		return fmt.Errorf("could not get the execution of action %s: %w", action.ID, err)
+	default:
+		done, err := h.subflowFinished(ctx, sub.ExecID, namespaceUUID)
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+	}
+
+	// Set the current action ID so that the execution is resumed or retried from this action
+	if _, err := h.store.UpdateExecutionActionID(ctx, repo.UpdateExecutionActionIDParams{
+		CurrentActionID: sql.NullString{String: action.ID, Valid: true},
+		ExecID:          execID,
+		Uuid:            namespaceUUID,
+	}); err != nil {
+		return fmt.Errorf("could not update current action ID in exec %s: %w", execID, err)
+	}
+
+	if req != nil {
+		streamLogger.SetActionID(action.ID)
+		if err := streamLogger.Checkpoint(action.ID, "", []byte(fmt.Sprintf("running flow %s", req.FlowID)), streamlogger.LogMessageType); err != nil {
+			h.logger.Error("failed to log sub-flow", "execID", execID, "actionID", action.ID, "error", err)
+		}
+	}
+
+	return &SubflowError{ActionID: action.ID, Request: req}
+}
+
+// subflowFinished reports whether an execution started by a flow action completed, errored or
+// was cancelled
+func (h *FlowExecutionHandler) subflowFinished(ctx context.Context, execID string, namespaceUUID uuid.UUID) (bool, error) {
+	e, err := h.store.GetExecutionByExecID(ctx, repo.GetExecutionByExecIDParams{
+		ExecID: execID,
+		Uuid:   namespaceUUID,
+	})
+	if err != nil {
+		return false, fmt.Errorf("could not get exec %s: %w", execID, err)
+	}
+
+	switch e.Status {
+	case repo.ExecutionStatusCompleted, repo.ExecutionStatusErrored, repo.ExecutionStatusCancelled:
+		return true, nil
+	}
+	return false, nil
+}
+
+// subflowResults returns the results of a flow action: the outputs of the execution it started,
+// along with its ID as exec_id. The action fails if the execution didn't complete. The link to the
+// execution is resolved, so that retrying the action starts a new execution.
+func (h *FlowExecutionHandler) subflowResults(ctx context.Context, execID string, action Action, namespaceID string) (map[string]string, error) {
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	sub, err := h.store.GetUnresolvedSubflowExecution(ctx, repo.GetUnresolvedSubflowExecutionParams{
+		ParentExecID:   execID,
+		ParentActionID: action.ID,
+		Uuid:           namespaceUUID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not get the execution of action %s: %w", action.ID, err)
+	}
+	if err := h.store.ResolveSubflowExecution(ctx, sub.ID); err != nil {
+		return nil, fmt.Errorf("could not resolve the execution of action %s: %w", action.ID, err)
+	}
+
+	e, err := h.store.GetExecutionByExecID(ctx, repo.GetExecutionByExecIDParams{
+		ExecID: sub.ExecID,
+		Uuid:   namespaceUUID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not get exec %s: %w", sub.ExecID, err)
+	}
+	switch e.Status {
+	case repo.ExecutionStatusCompleted:
+	case repo.ExecutionStatusCancelled:
+		return nil, fmt.Errorf("execution %s of flow %s was cancelled", sub.ExecID, e.FlowSlug)
+	default:
+		return nil, fmt.Errorf("execution %s of flow %s failed: %s", sub.ExecID, e.FlowSlug, e.Error.String)
+	}
+
+	rows, err := h.store.ListExecutionOutputs(ctx, repo.ListExecutionOutputsParams{
+		ExecID: sub.ExecID,
+		Uuid:   namespaceUUID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not get outputs of exec %s: %w", sub.ExecID, err)
+	}
+
+	// The results of the actions are merged in the order they finished, like the outputs of an
+	// execution. Results of nodes keep their node suffix.
+	results := make(map[string]string)
+	for _, r := range rows {
+		var res map[string]string
+		if err := json.Unmarshal(r.Outputs, &res); err != nil {
+			return nil, fmt.Errorf("could not decode outputs of action %s in exec %s: %w", r.ActionID, sub.ExecID, err)
+		}
+		maps.Copy(results, res)
+	}
+	results["exec_id"] = sub.ExecID
+	return results, nil
+}
+
+// startSubflow sets an execution that reached a flow action as pending and queues the execution
+// of the flow, which resumes it once it finished. The execution is queued after the status is
+// set so that it can't finish before the execution waiting on it is pending.
+func (h *FlowExecutionHandler) startSubflow(ctx context.Context, execID string, payload FlowExecutionPayload, subErr *SubflowError) error {
+	if err := h.setStatus(ctx, execID, repo.ExecutionStatusPending, payload.NamespaceID, nil); err != nil {
+		return err
+	}
+	if subErr.Request == nil {
+		return nil
+	}
+
+	if h.subflowQueuer == nil {
+		err := errors.New("no sub-flow queuer to run the flow")
+		return h.setStatusWithMetrics(ctx, execID, repo.ExecutionStatusErrored, payload, err)
+	}
+	if _, err := h.subflowQueuer(ctx, *subErr.Request); err != nil {
+		err = fmt.Errorf("action %s could not run flow %s: %w", subErr.ActionID, subErr.Request.FlowID, err)
+		return h.setStatusWithMetrics(ctx, execID, repo.ExecutionStatusErrored, payload, err)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/cvhariharan/flowctl/internal/repo"
+	"github.com/google/uuid"
+)
+
+// subflowStore holds the execution started by a flow action
+type subflowStore struct {
+	repo.Store
+	sub      repo.SubflowExecution
+	status   repo.ExecutionStatus
+	outputs  []repo.ListExecutionOutputsRow
+	resolved bool
+}
+
+func (s *subflowStore) GetUnresolvedSubflowExecution(ctx context.Context, arg repo.GetUnresolvedSubflowExecutionParams) (repo.SubflowExecution, error) {
+	if s.resolved || arg.ParentActionID != s.sub.ParentActionID {
+		return repo.SubflowExecution{}, sql.ErrNoRows
+	}
+	return s.sub, nil
+}
+
+func (s *subflowStore) ResolveSubflowExecution(ctx context.Context, id int32) error {
+	s.resolved = true
+	return nil
+}
+
+func (s *subflowStore) GetExecutionByExecID(ctx context.Context, arg repo.GetExecutionByExecIDParams) (repo.GetExecutionByExecIDRow, error) {
+	return repo.GetExecutionByExecIDRow{
+		ExecID:   arg.ExecID,
+		Status:   s.status,
+		FlowSlug: "deploy_service",
+		Error:    sql.NullString{String: "exit status 1", Valid: s.status == repo.ExecutionStatusErrored},
+	}, nil
+}
+
+func (s *subflowStore) ListExecutionOutputs(ctx context.Context, arg repo.ListExecutionOutputsParams) ([]repo.ListExecutionOutputsRow, error) {
+	return s.outputs, nil
+}
+
+func TestSubflowResults(t *testing.T) {
+	record := func(actionID string, results map[string]string) repo.ListExecutionOutputsRow {
+		data, err := json.Marshal(results)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return repo.ListExecutionOutputsRow{ActionID: actionID, Outputs: data}
+	}
+	action := Action{ID: "deploy", Executor: ExecutorFlow, With: map[string]any{"flow": "deploy_service"}}
+	namespaceID := uuid.NewString()
+
+	t.Run("completed", func(t *testing.T) {
+		store := &subflowStore{
+			sub:    repo.SubflowExecution{ID: 1, ParentExecID: "parent", ParentActionID: "deploy", ExecID: "child"},
+			status: repo.ExecutionStatusCompleted,
+			outputs: []repo.ListExecutionOutputsRow{
+				record("build", map[string]string{"image": "app:1.1.0"}),
+				record("rollout", map[string]string{"image": "app:1.2.0", "url": "https://app.example.com"}),
+			},
+		}
+		h := &FlowExecutionHandler{store: store, logger: slog.New(slog.DiscardHandler)}
+
+		got, err := h.subflowResults(context.Background(), "parent", action, namespaceID)
+		if err != nil {
+			t.Fatalf("subflowResults() error = %v", err)
+		}
+		want := map[string]string{"image": "app:1.2.0", "url": "https://app.example.com", "exec_id": "child"}
+		if len(got) != len(want) {
+			t.Errorf("subflowResults() = %v, want %v", got, want)
+		}
+		for k, v := range want {
+			if got[k] != v {
+				t.Errorf("subflowResults()[%s] = %q, want %q", k, got[k], v)
+			}
+		}
+		if !store.resolved {
+			t.Error("subflowResults() didn't resolve the execution, a retry wouldn't run the flow again")
+		}
+	})
+
+	for _, status := range []repo.ExecutionStatus{repo.ExecutionStatusErrored, repo.ExecutionStatusCancelled} {
+		t.Run(string(status), func(t *testing.T) {
+			store := &subflowStore{
+				sub:    repo.SubflowExecution{ID: 1, ParentExecID: "parent", ParentActionID: "deploy", ExecID: "child"},
+				status: status,
+			}
+			h := &FlowExecutionHandler{store: store, logger: slog.New(slog.DiscardHandler)}
+
+			if _, err := h.subflowResults(context.Background(), "parent", action, namespaceID); err == nil {
+				t.Errorf("subflowResults() of a %s execution succeeded", status)
+			}
+			if !store.resolved {
+				t.Error("subflowResults() didn't resolve the execution")
+			}
+		})
+	}
+}
+
+func TestSubflowInputs(t *testing.T) {
+	got, err := subflowInputs(map[string]any{
+		"flow":   "deploy_service",
+		"inputs": map[string]any{"version": "{{ outputs.BUILD_ID }}", "replicas": 3, "canary": true},
+	})
+	if err != nil {
+		t.Fatalf("subflowInputs() error = %v", err)
+	}
+	want := map[string]string{"version": "{{ outputs.BUILD_ID }}", "replicas": "3", "canary": "true"}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("subflowInputs()[%s] = %q, want %q", k, got[k], v)
+		}
+	}
+
+	if _, err := subflowInputs(map[string]any{"inputs": "version=1"}); err == nil {
+		t.Error("subflowInputs() with inputs that aren't a map succeeded")
+	}
+}
@@ -0,0 +1,63 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/cvhariharan/flowctl/internal/repo"
+	"github.com/cvhariharan/flowctl/sdk/executor"
+	"github.com/google/uuid"
+)
+
+// sampleNodeResources records the resource usage of the node the driver is connected to every
+// telemetry period, until the returned function is called. Samples are informational so
+// failures are only logged.
+func (h *FlowExecutionHandler) sampleNodeResources(ctx context.Context, driver executor.NodeDriver, execID string, namespaceID string, actionID string, nodeName string) func() {
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		h.logger.Error("invalid namespace UUID", "execID", execID, "error", err)
+		return func() {}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(h.telemetryPeriod)
+		defer ticker.Stop()
+
+		for {
+			sample, err := executor.SampleResources(ctx, driver)
+			if err != nil {
+				if ctx.Err() == nil {
+					h.logger.Warn("failed to sample node resources", "execID", execID, "actionID", actionID, "node", nodeName, "error", err)
+				}
+			} else if err := h.store.CreateNodeTelemetrySample(context.WithoutCancel(ctx), repo.CreateNodeTelemetrySampleParams{
+				ExecID:        execID,
+				NamespaceUuid: namespaceUUID,
+				ActionID:      actionID,
+				Node:          nodeName,
+				SampledAt:     sample.Time,
+				CpuPercent:    sample.CPUPercent,
+				MemoryPercent: sample.MemoryPercent,
+				DiskPercent:   sample.DiskPercent,
+				Load1:         sample.Load1,
+			}); err != nil {
+				h.logger.Error("failed to record node telemetry", "execID", execID, "actionID", actionID, "node", nodeName, "error", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
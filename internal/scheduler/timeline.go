@@ -0,0 +1,110 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/cvhariharan/flowctl/internal/repo"
+	"github.com/google/uuid"
+)
+
+// timelineEntry is the timeline entry of a node running an action, buffered until it is recorded
+type timelineEntry struct {
+	actionID   string
+	retry      int32
+	status     string
+	err        string
+	startedAt  time.Time
+	finishedAt time.Time
+}
+
+// startTimelineEntry records when an action started running.
+// The timeline is informational so failures are only logged.
+func (p *progressTracker) startTimelineEntry(ctx context.Context, actionID string, retry int32) {
+	namespaceUUID, err := uuid.Parse(p.namespaceID)
+	if err != nil {
+		p.logger.Error("invalid namespace UUID", "execID", p.execID, "error", err)
+		return
+	}
+
+	if err := p.store.StartExecutionTimelineEntry(context.WithoutCancel(ctx), repo.StartExecutionTimelineEntryParams{
+		ExecID:        p.execID,
+		NamespaceUuid: namespaceUUID,
+		ActionID:      actionID,
+		Retry:         retry,
+	}); err != nil {
+		p.logger.Error("failed to record timeline entry", "execID", p.execID, "actionID", actionID, "error", err)
+	}
+}
+
+// finishTimelineEntry records when an action started with startTimelineEntry finished and its outcome
+func (p *progressTracker) finishTimelineEntry(ctx context.Context, actionID string, retry int32, err error) {
+	namespaceUUID, perr := uuid.Parse(p.namespaceID)
+	if perr != nil {
+		p.logger.Error("invalid namespace UUID", "execID", p.execID, "error", perr)
+		return
+	}
+
+	var errMsg sql.NullString
+	if err != nil {
+		errMsg = sql.NullString{String: err.Error(), Valid: true}
+	}
+
+	if ferr := p.store.FinishExecutionTimelineEntry(context.WithoutCancel(ctx), repo.FinishExecutionTimelineEntryParams{
+		Status:        progressStatus(err),
+		Error:         errMsg,
+		NamespaceUuid: namespaceUUID,
+		ExecID:        p.execID,
+		ActionID:      actionID,
+		Retry:         retry,
+	}); ferr != nil {
+		p.logger.Error("failed to record timeline entry", "execID", p.execID, "actionID", actionID, "error", ferr)
+	}
+}
+
+// finishNodeEntry buffers the outcome of a node. Callers must hold p.mu.
+func (p *progressTracker) finishNodeEntry(actionID string, node string, err error) {
+	now := time.Now()
+	e, ok := p.nodes[node]
+	if !ok {
+		e = &timelineEntry{actionID: actionID, retry: p.retry, startedAt: now}
+		p.nodes[node] = e
+	}
+
+	e.status = progressStatus(err)
+	if err != nil {
+		e.err = err.Error()
+	}
+	e.finishedAt = now
+	p.pendingNodes[node] = struct{}{}
+}
+
+// recordNodeEntries records the node entries that changed since they were last recorded in a
+// single statement. Callers must hold p.mu.
+func (p *progressTracker) recordNodeEntries(ctx context.Context, namespaceUUID uuid.UUID) {
+	if len(p.pendingNodes) == 0 {
+		return
+	}
+
+	params := repo.RecordExecutionTimelineEntriesParams{
+		ExecID:        p.execID,
+		NamespaceUuid: namespaceUUID,
+	}
+	for node := range p.pendingNodes {
+		e := p.nodes[node]
+		params.ActionIds = append(params.ActionIds, e.actionID)
+		params.Nodes = append(params.Nodes, node)
+		params.Retries = append(params.Retries, e.retry)
+		params.Statuses = append(params.Statuses, e.status)
+		params.Errors = append(params.Errors, e.err)
+		params.StartedAt = append(params.StartedAt, e.startedAt)
+		params.Finished = append(params.Finished, !e.finishedAt.IsZero())
+		params.FinishedAt = append(params.FinishedAt, e.finishedAt)
+	}
+	clear(p.pendingNodes)
+
+	if err := p.store.RecordExecutionTimelineEntries(context.WithoutCancel(ctx), params); err != nil {
+		p.logger.Error("failed to record timeline entries", "execID", p.execID, "nodes", len(params.Nodes), "error", err)
+	}
+}
@@ -23,6 +23,9 @@ const (
 var (
 	ErrPendingApproval    = errors.New("pending approval")
 	ErrExecutionCancelled = errors.New("execution cancelled")
+	// ErrHandoff is the cause of the cancellation of the jobs of a scheduler that is stopping.
+	// Their executions are left running for the server that picks up the jobs next to adopt.
+	ErrHandoff = errors.New("execution handed off to another server")
 )
 
 type TriggerType string
@@ -51,6 +54,8 @@ const (
 	INPUT_TYPE_SLICE_INT    InputType = "slice_int"
 	INPUT_TYPE_SLICE_UINT   InputType = "slice_uint"
 	INPUT_TYPE_SLICE_FLOAT  InputType = "slice_float"
+	INPUT_TYPE_PASSWORD     InputType = "password"
+	INPUT_TYPE_FILE         InputType = "file"
 )
 
 type AuthMethod string
@@ -63,6 +68,8 @@ const (
 type ExecResults struct {
 	result map[string]string
 	err    error
+	// unreachable is set when the node could not be connected to
+	unreachable bool
 }
 
 type Node struct {
@@ -129,13 +136,21 @@ type Input struct {
 }
 
 type Action struct {
-	ID        string         `yaml:"id" validate:"required,alphanum_underscore"`
-	Name      string         `yaml:"name" validate:"required"`
-	Executor  string         `yaml:"executor"`
-	With      map[string]any `yaml:"with" validate:"required"`
-	Approval  bool           `yaml:"approval"`
-	Variables []Variable     `yaml:"variables"`
-	On        []Node         `yaml:"on"`
+	ID         string         `yaml:"id" validate:"required,alphanum_underscore"`
+	Name       string         `yaml:"name" validate:"required"`
+	Executor   string         `yaml:"executor"`
+	With       map[string]any `yaml:"with" validate:"required"`
+	Approval   bool           `yaml:"approval"`
+	Variables  []Variable     `yaml:"variables"`
+	On         []Node         `yaml:"on"`
+	Telemetry  bool           `yaml:"telemetry"`
+	Lock       string         `yaml:"lock"`
+	Approvers  []string       `yaml:"approvers"`
+	Username   string         `yaml:"username"`
+	Credential string         `yaml:"credential"`
+	Needs      []string       `yaml:"needs"`
+	Condition  string         `yaml:"condition"`
+	Artifacts  []string       `yaml:"artifacts"`
 }
 
 type Scheduling struct {
@@ -150,6 +165,7 @@ type Metadata struct {
 	Description string `yaml:"description"`
 	SrcDir      string `yaml:"-"`
 	Namespace   string `yaml:"namespace"`
+	Lock        string `yaml:"lock"`
 }
 
 type Variable map[string]any
@@ -191,6 +207,8 @@ const (
 	NotifyEventOnFailure   NotifyEvent = "on_failure"
 	NotifyEventOnWaiting   NotifyEvent = "on_waiting"
 	NotifyEventOnCancelled NotifyEvent = "on_cancelled"
+	NotifyEventOnSkipped   NotifyEvent = "on_skipped"
+	NotifyEventOnSLABreach NotifyEvent = "on_sla_breach"
 )
 
 type Notify struct {
@@ -219,17 +237,47 @@ type FlowExecutionPayload struct {
 
 	// Resumed should be set to true if resuming an existing execution (after approval or retry)
 	Resumed bool
+
+	// TraceContext carries the trace of the request that queued the execution to the worker
+	TraceContext map[string]string `json:",omitempty"`
+
+	// ScheduleID is the cron schedule that queued the execution and FiredAt the time it was due to run
+	ScheduleID int32     `json:",omitempty"`
+	FiredAt    time.Time `json:",omitzero"`
+
+	// WakeAt is when the wait action at StartingActionIdx is over, set when the execution was
+	// queued again to resume after the wait
+	WakeAt time.Time `json:",omitzero"`
+
+	// Skipped is the failed action that was skipped when the execution was retried
+	Skipped *SkippedAction `json:",omitempty"`
+
+	// Adopted is set when the execution was interrupted by a restart of the server running it
+	// and is continued by the server that picked up its job
+	Adopted bool `json:"-"`
+}
+
+// SkippedAction is a failed action that a user chose to skip when retrying the execution
+type SkippedAction struct {
+	ActionID  string
+	SkippedBy string
 }
 
 // Hook function types for flow execution
 type HookFn func(ctx context.Context, execID string, action Action, namespaceID string) error
 type SecretsProviderFn func(ctx context.Context, flowID string, namespaceID string) (map[string]string, error)
+type VariablesProviderFn func(ctx context.Context, namespaceID string) (map[string]string, error)
+type InputSealerFn func(ctx context.Context, inputs []Input, input map[string]any) (map[string]any, error)
 type FlowLoaderFn func(ctx context.Context, flowSlug string, namespaceUUID string) (Flow, error)
 
+// CompletionHookFn is called after an execution completed, errored or was cancelled
+type CompletionHookFn func(ctx context.Context, execID string, status string, flowSlug string, namespaceID string) error
+
 // TaskQueuer allows handlers to enqueue new tasks
 type TaskQueuer interface {
 	QueueTask(ctx context.Context, payloadType PayloadType, execID string, payload any) (string, error)
 	QueueTaskWithRetries(ctx context.Context, payloadType PayloadType, execID string, payload any, maxRetries int) (string, error)
+	QueueScheduledTask(ctx context.Context, payloadType PayloadType, execID string, payload any, scheduledAt time.Time) (string, error)
 }
 
 // PayloadType identifies different types of jobs in the queue
@@ -376,3 +424,16 @@ type ScheduledJob struct {
 
 // JobSyncerFn syncs scheduled jobs from a data source
 type JobSyncerFn func(ctx context.Context) ([]ScheduledJob, error)
+
+// SkipCheckerFn is called before a due scheduled job is queued. A non-empty reason
+// skips the run and triggers the on_skipped notifications for the flow.
+type SkipCheckerFn func(ctx context.Context, job ScheduledJob) (string, error)
+
+// ConcurrencyLimiterFn is called before a job is handed to a worker. A job that can't run yet,
+// because too many executions of its flow or namespace are running, is left in its place in the
+// queue. Otherwise release is called once the job was handled.
+type ConcurrencyLimiterFn func(ctx context.Context, job Job) (release func(), ok bool, err error)
+
+// SLAMonitorFn is called every minute to check running and scheduled executions against
+// the SLA of their flows
+type SLAMonitorFn func(ctx context.Context) error
@@ -0,0 +1,168 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/cvhariharan/flowctl/internal/repo"
+	"github.com/cvhariharan/flowctl/internal/streamlogger"
+	"github.com/expr-lang/expr"
+	"github.com/google/uuid"
+)
+
+// ExecutorWait is the executor of actions that pause the execution for a duration or until a
+// time. The execution doesn't hold a worker while it waits, it is queued again to resume from
+// the action once the wait is over.
+const ExecutorWait = "wait"
+
+// IsBuiltinExecutor reports whether actions of the executor are handled by the scheduler
+// itself instead of running on nodes
+func IsBuiltinExecutor(name string) bool {
+	return name == ExecutorManual || name == ExecutorWait || name == ExecutorFlow
+}
+
+var waitExprPattern = regexp.MustCompile(`^{{\s*([^}]+)\s*}}$`)
+
+// WaitError is returned when an execution reaches a wait action that isn't over yet
+type WaitError struct {
+	ActionID string
+	WakeAt   time.Time
+}
+
+func (e *WaitError) Error() string {
+	return fmt.Sprintf("action %s waits until %s", e.ActionID, e.WakeAt.Format(time.RFC3339))
+}
+
+// WaitUntil returns when a wait action that was reached at now is over. The with block sets
+// either a duration or a time to wait until, an RFC3339 timestamp or an expression that
+// evaluates to one.
+func WaitUntil(with map[string]any, env map[string]any, now time.Time) (time.Time, error) {
+	duration, _ := with["duration"].(string)
+	until, _ := with["until"].(string)
+
+	switch {
+	case duration != "" && until != "":
+		return time.Time{}, errors.New("only one of duration and until can be set")
+	case duration != "":
+		d, err := time.ParseDuration(duration)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid duration: %w", err)
+		}
+		if d <= 0 {
+			return time.Time{}, errors.New("duration must be positive")
+		}
+		return now.Add(d), nil
+	case until != "":
+		m := waitExprPattern.FindStringSubmatch(strings.TrimSpace(until))
+		if m == nil {
+			return parseWaitTime(until)
+		}
+		out, err := expr.Eval(strings.TrimSpace(m[1]), env)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to evaluate until: %w", err)
+		}
+		switch v := out.(type) {
+		case time.Time:
+			return v, nil
+		case string:
+			return parseWaitTime(v)
+		}
+		return time.Time{}, fmt.Errorf("until must evaluate to a time, got %T", out)
+	}
+	return time.Time{}, errors.New("one of duration and until is required")
+}
+
+func parseWaitTime(s string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, strings.TrimSpace(s))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("until must be an RFC3339 time: %w", err)
+	}
+	return t, nil
+}
+
+// checkWait returns nil once a wait action is over. wakeAt is when the wait of the action the
+// execution resumed from is over, zero if the action was not reached before. Until then the
+// action is set as the current action of the execution and a WaitError is returned.
+func (h *FlowExecutionHandler) checkWait(ctx context.Context, execID string, action Action, input map[string]any, vars map[string]string, outputs map[string]any, namespaceID string, wakeAt time.Time, streamLogger streamlogger.Logger) error {
+	if action.Executor != ExecutorWait {
+		return nil
+	}
+
+	now := time.Now()
+	if wakeAt.IsZero() {
+		env := map[string]any{
+			"inputs":  input,
+			"vars":    vars,
+			"outputs": outputs,
+		}
+		t, err := WaitUntil(action.With, env, now)
+		if err != nil {
+			return fmt.Errorf("action %s: %w", action.ID, err)
+		}
+		wakeAt = t
+	}
+	if !now.Before(wakeAt) {
+		return nil
+	}
+
+	namespaceUUID, err := uuid.Parse(namespaceID)
+	if err != nil {
+		return fmt.Errorf("invalid namespace UUID: %w", err)
+	}
+
+	// Set the current action ID so that the execution is resumed or retried from this action
+	if _, err := h.store.UpdateExecutionActionID(ctx, repo.UpdateExecutionActionIDParams{
+		CurrentActionID: sql.NullString{String: action.ID, Valid: true},
+		ExecID:          execID,
+		Uuid:            namespaceUUID,
+	}); err != nil {
+		return fmt.Errorf("could not update current action ID in exec %s: %w", execID, err)
+	}
+
+	streamLogger.SetActionID(action.ID)
+	if err := streamLogger.Checkpoint(action.ID, "", []byte(fmt.Sprintf("waiting until %s", wakeAt.Format(time.RFC3339))), streamlogger.LogMessageType); err != nil {
+		h.logger.Error("failed to log wait", "execID", execID, "actionID", action.ID, "error", err)
+	}
+
+	return &WaitError{ActionID: action.ID, WakeAt: wakeAt}
+}
+
+// parkExecution queues the execution again to resume from the wait action once it is over. Jobs
+// are scheduled with minute precision, so the job is due at the first minute after the wake time.
+func (h *FlowExecutionHandler) parkExecution(ctx context.Context, execID string, payload FlowExecutionPayload, waitErr *WaitError) error {
+	if h.taskQueuer == nil {
+		return errors.New("no task queuer to resume the execution after the wait")
+	}
+
+	idx := -1
+	for i, action := range payload.Workflow.Actions {
+		if action.ID == waitErr.ActionID {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return fmt.Errorf("action %s not found in flow %s", waitErr.ActionID, payload.Workflow.Meta.ID)
+	}
+
+	payload.StartingActionIdx = idx
+	payload.Resumed = true
+	payload.WakeAt = waitErr.WakeAt
+	payload.ScheduleID = 0
+	payload.FiredAt = time.Time{}
+
+	dueAt := waitErr.WakeAt.Truncate(time.Minute)
+	if dueAt.Before(waitErr.WakeAt) {
+		dueAt = dueAt.Add(time.Minute)
+	}
+	if _, err := h.taskQueuer.QueueScheduledTask(ctx, PayloadTypeFlowExecution, execID, payload, dueAt); err != nil {
+		return fmt.Errorf("could not queue exec %s to resume after the wait: %w", execID, err)
+	}
+
+	return h.setStatus(ctx, execID, repo.ExecutionStatusPending, payload.NamespaceID, nil)
+}
@@ -0,0 +1,103 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/cvhariharan/flowctl/internal/repo"
+	"github.com/google/uuid"
+)
+
+func TestWaitUntil(t *testing.T) {
+	now := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	env := map[string]any{
+		"inputs": map[string]any{"window": "2025-06-01T12:00:00Z"},
+	}
+
+	tests := []struct {
+		name    string
+		with    map[string]any
+		want    time.Time
+		wantErr bool
+	}{
+		{"duration", map[string]any{"duration": "15m"}, now.Add(15 * time.Minute), false},
+		{"timestamp", map[string]any{"until": "2025-06-02T08:30:00Z"}, time.Date(2025, 6, 2, 8, 30, 0, 0, time.UTC), false},
+		{"expression", map[string]any{"until": "{{ inputs.window }}"}, time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC), false},
+		{"neither", map[string]any{}, time.Time{}, true},
+		{"both", map[string]any{"duration": "1h", "until": "2025-06-02T08:30:00Z"}, time.Time{}, true},
+		{"negative duration", map[string]any{"duration": "-5m"}, time.Time{}, true},
+		{"invalid timestamp", map[string]any{"until": "tomorrow"}, time.Time{}, true},
+		{"expression not a time", map[string]any{"until": "{{ 42 }}"}, time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := WaitUntil(tt.with, env, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WaitUntil() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("WaitUntil() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// outputsStore returns the outputs recorded for an execution
+type outputsStore struct {
+	repo.Store
+	rows []repo.ListExecutionOutputsRow
+}
+
+func (s *outputsStore) ListExecutionOutputs(ctx context.Context, arg repo.ListExecutionOutputsParams) ([]repo.ListExecutionOutputsRow, error) {
+	return s.rows, nil
+}
+
+func TestRestoreOutputs_AcrossWait(t *testing.T) {
+	actions := []Action{
+		{ID: "build"},
+		{ID: "pause", Executor: ExecutorWait, With: map[string]any{"duration": "1h"}},
+		{ID: "deploy", Variables: []Variable{{"image": "{{ outputs.image }}"}, {"host": "{{ outputs.web1.addr }}"}}},
+	}
+
+	record := func(actionID string, results map[string]string) repo.ListExecutionOutputsRow {
+		data, err := json.Marshal(results)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return repo.ListExecutionOutputsRow{ActionID: actionID, Outputs: data}
+	}
+	h := &FlowExecutionHandler{
+		store: &outputsStore{rows: []repo.ListExecutionOutputsRow{
+			record("build", map[string]string{"image": "app:1.2.0", "addr@web1": "10.0.0.5"}),
+			// Recorded by an earlier attempt, deploy runs again after the wait
+			record("deploy", map[string]string{"image": "app:1.1.0"}),
+		}},
+		logger: slog.New(slog.DiscardHandler),
+	}
+
+	// The execution was parked at the wait and resumes from it once it is over
+	payload := FlowExecutionPayload{
+		Workflow:          Flow{Actions: actions},
+		StartingActionIdx: 1,
+		Resumed:           true,
+		NamespaceID:       uuid.NewString(),
+	}
+
+	outputs := make(map[string]any)
+	h.restoreOutputs(context.Background(), "exec-1", payload.NamespaceID, payload.Workflow.Actions[:payload.StartingActionIdx], outputs)
+
+	vars, err := h.interpolateVariables(actions[2], nil, nil, nil, outputs)
+	if err != nil {
+		t.Fatalf("interpolateVariables() error = %v", err)
+	}
+	if vars["image"] != "app:1.2.0" {
+		t.Errorf("image = %v, want the output of build from before the wait", vars["image"])
+	}
+	if vars["host"] != "10.0.0.5" {
+		t.Errorf("host = %v, want the node output of build from before the wait", vars["host"])
+	}
+}
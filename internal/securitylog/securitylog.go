@@ -0,0 +1,156 @@
+package securitylog
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Types of security events
+const (
+	TypeLogin            = "auth.login"
+	TypeLogout           = "auth.logout"
+	TypeAPITokenRejected = "auth.api_token_rejected"
+	TypeAccessDenied     = "access.denied"
+	TypeCredentialAccess = "credential.access"
+	TypeApprovalDecision = "approval.decision"
+	TypeExecutionResume  = "execution.resume"
+)
+
+// Outcomes of security events
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+// queueSize is the number of events that can wait to be written before new events are dropped
+const queueSize = 1024
+
+// Event is a security relevant event. It is written to the sink as a single JSON line.
+type Event struct {
+	Time      time.Time      `json:"time"`
+	Type      string         `json:"type"`
+	Outcome   string         `json:"outcome"`
+	UserID    string         `json:"user_id,omitempty"`
+	Username  string         `json:"username,omitempty"`
+	SourceIP  string         `json:"source_ip,omitempty"`
+	Namespace string         `json:"namespace,omitempty"`
+	Method    string         `json:"method,omitempty"`
+	Path      string         `json:"path,omitempty"`
+	Reason    string         `json:"reason,omitempty"`
+	Details   map[string]any `json:"details,omitempty"`
+}
+
+type Config struct {
+	Sink          string
+	FilePath      string
+	SyslogNetwork string
+	SyslogAddress string
+	SyslogTag     string
+	HTTPURL       string
+	HTTPHeaders   map[string]string
+	HTTPTimeout   time.Duration
+}
+
+// sink writes encoded events to their destination
+type sink interface {
+	Write(line []byte) error
+	Close() error
+}
+
+// Logger writes security events to a sink in the background so that requests are not slowed
+// down by the sink. A nil Logger discards events.
+type Logger struct {
+	sink   sink
+	events chan Event
+	wg     sync.WaitGroup
+	logger *slog.Logger
+
+	// mu guards closed so that events logged while shutting down are dropped
+	mu     sync.RWMutex
+	closed bool
+}
+
+func New(cfg Config, logger *slog.Logger) (*Logger, error) {
+	var (
+		s   sink
+		err error
+	)
+	switch cfg.Sink {
+	case "file":
+		s, err = newFileSink(cfg.FilePath)
+	case "syslog":
+		s, err = newSyslogSink(cfg.SyslogNetwork, cfg.SyslogAddress, cfg.SyslogTag)
+	case "http":
+		s = newHTTPSink(cfg.HTTPURL, cfg.HTTPHeaders, cfg.HTTPTimeout)
+	default:
+		return nil, fmt.Errorf("unknown security events sink %q", cfg.Sink)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	l := &Logger{
+		sink:   s,
+		events: make(chan Event, queueSize),
+		logger: logger,
+	}
+
+	l.wg.Add(1)
+	go l.run()
+
+	return l, nil
+}
+
+// Log queues the event to be written. If the sink can't keep up, the event is dropped.
+func (l *Logger) Log(e Event) {
+	if l == nil {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.closed {
+		return
+	}
+
+	select {
+	case l.events <- e:
+	default:
+		l.logger.Warn("security event queue is full, dropping event", "type", e.Type)
+	}
+}
+
+// Close writes the queued events and closes the sink
+func (l *Logger) Close() error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	l.closed = true
+	close(l.events)
+	l.mu.Unlock()
+
+	l.wg.Wait()
+	return l.sink.Close()
+}
+
+func (l *Logger) run() {
+	defer l.wg.Done()
+
+	for e := range l.events {
+		line, err := json.Marshal(e)
+		if err != nil {
+			l.logger.Error("could not encode security event", "type", e.Type, "error", err)
+			continue
+		}
+		if err := l.sink.Write(line); err != nil {
+			l.logger.Error("could not write security event", "type", e.Type, "error", err)
+		}
+	}
+}
@@ -0,0 +1,12 @@
+package streamlogger
+
+import "regexp"
+
+// ansiPattern matches ANSI escape sequences: CSI sequences like colors and cursor movement,
+// OSC sequences like window titles and hyperlinks, and two-character escapes.
+var ansiPattern = regexp.MustCompile(`\x1b\[[0-?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)|\x1b[@-Z\\-_]`)
+
+// StripANSI removes ANSI escape sequences from s
+func StripANSI(s string) string {
+	return ansiPattern.ReplaceAllString(s, "")
+}
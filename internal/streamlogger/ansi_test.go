@@ -0,0 +1,24 @@
+package streamlogger
+
+import "testing"
+
+func TestStripANSI(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"plain text", "plain text"},
+		{"\x1b[31mred\x1b[0m", "red"},
+		{"\x1b[1;32mbold green\x1b[m done", "bold green done"},
+		{"\x1b[2K\x1b[1Gprogress", "progress"},
+		{"\x1b]0;title\x07output", "output"},
+		{"\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\", "link"},
+		{"\x1bMreverse index", "reverse index"},
+	}
+
+	for _, test := range tests {
+		if result := StripANSI(test.input); result != test.expected {
+			t.Errorf("StripANSI(%q) = %q, want %q", test.input, result, test.expected)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package streamlogger
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CompressedLogExt is appended to log files once they are compressed
+const CompressedLogExt = ".gz"
+
+// execIDFromFileName returns the exec ID of a log file name (exec-id.N or exec-id.N.gz).
+// An empty string is returned if the name is not a log file name. Hidden files like
+// markers and temporary files are never log files.
+func execIDFromFileName(filename string) string {
+	if strings.HasPrefix(filename, ".") {
+		return ""
+	}
+	filename = strings.TrimSuffix(filename, CompressedLogExt)
+	lastDot := strings.LastIndex(filename, ".")
+	if lastDot == -1 {
+		return ""
+	}
+	return filename[:lastDot]
+}
+
+// gzipReadCloser closes both the gzip reader and the underlying reader
+type gzipReadCloser struct {
+	*gzip.Reader
+	underlying io.Closer
+}
+
+func (g *gzipReadCloser) Close() error {
+	err := g.Reader.Close()
+	if cerr := g.underlying.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// decompressReader wraps rc with a gzip reader if name is a compressed log file.
+// Closing the returned reader closes rc.
+func decompressReader(name string, rc io.ReadCloser) (io.ReadCloser, error) {
+	if !strings.HasSuffix(name, CompressedLogExt) {
+		return rc, nil
+	}
+
+	gz, err := gzip.NewReader(rc)
+	if err != nil {
+		rc.Close()
+		return nil, fmt.Errorf("failed to read compressed log %s: %w", name, err)
+	}
+
+	return &gzipReadCloser{Reader: gz, underlying: rc}, nil
+}
+
+// openLogFile opens a log file, decompressing it transparently if needed
+func openLogFile(path string) (io.ReadCloser, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return decompressReader(path, file)
+}
+
+// compressFile gzips path to path.gz and removes the original.
+// The archive is written under a temporary name first so readers never see a partial file.
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	// The temporary name doesn't start with the exec ID, so it is never picked up as a log file
+	tmpPath := filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+CompressedLogExt+".tmp")
+	dst, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		dst.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		dst.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	if err := os.Rename(tmpPath, path+CompressedLogExt); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	return os.Remove(path)
+}
@@ -23,8 +23,55 @@ import (
 
 const FileSyncInterval = 100 * time.Millisecond
 
+const (
+	// fileLoggerQueueSize is the number of encoded messages a FileLogger queues for its sync goroutine.
+	// Checkpoint blocks once the queue is full.
+	fileLoggerQueueSize = 1024
+	// fileFlushSize is the buffered size after which messages are written to the file without
+	// waiting for the next sync interval
+	fileFlushSize = 256 * 1024
+	// maxPooledMessageSize is the capacity above which encoded message buffers aren't reused
+	maxPooledMessageSize = 64 * 1024
+)
+
+// encodedMessage is a JSON encoded StreamMessage followed by a newline
+type encodedMessage struct {
+	bytes.Buffer
+	enc *json.Encoder
+}
+
+var encodedMessagePool = sync.Pool{
+	New: func() any {
+		m := &encodedMessage{}
+		m.enc = json.NewEncoder(&m.Buffer)
+		return m
+	},
+}
+
+// encodeMessage encodes sm into a pooled buffer, it has to be released with putEncodedMessage
+func encodeMessage(sm StreamMessage) (*encodedMessage, error) {
+	m := encodedMessagePool.Get().(*encodedMessage)
+	m.Reset()
+	if err := m.enc.Encode(sm); err != nil {
+		putEncodedMessage(m)
+		return nil, err
+	}
+	return m, nil
+}
+
+func putEncodedMessage(m *encodedMessage) {
+	if m.Cap() > maxPooledMessageSize {
+		return
+	}
+	encodedMessagePool.Put(m)
+}
+
+// LogTruncatedMessage is logged once when an execution exceeds its log size limit
+const LogTruncatedMessage = "\n[log truncated: execution exceeded the log size limit of %d bytes, further output is dropped]\n"
+
 // extractFileIndex extracts the numeric index from a log filename
 func extractFileIndex(filename string) int {
+	filename = strings.TrimSuffix(filename, CompressedLogExt)
 	lastDot := strings.LastIndex(filename, ".")
 	if lastDot == -1 {
 		return 0
@@ -54,6 +101,18 @@ type FileLogManagerCfg struct {
 
 	// LogDir stores the log files created by the FileLogger
 	LogDir string
+
+	// Compress gzips the log files of a FileLogger once it is closed
+	Compress bool
+
+	// Shared is set when LogDir is shared between server replicas, e.g. a network file system.
+	// Loggers mark their executions as running in LogDir so that the logs can be streamed live
+	// from any replica.
+	Shared bool
+
+	// MaxExecutionLogBytes limits the total size of the logs of a single execution.
+	// Log messages beyond this are dropped, results and errors are still written. 0 is unlimited
+	MaxExecutionLogBytes int64
 }
 
 type FileLogManager struct {
@@ -87,7 +146,26 @@ func NewFileLogManager(cfg FileLogManagerCfg) LogManager {
 // NewLogger creates a new FileLogger.
 // This is used by the task handler to create a new logger for each flow execution.
 func (f *FileLogManager) NewLogger(id string) (Logger, error) {
-	fl, err := newFileLogger(id, f.cfg.LogDir, FileSyncInterval, f.cfg.MaxSizeBytes)
+	return f.newLogger(id, nil)
+}
+
+// newLogger creates and tracks a FileLogger. onRotate, if set, is called with the path
+// of every log file that is closed because of rotation.
+func (f *FileLogManager) newLogger(id string, onRotate func(path string)) (*FileLogger, error) {
+	var onClose func(execID string)
+	if f.cfg.Compress {
+		onClose = func(execID string) {
+			go f.compressLogs(execID)
+		}
+	}
+
+	fl, err := newFileLoggerWithOpts(id, f.cfg.LogDir, FileSyncInterval, fileLoggerOpts{
+		maxSize:      f.cfg.MaxSizeBytes,
+		maxTotalSize: f.cfg.MaxExecutionLogBytes,
+		onRotate:     onRotate,
+		onClose:      onClose,
+		shared:       f.cfg.Shared,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -106,7 +184,7 @@ func (f *FileLogManager) LoggerExists(execID string) bool {
 	f.loggerMut.RUnlock()
 
 	if !exists {
-		return false
+		return f.sharedLoggerActive(execID)
 	}
 
 	if fl, ok := logger.(*FileLogger); ok {
@@ -140,6 +218,8 @@ func (f *FileLogManager) StreamLogs(ctx context.Context, execID string, actionRe
 				} else {
 					err = f.streamAllLogs(ctx, execID, actionRetries, logCh)
 				}
+			} else if f.sharedLoggerActive(execID) {
+				err = f.followSharedLogs(ctx, execID, actionRetries, logCh)
 			} else {
 				err = f.streamAllLogs(ctx, execID, actionRetries, logCh)
 			}
@@ -161,13 +241,24 @@ func (f *FileLogManager) getLogFiles(execID string) ([]string, error) {
 	}
 
 	prefix := execID + "."
-	var logFiles []string
+	names := make(map[string]struct{})
 	for _, entry := range entries {
 		if !entry.IsDir() && strings.HasPrefix(entry.Name(), prefix) {
-			logFiles = append(logFiles, entry.Name())
+			names[entry.Name()] = struct{}{}
 		}
 	}
 
+	var logFiles []string
+	for name := range names {
+		// Both files exist for a moment while a file is being compressed
+		if plain, ok := strings.CutSuffix(name, CompressedLogExt); ok {
+			if _, exists := names[plain]; exists {
+				continue
+			}
+		}
+		logFiles = append(logFiles, name)
+	}
+
 	sort.Slice(logFiles, func(i, j int) bool {
 		return extractFileIndex(logFiles[i]) < extractFileIndex(logFiles[j])
 	})
@@ -232,15 +323,21 @@ func (f *FileLogManager) streamRealtimeLogs(ctx context.Context, execID string,
 	return f.followActiveFile(ctx, activeFilePath, fl.syncCh, actionRetries, logCh)
 }
 
-// streamFromFile reads all lines from a file and filters by retry attempt
+// streamFromFile reads all lines from a file and filters by retry attempt.
+// Compressed files are decompressed transparently.
 func (f *FileLogManager) streamFromFile(ctx context.Context, filePath string, actionRetries map[string]int32, logCh chan<- string) error {
-	file, err := os.Open(filePath)
+	file, err := openLogFile(filePath)
 	if err != nil {
 		return err
 	}
 	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
+	return f.streamFromReader(ctx, file, actionRetries, logCh)
+}
+
+// streamFromReader reads all lines from r and filters by retry attempt
+func (f *FileLogManager) streamFromReader(ctx context.Context, r io.Reader, actionRetries map[string]int32, logCh chan<- string) error {
+	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		select {
 		case <-ctx.Done():
@@ -335,7 +432,7 @@ func (f *FileLogManager) GetRawLogs(ctx context.Context, execID string, w io.Wri
 		case <-ctx.Done():
 			return ctx.Err()
 		default:
-			file, err := os.Open(filepath.Join(f.cfg.LogDir, filename))
+			file, err := openLogFile(filepath.Join(f.cfg.LogDir, filename))
 			if err != nil {
 				return fmt.Errorf("failed to open log file %s: %w", filename, err)
 			}
@@ -350,6 +447,34 @@ func (f *FileLogManager) GetRawLogs(ctx context.Context, execID string, w io.Wri
 	return nil
 }
 
+// DeleteLogs removes all log files of the given execID.
+// Returns an error if the logger for this execID is still active (execution still running).
+func (f *FileLogManager) DeleteLogs(ctx context.Context, execID string) error {
+	if f.LoggerExists(execID) {
+		return fmt.Errorf("execution %s is still running", execID)
+	}
+
+	logFiles, err := f.getLogFiles(execID)
+	if err != nil {
+		return err
+	}
+
+	for _, filename := range logFiles {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := os.Remove(filepath.Join(f.cfg.LogDir, filename)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove log file %s: %w", filename, err)
+		}
+	}
+
+	f.loggerMut.Lock()
+	delete(f.loggers, execID)
+	f.loggerMut.Unlock()
+
+	return nil
+}
+
 // Run starts the scan loop.
 // This is a blocking call and should be run from a goroutine.
 func (f *FileLogManager) Run(ctx context.Context, l *slog.Logger) error {
@@ -407,20 +532,38 @@ func (f *FileLogManager) run(ctx context.Context, l *slog.Logger) error {
 	return nil
 }
 
-// isFileInUse checks if a file belongs to an active (not closed) logger
+// compressLogs gzips the uncompressed log files of a closed logger
+func (f *FileLogManager) compressLogs(execID string) {
+	logFiles, err := f.getLogFiles(execID)
+	if err != nil {
+		log.Printf("could not compress logs for exec %s: %v", execID, err)
+		return
+	}
+
+	for _, filename := range logFiles {
+		if strings.HasSuffix(filename, CompressedLogExt) {
+			continue
+		}
+		if err := compressFile(filepath.Join(f.cfg.LogDir, filename)); err != nil {
+			log.Printf("could not compress log file %s: %v", filename, err)
+		}
+	}
+}
+
+// isFileInUse checks if a file belongs to an active (not closed) logger on this server
+// or, with a shared log directory, on another one
 func (f *FileLogManager) isFileInUse(filename string) bool {
 	f.loggerMut.RLock()
 	defer f.loggerMut.RUnlock()
 
-	lastDot := strings.LastIndex(filename, ".")
-	if lastDot == -1 {
+	execID := execIDFromFileName(filename)
+	if execID == "" {
 		return false
 	}
-	execID := filename[:lastDot]
 
 	logger, exists := f.loggers[execID]
 	if !exists {
-		return false
+		return f.sharedLoggerActive(execID)
 	}
 
 	if fl, ok := logger.(*FileLogger); ok {
@@ -453,34 +596,79 @@ type FileLogger struct {
 	actionID atomic.Value
 	// Retry is the retry count for the current action
 	Retry atomic.Int32
-	// buffer stores the messages from executions
-	buffer    *bytes.Buffer
-	bufferMut sync.RWMutex
+	// buffer stores the messages from executions until they are written to the file.
+	// It is only used by the sync goroutine
+	buffer *bytes.Buffer
+	// msgCh queues encoded messages from Checkpoint for the sync goroutine
+	msgCh chan *encodedMessage
 	// logDirPath is the directory where all log files will be stored
 	logDirPath string
 	// flushTicker is used to periodically write values from buffer to file
 	flushTicker *time.Ticker
-	// syncCh is used to track if the logger is closed
+	// stopCh is closed by Close to stop the sync goroutine
+	stopCh chan struct{}
+	// closeMu guards closed. Checkpoint holds it for reading while queueing a message, so once
+	// Close has set closed no message can be queued after the sync goroutine drains msgCh.
+	closeMu sync.RWMutex
+	closed  bool
+	// syncCh is closed once the sync goroutine has written all messages, it is used to track if the logger is closed
 	syncCh  chan struct{}
 	runOnce sync.Once
 	// writtenCount is used to track the written bytes by this logger
 	writtenCount atomic.Int64
 	// maxSize is the max file size in bytes before it is rotated
 	maxSize int64
+	// maxTotalSize is the max size in bytes of all log messages across files
+	maxTotalSize int64
+	// totalSize tracks the bytes checkpointed by this logger across files
+	totalSize atomic.Int64
+	// truncated is set once log messages are dropped because of maxTotalSize
+	truncated atomic.Bool
+	// lastTimestamp is the unix nano timestamp of the last message
+	lastTimestamp atomic.Int64
 	// nextFileIndex is the file index for the next file after rotation
 	nextFileIndex atomic.Int32
 	// currentFile is the current open log file
 	currentFile atomic.Pointer[os.File]
+	// onRotate is called with the path of the previous file after rotation
+	onRotate func(path string)
+	// onClose is called once the logger and its current file are closed
+	onClose func(execID string)
+	// marker is the path of the file that marks the execution as running in a shared log directory
+	marker string
+}
+
+// fileLoggerOpts are the optional settings of a FileLogger
+type fileLoggerOpts struct {
+	maxSize      int64
+	maxTotalSize int64
+	onRotate     func(path string)
+	onClose      func(execID string)
+	// shared creates a marker file for the execution while the logger is open
+	shared bool
 }
 
 func newFileLogger(execID string, logDirPath string, syncInterval time.Duration, maxSize int64) (Logger, error) {
+	fl, err := newFileLoggerWithOpts(execID, logDirPath, syncInterval, fileLoggerOpts{maxSize: maxSize})
+	if err != nil {
+		return nil, err
+	}
+	return fl, nil
+}
+
+func newFileLoggerWithOpts(execID string, logDirPath string, syncInterval time.Duration, opts fileLoggerOpts) (*FileLogger, error) {
 	fl := &FileLogger{
-		ExecID:      execID,
-		logDirPath:  logDirPath,
-		flushTicker: time.NewTicker(syncInterval),
-		syncCh:      make(chan struct{}),
-		buffer:      new(bytes.Buffer),
-		maxSize:     maxSize,
+		ExecID:       execID,
+		logDirPath:   logDirPath,
+		flushTicker:  time.NewTicker(syncInterval),
+		stopCh:       make(chan struct{}),
+		syncCh:       make(chan struct{}),
+		buffer:       new(bytes.Buffer),
+		msgCh:        make(chan *encodedMessage, fileLoggerQueueSize),
+		maxSize:      opts.maxSize,
+		maxTotalSize: opts.maxTotalSize,
+		onRotate:     opts.onRotate,
+		onClose:      opts.onClose,
 	}
 
 	fl.actionID.Store("")
@@ -489,6 +677,14 @@ func newFileLogger(execID string, logDirPath string, syncInterval time.Duration,
 		return nil, err
 	}
 
+	if opts.shared {
+		fl.marker = activeMarkerPath(logDirPath, execID)
+		if err := os.WriteFile(fl.marker, nil, 0644); err != nil {
+			fl.currentFile.Load().Close()
+			return nil, fmt.Errorf("could not create log marker for exec=%s: %w", execID, err)
+		}
+	}
+
 	go fl.sync()
 
 	return fl, nil
@@ -503,6 +699,13 @@ func (fl *FileLogger) IsClosed() bool {
 	}
 }
 
+// isClosing reports whether Close has been called, the logger may still be flushing
+func (fl *FileLogger) isClosing() bool {
+	fl.closeMu.RLock()
+	defer fl.closeMu.RUnlock()
+	return fl.closed
+}
+
 // rotateFile creates a new file with the next file index and swaps the current file pointer
 func (fl *FileLogger) rotateFile() error {
 	f, err := os.OpenFile(filepath.Join(fl.logDirPath, fmt.Sprintf("%s.%d", fl.ExecID, fl.nextFileIndex.Load())), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
@@ -512,20 +715,40 @@ func (fl *FileLogger) rotateFile() error {
 	fl.nextFileIndex.Add(1)
 	if old := fl.currentFile.Swap(f); old != nil {
 		old.Close()
+		if fl.onRotate != nil {
+			fl.onRotate(old.Name())
+		}
 	}
 	return nil
 }
 
 // Close flushes the buffer and closes the logger and file
 func (fl *FileLogger) Close() error {
+	var closed bool
 	fl.runOnce.Do(func() {
 		fl.flushTicker.Stop()
-		// Flush any pending data to file
-		fl.filesync()
-		close(fl.syncCh)
+		// Stop accepting messages before the sync goroutine drains the queue
+		fl.closeMu.Lock()
+		fl.closed = true
+		fl.closeMu.Unlock()
+		// Wait for the sync goroutine to flush any pending data to file
+		close(fl.stopCh)
+		<-fl.syncCh
+		closed = true
 	})
 	f := fl.currentFile.Load()
-	return f.Close()
+	err := f.Close()
+
+	if closed && fl.marker != "" {
+		if rmErr := os.Remove(fl.marker); rmErr != nil && !os.IsNotExist(rmErr) {
+			log.Printf("could not remove log marker for exec %s: %v", fl.ExecID, rmErr)
+		}
+	}
+
+	if closed && fl.onClose != nil {
+		fl.onClose(fl.ExecID)
+	}
+	return err
 }
 
 // GetID returns the exec ID
@@ -560,7 +783,7 @@ func (fl *FileLogger) Checkpoint(id string, nodeID string, val interface{}, mtyp
 		sm.ActionID = id
 	}
 	sm.NodeID = nodeID
-	sm.Timestamp = time.Now().Format(time.RFC3339)
+	sm.Timestamp = fl.nextTimestamp().Format(time.RFC3339Nano)
 	sm.Retry = fl.Retry.Load()
 	switch mtype {
 	case ErrMessageType:
@@ -596,41 +819,122 @@ func (fl *FileLogger) Checkpoint(id string, nodeID string, val interface{}, mtyp
 		}
 		sm.MType = CancelledMessageType
 		sm.Val = e
+	case ProgressMessageType:
+		p, ok := val.(ProgressEvent)
+		if !ok {
+			return fmt.Errorf("expected ProgressEvent type for progress got %T in stream checkpoint", val)
+		}
+		data, err := json.Marshal(p)
+		if err != nil {
+			return fmt.Errorf("could not marshal progress event in stream message %s: %w", id, err)
+		}
+		sm.MType = ProgressMessageType
+		sm.Val = string(data)
 	}
 
-	msgBytes, err := json.Marshal(sm)
+	if fl.isClosing() {
+		return fmt.Errorf("logger has been closed")
+	}
+
+	msg, err := encodeMessage(sm)
 	if err != nil {
 		return fmt.Errorf("could not marshal stream message: %w", err)
 	}
 
-	if fl.IsClosed() {
+	// Drop log messages once the execution exceeds its log quota. The first dropped message
+	// is replaced with a marker so the truncation is visible in the logs.
+	if mtype == LogMessageType && fl.maxTotalSize > 0 && fl.totalSize.Load()+int64(msg.Len()) > fl.maxTotalSize {
+		putEncodedMessage(msg)
+		if !fl.truncated.CompareAndSwap(false, true) {
+			return nil
+		}
+
+		sm.Val = fmt.Sprintf(LogTruncatedMessage, fl.maxTotalSize)
+		msg, err = encodeMessage(sm)
+		if err != nil {
+			return fmt.Errorf("could not marshal stream message: %w", err)
+		}
+	}
+
+	// The sync goroutine keeps draining msgCh until Close has set closed, so the send can't
+	// block forever while the lock is held
+	fl.closeMu.RLock()
+	defer fl.closeMu.RUnlock()
+	if fl.closed {
+		putEncodedMessage(msg)
 		return fmt.Errorf("logger has been closed")
 	}
-	fl.bufferMut.Lock()
-	defer fl.bufferMut.Unlock()
-	_, err = fl.buffer.Write(msgBytes)
-	if err != nil {
-		return err
+	// msg goes back to the pool once it is written, so its size is read before it is queued
+	size := int64(msg.Len())
+	fl.msgCh <- msg
+	fl.totalSize.Add(size)
+	return nil
+}
+
+// nextTimestamp returns the current UTC time, moved forward if needed so that it is strictly
+// after the previous message. This keeps messages from concurrent nodes in write order
+// when sorted by timestamp, even if the wall clock goes backwards.
+func (fl *FileLogger) nextTimestamp() time.Time {
+	for {
+		last := fl.lastTimestamp.Load()
+		now := time.Now().UnixNano()
+		if now <= last {
+			now = last + 1
+		}
+		if fl.lastTimestamp.CompareAndSwap(last, now) {
+			return time.Unix(0, now).UTC()
+		}
 	}
-	_, err = fl.buffer.Write([]byte("\n"))
-	return err
 }
 
-// sync uses the flushticker to sync buffer with file
-func (fl *FileLogger) sync() error {
+// sync collects queued messages in the buffer and uses the flushticker to sync buffer with file.
+// The buffer is also written once it exceeds fileFlushSize so that busy loggers append in batches.
+// In a shared log directory, it also keeps the marker file fresh.
+func (fl *FileLogger) sync() {
+	defer close(fl.syncCh)
+
+	var heartbeat <-chan time.Time
+	if fl.marker != "" {
+		heartbeatTicker := time.NewTicker(SharedLogHeartbeat)
+		defer heartbeatTicker.Stop()
+		heartbeat = heartbeatTicker.C
+	}
+
 	for {
 		select {
-		case <-fl.syncCh:
-			return nil
+		case <-fl.stopCh:
+			// Write the messages that were queued before the logger was closed
+			for {
+				select {
+				case msg := <-fl.msgCh:
+					fl.buffer.Write(msg.Bytes())
+					putEncodedMessage(msg)
+				default:
+					fl.filesync()
+					return
+				}
+			}
+		case msg := <-fl.msgCh:
+			fl.buffer.Write(msg.Bytes())
+			putEncodedMessage(msg)
+			if fl.buffer.Len() >= fileFlushSize {
+				fl.filesync()
+			}
 		case <-fl.flushTicker.C:
 			fl.filesync()
+		case now := <-heartbeat:
+			// Chtimes doesn't recreate the marker if Close removed it in the meantime
+			if err := os.Chtimes(fl.marker, now, now); err != nil {
+				log.Printf("could not refresh log marker for exec %s: %v", fl.ExecID, err)
+			}
 		}
 	}
 }
 
-// filesync copies the contents from buffer to the current logger file
+// filesync copies the contents from buffer to the current logger file.
+// It must only be called from the sync goroutine.
 func (fl *FileLogger) filesync() error {
-	// Check if rotation is needed before acquiring any locks
+	// Check if rotation is needed before writing
 	if fl.maxSize > 0 && fl.writtenCount.Load() > fl.maxSize {
 		if err := fl.rotateFile(); err != nil {
 			return err
@@ -638,16 +942,18 @@ func (fl *FileLogger) filesync() error {
 		fl.writtenCount.Store(0)
 	}
 
-	// Now copy buffer contents to file
-	fl.bufferMut.Lock()
-	n, err := io.Copy(fl.currentFile.Load(), fl.buffer)
+	// Skip the write and fsync if there is nothing new
+	if fl.buffer.Len() == 0 {
+		return nil
+	}
+
+	// Now copy buffer contents to file in a single append
+	n, err := fl.buffer.WriteTo(fl.currentFile.Load())
 	fl.writtenCount.Add(n)
 	fl.buffer.Reset()
 	if err != nil {
-		fl.bufferMut.Unlock()
 		return err
 	}
-	fl.bufferMut.Unlock()
 
 	return fl.currentFile.Load().Sync()
 }
@@ -3,10 +3,13 @@ package streamlogger
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -189,6 +192,96 @@ func TestFileLogger_InvalidPath(t *testing.T) {
 	}
 }
 
+func TestFileLogger_LogQuota(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	logger, err := newFileLoggerWithOpts("exec-id", tmpDir, 50*time.Millisecond, fileLoggerOpts{maxTotalSize: 300})
+	if err != nil {
+		t.Fatalf("newFileLoggerWithOpts() error = %v", err)
+	}
+
+	for i := 0; i < 10; i++ {
+		if _, err := logger.Write([]byte("some chatty output\n")); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := logger.Checkpoint("action", "", "failed", ErrMessageType); err != nil {
+		t.Fatalf("Checkpoint() error = %v", err)
+	}
+	logger.Close()
+
+	fileData, err := os.ReadFile(filepath.Join(tmpDir, "exec-id.0"))
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+
+	var logs, truncated, errs int
+	for _, line := range strings.Split(strings.TrimSpace(string(fileData)), "\n") {
+		var sm StreamMessage
+		if err := json.Unmarshal([]byte(line), &sm); err != nil {
+			t.Fatalf("Failed to unmarshal stream message: %v", err)
+		}
+		switch {
+		case sm.MType == ErrMessageType:
+			errs++
+		case strings.Contains(sm.Val, "log truncated"):
+			truncated++
+		default:
+			logs++
+		}
+	}
+
+	if logs == 0 || logs >= 10 {
+		t.Errorf("got %d log messages, want some but not all of them", logs)
+	}
+	if truncated != 1 {
+		t.Errorf("got %d truncation markers, want 1", truncated)
+	}
+	if errs != 1 {
+		t.Errorf("got %d error messages, want 1", errs)
+	}
+}
+
+func TestFileLogger_OrderedTimestamps(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	logger, err := newFileLogger("exec-id", tmpDir, 50*time.Millisecond, 0)
+	if err != nil {
+		t.Fatalf("newFileLogger() error = %v", err)
+	}
+
+	for i := 0; i < 100; i++ {
+		if err := logger.Checkpoint("action", "", []byte("line"), LogMessageType); err != nil {
+			t.Fatalf("Checkpoint() error = %v", err)
+		}
+	}
+	logger.Close()
+
+	fileData, err := os.ReadFile(filepath.Join(tmpDir, "exec-id.0"))
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+
+	var last time.Time
+	for _, line := range strings.Split(strings.TrimSpace(string(fileData)), "\n") {
+		var sm StreamMessage
+		if err := json.Unmarshal([]byte(line), &sm); err != nil {
+			t.Fatalf("Failed to unmarshal stream message: %v", err)
+		}
+		ts, err := time.Parse(time.RFC3339Nano, sm.Timestamp)
+		if err != nil {
+			t.Fatalf("invalid timestamp %q: %v", sm.Timestamp, err)
+		}
+		if ts.Location() != time.UTC {
+			t.Errorf("timestamp %q is not in UTC", sm.Timestamp)
+		}
+		if !ts.After(last) {
+			t.Errorf("timestamp %q is not after the previous one %q", sm.Timestamp, last.Format(time.RFC3339Nano))
+		}
+		last = ts
+	}
+}
+
 func TestFileLogger_IsClosed(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -223,6 +316,80 @@ func TestFileLogger_IsClosed(t *testing.T) {
 	}
 }
 
+// TestFileLogger_CloseFlushesAcceptedMessages closes the logger while writers are still
+// checkpointing and checks that every accepted message is written, in the order it was accepted
+func TestFileLogger_CloseFlushesAcceptedMessages(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// A long sync interval leaves the messages in the queue and buffer until Close
+	logger, err := newFileLogger("exec-id", tmpDir, time.Hour, 0)
+	if err != nil {
+		t.Fatalf("newFileLogger() error = %v", err)
+	}
+
+	const writers = 8
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		accepted = make(map[string][]int)
+	)
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			actionID := fmt.Sprintf("writer-%d", w)
+			for i := 0; ; i++ {
+				if err := logger.Checkpoint(actionID, "", []byte(strconv.Itoa(i)), LogMessageType); err != nil {
+					return
+				}
+				mu.Lock()
+				accepted[actionID] = append(accepted[actionID], i)
+				mu.Unlock()
+			}
+		}(w)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	wg.Wait()
+
+	if err := logger.Checkpoint("writer-0", "", []byte("late"), LogMessageType); err == nil {
+		t.Error("Checkpoint() after Close() should return error")
+	}
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, "exec-id.0"))
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+
+	written := make(map[string][]int)
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var sm StreamMessage
+		if err := json.Unmarshal([]byte(line), &sm); err != nil {
+			t.Fatalf("Failed to unmarshal stream message: %v", err)
+		}
+		n, err := strconv.Atoi(sm.Val)
+		if err != nil {
+			t.Fatalf("unexpected message value %q", sm.Val)
+		}
+		written[sm.ActionID] = append(written[sm.ActionID], n)
+	}
+
+	for actionID, want := range accepted {
+		got := written[actionID]
+		if len(got) != len(want) {
+			t.Fatalf("%s: wrote %d messages, accepted %d", actionID, len(got), len(want))
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("%s: message %d = %d, want %d", actionID, i, got[i], want[i])
+			}
+		}
+	}
+}
+
 func TestFileLogger_FileRotation(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -490,6 +657,90 @@ func TestFileLogManager_StreamLogs_ArchivedOnly(t *testing.T) {
 	}
 }
 
+func TestFileLogManager_CompressOnClose(t *testing.T) {
+	tmpDir := t.TempDir()
+	execID := "test-exec-compress"
+
+	cfg := FileLogManagerCfg{
+		LogDir:       tmpDir,
+		ScanInterval: 1 * time.Hour,
+		MaxSizeBytes: 10,
+		Compress:     true,
+	}
+
+	manager := NewFileLogManager(cfg).(*FileLogManager)
+
+	logger, err := manager.NewLogger(execID)
+	if err != nil {
+		t.Fatalf("NewLogger() error = %v", err)
+	}
+
+	data1 := "line1\nline2\n"
+	data2 := "line3\nline4\n"
+
+	logger.Write([]byte(data1))
+	time.Sleep(100 * time.Millisecond)
+	logger.Write([]byte(data2))
+	time.Sleep(100 * time.Millisecond)
+
+	logger.Close()
+
+	// Compression runs in the background after close
+	deadline := time.Now().Add(2 * time.Second)
+	var logFiles []string
+	for time.Now().Before(deadline) {
+		logFiles, err = manager.getLogFiles(execID)
+		if err != nil {
+			t.Fatalf("getLogFiles() error = %v", err)
+		}
+		compressed := len(logFiles) > 0
+		for _, f := range logFiles {
+			if !strings.HasSuffix(f, CompressedLogExt) {
+				compressed = false
+			}
+		}
+		if compressed {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	if len(logFiles) < 2 {
+		t.Fatalf("Expected rotated log files, got %v", logFiles)
+	}
+	for _, f := range logFiles {
+		if !strings.HasSuffix(f, CompressedLogExt) {
+			t.Fatalf("log file %s was not compressed", f)
+		}
+	}
+
+	logCh, err := manager.StreamLogs(context.Background(), execID, make(map[string]int32))
+	if err != nil {
+		t.Fatalf("StreamLogs() error = %v", err)
+	}
+
+	var values []string
+	for jsonMsg := range logCh {
+		var sm StreamMessage
+		if err := json.Unmarshal([]byte(jsonMsg), &sm); err != nil {
+			t.Fatalf("Failed to unmarshal JSON message: %v", err)
+		}
+		values = append(values, sm.Val)
+	}
+
+	if len(values) != 2 || values[0] != data1 || values[1] != data2 {
+		t.Errorf("StreamLogs() values = %q, want %q", values, []string{data1, data2})
+	}
+
+	var raw strings.Builder
+	if err := manager.GetRawLogs(context.Background(), execID, &raw); err != nil {
+		t.Fatalf("GetRawLogs() error = %v", err)
+	}
+	if strings.Count(raw.String(), "\n") != 2 {
+		t.Errorf("GetRawLogs() = %q, want 2 decompressed lines", raw.String())
+	}
+}
+
 func TestFileLogManager_StreamLogs_ActiveLogger(t *testing.T) {
 	tmpDir := t.TempDir()
 	execID := "test-exec-active"
@@ -733,11 +984,12 @@ func TestExtractFileIndex(t *testing.T) {
 		{"exec-123.0", 0},
 		{"exec-123.1", 1},
 		{"exec-123.42", 42},
-		{"exec-123", 0},     // No dot
-		{"exec-123.abc", 0}, // Invalid number
-		{"exec-123.", 0},    // Empty after dot
-		{".123", 123},       // Dot at start
-		{"", 0},             // Empty string
+		{"exec-123", 0},      // No dot
+		{"exec-123.abc", 0},  // Invalid number
+		{"exec-123.", 0},     // Empty after dot
+		{".123", 123},        // Dot at start
+		{"exec-123.7.gz", 7}, // Compressed
+		{"", 0},              // Empty string
 	}
 
 	for _, test := range tests {
@@ -747,3 +999,150 @@ func TestExtractFileIndex(t *testing.T) {
 		}
 	}
 }
+
+func TestFileLogManager_StreamLogs_SharedLogDir(t *testing.T) {
+	tmpDir := t.TempDir()
+	execID := "test-exec-shared"
+
+	cfg := FileLogManagerCfg{
+		LogDir:       tmpDir,
+		ScanInterval: 1 * time.Hour,
+		MaxSizeBytes: 5,
+		Shared:       true,
+	}
+
+	// Two managers sharing a log directory act as two server replicas
+	writer := NewFileLogManager(cfg).(*FileLogManager)
+	reader := NewFileLogManager(cfg).(*FileLogManager)
+
+	logger, err := writer.NewLogger(execID)
+	if err != nil {
+		t.Fatalf("NewLogger() error = %v", err)
+	}
+
+	if !reader.LoggerExists(execID) {
+		t.Fatal("LoggerExists() = false on another replica for active logger")
+	}
+	if !reader.isFileInUse(execID + ".0") {
+		t.Error("isFileInUse() = false on another replica for active logger")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	logCh, err := reader.StreamLogs(ctx, execID, make(map[string]int32))
+	if err != nil {
+		t.Fatalf("StreamLogs() error = %v", err)
+	}
+
+	writes := []string{"AAAAAAA\n", "BBBBBBB\n", "CCCCCCC\n"}
+	go func() {
+		for _, data := range writes {
+			logger.Write([]byte(data))
+			time.Sleep(120 * time.Millisecond)
+		}
+		logger.Close()
+	}()
+
+	var values []string
+	for msg := range logCh {
+		var sm StreamMessage
+		if err := json.Unmarshal([]byte(msg), &sm); err != nil {
+			t.Fatalf("Failed to unmarshal stream message: %v", err)
+		}
+		values = append(values, sm.Val)
+	}
+
+	if ctx.Err() != nil {
+		t.Fatal("StreamLogs() did not finish after the logger was closed")
+	}
+	if strings.Join(values, "") != strings.Join(writes, "") {
+		t.Errorf("streamed %q, want %q", values, writes)
+	}
+	if reader.LoggerExists(execID) {
+		t.Error("LoggerExists() = true on another replica after close")
+	}
+}
+
+func TestFileLogManager_DeleteLogs(t *testing.T) {
+	tmpDir := t.TempDir()
+	manager := NewFileLogManager(FileLogManagerCfg{LogDir: tmpDir}).(*FileLogManager)
+	execID := "exec-delete"
+
+	logger, err := manager.NewLogger(execID)
+	if err != nil {
+		t.Fatalf("NewLogger() error = %v", err)
+	}
+	logger.Write([]byte("test data\n"))
+
+	if err := manager.DeleteLogs(context.Background(), execID); err == nil {
+		t.Error("DeleteLogs() error = nil for active logger")
+	}
+
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	other, err := manager.NewLogger("exec-keep")
+	if err != nil {
+		t.Fatalf("NewLogger() error = %v", err)
+	}
+	other.Write([]byte("test data\n"))
+	other.Close()
+
+	if err := manager.DeleteLogs(context.Background(), execID); err != nil {
+		t.Fatalf("DeleteLogs() error = %v", err)
+	}
+
+	files, err := manager.getLogFiles(execID)
+	if err != nil {
+		t.Fatalf("getLogFiles() error = %v", err)
+	}
+	if len(files) != 0 {
+		t.Errorf("log files = %v, want none after DeleteLogs()", files)
+	}
+
+	files, err = manager.getLogFiles("exec-keep")
+	if err != nil {
+		t.Fatalf("getLogFiles() error = %v", err)
+	}
+	if len(files) == 0 {
+		t.Error("DeleteLogs() removed the logs of another execution")
+	}
+}
+
+func BenchmarkFileLogger_Write(b *testing.B) {
+	logger, err := newFileLogger("exec-id", b.TempDir(), FileSyncInterval, 0)
+	if err != nil {
+		b.Fatalf("newFileLogger() error = %v", err)
+	}
+	defer logger.Close()
+
+	line := []byte("2024-01-01 12:00:00 building target //pkg/server:all with 16 workers\n")
+	b.SetBytes(int64(len(line)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		logger.Write(line)
+	}
+}
+
+// BenchmarkFileLogger_WriteParallel simulates many nodes writing to the logger of one execution
+func BenchmarkFileLogger_WriteParallel(b *testing.B) {
+	logger, err := newFileLogger("exec-id", b.TempDir(), FileSyncInterval, 0)
+	if err != nil {
+		b.Fatalf("newFileLogger() error = %v", err)
+	}
+	defer logger.Close()
+
+	line := []byte("2024-01-01 12:00:00 building target //pkg/server:all with 16 workers\n")
+	b.SetBytes(int64(len(line)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		nl := NewNodeContextLogger(logger, "action", "node")
+		for pb.Next() {
+			nl.Write(line)
+		}
+	})
+}
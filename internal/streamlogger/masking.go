@@ -0,0 +1,100 @@
+package streamlogger
+
+import (
+	"sort"
+	"strings"
+)
+
+const (
+	// MaskReplacement replaces secret values in log messages
+	MaskReplacement = "*****"
+
+	// MinMaskLength is the minimum length of a secret value to be masked.
+	// Shorter values would redact unrelated output.
+	MinMaskLength = 4
+)
+
+// MaskingLogger wraps a Logger and replaces secret values in every message
+// before it is passed on to the underlying logger.
+type MaskingLogger struct {
+	Logger
+	replacer *strings.Replacer
+}
+
+// NewMaskingLogger returns a logger that masks the given secret values. Each line of a
+// multi-line secret is masked separately since output can be split on line boundaries.
+// If there is nothing to mask, the logger is returned as is.
+func NewMaskingLogger(logger Logger, secrets []string) Logger {
+	seen := make(map[string]struct{})
+	var values []string
+	add := func(v string) {
+		if len(v) < MinMaskLength {
+			return
+		}
+		if _, ok := seen[v]; ok {
+			return
+		}
+		seen[v] = struct{}{}
+		values = append(values, v)
+	}
+
+	for _, secret := range secrets {
+		add(secret)
+		if strings.Contains(secret, "\n") {
+			for _, line := range strings.Split(secret, "\n") {
+				add(strings.TrimSpace(line))
+			}
+		}
+	}
+
+	if len(values) == 0 {
+		return logger
+	}
+
+	// Replace longer values first so a secret containing another one is fully masked
+	sort.Slice(values, func(i, j int) bool {
+		return len(values[i]) > len(values[j])
+	})
+
+	oldnew := make([]string, 0, len(values)*2)
+	for _, v := range values {
+		oldnew = append(oldnew, v, MaskReplacement)
+	}
+
+	return &MaskingLogger{
+		Logger:   logger,
+		replacer: strings.NewReplacer(oldnew...),
+	}
+}
+
+// Mask replaces all secret values in s
+func (m *MaskingLogger) Mask(s string) string {
+	return m.replacer.Replace(s)
+}
+
+// Write masks p and writes it to the underlying logger.
+// The length of p is returned so callers don't treat masking as a short write.
+func (m *MaskingLogger) Write(p []byte) (int, error) {
+	if _, err := m.Logger.Write([]byte(m.Mask(string(p)))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Checkpoint masks log, error, cancelled and result values before passing them on.
+func (m *MaskingLogger) Checkpoint(id string, nodeID string, val interface{}, mtype MessageType) error {
+	switch v := val.(type) {
+	case []byte:
+		val = []byte(m.Mask(string(v)))
+	case string:
+		val = m.Mask(v)
+	case map[string]string:
+		masked := make(map[string]string, len(v))
+		for key, value := range v {
+			masked[key] = m.Mask(value)
+		}
+		val = masked
+	}
+
+	return m.Logger.Checkpoint(id, nodeID, val, mtype)
+}
@@ -0,0 +1,81 @@
+package streamlogger
+
+import (
+	"testing"
+)
+
+type recordingLogger struct {
+	vals []interface{}
+}
+
+func (r *recordingLogger) Write(p []byte) (int, error) {
+	r.vals = append(r.vals, string(p))
+	return len(p), nil
+}
+func (r *recordingLogger) GetID() string         { return "exec-id" }
+func (r *recordingLogger) SetActionID(id string) {}
+func (r *recordingLogger) SetRetry(retry int32)  {}
+func (r *recordingLogger) Close() error          { return nil }
+func (r *recordingLogger) Checkpoint(id string, nodeID string, val interface{}, mtype MessageType) error {
+	r.vals = append(r.vals, val)
+	return nil
+}
+
+func TestMaskingLogger_Write(t *testing.T) {
+	tests := []struct {
+		name    string
+		secrets []string
+		input   string
+		want    string
+	}{
+		{"single secret", []string{"hunter22"}, "password is hunter22\n", "password is *****\n"},
+		{"multiple occurrences", []string{"s3cr3t"}, "s3cr3t and s3cr3t", "***** and *****"},
+		{"overlapping secrets", []string{"abcd", "abcdefgh"}, "key=abcdefgh", "key=*****"},
+		{"short values are ignored", []string{"abc"}, "abc", "abc"},
+		{"multi-line secret", []string{"line-one\nline-two"}, "got line-two\n", "got *****\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &recordingLogger{}
+			logger := NewMaskingLogger(rec, tt.secrets)
+
+			n, err := logger.Write([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if n != len(tt.input) {
+				t.Errorf("Write() returned %d, want %d", n, len(tt.input))
+			}
+			if got := rec.vals[0].(string); got != tt.want {
+				t.Errorf("Write() wrote %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaskingLogger_Checkpoint(t *testing.T) {
+	rec := &recordingLogger{}
+	logger := NewMaskingLogger(rec, []string{"topsecret"})
+
+	logger.Checkpoint("action", "", []byte("log topsecret"), LogMessageType)
+	logger.Checkpoint("action", "", "error topsecret", ErrMessageType)
+	logger.Checkpoint("action", "", map[string]string{"token": "topsecret"}, ResultMessageType)
+
+	if got := string(rec.vals[0].([]byte)); got != "log *****" {
+		t.Errorf("log checkpoint = %q, want %q", got, "log *****")
+	}
+	if got := rec.vals[1].(string); got != "error *****" {
+		t.Errorf("error checkpoint = %q, want %q", got, "error *****")
+	}
+	if got := rec.vals[2].(map[string]string)["token"]; got != "*****" {
+		t.Errorf("result checkpoint = %q, want %q", got, "*****")
+	}
+}
+
+func TestNewMaskingLogger_NoSecrets(t *testing.T) {
+	rec := &recordingLogger{}
+	if logger := NewMaskingLogger(rec, []string{"", "ab"}); logger != Logger(rec) {
+		t.Error("NewMaskingLogger() should return the logger as is when there is nothing to mask")
+	}
+}
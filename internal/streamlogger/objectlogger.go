@@ -0,0 +1,413 @@
+package streamlogger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"gocloud.dev/blob"
+)
+
+type ObjectLogManagerCfg struct {
+	// BucketURL is a gocloud blob URL (s3://, gs://, ...) where closed log files are stored.
+	// A key prefix can be set with the prefix query parameter, e.g. s3://bucket?prefix=logs/
+	BucketURL string
+
+	// RetentionTime is used to delete objects older than this from the bucket. 0 keeps them forever.
+	RetentionTime time.Duration
+
+	// MaxSizeBytes is used by the FileLogger to rotate files.
+	// Rotated files are uploaded while the execution is still running
+	MaxSizeBytes int64
+
+	// MaxExecutionLogBytes limits the total size of the logs of a single execution. 0 is unlimited
+	MaxExecutionLogBytes int64
+
+	// ScanInterval is the interval with which the ObjectLogManager uploads leftover local files
+	// and applies the retention time on the bucket
+	ScanInterval time.Duration
+
+	// LogDir is used to stage log files of running executions before they are uploaded
+	LogDir string
+
+	// Shared is set when LogDir is shared between server replicas, see FileLogManagerCfg
+	Shared bool
+}
+
+// ObjectLogManager writes logs of running executions to local files and moves them to
+// S3-compatible object storage once they are rotated or the logger is closed.
+// Logs of executions that are not running on this host are streamed from the bucket,
+// so any replica can serve them.
+type ObjectLogManager struct {
+	cfg    ObjectLogManagerCfg
+	local  *FileLogManager
+	bucket *blob.Bucket
+	// scanTicker uses the ScanInterval from the cfg and is used to run periodic scans
+	scanTicker *time.Ticker
+}
+
+// NewObjectLogManager creates a log manager that uses object storage as the storage backend.
+func NewObjectLogManager(ctx context.Context, cfg ObjectLogManagerCfg) (LogManager, error) {
+	if cfg.ScanInterval == 0 {
+		cfg.ScanInterval = 1 * time.Hour
+	}
+
+	if cfg.LogDir == "" {
+		cfg.LogDir = os.TempDir()
+	}
+
+	bucket, err := blob.OpenBucket(ctx, cfg.BucketURL)
+	if err != nil {
+		return nil, fmt.Errorf("could not open log bucket: %w", err)
+	}
+
+	return &ObjectLogManager{
+		cfg: cfg,
+		local: &FileLogManager{
+			cfg: FileLogManagerCfg{
+				MaxSizeBytes:         cfg.MaxSizeBytes,
+				MaxExecutionLogBytes: cfg.MaxExecutionLogBytes,
+				LogDir:               cfg.LogDir,
+				Shared:               cfg.Shared,
+			},
+			loggers: make(map[string]Logger),
+		},
+		bucket:     bucket,
+		scanTicker: time.NewTicker(cfg.ScanInterval),
+	}, nil
+}
+
+// NewLogger creates a new logger that writes to a local file and uploads it on rotation and close.
+func (o *ObjectLogManager) NewLogger(id string) (Logger, error) {
+	ol := &objectLogger{manager: o}
+
+	fl, err := o.local.newLogger(id, ol.rotated)
+	if err != nil {
+		return nil, err
+	}
+	ol.FileLogger = fl
+
+	return ol, nil
+}
+
+// LoggerExists checks if an active logger exists on this host for the given exec ID
+func (o *ObjectLogManager) LoggerExists(execID string) bool {
+	return o.local.LoggerExists(execID)
+}
+
+// StreamLogs streams logs from the local files while the execution is running on this host
+// or has not been uploaded yet, and from the bucket otherwise.
+func (o *ObjectLogManager) StreamLogs(ctx context.Context, execID string, actionRetries map[string]int32) (<-chan string, error) {
+	if o.hasLocalLogs(execID) {
+		return o.local.StreamLogs(ctx, execID, actionRetries)
+	}
+
+	logCh := make(chan string, 100)
+
+	go func() {
+		defer close(logCh)
+
+		keys, err := o.objectKeys(ctx, execID)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+
+		for _, key := range keys {
+			if err := o.streamObject(ctx, key, actionRetries, logCh); err != nil {
+				log.Printf("stream logs for exec %s: failed to stream from object %s: %v", execID, key, err)
+				return
+			}
+		}
+	}()
+
+	return logCh, nil
+}
+
+// streamObject reads all lines from the object and filters by retry attempt
+func (o *ObjectLogManager) streamObject(ctx context.Context, key string, actionRetries map[string]int32, logCh chan<- string) error {
+	br, err := o.bucket.NewReader(ctx, key, nil)
+	if err != nil {
+		return err
+	}
+
+	r, err := decompressReader(key, br)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	return o.local.streamFromReader(ctx, r, actionRetries, logCh)
+}
+
+// GetRawLogs writes all raw log segments for the given execID to w, in order.
+// Returns an error if the execution is still running on this host.
+func (o *ObjectLogManager) GetRawLogs(ctx context.Context, execID string, w io.Writer) error {
+	if o.hasLocalLogs(execID) {
+		return o.local.GetRawLogs(ctx, execID, w)
+	}
+
+	keys, err := o.objectKeys(ctx, execID)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		br, err := o.bucket.NewReader(ctx, key, nil)
+		if err != nil {
+			return fmt.Errorf("failed to open log object %s: %w", key, err)
+		}
+		r, err := decompressReader(key, br)
+		if err != nil {
+			return err
+		}
+		_, copyErr := io.Copy(w, r)
+		r.Close()
+		if copyErr != nil {
+			return fmt.Errorf("failed to read log object %s: %w", key, copyErr)
+		}
+	}
+
+	return nil
+}
+
+// DeleteLogs removes the logs of the given execID from local disk and from the bucket.
+// Returns an error if the execution is still running on this host.
+func (o *ObjectLogManager) DeleteLogs(ctx context.Context, execID string) error {
+	if err := o.local.DeleteLogs(ctx, execID); err != nil {
+		return err
+	}
+
+	keys, err := o.objectKeys(ctx, execID)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if err := o.bucket.Delete(ctx, key); err != nil {
+			return fmt.Errorf("failed to delete log object %s: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+// hasLocalLogs checks if the logs for execID are still on local disk, either because the
+// execution is running on this host or because the files have not been uploaded yet.
+func (o *ObjectLogManager) hasLocalLogs(execID string) bool {
+	if o.local.LoggerExists(execID) {
+		return true
+	}
+
+	files, err := o.local.getLogFiles(execID)
+	return err == nil && len(files) > 0
+}
+
+// objectKeys returns the sorted list of object keys for the given execID.
+func (o *ObjectLogManager) objectKeys(ctx context.Context, execID string) ([]string, error) {
+	var keys []string
+
+	iter := o.bucket.List(&blob.ListOptions{Prefix: execID + "."})
+	for {
+		obj, err := iter.Next(ctx)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list log objects: %w", err)
+		}
+		if !obj.IsDir {
+			keys = append(keys, obj.Key)
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return extractFileIndex(keys[i]) < extractFileIndex(keys[j])
+	})
+
+	return keys, nil
+}
+
+// upload copies a local log file to the bucket using the file name as the key.
+func (o *ObjectLogManager) upload(ctx context.Context, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open log file %s: %w", path, err)
+	}
+	defer file.Close()
+
+	w, err := o.bucket.NewWriter(ctx, filepath.Base(path), &blob.WriterOptions{
+		ContentType: "application/x-ndjson",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create log object for %s: %w", path, err)
+	}
+
+	if _, err := io.Copy(w, file); err != nil {
+		w.Close()
+		return fmt.Errorf("failed to upload log file %s: %w", path, err)
+	}
+
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to upload log file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// archive uploads the local files of execID that have not been uploaded yet and removes all of
+// them from disk. Files are only removed if every upload succeeded.
+func (o *ObjectLogManager) archive(ctx context.Context, execID string, uploaded *sync.Map) error {
+	files, err := o.local.getLogFiles(execID)
+	if err != nil {
+		return err
+	}
+
+	for _, filename := range files {
+		path := filepath.Join(o.cfg.LogDir, filename)
+		if uploaded != nil {
+			if _, ok := uploaded.Load(path); ok {
+				continue
+			}
+		}
+		if err := o.upload(ctx, path); err != nil {
+			return err
+		}
+	}
+
+	for _, filename := range files {
+		if err := os.Remove(filepath.Join(o.cfg.LogDir, filename)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove uploaded log file %s: %w", filename, err)
+		}
+	}
+
+	return nil
+}
+
+// Run starts the scan loop.
+// This is a blocking call and should be run from a goroutine.
+func (o *ObjectLogManager) Run(ctx context.Context, l *slog.Logger) error {
+	defer o.bucket.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-o.scanTicker.C:
+			if err := o.uploadLeftovers(ctx, l); err != nil {
+				l.Error("failed to upload leftover log files", "error", err)
+			}
+			if err := o.run(ctx, l); err != nil {
+				l.Error("failed to run retention scan", "error", err)
+			}
+		}
+	}
+}
+
+// uploadLeftovers uploads local files that do not belong to an active logger. These are left
+// behind when an upload failed or the server stopped before a logger was closed.
+func (o *ObjectLogManager) uploadLeftovers(ctx context.Context, l *slog.Logger) error {
+	entries, err := os.ReadDir(o.cfg.LogDir)
+	if err != nil {
+		return fmt.Errorf("failed to read log directory: %w", err)
+	}
+
+	execIDs := make(map[string]struct{})
+	for _, entry := range entries {
+		if entry.IsDir() || o.local.isFileInUse(entry.Name()) {
+			continue
+		}
+
+		if execID := execIDFromFileName(entry.Name()); execID != "" {
+			execIDs[execID] = struct{}{}
+		}
+	}
+
+	for execID := range execIDs {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+			if err := o.archive(ctx, execID, nil); err != nil {
+				l.Warn("failed to upload log files", "exec_id", execID, "error", err)
+			} else {
+				l.Debug("uploaded leftover log files", "exec_id", execID)
+			}
+		}
+	}
+
+	return nil
+}
+
+// run performs the retention scan and deletes old objects
+func (o *ObjectLogManager) run(ctx context.Context, l *slog.Logger) error {
+	if o.cfg.RetentionTime <= 0 {
+		return nil
+	}
+
+	now := time.Now()
+	iter := o.bucket.List(nil)
+	for {
+		obj, err := iter.Next(ctx)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to list log objects: %w", err)
+		}
+
+		if obj.IsDir || now.Sub(obj.ModTime) <= o.cfg.RetentionTime {
+			continue
+		}
+
+		if err := o.bucket.Delete(ctx, obj.Key); err != nil {
+			l.Warn("failed to delete old log object", "key", obj.Key, "error", err)
+		} else {
+			l.Debug("deleted old log object", "key", obj.Key)
+		}
+	}
+}
+
+// objectLogger is a FileLogger whose files are uploaded to the bucket on rotation and close
+type objectLogger struct {
+	*FileLogger
+	manager *ObjectLogManager
+	// uploads tracks uploads of rotated files that are in progress
+	uploads sync.WaitGroup
+	// uploaded holds the paths of rotated files that were uploaded successfully
+	uploaded sync.Map
+}
+
+// rotated uploads a rotated file in the background
+func (ol *objectLogger) rotated(path string) {
+	ol.uploads.Add(1)
+	go func() {
+		defer ol.uploads.Done()
+		if err := ol.manager.upload(context.Background(), path); err != nil {
+			log.Println(err)
+			return
+		}
+		ol.uploaded.Store(path, true)
+	}()
+}
+
+// Close flushes and closes the underlying FileLogger and moves its files to the bucket.
+// Failed uploads are retried by the scan loop.
+func (ol *objectLogger) Close() error {
+	err := ol.FileLogger.Close()
+
+	ol.uploads.Wait()
+	if archiveErr := ol.manager.archive(context.Background(), ol.ExecID, &ol.uploaded); archiveErr != nil {
+		log.Printf("exec %s: could not move logs to object storage: %v", ol.ExecID, archiveErr)
+	}
+
+	return err
+}
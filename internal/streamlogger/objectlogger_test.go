@@ -0,0 +1,155 @@
+package streamlogger
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	_ "gocloud.dev/blob/memblob"
+)
+
+func newTestObjectLogManager(t *testing.T, maxSize int64) *ObjectLogManager {
+	t.Helper()
+
+	manager, err := NewObjectLogManager(context.Background(), ObjectLogManagerCfg{
+		BucketURL:    "mem://",
+		MaxSizeBytes: maxSize,
+		LogDir:       t.TempDir(),
+	})
+	if err != nil {
+		t.Fatalf("NewObjectLogManager() error = %v", err)
+	}
+
+	om := manager.(*ObjectLogManager)
+	t.Cleanup(func() { om.bucket.Close() })
+	return om
+}
+
+func TestObjectLogManager_UploadOnClose(t *testing.T) {
+	manager := newTestObjectLogManager(t, 0)
+	execID := "exec-upload"
+
+	logger, err := manager.NewLogger(execID)
+	if err != nil {
+		t.Fatalf("NewLogger() error = %v", err)
+	}
+
+	logger.SetActionID("action1")
+	logger.Write([]byte("first line\n"))
+	logger.Write([]byte("second line\n"))
+
+	if !manager.LoggerExists(execID) {
+		t.Error("LoggerExists() = false for active logger")
+	}
+
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if manager.LoggerExists(execID) {
+		t.Error("LoggerExists() = true after close")
+	}
+
+	if _, err := os.Stat(filepath.Join(manager.cfg.LogDir, execID+".0")); !os.IsNotExist(err) {
+		t.Errorf("local log file should be removed after upload, stat error = %v", err)
+	}
+
+	keys, err := manager.objectKeys(context.Background(), execID)
+	if err != nil {
+		t.Fatalf("objectKeys() error = %v", err)
+	}
+	if len(keys) != 1 || keys[0] != execID+".0" {
+		t.Fatalf("objectKeys() = %v, want [%s.0]", keys, execID)
+	}
+
+	logCh, err := manager.StreamLogs(context.Background(), execID, map[string]int32{})
+	if err != nil {
+		t.Fatalf("StreamLogs() error = %v", err)
+	}
+
+	var lines []string
+	for line := range logCh {
+		lines = append(lines, line)
+	}
+	if len(lines) != 2 {
+		t.Fatalf("StreamLogs() returned %d lines, want 2", len(lines))
+	}
+
+	var buf bytes.Buffer
+	if err := manager.GetRawLogs(context.Background(), execID, &buf); err != nil {
+		t.Fatalf("GetRawLogs() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "second line") {
+		t.Errorf("GetRawLogs() = %q, want it to contain the written logs", buf.String())
+	}
+}
+
+func TestObjectLogManager_UploadRotatedFiles(t *testing.T) {
+	manager := newTestObjectLogManager(t, 50)
+	execID := "exec-rotate"
+
+	logger, err := manager.NewLogger(execID)
+	if err != nil {
+		t.Fatalf("NewLogger() error = %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		logger.Write([]byte(strings.Repeat("x", 100)))
+		time.Sleep(2 * FileSyncInterval)
+	}
+
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	keys, err := manager.objectKeys(context.Background(), execID)
+	if err != nil {
+		t.Fatalf("objectKeys() error = %v", err)
+	}
+	if len(keys) < 2 {
+		t.Fatalf("objectKeys() = %v, want rotated files to be uploaded", keys)
+	}
+	for i, key := range keys {
+		if extractFileIndex(key) != i {
+			t.Errorf("objectKeys()[%d] = %s, want index %d", i, key, i)
+		}
+	}
+
+	files, err := manager.local.getLogFiles(execID)
+	if err != nil {
+		t.Fatalf("getLogFiles() error = %v", err)
+	}
+	if len(files) != 0 {
+		t.Errorf("local log files = %v, want none after close", files)
+	}
+}
+
+func TestObjectLogManager_DeleteLogs(t *testing.T) {
+	manager := newTestObjectLogManager(t, 0)
+	execID := "exec-delete"
+
+	logger, err := manager.NewLogger(execID)
+	if err != nil {
+		t.Fatalf("NewLogger() error = %v", err)
+	}
+	logger.Write([]byte("first line\n"))
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if err := manager.DeleteLogs(context.Background(), execID); err != nil {
+		t.Fatalf("DeleteLogs() error = %v", err)
+	}
+
+	keys, err := manager.objectKeys(context.Background(), execID)
+	if err != nil {
+		t.Fatalf("objectKeys() error = %v", err)
+	}
+	if len(keys) != 0 {
+		t.Errorf("objectKeys() = %v, want none after DeleteLogs()", keys)
+	}
+}
@@ -0,0 +1,171 @@
+package streamlogger
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// SharedLogHeartbeat is how often a logger refreshes its marker file in a shared log directory.
+// A marker that hasn't been refreshed for SharedLogStaleAfter belongs to a logger that is gone,
+// e.g. because its server crashed.
+const (
+	SharedLogHeartbeat  = 5 * time.Second
+	SharedLogStaleAfter = 3 * SharedLogHeartbeat
+)
+
+// activeMarkerPath returns the path of the file that marks the logs of execID as being written.
+// The name starts with a dot so it is never picked up as a log file.
+func activeMarkerPath(dir string, execID string) string {
+	return filepath.Join(dir, "."+execID+".active")
+}
+
+// sharedLoggerActive checks if a logger on another server sharing the log directory
+// is writing the logs of execID.
+func (f *FileLogManager) sharedLoggerActive(execID string) bool {
+	if !f.cfg.Shared {
+		return false
+	}
+
+	info, err := os.Stat(activeMarkerPath(f.cfg.LogDir, execID))
+	if err != nil {
+		return false
+	}
+
+	return time.Since(info.ModTime()) < SharedLogStaleAfter
+}
+
+// followSharedLogs streams the logs of an execution that is running on another server sharing
+// the log directory. Log files are read as they are written, moving to the next file on rotation,
+// until the logger's marker goes away.
+func (f *FileLogManager) followSharedLogs(ctx context.Context, execID string, actionRetries map[string]int32, logCh chan<- string) error {
+	ticker := time.NewTicker(FileSyncInterval)
+	defer ticker.Stop()
+
+	var (
+		index   int
+		file    *os.File
+		reader  *bufio.Reader
+		partial strings.Builder
+	)
+	defer func() {
+		if file != nil {
+			file.Close()
+		}
+	}()
+
+	logPath := func(i int) string {
+		return filepath.Join(f.cfg.LogDir, fmt.Sprintf("%s.%d", execID, i))
+	}
+
+	// readLines sends all complete lines up to the end of the current file. An incomplete last
+	// line is kept until the rest of it is written.
+	readLines := func() error {
+		for {
+			line, err := reader.ReadString('\n')
+			partial.WriteString(line)
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			text := strings.TrimSuffix(partial.String(), "\n")
+			partial.Reset()
+			if f.shouldStreamLogLine(text, actionRetries) {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case logCh <- text:
+				}
+			}
+		}
+	}
+
+	for {
+		// Check before reading so that everything written before the logger was closed is read
+		active := f.sharedLoggerActive(execID)
+
+		if file == nil {
+			var err error
+			file, err = os.Open(logPath(index))
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+			if err == nil {
+				reader = bufio.NewReader(file)
+			} else {
+				file = nil
+			}
+		}
+
+		if file != nil {
+			// The next file is only created after the current one is fully written
+			_, statErr := os.Stat(logPath(index + 1))
+			rotated := statErr == nil
+
+			if err := readLines(); err != nil {
+				return err
+			}
+
+			if rotated {
+				file.Close()
+				file = nil
+				if partial.Len() > 0 && f.shouldStreamLogLine(partial.String(), actionRetries) {
+					logCh <- partial.String()
+				}
+				partial.Reset()
+				index++
+				continue
+			}
+		}
+
+		if !active {
+			if partial.Len() > 0 && f.shouldStreamLogLine(partial.String(), actionRetries) {
+				logCh <- partial.String()
+			}
+			// Files after the current one might have been compressed by now
+			return f.streamRemainingLogs(ctx, execID, index, file != nil, actionRetries, logCh)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// streamRemainingLogs streams the log files of execID after the given index. If the file at
+// index has not been read yet, it is included.
+func (f *FileLogManager) streamRemainingLogs(ctx context.Context, execID string, index int, indexRead bool, actionRetries map[string]int32, logCh chan<- string) error {
+	logFiles, err := f.getLogFiles(execID)
+	if err != nil {
+		return err
+	}
+
+	for _, filename := range logFiles {
+		i := extractFileIndex(filename)
+		if i < index || (i == index && indexRead) {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+			if err := f.streamFromFile(ctx, filepath.Join(f.cfg.LogDir, filename), actionRetries, logCh); err != nil {
+				return fmt.Errorf("failed to stream from file %s: %w", filename, err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -27,6 +27,8 @@ type LogManager interface {
 	LoggerExists(execID string) bool
 	StreamLogs(ctx context.Context, execID string, actionRetries map[string]int32) (<-chan string, error)
 	GetRawLogs(ctx context.Context, execID string, w io.Writer) error
+	// DeleteLogs removes all logs of a finished execution
+	DeleteLogs(ctx context.Context, execID string) error
 	Run(ctx context.Context, logger *slog.Logger) error
 }
 
@@ -38,8 +40,33 @@ const (
 	ResultMessageType    MessageType = "result"
 	StateMessageType     MessageType = "state"
 	CancelledMessageType MessageType = "cancelled"
+	ProgressMessageType  MessageType = "progress"
 )
 
+type ProgressEventType string
+
+const (
+	ActionStartedEvent  ProgressEventType = "action_started"
+	ActionFinishedEvent ProgressEventType = "action_finished"
+	NodeFinishedEvent   ProgressEventType = "node_finished"
+)
+
+// ProgressEvent is the value of a progress message. It describes the whole progress of the
+// execution after the event, so clients don't have to keep track of earlier events.
+type ProgressEvent struct {
+	Event ProgressEventType `json:"event"`
+	// ActionIndex is the 1-based position of the current action in the flow
+	ActionIndex  int `json:"action_index"`
+	TotalActions int `json:"total_actions"`
+	// CompletedNodes is the number of nodes that finished running the current action
+	CompletedNodes int `json:"completed_nodes"`
+	TotalNodes     int `json:"total_nodes"`
+	// Node is set on node_finished events
+	Node string `json:"node,omitempty"`
+	// Status is set on finished events, one of success, failed or cancelled
+	Status string `json:"status,omitempty"`
+}
+
 type StreamMessage struct {
 	ActionID  string      `json:"action_id"`
 	MType     MessageType `json:"message_type"`
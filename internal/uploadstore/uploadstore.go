@@ -0,0 +1,151 @@
+// Package uploadstore keeps the files uploaded as flow inputs until the executions that use
+// them have run. Uploads are streamed into a local directory or a bucket as they are received,
+// so the API doesn't buffer them and the worker that runs an execution can be on another host.
+// Executions get a reference to the stored file instead of a local path.
+package uploadstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/cvhariharan/flowctl/internal/artifactstore"
+	"gocloud.dev/blob"
+	"gocloud.dev/gcerrors"
+)
+
+// RefPrefix is the prefix of the input values that reference a stored upload
+const RefPrefix = "upload://"
+
+// ErrTooLarge is returned by Put when the upload exceeds the maximum size
+var ErrTooLarge = errors.New("file exceeds the maximum size")
+
+// Store keeps uploaded files in a gocloud bucket. Files are keyed by the execution, the input
+// and the name of the uploaded file.
+type Store struct {
+	bucket *blob.Bucket
+}
+
+// Open opens the store at location, a gocloud blob URL such as s3://bucket or gs://bucket,
+// or a local directory which is created if it doesn't exist
+func Open(ctx context.Context, location string) (*Store, error) {
+	bucket, err := artifactstore.OpenBucket(ctx, location)
+	if err != nil {
+		return nil, fmt.Errorf("could not open uploads bucket: %w", err)
+	}
+	return &Store{bucket: bucket}, nil
+}
+
+// Key returns the key of a file uploaded to an input of an execution
+func Key(execID, inputName, filename string) string {
+	return path.Join(execID, inputName, filename)
+}
+
+// Ref returns the input value that references the upload stored under key
+func Ref(key string) string {
+	return RefPrefix + key
+}
+
+// ParseRef returns the key referenced by an input value, if it references an upload
+func ParseRef(value string) (string, bool) {
+	key, ok := strings.CutPrefix(value, RefPrefix)
+	if !ok || key == "" {
+		return "", false
+	}
+	return key, true
+}
+
+// BelongsTo reports whether key is an upload of the execution execID. Keys that would leave the
+// execution's prefix once cleaned don't belong to it.
+func BelongsTo(key, execID string) bool {
+	if execID == "" || path.Clean(key) != key {
+		return false
+	}
+	return strings.HasPrefix(key, execID+"/")
+}
+
+// Put streams r into the store under key and returns the number of bytes written. If r has more
+// than maxSize bytes, nothing is stored and ErrTooLarge is returned.
+func (s *Store) Put(ctx context.Context, key string, r io.Reader, maxSize int64) (int64, error) {
+	// Cancelling the context before closing the writer discards the partial upload
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	w, err := s.bucket.NewWriter(ctx, key, nil)
+	if err != nil {
+		return 0, fmt.Errorf("could not store %s: %w", key, err)
+	}
+
+	n, err := io.Copy(w, io.LimitReader(r, maxSize+1))
+	if err == nil && n > maxSize {
+		err = ErrTooLarge
+	}
+	if err != nil {
+		cancel()
+		w.Close()
+		return n, err
+	}
+
+	if err := w.Close(); err != nil {
+		return n, fmt.Errorf("could not store %s: %w", key, err)
+	}
+	return n, nil
+}
+
+// NewReader returns a reader of the upload stored under key
+func (s *Store) NewReader(ctx context.Context, key string) (io.ReadCloser, error) {
+	r, err := s.bucket.NewReader(ctx, key, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not read upload %s: %w", key, err)
+	}
+	return r, nil
+}
+
+// Download copies the upload stored under key to the file at target
+func (s *Store) Download(ctx context.Context, key string, target string) error {
+	r, err := s.bucket.NewReader(ctx, key, nil)
+	if err != nil {
+		return fmt.Errorf("could not read upload %s: %w", key, err)
+	}
+	defer r.Close()
+
+	f, err := os.Create(target)
+	if err != nil {
+		return fmt.Errorf("failed to create file %s: %w", target, err)
+	}
+
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write file %s: %w", target, err)
+	}
+	return f.Close()
+}
+
+// DeleteExecution removes the files uploaded for an execution
+func (s *Store) DeleteExecution(ctx context.Context, execID string) error {
+	iter := s.bucket.List(&blob.ListOptions{Prefix: execID + "/"})
+	for {
+		obj, err := iter.Next(ctx)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("could not list uploads of execution %s: %w", execID, err)
+		}
+		if obj.IsDir {
+			continue
+		}
+
+		if err := s.bucket.Delete(ctx, obj.Key); err != nil && gcerrors.Code(err) != gcerrors.NotFound {
+			return fmt.Errorf("could not delete upload %s: %w", obj.Key, err)
+		}
+	}
+}
+
+func (s *Store) Close() error {
+	return s.bucket.Close()
+}
@@ -0,0 +1,27 @@
+package uploadstore
+
+import "testing"
+
+func TestBelongsTo(t *testing.T) {
+	tests := []struct {
+		name   string
+		key    string
+		execID string
+		want   bool
+	}{
+		{"own upload", "exec-1/report/data.csv", "exec-1", true},
+		{"other execution", "exec-2/report/data.csv", "exec-1", false},
+		{"execution ID prefix", "exec-10/report/data.csv", "exec-1", false},
+		{"escapes the prefix", "exec-1/../exec-2/report/data.csv", "exec-1", false},
+		{"bare execution ID", "exec-1", "exec-1", false},
+		{"empty execution ID", "/report/data.csv", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := BelongsTo(tt.key, tt.execID); got != tt.want {
+				t.Errorf("BelongsTo(%q, %q) = %v, want %v", tt.key, tt.execID, got, tt.want)
+			}
+		})
+	}
+}
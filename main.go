@@ -10,6 +10,7 @@ import (
 //go:embed site/build/_app
 //go:embed configs
 //go:embed migrations
+//go:embed migrations_sqlite
 var staticFiles embed.FS
 
 func main() {
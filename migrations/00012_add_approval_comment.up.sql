@@ -0,0 +1 @@
+ALTER TABLE approvals ADD COLUMN comment TEXT NOT NULL DEFAULT '';
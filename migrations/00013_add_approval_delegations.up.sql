@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS approval_delegations (
+    id SERIAL PRIMARY KEY,
+    uuid UUID NOT NULL DEFAULT uuid_generate_v4(),
+    namespace_id INTEGER NOT NULL,
+    delegator_id INTEGER NOT NULL,
+    delegate_user_id INTEGER,
+    delegate_group_id INTEGER,
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE,
+    FOREIGN KEY (delegator_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (delegate_user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (delegate_group_id) REFERENCES groups(id) ON DELETE CASCADE,
+    CHECK ((delegate_user_id IS NULL) <> (delegate_group_id IS NULL)),
+    CHECK (ends_at > starts_at)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_approval_delegations_uuid ON approval_delegations(uuid);
+CREATE INDEX IF NOT EXISTS idx_approval_delegations_namespace_id ON approval_delegations(namespace_id);
+CREATE INDEX IF NOT EXISTS idx_approval_delegations_delegator_id ON approval_delegations(delegator_id);
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS messenger_configs (
+    id SERIAL PRIMARY KEY,
+    channel VARCHAR(50) NOT NULL UNIQUE,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    encrypted_config TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
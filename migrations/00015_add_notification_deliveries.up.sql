@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS notification_deliveries (
+    id SERIAL PRIMARY KEY,
+    uuid UUID NOT NULL DEFAULT uuid_generate_v4(),
+    notification_id UUID NOT NULL,
+    namespace_id INTEGER NOT NULL,
+    exec_id VARCHAR(36) NOT NULL,
+    flow_id VARCHAR(150) NOT NULL,
+    channel VARCHAR(50) NOT NULL,
+    receiver TEXT NOT NULL,
+    event VARCHAR(50) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 1,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE,
+    UNIQUE(notification_id, receiver),
+    CHECK (status IN ('delivered', 'retrying', 'failed'))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notification_deliveries_uuid ON notification_deliveries(uuid);
+CREATE INDEX IF NOT EXISTS idx_notification_deliveries_namespace_status ON notification_deliveries(namespace_id, status);
+CREATE INDEX IF NOT EXISTS idx_notification_deliveries_exec_id ON notification_deliveries(exec_id);
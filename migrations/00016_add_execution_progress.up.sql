@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS execution_progress (
+    id SERIAL PRIMARY KEY,
+    exec_id VARCHAR(36) NOT NULL UNIQUE,
+    namespace_id INTEGER NOT NULL,
+    progress JSONB NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE
+);
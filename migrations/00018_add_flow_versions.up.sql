@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS flow_versions (
+    id SERIAL PRIMARY KEY,
+    flow_id INTEGER NOT NULL,
+    checksum VARCHAR(64) NOT NULL,
+    commit_sha VARCHAR(64),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (flow_id) REFERENCES flows(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_flow_versions_flow_id ON flow_versions(flow_id, created_at DESC);
@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS flow_revisions (
+    id SERIAL PRIMARY KEY,
+    uuid UUID NOT NULL DEFAULT uuid_generate_v4(),
+    flow_id INTEGER NOT NULL,
+    namespace_id INTEGER NOT NULL,
+    content TEXT NOT NULL,
+    format VARCHAR(10) NOT NULL,
+    checksum VARCHAR(64) NOT NULL,
+    commit_sha VARCHAR(64),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    requested_by INTEGER,
+    reviewed_by INTEGER,
+    comment TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    FOREIGN KEY (flow_id) REFERENCES flows(id) ON DELETE CASCADE,
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE,
+    FOREIGN KEY (requested_by) REFERENCES users(id) ON DELETE SET NULL,
+    FOREIGN KEY (reviewed_by) REFERENCES users(id) ON DELETE SET NULL,
+    CONSTRAINT unique_flow_revision_uuid UNIQUE (uuid),
+    CHECK (status IN ('pending', 'approved', 'rejected', 'superseded'))
+);
+CREATE INDEX idx_flow_revisions_namespace ON flow_revisions(namespace_id, status, created_at DESC);
+-- A flow has at most one revision waiting for review
+CREATE UNIQUE INDEX idx_flow_revisions_pending ON flow_revisions(flow_id) WHERE status = 'pending';
@@ -0,0 +1,2 @@
+ALTER TABLE flow_versions DROP COLUMN IF EXISTS format;
+ALTER TABLE flow_versions DROP COLUMN IF EXISTS content;
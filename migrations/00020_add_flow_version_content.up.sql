@@ -0,0 +1,3 @@
+-- Versions recorded before this migration have no content and can't be diffed
+ALTER TABLE flow_versions ADD COLUMN IF NOT EXISTS content TEXT;
+ALTER TABLE flow_versions ADD COLUMN IF NOT EXISTS format VARCHAR(10);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS flow_import_errors (
+    id SERIAL PRIMARY KEY,
+    namespace_id INTEGER NOT NULL,
+    file_path TEXT NOT NULL,
+    error TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE,
+    CONSTRAINT unique_flow_import_error_file UNIQUE (namespace_id, file_path)
+);
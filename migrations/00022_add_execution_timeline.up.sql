@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS execution_timeline (
+    id SERIAL PRIMARY KEY,
+    exec_id VARCHAR(36) NOT NULL,
+    namespace_id INTEGER NOT NULL,
+    action_id VARCHAR(150) NOT NULL,
+    node TEXT NOT NULL DEFAULT '',
+    retry INTEGER NOT NULL DEFAULT 0,
+    status TEXT NOT NULL DEFAULT 'running',
+    error TEXT,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMP WITH TIME ZONE,
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE,
+    CONSTRAINT unique_execution_timeline_entry UNIQUE (exec_id, action_id, node, retry)
+);
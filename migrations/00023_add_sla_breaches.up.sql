@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS sla_breaches (
+    id SERIAL PRIMARY KEY,
+    exec_id VARCHAR(36) NOT NULL,
+    namespace_id INTEGER NOT NULL,
+    flow_slug VARCHAR(150) NOT NULL,
+    kind TEXT NOT NULL,
+    reason TEXT NOT NULL,
+    detected_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE,
+    CONSTRAINT unique_sla_breach UNIQUE (exec_id, kind)
+);
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS execution_node_telemetry (
+    id SERIAL PRIMARY KEY,
+    exec_id VARCHAR(36) NOT NULL,
+    namespace_id INTEGER NOT NULL,
+    action_id VARCHAR(150) NOT NULL,
+    node TEXT NOT NULL DEFAULT '',
+    sampled_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    cpu_percent DOUBLE PRECISION NOT NULL,
+    memory_percent DOUBLE PRECISION NOT NULL,
+    disk_percent DOUBLE PRECISION NOT NULL,
+    load1 DOUBLE PRECISION NOT NULL,
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_execution_node_telemetry_exec_id ON execution_node_telemetry(exec_id);
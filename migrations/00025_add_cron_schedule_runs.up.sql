@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS cron_schedule_runs (
+    schedule_id INTEGER PRIMARY KEY REFERENCES cron_schedules(id) ON DELETE CASCADE,
+    exec_id VARCHAR(36) NOT NULL,
+    fired_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    max_drift_ms BIGINT NOT NULL DEFAULT 0,
+    run_count BIGINT NOT NULL DEFAULT 0
+);
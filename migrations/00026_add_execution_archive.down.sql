@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_execution_log_updated_at;
+DROP TABLE IF EXISTS execution_archive;
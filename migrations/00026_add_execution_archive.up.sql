@@ -0,0 +1,25 @@
+-- Summaries of executions moved out of execution_log by the archiver.
+-- They don't reference flows or users so that they outlive them.
+CREATE TABLE IF NOT EXISTS execution_archive (
+    exec_id VARCHAR(36) PRIMARY KEY,
+    namespace_id INTEGER NOT NULL,
+    flow_slug VARCHAR(150) NOT NULL,
+    flow_name VARCHAR(150) NOT NULL,
+    status execution_status NOT NULL,
+    trigger_type trigger_type NOT NULL,
+    triggered_by_uuid UUID NOT NULL,
+    triggered_by_name TEXT NOT NULL,
+    input JSONB DEFAULT '{}'::jsonb NOT NULL,
+    error TEXT,
+    current_action_id TEXT,
+    action_retries JSONB DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    started_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    scheduled_at TIMESTAMP WITH TIME ZONE,
+    archived_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_execution_archive_namespace_created_at ON execution_archive(namespace_id, created_at DESC);
+CREATE INDEX idx_execution_log_updated_at ON execution_log(updated_at);
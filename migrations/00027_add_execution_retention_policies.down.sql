@@ -0,0 +1,4 @@
+DELETE FROM cron_schedule_runs WHERE exec_id IS NULL;
+ALTER TABLE cron_schedule_runs ALTER COLUMN exec_id SET NOT NULL;
+DROP INDEX IF EXISTS idx_execution_log_namespace_created_at;
+DROP TABLE IF EXISTS execution_retention_policies;
@@ -0,0 +1,16 @@
+-- Per namespace limits on how long finished executions are kept.
+-- At least one of max_age_days and keep_last is set.
+CREATE TABLE IF NOT EXISTS execution_retention_policies (
+    namespace_id INTEGER PRIMARY KEY,
+    max_age_days INTEGER CHECK (max_age_days > 0),
+    keep_last INTEGER CHECK (keep_last > 0),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE,
+    CHECK (max_age_days IS NOT NULL OR keep_last IS NOT NULL)
+);
+
+CREATE INDEX IF NOT EXISTS idx_execution_log_namespace_created_at ON execution_log(namespace_id, created_at);
+
+-- Purging an execution clears it from the last run of its cron schedule
+ALTER TABLE cron_schedule_runs ALTER COLUMN exec_id DROP NOT NULL;
@@ -0,0 +1,9 @@
+-- Full text search over the content of flows: action names, scripts and input descriptions. Names
+-- and descriptions are read from flows when searching so that renaming a flow needs no reindex.
+CREATE TABLE IF NOT EXISTS flow_search (
+    flow_id INTEGER PRIMARY KEY,
+    content TEXT NOT NULL DEFAULT '',
+    document TSVECTOR NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (flow_id) REFERENCES flows(id) ON DELETE CASCADE
+);
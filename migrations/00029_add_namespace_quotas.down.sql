@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_execution_log_usage_namespace_id;
+DROP TABLE IF EXISTS execution_log_usage;
+DROP TABLE IF EXISTS namespace_execution_counts;
+DROP TABLE IF EXISTS namespace_quotas;
@@ -0,0 +1,36 @@
+-- Per namespace limits on executions, nodes and log storage.
+-- At least one limit is set.
+CREATE TABLE IF NOT EXISTS namespace_quotas (
+    namespace_id INTEGER PRIMARY KEY,
+    max_executions_per_day INTEGER CHECK (max_executions_per_day > 0),
+    max_concurrent_executions INTEGER CHECK (max_concurrent_executions > 0),
+    max_nodes INTEGER CHECK (max_nodes > 0),
+    max_log_bytes BIGINT CHECK (max_log_bytes > 0),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE,
+    CHECK (
+        max_executions_per_day IS NOT NULL OR max_concurrent_executions IS NOT NULL
+        OR max_nodes IS NOT NULL OR max_log_bytes IS NOT NULL
+    )
+);
+
+-- Executions started in a namespace per UTC day. Purged executions are still counted.
+CREATE TABLE IF NOT EXISTS namespace_execution_counts (
+    namespace_id INTEGER NOT NULL,
+    day DATE NOT NULL,
+    executions INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (namespace_id, day),
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE
+);
+
+-- Bytes of logs written by each execution, removed when the execution is purged
+CREATE TABLE IF NOT EXISTS execution_log_usage (
+    exec_id VARCHAR(36) PRIMARY KEY,
+    namespace_id INTEGER NOT NULL,
+    log_bytes BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_execution_log_usage_namespace_id ON execution_log_usage(namespace_id);
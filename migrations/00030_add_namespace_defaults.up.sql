@@ -0,0 +1,11 @@
+-- Settings inherited by the flows of a namespace unless a flow sets its own.
+-- executor_options holds default action options keyed by executor name.
+CREATE TABLE IF NOT EXISTS namespace_defaults (
+    namespace_id INTEGER PRIMARY KEY,
+    timezone TEXT,
+    notify JSONB NOT NULL DEFAULT '[]',
+    executor_options JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE
+);
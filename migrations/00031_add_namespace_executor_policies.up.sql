@@ -0,0 +1,9 @@
+-- Executors the flows of a namespace may use. Namespaces without a policy may use every executor.
+CREATE TABLE IF NOT EXISTS namespace_executor_policies (
+    namespace_id INTEGER PRIMARY KEY,
+    allowed_executors TEXT[] NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE,
+    CHECK (cardinality(allowed_executors) > 0)
+);
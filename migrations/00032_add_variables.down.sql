@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS namespace_variables;
+DROP TABLE IF EXISTS global_variables;
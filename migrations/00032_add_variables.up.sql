@@ -0,0 +1,20 @@
+-- Plain configuration values available to flow expressions as vars.<key>.
+-- Namespace variables take precedence over global variables with the same key.
+CREATE TABLE IF NOT EXISTS global_variables (
+    key VARCHAR(150) PRIMARY KEY,
+    value TEXT NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS namespace_variables (
+    namespace_id INTEGER NOT NULL,
+    key VARCHAR(150) NOT NULL,
+    value TEXT NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (namespace_id, key),
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_execution_log_triggered_by_created_at;
+DROP TABLE IF EXISTS flow_favorites;
@@ -0,0 +1,13 @@
+-- Flows starred by users so they can find the flows they use among every flow they can access
+CREATE TABLE IF NOT EXISTS flow_favorites (
+    user_id INTEGER NOT NULL,
+    flow_id INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, flow_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (flow_id) REFERENCES flows(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_flow_favorites_flow_id ON flow_favorites(flow_id);
+
+-- Finds the flows a user ran last
+CREATE INDEX IF NOT EXISTS idx_execution_log_triggered_by_created_at ON execution_log(triggered_by, created_at DESC);
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS execution_log_events ON execution_log;
+DROP FUNCTION IF EXISTS notify_execution_event();
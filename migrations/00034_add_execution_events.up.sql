@@ -0,0 +1,26 @@
+-- Publishes execution status changes so that servers can stream them to clients
+CREATE OR REPLACE FUNCTION notify_execution_event() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'UPDATE' AND NEW.status IS NOT DISTINCT FROM OLD.status THEN
+        RETURN NEW;
+    END IF;
+    -- Executions scheduled for later are announced once they start
+    IF NEW.scheduled_at IS NOT NULL AND NEW.scheduled_at > NOW() THEN
+        RETURN NEW;
+    END IF;
+
+    PERFORM pg_notify('flowctl_execution_events', json_build_object(
+        'exec_id', NEW.exec_id,
+        'namespace_id', (SELECT uuid FROM namespaces WHERE id = NEW.namespace_id),
+        'flow_id', (SELECT slug FROM flows WHERE id = NEW.flow_id),
+        'status', NEW.status,
+        'triggered_by', (SELECT uuid FROM users WHERE id = NEW.triggered_by),
+        'timestamp', NOW()
+    )::text);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER execution_log_events
+    AFTER INSERT OR UPDATE OF status ON execution_log
+    FOR EACH ROW EXECUTE FUNCTION notify_execution_event();
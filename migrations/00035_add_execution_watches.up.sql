@@ -0,0 +1,12 @@
+-- Flows and executions users are notified about even if they didn't trigger them
+CREATE TABLE IF NOT EXISTS execution_watches (
+    user_id INTEGER NOT NULL,
+    flow_id INTEGER NOT NULL,
+    -- Watches without an execution cover every execution of the flow
+    exec_id VARCHAR(36),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (flow_id) REFERENCES flows(id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_execution_watches_target ON execution_watches(user_id, flow_id, COALESCE(exec_id, ''));
+CREATE INDEX IF NOT EXISTS idx_execution_watches_flow_id ON execution_watches(flow_id);
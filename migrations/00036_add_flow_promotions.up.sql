@@ -0,0 +1,18 @@
+-- Records the flows promoted from one namespace to another, such as from staging to production,
+-- so that the path of a flow through namespaces can be traced
+CREATE TABLE IF NOT EXISTS flow_promotions (
+    id SERIAL PRIMARY KEY,
+    source_flow_id INTEGER NOT NULL,
+    -- Checksum of the source flow file when it was promoted
+    source_checksum VARCHAR(64) NOT NULL,
+    target_flow_id INTEGER NOT NULL,
+    -- Set if the target namespace requires flow reviews and the promotion replaced an existing flow
+    revision_uuid UUID,
+    promoted_by INTEGER,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (source_flow_id) REFERENCES flows(id) ON DELETE CASCADE,
+    FOREIGN KEY (target_flow_id) REFERENCES flows(id) ON DELETE CASCADE,
+    FOREIGN KEY (promoted_by) REFERENCES users(id) ON DELETE SET NULL
+);
+CREATE INDEX IF NOT EXISTS idx_flow_promotions_source_flow_id ON flow_promotions(source_flow_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_flow_promotions_target_flow_id ON flow_promotions(target_flow_id, created_at DESC);
@@ -0,0 +1,17 @@
+-- Named input values users can trigger a flow with
+CREATE TABLE IF NOT EXISTS input_presets (
+    id SERIAL PRIMARY KEY,
+    uuid UUID NOT NULL DEFAULT uuid_generate_v4(),
+    flow_id INTEGER NOT NULL,
+    name VARCHAR(150) NOT NULL,
+    inputs JSONB NOT NULL DEFAULT '{}'::jsonb,
+    -- Shared presets can be used by every member of the namespace
+    is_shared BOOLEAN NOT NULL DEFAULT FALSE,
+    created_by INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (flow_id) REFERENCES flows(id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_input_presets_uuid ON input_presets(uuid);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_input_presets_name ON input_presets(flow_id, created_by, name);
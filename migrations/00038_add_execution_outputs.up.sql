@@ -0,0 +1,10 @@
+-- Results of the actions of executions, masked like they are in the logs
+CREATE TABLE IF NOT EXISTS execution_outputs (
+    exec_id VARCHAR(36) NOT NULL,
+    namespace_id INTEGER NOT NULL,
+    action_id VARCHAR(150) NOT NULL,
+    outputs JSONB NOT NULL DEFAULT '{}'::jsonb,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (exec_id, action_id),
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE
+);
@@ -0,0 +1,13 @@
+-- Executions queued by the triggers of flows. An execution triggers a flow at most once.
+CREATE TABLE IF NOT EXISTS flow_trigger_runs (
+    id SERIAL PRIMARY KEY,
+    upstream_exec_id VARCHAR(36) NOT NULL,
+    exec_id VARCHAR(36) NOT NULL,
+    flow_id INTEGER NOT NULL,
+    -- Number of triggered executions in the chain up to and including this one
+    depth INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (flow_id) REFERENCES flows(id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_flow_trigger_runs_exec_id ON flow_trigger_runs(exec_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_flow_trigger_runs_upstream ON flow_trigger_runs(upstream_exec_id, flow_id);
@@ -0,0 +1,12 @@
+-- Mutex keys held by running executions. Executions of any flow in a namespace that declare the
+-- same key run one at a time.
+CREATE TABLE IF NOT EXISTS execution_locks (
+    id SERIAL PRIMARY KEY,
+    namespace_id INTEGER NOT NULL,
+    lock_key VARCHAR(100) NOT NULL,
+    exec_id VARCHAR(36) NOT NULL,
+    acquired_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_execution_locks_key ON execution_locks(namespace_id, lock_key);
+CREATE INDEX IF NOT EXISTS idx_execution_locks_exec_id ON execution_locks(exec_id);
@@ -0,0 +1,21 @@
+-- Tasks of manual actions. An execution waits on a pending task until a member of the assigned
+-- group completes it, the response becomes the outputs of the action.
+CREATE TABLE IF NOT EXISTS manual_tasks (
+    id SERIAL PRIMARY KEY,
+    uuid UUID NOT NULL DEFAULT uuid_generate_v4(),
+    exec_id VARCHAR(36) NOT NULL,
+    action_id VARCHAR(150) NOT NULL,
+    namespace_id INTEGER NOT NULL,
+    -- The with block of the action: instructions, group, checklist and inputs
+    definition JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'completed', 'cancelled')),
+    response JSONB,
+    completed_by INTEGER,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE,
+    FOREIGN KEY (completed_by) REFERENCES users(id) ON DELETE SET NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_manual_tasks_uuid ON manual_tasks(uuid);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_manual_tasks_exec_action ON manual_tasks(exec_id, action_id);
+CREATE INDEX IF NOT EXISTS idx_manual_tasks_namespace_status ON manual_tasks(namespace_id, status);
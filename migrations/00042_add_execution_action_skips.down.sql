@@ -0,0 +1 @@
+DROP TABLE IF EXISTS execution_action_skips;
@@ -0,0 +1,12 @@
+-- Failed actions that were skipped when their execution was retried, and who skipped them
+CREATE TABLE IF NOT EXISTS execution_action_skips (
+    id SERIAL PRIMARY KEY,
+    exec_id VARCHAR(36) NOT NULL,
+    action_id VARCHAR(150) NOT NULL,
+    namespace_id INTEGER NOT NULL,
+    skipped_by INTEGER,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE,
+    FOREIGN KEY (skipped_by) REFERENCES users(id) ON DELETE SET NULL
+);
+CREATE INDEX IF NOT EXISTS idx_execution_action_skips_exec_id ON execution_action_skips(exec_id);
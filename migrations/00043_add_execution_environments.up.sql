@@ -0,0 +1,19 @@
+-- Runtime details of the nodes the actions of executions ran on, kept to investigate how an
+-- execution can be reproduced. A retried action replaces the details of its previous run.
+CREATE TABLE IF NOT EXISTS execution_environments (
+    id SERIAL PRIMARY KEY,
+    exec_id VARCHAR(36) NOT NULL,
+    namespace_id INTEGER NOT NULL,
+    action_id VARCHAR(150) NOT NULL,
+    node TEXT NOT NULL DEFAULT '',
+    hostname TEXT NOT NULL DEFAULT '',
+    os TEXT NOT NULL DEFAULT '',
+    executor VARCHAR(150) NOT NULL,
+    executor_version TEXT NOT NULL DEFAULT '',
+    image TEXT NOT NULL DEFAULT '',
+    image_digest TEXT NOT NULL DEFAULT '',
+    flow_checksum VARCHAR(128) NOT NULL DEFAULT '',
+    recorded_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_execution_environments_exec_action_node ON execution_environments(exec_id, action_id, node);
@@ -0,0 +1,12 @@
+-- Webhooks that queue executions of flows. Only the hash of the token in the URL of a webhook is
+-- stored, the secret that signs its requests is encrypted with the keeper.
+CREATE TABLE IF NOT EXISTS flow_webhooks (
+    id SERIAL PRIMARY KEY,
+    flow_id INTEGER NOT NULL,
+    token_hash VARCHAR(64) NOT NULL,
+    encrypted_secret TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    FOREIGN KEY (flow_id) REFERENCES flows(id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_flow_webhooks_flow_id ON flow_webhooks(flow_id);
@@ -0,0 +1,17 @@
+-- Executions started by the flow actions of other executions. The parent execution waits until
+-- the execution finishes and then takes over its outputs, which resolves the link.
+CREATE TABLE IF NOT EXISTS subflow_executions (
+    id SERIAL PRIMARY KEY,
+    parent_exec_id VARCHAR(36) NOT NULL,
+    parent_action_id VARCHAR(150) NOT NULL,
+    exec_id VARCHAR(36) NOT NULL,
+    namespace_id INTEGER NOT NULL,
+    -- Number of executions in the chain of sub-flows up to and including this one
+    depth INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_subflow_executions_exec_id ON subflow_executions(exec_id);
+-- An action of a parent execution waits on at most one execution at a time
+CREATE UNIQUE INDEX IF NOT EXISTS idx_subflow_executions_unresolved ON subflow_executions(parent_exec_id, parent_action_id) WHERE resolved_at IS NULL;
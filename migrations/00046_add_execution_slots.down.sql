@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS execution_slots;
+ALTER TABLE namespace_defaults DROP COLUMN IF EXISTS max_concurrency;
@@ -0,0 +1,13 @@
+-- Maximum number of executions of the namespace that run at the same time, 0 is unlimited
+ALTER TABLE namespace_defaults ADD COLUMN IF NOT EXISTS max_concurrency INTEGER NOT NULL DEFAULT 0;
+
+-- Slots held by the executions that were picked from the job queue and are running. Jobs of
+-- flows or namespaces whose slots are all taken stay queued.
+CREATE TABLE IF NOT EXISTS execution_slots (
+    exec_id VARCHAR(36) PRIMARY KEY,
+    namespace_id INTEGER NOT NULL,
+    flow_slug VARCHAR(150) NOT NULL,
+    acquired_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_execution_slots_namespace_flow ON execution_slots(namespace_id, flow_slug);
@@ -0,0 +1,17 @@
+-- Files published by the actions of executions. The files are kept in the artifact archive
+-- under object_key after the execution finished, name is their path in the artifact directory.
+CREATE TABLE IF NOT EXISTS artifacts (
+    id SERIAL PRIMARY KEY,
+    uuid UUID NOT NULL DEFAULT uuid_generate_v4(),
+    exec_id VARCHAR(36) NOT NULL,
+    namespace_id INTEGER NOT NULL,
+    action_id VARCHAR(150) NOT NULL,
+    name TEXT NOT NULL,
+    object_key TEXT NOT NULL,
+    size BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_artifacts_uuid ON artifacts(uuid);
+-- A retried action replaces the files it published in the earlier attempt
+CREATE UNIQUE INDEX IF NOT EXISTS idx_artifacts_exec_action_name ON artifacts(exec_id, action_id, name);
@@ -0,0 +1,19 @@
+-- Changes made through the API, with the state of the changed resource before and after the
+-- change. The actor and the namespace are copied so that entries outlive the users and
+-- namespaces they refer to.
+CREATE TABLE IF NOT EXISTS audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    -- NULL for changes outside of a namespace, like users and groups
+    namespace_uuid UUID,
+    actor_uuid UUID NOT NULL,
+    actor_name VARCHAR(150) NOT NULL,
+    action VARCHAR(100) NOT NULL,
+    resource_type VARCHAR(50) NOT NULL,
+    resource_id VARCHAR(150) NOT NULL,
+    before JSONB,
+    after JSONB,
+    source_ip VARCHAR(64) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_log_namespace_created_at ON audit_log(namespace_uuid, created_at DESC);
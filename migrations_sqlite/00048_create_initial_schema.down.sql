@@ -0,0 +1,60 @@
+DROP TRIGGER IF EXISTS execution_log_events_update;
+DROP TRIGGER IF EXISTS execution_log_events_insert;
+DROP VIEW IF EXISTS user_view;
+DROP VIEW IF EXISTS group_view;
+DROP TABLE IF EXISTS audit_log;
+DROP TABLE IF EXISTS artifacts;
+DROP TABLE IF EXISTS execution_slots;
+DROP TABLE IF EXISTS subflow_executions;
+DROP TABLE IF EXISTS flow_webhooks;
+DROP TABLE IF EXISTS execution_environments;
+DROP TABLE IF EXISTS execution_action_skips;
+DROP TABLE IF EXISTS manual_tasks;
+DROP TABLE IF EXISTS execution_locks;
+DROP TABLE IF EXISTS flow_trigger_runs;
+DROP TABLE IF EXISTS execution_outputs;
+DROP TABLE IF EXISTS input_presets;
+DROP TABLE IF EXISTS flow_promotions;
+DROP TABLE IF EXISTS execution_watches;
+DROP TABLE IF EXISTS execution_events;
+DROP TABLE IF EXISTS flow_favorites;
+DROP TABLE IF EXISTS namespace_variables;
+DROP TABLE IF EXISTS global_variables;
+DROP TABLE IF EXISTS namespace_executor_policies;
+DROP TABLE IF EXISTS namespace_defaults;
+DROP TABLE IF EXISTS execution_log_usage;
+DROP TABLE IF EXISTS namespace_execution_counts;
+DROP TABLE IF EXISTS namespace_quotas;
+DROP TABLE IF EXISTS flow_search;
+DROP TABLE IF EXISTS execution_retention_policies;
+DROP TABLE IF EXISTS execution_archive;
+DROP TABLE IF EXISTS cron_schedule_runs;
+DROP TABLE IF EXISTS execution_node_telemetry;
+DROP TABLE IF EXISTS sla_breaches;
+DROP TABLE IF EXISTS execution_timeline;
+DROP TABLE IF EXISTS flow_import_errors;
+DROP TABLE IF EXISTS flow_revisions;
+DROP TABLE IF EXISTS flow_versions;
+DROP TABLE IF EXISTS api_tokens;
+DROP TABLE IF EXISTS execution_progress;
+DROP TABLE IF EXISTS notification_deliveries;
+DROP TABLE IF EXISTS messenger_configs;
+DROP TABLE IF EXISTS approval_delegations;
+DROP TABLE IF EXISTS prefix_access;
+DROP TABLE IF EXISTS namespace_secrets;
+DROP TABLE IF EXISTS cron_schedules;
+DROP TABLE IF EXISTS scheduler_tasks;
+DROP TABLE IF EXISTS flow_secrets;
+DROP TABLE IF EXISTS casbin_rule;
+DROP TABLE IF EXISTS namespace_members;
+DROP TABLE IF EXISTS nodes;
+DROP TABLE IF EXISTS credentials;
+DROP TABLE IF EXISTS sessions;
+DROP TABLE IF EXISTS approvals;
+DROP TABLE IF EXISTS execution_log;
+DROP TABLE IF EXISTS group_memberships;
+DROP TABLE IF EXISTS groups;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS flows;
+DROP TABLE IF EXISTS flow_prefixes;
+DROP TABLE IF EXISTS namespaces;
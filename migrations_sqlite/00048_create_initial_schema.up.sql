@@ -0,0 +1,923 @@
+-- Schema of SQLite databases, equivalent to the PostgreSQL schema after migration 00048. It is
+-- numbered after that migration so that both databases report the same schema version, later
+-- PostgreSQL migrations need a SQLite migration with the same number.
+--
+-- UUIDs, JSON and enums are stored as TEXT, arrays as JSON arrays and timestamps
+-- as UTC TEXT that sorts in time order. now() and uuid_generate_v4() are provided by flowctl.
+
+CREATE TABLE IF NOT EXISTS namespaces (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    uuid TEXT NOT NULL DEFAULT (uuid_generate_v4()),
+    name VARCHAR(150) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    updated_at TIMESTAMP NOT NULL DEFAULT (now())
+);
+CREATE UNIQUE INDEX idx_namespaces_uuid ON namespaces(uuid);
+CREATE UNIQUE INDEX idx_namespaces_name ON namespaces(name);
+
+-- Create default namespace
+INSERT INTO namespaces (name) VALUES ('default') ON CONFLICT (name) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS flow_prefixes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    uuid TEXT NOT NULL DEFAULT (uuid_generate_v4()),
+    namespace_id INTEGER NOT NULL REFERENCES namespaces(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    updated_at TIMESTAMP NOT NULL DEFAULT (now()),
+    CONSTRAINT unique_prefix_per_namespace UNIQUE(namespace_id, name)
+);
+CREATE UNIQUE INDEX idx_flow_prefixes_uuid ON flow_prefixes(uuid);
+CREATE INDEX idx_flow_prefixes_namespace ON flow_prefixes(namespace_id);
+
+CREATE TABLE IF NOT EXISTS flows (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    slug VARCHAR(100) NOT NULL,
+    name VARCHAR(150) NOT NULL,
+    checksum VARCHAR(128) NOT NULL,
+    description TEXT,
+    file_path TEXT NOT NULL,
+    namespace_id INTEGER NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    updated_at TIMESTAMP NOT NULL DEFAULT (now()),
+    prefix_id INTEGER REFERENCES flow_prefixes(id) ON DELETE SET NULL,
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX idx_flows_slug_namespace ON flows(slug, namespace_id);
+CREATE INDEX idx_flows_namespace_id ON flows(namespace_id);
+CREATE INDEX idx_flows_prefix_id ON flows(namespace_id, prefix_id);
+
+CREATE TABLE IF NOT EXISTS users (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    uuid TEXT NOT NULL DEFAULT (uuid_generate_v4()),
+    name VARCHAR(150) NOT NULL,
+    username VARCHAR(255) NOT NULL,
+    password VARCHAR(255),
+    login_type TEXT NOT NULL DEFAULT 'standard' CHECK (login_type IN ('oidc', 'standard', 'token')),
+    role TEXT NOT NULL DEFAULT 'user' CHECK (role IN ('superuser', 'user')),
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    updated_at TIMESTAMP NOT NULL DEFAULT (now())
+);
+CREATE UNIQUE INDEX idx_users_uuid ON users(uuid);
+CREATE UNIQUE INDEX idx_users_username ON users(username);
+
+-- Create system user for scheduled executions
+INSERT INTO users (uuid, name, username, login_type, role)
+VALUES ('00000000-0000-0000-0000-000000000000', 'System', 'system', 'token', 'superuser')
+ON CONFLICT (uuid) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS groups (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    uuid TEXT NOT NULL DEFAULT (uuid_generate_v4()),
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    updated_at TIMESTAMP NOT NULL DEFAULT (now())
+);
+CREATE UNIQUE INDEX idx_groups_uuid ON groups(uuid);
+CREATE UNIQUE INDEX idx_groups_name ON groups(name);
+
+CREATE TABLE IF NOT EXISTS group_memberships (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    group_id INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (group_id) REFERENCES groups(id) ON DELETE CASCADE,
+    UNIQUE(user_id, group_id)
+);
+CREATE INDEX idx_group_memberships_user_id ON group_memberships(user_id);
+CREATE INDEX idx_group_memberships_group_id ON group_memberships(group_id);
+
+CREATE VIEW group_view AS
+SELECT
+    g.*,
+    CASE
+        WHEN COUNT(u.id) > 0 THEN json_group_array(json_object(
+            'id', u.id, 'uuid', u.uuid, 'name', u.name, 'username', u.username, 'password', u.password,
+            'login_type', u.login_type, 'role', u.role, 'created_at', u.created_at, 'updated_at', u.updated_at
+        ))
+        ELSE NULL
+    END AS users
+FROM
+    groups g
+LEFT JOIN
+    group_memberships gm ON g.id = gm.group_id
+LEFT JOIN
+    users u ON gm.user_id = u.id
+GROUP BY
+    g.id, g.uuid, g.name, g.description, g.created_at, g.updated_at;
+
+CREATE VIEW user_view AS
+SELECT
+    u.*,
+    CASE
+        WHEN COUNT(g.id) > 0 THEN json_group_array(json_object(
+            'id', g.id, 'uuid', g.uuid, 'name', g.name, 'description', g.description,
+            'created_at', g.created_at, 'updated_at', g.updated_at
+        ))
+        ELSE NULL
+    END AS groups
+FROM
+    users u
+LEFT JOIN
+    group_memberships gm ON u.id = gm.user_id
+LEFT JOIN
+    groups g ON gm.group_id = g.id
+GROUP BY
+    u.id, u.uuid, u.name, u.username, u.password, u.login_type, u.role, u.created_at, u.updated_at;
+
+CREATE TABLE IF NOT EXISTS execution_log (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    exec_id VARCHAR(36) NOT NULL,
+    flow_id INTEGER NOT NULL,
+    version INTEGER NOT NULL DEFAULT 0,
+    input TEXT NOT NULL DEFAULT '{}',
+    error TEXT,
+    current_action_id TEXT,
+    status TEXT NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('cancelled', 'completed', 'errored', 'pending', 'pending_approval', 'running')),
+    trigger_type TEXT NOT NULL DEFAULT 'manual' CHECK (trigger_type IN ('manual', 'scheduled')),
+    triggered_by INTEGER NOT NULL,
+    namespace_id INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    updated_at TIMESTAMP NOT NULL DEFAULT (now()),
+    completed_at TIMESTAMP,
+    action_retries TEXT DEFAULT '{}',
+    scheduled_at TIMESTAMP DEFAULT NULL,
+    started_at TIMESTAMP,
+    FOREIGN KEY (flow_id) REFERENCES flows(id) ON DELETE CASCADE,
+    FOREIGN KEY (triggered_by) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_execution_log_exec_id ON execution_log(exec_id);
+CREATE UNIQUE INDEX idx_execution_log_exec_id_version ON execution_log(exec_id, version);
+CREATE INDEX idx_execution_log_triggered_by ON execution_log(triggered_by);
+CREATE INDEX idx_execution_log_scheduled_at ON execution_log(scheduled_at) WHERE scheduled_at IS NOT NULL;
+CREATE INDEX idx_execution_log_updated_at ON execution_log(updated_at);
+CREATE INDEX idx_execution_log_namespace_created_at ON execution_log(namespace_id, created_at);
+CREATE INDEX idx_execution_log_triggered_by_created_at ON execution_log(triggered_by, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS approvals (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    uuid TEXT NOT NULL DEFAULT (uuid_generate_v4()),
+    exec_log_id INTEGER NOT NULL,
+    action_id VARCHAR(50) NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+    decided_by INTEGER,
+    namespace_id INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    updated_at TIMESTAMP NOT NULL DEFAULT (now()),
+    comment TEXT NOT NULL DEFAULT '',
+    FOREIGN KEY (exec_log_id) REFERENCES execution_log(id) ON DELETE CASCADE,
+    FOREIGN KEY (decided_by) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX idx_approvals_uuid ON approvals(uuid);
+CREATE UNIQUE INDEX idx_approvals_exec_action_id ON approvals(exec_log_id, action_id);
+
+CREATE TABLE IF NOT EXISTS sessions (
+    id TEXT NOT NULL PRIMARY KEY,
+    data TEXT NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT (now())
+);
+CREATE INDEX idx_sessions ON sessions (id, created_at);
+
+CREATE TABLE IF NOT EXISTS credentials (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    uuid TEXT NOT NULL DEFAULT (uuid_generate_v4()),
+    name VARCHAR(150) NOT NULL,
+    key_type VARCHAR(50) NOT NULL DEFAULT 'private_key',
+    key_data TEXT NOT NULL,
+    namespace_id INTEGER NOT NULL,
+    last_accessed TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    updated_at TIMESTAMP NOT NULL DEFAULT (now()),
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX idx_credentials_uuid ON credentials(uuid);
+CREATE UNIQUE INDEX idx_credentials_name_namespace ON credentials(name, namespace_id);
+CREATE INDEX idx_credentials_name ON credentials(name);
+CREATE INDEX idx_credentials_namespace_id ON credentials(namespace_id);
+
+CREATE TABLE IF NOT EXISTS nodes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    uuid TEXT NOT NULL DEFAULT (uuid_generate_v4()),
+    name VARCHAR(150) NOT NULL,
+    hostname VARCHAR(255) NOT NULL,
+    port INTEGER NOT NULL DEFAULT 22,
+    username VARCHAR(150) NOT NULL,
+    os_family VARCHAR(50) NOT NULL,
+    tags TEXT[],
+    auth_method TEXT NOT NULL DEFAULT 'private_key' CHECK (auth_method IN ('private_key', 'password')),
+    connection_type TEXT NOT NULL DEFAULT 'ssh' CHECK (connection_type IN ('ssh', 'qssh')),
+    credential_id INTEGER,
+    namespace_id INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    updated_at TIMESTAMP NOT NULL DEFAULT (now()),
+    FOREIGN KEY (credential_id) REFERENCES credentials(id) ON DELETE CASCADE,
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX idx_nodes_uuid ON nodes(uuid);
+CREATE UNIQUE INDEX idx_nodes_name_namespace ON nodes(name, namespace_id);
+CREATE INDEX idx_nodes_name ON nodes(name);
+CREATE INDEX idx_nodes_namespace_id ON nodes(namespace_id);
+
+CREATE TABLE IF NOT EXISTS namespace_members (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    uuid TEXT NOT NULL DEFAULT (uuid_generate_v4()),
+    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    group_id INTEGER REFERENCES groups(id) ON DELETE CASCADE,
+    namespace_id INTEGER NOT NULL REFERENCES namespaces(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('user', 'operator', 'reviewer', 'admin')),
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    updated_at TIMESTAMP NOT NULL DEFAULT (now()),
+
+    -- Constraint to ensure only one of user_id or group_id is set
+    CONSTRAINT check_single_subject CHECK (
+        (user_id IS NOT NULL AND group_id IS NULL) OR
+        (user_id IS NULL AND group_id IS NOT NULL)
+    ),
+
+    CONSTRAINT unique_user_namespace UNIQUE(user_id, namespace_id),
+    CONSTRAINT unique_group_namespace UNIQUE(group_id, namespace_id)
+);
+CREATE INDEX idx_namespace_members_namespace ON namespace_members(namespace_id);
+CREATE INDEX idx_namespace_members_uuid ON namespace_members(uuid);
+CREATE INDEX idx_namespace_members_user ON namespace_members(user_id) WHERE user_id IS NOT NULL;
+CREATE INDEX idx_namespace_members_group ON namespace_members(group_id) WHERE group_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS casbin_rule (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    ptype VARCHAR(100),
+    v0 VARCHAR(100),
+    v1 VARCHAR(100),
+    v2 VARCHAR(100),
+    v3 VARCHAR(100),
+    v4 VARCHAR(100),
+    v5 VARCHAR(100),
+    CONSTRAINT idx_casbin_rule UNIQUE(ptype, v0, v1, v2, v3, v4, v5)
+);
+
+CREATE TABLE IF NOT EXISTS flow_secrets (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    uuid TEXT NOT NULL DEFAULT (uuid_generate_v4()),
+    flow_id INTEGER NOT NULL,
+    key VARCHAR(255) NOT NULL,
+    encrypted_value TEXT NOT NULL,
+    description TEXT,
+    namespace_id INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    updated_at TIMESTAMP NOT NULL DEFAULT (now()),
+    FOREIGN KEY (flow_id) REFERENCES flows(id) ON DELETE CASCADE,
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE,
+    UNIQUE(flow_id, key, namespace_id)
+);
+CREATE UNIQUE INDEX idx_flow_secrets_uuid ON flow_secrets(uuid);
+CREATE INDEX idx_flow_secrets_flow_id ON flow_secrets(flow_id);
+CREATE INDEX idx_flow_secrets_namespace_id ON flow_secrets(namespace_id);
+
+CREATE TABLE IF NOT EXISTS scheduler_tasks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    uuid TEXT NOT NULL DEFAULT (uuid_generate_v4()),
+    exec_id VARCHAR(36) NOT NULL,
+    payload TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMP NOT NULL DEFAULT (now())
+);
+CREATE UNIQUE INDEX idx_scheduler_tasks_uuid ON scheduler_tasks(uuid);
+CREATE INDEX idx_scheduler_tasks_status ON scheduler_tasks(status);
+CREATE INDEX idx_scheduler_tasks_exec_id ON scheduler_tasks(exec_id);
+
+CREATE TABLE IF NOT EXISTS cron_schedules (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    flow_id INTEGER NOT NULL REFERENCES flows(id) ON DELETE CASCADE,
+    cron VARCHAR(255) NOT NULL,
+    timezone VARCHAR(100) NOT NULL DEFAULT 'UTC',
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    updated_at TIMESTAMP NOT NULL DEFAULT (now()),
+    uuid TEXT NOT NULL DEFAULT (uuid_generate_v4()),
+    inputs TEXT DEFAULT '{}',
+    created_by INTEGER NOT NULL DEFAULT 1 REFERENCES users(id) ON DELETE CASCADE,
+    is_user_created BOOLEAN NOT NULL DEFAULT FALSE,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE
+);
+CREATE INDEX idx_cron_schedules_flow_id ON cron_schedules(flow_id);
+CREATE UNIQUE INDEX idx_cron_schedules_uuid ON cron_schedules(uuid);
+CREATE INDEX idx_cron_schedules_created_by ON cron_schedules(created_by);
+CREATE INDEX idx_cron_schedules_is_user_created ON cron_schedules(is_user_created);
+CREATE INDEX idx_cron_schedules_is_active ON cron_schedules(is_active, flow_id);
+CREATE INDEX idx_cron_schedules_user_active ON cron_schedules(created_by, is_active) WHERE is_user_created = TRUE;
+
+CREATE TABLE IF NOT EXISTS namespace_secrets (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    uuid TEXT NOT NULL DEFAULT (uuid_generate_v4()),
+    key VARCHAR(255) NOT NULL,
+    encrypted_value TEXT NOT NULL,
+    description TEXT,
+    namespace_id INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    updated_at TIMESTAMP NOT NULL DEFAULT (now()),
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE,
+    UNIQUE(key, namespace_id)
+);
+CREATE UNIQUE INDEX idx_namespace_secrets_uuid ON namespace_secrets(uuid);
+CREATE INDEX idx_namespace_secrets_namespace_id ON namespace_secrets(namespace_id);
+
+CREATE TABLE IF NOT EXISTS prefix_access (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    uuid TEXT NOT NULL DEFAULT (uuid_generate_v4()),
+    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    group_id INTEGER REFERENCES groups(id) ON DELETE CASCADE,
+    namespace_id INTEGER NOT NULL REFERENCES namespaces(id) ON DELETE CASCADE,
+    prefix_id INTEGER NOT NULL REFERENCES flow_prefixes(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    CONSTRAINT check_single_subject CHECK (
+        (user_id IS NOT NULL AND group_id IS NULL) OR
+        (user_id IS NULL AND group_id IS NOT NULL)
+    ),
+    CONSTRAINT unique_user_prefix UNIQUE(user_id, namespace_id, prefix_id),
+    CONSTRAINT unique_group_prefix UNIQUE(group_id, namespace_id, prefix_id)
+);
+CREATE UNIQUE INDEX idx_prefix_access_uuid ON prefix_access(uuid);
+CREATE INDEX idx_prefix_access_namespace ON prefix_access(namespace_id);
+CREATE INDEX idx_prefix_access_user ON prefix_access(user_id) WHERE user_id IS NOT NULL;
+CREATE INDEX idx_prefix_access_group ON prefix_access(group_id) WHERE group_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS approval_delegations (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    uuid TEXT NOT NULL DEFAULT (uuid_generate_v4()),
+    namespace_id INTEGER NOT NULL,
+    delegator_id INTEGER NOT NULL,
+    delegate_user_id INTEGER,
+    delegate_group_id INTEGER,
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE,
+    FOREIGN KEY (delegator_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (delegate_user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (delegate_group_id) REFERENCES groups(id) ON DELETE CASCADE,
+    CHECK ((delegate_user_id IS NULL) <> (delegate_group_id IS NULL)),
+    CHECK (ends_at > starts_at)
+);
+CREATE UNIQUE INDEX idx_approval_delegations_uuid ON approval_delegations(uuid);
+CREATE INDEX idx_approval_delegations_namespace_id ON approval_delegations(namespace_id);
+CREATE INDEX idx_approval_delegations_delegator_id ON approval_delegations(delegator_id);
+
+CREATE TABLE IF NOT EXISTS messenger_configs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    channel VARCHAR(50) NOT NULL UNIQUE,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    encrypted_config TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    updated_at TIMESTAMP NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE IF NOT EXISTS notification_deliveries (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    uuid TEXT NOT NULL DEFAULT (uuid_generate_v4()),
+    notification_id TEXT NOT NULL,
+    namespace_id INTEGER NOT NULL,
+    exec_id VARCHAR(36) NOT NULL,
+    flow_id VARCHAR(150) NOT NULL,
+    channel VARCHAR(50) NOT NULL,
+    receiver TEXT NOT NULL,
+    event VARCHAR(50) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 1,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    updated_at TIMESTAMP NOT NULL DEFAULT (now()),
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE,
+    UNIQUE(notification_id, receiver),
+    CHECK (status IN ('delivered', 'retrying', 'failed'))
+);
+CREATE UNIQUE INDEX idx_notification_deliveries_uuid ON notification_deliveries(uuid);
+CREATE INDEX idx_notification_deliveries_namespace_status ON notification_deliveries(namespace_id, status);
+CREATE INDEX idx_notification_deliveries_exec_id ON notification_deliveries(exec_id);
+
+CREATE TABLE IF NOT EXISTS execution_progress (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    exec_id VARCHAR(36) NOT NULL UNIQUE,
+    namespace_id INTEGER NOT NULL,
+    progress TEXT NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT (now()),
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS api_tokens (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    uuid TEXT NOT NULL DEFAULT (uuid_generate_v4()),
+    user_id INTEGER NOT NULL,
+    name VARCHAR(150) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMP,
+    last_used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX idx_api_tokens_uuid ON api_tokens(uuid);
+CREATE UNIQUE INDEX idx_api_tokens_token_hash ON api_tokens(token_hash);
+CREATE INDEX idx_api_tokens_user_id ON api_tokens(user_id);
+
+CREATE TABLE IF NOT EXISTS flow_versions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    flow_id INTEGER NOT NULL,
+    checksum VARCHAR(64) NOT NULL,
+    commit_sha VARCHAR(64),
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    content TEXT,
+    format VARCHAR(10),
+    FOREIGN KEY (flow_id) REFERENCES flows(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_flow_versions_flow_id ON flow_versions(flow_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS flow_revisions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    uuid TEXT NOT NULL DEFAULT (uuid_generate_v4()),
+    flow_id INTEGER NOT NULL,
+    namespace_id INTEGER NOT NULL,
+    content TEXT NOT NULL,
+    format VARCHAR(10) NOT NULL,
+    checksum VARCHAR(64) NOT NULL,
+    commit_sha VARCHAR(64),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    requested_by INTEGER,
+    reviewed_by INTEGER,
+    comment TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    reviewed_at TIMESTAMP,
+    FOREIGN KEY (flow_id) REFERENCES flows(id) ON DELETE CASCADE,
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE,
+    FOREIGN KEY (requested_by) REFERENCES users(id) ON DELETE SET NULL,
+    FOREIGN KEY (reviewed_by) REFERENCES users(id) ON DELETE SET NULL,
+    CONSTRAINT unique_flow_revision_uuid UNIQUE (uuid),
+    CHECK (status IN ('pending', 'approved', 'rejected', 'superseded'))
+);
+CREATE INDEX idx_flow_revisions_namespace ON flow_revisions(namespace_id, status, created_at DESC);
+-- A flow has at most one revision waiting for review
+CREATE UNIQUE INDEX idx_flow_revisions_pending ON flow_revisions(flow_id) WHERE status = 'pending';
+
+CREATE TABLE IF NOT EXISTS flow_import_errors (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    namespace_id INTEGER NOT NULL,
+    file_path TEXT NOT NULL,
+    error TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE,
+    CONSTRAINT unique_flow_import_error_file UNIQUE (namespace_id, file_path)
+);
+
+CREATE TABLE IF NOT EXISTS execution_timeline (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    exec_id VARCHAR(36) NOT NULL,
+    namespace_id INTEGER NOT NULL,
+    action_id VARCHAR(150) NOT NULL,
+    node TEXT NOT NULL DEFAULT '',
+    retry INTEGER NOT NULL DEFAULT 0,
+    status TEXT NOT NULL DEFAULT 'running',
+    error TEXT,
+    started_at TIMESTAMP NOT NULL DEFAULT (now()),
+    finished_at TIMESTAMP,
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE,
+    CONSTRAINT unique_execution_timeline_entry UNIQUE (exec_id, action_id, node, retry)
+);
+
+CREATE TABLE IF NOT EXISTS sla_breaches (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    exec_id VARCHAR(36) NOT NULL,
+    namespace_id INTEGER NOT NULL,
+    flow_slug VARCHAR(150) NOT NULL,
+    kind TEXT NOT NULL,
+    reason TEXT NOT NULL,
+    detected_at TIMESTAMP NOT NULL DEFAULT (now()),
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE,
+    CONSTRAINT unique_sla_breach UNIQUE (exec_id, kind)
+);
+
+CREATE TABLE IF NOT EXISTS execution_node_telemetry (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    exec_id VARCHAR(36) NOT NULL,
+    namespace_id INTEGER NOT NULL,
+    action_id VARCHAR(150) NOT NULL,
+    node TEXT NOT NULL DEFAULT '',
+    sampled_at TIMESTAMP NOT NULL,
+    cpu_percent REAL NOT NULL,
+    memory_percent REAL NOT NULL,
+    disk_percent REAL NOT NULL,
+    load1 REAL NOT NULL,
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_execution_node_telemetry_exec_id ON execution_node_telemetry(exec_id);
+
+CREATE TABLE IF NOT EXISTS cron_schedule_runs (
+    schedule_id INTEGER PRIMARY KEY REFERENCES cron_schedules(id) ON DELETE CASCADE,
+    -- Purging an execution clears it from the last run of its cron schedule
+    exec_id VARCHAR(36),
+    fired_at TIMESTAMP NOT NULL,
+    started_at TIMESTAMP NOT NULL,
+    max_drift_ms BIGINT NOT NULL DEFAULT 0,
+    run_count BIGINT NOT NULL DEFAULT 0
+);
+
+-- Summaries of executions moved out of execution_log by the archiver.
+-- They don't reference flows or users so that they outlive them.
+CREATE TABLE IF NOT EXISTS execution_archive (
+    exec_id VARCHAR(36) PRIMARY KEY,
+    namespace_id INTEGER NOT NULL,
+    flow_slug VARCHAR(150) NOT NULL,
+    flow_name VARCHAR(150) NOT NULL,
+    status TEXT NOT NULL
+        CHECK (status IN ('cancelled', 'completed', 'errored', 'pending', 'pending_approval', 'running')),
+    trigger_type TEXT NOT NULL CHECK (trigger_type IN ('manual', 'scheduled')),
+    triggered_by_uuid TEXT NOT NULL,
+    triggered_by_name TEXT NOT NULL,
+    input TEXT NOT NULL DEFAULT '{}',
+    error TEXT,
+    current_action_id TEXT,
+    action_retries TEXT DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL,
+    started_at TIMESTAMP,
+    completed_at TIMESTAMP,
+    scheduled_at TIMESTAMP,
+    archived_at TIMESTAMP NOT NULL DEFAULT (now()),
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_execution_archive_namespace_created_at ON execution_archive(namespace_id, created_at DESC);
+
+-- Per namespace limits on how long finished executions are kept.
+-- At least one of max_age_days and keep_last is set.
+CREATE TABLE IF NOT EXISTS execution_retention_policies (
+    namespace_id INTEGER PRIMARY KEY,
+    max_age_days INTEGER CHECK (max_age_days > 0),
+    keep_last INTEGER CHECK (keep_last > 0),
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    updated_at TIMESTAMP NOT NULL DEFAULT (now()),
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE,
+    CHECK (max_age_days IS NOT NULL OR keep_last IS NOT NULL)
+);
+
+-- Content of flows searched by flow search. document is the lowercased content.
+CREATE TABLE IF NOT EXISTS flow_search (
+    flow_id INTEGER PRIMARY KEY,
+    content TEXT NOT NULL DEFAULT '',
+    document TEXT NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT (now()),
+    FOREIGN KEY (flow_id) REFERENCES flows(id) ON DELETE CASCADE
+);
+
+-- Per namespace limits on executions, nodes and log storage.
+-- At least one limit is set.
+CREATE TABLE IF NOT EXISTS namespace_quotas (
+    namespace_id INTEGER PRIMARY KEY,
+    max_executions_per_day INTEGER CHECK (max_executions_per_day > 0),
+    max_concurrent_executions INTEGER CHECK (max_concurrent_executions > 0),
+    max_nodes INTEGER CHECK (max_nodes > 0),
+    max_log_bytes BIGINT CHECK (max_log_bytes > 0),
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    updated_at TIMESTAMP NOT NULL DEFAULT (now()),
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE,
+    CHECK (
+        max_executions_per_day IS NOT NULL OR max_concurrent_executions IS NOT NULL
+        OR max_nodes IS NOT NULL OR max_log_bytes IS NOT NULL
+    )
+);
+
+-- Executions started in a namespace per UTC day. Purged executions are still counted.
+CREATE TABLE IF NOT EXISTS namespace_execution_counts (
+    namespace_id INTEGER NOT NULL,
+    day DATE NOT NULL,
+    executions INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (namespace_id, day),
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE
+);
+
+-- Bytes of logs written by each execution, removed when the execution is purged
+CREATE TABLE IF NOT EXISTS execution_log_usage (
+    exec_id VARCHAR(36) PRIMARY KEY,
+    namespace_id INTEGER NOT NULL,
+    log_bytes BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL DEFAULT (now()),
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_execution_log_usage_namespace_id ON execution_log_usage(namespace_id);
+
+-- Settings inherited by the flows of a namespace unless a flow sets its own.
+-- executor_options holds default action options keyed by executor name.
+CREATE TABLE IF NOT EXISTS namespace_defaults (
+    namespace_id INTEGER PRIMARY KEY,
+    timezone TEXT,
+    notify TEXT NOT NULL DEFAULT '[]',
+    executor_options TEXT NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    updated_at TIMESTAMP NOT NULL DEFAULT (now()),
+    -- Maximum number of executions of the namespace that run at the same time, 0 is unlimited
+    max_concurrency INTEGER NOT NULL DEFAULT 0,
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE
+);
+
+-- Executors the flows of a namespace may use. Namespaces without a policy may use every executor.
+CREATE TABLE IF NOT EXISTS namespace_executor_policies (
+    namespace_id INTEGER PRIMARY KEY,
+    allowed_executors TEXT[] NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    updated_at TIMESTAMP NOT NULL DEFAULT (now()),
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE,
+    CHECK (allowed_executors <> '[]')
+);
+
+-- Plain configuration values available to flow expressions as vars.<key>.
+-- Namespace variables take precedence over global variables with the same key.
+CREATE TABLE IF NOT EXISTS global_variables (
+    key VARCHAR(150) PRIMARY KEY,
+    value TEXT NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    updated_at TIMESTAMP NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE IF NOT EXISTS namespace_variables (
+    namespace_id INTEGER NOT NULL,
+    key VARCHAR(150) NOT NULL,
+    value TEXT NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    updated_at TIMESTAMP NOT NULL DEFAULT (now()),
+    PRIMARY KEY (namespace_id, key),
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE
+);
+
+-- Flows starred by users so they can find the flows they use among every flow they can access
+CREATE TABLE IF NOT EXISTS flow_favorites (
+    user_id INTEGER NOT NULL,
+    flow_id INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    PRIMARY KEY (user_id, flow_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (flow_id) REFERENCES flows(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_flow_favorites_flow_id ON flow_favorites(flow_id);
+
+-- Execution status changes, read by the server to stream them to clients. SQLite has no
+-- LISTEN/NOTIFY, the triggers below record the payload PostgreSQL publishes instead.
+CREATE TABLE IF NOT EXISTS execution_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    payload TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (now())
+);
+
+CREATE TRIGGER execution_log_events_insert
+AFTER INSERT ON execution_log
+-- Executions scheduled for later are announced once they start
+WHEN NEW.scheduled_at IS NULL OR NEW.scheduled_at <= now()
+BEGIN
+    INSERT INTO execution_events (payload) VALUES (json_object(
+        'exec_id', NEW.exec_id,
+        'namespace_id', (SELECT uuid FROM namespaces WHERE id = NEW.namespace_id),
+        'flow_id', (SELECT slug FROM flows WHERE id = NEW.flow_id),
+        'status', NEW.status,
+        'triggered_by', (SELECT uuid FROM users WHERE id = NEW.triggered_by),
+        'timestamp', strftime('%Y-%m-%dT%H:%M:%fZ', 'now')
+    ));
+END;
+
+CREATE TRIGGER execution_log_events_update
+AFTER UPDATE OF status ON execution_log
+WHEN NEW.status IS NOT OLD.status AND (NEW.scheduled_at IS NULL OR NEW.scheduled_at <= now())
+BEGIN
+    INSERT INTO execution_events (payload) VALUES (json_object(
+        'exec_id', NEW.exec_id,
+        'namespace_id', (SELECT uuid FROM namespaces WHERE id = NEW.namespace_id),
+        'flow_id', (SELECT slug FROM flows WHERE id = NEW.flow_id),
+        'status', NEW.status,
+        'triggered_by', (SELECT uuid FROM users WHERE id = NEW.triggered_by),
+        'timestamp', strftime('%Y-%m-%dT%H:%M:%fZ', 'now')
+    ));
+END;
+
+-- Flows and executions users are notified about even if they didn't trigger them
+CREATE TABLE IF NOT EXISTS execution_watches (
+    user_id INTEGER NOT NULL,
+    flow_id INTEGER NOT NULL,
+    -- Watches without an execution cover every execution of the flow
+    exec_id VARCHAR(36),
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (flow_id) REFERENCES flows(id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX idx_execution_watches_target ON execution_watches(user_id, flow_id, COALESCE(exec_id, ''));
+CREATE INDEX idx_execution_watches_flow_id ON execution_watches(flow_id);
+
+-- Records the flows promoted from one namespace to another, such as from staging to production,
+-- so that the path of a flow through namespaces can be traced
+CREATE TABLE IF NOT EXISTS flow_promotions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    source_flow_id INTEGER NOT NULL,
+    -- Checksum of the source flow file when it was promoted
+    source_checksum VARCHAR(64) NOT NULL,
+    target_flow_id INTEGER NOT NULL,
+    -- Set if the target namespace requires flow reviews and the promotion replaced an existing flow
+    revision_uuid TEXT,
+    promoted_by INTEGER,
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    FOREIGN KEY (source_flow_id) REFERENCES flows(id) ON DELETE CASCADE,
+    FOREIGN KEY (target_flow_id) REFERENCES flows(id) ON DELETE CASCADE,
+    FOREIGN KEY (promoted_by) REFERENCES users(id) ON DELETE SET NULL
+);
+CREATE INDEX idx_flow_promotions_source_flow_id ON flow_promotions(source_flow_id, created_at DESC);
+CREATE INDEX idx_flow_promotions_target_flow_id ON flow_promotions(target_flow_id, created_at DESC);
+
+-- Named input values users can trigger a flow with
+CREATE TABLE IF NOT EXISTS input_presets (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    uuid TEXT NOT NULL DEFAULT (uuid_generate_v4()),
+    flow_id INTEGER NOT NULL,
+    name VARCHAR(150) NOT NULL,
+    inputs TEXT NOT NULL DEFAULT '{}',
+    -- Shared presets can be used by every member of the namespace
+    is_shared BOOLEAN NOT NULL DEFAULT FALSE,
+    created_by INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    updated_at TIMESTAMP NOT NULL DEFAULT (now()),
+    FOREIGN KEY (flow_id) REFERENCES flows(id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX idx_input_presets_uuid ON input_presets(uuid);
+CREATE UNIQUE INDEX idx_input_presets_name ON input_presets(flow_id, created_by, name);
+
+-- Results of the actions of executions, masked like they are in the logs
+CREATE TABLE IF NOT EXISTS execution_outputs (
+    exec_id VARCHAR(36) NOT NULL,
+    namespace_id INTEGER NOT NULL,
+    action_id VARCHAR(150) NOT NULL,
+    outputs TEXT NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    PRIMARY KEY (exec_id, action_id),
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE
+);
+
+-- Executions queued by the triggers of flows. An execution triggers a flow at most once.
+CREATE TABLE IF NOT EXISTS flow_trigger_runs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    upstream_exec_id VARCHAR(36) NOT NULL,
+    exec_id VARCHAR(36) NOT NULL,
+    flow_id INTEGER NOT NULL,
+    -- Number of triggered executions in the chain up to and including this one
+    depth INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    FOREIGN KEY (flow_id) REFERENCES flows(id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX idx_flow_trigger_runs_exec_id ON flow_trigger_runs(exec_id);
+CREATE UNIQUE INDEX idx_flow_trigger_runs_upstream ON flow_trigger_runs(upstream_exec_id, flow_id);
+
+-- Mutex keys held by running executions. Executions of any flow in a namespace that declare the
+-- same key run one at a time.
+CREATE TABLE IF NOT EXISTS execution_locks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    namespace_id INTEGER NOT NULL,
+    lock_key VARCHAR(100) NOT NULL,
+    exec_id VARCHAR(36) NOT NULL,
+    acquired_at TIMESTAMP NOT NULL DEFAULT (now()),
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX idx_execution_locks_key ON execution_locks(namespace_id, lock_key);
+CREATE INDEX idx_execution_locks_exec_id ON execution_locks(exec_id);
+
+-- Tasks of manual actions. An execution waits on a pending task until a member of the assigned
+-- group completes it, the response becomes the outputs of the action.
+CREATE TABLE IF NOT EXISTS manual_tasks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    uuid TEXT NOT NULL DEFAULT (uuid_generate_v4()),
+    exec_id VARCHAR(36) NOT NULL,
+    action_id VARCHAR(150) NOT NULL,
+    namespace_id INTEGER NOT NULL,
+    -- The with block of the action: instructions, group, checklist and inputs
+    definition TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'completed', 'cancelled')),
+    response TEXT,
+    completed_by INTEGER,
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    updated_at TIMESTAMP NOT NULL DEFAULT (now()),
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE,
+    FOREIGN KEY (completed_by) REFERENCES users(id) ON DELETE SET NULL
+);
+CREATE UNIQUE INDEX idx_manual_tasks_uuid ON manual_tasks(uuid);
+CREATE UNIQUE INDEX idx_manual_tasks_exec_action ON manual_tasks(exec_id, action_id);
+CREATE INDEX idx_manual_tasks_namespace_status ON manual_tasks(namespace_id, status);
+
+-- Failed actions that were skipped when their execution was retried, and who skipped them
+CREATE TABLE IF NOT EXISTS execution_action_skips (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    exec_id VARCHAR(36) NOT NULL,
+    action_id VARCHAR(150) NOT NULL,
+    namespace_id INTEGER NOT NULL,
+    skipped_by INTEGER,
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE,
+    FOREIGN KEY (skipped_by) REFERENCES users(id) ON DELETE SET NULL
+);
+CREATE INDEX idx_execution_action_skips_exec_id ON execution_action_skips(exec_id);
+
+-- Runtime details of the nodes the actions of executions ran on, kept to investigate how an
+-- execution can be reproduced. A retried action replaces the details of its previous run.
+CREATE TABLE IF NOT EXISTS execution_environments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    exec_id VARCHAR(36) NOT NULL,
+    namespace_id INTEGER NOT NULL,
+    action_id VARCHAR(150) NOT NULL,
+    node TEXT NOT NULL DEFAULT '',
+    hostname TEXT NOT NULL DEFAULT '',
+    os TEXT NOT NULL DEFAULT '',
+    executor VARCHAR(150) NOT NULL,
+    executor_version TEXT NOT NULL DEFAULT '',
+    image TEXT NOT NULL DEFAULT '',
+    image_digest TEXT NOT NULL DEFAULT '',
+    flow_checksum VARCHAR(128) NOT NULL DEFAULT '',
+    recorded_at TIMESTAMP NOT NULL DEFAULT (now()),
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX idx_execution_environments_exec_action_node ON execution_environments(exec_id, action_id, node);
+
+-- Webhooks that queue executions of flows. Only the hash of the token in the URL of a webhook is
+-- stored, the secret that signs its requests is encrypted with the keeper.
+CREATE TABLE IF NOT EXISTS flow_webhooks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    flow_id INTEGER NOT NULL,
+    token_hash VARCHAR(64) NOT NULL,
+    encrypted_secret TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    last_used_at TIMESTAMP,
+    FOREIGN KEY (flow_id) REFERENCES flows(id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX idx_flow_webhooks_flow_id ON flow_webhooks(flow_id);
+
+-- Executions started by the flow actions of other executions. The parent execution waits until
+-- the execution finishes and then takes over its outputs, which resolves the link.
+CREATE TABLE IF NOT EXISTS subflow_executions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    parent_exec_id VARCHAR(36) NOT NULL,
+    parent_action_id VARCHAR(150) NOT NULL,
+    exec_id VARCHAR(36) NOT NULL,
+    namespace_id INTEGER NOT NULL,
+    -- Number of executions in the chain of sub-flows up to and including this one
+    depth INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    resolved_at TIMESTAMP,
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX idx_subflow_executions_exec_id ON subflow_executions(exec_id);
+-- An action of a parent execution waits on at most one execution at a time
+CREATE UNIQUE INDEX idx_subflow_executions_unresolved ON subflow_executions(parent_exec_id, parent_action_id) WHERE resolved_at IS NULL;
+
+-- Slots held by the executions that were picked from the job queue and are running. Jobs of
+-- flows or namespaces whose slots are all taken stay queued.
+CREATE TABLE IF NOT EXISTS execution_slots (
+    exec_id VARCHAR(36) PRIMARY KEY,
+    namespace_id INTEGER NOT NULL,
+    flow_slug VARCHAR(150) NOT NULL,
+    acquired_at TIMESTAMP NOT NULL DEFAULT (now()),
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_execution_slots_namespace_flow ON execution_slots(namespace_id, flow_slug);
+
+-- Files published by the actions of executions. The files are kept in the artifact archive
+-- under object_key after the execution finished, name is their path in the artifact directory.
+CREATE TABLE IF NOT EXISTS artifacts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    uuid TEXT NOT NULL DEFAULT (uuid_generate_v4()),
+    exec_id VARCHAR(36) NOT NULL,
+    namespace_id INTEGER NOT NULL,
+    action_id VARCHAR(150) NOT NULL,
+    name TEXT NOT NULL,
+    object_key TEXT NOT NULL,
+    size BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    FOREIGN KEY (namespace_id) REFERENCES namespaces(id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX idx_artifacts_uuid ON artifacts(uuid);
+-- A retried action replaces the files it published in the earlier attempt
+CREATE UNIQUE INDEX idx_artifacts_exec_action_name ON artifacts(exec_id, action_id, name);
+
+-- Changes made through the API, with the state of the changed resource before and after the
+-- change. The actor and the namespace are copied so that entries outlive the users and
+-- namespaces they refer to.
+CREATE TABLE IF NOT EXISTS audit_log (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    -- NULL for changes outside of a namespace, like users and groups
+    namespace_uuid TEXT,
+    actor_uuid TEXT NOT NULL,
+    actor_name VARCHAR(150) NOT NULL,
+    action VARCHAR(100) NOT NULL,
+    resource_type VARCHAR(50) NOT NULL,
+    resource_id VARCHAR(150) NOT NULL,
+    before TEXT,
+    after TEXT,
+    source_ip VARCHAR(64) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT (now())
+);
+CREATE INDEX idx_audit_log_created_at ON audit_log(created_at DESC);
+CREATE INDEX idx_audit_log_namespace_created_at ON audit_log(namespace_uuid, created_at DESC);
@@ -0,0 +1,46 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// NodeEnvironment is the hostname and operating system of a node
+type NodeEnvironment struct {
+	Hostname string
+	// OS is the name and release of the operating system, e.g. Linux 6.8.0-45-generic x86_64
+	OS string
+}
+
+const (
+	linuxEnvironmentCommand   = "hostname; uname -srm"
+	windowsEnvironmentCommand = "hostname; (Get-CimInstance Win32_OperatingSystem).Caption + ' ' + [System.Environment]::OSVersion.Version"
+)
+
+// DescribeNode returns the hostname and operating system of the node the driver is connected to
+func DescribeNode(ctx context.Context, driver NodeDriver) (NodeEnvironment, error) {
+	cmd := linuxEnvironmentCommand
+	if _, ok := driver.(*RemoteWindowsDriver); ok {
+		cmd = windowsEnvironmentCommand
+	}
+
+	var out strings.Builder
+	if err := driver.Exec(ctx, cmd, "", nil, &out, io.Discard); err != nil {
+		return NodeEnvironment{}, fmt.Errorf("failed to describe node: %w", err)
+	}
+
+	var lines []string
+	for _, line := range strings.Split(out.String(), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) < 2 {
+		return NodeEnvironment{}, errors.New("failed to describe node: unexpected output")
+	}
+
+	return NodeEnvironment{Hostname: lines[0], OS: lines[1]}, nil
+}
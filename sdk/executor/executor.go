@@ -72,3 +72,16 @@ type Executor interface {
 	GetArtifactsDir() string
 	Close() error
 }
+
+// EnvironmentReporter is implemented by executors that resolve runtime details while they run,
+// such as the digest of the image that was pulled. The details are recorded with the
+// environment of the execution.
+type EnvironmentReporter interface {
+	Environment() map[string]string
+}
+
+// Keys of the details reported by executors
+const (
+	EnvironmentImage       = "image"
+	EnvironmentImageDigest = "image_digest"
+)
@@ -50,9 +50,13 @@ func NewNodeDriver(ctx context.Context, node Node) (NodeDriver, error) {
 		return nil, fmt.Errorf("failed to create remote client: %w", err)
 	}
 
-	if node.OSFamily == "windows" {
-		remoteClient.Close()
-		return nil, fmt.Errorf("windows remote execution not yet supported")
+	if node.OSFamily == OSFamilyWindows {
+		driver, err := NewRemoteWindows(remoteClient)
+		if err != nil {
+			remoteClient.Close()
+			return nil, err
+		}
+		return driver, nil
 	}
 
 	return NewRemoteLinux(remoteClient)
@@ -13,9 +13,11 @@ var (
 	registry       = make(map[string]NewExecutorFunc)
 	schemaRegistry = make(map[string]interface{})
 	capRegistry    = make(map[string]Capability)
+	verRegistry    = make(map[string]string)
 	mu             sync.RWMutex
 	smu            sync.RWMutex
 	cmu            sync.RWMutex
+	vmu            sync.RWMutex
 )
 
 var validNameRegex = regexp.MustCompile(`^[a-zA-Z_]+$`)
@@ -86,6 +88,29 @@ func GetCapabilities(name string) (Capability, error) {
 	return caps, nil
 }
 
+// RegisterVersion registers the version of a named executor. Built-in executors have the version
+// of flowctl and external plugins the checksum of their binary.
+func RegisterVersion(name string, version string) {
+	vmu.Lock()
+	defer vmu.Unlock()
+
+	mu.RLock()
+	if _, exists := registry[name]; !exists {
+		panic(fmt.Sprintf("executor '%s' is not registered, cannot register version", name))
+	}
+	mu.RUnlock()
+
+	verRegistry[name] = version
+}
+
+// GetVersion returns the version of a named executor, empty if it was not registered
+func GetVersion(name string) string {
+	vmu.RLock()
+	defer vmu.RUnlock()
+
+	return verRegistry[name]
+}
+
 // GetSchema returns the config schema of the executor
 func GetSchema(name string) (interface{}, error) {
 	smu.RLock()
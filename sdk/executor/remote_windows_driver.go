@@ -0,0 +1,196 @@
+package executor
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/cvhariharan/flowctl/sdk/remoteclient"
+	"github.com/rs/xid"
+)
+
+// OSFamilyWindows is the OS family of nodes running Windows
+const OSFamilyWindows = "windows"
+
+// defaultWindowsTempDir is used when the temp directory of the node can't be read
+const defaultWindowsTempDir = `C:\Windows\Temp`
+
+// RemoteWindowsDriver runs commands on Windows nodes through PowerShell. The nodes are expected to
+// run the OpenSSH server that ships with Windows, with SFTP enabled.
+type RemoteWindowsDriver struct {
+	client           remoteclient.RemoteClient
+	workingDirectory string
+	tempDir          string
+}
+
+func NewRemoteWindows(client remoteclient.RemoteClient) (NodeDriver, error) {
+	r := &RemoteWindowsDriver{
+		client:  client,
+		tempDir: defaultWindowsTempDir,
+	}
+
+	var out strings.Builder
+	if err := r.Exec(context.Background(), "[System.IO.Path]::GetTempPath()", "", nil, &out, io.Discard); err == nil {
+		if dir := strings.TrimRight(strings.TrimSpace(out.String()), `\`); dir != "" {
+			r.tempDir = dir
+		}
+	}
+
+	wd := r.Join(r.TempDir(), fmt.Sprintf("flows-%s", xid.New().String()))
+	if err := r.CreateDir(context.Background(), wd); err != nil {
+		return nil, err
+	}
+	r.workingDirectory = wd
+	return r, nil
+}
+
+func (d *RemoteWindowsDriver) GetWorkingDirectory() string {
+	return d.workingDirectory
+}
+
+func (d *RemoteWindowsDriver) Upload(ctx context.Context, localPath, remotePath string) error {
+	if err := d.CreateDir(ctx, windowsDir(remotePath)); err != nil {
+		return fmt.Errorf("failed to create destination directory: %w", err)
+	}
+
+	return d.client.Upload(ctx, localPath, sftpPath(remotePath))
+}
+
+func (d *RemoteWindowsDriver) Download(ctx context.Context, remotePath, localPath string) error {
+	return d.client.Download(ctx, sftpPath(remotePath), localPath)
+}
+
+func (d *RemoteWindowsDriver) CreateDir(ctx context.Context, dirPath string) error {
+	cmd := fmt.Sprintf("New-Item -ItemType Directory -Force -Path %s | Out-Null", quotePowerShell(dirPath))
+	return d.Exec(ctx, cmd, "", nil, io.Discard, io.Discard)
+}
+
+func (d *RemoteWindowsDriver) CreateFile(ctx context.Context, filePath string) error {
+	cmd := fmt.Sprintf("New-Item -ItemType File -Force -Path %s | Out-Null", quotePowerShell(filePath))
+	return d.Exec(ctx, cmd, "", nil, io.Discard, io.Discard)
+}
+
+func (d *RemoteWindowsDriver) Remove(ctx context.Context, filePath string) error {
+	cmd := fmt.Sprintf("Remove-Item -Recurse -Force -ErrorAction SilentlyContinue -LiteralPath %s", quotePowerShell(filePath))
+	return d.Exec(ctx, cmd, "", nil, io.Discard, io.Discard)
+}
+
+// SetPermissions is a no-op, files on Windows don't have permission bits and scripts are run
+// through their interpreter
+func (d *RemoteWindowsDriver) SetPermissions(ctx context.Context, filePath string, perms os.FileMode) error {
+	return nil
+}
+
+// Exec runs the command with PowerShell. The command is passed encoded so that it is not
+// interpreted by the shell of the SSH server, and the exit code of the last native command
+// run is returned as the exit code of the session.
+func (d *RemoteWindowsDriver) Exec(ctx context.Context, command string, workingDir string, env []string, stdout, stderr io.Writer) error {
+	parts := []string{"$ErrorActionPreference = 'Stop'", "$ProgressPreference = 'SilentlyContinue'"}
+
+	for _, envVar := range env {
+		key, val, _ := strings.Cut(envVar, "=")
+		parts = append(parts, fmt.Sprintf("$env:%s = %s", key, quotePowerShell(val)))
+	}
+
+	if workingDir != "" {
+		parts = append(parts, fmt.Sprintf("Set-Location -LiteralPath %s", quotePowerShell(workingDir)))
+	}
+
+	parts = append(parts, "$global:LASTEXITCODE = 0", command, "exit $LASTEXITCODE")
+
+	script := strings.Join(parts, "\n")
+	fullCommand := fmt.Sprintf("powershell -NoProfile -NonInteractive -EncodedCommand %s", encodePowerShell(script))
+
+	return d.client.RunCommand(ctx, fullCommand, stdout, stderr)
+}
+
+func (d *RemoteWindowsDriver) Dial(network, address string) (net.Conn, error) {
+	return d.client.Dial(network, address)
+}
+
+func (d *RemoteWindowsDriver) IsRemote() bool {
+	return true
+}
+
+func (d *RemoteWindowsDriver) TempDir() string {
+	return d.tempDir
+}
+
+func (d *RemoteWindowsDriver) Join(parts ...string) string {
+	return JoinWindowsPath(parts...)
+}
+
+func (d *RemoteWindowsDriver) ListFiles(ctx context.Context, dirPath string) ([]string, error) {
+	var output strings.Builder
+
+	cmd := fmt.Sprintf("Get-ChildItem -File -LiteralPath %s -ErrorAction SilentlyContinue | ForEach-Object { $_.Name }", quotePowerShell(dirPath))
+	if err := d.Exec(ctx, cmd, "", nil, &output, io.Discard); err != nil {
+		return nil, fmt.Errorf("failed to list files in %s: %w", dirPath, err)
+	}
+
+	var result []string
+	for _, file := range strings.Split(output.String(), "\n") {
+		if file = strings.TrimSpace(file); file != "" {
+			result = append(result, file)
+		}
+	}
+
+	return result, nil
+}
+
+func (d *RemoteWindowsDriver) Close() error {
+	return d.client.Close()
+}
+
+// JoinWindowsPath joins path elements with backslashes. Forward slashes in the elements, such as
+// in paths relative to a local directory, are converted and repeated separators are removed.
+func JoinWindowsPath(parts ...string) string {
+	var elems []string
+	for i, p := range parts {
+		p = strings.ReplaceAll(p, "/", `\`)
+		if i > 0 {
+			p = strings.Trim(p, `\`)
+		} else if len(p) > 1 {
+			p = strings.TrimRight(p, `\`)
+		}
+		if p != "" && p != "." {
+			elems = append(elems, p)
+		}
+	}
+	return strings.Join(elems, `\`)
+}
+
+// windowsDir returns all but the last element of a Windows path
+func windowsDir(p string) string {
+	p = strings.ReplaceAll(p, "/", `\`)
+	if i := strings.LastIndex(p, `\`); i > 0 {
+		return p[:i]
+	}
+	return p
+}
+
+// sftpPath converts a Windows path to the form accepted by the SFTP server of Windows OpenSSH
+func sftpPath(p string) string {
+	return strings.ReplaceAll(p, `\`, "/")
+}
+
+// quotePowerShell quotes a string as a PowerShell literal
+func quotePowerShell(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// encodePowerShell encodes a script for powershell -EncodedCommand, base64 of UTF-16LE
+func encodePowerShell(script string) string {
+	units := utf16.Encode([]rune(script))
+	b := make([]byte, len(units)*2)
+	for i, u := range units {
+		b[2*i] = byte(u)
+		b[2*i+1] = byte(u >> 8)
+	}
+	return base64.StdEncoding.EncodeToString(b)
+}
@@ -0,0 +1,116 @@
+package executor
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ResourceSample is the resource usage of a node at a point in time
+type ResourceSample struct {
+	Time time.Time
+	// CPUPercent is the CPU utilization over the second before the sample was taken
+	CPUPercent    float64
+	MemoryPercent float64
+	// DiskPercent is the used space of the filesystem holding the driver's working directory
+	DiskPercent float64
+	Load1       float64
+}
+
+// resourceSampleCommand reads the CPU counters twice, a second apart, followed by the memory,
+// load average and disk usage. It only relies on procfs and POSIX df so it works on any Linux node.
+const resourceSampleCommand = "head -n1 /proc/stat; sleep 1; head -n1 /proc/stat; " +
+	"grep -E '^(MemTotal|MemAvailable):' /proc/meminfo; cat /proc/loadavg; df -Pk '%s' | tail -n1"
+
+// SampleResources returns the current resource usage of the node the driver is connected to
+func SampleResources(ctx context.Context, driver NodeDriver) (ResourceSample, error) {
+	path := driver.GetWorkingDirectory()
+	if path == "" {
+		path = "/"
+	}
+
+	var out strings.Builder
+	cmd := fmt.Sprintf(resourceSampleCommand, strings.ReplaceAll(path, "'", "'\\''"))
+	if err := driver.Exec(ctx, cmd, "", nil, &out, io.Discard); err != nil {
+		return ResourceSample{}, fmt.Errorf("failed to read resource usage: %w", err)
+	}
+
+	return parseResourceSample(out.String())
+}
+
+func parseResourceSample(output string) (ResourceSample, error) {
+	sample := ResourceSample{Time: time.Now().UTC()}
+
+	var (
+		cpuLines           [][]string
+		memTotal, memAvail float64
+		sawLoad            bool
+		diskFields         []string
+	)
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch {
+		case fields[0] == "cpu":
+			cpuLines = append(cpuLines, fields[1:])
+		case fields[0] == "MemTotal:" && len(fields) > 1:
+			memTotal, _ = strconv.ParseFloat(fields[1], 64)
+		case fields[0] == "MemAvailable:" && len(fields) > 1:
+			memAvail, _ = strconv.ParseFloat(fields[1], 64)
+		case !sawLoad && len(fields) == 5 && strings.Contains(fields[3], "/"):
+			sample.Load1, _ = strconv.ParseFloat(fields[0], 64)
+			sawLoad = true
+		case len(fields) >= 6 && strings.HasSuffix(fields[4], "%"):
+			diskFields = fields
+		}
+	}
+
+	if len(cpuLines) != 2 {
+		return ResourceSample{}, fmt.Errorf("unexpected CPU counters in resource usage output")
+	}
+	idle1, total1 := cpuTimes(cpuLines[0])
+	idle2, total2 := cpuTimes(cpuLines[1])
+	if total2 > total1 {
+		sample.CPUPercent = 100 * (1 - (idle2-idle1)/(total2-total1))
+	}
+
+	if memTotal > 0 {
+		sample.MemoryPercent = 100 * (memTotal - memAvail) / memTotal
+	}
+
+	if diskFields != nil {
+		used, _ := strconv.ParseFloat(diskFields[2], 64)
+		avail, _ := strconv.ParseFloat(diskFields[3], 64)
+		if used+avail > 0 {
+			sample.DiskPercent = 100 * used / (used + avail)
+		}
+	}
+
+	return sample, nil
+}
+
+// cpuTimes returns the idle and total jiffies from the fields of the cpu line of /proc/stat.
+// iowait is counted as idle time.
+func cpuTimes(fields []string) (idle float64, total float64) {
+	for i, f := range fields {
+		// guest and guest_nice are already included in user and nice
+		if i >= 8 {
+			break
+		}
+		v, _ := strconv.ParseFloat(f, 64)
+		total += v
+		if i == 3 || i == 4 {
+			idle += v
+		}
+	}
+	return idle, total
+}
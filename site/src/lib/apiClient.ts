@@ -41,6 +41,10 @@ import type {
   ApprovalsPaginateResponse,
   ExecutionsPaginateResponse,
   ExecutionSummary,
+  ExecutionTimeline,
+  NodeTelemetry,
+  FailureAnalytics,
+  FailureAnalyticsRequest,
   UsersPaginateResponse,
   GroupsPaginateResponse,
   PaginateRequest,
@@ -54,7 +58,11 @@ import type {
   UserSchedule,
   ScheduleCreateReq,
   ScheduleUpdateReq,
-  SchedulesPaginateResponse
+  SchedulesPaginateResponse,
+  ScheduleLag,
+  RetentionPolicy,
+  RetentionPolicyRequest,
+  RetentionPreview
 } from './types.js';
 
 export class ApiError extends Error {
@@ -320,6 +328,8 @@ export const apiClient = {
           `/api/v1/${namespace}/flows/${flowId}/schedules/${scheduleId}`,
           { method: 'DELETE' }
         ),
+      getLag: (namespace: string) =>
+        baseFetch<ScheduleLag[]>(`/api/v1/${namespace}/schedules/lag`),
     },
   },
 
@@ -432,6 +442,14 @@ export const apiClient = {
       baseFetch<ExecutionsPaginateResponse>(`/api/v1/${namespace}/flows/executions${buildQueryString(params)}`),
     getById: (namespace: string, execId: string) =>
       baseFetch<ExecutionSummary>(`/api/v1/${namespace}/flows/executions/${execId}`),
+    listArchived: (namespace: string, params: PaginateRequest = {}) =>
+      baseFetch<ExecutionsPaginateResponse>(`/api/v1/${namespace}/flows/executions/archived${buildQueryString(params)}`),
+    getArchived: (namespace: string, execId: string) =>
+      baseFetch<ExecutionSummary>(`/api/v1/${namespace}/flows/executions/archived/${execId}`),
+    getTelemetry: (namespace: string, execId: string) =>
+      baseFetch<NodeTelemetry[]>(`/api/v1/${namespace}/flows/executions/${execId}/telemetry`),
+    getTimeline: (namespace: string, execId: string) =>
+      baseFetch<ExecutionTimeline>(`/api/v1/${namespace}/flows/executions/${execId}/timeline`),
     listForFlow: (namespace: string, flowId: string, params: PaginateRequest = {}) =>
       baseFetch<ExecutionsPaginateResponse>(`/api/v1/${namespace}/flows/${flowId}/executions${buildQueryString(params)}`),
     cancel: (namespace: string, execId: string) =>
@@ -444,6 +462,29 @@ export const apiClient = {
       }),
   },
 
+  // Analytics
+  analytics: {
+    getFailures: (namespace: string, params: FailureAnalyticsRequest = {}) =>
+      baseFetch<FailureAnalytics>(`/api/v1/${namespace}/analytics/failures${buildQueryString(params)}`),
+  },
+
+  // Execution retention
+  retention: {
+    get: (namespace: string) =>
+      baseFetch<RetentionPolicy>(`/api/v1/${namespace}/retention`),
+    update: (namespace: string, policy: RetentionPolicyRequest) =>
+      baseFetch<RetentionPolicy>(`/api/v1/${namespace}/retention`, {
+        method: 'PUT',
+        body: JSON.stringify(policy),
+      }),
+    delete: (namespace: string) =>
+      baseFetch<void>(`/api/v1/${namespace}/retention`, {
+        method: 'DELETE',
+      }),
+    preview: (namespace: string) =>
+      baseFetch<RetentionPreview>(`/api/v1/${namespace}/retention/preview`),
+  },
+
   // Executors
   executors: {
     list: () => baseFetch<ExecutorsListResponse>('/api/v1/executors'),
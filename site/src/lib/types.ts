@@ -75,6 +75,22 @@ export interface ScheduleUpdateReq {
   is_active?: boolean;
 }
 
+export interface ScheduleLag {
+  uuid: string;
+  flow_slug: string;
+  flow_name: string;
+  cron: string;
+  timezone: string;
+  is_active: boolean;
+  is_user_created: boolean;
+  last_exec_id?: string;
+  last_fired_at?: string;
+  last_started_at?: string;
+  last_drift_ms: number;
+  max_drift_ms: number;
+  runs: number;
+}
+
 export interface SchedulesPaginateResponse {
   schedules: UserSchedule[];
   page_count: number;
@@ -119,6 +135,7 @@ export interface FlowInput {
   options: string[];
   default?: string;
   max_file_size?: number;
+  accept?: string[];
 }
 
 export interface FlowInputsResp {
@@ -135,6 +152,15 @@ export interface FlowMeta {
   namespace: string;
   allow_overlap: boolean;
   user_schedulable: boolean;
+  sla?: FlowSLA;
+  lock?: string;
+  max_concurrency?: number;
+}
+
+export interface FlowSLA {
+  max_duration?: string;
+  deadline?: string;
+  timezone?: string;
 }
 
 export interface FlowAction {
@@ -166,7 +192,7 @@ export interface FlowTriggerResp {
 
 export interface FlowLogResp {
   action_id: string;
-  message_type: "log" | "error" | "result" | "approval";
+  message_type: "log" | "error" | "result" | "approval" | "progress";
   value: string;
   results?: Record<string, string>;
 }
@@ -264,6 +290,7 @@ export interface NamespaceMembersResponse {
 // Approval types
 export interface ApprovalActionReq {
   action: string;
+  comment?: string;
 }
 
 export interface ApprovalActionResp {
@@ -293,6 +320,7 @@ export interface ApprovalDetailsResp {
   flow_id: string;
   requested_by: string;
   approved_by?: string;
+  comment?: string;
   created_at: string;
   updated_at: string;
 }
@@ -323,6 +351,99 @@ export interface ExecutionSummary {
   action_retries?: Record<string, number>;
 }
 
+export interface NodeTimeline {
+  node: string;
+  status: string;
+  error?: string;
+  started_at: string;
+  finished_at?: string;
+  duration_ms: number;
+}
+
+export interface ActionTimeline {
+  action_id: string;
+  retry: number;
+  status: string;
+  error?: string;
+  started_at: string;
+  finished_at?: string;
+  duration_ms: number;
+  nodes: NodeTimeline[];
+}
+
+export interface TelemetrySample {
+  time: string;
+  cpu_percent: number;
+  memory_percent: number;
+  disk_percent: number;
+  load1: number;
+}
+
+export interface NodeTelemetry {
+  action_id: string;
+  node: string;
+  samples: TelemetrySample[];
+}
+
+export interface ExecutionTimeline {
+  exec_id: string;
+  actions: ActionTimeline[];
+  slowest_action_id?: string;
+}
+
+// Failure analytics types
+export interface FailureAnalyticsRequest {
+  from?: string;
+  to?: string;
+  window?: "24h" | "7d" | "30d" | "90d";
+  limit?: number;
+}
+
+export interface FailureCause {
+  message: string;
+  sample_error: string;
+  count: number;
+  flow_ids: string[];
+  action_ids: string[];
+  sample_exec_ids: string[];
+  last_seen: string;
+}
+
+export interface FailureCount {
+  name: string;
+  count: number;
+  last_seen: string;
+}
+
+export interface FailureAnalytics {
+  from: string;
+  to: string;
+  total_failures: number;
+  causes: FailureCause[];
+  actions: FailureCount[];
+  nodes: FailureCount[];
+}
+
+export interface RetentionPolicyRequest {
+  max_age_days: number;
+  keep_last: number;
+}
+
+export interface RetentionPolicy extends RetentionPolicyRequest {
+  enabled: boolean;
+  updated_at?: string;
+}
+
+export interface RetentionPreview {
+  policy: RetentionPolicy;
+  total: number;
+  archived: number;
+  by_status: Record<string, number>;
+  oldest?: string;
+  newest?: string;
+  sample_exec_ids: string[];
+}
+
 // Pagination types
 export interface PaginateRequest {
   filter?: string;
@@ -513,6 +634,8 @@ export interface FlowMetaReq {
   prefix?: string;
   schedules?: Schedule[];
   allow_overlap?: boolean;
+  lock?: string;
+  max_concurrency?: number;
 }
 
 export interface RemoteOptionsReq {
@@ -539,6 +662,7 @@ export interface FlowInputReq {
   options?: string[];
   remote_options?: RemoteOptionsReq;
   max_file_size?: number;
+  accept?: string[];
 }
 
 export interface FlowActionReq {
@@ -550,6 +674,8 @@ export interface FlowActionReq {
   artifacts?: string[];
   condition?: string;
   on?: string[];
+  telemetry?: boolean;
+  lock?: string;
 }
 
 export interface FlowCreateResp {
@@ -561,6 +687,8 @@ export interface FlowUpdateReq {
   schedules: Schedule[];
   allow_overlap?: boolean;
   user_schedulable?: boolean;
+  lock?: string;
+  max_concurrency?: number;
   description?: string;
   inputs: FlowInputReq[];
   actions: FlowActionReq[];